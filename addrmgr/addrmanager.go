@@ -17,6 +17,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -49,6 +50,8 @@ type AddrManager struct {
 	version        int
 }
 
+// serializedKnownAddress is the format of a known address in the peers file.
+// See the package documentation for a description of the fields.
 type serializedKnownAddress struct {
 	Addr        string
 	Src         string
@@ -58,15 +61,26 @@ type serializedKnownAddress struct {
 	LastSuccess int64
 	Services    wire.ServiceFlag
 	SrcServices wire.ServiceFlag
-	// no refcount or tried, that is available from context.
+
+	// The following fields are only set from version 3 onwards.  Earlier
+	// versions keep the bucket assignments in the address manager and
+	// the refcount and tried flag are worked out from context.
+	Network     string `json:",omitempty"`
+	NewBuckets  []int  `json:",omitempty"`
+	TriedBucket *int   `json:",omitempty"`
 }
 
+// serializedAddrManager is the format of the peers file.  See the package
+// documentation for a description of the fields.
 type serializedAddrManager struct {
-	Version      int
-	Key          [32]byte
-	Addresses    []*serializedKnownAddress
-	NewBuckets   [newBucketCount][]string // string is NetAddressKey
-	TriedBuckets [triedBucketCount][]string
+	Version   int
+	Key       [32]byte
+	Addresses []*serializedKnownAddress
+
+	// NewBuckets and TriedBuckets are only set by versions 1 and 2.  Each
+	// bucket lists the NetAddressKey of its addresses.
+	NewBuckets   [][]string `json:",omitempty"`
+	TriedBuckets [][]string `json:",omitempty"`
 }
 
 type localAddress struct {
@@ -158,7 +172,7 @@ const (
 	getAddrPercent = 23
 
 	// serialisationVersion is the current version of the on-disk format.
-	serialisationVersion = 2
+	serialisationVersion = 3
 )
 
 // updateAddress is a helper function to either update an address already known
@@ -366,6 +380,67 @@ func (a *AddrManager) savePeers() {
 
 	// First we make a serialisable datastructure so we can encode it to
 	// json.
+	var sam *serializedAddrManager
+	if a.version < 3 {
+		sam = a.serializeLegacyPeers()
+	} else {
+		sam = a.serializePeers()
+	}
+
+	w, err := os.Create(a.peersFile)
+	if err != nil {
+		log.Errorf("Error opening file %s: %v", a.peersFile, err)
+		return
+	}
+	enc := json.NewEncoder(w)
+	defer w.Close()
+	if err := enc.Encode(&sam); err != nil {
+		log.Errorf("Failed to encode file %s: %v", a.peersFile, err)
+		return
+	}
+}
+
+// serializePeers returns the known addresses in the current format of the
+// peers file, where each address records the buckets it is assigned to.  The
+// addresses are listed in the order of addressInfos, so the output only depends
+// on the state of the address manager.
+//
+// This function MUST be called with the address manager lock held (for reads).
+func (a *AddrManager) serializePeers() *serializedAddrManager {
+	sam := new(serializedAddrManager)
+	sam.Version = a.version
+	copy(sam.Key[:], a.key[:])
+
+	infos := a.addressInfos()
+	sam.Addresses = make([]*serializedKnownAddress, 0, len(infos))
+	for _, info := range infos {
+		ska := &serializedKnownAddress{
+			Addr:        info.Key,
+			Src:         NetAddressKey(info.Src),
+			Attempts:    info.Attempts,
+			TimeStamp:   info.Addr.Timestamp.Unix(),
+			LastAttempt: info.LastAttempt.Unix(),
+			LastSuccess: info.LastSuccess.Unix(),
+			Services:    info.Addr.Services,
+			SrcServices: info.Src.Services,
+			Network:     info.Network,
+			NewBuckets:  info.NewBuckets,
+		}
+		if info.TriedBucket >= 0 {
+			triedBucket := info.TriedBucket
+			ska.TriedBucket = &triedBucket
+		}
+		sam.Addresses = append(sam.Addresses, ska)
+	}
+	return sam
+}
+
+// serializeLegacyPeers returns the known addresses in the format of versions 1
+// and 2 of the peers file, where the buckets list the addresses assigned to
+// them.
+//
+// This function MUST be called with the address manager lock held (for reads).
+func (a *AddrManager) serializeLegacyPeers() *serializedAddrManager {
 	sam := new(serializedAddrManager)
 	sam.Version = a.version
 	copy(sam.Key[:], a.key[:])
@@ -389,6 +464,7 @@ func (a *AddrManager) savePeers() {
 		sam.Addresses[i] = ska
 		i++
 	}
+	sam.NewBuckets = make([][]string, newBucketCount)
 	for i := range a.addrNew {
 		sam.NewBuckets[i] = make([]string, len(a.addrNew[i]))
 		j := 0
@@ -397,6 +473,7 @@ func (a *AddrManager) savePeers() {
 			j++
 		}
 	}
+	sam.TriedBuckets = make([][]string, triedBucketCount)
 	for i := range a.addrTried {
 		sam.TriedBuckets[i] = make([]string, a.addrTried[i].Len())
 		j := 0
@@ -406,18 +483,7 @@ func (a *AddrManager) savePeers() {
 			j++
 		}
 	}
-
-	w, err := os.Create(a.peersFile)
-	if err != nil {
-		log.Errorf("Error opening file %s: %v", a.peersFile, err)
-		return
-	}
-	enc := json.NewEncoder(w)
-	defer w.Close()
-	if err := enc.Encode(&sam); err != nil {
-		log.Errorf("Failed to encode file %s: %v", a.peersFile, err)
-		return
-	}
+	return sam
 }
 
 // loadPeers loads the known address from the saved file.  If empty, missing, or
@@ -467,6 +533,10 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 		return fmt.Errorf("unknown version %v in serialized "+
 			"addrmanager", sam.Version)
 	}
+	if sam.Version < a.version {
+		log.Infof("Upgrading peers file %s from version %d to %d",
+			filePath, sam.Version, a.version)
+	}
 
 	copy(a.key[:], sam.Key[:])
 
@@ -500,9 +570,66 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 		ka.attempts = v.Attempts
 		ka.lastattempt = time.Unix(v.LastAttempt, 0)
 		ka.lastsuccess = time.Unix(v.LastSuccess, 0)
-		a.addrIndex[NetAddressKey(ka.na)] = ka
+		ka.na.Timestamp = time.Unix(v.TimeStamp, 0)
+		key := NetAddressKey(ka.na)
+		a.addrIndex[key] = ka
+
+		// Starting with version 3, each address records the buckets it
+		// is assigned to.
+		if sam.Version < 3 {
+			continue
+		}
+		if network := NetworkName(ka.na); v.Network != network {
+			return fmt.Errorf("address %s is on network %s, not "+
+				"%s", v.Addr, network, v.Network)
+		}
+		if v.TriedBucket != nil && len(v.NewBuckets) > 0 {
+			return fmt.Errorf("address %s is in both new and tried "+
+				"buckets", v.Addr)
+		}
+		for _, bucket := range v.NewBuckets {
+			if bucket < 0 || bucket >= newBucketCount {
+				return fmt.Errorf("address %s in invalid new "+
+					"bucket %d", v.Addr, bucket)
+			}
+
+			// An address listing the same bucket more than once is
+			// only referenced by it once.
+			if _, ok := a.addrNew[bucket][key]; ok {
+				continue
+			}
+			if ka.refs == newBucketsPerAddress {
+				return fmt.Errorf("address %s in more than %d "+
+					"new buckets", v.Addr, newBucketsPerAddress)
+			}
+			if ka.refs == 0 {
+				a.nNew++
+			}
+			ka.refs++
+			a.addrNew[bucket][key] = ka
+		}
+		if v.TriedBucket != nil {
+			bucket := *v.TriedBucket
+			if bucket < 0 || bucket >= triedBucketCount {
+				return fmt.Errorf("address %s in invalid tried "+
+					"bucket %d", v.Addr, bucket)
+			}
+			if a.addrTried[bucket].Len() >= triedBucketSize {
+				return fmt.Errorf("address %s in full tried "+
+					"bucket %d", v.Addr, bucket)
+			}
+			ka.tried = true
+			a.nTried++
+			a.addrTried[bucket].PushBack(ka)
+		}
 	}
 
+	if len(sam.NewBuckets) > newBucketCount ||
+		len(sam.TriedBuckets) > triedBucketCount {
+
+		return fmt.Errorf("too many buckets: %d new and %d tried",
+			len(sam.NewBuckets), len(sam.TriedBuckets))
+	}
 	for i := range sam.NewBuckets {
 		for _, val := range sam.NewBuckets[i] {
 			ka, ok := a.addrIndex[val]
@@ -564,6 +691,209 @@ func (a *AddrManager) DeserializeNetAddress(addr string,
 	return a.HostToNetAddress(host, uint16(port), services)
 }
 
+// AddressInfo describes a known address of the address manager along with the
+// buckets it is assigned to, as recorded in the peers file.
+type AddressInfo struct {
+	// Key is the NetAddressKey of the address and Network is the name of
+	// its network as returned by NetworkName.
+	Key     string
+	Network string
+
+	// Addr is the address and Src is the address of the peer which sent
+	// it to us.
+	Addr *wire.NetAddressV2
+	Src  *wire.NetAddressV2
+
+	Attempts    int
+	LastAttempt time.Time
+	LastSuccess time.Time
+
+	// NewBuckets lists the new buckets the address is in, in ascending
+	// order.  It is empty for tried addresses.
+	NewBuckets []int
+
+	// TriedBucket is the tried bucket the address is in, or -1 when the
+	// address has not been tried.
+	TriedBucket int
+}
+
+// addressInfos returns the known addresses in a deterministic order.  The tried
+// addresses come first, bucket by bucket in the order they were added to each
+// bucket, followed by the new addresses sorted by key.
+//
+// This function MUST be called with the address manager lock held (for reads).
+func (a *AddrManager) addressInfos() []*AddressInfo {
+	newInfo := func(key string, ka *KnownAddress) *AddressInfo {
+		ka.mtx.RLock()
+		defer ka.mtx.RUnlock()
+
+		na, srcAddr := *ka.na, *ka.srcAddr
+		return &AddressInfo{
+			Key:         key,
+			Network:     NetworkName(ka.na),
+			Addr:        &na,
+			Src:         &srcAddr,
+			Attempts:    ka.attempts,
+			LastAttempt: ka.lastattempt,
+			LastSuccess: ka.lastsuccess,
+			TriedBucket: -1,
+		}
+	}
+
+	infos := make([]*AddressInfo, 0, len(a.addrIndex))
+	for i := range a.addrTried {
+		for e := a.addrTried[i].Front(); e != nil; e = e.Next() {
+			ka := e.Value.(*KnownAddress)
+			info := newInfo(NetAddressKey(ka.na), ka)
+			info.TriedBucket = i
+			infos = append(infos, info)
+		}
+	}
+
+	newInfos := make(map[string]*AddressInfo, a.nNew)
+	for i := range a.addrNew {
+		for key, ka := range a.addrNew[i] {
+			info, ok := newInfos[key]
+			if !ok {
+				info = newInfo(key, ka)
+				newInfos[key] = info
+			}
+			info.NewBuckets = append(info.NewBuckets, i)
+		}
+	}
+	keys := make([]string, 0, len(newInfos))
+	for key := range newInfos {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		infos = append(infos, newInfos[key])
+	}
+	return infos
+}
+
+// ForEachAddress calls the passed function with each known address, tried
+// addresses first, in the order they are written to the peers file.  The
+// iteration stops at the first error returned by the function, which is then
+// returned.  The function is called without the address manager lock held, so
+// it may call methods of the address manager.
+func (a *AddrManager) ForEachAddress(fn func(info *AddressInfo) error) error {
+	a.mtx.RLock()
+	infos := a.addressInfos()
+	a.mtx.RUnlock()
+
+	for _, info := range infos {
+		if err := fn(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadPeersFile reads the known addresses from the passed peers file, which may
+// be of any supported version, and calls the passed function with each of them
+// as described by ForEachAddress.  It allows tooling to inspect the peers file
+// of a node without running an address manager.
+func ReadPeersFile(filePath string, fn func(info *AddressInfo) error) error {
+	if _, err := os.Stat(filePath); err != nil {
+		return err
+	}
+
+	a := New(filepath.Dir(filePath), nil)
+	a.mtx.Lock()
+	err := a.deserializePeers(filePath)
+	a.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	return a.ForEachAddress(fn)
+}
+
+// SampleAddresses returns up to the passed number of known addresses picked at
+// random, without repetition, from both the new and the tried tables.
+func (a *AddrManager) SampleAddresses(count int) []*AddressInfo {
+	a.mtx.RLock()
+	infos := a.addressInfos()
+	a.mtx.RUnlock()
+
+	if count > len(infos) {
+		count = len(infos)
+	}
+
+	// Fisher-Yates shuffle the first count addresses like AddressCache.
+	for i := 0; i < count; i++ {
+		j := rand.Intn(len(infos)-i) + i
+		infos[i], infos[j] = infos[j], infos[i]
+	}
+	return infos[:count]
+}
+
+// NetworkStats holds the number of addresses of a network in the new and the
+// tried tables of the address manager.
+type NetworkStats struct {
+	New   int
+	Tried int
+}
+
+// Stats holds statistics about the addresses known to the address manager.
+type Stats struct {
+	// Networks maps the network names returned by NetworkName to the
+	// number of known addresses of each network.  Networks without any
+	// known address are not included.
+	Networks map[string]*NetworkStats
+
+	// Terrible is the number of addresses which are considered bad and
+	// are the first to be evicted from the new table.
+	Terrible int
+
+	// NewBucketsUsed and TriedBucketsUsed are the number of buckets of
+	// each table which hold at least one address.
+	NewBucketsUsed   int
+	TriedBucketsUsed int
+}
+
+// Stats returns statistics about the addresses known to the address manager
+// which help to judge the diversity of its addresses.
+func (a *AddrManager) Stats() *Stats {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	stats := &Stats{
+		Networks: make(map[string]*NetworkStats),
+	}
+	for _, ka := range a.addrIndex {
+		ka.mtx.RLock()
+		network := NetworkName(ka.na)
+		tried, bad := ka.tried, ka.isBad()
+		ka.mtx.RUnlock()
+
+		netStats, ok := stats.Networks[network]
+		if !ok {
+			netStats = &NetworkStats{}
+			stats.Networks[network] = netStats
+		}
+		if tried {
+			netStats.Tried++
+		} else {
+			netStats.New++
+		}
+		if bad {
+			stats.Terrible++
+		}
+	}
+	for i := range a.addrNew {
+		if len(a.addrNew[i]) > 0 {
+			stats.NewBucketsUsed++
+		}
+	}
+	for i := range a.addrTried {
+		if a.addrTried[i].Len() > 0 {
+			stats.TriedBucketsUsed++
+		}
+	}
+	return stats
+}
+
 // Start begins the core address handler which manages a pool of known
 // addresses, timeouts, and interval based writes.
 func (a *AddrManager) Start() {
@@ -1,10 +1,13 @@
 package addrmgr
 
 import (
+	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"net"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -198,3 +201,193 @@ func TestAddrManagerV1ToV2(t *testing.T) {
 	addrMgr.loadPeers()
 	assertAddrs(t, addrMgr, expectedAddrs)
 }
+
+// addressInfosOf returns the known addresses of the passed address manager as
+// iterated by ForEachAddress.
+func addressInfosOf(t *testing.T, addrMgr *AddrManager) []*AddressInfo {
+	t.Helper()
+
+	var infos []*AddressInfo
+	err := addrMgr.ForEachAddress(func(info *AddressInfo) error {
+		infos = append(infos, info)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachAddress: unexpected error: %v", err)
+	}
+	return infos
+}
+
+// assertAddressInfos ensures the two lists of known addresses match, including
+// their bucket assignments and persisted statistics.
+func assertAddressInfos(t *testing.T, got, expected []*AddressInfo) {
+	t.Helper()
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d addresses, got %d", len(expected),
+			len(got))
+	}
+	for i := range got {
+		g, e := got[i], expected[i]
+		if g.Key != e.Key || g.Network != e.Network ||
+			NetAddressKey(g.Src) != NetAddressKey(e.Src) ||
+			g.Attempts != e.Attempts ||
+			g.LastAttempt.Unix() != e.LastAttempt.Unix() ||
+			g.LastSuccess.Unix() != e.LastSuccess.Unix() ||
+			g.Addr.Timestamp.Unix() != e.Addr.Timestamp.Unix() ||
+			!reflect.DeepEqual(g.NewBuckets, e.NewBuckets) ||
+			g.TriedBucket != e.TriedBucket {
+
+			t.Fatalf("address %d: expected %+v, got %+v", i, e, g)
+		}
+		assertAddr(t, g.Addr, e.Addr)
+	}
+}
+
+// TestAddrManagerV3Serialization ensures the bucket assignments, networks and
+// statistics of the known addresses survive a restart, that older peers files
+// are upgraded to the current version, and that peers files can be read by
+// tooling.
+func TestAddrManagerV3Serialization(t *testing.T) {
+	t.Parallel()
+
+	tempDir, err := ioutil.TempDir("", "addrmgr")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	addrMgr := New(tempDir, nil)
+
+	// Add random addresses along with a Tor v3 address, and move a few of
+	// them to the tried buckets.
+	var addrs []*wire.NetAddressV2
+	for i := 0; i < 20; i++ {
+		addrs = append(addrs, randAddr(t))
+	}
+	var torKey [wire.TorV3Size]byte
+	rand.Read(torKey[:])
+	torAddr := wire.NetAddressV2FromBytes(
+		time.Unix(time.Now().Unix()-3600, 0), wire.SFNodeNetwork,
+		torKey[:], 9333,
+	)
+	addrs = append(addrs, torAddr)
+	for _, addr := range addrs {
+		addrMgr.AddAddress(addr, randAddr(t))
+	}
+	for _, addr := range addrs[:5] {
+		addrMgr.Attempt(addr)
+		addrMgr.Good(addr)
+	}
+	addrMgr.Attempt(addrs[10])
+
+	expected := addressInfosOf(t, addrMgr)
+	var sawTor bool
+	for _, info := range expected {
+		sawTor = sawTor || info.Network == "torv3"
+	}
+	if len(expected) == 0 || !sawTor {
+		t.Fatalf("expected routable addresses including torv3, got %d",
+			len(expected))
+	}
+
+	// The addresses are restored in the same buckets after a restart.
+	addrMgr.savePeers()
+	addrMgr = New(tempDir, nil)
+	addrMgr.loadPeers()
+	assertAddressInfos(t, addressInfosOf(t, addrMgr), expected)
+
+	// A version 2 peers file is upgraded without losing any address or
+	// bucket assignment.
+	addrMgr.version = 2
+	addrMgr.savePeers()
+	addrMgr = New(tempDir, nil)
+	addrMgr.loadPeers()
+	assertAddressInfos(t, addressInfosOf(t, addrMgr), expected)
+	addrMgr.savePeers()
+	addrMgr = New(tempDir, nil)
+	addrMgr.loadPeers()
+	assertAddressInfos(t, addressInfosOf(t, addrMgr), expected)
+
+	// Tooling can read the peers file without an address manager.
+	var infos []*AddressInfo
+	err = ReadPeersFile(addrMgr.peersFile, func(info *AddressInfo) error {
+		infos = append(infos, info)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadPeersFile: unexpected error: %v", err)
+	}
+	assertAddressInfos(t, infos, expected)
+	if err := ReadPeersFile(tempDir+"/missing.json", nil); err == nil {
+		t.Fatal("ReadPeersFile: expected error for missing file")
+	}
+}
+
+// TestAddrManagerV3Corrupt ensures version 3 peers files with invalid bucket
+// assignments or networks are rejected, and that an address listing the same
+// new bucket more than once is only referenced by it once.
+func TestAddrManagerV3Corrupt(t *testing.T) {
+	t.Parallel()
+
+	tempDir, err := ioutil.TempDir("", "addrmgr")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	const addr = `"Addr":"173.194.115.66:9333","Src":"173.194.115.67:9333"`
+
+	// A tried bucket holds no more than triedBucketSize addresses.
+	var fullTried []string
+	for i := 0; i <= triedBucketSize; i++ {
+		fullTried = append(fullTried, fmt.Sprintf(`{"Addr":"173.194.%d.%d:9333",`+
+			`"Src":"173.194.115.67:9333","Network":"ipv4","TriedBucket":0}`,
+			i/256, i%256))
+	}
+
+	tests := []struct {
+		name    string
+		address string
+	}{
+		{"invalid new bucket", `{` + addr + `,"Network":"ipv4","NewBuckets":[1024]}`},
+		{"invalid tried bucket", `{` + addr + `,"Network":"ipv4","TriedBucket":64}`},
+		{"wrong network", `{` + addr + `,"Network":"torv3","NewBuckets":[1]}`},
+		{"no bucket", `{` + addr + `,"Network":"ipv4"}`},
+		{"new and tried", `{` + addr + `,"Network":"ipv4","NewBuckets":[1],"TriedBucket":1}`},
+		{"too many new buckets", `{` + addr + `,"Network":"ipv4","NewBuckets":[1,2,3,4,5,6,7,8,9]}`},
+		{"full tried bucket", strings.Join(fullTried, ",")},
+	}
+
+	writePeersFile := func(name, addresses string) string {
+		peersFile := tempDir + "/" + name + ".json"
+		data := `{"Version":3,"Addresses":[` + addresses + `]}`
+		if err := ioutil.WriteFile(peersFile, []byte(data), 0600); err != nil {
+			t.Fatalf("unable to write peers file: %v", err)
+		}
+		return peersFile
+	}
+	for _, test := range tests {
+		peersFile := writePeersFile(test.name, test.address)
+		err := ReadPeersFile(peersFile, func(*AddressInfo) error {
+			return nil
+		})
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+
+	peersFile := writePeersFile("duplicate new buckets",
+		`{`+addr+`,"Network":"ipv4","NewBuckets":[1,1,2]}`)
+	addrMgr := New(tempDir, nil)
+	if err := addrMgr.deserializePeers(peersFile); err != nil {
+		t.Fatalf("duplicate new buckets: unexpected error: %v", err)
+	}
+	for _, ka := range addrMgr.addrIndex {
+		if ka.refs != 2 || addrMgr.nNew != 1 {
+			t.Fatalf("duplicate new buckets: got %d references and "+
+				"%d new addresses, want 2 and 1", ka.refs,
+				addrMgr.nNew)
+		}
+	}
+}
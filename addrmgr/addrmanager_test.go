@@ -539,3 +539,126 @@ func TestNetAddressKey(t *testing.T) {
 	}
 
 }
+
+// TestForEachAddress ensures the known addresses are iterated tried addresses
+// first along with the buckets they are assigned to.
+func TestForEachAddress(t *testing.T) {
+	n := addrmgr.New("testforeachaddress", lookupFunc)
+	addrmgr.TstSetKey(n, [32]byte{1, 2, 3})
+
+	srcAddr := wire.NetAddressV2FromBytes(
+		time.Now(), 0, net.IPv4(173, 144, 173, 111), 9333,
+	)
+	var addrs []*wire.NetAddressV2
+	for i := 0; i < 4; i++ {
+		addr := wire.NetAddressV2FromBytes(time.Now(),
+			wire.SFNodeNetwork, net.IPv4(173, 194, byte(i), 1), 9333)
+		addrs = append(addrs, addr)
+	}
+	n.AddAddresses(addrs, srcAddr)
+	n.Good(addrs[2])
+
+	var got []*addrmgr.AddressInfo
+	err := n.ForEachAddress(func(info *addrmgr.AddressInfo) error {
+		got = append(got, info)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachAddress: unexpected error: %v", err)
+	}
+	if len(got) != len(addrs) {
+		t.Fatalf("ForEachAddress: got %d addresses, want %d", len(got),
+			len(addrs))
+	}
+
+	// The tried address comes first, then the new addresses sorted by key.
+	tried := got[0]
+	if tried.Key != addrmgr.NetAddressKey(addrs[2]) ||
+		tried.TriedBucket != addrmgr.TstTriedBucket(n, addrs[2]) ||
+		len(tried.NewBuckets) != 0 || tried.Network != "ipv4" {
+
+		t.Errorf("ForEachAddress: unexpected tried address %+v", tried)
+	}
+	for i, j := range []int{0, 1, 3} {
+		info := got[i+1]
+		wantBuckets := []int{addrmgr.TstNewBucket(n, addrs[j], srcAddr)}
+		if info.Key != addrmgr.NetAddressKey(addrs[j]) ||
+			info.TriedBucket != -1 ||
+			!reflect.DeepEqual(info.NewBuckets, wantBuckets) {
+
+			t.Errorf("ForEachAddress: unexpected new address %+v, "+
+				"want buckets %v", info, wantBuckets)
+		}
+	}
+
+	// The iteration stops at the first error.
+	errStop := errors.New("stop")
+	var calls int
+	err = n.ForEachAddress(func(info *addrmgr.AddressInfo) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("ForEachAddress: got error %v after %d calls, want %v "+
+			"after 1 call", err, calls, errStop)
+	}
+}
+
+func TestStats(t *testing.T) {
+	n := addrmgr.New("teststats", lookupFunc)
+
+	srcAddr := wire.NetAddressV2FromBytes(
+		time.Now(), 0, net.IPv4(173, 144, 173, 111), 9333,
+	)
+	addrs := []*wire.NetAddressV2{
+		wire.NetAddressV2FromBytes(time.Now(), wire.SFNodeNetwork,
+			net.IPv4(173, 194, 1, 1), 9333),
+		wire.NetAddressV2FromBytes(time.Now(), wire.SFNodeNetwork,
+			net.IPv4(173, 194, 2, 1), 9333),
+		wire.NetAddressV2FromBytes(time.Now(), wire.SFNodeNetwork,
+			net.ParseIP("2001:470::1"), 9333),
+
+		// An address which has not been seen for two months is
+		// terrible.
+		wire.NetAddressV2FromBytes(time.Now().Add(-60*24*time.Hour),
+			wire.SFNodeNetwork, net.IPv4(173, 194, 3, 1), 9333),
+	}
+	n.AddAddresses(addrs, srcAddr)
+	n.Good(addrs[0])
+
+	stats := n.Stats()
+	want := map[string]addrmgr.NetworkStats{
+		"ipv4": {New: 2, Tried: 1},
+		"ipv6": {New: 1},
+	}
+	if len(stats.Networks) != len(want) {
+		t.Errorf("Stats: got %d networks, want %d", len(stats.Networks),
+			len(want))
+	}
+	for network, wantStats := range want {
+		got, ok := stats.Networks[network]
+		if !ok || *got != wantStats {
+			t.Errorf("Stats: got %+v for %s, want %+v", got, network,
+				wantStats)
+		}
+	}
+	if stats.Terrible != 1 {
+		t.Errorf("Stats: got %d terrible addresses, want 1",
+			stats.Terrible)
+	}
+	if stats.TriedBucketsUsed != 1 || stats.NewBucketsUsed == 0 {
+		t.Errorf("Stats: got %d new and %d tried buckets used",
+			stats.NewBucketsUsed, stats.TriedBucketsUsed)
+	}
+
+	// Samples never repeat an address and are limited by the number of
+	// known addresses.
+	sample := n.SampleAddresses(2)
+	if len(sample) != 2 || sample[0].Key == sample[1].Key {
+		t.Errorf("SampleAddresses: unexpected sample %+v", sample)
+	}
+	if sample = n.SampleAddresses(10); len(sample) != len(addrs) {
+		t.Errorf("SampleAddresses: got %d addresses, want %d",
+			len(sample), len(addrs))
+	}
+}
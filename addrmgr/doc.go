@@ -34,5 +34,37 @@ provided by the caller about connected, known good, and attempted addresses to
 periodically purge peers which no longer appear to be good peers as well as
 bias the selection toward known good peers.  The general idea is to make a best
 effort at only providing usable addresses.
+
+# Peers File
+
+The known addresses are saved periodically and on shutdown to a JSON file named
+peers.json in the data directory, so they are available again on the next run.
+Version 3 of the file is an object with the following fields:
+
+  - Version: the version of the format, which is 3
+  - Key: the 32 secret bytes used to assign addresses to buckets, so the
+    assignments stay stable across restarts
+  - Addresses: the known addresses, tried addresses first in the order they
+    were added to each tried bucket, followed by new addresses sorted by Addr
+
+Each address is an object with the following fields:
+
+  - Addr: the address as host:port, where the host is an IP address or an
+    onion address
+  - Network: the network of the address, one of ipv4, ipv6, torv2 and torv3
+  - Src: the address of the peer which sent us the address
+  - Services and SrcServices: the services advertised for Addr and Src
+  - TimeStamp: the time the address was last seen, in seconds since 1 Jan 1970
+  - Attempts: the number of connection attempts since the last success
+  - LastAttempt and LastSuccess: the times of the last connection attempt and
+    of the last successful connection, in seconds since 1 Jan 1970
+  - NewBuckets: the new buckets the address is in, omitted for tried addresses
+  - TriedBucket: the tried bucket the address is in, omitted for new addresses
+
+Versions 1 and 2 of the file list the addresses of each bucket in NewBuckets and
+TriedBuckets fields of the top-level object instead, and version 1 does not
+record services.  Older files are read transparently and rewritten in the
+current version the next time the addresses are saved.  ReadPeersFile reads a
+peers file of any version for tooling.
 */
 package addrmgr
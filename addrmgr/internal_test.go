@@ -23,3 +23,20 @@ func TstNewKnownAddress(na *wire.NetAddressV2, attempts int,
 	return &KnownAddress{na: na, attempts: attempts, lastattempt: lastattempt,
 		lastsuccess: lastsuccess, tried: tried, refs: refs}
 }
+
+// TstSetKey replaces the key used to assign addresses to buckets, which makes
+// the bucket assignments deterministic.  It must be called before any address
+// is added.
+func TstSetKey(a *AddrManager, key [32]byte) {
+	a.mtx.Lock()
+	a.key = key
+	a.mtx.Unlock()
+}
+
+func TstNewBucket(a *AddrManager, netAddr, srcAddr *wire.NetAddressV2) int {
+	return a.getNewBucket(netAddr, srcAddr)
+}
+
+func TstTriedBucket(a *AddrManager, netAddr *wire.NetAddressV2) int {
+	return a.getTriedBucket(netAddr)
+}
@@ -238,6 +238,23 @@ func IsRoutable(na *wire.NetAddressV2) bool {
 		!IsOnionCatTor(lna)))
 }
 
+// NetworkName returns the name of the network of the passed address, which is
+// one of ipv4, ipv6, torv2 and torv3.
+func NetworkName(na *wire.NetAddressV2) string {
+	if na.IsTorV3() {
+		return "torv3"
+	}
+
+	lna := na.ToLegacy()
+	switch {
+	case IsOnionCatTor(lna):
+		return "torv2"
+	case IsIPv4(lna):
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
 // GroupKey returns a string representing the network group an address is part
 // of.  This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the string
 // "local" for a local address, the string "tor:key" where key is the /4 of the
@@ -0,0 +1,267 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package amount
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+const (
+	// DefaultPrecision is the number of decimal places of a coin, which
+	// makes a coin 10^8 base units.
+	DefaultPrecision = 8
+
+	// maxPrecision is the maximum supported precision, which is the
+	// largest power of ten that fits in an ltcutil.Amount.
+	maxPrecision = 18
+)
+
+var (
+	// ErrOverflow describes an error where the result of an arithmetic
+	// operation or a conversion does not fit in an ltcutil.Amount.
+	ErrOverflow = errors.New("amount overflows")
+
+	// ErrInvalidAmount describes an error where a string is not a decimal
+	// number of coins of the requested precision.
+	ErrInvalidAmount = errors.New("invalid amount")
+
+	// ErrInvalidPrecision describes an error where a precision exceeds the
+	// number of decimal places an ltcutil.Amount can represent.
+	ErrInvalidPrecision = errors.New("invalid precision")
+
+	// ErrNegative describes an error where an amount which must not be
+	// negative is.
+	ErrNegative = errors.New("amount is negative")
+
+	// ErrExceedsMaxSupply describes an error where an amount exceeds the
+	// maximum supply of a network.
+	ErrExceedsMaxSupply = errors.New("amount exceeds the maximum supply")
+)
+
+// unitsPerCoin returns the number of base units of a coin of the passed
+// precision.
+func unitsPerCoin(precision uint8) (int64, error) {
+	if precision > maxPrecision {
+		return 0, ErrInvalidPrecision
+	}
+	units := int64(1)
+	for i := uint8(0); i < precision; i++ {
+		units *= 10
+	}
+	return units, nil
+}
+
+// Add returns the sum of a and b.
+func Add(a, b ltcutil.Amount) (ltcutil.Amount, error) {
+	if b > 0 && a > math.MaxInt64-b || b < 0 && a < math.MinInt64-b {
+		return 0, ErrOverflow
+	}
+	return a + b, nil
+}
+
+// Sub returns the difference of a and b.
+func Sub(a, b ltcutil.Amount) (ltcutil.Amount, error) {
+	if b < 0 && a > math.MaxInt64+b || b > 0 && a < math.MinInt64+b {
+		return 0, ErrOverflow
+	}
+	return a - b, nil
+}
+
+// MulInt returns the product of a and n.
+func MulInt(a ltcutil.Amount, n int64) (ltcutil.Amount, error) {
+	if a == 0 || n == 0 {
+		return 0, nil
+	}
+	product := a * ltcutil.Amount(n)
+	if product/ltcutil.Amount(n) != a || (a == -1 && n == math.MinInt64) ||
+		(n == -1 && a == math.MinInt64) {
+
+		return 0, ErrOverflow
+	}
+	return product, nil
+}
+
+// Sum returns the sum of the passed amounts.
+func Sum(amounts ...ltcutil.Amount) (ltcutil.Amount, error) {
+	var sum ltcutil.Amount
+	for _, a := range amounts {
+		var err error
+		sum, err = Add(sum, a)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return sum, nil
+}
+
+// Parse parses a decimal number of coins of the default precision, such as
+// "1.5" or "-0.00000001", into an amount.
+func Parse(s string) (ltcutil.Amount, error) {
+	return ParsePrecision(s, DefaultPrecision)
+}
+
+// ParsePrecision parses a decimal number of coins of the passed precision into
+// an amount.  Exponents and more decimal places than the precision are
+// rejected rather than rounded.
+func ParsePrecision(s string, precision uint8) (ltcutil.Amount, error) {
+	units, err := unitsPerCoin(precision)
+	if err != nil {
+		return 0, err
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+	whole, frac := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" && frac == "" || len(frac) > int(precision) ||
+		!isDigits(whole) || !isDigits(frac) {
+
+		return 0, ErrInvalidAmount
+	}
+	frac += strings.Repeat("0", int(precision)-len(frac))
+
+	var coins, fracUnits int64
+	if whole != "" {
+		coins, err = strconv.ParseInt(whole, 10, 64)
+		if err != nil {
+			return 0, ErrOverflow
+		}
+	}
+	if frac != "" {
+		fracUnits, err = strconv.ParseInt(frac, 10, 64)
+		if err != nil {
+			return 0, ErrInvalidAmount
+		}
+	}
+
+	a, err := MulInt(ltcutil.Amount(coins), units)
+	if err != nil {
+		return 0, err
+	}
+	a, err = Add(a, ltcutil.Amount(fracUnits))
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		a = -a
+	}
+	return a, nil
+}
+
+// Format formats the amount as an exact decimal number of coins of the passed
+// precision without trailing zeros.  Unlike ltcutil.Amount.Format, it does not
+// go through floating point and does not append a unit.
+func Format(a ltcutil.Amount, precision uint8) string {
+	// The magnitude is computed as an unsigned integer so the minimum
+	// amount can be negated.
+	magnitude := uint64(a)
+	if a < 0 {
+		magnitude = -magnitude
+	}
+	s := strconv.FormatUint(magnitude, 10)
+	if len(s) <= int(precision) {
+		s = strings.Repeat("0", int(precision)-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-int(precision)], s[len(s)-int(precision):]
+	frac = strings.TrimRight(frac, "0")
+
+	sign := ""
+	if a < 0 {
+		sign = "-"
+	}
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// MaxSupply returns the maximum number of base units which are ever created by
+// the block subsidies of the passed network, as calculated by
+// blockchain.CalcBlockSubsidy.  Networks which use a custom CalcSubsidy
+// function or whose subsidy never ends are bounded by ltcutil.MaxSatoshi
+// instead, which is the maximum amount of a transaction.
+func MaxSupply(params *chaincfg.Params) ltcutil.Amount {
+	const unbounded = ltcutil.Amount(ltcutil.MaxSatoshi)
+
+	if params.CalcSubsidy != nil {
+		return unbounded
+	}
+
+	// The subsidy only changes at the steps of the subsidy schedule or
+	// every SubsidyReductionInterval blocks, so the supply is the sum of
+	// the subsidy at the start of each era times the length of the era.
+	var supply ltcutil.Amount
+	addEra := func(start, end int64) bool {
+		subsidy := blockchain.CalcBlockSubsidy(int32(start), params)
+		eraTotal, err := MulInt(ltcutil.Amount(subsidy), end-start)
+		if err == nil {
+			supply, err = Add(supply, eraTotal)
+		}
+		return err == nil
+	}
+
+	if schedule := params.SubsidySchedule; len(schedule) > 0 {
+		for i, step := range schedule[:len(schedule)-1] {
+			next := schedule[i+1].Height
+			if !addEra(int64(step.Height), int64(next)) {
+				return unbounded
+			}
+		}
+		last := schedule[len(schedule)-1].Height
+		if blockchain.CalcBlockSubsidy(last, params) != 0 {
+			return unbounded
+		}
+		return supply
+	}
+
+	interval := int64(params.SubsidyReductionInterval)
+	if interval == 0 {
+		return unbounded
+	}
+	for start := int64(0); ; start += interval {
+		if start > math.MaxInt32 {
+			return unbounded
+		}
+		if blockchain.CalcBlockSubsidy(int32(start), params) == 0 {
+			return supply
+		}
+		if !addEra(start, start+interval) {
+			return unbounded
+		}
+	}
+}
+
+// Validate ensures the amount is not negative and does not exceed the maximum
+// supply of the passed network.
+func Validate(a ltcutil.Amount, params *chaincfg.Params) error {
+	if a < 0 {
+		return ErrNegative
+	}
+	if a > MaxSupply(params) {
+		return ErrExceedsMaxSupply
+	}
+	return nil
+}
+
+// isDigits returns whether the passed string only consists of decimal digits.
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
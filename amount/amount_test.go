@@ -0,0 +1,197 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package amount
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// TestArithmetic ensures the arithmetic methods return the expected results
+// and detect overflows.
+func TestArithmetic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		f    func() (ltcutil.Amount, error)
+		want ltcutil.Amount
+		err  error
+	}{
+		{"add", func() (ltcutil.Amount, error) { return Add(1, 2) }, 3, nil},
+		{"add negative", func() (ltcutil.Amount, error) { return Add(1, -2) }, -1, nil},
+		{"add overflow", func() (ltcutil.Amount, error) { return Add(math.MaxInt64, 1) }, 0, ErrOverflow},
+		{"add underflow", func() (ltcutil.Amount, error) { return Add(math.MinInt64, -1) }, 0, ErrOverflow},
+		{"sub", func() (ltcutil.Amount, error) { return Sub(1, 2) }, -1, nil},
+		{"sub overflow", func() (ltcutil.Amount, error) { return Sub(math.MaxInt64, -1) }, 0, ErrOverflow},
+		{"sub underflow", func() (ltcutil.Amount, error) { return Sub(math.MinInt64, 1) }, 0, ErrOverflow},
+		{"mul", func() (ltcutil.Amount, error) { return MulInt(-3, 4) }, -12, nil},
+		{"mul zero", func() (ltcutil.Amount, error) { return MulInt(math.MaxInt64, 0) }, 0, nil},
+		{"mul overflow", func() (ltcutil.Amount, error) { return MulInt(math.MaxInt64/2, 3) }, 0, ErrOverflow},
+		{"mul min", func() (ltcutil.Amount, error) { return MulInt(math.MinInt64, -1) }, 0, ErrOverflow},
+		{"sum", func() (ltcutil.Amount, error) { return Sum(1, 2, 3) }, 6, nil},
+		{"sum overflow", func() (ltcutil.Amount, error) { return Sum(math.MaxInt64, 1, -1) }, 0, ErrOverflow},
+	}
+
+	for _, test := range tests {
+		got, err := test.f()
+		if err != test.err || got != test.want {
+			t.Errorf("%s: got %d (err %v), want %d (err %v)", test.name,
+				int64(got), err, int64(test.want), test.err)
+		}
+	}
+}
+
+// TestParseFormat ensures decimal numbers of coins are parsed exactly and
+// formatted back without trailing zeros.
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in        string
+		precision uint8
+		want      ltcutil.Amount
+		formatted string
+	}{
+		{"0", 8, 0, "0"},
+		{"1", 8, 1e8, "1"},
+		{"1.5", 8, 1.5e8, "1.5"},
+		{".00000001", 8, 1, "0.00000001"},
+		{"-0.1", 8, -1e7, "-0.1"},
+		{"21000000.12345678", 8, 2100000012345678, "21000000.12345678"},
+		{"1.50", 2, 150, "1.5"},
+		{"7", 0, 7, "7"},
+		{"92233720368.54775807", 8, math.MaxInt64, "92233720368.54775807"},
+	}
+
+	for _, test := range tests {
+		got, err := ParsePrecision(test.in, test.precision)
+		if err != nil || got != test.want {
+			t.Errorf("ParsePrecision(%q, %d): got %d (err %v), want %d",
+				test.in, test.precision, int64(got), err,
+				int64(test.want))
+			continue
+		}
+		if formatted := Format(got, test.precision); formatted != test.formatted {
+			t.Errorf("Format(%d): got %s, want %s", test.precision,
+				formatted, test.formatted)
+		}
+	}
+
+	if s := Format(math.MinInt64, DefaultPrecision); s != "-92233720368.54775808" {
+		t.Errorf("Format: got %s for the minimum amount", s)
+	}
+}
+
+// TestParseErrors ensures malformed numbers and numbers which don't fit in an
+// amount are rejected.
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in        string
+		precision uint8
+		err       error
+	}{
+		{"", 8, ErrInvalidAmount},
+		{".", 8, ErrInvalidAmount},
+		{"-", 8, ErrInvalidAmount},
+		{"--1", 8, ErrInvalidAmount},
+		{"+1", 8, ErrInvalidAmount},
+		{"1e3", 8, ErrInvalidAmount},
+		{"1.2.3", 8, ErrInvalidAmount},
+		{"0.000000001", 8, ErrInvalidAmount},
+		{"0.1", 0, ErrInvalidAmount},
+		{"92233720368.54775808", 8, ErrOverflow},
+		{"99999999999999999999", 8, ErrOverflow},
+		{"1", 19, ErrInvalidPrecision},
+	}
+
+	for _, test := range tests {
+		if _, err := ParsePrecision(test.in, test.precision); err != test.err {
+			t.Errorf("ParsePrecision(%q, %d): got error %v, want %v",
+				test.in, test.precision, err, test.err)
+		}
+	}
+}
+
+// TestMaxSupply ensures the maximum supply is derived from the halving
+// interval or the subsidy schedule of a network and bounded otherwise.
+func TestMaxSupply(t *testing.T) {
+	t.Parallel()
+
+	halving := chaincfg.MainNetParams
+	halving.SubsidyReductionInterval = 2
+
+	schedule := chaincfg.MainNetParams
+	schedule.SubsidySchedule = []chaincfg.SubsidyStep{
+		{Height: 0, Subsidy: 10},
+		{Height: 5, Subsidy: 3},
+		{Height: 10, Subsidy: 0},
+	}
+
+	endless := schedule
+	endless.SubsidySchedule = schedule.SubsidySchedule[:2]
+
+	custom := chaincfg.MainNetParams
+	custom.CalcSubsidy = func(int32) int64 { return 1 }
+
+	tests := []struct {
+		name   string
+		params *chaincfg.Params
+		want   ltcutil.Amount
+	}{
+		// The subsidy halves until it is rounded down to zero, so the
+		// eras pay slightly less than twice the base subsidy.
+		{"halving", &halving, 2 * 9999999989},
+		{"schedule", &schedule, 5*10 + 5*3},
+		{"endless schedule", &endless, ltcutil.MaxSatoshi},
+		{"custom", &custom, ltcutil.MaxSatoshi},
+	}
+
+	for _, test := range tests {
+		if got := MaxSupply(test.params); got != test.want {
+			t.Errorf("%s: got %d, want %d", test.name, int64(got),
+				int64(test.want))
+		}
+	}
+
+	if got := MaxSupply(&chaincfg.MainNetParams); got > ltcutil.MaxSatoshi ||
+		got < ltcutil.MaxSatoshi-ltcutil.SatoshiPerBitcoin {
+
+		t.Errorf("mainnet: got unexpected maximum supply %v", got)
+	}
+}
+
+// TestValidate ensures amounts are validated against the maximum supply of a
+// network.
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	params := chaincfg.MainNetParams
+	params.SubsidySchedule = []chaincfg.SubsidyStep{
+		{Height: 0, Subsidy: 10},
+		{Height: 5, Subsidy: 0},
+	}
+
+	tests := []struct {
+		amount ltcutil.Amount
+		err    error
+	}{
+		{0, nil},
+		{50, nil},
+		{51, ErrExceedsMaxSupply},
+		{-1, ErrNegative},
+	}
+	for _, test := range tests {
+		if err := Validate(test.amount, &params); err != test.err {
+			t.Errorf("Validate(%d): got error %v, want %v",
+				int64(test.amount), err, test.err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package amount provides overflow-checked arithmetic, exact decimal parsing and
+formatting, and validation against the maximum supply of a network for the
+ltcutil.Amount type.
+
+# Overview
+
+An ltcutil.Amount is an integer number of base units, so amounts never suffer
+from the rounding errors of floating point numbers as long as they are not
+converted to them.  Decimal numbers of coins are parsed with Parse and
+formatted with Format without going through floating point.
+
+# Precision
+
+A coin is divided into 10^precision base units.  DefaultPrecision is the
+precision of Doriancoin, and ParsePrecision and Format accept the precision of
+coins and units of other precisions.
+
+# Arithmetic
+
+The Add, Sub, MulInt, and Sum functions return ErrOverflow instead of silently
+wrapping around when the result does not fit in an ltcutil.Amount.
+
+# Maximum Supply
+
+MaxSupply derives the maximum number of base units ever created by a network
+from the block subsidies blockchain.CalcBlockSubsidy calculates for its
+chaincfg.Params, which follow either the halving of the subsidy every
+SubsidyReductionInterval blocks or the SubsidySchedule of the network, and
+Validate ensures an amount is not negative and does not exceed it.
+*/
+package amount
@@ -5,6 +5,7 @@
 package blockchain
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ltcsuite/ltcd/database"
@@ -18,10 +19,13 @@ import (
 // ProcessBlock before calling this function with it.
 //
 // The flags are also passed to checkBlockContext and connectBestChain.  See
-// their documentation for how the flags modify their behavior.
+// their documentation for how the flags modify their behavior.  The context is
+// passed to connectBestChain so reorganizations can be cancelled.
 //
 // This function MUST be called with the chain state lock held (for writes).
-func (b *BlockChain) maybeAcceptBlock(block *ltcutil.Block, flags BehaviorFlags) (bool, error) {
+func (b *BlockChain) maybeAcceptBlock(ctx context.Context, block *ltcutil.Block,
+	flags BehaviorFlags) (bool, error) {
+
 	// The height of this block is one more than the referenced previous
 	// block.
 	prevHash := &block.MsgBlock().Header.PrevBlock
@@ -62,21 +66,25 @@ func (b *BlockChain) maybeAcceptBlock(block *ltcutil.Block, flags BehaviorFlags)
 
 	// Create a new block node for the block and add it to the node index. Even
 	// if the block ultimately gets connected to the main chain, it starts out
-	// on a side chain.
+	// on a side chain.  The node already exists when the header of the block
+	// was processed ahead of it, in which case it is marked as having its
+	// data stored instead.  The node is written to the database along with
+	// the rest of the changes once the block has been connected, or by
+	// connectBestChain when it is not.
 	blockHeader := &block.MsgBlock().Header
-	newNode := newBlockNode(blockHeader, prevNode)
-	newNode.status = statusDataStored
-
-	b.index.AddNode(newNode)
-	err = b.index.flushToDB()
-	if err != nil {
-		return false, err
+	newNode := b.index.LookupNode(block.Hash())
+	if newNode != nil {
+		b.index.SetStatusFlags(newNode, statusDataStored)
+	} else {
+		newNode = newBlockNode(blockHeader, prevNode)
+		newNode.status = statusDataStored
+		b.index.AddNode(newNode)
 	}
 
 	// Connect the passed block to the chain while respecting proper chain
 	// selection according to the chain with the most proof of work.  This
 	// also handles validation of the transaction scripts.
-	isMainChain, err := b.connectBestChain(newNode, block, flags)
+	isMainChain, err := b.connectBestChain(ctx, newNode, block, flags)
 	if err != nil {
 		return false, err
 	}
@@ -5,7 +5,6 @@
 package blockchain
 
 import (
-	"math/big"
 	"sort"
 	"sync"
 	"time"
@@ -68,8 +67,13 @@ type blockNode struct {
 	// definitions in this struct should not be changed without considering
 	// how it affects alignment on 64-bit platforms.  The current order is
 	// specifically crafted to result in minimal padding.  There will be
-	// hundreds of thousands of these in memory, so a few extra bytes of
-	// padding adds up.
+	// over a million of these in memory, so a few extra bytes of padding
+	// adds up.  The fields are kept inline and packed so a node fits in
+	// 128 bytes on 64-bit platforms without any further allocations.
+	//
+	// TestBlockNodeSize and BenchmarkBlockNodeMemory guard that size.  A
+	// smaller node would have to drop the merkle root or the chain work and
+	// read them back from the database when needed, which is not done.
 
 	// parent is the parent block for this node.
 	parent *blockNode
@@ -79,7 +83,7 @@ type blockNode struct {
 
 	// workSum is the total amount of work in the chain up to and including
 	// this node.
-	workSum *big.Int
+	workSum chainWork
 
 	// height is the position in the block chain.
 	height int32
@@ -87,11 +91,12 @@ type blockNode struct {
 	// Some fields from block headers to aid in best chain selection and
 	// reconstructing headers from memory.  These must be treated as
 	// immutable and are intentionally ordered to avoid padding on 64-bit
-	// platforms.
+	// platforms.  The timestamp is kept with the 32 bits it has in the
+	// serialized header.
 	version    int32
 	bits       uint32
 	nonce      uint32
-	timestamp  int64
+	timestamp  uint32
 	merkleRoot chainhash.Hash
 
 	// status is a bitfield representing the validation state of the block. The
@@ -108,17 +113,17 @@ type blockNode struct {
 func initBlockNode(node *blockNode, blockHeader *wire.BlockHeader, parent *blockNode) {
 	*node = blockNode{
 		hash:       blockHeader.BlockHash(),
-		workSum:    CalcWork(blockHeader.Bits),
+		workSum:    newChainWork(CalcWork(blockHeader.Bits)),
 		version:    blockHeader.Version,
 		bits:       blockHeader.Bits,
 		nonce:      blockHeader.Nonce,
-		timestamp:  blockHeader.Timestamp.Unix(),
+		timestamp:  uint32(blockHeader.Timestamp.Unix()),
 		merkleRoot: blockHeader.MerkleRoot,
 	}
 	if parent != nil {
 		node.parent = parent
 		node.height = parent.height + 1
-		node.workSum = node.workSum.Add(parent.workSum, node.workSum)
+		node.workSum = parent.workSum.add(&node.workSum)
 	}
 }
 
@@ -144,7 +149,7 @@ func (node *blockNode) Header() wire.BlockHeader {
 		Version:    node.version,
 		PrevBlock:  *prevHash,
 		MerkleRoot: node.merkleRoot,
-		Timestamp:  time.Unix(node.timestamp, 0),
+		Timestamp:  time.Unix(int64(node.timestamp), 0),
 		Bits:       node.bits,
 		Nonce:      node.nonce,
 	}
@@ -187,7 +192,7 @@ func (node *blockNode) Bits() uint32 {
 //
 // NOTE: Part of the HeaderCtx interface.
 func (node *blockNode) Timestamp() int64 {
-	return node.timestamp
+	return int64(node.timestamp)
 }
 
 // Parent returns the blockNode's parent.
@@ -377,30 +382,66 @@ func (bi *blockIndex) UnsetStatusFlags(node *blockNode, flags blockStatus) {
 	bi.Unlock()
 }
 
+// storeDirtyNodes writes all dirty block nodes to the database using the passed
+// transaction so they are committed atomically along with the other changes
+// made by it.  The returned nodes and the statuses which were written must be
+// passed to clearDirty once the transaction has been committed.
+//
+// This function is safe for concurrent access.
+func (bi *blockIndex) storeDirtyNodes(dbTx database.Tx) (map[*blockNode]blockStatus, error) {
+	bi.RLock()
+	defer bi.RUnlock()
+
+	if len(bi.dirty) == 0 {
+		return nil, nil
+	}
+	stored := make(map[*blockNode]blockStatus, len(bi.dirty))
+	for node := range bi.dirty {
+		err := dbStoreBlockNode(dbTx, node)
+		if err != nil {
+			return nil, err
+		}
+		stored[node] = node.status
+	}
+	return stored, nil
+}
+
+// clearDirty removes the passed nodes, which were written by storeDirtyNodes,
+// from the dirty set.  Nodes whose status changed since they were written are
+// left dirty so the change is written by a later flush.
+//
+// This function is safe for concurrent access.
+func (bi *blockIndex) clearDirty(stored map[*blockNode]blockStatus) {
+	bi.Lock()
+	for node, status := range stored {
+		if node.status == status {
+			delete(bi.dirty, node)
+		}
+	}
+	bi.Unlock()
+}
+
 // flushToDB writes all dirty block nodes to the database. If all writes
 // succeed, this clears the dirty set.
 func (bi *blockIndex) flushToDB() error {
-	bi.Lock()
-	if len(bi.dirty) == 0 {
-		bi.Unlock()
+	bi.RLock()
+	numDirty := len(bi.dirty)
+	bi.RUnlock()
+	if numDirty == 0 {
 		return nil
 	}
 
+	var stored map[*blockNode]blockStatus
 	err := bi.db.Update(func(dbTx database.Tx) error {
-		for node := range bi.dirty {
-			err := dbStoreBlockNode(dbTx, node)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
+		var err error
+		stored, err = bi.storeDirtyNodes(dbTx)
+		return err
 	})
-
-	// If write was successful, clear the dirty set.
-	if err == nil {
-		bi.dirty = make(map[*blockNode]struct{})
+	if err != nil {
+		return err
 	}
 
-	bi.Unlock()
-	return err
+	// The write was successful, so clear the dirty set.
+	bi.clearDirty(stored)
+	return nil
 }
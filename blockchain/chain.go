@@ -7,7 +7,10 @@ package blockchain
 
 import (
 	"container/list"
+	"context"
 	"fmt"
+	"math/big"
+	"sort"
 	"sync"
 	"time"
 
@@ -60,21 +63,26 @@ type orphanBlock struct {
 // However, the returned snapshot must be treated as immutable since it is
 // shared by all callers.
 type BestState struct {
-	Hash        chainhash.Hash // The hash of the block.
-	Height      int32          // The height of the block.
-	Bits        uint32         // The difficulty bits of the block.
-	BlockSize   uint64         // The size of the block.
-	BlockWeight uint64         // The weight of the block.
-	NumTxns     uint64         // The number of txns in the block.
-	TotalTxns   uint64         // The total number of txns in the chain.
-	MedianTime  time.Time      // Median time as per CalcPastMedianTime.
+	Hash           chainhash.Hash // The hash of the block.
+	Height         int32          // The height of the block.
+	Bits           uint32         // The difficulty bits of the block.
+	BlockSize      uint64         // The size of the block.
+	BlockWeight    uint64         // The weight of the block.
+	NumTxns        uint64         // The number of txns in the block.
+	TotalTxns      uint64         // The total number of txns in the chain.
+	MedianTime     time.Time      // Median time as per CalcPastMedianTime.
+	WorkSum        *big.Int       // The total work of the chain.
+	MwebHeaderHash chainhash.Hash // The MWEB header hash, zero without one.
+	MwebOutputs    uint64         // The number of outputs in the MWEB MMR.
 }
 
 // newBestState returns a new best stats instance for the given parameters.
+// The MWEB header is nil for blocks without an MWEB extension block.
 func newBestState(node *blockNode, blockSize, blockWeight, numTxns,
-	totalTxns uint64, medianTime time.Time) *BestState {
+	totalTxns uint64, medianTime time.Time,
+	mwebHeader *wire.MwebHeader) *BestState {
 
-	return &BestState{
+	state := &BestState{
 		Hash:        node.hash,
 		Height:      node.height,
 		Bits:        node.bits,
@@ -83,7 +91,13 @@ func newBestState(node *blockNode, blockSize, blockWeight, numTxns,
 		NumTxns:     numTxns,
 		TotalTxns:   totalTxns,
 		MedianTime:  medianTime,
+		WorkSum:     node.workSum.Big(),
 	}
+	if mwebHeader != nil {
+		state.MwebHeaderHash = *mwebHeader.Hash()
+		state.MwebOutputs = mwebHeader.OutputMMRSize
+	}
+	return state
 }
 
 // BlockChain provides functions for working with the litecoin block chain.
@@ -100,6 +114,7 @@ type BlockChain struct {
 	chainParams         *chaincfg.Params
 	timeSource          MedianTimeSource
 	sigCache            *txscript.SigCache
+	scriptCache         *txscript.ScriptCache
 	indexManager        IndexManager
 	hashCache           *txscript.HashCache
 
@@ -112,13 +127,23 @@ type BlockChain struct {
 	blocksPerRetarget   int32 // target timespan / target time per block
 
 	// chainLock protects concurrent access to the vast majority of the
-	// fields in this struct below this point.
+	// fields in this struct below this point.  It is held for the whole
+	// processing of a block, so read-mostly operations which only depend
+	// on the tip of the best chain, such as difficulty, deployment state
+	// and locator queries, don't take it.  They read the tip once from
+	// the best chain view, which has its own lock, and only use it and
+	// its ancestors since block nodes don't change once created.
 	chainLock sync.RWMutex
 
 	// pruneTarget is the size in bytes the database targets for when the node
 	// is pruned.
 	pruneTarget uint64
 
+	// staleForks tracks the removal of side chains which have fallen too
+	// far behind the main chain from the block index.  Its depth is set
+	// when the instance is created.
+	staleForks StaleForkStats
+
 	// These fields are related to the memory block index.  They both have
 	// their own locks, however they are often also protected by the chain
 	// lock to help prevent logic races when blocks are being processed.
@@ -174,12 +199,15 @@ type BlockChain struct {
 	warningCaches    []thresholdStateCache
 	deploymentCaches []thresholdStateCache
 
-	// The following fields are used to determine if certain warnings have
-	// already been shown.
+	// warnings holds the messages of the warnings which currently apply by
+	// their type.  It is protected by the warnings lock.
 	//
-	// unknownRulesWarned refers to warnings due to unknown rules being
-	// activated.
-	unknownRulesWarned bool
+	// bestInvalid is the block known to be invalid with the most work,
+	// which is used to warn about invalid chains with more work than the
+	// best chain.  It is protected by the chain lock.
+	warningsLock sync.RWMutex
+	warnings     map[WarningType]string
+	bestInvalid  *blockNode
 
 	// The notifications field stores a slice of callbacks to be executed on
 	// certain blockchain events.
@@ -562,6 +590,8 @@ func (b *BlockChain) getReorganizeNodes(node *blockNode) (*list.List, *list.List
 func (b *BlockChain) connectBlock(node *blockNode, block *ltcutil.Block,
 	view *UtxoViewpoint, stxos []SpentTxOut) error {
 
+	defer startTraceRegion(traceRegionConnectBlock).End()
+
 	// Make sure it's extending the end of the best chain.
 	prevHash := &block.MsgBlock().Header.PrevBlock
 	if !prevHash.IsEqual(&b.bestChain.Tip().hash) {
@@ -582,12 +612,10 @@ func (b *BlockChain) connectBlock(node *blockNode, block *ltcutil.Block,
 		if err := b.warnUnknownRuleActivations(node); err != nil {
 			return err
 		}
-	}
 
-	// Write any block status changes to DB before updating best state.
-	err := b.index.flushToDB()
-	if err != nil {
-		return err
+		// Warn if the majority of the recent blocks signal unknown
+		// version bits.
+		b.warnUnknownVersions(node)
 	}
 
 	// Generate a new best state snapshot that will be used to update the
@@ -600,10 +628,15 @@ func (b *BlockChain) connectBlock(node *blockNode, block *ltcutil.Block,
 	blockWeight := uint64(GetBlockWeight(block))
 	state := newBestState(node, blockSize, blockWeight, numTxns,
 		curTotalTxns+numTxns, CalcPastMedianTime(node),
+		block.MsgBlock().MwebHeader,
 	)
 
-	// Atomically insert info into the database.
-	err = b.db.Update(func(dbTx database.Tx) error {
+	// Atomically insert info into the database.  The block index updates,
+	// such as the status of the block, are written in the same transaction
+	// as the best state, utxo set and spend journal changes instead of a
+	// separate one.
+	var storedNodes map[*blockNode]blockStatus
+	err := b.db.Update(func(dbTx database.Tx) error {
 		// If the pruneTarget isn't 0, we should attempt to delete older blocks
 		// from the database.
 		if b.pruneTarget != 0 {
@@ -624,8 +657,15 @@ func (b *BlockChain) connectBlock(node *blockNode, block *ltcutil.Block,
 			}
 		}
 
+		// Write any block status changes along with the best state.
+		var err error
+		storedNodes, err = b.index.storeDirtyNodes(dbTx)
+		if err != nil {
+			return err
+		}
+
 		// Update best block state.
-		err := dbPutBestState(dbTx, state, node.workSum)
+		err = dbPutBestState(dbTx, state, node.workSum.Big())
 		if err != nil {
 			return err
 		}
@@ -667,6 +707,7 @@ func (b *BlockChain) connectBlock(node *blockNode, block *ltcutil.Block,
 	if err != nil {
 		return err
 	}
+	b.index.clearDirty(storedNodes)
 
 	// Prune fully spent entries and mark all entries in the view unmodified
 	// now that the modifications have been committed to the database.
@@ -717,12 +758,6 @@ func (b *BlockChain) disconnectBlock(node *blockNode, block *ltcutil.Block, view
 		return err
 	}
 
-	// Write any block status changes to DB before updating best state.
-	err = b.index.flushToDB()
-	if err != nil {
-		return err
-	}
-
 	// Generate a new best state snapshot that will be used to update the
 	// database and later memory if all database updates are successful.
 	b.stateLock.RLock()
@@ -733,11 +768,20 @@ func (b *BlockChain) disconnectBlock(node *blockNode, block *ltcutil.Block, view
 	blockWeight := uint64(GetBlockWeight(prevBlock))
 	newTotalTxns := curTotalTxns - uint64(len(block.MsgBlock().Transactions))
 	state := newBestState(prevNode, blockSize, blockWeight, numTxns,
-		newTotalTxns, CalcPastMedianTime(prevNode))
+		newTotalTxns, CalcPastMedianTime(prevNode),
+		prevBlock.MsgBlock().MwebHeader)
 
+	var storedNodes map[*blockNode]blockStatus
 	err = b.db.Update(func(dbTx database.Tx) error {
+		// Write any block status changes along with the best state.
+		var err error
+		storedNodes, err = b.index.storeDirtyNodes(dbTx)
+		if err != nil {
+			return err
+		}
+
 		// Update best block state.
-		err := dbPutBestState(dbTx, state, node.workSum)
+		err = dbPutBestState(dbTx, state, node.workSum.Big())
 		if err != nil {
 			return err
 		}
@@ -786,6 +830,7 @@ func (b *BlockChain) disconnectBlock(node *blockNode, block *ltcutil.Block, view
 	if err != nil {
 		return err
 	}
+	b.index.clearDirty(storedNodes)
 
 	// Prune fully spent entries and mark all entries in the view unmodified
 	// now that the modifications have been committed to the database.
@@ -831,10 +876,15 @@ func countSpentOutputs(block *ltcutil.Block) int {
 // the chain) and nodes the are being attached must be in forwards order
 // (think pushing them onto the end of the chain).
 //
+// The reorganization is abandoned with the error of the passed context when it
+// is done before the best chain has been modified.
+//
 // This function may modify node statuses in the block index without flushing.
 //
 // This function MUST be called with the chain state lock held (for writes).
-func (b *BlockChain) reorganizeChain(detachNodes, attachNodes *list.List) error {
+func (b *BlockChain) reorganizeChain(ctx context.Context, detachNodes,
+	attachNodes *list.List) error {
+
 	// Nothing to do if no reorganize nodes were provided.
 	if detachNodes.Len() == 0 && attachNodes.Len() == 0 {
 		return nil
@@ -883,6 +933,12 @@ func (b *BlockChain) reorganizeChain(detachNodes, attachNodes *list.List) error
 	view := NewUtxoViewpoint()
 	view.SetBestHash(&oldBest.hash)
 	for e := detachNodes.Front(); e != nil; e = e.Next() {
+		// Give up on the reorganization when requested since nothing
+		// has been changed yet.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		n := e.Value.(*blockNode)
 		var block *ltcutil.Block
 		err := b.db.View(func(dbTx database.Tx) error {
@@ -949,6 +1005,10 @@ func (b *BlockChain) reorganizeChain(detachNodes, attachNodes *list.List) error
 	// tweaking the chain and/or database.  This approach catches these
 	// issues before ever modifying the chain.
 	for e := attachNodes.Front(); e != nil; e = e.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		n := e.Value.(*blockNode)
 
 		var block *ltcutil.Block
@@ -1097,7 +1157,9 @@ func (b *BlockChain) reorganizeChain(detachNodes, attachNodes *list.List) error
 //     This is useful when using checkpoints.
 //
 // This function MUST be called with the chain state lock held (for writes).
-func (b *BlockChain) connectBestChain(node *blockNode, block *ltcutil.Block, flags BehaviorFlags) (bool, error) {
+func (b *BlockChain) connectBestChain(ctx context.Context, node *blockNode,
+	block *ltcutil.Block, flags BehaviorFlags) (bool, error) {
+
 	fastAdd := flags&BFFastAdd == BFFastAdd
 
 	flushIndexState := func() {
@@ -1126,17 +1188,12 @@ func (b *BlockChain) connectBestChain(node *blockNode, block *ltcutil.Block, fla
 		stxos := make([]SpentTxOut, 0, countSpentOutputs(block))
 		if !fastAdd {
 			err := b.checkConnectBlock(node, block, view, &stxos)
-			if err == nil {
-				b.index.SetStatusFlags(node, statusValid)
-			} else if _, ok := err.(RuleError); ok {
-				b.index.SetStatusFlags(node, statusValidateFailed)
-			} else {
-				return false, err
-			}
-
-			flushIndexState()
-
 			if err != nil {
+				if _, ok := err.(RuleError); ok {
+					b.index.SetStatusFlags(node,
+						statusValidateFailed)
+					flushIndexState()
+				}
 				return false, err
 			}
 		}
@@ -1156,12 +1213,21 @@ func (b *BlockChain) connectBestChain(node *blockNode, block *ltcutil.Block, fla
 			}
 		}
 
-		// Connect the block to the main chain.
+		// Connect the block to the main chain.  The block is marked
+		// valid beforehand so its status is written in the same
+		// database transaction as the rest of the changes.
+		wasValid := b.index.NodeStatus(node).KnownValid()
+		b.index.SetStatusFlags(node, statusValid)
 		err := b.connectBlock(node, block, view, stxos)
 		if err != nil {
 			// If we got hit with a rule error, then we'll mark
 			// that status of the block as invalid and flush the
 			// index state to disk before returning with the error.
+			// A block which was not known to be valid before is
+			// not until it is connected again.
+			if !wasValid {
+				b.index.UnsetStatusFlags(node, statusValid)
+			}
 			if _, ok := err.(RuleError); ok {
 				b.index.SetStatusFlags(
 					node, statusValidateFailed,
@@ -1173,12 +1239,10 @@ func (b *BlockChain) connectBestChain(node *blockNode, block *ltcutil.Block, fla
 			return false, err
 		}
 
-		// If this is fast add, or this block node isn't yet marked as
-		// valid, then we'll update its status and flush the state to
-		// disk again.
-		if fastAdd || !b.index.NodeStatus(node).KnownValid() {
-			b.index.SetStatusFlags(node, statusValid)
-			flushIndexState()
+		// Clear the warning about an invalid chain with more work once
+		// the best chain has caught up with it.
+		if b.bestInvalid != nil {
+			b.checkInvalidChainWork(nil)
 		}
 
 		return true, nil
@@ -1190,7 +1254,7 @@ func (b *BlockChain) connectBestChain(node *blockNode, block *ltcutil.Block, fla
 
 	// We're extending (or creating) a side chain, but the cumulative
 	// work for this new side chain is not enough to make it the new chain.
-	if node.workSum.Cmp(b.bestChain.Tip().workSum) <= 0 {
+	if node.workSum.Cmp(&b.bestChain.Tip().workSum) <= 0 {
 		// Log information about how the block is forking the chain.
 		fork := b.bestChain.FindFork(node)
 		if fork.hash.IsEqual(parentHash) {
@@ -1203,6 +1267,10 @@ func (b *BlockChain) connectBestChain(node *blockNode, block *ltcutil.Block, fla
 				node.hash, fork.height, fork.hash)
 		}
 
+		// The new node was only added to the block index in memory,
+		// so write it now that it is known to stay on a side chain.
+		flushIndexState()
+
 		return false, nil
 	}
 
@@ -1217,7 +1285,15 @@ func (b *BlockChain) connectBestChain(node *blockNode, block *ltcutil.Block, fla
 
 	// Reorganize the chain.
 	log.Infof("REORGANIZE: Block %v is causing a reorganize.", node.hash)
-	err := b.reorganizeChain(detachNodes, attachNodes)
+	err := b.reorganizeChain(ctx, detachNodes, attachNodes)
+
+	// Warn when the chain with more work turned out to be invalid since
+	// either this node or the network doesn't follow the consensus rules.
+	if b.index.NodeStatus(node).KnownInvalid() {
+		b.checkInvalidChainWork(node)
+	} else if b.bestInvalid != nil {
+		b.checkInvalidChainWork(nil)
+	}
 
 	// Either getReorganizeNodes or reorganizeChain could have made unsaved
 	// changes to the block index, so flush regardless of whether there was an
@@ -1235,13 +1311,22 @@ func (b *BlockChain) connectBestChain(node *blockNode, block *ltcutil.Block, fla
 // believe it is current are:
 //   - Latest block height is after the latest checkpoint (if enabled)
 //   - Latest block has a timestamp newer than 24 hours ago
+//   - Best chain has at least the minimum chain work (if configured)
 //
-// This function MUST be called with the chain state lock held (for reads).
+// This function is safe for concurrent access.
 func (b *BlockChain) isCurrent() bool {
 	// Not current if the latest main (best) chain height is before the
 	// latest known good checkpoint (when checkpoints are enabled).
+	tip := b.bestChain.Tip()
 	checkpoint := b.LatestCheckpoint()
-	if checkpoint != nil && b.bestChain.Tip().height < checkpoint.Height {
+	if checkpoint != nil && tip.height < checkpoint.Height {
+		return false
+	}
+
+	// Not current if the best chain does not yet have the minimum amount
+	// of work required by the network parameters.
+	minWork := b.chainParams.MinimumChainWork
+	if minWork != nil && tip.workSum.Big().Cmp(minWork) < 0 {
 		return false
 	}
 
@@ -1251,7 +1336,7 @@ func (b *BlockChain) isCurrent() bool {
 	// The chain appears to be current if none of the checks reported
 	// otherwise.
 	minus24Hours := b.timeSource.AdjustedTime().Add(-24 * time.Hour).Unix()
-	return b.bestChain.Tip().timestamp >= minus24Hours
+	return tip.Timestamp() >= minus24Hours
 }
 
 // IsCurrent returns whether or not the chain believes it is current.  Several
@@ -1259,15 +1344,36 @@ func (b *BlockChain) isCurrent() bool {
 // believe it is current are:
 //   - Latest block height is after the latest checkpoint (if enabled)
 //   - Latest block has a timestamp newer than 24 hours ago
+//   - Best chain has at least the minimum chain work (if configured)
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) IsCurrent() bool {
-	b.chainLock.RLock()
-	defer b.chainLock.RUnlock()
-
 	return b.isCurrent()
 }
 
+// hasMinimumChainWork returns whether or not the best chain has accumulated at
+// least the minimum amount of work required by the chain parameters.  It
+// always returns true when the chain parameters do not specify a minimum.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) hasMinimumChainWork() bool {
+	minWork := b.chainParams.MinimumChainWork
+	if minWork == nil {
+		return true
+	}
+	return b.bestChain.Tip().workSum.Big().Cmp(minWork) >= 0
+}
+
+// HasMinimumChainWork returns whether or not the best chain has accumulated at
+// least the minimum amount of work required by the chain parameters.  Until it
+// has, blocks and headers that don't extend a chain known to have that much
+// work should be treated with suspicion.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) HasMinimumChainWork() bool {
+	return b.hasMinimumChainWork()
+}
+
 // BestSnapshot returns information about the current best chain block and
 // related state as of the current point in time.  The returned instance must be
 // treated as immutable since it is shared by all callers.
@@ -1280,6 +1386,81 @@ func (b *BlockChain) BestSnapshot() *BestState {
 	return snapshot
 }
 
+// estimateVerificationProgress returns the estimated fraction of the
+// transactions of the network which have been verified given the number of
+// transactions in the best chain, the timestamps of the genesis block and tip of
+// the best chain and the current time, all in seconds.  The number of
+// transactions made since the tip is extrapolated from the average transaction
+// rate of the best chain.
+func estimateVerificationProgress(totalTxns uint64, genesisTime, tipTime,
+	now int64) float64 {
+
+	if totalTxns == 0 {
+		return 0
+	}
+
+	var txRate float64
+	if tipTime > genesisTime {
+		txRate = float64(totalTxns) / float64(tipTime-genesisTime)
+	}
+	var missingTxns float64
+	if now > tipTime {
+		missingTxns = float64(now-tipTime) * txRate
+	}
+	return float64(totalTxns) / (float64(totalTxns) + missingTxns)
+}
+
+// VerificationProgress returns an estimate of the fraction of the transactions
+// of the network which have been verified by the best chain, between zero and
+// one.  The transactions made since the tip of the best chain are estimated
+// from its cumulative transaction count and the time that passed since.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) VerificationProgress() float64 {
+	tip := b.bestChain.Tip()
+	genesisTime := b.bestChain.Genesis().Timestamp()
+
+	totalTxns := b.BestSnapshot().TotalTxns
+	now := b.timeSource.AdjustedTime().Unix()
+	return estimateVerificationProgress(totalTxns, genesisTime,
+		tip.Timestamp(), now)
+}
+
+// PruneHeight returns the height of the first block of the best chain whose
+// data is still stored in the database.  It is zero when no blocks have been
+// pruned.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) PruneHeight() (int32, error) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	// Blocks are pruned starting with the oldest block files, so the
+	// blocks of the best chain which are still stored are contiguous
+	// and end at its tip.
+	low, high := int32(0), b.bestChain.Tip().height
+	err := b.db.View(func(dbTx database.Tx) error {
+		for low < high {
+			mid := low + (high-low)/2
+			node := b.bestChain.NodeByHeight(mid)
+			exists, err := dbTx.HasBlock(&node.hash)
+			if err != nil {
+				return err
+			}
+			if exists {
+				high = mid
+			} else {
+				low = mid + 1
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return low, nil
+}
+
 // HeaderByHash returns the block header identified by the given hash or an
 // error if it doesn't exist. Note that this will return headers from both the
 // main and side chains.
@@ -1293,6 +1474,146 @@ func (b *BlockChain) HeaderByHash(hash *chainhash.Hash) (wire.BlockHeader, error
 	return node.Header(), nil
 }
 
+// ChainWorkByHash returns the total work of the chain ending with the block
+// identified by the given hash or an error if it doesn't exist.  Note that this
+// will return the work of both main and side chain blocks.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) ChainWorkByHash(hash *chainhash.Hash) (*big.Int, error) {
+	node := b.index.LookupNode(hash)
+	if node == nil {
+		return nil, fmt.Errorf("block %s is not known", hash)
+	}
+
+	return node.workSum.Big(), nil
+}
+
+// ChainTipStatus describes the validation state of the branch ending with a
+// chain tip.
+type ChainTipStatus int
+
+const (
+	// ChainTipActive indicates the tip of the main chain.
+	ChainTipActive ChainTipStatus = iota
+
+	// ChainTipValidFork indicates a side chain whose blocks are all known
+	// to be valid.
+	ChainTipValidFork
+
+	// ChainTipValidHeaders indicates a side chain whose blocks are stored
+	// but not fully validated yet.
+	ChainTipValidHeaders
+
+	// ChainTipHeadersOnly indicates a side chain for which only the headers
+	// are known.
+	ChainTipHeadersOnly
+
+	// ChainTipInvalid indicates a side chain which contains an invalid
+	// block.
+	ChainTipInvalid
+)
+
+// chainTipStatusStrings is a map of chain tip statuses back to their constant
+// names for pretty printing.
+var chainTipStatusStrings = map[ChainTipStatus]string{
+	ChainTipActive:       "active",
+	ChainTipValidFork:    "valid-fork",
+	ChainTipValidHeaders: "valid-headers",
+	ChainTipHeadersOnly:  "headers-only",
+	ChainTipInvalid:      "invalid",
+}
+
+// String returns the ChainTipStatus as the status name used by the
+// getchaintips RPC.
+func (status ChainTipStatus) String() string {
+	if s, ok := chainTipStatusStrings[status]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown ChainTipStatus (%d)", int(status))
+}
+
+// ChainTip describes a block in the block index without any known children.
+type ChainTip struct {
+	// Height is the height of the tip.
+	Height int32
+
+	// Hash is the hash of the tip.
+	Hash chainhash.Hash
+
+	// BranchLen is the number of blocks between the tip and the main chain,
+	// which is zero for the tip of the main chain.
+	BranchLen int32
+
+	// Status is the validation state of the branch ending with the tip.
+	Status ChainTipStatus
+}
+
+// ChainTips returns the tips of all known branches of the block index, which
+// includes the tip of the main chain as well as the tips of any side chains.
+// The tip of the main chain is always returned first.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) ChainTips() []ChainTip {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	// Every block of the main chain but its tip has a child, so only the
+	// side chain blocks need to be examined to find the other tips.
+	b.index.RLock()
+	var sideNodes []*blockNode
+	parents := make(map[*blockNode]struct{})
+	for _, node := range b.index.index {
+		if b.bestChain.Contains(node) {
+			continue
+		}
+		sideNodes = append(sideNodes, node)
+		parents[node.parent] = struct{}{}
+	}
+
+	tip := b.bestChain.Tip()
+	tips := []ChainTip{{
+		Height: tip.height,
+		Hash:   tip.hash,
+		Status: ChainTipActive,
+	}}
+	for _, node := range sideNodes {
+		if _, ok := parents[node]; ok {
+			continue
+		}
+
+		var status ChainTipStatus
+		switch {
+		case node.status.KnownInvalid():
+			status = ChainTipInvalid
+		case node.status.KnownValid():
+			status = ChainTipValidFork
+		case node.status.HaveData():
+			status = ChainTipValidHeaders
+		default:
+			status = ChainTipHeadersOnly
+		}
+
+		var branchLen int32
+		if fork := b.bestChain.FindFork(node); fork != nil {
+			branchLen = node.height - fork.height
+		}
+		tips = append(tips, ChainTip{
+			Height:    node.height,
+			Hash:      node.hash,
+			BranchLen: branchLen,
+			Status:    status,
+		})
+	}
+	b.index.RUnlock()
+
+	// Sort the side chain tips by descending height so the most competitive
+	// branches come first.
+	sort.Slice(tips[1:], func(i, j int) bool {
+		return tips[i+1].Height > tips[j+1].Height
+	})
+	return tips
+}
+
 // MainChainHasBlock returns whether or not the block with the given hash is in
 // the main chain.
 //
@@ -1311,11 +1632,8 @@ func (b *BlockChain) MainChainHasBlock(hash *chainhash.Hash) bool {
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) BlockLocatorFromHash(hash *chainhash.Hash) BlockLocator {
-	b.chainLock.RLock()
 	node := b.index.LookupNode(hash)
-	locator := b.bestChain.blockLocator(node)
-	b.chainLock.RUnlock()
-	return locator
+	return b.bestChain.BlockLocator(node)
 }
 
 // LatestBlockLocator returns a block locator for the latest known tip of the
@@ -1323,10 +1641,7 @@ func (b *BlockChain) BlockLocatorFromHash(hash *chainhash.Hash) BlockLocator {
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) LatestBlockLocator() (BlockLocator, error) {
-	b.chainLock.RLock()
-	locator := b.bestChain.BlockLocator(nil)
-	b.chainLock.RUnlock()
-	return locator, nil
+	return b.bestChain.BlockLocator(nil), nil
 }
 
 // BlockHeightByHash returns the height of the block with the given hash in the
@@ -1406,6 +1721,68 @@ func (b *BlockChain) HeightRange(startHeight, endHeight int32) ([]chainhash.Hash
 	return hashes, nil
 }
 
+// DifficultyHistoryEntry describes the proof of work of a block in the main
+// chain as returned by DifficultyHistory.
+type DifficultyHistoryEntry struct {
+	Height    int32
+	Hash      chainhash.Hash
+	Bits      uint32
+	Timestamp int64
+
+	// SolveTime is the number of seconds between the timestamps of the
+	// block and its parent, which is zero for the genesis block and may be
+	// negative since timestamps are not required to increase.
+	SolveTime int64
+}
+
+// DifficultyHistory returns the proof of work of the blocks of the main chain
+// between the given start and end heights, inclusive on both ends.  The end
+// height will be limited to the current main chain height.  The entries are
+// read from the block index, so no headers are loaded from the database.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) DifficultyHistory(startHeight, endHeight int32) ([]DifficultyHistoryEntry, error) {
+	// Ensure requested heights are sane.
+	if startHeight < 0 {
+		return nil, fmt.Errorf("start height of history must not be "+
+			"less than zero - got %d", startHeight)
+	}
+	if endHeight < startHeight {
+		return nil, fmt.Errorf("end height of history must not be less "+
+			"than the start height - got start %d, end %d",
+			startHeight, endHeight)
+	}
+
+	// Grab a lock on the chain view to prevent it from changing due to a
+	// reorg while collecting the entries.
+	b.bestChain.mtx.Lock()
+	defer b.bestChain.mtx.Unlock()
+
+	latestHeight := b.bestChain.tip().height
+	if startHeight > latestHeight {
+		return nil, nil
+	}
+	if endHeight > latestHeight {
+		endHeight = latestHeight
+	}
+
+	entries := make([]DifficultyHistoryEntry, 0, endHeight-startHeight+1)
+	for height := startHeight; height <= endHeight; height++ {
+		node := b.bestChain.nodeByHeight(height)
+		entry := DifficultyHistoryEntry{
+			Height:    height,
+			Hash:      node.hash,
+			Bits:      node.bits,
+			Timestamp: node.Timestamp(),
+		}
+		if node.parent != nil {
+			entry.SolveTime = node.Timestamp() - node.parent.Timestamp()
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
 // HeightToHashRange returns a range of block hashes for the given start height
 // and end hash, inclusive on both ends.  The hashes are for all blocks that are
 // ancestors of endHash with height greater than or equal to startHeight.  The
@@ -1487,9 +1864,9 @@ func (b *BlockChain) IntervalBlockHashes(endHash *chainhash.Hash, interval int,
 	return hashes, nil
 }
 
-// locateInventory returns the node of the block after the first known block in
-// the locator along with the number of subsequent nodes needed to either reach
-// the provided stop hash or the provided max number of entries.
+// locateInventory returns the nodes of the blocks after the first known block
+// in the locator until the provided stop hash is reached, or up to the provided
+// max number of entries.
 //
 // In addition, there are two special cases:
 //
@@ -1500,10 +1877,12 @@ func (b *BlockChain) IntervalBlockHashes(endHash *chainhash.Hash, interval int,
 //     after the genesis block will be returned
 //
 // This is primarily a helper function for the locateBlocks and locateHeaders
-// functions.
+// functions.  The nodes are taken from a single consistent view of the best
+// chain without the chain lock, so peers can be served while blocks are being
+// validated.
 //
-// This function MUST be called with the chain state lock held (for reads).
-func (b *BlockChain) locateInventory(locator BlockLocator, hashStop *chainhash.Hash, maxEntries uint32) (*blockNode, uint32) {
+// This function is safe for concurrent access.
+func (b *BlockChain) locateInventory(locator BlockLocator, hashStop *chainhash.Hash, maxEntries uint32) []*blockNode {
 	// There are no block locators so a specific block is being requested
 	// as identified by the stop hash.
 	stopNode := b.index.LookupNode(hashStop)
@@ -1511,43 +1890,21 @@ func (b *BlockChain) locateInventory(locator BlockLocator, hashStop *chainhash.H
 		if stopNode == nil {
 			// No blocks with the stop hash were found so there is
 			// nothing to do.
-			return nil, 0
+			return nil
 		}
-		return stopNode, 1
+		return []*blockNode{stopNode}
 	}
 
-	// Find the most recent locator block hash in the main chain.  In the
-	// case none of the hashes in the locator are in the main chain, fall
-	// back to the genesis block.
-	startNode := b.bestChain.Genesis()
+	// Look up the locator blocks up front so the nodes are found with a
+	// single lock of the best chain view.
+	locatorNodes := make([]*blockNode, 0, len(locator))
 	for _, hash := range locator {
-		node := b.index.LookupNode(hash)
-		if node != nil && b.bestChain.Contains(node) {
-			startNode = node
-			break
+		if node := b.index.LookupNode(hash); node != nil {
+			locatorNodes = append(locatorNodes, node)
 		}
 	}
 
-	// Start at the block after the most recently known block.  When there
-	// is no next block it means the most recently known block is the tip of
-	// the best chain, so there is nothing more to do.
-	startNode = b.bestChain.Next(startNode)
-	if startNode == nil {
-		return nil, 0
-	}
-
-	// Calculate how many entries are needed.
-	total := uint32((b.bestChain.Tip().height - startNode.height) + 1)
-	if stopNode != nil && b.bestChain.Contains(stopNode) &&
-		stopNode.height >= startNode.height {
-
-		total = uint32((stopNode.height - startNode.height) + 1)
-	}
-	if total > maxEntries {
-		total = maxEntries
-	}
-
-	return startNode, total
+	return b.bestChain.NodesAfter(locatorNodes, stopNode, maxEntries)
 }
 
 // locateBlocks returns the hashes of the blocks after the first known block in
@@ -1556,21 +1913,19 @@ func (b *BlockChain) locateInventory(locator BlockLocator, hashStop *chainhash.H
 //
 // See the comment on the exported function for more details on special cases.
 //
-// This function MUST be called with the chain state lock held (for reads).
+// This function is safe for concurrent access.
 func (b *BlockChain) locateBlocks(locator BlockLocator, hashStop *chainhash.Hash, maxHashes uint32) []chainhash.Hash {
-	// Find the node after the first known block in the locator and the
-	// total number of nodes after it needed while respecting the stop hash
-	// and max entries.
-	node, total := b.locateInventory(locator, hashStop, maxHashes)
-	if total == 0 {
+	// Find the nodes after the first known block in the locator while
+	// respecting the stop hash and max entries.
+	nodes := b.locateInventory(locator, hashStop, maxHashes)
+	if len(nodes) == 0 {
 		return nil
 	}
 
 	// Populate and return the found hashes.
-	hashes := make([]chainhash.Hash, 0, total)
-	for i := uint32(0); i < total; i++ {
+	hashes := make([]chainhash.Hash, 0, len(nodes))
+	for _, node := range nodes {
 		hashes = append(hashes, node.hash)
-		node = b.bestChain.Next(node)
 	}
 	return hashes
 }
@@ -1589,10 +1944,7 @@ func (b *BlockChain) locateBlocks(locator BlockLocator, hashStop *chainhash.Hash
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) LocateBlocks(locator BlockLocator, hashStop *chainhash.Hash, maxHashes uint32) []chainhash.Hash {
-	b.chainLock.RLock()
-	hashes := b.locateBlocks(locator, hashStop, maxHashes)
-	b.chainLock.RUnlock()
-	return hashes
+	return b.locateBlocks(locator, hashStop, maxHashes)
 }
 
 // locateHeaders returns the headers of the blocks after the first known block
@@ -1601,21 +1953,19 @@ func (b *BlockChain) LocateBlocks(locator BlockLocator, hashStop *chainhash.Hash
 //
 // See the comment on the exported function for more details on special cases.
 //
-// This function MUST be called with the chain state lock held (for reads).
+// This function is safe for concurrent access.
 func (b *BlockChain) locateHeaders(locator BlockLocator, hashStop *chainhash.Hash, maxHeaders uint32) []wire.BlockHeader {
-	// Find the node after the first known block in the locator and the
-	// total number of nodes after it needed while respecting the stop hash
-	// and max entries.
-	node, total := b.locateInventory(locator, hashStop, maxHeaders)
-	if total == 0 {
+	// Find the nodes after the first known block in the locator while
+	// respecting the stop hash and max entries.
+	nodes := b.locateInventory(locator, hashStop, maxHeaders)
+	if len(nodes) == 0 {
 		return nil
 	}
 
 	// Populate and return the found headers.
-	headers := make([]wire.BlockHeader, 0, total)
-	for i := uint32(0); i < total; i++ {
+	headers := make([]wire.BlockHeader, 0, len(nodes))
+	for _, node := range nodes {
 		headers = append(headers, node.Header())
-		node = b.bestChain.Next(node)
 	}
 	return headers
 }
@@ -1634,10 +1984,7 @@ func (b *BlockChain) locateHeaders(locator BlockLocator, hashStop *chainhash.Has
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) LocateHeaders(locator BlockLocator, hashStop *chainhash.Hash) []wire.BlockHeader {
-	b.chainLock.RLock()
-	headers := b.locateHeaders(locator, hashStop, wire.MaxBlockHeadersPerMsg)
-	b.chainLock.RUnlock()
-	return headers
+	return b.locateHeaders(locator, hashStop, wire.MaxBlockHeadersPerMsg)
 }
 
 // IndexManager provides a generic interface that the is called when blocks are
@@ -1710,6 +2057,14 @@ type Config struct {
 	// signature cache.
 	SigCache *txscript.SigCache
 
+	// ScriptCache defines a cache of transaction inputs whose scripts
+	// have already been validated.  Inputs found in the cache are not
+	// validated again when connecting blocks.
+	//
+	// This field can be nil if the caller is not interested in using a
+	// script cache.
+	ScriptCache *txscript.ScriptCache
+
 	// IndexManager defines an index manager to use when initializing the
 	// chain and connecting and disconnecting blocks.
 	//
@@ -1731,6 +2086,17 @@ type Config struct {
 	// will target for with block files.  Prune at 0 specifies that no
 	// blocks will be deleted.
 	Prune uint64
+
+	// StaleForkDepth specifies the number of blocks the tip of a side
+	// chain must be behind the tip of the main chain for the side chain to
+	// be removed from the block index.  Zero specifies that side chains
+	// are never removed.
+	StaleForkDepth int32
+
+	// StaleForkQuota specifies the number of bytes the deleted blocks of
+	// removed side chains may keep using in the block files before the
+	// files holding the most of them are rewritten to release the space.
+	StaleForkQuota uint64
 }
 
 // New returns a BlockChain instance using the provided configuration details.
@@ -1746,6 +2112,28 @@ func New(config *Config) (*BlockChain, error) {
 		return nil, AssertError("blockchain.New timesource is nil")
 	}
 
+	// Blocks are limited to the maximum block payload by the wire protocol
+	// regardless of the network, and the size of a block never exceeds its
+	// weight, so the block weight limit of the network can't be more than
+	// the maximum payload without producing blocks no peer can send.  This
+	// also keeps the limit of the non-witness data of blocks within
+	// MaxBlockBaseSize, which limits the size of transactions.
+	if BlockWeightLimit(config.ChainParams) > wire.MaxBlockPayload {
+		return nil, AssertError(fmt.Sprintf("blockchain.New max block "+
+			"weight of %d exceeds the maximum block payload of %d",
+			config.ChainParams.MaxBlockWeight,
+			wire.MaxBlockPayload))
+	}
+
+	// Strict DER signatures can't be both required and optional in every
+	// block.
+	if config.ChainParams.EnforceStrictDER &&
+		config.ChainParams.AllowNonStrictDER {
+
+		return nil, AssertError("blockchain.New chain parameters both " +
+			"enforce and allow non-strict DER signatures")
+	}
+
 	// Generate a checkpoint by height map from the provided checkpoints
 	// and assert the provided checkpoints are sorted by height as required.
 	var checkpointsByHeight map[int32]*chaincfg.Checkpoint
@@ -1775,6 +2163,7 @@ func New(config *Config) (*BlockChain, error) {
 		chainParams:         params,
 		timeSource:          config.TimeSource,
 		sigCache:            config.SigCache,
+		scriptCache:         config.ScriptCache,
 		indexManager:        config.IndexManager,
 		minRetargetTimespan: targetTimespan / adjustmentFactor,
 		maxRetargetTimespan: targetTimespan * adjustmentFactor,
@@ -1786,7 +2175,12 @@ func New(config *Config) (*BlockChain, error) {
 		prevOrphans:         make(map[chainhash.Hash][]*orphanBlock),
 		warningCaches:       newThresholdCaches(vbNumBits),
 		deploymentCaches:    newThresholdCaches(chaincfg.DefinedDeployments),
+		warnings:            make(map[WarningType]string),
 		pruneTarget:         config.Prune,
+		staleForks: StaleForkStats{
+			Depth: config.StaleForkDepth,
+			Quota: config.StaleForkQuota,
+		},
 	}
 
 	// Ensure all the deployments are synchronized with our clock if
@@ -1829,6 +2223,23 @@ func New(config *Config) (*BlockChain, error) {
 		return nil, err
 	}
 
+	// Notify the subscribers when the time source finds the local clock to
+	// be skewed since blocks with valid timestamps might be rejected then.
+	if timeSource, ok := config.TimeSource.(*medianTime); ok {
+		timeSource.setSkewCallback(func(skew *ClockSkew) {
+			b.sendNotification(NTClockSkew, skew)
+
+			var message string
+			if skew.Skewed {
+				message = fmt.Sprintf("The median time of %d "+
+					"peers differs from the local clock by %v, "+
+					"please check your date and time are correct",
+					skew.NumSamples, skew.MedianOffset)
+			}
+			b.SetWarning(WarningClockSkew, message)
+		})
+	}
+
 	bestNode := b.bestChain.Tip()
 	log.Infof("Chain state (height %d, hash %v, totaltx %d, work %v)",
 		bestNode.height, bestNode.hash, b.stateSnapshot.TotalTxns,
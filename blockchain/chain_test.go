@@ -5,6 +5,8 @@
 package blockchain
 
 import (
+	"fmt"
+	"math/big"
 	"reflect"
 	"testing"
 	"time"
@@ -893,6 +895,57 @@ func TestHeightToHashRange(t *testing.T) {
 	}
 }
 
+// TestDifficultyHistory ensures the proof of work of a range of main chain
+// blocks is read from the block index as expected.
+func TestDifficultyHistory(t *testing.T) {
+	// Construct a synthetic block chain whose blocks are solved in 60, 90
+	// and then -30 seconds.
+	chain := newFakeChain(&chaincfg.MainNetParams)
+	genesis := chain.bestChain.Genesis()
+	timestamp := time.Unix(genesis.Timestamp(), 0)
+	node := genesis
+	for i, solveTime := range []int64{60, 90, -30} {
+		timestamp = timestamp.Add(time.Duration(solveTime) * time.Second)
+		node = newFakeNode(node, 4, 0x1d00ffff-uint32(i), timestamp)
+		chain.index.AddNode(node)
+	}
+	chain.bestChain.SetTip(node)
+
+	entries, err := chain.DifficultyHistory(0, 10)
+	if err != nil {
+		t.Fatalf("DifficultyHistory: unexpected error: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("DifficultyHistory: got %d entries, want 4",
+			len(entries))
+	}
+	wantSolveTimes := []int64{0, 60, 90, -30}
+	for i, entry := range entries {
+		node := chain.bestChain.NodeByHeight(int32(i))
+		if entry.Height != int32(i) || entry.Hash != node.hash ||
+			entry.Bits != node.bits || entry.Timestamp != node.Timestamp() ||
+			entry.SolveTime != wantSolveTimes[i] {
+
+			t.Fatalf("DifficultyHistory: unexpected entry %+v at "+
+				"height %d", entry, i)
+		}
+	}
+
+	// A range starting past the tip is empty, while invalid ranges are
+	// rejected.
+	entries, err = chain.DifficultyHistory(4, 10)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("DifficultyHistory: unexpected result %v (err %v) past "+
+			"the tip", entries, err)
+	}
+	if _, err := chain.DifficultyHistory(-1, 2); err == nil {
+		t.Fatalf("DifficultyHistory: negative start height accepted")
+	}
+	if _, err := chain.DifficultyHistory(2, 1); err == nil {
+		t.Fatalf("DifficultyHistory: end before start accepted")
+	}
+}
+
 // TestIntervalBlockHashes ensures that fetching block hashes at specified
 // intervals by end hash works as expected.
 func TestIntervalBlockHashes(t *testing.T) {
@@ -964,3 +1017,299 @@ func TestIntervalBlockHashes(t *testing.T) {
 		}
 	}
 }
+
+// TestHasMinimumChainWork ensures the best chain is only reported as having
+// the minimum chain work once its accumulated work reaches the amount set in
+// the chain parameters.
+func TestHasMinimumChainWork(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	chain := newFakeChain(&params)
+	nodes := make([]*blockNode, 10)
+	tip := chain.bestChain.Genesis()
+	for i := range nodes {
+		nodes[i] = newFakeNode(tip, 1, params.PowLimitBits,
+			time.Unix(tip.Timestamp()+1, 0))
+		tip = nodes[i]
+	}
+
+	// No minimum configured means any chain qualifies.
+	if !chain.HasMinimumChainWork() {
+		t.Fatal("HasMinimumChainWork: want true without a minimum")
+	}
+
+	// Require the work of the sixth block in the chain.
+	params.MinimumChainWork = nodes[5].workSum.Big()
+	tests := []struct {
+		tip  *blockNode
+		want bool
+	}{
+		{tip: nodes[0], want: false},
+		{tip: nodes[4], want: false},
+		{tip: nodes[5], want: true},
+		{tip: nodes[9], want: true},
+	}
+	for i, test := range tests {
+		chain.bestChain.SetTip(test.tip)
+		if got := chain.HasMinimumChainWork(); got != test.want {
+			t.Errorf("HasMinimumChainWork #%d (height %d): got %v, "+
+				"want %v", i, test.tip.height, got, test.want)
+		}
+	}
+}
+
+// TestIsCurrentMinimumChainWork ensures the chain is never considered current
+// while the best chain is below the minimum chain work of the network.
+func TestIsCurrentMinimumChainWork(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	chain := newFakeChain(&params)
+	nodes := make([]*blockNode, 10)
+	tip := chain.bestChain.Genesis()
+	now := time.Now()
+	for i := range nodes {
+		nodes[i] = newFakeNode(tip, 1, params.PowLimitBits,
+			now.Add(time.Duration(i-len(nodes))*time.Second))
+		tip = nodes[i]
+	}
+
+	// Recent tips are current when no minimum is configured.
+	chain.bestChain.SetTip(nodes[2])
+	if !chain.IsCurrent() {
+		t.Fatal("IsCurrent: want true without a minimum")
+	}
+
+	params.MinimumChainWork = nodes[5].workSum.Big()
+	tests := []struct {
+		tip  *blockNode
+		want bool
+	}{
+		{tip: nodes[2], want: false},
+		{tip: nodes[4], want: false},
+		{tip: nodes[5], want: true},
+		{tip: nodes[9], want: true},
+	}
+	for i, test := range tests {
+		chain.bestChain.SetTip(test.tip)
+		if got := chain.IsCurrent(); got != test.want {
+			t.Errorf("IsCurrent #%d (height %d): got %v, want %v", i,
+				test.tip.height, got, test.want)
+		}
+	}
+}
+
+// TestEstimateVerificationProgress ensures the verification progress is
+// extrapolated from the average transaction rate of the chain.
+func TestEstimateVerificationProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		totalTxns uint64
+		genesis   int64
+		tip       int64
+		now       int64
+		want      float64
+	}{
+		{name: "no transactions", totalTxns: 0, genesis: 0, tip: 100,
+			now: 200, want: 0},
+		{name: "tip at now", totalTxns: 1000, genesis: 0, tip: 200,
+			now: 200, want: 1},
+		{name: "half way", totalTxns: 1000, genesis: 0, tip: 100,
+			now: 200, want: 0.5},
+		{name: "tip in the future", totalTxns: 1000, genesis: 0,
+			tip: 300, now: 200, want: 1},
+	}
+	for _, test := range tests {
+		got := estimateVerificationProgress(test.totalTxns, test.genesis,
+			test.tip, test.now)
+		if got < test.want-1e-9 || got > test.want+1e-9 {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+// TestChainTips ensures the tips of all branches of the block index are
+// returned along with their branch lengths and statuses.
+func TestChainTips(t *testing.T) {
+	// Construct a synthetic block chain with a block index consisting of
+	// the following structure.
+	// 	genesis -> 1 -> 2 -> ... -> 15 -> 16  -> 17  -> 18
+	// 	                              \-> 16a -> 17a
+	// 	                    \-> 11b
+	// 	               \-> 6c
+	tip := tstTip
+	chain := newFakeChain(&chaincfg.MainNetParams)
+	branch0Nodes := chainedNodes(chain.bestChain.Genesis(), 18)
+	branch1Nodes := chainedNodes(branch0Nodes[14], 2)
+	branch2Nodes := chainedNodes(branch0Nodes[9], 1)
+	branch3Nodes := chainedNodes(branch0Nodes[4], 1)
+	for _, nodes := range [][]*blockNode{branch0Nodes, branch1Nodes,
+		branch2Nodes, branch3Nodes} {
+
+		for _, node := range nodes {
+			chain.index.AddNode(node)
+		}
+	}
+	chain.bestChain.SetTip(tip(branch0Nodes))
+
+	for _, node := range branch1Nodes {
+		chain.index.SetStatusFlags(node, statusDataStored|statusValid)
+	}
+	chain.index.SetStatusFlags(branch2Nodes[0], statusDataStored)
+	chain.index.SetStatusFlags(branch3Nodes[0],
+		statusDataStored|statusValidateFailed)
+
+	want := []ChainTip{{
+		Height: 18,
+		Hash:   tip(branch0Nodes).hash,
+		Status: ChainTipActive,
+	}, {
+		Height:    17,
+		Hash:      tip(branch1Nodes).hash,
+		BranchLen: 2,
+		Status:    ChainTipValidFork,
+	}, {
+		Height:    11,
+		Hash:      tip(branch2Nodes).hash,
+		BranchLen: 1,
+		Status:    ChainTipValidHeaders,
+	}, {
+		Height:    6,
+		Hash:      tip(branch3Nodes).hash,
+		BranchLen: 1,
+		Status:    ChainTipInvalid,
+	}}
+	got := chain.ChainTips()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ChainTips: mismatched tips -- got %v, want %v", got,
+			want)
+	}
+}
+
+// TestBestSnapshot ensures the best state snapshot reports the total work,
+// median time and MWEB state of the tip of the main chain.
+func TestBestSnapshot(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("bestsnapshot",
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+
+	genesis := chain.BestSnapshot()
+	timestamp := time.Unix(chaincfg.RegressionNetParams.GenesisBlock.
+		Header.Timestamp.Unix()+60, 0)
+	block := solveTestBlock(t, &genesis.Hash, 1, timestamp)
+	if _, _, err := chain.ProcessBlock(block, BFNone); err != nil {
+		t.Fatalf("ProcessBlock: unexpected error: %v", err)
+	}
+
+	best := chain.BestSnapshot()
+	wantWork := new(big.Int).Add(genesis.WorkSum,
+		CalcWork(block.MsgBlock().Header.Bits))
+	if best.WorkSum.Cmp(wantWork) != 0 {
+		t.Errorf("work sum: got %v, want %v", best.WorkSum, wantWork)
+	}
+	if best.MedianTime != CalcPastMedianTime(chain.bestChain.Tip()) {
+		t.Errorf("median time: got %v, want %v", best.MedianTime,
+			CalcPastMedianTime(chain.bestChain.Tip()))
+	}
+	if best.MwebHeaderHash != (chainhash.Hash{}) || best.MwebOutputs != 0 {
+		t.Errorf("MWEB state: got header hash %v and %d outputs for a "+
+			"block without MWEB", best.MwebHeaderHash, best.MwebOutputs)
+	}
+
+	// The MWEB state is taken from the MWEB header of the block.
+	mwebHeader := &wire.MwebHeader{Height: 1, OutputMMRSize: 42}
+	state := newBestState(chain.bestChain.Tip(), 0, 0, 1, 2, best.MedianTime,
+		mwebHeader)
+	if state.MwebHeaderHash != *mwebHeader.Hash() || state.MwebOutputs != 42 {
+		t.Errorf("MWEB state: got header hash %v and %d outputs, want "+
+			"%v and 42", state.MwebHeaderHash, state.MwebOutputs,
+			mwebHeader.Hash())
+	}
+}
+
+// TestReadsDuringBlockProcessing ensures the read-mostly queries about the best
+// chain don't wait for the chain lock, which is held while blocks are
+// processed.
+func TestReadsDuringBlockProcessing(t *testing.T) {
+	chain := newFakeChain(&chaincfg.RegressionNetParams)
+	nodes := chainedNodes(chain.bestChain.Genesis(), 20)
+	for _, node := range nodes {
+		chain.index.AddNode(node)
+	}
+	tip := nodes[len(nodes)-1]
+	chain.bestChain.SetTip(tip)
+	chain.stateSnapshot = newBestState(tip, 0, 0, 1, uint64(len(nodes)),
+		CalcPastMedianTime(tip), nil)
+
+	chain.chainLock.Lock()
+	defer chain.chainLock.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		if _, err := chain.CalcNextRequiredDifficulty(time.Now()); err != nil {
+			done <- err
+			return
+		}
+		if _, err := chain.ThresholdState(chaincfg.DeploymentTestDummy); err != nil {
+			done <- err
+			return
+		}
+		if _, _, err := chain.ThresholdStateSince(chaincfg.DeploymentTestDummy); err != nil {
+			done <- err
+			return
+		}
+		if _, err := chain.CalcNextBlockVersion(); err != nil {
+			done <- err
+			return
+		}
+		chain.IsCurrent()
+		chain.HasMinimumChainWork()
+		chain.VerificationProgress()
+		chain.BestSnapshot()
+		locator := chain.BlockLocatorFromHash(&nodes[5].hash)
+		if hashes := chain.LocateBlocks(locator, &zeroHash,
+			wire.MaxBlocksPerMsg); len(hashes) != 14 {
+			done <- fmt.Errorf("located %d blocks, want 14", len(hashes))
+			return
+		}
+		if _, err := chain.LatestBlockLocator(); err != nil {
+			done <- err
+			return
+		}
+		headers := chain.LocateHeaders(locator, &zeroHash)
+		if len(headers) != 14 {
+			done <- fmt.Errorf("located %d headers, want 14", len(headers))
+			return
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("queries blocked on the chain lock")
+	}
+}
+
+// TestNewBlockWeightLimit ensures a chain can't be created for a network whose
+// block weight limit exceeds the maximum block payload of the wire protocol.
+func TestNewBlockWeightLimit(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	params.MaxBlockWeight = wire.MaxBlockPayload + 1
+	_, teardownFunc, err := chainSetup("newblockweightlimit", &params)
+	if err == nil {
+		teardownFunc()
+		t.Fatal("chainSetup: expected error for a block weight limit " +
+			"above the maximum block payload")
+	}
+
+	params.MaxBlockWeight = wire.MaxBlockPayload / 2
+	_, teardownFunc, err = chainSetup("newblockweightlimit", &params)
+	if err != nil {
+		t.Fatalf("chainSetup: unexpected error: %v", err)
+	}
+	teardownFunc()
+}
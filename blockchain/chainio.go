@@ -1035,7 +1035,8 @@ func (b *BlockChain) createChainState() error {
 	blockSize := uint64(genesisBlock.MsgBlock().SerializeSize())
 	blockWeight := uint64(GetBlockWeight(genesisBlock))
 	b.stateSnapshot = newBestState(node, blockSize, blockWeight, numTxns,
-		numTxns, time.Unix(node.timestamp, 0))
+		numTxns, time.Unix(node.Timestamp(), 0),
+		genesisBlock.MsgBlock().MwebHeader)
 
 	// Create the initial the database chain state including creating the
 	// necessary index buckets and inserting the genesis block.
@@ -1102,7 +1103,7 @@ func (b *BlockChain) createChainState() error {
 		}
 
 		// Store the current best chain state into the database.
-		err = dbPutBestState(dbTx, b.stateSnapshot, node.workSum)
+		err = dbPutBestState(dbTx, b.stateSnapshot, node.workSum.Big())
 		if err != nil {
 			return err
 		}
@@ -1251,7 +1252,8 @@ func (b *BlockChain) initChainState() error {
 		blockWeight := uint64(GetBlockWeight(ltcutil.NewBlock(&block)))
 		numTxns := uint64(len(block.Transactions))
 		b.stateSnapshot = newBestState(tip, blockSize, blockWeight,
-			numTxns, state.totalTxns, CalcPastMedianTime(tip))
+			numTxns, state.totalTxns, CalcPastMedianTime(tip),
+			block.MwebHeader)
 
 		return nil
 	})
@@ -10,7 +10,9 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/database"
 	"github.com/ltcsuite/ltcd/wire"
 )
@@ -719,3 +721,74 @@ func TestBestChainStateDeserializeErrors(t *testing.T) {
 		}
 	}
 }
+
+// TestFetchUtxoSetStats ensures the statistics of the utxo set account for the
+// outputs of each block connected to the main chain.
+func TestFetchUtxoSetStats(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("fetchutxosetstats",
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+
+	// The output of the genesis block is not part of the utxo set.
+	stats, err := chain.FetchUtxoSetStats(nil, nil)
+	if err != nil {
+		t.Fatalf("FetchUtxoSetStats: unexpected error: %v", err)
+	}
+	genesis := chain.BestSnapshot()
+	if stats.Hash != genesis.Hash || stats.Height != 0 || stats.TxOuts != 0 {
+		t.Fatalf("unexpected stats for the genesis block: %+v", stats)
+	}
+
+	timestamp := chaincfg.RegressionNetParams.GenesisBlock.Header.Timestamp
+	for height := int32(1); height <= 3; height++ {
+		best := chain.BestSnapshot()
+		timestamp = timestamp.Add(time.Minute)
+		block := solveTestBlock(t, &best.Hash, height, timestamp)
+		if _, _, err := chain.ProcessBlock(block, BFNone); err != nil {
+			t.Fatalf("ProcessBlock: unexpected error: %v", err)
+		}
+	}
+
+	var lastDone, lastTotal int64
+	stats, err = chain.FetchUtxoSetStats(nil, func(done, total int64) {
+		if done < lastDone {
+			t.Errorf("progress went backwards from %d to %d",
+				lastDone, done)
+		}
+		lastDone, lastTotal = done, total
+	})
+	if err != nil {
+		t.Fatalf("FetchUtxoSetStats: unexpected error: %v", err)
+	}
+	if lastDone != lastTotal || lastTotal == 0 {
+		t.Fatalf("final progress is %d of %d", lastDone, lastTotal)
+	}
+	best := chain.BestSnapshot()
+	if stats.Hash != best.Hash || stats.Height != 3 {
+		t.Fatalf("stats are as of block %v at height %d, want %v",
+			stats.Hash, stats.Height, best.Hash)
+	}
+	if stats.Transactions != 3 || stats.TxOuts != 3 {
+		t.Fatalf("got %d transactions and %d outputs, want 3 and 3",
+			stats.Transactions, stats.TxOuts)
+	}
+	if stats.BogoSize != 3*(utxoBogoSize+1) || stats.DiskSize == 0 {
+		t.Fatalf("unexpected sizes: bogo size %d, disk size %d",
+			stats.BogoSize, stats.DiskSize)
+	}
+	if stats.TotalAmount != 0 || stats.MwebPeggedAmount != 0 {
+		t.Fatalf("unexpected amounts: total %d, MWEB %d",
+			stats.TotalAmount, stats.MwebPeggedAmount)
+	}
+
+	// The scan stops once an interrupt is requested.
+	interrupt := make(chan struct{})
+	close(interrupt)
+	if _, err := chain.FetchUtxoSetStats(interrupt, nil); err != errInterruptRequested {
+		t.Fatalf("FetchUtxoSetStats: got error %v, want %v", err,
+			errInterruptRequested)
+	}
+}
@@ -281,6 +281,54 @@ func (c *chainView) Next(node *blockNode) *blockNode {
 	return next
 }
 
+// NodesAfter returns the nodes of the chain view after the most recent of the
+// passed locator nodes which is in the view, or after the genesis block when
+// none of them are, until the passed stop node is reached or up to the passed
+// max number of entries.  The stop node is ignored when it is nil or not in
+// the view.
+//
+// This function is safe for concurrent access.
+func (c *chainView) NodesAfter(locatorNodes []*blockNode, stopNode *blockNode,
+	maxEntries uint32) []*blockNode {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// Find the most recent locator node in the view.
+	startNode := c.genesis()
+	for _, node := range locatorNodes {
+		if c.contains(node) {
+			startNode = node
+			break
+		}
+	}
+
+	// Start at the node after the most recently known node.  When there
+	// is no next node it means the most recently known node is the tip of
+	// the view, so there is nothing more to do.
+	startNode = c.next(startNode)
+	if startNode == nil {
+		return nil
+	}
+
+	// Calculate how many entries are needed.
+	total := uint32((c.tip().height - startNode.height) + 1)
+	if stopNode != nil && c.contains(stopNode) &&
+		stopNode.height >= startNode.height {
+
+		total = uint32((stopNode.height - startNode.height) + 1)
+	}
+	if total > maxEntries {
+		total = maxEntries
+	}
+
+	// The nodes are copied since the backing array of the view is reused
+	// when its tip changes.
+	nodes := make([]*blockNode, total)
+	copy(nodes, c.nodes[startNode.height:])
+	return nodes
+}
+
 // findFork returns the final common block between the provided node and the
 // the chain view.  It will return nil if there is no common block.  This only
 // differs from the exported version in that it is up to the caller to ensure
@@ -0,0 +1,79 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// chainWork is the total amount of work in a chain up to and including a block
+// as a fixed-size 256-bit unsigned integer with the least significant word
+// first.  It is kept inline in every block node instead of a *big.Int, which
+// would cost a pointer along with two separate heap allocations per node.
+type chainWork [4]uint64
+
+// newChainWork returns the passed amount of work as a chain work value.  Work
+// which does not fit in 256 bits saturates to the maximum value, which can't
+// happen in practice since the work of a single block is less than 2^256.
+func newChainWork(work *big.Int) chainWork {
+	if work.Sign() <= 0 {
+		return chainWork{}
+	}
+	if work.BitLen() > 256 {
+		return chainWork{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}
+	}
+
+	var buf [32]byte
+	work.FillBytes(buf[:])
+	var w chainWork
+	for i := range w {
+		w[i] = binary.BigEndian.Uint64(buf[32-8*(i+1):])
+	}
+	return w
+}
+
+// add returns the sum of the chain work and the passed chain work, saturating
+// to the maximum value on overflow.
+func (w *chainWork) add(other *chainWork) chainWork {
+	var sum chainWork
+	var carry uint64
+	for i := range sum {
+		sum[i], carry = bits.Add64(w[i], other[i], carry)
+	}
+	if carry != 0 {
+		return chainWork{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}
+	}
+	return sum
+}
+
+// Cmp compares the chain work to the passed chain work and returns -1, 0 or +1
+// depending on whether it is less than, equal to or greater than it.
+func (w *chainWork) Cmp(other *chainWork) int {
+	for i := len(w) - 1; i >= 0; i-- {
+		switch {
+		case w[i] < other[i]:
+			return -1
+		case w[i] > other[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Big returns the chain work as a new big integer.
+func (w *chainWork) Big() *big.Int {
+	var buf [32]byte
+	for i := range w {
+		binary.BigEndian.PutUint64(buf[32-8*(i+1):], w[i])
+	}
+	return new(big.Int).SetBytes(buf[:])
+}
+
+// String returns the chain work in decimal.
+func (w chainWork) String() string {
+	return w.Big().String()
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"math/big"
+	"runtime"
+	"testing"
+	"unsafe"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+// TestChainWork ensures the fixed-size chain work values convert to and from
+// big integers and add and compare like them.
+func TestChainWork(t *testing.T) {
+	maxWork := new(big.Int).Sub(new(big.Int).Lsh(bigOne, 256), bigOne)
+	values := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		CalcWork(0x1e0ffff0),
+		CalcWork(0x1b0404cb),
+		CalcWork(0x207fffff),
+		new(big.Int).Lsh(bigOne, 64),
+		new(big.Int).Sub(new(big.Int).Lsh(bigOne, 128), bigOne),
+		new(big.Int).Lsh(bigOne, 255),
+	}
+	for _, a := range values {
+		wa := newChainWork(a)
+		if got := wa.Big(); got.Cmp(a) != 0 {
+			t.Errorf("newChainWork(%v): round trip got %v", a, got)
+		}
+
+		for _, b := range values {
+			wb := newChainWork(b)
+			if got, want := wa.Cmp(&wb), a.Cmp(b); got != want {
+				t.Errorf("Cmp(%v, %v): got %d, want %d", a, b, got,
+					want)
+			}
+
+			want := new(big.Int).Add(a, b)
+			if want.Cmp(maxWork) > 0 {
+				want = maxWork
+			}
+			sum := wa.add(&wb)
+			if got := sum.Big(); got.Cmp(want) != 0 {
+				t.Errorf("add(%v, %v): got %v, want %v", a, b, got,
+					want)
+			}
+		}
+	}
+
+	// Work which does not fit in 256 bits must saturate.
+	tooMuch := new(big.Int).Lsh(bigOne, 256)
+	if got := newChainWork(tooMuch); got.Big().Cmp(maxWork) != 0 {
+		t.Errorf("newChainWork(2^256): got %v, want %v", got, maxWork)
+	}
+}
+
+// TestBlockNodeSize ensures block nodes stay within 128 bytes on 64-bit
+// platforms since there are over a million of them in memory.
+func TestBlockNodeSize(t *testing.T) {
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("block node size is only checked on 64-bit platforms")
+	}
+	if size := unsafe.Sizeof(blockNode{}); size > 128 {
+		t.Errorf("block node size is %d bytes, want at most 128", size)
+	}
+}
+
+// bigWorkBlockNode is the layout block nodes had before the chain work was
+// kept inline, which the memory benchmarks compare against.
+type bigWorkBlockNode struct {
+	parent     *bigWorkBlockNode
+	hash       chainhash.Hash
+	workSum    *big.Int
+	height     int32
+	version    int32
+	bits       uint32
+	nonce      uint32
+	timestamp  int64
+	merkleRoot chainhash.Hash
+	status     blockStatus
+}
+
+// benchmarkNodeMemory reports the heap memory retained per node by a chain of
+// nodes created by the passed function as the bytes/node metric.
+func benchmarkNodeMemory(b *testing.B, newChain func(n int) interface{}) {
+	const numNodes = 100000
+
+	var before, after runtime.MemStats
+	var chain interface{}
+	for i := 0; i < b.N; i++ {
+		chain = nil
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		chain = newChain(numNodes)
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+	}
+	runtime.KeepAlive(chain)
+
+	bytesPerNode := float64(after.HeapAlloc-before.HeapAlloc) / numNodes
+	b.ReportMetric(bytesPerNode, "bytes/node")
+}
+
+// BenchmarkBlockNodeMemory measures the heap memory retained per block node,
+// which is 128 bytes on 64-bit platforms.
+func BenchmarkBlockNodeMemory(b *testing.B) {
+	header := chaincfg.MainNetParams.GenesisBlock.Header
+	benchmarkNodeMemory(b, func(n int) interface{} {
+		var node *blockNode
+		for i := 0; i < n; i++ {
+			node = newBlockNode(&header, node)
+		}
+		return node
+	})
+}
+
+// BenchmarkBigWorkBlockNodeMemory measures the heap memory retained per block
+// node with the chain work kept as a *big.Int, which is 192 bytes on 64-bit
+// platforms once the allocations of the big integer are included.
+func BenchmarkBigWorkBlockNodeMemory(b *testing.B) {
+	header := chaincfg.MainNetParams.GenesisBlock.Header
+	benchmarkNodeMemory(b, func(n int) interface{} {
+		var node *bigWorkBlockNode
+		for i := 0; i < n; i++ {
+			workSum := CalcWork(header.Bits)
+			if node != nil {
+				workSum.Add(workSum, node.workSum)
+			}
+			node = &bigWorkBlockNode{
+				parent:     node,
+				hash:       header.BlockHash(),
+				workSum:    workSum,
+				version:    header.Version,
+				bits:       header.Bits,
+				nonce:      header.Nonce,
+				timestamp:  header.Timestamp.Unix(),
+				merkleRoot: header.MerkleRoot,
+			}
+		}
+		return node
+	})
+}
@@ -57,7 +57,8 @@ func (b *BlockChain) LatestCheckpoint() *chaincfg.Checkpoint {
 
 // verifyCheckpoint returns whether the passed block height and hash combination
 // match the checkpoint data.  It also returns true if there is no checkpoint
-// data for the passed block height.
+// data for the passed block height, or the checkpoints of the network are only
+// advisory, in which case a mismatch is logged.
 func (b *BlockChain) verifyCheckpoint(height int32, hash *chainhash.Hash) bool {
 	if !b.HasCheckpoints() {
 		return true
@@ -70,6 +71,11 @@ func (b *BlockChain) verifyCheckpoint(height int32, hash *chainhash.Hash) bool {
 	}
 
 	if !checkpoint.Hash.IsEqual(hash) {
+		if b.chainParams.AdvisoryCheckpoints {
+			log.Warnf("Block %v at height %d does not match advisory "+
+				"checkpoint %v", hash, height, checkpoint.Hash)
+			return true
+		}
 		return false
 	}
 
@@ -81,11 +87,13 @@ func (b *BlockChain) verifyCheckpoint(height int32, hash *chainhash.Hash) bool {
 // findPreviousCheckpoint finds the most recent checkpoint that is already
 // available in the downloaded portion of the block chain and returns the
 // associated block node.  It returns nil if a checkpoint can't be found (this
-// should really only happen for blocks before the first checkpoint).
+// should really only happen for blocks before the first checkpoint), or the
+// checkpoints of the network are only advisory, since the checkpoint is only
+// used to reject blocks.
 //
 // This function MUST be called with the chain lock held (for reads).
 func (b *BlockChain) findPreviousCheckpoint() (*blockNode, error) {
-	if !b.HasCheckpoints() {
+	if !b.HasCheckpoints() || b.chainParams.AdvisoryCheckpoints {
 		return nil, nil
 	}
 
@@ -241,9 +249,9 @@ func (b *BlockChain) IsCheckpointCandidate(block *ltcutil.Block) (bool, error) {
 	// A checkpoint must have timestamps for the block and the blocks on
 	// either side of it in order (due to the median time allowance this is
 	// not always the case).
-	prevTime := time.Unix(node.parent.timestamp, 0)
+	prevTime := time.Unix(node.parent.Timestamp(), 0)
 	curTime := block.MsgBlock().Header.Timestamp
-	nextTime := time.Unix(nextNode.timestamp, 0)
+	nextTime := time.Unix(nextNode.Timestamp(), 0)
 	if prevTime.After(curTime) || nextTime.Before(curTime) {
 		return false, nil
 	}
@@ -0,0 +1,68 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+// TestAdvisoryCheckpoints ensures checkpoints are only enforced when they are
+// not advisory.
+func TestAdvisoryCheckpoints(t *testing.T) {
+	for _, advisory := range []bool{false, true} {
+		params := chaincfg.RegressionNetParams
+		params.AdvisoryCheckpoints = advisory
+		chain := newFakeChain(&params)
+		nodes := chainedNodes(chain.bestChain.Genesis(), 10)
+		for _, node := range nodes {
+			chain.index.AddNode(node)
+		}
+		chain.bestChain.SetTip(nodes[len(nodes)-1])
+
+		checkpoint := chaincfg.Checkpoint{
+			Height: nodes[4].height,
+			Hash:   &nodes[4].hash,
+		}
+		chain.checkpoints = []chaincfg.Checkpoint{checkpoint}
+		chain.checkpointsByHeight = map[int32]*chaincfg.Checkpoint{
+			checkpoint.Height: &chain.checkpoints[0],
+		}
+
+		if !chain.VerifyCheckpoint(checkpoint.Height, checkpoint.Hash) {
+			t.Errorf("advisory %v: matching checkpoint rejected",
+				advisory)
+		}
+		badHash := chainhash.Hash{0x01}
+		if got := chain.VerifyCheckpoint(checkpoint.Height, &badHash); got != advisory {
+			t.Errorf("advisory %v: mismatching checkpoint verified %v",
+				advisory, got)
+		}
+
+		// The previous checkpoint is only used to reject blocks, so it
+		// must not be found for advisory checkpoints.
+		node, err := chain.findPreviousCheckpoint()
+		if err != nil {
+			t.Fatalf("advisory %v: findPreviousCheckpoint: %v",
+				advisory, err)
+		}
+		if advisory && node != nil {
+			t.Errorf("advisory %v: found previous checkpoint %v",
+				advisory, node.hash)
+		}
+		if !advisory && node != nodes[4] {
+			t.Errorf("advisory %v: previous checkpoint is %v, want %v",
+				advisory, node, nodes[4].hash)
+		}
+
+		latest := chain.LatestCheckpoint()
+		if latest == nil || *latest.Hash != nodes[4].hash {
+			t.Errorf("advisory %v: latest checkpoint is %v, want %v",
+				advisory, latest, nodes[4].hash)
+		}
+	}
+}
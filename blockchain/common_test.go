@@ -367,6 +367,7 @@ func newFakeChain(params *chaincfg.Params) *BlockChain {
 		bestChain:           newChainView(node),
 		warningCaches:       newThresholdCaches(vbNumBits),
 		deploymentCaches:    newThresholdCaches(chaincfg.DefinedDeployments),
+		warnings:            make(map[WarningType]string),
 	}
 
 	for _, deployment := range params.Deployments {
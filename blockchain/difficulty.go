@@ -5,10 +5,14 @@
 package blockchain
 
 import (
+	"fmt"
+	"math"
 	"math/big"
 	"time"
 
+	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
 )
 
 var (
@@ -153,44 +157,271 @@ func CalcWork(bits uint32) *big.Int {
 	return new(big.Int).Div(oneLsh256, denominator)
 }
 
+const (
+	// lwmaMaxSolvetimeFactor is the largest factor by which a single LWMA
+	// retarget can raise the target of the previous block.  Solve times are
+	// capped to six times the target block time, so their weighted average
+	// can not exceed it.
+	lwmaMaxSolvetimeFactor = 6
+
+	// lwmaV2MaxAdjustmentFactor is the largest factor by which a single
+	// LWMAv2 retarget can raise the target of the block at the start of its
+	// window.
+	lwmaV2MaxAdjustmentFactor = 3
+
+	// asertEasingSlackShifts is the number of extra doublings allowed on
+	// top of the ASERT schedule to account for its polynomial approximation
+	// and the precision lost by the compact representation.
+	asertEasingSlackShifts = 2
+)
+
+// easeTarget multiplies the passed target by the given factor the given number
+// of times, limited to the passed proof of work limit.
+func easeTarget(target *big.Int, factor, times int64, powLimit *big.Int) {
+	bigFactor := big.NewInt(factor)
+	for ; times > 0 && target.Cmp(powLimit) < 0; times-- {
+		target.Mul(target, bigFactor)
+	}
+	if target.Cmp(powLimit) > 0 {
+		target.Set(powLimit)
+	}
+}
+
 // calcEasiestDifficulty calculates the easiest possible difficulty that a block
-// can have given starting difficulty bits and a duration.  It is mainly used to
-// verify that claimed proof of work by a block is sane as compared to a
-// known good checkpoint.
-func (b *BlockChain) calcEasiestDifficulty(bits uint32, duration time.Duration) uint32 {
-	// Convert types used in the calculations below.
-	durationVal := int64(duration / time.Second)
-	adjustmentFactor := big.NewInt(b.chainParams.RetargetAdjustmentFactor)
-
-	// The test network rules allow minimum difficulty blocks after more
-	// than twice the desired amount of time needed to generate a block has
-	// elapsed.
-	if b.chainParams.ReduceMinDifficulty {
-		reductionTime := int64(b.chainParams.MinDiffReductionTime /
-			time.Second)
-		if durationVal > reductionTime {
-			return b.chainParams.PowLimitBits
+// at the passed height and time can have given a known good checkpoint.  It is
+// mainly used to verify that claimed proof of work by a block is sane as
+// compared to the checkpoint.
+//
+// Every difficulty algorithm which may apply to the blocks between the
+// checkpoint and the passed height contributes the most it can ease the
+// target: the original retarget algorithm by its adjustment factor per maximum
+// retarget timespan elapsed, LWMA by its maximum adjustment for every block it
+// retargets, LWMAv2 by its maximum adjustment for every window of blocks, and
+// ASERT by its exponential schedule.  The LWMA algorithms are bounded by the
+// number of blocks instead of the elapsed time since the solve times they are
+// based on are only clamped, so timestamps that go back and forth ease them
+// without the time passing.  ASERT does not depend on the targets of previous
+// blocks, so it alone determines the easiest difficulty of the blocks it
+// applies to.  A negative height means the height of the block is not known,
+// in which case all algorithms activated after the checkpoint are taken into
+// account.
+func calcEasiestDifficulty(checkpoint HeaderCtx, height int32,
+	blockTime time.Time, c ChainCtx) uint32 {
+
+	params := c.ChainParams()
+	if params.PoWNoRetargeting {
+		return params.PowLimitBits
+	}
+	heightKnown := height >= 0
+	if !heightKnown {
+		height = math.MaxInt32
+	}
+	durationVal := blockTime.Unix() - checkpoint.Timestamp()
+
+	// Determine the first height each difficulty algorithm is used at.  A
+	// zero activation height means the algorithm is never used.
+	lwmaStart, lwmaFixStart, asertStart := int32(math.MaxInt32),
+		int32(math.MaxInt32), int32(math.MaxInt32)
+	if params.LWMAHeight > 0 {
+		lwmaStart = params.LWMAHeight
+	}
+	if params.LWMAFixHeight > 0 {
+		lwmaFixStart = params.LWMAFixHeight
+	}
+	if params.ASERTHeight > 0 {
+		asertStart = params.ASERTHeight + 1
+	}
+	minHeight := func(heights ...int32) int32 {
+		min := heights[0]
+		for _, h := range heights[1:] {
+			if h < min {
+				min = h
+			}
+		}
+		return min
+	}
+
+	// blocksBetween returns the number of blocks after the checkpoint up to
+	// the block being checked which use the algorithm from the start height
+	// up to, but not including, the end height.
+	cpHeight := checkpoint.Height()
+	blocksBetween := func(start, end int32) int64 {
+		first := int64(start)
+		if first <= int64(cpHeight) {
+			first = int64(cpHeight) + 1
+		}
+		last := int64(end) - 1
+		if last > int64(height) {
+			last = int64(height)
 		}
+		if last < first {
+			return 0
+		}
+		return last - first + 1
 	}
 
+	newTarget := CompactToBig(checkpoint.Bits())
+
 	// Since easier difficulty equates to higher numbers, the easiest
-	// difficulty for a given duration is the largest value possible given
-	// the number of retargets for the duration and starting difficulty
-	// multiplied by the max adjustment factor.
-	newTarget := CompactToBig(bits)
-	for durationVal > 0 && newTarget.Cmp(b.chainParams.PowLimit) < 0 {
-		newTarget.Mul(newTarget, adjustmentFactor)
-		durationVal -= b.maxRetargetTimespan
+	// difficulty for a given duration under the original algorithm is the
+	// largest value possible given the number of retargets for the duration
+	// and starting difficulty multiplied by the max adjustment factor.
+	if blocksBetween(0, minHeight(lwmaStart, lwmaFixStart, asertStart)) > 0 {
+		// The test network rules allow minimum difficulty blocks after
+		// more than twice the desired amount of time needed to generate
+		// a block has elapsed.
+		if params.ReduceMinDifficulty {
+			reductionTime := int64(params.MinDiffReductionTime /
+				time.Second)
+			if durationVal > reductionTime {
+				return params.PowLimitBits
+			}
+		}
+
+		adjustmentFactor := big.NewInt(params.RetargetAdjustmentFactor)
+		remaining := durationVal
+		for remaining > 0 && newTarget.Cmp(params.PowLimit) < 0 {
+			newTarget.Mul(newTarget, adjustmentFactor)
+			remaining -= c.MaxRetargetTimespan()
+		}
+		if newTarget.Cmp(params.PowLimit) > 0 {
+			newTarget.Set(params.PowLimit)
+		}
+	}
+
+	// LWMA retargets from the target of the previous block, so every block
+	// it applies to may raise the target by its maximum adjustment.
+	lwmaBlocks := blocksBetween(lwmaStart, minHeight(lwmaFixStart,
+		asertStart))
+	easeTarget(newTarget, lwmaMaxSolvetimeFactor, lwmaBlocks,
+		params.PowLimit)
+
+	// LWMAv2 retargets from the target of the block at the start of its
+	// window instead, so the target of a block can only exceed the target
+	// of the block a window before it by the maximum adjustment.  The window
+	// is shorter until enough blocks passed since the LWMA activation, and
+	// blocks with fewer than three blocks in their window keep the target of
+	// the previous block.  When the window of the first blocks reaches back
+	// to before the checkpoint, their reference may be any of the blocks it
+	// covers.
+	lwmaV2Blocks := blocksBetween(lwmaFixStart, asertStart)
+	if lwmaV2Blocks > 0 {
+		window := params.LWMAWindow
+		since := int64(lwmaFixStart) - int64(params.LWMAHeight)
+		if since < window {
+			window = since
+		}
+		if window < 3 {
+			window = 3
+		}
+		if int64(cpHeight)+params.LWMAWindow >= int64(lwmaFixStart) {
+			node := checkpoint.Parent()
+			for i := int64(0); i < params.LWMAWindow && node != nil; i++ {
+				target := CompactToBig(node.Bits())
+				if target.Cmp(newTarget) > 0 {
+					newTarget = target
+				}
+				node = node.Parent()
+			}
+		}
+
+		// Every block references a block at least one more than the
+		// window size before it.
+		windows := (lwmaV2Blocks + window) / (window + 1)
+		easeTarget(newTarget, lwmaV2MaxAdjustmentFactor, windows,
+			params.PowLimit)
+	}
+
+	// ASERT does not depend on the difficulty of the previous blocks, but
+	// only on how far the timestamp of the parent block is ahead of the
+	// schedule of one block per target block time since its anchor block,
+	// so the target doubles once per half-life of delay.  When the
+	// checkpoint itself was retargeted by ASERT, the schedule continues from
+	// it.  Otherwise it starts from the anchor target at an unknown block
+	// after the checkpoint, whose parent may have a timestamp up to the
+	// maximum time offset before it.  The lowest height the block may have
+	// is assumed when it is not known, since it allows the most delay.
+	if blocksBetween(asertStart, math.MaxInt32) > 0 {
+		blockHeight := int64(height)
+		if !heightKnown {
+			blockHeight = int64(asertStart)
+			if int64(cpHeight) >= blockHeight {
+				blockHeight = int64(cpHeight) + 1
+			}
+		}
+		targetTimePerBlock := int64(params.TargetTimePerBlock /
+			time.Second)
+
+		asertTarget := CompactToBig(params.ASERTAnchorBits)
+		elapsed := blockTime.Unix() - checkpoint.Timestamp() +
+			2*MaxTimeOffsetSeconds - targetTimePerBlock*
+			(blockHeight-int64(params.ASERTHeight))
+		if cpHeight >= asertStart {
+			asertTarget = CompactToBig(checkpoint.Bits())
+			elapsed = blockTime.Unix() + MaxTimeOffsetSeconds -
+				checkpoint.Parent().Timestamp() -
+				targetTimePerBlock*(blockHeight-int64(cpHeight))
+		}
+
+		halfLife := params.ASERTHalfLife
+		shifts := asertEasingSlackShifts + (elapsed+halfLife-1)/halfLife
+		if elapsed < 0 {
+			shifts = asertEasingSlackShifts + elapsed/halfLife
+		}
+		if shifts >= 0 {
+			easeTarget(asertTarget, 2, shifts, params.PowLimit)
+		} else if shifts > -256 {
+			asertTarget.Rsh(asertTarget, uint(-shifts))
+		} else {
+			asertTarget.SetInt64(1)
+		}
+		if asertTarget.Sign() == 0 {
+			asertTarget.SetInt64(1)
+		}
+
+		// Only ASERT applies when the block or the checkpoint is past its
+		// activation.
+		if (heightKnown && height >= asertStart) ||
+			cpHeight >= asertStart || asertTarget.Cmp(newTarget) > 0 {
+
+			newTarget = asertTarget
+		}
 	}
 
 	// Limit new value to the proof of work limit.
-	if newTarget.Cmp(b.chainParams.PowLimit) > 0 {
-		newTarget.Set(b.chainParams.PowLimit)
+	if newTarget.Cmp(params.PowLimit) > 0 {
+		newTarget.Set(params.PowLimit)
 	}
 
 	return BigToCompact(newTarget)
 }
 
+// checkCheckpointDifficulty ensures the target difficulty claimed by the passed
+// header at the given height is not easier than the easiest difficulty allowed
+// since the passed checkpoint.  A negative height means the height of the block
+// is not known.
+//
+// Even though the proof of work of the header has already been checked to
+// exceed the claimed amount, the claimed amount is a field in the block header
+// which could be forged.  This check ensures the proof of work is at least the
+// minimum expected based on elapsed time since the checkpoint and the maximum
+// adjustment allowed by the difficulty algorithms.
+func checkCheckpointDifficulty(header *wire.BlockHeader, height int32,
+	checkpoint HeaderCtx, c ChainCtx) error {
+
+	requiredTarget := CompactToBig(calcEasiestDifficulty(checkpoint, height,
+		header.Timestamp, c))
+	currentTarget := CompactToBig(header.Bits)
+	if currentTarget.Cmp(requiredTarget) > 0 {
+		str := fmt.Sprintf("block target difficulty of %064x is too "+
+			"low when compared to the previous checkpoint",
+			currentTarget)
+		return ruleError(ErrDifficultyTooLow, str)
+	}
+
+	return nil
+}
+
 // findPrevTestNetDifficulty returns the difficulty of the previous block which
 // did not have the special testnet minimum difficulty rule applied.
 func findPrevTestNetDifficulty(startNode HeaderCtx, c ChainCtx) uint32 {
@@ -318,12 +549,14 @@ func calcNextRequiredDifficulty(lastNode HeaderCtx, newBlockTime time.Time,
 	// Log new target difficulty and return it.  The new target logging is
 	// intentionally converting the bits back to a number instead of using
 	// newTarget since conversion to the compact representation loses
-	// precision.
+	// precision.  The details are logged as key=value pairs on a single
+	// line so they can be consumed by log processing pipelines.
 	newTargetBits := BigToCompact(newTarget)
-	log.Debugf("Difficulty retarget at block height %d", lastNode.Height()+1)
-	log.Debugf("Old target %08x (%064x)", lastNode.Bits(), oldTarget)
-	log.Debugf("New target %08x (%064x)", newTargetBits, CompactToBig(newTargetBits))
-	log.Debugf("Actual timespan %v, adjusted timespan %v, target timespan %v",
+	log.Debugf("Difficulty retarget height=%d old_bits=%08x "+
+		"old_target=%064x new_bits=%08x new_target=%064x "+
+		"actual_timespan=%v adjusted_timespan=%v target_timespan=%v",
+		lastNode.Height()+1, lastNode.Bits(), oldTarget, newTargetBits,
+		CompactToBig(newTargetBits),
 		time.Duration(actualTimespan)*time.Second,
 		time.Duration(adjustedTimespan)*time.Second,
 		c.ChainParams().TargetTimespan)
@@ -584,14 +817,88 @@ func calcNextRequiredDifficultyASERT(lastNode HeaderCtx, c ChainCtx) (uint32, er
 	return BigToCompact(nextTarget), nil
 }
 
+// CalcNextRequiredDifficultyCtx calculates the required difficulty for the
+// block after the passed previous HeaderCtx based on the difficulty algorithm
+// active at its height.  This allows external libraries which provide their
+// own header and chain context, such as light clients, to compute the expected
+// difficulty of a header without a BlockChain instance.
+func CalcNextRequiredDifficultyCtx(lastNode HeaderCtx, newBlockTime time.Time,
+	c ChainCtx) (uint32, error) {
+
+	return calcNextRequiredDifficulty(lastNode, newBlockTime, c)
+}
+
+// DifficultyAlgorithm returns the name of the difficulty algorithm which
+// determines the required difficulty of the block at the passed height, which
+// is one of "none" for networks without retargeting, "retarget", "lwma",
+// "lwmav2" or "asert".
+func DifficultyAlgorithm(height int32, params *chaincfg.Params) string {
+	switch {
+	case params.PoWNoRetargeting:
+		return "none"
+	case params.ASERTHeight > 0 && height > params.ASERTHeight:
+		return "asert"
+	case params.LWMAFixHeight > 0 && height >= params.LWMAFixHeight:
+		return "lwmav2"
+	case params.LWMAHeight > 0 && height >= params.LWMAHeight:
+		return "lwma"
+	}
+	return "retarget"
+}
+
 // CalcNextRequiredDifficulty calculates the required difficulty for the block
 // after the end of the current best chain based on the difficulty retarget
 // rules.
 //
+// The difficulty only depends on the tip and its ancestors, whose block nodes
+// never change, so it is calculated without the chain lock and doesn't wait
+// for the validation of blocks.
+//
 // This function is safe for concurrent access.
 func (b *BlockChain) CalcNextRequiredDifficulty(timestamp time.Time) (uint32, error) {
+	return calcNextRequiredDifficulty(b.bestChain.Tip(), timestamp, b)
+}
+
+// CheckHeadersDifficulty performs a cheap contextual check of the target
+// difficulty claimed by each of the passed consecutive headers, the first of
+// which is at the given height, against the most recent checkpoint in the best
+// chain.  It rejects headers whose claimed difficulty is easier than any of the
+// difficulty algorithms could have reached since the checkpoint, without
+// requiring the previous headers the exact difficulty depends on.  This makes
+// it suitable for filtering headers before they are connected to the chain,
+// such as during the initial headers download.  The checkpoint is only looked
+// up once for all of the headers, and the chain lock is not held while they are
+// checked.
+//
+// Headers at or before the checkpoint are not checked.  It returns the index of
+// the first header in the slice which is invalid along with the reason, or -1
+// and nil when the difficulty of all of the headers is valid.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) CheckHeadersDifficulty(headers []*wire.BlockHeader,
+	height int32) (int, error) {
+
 	b.chainLock.Lock()
-	difficulty, err := calcNextRequiredDifficulty(b.bestChain.Tip(), timestamp, b)
+	checkpoint, err := b.findPreviousCheckpoint()
 	b.chainLock.Unlock()
-	return difficulty, err
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint == nil {
+		return -1, nil
+	}
+
+	for i, header := range headers {
+		headerHeight := height + int32(i)
+		if headerHeight <= checkpoint.height {
+			continue
+		}
+		err := checkCheckpointDifficulty(header, headerHeight,
+			checkpoint, b)
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return -1, nil
 }
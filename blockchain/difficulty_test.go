@@ -7,6 +7,10 @@ package blockchain
 import (
 	"math/big"
 	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/wire"
 )
 
 // TestBigToCompact ensures BigToCompact converts big integers to the expected
@@ -73,3 +77,176 @@ func TestCalcWork(t *testing.T) {
 		}
 	}
 }
+
+// TestCalcEasiestDifficulty ensures the easiest difficulty allowed since a
+// checkpoint accounts for each of the difficulty algorithms which may apply
+// between the checkpoint and the checked block.
+func TestCalcEasiestDifficulty(t *testing.T) {
+	params := chaincfg.MainNetParams
+	params.LWMAHeight = 100
+	params.LWMAFixHeight = 105
+	params.ASERTHeight = 140
+	params.ASERTAnchorBits = 0x1c00ffff
+	chain := newFakeChain(&params)
+
+	// Create a chain with a block every target block time.  One block in
+	// the LWMAv2 window of the later checkpoints has an easier difficulty.
+	const bits = 0x1b00ffff
+	targetTime := int64(params.TargetTimePerBlock / time.Second)
+	nodes := make([]*blockNode, 260)
+	tip := chain.bestChain.Genesis()
+	for i := range nodes {
+		nodeBits := uint32(bits)
+		if i == 110 {
+			nodeBits = 0x1b01fffe
+		}
+		nodes[i] = newFakeNode(tip, 1, nodeBits,
+			time.Unix(tip.Timestamp()+targetTime, 0))
+		tip = nodes[i]
+	}
+	lwmaCheckpoint := nodes[9]
+	asertCheckpoint := nodes[249]
+
+	// scaled returns the checkpoint target multiplied by the passed factor
+	// and shifted left by the passed number of bits.
+	scaled := func(compact uint32, factor int64, shifts uint) uint32 {
+		target := CompactToBig(compact)
+		target.Mul(target, big.NewInt(factor))
+		return BigToCompact(target.Lsh(target, shifts))
+	}
+
+	const day = 24 * 60 * 60
+	tests := []struct {
+		name       string
+		checkpoint *blockNode
+		height     int32
+		duration   int64
+		want       uint32
+	}{{
+		name:       "original algorithm without elapsed time",
+		checkpoint: lwmaCheckpoint,
+		height:     50,
+		duration:   0,
+		want:       bits,
+	}, {
+		name:       "original algorithm retarget",
+		checkpoint: lwmaCheckpoint,
+		height:     50,
+		duration:   60,
+		want:       scaled(bits, 4, 0),
+	}, {
+		name:       "through LWMA",
+		checkpoint: lwmaCheckpoint,
+		height:     103,
+		duration:   targetTime,
+		want:       scaled(bits, 4*6*6*6*6, 0),
+	}, {
+		name:       "through LWMAv2",
+		checkpoint: lwmaCheckpoint,
+		height:     115,
+		duration:   targetTime,
+		want:       scaled(bits, 4*6*6*6*6*6*3*3, 0),
+	}, {
+		name:       "LWMA multi-day gap",
+		checkpoint: nodes[100],
+		height:     103,
+		duration:   3 * day,
+		want:       scaled(bits, 6*6, 0),
+	}, {
+		name:       "LWMAv2 multi-day gap from window",
+		checkpoint: nodes[119],
+		height:     130,
+		duration:   3 * day,
+		want:       scaled(bits, 2*3*3, 0),
+	}, {
+		name:       "unknown height includes ASERT",
+		checkpoint: nodes[129],
+		height:     -1,
+		duration:   0,
+		want:       scaled(params.ASERTAnchorBits, 1, 6),
+	}, {
+		name:       "ASERT ignores earlier algorithms",
+		checkpoint: lwmaCheckpoint,
+		height:     180,
+		duration:   0,
+		want:       scaled(params.ASERTAnchorBits, 1, 5),
+	}, {
+		name:       "checkpoint after ASERT activation",
+		checkpoint: asertCheckpoint,
+		height:     259,
+		duration:   1800,
+		want:       scaled(bits, 1, 5),
+	}, {
+		name:       "ASERT multi-day gap",
+		checkpoint: asertCheckpoint,
+		height:     259,
+		duration:   2 * day,
+		want:       BigToCompact(params.PowLimit),
+	}}
+	for _, test := range tests {
+		blockTime := time.Unix(test.checkpoint.Timestamp()+test.duration, 0)
+		got := calcEasiestDifficulty(test.checkpoint, test.height,
+			blockTime, chain)
+		if got != test.want {
+			t.Errorf("%s: got %08x, want %08x", test.name, got,
+				test.want)
+			continue
+		}
+
+		// Headers claiming an easier difficulty are rejected.
+		header := wire.BlockHeader{Bits: got, Timestamp: blockTime}
+		err := checkCheckpointDifficulty(&header, test.height,
+			test.checkpoint, chain)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		header.Bits = scaled(got, 2, 0)
+		err = checkCheckpointDifficulty(&header, test.height,
+			test.checkpoint, chain)
+		if rerr, ok := err.(RuleError); !ok ||
+			rerr.ErrorCode != ErrDifficultyTooLow {
+
+			t.Errorf("%s: got %v, want %v", test.name, err,
+				ErrDifficultyTooLow)
+		}
+	}
+
+	// Networks without retargeting always allow the proof of work limit.
+	params.PoWNoRetargeting = true
+	got := calcEasiestDifficulty(lwmaCheckpoint, 50,
+		time.Unix(lwmaCheckpoint.Timestamp(), 0), chain)
+	if got != params.PowLimitBits {
+		t.Errorf("no retargeting: got %08x, want %08x", got,
+			params.PowLimitBits)
+	}
+}
+
+// TestDifficultyAlgorithm ensures the difficulty algorithm reported for a
+// height matches the transitions of the network parameters.
+func TestDifficultyAlgorithm(t *testing.T) {
+	params := chaincfg.MainNetParams
+	tests := []struct {
+		height int32
+		want   string
+	}{
+		{0, "retarget"},
+		{params.LWMAHeight - 1, "retarget"},
+		{params.LWMAHeight, "lwma"},
+		{params.LWMAFixHeight - 1, "lwma"},
+		{params.LWMAFixHeight, "lwmav2"},
+		{params.ASERTHeight, "lwmav2"},
+		{params.ASERTHeight + 1, "asert"},
+	}
+	for _, test := range tests {
+		got := DifficultyAlgorithm(test.height, &params)
+		if got != test.want {
+			t.Errorf("height %d: got algorithm %q, want %q",
+				test.height, got, test.want)
+		}
+	}
+
+	regtest := chaincfg.RegressionNetParams
+	if got := DifficultyAlgorithm(100, &regtest); got != "none" {
+		t.Errorf("regtest: got algorithm %q, want \"none\"", got)
+	}
+}
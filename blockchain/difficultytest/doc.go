@@ -0,0 +1,22 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package difficultytest generates test vectors for the difficulty algorithms.
+
+Each vector is a deterministic synthetic header chain along with the golden
+difficulty bits of every header as calculated by the blockchain package for
+one of the LWMA, LWMAv2 and ASERT algorithms.  The chains are described by a
+Scenario, which either follows a fixed pattern of solve times or derives the
+solve time of each header from the difficulty it has to meet and a simulated
+hashrate which changes in steps at configured heights.
+
+This package has intentionally been designed so it can be used as a standalone
+package by any projects which reimplement the difficulty checks, such as
+wallets and light clients, to test their implementation against the reference
+one.  The vectors can be generated on the fly with Generate and the default set
+of scenarios returned by Scenarios, or serialized to JSON and shipped along with
+the implementation under test.
+*/
+package difficultytest
@@ -0,0 +1,411 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package difficultytest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+// Algorithm identifies the difficulty algorithm a vector is generated for.
+type Algorithm uint8
+
+const (
+	// LWMA is the linearly weighted moving average algorithm.
+	LWMA Algorithm = iota
+
+	// LWMAv2 is the stabilized LWMA algorithm which uses the target at the
+	// start of the averaging window as its reference.
+	LWMAv2
+
+	// ASERT is the absolutely scheduled exponentially rising targets
+	// algorithm.
+	ASERT
+)
+
+// algorithmStrings is a map of difficulty algorithms back to their constant
+// names for pretty printing.
+var algorithmStrings = map[Algorithm]string{
+	LWMA:   "lwma",
+	LWMAv2: "lwmav2",
+	ASERT:  "asert",
+}
+
+// String returns the Algorithm as a human-readable name.
+func (a Algorithm) String() string {
+	if s, ok := algorithmStrings[a]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown Algorithm (%d)", uint8(a))
+}
+
+// MarshalText returns the name of the algorithm.  It implements the
+// encoding.TextMarshaler interface.
+func (a Algorithm) MarshalText() ([]byte, error) {
+	if _, ok := algorithmStrings[a]; !ok {
+		return nil, fmt.Errorf("unknown algorithm %d", uint8(a))
+	}
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText sets the algorithm from its name.  It implements the
+// encoding.TextUnmarshaler interface.
+func (a *Algorithm) UnmarshalText(text []byte) error {
+	for algorithm, s := range algorithmStrings {
+		if s == string(text) {
+			*a = algorithm
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown algorithm %q", text)
+}
+
+// HashrateStep changes the simulated hashrate of a scenario starting at a
+// height.
+type HashrateStep struct {
+	// Height is the first height mined with the hashrate.
+	Height int32
+
+	// Percent is the hashrate as a percentage of the one which solves
+	// headers at the starting difficulty in exactly the target block time.
+	Percent int64
+}
+
+// Scenario describes a synthetic header chain.
+type Scenario struct {
+	// Name uniquely identifies the scenario.
+	Name string
+
+	// Algorithm is the difficulty algorithm active for the whole chain.
+	Algorithm Algorithm
+
+	// NumBlocks is the number of headers generated after the starting
+	// header.
+	NumBlocks int32
+
+	// StartBits is the difficulty of the starting header.  It also serves
+	// as the anchor difficulty of the ASERT algorithm.
+	StartBits uint32
+
+	// StartTime is the timestamp of the starting header.
+	StartTime time.Time
+
+	// SolveTimes is a pattern of solve times in seconds which is repeated
+	// for the whole chain.  Solve times may be zero or negative to model
+	// out of order timestamps.  When it is empty, the solve times are
+	// derived from the hashrate instead.
+	SolveTimes []int64
+
+	// HashrateSteps changes the simulated hashrate at the given heights.
+	// The hashrate is 100 percent until the first step.  The steps must be
+	// sorted by height.
+	HashrateSteps []HashrateStep
+}
+
+// Block is a header of a test vector.
+type Block struct {
+	Height    int32  `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Bits      uint32 `json:"bits"`
+}
+
+// Vector is a synthetic header chain along with the golden difficulty bits of
+// each header.  The first block is the starting header of the chain, whose
+// difficulty is given rather than calculated.
+type Vector struct {
+	Name          string    `json:"name"`
+	Algorithm     Algorithm `json:"algorithm"`
+	TargetSpacing int64     `json:"targetspacing"`
+	PowLimitBits  uint32    `json:"powlimitbits"`
+	LWMAWindow    int64     `json:"lwmawindow,omitempty"`
+	ASERTHalfLife int64     `json:"aserthalflife,omitempty"`
+	Blocks        []Block   `json:"blocks"`
+}
+
+// Params returns a copy of the passed network parameters in which the passed
+// difficulty algorithm is active from the first block after genesis and the
+// ASERT anchor difficulty is the passed anchor bits.  The other parameters of
+// the algorithms, such as the target block time, averaging window and
+// half-life, are those of the passed network.
+func Params(base *chaincfg.Params, algorithm Algorithm,
+	anchorBits uint32) *chaincfg.Params {
+
+	params := *base
+	params.PoWNoRetargeting = false
+	params.ReduceMinDifficulty = false
+	params.LWMAHeight = 0
+	params.LWMAFixHeight = 0
+	params.ASERTHeight = 0
+	params.ASERTAnchorBits = anchorBits
+	switch algorithm {
+	case LWMA:
+		params.LWMAHeight = 1
+	case LWMAv2:
+		params.LWMAHeight = 1
+		params.LWMAFixHeight = 1
+	case ASERT:
+		params.ASERTHeight = 1
+	}
+	return &params
+}
+
+// header is a synthetic header which provides the context the difficulty
+// algorithms depend on.  It implements the blockchain.HeaderCtx interface.
+type header struct {
+	height    int32
+	bits      uint32
+	timestamp int64
+	parent    *header
+}
+
+// Height returns the height of the header.
+//
+// NOTE: Part of the blockchain.HeaderCtx interface.
+func (h *header) Height() int32 {
+	return h.height
+}
+
+// Bits returns the difficulty bits of the header.
+//
+// NOTE: Part of the blockchain.HeaderCtx interface.
+func (h *header) Bits() uint32 {
+	return h.bits
+}
+
+// Timestamp returns the timestamp of the header.
+//
+// NOTE: Part of the blockchain.HeaderCtx interface.
+func (h *header) Timestamp() int64 {
+	return h.timestamp
+}
+
+// Parent returns the parent of the header.
+//
+// NOTE: Part of the blockchain.HeaderCtx interface.
+func (h *header) Parent() blockchain.HeaderCtx {
+	if h.parent == nil {
+		return nil
+	}
+	return h.parent
+}
+
+// RelativeAncestorCtx returns the ancestor of the header that is distance
+// headers before it.
+//
+// NOTE: Part of the blockchain.HeaderCtx interface.
+func (h *header) RelativeAncestorCtx(distance int32) blockchain.HeaderCtx {
+	ancestor := h
+	for ; ancestor != nil && distance > 0; distance-- {
+		ancestor = ancestor.parent
+	}
+	if ancestor == nil {
+		return nil
+	}
+	return ancestor
+}
+
+// chainCtx provides the network parameters the difficulty algorithms depend
+// on.  It implements the blockchain.ChainCtx interface.
+type chainCtx struct {
+	params *chaincfg.Params
+}
+
+// ChainParams returns the network parameters.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (c *chainCtx) ChainParams() *chaincfg.Params {
+	return c.params
+}
+
+// BlocksPerRetarget returns the number of blocks between retargets of the
+// original difficulty algorithm.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (c *chainCtx) BlocksPerRetarget() int32 {
+	return int32(c.params.TargetTimespan / c.params.TargetTimePerBlock)
+}
+
+// MinRetargetTimespan returns the minimum timespan of a retarget of the
+// original difficulty algorithm in seconds.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (c *chainCtx) MinRetargetTimespan() int64 {
+	return int64(c.params.TargetTimespan/time.Second) /
+		c.params.RetargetAdjustmentFactor
+}
+
+// MaxRetargetTimespan returns the maximum timespan of a retarget of the
+// original difficulty algorithm in seconds.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (c *chainCtx) MaxRetargetTimespan() int64 {
+	return int64(c.params.TargetTimespan/time.Second) *
+		c.params.RetargetAdjustmentFactor
+}
+
+// VerifyCheckpoint always returns true since synthetic chains have no
+// checkpoints.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (c *chainCtx) VerifyCheckpoint(int32, *chainhash.Hash) bool {
+	return true
+}
+
+// FindPreviousCheckpoint always returns nil since synthetic chains have no
+// checkpoints.
+//
+// NOTE: Part of the blockchain.ChainCtx interface.
+func (c *chainCtx) FindPreviousCheckpoint() (blockchain.HeaderCtx, error) {
+	return nil, nil
+}
+
+// solveTime returns the time in seconds a miner with the passed hashrate, as a
+// percentage of the one which solves the starting difficulty in the target
+// block time, takes on average to solve a header with the passed bits.  Integer
+// arithmetic is used so the result is the same on every platform.
+func solveTime(bits, startBits uint32, percent, targetSpacing int64) int64 {
+	// solveTime = targetSpacing * work(bits) / work(startBits) * 100 / percent
+	numerator := blockchain.CalcWork(bits)
+	numerator.Mul(numerator, big.NewInt(targetSpacing*100))
+	denominator := blockchain.CalcWork(startBits)
+	denominator.Mul(denominator, big.NewInt(percent))
+	return numerator.Div(numerator, denominator).Int64()
+}
+
+// Generate generates the header chain described by the passed scenario using
+// the passed network parameters as modified by Params, and returns it along
+// with the difficulty bits of each header.
+func Generate(base *chaincfg.Params, scenario *Scenario) (*Vector, error) {
+	if _, ok := algorithmStrings[scenario.Algorithm]; !ok {
+		return nil, fmt.Errorf("unknown algorithm %d",
+			uint8(scenario.Algorithm))
+	}
+	if scenario.NumBlocks <= 0 {
+		return nil, errors.New("the scenario must have at least one block")
+	}
+	for i, step := range scenario.HashrateSteps {
+		if step.Percent <= 0 {
+			return nil, fmt.Errorf("hashrate step %d is not positive",
+				i)
+		}
+		if i > 0 && step.Height < scenario.HashrateSteps[i-1].Height {
+			return nil, errors.New("hashrate steps are not sorted " +
+				"by height")
+		}
+	}
+
+	params := Params(base, scenario.Algorithm, scenario.StartBits)
+	c := &chainCtx{params: params}
+	targetSpacing := int64(params.TargetTimePerBlock / time.Second)
+
+	tip := &header{
+		bits:      scenario.StartBits,
+		timestamp: scenario.StartTime.Unix(),
+	}
+	vector := &Vector{
+		Name:          scenario.Name,
+		Algorithm:     scenario.Algorithm,
+		TargetSpacing: targetSpacing,
+		PowLimitBits:  params.PowLimitBits,
+		Blocks:        make([]Block, 0, scenario.NumBlocks+1),
+	}
+	switch scenario.Algorithm {
+	case LWMA, LWMAv2:
+		vector.LWMAWindow = params.LWMAWindow
+	case ASERT:
+		vector.ASERTHalfLife = params.ASERTHalfLife
+	}
+	vector.Blocks = append(vector.Blocks, Block{
+		Height:    tip.height,
+		Timestamp: tip.timestamp,
+		Bits:      tip.bits,
+	})
+
+	percent := int64(100)
+	nextStep := 0
+	for height := int32(1); height <= scenario.NumBlocks; height++ {
+		// The solve time of the header is either given by the pattern or
+		// derived from the difficulty it has to meet.  Since the
+		// difficulty of the ASERT algorithm depends on the timestamp of
+		// the previous header only, it is calculated before the
+		// timestamp of the new header is known.
+		bits, err := blockchain.CalcNextRequiredDifficultyCtx(tip,
+			time.Unix(tip.timestamp, 0), c)
+		if err != nil {
+			return nil, err
+		}
+		for nextStep < len(scenario.HashrateSteps) &&
+			scenario.HashrateSteps[nextStep].Height <= height {
+
+			percent = scenario.HashrateSteps[nextStep].Percent
+			nextStep++
+		}
+		var solve int64
+		if len(scenario.SolveTimes) > 0 {
+			solve = scenario.SolveTimes[int(height-1)%
+				len(scenario.SolveTimes)]
+		} else {
+			solve = solveTime(bits, scenario.StartBits, percent,
+				targetSpacing)
+		}
+
+		tip = &header{
+			height:    height,
+			bits:      bits,
+			timestamp: tip.timestamp + solve,
+			parent:    tip,
+		}
+		vector.Blocks = append(vector.Blocks, Block{
+			Height:    tip.height,
+			Timestamp: tip.timestamp,
+			Bits:      tip.bits,
+		})
+	}
+
+	return vector, nil
+}
+
+// Verify recalculates the difficulty of each header of the passed vector with
+// the passed network parameters as modified by Params and returns an error
+// describing the first header whose golden difficulty does not match.
+func Verify(base *chaincfg.Params, vector *Vector) error {
+	if len(vector.Blocks) == 0 {
+		return errors.New("the vector does not have any blocks")
+	}
+	start := vector.Blocks[0]
+	params := Params(base, vector.Algorithm, start.Bits)
+	c := &chainCtx{params: params}
+
+	tip := &header{
+		height:    start.Height,
+		bits:      start.Bits,
+		timestamp: start.Timestamp,
+	}
+	for _, block := range vector.Blocks[1:] {
+		bits, err := blockchain.CalcNextRequiredDifficultyCtx(tip,
+			time.Unix(block.Timestamp, 0), c)
+		if err != nil {
+			return err
+		}
+		if bits != block.Bits {
+			return fmt.Errorf("%s: block %d has bits %08x, want %08x",
+				vector.Name, block.Height, block.Bits, bits)
+		}
+		tip = &header{
+			height:    block.Height,
+			bits:      block.Bits,
+			timestamp: block.Timestamp,
+			parent:    tip,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package difficultytest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+)
+
+// TestGenerate ensures the default scenarios generate deterministic vectors
+// whose golden difficulty bits match the reference implementation.
+func TestGenerate(t *testing.T) {
+	params := &chaincfg.MainNetParams
+
+	// The difficulty of the final header of each default scenario.
+	finalBits := map[string]uint32{
+		"lwma-steady":            0x1d18ffe7,
+		"lwma-hashrate-double":   0x1d0c0955,
+		"lwma-hashrate-drop":     0x1e00ea05,
+		"lwma-stall-recovery":    0x1e01816f,
+		"lwma-oscillating":       0x1d10a5eb,
+		"lwma-out-of-order":      0x1e0fffff,
+		"lwmav2-steady":          0x1d18ffe7,
+		"lwmav2-hashrate-double": 0x1d0c5985,
+		"lwmav2-hashrate-drop":   0x1e0137f2,
+		"lwmav2-stall-recovery":  0x1d1d35a9,
+		"lwmav2-oscillating":     0x1d160716,
+		"lwmav2-out-of-order":    0x1d2c4568,
+		"asert-steady":           0x1d18ffe7,
+		"asert-hashrate-double":  0x1d0c9d26,
+		"asert-hashrate-drop":    0x1e00f68d,
+		"asert-stall-recovery":   0x1d2db229,
+		"asert-oscillating":      0x1d186e1a,
+		"asert-out-of-order":     0x1d18a466,
+	}
+
+	scenarios := Scenarios(params)
+	if len(scenarios) != len(finalBits) {
+		t.Fatalf("got %d scenarios, want %d", len(scenarios),
+			len(finalBits))
+	}
+	for i := range scenarios {
+		scenario := &scenarios[i]
+		vector, err := Generate(params, scenario)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", scenario.Name, err)
+		}
+		if len(vector.Blocks) != int(scenario.NumBlocks)+1 {
+			t.Fatalf("%s: got %d blocks, want %d", scenario.Name,
+				len(vector.Blocks), scenario.NumBlocks+1)
+		}
+		final := vector.Blocks[len(vector.Blocks)-1]
+		if want, ok := finalBits[scenario.Name]; !ok || final.Bits != want {
+			t.Errorf("%s: final bits %08x, want %08x", scenario.Name,
+				final.Bits, want)
+		}
+		if err := Verify(params, vector); err != nil {
+			t.Errorf("%s: %v", scenario.Name, err)
+		}
+
+		// Generating the same scenario again yields the same vector.
+		again, err := Generate(params, scenario)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", scenario.Name, err)
+		}
+		if !reflect.DeepEqual(vector, again) {
+			t.Errorf("%s: vectors are not deterministic",
+				scenario.Name)
+		}
+	}
+}
+
+// TestVerify ensures vectors survive a JSON round trip and that tampered
+// difficulty bits are detected.
+func TestVerify(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	scenario := Scenarios(params)[1]
+	vector, err := Generate(params, &scenario)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	serialized, err := json.Marshal(vector)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Vector
+	if err := json.Unmarshal(serialized, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(vector, &decoded) {
+		t.Fatalf("decoded vector does not match the generated one")
+	}
+	if err := Verify(params, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded.Blocks[100].Bits++
+	if err := Verify(params, &decoded); err == nil {
+		t.Fatalf("tampered vector was not detected")
+	}
+}
+
+// TestGenerateErrors ensures invalid scenarios are rejected.
+func TestGenerateErrors(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	valid := Scenarios(params)[0]
+
+	noBlocks := valid
+	noBlocks.NumBlocks = 0
+	unknownAlgorithm := valid
+	unknownAlgorithm.Algorithm = 0xff
+	badHashrate := valid
+	badHashrate.HashrateSteps = []HashrateStep{{Height: 1, Percent: 0}}
+	unsortedSteps := valid
+	unsortedSteps.HashrateSteps = []HashrateStep{
+		{Height: 20, Percent: 100},
+		{Height: 10, Percent: 100},
+	}
+
+	tests := []struct {
+		name     string
+		scenario Scenario
+	}{
+		{"no blocks", noBlocks},
+		{"unknown algorithm", unknownAlgorithm},
+		{"non-positive hashrate", badHashrate},
+		{"unsorted hashrate steps", unsortedSteps},
+	}
+	for _, test := range tests {
+		if _, err := Generate(params, &test.scenario); err == nil {
+			t.Errorf("%s: scenario was not rejected", test.name)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package difficultytest
+
+import (
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+)
+
+const (
+	// scenarioBlocks is the number of headers generated by each of the
+	// default scenarios.  It covers a few LWMA averaging windows.
+	scenarioBlocks = 200
+
+	// scenarioStartBits is the starting difficulty of the default
+	// scenarios.
+	scenarioStartBits = 0x1d18ffe7
+)
+
+// scenarioStartTime is the timestamp of the starting header of the default
+// scenarios.
+var scenarioStartTime = time.Unix(1700000000, 0)
+
+// Scenarios returns the default set of scenarios for each of the difficulty
+// algorithms using the target block time of the passed network parameters.
+// They cover steady solve times, step changes of the hashrate in both
+// directions, a stall followed by a recovery, oscillating solve times and out
+// of order timestamps.
+func Scenarios(params *chaincfg.Params) []Scenario {
+	spacing := int64(params.TargetTimePerBlock / time.Second)
+	templates := []Scenario{{
+		Name:       "steady",
+		SolveTimes: []int64{spacing},
+	}, {
+		Name:          "hashrate-double",
+		HashrateSteps: []HashrateStep{{Height: 50, Percent: 200}},
+	}, {
+		Name:          "hashrate-drop",
+		HashrateSteps: []HashrateStep{{Height: 50, Percent: 10}},
+	}, {
+		Name: "stall-recovery",
+		HashrateSteps: []HashrateStep{
+			{Height: 60, Percent: 1},
+			{Height: 70, Percent: 100},
+		},
+	}, {
+		Name:       "oscillating",
+		SolveTimes: []int64{spacing / 5, spacing * 9 / 5},
+	}, {
+		Name: "out-of-order",
+		SolveTimes: []int64{spacing * 2, -spacing / 2, spacing,
+			spacing * 3 / 2},
+	}}
+
+	algorithms := []Algorithm{LWMA, LWMAv2, ASERT}
+	scenarios := make([]Scenario, 0, len(algorithms)*len(templates))
+	for _, algorithm := range algorithms {
+		for _, template := range templates {
+			scenario := template
+			scenario.Name = algorithm.String() + "-" + template.Name
+			scenario.Algorithm = algorithm
+			scenario.NumBlocks = scenarioBlocks
+			scenario.StartBits = scenarioStartBits
+			scenario.StartTime = scenarioStartTime
+			scenarios = append(scenarios, scenario)
+		}
+	}
+	return scenarios
+}
@@ -220,6 +220,14 @@ const (
 	// current chain tip. This is not a block validation rule, but is required
 	// for block proposals submitted via getblocktemplate RPC.
 	ErrPrevBlockNotBest
+
+	// ErrBadSignetSolution indicates that a block of a signet network does
+	// not contain a valid solution to the signet challenge.
+	ErrBadSignetSolution
+
+	// ErrUnexpectedMweb indicates that a block or transaction includes MWEB
+	// data before the MWEB deployment is active.
+	ErrUnexpectedMweb
 )
 
 // Map of ErrorCode values back to their constant names for pretty printing.
@@ -267,6 +275,8 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrPreviousBlockUnknown:      "ErrPreviousBlockUnknown",
 	ErrInvalidAncestorBlock:      "ErrInvalidAncestorBlock",
 	ErrPrevBlockNotBest:          "ErrPrevBlockNotBest",
+	ErrBadSignetSolution:         "ErrBadSignetSolution",
+	ErrUnexpectedMweb:            "ErrUnexpectedMweb",
 }
 
 // String returns the ErrorCode as a human-readable name.
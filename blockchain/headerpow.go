@@ -0,0 +1,65 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"math/big"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// CheckHeadersProofOfWork ensures the proof of work of each of the passed
+// headers is valid as defined by CheckHeaderProofOfWork.  The scrypt hashes,
+// which dominate the cost of a headers-first sync, are calculated by a pool of
+// one worker per processor instead of one header at a time.
+//
+// It returns the index of the first header in the slice which is invalid along
+// with the reason, or -1 and nil when the proof of work of all of the headers
+// is valid.  Workers stop taking new headers once an invalid one is found, but
+// every header before it is still checked so the returned index is the same as
+// the one a sequential check would return.
+func CheckHeadersProofOfWork(headers []*wire.BlockHeader, powLimit *big.Int) (int, error) {
+	numWorkers := runtime.NumCPU()
+	if numWorkers > len(headers) {
+		numWorkers = len(headers)
+	}
+
+	// Headers are handed out to the workers in order through a shared
+	// counter, so by the time a worker notices an invalid header and
+	// the others stop, every header with a lower index has already been
+	// taken and will be finished by its worker.
+	errs := make([]error, len(headers))
+	var next int64
+	var failed int32
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&failed) == 0 {
+				idx := int(atomic.AddInt64(&next, 1) - 1)
+				if idx >= len(headers) {
+					return
+				}
+				err := checkProofOfWork(headers[idx], powLimit, BFNone)
+				if err != nil {
+					errs[idx] = err
+					atomic.StoreInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return i, err
+		}
+	}
+	return -1, nil
+}
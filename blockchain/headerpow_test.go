@@ -0,0 +1,99 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestCheckHeadersProofOfWork ensures the batched proof of work check accepts
+// valid headers and reports the first invalid header the same way checking
+// them one at a time would.
+func TestCheckHeadersProofOfWork(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	const numHeaders = 64
+	headers := make([]*wire.BlockHeader, 0, numHeaders)
+	prevHash := *params.GenesisHash
+	timestamp := params.GenesisBlock.Header.Timestamp
+	for i := int32(1); i <= numHeaders; i++ {
+		timestamp = timestamp.Add(time.Minute)
+		block := solveTestBlock(t, &prevHash, i, timestamp)
+		header := block.MsgBlock().Header
+		headers = append(headers, &header)
+		prevHash = header.BlockHash()
+	}
+
+	idx, err := CheckHeadersProofOfWork(headers, params.PowLimit)
+	if err != nil {
+		t.Fatalf("valid headers: unexpected error at index %d: %v", idx,
+			err)
+	}
+	if idx != -1 {
+		t.Fatalf("valid headers: got index %d, want -1", idx)
+	}
+	if idx, err := CheckHeadersProofOfWork(nil, params.PowLimit); idx != -1 ||
+		err != nil {
+
+		t.Fatalf("no headers: got index %d and error %v", idx, err)
+	}
+
+	// invalidate returns a copy of the passed header which does not
+	// satisfy its claimed proof of work.
+	invalidate := func(header *wire.BlockHeader) *wire.BlockHeader {
+		bad := *header
+		target := CompactToBig(bad.Bits)
+		for {
+			bad.Nonce++
+			hash := bad.PowHash()
+			if HashToBig(&hash).Cmp(target) > 0 {
+				return &bad
+			}
+		}
+	}
+
+	tests := []struct {
+		name    string
+		badIdxs []int
+		wantIdx int
+	}{
+		{"first", []int{0}, 0},
+		{"middle", []int{31}, 31},
+		{"last", []int{numHeaders - 1}, numHeaders - 1},
+		{"several", []int{50, 7, 12}, 7},
+	}
+	for _, test := range tests {
+		modified := make([]*wire.BlockHeader, len(headers))
+		copy(modified, headers)
+		for _, badIdx := range test.badIdxs {
+			modified[badIdx] = invalidate(headers[badIdx])
+		}
+
+		idx, err := CheckHeadersProofOfWork(modified, params.PowLimit)
+		if !isRuleErrorCode(err, ErrHighHash) {
+			t.Errorf("%s: got error %v, want %v", test.name, err,
+				ErrHighHash)
+			continue
+		}
+		if idx != test.wantIdx {
+			t.Errorf("%s: got index %d, want %d", test.name, idx,
+				test.wantIdx)
+		}
+	}
+
+	// Headers claiming a target above the proof of work limit are
+	// rejected as well.
+	easy := *headers[3]
+	easy.Bits = 0x2100ffff
+	modified := append([]*wire.BlockHeader{}, headers...)
+	modified[3] = &easy
+	idx, err = CheckHeadersProofOfWork(modified, params.PowLimit)
+	if idx != 3 || !isRuleErrorCode(err, ErrUnexpectedDifficulty) {
+		t.Errorf("too easy: got index %d and error %v", idx, err)
+	}
+}
@@ -7,6 +7,8 @@ package indexers
 import (
 	"bytes"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/ltcsuite/ltcd/blockchain"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
@@ -21,6 +23,10 @@ var (
 	indexTipsBucketName = []byte("idxtips")
 )
 
+// catchUpRetryInterval is the time the background catch up waits for the best
+// chain to settle when it was modified while loading the next block to index.
+const catchUpRetryInterval = time.Second
+
 // -----------------------------------------------------------------------------
 // The index manager tracks the current tip of each index by using a parent
 // bucket that contains an entry for index.
@@ -130,8 +136,39 @@ func dbIndexDisconnectBlock(dbTx database.Tx, indexer Indexer, block *ltcutil.Bl
 // implements the blockchain.IndexManager interface so it can be seamlessly
 // plugged into normal chain processing.
 type Manager struct {
-	db             database.DB
-	enabledIndexes []Indexer
+	db                database.DB
+	enabledIndexes    []Indexer
+	backgroundCatchUp bool
+	chain             *blockchain.BlockChain
+
+	// mtx protects the tips of the indexes and of the main chain below.
+	// It is only acquired within database update transactions when both
+	// are needed, so it never waits on the database write lock.
+	mtx        sync.Mutex
+	tips       []indexTip
+	bestHash   chainhash.Hash
+	bestHeight int32
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// indexTip is the block an index has indexed up to.
+type indexTip struct {
+	hash   chainhash.Hash
+	height int32
+}
+
+// IndexStatus describes how far an index is synced with the main chain.
+type IndexStatus struct {
+	// Name is the human-readable name of the index.
+	Name string
+
+	// Height is the height of the last block indexed.
+	Height int32
+
+	// Synced is whether the index is caught up with the main chain.
+	Synced bool
 }
 
 // Ensure the Manager type implements the blockchain.IndexManager interface.
@@ -230,7 +267,9 @@ func (m *Manager) maybeCreateIndexes(dbTx database.Tx) error {
 // current best chain tip.  This is necessary since each index can be disabled
 // and re-enabled at any time and attempting to catch-up indexes at the same
 // time new blocks are being downloaded would lead to an overall longer time to
-// catch up due to the I/O contention.
+// catch up due to the I/O contention.  Managers created by NewBackgroundManager
+// instead catch up once started, so enabling an index on a synced node does
+// not hold up its startup.
 //
 // This is part of the blockchain.IndexManager interface.
 func (m *Manager) Init(chain *blockchain.BlockChain, interrupt <-chan struct{}) error {
@@ -361,13 +400,10 @@ func (m *Manager) Init(chain *blockchain.BlockChain, interrupt <-chan struct{})
 		}
 	}
 
-	// Fetch the current tip heights for each index along with tracking the
-	// lowest one so the catchup code only needs to start at the earliest
-	// block and is able to skip connecting the block for the indexes that
-	// don't need it.
-	bestHeight := chain.BestSnapshot().Height
-	lowestHeight := bestHeight
-	indexerHeights := make([]int32, len(m.enabledIndexes))
+	// Load the current tips of the indexes and of the main chain, which
+	// are kept up to date as blocks are connected and disconnected.
+	m.chain = chain
+	m.tips = make([]indexTip, len(m.enabledIndexes))
 	err = m.db.View(func(dbTx database.Tx) error {
 		for i, indexer := range m.enabledIndexes {
 			idxKey := indexer.Key()
@@ -378,84 +414,191 @@ func (m *Manager) Init(chain *blockchain.BlockChain, interrupt <-chan struct{})
 
 			log.Debugf("Current %s tip (height %d, hash %v)",
 				indexer.Name(), height, hash)
-			indexerHeights[i] = height
-			if height < lowestHeight {
-				lowestHeight = height
-			}
+			m.tips[i] = indexTip{hash: *hash, height: height}
 		}
 		return nil
 	})
 	if err != nil {
 		return err
 	}
+	best := chain.BestSnapshot()
+	m.bestHash = best.Hash
+	m.bestHeight = best.Height
 
-	// Nothing to index if all of the indexes are caught up.
-	if lowestHeight == bestHeight {
+	// Indexes which are behind the main chain are caught up once the
+	// manager is started when catching up in the background.
+	if m.backgroundCatchUp {
 		return nil
 	}
+	return m.catchUp(interrupt)
+}
 
-	// Create a progress logger for the indexing process below.
-	progressLogger := newBlockProgressLogger("Indexed", log)
-
-	// At this point, one or more indexes are behind the current best chain
-	// tip and need to be caught up, so log the details and loop through
-	// each block that needs to be indexed.
-	log.Infof("Catching up indexes from height %d to %d", lowestHeight,
-		bestHeight)
-	for height := lowestHeight + 1; height <= bestHeight; height++ {
-		// Load the block for the height since it is required to index
-		// it.
-		block, err := chain.BlockByHeight(height)
-		if err != nil {
-			return err
-		}
-
+// catchUp indexes the blocks of the main chain the indexes are missing until
+// all of them are synced with the main chain.  The indexes with the lowest tip
+// are caught up first so indexes at the same height are caught up together.
+// Blocks connected to the main chain meanwhile are indexed by ConnectBlock for
+// the indexes which have caught up.
+func (m *Manager) catchUp(interrupt <-chan struct{}) error {
+	var progressLogger *blockProgressLogger
+	for {
 		if interruptRequested(interrupt) {
 			return errInterruptRequested
 		}
 
-		// Connect the block for all indexes that need it.
-		var spentTxos []blockchain.SpentTxOut
-		for i, indexer := range m.enabledIndexes {
-			// Skip indexes that don't need to be updated with this
-			// block.
-			if indexerHeights[i] >= height {
-				continue
+		// Find the lowest index tip.
+		m.mtx.Lock()
+		lowest := m.tips[0]
+		for _, tip := range m.tips[1:] {
+			if tip.height < lowest.height {
+				lowest = tip
 			}
+		}
+		bestHeight := m.bestHeight
+		m.mtx.Unlock()
+
+		// Nothing left to index when all of the indexes are caught up.
+		if lowest.height >= bestHeight {
+			if progressLogger != nil {
+				log.Infof("Indexes caught up to height %d",
+					bestHeight)
+			}
+			return nil
+		}
+		if progressLogger == nil {
+			log.Infof("Catching up indexes from height %d to %d",
+				lowest.height, bestHeight)
+			progressLogger = newBlockProgressLogger("Indexed", log)
+		}
 
-			// When the index requires all of the referenced txouts
-			// and they haven't been loaded yet, they need to be
-			// retrieved from the spend journal.
-			if spentTxos == nil && indexNeedsInputs(indexer) {
-				spentTxos, err = chain.FetchSpendJournal(block)
-				if err != nil {
-					return err
+		// Load the next block to index.  The main chain may be modified
+		// concurrently when catching up in the background, in which
+		// case the block is loaded again once it settles.
+		block, err := m.chain.BlockByHeight(lowest.height + 1)
+		if err != nil || block.MsgBlock().Header.PrevBlock != lowest.hash {
+			if !m.backgroundCatchUp {
+				if err == nil {
+					err = AssertError(fmt.Sprintf("block %v at "+
+						"height %d does not extend the "+
+						"index tip %v", block.Hash(),
+						block.Height(), lowest.hash))
 				}
+				return err
 			}
+			select {
+			case <-interrupt:
+				return errInterruptRequested
+			case <-time.After(catchUpRetryInterval):
+			}
+			continue
+		}
 
-			err := m.db.Update(func(dbTx database.Tx) error {
-				return dbIndexConnectBlock(
-					dbTx, indexer, block, spentTxos,
-				)
-			})
+		// When any of the indexes to catch up requires the referenced
+		// txouts, they need to be retrieved from the spend journal.
+		var spentTxos []blockchain.SpentTxOut
+		m.mtx.Lock()
+		for i, indexer := range m.enabledIndexes {
+			if m.tips[i] == lowest && indexNeedsInputs(indexer) {
+				spentTxos = []blockchain.SpentTxOut{}
+				break
+			}
+		}
+		m.mtx.Unlock()
+		if spentTxos != nil {
+			spentTxos, err = m.chain.FetchSpendJournal(block)
 			if err != nil {
 				return err
 			}
-			indexerHeights[i] = height
+		}
+
+		// Connect the block for all indexes at its parent, provided it
+		// was not disconnected from the main chain since it was loaded.
+		err = m.db.Update(func(dbTx database.Tx) error {
+			m.mtx.Lock()
+			defer m.mtx.Unlock()
+
+			if block.Height() > m.bestHeight {
+				return nil
+			}
+			return m.connectBlock(dbTx, block, spentTxos)
+		})
+		if err != nil {
+			return err
 		}
 
 		// Log indexing progress.
 		progressLogger.LogBlockHeight(block)
+	}
+}
 
-		if interruptRequested(interrupt) {
-			return errInterruptRequested
+// connectBlock connects the passed block to the indexes whose tip is its
+// parent.
+//
+// This function MUST be called with the manager lock held.
+func (m *Manager) connectBlock(dbTx database.Tx, block *ltcutil.Block,
+	stxos []blockchain.SpentTxOut) error {
+
+	prevHash := block.MsgBlock().Header.PrevBlock
+	for i, indexer := range m.enabledIndexes {
+		if m.tips[i].hash != prevHash {
+			continue
 		}
+		err := dbIndexConnectBlock(dbTx, indexer, block, stxos)
+		if err != nil {
+			return err
+		}
+		m.tips[i] = indexTip{hash: *block.Hash(), height: block.Height()}
 	}
-
-	log.Infof("Indexes caught up to height %d", bestHeight)
 	return nil
 }
 
+// Start begins catching up the indexes which are behind the main chain in the
+// background when the manager was created by NewBackgroundManager.
+func (m *Manager) Start() {
+	if !m.backgroundCatchUp || len(m.enabledIndexes) == 0 {
+		return
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		err := m.catchUp(m.quit)
+		if err != nil && err != errInterruptRequested {
+			log.Errorf("Unable to catch up indexes: %v", err)
+		}
+	}()
+}
+
+// Stop interrupts catching up the indexes in the background and waits for it
+// to finish.
+func (m *Manager) Stop() {
+	if !m.backgroundCatchUp {
+		return
+	}
+
+	close(m.quit)
+	m.wg.Wait()
+}
+
+// Statuses returns how far each of the enabled indexes is synced with the main
+// chain.
+//
+// This function is safe for concurrent access.
+func (m *Manager) Statuses() []IndexStatus {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	statuses := make([]IndexStatus, 0, len(m.tips))
+	for i, tip := range m.tips {
+		statuses = append(statuses, IndexStatus{
+			Name:   m.enabledIndexes[i].Name(),
+			Height: tip.height,
+			Synced: tip.hash == m.bestHash,
+		})
+	}
+	return statuses
+}
+
 // indexNeedsInputs returns whether or not the index needs access to the txouts
 // referenced by the transaction inputs being indexed.
 func indexNeedsInputs(index Indexer) bool {
@@ -502,14 +645,17 @@ func dbFetchTx(dbTx database.Tx, hash *chainhash.Hash) (*wire.MsgTx, error) {
 func (m *Manager) ConnectBlock(dbTx database.Tx, block *ltcutil.Block,
 	stxos []blockchain.SpentTxOut) error {
 
-	// Call each of the currently active optional indexes with the block
-	// being connected so they can update accordingly.
-	for _, index := range m.enabledIndexes {
-		err := dbIndexConnectBlock(dbTx, index, block, stxos)
-		if err != nil {
-			return err
-		}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	// Call each of the optional indexes which are caught up with the block
+	// being connected so they can update accordingly.  The indexes which
+	// are still being caught up index the block later on.
+	if err := m.connectBlock(dbTx, block, stxos); err != nil {
+		return err
 	}
+	m.bestHash = *block.Hash()
+	m.bestHeight = block.Height()
 	return nil
 }
 
@@ -522,14 +668,24 @@ func (m *Manager) ConnectBlock(dbTx database.Tx, block *ltcutil.Block,
 func (m *Manager) DisconnectBlock(dbTx database.Tx, block *ltcutil.Block,
 	stxo []blockchain.SpentTxOut) error {
 
-	// Call each of the currently active optional indexes with the block
-	// being disconnected so they can update accordingly.
-	for _, index := range m.enabledIndexes {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	// Call each of the optional indexes which have indexed the block being
+	// disconnected so they can update accordingly.
+	prevHash := block.MsgBlock().Header.PrevBlock
+	for i, index := range m.enabledIndexes {
+		if m.tips[i].hash != *block.Hash() {
+			continue
+		}
 		err := dbIndexDisconnectBlock(dbTx, index, block, stxo)
 		if err != nil {
 			return err
 		}
+		m.tips[i] = indexTip{hash: prevHash, height: block.Height() - 1}
 	}
+	m.bestHash = prevHash
+	m.bestHeight = block.Height() - 1
 	return nil
 }
 
@@ -544,6 +700,19 @@ func NewManager(db database.DB, enabledIndexes []Indexer) *Manager {
 	}
 }
 
+// NewBackgroundManager returns a new index manager like NewManager, except the
+// indexes which are behind the main chain are caught up in the background once
+// the manager is started instead of during chain initialization.  The indexes
+// are not used for blocks connected meanwhile until they have caught up.
+func NewBackgroundManager(db database.DB, enabledIndexes []Indexer) *Manager {
+	return &Manager{
+		db:                db,
+		enabledIndexes:    enabledIndexes,
+		backgroundCatchUp: true,
+		quit:              make(chan struct{}),
+	}
+}
+
 // dropIndex drops the passed index from the database.  Since indexes can be
 // massive, it deletes the index in multiple database transactions in order to
 // keep memory usage to reasonable levels.  It also marks the drop in progress
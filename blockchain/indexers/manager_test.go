@@ -0,0 +1,140 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+	_ "github.com/ltcsuite/ltcd/database/ffldb"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// solveTestBlock returns a block with only a coinbase transaction which builds
+// on the passed parent and satisfies the proof of work of the regression test
+// network.
+func solveTestBlock(t *testing.T, parent *chainhash.Hash, height int32,
+	timestamp time.Time) *ltcutil.Block {
+
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{},
+			wire.MaxPrevOutIndex),
+		SignatureScript: []byte{0x51, byte(height), byte(height >> 8)},
+		Sequence:        wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(0, []byte{0x51}))
+
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:    4,
+			PrevBlock:  *parent,
+			MerkleRoot: coinbase.TxHash(),
+			Timestamp:  timestamp,
+			Bits:       chaincfg.RegressionNetParams.PowLimitBits,
+		},
+		Transactions: []*wire.MsgTx{coinbase},
+	}
+
+	target := blockchain.CompactToBig(block.Header.Bits)
+	for nonce := uint32(0); ; nonce++ {
+		block.Header.Nonce = nonce
+		hash := block.Header.PowHash()
+		if blockchain.HashToBig(&hash).Cmp(target) <= 0 {
+			break
+		}
+		if nonce == ^uint32(0) {
+			t.Fatalf("unable to solve block at height %d", height)
+		}
+	}
+	return ltcutil.NewBlock(block)
+}
+
+// TestBackgroundCatchUp ensures an index enabled on a synced chain is caught up
+// in the background and kept up to date once it has caught up.
+func TestBackgroundCatchUp(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	db, err := database.Create("ffldb", t.TempDir(), params.Net)
+	if err != nil {
+		t.Fatalf("unable to create database: %v", err)
+	}
+	defer db.Close()
+
+	// Extend the chain without any indexes enabled.
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		ChainParams: &params,
+		TimeSource:  blockchain.NewMedianTime(),
+	})
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+	timestamp := params.GenesisBlock.Header.Timestamp
+	var blocks []*ltcutil.Block
+	processBlock := func(chain *blockchain.BlockChain) {
+		best := chain.BestSnapshot()
+		timestamp = timestamp.Add(time.Minute)
+		block := solveTestBlock(t, &best.Hash, best.Height+1, timestamp)
+		_, _, err := chain.ProcessBlock(block, blockchain.BFNone)
+		if err != nil {
+			t.Fatalf("ProcessBlock: unexpected error: %v", err)
+		}
+		blocks = append(blocks, block)
+	}
+	for i := 0; i < 5; i++ {
+		processBlock(chain)
+	}
+
+	// Enable the transaction index, which is not caught up until the
+	// manager is started.
+	txIndex := NewTxIndex(db)
+	manager := NewBackgroundManager(db, []Indexer{txIndex})
+	chain, err = blockchain.New(&blockchain.Config{
+		DB:           db,
+		ChainParams:  &params,
+		TimeSource:   blockchain.NewMedianTime(),
+		IndexManager: manager,
+	})
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+	status := manager.Statuses()[0]
+	if status.Synced || status.Height != -1 {
+		t.Fatalf("index status before catching up is %+v", status)
+	}
+
+	manager.Start()
+	defer manager.Stop()
+	deadline := time.Now().Add(10 * time.Second)
+	for !manager.Statuses()[0].Synced {
+		if time.Now().After(deadline) {
+			t.Fatalf("index did not catch up: %+v",
+				manager.Statuses()[0])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Blocks connected once the index has caught up are indexed as well.
+	processBlock(chain)
+	status = manager.Statuses()[0]
+	if !status.Synced || status.Height != int32(len(blocks)) {
+		t.Fatalf("index status after connecting a block is %+v", status)
+	}
+	for _, block := range blocks {
+		txHash := block.Transactions()[0].Hash()
+		region, err := txIndex.TxBlockRegion(txHash)
+		if err != nil {
+			t.Fatalf("TxBlockRegion: unexpected error: %v", err)
+		}
+		if region == nil || !region.Hash.IsEqual(block.Hash()) {
+			t.Fatalf("transaction %v is not indexed", txHash)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"sync"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// watchIndexName is the human-readable name for the index.
+	watchIndexName = "watch-only index"
+
+	// watchedOutputHeaderSize is the size of the serialized fields of a
+	// watched output preceding its public key script.
+	watchedOutputHeaderSize = 4 + 1 + 8
+)
+
+var (
+	// watchIndexKey is the key of the watch-only index and the db bucket
+	// used to house it.
+	watchIndexKey = []byte("watchonlyidx")
+
+	// watchScriptsBucketName is the name of the db bucket used to house
+	// the watched public key scripts.
+	watchScriptsBucketName = []byte("scripts")
+
+	// watchOutputsBucketName is the name of the db bucket used to house
+	// the unspent outputs paying to the watched scripts.
+	watchOutputsBucketName = []byte("utxos")
+)
+
+// -----------------------------------------------------------------------------
+// The watch-only index tracks the unspent outputs of the main chain which pay
+// to a set of registered public key scripts.  Both are kept in memory since
+// the set is expected to be small, and persisted in two buckets nested in the
+// index bucket.  The scripts bucket maps each watched script to an empty
+// value, while the outputs bucket maps the outpoint of each unspent output to
+// the following:
+//
+//   <block height><flags><amount><pk script>
+//
+//   Field           Type      Size
+//   block height    uint32    4 bytes
+//   flags           byte      1 byte (bit 0 is set for coinbase outputs)
+//   amount          uint64    8 bytes
+//   pk script       []byte    variable
+//
+// The outpoints are serialized as the transaction hash followed by the output
+// index as a uint32.
+// -----------------------------------------------------------------------------
+
+// WatchedOutput is an unspent output paying to a watched script.
+type WatchedOutput struct {
+	OutPoint   wire.OutPoint
+	Amount     ltcutil.Amount
+	PkScript   []byte
+	Height     int32
+	IsCoinBase bool
+}
+
+// serializeWatchedOutPoint returns the key of the passed outpoint in the
+// outputs bucket.
+func serializeWatchedOutPoint(op *wire.OutPoint) []byte {
+	key := make([]byte, chainhash.HashSize+4)
+	copy(key, op.Hash[:])
+	byteOrder.PutUint32(key[chainhash.HashSize:], op.Index)
+	return key
+}
+
+// serializeWatchedOutput returns the value of the passed output in the outputs
+// bucket.
+func serializeWatchedOutput(out *WatchedOutput) []byte {
+	serialized := make([]byte, watchedOutputHeaderSize+len(out.PkScript))
+	byteOrder.PutUint32(serialized, uint32(out.Height))
+	if out.IsCoinBase {
+		serialized[4] = 1
+	}
+	byteOrder.PutUint64(serialized[5:], uint64(out.Amount))
+	copy(serialized[watchedOutputHeaderSize:], out.PkScript)
+	return serialized
+}
+
+// deserializeWatchedOutput decodes an entry of the outputs bucket.
+func deserializeWatchedOutput(key, serialized []byte) (*WatchedOutput, error) {
+	if len(key) != chainhash.HashSize+4 ||
+		len(serialized) < watchedOutputHeaderSize {
+
+		return nil, errDeserialize("corrupt watched output entry")
+	}
+
+	out := &WatchedOutput{
+		Height:     int32(byteOrder.Uint32(serialized)),
+		IsCoinBase: serialized[4]&1 != 0,
+		Amount:     ltcutil.Amount(byteOrder.Uint64(serialized[5:])),
+		PkScript: append([]byte(nil),
+			serialized[watchedOutputHeaderSize:]...),
+	}
+	copy(out.OutPoint.Hash[:], key)
+	out.OutPoint.Index = byteOrder.Uint32(key[chainhash.HashSize:])
+	return out, nil
+}
+
+// scanWatchedBlock calls spend with each outpoint spent by the passed block
+// and add with each output of the block paying to a script for which watched
+// returns true, in the order they appear in the block.
+func scanWatchedBlock(block *ltcutil.Block, watched func([]byte) bool,
+	spend func(wire.OutPoint), add func(*WatchedOutput)) {
+
+	for txIdx, tx := range block.Transactions() {
+		if txIdx != 0 {
+			for _, txIn := range tx.MsgTx().TxIn {
+				spend(txIn.PreviousOutPoint)
+			}
+		}
+
+		for i, txOut := range tx.MsgTx().TxOut {
+			if !watched(txOut.PkScript) {
+				continue
+			}
+			add(&WatchedOutput{
+				OutPoint:   wire.OutPoint{Hash: *tx.Hash(), Index: uint32(i)},
+				Amount:     ltcutil.Amount(txOut.Value),
+				PkScript:   txOut.PkScript,
+				Height:     block.Height(),
+				IsCoinBase: txIdx == 0,
+			})
+		}
+	}
+}
+
+// WatchRescan finds the unspent outputs paying to scripts which are about to be
+// watched by scanning the blocks of the main chain in order.
+type WatchRescan struct {
+	scripts map[string]struct{}
+	outputs map[wire.OutPoint]*WatchedOutput
+}
+
+// NewWatchRescan returns a rescan for the passed scripts.
+func NewWatchRescan(scripts [][]byte) *WatchRescan {
+	r := &WatchRescan{
+		scripts: make(map[string]struct{}, len(scripts)),
+		outputs: make(map[wire.OutPoint]*WatchedOutput),
+	}
+	for _, script := range scripts {
+		r.scripts[string(script)] = struct{}{}
+	}
+	return r
+}
+
+// ScanBlock updates the unspent outputs of the rescan with the passed block,
+// which must extend the last block scanned.
+func (r *WatchRescan) ScanBlock(block *ltcutil.Block) {
+	scanWatchedBlock(block, func(pkScript []byte) bool {
+		_, ok := r.scripts[string(pkScript)]
+		return ok
+	}, func(op wire.OutPoint) {
+		delete(r.outputs, op)
+	}, func(out *WatchedOutput) {
+		r.outputs[out.OutPoint] = out
+	})
+}
+
+// WatchIndex implements a watch-only index which tracks the unspent outputs
+// paying to a set of registered public key scripts, without keeping any keys.
+// Scripts are registered with AddScripts, optionally along with the outputs
+// found by rescanning the blocks already in the main chain.
+type WatchIndex struct {
+	db database.DB
+
+	mtx     sync.RWMutex
+	scripts map[string]struct{}
+	outputs map[wire.OutPoint]*WatchedOutput
+}
+
+// Ensure the WatchIndex type implements the Indexer interface.
+var _ Indexer = (*WatchIndex)(nil)
+
+// Ensure the WatchIndex type implements the NeedsInputser interface.
+var _ NeedsInputser = (*WatchIndex)(nil)
+
+// NeedsInputs signals that the index requires the referenced inputs in order
+// to restore the watched outputs spent by disconnected blocks.
+//
+// This implements the NeedsInputser interface.
+func (idx *WatchIndex) NeedsInputs() bool {
+	return true
+}
+
+// Init loads the watched scripts and their unspent outputs from the database.
+//
+// This is part of the Indexer interface.
+func (idx *WatchIndex) Init() error {
+	return idx.db.View(func(dbTx database.Tx) error {
+		bucket := dbTx.Metadata().Bucket(watchIndexKey)
+		err := bucket.Bucket(watchScriptsBucketName).ForEach(
+			func(k, _ []byte) error {
+				idx.scripts[string(k)] = struct{}{}
+				return nil
+			})
+		if err != nil {
+			return err
+		}
+		return bucket.Bucket(watchOutputsBucketName).ForEach(
+			func(k, v []byte) error {
+				out, err := deserializeWatchedOutput(k, v)
+				if err != nil {
+					return err
+				}
+				idx.outputs[out.OutPoint] = out
+				return nil
+			})
+	})
+}
+
+// Key returns the database key to use for the index as a byte slice.
+//
+// This is part of the Indexer interface.
+func (idx *WatchIndex) Key() []byte {
+	return watchIndexKey
+}
+
+// Name returns the human-readable name of the index.
+//
+// This is part of the Indexer interface.
+func (idx *WatchIndex) Name() string {
+	return watchIndexName
+}
+
+// Create is invoked when the indexer manager determines the index needs
+// to be created for the first time.  It creates the buckets for the watched
+// scripts and their unspent outputs.
+//
+// This is part of the Indexer interface.
+func (idx *WatchIndex) Create(dbTx database.Tx) error {
+	bucket, err := dbTx.Metadata().CreateBucket(watchIndexKey)
+	if err != nil {
+		return err
+	}
+	if _, err := bucket.CreateBucket(watchScriptsBucketName); err != nil {
+		return err
+	}
+	_, err = bucket.CreateBucket(watchOutputsBucketName)
+	return err
+}
+
+// ConnectBlock is invoked by the index manager when a new block has been
+// connected to the main chain.  This indexer removes the watched outputs spent
+// by the block and adds the outputs of the block paying to watched scripts.
+//
+// This is part of the Indexer interface.
+func (idx *WatchIndex) ConnectBlock(dbTx database.Tx, block *ltcutil.Block,
+	stxos []blockchain.SpentTxOut) error {
+
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	bucket := dbTx.Metadata().Bucket(watchIndexKey).
+		Bucket(watchOutputsBucketName)
+	var err error
+	scanWatchedBlock(block, idx.isWatched, func(op wire.OutPoint) {
+		if _, ok := idx.outputs[op]; !ok || err != nil {
+			return
+		}
+		err = bucket.Delete(serializeWatchedOutPoint(&op))
+		delete(idx.outputs, op)
+	}, func(out *WatchedOutput) {
+		if err != nil {
+			return
+		}
+		err = bucket.Put(serializeWatchedOutPoint(&out.OutPoint),
+			serializeWatchedOutput(out))
+		idx.outputs[out.OutPoint] = out
+	})
+	return err
+}
+
+// DisconnectBlock is invoked by the index manager when a block has been
+// disconnected from the main chain.  This indexer removes the outputs of the
+// block paying to watched scripts and restores the watched outputs spent by
+// the block.
+//
+// This is part of the Indexer interface.
+func (idx *WatchIndex) DisconnectBlock(dbTx database.Tx, block *ltcutil.Block,
+	stxos []blockchain.SpentTxOut) error {
+
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	// Undo the transactions in reverse order so outputs created and spent
+	// within the block are not restored.
+	bucket := dbTx.Metadata().Bucket(watchIndexKey).
+		Bucket(watchOutputsBucketName)
+	txns := block.Transactions()
+	stxoIndex := len(stxos)
+	for txIdx := len(txns) - 1; txIdx >= 0; txIdx-- {
+		tx := txns[txIdx]
+		for i, txOut := range tx.MsgTx().TxOut {
+			if !idx.isWatched(txOut.PkScript) {
+				continue
+			}
+			op := wire.OutPoint{Hash: *tx.Hash(), Index: uint32(i)}
+			if err := bucket.Delete(serializeWatchedOutPoint(&op)); err != nil {
+				return err
+			}
+			delete(idx.outputs, op)
+		}
+
+		if txIdx == 0 {
+			continue
+		}
+		txIns := tx.MsgTx().TxIn
+		stxoIndex -= len(txIns)
+		for i, txIn := range txIns {
+			stxo := &stxos[stxoIndex+i]
+			if !idx.isWatched(stxo.PkScript) {
+				continue
+			}
+			out := &WatchedOutput{
+				OutPoint:   txIn.PreviousOutPoint,
+				Amount:     ltcutil.Amount(stxo.Amount),
+				PkScript:   stxo.PkScript,
+				Height:     stxo.Height,
+				IsCoinBase: stxo.IsCoinBase,
+			}
+			err := bucket.Put(serializeWatchedOutPoint(&out.OutPoint),
+				serializeWatchedOutput(out))
+			if err != nil {
+				return err
+			}
+			idx.outputs[out.OutPoint] = out
+		}
+	}
+	return nil
+}
+
+// isWatched returns whether the passed public key script is watched.
+//
+// This function MUST be called with the index lock held (for reads).
+func (idx *WatchIndex) isWatched(pkScript []byte) bool {
+	_, ok := idx.scripts[string(pkScript)]
+	return ok
+}
+
+// IsWatched returns whether the passed public key script is watched.
+//
+// This function is safe for concurrent access.
+func (idx *WatchIndex) IsWatched(pkScript []byte) bool {
+	idx.mtx.RLock()
+	defer idx.mtx.RUnlock()
+
+	return idx.isWatched(pkScript)
+}
+
+// AddScripts starts watching the passed public key scripts and adds the unspent
+// outputs found by the passed rescan, if any.  The rescan must have scanned up
+// to the current tip of the index, which callers ensure by preventing blocks
+// from being connected meanwhile.
+//
+// This function is safe for concurrent access.
+func (idx *WatchIndex) AddScripts(scripts [][]byte, rescan *WatchRescan) error {
+	return idx.db.Update(func(dbTx database.Tx) error {
+		idx.mtx.Lock()
+		defer idx.mtx.Unlock()
+
+		bucket := dbTx.Metadata().Bucket(watchIndexKey)
+		scriptsBucket := bucket.Bucket(watchScriptsBucketName)
+		for _, script := range scripts {
+			if err := scriptsBucket.Put(script, nil); err != nil {
+				return err
+			}
+			idx.scripts[string(script)] = struct{}{}
+		}
+		if rescan == nil {
+			return nil
+		}
+
+		outputsBucket := bucket.Bucket(watchOutputsBucketName)
+		for op, out := range rescan.outputs {
+			err := outputsBucket.Put(serializeWatchedOutPoint(&op),
+				serializeWatchedOutput(out))
+			if err != nil {
+				return err
+			}
+			idx.outputs[op] = out
+		}
+		return nil
+	})
+}
+
+// UnspentOutputs returns the unspent outputs of the main chain paying to the
+// watched scripts.
+//
+// This function is safe for concurrent access.
+func (idx *WatchIndex) UnspentOutputs() []WatchedOutput {
+	idx.mtx.RLock()
+	defer idx.mtx.RUnlock()
+
+	outputs := make([]WatchedOutput, 0, len(idx.outputs))
+	for _, out := range idx.outputs {
+		outputs = append(outputs, *out)
+	}
+	return outputs
+}
+
+// NewWatchIndex returns a new instance of an indexer that is used to track the
+// unspent outputs paying to watched scripts.
+//
+// It implements the Indexer interface which plugs into the IndexManager that
+// in turn is used by the blockchain package.  This allows the index to be
+// seamlessly maintained along with the chain.
+func NewWatchIndex(db database.DB) *WatchIndex {
+	return &WatchIndex{
+		db:      db,
+		scripts: make(map[string]struct{}),
+		outputs: make(map[wire.OutPoint]*WatchedOutput),
+	}
+}
+
+// DropWatchIndex drops the watch-only index from the provided database if it
+// exists.
+func DropWatchIndex(db database.DB, interrupt <-chan struct{}) error {
+	return dropIndex(db, watchIndexKey, watchIndexName, interrupt)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/database"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestWatchIndex ensures the watch-only index tracks the outputs paying to the
+// watched scripts as blocks are connected and disconnected.
+func TestWatchIndex(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	db, err := database.Create("ffldb", t.TempDir(), params.Net)
+	if err != nil {
+		t.Fatalf("unable to create database: %v", err)
+	}
+	defer db.Close()
+
+	watched := []byte{0x00, 0x14, 0x01, 0x02}
+	other := []byte{0x51}
+
+	// The first block pays to the watched script in its coinbase and in a
+	// regular transaction, while the second block spends one of them.
+	coinbase1 := wire.NewMsgTx(1)
+	coinbase1.AddTxIn(&wire.TxIn{PreviousOutPoint: wire.OutPoint{
+		Index: wire.MaxPrevOutIndex}})
+	coinbase1.AddTxOut(wire.NewTxOut(5000, watched))
+	coinbase1.AddTxOut(wire.NewTxOut(1000, other))
+	tx1 := wire.NewMsgTx(1)
+	tx1.AddTxIn(&wire.TxIn{PreviousOutPoint: wire.OutPoint{Index: 7}})
+	tx1.AddTxOut(wire.NewTxOut(2000, watched))
+	block1 := ltcutil.NewBlock(&wire.MsgBlock{
+		Transactions: []*wire.MsgTx{coinbase1, tx1},
+	})
+	block1.SetHeight(1)
+
+	coinbase2 := wire.NewMsgTx(1)
+	coinbase2.AddTxIn(&wire.TxIn{PreviousOutPoint: wire.OutPoint{
+		Index: wire.MaxPrevOutIndex}})
+	coinbase2.AddTxOut(wire.NewTxOut(5000, other))
+	tx2 := wire.NewMsgTx(1)
+	tx2.AddTxIn(&wire.TxIn{PreviousOutPoint: wire.OutPoint{
+		Hash: tx1.TxHash()}})
+	tx2.AddTxOut(wire.NewTxOut(1500, other))
+	block2 := ltcutil.NewBlock(&wire.MsgBlock{
+		Transactions: []*wire.MsgTx{coinbase2, tx2},
+	})
+	block2.SetHeight(2)
+	stxos2 := []blockchain.SpentTxOut{{
+		Amount:   2000,
+		PkScript: watched,
+		Height:   1,
+	}}
+
+	idx := NewWatchIndex(db)
+	err = db.Update(func(dbTx database.Tx) error {
+		return idx.Create(dbTx)
+	})
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	if err := idx.AddScripts([][]byte{watched}, nil); err != nil {
+		t.Fatalf("AddScripts: unexpected error: %v", err)
+	}
+
+	update := func(f func(database.Tx) error) {
+		t.Helper()
+		if err := db.Update(f); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	checkOutputs := func(idx *WatchIndex, want ...wire.OutPoint) {
+		t.Helper()
+		got := idx.UnspentOutputs()
+		if len(got) != len(want) {
+			t.Fatalf("got %d unspent outputs, want %d", len(got),
+				len(want))
+		}
+		for _, op := range want {
+			found := false
+			for _, out := range got {
+				found = found || out.OutPoint == op
+			}
+			if !found {
+				t.Fatalf("unspent output %v is not tracked", op)
+			}
+		}
+	}
+
+	cbOut := wire.OutPoint{Hash: coinbase1.TxHash(), Index: 0}
+	txOut := wire.OutPoint{Hash: tx1.TxHash(), Index: 0}
+	update(func(dbTx database.Tx) error {
+		return idx.ConnectBlock(dbTx, block1, nil)
+	})
+	checkOutputs(idx, cbOut, txOut)
+	update(func(dbTx database.Tx) error {
+		return idx.ConnectBlock(dbTx, block2, stxos2)
+	})
+	checkOutputs(idx, cbOut)
+
+	// The outputs are loaded from the database.
+	reloaded := NewWatchIndex(db)
+	if err := reloaded.Init(); err != nil {
+		t.Fatalf("Init: unexpected error: %v", err)
+	}
+	if !reloaded.IsWatched(watched) || reloaded.IsWatched(other) {
+		t.Fatalf("IsWatched: watched scripts were not loaded")
+	}
+	checkOutputs(reloaded, cbOut)
+	for _, out := range reloaded.UnspentOutputs() {
+		if !out.IsCoinBase || out.Amount != 5000 || out.Height != 1 {
+			t.Fatalf("unexpected unspent output %+v", out)
+		}
+	}
+
+	// Disconnecting the blocks restores the spent output and then removes
+	// the outputs of the first block.
+	update(func(dbTx database.Tx) error {
+		return idx.DisconnectBlock(dbTx, block2, stxos2)
+	})
+	checkOutputs(idx, cbOut, txOut)
+	update(func(dbTx database.Tx) error {
+		return idx.DisconnectBlock(dbTx, block1, []blockchain.SpentTxOut{{
+			PkScript: other,
+		}})
+	})
+	checkOutputs(idx)
+
+	// A rescan finds the outputs of blocks connected before the script was
+	// watched.
+	rescan := NewWatchRescan([][]byte{other})
+	rescan.ScanBlock(block1)
+	rescan.ScanBlock(block2)
+	if err := idx.AddScripts([][]byte{other}, rescan); err != nil {
+		t.Fatalf("AddScripts: unexpected error: %v", err)
+	}
+	checkOutputs(idx,
+		wire.OutPoint{Hash: coinbase1.TxHash(), Index: 1},
+		wire.OutPoint{Hash: coinbase2.TxHash(), Index: 0},
+		wire.OutPoint{Hash: tx2.TxHash(), Index: 0})
+}
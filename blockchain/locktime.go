@@ -0,0 +1,93 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// SequenceToLockTime converts the passed sequence number to the relative lock
+// time it encodes in accordance to BIP-68, which makes it the inverse of
+// LockTimeToSequence.  The relative lock time is either a number of blocks or,
+// when isSeconds is true, a number of seconds with a granularity of 512
+// seconds.  The enabled flag is false when the sequence number disables the
+// relative lock time, in which case the other values are meaningless.
+func SequenceToLockTime(sequence uint32) (isSeconds bool, locktime uint32, enabled bool) {
+	if sequence&wire.SequenceLockTimeDisabled != 0 {
+		return false, 0, false
+	}
+
+	locktime = sequence & wire.SequenceLockTimeMask
+	if sequence&wire.SequenceLockTimeIsSeconds != 0 {
+		return true, locktime << wire.SequenceLockTimeGranularity, true
+	}
+	return false, locktime, true
+}
+
+// LockTimeIsSeconds returns whether the passed absolute lock time, as used by
+// the lock time of transactions and OP_CHECKLOCKTIMEVERIFY, is a timestamp
+// rather than a block height.
+func LockTimeIsSeconds(lockTime uint32) bool {
+	return lockTime >= txscript.LockTimeThreshold
+}
+
+// LockTimeActive determines if the passed absolute lock time has been reached
+// by a block at the passed height whose previous block has the passed median
+// time past, meaning a transaction with the lock time may be included in the
+// block and an OP_CHECKLOCKTIMEVERIFY of the lock time succeeds in it.
+func LockTimeActive(lockTime uint32, blockHeight int32, medianTimePast time.Time) bool {
+	if LockTimeIsSeconds(lockTime) {
+		return int64(lockTime) < medianTimePast.Unix()
+	}
+	return int64(lockTime) < int64(blockHeight)
+}
+
+// medianTimePastAt returns the median time past which applies to a block at the
+// passed height of the main chain, which is the median time past of its
+// previous block.  The height may be at most one more than the best height.
+func (b *BlockChain) medianTimePastAt(blockHeight int32) (time.Time, error) {
+	prevNode := b.bestChain.NodeByHeight(blockHeight - 1)
+	if prevNode == nil {
+		str := fmt.Sprintf("no block at height %d exists to compute "+
+			"the median time past of height %d", blockHeight-1,
+			blockHeight)
+		return time.Time{}, errNotInMainChain(str)
+	}
+	return CalcPastMedianTime(prevNode), nil
+}
+
+// SequenceLockActiveAt determines if the passed sequence locks, as computed by
+// CalcSequenceLock, have been met by a block at the passed height of the main
+// chain.  The median time past of the block is taken from the main chain, so
+// the height may be at most one more than the best height, which determines
+// whether the sequence locks allow inclusion in the next block.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) SequenceLockActiveAt(sequenceLock *SequenceLock, blockHeight int32) (bool, error) {
+	medianTimePast, err := b.medianTimePastAt(blockHeight)
+	if err != nil {
+		return false, err
+	}
+	return SequenceLockActive(sequenceLock, blockHeight, medianTimePast), nil
+}
+
+// LockTimeActiveAt determines if the passed absolute lock time has been reached
+// by a block at the passed height of the main chain.  The median time past of
+// the block is taken from the main chain, so the height may be at most one more
+// than the best height, which determines whether the lock time allows
+// inclusion in the next block.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) LockTimeActiveAt(lockTime uint32, blockHeight int32) (bool, error) {
+	medianTimePast, err := b.medianTimePastAt(blockHeight)
+	if err != nil {
+		return false, err
+	}
+	return LockTimeActive(lockTime, blockHeight, medianTimePast), nil
+}
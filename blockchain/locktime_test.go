@@ -0,0 +1,136 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestSequenceToLockTime ensures sequence numbers are converted back into the
+// relative lock times they were created from.
+func TestSequenceToLockTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		sequence  uint32
+		isSeconds bool
+		locktime  uint32
+		enabled   bool
+	}{
+		{"blocks", LockTimeToSequence(false, 144), false, 144, true},
+		{"seconds", LockTimeToSequence(true, 1024), true, 1024, true},
+		{"seconds rounded down", LockTimeToSequence(true, 1000), true, 512, true},
+		{"flags ignored", 1<<16 | 10, false, 10, true},
+		{"disabled", wire.SequenceLockTimeDisabled | 10, false, 0, false},
+		{"final", wire.MaxTxInSequenceNum, false, 0, false},
+	}
+
+	for _, test := range tests {
+		isSeconds, locktime, enabled := SequenceToLockTime(test.sequence)
+		if isSeconds != test.isSeconds || locktime != test.locktime ||
+			enabled != test.enabled {
+
+			t.Errorf("%s: got (%v, %d, %v), want (%v, %d, %v)",
+				test.name, isSeconds, locktime, enabled,
+				test.isSeconds, test.locktime, test.enabled)
+		}
+	}
+}
+
+// TestLockTimeActive ensures absolute lock times are compared against the
+// height or the median time past depending on their type.
+func TestLockTimeActive(t *testing.T) {
+	t.Parallel()
+
+	const timeLock = 600000000
+	mtp := time.Unix(timeLock, 0)
+	tests := []struct {
+		name     string
+		lockTime uint32
+		height   int32
+		mtp      time.Time
+		want     bool
+	}{
+		{"height reached", 99, 100, mtp, true},
+		{"height not reached", 100, 100, mtp, false},
+		{"time reached", timeLock - 1, 0, mtp, true},
+		{"time not reached", timeLock, 1 << 30, mtp, false},
+	}
+
+	for _, test := range tests {
+		got := LockTimeActive(test.lockTime, test.height, test.mtp)
+		if got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+	if !LockTimeIsSeconds(timeLock) || LockTimeIsSeconds(timeLock/2) {
+		t.Error("LockTimeIsSeconds: unexpected result")
+	}
+}
+
+// TestLockActiveAt ensures sequence locks and absolute lock times are evaluated
+// against the median time past of the main chain.
+func TestLockActiveAt(t *testing.T) {
+	chain := newFakeChain(&chaincfg.SimNetParams)
+	node := chain.bestChain.Tip()
+	blockTime := node.Header().Timestamp
+	for i := 0; i < 20; i++ {
+		blockTime = blockTime.Add(time.Minute)
+		node = newFakeNode(node, 1, 0, blockTime)
+		chain.index.AddNode(node)
+		chain.bestChain.SetTip(node)
+	}
+	nextHeight := node.height + 1
+	nextMTP := CalcPastMedianTime(node)
+
+	tests := []struct {
+		name string
+		lock SequenceLock
+		want bool
+	}{
+		{"disabled", SequenceLock{Seconds: -1, BlockHeight: -1}, true},
+		{"height reached", SequenceLock{Seconds: -1, BlockHeight: nextHeight - 1}, true},
+		{"height not reached", SequenceLock{Seconds: -1, BlockHeight: nextHeight}, false},
+		{"time reached", SequenceLock{Seconds: nextMTP.Unix() - 1, BlockHeight: -1}, true},
+		{"time not reached", SequenceLock{Seconds: nextMTP.Unix(), BlockHeight: -1}, false},
+	}
+	for _, test := range tests {
+		got, err := chain.SequenceLockActiveAt(&test.lock, nextHeight)
+		if err != nil || got != test.want {
+			t.Errorf("SequenceLockActiveAt: %s: got %v (err %v), want %v",
+				test.name, got, err, test.want)
+		}
+	}
+
+	lockTimes := []struct {
+		lockTime uint32
+		want     bool
+	}{
+		{uint32(nextHeight) - 1, true},
+		{uint32(nextHeight), false},
+		{uint32(nextMTP.Unix()) - 1, true},
+		{uint32(nextMTP.Unix()), false},
+	}
+	for _, test := range lockTimes {
+		got, err := chain.LockTimeActiveAt(test.lockTime, nextHeight)
+		if err != nil || got != test.want {
+			t.Errorf("LockTimeActiveAt(%d): got %v (err %v), want %v",
+				test.lockTime, got, err, test.want)
+		}
+	}
+
+	// Heights beyond the next block have no median time past yet.
+	if _, err := chain.LockTimeActiveAt(0, nextHeight+1); err == nil {
+		t.Error("LockTimeActiveAt: expected error for future height")
+	}
+	if _, err := chain.SequenceLockActiveAt(&SequenceLock{}, 0); err == nil {
+		t.Error("SequenceLockActiveAt: expected error for genesis height")
+	}
+}
@@ -21,6 +21,14 @@ const (
 	// local clock that is used to determine that it is likely wrong and
 	// hence to show a warning.
 	similarTimeSecs = 5 * 60 // 5 minutes
+
+	// maxSampleOffsetSecs is the maximum number of seconds in either
+	// direction from the local clock a time sample may be to be used when
+	// determining the median offset.  Samples that are further away are
+	// outliers which are most likely caused by broken or misbehaving peers,
+	// so they are ignored when adjusting the clock.  They are still taken
+	// into account when checking whether the local clock is skewed.
+	maxSampleOffsetSecs = 24 * 60 * 60 // 1 day
 )
 
 var (
@@ -47,6 +55,42 @@ type MedianTimeSource interface {
 	Offset() time.Duration
 }
 
+// MockTimeSource is a MedianTimeSource whose local clock can be replaced by a
+// fixed mock time.  This allows time dependent behavior such as the minimum
+// difficulty reduction of the test networks and the time offsets of peers to
+// be tested deterministically.
+type MockTimeSource interface {
+	MedianTimeSource
+
+	// SetMockTime makes the local clock return the passed time until it
+	// is set again.  Passing the zero time restores the real clock.
+	SetMockTime(t time.Time)
+
+	// MockTime returns the time the local clock is fixed to, or the zero
+	// time when the real clock is used.
+	MockTime() time.Time
+}
+
+// ClockSkew describes whether the local clock appears to be skewed according to
+// the time samples added to a MedianTimeSource.  It is the data of the
+// NTClockSkew notifications sent by the block chain.
+type ClockSkew struct {
+	// Skewed is whether the local clock appears to be skewed.  The
+	// median offset of the time samples is beyond the maximum allowed
+	// offset in that case and none of the samples are close to the local
+	// clock, so no offset is applied and blocks with valid timestamps
+	// might be rejected.
+	Skewed bool
+
+	// MedianOffset is the median offset of all time samples, including
+	// the outliers, from the local clock.
+	MedianOffset time.Duration
+
+	// NumSamples is the number of time samples the median offset was
+	// determined from.
+	NumSamples int
+}
+
 // int64Sorter implements sort.Interface to allow a slice of 64-bit integers to
 // be sorted.
 type int64Sorter []int64
@@ -73,17 +117,37 @@ func (s int64Sorter) Less(i, j int) bool {
 // medianTime provides an implementation of the MedianTimeSource interface.
 // It is limited to maxMedianTimeEntries includes the same buggy behavior as
 // the time offset mechanism in Litecoin Core.  This is necessary because it is
-// used in the consensus code.
+// used in the consensus code.  Time samples more than a day away from the
+// local clock are ignored as outliers when determining the offset.
 type medianTime struct {
-	mtx                sync.Mutex
-	knownIDs           map[string]struct{}
-	offsets            []int64
-	offsetSecs         int64
-	invalidTimeChecked bool
+	mtx         sync.Mutex
+	knownIDs    map[string]struct{}
+	offsets     []int64
+	offsetSecs  int64
+	clockSkewed bool
+
+	// skewCallback is invoked when the local clock becomes skewed or is no
+	// longer skewed according to the time samples.
+	skewCallback func(*ClockSkew)
+
+	// mockTime is the unix time the local clock is fixed to, or zero when
+	// the real clock is used.
+	mockTime int64
 }
 
-// Ensure the medianTime type implements the MedianTimeSource interface.
-var _ MedianTimeSource = (*medianTime)(nil)
+// Ensure the medianTime type implements the MockTimeSource interface.
+var _ MockTimeSource = (*medianTime)(nil)
+
+// now returns the time of the local clock limited to 1 second precision,
+// which is the mock time when it is set.
+//
+// This function MUST be called with the lock held.
+func (m *medianTime) now() time.Time {
+	if m.mockTime != 0 {
+		return time.Unix(m.mockTime, 0)
+	}
+	return time.Unix(time.Now().Unix(), 0)
+}
 
 // AdjustedTime returns the current time adjusted by the median time offset as
 // calculated from the time samples added by AddTimeSample.
@@ -94,9 +158,7 @@ func (m *medianTime) AdjustedTime() time.Time {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
-	// Limit the adjusted time to 1 second precision.
-	now := time.Unix(time.Now().Unix(), 0)
-	return now.Add(time.Duration(m.offsetSecs) * time.Second)
+	return m.now().Add(time.Duration(m.offsetSecs) * time.Second)
 }
 
 // AddTimeSample adds a time sample that is used when determining the median
@@ -106,11 +168,26 @@ func (m *medianTime) AdjustedTime() time.Time {
 // MedianTimeSource interface implementation.
 func (m *medianTime) AddTimeSample(sourceID string, timeVal time.Time) {
 	m.mtx.Lock()
-	defer m.mtx.Unlock()
+	skew := m.addTimeSample(sourceID, timeVal)
+	callback := m.skewCallback
+	m.mtx.Unlock()
+
+	// Notify the caller about changes of the clock skew without the lock
+	// held so the callback is free to query the time source.
+	if skew != nil && callback != nil {
+		callback(skew)
+	}
+}
 
+// addTimeSample adds a time sample that is used when determining the median
+// time of the added samples and returns the clock skew when the local clock
+// became skewed or is no longer skewed due to it.
+//
+// This function MUST be called with the lock held.
+func (m *medianTime) addTimeSample(sourceID string, timeVal time.Time) *ClockSkew {
 	// Don't add time data from the same source.
 	if _, exists := m.knownIDs[sourceID]; exists {
-		return
+		return nil
 	}
 	m.knownIDs[sourceID] = struct{}{}
 
@@ -118,8 +195,7 @@ func (m *medianTime) AddTimeSample(sourceID string, timeVal time.Time) {
 	// of offsets while respecting the maximum number of allowed entries by
 	// replacing the oldest entry with the new entry once the maximum number
 	// of entries is reached.
-	now := time.Unix(time.Now().Unix(), 0)
-	offsetSecs := int64(timeVal.Sub(now).Seconds())
+	offsetSecs := int64(timeVal.Sub(m.now()).Seconds())
 	numOffsets := len(m.offsets)
 	if numOffsets == maxMedianTimeEntries && maxMedianTimeEntries > 0 {
 		m.offsets = m.offsets[1:]
@@ -137,6 +213,25 @@ func (m *medianTime) AddTimeSample(sourceID string, timeVal time.Time) {
 	log.Debugf("Added time sample of %v (total: %v)", offsetDuration,
 		numOffsets)
 
+	skew := m.checkClockSkew(sortedOffsets)
+
+	// Ignore the outliers when determining the median offset.  Since the
+	// offsets are sorted, they are at either end.
+	candidates := sortedOffsets
+	for len(candidates) > 0 && -candidates[0] > maxSampleOffsetSecs {
+		candidates = candidates[1:]
+	}
+	for len(candidates) > 0 &&
+		candidates[len(candidates)-1] > maxSampleOffsetSecs {
+
+		candidates = candidates[:len(candidates)-1]
+	}
+	if len(candidates) != numOffsets {
+		log.Debugf("Ignoring %d outlying time samples",
+			numOffsets-len(candidates))
+	}
+	numCandidates := len(candidates)
+
 	// NOTE: The following code intentionally has a bug to mirror the
 	// buggy behavior in Litecoin Core since the median time is used in the
 	// consensus rules.
@@ -144,53 +239,89 @@ func (m *medianTime) AddTimeSample(sourceID string, timeVal time.Time) {
 	// In particular, the offset is only updated when the number of entries
 	// is odd, but the max number of entries is 200, an even number.  Thus,
 	// the offset will never be updated again once the max number of entries
-	// is reached.
+	// is reached unless some of them are outliers.
 
 	// The median offset is only updated when there are enough offsets and
 	// the number of offsets is odd so the middle value is the true median.
 	// Thus, there is nothing to do when those conditions are not met.
-	if numOffsets < 5 || numOffsets&0x01 != 1 {
-		return
+	if numCandidates < 5 || numCandidates&0x01 != 1 {
+		return skew
 	}
 
 	// At this point the number of offsets in the list is odd, so the
 	// middle value of the sorted offsets is the median.
-	median := sortedOffsets[numOffsets/2]
+	median := candidates[numCandidates/2]
 
 	// Set the new offset when the median offset is within the allowed
-	// offset range.
+	// offset range.  Otherwise, don't use an offset.  This effectively
+	// limits how far the local clock can be skewed.
 	if math.Abs(float64(median)) < maxAllowedOffsetSecs {
 		m.offsetSecs = median
 	} else {
-		// The median offset of all added time data is larger than the
-		// maximum allowed offset, so don't use an offset.  This
-		// effectively limits how far the local clock can be skewed.
 		m.offsetSecs = 0
+	}
 
-		if !m.invalidTimeChecked {
-			m.invalidTimeChecked = true
-
-			// Find if any time samples have a time that is close
-			// to the local time.
-			var remoteHasCloseTime bool
-			for _, offset := range sortedOffsets {
-				if math.Abs(float64(offset)) < similarTimeSecs {
-					remoteHasCloseTime = true
-					break
-				}
-			}
+	medianDuration := time.Duration(m.offsetSecs) * time.Second
+	log.Debugf("New time offset: %v", medianDuration)
+	return skew
+}
+
+// checkClockSkew updates whether the local clock is skewed according to the
+// passed sorted offsets of all time samples and returns the clock skew when
+// that changed.  The local clock is considered skewed when the median offset is
+// beyond the maximum allowed offset and none of the samples are close to it.
+//
+// This function MUST be called with the lock held.
+func (m *medianTime) checkClockSkew(sortedOffsets []int64) *ClockSkew {
+	numOffsets := len(sortedOffsets)
+	if numOffsets < 5 {
+		return nil
+	}
+	median := sortedOffsets[numOffsets/2]
 
-			// Warn if none of the time samples are close.
-			if !remoteHasCloseTime {
-				log.Warnf("Please check your date and time " +
-					"are correct!  ltcd will not work " +
-					"properly with an invalid time")
+	skewed := false
+	if math.Abs(float64(median)) >= maxAllowedOffsetSecs {
+		// Find if any time samples have a time that is close to the
+		// local time.
+		skewed = true
+		for _, offset := range sortedOffsets {
+			if math.Abs(float64(offset)) < similarTimeSecs {
+				skewed = false
+				break
 			}
 		}
 	}
+	if skewed == m.clockSkewed {
+		return nil
+	}
+	m.clockSkewed = skewed
+
+	medianDuration := time.Duration(median) * time.Second
+	if skewed {
+		log.Warnf("Please check your date and time are correct!  "+
+			"The median time of %d peers is off by %v, so ltcd will "+
+			"not work properly with an invalid time", numOffsets,
+			medianDuration)
+	} else {
+		log.Infof("The local clock is no longer skewed from the median "+
+			"time of %d peers", numOffsets)
+	}
 
-	medianDuration := time.Duration(m.offsetSecs) * time.Second
-	log.Debugf("New time offset: %v", medianDuration)
+	return &ClockSkew{
+		Skewed:       skewed,
+		MedianOffset: medianDuration,
+		NumSamples:   numOffsets,
+	}
+}
+
+// setSkewCallback sets the function invoked when the local clock becomes skewed
+// or is no longer skewed according to the time samples.
+//
+// This function is safe for concurrent access.
+func (m *medianTime) setSkewCallback(callback func(*ClockSkew)) {
+	m.mtx.Lock()
+	m.skewCallback = callback
+	m.mtx.Unlock()
 }
 
 // Offset returns the number of seconds to adjust the local clock based upon the
@@ -205,6 +336,37 @@ func (m *medianTime) Offset() time.Duration {
 	return time.Duration(m.offsetSecs) * time.Second
 }
 
+// SetMockTime makes the local clock return the passed time until it is set
+// again.  Passing the zero time restores the real clock.
+//
+// This function is safe for concurrent access and is part of the
+// MockTimeSource interface implementation.
+func (m *medianTime) SetMockTime(t time.Time) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if t.IsZero() {
+		m.mockTime = 0
+		return
+	}
+	m.mockTime = t.Unix()
+}
+
+// MockTime returns the time the local clock is fixed to, or the zero time when
+// the real clock is used.
+//
+// This function is safe for concurrent access and is part of the
+// MockTimeSource interface implementation.
+func (m *medianTime) MockTime() time.Time {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.mockTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(m.mockTime, 0)
+}
+
 // NewMedianTime returns a new instance of concurrency-safe implementation of
 // the MedianTimeSource interface.  The returned implementation contains the
 // rules necessary for proper time handling in the chain consensus rules and
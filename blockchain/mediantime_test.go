@@ -50,6 +50,11 @@ func TestMedianTime(t *testing.T) {
 		// sample that is close enough to the current time to avoid
 		// triggering a warning about an invalid local clock.
 		{in: []int64{4201, 4202, 4203, 4204, -299}, wantOffset: 0},
+
+		// Outliers more than a day away from the local time are ignored
+		// when determining the median offset.
+		{in: []int64{10, 20, 30, 40, 50, 86401, 90000}, wantOffset: 30},
+		{in: []int64{-90000, 10, 20, 30, 40, 50}, wantOffset: 30},
 	}
 
 	// Modify the max number of allowed median time entries for these tests.
@@ -102,3 +107,96 @@ func TestMedianTime(t *testing.T) {
 		}
 	}
 }
+
+// TestMedianTimeMock ensures the mock time replaces the local clock both for
+// the adjusted time and for the offsets of the added time samples.
+func TestMedianTimeMock(t *testing.T) {
+	filter := NewMedianTime().(MockTimeSource)
+	if !filter.MockTime().IsZero() {
+		t.Fatalf("MockTime: got %v before it was set", filter.MockTime())
+	}
+
+	mockTime := time.Unix(1600000000, 0)
+	filter.SetMockTime(mockTime)
+	if got := filter.MockTime(); !got.Equal(mockTime) {
+		t.Fatalf("MockTime: got %v, want %v", got, mockTime)
+	}
+	if got := filter.AdjustedTime(); !got.Equal(mockTime) {
+		t.Fatalf("AdjustedTime: got %v, want %v", got, mockTime)
+	}
+
+	// The offsets of the time samples are relative to the mock time.
+	for i, offset := range []int64{-10, 20, 30, 40, 50} {
+		sample := mockTime.Add(time.Duration(offset) * time.Second)
+		filter.AddTimeSample(strconv.Itoa(i), sample)
+	}
+	if got := filter.Offset(); got != 30*time.Second {
+		t.Fatalf("Offset: got %v, want %v", got, 30*time.Second)
+	}
+	want := mockTime.Add(30 * time.Second)
+	if got := filter.AdjustedTime(); !got.Equal(want) {
+		t.Fatalf("AdjustedTime: got %v, want %v", got, want)
+	}
+
+	// Resetting the mock time restores the local clock.
+	filter.SetMockTime(time.Time{})
+	if !filter.MockTime().IsZero() {
+		t.Fatalf("MockTime: got %v after it was reset", filter.MockTime())
+	}
+	if got := filter.AdjustedTime(); got.Sub(time.Now()) < 25*time.Second {
+		t.Fatalf("AdjustedTime: got %v, want the local time plus the "+
+			"offset", got)
+	}
+}
+
+// TestMedianTimeClockSkew ensures the skew callback is invoked when the local
+// clock becomes skewed according to the time samples and once it is no longer
+// skewed.
+func TestMedianTimeClockSkew(t *testing.T) {
+	filter := NewMedianTime().(*medianTime)
+	mockTime := time.Unix(1600000000, 0)
+	filter.SetMockTime(mockTime)
+
+	var skews []*ClockSkew
+	filter.setSkewCallback(func(skew *ClockSkew) {
+		skews = append(skews, skew)
+	})
+	addSamples := func(offsets ...int64) {
+		for _, offset := range offsets {
+			id := strconv.Itoa(len(filter.knownIDs))
+			sample := mockTime.Add(time.Duration(offset) * time.Second)
+			filter.AddTimeSample(id, sample)
+		}
+	}
+
+	// The clock is skewed once the median offset is beyond the maximum
+	// allowed offset and no sample is close to the local clock.  It is
+	// only reported once.
+	addSamples(7200, 7300, 7400, 7500)
+	if len(skews) != 0 {
+		t.Fatalf("skew reported with too few samples")
+	}
+	addSamples(7600)
+	if len(skews) != 1 || !skews[0].Skewed {
+		t.Fatalf("skew not reported: %v", skews)
+	}
+	if skews[0].MedianOffset != 7400*time.Second ||
+		skews[0].NumSamples != 5 {
+
+		t.Fatalf("unexpected skew %+v", skews[0])
+	}
+	addSamples(7700)
+	if len(skews) != 1 {
+		t.Fatalf("skew reported again: %v", skews)
+	}
+	if filter.Offset() != 0 {
+		t.Fatalf("Offset: got %v for a skewed clock", filter.Offset())
+	}
+
+	// The clock is no longer skewed once the median offset is close to
+	// the local clock again.
+	addSamples(0, 1, 2, 3, 4, 5, 6)
+	if len(skews) != 2 || skews[1].Skewed {
+		t.Fatalf("end of skew not reported: %v", skews)
+	}
+}
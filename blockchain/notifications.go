@@ -29,6 +29,16 @@ const (
 	// NTBlockDisconnected indicates the associated block was disconnected
 	// from the main chain.
 	NTBlockDisconnected
+
+	// NTClockSkew indicates the local clock became skewed or is no longer
+	// skewed according to the time samples added to the time source of
+	// the chain.  Blocks with valid timestamps might be rejected while the
+	// local clock is skewed.
+	NTClockSkew
+
+	// NTWarning indicates a warning about a condition which might prevent
+	// the node from working properly was raised or cleared.
+	NTWarning
 )
 
 // notificationTypeStrings is a map of notification types back to their constant
@@ -37,6 +47,8 @@ var notificationTypeStrings = map[NotificationType]string{
 	NTBlockAccepted:     "NTBlockAccepted",
 	NTBlockConnected:    "NTBlockConnected",
 	NTBlockDisconnected: "NTBlockDisconnected",
+	NTClockSkew:         "NTClockSkew",
+	NTWarning:           "NTWarning",
 }
 
 // String returns the NotificationType in human-readable form.
@@ -53,6 +65,8 @@ func (n NotificationType) String() string {
 //   - NTBlockAccepted:     *ltcutil.Block
 //   - NTBlockConnected:    *ltcutil.Block
 //   - NTBlockDisconnected: *ltcutil.Block
+//   - NTClockSkew:         *ClockSkew
+//   - NTWarning:           *Warning
 type Notification struct {
 	Type NotificationType
 	Data interface{}
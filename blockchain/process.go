@@ -5,12 +5,15 @@
 package blockchain
 
 import (
+	"context"
 	"fmt"
+	"runtime/trace"
 	"time"
 
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/database"
 	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
 )
 
 // BehaviorFlags is a bitmask defining tweaks to the normal behavior when
@@ -34,13 +37,15 @@ const (
 )
 
 // blockExists determines whether a block with the given hash exists either in
-// the main chain or any side chains.
+// the main chain or any side chains.  Blocks whose headers are in the block
+// index without their data, such as the ones added by ProcessBlockHeader, do
+// not exist.
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) blockExists(hash *chainhash.Hash) (bool, error) {
 	// Check block index first (could be main chain or side chain blocks).
-	if b.index.HaveBlock(hash) {
-		return true, nil
+	if node := b.index.LookupNode(hash); node != nil {
+		return b.index.NodeStatus(node).HaveData(), nil
 	}
 
 	// Check in the database.
@@ -77,10 +82,12 @@ func (b *BlockChain) blockExists(hash *chainhash.Hash) (bool, error) {
 // orphans which may no longer be orphans) until there are no more.
 //
 // The flags do not modify the behavior of this function directly, however they
-// are needed to pass along to maybeAcceptBlock.
+// are needed to pass along to maybeAcceptBlock along with the context.
 //
 // This function MUST be called with the chain state lock held (for writes).
-func (b *BlockChain) processOrphans(hash *chainhash.Hash, flags BehaviorFlags) error {
+func (b *BlockChain) processOrphans(ctx context.Context, hash *chainhash.Hash,
+	flags BehaviorFlags) error {
+
 	// Start with processing at least the passed hash.  Leave a little room
 	// for additional orphan blocks that need to be processed without
 	// needing to grow the array in the common case.
@@ -115,7 +122,7 @@ func (b *BlockChain) processOrphans(hash *chainhash.Hash, flags BehaviorFlags) e
 			i--
 
 			// Potentially accept the block into the block chain.
-			_, err := b.maybeAcceptBlock(orphan.block, flags)
+			_, err := b.maybeAcceptBlock(ctx, orphan.block, flags)
 			if err != nil {
 				return err
 			}
@@ -140,13 +147,36 @@ func (b *BlockChain) processOrphans(hash *chainhash.Hash, flags BehaviorFlags) e
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) ProcessBlock(block *ltcutil.Block, flags BehaviorFlags) (bool, bool, error) {
+	return b.ProcessBlockContext(context.Background(), block, flags)
+}
+
+// ProcessBlockContext is the same as ProcessBlock, but it stops early with the
+// error of the passed context once the context is done.  This allows callers
+// such as the sync manager to shut down promptly instead of waiting for a deep
+// reorganization to finish.
+//
+// The context is checked before each block of a reorganization is loaded and
+// validated, which is where almost all of its time is spent.  Nothing about the
+// best chain has been changed at that point, so the block is left on its side
+// chain, and the chain is reorganized to it once it is extended again.  Once
+// the best chain starts being modified, the reorganization always completes.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) ProcessBlockContext(ctx context.Context, block *ltcutil.Block,
+	flags BehaviorFlags) (bool, bool, error) {
+
 	b.chainLock.Lock()
 	defer b.chainLock.Unlock()
 
+	defer startTraceRegion(traceRegionProcessBlock).End()
+
 	fastAdd := flags&BFFastAdd == BFFastAdd
 
 	blockHash := block.Hash()
 	log.Tracef("Processing block %v", blockHash)
+	if trace.IsEnabled() {
+		trace.Log(ctx, "block", blockHash.String())
+	}
 
 	// The block must not already exist in the main chain or side chains.
 	exists, err := b.blockExists(blockHash)
@@ -165,7 +195,7 @@ func (b *BlockChain) ProcessBlock(block *ltcutil.Block, flags BehaviorFlags) (bo
 	}
 
 	// Perform preliminary sanity checks on the block and its transactions.
-	err = checkBlockSanity(block, b.chainParams.PowLimit, b.timeSource, flags)
+	err = checkBlockSanity(block, b.chainParams, b.timeSource, flags)
 	if err != nil {
 		return false, false, err
 	}
@@ -183,7 +213,7 @@ func (b *BlockChain) ProcessBlock(block *ltcutil.Block, flags BehaviorFlags) (bo
 	}
 	if checkpointNode != nil {
 		// Ensure the block timestamp is after the checkpoint timestamp.
-		checkpointTime := time.Unix(checkpointNode.timestamp, 0)
+		checkpointTime := time.Unix(checkpointNode.Timestamp(), 0)
 		if blockHeader.Timestamp.Before(checkpointTime) {
 			str := fmt.Sprintf("block %v has timestamp %v before "+
 				"last checkpoint timestamp %v", blockHash,
@@ -191,21 +221,18 @@ func (b *BlockChain) ProcessBlock(block *ltcutil.Block, flags BehaviorFlags) (bo
 			return false, false, ruleError(ErrCheckpointTimeTooOld, str)
 		}
 		if !fastAdd {
-			// Even though the checks prior to now have already ensured the
-			// proof of work exceeds the claimed amount, the claimed amount
-			// is a field in the block header which could be forged.  This
-			// check ensures the proof of work is at least the minimum
-			// expected based on elapsed time since the last checkpoint and
-			// maximum adjustment allowed by the retarget rules.
-			duration := blockHeader.Timestamp.Sub(checkpointTime)
-			requiredTarget := CompactToBig(b.calcEasiestDifficulty(
-				checkpointNode.bits, duration))
-			currentTarget := CompactToBig(blockHeader.Bits)
-			if currentTarget.Cmp(requiredTarget) > 0 {
-				str := fmt.Sprintf("block target difficulty of %064x "+
-					"is too low when compared to the previous "+
-					"checkpoint", currentTarget)
-				return false, false, ruleError(ErrDifficultyTooLow, str)
+			// The height of the block is only known when its parent
+			// is.  Otherwise all difficulty algorithms which may
+			// apply after the checkpoint are taken into account.
+			height := int32(-1)
+			prevNode := b.index.LookupNode(&blockHeader.PrevBlock)
+			if prevNode != nil {
+				height = prevNode.height + 1
+			}
+			err := checkCheckpointDifficulty(blockHeader, height,
+				checkpointNode, b)
+			if err != nil {
+				return false, false, err
 			}
 		}
 	}
@@ -225,7 +252,7 @@ func (b *BlockChain) ProcessBlock(block *ltcutil.Block, flags BehaviorFlags) (bo
 
 	// The block has passed all context independent checks and appears sane
 	// enough to potentially accept it into the block chain.
-	isMainChain, err := b.maybeAcceptBlock(block, flags)
+	isMainChain, err := b.maybeAcceptBlock(ctx, block, flags)
 	if err != nil {
 		return false, false, err
 	}
@@ -233,12 +260,86 @@ func (b *BlockChain) ProcessBlock(block *ltcutil.Block, flags BehaviorFlags) (bo
 	// Accept any orphan blocks that depend on this block (they are
 	// no longer orphans) and repeat for those accepted blocks until
 	// there are no more.
-	err = b.processOrphans(blockHash, flags)
+	err = b.processOrphans(ctx, blockHash, flags)
 	if err != nil {
 		return false, false, err
 	}
 
+	// Remove side chains which have fallen too far behind now that the
+	// main chain may have advanced.
+	if isMainChain {
+		b.maybePruneStaleForks()
+	}
+
 	log.Debugf("Accepted block %v", blockHash)
 
 	return isMainChain, false, nil
 }
+
+// ProcessBlockHeader is the main workhorse for handling insertion of bare block
+// headers into the block index ahead of their blocks.  It includes
+// functionality such as rejecting duplicate headers, ensuring headers follow
+// all rules that do not depend on the block data, and storing the header in
+// the block index so its block can be accepted once received.
+//
+// Headers whose parent is not in the block index are rejected since, unlike
+// blocks, they are not held as orphans.  Headers which are already known are
+// accepted again without error unless they are known to be invalid.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) ProcessBlockHeader(header *wire.BlockHeader,
+	flags BehaviorFlags) error {
+
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+
+	blockHash := header.BlockHash()
+	log.Tracef("Processing block header %v", blockHash)
+
+	// Nothing more to do when the header is already known.
+	if node := b.index.LookupNode(&blockHash); node != nil {
+		if b.index.NodeStatus(node).KnownInvalid() {
+			str := fmt.Sprintf("already have block header %v and "+
+				"it is known to be invalid", blockHash)
+			return ruleError(ErrDuplicateBlock, str)
+		}
+		return nil
+	}
+
+	// Perform preliminary sanity checks on the header.
+	err := CheckBlockHeaderSanity(header, b.chainParams.PowLimit,
+		b.timeSource, flags)
+	if err != nil {
+		return err
+	}
+
+	prevHash := &header.PrevBlock
+	prevNode := b.index.LookupNode(prevHash)
+	if prevNode == nil {
+		str := fmt.Sprintf("previous block %s is unknown", prevHash)
+		return ruleError(ErrPreviousBlockUnknown, str)
+	} else if b.index.NodeStatus(prevNode).KnownInvalid() {
+		str := fmt.Sprintf("previous block %s is known to be invalid",
+			prevHash)
+		return ruleError(ErrInvalidAncestorBlock, str)
+	}
+
+	// The header must pass all of the validation rules which depend on its
+	// position within the block chain.
+	err = CheckBlockHeaderContext(header, prevNode, flags, b, false)
+	if err != nil {
+		return err
+	}
+
+	// Add a node without any data for the header to the block index.  It
+	// is updated once the block is accepted.
+	newNode := newBlockNode(header, prevNode)
+	b.index.AddNode(newNode)
+	if err := b.index.flushToDB(); err != nil {
+		return err
+	}
+
+	log.Debugf("Accepted block header %v", blockHash)
+
+	return nil
+}
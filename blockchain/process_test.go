@@ -0,0 +1,289 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// solveTestBlock returns a block with only a coinbase transaction which builds
+// on the passed parent and satisfies the proof of work of the regression test
+// network.
+func solveTestBlock(t *testing.T, parent *chainhash.Hash, height int32,
+	timestamp time.Time) *ltcutil.Block {
+
+	return solveTestBlockTxns(t, parent, height, timestamp, []byte{0x51})
+}
+
+// solveTestBlockTxns returns a block with a coinbase transaction paying to the
+// passed public key script followed by the passed transactions which builds on
+// the passed parent and satisfies the proof of work of the regression test
+// network.
+func solveTestBlockTxns(t *testing.T, parent *chainhash.Hash, height int32,
+	timestamp time.Time, pkScript []byte, txns ...*wire.MsgTx) *ltcutil.Block {
+
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{},
+			wire.MaxPrevOutIndex),
+		SignatureScript: []byte{0x51, byte(height), byte(height >> 8)},
+		Sequence:        wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(0, pkScript))
+
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   4,
+			PrevBlock: *parent,
+			Timestamp: timestamp,
+			Bits:      chaincfg.RegressionNetParams.PowLimitBits,
+		},
+		Transactions: append([]*wire.MsgTx{coinbase}, txns...),
+	}
+	block.Header.MerkleRoot = CalcMerkleRoot(
+		ltcutil.NewBlock(block).Transactions(), false)
+
+	target := CompactToBig(block.Header.Bits)
+	for nonce := uint32(0); ; nonce++ {
+		block.Header.Nonce = nonce
+		hash := block.Header.PowHash()
+		if HashToBig(&hash).Cmp(target) <= 0 {
+			break
+		}
+		if nonce == ^uint32(0) {
+			t.Fatalf("unable to solve block at height %d", height)
+		}
+	}
+	return ltcutil.NewBlock(block)
+}
+
+// TestProcessBlockHeader ensures bare headers are accepted into the block index
+// ahead of their blocks and that the blocks are accepted once received.
+func TestProcessBlockHeader(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("processblockheader",
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+
+	genesis := chain.BestSnapshot()
+	timestamp := time.Unix(chaincfg.RegressionNetParams.GenesisBlock.
+		Header.Timestamp.Unix()+60, 0)
+	block1 := solveTestBlock(t, &genesis.Hash, 1, timestamp)
+	block2 := solveTestBlock(t, block1.Hash(), 2,
+		timestamp.Add(time.Minute))
+
+	// A header whose parent is not known is rejected.
+	err = chain.ProcessBlockHeader(&block2.MsgBlock().Header, BFNone)
+	if !isRuleErrorCode(err, ErrPreviousBlockUnknown) {
+		t.Fatalf("ProcessBlockHeader: unexpected error for a header "+
+			"with an unknown parent: %v", err)
+	}
+
+	// A header which builds on a known block is accepted, again when it is
+	// submitted twice, without making its block known.
+	for i := 0; i < 2; i++ {
+		err = chain.ProcessBlockHeader(&block1.MsgBlock().Header, BFNone)
+		if err != nil {
+			t.Fatalf("ProcessBlockHeader: unexpected error: %v", err)
+		}
+	}
+	if _, err := chain.HeaderByHash(block1.Hash()); err != nil {
+		t.Fatalf("HeaderByHash: unexpected error: %v", err)
+	}
+	haveBlock, err := chain.HaveBlock(block1.Hash())
+	if err != nil {
+		t.Fatalf("HaveBlock: unexpected error: %v", err)
+	}
+	if haveBlock {
+		t.Fatalf("HaveBlock: block of a bare header is known")
+	}
+	tips := chain.ChainTips()
+	if len(tips) != 2 || tips[1].Hash != *block1.Hash() ||
+		tips[1].Status != ChainTipHeadersOnly {
+
+		t.Fatalf("ChainTips: unexpected tips %v", tips)
+	}
+
+	// A block whose parent is only known by its header is an orphan.
+	_, isOrphan, err := chain.ProcessBlock(block2, BFNone)
+	if err != nil {
+		t.Fatalf("ProcessBlock: unexpected error: %v", err)
+	}
+	if !isOrphan {
+		t.Fatalf("ProcessBlock: block building on a bare header is " +
+			"not an orphan")
+	}
+
+	// Once the block of the header is received, it is connected along with
+	// the orphan building on it.
+	isMainChain, isOrphan, err := chain.ProcessBlock(block1, BFNone)
+	if err != nil {
+		t.Fatalf("ProcessBlock: unexpected error: %v", err)
+	}
+	if !isMainChain || isOrphan {
+		t.Fatalf("ProcessBlock: block of a bare header was not " +
+			"connected")
+	}
+	best := chain.BestSnapshot()
+	if best.Height != 2 || best.Hash != *block2.Hash() {
+		t.Fatalf("BestSnapshot: unexpected tip %v at height %d",
+			best.Hash, best.Height)
+	}
+	tips = chain.ChainTips()
+	if len(tips) != 1 || tips[0].Status != ChainTipActive {
+		t.Fatalf("ChainTips: unexpected tips %v", tips)
+	}
+}
+
+// TestProcessBlockIndexWrites ensures the block index entries of processed
+// blocks are written to the database along with the blocks, both for blocks
+// connected to the main chain and for side chain blocks.
+func TestProcessBlockIndexWrites(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("processblockindexwrites",
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+
+	// dbStatus returns the status of the block stored in the database
+	// block index.
+	dbStatus := func(hash *chainhash.Hash, height int32) blockStatus {
+		var status blockStatus
+		err := chain.db.View(func(dbTx database.Tx) error {
+			bucket := dbTx.Metadata().Bucket(blockIndexBucketName)
+			value := bucket.Get(blockIndexKey(hash, uint32(height)))
+			if len(value) != blockHdrSize+1 {
+				t.Fatalf("block %v is not in the database block "+
+					"index", hash)
+			}
+			status = blockStatus(value[blockHdrSize])
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unable to read block index: %v", err)
+		}
+		return status
+	}
+
+	genesis := chain.BestSnapshot()
+	timestamp := time.Unix(chaincfg.RegressionNetParams.GenesisBlock.
+		Header.Timestamp.Unix()+60, 0)
+	block1 := solveTestBlock(t, &genesis.Hash, 1, timestamp)
+	sideBlock1 := solveTestBlock(t, &genesis.Hash, 1,
+		timestamp.Add(time.Second))
+	tests := []struct {
+		name       string
+		block      *ltcutil.Block
+		isMain     bool
+		wantStatus blockStatus
+	}{
+		{"main chain", block1, true, statusDataStored | statusValid},
+		{"side chain", sideBlock1, false, statusDataStored},
+	}
+	for _, test := range tests {
+		isMainChain, _, err := chain.ProcessBlock(test.block, BFNone)
+		if err != nil {
+			t.Fatalf("%s: ProcessBlock: unexpected error: %v",
+				test.name, err)
+		}
+		if isMainChain != test.isMain {
+			t.Fatalf("%s: ProcessBlock: got main chain %v, want %v",
+				test.name, isMainChain, test.isMain)
+		}
+		if numDirty := len(chain.index.dirty); numDirty != 0 {
+			t.Fatalf("%s: %d block index nodes were not written",
+				test.name, numDirty)
+		}
+		status := dbStatus(test.block.Hash(), 1)
+		if status != test.wantStatus {
+			t.Fatalf("%s: got stored status %08b, want %08b",
+				test.name, status, test.wantStatus)
+		}
+	}
+}
+
+// TestProcessBlockContextCancel ensures a reorganization is abandoned without
+// changing the best chain when the context is done, and that it happens once
+// the side chain is extended again.
+func TestProcessBlockContextCancel(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("processblockcontextcancel",
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+
+	// addBlocks processes blocks building on the passed parent up to the
+	// passed height and returns the last one.
+	genesis := chain.BestSnapshot()
+	timestamp := chaincfg.RegressionNetParams.GenesisBlock.Header.Timestamp
+	addBlocks := func(parent chainhash.Hash, height, tipHeight int32) *ltcutil.Block {
+		var block *ltcutil.Block
+		for ; height <= tipHeight; height++ {
+			timestamp = timestamp.Add(time.Minute)
+			block = solveTestBlock(t, &parent, height, timestamp)
+			_, _, err := chain.ProcessBlock(block, BFNone)
+			if err != nil {
+				t.Fatalf("ProcessBlock: unexpected error: %v", err)
+			}
+			parent = *block.Hash()
+		}
+		return block
+	}
+	mainTip := addBlocks(genesis.Hash, 1, 3)
+	sideTip := addBlocks(genesis.Hash, 1, 3)
+
+	// The block which gives the side chain more work is accepted, but the
+	// reorganization to it is abandoned since the context is cancelled.
+	timestamp = timestamp.Add(time.Minute)
+	sideBlock4 := solveTestBlock(t, sideTip.Hash(), 4, timestamp)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = chain.ProcessBlockContext(ctx, sideBlock4, BFNone)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ProcessBlockContext: got error %v, want %v", err,
+			context.Canceled)
+	}
+	best := chain.BestSnapshot()
+	if best.Hash != *mainTip.Hash() {
+		t.Fatalf("BestSnapshot: best chain changed to %v at height %d",
+			best.Hash, best.Height)
+	}
+	haveBlock, err := chain.HaveBlock(sideBlock4.Hash())
+	if err != nil {
+		t.Fatalf("HaveBlock: unexpected error: %v", err)
+	}
+	if !haveBlock {
+		t.Fatalf("HaveBlock: block of abandoned reorganization is " +
+			"not known")
+	}
+
+	// Extending the side chain again reorganizes to it.
+	sideBlock5 := addBlocks(*sideBlock4.Hash(), 5, 5)
+	best = chain.BestSnapshot()
+	if best.Hash != *sideBlock5.Hash() {
+		t.Fatalf("BestSnapshot: got best chain %v at height %d, want %v",
+			best.Hash, best.Height, sideBlock5.Hash())
+	}
+}
+
+// isRuleErrorCode returns whether the passed error is a RuleError with the
+// passed error code.
+func isRuleErrorCode(err error, code ErrorCode) bool {
+	rerr, ok := err.(RuleError)
+	return ok && rerr.ErrorCode == code
+}
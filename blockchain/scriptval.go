@@ -10,6 +10,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/txscript"
 	"github.com/ltcsuite/ltcd/wire"
@@ -33,6 +34,7 @@ type txValidator struct {
 	utxoView     *UtxoViewpoint
 	flags        txscript.ScriptFlags
 	sigCache     *txscript.SigCache
+	scriptCache  *txscript.ScriptCache
 	hashCache    *txscript.HashCache
 }
 
@@ -69,11 +71,25 @@ out:
 				break out
 			}
 
-			// Create a new script engine for the script pair.
+			// Skip inputs which have already been validated under
+			// the same flags.
 			sigScript := txIn.SignatureScript
 			witness := txIn.Witness
 			pkScript := utxo.PkScript()
 			inputAmount := utxo.Amount()
+			var cacheKey chainhash.Hash
+			if v.scriptCache != nil {
+				cacheKey = txscript.ScriptCacheKey(
+					txVI.tx.WitnessHash(), txVI.txInIndex,
+					pkScript, inputAmount, v.flags,
+				)
+				if v.scriptCache.Exists(cacheKey) {
+					v.sendResult(nil)
+					continue
+				}
+			}
+
+			// Create a new script engine for the script pair.
 			vm, err := txscript.NewEngine(
 				pkScript, txVI.tx.MsgTx(), txVI.txInIndex,
 				v.flags, v.sigCache, txVI.sigHashes,
@@ -107,6 +123,9 @@ out:
 			}
 
 			// Validation succeeded.
+			if v.scriptCache != nil {
+				v.scriptCache.Add(cacheKey)
+			}
 			v.sendResult(nil)
 
 		case <-v.quitChan:
@@ -176,23 +195,28 @@ func (v *txValidator) Validate(items []*txValidateItem) error {
 // newTxValidator returns a new instance of txValidator to be used for
 // validating transaction scripts asynchronously.
 func newTxValidator(utxoView *UtxoViewpoint, flags txscript.ScriptFlags,
-	sigCache *txscript.SigCache, hashCache *txscript.HashCache) *txValidator {
+	sigCache *txscript.SigCache, scriptCache *txscript.ScriptCache,
+	hashCache *txscript.HashCache) *txValidator {
 	return &txValidator{
 		validateChan: make(chan *txValidateItem),
 		quitChan:     make(chan struct{}),
 		resultChan:   make(chan error),
 		utxoView:     utxoView,
 		sigCache:     sigCache,
+		scriptCache:  scriptCache,
 		hashCache:    hashCache,
 		flags:        flags,
 	}
 }
 
 // ValidateTransactionScripts validates the scripts for the passed transaction
-// using multiple goroutines.
+// using multiple goroutines.  Inputs found in the script cache, if provided,
+// are not validated again, and successfully validated inputs are added to it.
 func ValidateTransactionScripts(tx *ltcutil.Tx, utxoView *UtxoViewpoint,
 	flags txscript.ScriptFlags, sigCache *txscript.SigCache,
-	hashCache *txscript.HashCache) error {
+	scriptCache *txscript.ScriptCache, hashCache *txscript.HashCache) error {
+
+	defer startTraceRegion(traceRegionValidateTxScripts).End()
 
 	// First determine if segwit is active according to the scriptFlags. If
 	// it isn't then we don't need to interact with the HashCache.
@@ -201,7 +225,7 @@ func ValidateTransactionScripts(tx *ltcutil.Tx, utxoView *UtxoViewpoint,
 	// If the hashcache doesn't yet has the sighash midstate for this
 	// transaction, then we'll compute them now so we can re-use them
 	// amongst all worker validation goroutines.
-	if segwitActive && tx.MsgTx().HasWitness() &&
+	if segwitActive && tx.MsgTx().HasWitness() && hashCache != nil &&
 		!hashCache.ContainsHashes(tx.Hash()) {
 		hashCache.AddSigHashes(tx.MsgTx(), utxoView)
 	}
@@ -213,7 +237,13 @@ func ValidateTransactionScripts(tx *ltcutil.Tx, utxoView *UtxoViewpoint,
 		// pre-computing the sighash here instead of during validation,
 		// we ensure the sighashes
 		// are only computed once.
-		cachedHashes, _ = hashCache.GetSigHashes(tx.Hash())
+		if hashCache != nil {
+			cachedHashes, _ = hashCache.GetSigHashes(tx.Hash())
+		} else {
+			cachedHashes = txscript.NewTxSigHashes(
+				tx.MsgTx(), utxoView,
+			)
+		}
 	}
 
 	// Collect all of the transaction inputs and required information for
@@ -236,7 +266,8 @@ func ValidateTransactionScripts(tx *ltcutil.Tx, utxoView *UtxoViewpoint,
 	}
 
 	// Validate all of the inputs.
-	validator := newTxValidator(utxoView, flags, sigCache, hashCache)
+	validator := newTxValidator(utxoView, flags, sigCache, scriptCache,
+		hashCache)
 	return validator.Validate(txValItems)
 }
 
@@ -244,7 +275,9 @@ func ValidateTransactionScripts(tx *ltcutil.Tx, utxoView *UtxoViewpoint,
 // the passed block using multiple goroutines.
 func checkBlockScripts(block *ltcutil.Block, utxoView *UtxoViewpoint,
 	scriptFlags txscript.ScriptFlags, sigCache *txscript.SigCache,
-	hashCache *txscript.HashCache) error {
+	scriptCache *txscript.ScriptCache, hashCache *txscript.HashCache) error {
+
+	defer startTraceRegion(traceRegionCheckBlockScripts).End()
 
 	// First determine if segwit is active according to the scriptFlags. If
 	// it isn't then we don't need to interact with the HashCache.
@@ -299,7 +332,8 @@ func checkBlockScripts(block *ltcutil.Block, utxoView *UtxoViewpoint,
 	}
 
 	// Validate all of the inputs.
-	validator := newTxValidator(utxoView, scriptFlags, sigCache, hashCache)
+	validator := newTxValidator(utxoView, scriptFlags, sigCache,
+		scriptCache, hashCache)
 	start := time.Now()
 	if err := validator.Validate(txValItems); err != nil {
 		return err
@@ -0,0 +1,240 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// signetBlockDataLen is the length of the block data committed to by
+	// the virtual transaction which pays to the signet challenge.  It
+	// consists of the block version, previous block hash, modified merkle
+	// root and timestamp.
+	signetBlockDataLen = 4 + chainhash.HashSize + chainhash.HashSize + 4
+
+	// signetScriptFlags are the script flags used to verify the solution to
+	// the signet challenge of a block as defined by BIP0325.
+	signetScriptFlags = txscript.ScriptBip16 |
+		txscript.ScriptVerifyWitness |
+		txscript.ScriptVerifyDERSignatures |
+		txscript.ScriptStrictMultiSig |
+		txscript.ScriptVerifyCheckLockTimeVerify |
+		txscript.ScriptVerifyCheckSequenceVerify
+)
+
+// SignetHeader is the prefix of the data push within the witness commitment
+// output of the coinbase which carries the solution to the signet challenge of
+// a block.
+var SignetHeader = []byte{0xec, 0xc7, 0xda, 0xa2}
+
+// witnessCommitmentIndex returns the index of the coinbase output which holds
+// the witness commitment of the passed block, or -1 when there is none.  When
+// there are multiple commitment outputs, the last one is used.
+func witnessCommitmentIndex(msgBlock *wire.MsgBlock) int {
+	if len(msgBlock.Transactions) == 0 {
+		return -1
+	}
+	coinbase := msgBlock.Transactions[0]
+	for i := len(coinbase.TxOut) - 1; i >= 0; i-- {
+		pkScript := coinbase.TxOut[i].PkScript
+		if len(pkScript) >= CoinbaseWitnessPkScriptLength &&
+			bytes.HasPrefix(pkScript, WitnessMagicBytes) {
+
+			return i
+		}
+	}
+	return -1
+}
+
+// appendSignetPush appends a push of the passed data to the script using the
+// smallest push opcode for its length.  Unlike the script builder, single byte
+// values are never converted to small integer opcodes so the pushes are
+// reproduced exactly as the reference implementation does when clearing the
+// signet solution.
+func appendSignetPush(script, data []byte) []byte {
+	dataLen := len(data)
+	switch {
+	case dataLen < txscript.OP_PUSHDATA1:
+		script = append(script, byte(dataLen))
+	case dataLen <= 0xff:
+		script = append(script, txscript.OP_PUSHDATA1, byte(dataLen))
+	case dataLen <= 0xffff:
+		var buf [2]byte
+		binary.LittleEndian.PutUint16(buf[:], uint16(dataLen))
+		script = append(script, txscript.OP_PUSHDATA2)
+		script = append(script, buf[:]...)
+	default:
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], uint32(dataLen))
+		script = append(script, txscript.OP_PUSHDATA4)
+		script = append(script, buf[:]...)
+	}
+	return append(script, data...)
+}
+
+// clearSignetSolution returns the passed witness commitment script with the
+// signet solution removed, leaving only the signet header in its data push,
+// along with the solution.  The first data push which starts with the signet
+// header and has data after it is the solution.  A nil solution is returned
+// when the script does not contain one.
+func clearSignetSolution(pkScript []byte) ([]byte, []byte) {
+	var replacement, solution []byte
+	var found bool
+	tokenizer := txscript.MakeScriptTokenizer(0, pkScript)
+	for tokenizer.Next() {
+		data := tokenizer.Data()
+		if len(data) == 0 {
+			replacement = append(replacement, tokenizer.Opcode())
+			continue
+		}
+		if !found && len(data) > len(SignetHeader) &&
+			bytes.HasPrefix(data, SignetHeader) {
+
+			solution = data[len(SignetHeader):]
+			data = SignetHeader
+			found = true
+		}
+		replacement = appendSignetPush(replacement, data)
+	}
+	if !found {
+		return pkScript, nil
+	}
+	return replacement, solution
+}
+
+// SignetTxs returns the virtual transactions used to verify the solution to
+// the passed signet challenge of a block as defined by BIP0325.  The first
+// transaction pays to the challenge and commits to the block with its signet
+// solution removed.  The second transaction spends the output of the first one
+// using the signature script and witness of the solution in the block, which
+// are empty when the block does not contain a solution.
+//
+// An error is returned when the block does not have a witness commitment or
+// the solution is malformed.
+func SignetTxs(msgBlock *wire.MsgBlock, challenge []byte) (*wire.MsgTx, *wire.MsgTx, error) {
+	commitmentIdx := witnessCommitmentIndex(msgBlock)
+	if commitmentIdx < 0 {
+		str := "block does not contain a witness commitment for the " +
+			"signet solution"
+		return nil, nil, ruleError(ErrBadSignetSolution, str)
+	}
+
+	// Remove the solution from the witness commitment of a copy of the
+	// coinbase and parse it into the signature script and witness which
+	// spend the output paying to the challenge.
+	modifiedCoinbase := msgBlock.Transactions[0].Copy()
+	pkScript, solution := clearSignetSolution(
+		modifiedCoinbase.TxOut[commitmentIdx].PkScript)
+	modifiedCoinbase.TxOut[commitmentIdx].PkScript = pkScript
+
+	toSign := wire.NewMsgTx(0)
+	toSign.AddTxIn(&wire.TxIn{})
+	toSign.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_RETURN}))
+	if solution != nil {
+		r := bytes.NewReader(solution)
+		sigScript, err := wire.ReadVarBytes(r, 0, wire.MaxBlockPayload,
+			"signet signature script")
+		if err != nil {
+			str := fmt.Sprintf("unable to read signet solution: %v", err)
+			return nil, nil, ruleError(ErrBadSignetSolution, str)
+		}
+		count, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			str := fmt.Sprintf("unable to read signet solution: %v", err)
+			return nil, nil, ruleError(ErrBadSignetSolution, str)
+		}
+		if count > uint64(len(solution)) {
+			str := fmt.Sprintf("signet solution witness has %d items "+
+				"which is more than its size", count)
+			return nil, nil, ruleError(ErrBadSignetSolution, str)
+		}
+		var witness wire.TxWitness
+		for i := uint64(0); i < count; i++ {
+			item, err := wire.ReadVarBytes(r, 0, wire.MaxBlockPayload,
+				"signet witness item")
+			if err != nil {
+				str := fmt.Sprintf("unable to read signet "+
+					"solution: %v", err)
+				return nil, nil, ruleError(ErrBadSignetSolution, str)
+			}
+			witness = append(witness, item)
+		}
+		if r.Len() != 0 {
+			str := fmt.Sprintf("signet solution has %d extra bytes",
+				r.Len())
+			return nil, nil, ruleError(ErrBadSignetSolution, str)
+		}
+		toSign.TxIn[0].SignatureScript = sigScript
+		toSign.TxIn[0].Witness = witness
+	}
+
+	// The transaction paying to the challenge commits to the block with
+	// the merkle root of the transactions including the modified coinbase.
+	transactions := make([]*ltcutil.Tx, 0, len(msgBlock.Transactions))
+	transactions = append(transactions, ltcutil.NewTx(modifiedCoinbase))
+	for _, tx := range msgBlock.Transactions[1:] {
+		transactions = append(transactions, ltcutil.NewTx(tx))
+	}
+	merkleRoot := CalcMerkleRoot(transactions, false)
+
+	var blockData [signetBlockDataLen]byte
+	header := &msgBlock.Header
+	binary.LittleEndian.PutUint32(blockData[0:4], uint32(header.Version))
+	copy(blockData[4:36], header.PrevBlock[:])
+	copy(blockData[36:68], merkleRoot[:])
+	binary.LittleEndian.PutUint32(blockData[68:72],
+		uint32(header.Timestamp.Unix()))
+
+	toSpend := wire.NewMsgTx(0)
+	toSpend.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		SignatureScript: appendSignetPush([]byte{txscript.OP_0},
+			blockData[:]),
+	})
+	toSpend.AddTxOut(wire.NewTxOut(0, challenge))
+
+	toSign.TxIn[0].PreviousOutPoint = wire.OutPoint{Hash: toSpend.TxHash()}
+	return toSpend, toSign, nil
+}
+
+// CheckSignetBlockSolution ensures the passed block contains a valid solution
+// to the signet challenge of the passed network as defined by BIP0325.  Blocks
+// of networks which are not signets and the genesis block always pass.
+func CheckSignetBlockSolution(block *ltcutil.Block, chainParams *chaincfg.Params) error {
+	challenge := chainParams.SignetChallenge
+	if challenge == nil || block.Hash().IsEqual(chainParams.GenesisHash) {
+		return nil
+	}
+
+	toSpend, toSign, err := SignetTxs(block.MsgBlock(), challenge)
+	if err != nil {
+		return err
+	}
+
+	prevOut := toSpend.TxOut[0]
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(prevOut.PkScript,
+		prevOut.Value)
+	sigHashes := txscript.NewTxSigHashes(toSign, prevOutFetcher)
+	vm, err := txscript.NewEngine(prevOut.PkScript, toSign, 0,
+		signetScriptFlags, nil, sigHashes, prevOut.Value, prevOutFetcher)
+	if err == nil {
+		err = vm.Execute()
+	}
+	if err != nil {
+		str := fmt.Sprintf("block %v does not solve the signet "+
+			"challenge: %v", block.Hash(), err)
+		return ruleError(ErrBadSignetSolution, str)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestClearSignetSolution ensures the signet solution is located within a
+// witness commitment script and replaced by the signet header.
+func TestClearSignetSolution(t *testing.T) {
+	commitment := append(append([]byte(nil), WitnessMagicBytes...),
+		make([]byte, 32)...)
+	withPush := func(pushes ...[]byte) []byte {
+		script := append([]byte(nil), commitment...)
+		for _, push := range pushes {
+			script = append(script, push...)
+		}
+		return script
+	}
+	header := append([]byte{0x04}, SignetHeader...)
+
+	tests := []struct {
+		name         string
+		pkScript     []byte
+		wantPkScript []byte
+		wantSolution []byte
+	}{{
+		name:         "no solution",
+		pkScript:     commitment,
+		wantPkScript: commitment,
+	}, {
+		name:         "header without solution",
+		pkScript:     withPush(header),
+		wantPkScript: withPush(header),
+	}, {
+		name: "solution",
+		pkScript: withPush([]byte{0x07, 0xec, 0xc7, 0xda, 0xa2, 0x01,
+			0x51, 0x00}),
+		wantPkScript: withPush(header),
+		wantSolution: []byte{0x01, 0x51, 0x00},
+	}, {
+		// Only the first solution is removed and single byte pushes
+		// are kept as they are.
+		name: "multiple pushes",
+		pkScript: withPush([]byte{0x01, 0x05},
+			[]byte{0x06, 0xec, 0xc7, 0xda, 0xa2, 0x00, 0x00},
+			[]byte{0x06, 0xec, 0xc7, 0xda, 0xa2, 0x01, 0x00}),
+		wantPkScript: withPush([]byte{0x01, 0x05}, header,
+			[]byte{0x06, 0xec, 0xc7, 0xda, 0xa2, 0x01, 0x00}),
+		wantSolution: []byte{0x00, 0x00},
+	}}
+
+	for _, test := range tests {
+		pkScript, solution := clearSignetSolution(test.pkScript)
+		if !bytes.Equal(pkScript, test.wantPkScript) {
+			t.Errorf("%s: got script %x, want %x", test.name,
+				pkScript, test.wantPkScript)
+		}
+		if !bytes.Equal(solution, test.wantSolution) {
+			t.Errorf("%s: got solution %x, want %x", test.name,
+				solution, test.wantSolution)
+		}
+	}
+}
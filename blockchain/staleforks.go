@@ -0,0 +1,173 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+)
+
+// staleForkPruneInterval is the number of blocks the main chain must advance
+// between searches of the block index for stale side chains.  Each search has
+// to visit every node of the block index, so it is not done for every block.
+const staleForkPruneInterval = 36
+
+// StaleForkStats describes the removal of stale side chains from the block
+// index.
+type StaleForkStats struct {
+	// Depth is the number of blocks the tip of a side chain must be behind
+	// the tip of the main chain for the side chain to be removed.  Zero
+	// means stale side chains are never removed.
+	Depth int32
+
+	// Quota is the number of bytes the deleted blocks of removed side
+	// chains may keep using in the block files before the space is
+	// reclaimed.
+	Quota uint64
+
+	// PrunedBlocks is the number of side chain blocks removed from the
+	// block index since the chain instance was created.
+	PrunedBlocks uint64
+
+	// LastPruneHeight is the height of the main chain the last time stale
+	// side chains were searched for, or zero if they never were.
+	LastPruneHeight int32
+}
+
+// dbRemoveBlockNode removes the block index entry of the passed node from the
+// block index bucket.
+func dbRemoveBlockNode(dbTx database.Tx, node *blockNode) error {
+	blockIndexBucket := dbTx.Metadata().Bucket(blockIndexBucketName)
+	key := blockIndexKey(&node.hash, uint32(node.height))
+	return blockIndexBucket.Delete(key)
+}
+
+// pruneStaleForks removes all side chains whose tips are at or below the passed
+// height from the block index, both in memory and in the database, along with
+// their blocks and returns the number of removed blocks.  Side chains which
+// share blocks with a side chain whose tip is above the height keep those
+// blocks.  Side chains with blocks known to be invalid are kept so the invalid
+// blocks are not downloaded and validated again, and a removed block which is
+// received again is accepted as new.
+//
+// The space of the deleted blocks is only released once it exceeds the quota,
+// since that rewrites the block files holding them.
+//
+// This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) pruneStaleForks(height int32) (int, error) {
+	// Find all of the side chain blocks along with their status and the
+	// blocks which have children so the tips of the side chains can be
+	// identified.
+	b.index.RLock()
+	var sideNodes []*blockNode
+	var sideStatus []blockStatus
+	parents := make(map[*blockNode]struct{})
+	for _, node := range b.index.index {
+		if b.bestChain.Contains(node) {
+			continue
+		}
+		sideNodes = append(sideNodes, node)
+		sideStatus = append(sideStatus, node.status)
+		parents[node.parent] = struct{}{}
+	}
+	b.index.RUnlock()
+
+	// Keep every block leading to a side chain tip which is recent enough
+	// or to a block known to be invalid, up to where it forks from the main
+	// chain or joins a side chain which is already kept.
+	keep := make(map[*blockNode]struct{})
+	for i, node := range sideNodes {
+		_, isParent := parents[node]
+		if !sideStatus[i].KnownInvalid() &&
+			(isParent || node.height <= height) {
+
+			continue
+		}
+		for n := node; n != nil && !b.bestChain.Contains(n); n = n.parent {
+			if _, ok := keep[n]; ok {
+				break
+			}
+			keep[n] = struct{}{}
+		}
+	}
+	var stale []*blockNode
+	var staleHashes []chainhash.Hash
+	for i, node := range sideNodes {
+		if _, ok := keep[node]; ok {
+			continue
+		}
+		stale = append(stale, node)
+		if sideStatus[i].HaveData() {
+			staleHashes = append(staleHashes, node.hash)
+		}
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	err := b.db.Update(func(dbTx database.Tx) error {
+		for _, node := range stale {
+			if err := dbRemoveBlockNode(dbTx, node); err != nil {
+				return err
+			}
+		}
+		if err := dbTx.DeleteBlocks(staleHashes); err != nil {
+			return err
+		}
+		return dbTx.ReclaimBlockSpace(b.staleForks.Quota)
+	})
+	if err != nil {
+		return 0, err
+	}
+	b.index.Lock()
+	for _, node := range stale {
+		delete(b.index.index, node.hash)
+		delete(b.index.dirty, node)
+	}
+	b.index.Unlock()
+
+	return len(stale), nil
+}
+
+// maybePruneStaleForks removes the side chains which have fallen the configured
+// depth behind the main chain from the block index when the main chain has
+// advanced far enough since the last search.  Nothing is removed while the
+// chain is not current since side chains seen during the initial download are
+// only removed once, after it finishes.
+//
+// This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) maybePruneStaleForks() {
+	depth := b.staleForks.Depth
+	tipHeight := b.bestChain.Tip().height
+	if depth <= 0 || tipHeight-depth <= 0 ||
+		tipHeight < b.staleForks.LastPruneHeight+staleForkPruneInterval ||
+		!b.isCurrent() {
+
+		return
+	}
+
+	numPruned, err := b.pruneStaleForks(tipHeight - depth)
+	if err != nil {
+		log.Warnf("Unable to prune stale side chains: %v", err)
+		return
+	}
+	b.staleForks.PrunedBlocks += uint64(numPruned)
+	b.staleForks.LastPruneHeight = tipHeight
+	if numPruned > 0 {
+		log.Infof("Pruned %d stale side chain blocks more than %d blocks "+
+			"behind the main chain", numPruned, depth)
+	}
+}
+
+// StaleForkStats returns the depth side chains are removed at along with the
+// number of side chain blocks removed so far.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) StaleForkStats() StaleForkStats {
+	b.chainLock.RLock()
+	stats := b.staleForks
+	b.chainLock.RUnlock()
+	return stats
+}
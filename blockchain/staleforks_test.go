@@ -0,0 +1,208 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+)
+
+// TestPruneStaleForks ensures only side chains whose tips are at or below the
+// prune height are removed from the block index and the database along with
+// their blocks, and that blocks shared with side chains which are kept and side
+// chains with blocks known to be invalid are not.
+func TestPruneStaleForks(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("prunestaleforks",
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+
+	// Build a main chain up to height 8.
+	genesis := chain.BestSnapshot()
+	timestamp := chaincfg.RegressionNetParams.GenesisBlock.Header.Timestamp
+	mainHashes := []chainhash.Hash{genesis.Hash}
+	for height := int32(1); height <= 8; height++ {
+		timestamp = timestamp.Add(time.Minute)
+		block := solveTestBlock(t, &mainHashes[height-1], height, timestamp)
+		_, _, err := chain.ProcessBlock(block, BFNone)
+		if err != nil {
+			t.Fatalf("ProcessBlock: unexpected error: %v", err)
+		}
+		mainHashes = append(mainHashes, *block.Hash())
+	}
+
+	// addSideChain adds headers building on the passed parent up to the
+	// passed tip height and returns their hashes.
+	addSideChain := func(parent chainhash.Hash, parentHeight,
+		tipHeight int32) []chainhash.Hash {
+
+		var hashes []chainhash.Hash
+		for height := parentHeight + 1; height <= tipHeight; height++ {
+			// Use a different time than the main chain so the
+			// blocks differ.
+			timestamp = timestamp.Add(time.Second)
+			block := solveTestBlock(t, &parent, height, timestamp)
+			err := chain.ProcessBlockHeader(&block.MsgBlock().Header,
+				BFNone)
+			if err != nil {
+				t.Fatalf("ProcessBlockHeader: unexpected error: %v",
+					err)
+			}
+			parent = *block.Hash()
+			hashes = append(hashes, parent)
+		}
+		return hashes
+	}
+
+	// Side chain a forks at height 2 and ends at height 4, side chain c
+	// builds on its first block up to height 6, and side chain b forks at
+	// height 5 and ends at height 7.
+	forkA := addSideChain(mainHashes[2], 2, 4)
+	forkC := addSideChain(forkA[0], 3, 6)
+	forkB := addSideChain(mainHashes[5], 5, 7)
+
+	// inIndex returns whether the block is in the block index both in
+	// memory and in the database.
+	inIndex := func(hash chainhash.Hash) bool {
+		node := chain.index.LookupNode(&hash)
+		var inDB bool
+		err := chain.db.View(func(dbTx database.Tx) error {
+			bucket := dbTx.Metadata().Bucket(blockIndexBucketName)
+			cursor := bucket.Cursor()
+			for ok := cursor.First(); ok; ok = cursor.Next() {
+				if bytes.Equal(cursor.Key()[4:], hash[:]) {
+					inDB = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unable to read block index: %v", err)
+		}
+		if (node != nil) != inDB {
+			t.Fatalf("block %v is in the memory index %v but in the "+
+				"database index %v", hash, node != nil, inDB)
+		}
+		return inDB
+	}
+	if err := chain.index.flushToDB(); err != nil {
+		t.Fatalf("flushToDB: unexpected error: %v", err)
+	}
+
+	// Pruning at height 5 removes the end of side chain a, but keeps its
+	// first block which side chain c builds on.
+	chain.chainLock.Lock()
+	numPruned, err := chain.pruneStaleForks(5)
+	chain.chainLock.Unlock()
+	if err != nil {
+		t.Fatalf("pruneStaleForks: unexpected error: %v", err)
+	}
+	if numPruned != 1 {
+		t.Fatalf("pruneStaleForks: pruned %d blocks, want 1", numPruned)
+	}
+	if inIndex(forkA[1]) {
+		t.Fatalf("stale block %v was not pruned", forkA[1])
+	}
+	for _, hash := range append(append([]chainhash.Hash{forkA[0]},
+		forkB...), forkC...) {
+
+		if !inIndex(hash) {
+			t.Fatalf("block %v was pruned", hash)
+		}
+	}
+
+	// Side chain d forks at height 3 and ends at height 5 with the full
+	// blocks stored, and side chain e forks at height 4 and ends at height
+	// 6 with a block known to be invalid.
+	var forkD []chainhash.Hash
+	parent := mainHashes[3]
+	for height := int32(4); height <= 5; height++ {
+		timestamp = timestamp.Add(time.Second)
+		block := solveTestBlock(t, &parent, height, timestamp)
+		_, _, err := chain.ProcessBlock(block, BFNone)
+		if err != nil {
+			t.Fatalf("ProcessBlock: unexpected error: %v", err)
+		}
+		parent = *block.Hash()
+		forkD = append(forkD, parent)
+	}
+	forkE := addSideChain(mainHashes[4], 4, 6)
+	invalid := chain.index.LookupNode(&forkE[len(forkE)-1])
+	chain.index.SetStatusFlags(invalid, statusValidateFailed)
+	if err := chain.index.flushToDB(); err != nil {
+		t.Fatalf("flushToDB: unexpected error: %v", err)
+	}
+
+	// haveBlock returns whether the block data is stored.
+	haveBlock := func(hash chainhash.Hash) bool {
+		var exists bool
+		err := chain.db.View(func(dbTx database.Tx) error {
+			var err error
+			exists, err = dbTx.HasBlock(&hash)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("HasBlock: unexpected error: %v", err)
+		}
+		return exists
+	}
+	for _, hash := range forkD {
+		if !haveBlock(hash) {
+			t.Fatalf("side chain block %v is not stored", hash)
+		}
+	}
+
+	// Pruning at height 6 removes side chains a, c and d along with the
+	// blocks of d, but not b nor the invalid side chain e.
+	chain.chainLock.Lock()
+	numPruned, err = chain.pruneStaleForks(6)
+	chain.chainLock.Unlock()
+	if err != nil {
+		t.Fatalf("pruneStaleForks: unexpected error: %v", err)
+	}
+	if numPruned != 6 {
+		t.Fatalf("pruneStaleForks: pruned %d blocks, want 6", numPruned)
+	}
+	for _, hash := range append(append([]chainhash.Hash{forkA[0]},
+		forkC...), forkD...) {
+
+		if inIndex(hash) {
+			t.Fatalf("stale block %v was not pruned", hash)
+		}
+	}
+	for _, hash := range forkD {
+		if haveBlock(hash) {
+			t.Fatalf("stale block %v is still stored", hash)
+		}
+	}
+	for _, hash := range append(forkB, forkE...) {
+		if !inIndex(hash) {
+			t.Fatalf("block %v was pruned", hash)
+		}
+	}
+	if !chain.index.NodeStatus(invalid).KnownInvalid() {
+		t.Fatalf("invalid block %v is no longer known to be invalid",
+			invalid.hash)
+	}
+
+	// The main chain is never pruned and its tip is the only tip left
+	// besides side chains b and e.
+	for _, hash := range mainHashes {
+		if !inIndex(hash) {
+			t.Fatalf("main chain block %v was pruned", hash)
+		}
+	}
+	tips := chain.ChainTips()
+	if len(tips) != 3 {
+		t.Fatalf("ChainTips: unexpected tips %v", tips)
+	}
+}
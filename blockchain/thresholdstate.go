@@ -6,6 +6,7 @@ package blockchain
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
@@ -105,22 +106,33 @@ type thresholdConditionChecker interface {
 }
 
 // thresholdStateCache provides a type to cache the threshold states of each
-// threshold window for a set of IDs.
+// threshold window for a set of IDs.  It has its own lock so threshold states
+// can be calculated without the chain lock.  Concurrent calculations of the
+// same state store the same result, so they don't need to be serialized.
 type thresholdStateCache struct {
+	mtx     sync.RWMutex
 	entries map[chainhash.Hash]ThresholdState
 }
 
 // Lookup returns the threshold state associated with the given hash along with
 // a boolean that indicates whether or not it is valid.
+//
+// This function is safe for concurrent access.
 func (c *thresholdStateCache) Lookup(hash *chainhash.Hash) (ThresholdState, bool) {
+	c.mtx.RLock()
 	state, ok := c.entries[*hash]
+	c.mtx.RUnlock()
 	return state, ok
 }
 
 // Update updates the cache to contain the provided hash to threshold state
 // mapping.
+//
+// This function is safe for concurrent access.
 func (c *thresholdStateCache) Update(hash *chainhash.Hash, state ThresholdState) {
+	c.mtx.Lock()
 	c.entries[*hash] = state
+	c.mtx.Unlock()
 }
 
 // newThresholdCaches returns a new array of caches to be used when calculating
@@ -360,11 +372,42 @@ func (b *BlockChain) thresholdState(prevNode *blockNode, checker thresholdCondit
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) ThresholdState(deploymentID uint32) (ThresholdState, error) {
-	b.chainLock.Lock()
-	state, err := b.deploymentState(b.bestChain.Tip(), deploymentID)
-	b.chainLock.Unlock()
+	return b.deploymentState(b.bestChain.Tip(), deploymentID)
+}
+
+// ThresholdStateSince returns the current rule change threshold state of the
+// given deployment ID for the block AFTER the end of the current best chain
+// along with the height of the first block for which the deployment has been in
+// that state.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) ThresholdStateSince(deploymentID uint32) (ThresholdState, int32, error) {
+	// The ancestors of the tip are used rather than the best chain view so
+	// the states are consistent with it when the best chain changes.
+	tip := b.bestChain.Tip()
+	state, err := b.deploymentState(tip, deploymentID)
+	if err != nil {
+		return state, 0, err
+	}
 
-	return state, err
+	// The state only changes at the start of a confirmation window, so
+	// step back one window at a time until the state differs.
+	window := int32(b.chainParams.MinerConfirmationWindow)
+	nextHeight := tip.height + 1
+	since := nextHeight - nextHeight%window
+	for since > 0 {
+		prevNode := tip.Ancestor(since - window - 1)
+		prevState, err := b.deploymentState(prevNode, deploymentID)
+		if err != nil {
+			return state, 0, err
+		}
+		if prevState != state {
+			break
+		}
+		since -= window
+	}
+
+	return state, since, nil
 }
 
 // IsDeploymentActive returns true if the target deploymentID is active, and
@@ -372,9 +415,7 @@ func (b *BlockChain) ThresholdState(deploymentID uint32) (ThresholdState, error)
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) IsDeploymentActive(deploymentID uint32) (bool, error) {
-	b.chainLock.Lock()
 	state, err := b.deploymentState(b.bestChain.Tip(), deploymentID)
-	b.chainLock.Unlock()
 	if err != nil {
 		return false, err
 	}
@@ -406,7 +447,7 @@ func (b *BlockChain) deploymentState(prevNode *blockNode, deploymentID uint32) (
 
 // initThresholdCaches initializes the threshold state caches for each warning
 // bit and defined deployment and provides warnings if the chain is current per
-// the warnUnknownRuleActivations function.
+// the warnUnknownRuleActivations and warnUnknownVersions functions.
 func (b *BlockChain) initThresholdCaches() error {
 	// Initialize the warning and deployment caches by calculating the
 	// threshold state for each of them.  This will ensure the caches are
@@ -440,6 +481,10 @@ func (b *BlockChain) initThresholdCaches() error {
 		if err := b.warnUnknownRuleActivations(bestNode); err != nil {
 			return err
 		}
+
+		// Warn if the majority of the recent blocks signal unknown
+		// version bits.
+		b.warnUnknownVersions(bestNode)
 	}
 
 	return nil
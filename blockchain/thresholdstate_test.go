@@ -6,7 +6,9 @@ package blockchain
 
 import (
 	"testing"
+	"time"
 
+	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 )
 
@@ -316,3 +318,55 @@ func TestThresholdStateTransition(t *testing.T) {
 		}
 	}
 }
+
+// TestThresholdStateSince ensures the height a deployment entered its current
+// state is reported as the start of the confirmation window of the change.
+func TestThresholdStateSince(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	chain := newFakeChain(&params)
+	window := int32(params.MinerConfirmationWindow)
+	deployment := &params.Deployments[chaincfg.DeploymentTestDummy]
+
+	// Extend the chain through four confirmation windows where every
+	// block of the second one signals for the deployment.
+	nodes := make([]*blockNode, 4*window)
+	tip := chain.bestChain.Genesis()
+	for i := range nodes {
+		version := int32(vbTopBits)
+		height := tip.height + 1
+		if height >= window && height < 2*window {
+			version |= 1 << deployment.BitNumber
+		}
+		nodes[i] = newFakeNode(tip, version, params.PowLimitBits,
+			time.Unix(tip.Timestamp()+1, 0))
+		tip = nodes[i]
+	}
+
+	tests := []struct {
+		height int32
+		state  ThresholdState
+		since  int32
+	}{
+		{height: 100, state: ThresholdDefined, since: 0},
+		{height: window - 1, state: ThresholdStarted, since: window},
+		{height: 2*window - 2, state: ThresholdStarted, since: window},
+		{height: 2*window - 1, state: ThresholdLockedIn, since: 2 * window},
+		{height: 3*window - 2, state: ThresholdLockedIn, since: 2 * window},
+		{height: 3*window - 1, state: ThresholdActive, since: 3 * window},
+		{height: 4 * window, state: ThresholdActive, since: 3 * window},
+	}
+	for i, test := range tests {
+		chain.bestChain.SetTip(nodes[test.height-1])
+		state, since, err := chain.ThresholdStateSince(
+			chaincfg.DeploymentTestDummy)
+		if err != nil {
+			t.Fatalf("ThresholdStateSince #%d: unexpected error: %v",
+				i, err)
+		}
+		if state != test.state || since != test.since {
+			t.Errorf("ThresholdStateSince #%d (height %d): got %v "+
+				"since %d, want %v since %d", i, test.height, state,
+				since, test.state, test.since)
+		}
+	}
+}
@@ -5,7 +5,10 @@
 package blockchain
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"sync"
 
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/database"
@@ -516,18 +519,89 @@ func (view *UtxoViewpoint) fetchUtxosMain(db database.DB, outpoints []wire.OutPo
 	// will result in nil entries in the view.  This is intentionally done
 	// so other code can use the presence of an entry in the store as a way
 	// to unnecessarily avoid attempting to reload it from the database.
-	return db.View(func(dbTx database.Tx) error {
-		for i := range outpoints {
-			entry, err := dbFetchUtxoEntry(dbTx, outpoints[i])
-			if err != nil {
-				return err
+	entries, err := fetchUtxoEntries(db, outpoints)
+	if err != nil {
+		return err
+	}
+	for i := range outpoints {
+		view.entries[outpoints[i]] = entries[i]
+	}
+
+	return nil
+}
+
+const (
+	// maxUtxoFetchWorkers is the maximum number of workers which load a
+	// set of outputs from the database concurrently.  The lookups mostly
+	// wait on storage, so more workers than processors are useful.
+	maxUtxoFetchWorkers = 8
+
+	// minUtxosPerFetchWorker is the minimum number of outputs loaded by
+	// each of the workers.  Fewer outputs are not worth the cost of an
+	// additional database transaction.
+	minUtxosPerFetchWorker = 32
+)
+
+// fetchUtxoEntries loads the passed outputs from the utxo set in the database
+// and returns them in the same order, with nil entries for the outputs which
+// do not exist.
+//
+// Large sets, such as the outputs spent by a full block, are split between up
+// to maxUtxoFetchWorkers workers which each load their share in a separate
+// database transaction.  This hides the latency of the point lookups, which
+// are otherwise made one after the other, as well as spreading the cost of
+// decompressing the entries.
+func fetchUtxoEntries(db database.DB, outpoints []wire.OutPoint) ([]*UtxoEntry, error) {
+	entries := make([]*UtxoEntry, len(outpoints))
+	fetchRange := func(start, end int) error {
+		return db.View(func(dbTx database.Tx) error {
+			for i := start; i < end; i++ {
+				entry, err := dbFetchUtxoEntry(dbTx, outpoints[i])
+				if err != nil {
+					return err
+				}
+				entries[i] = entry
 			}
+			return nil
+		})
+	}
 
-			view.entries[outpoints[i]] = entry
+	numWorkers := len(outpoints) / minUtxosPerFetchWorker
+	if numWorkers > maxUtxoFetchWorkers {
+		numWorkers = maxUtxoFetchWorkers
+	}
+	if numWorkers <= 1 {
+		if err := fetchRange(0, len(outpoints)); err != nil {
+			return nil, err
 		}
+		return entries, nil
+	}
 
-		return nil
-	})
+	// Split the outputs into contiguous ranges of roughly equal size, one
+	// per worker.
+	var wg sync.WaitGroup
+	errs := make([]error, numWorkers)
+	rangeSize := (len(outpoints) + numWorkers - 1) / numWorkers
+	for i := 0; i < numWorkers; i++ {
+		start := i * rangeSize
+		end := start + rangeSize
+		if end > len(outpoints) {
+			end = len(outpoints)
+		}
+		wg.Add(1)
+		go func(i, start, end int) {
+			defer wg.Done()
+			errs[i] = fetchRange(start, end)
+		}(i, start, end)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
 }
 
 // fetchUtxos loads the unspent transaction outputs for the provided set of
@@ -677,3 +751,159 @@ func (b *BlockChain) FetchUtxoEntry(outpoint wire.OutPoint) (*UtxoEntry, error)
 
 	return entry, nil
 }
+
+// utxoBogoSize is the fixed part of the size of an unspent transaction output
+// counted by the bogo size of the utxo set, which is made of the transaction
+// hash, the output index, the height and coinbase flag, the amount and the
+// length of the public key script.
+const utxoBogoSize = chainhash.HashSize + 4 + 4 + 8 + 2
+
+// UtxoSetStats describes the unspent transaction output set as of a block of
+// the main chain.
+type UtxoSetStats struct {
+	// Hash and Height identify the block the set is as of.
+	Hash   chainhash.Hash
+	Height int32
+
+	// Transactions is the number of transactions with unspent outputs and
+	// TxOuts is the number of unspent outputs.
+	Transactions int64
+	TxOuts       int64
+
+	// BogoSize is a database-independent metric of the size of the set,
+	// while DiskSize is the size of its serialized entries.
+	BogoSize int64
+	DiskSize int64
+
+	// SerializedHash commits to the serialized entries of the set in the
+	// order they are stored.
+	SerializedHash chainhash.Hash
+
+	// TotalAmount is the sum of the unspent outputs, which includes the
+	// amount pegged into the MWEB held by the HogAddr output, also
+	// reported as MwebPeggedAmount.
+	TotalAmount      int64
+	MwebPeggedAmount int64
+}
+
+// FetchUtxoSetStats scans the whole unspent transaction output set and returns
+// its statistics.  The set and the block it is as of are read from the same
+// database snapshot, so blocks may be connected while the set is scanned.
+//
+// The interrupt channel may be closed to stop the scan early, in which case an
+// error is returned.  The optional progress function is called as the scan
+// advances through the set.  Since the set is ordered by transaction hash, the
+// progress is estimated from the first two bytes of the hash being scanned out
+// of a total of 65536.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) FetchUtxoSetStats(interrupt <-chan struct{},
+	progress ProgressFunc) (*UtxoSetStats, error) {
+
+	const progressTotal = 1 << 16
+
+	var stats UtxoSetStats
+	hasher := sha256.New()
+	err := b.db.View(func(dbTx database.Tx) error {
+		meta := dbTx.Metadata()
+		state, err := deserializeBestChainState(meta.Get(chainStateKeyName))
+		if err != nil {
+			return err
+		}
+		stats.Hash = state.hash
+		stats.Height = int32(state.height)
+
+		var txHash []byte
+		cursor := meta.Bucket(utxoSetBucketName).Cursor()
+		for ok := cursor.First(); ok; ok = cursor.Next() {
+			key, serialized := cursor.Key(), cursor.Value()
+			if len(key) <= chainhash.HashSize {
+				return AssertError(fmt.Sprintf("invalid utxo "+
+					"key %x", key))
+			}
+			entry, err := deserializeUtxoEntry(serialized)
+			if err != nil {
+				return err
+			}
+
+			// The keys start with the transaction hash, so the
+			// outputs of a transaction are adjacent.
+			if !bytes.Equal(txHash, key[:chainhash.HashSize]) {
+				if interruptRequested(interrupt) {
+					return errInterruptRequested
+				}
+				if progress != nil && (txHash == nil ||
+					txHash[0] != key[0] || txHash[1] != key[1]) {
+
+					progress(int64(key[0])<<8|int64(key[1]),
+						progressTotal)
+				}
+
+				txHash = append(txHash[:0], key[:chainhash.HashSize]...)
+				stats.Transactions++
+			}
+			stats.TxOuts++
+			stats.BogoSize += utxoBogoSize + int64(len(entry.PkScript()))
+			stats.DiskSize += int64(len(key) + len(serialized))
+			stats.TotalAmount += entry.Amount()
+			if txscript.GetScriptClass(entry.PkScript()) ==
+				txscript.WitnessMwebHogAddrTy {
+
+				stats.MwebPeggedAmount += entry.Amount()
+			}
+			hasher.Write(key)
+			hasher.Write(serialized)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats.SerializedHash = chainhash.HashH(hasher.Sum(nil))
+	if progress != nil {
+		progress(progressTotal, progressTotal)
+	}
+	return &stats, nil
+}
+
+// ForEachUTXO calls the passed function with every unspent transaction output
+// of the main chain, ordered by transaction hash and then output index.  The
+// set is read from a single database snapshot, so the outputs are consistently
+// the ones of the main chain as of the time of the call, and blocks may be
+// connected while the set is iterated.
+//
+// The iteration stops at the first error returned by the passed function,
+// which is then returned.  The entries passed to it are not shared with the
+// chain, so they may be retained.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) ForEachUTXO(fn func(outpoint wire.OutPoint, entry *UtxoEntry) error) error {
+	return b.db.View(func(dbTx database.Tx) error {
+		cursor := dbTx.Metadata().Bucket(utxoSetBucketName).Cursor()
+		for ok := cursor.First(); ok; ok = cursor.Next() {
+			key := cursor.Key()
+			if len(key) <= chainhash.HashSize {
+				return AssertError(fmt.Sprintf("invalid utxo "+
+					"key %x", key))
+			}
+			var outpoint wire.OutPoint
+			copy(outpoint.Hash[:], key[:chainhash.HashSize])
+			index, bytesRead := deserializeVLQ(key[chainhash.HashSize:])
+			if bytesRead != len(key)-chainhash.HashSize {
+				return AssertError(fmt.Sprintf("invalid utxo "+
+					"key %x", key))
+			}
+			outpoint.Index = uint32(index)
+
+			entry, err := deserializeUtxoEntry(cursor.Value())
+			if err != nil {
+				return err
+			}
+			if err := fn(outpoint, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
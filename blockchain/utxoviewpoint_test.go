@@ -0,0 +1,178 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestFetchUtxoEntries ensures sets of outputs are loaded from the database in
+// order, with nil entries for missing outputs, both when they are loaded by a
+// single worker and when they are split between several.
+func TestFetchUtxoEntries(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("fetchutxoentries",
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+
+	// Store outputs in the utxo set, leaving every seventh one out so it
+	// is missing.
+	const numOutpoints = 1000
+	outpoints := make([]wire.OutPoint, 0, numOutpoints)
+	view := NewUtxoViewpoint()
+	for i := 0; i < numOutpoints; i++ {
+		outpoint := wire.OutPoint{
+			Hash:  chainhash.Hash{byte(i), byte(i >> 8)},
+			Index: uint32(i % 3),
+		}
+		outpoints = append(outpoints, outpoint)
+		if i%7 == 0 {
+			continue
+		}
+		pkScript := []byte{txscript.OP_TRUE, byte(i), byte(i >> 8)}
+		view.addTxOut(outpoint, wire.NewTxOut(int64(i+1), pkScript),
+			i%5 == 0, int32(i))
+	}
+	err = chain.db.Update(func(dbTx database.Tx) error {
+		return dbPutUtxoView(dbTx, view)
+	})
+	if err != nil {
+		t.Fatalf("unable to store utxos: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		outpoints []wire.OutPoint
+	}{
+		{"single worker", outpoints[:minUtxosPerFetchWorker+1]},
+		{"several workers", outpoints},
+		{"uneven ranges", outpoints[3 : numOutpoints-10]},
+	}
+	for _, test := range tests {
+		entries, err := fetchUtxoEntries(chain.db, test.outpoints)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if len(entries) != len(test.outpoints) {
+			t.Fatalf("%s: got %d entries, want %d", test.name,
+				len(entries), len(test.outpoints))
+		}
+		for i, outpoint := range test.outpoints {
+			want := view.LookupEntry(outpoint)
+			got := entries[i]
+			if want == nil {
+				if got != nil {
+					t.Fatalf("%s: got entry for missing "+
+						"output %v", test.name, outpoint)
+				}
+				continue
+			}
+			if got == nil || got.Amount() != want.Amount() ||
+				!bytes.Equal(got.PkScript(), want.PkScript()) ||
+				got.BlockHeight() != want.BlockHeight() ||
+				got.IsCoinBase() != want.IsCoinBase() {
+
+				t.Fatalf("%s: got entry %+v for output %v, "+
+					"want %+v", test.name, got, outpoint, want)
+			}
+		}
+	}
+}
+
+// TestForEachUTXO ensures every unspent output is iterated in order from a
+// snapshot of the set which is unaffected by blocks connected meanwhile.
+func TestForEachUTXO(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("foreachutxo",
+		&chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+
+	const numOutpoints = 100
+	view := NewUtxoViewpoint()
+	for i := 0; i < numOutpoints; i++ {
+		outpoint := wire.OutPoint{
+			Hash:  chainhash.Hash{byte(i * 37)},
+			Index: uint32(i%2) * 300,
+		}
+		pkScript := []byte{txscript.OP_DATA_1, byte(i)}
+		view.addTxOut(outpoint, wire.NewTxOut(int64(i+1), pkScript),
+			false, 1)
+	}
+	err = chain.db.Update(func(dbTx database.Tx) error {
+		return dbPutUtxoView(dbTx, view)
+	})
+	if err != nil {
+		t.Fatalf("unable to store utxos: %v", err)
+	}
+
+	// Connect a block while iterating, whose coinbase output must not be
+	// part of the iterated set.
+	genesis := chain.BestSnapshot()
+	timestamp := time.Unix(chaincfg.RegressionNetParams.GenesisBlock.
+		Header.Timestamp.Unix()+60, 0)
+	block := solveTestBlock(t, &genesis.Hash, 1, timestamp)
+	var prev *wire.OutPoint
+	var numIterated int
+	err = chain.ForEachUTXO(func(outpoint wire.OutPoint, entry *UtxoEntry) error {
+		if numIterated == 0 {
+			_, _, err := chain.ProcessBlock(block, BFNone)
+			if err != nil {
+				t.Fatalf("ProcessBlock: unexpected error: %v", err)
+			}
+		}
+		numIterated++
+
+		if prev != nil && (bytes.Compare(prev.Hash[:], outpoint.Hash[:]) > 0 ||
+			prev.Hash == outpoint.Hash && prev.Index >= outpoint.Index) {
+
+			t.Fatalf("output %v iterated after %v", outpoint, prev)
+		}
+		prev = &outpoint
+
+		want := view.LookupEntry(outpoint)
+		if want == nil || entry.Amount() != want.Amount() ||
+			!bytes.Equal(entry.PkScript(), want.PkScript()) {
+
+			t.Fatalf("got entry %+v for output %v, want %+v", entry,
+				outpoint, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachUTXO: unexpected error: %v", err)
+	}
+	if numIterated != numOutpoints {
+		t.Fatalf("iterated %d outputs, want %d", numIterated,
+			numOutpoints)
+	}
+	if best := chain.BestSnapshot(); best.Hash != *block.Hash() {
+		t.Fatalf("block %v was not connected", block.Hash())
+	}
+
+	// The iteration must stop at the first error of the function.
+	errStop := errors.New("stop")
+	numIterated = 0
+	err = chain.ForEachUTXO(func(wire.OutPoint, *UtxoEntry) error {
+		numIterated++
+		return errStop
+	})
+	if err != errStop || numIterated != 1 {
+		t.Fatalf("ForEachUTXO: got error %v after %d outputs, want %v "+
+			"after 1", err, numIterated, errStop)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/ltcsuite/ltcd/chaincfg"
@@ -170,13 +171,7 @@ func IsFinalizedTransaction(tx *ltcutil.Tx, blockHeight int32, blockTime time.Ti
 	// which the transaction is finalized or a timestamp depending on if the
 	// value is before the txscript.LockTimeThreshold.  When it is under the
 	// threshold it is a block height.
-	blockTimeOrHeight := int64(0)
-	if lockTime < txscript.LockTimeThreshold {
-		blockTimeOrHeight = int64(blockHeight)
-	} else {
-		blockTimeOrHeight = blockTime.Unix()
-	}
-	if int64(lockTime) < blockTimeOrHeight {
+	if LockTimeActive(lockTime, blockHeight, blockTime) {
 		return true
 	}
 
@@ -216,7 +211,25 @@ func isBIP0030Node(node *blockNode) bool {
 //
 // At the target block generation rate for the main network, this is
 // approximately every 4 years.
+//
+// Networks with other emission curves override the halving with either the
+// CalcSubsidy callback or the SubsidySchedule steps of their parameters, in
+// that order of precedence.
 func CalcBlockSubsidy(height int32, chainParams *chaincfg.Params) int64 {
+	if chainParams.CalcSubsidy != nil {
+		return chainParams.CalcSubsidy(height)
+	}
+	if schedule := chainParams.SubsidySchedule; len(schedule) > 0 {
+		// Find the last step which applies to the height.
+		idx := sort.Search(len(schedule), func(i int) bool {
+			return schedule[i].Height > height
+		})
+		if idx == 0 {
+			return 0
+		}
+		return schedule[idx-1].Subsidy
+	}
+
 	if chainParams.SubsidyReductionInterval == 0 {
 		return baseSubsidy
 	}
@@ -380,6 +393,15 @@ func CheckProofOfWork(block *ltcutil.Block, powLimit *big.Int) error {
 	return checkProofOfWork(&block.MsgBlock().Header, powLimit, BFNone)
 }
 
+// CheckHeaderProofOfWork ensures the block header bits which indicate the
+// target difficulty is in min/max range and that the header hash is less than
+// the target difficulty as claimed.  It allows callers that only have headers,
+// such as a headers-first sync, to cheaply reject garbage headers before
+// committing any resources to them.
+func CheckHeaderProofOfWork(header *wire.BlockHeader, powLimit *big.Int) error {
+	return checkProofOfWork(header, powLimit, BFNone)
+}
+
 // CountSigOps returns the number of signature operations for all transaction
 // input and output scripts in the provided transaction.  This uses the
 // quicker, but imprecise, signature operation counting mechanism from
@@ -501,12 +523,15 @@ func CheckBlockHeaderSanity(header *wire.BlockHeader, powLimit *big.Int,
 // checkBlockSanity performs some preliminary checks on a block to ensure it is
 // sane before continuing with block processing.  These checks are context free.
 //
+// The size and signature operation limits are those of the passed network.
+//
 // The flags do not modify the behavior of this function directly, however they
 // are needed to pass along to checkBlockHeaderSanity.
-func checkBlockSanity(block *ltcutil.Block, powLimit *big.Int, timeSource MedianTimeSource, flags BehaviorFlags) error {
+func checkBlockSanity(block *ltcutil.Block, chainParams *chaincfg.Params, timeSource MedianTimeSource, flags BehaviorFlags) error {
 	msgBlock := block.MsgBlock()
 	header := &msgBlock.Header
-	err := CheckBlockHeaderSanity(header, powLimit, timeSource, flags)
+	err := CheckBlockHeaderSanity(header, chainParams.PowLimit, timeSource,
+		flags)
 	if err != nil {
 		return err
 	}
@@ -520,18 +545,19 @@ func checkBlockSanity(block *ltcutil.Block, powLimit *big.Int, timeSource Median
 
 	// A block must not have more transactions than the max block payload or
 	// else it is certainly over the weight limit.
-	if numTx > MaxBlockBaseSize {
+	maxBaseSize := BlockBaseSizeLimit(chainParams)
+	if int64(numTx) > maxBaseSize {
 		str := fmt.Sprintf("block contains too many transactions - "+
-			"got %d, max %d", numTx, MaxBlockBaseSize)
+			"got %d, max %d", numTx, maxBaseSize)
 		return ruleError(ErrBlockTooBig, str)
 	}
 
 	// A block must not exceed the maximum allowed block payload when
 	// serialized.
 	serializedSize := msgBlock.SerializeSizeStripped()
-	if serializedSize > MaxBlockBaseSize {
+	if int64(serializedSize) > maxBaseSize {
 		str := fmt.Sprintf("serialized block is too big - got %d, "+
-			"max %d", serializedSize, MaxBlockBaseSize)
+			"max %d", serializedSize, maxBaseSize)
 		return ruleError(ErrBlockTooBig, str)
 	}
 
@@ -590,16 +616,17 @@ func checkBlockSanity(block *ltcutil.Block, powLimit *big.Int, timeSource Median
 
 	// The number of signature operations must be less than the maximum
 	// allowed per block.
+	maxSigOpsCost := BlockSigOpsCostLimit(chainParams)
 	totalSigOps := 0
 	for _, tx := range transactions {
 		// We could potentially overflow the accumulator so check for
 		// overflow.
 		lastSigOps := totalSigOps
 		totalSigOps += (CountSigOps(tx) * WitnessScaleFactor)
-		if totalSigOps < lastSigOps || totalSigOps > MaxBlockSigOpsCost {
+		if totalSigOps < lastSigOps || int64(totalSigOps) > maxSigOpsCost {
 			str := fmt.Sprintf("block contains too many signature "+
 				"operations - got %v, max %v", totalSigOps,
-				MaxBlockSigOpsCost)
+				maxSigOpsCost)
 			return ruleError(ErrTooManySigOps, str)
 		}
 	}
@@ -609,8 +636,104 @@ func checkBlockSanity(block *ltcutil.Block, powLimit *big.Int, timeSource Median
 
 // CheckBlockSanity performs some preliminary checks on a block to ensure it is
 // sane before continuing with block processing.  These checks are context free.
+//
+// The default size and signature operation limits are enforced.
 func CheckBlockSanity(block *ltcutil.Block, powLimit *big.Int, timeSource MedianTimeSource) error {
-	return checkBlockSanity(block, powLimit, timeSource, BFNone)
+	return checkBlockSanity(block, &chaincfg.Params{PowLimit: powLimit},
+		timeSource, BFNone)
+}
+
+// SanityFlags is a bitmask of the rule changes which are active for a block or
+// transaction passed to CheckBlockSanityWithFlags and
+// CheckTransactionSanityWithFlags.  Since the sanity checks are context free,
+// they can't query the deployment states themselves, so the caller, such as a
+// service building blocks or transactions to submit to the node, passes the
+// deployments the node would enforce for them.
+type SanityFlags uint32
+
+const (
+	// SFSegWit indicates the segwit deployment is active.  Transactions
+	// may then carry witness data, and blocks must commit to it in their
+	// coinbase and stay within the block weight limit.
+	SFSegWit SanityFlags = 1 << iota
+
+	// SFTaproot indicates the taproot deployment is active.  Taproot only
+	// changes the script validation rules, which the sanity checks don't
+	// run, so it otherwise implies SFSegWit since it can't activate
+	// without it.
+	SFTaproot
+
+	// SFMweb indicates the MWEB deployment is active.  Transactions may
+	// then carry MWEB data or be HogEx transactions, and blocks may carry
+	// an MWEB extension block.
+	SFMweb
+
+	// SFNone is a convenience value to specifically indicate no active
+	// deployments.
+	SFNone SanityFlags = 0
+)
+
+// checkTransactionDeployments ensures a transaction only uses the features of
+// the deployments which are active according to the passed flags.
+func checkTransactionDeployments(tx *ltcutil.Tx, flags SanityFlags) error {
+	msgTx := tx.MsgTx()
+	if flags&(SFSegWit|SFTaproot) == 0 && msgTx.HasWitness() {
+		str := fmt.Sprintf("transaction %v has witness data before "+
+			"segwit is active", tx.Hash())
+		return ruleError(ErrUnexpectedWitness, str)
+	}
+	if flags&SFMweb == 0 && (msgTx.Mweb != nil || msgTx.IsHogEx) {
+		str := fmt.Sprintf("transaction %v has MWEB data before MWEB "+
+			"is active", tx.Hash())
+		return ruleError(ErrUnexpectedMweb, str)
+	}
+
+	return nil
+}
+
+// CheckTransactionSanityWithFlags performs the same context free checks as
+// CheckTransactionSanity and additionally ensures the transaction only uses the
+// features of the deployments which are active according to the passed flags.
+func CheckTransactionSanityWithFlags(tx *ltcutil.Tx, flags SanityFlags) error {
+	if err := CheckTransactionSanity(tx); err != nil {
+		return err
+	}
+	return checkTransactionDeployments(tx, flags)
+}
+
+// CheckBlockSanityWithFlags performs the same context free checks the node
+// does on a block before processing it, enforcing the size and signature
+// operation limits of the passed network along with the rules of the
+// deployments which are active according to the passed flags.  It allows
+// callers building blocks to reject them before submitting them to the node.
+//
+// The checks which depend on the chain the block connects to, such as its
+// difficulty, coinbase height and transaction inputs, are not performed.
+func CheckBlockSanityWithFlags(block *ltcutil.Block, chainParams *chaincfg.Params,
+	timeSource MedianTimeSource, flags SanityFlags) error {
+
+	err := checkBlockSanity(block, chainParams, timeSource, BFNone)
+	if err != nil {
+		return err
+	}
+
+	msgBlock := block.MsgBlock()
+	if flags&SFMweb == 0 && (msgBlock.MwebHeader != nil ||
+		msgBlock.MwebTransactions != nil) {
+
+		return ruleError(ErrUnexpectedMweb, "block has an MWEB "+
+			"extension block before MWEB is active")
+	}
+	for _, tx := range block.Transactions() {
+		if err := checkTransactionDeployments(tx, flags); err != nil {
+			return err
+		}
+	}
+
+	if flags&(SFSegWit|SFTaproot) != 0 {
+		return checkBlockWitness(block, chainParams)
+	}
+	return nil
 }
 
 // ExtractCoinbaseHeight attempts to extract the height of the block from the
@@ -722,10 +845,12 @@ func CheckBlockHeaderContext(header *wire.BlockHeader, prevNode HeaderCtx,
 	// Reject outdated block versions once a majority of the network
 	// has upgraded.  These were originally voted on by BIP0034,
 	// BIP0065, and BIP0066.
+	// Networks may opt out of this to accept their historical blocks.
 	params := c.ChainParams()
-	if header.Version < 2 && blockHeight >= params.BIP0034Height ||
-		header.Version < 3 && blockHeight >= params.BIP0066Height ||
-		header.Version < 4 && blockHeight >= params.BIP0065Height {
+	if !params.AllowLegacyBlockVersions &&
+		(header.Version < 2 && blockHeight >= params.BIP0034Height ||
+			header.Version < 3 && blockHeight >= params.BIP0066Height ||
+			header.Version < 4 && blockHeight >= params.BIP0065Height) {
 
 		str := "new blocks with version %d are no longer valid"
 		str = fmt.Sprintf(str, header.Version)
@@ -783,6 +908,16 @@ func (b *BlockChain) checkBlockContext(block *ltcutil.Block, prevNode *blockNode
 		return err
 	}
 
+	// Ensure the block solves the signet challenge when running on a
+	// signet network.  Much like the proof of work, the solution is not
+	// checked when the caller indicates it has already been checked.
+	if flags&BFNoPoWCheck != BFNoPoWCheck {
+		err := CheckSignetBlockSolution(block, b.chainParams)
+		if err != nil {
+			return err
+		}
+	}
+
 	fastAdd := flags&BFFastAdd == BFFastAdd
 	if !fastAdd {
 		// Obtain the latest state of the deployed CSV soft-fork in
@@ -842,34 +977,43 @@ func (b *BlockChain) checkBlockContext(block *ltcutil.Block, prevNode *blockNode
 		// If segwit is active, then we'll need to fully validate the
 		// new witness commitment for adherence to the rules.
 		if segwitState == ThresholdActive {
-			// Validate the witness commitment (if any) within the
-			// block.  This involves asserting that if the coinbase
-			// contains the special commitment output, then this
-			// merkle root matches a computed merkle root of all
-			// the wtxid's of the transactions within the block. In
-			// addition, various other checks against the
-			// coinbase's witness stack.
-			if err := ValidateWitnessCommitment(block); err != nil {
+			err := checkBlockWitness(block, b.chainParams)
+			if err != nil {
 				return err
 			}
-
-			// Once the witness commitment, witness nonce, and sig
-			// op cost have been validated, we can finally assert
-			// that the block's weight doesn't exceed the current
-			// consensus parameter.
-			blockWeight := GetBlockWeight(block)
-			if blockWeight > MaxBlockWeight {
-				str := fmt.Sprintf("block's weight metric is "+
-					"too high - got %v, max %v",
-					blockWeight, MaxBlockWeight)
-				return ruleError(ErrBlockWeightTooHigh, str)
-			}
 		}
 	}
 
 	return nil
 }
 
+// checkBlockWitness ensures a block satisfies the rules of the segwit
+// soft-fork, which are its witness commitment and the weight limit of the
+// passed network.
+func checkBlockWitness(block *ltcutil.Block, chainParams *chaincfg.Params) error {
+	// Validate the witness commitment (if any) within the block.  This
+	// involves asserting that if the coinbase contains the special
+	// commitment output, then this merkle root matches a computed merkle
+	// root of all the wtxid's of the transactions within the block. In
+	// addition, various other checks against the coinbase's witness stack.
+	if err := ValidateWitnessCommitment(block); err != nil {
+		return err
+	}
+
+	// Once the witness commitment, witness nonce, and sig op cost have been
+	// validated, we can finally assert that the block's weight doesn't
+	// exceed the current consensus parameter.
+	blockWeight := GetBlockWeight(block)
+	maxWeight := BlockWeightLimit(chainParams)
+	if blockWeight > maxWeight {
+		str := fmt.Sprintf("block's weight metric is too high - got %v, "+
+			"max %v", blockWeight, maxWeight)
+		return ruleError(ErrBlockWeightTooHigh, str)
+	}
+
+	return nil
+}
+
 // checkBIP0030 ensures blocks do not contain duplicate transactions which
 // 'overwrite' older transactions that are not fully spent.  This prevents an
 // attack where a coinbase and all of its dependent transactions could be
@@ -1038,6 +1182,8 @@ func CheckTransactionInputs(tx *ltcutil.Tx, txHeight int32, utxoView *UtxoViewpo
 //
 // This function MUST be called with the chain state lock held (for writes).
 func (b *BlockChain) checkConnectBlock(node *blockNode, block *ltcutil.Block, view *UtxoViewpoint, stxos *[]SpentTxOut) error {
+	defer startTraceRegion(traceRegionCheckConnectBlock).End()
+
 	// If the side chain blocks end up in the database, a call to
 	// CheckBlockSanity should be done here in case a previous version
 	// allowed a block that is no longer valid.  However, since the
@@ -1096,7 +1242,7 @@ func (b *BlockChain) checkConnectBlock(node *blockNode, block *ltcutil.Block, vi
 	// "standard" type.  The rules for this BIP only apply to transactions
 	// after the timestamp defined by txscript.Bip16Activation.  See
 	// https://en.bitcoin.it/wiki/BIP_0016 for more details.
-	enforceBIP0016 := node.timestamp >= txscript.Bip16Activation.Unix()
+	enforceBIP0016 := node.Timestamp() >= txscript.Bip16Activation.Unix()
 
 	// Query for the Version Bits state for the segwit soft-fork
 	// deployment. If segwit is active, we'll switch over to enforcing all
@@ -1114,6 +1260,7 @@ func (b *BlockChain) checkConnectBlock(node *blockNode, block *ltcutil.Block, vi
 	// signature operations in each of the input transaction public key
 	// scripts.
 	transactions := block.Transactions()
+	maxSigOpsCost := BlockSigOpsCostLimit(b.chainParams)
 	totalSigOpCost := 0
 	for i, tx := range transactions {
 		// Since the first (and only the first) transaction has
@@ -1132,10 +1279,10 @@ func (b *BlockChain) checkConnectBlock(node *blockNode, block *ltcutil.Block, vi
 		// this on every loop iteration to avoid overflow.
 		lastSigOpCost := totalSigOpCost
 		totalSigOpCost += sigOpCost
-		if totalSigOpCost < lastSigOpCost || totalSigOpCost > MaxBlockSigOpsCost {
+		if totalSigOpCost < lastSigOpCost || int64(totalSigOpCost) > maxSigOpsCost {
 			str := fmt.Sprintf("block contains too many "+
 				"signature operations - got %v, max %v",
-				totalSigOpCost, MaxBlockSigOpsCost)
+				totalSigOpCost, maxSigOpsCost)
 			return ruleError(ErrTooManySigOps, str)
 		}
 	}
@@ -1197,10 +1344,13 @@ func (b *BlockChain) checkConnectBlock(node *blockNode, block *ltcutil.Block, vi
 	// transactions are included in the merkle root hash and any changes
 	// will therefore be detected by the next checkpoint).  This is a huge
 	// optimization because running the scripts is the most time consuming
-	// portion of block handling.
+	// portion of block handling.  Advisory checkpoints are not trusted to
+	// detect such changes, so the scripts are always run for them.
 	checkpoint := b.LatestCheckpoint()
 	runScripts := true
-	if checkpoint != nil && node.height <= checkpoint.Height {
+	if checkpoint != nil && node.height <= checkpoint.Height &&
+		!b.chainParams.AdvisoryCheckpoints {
+
 		runScripts = false
 	}
 
@@ -1213,8 +1363,12 @@ func (b *BlockChain) checkConnectBlock(node *blockNode, block *ltcutil.Block, vi
 
 	// Enforce DER signatures for block versions 3+ once the historical
 	// activation threshold has been reached.  This is part of BIP0066.
+	// Networks may also require them in all blocks or in none at all.
 	blockHeader := &block.MsgBlock().Header
-	if blockHeader.Version >= 3 && node.height >= b.chainParams.BIP0066Height {
+	if b.chainParams.EnforceStrictDER || !b.chainParams.AllowNonStrictDER &&
+		blockHeader.Version >= 3 &&
+		node.height >= b.chainParams.BIP0066Height {
+
 		scriptFlags |= txscript.ScriptVerifyDERSignatures
 	}
 
@@ -1288,7 +1442,7 @@ func (b *BlockChain) checkConnectBlock(node *blockNode, block *ltcutil.Block, vi
 	// prevent CPU exhaustion attacks.
 	if runScripts {
 		err := checkBlockScripts(block, view, scriptFlags, b.sigCache,
-			b.hashCache)
+			b.scriptCache, b.hashCache)
 		if err != nil {
 			return err
 		}
@@ -1323,7 +1477,7 @@ func (b *BlockChain) CheckConnectBlockTemplate(block *ltcutil.Block) error {
 		return ruleError(ErrPrevBlockNotBest, str)
 	}
 
-	err := checkBlockSanity(block, b.chainParams.PowLimit, b.timeSource, flags)
+	err := checkBlockSanity(block, b.chainParams, b.timeSource, flags)
 	if err != nil {
 		return err
 	}
@@ -1341,6 +1495,63 @@ func (b *BlockChain) CheckConnectBlockTemplate(block *ltcutil.Block) error {
 	return b.checkConnectBlock(newNode, block, view, nil)
 }
 
+// PrecheckBlock performs the cheap checks of the passed block which allow
+// obviously invalid work, such as a block submitted by a miner with a stale
+// difficulty or a mangled coinbase, to be rejected with a precise reason
+// before the block is fully processed.  It ensures the claimed difficulty is
+// the one required by the difficulty algorithm active after the previous
+// block, the proof of work is valid for it, the timestamp is not too far in
+// the future and the merkle root commits to the transactions.
+// The merkle root is calculated concurrently with the header checks.
+//
+// The required difficulty is not checked when the previous block is unknown
+// since the block can only be processed as an orphan.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) PrecheckBlock(block *ltcutil.Block) error {
+	header := &block.MsgBlock().Header
+	merkleErr := make(chan error, 1)
+	go func() {
+		calcMerkleRoot := CalcMerkleRoot(block.Transactions(), false)
+		if !header.MerkleRoot.IsEqual(&calcMerkleRoot) {
+			str := fmt.Sprintf("block merkle root is invalid - block "+
+				"header indicates %v, but calculated value is %v",
+				header.MerkleRoot, calcMerkleRoot)
+			merkleErr <- ruleError(ErrBadMerkleRoot, str)
+			return
+		}
+		merkleErr <- nil
+	}()
+
+	// Check the claimed difficulty before the proof of work so that work
+	// solved against a stale target is reported as such rather than as a
+	// high hash.
+	var err error
+	b.chainLock.Lock()
+	if prevNode := b.index.LookupNode(&header.PrevBlock); prevNode != nil {
+		var expectedDifficulty uint32
+		expectedDifficulty, err = calcNextRequiredDifficulty(prevNode,
+			header.Timestamp, b)
+		if err == nil && header.Bits != expectedDifficulty {
+			str := "block difficulty of %d is not the expected value of %d"
+			str = fmt.Sprintf(str, header.Bits, expectedDifficulty)
+			err = ruleError(ErrUnexpectedDifficulty, str)
+		}
+	}
+	b.chainLock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	err = CheckBlockHeaderSanity(header, b.chainParams.PowLimit,
+		b.timeSource, BFNone)
+	if err != nil {
+		return err
+	}
+
+	return <-merkleErr
+}
+
 // ChainParams returns the Blockchain's configured chaincfg.Params.
 //
 // NOTE: Part of the ChainCtx interface.
@@ -1372,7 +1583,11 @@ func (b *BlockChain) MaxRetargetTimespan() int64 {
 }
 
 // VerifyCheckpoint checks that the height and hash match the stored
-// checkpoints.
+// checkpoints, which are the ones of the network along with the ones added by
+// the caller.  It returns true when there is no checkpoint at the height or the
+// checkpoints of the network are only advisory.
+//
+// This function is safe for concurrent access.
 //
 // NOTE: Part of the ChainCtx interface.
 func (b *BlockChain) VerifyCheckpoint(height int32,
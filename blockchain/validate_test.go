@@ -10,9 +10,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ltcsuite/ltcd/btcec/v2"
 	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
 	"github.com/ltcsuite/ltcd/wire"
 )
 
@@ -63,6 +65,49 @@ func TestSequenceLocksActive(t *testing.T) {
 	}
 }
 
+// TestCalcBlockSubsidy ensures the block subsidy follows the halving schedule
+// of the network unless it is overridden by a subsidy schedule or callback.
+func TestCalcBlockSubsidy(t *testing.T) {
+	halving := chaincfg.RegressionNetParams
+	schedule := halving
+	schedule.SubsidySchedule = []chaincfg.SubsidyStep{
+		{Height: 1, Subsidy: 1000 * ltcutil.SatoshiPerBitcoin},
+		{Height: 100, Subsidy: 10 * ltcutil.SatoshiPerBitcoin},
+		{Height: 1000, Subsidy: 1},
+	}
+	callback := schedule
+	callback.CalcSubsidy = func(height int32) int64 {
+		return int64(height) * 2
+	}
+
+	tests := []struct {
+		name   string
+		params *chaincfg.Params
+		height int32
+		want   int64
+	}{
+		{"halving genesis", &halving, 0, baseSubsidy},
+		{"halving before first", &halving, 149, baseSubsidy},
+		{"halving first", &halving, 150, baseSubsidy / 2},
+		{"halving second", &halving, 300, baseSubsidy / 4},
+		{"halving exhausted", &halving, 150 * 64, 0},
+		{"schedule before first step", &schedule, 0, 0},
+		{"schedule first step", &schedule, 1, 1000 * ltcutil.SatoshiPerBitcoin},
+		{"schedule within step", &schedule, 99, 1000 * ltcutil.SatoshiPerBitcoin},
+		{"schedule second step", &schedule, 100, 10 * ltcutil.SatoshiPerBitcoin},
+		{"schedule last step", &schedule, math.MaxInt32, 1},
+		{"callback", &callback, 21, 42},
+	}
+
+	for _, test := range tests {
+		got := CalcBlockSubsidy(test.height, test.params)
+		if got != test.want {
+			t.Errorf("%s: got subsidy %d, want %d", test.name, got,
+				test.want)
+		}
+	}
+}
+
 // TestCheckConnectBlockTemplate tests the CheckConnectBlockTemplate function to
 // ensure it fails.
 func TestCheckConnectBlockTemplate(t *testing.T) {
@@ -148,6 +193,214 @@ func TestCheckConnectBlockTemplate(t *testing.T) {
 	}
 }
 
+// TestPrecheckBlock ensures PrecheckBlock rejects blocks with an unexpected
+// difficulty, an invalid proof of work, a timestamp too far in the future or a
+// bad merkle root with the matching error codes.
+func TestPrecheckBlock(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	chain := newFakeChain(&params)
+	tip := chain.bestChain.Tip()
+
+	now := time.Unix(time.Now().Unix(), 0)
+	bits, err := chain.CalcNextRequiredDifficulty(now)
+	if err != nil {
+		t.Fatalf("CalcNextRequiredDifficulty: %v", err)
+	}
+
+	// solve creates a block building on the tip with the passed header
+	// fields and solves it unless requested otherwise.
+	solve := func(bits uint32, timestamp time.Time, solved bool) *wire.MsgBlock {
+		coinbase := wire.NewMsgTx(1)
+		coinbase.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{},
+				wire.MaxPrevOutIndex),
+			SignatureScript: []byte{0x51, 0x51},
+			Sequence:        wire.MaxTxInSequenceNum,
+		})
+		coinbase.AddTxOut(wire.NewTxOut(0, []byte{0x51}))
+		txns := []*ltcutil.Tx{ltcutil.NewTx(coinbase)}
+
+		msgBlock := &wire.MsgBlock{
+			Header: wire.BlockHeader{
+				Version:    4,
+				PrevBlock:  tip.hash,
+				MerkleRoot: CalcMerkleRoot(txns, false),
+				Timestamp:  timestamp,
+				Bits:       bits,
+			},
+			Transactions: []*wire.MsgTx{coinbase},
+		}
+		target := CompactToBig(bits)
+		for {
+			hash := msgBlock.Header.PowHash()
+			if (HashToBig(&hash).Cmp(target) <= 0) == solved {
+				return msgBlock
+			}
+			msgBlock.Header.Nonce++
+		}
+	}
+
+	badMerkle := solve(bits, now, true)
+	badMerkle.Transactions[0].TxOut[0].Value++
+
+	tests := []struct {
+		name  string
+		block *wire.MsgBlock
+		err   ErrorCode
+	}{
+		{"valid", solve(bits, now, true), 0},
+		{"difficulty", solve(bits-1, now, true), ErrUnexpectedDifficulty},
+		{"high hash", solve(bits, now, false), ErrHighHash},
+		{"time too new", solve(bits, now.Add(3*time.Hour), true),
+			ErrTimeTooNew},
+		{"merkle root", badMerkle, ErrBadMerkleRoot},
+	}
+	for _, test := range tests {
+		err := chain.PrecheckBlock(ltcutil.NewBlock(test.block))
+		if test.err == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		if rerr, ok := err.(RuleError); !ok || rerr.ErrorCode != test.err {
+			t.Errorf("%s: got error %v, want %v", test.name, err,
+				test.err)
+		}
+	}
+}
+
+// TestCheckBlockHeaderContextLegacyVersions ensures outdated block versions are
+// rejected once BIP0034, BIP0065 and BIP0066 are active unless the network
+// allows legacy block versions.
+func TestCheckBlockHeaderContextLegacyVersions(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	params.BIP0034Height = 1
+	params.BIP0065Height = 1
+	params.BIP0066Height = 1
+
+	for _, allowLegacy := range []bool{false, true} {
+		params.AllowLegacyBlockVersions = allowLegacy
+		chain := newFakeChain(&params)
+		tip := chain.bestChain.Tip()
+		for version := int32(1); version <= 4; version++ {
+			header := wire.BlockHeader{
+				Version:   version,
+				PrevBlock: tip.hash,
+				Timestamp: time.Unix(tip.Timestamp()+60, 0),
+				Bits:      params.PowLimitBits,
+			}
+			err := CheckBlockHeaderContext(&header, tip, BFNone, chain,
+				true)
+			wantValid := allowLegacy || version == 4
+			if wantValid && err != nil {
+				t.Errorf("allow legacy %v, version %d: unexpected "+
+					"error: %v", allowLegacy, version, err)
+			}
+			if !wantValid && !isRuleErrorCode(err, ErrBlockVersionTooOld) {
+				t.Errorf("allow legacy %v, version %d: got error "+
+					"%v, want %v", allowLegacy, version, err,
+					ErrBlockVersionTooOld)
+			}
+		}
+	}
+}
+
+// TestNonStrictDERReplay ensures a block spending an output with a signature
+// which is not strict DER is rejected once BIP0066 is active unless the network
+// allows such signatures, and that a network can't both require and allow them.
+func TestNonStrictDERReplay(t *testing.T) {
+	privKey, _ := btcec.PrivKeyFromBytes([]byte{0x01})
+	pkScript, err := txscript.NewScriptBuilder().
+		AddData(privKey.PubKey().SerializeCompressed()).
+		AddOp(txscript.OP_CHECKSIG).Script()
+	if err != nil {
+		t.Fatalf("unable to create public key script: %v", err)
+	}
+
+	params := chaincfg.RegressionNetParams
+	params.BIP0066Height = 1
+	params.CoinbaseMaturity = 1
+	genesisHash := params.GenesisHash
+	timestamp := params.GenesisBlock.Header.Timestamp.Add(time.Minute)
+	block1 := solveTestBlockTxns(t, genesisHash, 1, timestamp, pkScript)
+
+	// Spend the coinbase of the first block with a signature whose R value
+	// is padded with an excess zero byte, which only BER allows.
+	spend := wire.NewMsgTx(1)
+	spend.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash: block1.Transactions()[0].MsgTx().TxHash(),
+		},
+		Sequence: wire.MaxTxInSequenceNum,
+	})
+	spend.AddTxOut(wire.NewTxOut(0, []byte{0x51}))
+	sig, err := txscript.RawTxInSignature(spend, 0, pkScript,
+		txscript.SigHashAll, privKey)
+	if err != nil {
+		t.Fatalf("unable to sign transaction: %v", err)
+	}
+	rLen := int(sig[3])
+	paddedSig := []byte{sig[0], sig[1] + 1, sig[2], byte(rLen + 1), 0x00}
+	paddedSig = append(paddedSig, sig[4:]...)
+	spend.TxIn[0].SignatureScript, err = txscript.NewScriptBuilder().
+		AddData(paddedSig).Script()
+	if err != nil {
+		t.Fatalf("unable to create signature script: %v", err)
+	}
+	block2 := solveTestBlockTxns(t, block1.Hash(), 2,
+		timestamp.Add(time.Minute), []byte{0x51}, spend)
+
+	tests := []struct {
+		name      string
+		allow     bool
+		wantValid bool
+	}{
+		{name: "strict DER enforced", allow: false, wantValid: false},
+		{name: "non-strict DER allowed", allow: true, wantValid: true},
+	}
+	for _, test := range tests {
+		params.AllowNonStrictDER = test.allow
+		chain, teardownFunc, err := chainSetup("nonstrictderreplay",
+			&params)
+		if err != nil {
+			t.Fatalf("%s: failed to setup chain instance: %v",
+				test.name, err)
+		}
+
+		for _, block := range []*ltcutil.Block{block1, block2} {
+			_, _, err = chain.ProcessBlock(block, BFNone)
+			if err != nil {
+				break
+			}
+		}
+		if test.wantValid && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if !test.wantValid && !isRuleErrorCode(err, ErrScriptValidation) {
+			t.Errorf("%s: got error %v, want %v", test.name, err,
+				ErrScriptValidation)
+		}
+		teardownFunc()
+	}
+
+	chain, teardownFunc, err := chainSetup("nonstrictderreplay", &params)
+	if err != nil {
+		t.Fatalf("failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+	params.EnforceStrictDER = true
+	_, err = New(&Config{
+		DB:          chain.db,
+		ChainParams: &params,
+		TimeSource:  NewMedianTime(),
+	})
+	if _, ok := err.(AssertError); !ok {
+		t.Fatalf("chain parameters enforcing and allowing non-strict "+
+			"DER signatures: got error %v, want AssertError", err)
+	}
+}
+
 // TestCheckBlockSanity tests the CheckBlockSanity function to ensure it works
 // as expected.
 func TestCheckBlockSanity(t *testing.T) {
@@ -169,6 +422,150 @@ func TestCheckBlockSanity(t *testing.T) {
 	}
 }
 
+// TestCheckBlockSanityLimits ensures the size and signature operation limits
+// of the chain parameters are enforced by the sanity checks of a block.
+func TestCheckBlockSanityLimits(t *testing.T) {
+	// Copy the block since TestCheckBlockSanity modifies its timestamp.
+	msgBlock := Block100000
+	msgBlock.Header.Timestamp = msgBlock.Header.Timestamp.Truncate(time.Second)
+	block := ltcutil.NewBlock(&msgBlock)
+	timeSource := NewMedianTime()
+	baseSize := int64(block.MsgBlock().SerializeSizeStripped())
+	sigOpsCost := int64(0)
+	for _, tx := range block.Transactions() {
+		sigOpsCost += int64(CountSigOps(tx) * WitnessScaleFactor)
+	}
+
+	tests := []struct {
+		name       string
+		weight     int64
+		sigOpsCost int64
+		err        ErrorCode
+	}{
+		{"defaults", 0, 0, 0},
+		{"exact limits", baseSize * WitnessScaleFactor, sigOpsCost, 0},
+		{"weight", (baseSize - 1) * WitnessScaleFactor, 0, ErrBlockTooBig},
+		{"sig ops", 0, sigOpsCost - 1, ErrTooManySigOps},
+	}
+	for _, test := range tests {
+		params := chaincfg.MainNetParams
+		params.MaxBlockWeight = test.weight
+		params.MaxBlockSigOpsCost = test.sigOpsCost
+		err := checkBlockSanity(block, &params, timeSource, BFNone)
+		if test.err == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		if rerr, ok := err.(RuleError); !ok || rerr.ErrorCode != test.err {
+			t.Errorf("%s: got error %v, want %v", test.name, err,
+				test.err)
+		}
+	}
+}
+
+// TestCheckSanityWithFlags ensures the flag-aware sanity checks only accept
+// witness and MWEB data once the deployments which introduce them are active.
+func TestCheckSanityWithFlags(t *testing.T) {
+	// newBlock returns a copy of Block100000 modified by the passed
+	// function.
+	newBlock := func(modify func(*wire.MsgBlock)) *ltcutil.Block {
+		msgBlock := Block100000
+		msgBlock.Header.Timestamp = msgBlock.Header.Timestamp.Truncate(
+			time.Second)
+		msgBlock.Transactions = make([]*wire.MsgTx, 0,
+			len(Block100000.Transactions))
+		for _, tx := range Block100000.Transactions {
+			msgBlock.Transactions = append(msgBlock.Transactions,
+				tx.Copy())
+		}
+		modify(&msgBlock)
+		return ltcutil.NewBlock(&msgBlock)
+	}
+	addWitness := func(b *wire.MsgBlock) {
+		b.Transactions[0].TxIn[0].Witness = wire.TxWitness{{0x01}}
+	}
+	addMweb := func(b *wire.MsgBlock) {
+		b.MwebHeader = &wire.MwebHeader{}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*wire.MsgBlock)
+		flags  SanityFlags
+		err    ErrorCode
+	}{
+		{"no deployments", func(*wire.MsgBlock) {}, SFNone, 0},
+		{"all deployments", func(*wire.MsgBlock) {},
+			SFSegWit | SFTaproot | SFMweb, 0},
+		{"witness before segwit", addWitness, SFNone,
+			ErrUnexpectedWitness},
+		{"witness without commitment", addWitness, SFSegWit,
+			ErrUnexpectedWitness},
+		{"mweb before activation", addMweb, SFSegWit,
+			ErrUnexpectedMweb},
+		{"mweb after activation", addMweb, SFSegWit | SFMweb, 0},
+	}
+	timeSource := NewMedianTime()
+	for _, test := range tests {
+		block := newBlock(test.modify)
+		err := CheckBlockSanityWithFlags(block, &chaincfg.MainNetParams,
+			timeSource, test.flags)
+		if test.err == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		if rerr, ok := err.(RuleError); !ok || rerr.ErrorCode != test.err {
+			t.Errorf("%s: got error %v, want %v", test.name, err,
+				test.err)
+		}
+	}
+
+	// Transactions with witness data are only sane once segwit or taproot
+	// are active, and HogEx transactions once MWEB is active.
+	newTx := func() *wire.MsgTx {
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{1}},
+		})
+		tx.AddTxOut(wire.NewTxOut(1000, []byte{txscript.OP_TRUE}))
+		return tx
+	}
+	witnessTx := newTx()
+	witnessTx.TxIn[0].Witness = wire.TxWitness{{0x01}}
+	hogExTx := newTx()
+	hogExTx.IsHogEx = true
+	txTests := []struct {
+		name  string
+		tx    *wire.MsgTx
+		flags SanityFlags
+		err   ErrorCode
+	}{
+		{"witness before segwit", witnessTx, SFNone, ErrUnexpectedWitness},
+		{"witness with segwit", witnessTx, SFSegWit, 0},
+		{"witness with taproot", witnessTx, SFTaproot, 0},
+		{"hogex before mweb", hogExTx, SFSegWit, ErrUnexpectedMweb},
+		{"hogex with mweb", hogExTx, SFMweb, 0},
+	}
+	for _, test := range txTests {
+		err := CheckTransactionSanityWithFlags(ltcutil.NewTx(test.tx),
+			test.flags)
+		if test.err == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		if rerr, ok := err.(RuleError); !ok || rerr.ErrorCode != test.err {
+			t.Errorf("%s: got error %v, want %v", test.name, err,
+				test.err)
+		}
+	}
+}
+
 // TestCheckSerializedHeight tests the checkSerializedHeight function with
 // various serialized heights and also does negative tests to ensure errors
 // and handled properly.
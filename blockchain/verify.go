@@ -0,0 +1,270 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ltcsuite/ltcd/database"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// VerifyError identifies a block of the main chain which failed verification
+// along with the reason.  It indicates the block, its undo data, or the utxo
+// set is corrupt.
+type VerifyError struct {
+	Height      int32
+	Hash        string
+	Description string
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e VerifyError) Error() string {
+	return fmt.Sprintf("block %s at height %d failed verification: %s",
+		e.Hash, e.Height, e.Description)
+}
+
+// CompactDatabase compacts the underlying storage of the block database on
+// demand.  An error is returned when the database driver does not support
+// compaction.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) CompactDatabase() error {
+	compacter, ok := b.db.(database.Compacter)
+	if !ok {
+		return fmt.Errorf("database type %s does not support "+
+			"compaction", b.db.Type())
+	}
+
+	log.Infof("Compacting the block database")
+	if err := compacter.Compact(); err != nil {
+		return err
+	}
+	log.Infof("Block database compaction completed")
+	return nil
+}
+
+// verifyBlockUtxos ensures every spendable output created by the passed block
+// exists in the view with the expected contents, and then rewinds the view to
+// the state before the block using its spend journal entry.  The view must be
+// at the state of the main chain after the block, which is the case when it is
+// only used to verify blocks in reverse order starting from the tip.
+func (b *BlockChain) verifyBlockUtxos(view *UtxoViewpoint, block *ltcutil.Block,
+	stxos []SpentTxOut) error {
+
+	// Load the outputs created by the block which are not already in the
+	// view.
+	var outpoints []wire.OutPoint
+	for _, tx := range block.Transactions() {
+		prevOut := wire.OutPoint{Hash: *tx.Hash()}
+		for txOutIdx, txOut := range tx.MsgTx().TxOut {
+			if txscript.IsUnspendable(txOut.PkScript) {
+				continue
+			}
+			prevOut.Index = uint32(txOutIdx)
+			outpoints = append(outpoints, prevOut)
+		}
+	}
+	if err := view.fetchUtxos(b.db, outpoints); err != nil {
+		return err
+	}
+
+	// Loop backwards through all transactions since transactions later in
+	// the block can spend outputs created earlier in it.
+	stxoIdx := len(stxos) - 1
+	transactions := block.Transactions()
+	for txIdx := len(transactions) - 1; txIdx > -1; txIdx-- {
+		tx := transactions[txIdx]
+		isCoinBase := txIdx == 0
+
+		prevOut := wire.OutPoint{Hash: *tx.Hash()}
+		for txOutIdx, txOut := range tx.MsgTx().TxOut {
+			if txscript.IsUnspendable(txOut.PkScript) {
+				continue
+			}
+
+			prevOut.Index = uint32(txOutIdx)
+			entry := view.LookupEntry(prevOut)
+			if entry == nil || entry.IsSpent() {
+				return fmt.Errorf("output %v is missing from "+
+					"the utxo set", prevOut)
+			}
+			if entry.Amount() != txOut.Value ||
+				!bytes.Equal(entry.PkScript(), txOut.PkScript) {
+
+				return fmt.Errorf("output %v does not match "+
+					"the utxo set", prevOut)
+			}
+
+			// Entries restored from legacy spend journal entries
+			// might not have their height and coinbase flag.
+			if entry.BlockHeight() != 0 &&
+				(entry.BlockHeight() != block.Height() ||
+					entry.IsCoinBase() != isCoinBase) {
+
+				return fmt.Errorf("output %v has height %d "+
+					"and coinbase flag %v in the utxo set",
+					prevOut, entry.BlockHeight(),
+					entry.IsCoinBase())
+			}
+			entry.Spend()
+		}
+
+		if isCoinBase {
+			continue
+		}
+
+		// Restore the outputs spent by the transaction in the reverse
+		// order of the spend journal entry.
+		txIns := tx.MsgTx().TxIn
+		for txInIdx := len(txIns) - 1; txInIdx > -1; txInIdx-- {
+			if stxoIdx < 0 {
+				return fmt.Errorf("spend journal entry is " +
+					"missing spent outputs")
+			}
+			stxo := &stxos[stxoIdx]
+			stxoIdx--
+
+			entry := &UtxoEntry{
+				amount:      stxo.Amount,
+				pkScript:    stxo.PkScript,
+				blockHeight: stxo.Height,
+			}
+			if stxo.IsCoinBase {
+				entry.packedFlags |= tfCoinBase
+			}
+			view.entries[txIns[txInIdx].PreviousOutPoint] = entry
+		}
+	}
+	if stxoIdx != -1 {
+		return fmt.Errorf("spend journal entry has %d unused spent "+
+			"outputs", stxoIdx+1)
+	}
+
+	return nil
+}
+
+// ProgressFunc is the type of function long-running operations of the chain
+// call to report their progress, with done growing towards total units of work.
+type ProgressFunc func(done, total int64)
+
+// VerifyChain re-validates the blocks at the end of the main chain against the
+// data stored for them.  The depth is the number of blocks to verify, with zero
+// meaning the entire chain, and the level determines how thorough the checks
+// are:
+//
+//	0 - Ensure each block can be loaded from the database
+//	1 - Also perform the context-free sanity checks on each block
+//	2 - Also ensure the spend journal (undo data) of each block is present
+//	    and consistent with the block
+//	3 - Also ensure the outputs created by each block are consistent with
+//	    the utxo set once the later blocks have been rewound using their
+//	    spend journal entries
+//
+// The rewinding done by level 3 happens in memory and does not modify the
+// database, but memory usage grows with the number of blocks verified.
+//
+// A VerifyError is returned for the first block which fails verification.  On
+// pruned nodes, verification stops at the first block which has been pruned.
+// The interrupt channel may be closed to stop verifying early, in which case
+// an error is returned.  The optional progress function is called after each
+// block is verified with the number of blocks verified so far.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) VerifyChain(level, depth int32, interrupt <-chan struct{},
+	progress ProgressFunc) error {
+
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	tip := b.bestChain.Tip()
+	var finishHeight int32
+	if depth > 0 && tip.height > depth {
+		finishHeight = tip.height - depth
+	}
+	log.Infof("Verifying chain for %d blocks at level %d",
+		tip.height-finishHeight, level)
+
+	view := NewUtxoViewpoint()
+	for node := tip; node != nil && node.height > finishHeight; node = node.parent {
+		if interruptRequested(interrupt) {
+			return errInterruptRequested
+		}
+
+		verifyErr := func(desc string, err error) error {
+			if err != nil {
+				desc = fmt.Sprintf("%s: %v", desc, err)
+			}
+			return VerifyError{
+				Height:      node.height,
+				Hash:        node.hash.String(),
+				Description: desc,
+			}
+		}
+
+		var block *ltcutil.Block
+		var stxos []SpentTxOut
+		err := b.db.View(func(dbTx database.Tx) error {
+			var err error
+			block, err = dbFetchBlockByNode(dbTx, node)
+			if err != nil {
+				return err
+			}
+			if level < 2 {
+				return nil
+			}
+			stxos, err = dbFetchSpendJournalEntry(dbTx, block)
+			if err != nil {
+				return verifyErr("unable to load spend journal "+
+					"entry", err)
+			}
+			return nil
+		})
+		if dbErr, ok := err.(database.Error); ok && b.pruneTarget != 0 &&
+			dbErr.ErrorCode == database.ErrBlockNotFound {
+
+			log.Infof("Stopping verification at pruned block %v "+
+				"(height %d)", node.hash, node.height)
+			break
+		}
+		if _, ok := err.(VerifyError); ok {
+			return err
+		}
+		if err != nil {
+			return verifyErr("unable to load block", err)
+		}
+
+		if level >= 1 {
+			err := checkBlockSanity(block, b.chainParams,
+				b.timeSource, BFNone)
+			if err != nil {
+				return verifyErr("block is not sane", err)
+			}
+		}
+
+		if level >= 2 && len(stxos) != countSpentOutputs(block) {
+			return verifyErr(fmt.Sprintf("spend journal entry has "+
+				"%d spent outputs instead of %d", len(stxos),
+				countSpentOutputs(block)), nil)
+		}
+
+		if level >= 3 {
+			if err := b.verifyBlockUtxos(view, block, stxos); err != nil {
+				return verifyErr("inconsistent utxo set", err)
+			}
+		}
+
+		if progress != nil {
+			progress(int64(tip.height-node.height+1),
+				int64(tip.height-finishHeight))
+		}
+	}
+	log.Infof("Chain verify completed successfully")
+
+	return nil
+}
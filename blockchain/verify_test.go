@@ -0,0 +1,117 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestVerifyBlockUtxos ensures the outputs created by a block are checked
+// against the utxo set and the spent outputs are restored from the spend
+// journal entry.
+func TestVerifyBlockUtxos(t *testing.T) {
+	chain := newFakeChain(&chaincfg.MainNetParams)
+	pkScript := []byte{0x51}
+	const height = 100
+
+	// A block with a coinbase and a transaction spending an older output
+	// and creating two outputs, the first of which is spent by a third
+	// transaction in the same block.
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		nil, nil))
+	coinbase.AddTxOut(wire.NewTxOut(5000, pkScript))
+
+	olderOut := wire.OutPoint{Hash: chainhash.Hash{0x01}, Index: 2}
+	spend := wire.NewMsgTx(wire.TxVersion)
+	spend.AddTxIn(wire.NewTxIn(&olderOut, nil, nil))
+	spend.AddTxOut(wire.NewTxOut(1000, pkScript))
+	spend.AddTxOut(wire.NewTxOut(2000, pkScript))
+
+	spendOut := wire.OutPoint{Hash: spend.TxHash(), Index: 0}
+	chained := wire.NewMsgTx(wire.TxVersion)
+	chained.AddTxIn(wire.NewTxIn(&spendOut, nil, nil))
+	chained.AddTxOut(wire.NewTxOut(900, pkScript))
+
+	block := ltcutil.NewBlock(&wire.MsgBlock{
+		Transactions: []*wire.MsgTx{coinbase, spend, chained},
+	})
+	block.SetHeight(height)
+
+	stxos := []SpentTxOut{
+		{Amount: 3100, PkScript: pkScript, Height: 50},
+		{Amount: 1000, PkScript: pkScript, Height: height},
+	}
+
+	// newView returns a view at the state after the block.
+	newView := func() *UtxoViewpoint {
+		view := NewUtxoViewpoint()
+		view.entries[wire.OutPoint{Hash: coinbase.TxHash()}] = &UtxoEntry{
+			amount:      5000,
+			pkScript:    pkScript,
+			blockHeight: height,
+			packedFlags: tfCoinBase,
+		}
+		view.entries[spendOut] = nil
+		view.entries[wire.OutPoint{Hash: spend.TxHash(), Index: 1}] =
+			&UtxoEntry{amount: 2000, pkScript: pkScript,
+				blockHeight: height}
+		view.entries[wire.OutPoint{Hash: chained.TxHash()}] =
+			&UtxoEntry{amount: 900, pkScript: pkScript,
+				blockHeight: height}
+		return view
+	}
+
+	view := newView()
+	if err := chain.verifyBlockUtxos(view, block, stxos); err != nil {
+		t.Fatalf("verifyBlockUtxos: unexpected error: %v", err)
+	}
+	entry := view.LookupEntry(olderOut)
+	if entry == nil || entry.IsSpent() || entry.Amount() != 3100 ||
+		entry.BlockHeight() != 50 {
+
+		t.Fatalf("spent output was not restored: %v", entry)
+	}
+	if entry := view.LookupEntry(spendOut); entry == nil || !entry.IsSpent() {
+		t.Fatalf("output created and spent in the block was not " +
+			"rewound")
+	}
+
+	// An output with a different amount in the utxo set is reported.
+	view = newView()
+	view.entries[wire.OutPoint{Hash: chained.TxHash()}].amount = 901
+	if err := chain.verifyBlockUtxos(view, block, stxos); err == nil {
+		t.Fatal("verifyBlockUtxos: mismatched output not reported")
+	}
+
+	// An output missing from the utxo set is reported.
+	view = newView()
+	view.entries[wire.OutPoint{Hash: spend.TxHash(), Index: 1}] = nil
+	if err := chain.verifyBlockUtxos(view, block, stxos); err == nil {
+		t.Fatal("verifyBlockUtxos: missing output not reported")
+	}
+
+	// An output with the wrong coinbase flag is reported.
+	view = newView()
+	view.entries[wire.OutPoint{Hash: coinbase.TxHash()}].packedFlags = 0
+	if err := chain.verifyBlockUtxos(view, block, stxos); err == nil {
+		t.Fatal("verifyBlockUtxos: wrong coinbase flag not reported")
+	}
+
+	// A spend journal entry with the wrong number of spent outputs is
+	// reported.
+	if err := chain.verifyBlockUtxos(newView(), block, stxos[1:]); err == nil {
+		t.Fatal("verifyBlockUtxos: short spend journal entry not reported")
+	}
+	extra := append(stxos[:1:1], stxos...)
+	if err := chain.verifyBlockUtxos(newView(), block, extra); err == nil {
+		t.Fatal("verifyBlockUtxos: long spend journal entry not reported")
+	}
+}
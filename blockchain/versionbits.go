@@ -5,6 +5,9 @@
 package blockchain
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/ltcsuite/ltcd/chaincfg"
 )
 
@@ -286,21 +289,17 @@ func (b *BlockChain) calcNextBlockVersion(prevNode *blockNode) (int32, error) {
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) CalcNextBlockVersion() (int32, error) {
-	b.chainLock.Lock()
-	version, err := b.calcNextBlockVersion(b.bestChain.Tip())
-	b.chainLock.Unlock()
-	return version, err
+	return b.calcNextBlockVersion(b.bestChain.Tip())
 }
 
-// warnUnknownRuleActivations displays a warning when any unknown new rules are
-// either about to activate or have been activated.  This will only happen once
-// when new rules have been activated and every block for those about to be
-// activated.
+// warnUnknownRuleActivations raises a warning when any unknown new rules are
+// either about to activate or have been activated, and clears it otherwise.
 //
 // This function MUST be called with the chain state lock held (for writes)
 func (b *BlockChain) warnUnknownRuleActivations(node *blockNode) error {
 	// Warn if any unknown new rules are either about to activate or have
 	// already been activated.
+	var messages []string
 	for bit := uint32(0); bit < vbNumBits; bit++ {
 		checker := bitConditionChecker{bit: bit, chain: b}
 		cache := &b.warningCaches[bit]
@@ -311,19 +310,62 @@ func (b *BlockChain) warnUnknownRuleActivations(node *blockNode) error {
 
 		switch state {
 		case ThresholdActive:
-			if !b.unknownRulesWarned {
-				log.Warnf("Unknown new rules activated (bit %d)",
-					bit)
-				b.unknownRulesWarned = true
-			}
+			messages = append(messages, fmt.Sprintf("Unknown new "+
+				"rules activated (bit %d)", bit))
 
 		case ThresholdLockedIn:
 			window := int32(checker.MinerConfirmationWindow())
-			activationHeight := window - (node.height % window)
-			log.Warnf("Unknown new rules are about to activate in "+
-				"%d blocks (bit %d)", activationHeight, bit)
+			activationHeight := node.height + window -
+				(node.height % window)
+			messages = append(messages, fmt.Sprintf("Unknown new "+
+				"rules are about to activate at height %d (bit %d)",
+				activationHeight, bit))
 		}
 	}
+	b.SetWarning(WarningUnknownRules, strings.Join(messages, "; "))
 
 	return nil
 }
+
+// warnUnknownVersions raises a warning when more than half of the blocks of the
+// last miner confirmation window ending with the passed node signal any bits
+// which are not used by the deployments defined by the chain parameters, and
+// clears it otherwise.  This allows the operator to learn about upcoming rule
+// changes this version of the software doesn't implement before they lock in.
+//
+// This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) warnUnknownVersions(node *blockNode) {
+	var knownBits uint32
+	for i := range b.chainParams.Deployments {
+		knownBits |= uint32(1) << b.chainParams.Deployments[i].BitNumber
+	}
+
+	// Count the blocks signaling each of the unknown bits within the
+	// window.
+	window := b.chainParams.MinerConfirmationWindow
+	var counts [vbNumBits]uint32
+	for i := uint32(0); node != nil && i < window; i++ {
+		version := uint32(node.version)
+		node = node.parent
+		if version&vbTopMask != vbTopBits {
+			continue
+		}
+		unknownBits := version &^ vbTopMask &^ knownBits
+		for bit := uint32(0); bit < vbNumBits; bit++ {
+			if unknownBits&(uint32(1)<<bit) != 0 {
+				counts[bit]++
+			}
+		}
+	}
+
+	var messages []string
+	for bit, count := range counts {
+		if count*2 > window {
+			messages = append(messages, fmt.Sprintf("%d of the last "+
+				"%d blocks signal unknown version bit %d, rules "+
+				"which are not implemented might be about to "+
+				"activate", count, window, bit))
+		}
+	}
+	b.SetWarning(WarningUnknownVersions, strings.Join(messages, "; "))
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// invalidChainWarningBlocks is the number of blocks worth of work at the
+// difficulty of the best chain an invalid chain must have over the best chain
+// before a warning is raised.
+const invalidChainWarningBlocks = 6
+
+// WarningType identifies a condition which might prevent the node from working
+// properly and which the operator should be made aware of.
+type WarningType int
+
+// Constants for the type of a warning.
+const (
+	// WarningUnknownRules indicates that rules which are not implemented
+	// are about to be activated or have been activated via version bits.
+	WarningUnknownRules WarningType = iota
+
+	// WarningInvalidChain indicates that a chain which was found to be
+	// invalid has significantly more work than the best chain, which
+	// means either the local node or the majority of the network doesn't
+	// follow the current consensus rules.
+	WarningInvalidChain
+
+	// WarningClockSkew indicates that the local clock appears to be skewed
+	// according to the time of the peers.
+	WarningClockSkew
+
+	// WarningLowDiskSpace indicates that the disk the data is stored on
+	// is running out of space.
+	WarningLowDiskSpace
+
+	// WarningUnknownVersions indicates that the majority of the recent
+	// blocks signal version bits which are not used by any of the known
+	// deployments, which means unknown rules might be about to activate.
+	WarningUnknownVersions
+)
+
+// warningTypeStrings is a map of warning types back to their constant names for
+// pretty printing.
+var warningTypeStrings = map[WarningType]string{
+	WarningUnknownRules:    "WarningUnknownRules",
+	WarningInvalidChain:    "WarningInvalidChain",
+	WarningClockSkew:       "WarningClockSkew",
+	WarningLowDiskSpace:    "WarningLowDiskSpace",
+	WarningUnknownVersions: "WarningUnknownVersions",
+}
+
+// String returns the WarningType in human-readable form.
+func (w WarningType) String() string {
+	if s, ok := warningTypeStrings[w]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown Warning Type (%d)", int(w))
+}
+
+// Warning is a condition which might prevent the node from working properly.
+// It is the data of the NTWarning notifications, in which case an empty
+// message indicates the warning no longer applies.
+type Warning struct {
+	Type    WarningType
+	Message string
+}
+
+// Warnings returns the warnings which currently apply ordered by their type.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) Warnings() []Warning {
+	b.warningsLock.RLock()
+	warnings := make([]Warning, 0, len(b.warnings))
+	for typ, message := range b.warnings {
+		warnings = append(warnings, Warning{Type: typ, Message: message})
+	}
+	b.warningsLock.RUnlock()
+
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].Type < warnings[j].Type
+	})
+	return warnings
+}
+
+// WarningsString returns the messages of the warnings which currently apply
+// joined into a single string, which is empty when there are none.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) WarningsString() string {
+	warnings := b.Warnings()
+	messages := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		messages = append(messages, warning.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// SetWarning raises a warning of the passed type with the passed message,
+// replacing any previous warning of the same type.  Passing an empty message
+// clears the warning.  The subscribers are sent an NTWarning notification when
+// the warning changes.
+//
+// Besides the warnings raised by the chain itself, this allows the callers to
+// raise warnings about conditions the chain can't detect such as low disk
+// space.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) SetWarning(typ WarningType, message string) {
+	b.warningsLock.Lock()
+	if b.warnings[typ] == message {
+		b.warningsLock.Unlock()
+		return
+	}
+	if message == "" {
+		delete(b.warnings, typ)
+	} else {
+		b.warnings[typ] = message
+	}
+	b.warningsLock.Unlock()
+
+	if message != "" {
+		log.Warnf("Warning: %s", message)
+	}
+	b.sendNotification(NTWarning, &Warning{Type: typ, Message: message})
+}
+
+// checkInvalidChainWork raises a warning when the passed node, which is known
+// to be invalid, has significantly more work than the best chain.  The warning
+// is cleared once the best chain catches up with the invalid chain with the
+// most work.
+//
+// This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) checkInvalidChainWork(node *blockNode) {
+	if node != nil && (b.bestInvalid == nil ||
+		node.workSum.Cmp(&b.bestInvalid.workSum) > 0) {
+
+		b.bestInvalid = node
+	}
+	if b.bestInvalid == nil {
+		return
+	}
+
+	tip := b.bestChain.Tip()
+	margin := new(big.Int).Mul(CalcWork(tip.bits),
+		big.NewInt(invalidChainWarningBlocks))
+	threshold := new(big.Int).Add(tip.workSum.Big(), margin)
+	if b.bestInvalid.workSum.Big().Cmp(threshold) <= 0 {
+		if b.bestInvalid.workSum.Cmp(&tip.workSum) <= 0 {
+			b.bestInvalid = nil
+			b.SetWarning(WarningInvalidChain, "")
+		}
+		return
+	}
+
+	b.SetWarning(WarningInvalidChain, fmt.Sprintf("An invalid chain "+
+		"with more work than the best chain was found (block %v, "+
+		"height %d), either this node or the majority of the network "+
+		"does not follow the current consensus rules",
+		b.bestInvalid.hash, b.bestInvalid.height))
+}
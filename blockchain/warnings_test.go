@@ -0,0 +1,140 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+)
+
+// TestWarnings ensures warnings are raised and cleared with notifications to
+// the subscribers and that invalid chains with significantly more work than
+// the best chain raise a warning.
+func TestWarnings(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	chain := newFakeChain(params)
+
+	var notified []Warning
+	chain.Subscribe(func(n *Notification) {
+		if n.Type == NTWarning {
+			notified = append(notified, *n.Data.(*Warning))
+		}
+	})
+
+	// The warnings are ordered by their type and are only notified when
+	// they change.
+	chain.SetWarning(WarningLowDiskSpace, "low disk space")
+	chain.SetWarning(WarningUnknownRules, "unknown rules")
+	chain.SetWarning(WarningUnknownRules, "unknown rules")
+	if len(notified) != 2 {
+		t.Fatalf("got %d notifications, want 2", len(notified))
+	}
+	warnings := chain.Warnings()
+	if len(warnings) != 2 || warnings[0].Type != WarningUnknownRules ||
+		warnings[1].Type != WarningLowDiskSpace {
+
+		t.Fatalf("Warnings: unexpected warnings %v", warnings)
+	}
+	want := "unknown rules; low disk space"
+	if got := chain.WarningsString(); got != want {
+		t.Fatalf("WarningsString: got %q, want %q", got, want)
+	}
+
+	// Clearing a warning notifies an empty message.
+	chain.SetWarning(WarningLowDiskSpace, "")
+	if len(notified) != 3 || notified[2].Message != "" ||
+		notified[2].Type != WarningLowDiskSpace {
+
+		t.Fatalf("clearing the warning was not notified: %v", notified)
+	}
+	if got := chain.WarningsString(); got != "unknown rules" {
+		t.Fatalf("WarningsString: got %q after clearing a warning", got)
+	}
+
+	// extend returns the tip of the passed number of nodes built on top of
+	// the passed parent.
+	extend := func(parent *blockNode, numNodes int) *blockNode {
+		tip := parent
+		for i := 0; i < numNodes; i++ {
+			tip = newFakeNode(tip, 1, params.PowLimitBits,
+				time.Unix(tip.Timestamp()+1, 0))
+		}
+		return tip
+	}
+	genesis := chain.bestChain.Genesis()
+	chain.bestChain.SetTip(extend(genesis, 10))
+
+	// An invalid chain with only slightly more work than the best chain
+	// doesn't raise a warning, while one with significantly more work
+	// does.
+	invalidTip := extend(genesis, 10+invalidChainWarningBlocks)
+	chain.checkInvalidChainWork(invalidTip)
+	if _, ok := chain.warnings[WarningInvalidChain]; ok {
+		t.Fatalf("warned about an invalid chain with little more work")
+	}
+	invalidTip = extend(invalidTip, 1)
+	chain.checkInvalidChainWork(invalidTip)
+	if _, ok := chain.warnings[WarningInvalidChain]; !ok {
+		t.Fatalf("no warning about an invalid chain with more work")
+	}
+
+	// The warning is cleared once the best chain caught up.
+	chain.bestChain.SetTip(extend(chain.bestChain.Tip(), 10))
+	chain.checkInvalidChainWork(nil)
+	if _, ok := chain.warnings[WarningInvalidChain]; ok {
+		t.Fatalf("warning about an invalid chain was not cleared")
+	}
+}
+
+// TestWarnUnknownVersions ensures a warning is raised once more than half of
+// the blocks of the last miner confirmation window signal a version bit which
+// is not used by any of the deployments.
+func TestWarnUnknownVersions(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	chain := newFakeChain(params)
+	for i := range params.Deployments {
+		if params.Deployments[i].BitNumber == 27 {
+			t.Fatalf("bit 27 is used by deployment %d", i)
+		}
+	}
+
+	// extend returns the tip of the passed number of nodes with the passed
+	// version built on top of the passed parent.
+	extend := func(parent *blockNode, version int32, numNodes uint32) *blockNode {
+		tip := parent
+		for i := uint32(0); i < numNodes; i++ {
+			tip = newFakeNode(tip, version, params.PowLimitBits,
+				time.Unix(tip.Timestamp()+1, 0))
+		}
+		return tip
+	}
+
+	// Exactly half of the window signaling the unknown bit doesn't raise
+	// a warning, while one more block does.  Signaling a known bit
+	// never raises a warning.
+	window := params.MinerConfirmationWindow
+	known := int32(vbTopBits | 1<<params.Deployments[0].BitNumber)
+	unknown := int32(vbTopBits | 1<<27)
+	tip := extend(chain.bestChain.Genesis(), known, window/2)
+	tip = extend(tip, unknown, window/2)
+	chain.warnUnknownVersions(tip)
+	if _, ok := chain.warnings[WarningUnknownVersions]; ok {
+		t.Fatalf("warned about half of the blocks signaling a bit")
+	}
+	tip = extend(tip, unknown, 1)
+	chain.warnUnknownVersions(tip)
+	if _, ok := chain.warnings[WarningUnknownVersions]; !ok {
+		t.Fatalf("no warning about the majority signaling a bit")
+	}
+
+	// The warning is cleared once the signaling drops out of the window.
+	tip = extend(tip, known, window/2)
+	chain.warnUnknownVersions(tip)
+	if _, ok := chain.warnings[WarningUnknownVersions]; ok {
+		t.Fatalf("warning about signaling a bit was not cleared")
+	}
+}
@@ -7,6 +7,7 @@ package blockchain
 import (
 	"fmt"
 
+	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/txscript"
 	"github.com/ltcsuite/ltcd/wire"
@@ -19,16 +20,22 @@ const (
 	// and header, plus the weight of each byte within a transaction. The
 	// weight of a "base" byte is 4, while the weight of a witness byte is
 	// 1. As a result, for a block to be valid, the BlockWeight MUST be
-	// less than, or equal to MaxBlockWeight.
+	// less than, or equal to MaxBlockWeight.  It is the default of networks
+	// which don't set the MaxBlockWeight of their chain parameters.
 	MaxBlockWeight = 4000000
 
 	// MaxBlockBaseSize is the maximum number of bytes within a block
-	// which can be allocated to non-witness data.
+	// which can be allocated to non-witness data.  It also limits the size
+	// of a transaction without its witness data on every network, which is
+	// why the block weight limit of a network can be lowered but not raised
+	// beyond MaxBlockWeight.
 	MaxBlockBaseSize = 1000000
 
 	// MaxBlockSigOpsCost is the maximum number of signature operations
 	// allowed for a block. It is calculated via a weighted algorithm which
-	// weights segregated witness sig ops lower than regular sig ops.
+	// weights segregated witness sig ops lower than regular sig ops.  It is
+	// the default of networks which don't set the MaxBlockSigOpsCost of
+	// their chain parameters.
 	MaxBlockSigOpsCost = 80000
 
 	// WitnessScaleFactor determines the level of "discount" witness data
@@ -45,6 +52,30 @@ const (
 	MaxOutputsPerBlock = MaxBlockWeight / MinTxOutputWeight
 )
 
+// BlockWeightLimit returns the maximum weight of a block of the passed network.
+func BlockWeightLimit(chainParams *chaincfg.Params) int64 {
+	if chainParams.MaxBlockWeight != 0 {
+		return chainParams.MaxBlockWeight
+	}
+	return MaxBlockWeight
+}
+
+// BlockBaseSizeLimit returns the maximum serialized size of a block of the
+// passed network without any witness data, which is the largest size whose
+// weight doesn't exceed the block weight limit of the network.
+func BlockBaseSizeLimit(chainParams *chaincfg.Params) int64 {
+	return BlockWeightLimit(chainParams) / WitnessScaleFactor
+}
+
+// BlockSigOpsCostLimit returns the maximum signature operation cost of a block
+// of the passed network.
+func BlockSigOpsCostLimit(chainParams *chaincfg.Params) int64 {
+	if chainParams.MaxBlockSigOpsCost != 0 {
+		return chainParams.MaxBlockSigOpsCost
+	}
+	return MaxBlockSigOpsCost
+}
+
 // GetBlockWeight computes the value of the weight metric for a given block.
 // Currently the weight metric is simply the sum of the block's serialized size
 // without any witness data scaled proportionally by the WitnessScaleFactor,
@@ -74,6 +105,18 @@ func GetTransactionWeight(tx *ltcutil.Tx) int64 {
 	return int64((baseSize * (WitnessScaleFactor - 1)) + totalSize)
 }
 
+// GetTransactionVirtualSize computes the virtual size of a given transaction,
+// which is its weight divided by the WitnessScaleFactor and rounded up.  It is
+// the size used for fee rates and policy limits.
+func GetTransactionVirtualSize(tx *ltcutil.Tx) int64 {
+	// vSize := (weight(tx) + 3) / 4
+	//       := (((baseSize * 3) + totalSize) + 3) / 4
+	// We add 3 here as a way to compute the ceiling of the prior arithmetic
+	// to 4. The division by 4 creates a discount for wit witness data.
+	return (GetTransactionWeight(tx) + (WitnessScaleFactor - 1)) /
+		WitnessScaleFactor
+}
+
 // GetSigOpCost returns the unified sig op cost for the passed transaction
 // respecting current active soft-forks which modified sig op cost counting.
 // The unified sig op cost for a transaction is computed as the sum of: the
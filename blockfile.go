@@ -0,0 +1,365 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// blockFileProgressInterval is the minimum time between progress
+	// messages while exporting or importing a block file.
+	blockFileProgressInterval = 10 * time.Second
+
+	// blockFileQueueSize is the number of blocks per pre-verification
+	// worker which are read ahead of the block being processed while
+	// importing a block file.
+	blockFileQueueSize = 16
+)
+
+// writeBlockFileEntry writes the passed serialized block to w using the block
+// file (bootstrap.dat) format:
+//
+//	<network> <block length> <serialized block>
+func writeBlockFileEntry(w io.Writer, net wire.BitcoinNet, serializedBlock []byte) error {
+	var header [8]byte
+	binary.LittleEndian.PutUint32(header[0:4], uint32(net))
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(serializedBlock)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(serializedBlock)
+	return err
+}
+
+// readBlockFileEntry reads the next serialized block from r which must be in
+// the block file (bootstrap.dat) format.  A nil block and error are returned
+// when there are no more blocks to read.
+func readBlockFileEntry(r io.Reader, net wire.BitcoinNet) ([]byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:4]); err != nil {
+		if err != io.EOF {
+			return nil, err
+		}
+
+		// No block and no error means there are no more blocks to read.
+		return nil, nil
+	}
+	if _, err := io.ReadFull(r, header[4:]); err != nil {
+		return nil, err
+	}
+
+	magic := binary.LittleEndian.Uint32(header[0:4])
+	if magic != uint32(net) {
+		return nil, fmt.Errorf("network mismatch -- got %x, want %x",
+			magic, uint32(net))
+	}
+
+	// Ensure the block length is sane before allocating it.
+	blockLen := binary.LittleEndian.Uint32(header[4:8])
+	if blockLen > wire.MaxBlockPayload {
+		return nil, fmt.Errorf("block payload of %d bytes is larger "+
+			"than the max allowed %d bytes", blockLen,
+			wire.MaxBlockPayload)
+	}
+
+	serializedBlock := make([]byte, blockLen)
+	if _, err := io.ReadFull(r, serializedBlock); err != nil {
+		return nil, err
+	}
+	return serializedBlock, nil
+}
+
+// exportBlocks writes the blocks of the main chain from the genesis block up to
+// and including the passed height to a block file at the passed path.  A
+// height of zero exports the entire main chain.
+//
+// The file uses the same format as the bootstrap.dat files consumed by the
+// --loadblock option and the addblock utility.
+func exportBlocks(db database.DB, path string, height int32,
+	interrupt <-chan struct{}) error {
+
+	// The exported blocks have already been validated, so the chain is
+	// only loaded to walk the main chain.
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		Interrupt:   interrupt,
+		ChainParams: activeNetParams.Params,
+		TimeSource:  blockchain.NewMedianTime(),
+	})
+	if err != nil {
+		return err
+	}
+
+	best := chain.BestSnapshot()
+	if height == 0 {
+		height = best.Height
+	}
+	if height > best.Height {
+		return fmt.Errorf("export height %d is higher than the current "+
+			"best height %d", height, best.Height)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+
+	ltcdLog.Infof("Exporting blocks 0 through %d to %s", height, path)
+	lastLog := time.Now()
+	for blockHeight := int32(0); blockHeight <= height; blockHeight++ {
+		if interruptRequested(interrupt) {
+			return fmt.Errorf("block export interrupted at height %d",
+				blockHeight)
+		}
+
+		block, err := chain.BlockByHeight(blockHeight)
+		if err != nil {
+			return fmt.Errorf("unable to load block at height %d: %v",
+				blockHeight, err)
+		}
+		serializedBlock, err := block.Bytes()
+		if err != nil {
+			return err
+		}
+		err = writeBlockFileEntry(w, activeNetParams.Net, serializedBlock)
+		if err != nil {
+			return err
+		}
+
+		if now := time.Now(); now.Sub(lastLog) >= blockFileProgressInterval {
+			ltcdLog.Infof("Exported blocks through height %d", blockHeight)
+			lastLog = now
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	ltcdLog.Infof("Exported %d blocks to %s", height+1, path)
+	return nil
+}
+
+// blockFileEntry houses a block read from a block file along with the result
+// of its pre-verification.  The done channel is closed once the block has been
+// deserialized and pre-verified.
+type blockFileEntry struct {
+	serializedBlock []byte
+	block           *ltcutil.Block
+	err             error
+	done            chan struct{}
+}
+
+// preverifyBlocks reads the blocks from r, which must be in the block file
+// format, and runs them through the passed verify function using one worker
+// per CPU.  The entries are returned on the channel in the order they appear
+// in the file, along with a function which must be called to wait for the
+// workers to exit once the caller is done with the channel.  An entry with an
+// error and no serialized block indicates the file could not be read.
+//
+// Closing the quit channel stops reading the file and causes the returned
+// channel to be closed.
+func preverifyBlocks(r io.Reader, net wire.BitcoinNet,
+	verify func(*ltcutil.Block) error,
+	quit <-chan struct{}) (<-chan *blockFileEntry, func()) {
+
+	numWorkers := runtime.NumCPU()
+	work := make(chan *blockFileEntry, numWorkers*blockFileQueueSize)
+	ordered := make(chan *blockFileEntry, numWorkers*blockFileQueueSize)
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for entry := range work {
+				block, err := ltcutil.NewBlockFromBytes(
+					entry.serializedBlock)
+				if err == nil {
+					err = verify(block)
+				}
+				entry.block, entry.err = block, err
+				close(entry.done)
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(work)
+		defer close(ordered)
+
+		for {
+			serializedBlock, err := readBlockFileEntry(r, net)
+			if serializedBlock == nil && err == nil {
+				return
+			}
+
+			entry := &blockFileEntry{
+				serializedBlock: serializedBlock,
+				err:             err,
+				done:            make(chan struct{}),
+			}
+			if err != nil {
+				close(entry.done)
+			}
+
+			// The entry is queued for processing before it is handed
+			// to the workers so it can never be verified without
+			// being waited on in order.
+			select {
+			case ordered <- entry:
+			case <-quit:
+				return
+			}
+			if err != nil {
+				return
+			}
+			select {
+			case work <- entry:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	return ordered, wg.Wait
+}
+
+// importBlockFile imports the blocks from the block file at the passed path
+// into the chain.  The context-free checks of the blocks, which include the
+// expensive proof of work and signet solution, are performed in parallel
+// before each block is processed in order by the chain without repeating
+// them.
+//
+// Blocks the chain already has are skipped.  The blocks in the file must be in
+// order, so a block which does not connect to a known block is an error.
+func importBlockFile(chain *blockchain.BlockChain,
+	timeSource blockchain.MedianTimeSource, path string,
+	interrupt <-chan struct{}) error {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// The signet solution is checked along with the proof of work since
+	// the chain skips both for the imported blocks.
+	powLimit := activeNetParams.PowLimit
+	verify := func(block *ltcutil.Block) error {
+		err := blockchain.CheckBlockSanity(block, powLimit, timeSource)
+		if err != nil {
+			return err
+		}
+		return blockchain.CheckSignetBlockSolution(block,
+			activeNetParams.Params)
+	}
+	quit := make(chan struct{})
+	entries, wait := preverifyBlocks(bufio.NewReader(f),
+		activeNetParams.Net, verify, quit)
+	defer wait()
+	defer close(quit)
+
+	ltcdLog.Infof("Importing blocks from %s", path)
+	var processed, imported int64
+	lastLog := time.Now()
+	for entry := range entries {
+		select {
+		case <-entry.done:
+		case <-interrupt:
+			return fmt.Errorf("block import from %s interrupted", path)
+		}
+		processed++
+		if entry.serializedBlock == nil {
+			return fmt.Errorf("unable to read block %d from %s: %v",
+				processed, path, entry.err)
+		}
+		if entry.err != nil {
+			return fmt.Errorf("block %d from %s is invalid: %v",
+				processed, path, entry.err)
+		}
+
+		block := entry.block
+		blockHash := block.Hash()
+		exists, err := chain.HaveBlock(blockHash)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		prevHash := &block.MsgBlock().Header.PrevBlock
+		if *prevHash != (chainhash.Hash{}) {
+			exists, err := chain.HaveBlock(prevHash)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("block %v from %s does not link "+
+					"to the available block chain", blockHash,
+					path)
+			}
+		}
+
+		_, isOrphan, err := chain.ProcessBlock(block,
+			blockchain.BFNoPoWCheck)
+		if err != nil {
+			return fmt.Errorf("unable to process block %v from %s: %v",
+				blockHash, path, err)
+		}
+		if isOrphan {
+			return fmt.Errorf("block %v from %s is an orphan",
+				blockHash, path)
+		}
+		imported++
+
+		if now := time.Now(); now.Sub(lastLog) >= blockFileProgressInterval {
+			ltcdLog.Infof("Imported %d blocks from %s (height %d)",
+				imported, path, chain.BestSnapshot().Height)
+			lastLog = now
+		}
+	}
+
+	ltcdLog.Infof("Imported %d of %d blocks from %s", imported, processed,
+		path)
+	return nil
+}
+
+// importBlockFiles imports the blocks from each of the passed block files in
+// order, stopping at the first file which fails to import.  It must be run as
+// a goroutine.
+func importBlockFiles(chain *blockchain.BlockChain,
+	timeSource blockchain.MedianTimeSource, paths []string,
+	interrupt <-chan struct{}) {
+
+	for _, path := range paths {
+		err := importBlockFile(chain, timeSource, path, interrupt)
+		if err != nil {
+			ltcdLog.Errorf("Block import failed: %v", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestBlockFile ensures blocks written in the block file format are read back
+// in order and pre-verified.
+func TestBlockFile(t *testing.T) {
+	genesisBlocks := []*wire.MsgBlock{
+		chaincfg.MainNetParams.GenesisBlock,
+		chaincfg.TestNet4Params.GenesisBlock,
+		chaincfg.RegressionNetParams.GenesisBlock,
+		chaincfg.SimNetParams.GenesisBlock,
+	}
+
+	var buf bytes.Buffer
+	var blocks []*ltcutil.Block
+	for i := 0; i < 50; i++ {
+		block := ltcutil.NewBlock(genesisBlocks[i%len(genesisBlocks)])
+		serializedBlock, err := block.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes: unexpected error: %v", err)
+		}
+		err = writeBlockFileEntry(&buf, wire.MainNet, serializedBlock)
+		if err != nil {
+			t.Fatalf("writeBlockFileEntry: unexpected error: %v", err)
+		}
+		blocks = append(blocks, block)
+	}
+	fileBytes := buf.Bytes()
+
+	// The blocks are returned in order with the result of their
+	// verification.
+	badHash := chaincfg.SimNetParams.GenesisHash
+	errBad := errors.New("bad block")
+	verify := func(block *ltcutil.Block) error {
+		if block.Hash().IsEqual(badHash) {
+			return errBad
+		}
+		return nil
+	}
+	quit := make(chan struct{})
+	entries, wait := preverifyBlocks(bytes.NewReader(fileBytes),
+		wire.MainNet, verify, quit)
+	var i int
+	for entry := range entries {
+		<-entry.done
+		if !entry.block.Hash().IsEqual(blocks[i].Hash()) {
+			t.Fatalf("entry %d: got block %v, want %v", i,
+				entry.block.Hash(), blocks[i].Hash())
+		}
+		wantErr := error(nil)
+		if blocks[i].Hash().IsEqual(badHash) {
+			wantErr = errBad
+		}
+		if entry.err != wantErr {
+			t.Fatalf("entry %d: got error %v, want %v", i, entry.err,
+				wantErr)
+		}
+		i++
+	}
+	close(quit)
+	wait()
+	if i != len(blocks) {
+		t.Fatalf("read %d blocks, want %d", i, len(blocks))
+	}
+
+	// A file for another network is reported as a read error.
+	quit = make(chan struct{})
+	entries, wait = preverifyBlocks(bytes.NewReader(fileBytes),
+		wire.TestNet4, verify, quit)
+	entry := <-entries
+	<-entry.done
+	if entry.serializedBlock != nil || entry.err == nil {
+		t.Fatalf("network mismatch not reported")
+	}
+	if _, ok := <-entries; ok {
+		t.Fatalf("entries returned after a read error")
+	}
+	close(quit)
+	wait()
+
+	// A truncated file is reported as a read error after the complete
+	// blocks.
+	quit = make(chan struct{})
+	entries, wait = preverifyBlocks(bytes.NewReader(fileBytes[:len(fileBytes)-1]),
+		wire.MainNet, verify, quit)
+	i = 0
+	for entry := range entries {
+		<-entry.done
+		if entry.serializedBlock == nil {
+			if i != len(blocks)-1 || entry.err == nil {
+				t.Fatalf("entry %d: unexpected read error %v", i,
+					entry.err)
+			}
+		}
+		i++
+	}
+	close(quit)
+	wait()
+	if i != len(blocks) {
+		t.Fatalf("read %d entries from truncated file, want %d", i,
+			len(blocks))
+	}
+
+	// Stopping early does not leak the workers.
+	quit = make(chan struct{})
+	entries, wait = preverifyBlocks(bytes.NewReader(fileBytes),
+		wire.MainNet, verify, quit)
+	<-entries
+	close(quit)
+	wait()
+}
@@ -5,6 +5,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -148,6 +149,14 @@ func ltcdMain(serverChan chan<- *server) error {
 
 		return nil
 	}
+	if cfg.DropWatchOnly {
+		if err := indexers.DropWatchIndex(db, interrupt); err != nil {
+			ltcdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
 	if cfg.DropCfIndex {
 		if err := indexers.DropCfIndex(db, interrupt); err != nil {
 			ltcdLog.Errorf("%v", err)
@@ -157,6 +166,18 @@ func ltcdMain(serverChan chan<- *server) error {
 		return nil
 	}
 
+	// Export the main chain to a block file and exit if requested.
+	if cfg.ExportBlocks != "" {
+		err := exportBlocks(db, cfg.ExportBlocks, cfg.ExportHeight,
+			interrupt)
+		if err != nil {
+			ltcdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
+
 	// Check if the database had previously been pruned.  If it had been, it's
 	// not possible to newly generate the tx index and addr index.
 	var beenPruned bool
@@ -245,6 +266,9 @@ func ltcdMain(serverChan chan<- *server) error {
 	unveilx(cfg.RPCKey, "rwc")
 	unveilx(cfg.RPCCert, "rwc")
 	unveilx(cfg.DataDir, "rwc")
+	for _, path := range cfg.LoadBlocks {
+		unveilx(path, "r")
+	}
 
 	// drop unveil and tty
 	pledgex("stdio rpath wpath cpath flock dns inet")
@@ -260,8 +284,21 @@ func ltcdMain(serverChan chan<- *server) error {
 	}
 	defer func() {
 		ltcdLog.Infof("Gracefully shutting down the server...")
-		server.Stop()
-		server.WaitForShutdown()
+
+		// Bound the time spent waiting for the server so the database is
+		// still flushed and closed before a process supervisor gives up
+		// and kills the process.
+		ctx := context.Background()
+		if cfg.ShutdownTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cfg.ShutdownTimeout)
+			defer cancel()
+		}
+		if err := server.Shutdown(ctx); err != nil {
+			srvrLog.Warnf("Server did not shut down within %v (%v) -- "+
+				"closing the database anyway", cfg.ShutdownTimeout, err)
+			return
+		}
 		srvrLog.Infof("Server shutdown complete")
 	}()
 	server.Start()
@@ -269,6 +306,14 @@ func ltcdMain(serverChan chan<- *server) error {
 		serverChan <- server
 	}
 
+	// Import blocks from the block files directly into the chain when
+	// requested.  This happens alongside the normal operation of the server
+	// so the node remains available while the blocks are processed.
+	if len(cfg.LoadBlocks) > 0 {
+		go importBlockFiles(server.chain, server.timeSource,
+			cfg.LoadBlocks, interrupt)
+	}
+
 	// Wait until the interrupt signal is received from an OS signal or
 	// shutdown is requested through one of the subsystems such as the RPC
 	// server.
@@ -8,6 +8,8 @@
 
 package btcjson
 
+import "encoding/json"
+
 // NodeSubCmd defines the type used in the addnode JSON-RPC command for the
 // sub command field.
 type NodeSubCmd string
@@ -44,6 +46,21 @@ func NewNodeCmd(subCmd NodeSubCmd, target string, connectSubCmd *string) *NodeCm
 	}
 }
 
+// CancelOperationCmd defines the canceloperation JSON-RPC command.  This
+// command is not a standard Litecoin command.  It is an extension for ltcd.
+type CancelOperationCmd struct {
+	ID string
+}
+
+// NewCancelOperationCmd returns a new CancelOperationCmd which can be used to
+// issue a canceloperation JSON-RPC command.  This command is not a standard
+// Litecoin command.  It is an extension for ltcd.
+func NewCancelOperationCmd(id string) *CancelOperationCmd {
+	return &CancelOperationCmd{
+		ID: id,
+	}
+}
+
 // DebugLevelCmd defines the debuglevel JSON-RPC command.  This command is not a
 // standard Litecoin command.  It is an extension for ltcd.
 type DebugLevelCmd struct {
@@ -59,6 +76,28 @@ func NewDebugLevelCmd(levelSpec string) *DebugLevelCmd {
 	}
 }
 
+// GenerateBlockCmd defines the generateblock JSON-RPC command.
+type GenerateBlockCmd struct {
+	Output       string
+	Transactions []string
+	Timestamp    *int64
+}
+
+// NewGenerateBlockCmd returns a new instance which can be used to issue a
+// generateblock JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGenerateBlockCmd(output string, transactions []string,
+	timestamp *int64) *GenerateBlockCmd {
+
+	return &GenerateBlockCmd{
+		Output:       output,
+		Transactions: transactions,
+		Timestamp:    timestamp,
+	}
+}
+
 // GenerateToAddressCmd defines the generatetoaddress JSON-RPC command.
 type GenerateToAddressCmd struct {
 	NumBlocks int64
@@ -98,6 +137,33 @@ func NewGetBestBlockCmd() *GetBestBlockCmd {
 	return &GetBestBlockCmd{}
 }
 
+// GetBlockSubsidyCmd defines the getblocksubsidy JSON-RPC command.  This
+// command is not a standard Litecoin command.  It is an extension for ltcd.
+type GetBlockSubsidyCmd struct {
+	Height *int32
+}
+
+// NewGetBlockSubsidyCmd returns a new instance which can be used to issue a
+// getblocksubsidy JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetBlockSubsidyCmd(height *int32) *GetBlockSubsidyCmd {
+	return &GetBlockSubsidyCmd{
+		Height: height,
+	}
+}
+
+// GetChainParamsCmd defines the getchainparams JSON-RPC command.  This command
+// is not a standard Litecoin command.  It is an extension for ltcd.
+type GetChainParamsCmd struct{}
+
+// NewGetChainParamsCmd returns a new instance which can be used to issue a
+// getchainparams JSON-RPC command.
+func NewGetChainParamsCmd() *GetChainParamsCmd {
+	return &GetChainParamsCmd{}
+}
+
 // GetCurrentNetCmd defines the getcurrentnet JSON-RPC command.
 type GetCurrentNetCmd struct{}
 
@@ -107,6 +173,26 @@ func NewGetCurrentNetCmd() *GetCurrentNetCmd {
 	return &GetCurrentNetCmd{}
 }
 
+// GetDifficultyHistoryCmd defines the getdifficultyhistory JSON-RPC command.
+// This command is not a standard Litecoin command.  It is an extension for
+// ltcd.
+type GetDifficultyHistoryCmd struct {
+	StartHeight int32
+	EndHeight   *int32
+}
+
+// NewGetDifficultyHistoryCmd returns a new instance which can be used to issue
+// a getdifficultyhistory JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetDifficultyHistoryCmd(startHeight int32, endHeight *int32) *GetDifficultyHistoryCmd {
+	return &GetDifficultyHistoryCmd{
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+	}
+}
+
 // GetHeadersCmd defines the getheaders JSON-RPC command.
 //
 // NOTE: This is a btcsuite extension ported from
@@ -128,6 +214,175 @@ func NewGetHeadersCmd(blockLocators []string, hashStop string) *GetHeadersCmd {
 	}
 }
 
+// GetMisbehavingLogCmd defines the getmisbehavinglog JSON-RPC command.  This
+// command is not a standard Litecoin command.  It is an extension for ltcd.
+type GetMisbehavingLogCmd struct {
+	Count *int `jsonrpcdefault:"100"`
+}
+
+// NewGetMisbehavingLogCmd returns a new instance which can be used to issue a
+// getmisbehavinglog JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMisbehavingLogCmd(count *int) *GetMisbehavingLogCmd {
+	return &GetMisbehavingLogCmd{
+		Count: count,
+	}
+}
+
+// GetOperationStatusCmd defines the getoperationstatus JSON-RPC command.  This
+// command is not a standard Litecoin command.  It is an extension for ltcd.
+type GetOperationStatusCmd struct {
+	ID string
+}
+
+// NewGetOperationStatusCmd returns a new GetOperationStatusCmd which can be
+// used to issue a getoperationstatus JSON-RPC command.  This command is not a
+// standard Litecoin command.  It is an extension for ltcd.
+func NewGetOperationStatusCmd(id string) *GetOperationStatusCmd {
+	return &GetOperationStatusCmd{
+		ID: id,
+	}
+}
+
+// ImportWatchOnlyCmd defines the importwatchonly JSON-RPC command.  This
+// command is not a standard Litecoin command.  It is an extension for ltcd.
+type ImportWatchOnlyCmd struct {
+	Scripts     []string
+	Rescan      *bool  `jsonrpcdefault:"true"`
+	StartHeight *int32 `jsonrpcdefault:"0"`
+	Range       *DescriptorRange
+}
+
+// NewImportWatchOnlyCmd returns a new instance which can be used to issue an
+// importwatchonly JSON-RPC command.  Each script is an address, a hex-encoded
+// public key script or an output descriptor.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewImportWatchOnlyCmd(scripts []string, rescan *bool, startHeight *int32,
+	descriptorRange *DescriptorRange) *ImportWatchOnlyCmd {
+
+	return &ImportWatchOnlyCmd{
+		Scripts:     scripts,
+		Rescan:      rescan,
+		StartHeight: startHeight,
+		Range:       descriptorRange,
+	}
+}
+
+// ListUnspentWatchOnlyCmd defines the listunspentwatchonly JSON-RPC command.
+// This command is not a standard Litecoin command.  It is an extension for
+// ltcd.
+type ListUnspentWatchOnlyCmd struct {
+	MinConf   *int `jsonrpcdefault:"1"`
+	MaxConf   *int `jsonrpcdefault:"9999999"`
+	Addresses *[]string
+}
+
+// NewListUnspentWatchOnlyCmd returns a new instance which can be used to issue
+// a listunspentwatchonly JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewListUnspentWatchOnlyCmd(minConf, maxConf *int,
+	addresses *[]string) *ListUnspentWatchOnlyCmd {
+
+	return &ListUnspentWatchOnlyCmd{
+		MinConf:   minConf,
+		MaxConf:   maxConf,
+		Addresses: addresses,
+	}
+}
+
+// RescanBlockchainCmd defines the rescanblockchain JSON-RPC command.  This
+// command is not a standard Litecoin command.  It is an extension for ltcd.
+type RescanBlockchainCmd struct {
+	StartHeight *int32 `jsonrpcdefault:"0"`
+	StopHeight  *int32
+	Scripts     *[]string
+	OutPoints   *[]OutPoint
+}
+
+// NewRescanBlockchainCmd returns a new instance which can be used to issue a
+// rescanblockchain JSON-RPC command.  Each script is an address, a hex-encoded
+// public key script or an output descriptor.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewRescanBlockchainCmd(startHeight, stopHeight *int32, scripts *[]string,
+	outPoints *[]OutPoint) *RescanBlockchainCmd {
+
+	return &RescanBlockchainCmd{
+		StartHeight: startHeight,
+		StopHeight:  stopHeight,
+		Scripts:     scripts,
+		OutPoints:   outPoints,
+	}
+}
+
+// SetLogLevelCmd defines the setloglevel JSON-RPC command.  This command is not
+// a standard Litecoin command.  It is an extension for ltcd.
+type SetLogLevelCmd struct {
+	Subsystem string
+	Level     string
+}
+
+// NewSetLogLevelCmd returns a new SetLogLevelCmd which can be used to issue a
+// setloglevel JSON-RPC command.  This command is not a standard Litecoin
+// command.  It is an extension for ltcd.
+func NewSetLogLevelCmd(subsystem, level string) *SetLogLevelCmd {
+	return &SetLogLevelCmd{
+		Subsystem: subsystem,
+		Level:     level,
+	}
+}
+
+// SetPolicyOpts houses the runtime settings the setpolicy JSON-RPC command
+// changes.  The settings which are nil are left unchanged.
+type SetPolicyOpts struct {
+	MinRelayTxFee *float64 `json:"minrelaytxfee,omitempty"`
+	MaxOrphanTxs  *int     `json:"maxorphantx,omitempty"`
+	RelayNonStd   *bool    `json:"relaynonstd,omitempty"`
+	MaxPeers      *int     `json:"maxpeers,omitempty"`
+}
+
+// SetPolicyCmd defines the setpolicy JSON-RPC command.  This command is not a
+// standard Litecoin command.  It is an extension for ltcd.
+type SetPolicyCmd struct {
+	Options *SetPolicyOpts
+}
+
+// NewSetPolicyCmd returns a new SetPolicyCmd which can be used to issue a
+// setpolicy JSON-RPC command.  The current settings are returned without
+// changing any of them when options is nil.  This command is not a standard
+// Litecoin command.  It is an extension for ltcd.
+func NewSetPolicyCmd(options *SetPolicyOpts) *SetPolicyCmd {
+	return &SetPolicyCmd{
+		Options: options,
+	}
+}
+
+// StartOperationCmd defines the startoperation JSON-RPC command, which runs
+// a long-running command in the background.  This command is not a standard
+// Litecoin command.  It is an extension for ltcd.
+type StartOperationCmd struct {
+	Method string
+	Params *[]json.RawMessage
+}
+
+// NewStartOperationCmd returns a new StartOperationCmd which can be used to
+// issue a startoperation JSON-RPC command running the passed method with the
+// passed parameters.  This command is not a standard Litecoin command.  It is
+// an extension for ltcd.
+func NewStartOperationCmd(method string, params *[]json.RawMessage) *StartOperationCmd {
+	return &StartOperationCmd{
+		Method: method,
+		Params: params,
+	}
+}
+
 // VersionCmd defines the version JSON-RPC command.
 //
 // NOTE: This is a btcsuite extension ported from
@@ -145,12 +400,25 @@ func init() {
 	// No special flags for commands in this file.
 	flags := UsageFlag(0)
 
+	MustRegisterCmd("canceloperation", (*CancelOperationCmd)(nil), flags)
 	MustRegisterCmd("debuglevel", (*DebugLevelCmd)(nil), flags)
 	MustRegisterCmd("node", (*NodeCmd)(nil), flags)
 	MustRegisterCmd("generate", (*GenerateCmd)(nil), flags)
+	MustRegisterCmd("generateblock", (*GenerateBlockCmd)(nil), flags)
 	MustRegisterCmd("generatetoaddress", (*GenerateToAddressCmd)(nil), flags)
 	MustRegisterCmd("getbestblock", (*GetBestBlockCmd)(nil), flags)
+	MustRegisterCmd("getblocksubsidy", (*GetBlockSubsidyCmd)(nil), flags)
+	MustRegisterCmd("getchainparams", (*GetChainParamsCmd)(nil), flags)
 	MustRegisterCmd("getcurrentnet", (*GetCurrentNetCmd)(nil), flags)
+	MustRegisterCmd("getdifficultyhistory", (*GetDifficultyHistoryCmd)(nil), flags)
 	MustRegisterCmd("getheaders", (*GetHeadersCmd)(nil), flags)
+	MustRegisterCmd("getmisbehavinglog", (*GetMisbehavingLogCmd)(nil), flags)
+	MustRegisterCmd("getoperationstatus", (*GetOperationStatusCmd)(nil), flags)
+	MustRegisterCmd("importwatchonly", (*ImportWatchOnlyCmd)(nil), flags)
+	MustRegisterCmd("listunspentwatchonly", (*ListUnspentWatchOnlyCmd)(nil), flags)
+	MustRegisterCmd("rescanblockchain", (*RescanBlockchainCmd)(nil), flags)
+	MustRegisterCmd("setloglevel", (*SetLogLevelCmd)(nil), flags)
+	MustRegisterCmd("setpolicy", (*SetPolicyCmd)(nil), flags)
+	MustRegisterCmd("startoperation", (*StartOperationCmd)(nil), flags)
 	MustRegisterCmd("version", (*VersionCmd)(nil), flags)
 }
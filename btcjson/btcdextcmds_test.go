@@ -43,6 +43,55 @@ func TestBtcdExtCmds(t *testing.T) {
 				LevelSpec: "trace",
 			},
 		},
+		{
+			name: "setloglevel",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("setloglevel", "CHAN", "debug")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSetLogLevelCmd("CHAN", "debug")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setloglevel","params":["CHAN","debug"],"id":1}`,
+			unmarshalled: &btcjson.SetLogLevelCmd{
+				Subsystem: "CHAN",
+				Level:     "debug",
+			},
+		},
+		{
+			name: "setpolicy",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("setpolicy")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSetPolicyCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setpolicy","params":[],"id":1}`,
+			unmarshalled: &btcjson.SetPolicyCmd{
+				Options: nil,
+			},
+		},
+		{
+			name: "setpolicy options",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("setpolicy", btcjson.SetPolicyOpts{
+					MinRelayTxFee: btcjson.Float64(0.0002),
+					MaxPeers:      btcjson.Int(50),
+				})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSetPolicyCmd(&btcjson.SetPolicyOpts{
+					MinRelayTxFee: btcjson.Float64(0.0002),
+					MaxPeers:      btcjson.Int(50),
+				})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setpolicy","params":[{"minrelaytxfee":0.0002,"maxpeers":50}],"id":1}`,
+			unmarshalled: &btcjson.SetPolicyCmd{
+				Options: &btcjson.SetPolicyOpts{
+					MinRelayTxFee: btcjson.Float64(0.0002),
+					MaxPeers:      btcjson.Int(50),
+				},
+			},
+		},
 		{
 			name: "node",
 			newCmd: func() (interface{}, error) {
@@ -114,6 +163,39 @@ func TestBtcdExtCmds(t *testing.T) {
 				NumBlocks: 1,
 			},
 		},
+		{
+			name: "generateblock",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("generateblock", "1Address",
+					[]string{"0100"})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGenerateBlockCmd("1Address",
+					[]string{"0100"}, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"generateblock","params":["1Address",["0100"]],"id":1}`,
+			unmarshalled: &btcjson.GenerateBlockCmd{
+				Output:       "1Address",
+				Transactions: []string{"0100"},
+			},
+		},
+		{
+			name: "generateblock optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("generateblock", "1Address",
+					[]string{}, 1600000000)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGenerateBlockCmd("1Address",
+					[]string{}, btcjson.Int64(1600000000))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"generateblock","params":["1Address",[],1600000000],"id":1}`,
+			unmarshalled: &btcjson.GenerateBlockCmd{
+				Output:       "1Address",
+				Transactions: []string{},
+				Timestamp:    btcjson.Int64(1600000000),
+			},
+		},
 		{
 			name: "generatetoaddress",
 			newCmd: func() (interface{}, error) {
@@ -143,6 +225,41 @@ func TestBtcdExtCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getbestblock","params":[],"id":1}`,
 			unmarshalled: &btcjson.GetBestBlockCmd{},
 		},
+		{
+			name: "getblocksubsidy",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getblocksubsidy")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetBlockSubsidyCmd(nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getblocksubsidy","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetBlockSubsidyCmd{},
+		},
+		{
+			name: "getblocksubsidy optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getblocksubsidy", 840000)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetBlockSubsidyCmd(btcjson.Int32(840000))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocksubsidy","params":[840000],"id":1}`,
+			unmarshalled: &btcjson.GetBlockSubsidyCmd{
+				Height: btcjson.Int32(840000),
+			},
+		},
+		{
+			name: "getchainparams",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getchainparams")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetChainParamsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getchainparams","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetChainParamsCmd{},
+		},
 		{
 			name: "getcurrentnet",
 			newCmd: func() (interface{}, error) {
@@ -154,6 +271,34 @@ func TestBtcdExtCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getcurrentnet","params":[],"id":1}`,
 			unmarshalled: &btcjson.GetCurrentNetCmd{},
 		},
+		{
+			name: "getdifficultyhistory",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getdifficultyhistory", 100)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetDifficultyHistoryCmd(100, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getdifficultyhistory","params":[100],"id":1}`,
+			unmarshalled: &btcjson.GetDifficultyHistoryCmd{
+				StartHeight: 100,
+			},
+		},
+		{
+			name: "getdifficultyhistory optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getdifficultyhistory", 100, 200)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetDifficultyHistoryCmd(100,
+					btcjson.Int32(200))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getdifficultyhistory","params":[100,200],"id":1}`,
+			unmarshalled: &btcjson.GetDifficultyHistoryCmd{
+				StartHeight: 100,
+				EndHeight:   btcjson.Int32(200),
+			},
+		},
 		{
 			name: "getheaders",
 			newCmd: func() (interface{}, error) {
@@ -194,6 +339,175 @@ func TestBtcdExtCmds(t *testing.T) {
 				HashStop: "000000000000000000ba33b33e1fad70b69e234fc24414dd47113bff38f523f7",
 			},
 		},
+		{
+			name: "importwatchonly",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("importwatchonly", []string{"0014abcd"})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewImportWatchOnlyCmd([]string{"0014abcd"},
+					nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"importwatchonly","params":[["0014abcd"]],"id":1}`,
+			unmarshalled: &btcjson.ImportWatchOnlyCmd{
+				Scripts:     []string{"0014abcd"},
+				Rescan:      btcjson.Bool(true),
+				StartHeight: btcjson.Int32(0),
+			},
+		},
+		{
+			name: "importwatchonly optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("importwatchonly",
+					[]string{"wpkh(xpub/0/*)"}, false, 100,
+					btcjson.DescriptorRange{Value: []int{0, 9}})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewImportWatchOnlyCmd(
+					[]string{"wpkh(xpub/0/*)"}, btcjson.Bool(false),
+					btcjson.Int32(100), &btcjson.DescriptorRange{
+						Value: []int{0, 9},
+					})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"importwatchonly","params":[["wpkh(xpub/0/*)"],false,100,[0,9]],"id":1}`,
+			unmarshalled: &btcjson.ImportWatchOnlyCmd{
+				Scripts:     []string{"wpkh(xpub/0/*)"},
+				Rescan:      btcjson.Bool(false),
+				StartHeight: btcjson.Int32(100),
+				Range:       &btcjson.DescriptorRange{Value: []int{0, 9}},
+			},
+		},
+		{
+			name: "listunspentwatchonly",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("listunspentwatchonly")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewListUnspentWatchOnlyCmd(nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"listunspentwatchonly","params":[],"id":1}`,
+			unmarshalled: &btcjson.ListUnspentWatchOnlyCmd{
+				MinConf: btcjson.Int(1),
+				MaxConf: btcjson.Int(9999999),
+			},
+		},
+		{
+			name: "listunspentwatchonly optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("listunspentwatchonly", 0, 6,
+					[]string{"1Address"})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewListUnspentWatchOnlyCmd(btcjson.Int(0),
+					btcjson.Int(6), &[]string{"1Address"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"listunspentwatchonly","params":[0,6,["1Address"]],"id":1}`,
+			unmarshalled: &btcjson.ListUnspentWatchOnlyCmd{
+				MinConf:   btcjson.Int(0),
+				MaxConf:   btcjson.Int(6),
+				Addresses: &[]string{"1Address"},
+			},
+		},
+		{
+			name: "rescanblockchain",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("rescanblockchain")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewRescanBlockchainCmd(nil, nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"rescanblockchain","params":[],"id":1}`,
+			unmarshalled: &btcjson.RescanBlockchainCmd{
+				StartHeight: btcjson.Int32(0),
+			},
+		},
+		{
+			name: "rescanblockchain optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("rescanblockchain", 100, 200,
+					[]string{"0014abcd"}, `[{"hash":"123","index":1}]`)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewRescanBlockchainCmd(btcjson.Int32(100),
+					btcjson.Int32(200), &[]string{"0014abcd"},
+					&[]btcjson.OutPoint{{Hash: "123", Index: 1}})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"rescanblockchain","params":[100,200,["0014abcd"],[{"hash":"123","index":1}]],"id":1}`,
+			unmarshalled: &btcjson.RescanBlockchainCmd{
+				StartHeight: btcjson.Int32(100),
+				StopHeight:  btcjson.Int32(200),
+				Scripts:     &[]string{"0014abcd"},
+				OutPoints:   &[]btcjson.OutPoint{{Hash: "123", Index: 1}},
+			},
+		},
+		{
+			name: "startoperation",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("startoperation", "verifychain",
+					`[3,100]`)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewStartOperationCmd("verifychain",
+					&[]json.RawMessage{[]byte("3"), []byte("100")})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"startoperation","params":["verifychain",[3,100]],"id":1}`,
+			unmarshalled: &btcjson.StartOperationCmd{
+				Method: "verifychain",
+				Params: &[]json.RawMessage{[]byte("3"), []byte("100")},
+			},
+		},
+		{
+			name: "getmisbehavinglog",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getmisbehavinglog")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetMisbehavingLogCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmisbehavinglog","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetMisbehavingLogCmd{
+				Count: btcjson.Int(100),
+			},
+		},
+		{
+			name: "getmisbehavinglog optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getmisbehavinglog", 10)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetMisbehavingLogCmd(btcjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmisbehavinglog","params":[10],"id":1}`,
+			unmarshalled: &btcjson.GetMisbehavingLogCmd{
+				Count: btcjson.Int(10),
+			},
+		},
+		{
+			name: "getoperationstatus",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getoperationstatus", "1")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetOperationStatusCmd("1")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getoperationstatus","params":["1"],"id":1}`,
+			unmarshalled: &btcjson.GetOperationStatusCmd{
+				ID: "1",
+			},
+		},
+		{
+			name: "canceloperation",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("canceloperation", "1")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewCancelOperationCmd("1")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"canceloperation","params":["1"],"id":1}`,
+			unmarshalled: &btcjson.CancelOperationCmd{
+				ID: "1",
+			},
+		},
 		{
 			name: "version",
 			newCmd: func() (interface{}, error) {
@@ -18,3 +18,171 @@ type VersionResult struct {
 	Prerelease    string `json:"prerelease"`
 	BuildMetadata string `json:"buildmetadata"`
 }
+
+// GenerateBlockResult models the data returned from the generateblock command.
+type GenerateBlockResult struct {
+	Hash string `json:"hash"`
+}
+
+// GetBlockSubsidyResult models the data returned from the getblocksubsidy
+// command.
+type GetBlockSubsidyResult struct {
+	Height  int32   `json:"height"`
+	Subsidy float64 `json:"subsidy"`
+}
+
+// GetDifficultyHistoryResult models an entry of the data returned from the
+// getdifficultyhistory command.
+type GetDifficultyHistoryResult struct {
+	Height     int32   `json:"height"`
+	Hash       string  `json:"hash"`
+	Bits       string  `json:"bits"`
+	Target     string  `json:"target"`
+	Difficulty float64 `json:"difficulty"`
+	Time       int64   `json:"time"`
+	SolveTime  int64   `json:"solvetime"`
+	Algorithm  string  `json:"algorithm"`
+}
+
+// ChainParamsCheckpoint models a checkpoint included in the getchainparams
+// response.
+type ChainParamsCheckpoint struct {
+	Height int32  `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// ChainParamsDeployment models a BIP0009 soft-fork deployment included in the
+// getchainparams response.  The start time and timeout are zero for
+// deployments which do not start or end at a median time.
+type ChainParamsDeployment struct {
+	Name                string `json:"name"`
+	Bit                 uint8  `json:"bit"`
+	StartTime           int64  `json:"starttime"`
+	Timeout             int64  `json:"timeout"`
+	MinActivationHeight uint32 `json:"minactivationheight"`
+	Threshold           uint32 `json:"threshold"`
+}
+
+// GetChainParamsResult models the data returned from the getchainparams
+// command.  Durations are expressed in seconds and byte identifiers as their
+// numeric values so clients can configure themselves for the network the
+// server is running on.
+type GetChainParamsResult struct {
+	Name                          string                  `json:"name"`
+	Net                           uint32                  `json:"net"`
+	DefaultPort                   string                  `json:"defaultport"`
+	DNSSeeds                      []string                `json:"dnsseeds"`
+	GenesisHash                   string                  `json:"genesishash"`
+	PowLimit                      string                  `json:"powlimit"`
+	PowLimitBits                  string                  `json:"powlimitbits"`
+	PoWNoRetargeting              bool                    `json:"pownoretargeting"`
+	BIP0034Height                 int32                   `json:"bip34height"`
+	BIP0065Height                 int32                   `json:"bip65height"`
+	BIP0066Height                 int32                   `json:"bip66height"`
+	AllowLegacyBlockVersions      bool                    `json:"allowlegacyblockversions"`
+	EnforceStrictDER              bool                    `json:"enforcestrictder"`
+	AllowNonStrictDER             bool                    `json:"allownonstrictder"`
+	CoinbaseMaturity              uint16                  `json:"coinbasematurity"`
+	MwebPegoutMaturity            uint16                  `json:"mwebpegoutmaturity"`
+	SubsidyReductionInterval      int32                   `json:"subsidyreductioninterval"`
+	TargetTimespan                int64                   `json:"targettimespan"`
+	TargetTimePerBlock            int64                   `json:"targettimeperblock"`
+	RetargetAdjustmentFactor      int64                   `json:"retargetadjustmentfactor"`
+	ReduceMinDifficulty           bool                    `json:"reducemindifficulty"`
+	MinDiffReductionTime          int64                   `json:"mindiffreductiontime"`
+	LWMAHeight                    int32                   `json:"lwmaheight"`
+	LWMAFixHeight                 int32                   `json:"lwmafixheight"`
+	LWMAWindow                    int64                   `json:"lwmawindow"`
+	ASERTHeight                   int32                   `json:"asertheight"`
+	ASERTHalfLife                 int64                   `json:"aserthalflife"`
+	ASERTAnchorBits               string                  `json:"asertanchorbits"`
+	MinimumChainWork              string                  `json:"minimumchainwork"`
+	SignetChallenge               string                  `json:"signetchallenge,omitempty"`
+	Checkpoints                   []ChainParamsCheckpoint `json:"checkpoints"`
+	AdvisoryCheckpoints           bool                    `json:"advisorycheckpoints"`
+	RuleChangeActivationThreshold uint32                  `json:"rulechangeactivationthreshold"`
+	MinerConfirmationWindow       uint32                  `json:"minerconfirmationwindow"`
+	Deployments                   []ChainParamsDeployment `json:"deployments"`
+	RelayNonStdTxs                bool                    `json:"relaynonstdtxs"`
+	Bech32HRPSegwit               string                  `json:"bech32hrpsegwit"`
+	Bech32HRPMweb                 string                  `json:"bech32hrpmweb"`
+	PubKeyHashAddrID              byte                    `json:"pubkeyhashaddrid"`
+	ScriptHashAddrID              byte                    `json:"scripthashaddrid"`
+	PrivateKeyID                  byte                    `json:"privatekeyid"`
+	WitnessPubKeyHashAddrID       byte                    `json:"witnesspubkeyhashaddrid"`
+	WitnessScriptHashAddrID       byte                    `json:"witnessscripthashaddrid"`
+	HDPrivateKeyID                string                  `json:"hdprivatekeyid"`
+	HDPublicKeyID                 string                  `json:"hdpublickeyid"`
+	HDCoinType                    uint32                  `json:"hdcointype"`
+}
+
+// ListUnspentWatchOnlyResult models a data object which is returned by the
+// listunspentwatchonly command for each unspent output paying to a watched
+// script.
+type ListUnspentWatchOnlyResult struct {
+	TxID          string  `json:"txid"`
+	Vout          uint32  `json:"vout"`
+	Address       string  `json:"address,omitempty"`
+	ScriptPubKey  string  `json:"scriptPubKey"`
+	Amount        float64 `json:"amount"`
+	Confirmations int64   `json:"confirmations"`
+	Coinbase      bool    `json:"coinbase"`
+}
+
+// MisbehaviorEventResult models an event returned by the getmisbehavinglog
+// command.  The kind of the event is either violation, ban or disconnect.
+type MisbehaviorEventResult struct {
+	Time     int64  `json:"time"`
+	Kind     string `json:"kind"`
+	ID       int32  `json:"id"`
+	Addr     string `json:"addr"`
+	Inbound  bool   `json:"inbound"`
+	BanScore uint32 `json:"banscore"`
+	Reason   string `json:"reason"`
+	Command  string `json:"command,omitempty"`
+	MsgHex   string `json:"msghex,omitempty"`
+}
+
+// OperationStatusResult models the data returned by the getoperationstatus
+// and canceloperation commands.  The result of the command run by the
+// operation is only set once it has completed, and the error once it has
+// failed or was cancelled.
+type OperationStatusResult struct {
+	ID        string      `json:"id"`
+	Method    string      `json:"method"`
+	State     string      `json:"state"`
+	Done      int64       `json:"done"`
+	Total     int64       `json:"total"`
+	Progress  float64     `json:"progress"`
+	StartTime int64       `json:"starttime"`
+	Elapsed   int64       `json:"elapsed"`
+	Result    interface{} `json:"result,omitempty"`
+	Error     *RPCError   `json:"error,omitempty"`
+}
+
+// RescanBlockchainMatch models a transaction found by the rescanblockchain
+// command.
+type RescanBlockchainMatch struct {
+	TxID      string `json:"txid"`
+	Hex       string `json:"hex"`
+	BlockHash string `json:"blockhash"`
+	Height    int32  `json:"height"`
+	Index     int    `json:"index"`
+}
+
+// RescanBlockchainResult models the data returned by the rescanblockchain
+// command.
+type RescanBlockchainResult struct {
+	StartHeight int32                   `json:"start_height"`
+	StopHeight  int32                   `json:"stop_height"`
+	Matches     []RescanBlockchainMatch `json:"matches"`
+}
+
+// SetPolicyResult models the data returned by the setpolicy command, which are
+// the runtime settings in effect after the command was processed.
+type SetPolicyResult struct {
+	MinRelayTxFee float64 `json:"minrelaytxfee"`
+	MaxOrphanTxs  int     `json:"maxorphantx"`
+	RelayNonStd   bool    `json:"relaynonstd"`
+	MaxPeers      int     `json:"maxpeers"`
+}
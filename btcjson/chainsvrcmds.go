@@ -81,6 +81,52 @@ func NewCreateRawTransactionCmd(inputs []TransactionInput, amounts map[string]fl
 	}
 }
 
+// CombinePsbtCmd defines the combinepsbt JSON-RPC command.
+type CombinePsbtCmd struct {
+	Psbts []string
+}
+
+// NewCombinePsbtCmd returns a new instance which can be used to issue a
+// combinepsbt JSON-RPC command.
+func NewCombinePsbtCmd(psbts []string) *CombinePsbtCmd {
+	return &CombinePsbtCmd{
+		Psbts: psbts,
+	}
+}
+
+// ConvertToPsbtCmd defines the converttopsbt JSON-RPC command.
+type ConvertToPsbtCmd struct {
+	HexTx         string
+	PermitSigData *bool `jsonrpcdefault:"false"`
+	IsWitness     *bool
+}
+
+// NewConvertToPsbtCmd returns a new instance which can be used to issue a
+// converttopsbt JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewConvertToPsbtCmd(hexTx string, permitSigData, isWitness *bool) *ConvertToPsbtCmd {
+	return &ConvertToPsbtCmd{
+		HexTx:         hexTx,
+		PermitSigData: permitSigData,
+		IsWitness:     isWitness,
+	}
+}
+
+// DecodePsbtCmd defines the decodepsbt JSON-RPC command.
+type DecodePsbtCmd struct {
+	Psbt string
+}
+
+// NewDecodePsbtCmd returns a new instance which can be used to issue a
+// decodepsbt JSON-RPC command.
+func NewDecodePsbtCmd(psbt string) *DecodePsbtCmd {
+	return &DecodePsbtCmd{
+		Psbt: psbt,
+	}
+}
+
 // DecodeRawTransactionCmd defines the decoderawtransaction JSON-RPC command.
 type DecodeRawTransactionCmd struct {
 	HexTx string
@@ -135,6 +181,24 @@ var (
 	ChangeTypeBech32 ChangeType = "bech32"
 )
 
+// FinalizePsbtCmd defines the finalizepsbt JSON-RPC command.
+type FinalizePsbtCmd struct {
+	Psbt    string
+	Extract *bool `jsonrpcdefault:"true"`
+}
+
+// NewFinalizePsbtCmd returns a new instance which can be used to issue a
+// finalizepsbt JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewFinalizePsbtCmd(psbt string, extract *bool) *FinalizePsbtCmd {
+	return &FinalizePsbtCmd{
+		Psbt:    psbt,
+		Extract: extract,
+	}
+}
+
 // FundRawTransactionOpts are the different options that can be passed to rawtransaction
 type FundRawTransactionOpts struct {
 	ChangeAddress          *string               `json:"changeAddress,omitempty"`
@@ -147,6 +211,11 @@ type FundRawTransactionOpts struct {
 	Replaceable            *bool                 `json:"replaceable,omitempty"`
 	ConfTarget             *int                  `json:"conf_target,omitempty"`
 	EstimateMode           *EstimateSmartFeeMode `json:"estimate_mode,omitempty"`
+	LockTime               *int64                `json:"locktime,omitempty"`
+
+	// Addresses lists the watched addresses whose unspent outputs may be
+	// used to fund the transaction.
+	Addresses []string `json:"addresses,omitempty"`
 }
 
 // FundRawTransactionCmd defines the fundrawtransaction JSON-RPC command
@@ -184,6 +253,23 @@ func NewGetAddedNodeInfoCmd(dns bool, node *string) *GetAddedNodeInfoCmd {
 	}
 }
 
+// GetAddrManInfoCmd defines the getaddrmaninfo JSON-RPC command.  Sample is an
+// extension which requests a random sample of the known addresses.
+type GetAddrManInfoCmd struct {
+	Sample *int `jsonrpcdefault:"0"`
+}
+
+// NewGetAddrManInfoCmd returns a new instance which can be used to issue a
+// getaddrmaninfo JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetAddrManInfoCmd(sample *int) *GetAddrManInfoCmd {
+	return &GetAddrManInfoCmd{
+		Sample: sample,
+	}
+}
+
 // GetBestBlockHashCmd defines the getbestblockhash JSON-RPC command.
 type GetBestBlockHashCmd struct{}
 
@@ -256,6 +342,21 @@ func NewGetBlockFilterCmd(blockHash string, filterType *FilterTypeName) *GetBloc
 	}
 }
 
+// GetBlockFromPeerCmd defines the getblockfrompeer JSON-RPC command.
+type GetBlockFromPeerCmd struct {
+	BlockHash string
+	PeerID    int32
+}
+
+// NewGetBlockFromPeerCmd returns a new instance which can be used to issue a
+// getblockfrompeer JSON-RPC command.
+func NewGetBlockFromPeerCmd(blockHash string, peerID int32) *GetBlockFromPeerCmd {
+	return &GetBlockFromPeerCmd{
+		BlockHash: blockHash,
+		PeerID:    peerID,
+	}
+}
+
 // GetBlockHashCmd defines the getblockhash JSON-RPC command.
 type GetBlockHashCmd struct {
 	Index int64
@@ -452,12 +553,19 @@ func NewGetCFilterHeaderCmd(hash string,
 }
 
 // GetChainTipsCmd defines the getchaintips JSON-RPC command.
-type GetChainTipsCmd struct{}
+type GetChainTipsCmd struct {
+	Verbose *bool `jsonrpcdefault:"false"`
+}
 
 // NewGetChainTipsCmd returns a new instance which can be used to issue a
 // getchaintips JSON-RPC command.
-func NewGetChainTipsCmd() *GetChainTipsCmd {
-	return &GetChainTipsCmd{}
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetChainTipsCmd(verbose *bool) *GetChainTipsCmd {
+	return &GetChainTipsCmd{
+		Verbose: verbose,
+	}
 }
 
 // GetChainTxStatsCmd defines the getchaintxstats JSON-RPC command.
@@ -501,7 +609,9 @@ func NewGetDescriptorInfoCmd(descriptor string) *GetDescriptorInfoCmd {
 }
 
 // GetDifficultyCmd defines the getdifficulty JSON-RPC command.
-type GetDifficultyCmd struct{}
+type GetDifficultyCmd struct {
+	Verbose *bool `jsonrpcdefault:"false"`
+}
 
 // NewGetDifficultyCmd returns a new instance which can be used to issue a
 // getdifficulty JSON-RPC command.
@@ -509,6 +619,15 @@ func NewGetDifficultyCmd() *GetDifficultyCmd {
 	return &GetDifficultyCmd{}
 }
 
+// NewGetDifficultyVerboseCmd returns a new instance which can be used to issue
+// a getdifficulty JSON-RPC command which also returns the difficulty algorithm
+// of the best block.
+func NewGetDifficultyVerboseCmd() *GetDifficultyCmd {
+	return &GetDifficultyCmd{
+		Verbose: Bool(true),
+	}
+}
+
 // GetGenerateCmd defines the getgenerate JSON-RPC command.
 type GetGenerateCmd struct{}
 
@@ -527,6 +646,22 @@ func NewGetHashesPerSecCmd() *GetHashesPerSecCmd {
 	return &GetHashesPerSecCmd{}
 }
 
+// GetIndexInfoCmd defines the getindexinfo JSON-RPC command.
+type GetIndexInfoCmd struct {
+	IndexName *string
+}
+
+// NewGetIndexInfoCmd returns a new instance which can be used to issue a
+// getindexinfo JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetIndexInfoCmd(indexName *string) *GetIndexInfoCmd {
+	return &GetIndexInfoCmd{
+		IndexName: indexName,
+	}
+}
+
 // GetInfoCmd defines the getinfo JSON-RPC command.
 type GetInfoCmd struct{}
 
@@ -549,6 +684,22 @@ func NewGetMempoolEntryCmd(txHash string) *GetMempoolEntryCmd {
 	}
 }
 
+// GetMemoryInfoCmd defines the getmemoryinfo JSON-RPC command.
+type GetMemoryInfoCmd struct {
+	Mode *string `jsonrpcdefault:"\"stats\""`
+}
+
+// NewGetMemoryInfoCmd returns a new instance which can be used to issue a
+// getmemoryinfo JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMemoryInfoCmd(mode *string) *GetMemoryInfoCmd {
+	return &GetMemoryInfoCmd{
+		Mode: mode,
+	}
+}
+
 // GetMempoolInfoCmd defines the getmempoolinfo JSON-RPC command.
 type GetMempoolInfoCmd struct{}
 
@@ -649,8 +800,9 @@ func NewGetRawMempoolCmd(verbose *bool) *GetRawMempoolCmd {
 // NOTE: This field is an int versus a bool to remain compatible with Litecoin
 // Core even though it really should be a bool.
 type GetRawTransactionCmd struct {
-	Txid    string
-	Verbose *int `jsonrpcdefault:"0"`
+	Txid      string
+	Verbose   *int `jsonrpcdefault:"0"`
+	BlockHash *string
 }
 
 // NewGetRawTransactionCmd returns a new instance which can be used to issue a
@@ -665,6 +817,28 @@ func NewGetRawTransactionCmd(txHash string, verbose *int) *GetRawTransactionCmd
 	}
 }
 
+// NewGetRawTransactionFromBlockCmd returns a new instance which can be used to
+// issue a getrawtransaction JSON-RPC command looking the transaction up in the
+// block with the passed hash.
+func NewGetRawTransactionFromBlockCmd(txHash string, verbose int,
+	blockHash string) *GetRawTransactionCmd {
+
+	return &GetRawTransactionCmd{
+		Txid:      txHash,
+		Verbose:   &verbose,
+		BlockHash: &blockHash,
+	}
+}
+
+// GetRPCInfoCmd defines the getrpcinfo JSON-RPC command.
+type GetRPCInfoCmd struct{}
+
+// NewGetRPCInfoCmd returns a new instance which can be used to issue a
+// getrpcinfo JSON-RPC command.
+func NewGetRPCInfoCmd() *GetRPCInfoCmd {
+	return &GetRPCInfoCmd{}
+}
+
 // GetTxOutCmd defines the gettxout JSON-RPC command.
 type GetTxOutCmd struct {
 	Txid           string
@@ -921,10 +1095,24 @@ func NewSetGenerateCmd(generate bool, genProcLimit *int) *SetGenerateCmd {
 	}
 }
 
+// SetMockTimeCmd defines the setmocktime JSON-RPC command.
+type SetMockTimeCmd struct {
+	Timestamp int64
+}
+
+// NewSetMockTimeCmd returns a new instance which can be used to issue a
+// setmocktime JSON-RPC command.
+func NewSetMockTimeCmd(timestamp int64) *SetMockTimeCmd {
+	return &SetMockTimeCmd{
+		Timestamp: timestamp,
+	}
+}
+
 // SignMessageWithPrivKeyCmd defines the signmessagewithprivkey JSON-RPC command.
 type SignMessageWithPrivKeyCmd struct {
-	PrivKey string // base 58 Wallet Import format private key
-	Message string // Message to sign
+	PrivKey     string  // base 58 Wallet Import format private key
+	Message     string  // Message to sign
+	AddressType *string `jsonrpcdefault:"\"legacy\""`
 }
 
 // NewSignMessageWithPrivKey returns a new instance which can be used to issue a
@@ -932,10 +1120,41 @@ type SignMessageWithPrivKeyCmd struct {
 //
 // The first parameter is a private key in base 58 Wallet Import format.
 // The second parameter is the message to sign.
-func NewSignMessageWithPrivKey(privKey, message string) *SignMessageWithPrivKeyCmd {
+// The third parameter is the type of the address the message is signed for,
+// which is one of legacy, p2sh-segwit, or bech32.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSignMessageWithPrivKey(privKey, message string, addressType *string) *SignMessageWithPrivKeyCmd {
 	return &SignMessageWithPrivKeyCmd{
-		PrivKey: privKey,
-		Message: message,
+		PrivKey:     privKey,
+		Message:     message,
+		AddressType: addressType,
+	}
+}
+
+// SignRawTransactionWithKeyCmd defines the signrawtransactionwithkey JSON-RPC
+// command.
+type SignRawTransactionWithKeyCmd struct {
+	RawTx       string
+	PrivKeys    []string
+	Inputs      *[]RawTxWitnessInput
+	SigHashType *string `jsonrpcdefault:"\"ALL\""`
+}
+
+// NewSignRawTransactionWithKeyCmd returns a new instance which can be used to
+// issue a signrawtransactionwithkey JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSignRawTransactionWithKeyCmd(hexEncodedTx string, privKeys []string,
+	inputs *[]RawTxWitnessInput, sigHashType *string) *SignRawTransactionWithKeyCmd {
+
+	return &SignRawTransactionWithKeyCmd{
+		RawTx:       hexEncodedTx,
+		PrivKeys:    privKeys,
+		Inputs:      inputs,
+		SigHashType: sigHashType,
 	}
 }
 
@@ -973,6 +1192,34 @@ func NewSubmitBlockCmd(hexBlock string, options *SubmitBlockOptions) *SubmitBloc
 	}
 }
 
+// SubmitHeaderCmd defines the submitheader JSON-RPC command.
+type SubmitHeaderCmd struct {
+	HexData string
+}
+
+// NewSubmitHeaderCmd returns a new instance which can be used to issue a
+// submitheader JSON-RPC command.
+func NewSubmitHeaderCmd(hexData string) *SubmitHeaderCmd {
+	return &SubmitHeaderCmd{
+		HexData: hexData,
+	}
+}
+
+// TraceScriptCmd defines the tracescript JSON-RPC command.
+type TraceScriptCmd struct {
+	HexTx string
+	Index uint32
+}
+
+// NewTraceScriptCmd returns a new instance which can be used to issue a
+// tracescript JSON-RPC command.
+func NewTraceScriptCmd(hexTx string, index uint32) *TraceScriptCmd {
+	return &TraceScriptCmd{
+		HexTx: hexTx,
+		Index: index,
+	}
+}
+
 // UptimeCmd defines the uptime JSON-RPC command.
 type UptimeCmd struct{}
 
@@ -1047,17 +1294,23 @@ func init() {
 	flags := UsageFlag(0)
 
 	MustRegisterCmd("addnode", (*AddNodeCmd)(nil), flags)
+	MustRegisterCmd("combinepsbt", (*CombinePsbtCmd)(nil), flags)
+	MustRegisterCmd("converttopsbt", (*ConvertToPsbtCmd)(nil), flags)
 	MustRegisterCmd("createrawtransaction", (*CreateRawTransactionCmd)(nil), flags)
+	MustRegisterCmd("decodepsbt", (*DecodePsbtCmd)(nil), flags)
 	MustRegisterCmd("decoderawtransaction", (*DecodeRawTransactionCmd)(nil), flags)
 	MustRegisterCmd("decodescript", (*DecodeScriptCmd)(nil), flags)
 	MustRegisterCmd("deriveaddresses", (*DeriveAddressesCmd)(nil), flags)
+	MustRegisterCmd("finalizepsbt", (*FinalizePsbtCmd)(nil), flags)
 	MustRegisterCmd("fundrawtransaction", (*FundRawTransactionCmd)(nil), flags)
 	MustRegisterCmd("getaddednodeinfo", (*GetAddedNodeInfoCmd)(nil), flags)
+	MustRegisterCmd("getaddrmaninfo", (*GetAddrManInfoCmd)(nil), flags)
 	MustRegisterCmd("getbestblockhash", (*GetBestBlockHashCmd)(nil), flags)
 	MustRegisterCmd("getblock", (*GetBlockCmd)(nil), flags)
 	MustRegisterCmd("getblockchaininfo", (*GetBlockChainInfoCmd)(nil), flags)
 	MustRegisterCmd("getblockcount", (*GetBlockCountCmd)(nil), flags)
 	MustRegisterCmd("getblockfilter", (*GetBlockFilterCmd)(nil), flags)
+	MustRegisterCmd("getblockfrompeer", (*GetBlockFromPeerCmd)(nil), flags)
 	MustRegisterCmd("getblockhash", (*GetBlockHashCmd)(nil), flags)
 	MustRegisterCmd("getblockheader", (*GetBlockHeaderCmd)(nil), flags)
 	MustRegisterCmd("getblockstats", (*GetBlockStatsCmd)(nil), flags)
@@ -1071,8 +1324,10 @@ func init() {
 	MustRegisterCmd("getdifficulty", (*GetDifficultyCmd)(nil), flags)
 	MustRegisterCmd("getgenerate", (*GetGenerateCmd)(nil), flags)
 	MustRegisterCmd("gethashespersec", (*GetHashesPerSecCmd)(nil), flags)
+	MustRegisterCmd("getindexinfo", (*GetIndexInfoCmd)(nil), flags)
 	MustRegisterCmd("getinfo", (*GetInfoCmd)(nil), flags)
 	MustRegisterCmd("getmempoolentry", (*GetMempoolEntryCmd)(nil), flags)
+	MustRegisterCmd("getmemoryinfo", (*GetMemoryInfoCmd)(nil), flags)
 	MustRegisterCmd("getmempoolinfo", (*GetMempoolInfoCmd)(nil), flags)
 	MustRegisterCmd("getmininginfo", (*GetMiningInfoCmd)(nil), flags)
 	MustRegisterCmd("getnetworkinfo", (*GetNetworkInfoCmd)(nil), flags)
@@ -1082,6 +1337,7 @@ func init() {
 	MustRegisterCmd("getpeerinfo", (*GetPeerInfoCmd)(nil), flags)
 	MustRegisterCmd("getrawmempool", (*GetRawMempoolCmd)(nil), flags)
 	MustRegisterCmd("getrawtransaction", (*GetRawTransactionCmd)(nil), flags)
+	MustRegisterCmd("getrpcinfo", (*GetRPCInfoCmd)(nil), flags)
 	MustRegisterCmd("gettxout", (*GetTxOutCmd)(nil), flags)
 	MustRegisterCmd("gettxoutproof", (*GetTxOutProofCmd)(nil), flags)
 	MustRegisterCmd("gettxoutsetinfo", (*GetTxOutSetInfoCmd)(nil), flags)
@@ -1094,9 +1350,13 @@ func init() {
 	MustRegisterCmd("searchrawtransactions", (*SearchRawTransactionsCmd)(nil), flags)
 	MustRegisterCmd("sendrawtransaction", (*SendRawTransactionCmd)(nil), flags)
 	MustRegisterCmd("setgenerate", (*SetGenerateCmd)(nil), flags)
+	MustRegisterCmd("setmocktime", (*SetMockTimeCmd)(nil), flags)
 	MustRegisterCmd("signmessagewithprivkey", (*SignMessageWithPrivKeyCmd)(nil), flags)
+	MustRegisterCmd("signrawtransactionwithkey", (*SignRawTransactionWithKeyCmd)(nil), flags)
 	MustRegisterCmd("stop", (*StopCmd)(nil), flags)
 	MustRegisterCmd("submitblock", (*SubmitBlockCmd)(nil), flags)
+	MustRegisterCmd("submitheader", (*SubmitHeaderCmd)(nil), flags)
+	MustRegisterCmd("tracescript", (*TraceScriptCmd)(nil), flags)
 	MustRegisterCmd("uptime", (*UptimeCmd)(nil), flags)
 	MustRegisterCmd("validateaddress", (*ValidateAddressCmd)(nil), flags)
 	MustRegisterCmd("verifychain", (*VerifyChainCmd)(nil), flags)
@@ -96,6 +96,34 @@ func TestChainSvrCmds(t *testing.T) {
 				LockTime: btcjson.Int64(12312333333),
 			},
 		},
+		{
+			name: "finalizepsbt",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("finalizepsbt", "cHNidP8B")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewFinalizePsbtCmd("cHNidP8B", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"finalizepsbt","params":["cHNidP8B"],"id":1}`,
+			unmarshalled: &btcjson.FinalizePsbtCmd{
+				Psbt:    "cHNidP8B",
+				Extract: btcjson.Bool(true),
+			},
+		},
+		{
+			name: "finalizepsbt no extract",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("finalizepsbt", "cHNidP8B", false)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewFinalizePsbtCmd("cHNidP8B", btcjson.Bool(false))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"finalizepsbt","params":["cHNidP8B",false],"id":1}`,
+			unmarshalled: &btcjson.FinalizePsbtCmd{
+				Psbt:    "cHNidP8B",
+				Extract: btcjson.Bool(false),
+			},
+		},
 		{
 			name: "fundrawtransaction - empty opts",
 			newCmd: func() (i interface{}, e error) {
@@ -198,6 +226,58 @@ func TestChainSvrCmds(t *testing.T) {
 				}(),
 			},
 		},
+		{
+			name: "combinepsbt",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("combinepsbt", []string{"cHNidP8B", "cHNidP8C"})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewCombinePsbtCmd([]string{"cHNidP8B", "cHNidP8C"})
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"combinepsbt","params":[["cHNidP8B","cHNidP8C"]],"id":1}`,
+			unmarshalled: &btcjson.CombinePsbtCmd{Psbts: []string{"cHNidP8B", "cHNidP8C"}},
+		},
+		{
+			name: "converttopsbt",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("converttopsbt", "0100")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewConvertToPsbtCmd("0100", nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"converttopsbt","params":["0100"],"id":1}`,
+			unmarshalled: &btcjson.ConvertToPsbtCmd{
+				HexTx:         "0100",
+				PermitSigData: btcjson.Bool(false),
+			},
+		},
+		{
+			name: "converttopsbt optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("converttopsbt", "0100", true, true)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewConvertToPsbtCmd("0100",
+					btcjson.Bool(true), btcjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"converttopsbt","params":["0100",true,true],"id":1}`,
+			unmarshalled: &btcjson.ConvertToPsbtCmd{
+				HexTx:         "0100",
+				PermitSigData: btcjson.Bool(true),
+				IsWitness:     btcjson.Bool(true),
+			},
+		},
+		{
+			name: "decodepsbt",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("decodepsbt", "cHNidP8B")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewDecodePsbtCmd("cHNidP8B")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"decodepsbt","params":["cHNidP8B"],"id":1}`,
+			unmarshalled: &btcjson.DecodePsbtCmd{Psbt: "cHNidP8B"},
+		},
 		{
 			name: "decoderawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -290,6 +370,32 @@ func TestChainSvrCmds(t *testing.T) {
 				Node: btcjson.String("127.0.0.1"),
 			},
 		},
+		{
+			name: "getaddrmaninfo",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getaddrmaninfo")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetAddrManInfoCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddrmaninfo","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetAddrManInfoCmd{
+				Sample: btcjson.Int(0),
+			},
+		},
+		{
+			name: "getaddrmaninfo optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getaddrmaninfo", 10)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetAddrManInfoCmd(btcjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddrmaninfo","params":[10],"id":1}`,
+			unmarshalled: &btcjson.GetAddrManInfoCmd{
+				Sample: btcjson.Int(10),
+			},
+		},
 		{
 			name: "getbestblockhash",
 			newCmd: func() (interface{}, error) {
@@ -401,6 +507,20 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getblockfilter","params":["0000afaf","basic"],"id":1}`,
 			unmarshalled: &btcjson.GetBlockFilterCmd{"0000afaf", btcjson.NewFilterTypeName(btcjson.FilterTypeBasic)},
 		},
+		{
+			name: "getblockfrompeer",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getblockfrompeer", "0000afaf", 1)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetBlockFromPeerCmd("0000afaf", 1)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockfrompeer","params":["0000afaf",1],"id":1}`,
+			unmarshalled: &btcjson.GetBlockFromPeerCmd{
+				BlockHash: "0000afaf",
+				PeerID:    1,
+			},
+		},
 		{
 			name: "getblockhash",
 			newCmd: func() (interface{}, error) {
@@ -601,10 +721,25 @@ func TestChainSvrCmds(t *testing.T) {
 				return btcjson.NewCmd("getchaintips")
 			},
 			staticCmd: func() interface{} {
-				return btcjson.NewGetChainTipsCmd()
+				return btcjson.NewGetChainTipsCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getchaintips","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetChainTipsCmd{
+				Verbose: btcjson.Bool(false),
+			},
+		},
+		{
+			name: "getchaintips verbose",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getchaintips", true)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetChainTipsCmd(btcjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getchaintips","params":[true],"id":1}`,
+			unmarshalled: &btcjson.GetChainTipsCmd{
+				Verbose: btcjson.Bool(true),
 			},
-			marshalled:   `{"jsonrpc":"1.0","method":"getchaintips","params":[],"id":1}`,
-			unmarshalled: &btcjson.GetChainTipsCmd{},
 		},
 		{
 			name: "getchaintxstats",
@@ -663,8 +798,23 @@ func TestChainSvrCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return btcjson.NewGetDifficultyCmd()
 			},
-			marshalled:   `{"jsonrpc":"1.0","method":"getdifficulty","params":[],"id":1}`,
-			unmarshalled: &btcjson.GetDifficultyCmd{},
+			marshalled: `{"jsonrpc":"1.0","method":"getdifficulty","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetDifficultyCmd{
+				Verbose: btcjson.Bool(false),
+			},
+		},
+		{
+			name: "getdifficulty verbose",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getdifficulty", true)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetDifficultyVerboseCmd()
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getdifficulty","params":[true],"id":1}`,
+			unmarshalled: &btcjson.GetDifficultyCmd{
+				Verbose: btcjson.Bool(true),
+			},
 		},
 		{
 			name: "getgenerate",
@@ -688,6 +838,32 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"gethashespersec","params":[],"id":1}`,
 			unmarshalled: &btcjson.GetHashesPerSecCmd{},
 		},
+		{
+			name: "getindexinfo",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getindexinfo")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetIndexInfoCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getindexinfo","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetIndexInfoCmd{
+				IndexName: nil,
+			},
+		},
+		{
+			name: "getindexinfo optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getindexinfo", "transaction index")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetIndexInfoCmd(btcjson.String("transaction index"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getindexinfo","params":["transaction index"],"id":1}`,
+			unmarshalled: &btcjson.GetIndexInfoCmd{
+				IndexName: btcjson.String("transaction index"),
+			},
+		},
 		{
 			name: "getinfo",
 			newCmd: func() (interface{}, error) {
@@ -712,6 +888,32 @@ func TestChainSvrCmds(t *testing.T) {
 				TxID: "txhash",
 			},
 		},
+		{
+			name: "getmemoryinfo",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getmemoryinfo")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetMemoryInfoCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmemoryinfo","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetMemoryInfoCmd{
+				Mode: btcjson.String("stats"),
+			},
+		},
+		{
+			name: "getmemoryinfo optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getmemoryinfo", "mallocinfo")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetMemoryInfoCmd(btcjson.String("mallocinfo"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmemoryinfo","params":["mallocinfo"],"id":1}`,
+			unmarshalled: &btcjson.GetMemoryInfoCmd{
+				Mode: btcjson.String("mallocinfo"),
+			},
+		},
 		{
 			name: "getmempoolinfo",
 			newCmd: func() (interface{}, error) {
@@ -889,6 +1091,32 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: btcjson.Int(1),
 			},
 		},
+		{
+			name: "getrawtransaction blockhash",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getrawtransaction", "123", 1, "456")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetRawTransactionFromBlockCmd("123", 1, "456")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getrawtransaction","params":["123",1,"456"],"id":1}`,
+			unmarshalled: &btcjson.GetRawTransactionCmd{
+				Txid:      "123",
+				Verbose:   btcjson.Int(1),
+				BlockHash: btcjson.String("456"),
+			},
+		},
+		{
+			name: "getrpcinfo",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getrpcinfo")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetRPCInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getrpcinfo","params":[],"id":1}`,
+			unmarshalled: &btcjson.GetRPCInfoCmd{},
+		},
 		{
 			name: "gettxout",
 			newCmd: func() (interface{}, error) {
@@ -1297,18 +1525,100 @@ func TestChainSvrCmds(t *testing.T) {
 				GenProcLimit: btcjson.Int(6),
 			},
 		},
+		{
+			name: "setmocktime",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("setmocktime", 1600000000)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSetMockTimeCmd(1600000000)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setmocktime","params":[1600000000],"id":1}`,
+			unmarshalled: &btcjson.SetMockTimeCmd{
+				Timestamp: 1600000000,
+			},
+		},
 		{
 			name: "signmessagewithprivkey",
 			newCmd: func() (interface{}, error) {
 				return btcjson.NewCmd("signmessagewithprivkey", "5Hue", "Hey")
 			},
 			staticCmd: func() interface{} {
-				return btcjson.NewSignMessageWithPrivKey("5Hue", "Hey")
+				return btcjson.NewSignMessageWithPrivKey("5Hue", "Hey", nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"signmessagewithprivkey","params":["5Hue","Hey"],"id":1}`,
 			unmarshalled: &btcjson.SignMessageWithPrivKeyCmd{
-				PrivKey: "5Hue",
-				Message: "Hey",
+				PrivKey:     "5Hue",
+				Message:     "Hey",
+				AddressType: btcjson.String("legacy"),
+			},
+		},
+		{
+			name: "signmessagewithprivkey optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("signmessagewithprivkey", "5Hue", "Hey", "bech32")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSignMessageWithPrivKey("5Hue", "Hey",
+					btcjson.String("bech32"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"signmessagewithprivkey","params":["5Hue","Hey","bech32"],"id":1}`,
+			unmarshalled: &btcjson.SignMessageWithPrivKeyCmd{
+				PrivKey:     "5Hue",
+				Message:     "Hey",
+				AddressType: btcjson.String("bech32"),
+			},
+		},
+		{
+			name: "signrawtransactionwithkey",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("signrawtransactionwithkey", "001122", []string{"5Hue"})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSignRawTransactionWithKeyCmd("001122", []string{"5Hue"}, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"signrawtransactionwithkey","params":["001122",["5Hue"]],"id":1}`,
+			unmarshalled: &btcjson.SignRawTransactionWithKeyCmd{
+				RawTx:       "001122",
+				PrivKeys:    []string{"5Hue"},
+				Inputs:      nil,
+				SigHashType: btcjson.String("ALL"),
+			},
+		},
+		{
+			name: "signrawtransactionwithkey optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("signrawtransactionwithkey", "001122", []string{"5Hue"},
+					`[{"txid":"123","vout":1,"scriptPubKey":"00","witnessScript":"01","amount":0.5}]`,
+					"ALL|ANYONECANPAY")
+			},
+			staticCmd: func() interface{} {
+				txInputs := []btcjson.RawTxWitnessInput{
+					{
+						Txid:          "123",
+						Vout:          1,
+						ScriptPubKey:  "00",
+						WitnessScript: btcjson.String("01"),
+						Amount:        btcjson.Float64(0.5),
+					},
+				}
+				return btcjson.NewSignRawTransactionWithKeyCmd("001122", []string{"5Hue"},
+					&txInputs, btcjson.String("ALL|ANYONECANPAY"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"signrawtransactionwithkey","params":["001122",["5Hue"],[{"txid":"123","vout":1,"scriptPubKey":"00","witnessScript":"01","amount":0.5}],"ALL|ANYONECANPAY"],"id":1}`,
+			unmarshalled: &btcjson.SignRawTransactionWithKeyCmd{
+				RawTx:    "001122",
+				PrivKeys: []string{"5Hue"},
+				Inputs: &[]btcjson.RawTxWitnessInput{
+					{
+						Txid:          "123",
+						Vout:          1,
+						ScriptPubKey:  "00",
+						WitnessScript: btcjson.String("01"),
+						Amount:        btcjson.Float64(0.5),
+					},
+				},
+				SigHashType: btcjson.String("ALL|ANYONECANPAY"),
 			},
 		},
 		{
@@ -1355,6 +1665,31 @@ func TestChainSvrCmds(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "submitheader",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("submitheader", "112233")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSubmitHeaderCmd("112233")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"submitheader","params":["112233"],"id":1}`,
+			unmarshalled: &btcjson.SubmitHeaderCmd{HexData: "112233"},
+		},
+		{
+			name: "tracescript",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("tracescript", "112233", 1)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewTraceScriptCmd("112233", 1)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"tracescript","params":["112233",1],"id":1}`,
+			unmarshalled: &btcjson.TraceScriptCmd{
+				HexTx: "112233",
+				Index: 1,
+			},
+		},
 		{
 			name: "uptime",
 			newCmd: func() (interface{}, error) {
@@ -129,6 +129,16 @@ type GetChainTxStatsResult struct {
 	TxRate                 float64 `json:"txrate"`
 }
 
+// GetDifficultyResult models the data from the getdifficulty command when the
+// verbose flag is set.
+type GetDifficultyResult struct {
+	Difficulty float64 `json:"difficulty"`
+	Algo       string  `json:"algo"`
+	Height     int32   `json:"height"`
+	Bits       string  `json:"bits"`
+	PowLimit   string  `json:"powlimit"`
+}
+
 // CreateMultiSigResult models the data returned from the createmultisig
 // command.
 type CreateMultiSigResult struct {
@@ -146,6 +156,25 @@ type DecodeScriptResult struct {
 	P2sh      string   `json:"p2sh,omitempty"`
 }
 
+// TraceScriptStep models the engine state after a single step of a
+// tracescript execution trace.
+type TraceScriptStep struct {
+	ScriptIndex int      `json:"scriptindex"`
+	OpcodeIndex int      `json:"opcodeindex"`
+	Opcode      string   `json:"opcode,omitempty"`
+	Stack       []string `json:"stack"`
+	AltStack    []string `json:"altstack"`
+	CondStack   []string `json:"condstack"`
+	Remaining   []string `json:"remaining"`
+}
+
+// TraceScriptResult models the data returned from the tracescript command.
+type TraceScriptResult struct {
+	Valid bool              `json:"valid"`
+	Error string            `json:"error,omitempty"`
+	Steps []TraceScriptStep `json:"steps"`
+}
+
 // GetAddedNodeInfoResultAddr models the data of the addresses portion of the
 // getaddednodeinfo command.
 type GetAddedNodeInfoResultAddr struct {
@@ -223,16 +252,41 @@ type GetBlockChainInfoResult struct {
 	BestBlockHash        string  `json:"bestblockhash"`
 	Difficulty           float64 `json:"difficulty"`
 	MedianTime           int64   `json:"mediantime"`
-	VerificationProgress float64 `json:"verificationprogress,omitempty"`
-	InitialBlockDownload bool    `json:"initialblockdownload,omitempty"`
+	VerificationProgress float64 `json:"verificationprogress"`
+	InitialBlockDownload bool    `json:"initialblockdownload"`
 	Pruned               bool    `json:"pruned"`
 	PruneHeight          int32   `json:"pruneheight,omitempty"`
 	ChainWork            string  `json:"chainwork,omitempty"`
 	SizeOnDisk           int64   `json:"size_on_disk,omitempty"`
+	Warnings             string  `json:"warnings"`
 	*SoftForks
 	*UnifiedSoftForks
 }
 
+// GetChainTipsResult models the data returned from the getchaintips command.
+type GetChainTipsResult struct {
+	Height    int32  `json:"height"`
+	Hash      string `json:"hash"`
+	BranchLen int32  `json:"branchlen"`
+	Status    string `json:"status"`
+}
+
+// GetChainTipsVerboseResult models the data returned from the getchaintips
+// command when the verbose flag is set.
+type GetChainTipsVerboseResult struct {
+	Tips              []GetChainTipsResult `json:"tips"`
+	StaleForkDepth    int32                `json:"staleforkdepth"`
+	PrunedStaleBlocks uint64               `json:"prunedstaleblocks"`
+	LastPruneHeight   int32                `json:"lastpruneheight"`
+}
+
+// GetIndexInfoResult models the data returned for each index from the
+// getindexinfo command.
+type GetIndexInfoResult struct {
+	Synced          bool  `json:"synced"`
+	BestBlockHeight int32 `json:"best_block_height"`
+}
+
 // GetBlockFilterResult models the data returned from the getblockfilter
 // command.
 type GetBlockFilterResult struct {
@@ -280,6 +334,17 @@ type GetBlockTemplateResult struct {
 	// Witness commitment defined in BIP 0141.
 	DefaultWitnessCommitment string `json:"default_witness_commitment,omitempty"`
 
+	// Challenge the block must solve on signet networks from BIP 0325.
+	SignetChallenge string `json:"signet_challenge,omitempty"`
+
+	// Bytes reserved in the signature script of the coinbase transaction
+	// for the extra nonces of mining pools.  The offsets of the first
+	// reserved byte are given within the signature script and within the
+	// serialized coinbase transaction of CoinbaseTxn.
+	CoinbaseReservedSize       int `json:"coinbase_reserved_size,omitempty"`
+	CoinbaseReservedOffset     int `json:"coinbase_reserved_offset,omitempty"`
+	CoinbaseReservedDataOffset int `json:"coinbase_reserved_dataoffset,omitempty"`
+
 	// Optional long polling from BIP 0022.
 	LongPollID  string `json:"longpollid,omitempty"`
 	LongPollURI string `json:"longpolluri,omitempty"`
@@ -331,6 +396,59 @@ type GetMempoolEntryResult struct {
 	Depends         []string    `json:"depends"`
 }
 
+// GetMemoryInfoLockedResult models the locked memory data from the
+// getmemoryinfo command.  Since locked memory is not used, all of the values
+// are zero, but they are included for compatibility with existing tooling.
+type GetMemoryInfoLockedResult struct {
+	Used       uint64 `json:"used"`
+	Free       uint64 `json:"free"`
+	Total      uint64 `json:"total"`
+	Locked     uint64 `json:"locked"`
+	ChunksUsed uint64 `json:"chunks_used"`
+	ChunksFree uint64 `json:"chunks_free"`
+}
+
+// GetMemoryInfoRuntimeResult models the Go runtime memory statistics from the
+// getmemoryinfo command.
+type GetMemoryInfoRuntimeResult struct {
+	Alloc        uint64 `json:"alloc"`
+	TotalAlloc   uint64 `json:"totalalloc"`
+	Sys          uint64 `json:"sys"`
+	HeapAlloc    uint64 `json:"heapalloc"`
+	HeapInuse    uint64 `json:"heapinuse"`
+	HeapIdle     uint64 `json:"heapidle"`
+	HeapReleased uint64 `json:"heapreleased"`
+	HeapObjects  uint64 `json:"heapobjects"`
+	StackInuse   uint64 `json:"stackinuse"`
+	NumGC        uint32 `json:"numgc"`
+	GCPauseTotal int64  `json:"gcpausetotal"`
+	Goroutines   int    `json:"goroutines"`
+}
+
+// GetMemoryInfoCacheResult models the statistics of a cache from the
+// getmemoryinfo command.
+type GetMemoryInfoCacheResult struct {
+	Entries    uint   `json:"entries"`
+	MaxEntries uint   `json:"maxentries"`
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+}
+
+// GetMemoryInfoCachesResult models the cache statistics from the
+// getmemoryinfo command.
+type GetMemoryInfoCachesResult struct {
+	SigCache    GetMemoryInfoCacheResult `json:"sigcache"`
+	ScriptCache GetMemoryInfoCacheResult `json:"scriptcache"`
+}
+
+// GetMemoryInfoResult models the data returned from the getmemoryinfo command
+// in the stats mode.
+type GetMemoryInfoResult struct {
+	Locked  GetMemoryInfoLockedResult  `json:"locked"`
+	Runtime GetMemoryInfoRuntimeResult `json:"runtime"`
+	Caches  GetMemoryInfoCachesResult  `json:"caches"`
+}
+
 // GetMempoolInfoResult models the data returned from the getmempoolinfo
 // command.
 type GetMempoolInfoResult struct {
@@ -375,6 +493,39 @@ type GetNetworkInfoResult struct {
 	Warnings        string                 `json:"warnings"`
 }
 
+// AddrManNetworkResult models the number of addresses of a network in the
+// results of the getaddrmaninfo command.
+type AddrManNetworkResult struct {
+	New   int `json:"new"`
+	Tried int `json:"tried"`
+	Total int `json:"total"`
+}
+
+// AddrManAddressResult models a sampled address in the results of the
+// getaddrmaninfo command.
+type AddrManAddressResult struct {
+	Address     string `json:"address"`
+	Network     string `json:"network"`
+	Services    uint64 `json:"services"`
+	Time        int64  `json:"time"`
+	Source      string `json:"source"`
+	Tried       bool   `json:"tried"`
+	Buckets     []int  `json:"buckets"`
+	Attempts    int    `json:"attempts"`
+	LastAttempt int64  `json:"lastattempt"`
+	LastSuccess int64  `json:"lastsuccess"`
+}
+
+// GetAddrManInfoResult models the data returned from the getaddrmaninfo
+// command.
+type GetAddrManInfoResult struct {
+	Networks         map[string]AddrManNetworkResult `json:"networks"`
+	Terrible         int                             `json:"terrible"`
+	NewBucketsUsed   int                             `json:"newbucketsused"`
+	TriedBucketsUsed int                             `json:"triedbucketsused"`
+	Sample           []AddrManAddressResult          `json:"sample,omitempty"`
+}
+
 // GetNodeAddressesResult models the data returned from the getnodeaddresses
 // command.
 type GetNodeAddressesResult struct {
@@ -408,6 +559,9 @@ type GetPeerInfoResult struct {
 	BanScore       int32   `json:"banscore"`
 	FeeFilter      int64   `json:"feefilter"`
 	SyncNode       bool    `json:"syncnode"`
+
+	BytesSentPerMsg map[string]uint64 `json:"bytessent_per_msg"`
+	BytesRecvPerMsg map[string]uint64 `json:"bytesrecv_per_msg"`
 }
 
 // GetRawMempoolVerboseResult models the data returned from the getrawmempool
@@ -436,6 +590,20 @@ type ScriptPubKeyResult struct {
 	Addresses []string `json:"addresses,omitempty"` // Deprecated: removed in Litecoin Core
 }
 
+// RPCActiveCommand models an active command from the getrpcinfo command.
+type RPCActiveCommand struct {
+	Method   string `json:"method"`
+	Duration int64  `json:"duration"`
+}
+
+// GetRPCInfoResult models the data returned from the getrpcinfo command.
+type GetRPCInfoResult struct {
+	ActiveCommands     []RPCActiveCommand `json:"active_commands"`
+	LogPath            string             `json:"logpath"`
+	RateLimited        uint64             `json:"ratelimited"`
+	ConcurrencyLimited uint64             `json:"concurrencylimited"`
+}
+
 // GetTxOutResult models the data from the gettxout command.
 type GetTxOutResult struct {
 	BestBlock     string             `json:"bestblock"`
@@ -446,15 +614,52 @@ type GetTxOutResult struct {
 }
 
 // GetTxOutSetInfoResult models the data from the gettxoutsetinfo command.
+//
+// The subsidy accounting and MWEB fields are ltcd extensions.
 type GetTxOutSetInfoResult struct {
-	Height         int64          `json:"height"`
-	BestBlock      chainhash.Hash `json:"bestblock"`
-	Transactions   int64          `json:"transactions"`
-	TxOuts         int64          `json:"txouts"`
-	BogoSize       int64          `json:"bogosize"`
-	HashSerialized chainhash.Hash `json:"hash_serialized_2"`
-	DiskSize       int64          `json:"disk_size"`
-	TotalAmount    ltcutil.Amount `json:"total_amount"`
+	Height                 int64                `json:"height"`
+	BestBlock              chainhash.Hash       `json:"bestblock"`
+	Transactions           int64                `json:"transactions"`
+	TxOuts                 int64                `json:"txouts"`
+	BogoSize               int64                `json:"bogosize"`
+	HashSerialized         chainhash.Hash       `json:"hash_serialized_2"`
+	DiskSize               int64                `json:"disk_size"`
+	TotalAmount            ltcutil.Amount       `json:"total_amount"`
+	TotalUnspendableAmount ltcutil.Amount       `json:"total_unspendable_amount"`
+	TotalSubsidyAmount     ltcutil.Amount       `json:"total_subsidy_amount"`
+	MwebPeggedAmount       ltcutil.Amount       `json:"mweb_pegged_amount"`
+	SubsidyEras            []TxOutSetSubsidyEra `json:"subsidy_eras,omitempty"`
+}
+
+// TxOutSetSubsidyEra models a range of blocks paying the same subsidy as
+// returned by the gettxoutsetinfo command.
+type TxOutSetSubsidyEra struct {
+	StartHeight int32   `json:"start_height"`
+	EndHeight   int32   `json:"end_height"`
+	Subsidy     float64 `json:"subsidy"`
+	Blocks      int64   `json:"blocks"`
+	TotalAmount float64 `json:"total_amount"`
+}
+
+// MarshalJSON marshals the result of the gettxoutsetinfo JSON-RPC call with
+// the amounts in LTC.
+func (g GetTxOutSetInfoResult) MarshalJSON() ([]byte, error) {
+	// Alias the struct so its methods are not called recursively.
+	type Alias GetTxOutSetInfoResult
+
+	return json.Marshal(&struct {
+		TotalAmount            float64 `json:"total_amount"`
+		TotalUnspendableAmount float64 `json:"total_unspendable_amount"`
+		TotalSubsidyAmount     float64 `json:"total_subsidy_amount"`
+		MwebPeggedAmount       float64 `json:"mweb_pegged_amount"`
+		*Alias
+	}{
+		TotalAmount:            g.TotalAmount.ToBTC(),
+		TotalUnspendableAmount: g.TotalUnspendableAmount.ToBTC(),
+		TotalSubsidyAmount:     g.TotalSubsidyAmount.ToBTC(),
+		MwebPeggedAmount:       g.MwebPeggedAmount.ToBTC(),
+		Alias:                  (*Alias)(&g),
+	})
 }
 
 // UnmarshalJSON unmarshals the result of the gettxoutsetinfo JSON-RPC call
@@ -465,9 +670,12 @@ func (g *GetTxOutSetInfoResult) UnmarshalJSON(data []byte) error {
 	// Step 2: Create an anonymous struct with raw replacements for the special
 	// fields.
 	aux := &struct {
-		BestBlock      string  `json:"bestblock"`
-		HashSerialized string  `json:"hash_serialized_2"`
-		TotalAmount    float64 `json:"total_amount"`
+		BestBlock              string  `json:"bestblock"`
+		HashSerialized         string  `json:"hash_serialized_2"`
+		TotalAmount            float64 `json:"total_amount"`
+		TotalUnspendableAmount float64 `json:"total_unspendable_amount"`
+		TotalSubsidyAmount     float64 `json:"total_subsidy_amount"`
+		MwebPeggedAmount       float64 `json:"mweb_pegged_amount"`
 		*Alias
 	}{
 		Alias: (*Alias)(g),
@@ -493,12 +701,21 @@ func (g *GetTxOutSetInfoResult) UnmarshalJSON(data []byte) error {
 
 	g.HashSerialized = *serializedHash
 
-	amount, err := ltcutil.NewAmount(aux.TotalAmount)
-	if err != nil {
-		return err
+	amounts := []struct {
+		value float64
+		dest  *ltcutil.Amount
+	}{
+		{aux.TotalAmount, &g.TotalAmount},
+		{aux.TotalUnspendableAmount, &g.TotalUnspendableAmount},
+		{aux.TotalSubsidyAmount, &g.TotalSubsidyAmount},
+		{aux.MwebPeggedAmount, &g.MwebPeggedAmount},
+	}
+	for _, amount := range amounts {
+		*amount.dest, err = ltcutil.NewAmount(amount.value)
+		if err != nil {
+			return err
+		}
 	}
-
-	g.TotalAmount = amount
 
 	return nil
 }
@@ -725,6 +942,10 @@ type TxRawResult struct {
 	Confirmations uint64 `json:"confirmations,omitempty"`
 	Time          int64  `json:"time,omitempty"`
 	Blocktime     int64  `json:"blocktime,omitempty"`
+
+	// InActiveChain is only set when the transaction was looked up in a
+	// provided block.
+	InActiveChain *bool `json:"in_active_chain,omitempty"`
 }
 
 // SearchRawTransactionsResult models the data from the searchrawtransaction
@@ -755,19 +976,83 @@ type TxRawDecodeResult struct {
 	Vout     []Vout `json:"vout"`
 }
 
+// DecodePsbtInput models an input of the data returned by the decodepsbt
+// command.  The MWEB fields are only set for inputs spending MWEB outputs.
+type DecodePsbtInput struct {
+	NonWitnessUtxo     *TxRawDecodeResult `json:"non_witness_utxo,omitempty"`
+	WitnessUtxo        *Vout              `json:"witness_utxo,omitempty"`
+	PartialSignatures  map[string]string  `json:"partial_signatures,omitempty"`
+	SigHash            string             `json:"sighash,omitempty"`
+	RedeemScript       *ScriptSig         `json:"redeem_script,omitempty"`
+	WitnessScript      *ScriptSig         `json:"witness_script,omitempty"`
+	FinalScriptSig     *ScriptSig         `json:"final_scriptSig,omitempty"`
+	FinalScriptWitness []string           `json:"final_scriptwitness,omitempty"`
+	MwebOutputID       string             `json:"mweb_output_id,omitempty"`
+	MwebAmount         *float64           `json:"mweb_amount,omitempty"`
+	Unknown            map[string]string  `json:"unknown,omitempty"`
+}
+
+// DecodePsbtOutput models an output of the data returned by the decodepsbt
+// command.  The stealth address is only set for MWEB outputs, and the peg-out
+// kernel only for transparent outputs paid by a peg-out of an MWEB kernel.
+type DecodePsbtOutput struct {
+	Amount           float64            `json:"amount"`
+	ScriptPubKey     ScriptPubKeyResult `json:"scriptPubKey"`
+	RedeemScript     *ScriptSig         `json:"redeem_script,omitempty"`
+	WitnessScript    *ScriptSig         `json:"witness_script,omitempty"`
+	StealthAddress   string             `json:"stealth_address,omitempty"`
+	MwebPegoutKernel *uint32            `json:"mweb_pegout_kernel,omitempty"`
+	Unknown          map[string]string  `json:"unknown,omitempty"`
+}
+
+// DecodePsbtKernel models an MWEB kernel of the data returned by the
+// decodepsbt command, including any peg-out outputs it commits to.
+type DecodePsbtKernel struct {
+	Fee         *float64 `json:"fee,omitempty"`
+	PeginAmount *float64 `json:"pegin_amount,omitempty"`
+	PegOuts     []Vout   `json:"pegouts,omitempty"`
+	LockHeight  *int32   `json:"lock_height,omitempty"`
+	Signed      bool     `json:"signed"`
+}
+
+// DecodePsbtResult models the data returned by the decodepsbt command.
+type DecodePsbtResult struct {
+	Tx          TxRawDecodeResult  `json:"tx"`
+	PsbtVersion uint32             `json:"psbt_version"`
+	Unknown     map[string]string  `json:"unknown"`
+	Inputs      []DecodePsbtInput  `json:"inputs"`
+	Outputs     []DecodePsbtOutput `json:"outputs"`
+	Kernels     []DecodePsbtKernel `json:"kernels,omitempty"`
+	Fee         *float64           `json:"fee,omitempty"`
+}
+
+// FinalizePsbtResult models the data returned by the finalizepsbt command.
+// Hex is only set when the PSBT is complete and extraction was requested,
+// otherwise Psbt holds the updated base64 encoded PSBT.
+type FinalizePsbtResult struct {
+	Psbt     string `json:"psbt,omitempty"`
+	Hex      string `json:"hex,omitempty"`
+	Complete bool   `json:"complete"`
+}
+
 // ValidateAddressChainResult models the data returned by the chain server
 // validateaddress command.
 //
-// Compared to the Litecoin Core version, this struct lacks the scriptPubKey
-// field since it requires wallet access, which is outside the scope of ltcd.
+// In addition to the fields of the Litecoin Core version, this struct includes
+// the type of the address and the network it belongs to, which is also set when
+// the address is invalid only because it belongs to another network.
 // Ref: https://bitcoincore.org/en/doc/0.20.0/rpc/util/validateaddress/
 type ValidateAddressChainResult struct {
 	IsValid        bool    `json:"isvalid"`
 	Address        string  `json:"address,omitempty"`
+	Type           string  `json:"type,omitempty"`
+	Network        string  `json:"network,omitempty"`
+	ScriptPubKey   string  `json:"scriptPubKey,omitempty"`
 	IsScript       *bool   `json:"isscript,omitempty"`
 	IsWitness      *bool   `json:"iswitness,omitempty"`
 	WitnessVersion *int32  `json:"witness_version,omitempty"`
 	WitnessProgram *string `json:"witness_program,omitempty"`
+	Error          string  `json:"error,omitempty"`
 }
 
 // EstimateSmartFeeResult models the data returned buy the chain server
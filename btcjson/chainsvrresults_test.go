@@ -107,8 +107,8 @@ func TestChainSvrCustomResults(t *testing.T) {
 	}
 }
 
-// TestGetTxOutSetInfoResult ensures that custom unmarshalling of
-// GetTxOutSetInfoResult works as intended.
+// TestGetTxOutSetInfoResult ensures that custom marshalling and unmarshalling
+// of GetTxOutSetInfoResult works as intended.
 func TestGetTxOutSetInfoResult(t *testing.T) {
 	t.Parallel()
 
@@ -152,6 +152,44 @@ func TestGetTxOutSetInfoResult(t *testing.T) {
 				}(),
 			},
 		},
+		{
+			name:   "GetTxOutSetInfoResult - supply audit",
+			result: `{"height":2,"bestblock":"000000000000005f94116250e2407310463c0a7cf950f1af9ebe935b1c0687ab","transactions":2,"txouts":3,"bogosize":150,"hash_serialized_2":"9a0a561203ff052182993bc5d0cb2c620880bfafdbd80331f65fd9546c3e5c3e","disk_size":120,"total_amount":99.5,"total_unspendable_amount":50.5,"total_subsidy_amount":150,"mweb_pegged_amount":1.25,"subsidy_eras":[{"start_height":0,"end_height":2,"subsidy":50,"blocks":3,"total_amount":150}]}`,
+			want: btcjson.GetTxOutSetInfoResult{
+				Height: 2,
+				BestBlock: func() chainhash.Hash {
+					h, err := chainhash.NewHashFromStr("000000000000005f94116250e2407310463c0a7cf950f1af9ebe935b1c0687ab")
+					if err != nil {
+						panic(err)
+					}
+
+					return *h
+				}(),
+				Transactions: 2,
+				TxOuts:       3,
+				BogoSize:     150,
+				HashSerialized: func() chainhash.Hash {
+					h, err := chainhash.NewHashFromStr("9a0a561203ff052182993bc5d0cb2c620880bfafdbd80331f65fd9546c3e5c3e")
+					if err != nil {
+						panic(err)
+					}
+
+					return *h
+				}(),
+				DiskSize:               120,
+				TotalAmount:            9950000000,
+				TotalUnspendableAmount: 5050000000,
+				TotalSubsidyAmount:     15000000000,
+				MwebPeggedAmount:       125000000,
+				SubsidyEras: []btcjson.TxOutSetSubsidyEra{{
+					StartHeight: 0,
+					EndHeight:   2,
+					Subsidy:     50,
+					Blocks:      3,
+					TotalAmount: 150,
+				}},
+			},
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -170,6 +208,26 @@ func TestGetTxOutSetInfoResult(t *testing.T) {
 				spew.Sdump(test.want))
 			continue
 		}
+
+		// Marshalling the result must round trip.
+		marshalled, err := json.Marshal(out)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected marshal error: %v", i,
+				test.name, err)
+			continue
+		}
+		var roundTrip btcjson.GetTxOutSetInfoResult
+		if err := json.Unmarshal(marshalled, &roundTrip); err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(roundTrip, test.want) {
+			t.Errorf("Test #%d (%s) unexpected round trip data - "+
+				"got %v, want %v", i, test.name, spew.Sdump(roundTrip),
+				spew.Sdump(test.want))
+			continue
+		}
 	}
 }
 
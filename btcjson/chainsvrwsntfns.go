@@ -61,6 +61,11 @@ const (
 	// Deprecated: Not used with rescanblocks command.
 	RescanProgressNtfnMethod = "rescanprogress"
 
+	// RescanBlockchainMatchNtfnMethod is the method used for notifications
+	// from the chain server that a rescanblockchain command found a
+	// relevant transaction.
+	RescanBlockchainMatchNtfnMethod = "rescanblockchainmatch"
+
 	// TxAcceptedNtfnMethod is the method used for notifications from the
 	// chain server that a transaction has been accepted into the mempool.
 	TxAcceptedNtfnMethod = "txaccepted"
@@ -75,6 +80,15 @@ const (
 	// from the chain server that inform a client that a transaction that
 	// matches the loaded filter was accepted by the mempool.
 	RelevantTxAcceptedNtfnMethod = "relevanttxaccepted"
+
+	// OperationProgressNtfnMethod is the method used for notifications
+	// from the chain server that an operation started by the client with
+	// the startoperation command has made progress or has finished.
+	OperationProgressNtfnMethod = "operationprogress"
+
+	// TxRemovedNtfnMethod is the method used for notifications from the
+	// chain server that a transaction has been removed from the mempool.
+	TxRemovedNtfnMethod = "txremoved"
 )
 
 // BlockConnectedNtfn defines the blockconnected JSON-RPC notification.
@@ -245,6 +259,20 @@ func NewRescanProgressNtfn(hash string, height int32, time int64) *RescanProgres
 	}
 }
 
+// RescanBlockchainMatchNtfn defines the rescanblockchainmatch JSON-RPC
+// notification.
+type RescanBlockchainMatchNtfn struct {
+	Match RescanBlockchainMatch
+}
+
+// NewRescanBlockchainMatchNtfn returns a new instance which can be used to
+// issue a rescanblockchainmatch JSON-RPC notification.
+func NewRescanBlockchainMatchNtfn(match RescanBlockchainMatch) *RescanBlockchainMatchNtfn {
+	return &RescanBlockchainMatchNtfn{
+		Match: match,
+	}
+}
+
 // TxAcceptedNtfn defines the txaccepted JSON-RPC notification.
 type TxAcceptedNtfn struct {
 	TxID   string
@@ -285,6 +313,40 @@ func NewRelevantTxAcceptedNtfn(txHex string) *RelevantTxAcceptedNtfn {
 	return &RelevantTxAcceptedNtfn{Transaction: txHex}
 }
 
+// OperationProgressNtfn defines the operationprogress JSON-RPC notification.
+type OperationProgressNtfn struct {
+	ID    string
+	State string
+	Done  int64
+	Total int64
+}
+
+// NewOperationProgressNtfn returns a new instance which can be used to issue
+// an operationprogress JSON-RPC notification.
+func NewOperationProgressNtfn(id, state string, done, total int64) *OperationProgressNtfn {
+	return &OperationProgressNtfn{
+		ID:    id,
+		State: state,
+		Done:  done,
+		Total: total,
+	}
+}
+
+// TxRemovedNtfn defines the txremoved JSON-RPC notification.
+type TxRemovedNtfn struct {
+	TxID   string
+	Reason string
+}
+
+// NewTxRemovedNtfn returns a new instance which can be used to issue a
+// txremoved JSON-RPC notification.
+func NewTxRemovedNtfn(txHash string, reason string) *TxRemovedNtfn {
+	return &TxRemovedNtfn{
+		TxID:   txHash,
+		Reason: reason,
+	}
+}
+
 func init() {
 	// The commands in this file are only usable by websockets and are
 	// notifications.
@@ -298,7 +360,10 @@ func init() {
 	MustRegisterCmd(RedeemingTxNtfnMethod, (*RedeemingTxNtfn)(nil), flags)
 	MustRegisterCmd(RescanFinishedNtfnMethod, (*RescanFinishedNtfn)(nil), flags)
 	MustRegisterCmd(RescanProgressNtfnMethod, (*RescanProgressNtfn)(nil), flags)
+	MustRegisterCmd(RescanBlockchainMatchNtfnMethod, (*RescanBlockchainMatchNtfn)(nil), flags)
 	MustRegisterCmd(TxAcceptedNtfnMethod, (*TxAcceptedNtfn)(nil), flags)
 	MustRegisterCmd(TxAcceptedVerboseNtfnMethod, (*TxAcceptedVerboseNtfn)(nil), flags)
 	MustRegisterCmd(RelevantTxAcceptedNtfnMethod, (*RelevantTxAcceptedNtfn)(nil), flags)
+	MustRegisterCmd(OperationProgressNtfnMethod, (*OperationProgressNtfn)(nil), flags)
+	MustRegisterCmd(TxRemovedNtfnMethod, (*TxRemovedNtfn)(nil), flags)
 }
@@ -168,6 +168,31 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Time:   12345678,
 			},
 		},
+		{
+			name: "rescanblockchainmatch",
+			newNtfn: func() (interface{}, error) {
+				return btcjson.NewCmd("rescanblockchainmatch", `{"txid":"123","hex":"001122","blockhash":"456","height":100,"index":2}`)
+			},
+			staticNtfn: func() interface{} {
+				return btcjson.NewRescanBlockchainMatchNtfn(btcjson.RescanBlockchainMatch{
+					TxID:      "123",
+					Hex:       "001122",
+					BlockHash: "456",
+					Height:    100,
+					Index:     2,
+				})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"rescanblockchainmatch","params":[{"txid":"123","hex":"001122","blockhash":"456","height":100,"index":2}],"id":null}`,
+			unmarshalled: &btcjson.RescanBlockchainMatchNtfn{
+				Match: btcjson.RescanBlockchainMatch{
+					TxID:      "123",
+					Hex:       "001122",
+					BlockHash: "456",
+					Height:    100,
+					Index:     2,
+				},
+			},
+		},
 		{
 			name: "txaccepted",
 			newNtfn: func() (interface{}, error) {
@@ -225,6 +250,36 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Transaction: "001122",
 			},
 		},
+		{
+			name: "operationprogress",
+			newNtfn: func() (interface{}, error) {
+				return btcjson.NewCmd("operationprogress", "1", "running", 5, 10)
+			},
+			staticNtfn: func() interface{} {
+				return btcjson.NewOperationProgressNtfn("1", "running", 5, 10)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"operationprogress","params":["1","running",5,10],"id":null}`,
+			unmarshalled: &btcjson.OperationProgressNtfn{
+				ID:    "1",
+				State: "running",
+				Done:  5,
+				Total: 10,
+			},
+		},
+		{
+			name: "txremoved",
+			newNtfn: func() (interface{}, error) {
+				return btcjson.NewCmd("txremoved", "123", "conflict")
+			},
+			staticNtfn: func() interface{} {
+				return btcjson.NewTxRemovedNtfn("123", "conflict")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"txremoved","params":["123","conflict"],"id":null}`,
+			unmarshalled: &btcjson.TxRemovedNtfn{
+				TxID:   "123",
+				Reason: "conflict",
+			},
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
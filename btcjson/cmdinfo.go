@@ -247,3 +247,27 @@ func MethodUsageText(method string) (string, error) {
 	registerLock.Unlock()
 	return usage, nil
 }
+
+// MethodParamNames returns the names of the params of the provided method in
+// the order they are marshalled, which are the names used in its one-line usage
+// and accepted by NewCmdNamed.  The provided method must be associated with a
+// registered type.  All commands provided by this package are registered by
+// default.
+func MethodParamNames(method string) ([]string, error) {
+	// Look up details about the provided method and error out if not
+	// registered.
+	registerLock.RLock()
+	rtp, ok := methodToConcreteType[method]
+	registerLock.RUnlock()
+	if !ok {
+		str := fmt.Sprintf("%q is not registered", method)
+		return nil, makeError(ErrUnregisteredMethod, str)
+	}
+
+	rt := rtp.Elem()
+	names := make([]string, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		names = append(names, strings.ToLower(rt.Field(i).Name))
+	}
+	return names, nil
+}
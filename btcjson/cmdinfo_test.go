@@ -200,6 +200,27 @@ func TestMethodUsageText(t *testing.T) {
 	}
 }
 
+// TestMethodParamNames ensures the MethodParamNames function returns the names
+// of the params in order along with the expected errors.
+func TestMethodParamNames(t *testing.T) {
+	t.Parallel()
+
+	names, err := btcjson.MethodParamNames("getblock")
+	if err != nil {
+		t.Fatalf("MethodParamNames: unexpected error: %v", err)
+	}
+	if want := []string{"hash", "verbosity"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("MethodParamNames: got %v, want %v", names, want)
+	}
+
+	_, err = btcjson.MethodParamNames("boguscommand")
+	if jerr, ok := err.(btcjson.Error); !ok ||
+		jerr.ErrorCode != btcjson.ErrUnregisteredMethod {
+
+		t.Fatalf("MethodParamNames: unexpected error: %v", err)
+	}
+}
+
 // TestFieldUsage tests the internal fieldUsage function ensure it returns the
 // expected text.
 func TestFieldUsage(t *testing.T) {
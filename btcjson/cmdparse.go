@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -554,3 +555,82 @@ func NewCmd(method string, args ...interface{}) (interface{}, error) {
 
 	return rvp.Interface(), nil
 }
+
+// NewCmdNamed provides the same functionality as NewCmd except the params are
+// specified by name rather than by position.  The names are the lowercase names
+// of the fields of the command as returned by MethodParamNames.
+//
+// All required params must be specified while any of the optional params may
+// be omitted.  Omitted optional params that precede a specified param are set
+// to their default value when they have one since they have to be marshalled
+// in order to specify the later param.
+func NewCmdNamed(method string, args map[string]interface{}) (interface{}, error) {
+	// Look up details about the provided method.  Any methods that aren't
+	// registered are an error.
+	registerLock.RLock()
+	rtp, ok := methodToConcreteType[method]
+	info := methodToInfo[method]
+	registerLock.RUnlock()
+	if !ok {
+		str := fmt.Sprintf("%q is not registered", method)
+		return nil, makeError(ErrUnregisteredMethod, str)
+	}
+
+	// Create the appropriate command type for the method.  Since all types
+	// are enforced to be a pointer to a struct at registration time, it's
+	// safe to indirect to the struct now.
+	rvp := reflect.New(rtp.Elem())
+	rv := rvp.Elem()
+	rt := rtp.Elem()
+
+	// Assign each of the specified params to the according struct field
+	// while keeping track of the last one so the omitted optional fields
+	// preceding it can be populated with their defaults.
+	lastParam := -1
+	numAssigned := 0
+	for i := 0; i < info.maxParams; i++ {
+		fieldName := strings.ToLower(rt.Field(i).Name)
+		arg, ok := args[fieldName]
+		if !ok {
+			if i < info.numReqParams {
+				str := fmt.Sprintf("missing required param %q",
+					fieldName)
+				return nil, makeError(ErrNumParams, str)
+			}
+			continue
+		}
+
+		err := assignField(i+1, fieldName, rv.Field(i),
+			reflect.ValueOf(arg))
+		if err != nil {
+			return nil, err
+		}
+		lastParam = i
+		numAssigned++
+	}
+
+	// Ensure all of the specified params were recognized.
+	if numAssigned != len(args) {
+		unknown := make([]string, 0, len(args)-numAssigned)
+		for name := range args {
+			if _, ok := rt.FieldByNameFunc(func(field string) bool {
+				return strings.ToLower(field) == name
+			}); !ok {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		str := fmt.Sprintf("unknown params for method %q: %s", method,
+			strings.Join(unknown, ", "))
+		return nil, makeError(ErrUnknownParam, str)
+	}
+
+	for i := info.numReqParams; i < lastParam; i++ {
+		rvf := rv.Field(i)
+		if defaultVal, ok := info.defaults[i]; ok && rvf.IsNil() {
+			rvf.Set(defaultVal)
+		}
+	}
+
+	return rvp.Interface(), nil
+}
@@ -422,6 +422,127 @@ func TestNewCmdErrors(t *testing.T) {
 	}
 }
 
+// TestNewCmdNamed ensures commands created from named params are populated as
+// expected, including the defaults of the omitted params preceding a specified
+// param.
+func TestNewCmdNamed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		method   string
+		args     map[string]interface{}
+		expected interface{}
+	}{
+		{
+			name:   "required only",
+			method: "getblock",
+			args:   map[string]interface{}{"hash": "123"},
+			expected: &btcjson.GetBlockCmd{
+				Hash: "123",
+			},
+		},
+		{
+			name:   "required and optional",
+			method: "getblock",
+			args: map[string]interface{}{
+				"verbosity": "2",
+				"hash":      "123",
+			},
+			expected: &btcjson.GetBlockCmd{
+				Hash:      "123",
+				Verbosity: btcjson.Int(2),
+			},
+		},
+		{
+			name:   "omitted params with defaults",
+			method: "searchrawtransactions",
+			args: map[string]interface{}{
+				"address": "1Address",
+				"count":   "5",
+			},
+			expected: &btcjson.SearchRawTransactionsCmd{
+				Address: "1Address",
+				Verbose: btcjson.Int(1),
+				Skip:    btcjson.Int(0),
+				Count:   btcjson.Int(5),
+			},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		cmd, err := btcjson.NewCmdNamed(test.method, test.args)
+		if err != nil {
+			t.Errorf("Test #%d (%s) unexpected error: %v", i,
+				test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(cmd, test.expected) {
+			t.Errorf("Test #%d (%s) unexpected command - got %#v, "+
+				"want %#v", i, test.name, cmd, test.expected)
+			continue
+		}
+	}
+}
+
+// TestNewCmdNamedErrors ensures the NewCmdNamed function returns the expected
+// errors.
+func TestNewCmdNamedErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method string
+		args   map[string]interface{}
+		err    btcjson.Error
+	}{
+		{
+			name:   "unregistered command",
+			method: "boguscommand",
+			err:    btcjson.Error{ErrorCode: btcjson.ErrUnregisteredMethod},
+		},
+		{
+			name:   "missing required parameter",
+			method: "getblock",
+			args:   map[string]interface{}{"verbosity": 1},
+			err:    btcjson.Error{ErrorCode: btcjson.ErrNumParams},
+		},
+		{
+			name:   "unknown parameter",
+			method: "getblock",
+			args: map[string]interface{}{
+				"hash":    "123",
+				"verbose": true,
+			},
+			err: btcjson.Error{ErrorCode: btcjson.ErrUnknownParam},
+		},
+		{
+			name:   "incorrect parameter type",
+			method: "getblock",
+			args:   map[string]interface{}{"hash": 1},
+			err:    btcjson.Error{ErrorCode: btcjson.ErrInvalidType},
+		},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		_, err := btcjson.NewCmdNamed(test.method, test.args)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.err) {
+			t.Errorf("Test #%d (%s) wrong error - got %T (%v), "+
+				"want %T", i, test.name, err, err, test.err)
+			continue
+		}
+		gotErrorCode := err.(btcjson.Error).ErrorCode
+		if gotErrorCode != test.err.ErrorCode {
+			t.Errorf("Test #%d (%s) mismatched error code - got "+
+				"%v (%v), want %v", i, test.name, gotErrorCode,
+				err, test.err.ErrorCode)
+			continue
+		}
+	}
+}
+
 // TestMarshalCmd tests the MarshalCmd function.
 func TestMarshalCmd(t *testing.T) {
 	t.Parallel()
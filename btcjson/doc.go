@@ -93,6 +93,10 @@ obviously, run-time which means any mistakes won't be found until the code is
 actually executed.  However, it is quite useful for user-supplied commands
 that are intentionally dynamic.
 
+The NewCmdNamed function works the same way except the parameters are provided
+by name, as returned by the MethodParamNames function, so any of the optional
+parameters can be omitted.
+
 # Custom Command Registration
 
 The command handling of this package is built around the concept of registered
@@ -122,6 +126,14 @@ generate the final help text.
 In addition, the MethodUsageText function is provided to generate consistent
 one-line usage for registered commands and notifications using reflection.
 
+Custom commands, such as the commands of forks which aren't provided by this
+package, can ship their help along with them by registering their descriptions
+and result types with the RegisterCmdHelp function.  GenerateHelp falls back to
+the registered descriptions for keys missing from the descriptions it is passed,
+and the registered result types can be obtained with the RegisteredCmdHelp
+function, so RPC servers can provide help for such commands without knowing
+about them in advance.
+
 # Errors
 
 There are 2 distinct type of errors supported by this package:
@@ -490,6 +490,9 @@ func isValidResultType(kind reflect.Kind) bool {
 // and the result key for a given result type is only required if it's not an
 // object.
 //
+// Descriptions which are not in the provided map are looked up in the
+// descriptions registered for the method with RegisterCmdHelp, if any.
+//
 // For example, consider the 'help' command itself.  There are two possible
 // returns depending on the provided parameters.  So, the help would be
 // generated by calling the function as follows:
@@ -510,41 +513,29 @@ func GenerateHelp(method string, descs map[string]string, resultTypes ...interfa
 	registerLock.RLock()
 	rtp, ok := methodToConcreteType[method]
 	info := methodToInfo[method]
+	registeredHelp := methodToHelp[method]
 	registerLock.RUnlock()
 	if !ok {
 		str := fmt.Sprintf("%q is not registered", method)
 		return "", makeError(ErrUnregisteredMethod, str)
 	}
 
-	// Validate each result type is a pointer to a supported type (or nil).
-	for i, resultType := range resultTypes {
-		if resultType == nil {
-			continue
-		}
-
-		rtp := reflect.TypeOf(resultType)
-		if rtp.Kind() != reflect.Ptr {
-			str := fmt.Sprintf("result #%d (%v) is not a pointer",
-				i, rtp.Kind())
-			return "", makeError(ErrInvalidType, str)
-		}
-
-		elemKind := rtp.Elem().Kind()
-		if !isValidResultType(elemKind) {
-			str := fmt.Sprintf("result #%d (%v) is not an allowed "+
-				"type", i, elemKind)
-			return "", makeError(ErrInvalidType, str)
-		}
+	if err := validateResultTypes(resultTypes); err != nil {
+		return "", err
 	}
 
 	// Create a closure for the description lookup function which falls back
-	// to the base help descriptions map for unrecognized keys and tracks
-	// and missing keys.
+	// to the descriptions registered for the method and then to the base
+	// help descriptions map for unrecognized keys and tracks and missing
+	// keys.
 	var missingKey string
 	xT := func(key string) string {
 		if desc, ok := descs[key]; ok {
 			return desc
 		}
+		if desc, ok := registeredHelp.descs[key]; ok {
+			return desc
+		}
 		if desc, ok := baseHelpDescs[key]; ok {
 			return desc
 		}
@@ -560,3 +551,103 @@ func GenerateHelp(method string, descs map[string]string, resultTypes ...interfa
 	}
 	return help, nil
 }
+
+// validateResultTypes ensures each of the passed result types is a pointer to a
+// supported type (or nil).
+func validateResultTypes(resultTypes []interface{}) error {
+	for i, resultType := range resultTypes {
+		if resultType == nil {
+			continue
+		}
+
+		rtp := reflect.TypeOf(resultType)
+		if rtp.Kind() != reflect.Ptr {
+			str := fmt.Sprintf("result #%d (%v) is not a pointer",
+				i, rtp.Kind())
+			return makeError(ErrInvalidType, str)
+		}
+
+		elemKind := rtp.Elem().Kind()
+		if !isValidResultType(elemKind) {
+			str := fmt.Sprintf("result #%d (%v) is not an allowed "+
+				"type", i, elemKind)
+			return makeError(ErrInvalidType, str)
+		}
+	}
+
+	return nil
+}
+
+// cmdHelp houses the help descriptions and result types registered for a
+// method with RegisterCmdHelp.
+type cmdHelp struct {
+	descs       map[string]string
+	resultTypes []interface{}
+}
+
+// methodToHelp maps the methods to the help registered for them.  It is
+// protected by the registerLock along with the other registration maps.
+var methodToHelp = make(map[string]cmdHelp)
+
+// RegisterCmdHelp registers the help descriptions and the result types of a
+// command which has already been registered with RegisterCmd.  This allows
+// callers such as forks which provide their own commands to ship the help for
+// them along with the commands, so RPC servers can generate it without knowing
+// about the commands in advance.
+//
+// The descriptions use the same keys as the descriptions passed to
+// GenerateHelp, which falls back to the registered descriptions for keys which
+// are not in the map it is passed.  The result types are returned by
+// RegisteredCmdHelp and are subject to the same rules as the result types
+// passed to GenerateHelp.
+//
+// Registering help for a method which already has help registered replaces it.
+func RegisterCmdHelp(method string, descs map[string]string, resultTypes ...interface{}) error {
+	if err := validateResultTypes(resultTypes); err != nil {
+		return err
+	}
+
+	registerLock.Lock()
+	defer registerLock.Unlock()
+
+	if _, ok := methodToConcreteType[method]; !ok {
+		str := fmt.Sprintf("%q is not registered", method)
+		return makeError(ErrUnregisteredMethod, str)
+	}
+
+	// Copy the descriptions and result types so the caller can't modify
+	// them once they are registered.
+	help := cmdHelp{
+		descs:       make(map[string]string, len(descs)),
+		resultTypes: append([]interface{}{}, resultTypes...),
+	}
+	for k, v := range descs {
+		help.descs[k] = v
+	}
+	methodToHelp[method] = help
+	return nil
+}
+
+// MustRegisterCmdHelp performs the same function as RegisterCmdHelp except it
+// panics if there is an error.  This should only be called from package init
+// functions.
+func MustRegisterCmdHelp(method string, descs map[string]string, resultTypes ...interface{}) {
+	if err := RegisterCmdHelp(method, descs, resultTypes...); err != nil {
+		panic(fmt.Sprintf("failed to register help for %q: %v\n",
+			method, err))
+	}
+}
+
+// RegisteredCmdHelp returns the result types registered for the provided method
+// with RegisterCmdHelp along with whether any help is registered for it.  The
+// returned result types are meant to be passed to GenerateHelp.
+func RegisteredCmdHelp(method string) ([]interface{}, bool) {
+	registerLock.RLock()
+	defer registerLock.RUnlock()
+
+	help, ok := methodToHelp[method]
+	if !ok {
+		return nil, false
+	}
+	return append([]interface{}{}, help.resultTypes...), true
+}
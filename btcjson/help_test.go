@@ -735,3 +735,61 @@ func TestGenerateHelp(t *testing.T) {
 			help, wantHelp)
 	}
 }
+
+// TestRegisterCmdHelp ensures the help registered for a command is used when
+// generating its help and that the registered result types are returned.
+func TestRegisterCmdHelp(t *testing.T) {
+	t.Parallel()
+
+	type testHelpCmd struct {
+		Count *int
+	}
+
+	const method = "testhelpcmd"
+	err := btcjson.RegisterCmdHelp(method, nil)
+	if !reflect.DeepEqual(err, btcjson.Error{
+		ErrorCode:   btcjson.ErrUnregisteredMethod,
+		Description: `"testhelpcmd" is not registered`,
+	}) {
+		t.Fatalf("RegisterCmdHelp: unexpected error for unregistered "+
+			"method: %v", err)
+	}
+
+	btcjson.MustRegisterCmd(method, (*testHelpCmd)(nil), 0)
+	err = btcjson.RegisterCmdHelp(method, nil, 0)
+	if jerr, ok := err.(btcjson.Error); !ok ||
+		jerr.ErrorCode != btcjson.ErrInvalidType {
+
+		t.Fatalf("RegisterCmdHelp: unexpected error for invalid "+
+			"result type: %v", err)
+	}
+	if _, ok := btcjson.RegisteredCmdHelp(method); ok {
+		t.Fatal("RegisteredCmdHelp: help registered after error")
+	}
+
+	descs := map[string]string{
+		"testhelpcmd--synopsis": "registered",
+		"testhelpcmd-count":     "registered",
+		"testhelpcmd--result0":  "registered",
+	}
+	btcjson.MustRegisterCmdHelp(method, descs, (*int)(nil))
+	resultTypes, ok := btcjson.RegisteredCmdHelp(method)
+	if !ok || !reflect.DeepEqual(resultTypes, []interface{}{(*int)(nil)}) {
+		t.Fatalf("RegisteredCmdHelp: unexpected result types %v", resultTypes)
+	}
+
+	// The passed descriptions take precedence over the registered ones.
+	help, err := btcjson.GenerateHelp(method, map[string]string{
+		"testhelpcmd--synopsis": "passed",
+	}, resultTypes...)
+	if err != nil {
+		t.Fatalf("GenerateHelp: unexpected error: %v", err)
+	}
+	wantHelp := "testhelpcmd (count)\n\n" +
+		"passed\n\nArguments:\n1. count (numeric, optional) registered\n\n" +
+		"Result:\nn (numeric) registered\n"
+	if help != wantHelp {
+		t.Fatalf("GenerateHelp: unexpected help - got\n%v\nwant\n%v",
+			help, wantHelp)
+	}
+}
@@ -186,4 +186,10 @@ const (
 const (
 	ErrRPCNoWallet      RPCErrorCode = -1
 	ErrRPCUnimplemented RPCErrorCode = -1
+
+	// ErrRPCLimitExceeded indicates that the request was rejected because
+	// the client exceeded its rate limit or because too many requests are
+	// being processed concurrently.  The code is in the range reserved for
+	// implementation-defined server errors by the JSON-RPC 2.0 spec.
+	ErrRPCLimitExceeded RPCErrorCode = -32005
 )
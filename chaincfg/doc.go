@@ -58,4 +58,7 @@
 // non-standard network.  As a general rule of thumb, all network parameters
 // should be unique to the network, but parameter collisions can still occur
 // (unfortunately, this is the case with regtest and testnet sharing magics).
+// Networks which only differ from a standard network in a few parameters, such
+// as test scenarios using the main network consensus rules with a shorter
+// coinbase maturity, may be built from it with NewParamsOverride instead.
 package chaincfg
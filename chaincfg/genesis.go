@@ -201,3 +201,35 @@ var sigNetGenesisBlock = wire.MsgBlock{
 	},
 	Transactions: []*wire.MsgTx{&genesisCoinbaseTx},
 }
+
+// sigNetGenesisBits is the difficulty of the genesis blocks of custom signet
+// networks.  It is the compact form of the signet proof of work limit.
+const sigNetGenesisBits = 0x1e0377ae
+
+// SignetGenesisBlock returns the genesis block of a custom signet network
+// defined by the passed challenge with the given timestamp and nonce.  The
+// coinbase commits to the challenge so every signet has a distinct genesis
+// block.
+//
+// The nonce must be chosen such that the proof of work hash of the block
+// satisfies the signet proof of work limit, which is what the gensignet tool
+// does.
+func SignetGenesisBlock(challenge []byte, timestamp time.Time, nonce uint32) *wire.MsgBlock {
+	coinbase := genesisCoinbaseTx.Copy()
+	challengeHash := chainhash.DoubleHashB(challenge)
+	coinbase.TxIn[0].SignatureScript = append(
+		[]byte{byte(len(challengeHash))}, challengeHash...,
+	)
+
+	return &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:    1,
+			PrevBlock:  chainhash.Hash{},
+			MerkleRoot: coinbase.TxHash(),
+			Timestamp:  time.Unix(timestamp.Unix(), 0),
+			Bits:       sigNetGenesisBits,
+			Nonce:      nonce,
+		},
+		Transactions: []*wire.MsgTx{coinbase},
+	}
+}
@@ -6,7 +6,9 @@ package chaincfg
 
 import (
 	"bytes"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 )
@@ -145,6 +147,51 @@ func TestSimNetGenesisBlock(t *testing.T) {
 // 	}
 // }
 
+// TestCustomSignetGenesisBlock ensures the genesis blocks of custom signet
+// networks are mined at the signet proof of work limit, commit to their
+// challenge and are used by the resulting network parameters.
+func TestCustomSignetGenesisBlock(t *testing.T) {
+	timestamp := time.Unix(1700000000, 0)
+	genesis := SignetGenesisBlock(DefaultSignetChallenge, timestamp, 1)
+
+	// The difficulty must be the compact form of the proof of work limit.
+	bits := genesis.Header.Bits
+	target := new(big.Int).Lsh(big.NewInt(int64(bits&0x007fffff)),
+		8*(uint(bits>>24)-3))
+	if target.Cmp(sigNetPowLimit) != 0 {
+		t.Fatalf("genesis target %064x is not the signet proof of "+
+			"work limit %064x", target, sigNetPowLimit)
+	}
+	merkleRoot := genesis.Transactions[0].TxHash()
+	if genesis.Header.MerkleRoot != merkleRoot {
+		t.Fatalf("genesis merkle root %v is not the coinbase hash %v",
+			genesis.Header.MerkleRoot, merkleRoot)
+	}
+	if !genesis.Header.Timestamp.Equal(timestamp) ||
+		genesis.Header.Nonce != 1 {
+
+		t.Fatalf("genesis header has timestamp %v and nonce %d",
+			genesis.Header.Timestamp, genesis.Header.Nonce)
+	}
+
+	// Signets with different challenges have distinct genesis blocks.
+	other := SignetGenesisBlock([]byte{0x51}, timestamp, 1)
+	if genesis.BlockHash() == other.BlockHash() {
+		t.Fatal("genesis blocks of different challenges are equal")
+	}
+
+	params := CustomSignetParamsWithGenesis(DefaultSignetChallenge, nil,
+		genesis)
+	hash := genesis.BlockHash()
+	if params.GenesisBlock != genesis || !params.GenesisHash.IsEqual(&hash) {
+		t.Fatalf("params genesis hash %v, want %v", params.GenesisHash,
+			hash)
+	}
+	if params.Net != SigNetParams.Net {
+		t.Fatalf("params net %v, want %v", params.Net, SigNetParams.Net)
+	}
+}
+
 // genesisBlockBytes are the wire encoded bytes for the genesis block of the
 // main network as of protocol version 60002.
 var genesisBlockBytes = []byte{
@@ -0,0 +1,201 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package genesistool builds and mines genesis blocks for custom networks.
+
+A genesis block is created from a coinbase message, the public key paid by the
+coinbase output, a timestamp and the proof of work bits with NewBlock.  Solve
+then searches for a nonce which makes the block satisfy the proof of work limit
+of the network it is created for.
+
+The resulting block can either be written as Go source in the style of the
+chaincfg package with WriteGo, or as JSON with WriteJSON.  The JSON document is
+loaded back into network parameters with LoadParams, so devnets can be spun up
+without editing the chaincfg package.
+*/
+package genesistool
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// coinbaseFlags is the value pushed at the start of the signature script of a
+// genesis coinbase.  It is the one used by all of the default networks.
+const coinbaseFlags = 486604799
+
+// DefaultReward is the amount paid by the coinbase of a genesis block when no
+// other reward is configured.  It matches the genesis blocks of the default
+// networks.
+const DefaultReward int64 = 50 * ltcutil.SatoshiPerBitcoin
+
+// Config describes the genesis block of a custom network.
+type Config struct {
+	// Message is embedded in the signature script of the coinbase.
+	Message string
+
+	// PubKey is the serialized public key paid by the coinbase output.
+	PubKey []byte
+
+	// Reward is the amount paid by the coinbase output in satoshi.
+	Reward int64
+
+	// Timestamp is the time of the genesis block.
+	Timestamp time.Time
+
+	// Bits is the proof of work difficulty of the genesis block in its
+	// compact form.
+	Bits uint32
+}
+
+// NewBlock returns the genesis block described by the passed config with a
+// nonce of zero.  Use Solve to find a nonce which satisfies the proof of work
+// limit of the network.
+func NewBlock(cfg *Config) (*wire.MsgBlock, error) {
+	if len(cfg.Message) == 0 {
+		return nil, errors.New("the coinbase message must not be empty")
+	}
+	if _, err := btcec.ParsePubKey(cfg.PubKey); err != nil {
+		return nil, err
+	}
+
+	// The signature script follows the layout of the default genesis
+	// blocks, which push the flags, the number four and the message.  The
+	// number four is not pushed as a small integer opcode.
+	sigScript, err := txscript.NewScriptBuilder().
+		AddInt64(coinbaseFlags).
+		Script()
+	if err != nil {
+		return nil, err
+	}
+	msgScript, err := txscript.NewScriptBuilder().
+		AddData([]byte(cfg.Message)).
+		Script()
+	if err != nil {
+		return nil, err
+	}
+	sigScript = append(sigScript, txscript.OP_DATA_1, 4)
+	sigScript = append(sigScript, msgScript...)
+	pkScript, err := txscript.NewScriptBuilder().
+		AddData(cfg.PubKey).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		return nil, err
+	}
+
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		SignatureScript:  sigScript,
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(cfg.Reward, pkScript))
+
+	return &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:    1,
+			PrevBlock:  chainhash.Hash{},
+			MerkleRoot: coinbase.TxHash(),
+			Timestamp:  time.Unix(cfg.Timestamp.Unix(), 0),
+			Bits:       cfg.Bits,
+		},
+		Transactions: []*wire.MsgTx{coinbase},
+	}, nil
+}
+
+// CheckProofOfWork returns whether or not the proof of work hash of the passed
+// header is at or below its target difficulty.
+func CheckProofOfWork(header *wire.BlockHeader) bool {
+	target := blockchain.CompactToBig(header.Bits)
+	powHash := header.PowHash()
+	return blockchain.HashToBig(&powHash).Cmp(target) <= 0
+}
+
+// Solve searches for a nonce which makes the proof of work hash of the passed
+// block satisfy its target difficulty and updates the header of the block with
+// it.  The nonce space is searched concurrently by one worker per CPU and the
+// timestamp is increased by a second whenever it has been exhausted.
+//
+// An error is returned when the target difficulty of the block is not within
+// the passed proof of work limit.
+func Solve(msgBlock *wire.MsgBlock, powLimit *big.Int) error {
+	target := blockchain.CompactToBig(msgBlock.Header.Bits)
+	if target.Sign() <= 0 || target.Cmp(powLimit) > 0 {
+		return errors.New("the target difficulty of the block is not " +
+			"within the proof of work limit")
+	}
+
+	numWorkers := uint32(runtime.NumCPU())
+	for {
+		var (
+			wg    sync.WaitGroup
+			once  sync.Once
+			found = make(chan struct{})
+			nonce uint32
+		)
+		for i := uint32(0); i < numWorkers; i++ {
+			wg.Add(1)
+			go func(header wire.BlockHeader, start uint32) {
+				defer wg.Done()
+				solveNonceRange(&header, target, start, numWorkers,
+					found, func(n uint32) {
+						once.Do(func() {
+							nonce = n
+							close(found)
+						})
+					})
+			}(msgBlock.Header, i)
+		}
+		wg.Wait()
+
+		select {
+		case <-found:
+			msgBlock.Header.Nonce = nonce
+			return nil
+		default:
+		}
+		msgBlock.Header.Timestamp = msgBlock.Header.Timestamp.Add(time.Second)
+	}
+}
+
+// solveNonceRange searches the nonces of the passed header starting at start
+// in increments of step for one whose proof of work hash is at or below the
+// target.  The solution is passed to solved.  The search stops when the nonce
+// space is exhausted or the found channel is closed.
+func solveNonceRange(header *wire.BlockHeader, target *big.Int, start,
+	step uint32, found chan struct{}, solved func(uint32)) {
+
+	var tries uint32
+	for nonce := uint64(start); nonce <= math.MaxUint32; nonce += uint64(step) {
+		// Check for a solution from the other workers periodically.
+		tries++
+		if tries%1024 == 0 {
+			select {
+			case <-found:
+				return
+			default:
+			}
+		}
+
+		header.Nonce = uint32(nonce)
+		powHash := header.PowHash()
+		if blockchain.HashToBig(&powHash).Cmp(target) <= 0 {
+			solved(header.Nonce)
+			return
+		}
+	}
+}
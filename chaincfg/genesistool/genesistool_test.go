@@ -0,0 +1,164 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package genesistool
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+)
+
+// newTestGenesis returns the config of a genesis block at the regression test
+// network proof of work limit.
+func newTestGenesis(t *testing.T) *Config {
+	t.Helper()
+
+	privKey, _ := btcec.PrivKeyFromBytes([]byte{0x01, 0x02, 0x03})
+	return &Config{
+		Message:   "Doriancoin devnet genesis",
+		PubKey:    privKey.PubKey().SerializeUncompressed(),
+		Reward:    DefaultReward,
+		Timestamp: time.Unix(1700000000, 0),
+		Bits:      0x207fffff,
+	}
+}
+
+// TestSolve ensures genesis blocks are built from their config and solved
+// within the proof of work limit of the network.
+func TestSolve(t *testing.T) {
+	cfg := newTestGenesis(t)
+	genesis, err := NewBlock(cfg)
+	if err != nil {
+		t.Fatalf("NewBlock: unexpected error: %v", err)
+	}
+	coinbase := genesis.Transactions[0]
+	if genesis.Header.MerkleRoot != coinbase.TxHash() {
+		t.Fatalf("merkle root %v is not the coinbase hash %v",
+			genesis.Header.MerkleRoot, coinbase.TxHash())
+	}
+	if !bytes.Contains(coinbase.TxIn[0].SignatureScript,
+		[]byte(cfg.Message)) {
+
+		t.Fatalf("coinbase signature script does not contain the message")
+	}
+	if coinbase.TxOut[0].Value != DefaultReward {
+		t.Fatalf("coinbase reward %d, want %d", coinbase.TxOut[0].Value,
+			DefaultReward)
+	}
+
+	powLimit := chaincfg.RegressionNetParams.PowLimit
+	if err := Solve(genesis, powLimit); err != nil {
+		t.Fatalf("Solve: unexpected error: %v", err)
+	}
+	if !CheckProofOfWork(&genesis.Header) {
+		t.Fatalf("solved genesis block does not satisfy its target")
+	}
+
+	// The target may not exceed the proof of work limit of the network.
+	if err := Solve(genesis, chaincfg.MainNetParams.PowLimit); err == nil {
+		t.Fatalf("Solve: solved a block above the proof of work limit")
+	}
+
+	// Invalid configs are rejected.
+	badCfg := *cfg
+	badCfg.Message = ""
+	if _, err := NewBlock(&badCfg); err == nil {
+		t.Fatalf("NewBlock: accepted an empty message")
+	}
+	badCfg = *cfg
+	badCfg.PubKey = []byte{0x02, 0x03}
+	if _, err := NewBlock(&badCfg); err == nil {
+		t.Fatalf("NewBlock: accepted an invalid public key")
+	}
+}
+
+// TestLoadParams ensures networks written as JSON are loaded into parameters
+// based on another network and that invalid documents are rejected.
+func TestLoadParams(t *testing.T) {
+	genesis, err := NewBlock(newTestGenesis(t))
+	if err != nil {
+		t.Fatalf("NewBlock: unexpected error: %v", err)
+	}
+	base := &chaincfg.RegressionNetParams
+	if err := Solve(genesis, base.PowLimit); err != nil {
+		t.Fatalf("Solve: unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = WriteJSON(&buf, "devnet", 0xd9b4bef9, "19555", genesis)
+	if err != nil {
+		t.Fatalf("WriteJSON: unexpected error: %v", err)
+	}
+	doc := buf.String()
+
+	params, err := LoadParams(strings.NewReader(doc), base)
+	if err != nil {
+		t.Fatalf("LoadParams: unexpected error: %v", err)
+	}
+	genesisHash := genesis.BlockHash()
+	if !params.GenesisHash.IsEqual(&genesisHash) ||
+		params.GenesisBlock.BlockHash() != genesisHash {
+
+		t.Fatalf("loaded genesis hash %v, want %v", params.GenesisHash,
+			genesisHash)
+	}
+	if params.Name != "devnet" || params.Net != 0xd9b4bef9 ||
+		params.DefaultPort != "19555" {
+
+		t.Fatalf("unexpected network %q with magic %v and port %s",
+			params.Name, params.Net, params.DefaultPort)
+	}
+	if params.CoinbaseMaturity != base.CoinbaseMaturity ||
+		base.GenesisHash.IsEqual(&genesisHash) {
+
+		t.Fatalf("loaded params do not copy the base network")
+	}
+
+	// A document whose genesis block does not match its hash is rejected.
+	badDoc := strings.Replace(doc, genesisHash.String(),
+		base.GenesisHash.String(), 1)
+	if _, err := LoadParams(strings.NewReader(badDoc), base); err == nil {
+		t.Fatalf("LoadParams: accepted a mismatched genesis hash")
+	}
+
+	// The genesis block must be within the limit of the base network.
+	_, err = LoadParams(strings.NewReader(doc), &chaincfg.MainNetParams)
+	if err == nil {
+		t.Fatalf("LoadParams: accepted a genesis block above the " +
+			"proof of work limit")
+	}
+}
+
+// TestWriteGo ensures genesis blocks are written as valid Go source.
+func TestWriteGo(t *testing.T) {
+	genesis, err := NewBlock(newTestGenesis(t))
+	if err != nil {
+		t.Fatalf("NewBlock: unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGo(&buf, "devNet", genesis); err != nil {
+		t.Fatalf("WriteGo: unexpected error: %v", err)
+	}
+	src := "package chaincfg\n\n" + buf.String()
+	_, err = parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		t.Fatalf("WriteGo: invalid source: %v\n%s", err, src)
+	}
+	for _, name := range []string{"devNetGenesisCoinbaseTx",
+		"devNetGenesisHash", "devNetGenesisMerkleRoot",
+		"devNetGenesisBlock"} {
+
+		if !strings.Contains(src, "var "+name+" =") {
+			t.Errorf("WriteGo: %s is not declared", name)
+		}
+	}
+}
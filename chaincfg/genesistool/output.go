@@ -0,0 +1,198 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package genesistool
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// Network is the JSON representation of a custom network as written by
+// WriteJSON and loaded by LoadParams.
+type Network struct {
+	Name        string `json:"name"`
+	Net         uint32 `json:"net"`
+	DefaultPort string `json:"defaultport,omitempty"`
+	GenesisHash string `json:"genesishash"`
+	Genesis     string `json:"genesis"`
+}
+
+// WriteJSON writes the JSON representation of a custom network with the passed
+// name, network magic, default port and genesis block to w.  The default port
+// is optional.
+func WriteJSON(w io.Writer, name string, net wire.BitcoinNet,
+	defaultPort string, genesis *wire.MsgBlock) error {
+
+	var buf bytes.Buffer
+	if err := genesis.Serialize(&buf); err != nil {
+		return err
+	}
+	network := Network{
+		Name:        name,
+		Net:         uint32(net),
+		DefaultPort: defaultPort,
+		GenesisHash: genesis.BlockHash().String(),
+		Genesis:     hex.EncodeToString(buf.Bytes()),
+	}
+	b, err := json.MarshalIndent(&network, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// LoadParams reads the JSON representation of a custom network as written by
+// WriteJSON from r and returns a copy of the passed base network parameters
+// which uses its name, network magic, default port and genesis block.  All
+// other parameters, such as the consensus rules and address encodings, are
+// those of the base network.
+//
+// The genesis block must match the genesis hash of the document and satisfy
+// the proof of work limit of the base network.
+func LoadParams(r io.Reader, base *chaincfg.Params) (*chaincfg.Params, error) {
+	var network Network
+	if err := json.NewDecoder(r).Decode(&network); err != nil {
+		return nil, err
+	}
+	if network.Name == "" {
+		return nil, fmt.Errorf("network does not have a name")
+	}
+
+	serialized, err := hex.DecodeString(network.Genesis)
+	if err != nil {
+		return nil, fmt.Errorf("invalid genesis block: %v", err)
+	}
+	var genesis wire.MsgBlock
+	if err := genesis.Deserialize(bytes.NewReader(serialized)); err != nil {
+		return nil, fmt.Errorf("invalid genesis block: %v", err)
+	}
+	genesisHash := genesis.BlockHash()
+	if genesisHash.String() != network.GenesisHash {
+		return nil, fmt.Errorf("genesis block hash %v does not match "+
+			"the expected hash %v", genesisHash, network.GenesisHash)
+	}
+	target := blockchain.CompactToBig(genesis.Header.Bits)
+	if target.Cmp(base.PowLimit) > 0 || !CheckProofOfWork(&genesis.Header) {
+		return nil, fmt.Errorf("genesis block %v does not satisfy the "+
+			"proof of work limit of the %s network", genesisHash,
+			base.Name)
+	}
+
+	params := *base
+	params.Name = network.Name
+	params.Net = wire.BitcoinNet(network.Net)
+	if network.DefaultPort != "" {
+		params.DefaultPort = network.DefaultPort
+	}
+	params.GenesisBlock = &genesis
+	params.GenesisHash = &genesisHash
+	return &params, nil
+}
+
+// writeGoBytes writes the passed bytes as the elements of a Go byte slice or
+// array literal with eight bytes per line.
+func writeGoBytes(w io.Writer, b []byte) {
+	for i := 0; i < len(b); i += 8 {
+		end := i + 8
+		if end > len(b) {
+			end = len(b)
+		}
+		for j := i; j < end; j++ {
+			fmt.Fprintf(w, "0x%02x, ", b[j])
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// writeGoHash writes the declaration of a Go hash variable with the passed
+// name and value in the style of the chaincfg package.
+func writeGoHash(w io.Writer, name, doc string, hash *chainhash.Hash) {
+	fmt.Fprintf(w, "// %s is the %s.\n", name, doc)
+	fmt.Fprintf(w, "var %s = chainhash.Hash([chainhash.HashSize]byte{ "+
+		"// Make go vet happy.\n", name)
+	writeGoBytes(w, hash[:])
+	fmt.Fprintf(w, "})\n\n")
+}
+
+// WriteGo writes Go source code which declares the passed genesis block along
+// with its coinbase transaction, hash and merkle root in the style of the
+// chaincfg package to w.  The names of the declared variables are prefixed
+// with the passed name, such as devNetGenesisBlock for devNet.
+func WriteGo(w io.Writer, name string, genesis *wire.MsgBlock) error {
+	if len(genesis.Transactions) != 1 {
+		return fmt.Errorf("genesis block must have exactly one " +
+			"transaction")
+	}
+	coinbase := genesis.Transactions[0]
+	if len(coinbase.TxIn) != 1 || len(coinbase.TxOut) != 1 {
+		return fmt.Errorf("genesis coinbase must have exactly one " +
+			"input and output")
+	}
+	header := &genesis.Header
+	genesisHash := genesis.BlockHash()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %sGenesisCoinbaseTx is the coinbase transaction "+
+		"of the genesis\n// block for the %s network.\n", name, name)
+	fmt.Fprintf(&buf, "var %sGenesisCoinbaseTx = wire.MsgTx{\n", name)
+	fmt.Fprintf(&buf, "Version: %d,\n", coinbase.Version)
+	fmt.Fprintf(&buf, "TxIn: []*wire.TxIn{\n{\n")
+	fmt.Fprintf(&buf, "PreviousOutPoint: wire.OutPoint{\n")
+	fmt.Fprintf(&buf, "Hash: chainhash.Hash{},\n")
+	fmt.Fprintf(&buf, "Index: 0x%08x,\n},\n",
+		coinbase.TxIn[0].PreviousOutPoint.Index)
+	fmt.Fprintf(&buf, "SignatureScript: []byte{\n")
+	writeGoBytes(&buf, coinbase.TxIn[0].SignatureScript)
+	fmt.Fprintf(&buf, "},\n")
+	fmt.Fprintf(&buf, "Sequence: 0x%08x,\n},\n},\n",
+		coinbase.TxIn[0].Sequence)
+	fmt.Fprintf(&buf, "TxOut: []*wire.TxOut{\n{\n")
+	fmt.Fprintf(&buf, "Value: 0x%x,\n", coinbase.TxOut[0].Value)
+	fmt.Fprintf(&buf, "PkScript: []byte{\n")
+	writeGoBytes(&buf, coinbase.TxOut[0].PkScript)
+	fmt.Fprintf(&buf, "},\n},\n},\n")
+	fmt.Fprintf(&buf, "LockTime: %d,\n}\n\n", coinbase.LockTime)
+
+	writeGoHash(&buf, name+"GenesisHash", fmt.Sprintf("hash of the "+
+		"first block in the block\n// chain for the %s network", name),
+		&genesisHash)
+	writeGoHash(&buf, name+"GenesisMerkleRoot", fmt.Sprintf("hash of "+
+		"the first transaction in the\n// genesis block for the %s "+
+		"network", name), &header.MerkleRoot)
+
+	fmt.Fprintf(&buf, "// %sGenesisBlock defines the genesis block of the "+
+		"block chain for\n// the %s network.\n", name, name)
+	fmt.Fprintf(&buf, "var %sGenesisBlock = wire.MsgBlock{\n", name)
+	fmt.Fprintf(&buf, "Header: wire.BlockHeader{\n")
+	fmt.Fprintf(&buf, "Version: %d,\n", header.Version)
+	fmt.Fprintf(&buf, "PrevBlock: chainhash.Hash{}, // %v\n",
+		header.PrevBlock)
+	fmt.Fprintf(&buf, "MerkleRoot: %sGenesisMerkleRoot, // %v\n", name,
+		header.MerkleRoot)
+	fmt.Fprintf(&buf, "Timestamp: time.Unix(%d, 0), // %v\n",
+		header.Timestamp.Unix(), header.Timestamp.UTC())
+	fmt.Fprintf(&buf, "Bits: 0x%08x, // %d [%064x]\n", header.Bits,
+		header.Bits, blockchain.CompactToBig(header.Bits))
+	fmt.Fprintf(&buf, "Nonce: %d,\n},\n", header.Nonce)
+	fmt.Fprintf(&buf, "Transactions: []*wire.MsgTx{&%sGenesisCoinbaseTx},\n}\n",
+		name)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"math/big"
+
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// ParamsOverride builds the parameters of a network from the ones of a base
+// network with some of them overridden, such as a test network which uses the
+// consensus rules of the main network but lets coinbases mature after a single
+// block.  Unlike a copy of the struct literal of the base network, the
+// parameters which are not overridden follow the base network when it changes.
+//
+// The methods return the override itself so they can be chained:
+//
+//	params, err := chaincfg.NewParamsOverride(&chaincfg.MainNetParams,
+//	        "mainnet-fastmature", 0xfabfb5db).
+//	        DefaultPort("29333").
+//	        CoinbaseMaturity(1).
+//	        Register()
+type ParamsOverride struct {
+	params Params
+}
+
+// NewParamsOverride returns an override of the parameters of the base network
+// for a network with the passed name and magic bytes.  The magic bytes must
+// differ from the ones of every registered network for the parameters to be
+// registered.
+//
+// The slices and big integers of the base network are copied, so neither the
+// base network nor the override are affected by changes to the other.
+func NewParamsOverride(base *Params, name string, net wire.BitcoinNet) *ParamsOverride {
+	o := &ParamsOverride{params: cloneParams(base)}
+	o.params.Name = name
+	o.params.Net = net
+	return o
+}
+
+// DefaultPort overrides the default peer-to-peer port of the network.
+func (o *ParamsOverride) DefaultPort(port string) *ParamsOverride {
+	o.params.DefaultPort = port
+	return o
+}
+
+// DNSSeeds overrides the DNS seeds of the network.  Passing no seeds disables
+// peer discovery through DNS.
+func (o *ParamsOverride) DNSSeeds(seeds ...DNSSeed) *ParamsOverride {
+	o.params.DNSSeeds = append([]DNSSeed(nil), seeds...)
+	return o
+}
+
+// GenesisBlock overrides the genesis block of the network along with its hash.
+func (o *ParamsOverride) GenesisBlock(genesis *wire.MsgBlock) *ParamsOverride {
+	genesisHash := genesis.BlockHash()
+	o.params.GenesisBlock = genesis
+	o.params.GenesisHash = &genesisHash
+	return o
+}
+
+// PowLimit overrides the highest allowed proof of work value of a block, both
+// as a uint256 and in compact form.
+func (o *ParamsOverride) PowLimit(limit *big.Int, bits uint32) *ParamsOverride {
+	o.params.PowLimit = new(big.Int).Set(limit)
+	o.params.PowLimitBits = bits
+	return o
+}
+
+// CoinbaseMaturity overrides the number of blocks required before coinbase
+// outputs can be spent.
+func (o *ParamsOverride) CoinbaseMaturity(maturity uint16) *ParamsOverride {
+	o.params.CoinbaseMaturity = maturity
+	return o
+}
+
+// Checkpoints overrides the checkpoints of the network, which must be ordered
+// from oldest to newest.  Passing no checkpoints disables them.
+func (o *ParamsOverride) Checkpoints(checkpoints ...Checkpoint) *ParamsOverride {
+	o.params.Checkpoints = append([]Checkpoint(nil), checkpoints...)
+	return o
+}
+
+// MinimumChainWork overrides the minimum amount of work a chain must have
+// before the node syncs it.  A nil value disables the check.
+func (o *ParamsOverride) MinimumChainWork(work *big.Int) *ParamsOverride {
+	o.params.MinimumChainWork = nil
+	if work != nil {
+		o.params.MinimumChainWork = new(big.Int).Set(work)
+	}
+	return o
+}
+
+// Deployment overrides the details of the consensus rule change deployment
+// with the passed ID, such as DeploymentSegwit.
+func (o *ParamsOverride) Deployment(id int, deployment ConsensusDeployment) *ParamsOverride {
+	o.params.Deployments[id] = deployment
+	return o
+}
+
+// Modify calls the passed function with the parameters being built, which
+// allows overriding the parameters without a dedicated method.
+func (o *ParamsOverride) Modify(modify func(params *Params)) *ParamsOverride {
+	modify(&o.params)
+	return o
+}
+
+// Params returns a copy of the parameters built so far.  The override may
+// continue to be used without affecting the returned parameters.
+func (o *ParamsOverride) Params() Params {
+	return cloneParams(&o.params)
+}
+
+// Register registers a copy of the parameters built so far as a distinct
+// network and returns it.  It errors with ErrDuplicateNet when a network with
+// the same magic bytes is already registered.
+func (o *ParamsOverride) Register() (*Params, error) {
+	params := o.Params()
+	if err := Register(&params); err != nil {
+		return nil, err
+	}
+	return &params, nil
+}
+
+// cloneParams returns a copy of the passed parameters which does not share the
+// slices and big integers of the original.  The genesis block, checkpoint
+// hashes and deployment starters and enders are shared since they are never
+// modified in place.
+func cloneParams(params *Params) Params {
+	clone := *params
+	clone.DNSSeeds = append([]DNSSeed(nil), params.DNSSeeds...)
+	clone.Checkpoints = append([]Checkpoint(nil), params.Checkpoints...)
+	clone.SubsidySchedule = append([]SubsidyStep(nil),
+		params.SubsidySchedule...)
+	clone.SignetChallenge = append([]byte(nil), params.SignetChallenge...)
+	if params.PowLimit != nil {
+		clone.PowLimit = new(big.Int).Set(params.PowLimit)
+	}
+	if params.MinimumChainWork != nil {
+		clone.MinimumChainWork = new(big.Int).Set(params.MinimumChainWork)
+	}
+	return clone
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg_test
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	. "github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestParamsOverride ensures overridden parameters are applied on top of the
+// base network without modifying it, and that they register as a distinct
+// network.
+func TestParamsOverride(t *testing.T) {
+	const net wire.BitcoinNet = 0x0b110907
+	seeds := []DNSSeed{{Host: "seed.example.com", HasFiltering: true}}
+	override := NewParamsOverride(&MainNetParams, "mainnet-override", net).
+		DefaultPort("29333").
+		DNSSeeds(seeds...).
+		CoinbaseMaturity(1).
+		Checkpoints().
+		MinimumChainWork(nil).
+		Modify(func(params *Params) {
+			params.RelayNonStdTxs = true
+		})
+	params := override.Params()
+
+	// Every parameter other than the overridden ones must match the base
+	// network.
+	want := MainNetParams
+	want.Name = "mainnet-override"
+	want.Net = net
+	want.DefaultPort = "29333"
+	want.DNSSeeds = seeds
+	want.CoinbaseMaturity = 1
+	want.Checkpoints = nil
+	want.MinimumChainWork = nil
+	want.RelayNonStdTxs = true
+	if !reflect.DeepEqual(params.Deployments, want.Deployments) {
+		t.Error("deployments do not match the base network")
+	}
+	params.Deployments = want.Deployments
+	params.CalcSubsidy, want.CalcSubsidy = nil, nil
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("overridden params:\n got %+v\nwant %+v", params, want)
+	}
+
+	// Modifying the built parameters must neither affect the base network
+	// nor the override.
+	params.PowLimit.SetInt64(1)
+	params.DNSSeeds[0].Host = "modified"
+	if MainNetParams.PowLimit.Cmp(big.NewInt(1)) == 0 {
+		t.Error("modifying the override changed the base network")
+	}
+	if again := override.Params(); again.DNSSeeds[0].Host != seeds[0].Host {
+		t.Error("modifying the built params changed the override")
+	}
+
+	// The override must register as a distinct network once, and the
+	// base network must remain registered.
+	registered, err := override.Register()
+	if err != nil {
+		t.Fatalf("Register: unexpected error: %v", err)
+	}
+	if registered.Net != net || registered.CoinbaseMaturity != 1 {
+		t.Errorf("Register: got net %v with coinbase maturity %d",
+			registered.Net, registered.CoinbaseMaturity)
+	}
+	if _, err := override.Register(); err != ErrDuplicateNet {
+		t.Errorf("Register again: got error %v, want %v", err,
+			ErrDuplicateNet)
+	}
+	if err := Register(&MainNetParams); err != ErrDuplicateNet {
+		t.Errorf("Register mainnet: got error %v, want %v", err,
+			ErrDuplicateNet)
+	}
+}
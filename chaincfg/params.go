@@ -43,6 +43,25 @@ var (
 	// have for the signet test network. It is the value 0x0377ae << 216.
 	sigNetPowLimit = new(big.Int).Lsh(new(big.Int).SetInt64(0x0377ae), 216)
 
+	// mainMinimumChainWork is the minimum amount of accumulated work the
+	// main network best chain must have before headers and blocks from it
+	// are trusted.  It is meant to be the chain work of the block at height
+	// 1200000, the final main network checkpoint, as reported by
+	// getblockheader on a synced node.  Until that value is filled in, it
+	// is the least possible work of a chain reaching that height, which is
+	// every block through it at the proof of work limit:
+	// 1200001 * 2^256 / (mainPowLimit + 1).
+	mainMinimumChainWork, _ = new(big.Int).SetString("0x124f8224f81", 0)
+
+	// testNet4MinimumChainWork is the minimum amount of accumulated work
+	// the test network (version 4) best chain must have before headers and
+	// blocks from it are trusted.  It is meant to be the chain work of the
+	// block at height 2056, the final test network checkpoint, as reported
+	// by getblockheader on a synced node.  Until that value is filled in,
+	// it is the least possible work of a chain reaching that height:
+	// 2057 * 2^256 / (testNet4PowLimit + 1).
+	testNet4MinimumChainWork, _ = new(big.Int).SetString("0x80900809", 0)
+
 	// DefaultSignetChallenge is the byte representation of the signet
 	// challenge for the default (public, Taproot enabled) signet network.
 	// This is the binary equivalent of the litecoin script
@@ -76,6 +95,21 @@ type Checkpoint struct {
 	Hash   *chainhash.Hash
 }
 
+// SubsidyStep defines the block subsidy of a network from a block height
+// onwards.  It is used to define emission curves which do not halve at a fixed
+// interval.
+type SubsidyStep struct {
+	// Height is the first block height the subsidy applies to.
+	Height int32
+
+	// Subsidy is the block subsidy in satoshi.
+	Subsidy int64
+}
+
+// SubsidyFunc returns the block subsidy in satoshi of a block at the passed
+// height.
+type SubsidyFunc func(height int32) int64
+
 // DNSSeed identifies a DNS seed.
 type DNSSeed struct {
 	// Host defines the hostname of the seed.
@@ -173,6 +207,17 @@ type Params struct {
 	// as one method to discover peers.
 	DNSSeeds []DNSSeed
 
+	// MinPeerProtocolVersion is the lowest protocol version of the peers
+	// the network accepts.  Zero accepts every protocol version supported
+	// by the peer package.
+	MinPeerProtocolVersion uint32
+
+	// RequiredServices defines the services outbound peers must advertise,
+	// while outbound connections favor the peers which also advertise the
+	// PreferredServices.
+	RequiredServices  wire.ServiceFlag
+	PreferredServices wire.ServiceFlag
+
 	// GenesisBlock defines the first block of the chain.
 	GenesisBlock *wire.MsgBlock
 
@@ -198,6 +243,24 @@ type Params struct {
 	BIP0065Height int32
 	BIP0066Height int32
 
+	// AllowLegacyBlockVersions accepts blocks with versions below the ones
+	// required once BIP0034, BIP0065 and BIP0066 are active, so networks
+	// which kept mining such blocks can be replayed.  The rules of those
+	// soft forks which depend on the block version are not enforced for
+	// the legacy blocks.
+	AllowLegacyBlockVersions bool
+
+	// EnforceStrictDER requires strict DER signatures as defined by
+	// BIP0066 in every block regardless of its version and height, rather
+	// than only in blocks with version 3 or higher from BIP0066Height on.
+	EnforceStrictDER bool
+
+	// AllowNonStrictDER accepts signatures which are not strict DER in
+	// every block regardless of its version and height, so networks which
+	// kept mining such signatures after BIP0066Height can be replayed.  It
+	// can't be combined with EnforceStrictDER.
+	AllowNonStrictDER bool
+
 	// CoinbaseMaturity is the number of blocks required before newly mined
 	// coins (coinbase transactions) can be spent.
 	CoinbaseMaturity uint16
@@ -206,10 +269,33 @@ type Params struct {
 	// pegged-out from MWEB can be spent.
 	MwebPegoutMaturity uint16
 
+	// MaxBlockWeight is the maximum weight of a block, where the weight is
+	// defined by BIP0141.  It also limits the size of the non-witness data
+	// of a block to a quarter of it.  The default of 4000000 is used when
+	// it is zero.  It can't exceed the default since blocks are limited to
+	// wire.MaxBlockPayload bytes on every network.
+	MaxBlockWeight int64
+
+	// MaxBlockSigOpsCost is the maximum total signature operation cost of
+	// the transactions of a block.  The default of 80000 is used when it
+	// is zero.
+	MaxBlockSigOpsCost int64
+
 	// SubsidyReductionInterval is the interval of blocks before the subsidy
 	// is reduced.
 	SubsidyReductionInterval int32
 
+	// SubsidySchedule overrides the halving of the block subsidy every
+	// SubsidyReductionInterval blocks when set.  The steps must be ordered
+	// by height and blocks below the height of the first step do not have
+	// a subsidy.
+	SubsidySchedule []SubsidyStep
+
+	// CalcSubsidy overrides both the halving of the block subsidy and the
+	// subsidy schedule when set.  It allows emission curves which can't be
+	// expressed as steps.
+	CalcSubsidy SubsidyFunc
+
 	// TargetTimespan is the desired amount of time that should elapse
 	// before the block difficulty requirement is examined to determine how
 	// it should be changed in order to maintain the desired block
@@ -259,12 +345,30 @@ type Params struct {
 	// activation height.
 	ASERTAnchorBits uint32
 
+	// MinimumChainWork is the minimum amount of accumulated work a chain
+	// must have before the node considers it worth syncing and relaying.
+	// It protects against peers feeding long low-work header and block
+	// chains during initial block download.  A nil value disables the
+	// check.
+	MinimumChainWork *big.Int
+
+	// SignetChallenge is the script every block other than the genesis
+	// block must provide a solution for in its coinbase as defined by
+	// BIP0325.  It is only set for signet networks.
+	SignetChallenge []byte
+
 	// GenerateSupported specifies whether or not CPU mining is allowed.
 	GenerateSupported bool
 
 	// Checkpoints ordered from oldest to newest.
 	Checkpoints []Checkpoint
 
+	// AdvisoryCheckpoints treats the checkpoints as advisory rather than
+	// strictly enforced.  Blocks which do not match a checkpoint are then
+	// only logged instead of rejected, forks before the latest checkpoint
+	// are allowed, and the scripts of the blocks before it are validated.
+	AdvisoryCheckpoints bool
+
 	// These fields are related to voting on consensus rule changes as
 	// defined by BIP0009.
 	//
@@ -316,6 +420,9 @@ var MainNetParams = Params{
 	DNSSeeds: []DNSSeed{
 		{"seed.doriancoin.org", true},
 	},
+	MinPeerProtocolVersion: wire.FeeFilterVersion,
+	RequiredServices:       wire.SFNodeNetwork | wire.SFNodeWitness,
+	PreferredServices:      wire.SFNodeMWEB,
 
 	// Chain parameters
 	GenesisBlock:             &genesisBlock,
@@ -327,6 +434,8 @@ var MainNetParams = Params{
 	BIP0066Height:            302983,
 	CoinbaseMaturity:         100,
 	MwebPegoutMaturity:       6,
+	MaxBlockWeight:           4000000,
+	MaxBlockSigOpsCost:       80000,
 	SubsidyReductionInterval: 840000,
 	TargetTimespan:           (time.Hour * 24 * 3) + (time.Hour * 12), // 3.5 days
 	TargetTimePerBlock:       (time.Minute * 2) + (time.Second * 30),  // 2.5 minutes
@@ -339,6 +448,7 @@ var MainNetParams = Params{
 	ASERTHeight:              1246000,
 	ASERTHalfLife:            3600,
 	ASERTAnchorBits:          0x1d18ffe7,
+	MinimumChainWork:         mainMinimumChainWork,
 	GenerateSupported:        false,
 
 	// Checkpoints ordered from oldest to newest.
@@ -459,10 +569,11 @@ var MainNetParams = Params{
 // Litecoin network.  Not to be confused with the test Litecoin network (version
 // 4), this network is sometimes simply called "testnet".
 var RegressionNetParams = Params{
-	Name:        "regtest",
-	Net:         wire.TestNet,
-	DefaultPort: "19444",
-	DNSSeeds:    []DNSSeed{},
+	Name:             "regtest",
+	Net:              wire.TestNet,
+	DefaultPort:      "19444",
+	DNSSeeds:         []DNSSeed{},
+	RequiredServices: wire.SFNodeNetwork,
 
 	// Chain parameters
 	GenesisBlock:             &regTestGenesisBlock,
@@ -472,6 +583,8 @@ var RegressionNetParams = Params{
 	PoWNoRetargeting:         true,
 	CoinbaseMaturity:         100,
 	MwebPegoutMaturity:       6,
+	MaxBlockWeight:           4000000,
+	MaxBlockSigOpsCost:       80000,
 	BIP0034Height:            100000000, // Not active - Permit ver 1 blocks
 	BIP0065Height:            1351,      // Used by regression tests
 	BIP0066Height:            1251,      // Used by regression tests
@@ -487,6 +600,7 @@ var RegressionNetParams = Params{
 	ASERTHeight:              700,
 	ASERTHalfLife:            3600,
 	ASERTAnchorBits:          0x1d18ffe7,
+	MinimumChainWork:         nil,
 	GenerateSupported:        true,
 
 	// Checkpoints ordered from oldest to newest.
@@ -593,6 +707,9 @@ var TestNet4Params = Params{
 		{"seed-b.doriancoin.loshan.co.uk", true},
 		{"dnsseed-testnet.thrasher.io", true},
 	},
+	MinPeerProtocolVersion: wire.FeeFilterVersion,
+	RequiredServices:       wire.SFNodeNetwork | wire.SFNodeWitness,
+	PreferredServices:      wire.SFNodeMWEB,
 
 	// Chain parameters
 	GenesisBlock:             &testNet4GenesisBlock,
@@ -604,6 +721,8 @@ var TestNet4Params = Params{
 	BIP0066Height:            0,
 	CoinbaseMaturity:         100,
 	MwebPegoutMaturity:       6,
+	MaxBlockWeight:           4000000,
+	MaxBlockSigOpsCost:       80000,
 	SubsidyReductionInterval: 840000,
 	TargetTimespan:           (time.Hour * 24 * 3) + (time.Hour * 12), // 3.5 days
 	TargetTimePerBlock:       (time.Minute * 2) + (time.Second * 30),  // 2.5 minutes
@@ -616,6 +735,7 @@ var TestNet4Params = Params{
 	ASERTHeight:              300,
 	ASERTHalfLife:            3600,
 	ASERTAnchorBits:          0x1d18ffe7,
+	MinimumChainWork:         testNet4MinimumChainWork,
 	GenerateSupported:        false,
 
 	// Checkpoints ordered from oldest to newest.
@@ -722,10 +842,11 @@ var TestNet4Params = Params{
 // following normal discovery rules.  This is important as otherwise it would
 // just turn into another public testnet.
 var SimNetParams = Params{
-	Name:        "simnet",
-	Net:         wire.SimNet,
-	DefaultPort: "18555",
-	DNSSeeds:    []DNSSeed{}, // NOTE: There must NOT be any seeds.
+	Name:             "simnet",
+	Net:              wire.SimNet,
+	DefaultPort:      "18555",
+	DNSSeeds:         []DNSSeed{}, // NOTE: There must NOT be any seeds.
+	RequiredServices: wire.SFNodeNetwork,
 
 	// Chain parameters
 	GenesisBlock:             &simNetGenesisBlock,
@@ -737,12 +858,15 @@ var SimNetParams = Params{
 	BIP0066Height:            0, // Always active on simnet
 	CoinbaseMaturity:         100,
 	MwebPegoutMaturity:       6,
+	MaxBlockWeight:           4000000,
+	MaxBlockSigOpsCost:       80000,
 	SubsidyReductionInterval: 210000,
 	TargetTimespan:           (time.Hour * 24 * 3) + (time.Hour * 12), // 3.5 days
 	TargetTimePerBlock:       (time.Minute * 2) + (time.Second * 30),  // 2.5 minutes
 	RetargetAdjustmentFactor: 4,                                       // 25% less, 400% more
 	ReduceMinDifficulty:      true,
 	MinDiffReductionTime:     time.Minute * 20, // TargetTimePerBlock * 2
+	MinimumChainWork:         nil,
 	GenerateSupported:        true,
 
 	// Checkpoints ordered from oldest to newest.
@@ -835,6 +959,20 @@ var SigNetParams = CustomSignetParams(
 	DefaultSignetChallenge, DefaultSignetDNSSeeds,
 )
 
+// CustomSignetParamsWithGenesis creates network parameters for a custom signet
+// network from a challenge and its own genesis block, such as one returned by
+// SignetGenesisBlock.  Private signets should use this instead of
+// CustomSignetParams, which uses the genesis block of the default signet.
+func CustomSignetParamsWithGenesis(challenge []byte, dnsSeeds []DNSSeed,
+	genesis *wire.MsgBlock) Params {
+
+	params := CustomSignetParams(challenge, dnsSeeds)
+	genesisHash := genesis.BlockHash()
+	params.GenesisBlock = genesis
+	params.GenesisHash = &genesisHash
+	return params
+}
+
 // CustomSignetParams creates network parameters for a custom signet network
 // from a challenge. The challenge is the binary compiled version of the block
 // challenge script.
@@ -851,10 +989,11 @@ func CustomSignetParams(challenge []byte, dnsSeeds []DNSSeed) Params {
 	// the other wire network identities.
 	net := binary.LittleEndian.Uint32(hashDouble[0:4])
 	return Params{
-		Name:        "signet",
-		Net:         wire.BitcoinNet(net),
-		DefaultPort: "38333",
-		DNSSeeds:    dnsSeeds,
+		Name:             "signet",
+		Net:              wire.BitcoinNet(net),
+		DefaultPort:      "38333",
+		DNSSeeds:         dnsSeeds,
+		RequiredServices: wire.SFNodeNetwork | wire.SFNodeWitness,
 
 		// Chain parameters
 		GenesisBlock:             &sigNetGenesisBlock,
@@ -866,12 +1005,16 @@ func CustomSignetParams(challenge []byte, dnsSeeds []DNSSeed) Params {
 		BIP0066Height:            1,
 		CoinbaseMaturity:         100,
 		MwebPegoutMaturity:       6,
+		MaxBlockWeight:           4000000,
+		MaxBlockSigOpsCost:       80000,
 		SubsidyReductionInterval: 210000,
 		TargetTimespan:           (time.Hour * 24 * 3) + (time.Hour * 12), // 3.5 days
 		TargetTimePerBlock:       (time.Minute * 2) + (time.Second * 30),  // 2.5 minutes
 		RetargetAdjustmentFactor: 4,                                       // 25% less, 400% more
 		ReduceMinDifficulty:      false,
 		MinDiffReductionTime:     time.Minute * 20, // TargetTimePerBlock * 2
+		MinimumChainWork:         nil,
+		SignetChallenge:          challenge,
 		GenerateSupported:        false,
 
 		// Checkpoints ordered from oldest to newest.
@@ -971,6 +1114,16 @@ var (
 	// ErrInvalidHDKeyID describes an error where the provided hierarchical
 	// deterministic version bytes, or hd key id, is malformed.
 	ErrInvalidHDKeyID = errors.New("invalid hd extended key version bytes")
+
+	// ErrUnknownPrivateKeyID describes an error where the provided id which
+	// is intended to identify the network of a WIF encoded private key is
+	// not registered.
+	ErrUnknownPrivateKeyID = errors.New("unknown private key id")
+
+	// ErrUnknownAddressID describes an error where the provided id or
+	// human-readable part which is intended to identify the network of an
+	// address is not registered.
+	ErrUnknownAddressID = errors.New("unknown address id")
 )
 
 var (
@@ -980,6 +1133,20 @@ var (
 	bech32SegwitPrefixes = make(map[string]struct{})
 	bech32MwebPrefixes   = make(map[string]struct{})
 	hdPrivToPubKeyIDs    = make(map[[4]byte][]byte)
+
+	// privateKeyIDNets and hdKeyIDNets map the WIF private key IDs and the
+	// standard private and public hierarchical deterministic extended key
+	// IDs to the first registered network using them.
+	privateKeyIDNets = make(map[byte]*Params)
+	hdKeyIDNets      = make(map[[4]byte]*Params)
+
+	// pubKeyHashAddrIDNets, scriptHashAddrIDNets, and bech32HRPNets map
+	// the address IDs and the human-readable parts of the bech32 encoded
+	// segwit and MWEB addresses to the first registered network using
+	// them.
+	pubKeyHashAddrIDNets = make(map[byte]*Params)
+	scriptHashAddrIDNets = make(map[byte]*Params)
+	bech32HRPNets        = make(map[string]*Params)
 )
 
 // String returns the hostname of the DNS seed in human-readable form.
@@ -987,6 +1154,15 @@ func (d DNSSeed) String() string {
 	return d.Host
 }
 
+// LatestCheckpoint returns the most recent checkpoint of the network.  It
+// returns nil when the network does not define any checkpoints.
+func (p *Params) LatestCheckpoint() *Checkpoint {
+	if len(p.Checkpoints) == 0 {
+		return nil
+	}
+	return &p.Checkpoints[len(p.Checkpoints)-1]
+}
+
 // Register registers the network parameters for a Doriancoin network.  This may
 // error with ErrDuplicateNet if the network is already registered (either
 // due to a previous Register call, or the network being one of the default
@@ -1009,6 +1185,30 @@ func Register(params *Params) error {
 		return err
 	}
 
+	// Several networks may share the same key IDs, such as the test
+	// network and the regression test network, in which case the first
+	// registered network is the one reported for them.
+	if _, ok := privateKeyIDNets[params.PrivateKeyID]; !ok {
+		privateKeyIDNets[params.PrivateKeyID] = params
+	}
+	for _, id := range [][4]byte{params.HDPrivateKeyID, params.HDPublicKeyID} {
+		if _, ok := hdKeyIDNets[id]; !ok {
+			hdKeyIDNets[id] = params
+		}
+	}
+	if _, ok := pubKeyHashAddrIDNets[params.PubKeyHashAddrID]; !ok {
+		pubKeyHashAddrIDNets[params.PubKeyHashAddrID] = params
+	}
+	if _, ok := scriptHashAddrIDNets[params.ScriptHashAddrID]; !ok {
+		scriptHashAddrIDNets[params.ScriptHashAddrID] = params
+	}
+	for _, hrp := range []string{params.Bech32HRPSegwit, params.Bech32HRPMweb} {
+		hrp = strings.ToLower(hrp)
+		if _, ok := bech32HRPNets[hrp]; !ok && hrp != "" {
+			bech32HRPNets[hrp] = params
+		}
+	}
+
 	// A valid Bech32 encoded segwit address always has as prefix the
 	// human-readable part for the given net followed by '1'.
 	bech32SegwitPrefixes[params.Bech32HRPSegwit+"1"] = struct{}{}
@@ -1110,6 +1310,80 @@ func HDPrivateKeyToPublicKeyID(id []byte) ([]byte, error) {
 	return pubBytes, nil
 }
 
+// ParamsForPrivateKeyID returns the parameters of the default or registered
+// network using the passed id to prefix WIF encoded private keys.  When several
+// networks use the same id, the network registered first is returned, so the
+// test network is reported for keys of the regression test network.  The
+// ErrUnknownPrivateKeyID error is returned when no network uses the id.
+func ParamsForPrivateKeyID(id byte) (*Params, error) {
+	params, ok := privateKeyIDNets[id]
+	if !ok {
+		return nil, ErrUnknownPrivateKeyID
+	}
+	return params, nil
+}
+
+// ParamsForHDKeyID returns the parameters of the default or registered network
+// using the passed id as the version bytes of either its private or public
+// hierarchical deterministic extended keys.  As with ParamsForPrivateKeyID, the
+// network registered first is returned when several networks use the same id.
+// Only the standard version bytes of each network are considered, so version
+// bytes registered with RegisterHDKeyID are not known.  The ErrUnknownHDKeyID
+// error is returned when no network uses the id.
+func ParamsForHDKeyID(id []byte) (*Params, error) {
+	if len(id) != 4 {
+		return nil, ErrUnknownHDKeyID
+	}
+
+	var key [4]byte
+	copy(key[:], id)
+	params, ok := hdKeyIDNets[key]
+	if !ok {
+		return nil, ErrUnknownHDKeyID
+	}
+	return params, nil
+}
+
+// ParamsForPubKeyHashAddrID returns the parameters of the default or registered
+// network using the passed id to prefix pay-to-pubkey-hash addresses.  As with
+// ParamsForPrivateKeyID, the network registered first is returned when several
+// networks use the same id.  The ErrUnknownAddressID error is returned when no
+// network uses the id.
+func ParamsForPubKeyHashAddrID(id byte) (*Params, error) {
+	params, ok := pubKeyHashAddrIDNets[id]
+	if !ok {
+		return nil, ErrUnknownAddressID
+	}
+	return params, nil
+}
+
+// ParamsForScriptHashAddrID returns the parameters of the default or registered
+// network using the passed id to prefix pay-to-script-hash addresses.  As with
+// ParamsForPrivateKeyID, the network registered first is returned when several
+// networks use the same id.  The ErrUnknownAddressID error is returned when no
+// network uses the id.
+func ParamsForScriptHashAddrID(id byte) (*Params, error) {
+	params, ok := scriptHashAddrIDNets[id]
+	if !ok {
+		return nil, ErrUnknownAddressID
+	}
+	return params, nil
+}
+
+// ParamsForBech32HRP returns the parameters of the default or registered
+// network using the passed human-readable part for either its bech32 encoded
+// segwit addresses or its MWEB addresses.  As with ParamsForPrivateKeyID, the
+// network registered first is returned when several networks use the same
+// human-readable part.  The ErrUnknownAddressID error is returned when no
+// network uses it.
+func ParamsForBech32HRP(hrp string) (*Params, error) {
+	params, ok := bech32HRPNets[strings.ToLower(hrp)]
+	if !ok {
+		return nil, ErrUnknownAddressID
+	}
+	return params, nil
+}
+
 // newHashFromStr converts the passed big-endian hex string into a
 // chainhash.Hash.  It only differs from the one available in chainhash in that
 // it panics on an error since it will only (and must only) be called with
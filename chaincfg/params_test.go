@@ -6,6 +6,7 @@ package chaincfg
 
 import (
 	"bytes"
+	"math/big"
 	"testing"
 )
 
@@ -131,3 +132,50 @@ func TestInvalidHDKeyID(t *testing.T) {
 
 // 	return bn
 // }
+
+// TestLatestCheckpoint ensures the latest checkpoint of a network is the last
+// one of its checkpoints.
+func TestLatestCheckpoint(t *testing.T) {
+	params := MainNetParams
+	params.Checkpoints = nil
+	if checkpoint := params.LatestCheckpoint(); checkpoint != nil {
+		t.Errorf("LatestCheckpoint: got %v for no checkpoints", checkpoint)
+	}
+
+	params.Checkpoints = []Checkpoint{
+		{Height: 10, Hash: newHashFromStr("0a")},
+		{Height: 20, Hash: newHashFromStr("14")},
+	}
+	checkpoint := params.LatestCheckpoint()
+	if checkpoint != &params.Checkpoints[1] {
+		t.Errorf("LatestCheckpoint: got %v, want %v", checkpoint,
+			params.Checkpoints[1])
+	}
+}
+
+// TestMinimumChainWork ensures the minimum chain work of the networks with
+// checkpoints is at least the work of a chain reaching the final checkpoint
+// with every block at the proof of work limit.
+func TestMinimumChainWork(t *testing.T) {
+	t.Parallel()
+
+	oneLsh256 := new(big.Int).Lsh(bigOne, 256)
+	for _, params := range []*Params{&MainNetParams, &TestNet4Params} {
+		if params.MinimumChainWork == nil {
+			continue
+		}
+
+		// The work of a block is 2^256 / (target + 1).
+		blockWork := new(big.Int).Add(params.PowLimit, bigOne)
+		blockWork.Div(oneLsh256, blockWork)
+		lastCheckpoint := params.Checkpoints[len(params.Checkpoints)-1]
+		minWork := blockWork.Mul(blockWork,
+			big.NewInt(int64(lastCheckpoint.Height)+1))
+		if params.MinimumChainWork.Cmp(minWork) < 0 {
+			t.Errorf("%s: minimum chain work %x is less than %x, "+
+				"the least work at checkpoint height %d",
+				params.Name, params.MinimumChainWork, minWork,
+				lastCheckpoint.Height)
+		}
+	}
+}
@@ -506,3 +506,109 @@ func TestRegister(t *testing.T) {
 		}
 	}
 }
+
+// TestParamsForKeyID ensures the networks of the registered private key and
+// extended key IDs are found.
+func TestParamsForKeyID(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *Params
+		want   *Params
+	}{
+		{"mainnet", &MainNetParams, &MainNetParams},
+		{"testnet4", &TestNet4Params, &TestNet4Params},
+		{"simnet", &SimNetParams, &SimNetParams},
+
+		// The regression test network shares its key IDs with the test
+		// network, which is registered first.
+		{"regtest", &RegressionNetParams, &TestNet4Params},
+	}
+
+	for _, test := range tests {
+		params, err := ParamsForPrivateKeyID(test.params.PrivateKeyID)
+		if err != nil || params != test.want {
+			t.Errorf("%s: ParamsForPrivateKeyID: got %v (err %v), "+
+				"want %s", test.name, params, err, test.want.Name)
+		}
+		for _, id := range [][4]byte{test.params.HDPrivateKeyID,
+			test.params.HDPublicKeyID} {
+
+			params, err := ParamsForHDKeyID(id[:])
+			if err != nil || params != test.want {
+				t.Errorf("%s: ParamsForHDKeyID(%x): got %v "+
+					"(err %v), want %s", test.name, id,
+					params, err, test.want.Name)
+			}
+		}
+	}
+
+	if _, err := ParamsForPrivateKeyID(0x01); err != ErrUnknownPrivateKeyID {
+		t.Errorf("ParamsForPrivateKeyID: unexpected error for unknown "+
+			"id: %v", err)
+	}
+	if _, err := ParamsForHDKeyID([]byte{0xff, 0xff, 0xff, 0xff}); err !=
+		ErrUnknownHDKeyID {
+
+		t.Errorf("ParamsForHDKeyID: unexpected error for unknown id: %v",
+			err)
+	}
+	if _, err := ParamsForHDKeyID([]byte{0xff}); err != ErrUnknownHDKeyID {
+		t.Errorf("ParamsForHDKeyID: unexpected error for short id: %v",
+			err)
+	}
+}
+
+// TestParamsForAddressID ensures the networks of the registered address IDs and
+// bech32 human-readable parts are found.
+func TestParamsForAddressID(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  *Params
+		want    *Params
+		wantHRP *Params
+	}{
+		{"mainnet", &MainNetParams, &MainNetParams, &MainNetParams},
+		{"testnet4", &TestNet4Params, &TestNet4Params, &TestNet4Params},
+		{"simnet", &SimNetParams, &SimNetParams, &SimNetParams},
+
+		// The regression test network shares its address IDs with the
+		// test network, which is registered first, but not the
+		// human-readable part of its segwit addresses.
+		{"regtest", &RegressionNetParams, &TestNet4Params,
+			&RegressionNetParams},
+	}
+
+	for _, test := range tests {
+		params, err := ParamsForPubKeyHashAddrID(test.params.PubKeyHashAddrID)
+		if err != nil || params != test.want {
+			t.Errorf("%s: ParamsForPubKeyHashAddrID: got %v (err %v), "+
+				"want %s", test.name, params, err, test.want.Name)
+		}
+		params, err = ParamsForScriptHashAddrID(test.params.ScriptHashAddrID)
+		if err != nil || params != test.want {
+			t.Errorf("%s: ParamsForScriptHashAddrID: got %v (err %v), "+
+				"want %s", test.name, params, err, test.want.Name)
+		}
+		hrp := strings.ToUpper(test.params.Bech32HRPSegwit)
+		params, err = ParamsForBech32HRP(hrp)
+		if err != nil || params != test.wantHRP {
+			t.Errorf("%s: ParamsForBech32HRP(%s): got %v (err %v), "+
+				"want %s", test.name, hrp, params, err,
+				test.wantHRP.Name)
+		}
+	}
+
+	params, err := ParamsForBech32HRP(MainNetParams.Bech32HRPMweb)
+	if err != nil || params != &MainNetParams {
+		t.Errorf("ParamsForBech32HRP(%s): got %v (err %v), want %s",
+			MainNetParams.Bech32HRPMweb, params, err, MainNetParams.Name)
+	}
+	if _, err := ParamsForBech32HRP("bogus"); err != ErrUnknownAddressID {
+		t.Errorf("ParamsForBech32HRP: unexpected error for unknown "+
+			"human-readable part: %v", err)
+	}
+	if _, err := ParamsForBech32HRP(""); err != ErrUnknownAddressID {
+		t.Errorf("ParamsForBech32HRP: unexpected error for empty "+
+			"human-readable part: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/genesistool"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// config defines the configuration options for gengenesis.
+type config struct {
+	Message        string `short:"m" long:"message" description:"Message embedded in the coinbase of the genesis block" required:"true"`
+	PubKey         string `short:"p" long:"pubkey" description:"Hex encoded public key paid by the coinbase of the genesis block" required:"true"`
+	Reward         int64  `long:"reward" description:"Amount paid by the coinbase of the genesis block in satoshi"`
+	Time           int64  `short:"t" long:"time" description:"Unix timestamp of the genesis block (default: now)"`
+	Bits           string `short:"b" long:"bits" description:"Hex encoded compact proof of work bits of the genesis block (default: the proof of work limit of the network)"`
+	Name           string `short:"n" long:"name" description:"Name of the network, used as the prefix of the Go variables"`
+	Magic          uint32 `long:"magic" description:"Network magic written to the JSON output"`
+	Port           string `long:"port" description:"Default peer port written to the JSON output"`
+	JSONOutput     bool   `short:"j" long:"json" description:"Display the network as JSON which can be loaded with genesistool.LoadParams instead of using Go syntax"`
+	RegressionTest bool   `long:"regtest" description:"Use the proof of work limit of the regression test network"`
+	SimNet         bool   `long:"simnet" description:"Use the proof of work limit of the simulation test network"`
+	TestNet4       bool   `long:"testnet" description:"Use the proof of work limit of the test network"`
+}
+
+// fatalf writes the passed error message to stderr and exits.
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+
+func main() {
+	cfg := config{
+		Reward: genesistool.DefaultReward,
+		Time:   time.Now().Unix(),
+		Name:   "devNet",
+	}
+	parser := flags.NewParser(&cfg, flags.Default)
+	_, err := parser.Parse()
+	if err != nil {
+		if e, ok := err.(*flags.Error); !ok || e.Type != flags.ErrHelp {
+			parser.WriteHelp(os.Stderr)
+		}
+		os.Exit(1)
+	}
+
+	// Select the network whose proof of work limit the genesis block is
+	// mined against.
+	numNets := 0
+	netParams := &chaincfg.MainNetParams
+	if cfg.TestNet4 {
+		numNets++
+		netParams = &chaincfg.TestNet4Params
+	}
+	if cfg.RegressionTest {
+		numNets++
+		netParams = &chaincfg.RegressionNetParams
+	}
+	if cfg.SimNet {
+		numNets++
+		netParams = &chaincfg.SimNetParams
+	}
+	if numNets > 1 {
+		fatalf("the testnet, regtest, and simnet params can't be used " +
+			"together -- choose one of the three")
+	}
+
+	pubKey, err := hex.DecodeString(cfg.PubKey)
+	if err != nil {
+		fatalf("invalid public key: %v", err)
+	}
+	bits := blockchain.BigToCompact(netParams.PowLimit)
+	if cfg.Bits != "" {
+		parsedBits, err := strconv.ParseUint(cfg.Bits, 16, 32)
+		if err != nil {
+			fatalf("invalid proof of work bits %q: %v", cfg.Bits, err)
+		}
+		bits = uint32(parsedBits)
+	}
+	if cfg.JSONOutput && cfg.Magic == 0 {
+		fatalf("the magic option is required for the JSON output")
+	}
+
+	genesis, err := genesistool.NewBlock(&genesistool.Config{
+		Message:   cfg.Message,
+		PubKey:    pubKey,
+		Reward:    cfg.Reward,
+		Timestamp: time.Unix(cfg.Time, 0),
+		Bits:      bits,
+	})
+	if err != nil {
+		fatalf("cannot create the genesis block: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Mining the genesis block against the %s proof "+
+		"of work limit using %d workers...\n", netParams.Name,
+		runtime.NumCPU())
+	if err := genesistool.Solve(genesis, netParams.PowLimit); err != nil {
+		fatalf("cannot mine the genesis block: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Genesis hash: %v\n", genesis.BlockHash())
+
+	if cfg.JSONOutput {
+		err = genesistool.WriteJSON(os.Stdout, cfg.Name,
+			wire.BitcoinNet(cfg.Magic), cfg.Port, genesis)
+	} else {
+		err = genesistool.WriteGo(os.Stdout, cfg.Name, genesis)
+	}
+	if err != nil {
+		fatalf("cannot write the genesis block: %v", err)
+	}
+}
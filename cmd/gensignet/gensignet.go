@@ -0,0 +1,125 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/genesistool"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// config defines the configuration options for gensignet.
+type config struct {
+	Challenge string   `short:"c" long:"challenge" description:"Hex encoded challenge script which defines the signet network" required:"true"`
+	SeedNodes []string `short:"s" long:"seednode" description:"Seed node of the signet network -- May be specified multiple times"`
+	Time      int64    `short:"t" long:"time" description:"Unix timestamp of the genesis block (default: now)"`
+	GoOutput  bool     `short:"g" long:"gooutput" description:"Display the network parameters using Go syntax instead of as a ltcd config snippet"`
+}
+
+// writeConfig writes the ltcd configuration options which join the passed
+// signet network.
+func writeConfig(cfg *config, genesis *wire.MsgBlock) {
+	fmt.Println("[Application Options]")
+	fmt.Println("signet=1")
+	fmt.Printf("signetchallenge=%s\n", cfg.Challenge)
+	fmt.Printf("signetgenesistime=%d\n", genesis.Header.Timestamp.Unix())
+	fmt.Printf("signetgenesisnonce=%d\n", genesis.Header.Nonce)
+	for _, seed := range cfg.SeedNodes {
+		fmt.Printf("signetseednode=%s\n", seed)
+	}
+}
+
+// writeGoParams writes Go code which defines and registers the network
+// parameters of the passed signet network.
+func writeGoParams(cfg *config, genesis *wire.MsgBlock) {
+	fmt.Println("var signetChallenge, _ = hex.DecodeString(")
+	fmt.Printf("\t%q,\n", cfg.Challenge)
+	fmt.Println(")")
+	fmt.Println()
+	fmt.Println("// SignetParams defines the network parameters of the " +
+		"private signet network.")
+	fmt.Println("var SignetParams = chaincfg.CustomSignetParamsWithGenesis(")
+	fmt.Println("\tsignetChallenge,")
+	if len(cfg.SeedNodes) == 0 {
+		fmt.Println("\tnil,")
+	} else {
+		fmt.Println("\t[]chaincfg.DNSSeed{")
+		for _, seed := range cfg.SeedNodes {
+			fmt.Printf("\t\t{Host: %q, HasFiltering: false},\n", seed)
+		}
+		fmt.Println("\t},")
+	}
+	fmt.Printf("\tchaincfg.SignetGenesisBlock(signetChallenge, "+
+		"time.Unix(%d, 0), %d),\n", genesis.Header.Timestamp.Unix(),
+		genesis.Header.Nonce)
+	fmt.Println(")")
+	fmt.Println()
+	fmt.Println("func init() {")
+	fmt.Println("\tif err := chaincfg.Register(&SignetParams); err != nil {")
+	fmt.Println("\t\tpanic(err)")
+	fmt.Println("\t}")
+	fmt.Println("}")
+}
+
+func main() {
+	cfg := config{
+		Time: time.Now().Unix(),
+	}
+	parser := flags.NewParser(&cfg, flags.Default)
+	_, err := parser.Parse()
+	if err != nil {
+		if e, ok := err.(*flags.Error); !ok || e.Type != flags.ErrHelp {
+			parser.WriteHelp(os.Stderr)
+		}
+		os.Exit(1)
+	}
+
+	challenge, err := hex.DecodeString(cfg.Challenge)
+	if err != nil || len(challenge) == 0 {
+		fmt.Fprintf(os.Stderr, "invalid challenge %q: must be a "+
+			"non-empty hex encoded script\n", cfg.Challenge)
+		os.Exit(1)
+	}
+
+	var seeds []chaincfg.DNSSeed
+	for _, seed := range cfg.SeedNodes {
+		seeds = append(seeds, chaincfg.DNSSeed{Host: seed})
+	}
+
+	// The network magic is derived from the challenge, so make sure it
+	// does not collide with any of the default networks before spending
+	// the time to mine the genesis block.
+	params := chaincfg.CustomSignetParams(challenge, seeds)
+	if err := chaincfg.Register(&params); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot register the signet network "+
+			"with magic 0x%08x: %v\n", uint32(params.Net), err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "Mining the genesis block using %d workers...\n",
+		runtime.NumCPU())
+	genesis := chaincfg.SignetGenesisBlock(challenge,
+		time.Unix(cfg.Time, 0), 0)
+	if err := genesistool.Solve(genesis, params.PowLimit); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot mine the genesis block: %v\n", err)
+		os.Exit(1)
+	}
+	params = chaincfg.CustomSignetParamsWithGenesis(challenge, seeds, genesis)
+
+	fmt.Fprintf(os.Stderr, "Network magic: 0x%08x\n", uint32(params.Net))
+	fmt.Fprintf(os.Stderr, "Genesis hash: %v\n", params.GenesisHash)
+	if cfg.GoOutput {
+		writeGoParams(&cfg, genesis)
+		return
+	}
+	writeConfig(&cfg, genesis)
+}
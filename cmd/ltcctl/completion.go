@@ -0,0 +1,116 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/ltcsuite/ltcd/btcjson"
+)
+
+// completionShells are the shells a completion script can be generated for.
+const completionShells = "bash, zsh"
+
+// bashCompletionScript is the template of the bash completion script.  The
+// script completes the options and the commands until a command is found, and
+// the names of the params of the command after that.  It is filled in with the
+// name of the completion function, the options, the commands, the cases
+// listing the params of each command, and the name of the utility.
+const bashCompletionScript = `%[1]s() {
+	local cur="${COMP_WORDS[COMP_CWORD]}"
+	local options="%[2]s"
+	local commands="%[3]s"
+	local cmd="" params="" i
+
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		if [[ " $commands " == *" ${COMP_WORDS[i]} "* ]]; then
+			cmd="${COMP_WORDS[i]}"
+			break
+		fi
+	done
+
+	if [[ -z "$cmd" ]]; then
+		if [[ "$cur" == -* ]]; then
+			COMPREPLY=($(compgen -W "$options" -- "$cur"))
+		else
+			COMPREPLY=($(compgen -W "$commands" -- "$cur"))
+		fi
+		return
+	fi
+
+	case "$cmd" in
+%[4]s	esac
+	compopt -o nospace 2>/dev/null
+	COMPREPLY=($(compgen -W "$params" -- "$cur"))
+}
+complete -F %[1]s %[5]s
+`
+
+// nonIdentifierRegexp matches the characters which may not be used in the name
+// of a shell function.
+var nonIdentifierRegexp = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// writeCompletion writes a script which provides completion of the options of
+// the passed parser, the usable commands, and the names of their params to the
+// passed writer for the passed shell.  The zsh script relies on the bash
+// completion support of zsh.
+func writeCompletion(w io.Writer, shell, appName string, parser *flags.Parser) error {
+	var header string
+	switch shell {
+	case "bash":
+	case "zsh":
+		header = "autoload -U +X bashcompinit && bashcompinit\n"
+	default:
+		return fmt.Errorf("unsupported completion shell %q -- "+
+			"supported shells are %s", shell, completionShells)
+	}
+
+	var options []string
+	for _, group := range parser.Groups() {
+		for _, option := range group.Options() {
+			if option.ShortName != 0 {
+				options = append(options, "-"+
+					string(option.ShortName))
+			}
+			if option.LongName != "" {
+				options = append(options, "--"+option.LongName)
+			}
+		}
+	}
+
+	// Complete the names of the params followed by an equals sign so the
+	// value can be typed right away.
+	var commands []string
+	var cases strings.Builder
+	for _, method := range btcjson.RegisteredCmdMethods() {
+		usageFlags, err := btcjson.MethodUsageFlags(method)
+		if err != nil || usageFlags&unusableFlags != 0 {
+			continue
+		}
+		names, err := btcjson.MethodParamNames(method)
+		if err != nil {
+			continue
+		}
+
+		commands = append(commands, method)
+		if len(names) == 0 {
+			continue
+		}
+		fmt.Fprintf(&cases, "\t%s) params=\"%s=\" ;;\n", method,
+			strings.Join(names, "= "))
+	}
+
+	funcName := "_" + nonIdentifierRegexp.ReplaceAllString(appName, "_")
+	script := fmt.Sprintf(bashCompletionScript, funcName,
+		strings.Join(options, " "), strings.Join(commands, " "),
+		cases.String(), appName)
+	_, err := fmt.Fprintf(w, "# %s completion for %s\n%s%s", shell,
+		appName, header, script)
+	return err
+}
@@ -93,14 +93,18 @@ func listCommands() {
 //
 // See loadConfig for details on the configuration load process.
 type config struct {
+	Completion     string `long:"completion" description:"Print a completion script for the specified shell (bash, zsh) and exit"`
 	ConfigFile     string `short:"C" long:"configfile" description:"Path to configuration file"`
 	ListCommands   bool   `short:"l" long:"listcommands" description:"List all of the supported commands and exit"`
 	NoTLS          bool   `long:"notls" description:"Disable TLS"`
 	Proxy          string `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	ProxyPass      string `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
 	ProxyUser      string `long:"proxyuser" description:"Username for proxy server"`
+	ReadStdin      bool   `long:"stdin" description:"Read additional arguments from standard input, one per line"`
 	RegressionTest bool   `long:"regtest" description:"Connect to the regression test network"`
 	RPCCert        string `short:"c" long:"rpccert" description:"RPC server certificate chain for validation"`
+	RPCClientCert  string `long:"rpcclientcert" description:"Certificate chain to authenticate with when the RPC server requires client certificates"`
+	RPCClientKey   string `long:"rpcclientkey" description:"Private key of the client certificate"`
 	RPCPassword    string `short:"P" long:"rpcpass" default-mask:"-" description:"RPC password"`
 	RPCServer      string `short:"s" long:"rpcserver" description:"RPC server to connect to"`
 	RPCUser        string `short:"u" long:"rpcuser" description:"RPC username"`
@@ -207,6 +211,11 @@ func loadConfig() (*config, []string, error) {
 				"indicates that a parameter should be read "+
 				"from the\nnext unread line from standard "+
 				"input.")
+			fmt.Fprintln(os.Stderr, "")
+			fmt.Fprintln(os.Stderr, "Parameters may also be "+
+				"specified by name as name=value, such as "+
+				"hash=<hash>\nverbosity=2 for getblock, after "+
+				"any positional parameters.")
 			return nil, nil, err
 		}
 	}
@@ -227,6 +236,18 @@ func loadConfig() (*config, []string, error) {
 		os.Exit(0)
 	}
 
+	// Print the completion script and exit if the associated flag was
+	// specified.
+	if preCfg.Completion != "" {
+		err := writeCompletion(os.Stdout, preCfg.Completion, appName,
+			preParser)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		os.Exit(0)
+	}
+
 	if _, err := os.Stat(preCfg.ConfigFile); os.IsNotExist(err) {
 		// Use config file for RPC server to create default btcctl config
 		var serverConfigPath string
@@ -302,6 +323,19 @@ func loadConfig() (*config, []string, error) {
 	// Handle environment variable expansion in the RPC certificate path.
 	cfg.RPCCert = cleanAndExpandPath(cfg.RPCCert)
 
+	// The client certificate and its key must be specified together.
+	if (cfg.RPCClientCert == "") != (cfg.RPCClientKey == "") {
+		str := "%s: the rpcclientcert and rpcclientkey options must " +
+			"be specified together"
+		err := fmt.Errorf(str, "loadConfig")
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+	if cfg.RPCClientCert != "" {
+		cfg.RPCClientCert = cleanAndExpandPath(cfg.RPCClientCert)
+		cfg.RPCClientKey = cleanAndExpandPath(cfg.RPCClientKey)
+	}
+
 	// Add default port to RPC server based on --testnet and --wallet flags
 	// if needed.
 	cfg.RPCServer, err = normalizeAddress(cfg.RPCServer, network, cfg.Wallet)
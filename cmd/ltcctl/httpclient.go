@@ -50,6 +50,21 @@ func newHTTPClient(cfg *config) (*http.Client, error) {
 		}
 	}
 
+	// Authenticate with the client certificate if one was specified.
+	if !cfg.NoTLS && cfg.RPCClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.RPCClientCert,
+			cfg.RPCClientKey)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{
+				InsecureSkipVerify: cfg.TLSSkipVerify,
+			}
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
 	// Create and return the new HTTP client potentially configured with a
 	// proxy and TLS.
 	client := http.Client{
@@ -80,8 +95,11 @@ func sendPostRequest(marshalledJSON []byte, cfg *config) ([]byte, error) {
 	httpRequest.Close = true
 	httpRequest.Header.Set("Content-Type", "application/json")
 
-	// Configure basic access authorization.
-	httpRequest.SetBasicAuth(cfg.RPCUser, cfg.RPCPassword)
+	// Configure basic access authorization unless the client authenticates
+	// with a certificate only.
+	if cfg.RPCClientCert == "" || cfg.RPCUser != "" || cfg.RPCPassword != "" {
+		httpRequest.SetBasicAuth(cfg.RPCUser, cfg.RPCPassword)
+	}
 
 	// Create the new HTTP client that is configured according to the user-
 	// specified options and submit the request.
@@ -46,6 +46,70 @@ func usage(errorMessage string) {
 	fmt.Fprintln(os.Stderr, listCmdMessage)
 }
 
+// newCmd creates the command for the passed method from the passed arguments.
+// An argument of the form name=value, where name is the name of one of the
+// params of the command, specifies that param by name.  Such arguments may only
+// follow the positional arguments.
+func newCmd(method string, args []string) (interface{}, error) {
+	names, err := btcjson.MethodParamNames(method)
+	if err != nil {
+		return nil, err
+	}
+	isParamName := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		isParamName[name] = struct{}{}
+	}
+
+	positional := make([]interface{}, 0, len(args))
+	var named map[string]interface{}
+	for _, arg := range args {
+		var name string
+		if i := strings.Index(arg, "="); i > 0 {
+			name = arg[:i]
+		}
+		if _, ok := isParamName[name]; !ok {
+			if named != nil && name != "" {
+				return nil, fmt.Errorf("unknown parameter %q",
+					name)
+			}
+			if named != nil {
+				return nil, fmt.Errorf("positional parameter "+
+					"%q follows named parameters", arg)
+			}
+			positional = append(positional, arg)
+			continue
+		}
+
+		if named == nil {
+			named = make(map[string]interface{})
+		}
+		if _, ok := named[name]; ok {
+			return nil, fmt.Errorf("parameter %q specified more "+
+				"than once", name)
+		}
+		named[name] = arg[len(name)+1:]
+	}
+	if named == nil {
+		return btcjson.NewCmd(method, positional...)
+	}
+
+	// Name the positional arguments so all of the arguments can be passed
+	// by name.
+	if len(positional) > len(names) {
+		return nil, fmt.Errorf("too many positional parameters "+
+			"(expected at most %d, received %d)", len(names),
+			len(positional))
+	}
+	for i, arg := range positional {
+		if _, ok := named[names[i]]; ok {
+			return nil, fmt.Errorf("parameter %q specified both "+
+				"by position and by name", names[i])
+		}
+		named[names[i]] = arg
+	}
+	return btcjson.NewCmdNamed(method, named)
+}
+
 func main() {
 	cfg, args, err := loadConfig()
 	if err != nil {
@@ -72,15 +136,12 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Convert remaining command line args to a slice of interface values
-	// to be passed along as parameters to new command creation function.
-	//
 	// Since some commands, such as submitblock, can involve data which is
 	// too large for the Operating System to allow as a normal command line
 	// parameter, support using '-' as an argument to allow the argument
 	// to be read from a stdin pipe.
 	bio := bufio.NewReader(os.Stdin)
-	params := make([]interface{}, 0, len(args[1:]))
+	cmdArgs := make([]string, 0, len(args[1:]))
 	for _, arg := range args[1:] {
 		if arg == "-" {
 			param, err := bio.ReadString('\n')
@@ -95,21 +156,40 @@ func main() {
 				os.Exit(1)
 			}
 			param = strings.TrimRight(param, "\r\n")
-			params = append(params, param)
+			cmdArgs = append(cmdArgs, param)
 			continue
 		}
 
-		params = append(params, arg)
+		cmdArgs = append(cmdArgs, arg)
+	}
+
+	// Append the remaining lines provided on stdin to the arguments when
+	// requested.
+	if cfg.ReadStdin {
+		for {
+			param, err := bio.ReadString('\n')
+			if err != nil && err != io.EOF {
+				fmt.Fprintf(os.Stderr, "Failed to read data "+
+					"from stdin: %v\n", err)
+				os.Exit(1)
+			}
+			if err == io.EOF && len(param) == 0 {
+				break
+			}
+			cmdArgs = append(cmdArgs, strings.TrimRight(param, "\r\n"))
+			if err == io.EOF {
+				break
+			}
+		}
 	}
 
 	// Attempt to create the appropriate command using the arguments
 	// provided by the user.
-	cmd, err := btcjson.NewCmd(method, params...)
+	cmd, err := newCmd(method, cmdArgs)
 	if err != nil {
 		// Show the error along with its error code when it's a
-		// btcjson.Error as it reallistcally will always be since the
-		// NewCmd function is only supposed to return errors of that
-		// type.
+		// btcjson.Error as it will be unless the named arguments are
+		// malformed.
 		if jerr, ok := err.(btcjson.Error); ok {
 			fmt.Fprintf(os.Stderr, "%s command: %v (code: %s)\n",
 				method, err, jerr.ErrorCode)
@@ -117,9 +197,6 @@ func main() {
 			os.Exit(1)
 		}
 
-		// The error is not a btcjson.Error and this really should not
-		// happen.  Nevertheless, fallback to just showing the error
-		// if it should happen due to a bug in the package.
 		fmt.Fprintf(os.Stderr, "%s command: %v\n", method, err)
 		commandUsage(method)
 		os.Exit(1)
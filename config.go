@@ -15,6 +15,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"sort"
 	"strconv"
@@ -31,6 +32,7 @@ import (
 	_ "github.com/ltcsuite/ltcd/database/ffldb"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/mempool"
+	"github.com/ltcsuite/ltcd/mining"
 	"github.com/ltcsuite/ltcd/peer"
 	"github.com/ltcsuite/ltcd/wire"
 )
@@ -41,32 +43,40 @@ const (
 	defaultLogLevel              = "info"
 	defaultLogDirname            = "logs"
 	defaultLogFilename           = "ltcd.log"
+	defaultLogFormat             = logFormatText
+	defaultLogMaxSize            = 10
+	defaultLogMaxRolls           = 3
 	defaultMaxPeers              = 125
 	defaultBanDuration           = time.Hour * 24
 	defaultBanThreshold          = 100
 	defaultConnectTimeout        = time.Second * 30
+	defaultShutdownTimeout       = time.Second * 20
 	defaultMaxRPCClients         = 10
 	defaultMaxRPCWebsockets      = 25
 	defaultMaxRPCConcurrentReqs  = 20
+	defaultRPCRateBurst          = 20
 	defaultDbType                = "ffldb"
 	defaultFreeTxRelayLimit      = 15.0
 	defaultTrickleInterval       = peer.DefaultTrickleInterval
+	defaultDandelionEpoch        = 10 * time.Minute
+	defaultDandelionEmbargo      = 30 * time.Second
 	defaultBlockMinSize          = 0
 	defaultBlockMaxSize          = 750000
 	defaultBlockMinWeight        = 0
 	defaultBlockMaxWeight        = 3000000
 	blockMaxSizeMin              = 1000
-	blockMaxSizeMax              = blockchain.MaxBlockBaseSize - 1000
 	blockMaxWeightMin            = 4000
-	blockMaxWeightMax            = blockchain.MaxBlockWeight - 4000
 	defaultGenerate              = false
 	defaultMaxOrphanTransactions = 100
 	defaultMaxOrphanTxSize       = 100000
 	defaultSigCacheMaxSize       = 100000
+	defaultScriptCacheMaxSize    = 100000
 	sampleConfigFilename         = "sample-ltcd.conf"
 	defaultTxIndex               = false
 	defaultAddrIndex             = false
 	pruneMinSize                 = 1536
+	pruneStaleForksMinDepth      = 288
+	defaultPruneStaleForkQuota   = 128
 )
 
 var (
@@ -109,9 +119,13 @@ type config struct {
 	BlockMinWeight       uint32        `long:"blockminweight" description:"Mininum block weight to be used when creating a block"`
 	BlockPrioritySize    uint32        `long:"blockprioritysize" description:"Size in bytes for high-priority/low-fee transactions when creating a block"`
 	BlocksOnly           bool          `long:"blocksonly" description:"Do not accept transactions from remote peers."`
+	CoinbaseReservedSize uint32        `long:"coinbasereservedsize" description:"Number of bytes to reserve in the signature script of the coinbase of block templates for the extra nonces of mining pools"`
 	ConfigFile           string        `short:"C" long:"configfile" description:"Path to configuration file"`
 	ConnectPeers         []string      `long:"connect" description:"Connect only to the specified peers at startup"`
 	CPUProfile           string        `long:"cpuprofile" description:"Write CPU profile to the specified file"`
+	Dandelion            bool          `long:"dandelion" description:"Relay locally submitted transactions to a single outbound peer along a Dandelion++ stem before they are broadcast to all peers to hide their origin"`
+	DandelionEmbargo     time.Duration `long:"dandelionembargo" description:"Minimum time a transaction stays in the Dandelion++ stem before it is broadcast to all peers unless another peer announced it -- A random delay of up to the same duration is added"`
+	DandelionEpoch       time.Duration `long:"dandelionepoch" description:"Minimum time the outbound peer chosen as the Dandelion++ stem is kept before a new one is chosen at random -- A random delay of up to the same duration is added"`
 	MemoryProfile        string        `long:"memprofile" description:"Write memory profile to the specified file"`
 	DataDir              string        `short:"b" long:"datadir" description:"Directory to store data"`
 	DbType               string        `long:"dbtype" description:"Database backend to use for the Block Chain"`
@@ -119,30 +133,45 @@ type config struct {
 	DropAddrIndex        bool          `long:"dropaddrindex" description:"Deletes the address-based transaction index from the database on start up and then exits."`
 	DropCfIndex          bool          `long:"dropcfindex" description:"Deletes the index used for committed filtering (CF) support from the database on start up and then exits."`
 	DropTxIndex          bool          `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits."`
+	DropWatchOnly        bool          `long:"dropwatchonly" description:"Deletes the watched scripts and their unspent outputs from the database on start up and then exits."`
+	DumpConfig           bool          `long:"dumpconfig" description:"Write the configuration merged from the config file, environment variables and command line options to stdout in the format of the config file and exit -- Passwords are masked"`
+	ExportBlocks         string        `long:"export-blocks" description:"Write the blocks of the main chain to the specified file in the bootstrap.dat format on start up and then exit"`
+	ExportHeight         int32         `long:"export-height" description:"Height of the last block written by --export-blocks (default: the current best height)"`
 	ExternalIPs          []string      `long:"externalip" description:"Add an ip to the list of local addresses we claim to listen on to peers"`
+	FastTemplate         bool          `long:"fasttemplate" description:"Serve an empty block template to getblocktemplate callers as soon as the best chain changes and notify long polling callers once the full template with transactions is ready"`
 	Generate             bool          `long:"generate" description:"Generate (mine) litecoins using the CPU"`
+	GetWork              bool          `long:"getwork" description:"Enable the legacy getwork RPC for mining proxies which cannot speak getblocktemplate or stratum -- Requires --miningaddr"`
 	FreeTxRelayLimit     float64       `long:"limitfreerelay" description:"Limit relay of transactions with no transaction fee to the given amount in thousands of bytes per minute"`
-	Listeners            []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9333, testnet: 19333)"`
+	Listeners            []string      `long:"listen" description:"Add an interface/port to listen for connections, optionally preceded by the comma separated services {network, witness, bloom, cf, networklimited, mweblightclient, mweb} or none advertised to its peers (default all interfaces port: 9333, testnet: 19333, services: all of the node, eg. witness,networklimited@127.0.0.1:9335)"`
+	LoadBlocks           []string      `long:"loadblock" description:"Import the blocks from the specified bootstrap.dat file on start up -- May be specified multiple times"`
 	LogDir               string        `long:"logdir" description:"Directory to log output."`
+	LogFormat            string        `long:"logformat" description:"Format of the log output {text, json}"`
+	LogMaxRolls          int           `long:"logmaxrolls" description:"Maximum number of rotated log files to keep"`
+	LogMaxSize           int64         `long:"logmaxsize" description:"Maximum size in MiB of the log file before it is rotated"`
 	MaxOrphanTxs         int           `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
 	MaxPeers             int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
 	MiningAddrs          []string      `long:"miningaddr" description:"Add the specified payment address to the list of addresses to use for generated blocks -- At least one address is required if the generate option is set"`
 	MinRelayTxFee        float64       `long:"minrelaytxfee" description:"The minimum transaction fee in BTC/kB to be considered a non-zero fee."`
+	MinProtocolVersion   uint32        `long:"minprotocolversion" description:"Minimum protocol version accepted from peers (default: the minimum of the active network)"`
+	NetProxies           []string      `long:"netproxy" description:"Connect to the ipv4 or ipv6 network via its own SOCKS5 proxy, or directly with 'direct', instead of --proxy (eg. ipv6=127.0.0.1:9050 or ipv4=direct) -- May be specified multiple times"`
 	DisableBanning       bool          `long:"nobanning" description:"Disable banning of misbehaving peers"`
 	NoCFilters           bool          `long:"nocfilters" description:"Disable committed filtering (CF) support"`
 	DisableCheckpoints   bool          `long:"nocheckpoints" description:"Disable built-in checkpoints.  Don't do this unless you know what you're doing."`
 	DisableDNSSeed       bool          `long:"nodnsseed" description:"Disable DNS seeding for peers"`
 	DisableListen        bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
 	NoOnion              bool          `long:"noonion" description:"Disable connecting to tor hidden services"`
-	NoPeerBloomFilters   bool          `long:"nopeerbloomfilters" description:"Disable bloom filtering support"`
+	NoPeerBloomFilters   bool          `long:"nopeerbloomfilters" description:"DEPRECATED: Bloom filtering support is disabled by default -- use --peerbloomfilters to enable it"`
 	NoRelayPriority      bool          `long:"norelaypriority" description:"Do not require free or low-fee transactions to have high priority for relaying"`
 	NoWinService         bool          `long:"nowinservice" description:"Do not start as a background service on Windows -- NOTE: This flag only works on the command line, not in the config file"`
-	DisableRPC           bool          `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
+	DisableRPC           bool          `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass, rpclimituser/rpclimitpass or rpcclientca is specified"`
 	DisableStallHandler  bool          `long:"nostalldetect" description:"Disables the stall handler system for each peer, useful in simnet/regtest integration tests frameworks"`
 	DisableTLS           bool          `long:"notls" description:"Disable TLS for the RPC server -- NOTE: This is only allowed if the RPC server is bound to localhost"`
 	OnionProxy           string        `long:"onion" description:"Connect to tor hidden services via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	OnionProxyPass       string        `long:"onionpass" default-mask:"-" description:"Password for onion proxy server"`
 	OnionProxyUser       string        `long:"onionuser" description:"Username for onion proxy server"`
+	OnlyNets             []string      `long:"onlynet" description:"Only make outbound connections, including to --addpeer and --connect peers, to the given network {ipv4, ipv6, onion} -- May be specified multiple times"`
+	PeerBloomFilters     bool          `long:"peerbloomfilters" description:"Enable BIP0037 bloom filtering support for SPV peers"`
+	PreferredServices    string        `long:"preferredservices" description:"Comma separated services {network, witness, bloom, cf, networklimited, mweblightclient, mweb} outbound connections favor peers advertising (default: the preferences of the active network)"`
 	Profile              string        `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
 	Proxy                string        `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	ProxyPass            string        `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
@@ -150,39 +179,65 @@ type config struct {
 	TorIsolation         bool          `long:"torisolation" description:"Enable Tor stream isolation by randomizing user credentials for each connection."`
 	TestNet4             bool          `long:"testnet" description:"Use the test network"`
 	Prune                uint64        `long:"prune" description:"Prune already validated blocks from the database. Must specify a target size in MiB (minimum value of 1536, default value of 0 will disable pruning)"`
+	PruneStaleForks      int32         `long:"prunestaleforks" description:"Remove side chains whose tips are more than the given number of blocks behind the main chain from the block index along with their blocks (minimum value of 288, default value of 0 will keep all side chains)"`
+	PruneStaleForkQuota  uint64        `long:"prunestaleforkquota" description:"Disk space in MiB the deleted blocks of removed side chains may keep using before the block files holding them are rewritten to release it -- Only used with --prunestaleforks"`
 	RegressionTest       bool          `long:"regtest" description:"Use the regression test network"`
 	RejectNonStd         bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network."`
 	RejectReplacement    bool          `long:"rejectreplacement" description:"Reject transactions that attempt to replace existing transactions within the mempool through the Replace-By-Fee (RBF) signaling policy."`
+	RequiredServices     string        `long:"requiredservices" description:"Comma separated services {network, witness, bloom, cf, networklimited, mweblightclient, mweb} outbound peers must advertise (default: the requirements of the active network)"`
 	RelayNonStd          bool          `long:"relaynonstd" description:"Relay non-standard transactions regardless of the default settings for the active network."`
 	RPCCert              string        `long:"rpccert" description:"File containing the certificate file"`
+	RPCClientCA          string        `long:"rpcclientca" description:"File containing the CA certificates RPC client certificates must be signed by -- Requires RPC clients to authenticate with a certificate"`
 	RPCKey               string        `long:"rpckey" description:"File containing the certificate key"`
 	RPCLimitPass         string        `long:"rpclimitpass" default-mask:"-" description:"Password for limited RPC connections"`
 	RPCLimitUser         string        `long:"rpclimituser" description:"Username for limited RPC connections"`
 	RPCListeners         []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 9334, testnet: 19334)"`
 	RPCMaxClients        int           `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
 	RPCMaxConcurrentReqs int           `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
+	RPCMaxGlobalReqs     int           `long:"rpcmaxglobalreqs" description:"Max number of RPC requests processed concurrently across all clients, mining requests excluded -- 0 for no limit"`
 	RPCMaxWebsockets     int           `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
+	RPCRateLimit         float64       `long:"rpcratelimit" description:"Max number of RPC requests per second from a single client host -- 0 for no limit"`
+	RPCRateBurst         int           `long:"rpcrateburst" description:"Max number of RPC requests a single client host may send at once when rpcratelimit is set"`
 	RPCQuirks            bool          `long:"rpcquirks" description:"Mirror some JSON-RPC quirks of Litecoin Core -- NOTE: Discouraged unless interoperability issues need to be worked around"`
 	RPCPass              string        `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
 	RPCUser              string        `short:"u" long:"rpcuser" description:"Username for RPC connections"`
+	ShutdownTimeout      time.Duration `long:"shutdowntimeout" description:"Maximum time to wait for the server to stop on shutdown before the database is flushed and closed anyway -- Valid time units are {s, m, h}.  Set to 0 to wait indefinitely"`
 	SigCacheMaxSize      uint          `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
+	ScriptCacheMaxSize   uint          `long:"scriptcachemaxsize" description:"The maximum number of entries in the validated script cache"`
 	SimNet               bool          `long:"simnet" description:"Use the simulation test network"`
 	SigNet               bool          `long:"signet" description:"Use the signet test network"`
 	SigNetChallenge      string        `long:"signetchallenge" description:"Connect to a custom signet network defined by this challenge instead of using the global default signet test network -- Can be specified multiple times"`
 	SigNetSeedNode       []string      `long:"signetseednode" description:"Specify a seed node for the signet network instead of using the global default signet network seed nodes"`
+	SigNetGenesisTime    int64         `long:"signetgenesistime" description:"Unix timestamp of the genesis block of the custom signet network as output by gensignet"`
+	SigNetGenesisNonce   uint32        `long:"signetgenesisnonce" description:"Nonce of the genesis block of the custom signet network as output by gensignet"`
+	SigNetMiningKeys     []string      `long:"signetminingkey" description:"Add the specified WIF private key to the keys used to sign the blocks generated on the signet network -- May be specified multiple times"`
 	TrickleInterval      time.Duration `long:"trickleinterval" description:"Minimum time between attempts to send new inventory to a connected peer"`
 	TxIndex              bool          `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
 	UserAgentComments    []string      `long:"uacomment" description:"Comment to add to the user agent -- See BIP 14 for more information."`
 	Upnp                 bool          `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
 	ShowVersion          bool          `short:"V" long:"version" description:"Display version information and exit"`
-	Whitelists           []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned. (eg. 192.168.1.0/24 or ::1)"`
+	WatchOnly            bool          `long:"watchonly" description:"Track the unspent outputs paying to the scripts registered with the importwatchonly RPC"`
+	Whitelists           []string      `long:"whitelist" description:"Add an IP network or IP with optional comma separated permissions (noban, forcerelay, mempool, bloomfilter, all) granted to its peers -- Defaults to noban when no permissions are given (eg. 192.168.1.0/24, ::1, or forcerelay,noban@10.0.0.0/8)"`
 	lookup               func(string) ([]net.IP, error)
 	oniondial            func(string, string, time.Duration) (net.Conn, error)
 	dial                 func(string, string, time.Duration) (net.Conn, error)
+	netdial              map[string]func(string, string, time.Duration) (net.Conn, error)
 	addCheckpoints       []chaincfg.Checkpoint
 	miningAddrs          []ltcutil.Address
+	signetMiningKeys     []*ltcutil.WIF
 	minRelayTxFee        ltcutil.Amount
-	whitelists           []*net.IPNet
+	minProtocolVersion   uint32
+	requiredServices     wire.ServiceFlag
+	preferredServices    wire.ServiceFlag
+	whitelists           []whitelist
+	listenerServices     map[string]wire.ServiceFlag
+}
+
+// whitelist houses a whitelisted IP network along with the permissions granted
+// to peers connecting from it.
+type whitelist struct {
+	ipnet       *net.IPNet
+	permissions peer.PermissionFlags
 }
 
 // serviceOptions defines the configuration options for the daemon as a service on
@@ -365,6 +420,64 @@ func newCheckpointFromStr(checkpoint string) (chaincfg.Checkpoint, error) {
 	}, nil
 }
 
+// parseWhitelist parses a whitelist entry in the '[<permissions>@]<ip or cidr>'
+// format.  The permissions are a comma separated list of permission names and
+// default to noban when omitted.
+func parseWhitelist(entry string) (whitelist, error) {
+	permissions := peer.PermissionNoBan
+	addr := entry
+	if idx := strings.LastIndex(entry, "@"); idx != -1 {
+		var err error
+		permissions, err = peer.ParsePermissionFlags(entry[:idx])
+		if err != nil {
+			return whitelist{}, err
+		}
+		addr = entry[idx+1:]
+	}
+
+	_, ipnet, err := net.ParseCIDR(addr)
+	if err != nil {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return whitelist{}, fmt.Errorf("invalid IP or network "+
+				"%q", addr)
+		}
+		var bits int
+		if ip.To4() == nil {
+			// IPv6
+			bits = 128
+		} else {
+			bits = 32
+		}
+		ipnet = &net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(bits, bits),
+		}
+	}
+
+	return whitelist{ipnet: ipnet, permissions: permissions}, nil
+}
+
+// parseListener parses a listener entry in the '[<services>@]<addr>' format.
+// The services are a comma separated list of service flag names, or none to
+// advertise no services, and hasServices is false when they are omitted.
+func parseListener(entry string) (addr string, services wire.ServiceFlag,
+	hasServices bool, err error) {
+
+	idx := strings.LastIndex(entry, "@")
+	if idx == -1 {
+		return entry, 0, false, nil
+	}
+
+	if names := entry[:idx]; !strings.EqualFold(names, "none") {
+		services, err = wire.ParseServiceFlags(names)
+		if err != nil {
+			return "", 0, false, err
+		}
+	}
+	return entry[idx+1:], services, true, nil
+}
+
 // parseCheckpoints checks the checkpoint strings for valid syntax
 // ('<height>:<hash>') and parses them to chaincfg.Checkpoint instances.
 func parseCheckpoints(checkpointStrings []string) ([]chaincfg.Checkpoint, error) {
@@ -401,18 +514,123 @@ func newConfigParser(cfg *config, so *serviceOptions, options flags.Options) *fl
 	return parser
 }
 
+// envVarPrefix is the prefix of the environment variables the options can be
+// set with.  The name of the variable of an option is its long name in upper
+// case with dashes replaced by underscores and this prefix, such as
+// LTCD_RPCUSER for --rpcuser.
+const envVarPrefix = "LTCD_"
+
+// forEachOption invokes the passed function with every option of the passed
+// parser which has a long name.
+func forEachOption(parser *flags.Parser, f func(option *flags.Option)) {
+	var walk func(groups []*flags.Group)
+	walk = func(groups []*flags.Group) {
+		for _, group := range groups {
+			for _, option := range group.Options() {
+				if option.LongName != "" {
+					f(option)
+				}
+			}
+			walk(group.Groups())
+		}
+	}
+	walk(parser.Groups())
+}
+
+// optionEnvVar returns the name of the environment variable the passed option
+// can be set with.
+func optionEnvVar(option *flags.Option) string {
+	name := strings.ReplaceAll(option.LongName, "-", "_")
+	return envVarPrefix + strings.ToUpper(name)
+}
+
+// parseEnvConfig sets the options of the passed parser, which parses into the
+// passed config, from their environment variables, which uses the same syntax
+// as the config file.  The values of options which may be specified multiple
+// times are separated by whitespace and replace the values from the config
+// file, so an empty variable clears them.
+func parseEnvConfig(parser *flags.Parser, cfg *config) error {
+	var err error
+	forEachOption(parser, func(option *flags.Option) {
+		key := optionEnvVar(option)
+		value, ok := os.LookupEnv(key)
+		if !ok || err != nil {
+			return
+		}
+
+		values := []string{value}
+		if reflect.ValueOf(option.Value()).Kind() == reflect.Slice {
+			values = strings.Fields(value)
+			field := reflect.ValueOf(cfg).Elem().FieldByName(
+				option.Field().Name)
+			if field.Kind() == reflect.Slice {
+				field.Set(reflect.Zero(field.Type()))
+			}
+			if len(values) == 0 {
+				return
+			}
+		}
+		var ini strings.Builder
+		for _, value := range values {
+			fmt.Fprintf(&ini, "%s=%s\n", option.LongName, value)
+		}
+
+		iniParser := flags.NewIniParser(parser)
+		e := iniParser.Parse(strings.NewReader(ini.String()))
+		if iniErr, ok := e.(*flags.IniError); ok {
+			e = errors.New(iniErr.Message)
+		}
+		if e != nil {
+			err = fmt.Errorf("invalid environment variable %s: %v",
+				key, e)
+		}
+	})
+	return err
+}
+
+// writeConfig writes the options of the passed parser which were set by the
+// config file, environment variables or command line options to the passed
+// writer in the format of the config file.  The values of the options which
+// are masked in the usage message, such as passwords, are masked as well.
+func writeConfig(w io.Writer, parser *flags.Parser) {
+	forEachOption(parser, func(option *flags.Option) {
+		if !option.IsSet() || option.LongName == "dumpconfig" {
+			return
+		}
+
+		var values []interface{}
+		value := reflect.ValueOf(option.Value())
+		if value.Kind() == reflect.Slice {
+			for i := 0; i < value.Len(); i++ {
+				values = append(values, value.Index(i).Interface())
+			}
+		} else {
+			values = append(values, value.Interface())
+		}
+		for _, value := range values {
+			if option.DefaultMask == "-" {
+				value = "********"
+			}
+			fmt.Fprintf(w, "%s=%v\n", option.LongName, value)
+		}
+	})
+}
+
 // loadConfig initializes and parses the config using a config file and command
 // line options.
 //
 // The configuration proceeds as follows:
 //  1. Start with a default config with sane settings
-//  2. Pre-parse the command line to check for an alternative config file
+//  2. Pre-parse the environment variables and command line to check for an
+//     alternative config file
 //  3. Load configuration file overwriting defaults with any specified options
-//  4. Parse CLI options and overwrite/add any specified options
+//  4. Load environment variables overwriting any specified options
+//  5. Parse CLI options and overwrite/add any specified options
 //
 // The above results in ltcd functioning properly without any config settings
-// while still allowing the user to override settings with config files and
-// command line options.  Command line options always take precedence.
+// while still allowing the user to override settings with config files,
+// environment variables and command line options.  Command line options always
+// take precedence.
 func loadConfig() (*config, []string, error) {
 	// Default config.
 	cfg := config{
@@ -424,14 +642,21 @@ func loadConfig() (*config, []string, error) {
 		RPCMaxClients:        defaultMaxRPCClients,
 		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
 		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
+		RPCRateBurst:         defaultRPCRateBurst,
 		DataDir:              defaultDataDir,
 		LogDir:               defaultLogDir,
+		LogFormat:            defaultLogFormat,
+		LogMaxSize:           defaultLogMaxSize,
+		LogMaxRolls:          defaultLogMaxRolls,
 		DbType:               defaultDbType,
 		RPCKey:               defaultRPCKeyFile,
 		RPCCert:              defaultRPCCertFile,
 		MinRelayTxFee:        mempool.DefaultMinRelayTxFee.ToBTC(),
 		FreeTxRelayLimit:     defaultFreeTxRelayLimit,
 		TrickleInterval:      defaultTrickleInterval,
+		DandelionEpoch:       defaultDandelionEpoch,
+		DandelionEmbargo:     defaultDandelionEmbargo,
+		ShutdownTimeout:      defaultShutdownTimeout,
 		BlockMinSize:         defaultBlockMinSize,
 		BlockMaxSize:         defaultBlockMaxSize,
 		BlockMinWeight:       defaultBlockMinWeight,
@@ -439,9 +664,11 @@ func loadConfig() (*config, []string, error) {
 		BlockPrioritySize:    mempool.DefaultBlockPrioritySize,
 		MaxOrphanTxs:         defaultMaxOrphanTransactions,
 		SigCacheMaxSize:      defaultSigCacheMaxSize,
+		ScriptCacheMaxSize:   defaultScriptCacheMaxSize,
 		Generate:             defaultGenerate,
 		TxIndex:              defaultTxIndex,
 		AddrIndex:            defaultAddrIndex,
+		PruneStaleForkQuota:  defaultPruneStaleForkQuota,
 	}
 
 	// Service options which are only added on Windows.
@@ -451,8 +678,14 @@ func loadConfig() (*config, []string, error) {
 	// file or the version flag was specified.  Any errors aside from the
 	// help message error can be ignored here since they will be caught by
 	// the final parse below.
+	// The environment variables are taken into account as well since they
+	// may select the config file or network too.
 	preCfg := cfg
 	preParser := newConfigParser(&preCfg, &serviceOpts, flags.HelpFlag)
+	if err := parseEnvConfig(preParser, &preCfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
 	_, err := preParser.Parse()
 	if err != nil {
 		if e, ok := err.(*flags.Error); ok && e.Type == flags.ErrHelp {
@@ -512,6 +745,14 @@ func loadConfig() (*config, []string, error) {
 		cfg.AddPeers = nil
 	}
 
+	// Load the options set via environment variables, which take
+	// precedence over the config file.
+	if err := parseEnvConfig(parser, &cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Parse command line options again to ensure they take precedence.
 	remainingArgs, err := parser.Parse()
 	if err != nil {
@@ -521,6 +762,12 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Show the merged configuration and exit if requested.
+	if cfg.DumpConfig {
+		writeConfig(os.Stdout, parser)
+		os.Exit(0)
+	}
+
 	// Create the home directory if it doesn't already exist.
 	funcName := "loadConfig"
 	err = os.MkdirAll(defaultHomeDir, 0700)
@@ -559,6 +806,18 @@ func loadConfig() (*config, []string, error) {
 		activeNetParams = &simNetParams
 		cfg.DisableDNSSeed = true
 	}
+	if (cfg.SigNetGenesisTime != 0 || cfg.SigNetGenesisNonce != 0) &&
+		(!cfg.SigNet || cfg.SigNetChallenge == "" ||
+			cfg.SigNetGenesisTime == 0) {
+
+		str := "%s: the signetgenesistime and signetgenesisnonce " +
+			"options may only be used together with the " +
+			"signetchallenge option of a signet network"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
 	if cfg.SigNet {
 		numNets++
 		activeNetParams = &sigNetParams
@@ -596,6 +855,30 @@ func loadConfig() (*config, []string, error) {
 		chainParams := chaincfg.CustomSignetParams(
 			sigNetChallenge, sigNetSeeds,
 		)
+
+		// Private signets use their own genesis block, which is
+		// identified by its timestamp and nonce since the rest of it
+		// is derived from the challenge.
+		if cfg.SigNetGenesisTime != 0 {
+			genesis := chaincfg.SignetGenesisBlock(sigNetChallenge,
+				time.Unix(cfg.SigNetGenesisTime, 0),
+				cfg.SigNetGenesisNonce)
+			powHash := genesis.Header.PowHash()
+			target := blockchain.CompactToBig(genesis.Header.Bits)
+			if blockchain.HashToBig(&powHash).Cmp(target) > 0 {
+				str := "%s: The signet genesis block with time %d " +
+					"and nonce %d does not satisfy the proof " +
+					"of work limit -- use gensignet to mine it"
+				err := fmt.Errorf(str, funcName,
+					cfg.SigNetGenesisTime, cfg.SigNetGenesisNonce)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
+			}
+			chainParams = chaincfg.CustomSignetParamsWithGenesis(
+				sigNetChallenge, sigNetSeeds, genesis,
+			)
+		}
 		activeNetParams.Params = &chainParams
 	}
 	if numNets > 1 {
@@ -658,9 +941,30 @@ func loadConfig() (*config, []string, error) {
 		os.Exit(0)
 	}
 
+	// Validate the log format and rotation options.
+	if !validLogFormat(cfg.LogFormat) {
+		str := "%s: the specified log format [%v] is invalid -- " +
+			"supported formats are %s and %s"
+		err := fmt.Errorf(str, funcName, cfg.LogFormat, logFormatText,
+			logFormatJSON)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.LogMaxSize < 1 || cfg.LogMaxRolls < 1 {
+		str := "%s: the --logmaxsize and --logmaxrolls options must be " +
+			"at least 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	setLogFormat(cfg.LogFormat)
+
 	// Initialize log rotation.  After log rotation has been initialized, the
 	// logger variables may be used.
-	initLogRotator(filepath.Join(cfg.LogDir, defaultLogFilename))
+	initLogRotator(filepath.Join(cfg.LogDir, defaultLogFilename),
+		cfg.LogMaxSize, cfg.LogMaxRolls)
 
 	// Parse, validate, and set debug log level(s).
 	if err := parseAndSetDebugLevels(cfg.DebugLevel); err != nil {
@@ -701,38 +1005,101 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// The Dandelion++ epoch and embargo must be at least a second.
+	if cfg.DandelionEpoch < time.Second {
+		str := "%s: The dandelionepoch option may not be less than 1s -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.DandelionEpoch)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.DandelionEmbargo < time.Second {
+		str := "%s: The dandelionembargo option may not be less than 1s -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.DandelionEmbargo)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Don't allow negative shutdown timeouts.
+	if cfg.ShutdownTimeout < 0 {
+		str := "%s: The shutdowntimeout option may not be negative -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.ShutdownTimeout)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Validate any given whitelisted IP addresses and networks.
 	if len(cfg.Whitelists) > 0 {
-		var ip net.IP
-		cfg.whitelists = make([]*net.IPNet, 0, len(cfg.Whitelists))
+		cfg.whitelists = make([]whitelist, 0, len(cfg.Whitelists))
 
-		for _, addr := range cfg.Whitelists {
-			_, ipnet, err := net.ParseCIDR(addr)
+		for _, entry := range cfg.Whitelists {
+			wl, err := parseWhitelist(entry)
 			if err != nil {
-				ip = net.ParseIP(addr)
-				if ip == nil {
-					str := "%s: The whitelist value of '%s' is invalid"
-					err = fmt.Errorf(str, funcName, addr)
-					fmt.Fprintln(os.Stderr, err)
-					fmt.Fprintln(os.Stderr, usageMessage)
-					return nil, nil, err
-				}
-				var bits int
-				if ip.To4() == nil {
-					// IPv6
-					bits = 128
-				} else {
-					bits = 32
-				}
-				ipnet = &net.IPNet{
-					IP:   ip,
-					Mask: net.CIDRMask(bits, bits),
-				}
+				str := "%s: The whitelist value of '%s' is " +
+					"invalid: %v"
+				err = fmt.Errorf(str, funcName, entry, err)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
 			}
-			cfg.whitelists = append(cfg.whitelists, ipnet)
+			cfg.whitelists = append(cfg.whitelists, wl)
 		}
 	}
 
+	// Default the peer requirements to those of the active network and
+	// ensure the minimum protocol version leaves peers to connect to.
+	cfg.minProtocolVersion = activeNetParams.MinPeerProtocolVersion
+	if cfg.MinProtocolVersion != 0 {
+		cfg.minProtocolVersion = cfg.MinProtocolVersion
+	}
+	if cfg.minProtocolVersion > peer.MaxProtocolVersion {
+		str := "%s: The minprotocolversion option must not be more " +
+			"than %d -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, peer.MaxProtocolVersion,
+			cfg.minProtocolVersion)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	serviceFlagOptions := []struct {
+		name     string
+		value    string
+		defValue wire.ServiceFlag
+		services *wire.ServiceFlag
+	}{
+		{"requiredservices", cfg.RequiredServices,
+			activeNetParams.RequiredServices, &cfg.requiredServices},
+		{"preferredservices", cfg.PreferredServices,
+			activeNetParams.PreferredServices, &cfg.preferredServices},
+	}
+	for _, opt := range serviceFlagOptions {
+		*opt.services = opt.defValue
+		if opt.value == "" {
+			continue
+		}
+		services, err := wire.ParseServiceFlags(opt.value)
+		if err != nil {
+			str := "%s: The %s option is invalid: %v"
+			err := fmt.Errorf(str, funcName, opt.name, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		*opt.services = services
+	}
+
+	// --peerbloomfilters and --nopeerbloomfilters do not mix.
+	if cfg.PeerBloomFilters && cfg.NoPeerBloomFilters {
+		str := "%s: the --peerbloomfilters and --nopeerbloomfilters " +
+			"options can not be mixed"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// --addPeer and --connect do not mix.
 	if len(cfg.AddPeers) > 0 && len(cfg.ConnectPeers) > 0 {
 		str := "%s: the --addpeer and --connect options can not be " +
@@ -783,12 +1150,27 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
-	// The RPC server is disabled if no username or password is provided.
+	// The RPC server is disabled if no username or password is provided,
+	// unless the clients are authenticated with certificates.
 	if (cfg.RPCUser == "" || cfg.RPCPass == "") &&
-		(cfg.RPCLimitUser == "" || cfg.RPCLimitPass == "") {
+		(cfg.RPCLimitUser == "" || cfg.RPCLimitPass == "") &&
+		cfg.RPCClientCA == "" {
 		cfg.DisableRPC = true
 	}
 
+	// Client certificates can't be verified without TLS.
+	if cfg.RPCClientCA != "" {
+		if cfg.DisableTLS {
+			str := "%s: the --rpcclientca and --notls options may " +
+				"not be used together"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.RPCClientCA = cleanAndExpandPath(cfg.RPCClientCA)
+	}
+
 	if cfg.DisableRPC {
 		ltcdLog.Infof("RPC service is disabled")
 	}
@@ -815,6 +1197,26 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	if cfg.RPCMaxGlobalReqs < 0 {
+		str := "%s: The rpcmaxglobalreqs option may not be less than " +
+			"0 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.RPCMaxGlobalReqs)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	if cfg.RPCRateLimit < 0 || cfg.RPCRateBurst < 1 {
+		str := "%s: The rpcratelimit option may not be less than 0 " +
+			"and the rpcrateburst option may not be less than 1 -- " +
+			"parsed [%v] and [%d]"
+		err := fmt.Errorf(str, funcName, cfg.RPCRateLimit,
+			cfg.RPCRateBurst)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Validate the minrelaytxfee.
 	cfg.minRelayTxFee, err = ltcutil.NewAmount(cfg.MinRelayTxFee)
 	if err != nil {
@@ -825,7 +1227,12 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
-	// Limit the max block size to a sane value.
+	// Limit the max block size and weight to sane values, which leave room
+	// for the coinbase below the limits of the network.
+	blockMaxSizeMax := uint32(blockchain.BlockBaseSizeLimit(
+		activeNetParams.Params) - 1000)
+	blockMaxWeightMax := uint32(blockchain.BlockWeightLimit(
+		activeNetParams.Params) - 4000)
 	if cfg.BlockMaxSize < blockMaxSizeMin || cfg.BlockMaxSize >
 		blockMaxSizeMax {
 
@@ -838,7 +1245,6 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
-	// Limit the max block weight to a sane value.
 	if cfg.BlockMaxWeight < blockMaxWeightMin ||
 		cfg.BlockMaxWeight > blockMaxWeightMax {
 
@@ -851,6 +1257,16 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	if cfg.CoinbaseReservedSize > mining.MaxCoinbaseReservedSize {
+		str := "%s: The coinbasereservedsize option may not be more " +
+			"than %d -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, mining.MaxCoinbaseReservedSize,
+			cfg.CoinbaseReservedSize)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Limit the max orphan count to a sane vlue.
 	if cfg.MaxOrphanTxs < 0 {
 		str := "%s: The maxorphantx option may not be less than 0 " +
@@ -873,7 +1289,7 @@ func loadConfig() (*config, []string, error) {
 	case cfg.BlockMaxSize == defaultBlockMaxSize &&
 		cfg.BlockMaxWeight != defaultBlockMaxWeight:
 
-		cfg.BlockMaxSize = blockchain.MaxBlockBaseSize - 1000
+		cfg.BlockMaxSize = blockMaxSizeMax
 
 	// If the max block weight isn't set, but the block size is, then we'll
 	// scale the set weight accordingly based on the max block size value.
@@ -927,6 +1343,38 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// --watchonly and --dropwatchonly do not mix.
+	if cfg.WatchOnly && cfg.DropWatchOnly {
+		err := fmt.Errorf("%s: the --watchonly and --dropwatchonly "+
+			"options may not be activated at the same time",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// --export-height is only meaningful with --export-blocks.
+	if cfg.ExportHeight != 0 && cfg.ExportBlocks == "" {
+		err := fmt.Errorf("%s: the --export-height option requires "+
+			"the --export-blocks option", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.ExportHeight < 0 {
+		err := fmt.Errorf("%s: the --export-height option may not be "+
+			"negative", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.ExportBlocks != "" {
+		cfg.ExportBlocks = cleanAndExpandPath(cfg.ExportBlocks)
+	}
+	for i, path := range cfg.LoadBlocks {
+		cfg.LoadBlocks[i] = cleanAndExpandPath(path)
+	}
+
 	// Check mining addresses are valid and saved parsed versions.
 	cfg.miningAddrs = make([]ltcutil.Address, 0, len(cfg.MiningAddrs))
 	for _, strAddr := range cfg.MiningAddrs {
@@ -948,6 +1396,35 @@ func loadConfig() (*config, []string, error) {
 		cfg.miningAddrs = append(cfg.miningAddrs, addr)
 	}
 
+	// Check the signet mining keys are valid and save parsed versions.
+	if len(cfg.SigNetMiningKeys) > 0 && !cfg.SigNet {
+		str := "%s: the signetminingkey option may only be used " +
+			"with the signet network"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	cfg.signetMiningKeys = make([]*ltcutil.WIF, 0, len(cfg.SigNetMiningKeys))
+	for _, strKey := range cfg.SigNetMiningKeys {
+		wif, err := ltcutil.DecodeWIF(strKey)
+		if err != nil {
+			str := "%s: signet mining key failed to decode: %v"
+			err := fmt.Errorf(str, funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		if !wif.IsForNet(activeNetParams.Params) {
+			str := "%s: signet mining key is on the wrong network"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.signetMiningKeys = append(cfg.signetMiningKeys, wif)
+	}
+
 	// Ensure there is at least one mining address when the generate flag is
 	// set.
 	if cfg.Generate && len(cfg.MiningAddrs) == 0 {
@@ -959,10 +1436,26 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
-	// Add default port to all listener addresses if needed and remove
-	// duplicate addresses.
-	cfg.Listeners = normalizeAddresses(cfg.Listeners,
-		activeNetParams.DefaultPort)
+	// Split the services advertised to the peers of each listener from its
+	// address, then add default port to all listener addresses if needed
+	// and remove duplicate addresses.
+	cfg.listenerServices = make(map[string]wire.ServiceFlag)
+	for i, listener := range cfg.Listeners {
+		addr, services, hasServices, err := parseListener(listener)
+		if err != nil {
+			str := "%s: The listen address '%s' is invalid: %v"
+			err := fmt.Errorf(str, funcName, listener, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		addr = normalizeAddress(addr, activeNetParams.DefaultPort)
+		if hasServices {
+			cfg.listenerServices[addr] = services
+		}
+		cfg.Listeners[i] = addr
+	}
+	cfg.Listeners = removeDuplicateAddresses(cfg.Listeners)
 
 	// Add default port to all rpc listener addresses if needed and remove
 	// duplicate addresses.
@@ -1015,6 +1508,36 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Validate the networks outbound connections are restricted to.  I2P
+	// is not supported since ltcd cannot relay or dial I2P addresses.
+	for i, network := range cfg.OnlyNets {
+		network = strings.ToLower(network)
+		switch network {
+		case connmgr.NetIPv4, connmgr.NetIPv6, connmgr.NetOnion:
+		case "i2p":
+			str := "%s: the i2p network is not supported by --onlynet"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		default:
+			str := "%s: the --onlynet network '%s' is invalid"
+			err := fmt.Errorf(str, funcName, network)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		if network == connmgr.NetOnion && cfg.NoOnion {
+			err := fmt.Errorf("%s: the --noonion and --onlynet=onion "+
+				"options may not be activated at the same time",
+				funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.OnlyNets[i] = network
+	}
+
 	// Check the checkpoints for syntax errors.
 	cfg.addCheckpoints, err = parseCheckpoints(cfg.AddCheckpoints)
 	if err != nil {
@@ -1139,6 +1662,55 @@ func loadConfig() (*config, []string, error) {
 		}
 	}
 
+	// Setup the dial functions of the networks routed through their own
+	// proxy, or dialed directly, instead of the dial function selected
+	// above.  They use the credentials of --proxy, which are randomized
+	// for each connection with Tor stream isolation like the main proxy.
+	// Onion addresses are routed with --onion instead.
+	cfg.netdial = make(map[string]func(string, string, time.Duration) (net.Conn, error))
+	for _, netProxy := range cfg.NetProxies {
+		parts := strings.SplitN(netProxy, "=", 2)
+		network := strings.ToLower(parts[0])
+		if len(parts) != 2 || (network != connmgr.NetIPv4 &&
+			network != connmgr.NetIPv6) {
+
+			str := "%s: the --netproxy option '%s' is invalid -- " +
+				"expected ipv4=<proxy> or ipv6=<proxy>"
+			err := fmt.Errorf(str, funcName, netProxy)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		if _, ok := cfg.netdial[network]; ok {
+			str := "%s: the --netproxy option is specified more " +
+				"than once for the %s network"
+			err := fmt.Errorf(str, funcName, network)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+
+		if parts[1] == "direct" {
+			cfg.netdial[network] = net.DialTimeout
+			continue
+		}
+		if _, _, err := net.SplitHostPort(parts[1]); err != nil {
+			str := "%s: Proxy address '%s' of the %s network is " +
+				"invalid: %v"
+			err := fmt.Errorf(str, funcName, parts[1], network, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		proxy := &socks.Proxy{
+			Addr:         parts[1],
+			Username:     cfg.ProxyUser,
+			Password:     cfg.ProxyPass,
+			TorIsolation: cfg.TorIsolation,
+		}
+		cfg.netdial[network] = proxy.DialTimeout
+	}
+
 	if cfg.Prune != 0 && cfg.Prune < pruneMinSize {
 		err := fmt.Errorf("%s: the minimum value for --prune is %d. Got %d",
 			funcName, pruneMinSize, cfg.Prune)
@@ -1163,6 +1735,25 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	if cfg.Prune != 0 && cfg.WatchOnly {
+		err := fmt.Errorf("%s: the --prune and --watchonly options may "+
+			"not be activated at the same time", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	if cfg.PruneStaleForks < 0 || (cfg.PruneStaleForks != 0 &&
+		cfg.PruneStaleForks < pruneStaleForksMinDepth) {
+
+		err := fmt.Errorf("%s: the minimum value for --prunestaleforks "+
+			"is %d. Got %d", funcName, pruneStaleForksMinDepth,
+			cfg.PruneStaleForks)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Warn about missing config file only after all other configuration is
 	// done.  This prevents the warning on help messages and invalid
 	// options.  Note this should go directly before the return.
@@ -1243,9 +1834,13 @@ func createDefaultConfigFile(destinationPath string) error {
 // ltcdDial connects to the address on the named network using the appropriate
 // dial function depending on the address and configuration options.  For
 // example, .onion addresses will be dialed using the onion specific proxy if
-// one was specified, but will otherwise use the normal dial function (which
+// one was specified, and IPv6 addresses using the proxy of --netproxy=ipv6
+// if one was specified, but will otherwise use the normal dial function (which
 // could itself use a proxy or not).
 func ltcdDial(addr net.Addr) (net.Conn, error) {
+	if dial, ok := cfg.netdial[connmgr.AddrNetwork(addr)]; ok {
+		return dial(addr.Network(), addr.String(), defaultConnectTimeout)
+	}
 	if strings.Contains(addr.String(), ".onion:") {
 		return cfg.oniondial(addr.Network(), addr.String(),
 			defaultConnectTimeout)
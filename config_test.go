@@ -1,12 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/ltcsuite/ltcd/peer"
+	"github.com/ltcsuite/ltcd/wire"
 )
 
 var (
@@ -70,3 +78,173 @@ func TestCreateDefaultConfigFile(t *testing.T) {
 		t.Error("Could not find rpcpass in generated default config file.")
 	}
 }
+
+// TestParseWhitelist ensures whitelist entries with and without permissions
+// are parsed properly.
+func TestParseWhitelist(t *testing.T) {
+	tests := []struct {
+		entry       string
+		network     string
+		permissions peer.PermissionFlags
+		isErr       bool
+	}{
+		{
+			entry:       "127.0.0.1",
+			network:     "127.0.0.1/32",
+			permissions: peer.PermissionNoBan,
+		},
+		{
+			entry:       "fd00::/16",
+			network:     "fd00::/16",
+			permissions: peer.PermissionNoBan,
+		},
+		{
+			entry:   "forcerelay,mempool@192.168.0.0/24",
+			network: "192.168.0.0/24",
+			permissions: peer.PermissionForceRelay |
+				peer.PermissionMempool,
+		},
+		{
+			entry:       "all@::1",
+			network:     "::1/128",
+			permissions: peer.PermissionAll,
+		},
+		{entry: "bogus@127.0.0.1", isErr: true},
+		{entry: "noban@", isErr: true},
+		{entry: "300.0.0.1", isErr: true},
+	}
+
+	for _, test := range tests {
+		wl, err := parseWhitelist(test.entry)
+		if test.isErr {
+			if err == nil {
+				t.Errorf("%q: expected error", test.entry)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.entry, err)
+			continue
+		}
+		if wl.ipnet.String() != test.network {
+			t.Errorf("%q: got network %v, want %v", test.entry,
+				wl.ipnet, test.network)
+		}
+		if wl.permissions != test.permissions {
+			t.Errorf("%q: got permissions %v, want %v", test.entry,
+				wl.permissions, test.permissions)
+		}
+	}
+}
+
+// TestParseListener ensures listener entries with and without services are
+// parsed properly.
+func TestParseListener(t *testing.T) {
+	tests := []struct {
+		entry       string
+		addr        string
+		services    wire.ServiceFlag
+		hasServices bool
+		isErr       bool
+	}{
+		{entry: "127.0.0.1:9333", addr: "127.0.0.1:9333"},
+		{entry: "[::1]:9333", addr: "[::1]:9333"},
+		{
+			entry:       "witness,networklimited@127.0.0.1:9335",
+			addr:        "127.0.0.1:9335",
+			services:    wire.SFNodeWitness | wire.SFNodeNetworkLimited,
+			hasServices: true,
+		},
+		{entry: "none@:9336", addr: ":9336", hasServices: true},
+		{entry: "bogus@127.0.0.1", isErr: true},
+		{entry: "@127.0.0.1", isErr: true},
+	}
+
+	for _, test := range tests {
+		addr, services, hasServices, err := parseListener(test.entry)
+		if test.isErr {
+			if err == nil {
+				t.Errorf("%q: expected error", test.entry)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.entry, err)
+			continue
+		}
+		if addr != test.addr || services != test.services ||
+			hasServices != test.hasServices {
+
+			t.Errorf("%q: got %q, %v, %v, want %q, %v, %v",
+				test.entry, addr, services, hasServices,
+				test.addr, test.services, test.hasServices)
+		}
+	}
+}
+
+// TestEnvConfig ensures environment variables take precedence over the config
+// file and are overridden by command line options, and that the merged
+// configuration is written with the passwords masked.
+func TestEnvConfig(t *testing.T) {
+	cfg := config{MaxPeers: defaultMaxPeers}
+	parser := newConfigParser(&cfg, &serviceOptions{}, flags.Default)
+
+	ini := "maxpeers=20\ntxindex=1\nrpcuser=fileuser\naddpeer=1.2.3.4\n" +
+		"connect=1.2.3.4\nlisten=:9333\nlisten=:9334\n"
+	err := flags.NewIniParser(parser).Parse(strings.NewReader(ini))
+	if err != nil {
+		t.Fatalf("Parse: unexpected error: %v", err)
+	}
+
+	t.Setenv("LTCD_MAXPEERS", "30")
+	t.Setenv("LTCD_BANDURATION", "1h")
+	t.Setenv("LTCD_RPCPASS", "secret")
+	t.Setenv("LTCD_ADDPEER", "5.6.7.8 9.9.9.9")
+	t.Setenv("LTCD_CONNECT", "")
+	t.Setenv("LTCD_LISTEN", ":9335")
+	if err := parseEnvConfig(parser, &cfg); err != nil {
+		t.Fatalf("parseEnvConfig: unexpected error: %v", err)
+	}
+	if _, err := parser.ParseArgs([]string{"--maxpeers=40"}); err != nil {
+		t.Fatalf("ParseArgs: unexpected error: %v", err)
+	}
+
+	if cfg.MaxPeers != 40 {
+		t.Errorf("maxpeers: got %d, want the command line value 40",
+			cfg.MaxPeers)
+	}
+	if cfg.BanDuration != time.Hour || cfg.RPCPass != "secret" {
+		t.Errorf("options were not set from the environment")
+	}
+	if !cfg.TxIndex || cfg.RPCUser != "fileuser" {
+		t.Errorf("options were not set from the config file")
+	}
+	if !reflect.DeepEqual(cfg.AddPeers, []string{"5.6.7.8", "9.9.9.9"}) {
+		t.Errorf("addpeer: got %v, want the environment values",
+			cfg.AddPeers)
+	}
+	if len(cfg.ConnectPeers) != 0 {
+		t.Errorf("connect: got %v, want the values of the config file "+
+			"cleared by the empty environment variable",
+			cfg.ConnectPeers)
+	}
+	if !reflect.DeepEqual(cfg.Listeners, []string{":9335"}) {
+		t.Errorf("listen: got %v, want the environment value",
+			cfg.Listeners)
+	}
+
+	var buf bytes.Buffer
+	writeConfig(&buf, parser)
+	want := "addpeer=5.6.7.8\naddpeer=9.9.9.9\nbanduration=1h0m0s\n" +
+		"listen=:9335\nmaxpeers=40\nrpcpass=********\nrpcuser=fileuser\n" +
+		"txindex=true\n"
+	if buf.String() != want {
+		t.Errorf("writeConfig: got %q, want %q", buf.String(), want)
+	}
+
+	// Invalid values are rejected.
+	t.Setenv("LTCD_MAXPEERS", "many")
+	if err := parseEnvConfig(parser, &cfg); err == nil {
+		t.Errorf("parseEnvConfig: accepted an invalid value")
+	}
+}
@@ -22,6 +22,11 @@ var (
 	//ErrDialNil is used to indicate that Dial cannot be nil in the configuration.
 	ErrDialNil = errors.New("Config: Dial cannot be nil")
 
+	// ErrNetworkNotAllowed is used to indicate that a connection was not
+	// attempted since the network of its address is not in the OnlyNets of
+	// the configuration.
+	ErrNetworkNotAllowed = errors.New("network of address not allowed")
+
 	// maxRetryDuration is the max duration of time retrying of a persistent
 	// connection is allowed to grow to.  This is necessary since the retry
 	// logic uses a backoff mechanism which increases the interval base times
@@ -142,6 +147,13 @@ type Config struct {
 
 	// Dial connects to the address on the named network. It cannot be nil.
 	Dial func(net.Addr) (net.Conn, error)
+
+	// OnlyNets restricts outbound connections, including permanent ones,
+	// to addresses of the listed networks as named by AddrNetwork.
+	// Connections to addresses of other networks are never dialed and
+	// permanent requests for them are not retried.  Connections may be
+	// made to any network when it is empty.
+	OnlyNets []string
 }
 
 // registerPending is used to register a pending connection attempt. By
@@ -174,9 +186,10 @@ type handleFailed struct {
 // ConnManager provides a manager to handle network connections.
 type ConnManager struct {
 	// The following variables must only be used atomically.
-	connReqCount uint64
-	start        int32
-	stop         int32
+	connReqCount   uint64
+	start          int32
+	stop           int32
+	targetOutbound uint32
 
 	cfg            Config
 	wg             sync.WaitGroup
@@ -327,7 +340,8 @@ out:
 				// re added to the pending map, so that
 				// subsequent processing of connections and
 				// failures do not ignore the request.
-				if uint32(len(conns)) < cm.cfg.TargetOutbound ||
+				target := atomic.LoadUint32(&cm.targetOutbound)
+				if uint32(len(conns)) < target ||
 					connReq.Permanent {
 
 					connReq.updateState(ConnPending)
@@ -349,6 +363,17 @@ out:
 				connReq.updateState(ConnFailing)
 				log.Debugf("Failed to connect to %v: %v",
 					connReq, msg.err)
+
+				// Retrying a permanent request to a network
+				// which is not allowed can never succeed, so
+				// it is canceled instead.
+				if msg.err == ErrNetworkNotAllowed &&
+					connReq.Permanent {
+
+					connReq.updateState(ConnCanceled)
+					delete(pending, connReq.id)
+					continue
+				}
 				cm.handleFailedConn(connReq)
 			}
 
@@ -444,6 +469,16 @@ func (cm *ConnManager) Connect(c *ConnReq) {
 		}
 	}
 
+	if !cm.NetworkAllowed(c.Addr) {
+		log.Warnf("Not connecting to %v: the %q network is not allowed",
+			c, AddrNetwork(c.Addr))
+		select {
+		case cm.requests <- handleFailed{c, ErrNetworkNotAllowed}:
+		case <-cm.quit:
+		}
+		return
+	}
+
 	log.Debugf("Attempting to connect to %v", c)
 
 	conn, err := cm.cfg.Dial(c.Addr)
@@ -475,6 +510,22 @@ func (cm *ConnManager) Disconnect(id uint64) {
 	}
 }
 
+// NetworkAllowed returns whether outbound connections may be made to the passed
+// address according to the OnlyNets of the configuration.
+func (cm *ConnManager) NetworkAllowed(addr net.Addr) bool {
+	if len(cm.cfg.OnlyNets) == 0 {
+		return true
+	}
+
+	network := AddrNetwork(addr)
+	for _, allowed := range cm.cfg.OnlyNets {
+		if network == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 // Remove removes the connection corresponding to the given connection id from
 // known connections.
 //
@@ -531,7 +582,25 @@ func (cm *ConnManager) Start() {
 		}
 	}
 
-	for i := atomic.LoadUint64(&cm.connReqCount); i < uint64(cm.cfg.TargetOutbound); i++ {
+	target := uint64(atomic.LoadUint32(&cm.targetOutbound))
+	for i := atomic.LoadUint64(&cm.connReqCount); i < target; i++ {
+		go cm.NewConnReq()
+	}
+}
+
+// SetTargetOutbound changes the number of outbound network connections to
+// maintain.  Lowering it does not drop any established connection, instead
+// disconnected ones are no longer replaced until the number of connections is
+// below the new target.  Raising it makes a new connection request for each
+// additional connection once the connection manager is started.
+//
+// This function is safe for concurrent access.
+func (cm *ConnManager) SetTargetOutbound(target uint32) {
+	prevTarget := atomic.SwapUint32(&cm.targetOutbound, target)
+	if atomic.LoadInt32(&cm.start) == 0 {
+		return
+	}
+	for i := prevTarget; i < target; i++ {
 		go cm.NewConnReq()
 	}
 }
@@ -574,9 +643,10 @@ func New(cfg *Config) (*ConnManager, error) {
 		cfg.TargetOutbound = defaultTargetOutbound
 	}
 	cm := ConnManager{
-		cfg:      *cfg, // Copy so caller can't mutate
-		requests: make(chan interface{}),
-		quit:     make(chan struct{}),
+		targetOutbound: cfg.TargetOutbound,
+		cfg:            *cfg, // Copy so caller can't mutate
+		requests:       make(chan interface{}),
+		quit:           make(chan struct{}),
 	}
 	return &cm, nil
 }
@@ -179,6 +179,67 @@ func TestConnectMode(t *testing.T) {
 	cmgr.Stop()
 }
 
+// TestOnlyNets tests that connections are only dialed to the allowed networks
+// and that permanent requests to other networks are not retried.
+func TestOnlyNets(t *testing.T) {
+	var requests uint32
+	dialed := make(chan net.Addr, 10)
+	connected := make(chan *ConnReq)
+	cmgr, err := New(&Config{
+		TargetOutbound: 1,
+		RetryDuration:  time.Millisecond,
+		OnlyNets:       []string{NetIPv4},
+		Dial: func(addr net.Addr) (net.Conn, error) {
+			dialed <- addr
+			return mockDialer(addr)
+		},
+		GetNewAddress: func() (net.Addr, error) {
+			// Offer an IPv6 address before the IPv4 one.
+			ip := net.ParseIP("2001:db8::1")
+			if atomic.AddUint32(&requests, 1) > 1 {
+				ip = net.ParseIP("127.0.0.1")
+			}
+			return &net.TCPAddr{IP: ip, Port: 18555}, nil
+		},
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	cmgr.Start()
+	defer cmgr.Stop()
+
+	c := <-connected
+	if network := AddrNetwork(c.Addr); network != NetIPv4 {
+		t.Fatalf("only nets: got connection to %v on %s network",
+			c.Addr, network)
+	}
+
+	onion := &ConnReq{
+		Addr:      &mockAddr{"tcp", "3g2upl4pq6kufc4m.onion:18555"},
+		Permanent: true,
+	}
+	cmgr.Connect(onion)
+	deadline := time.Now().Add(time.Second)
+	for onion.State() != ConnCanceled {
+		if time.Now().After(deadline) {
+			t.Fatalf("only nets: got state %v for permanent request "+
+				"to disallowed network", onion.State())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	close(dialed)
+	for addr := range dialed {
+		if network := AddrNetwork(addr); network != NetIPv4 {
+			t.Errorf("only nets: dialed %v on %s network", addr,
+				network)
+		}
+	}
+}
+
 // TestTargetOutbound tests the target number of outbound connections.
 //
 // We wait until all connections are established, then test they there are the
@@ -216,6 +277,64 @@ func TestTargetOutbound(t *testing.T) {
 	cmgr.Stop()
 }
 
+// TestSetTargetOutbound tests changing the target number of outbound
+// connections after the connection manager is started.
+//
+// We raise the target and wait for the additional connections, then lower it
+// and test that disconnected connections are not replaced.
+func TestSetTargetOutbound(t *testing.T) {
+	connected := make(chan *ConnReq)
+	disconnected := make(chan *ConnReq)
+	cmgr, err := New(&Config{
+		TargetOutbound: 2,
+		Dial:           mockDialer,
+		GetNewAddress: func() (net.Addr, error) {
+			return &net.TCPAddr{
+				IP:   net.ParseIP("127.0.0.1"),
+				Port: 18555,
+			}, nil
+		},
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+		OnDisconnection: func(c *ConnReq) {
+			disconnected <- c
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	cmgr.Start()
+	conns := make([]*ConnReq, 0, 4)
+	for i := 0; i < 2; i++ {
+		conns = append(conns, <-connected)
+	}
+
+	cmgr.SetTargetOutbound(4)
+	for i := 0; i < 2; i++ {
+		conns = append(conns, <-connected)
+	}
+	select {
+	case c := <-connected:
+		t.Fatalf("raised target: got unexpected connection - %v", c.Addr)
+	case <-time.After(time.Millisecond):
+		break
+	}
+
+	cmgr.SetTargetOutbound(1)
+	for _, c := range conns[:3] {
+		cmgr.Disconnect(c.ID())
+		<-disconnected
+	}
+	select {
+	case c := <-connected:
+		t.Fatalf("lowered target: got unexpected connection - %v", c.Addr)
+	case <-time.After(time.Millisecond):
+		break
+	}
+	cmgr.Stop()
+}
+
 // TestRetryPermanent tests that permanent connection requests are retried.
 //
 // We make a permanent connection request using Connect, disconnect it using
@@ -0,0 +1,45 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package connmgr
+
+import (
+	"net"
+	"strings"
+)
+
+// These constants are the names of the networks returned by AddrNetwork which
+// outbound connections may be restricted to with Config.OnlyNets.
+const (
+	// NetIPv4 is the name of the IPv4 network.
+	NetIPv4 = "ipv4"
+
+	// NetIPv6 is the name of the IPv6 network.
+	NetIPv6 = "ipv6"
+
+	// NetOnion is the name of the network of Tor hidden services.
+	NetOnion = "onion"
+)
+
+// AddrNetwork returns the name of the network of the passed address, which is
+// one of NetIPv4, NetIPv6 or NetOnion, or an empty string when the address is
+// a host name which has not been resolved.
+func AddrNetwork(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	if strings.HasSuffix(host, ".onion") {
+		return NetOnion
+	}
+
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return NetIPv4
+	}
+	return NetIPv6
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package connmgr
+
+import (
+	"net"
+	"testing"
+)
+
+// TestAddrNetwork ensures the networks of addresses are named as expected.
+func TestAddrNetwork(t *testing.T) {
+	tests := []struct {
+		addr net.Addr
+		want string
+	}{
+		{&net.TCPAddr{IP: net.ParseIP("203.0.113.5"), Port: 9333}, NetIPv4},
+		{&net.TCPAddr{IP: net.ParseIP("::ffff:203.0.113.5"), Port: 9333}, NetIPv4},
+		{&net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 9333}, NetIPv6},
+		{&mockAddr{"tcp", "3g2upl4pq6kufc4m.onion:9333"}, NetOnion},
+		{&mockAddr{"tcp", "seed.example.com:9333"}, ""},
+	}
+	for _, test := range tests {
+		if got := AddrNetwork(test.addr); got != test.want {
+			t.Errorf("AddrNetwork(%v): got %q, want %q", test.addr, got,
+				test.want)
+		}
+	}
+}
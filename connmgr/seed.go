@@ -34,11 +34,15 @@ func SeedFromDNS(chainParams *chaincfg.Params, reqServices wire.ServiceFlag,
 	lookupFn LookupFunc, seedFn OnSeed) {
 
 	for _, dnsseed := range chainParams.DNSSeeds {
+		// Seeds which support filtering only return peers advertising the
+		// requested services, so the addresses are known to offer them.
 		var host string
+		var services wire.ServiceFlag
 		if !dnsseed.HasFiltering || reqServices == wire.SFNodeNetwork {
 			host = dnsseed.Host
 		} else {
 			host = fmt.Sprintf("x%x.%s", uint64(reqServices), dnsseed.Host)
+			services = reqServices
 		}
 
 		go func(host string) {
@@ -66,7 +70,7 @@ func SeedFromDNS(chainParams *chaincfg.Params, reqServices wire.ServiceFlag,
 					// and 7 days ago.
 					time.Now().Add(-1*time.Second*time.Duration(secondsIn3Days+
 						randSource.Int31n(secondsIn4Days))),
-					0, peer, uint16(intPort))
+					services, peer, uint16(intPort))
 			}
 
 			seedFn(addresses)
@@ -0,0 +1,69 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package connmgr
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestSeedFromDNS ensures the required services are only requested from the
+// seeds which support filtering and that the addresses they return are known
+// to offer them.
+func TestSeedFromDNS(t *testing.T) {
+	params := chaincfg.MainNetParams
+	params.DNSSeeds = []chaincfg.DNSSeed{
+		{Host: "plain.example.com", HasFiltering: false},
+		{Host: "filter.example.com", HasFiltering: true},
+	}
+	reqServices := wire.SFNodeNetwork | wire.SFNodeWitness |
+		wire.ServiceFlag(wire.SFNodeMWEB)
+
+	// Each host resolves to a distinct address so the results can be told
+	// apart, along with the services they are expected to have.
+	lookups := map[string]net.IP{
+		"plain.example.com": net.ParseIP("1.2.3.4"),
+		fmt.Sprintf("x%x.filter.example.com", uint64(reqServices)): net.ParseIP("5.6.7.8"),
+	}
+	want := map[string]wire.ServiceFlag{
+		"1.2.3.4": 0,
+		"5.6.7.8": reqServices,
+	}
+
+	results := make(chan []*wire.NetAddressV2, len(params.DNSSeeds))
+	SeedFromDNS(&params, reqServices, func(host string) ([]net.IP, error) {
+		ip, ok := lookups[host]
+		if !ok {
+			return nil, fmt.Errorf("unexpected seed host %q", host)
+		}
+		return []net.IP{ip}, nil
+	}, func(addrs []*wire.NetAddressV2) {
+		results <- addrs
+	})
+
+	for range params.DNSSeeds {
+		select {
+		case addrs := <-results:
+			if len(addrs) != 1 {
+				t.Fatalf("got %d addresses, want 1", len(addrs))
+			}
+			services, ok := want[addrs[0].Addr.String()]
+			if !ok {
+				t.Fatalf("unexpected address %v", addrs[0].Addr)
+			}
+			if addrs[0].Services != services {
+				t.Fatalf("address %v: got services %v, want %v",
+					addrs[0].Addr, addrs[0].Services, services)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("seeding timed out")
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/mempool"
+)
+
+// dandelionTick is the interval at which the embargoes of the transactions in
+// the Dandelion++ stem are checked for expiry.
+const dandelionTick = time.Second
+
+// randomDuration returns a random duration between the passed duration and
+// twice the passed duration.
+func randomDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(d)))
+	if err != nil {
+		return d
+	}
+	return d + time.Duration(n.Int64())
+}
+
+// dandelionEmbargo is a transaction relayed along the Dandelion++ stem which is
+// not announced to the other peers until its embargo expires.
+type dandelionEmbargo struct {
+	txD      *mempool.TxDesc
+	stemPeer int32
+	expiry   time.Time
+}
+
+// dandelionRouter routes locally submitted transactions along a Dandelion++
+// stem so their origin is harder to infer.  Rather than announcing them to all
+// peers, they are only relayed to a single outbound peer which is chosen at
+// random for each epoch.  The transactions are broadcast to all peers, or
+// fluffed, once their embargo expires without them being announced by another
+// peer, which covers a stem peer that drops them.
+//
+// The epochs and embargoes last a random duration between their configured
+// duration and twice that so they can't be predicted by observers.
+type dandelionRouter struct {
+	epoch   time.Duration
+	embargo time.Duration
+
+	mtx       sync.Mutex
+	stemPeer  int32
+	epochEnd  time.Time
+	embargoes map[chainhash.Hash]*dandelionEmbargo
+}
+
+// newDandelionRouter returns a new Dandelion++ router which picks a new stem
+// peer after the passed epoch and fluffs transactions after the passed
+// embargo.
+func newDandelionRouter(epoch, embargo time.Duration) *dandelionRouter {
+	return &dandelionRouter{
+		epoch:     epoch,
+		embargo:   embargo,
+		embargoes: make(map[chainhash.Hash]*dandelionEmbargo),
+	}
+}
+
+// StemPeer returns the ID of the peer the transactions are relayed to along the
+// stem out of the passed IDs of the eligible peers.  The stem peer of the
+// current epoch is kept as long as it is eligible, while a new one is picked at
+// random otherwise.  False is returned when there are no eligible peers.
+//
+// This function is safe for concurrent access.
+func (r *dandelionRouter) StemPeer(now time.Time, candidates []int32) (int32, bool) {
+	if len(candidates) == 0 {
+		return 0, false
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if now.Before(r.epochEnd) {
+		for _, id := range candidates {
+			if id == r.stemPeer {
+				return id, true
+			}
+		}
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(candidates))))
+	if err != nil {
+		return 0, false
+	}
+	r.stemPeer = candidates[n.Int64()]
+	r.epochEnd = now.Add(randomDuration(r.epoch))
+	return r.stemPeer, true
+}
+
+// Stem records the passed transaction as relayed to the passed stem peer and
+// embargoes it from the other peers.
+//
+// This function is safe for concurrent access.
+func (r *dandelionRouter) Stem(now time.Time, txD *mempool.TxDesc, stemPeer int32) {
+	r.mtx.Lock()
+	r.embargoes[*txD.Tx.Hash()] = &dandelionEmbargo{
+		txD:      txD,
+		stemPeer: stemPeer,
+		expiry:   now.Add(randomDuration(r.embargo)),
+	}
+	r.mtx.Unlock()
+}
+
+// IsEmbargoed returns whether the transaction with the passed hash is in the
+// stem and must not be revealed to the peer with the passed ID.
+//
+// This function is safe for concurrent access.
+func (r *dandelionRouter) IsEmbargoed(hash *chainhash.Hash, peerID int32) bool {
+	r.mtx.Lock()
+	e, ok := r.embargoes[*hash]
+	r.mtx.Unlock()
+	return ok && e.stemPeer != peerID
+}
+
+// InStem returns whether the transaction with the passed hash is in the stem,
+// which means it must not be revealed to any peer but its stem peer.
+//
+// This function is safe for concurrent access.
+func (r *dandelionRouter) InStem(hash *chainhash.Hash) bool {
+	r.mtx.Lock()
+	_, ok := r.embargoes[*hash]
+	r.mtx.Unlock()
+	return ok
+}
+
+// Fluffed lifts the embargo of the transaction with the passed hash, which is
+// invoked once it was announced by a peer since it is no longer secret.
+//
+// This function is safe for concurrent access.
+func (r *dandelionRouter) Fluffed(hash *chainhash.Hash) {
+	r.mtx.Lock()
+	delete(r.embargoes, *hash)
+	r.mtx.Unlock()
+}
+
+// Expired lifts the embargoes which expired by the passed time and returns the
+// transactions they applied to so they can be fluffed.
+//
+// This function is safe for concurrent access.
+func (r *dandelionRouter) Expired(now time.Time) []*mempool.TxDesc {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	var expired []*mempool.TxDesc
+	for hash, e := range r.embargoes {
+		if !now.Before(e.expiry) {
+			expired = append(expired, e.txD)
+			delete(r.embargoes, hash)
+		}
+	}
+	return expired
+}
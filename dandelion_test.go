@@ -0,0 +1,122 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/mempool"
+	"github.com/ltcsuite/ltcd/mining"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestDandelionRouter ensures the stem peer is kept for the duration of an
+// epoch while it is eligible and that transactions are embargoed from the
+// other peers until they are fluffed or their embargo expires.
+func TestDandelionRouter(t *testing.T) {
+	epoch := 10 * time.Minute
+	embargo := 30 * time.Second
+	r := newDandelionRouter(epoch, embargo)
+	now := time.Unix(1700000000, 0)
+
+	if _, ok := r.StemPeer(now, nil); ok {
+		t.Fatalf("StemPeer: chose a stem peer without candidates")
+	}
+
+	// The stem peer is kept within the epoch as long as it is eligible.
+	stemPeer, ok := r.StemPeer(now, []int32{1, 2, 3})
+	if !ok {
+		t.Fatalf("StemPeer: no stem peer was chosen")
+	}
+	got, _ := r.StemPeer(now.Add(epoch-time.Second), []int32{3, 2, 1})
+	if got != stemPeer {
+		t.Fatalf("StemPeer: stem peer changed from %d to %d within the "+
+			"epoch", stemPeer, got)
+	}
+	got, _ = r.StemPeer(now, []int32{stemPeer + 10})
+	if got != stemPeer+10 {
+		t.Fatalf("StemPeer: got stem peer %d, want the only eligible "+
+			"peer %d", got, stemPeer+10)
+	}
+
+	// Transactions are only revealed to their stem peer until they are
+	// fluffed.
+	newTxDesc := func(lockTime uint32) *mempool.TxDesc {
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.LockTime = lockTime
+		return &mempool.TxDesc{TxDesc: mining.TxDesc{
+			Tx: ltcutil.NewTx(msgTx),
+		}}
+	}
+	fluffed := newTxDesc(1)
+	expiring := newTxDesc(2)
+	r.Stem(now, fluffed, 1)
+	r.Stem(now, expiring, 1)
+	if r.IsEmbargoed(fluffed.Tx.Hash(), 1) {
+		t.Fatalf("IsEmbargoed: transaction embargoed from its stem peer")
+	}
+	if !r.IsEmbargoed(fluffed.Tx.Hash(), 2) {
+		t.Fatalf("IsEmbargoed: transaction not embargoed from other peers")
+	}
+	r.Fluffed(fluffed.Tx.Hash())
+	if r.IsEmbargoed(fluffed.Tx.Hash(), 2) {
+		t.Fatalf("IsEmbargoed: fluffed transaction is still embargoed")
+	}
+
+	// The embargo expires after at least its duration and at most twice
+	// its duration.
+	if expired := r.Expired(now.Add(embargo - time.Second)); len(expired) != 0 {
+		t.Fatalf("Expired: embargo expired early")
+	}
+	expired := r.Expired(now.Add(2 * embargo))
+	if len(expired) != 1 || expired[0] != expiring {
+		t.Fatalf("Expired: got %d expired transactions, want 1",
+			len(expired))
+	}
+	if r.IsEmbargoed(expiring.Tx.Hash(), 2) {
+		t.Fatalf("IsEmbargoed: expired transaction is still embargoed")
+	}
+}
+
+// TestRebroadcastInventoryStem ensures transactions in the Dandelion++ stem are
+// only rebroadcast once they were fluffed.
+func TestRebroadcastInventoryStem(t *testing.T) {
+	s := &server{
+		relayInv:  make(chan relayMsg, 2),
+		dandelion: newDandelionRouter(time.Minute, time.Minute),
+	}
+	newTxDesc := func(lockTime uint32) *mempool.TxDesc {
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.LockTime = lockTime
+		return &mempool.TxDesc{TxDesc: mining.TxDesc{
+			Tx: ltcutil.NewTx(msgTx),
+		}}
+	}
+	stemmed := newTxDesc(1)
+	fluffed := newTxDesc(2)
+	s.dandelion.Stem(time.Now(), stemmed, 1)
+	pendingInvs := map[wire.InvVect]interface{}{
+		*wire.NewInvVect(wire.InvTypeTx, stemmed.Tx.Hash()): stemmed,
+		*wire.NewInvVect(wire.InvTypeTx, fluffed.Tx.Hash()): fluffed,
+	}
+
+	s.rebroadcastInventory(pendingInvs)
+	if len(s.relayInv) != 1 {
+		t.Fatalf("rebroadcast %d transactions, want 1", len(s.relayInv))
+	}
+	if msg := <-s.relayInv; msg.data != fluffed {
+		t.Fatalf("rebroadcast transaction %v in the stem",
+			msg.invVect.Hash)
+	}
+
+	s.dandelion.Fluffed(stemmed.Tx.Hash())
+	s.rebroadcastInventory(pendingInvs)
+	if len(s.relayInv) != 2 {
+		t.Fatalf("rebroadcast %d transactions after fluffing, want 2",
+			len(s.relayInv))
+	}
+}
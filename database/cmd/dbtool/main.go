@@ -88,6 +88,13 @@ func realMain() error {
 	parser.AddCommand("fetchblockregion",
 		"Fetch the specified block region from the database", "",
 		&blockRegionCfg)
+	parser.AddCommand("migrate",
+		"Migrate the block database to another database backend",
+		"Copy all blocks and metadata of the block database into a "+
+			"new database using the backend specified by "+
+			"--dstdbtype.  The new database is created in the data "+
+			"directory unless --dstdatadir is specified.",
+		&migrateCfg)
 
 	// Parse command line and invoke the Execute function for the specified
 	// command.
@@ -0,0 +1,116 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+)
+
+// migrateCmd defines the configuration options for the migrate command.
+type migrateCmd struct {
+	DstDbType  string `long:"dstdbtype" description:"Database backend to migrate the block database to"`
+	DstDataDir string `long:"dstdatadir" description:"Data directory to create the migrated block database in (defaults to the data directory)"`
+}
+
+var (
+	// migrateCfg defines the configuration options for the command.
+	migrateCfg = migrateCmd{}
+)
+
+// Execute is the main entry point for the command.  It's invoked by the parser.
+func (cmd *migrateCmd) Execute(args []string) error {
+	// Setup the global config options and ensure they are valid.
+	if err := setupGlobalConfig(); err != nil {
+		return err
+	}
+
+	// NOTE: Enumerating the stored blocks relies on the internal block
+	// index of ffldb, so only ffldb databases may be migrated.
+	if cfg.DbType != "ffldb" {
+		return fmt.Errorf("migrating from database type %s is not "+
+			"supported", cfg.DbType)
+	}
+	if !validDbType(cmd.DstDbType) {
+		str := "The specified destination database type [%v] is " +
+			"invalid -- supported types %v"
+		return fmt.Errorf(str, cmd.DstDbType, knownDbTypes)
+	}
+	dstDataDir := cfg.DataDir
+	if cmd.DstDataDir != "" {
+		dstDataDir = filepath.Join(cmd.DstDataDir,
+			netName(activeNetParams))
+	}
+	dstPath := filepath.Join(dstDataDir,
+		blockDbNamePrefix+"_"+cmd.DstDbType)
+	if fileExists(dstPath) {
+		return fmt.Errorf("destination database %s already exists",
+			dstPath)
+	}
+
+	// Load the source block database.
+	src, err := loadBlockDB()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(dstDataDir, 0700); err != nil {
+		return err
+	}
+	log.Infof("Creating block database at '%s'", dstPath)
+	dst, err := database.Create(cmd.DstDbType, dstPath, activeNetParams.Net)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	// Gather the hashes of all stored blocks.
+	blockIdxName := []byte("ffldb-blockidx")
+	var hashes []chainhash.Hash
+	err = src.View(func(tx database.Tx) error {
+		blockIdxBucket := tx.Metadata().Bucket(blockIdxName)
+		if blockIdxBucket == nil {
+			return errors.New("block index does not exist")
+		}
+		return blockIdxBucket.ForEach(func(k, v []byte) error {
+			var hash chainhash.Hash
+			copy(hash[:], k)
+			hashes = append(hashes, hash)
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	startTime := time.Now()
+	log.Infof("Migrating %d blocks...", len(hashes))
+	numCopied, err := database.CopyBlocks(src, dst, hashes)
+	if err != nil {
+		return err
+	}
+	log.Infof("Migrated %d blocks in %v", numCopied, time.Since(startTime))
+
+	// Copy the metadata, which includes the chain state and indexes,
+	// without the keys used internally by ffldb.
+	startTime = time.Now()
+	log.Info("Migrating metadata...")
+	err = database.CopyMetadata(src, dst, func(key []byte) bool {
+		return strings.HasPrefix(string(key), "ffldb-")
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Migrated metadata in %v", time.Since(startTime))
+	return nil
+}
@@ -87,5 +87,18 @@ The CreateBucket and CreateBucketIfNotExists functions on the Bucket interface
 provide the ability to create an arbitrary number of nested buckets.  It is
 a good idea to avoid a lot of buckets with little data in them as it could lead
 to poor page utilization depending on the specific driver in use.
+
+# Migrating Between Drivers
+
+The CopyBlocks and CopyMetadata functions copy the blocks and metadata of one
+database into another in batches, which allows a database to be migrated to a
+different driver.  The migrate command of the dbtool utility uses them to copy
+an existing ffldb block database into a new database of any registered type.
+
+The ffldb driver is currently the only registered driver, so there is no other
+backend to migrate to yet.  The leveldb compaction settings ffldb uses for its
+metadata to limit write stalls are options applied whenever a database is
+opened rather than properties of the stored data, so existing databases use
+them without being migrated.
 */
 package database
@@ -432,9 +432,22 @@ func (s *blockStore) writeBlock(rawBlock []byte) (blockLocation, error) {
 		// with LRU tracking.  The close is done under the write lock
 		// for the file to prevent it from being closed out from under
 		// any readers currently reading from it.
+		//
+		// The file is synced before it is closed since only the current
+		// write file is synced when the metadata is flushed, so the
+		// metadata must never be flushed while the tail of a previous
+		// file is still only in the operating system cache.
 		wc.Lock()
 		wc.curFile.Lock()
 		if wc.curFile.file != nil {
+			if err := wc.curFile.file.Sync(); err != nil {
+				wc.curFile.Unlock()
+				wc.Unlock()
+				str := fmt.Sprintf("failed to sync file %d: %v",
+					wc.curFileNum, err)
+				return blockLocation{}, makeDbErr(
+					database.ErrDriverSpecific, str, err)
+			}
 			_ = wc.curFile.file.Close()
 			wc.curFile.file = nil
 		}
@@ -43,6 +43,25 @@ const (
 	// The serialized block index row format is:
 	//   <blocklocation><blockheader>
 	blockHdrOffset = blockLocSize
+
+	// metadataWriteBuffer is the size of the memtable of the metadata
+	// database.  It is raised well above the leveldb default of 4MiB so
+	// fewer, larger level 0 tables are flushed while syncing, which keeps
+	// compaction from falling behind the writes.
+	metadataWriteBuffer = 64 * opt.MiB
+
+	// metadataCompactionTableSize is the size of the tables produced by
+	// compaction of the metadata database.  The leveldb default of 2MiB
+	// results in a very large number of tables for the utxo set.
+	metadataCompactionTableSize = 8 * opt.MiB
+
+	// metadataL0SlowdownTrigger and metadataL0PauseTrigger are the number
+	// of level 0 tables of the metadata database at which writes are
+	// slowed down and paused respectively until compaction catches up.
+	// The leveldb defaults of 8 and 12 stall block connection for seconds
+	// at a time during the initial sync.
+	metadataL0SlowdownTrigger = 32
+	metadataL0PauseTrigger    = 64
 )
 
 var (
@@ -74,6 +93,17 @@ var (
 	// writeLocKeyName is the key used to store the current write file
 	// location.
 	writeLocKeyName = []byte("ffldb-writeloc")
+
+	// pruneJournalKeyName is the key used to store the block files which
+	// are to be deleted by a prune.  It is written along with the removal of
+	// the block locations in those files and removed once the files have
+	// been deleted, so an interrupted prune can be completed when the
+	// database is opened.
+	pruneJournalKeyName = []byte("ffldb-prunejournal")
+
+	// deletedSizesKeyName is the key used to store the number of bytes used
+	// by deleted blocks in each block file until the space is reclaimed.
+	deletedSizesKeyName = []byte("ffldb-deletedsizes")
 )
 
 // Common error strings.
@@ -949,6 +979,14 @@ type pendingBlock struct {
 	bytes []byte
 }
 
+// pendingMove houses a block that will be read from its current location and
+// written to the end of the block storage when the database transaction is
+// committed.
+type pendingMove struct {
+	hash chainhash.Hash
+	loc  blockLocation
+}
+
 // transaction represents a database transaction.  It can either be read-only or
 // read-write and implements the database.Tx interface.  The transaction
 // provides a root bucket against which all read and writes occur.
@@ -970,6 +1008,13 @@ type transaction struct {
 	pendingKeys   *treap.Mutable
 	pendingRemove *treap.Mutable
 
+	// Block files that need to be deleted once the commit is persisted.
+	pendingPrunes []uint32
+
+	// Blocks that need to be moved to the end of the block storage on
+	// commit so the space of the files they are in can be reclaimed.
+	pendingMoves []pendingMove
+
 	// Active iterators that need to be notified when the pending keys have
 	// been updated so the cursors can properly handle updates to the
 	// transaction state.
@@ -1596,6 +1641,8 @@ func (tx *transaction) close() {
 	// Clear pending keys that would have been written or deleted on commit.
 	tx.pendingKeys = nil
 	tx.pendingRemove = nil
+	tx.pendingPrunes = nil
+	tx.pendingMoves = nil
 
 	// Release the snapshot.
 	if tx.snapshot != nil {
@@ -1657,6 +1704,30 @@ func (tx *transaction) writePendingAndCommit() error {
 		}
 	}
 
+	// Move the blocks in the block files whose space is being reclaimed to
+	// the end of the block storage one at a time and point their records
+	// in the block index to the new locations.  The files are only deleted
+	// once the new locations are persisted.
+	for _, move := range tx.pendingMoves {
+		log.Tracef("Moving block %s", move.hash)
+		rawBlock, err := tx.db.store.readBlock(&move.hash, move.loc)
+		if err != nil {
+			rollback()
+			return err
+		}
+		location, err := tx.db.store.writeBlock(rawBlock)
+		if err != nil {
+			rollback()
+			return err
+		}
+		blockRow := serializeBlockLoc(location)
+		err = tx.blockIdxBucket.Put(move.hash[:], blockRow)
+		if err != nil {
+			rollback()
+			return err
+		}
+	}
+
 	// Update the metadata for the current write file and offset.
 	writeRow := serializeWriteRow(wc.curFileNum, wc.curOffset)
 	if err := tx.metaBucket.Put(writeLocKeyName, writeRow); err != nil {
@@ -1666,7 +1737,16 @@ func (tx *transaction) writePendingAndCommit() error {
 
 	// Atomically update the database cache.  The cache automatically
 	// handles flushing to the underlying persistent storage database.
-	return tx.db.cache.commitTx(tx)
+	if err := tx.db.cache.commitTx(tx); err != nil {
+		return err
+	}
+
+	// Delete the block files pruned by the transaction now that the removal
+	// of their block locations has been committed.
+	if len(tx.pendingPrunes) > 0 {
+		tx.db.deletePrunedFiles(tx.pendingPrunes)
+	}
+	return nil
 }
 
 // PruneBlocks deletes the block files until it reaches the target size
@@ -1704,10 +1784,16 @@ func (tx *transaction) PruneBlocks(targetSize uint64) ([]chainhash.Hash, error)
 		return nil, nil
 	}
 
-	// Last file number minus the first file number gives us the count of files
-	// on disk minus 1.  We don't want to count the last file since we can't assume
-	// that it is of max size.
-	maxSizeFileCount := last - first
+	// Count the files on disk besides the last one.  We don't want to count
+	// the last file since we can't assume that it is of max size.  Files
+	// whose space was reclaimed leave gaps in the numbering, so they are
+	// not all between the first and the last file.
+	var maxSizeFileCount int
+	for i := uint32(first); i < uint32(last); i++ {
+		if fileExists(blockFilePath(tx.db.store.basePath, i)) {
+			maxSizeFileCount++
+		}
+	}
 
 	// If the total size of block files are under the target, return early and
 	// don't prune.
@@ -1721,19 +1807,24 @@ func (tx *transaction) PruneBlocks(targetSize uint64) ([]chainhash.Hash, error)
 		targetSize/(1024*1024))
 
 	deletedFiles := make(map[uint32]struct{})
+	var pendingPrunes []uint32
 
 	// We use < not <= so that the last file is never deleted.  There are other checks in place
 	// but setting it to < here doesn't hurt.
+	//
+	// The files are only deleted once the transaction is committed and the
+	// removal of their block locations has been persisted.  Otherwise an
+	// unclean shutdown, or a rollback, could leave the block index
+	// referencing blocks which no longer exist.
 	for i := uint32(first); i < uint32(last); i++ {
-		err = tx.db.store.deleteFileFunc(i)
-		if err != nil {
-			return nil, fmt.Errorf("PruneBlocks: Failed to delete block file "+
-				"number %d: %v", i, err)
+		if !fileExists(blockFilePath(tx.db.store.basePath, i)) {
+			continue
 		}
 
 		// Add the file index to the deleted files map so that we can later
 		// delete the block location index.
 		deletedFiles[i] = struct{}{}
+		pendingPrunes = append(pendingPrunes, i)
 
 		// If we're already at or below the target usage, break and don't
 		// try to delete more files.
@@ -1759,11 +1850,227 @@ func (tx *transaction) PruneBlocks(targetSize uint64) ([]chainhash.Hash, error)
 		}
 	}
 
+	// The deleted blocks in the pruned files no longer take up any space
+	// and the blocks in them can no longer be moved.
+	sizes, err := tx.fetchDeletedSizes()
+	if err != nil {
+		return nil, err
+	}
+	for fileNum := range deletedFiles {
+		delete(sizes, fileNum)
+	}
+	if err := tx.putDeletedSizes(sizes); err != nil {
+		return nil, err
+	}
+	pendingMoves := tx.pendingMoves[:0]
+	for _, move := range tx.pendingMoves {
+		if _, ok := deletedFiles[move.loc.blockFileNum]; !ok {
+			pendingMoves = append(pendingMoves, move)
+		}
+	}
+	tx.pendingMoves = pendingMoves
+
+	if err := tx.addPendingPrunes(pendingPrunes); err != nil {
+		return nil, err
+	}
+
 	log.Tracef("Finished pruning. Database now at %d bytes", totalSize)
 
 	return deletedBlockHashes, nil
 }
 
+// addPendingPrunes adds the passed block files to the files to delete once the
+// transaction is committed and journals all of them along with the changes of
+// the transaction, so the deletion can be completed when the database is
+// opened after an unclean shutdown.
+func (tx *transaction) addPendingPrunes(fileNums []uint32) error {
+	tx.pendingPrunes = append(tx.pendingPrunes, fileNums...)
+	err := tx.metaBucket.Put(pruneJournalKeyName,
+		serializePruneJournal(tx.pendingPrunes))
+	if err != nil {
+		return convertErr("failed to store prune journal", err)
+	}
+	return nil
+}
+
+// fetchDeletedSizes returns the number of bytes used by deleted blocks in each
+// block file as of the transaction.
+func (tx *transaction) fetchDeletedSizes() (map[uint32]uint32, error) {
+	serialized := tx.metaBucket.Get(deletedSizesKeyName)
+	if serialized == nil {
+		return make(map[uint32]uint32), nil
+	}
+	return deserializeDeletedSizes(serialized)
+}
+
+// putDeletedSizes stores the number of bytes used by deleted blocks in each
+// block file, or removes them when there are none.
+func (tx *transaction) putDeletedSizes(sizes map[uint32]uint32) error {
+	if len(sizes) == 0 {
+		return tx.metaBucket.Delete(deletedSizesKeyName)
+	}
+	err := tx.metaBucket.Put(deletedSizesKeyName,
+		serializeDeletedSizes(sizes))
+	if err != nil {
+		return convertErr("failed to store deleted block sizes", err)
+	}
+	return nil
+}
+
+// DeleteBlocks removes the blocks for the given hashes from the block storage.
+// Hashes of blocks which are not stored, including blocks stored by the same
+// transaction, are ignored.  The space used by the removed blocks is tracked
+// for each block file so it can later be reclaimed by ReclaimBlockSpace.
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) DeleteBlocks(hashes []chainhash.Hash) error {
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return err
+	}
+
+	// Ensure the transaction is writable.
+	if !tx.writable {
+		str := "delete blocks requires a writable database transaction"
+		return makeDbErr(database.ErrTxNotWritable, str, nil)
+	}
+
+	sizes, err := tx.fetchDeletedSizes()
+	if err != nil {
+		return err
+	}
+	deleted := make(map[chainhash.Hash]struct{}, len(hashes))
+	for i := range hashes {
+		blockRow := tx.blockIdxBucket.Get(hashes[i][:])
+		if blockRow == nil {
+			continue
+		}
+		if err := tx.blockIdxBucket.Delete(hashes[i][:]); err != nil {
+			return err
+		}
+		deleted[hashes[i]] = struct{}{}
+		loc := deserializeBlockLoc(blockRow)
+		sizes[loc.blockFileNum] += loc.blockLen
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	// Blocks which were to be moved out of a block file whose space is
+	// being reclaimed are deleted along with the file instead.
+	pendingMoves := tx.pendingMoves[:0]
+	for _, move := range tx.pendingMoves {
+		if _, ok := deleted[move.hash]; ok {
+			delete(sizes, move.loc.blockFileNum)
+			continue
+		}
+		pendingMoves = append(pendingMoves, move)
+	}
+	tx.pendingMoves = pendingMoves
+
+	return tx.putDeletedSizes(sizes)
+}
+
+// ReclaimBlockSpace releases the space used by blocks removed with DeleteBlocks
+// until at most the target size (specified in bytes) of it remains unreleased.
+// The block files with the most deleted data are reclaimed first.  The
+// remaining blocks in them are moved to the end of the block storage when the
+// transaction is committed, and the files are deleted once the new locations
+// are persisted the same way pruned files are.
+//
+// The current write file is never reclaimed since blocks are still appended to
+// it.  Neither is the first block file, since its absence is how BeenPruned
+// detects a pruned block storage.
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) ReclaimBlockSpace(targetSize uint64) error {
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return err
+	}
+
+	// Ensure the transaction is writable.
+	if !tx.writable {
+		str := "reclaim block space requires a writable database " +
+			"transaction"
+		return makeDbErr(database.ErrTxNotWritable, str, nil)
+	}
+
+	sizes, err := tx.fetchDeletedSizes()
+	if err != nil {
+		return err
+	}
+	var totalSize uint64
+	for _, size := range sizes {
+		totalSize += uint64(size)
+	}
+	if totalSize <= targetSize {
+		return nil
+	}
+
+	wc := tx.db.store.writeCursor
+	wc.RLock()
+	curFileNum := wc.curFileNum
+	wc.RUnlock()
+	fileNums := make([]uint32, 0, len(sizes))
+	for fileNum := range sizes {
+		if fileNum != 0 && fileNum != curFileNum {
+			fileNums = append(fileNums, fileNum)
+		}
+	}
+	sort.Slice(fileNums, func(i, j int) bool {
+		sizeI, sizeJ := sizes[fileNums[i]], sizes[fileNums[j]]
+		if sizeI != sizeJ {
+			return sizeI > sizeJ
+		}
+		return fileNums[i] < fileNums[j]
+	})
+	reclaimedFiles := make(map[uint32]struct{})
+	var reclaimFileNums []uint32
+	for _, fileNum := range fileNums {
+		if totalSize <= targetSize {
+			break
+		}
+		reclaimedFiles[fileNum] = struct{}{}
+		reclaimFileNums = append(reclaimFileNums, fileNum)
+		totalSize -= uint64(sizes[fileNum])
+		delete(sizes, fileNum)
+	}
+	if len(reclaimFileNums) == 0 {
+		return nil
+	}
+
+	// Move the remaining blocks in the reclaimed files in the order they
+	// are stored so the files are read sequentially.
+	var moves []pendingMove
+	cursor := tx.blockIdxBucket.Cursor()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		loc := deserializeBlockLoc(cursor.Value())
+		if _, ok := reclaimedFiles[loc.blockFileNum]; !ok {
+			continue
+		}
+		move := pendingMove{loc: loc}
+		copy(move.hash[:], cursor.Key())
+		moves = append(moves, move)
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		locI, locJ := moves[i].loc, moves[j].loc
+		if locI.blockFileNum != locJ.blockFileNum {
+			return locI.blockFileNum < locJ.blockFileNum
+		}
+		return locI.fileOffset < locJ.fileOffset
+	})
+	tx.pendingMoves = append(tx.pendingMoves, moves...)
+
+	log.Debugf("Reclaiming the space of deleted blocks in block files %v",
+		reclaimFileNums)
+
+	if err := tx.putDeletedSizes(sizes); err != nil {
+		return err
+	}
+	return tx.addPendingPrunes(reclaimFileNums)
+}
+
 // BeenPruned returns if the block storage has ever been pruned.
 //
 // This function is part of the database.Tx interface implementation.
@@ -1844,8 +2151,9 @@ type db struct {
 	cache     *dbCache     // Cache layer which wraps underlying leveldb DB.
 }
 
-// Enforce db implements the database.DB interface.
+// Enforce db implements the database.DB and database.Compacter interfaces.
 var _ database.DB = (*db)(nil)
+var _ database.Compacter = (*db)(nil)
 
 // Type returns the database driver type the current database instance was
 // created with.
@@ -2051,6 +2359,35 @@ func (db *db) Close() error {
 	return closeErr
 }
 
+// Compact flushes the database cache to persistent storage and then compacts
+// the entire underlying leveldb database.
+//
+// This function is part of the database.Compacter interface implementation.
+func (db *db) Compact() error {
+	// Prevent the database from being closed while compacting.
+	db.closeLock.RLock()
+	defer db.closeLock.RUnlock()
+
+	if db.closed {
+		return makeDbErr(database.ErrDbNotOpen, errDbNotOpenStr, nil)
+	}
+
+	// Flush the cache so the compaction covers all of the data.  The write
+	// lock is only held while flushing since leveldb allows compactions to
+	// run concurrently with reads and writes.
+	db.writeLock.Lock()
+	err := db.cache.flush()
+	db.writeLock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := db.cache.ldb.CompactRange(util.Range{}); err != nil {
+		return convertErr("failed to compact database", err)
+	}
+	return nil
+}
+
 // filesExists reports whether the named file or directory exists.
 func fileExists(name string) bool {
 	if _, err := os.Stat(name); err != nil {
@@ -2115,6 +2452,11 @@ func openDB(dbPath string, network wire.BitcoinNet, create bool) (database.DB, e
 		Strict:       opt.DefaultStrict,
 		Compression:  opt.NoCompression,
 		Filter:       filter.NewBloomFilter(10),
+
+		WriteBuffer:            metadataWriteBuffer,
+		CompactionTableSize:    metadataCompactionTableSize,
+		WriteL0SlowdownTrigger: metadataL0SlowdownTrigger,
+		WriteL0PauseTrigger:    metadataL0PauseTrigger,
 	}
 	ldb, err := leveldb.OpenFile(metadataDbPath, &opts)
 	if err != nil {
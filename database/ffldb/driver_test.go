@@ -255,6 +255,85 @@ func TestPersistence(t *testing.T) {
 	}
 }
 
+// TestCompact ensures compacting the database keeps the stored data and fails
+// once the database is closed.
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	dbPath := t.TempDir()
+	db, err := database.Create(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+
+	// Store some keys and delete half of them so there is something to
+	// compact.
+	err = db.Update(func(tx database.Tx) error {
+		bucket, err := tx.Metadata().CreateBucket([]byte("compact"))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 1000; i++ {
+			key := []byte(fmt.Sprintf("key%d", i))
+			if err := bucket.Put(key, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+	err = db.Update(func(tx database.Tx) error {
+		bucket := tx.Metadata().Bucket([]byte("compact"))
+		for i := 0; i < 1000; i += 2 {
+			key := []byte(fmt.Sprintf("key%d", i))
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	compacter, ok := db.(database.Compacter)
+	if !ok {
+		t.Fatalf("%T does not implement database.Compacter", db)
+	}
+	if err := compacter.Compact(); err != nil {
+		t.Fatalf("Compact: unexpected error: %v", err)
+	}
+
+	err = db.View(func(tx database.Tx) error {
+		bucket := tx.Metadata().Bucket([]byte("compact"))
+		for i := 0; i < 1000; i++ {
+			key := []byte(fmt.Sprintf("key%d", i))
+			got := bucket.Get(key)
+			if i%2 == 0 && got != nil {
+				return fmt.Errorf("deleted key %s exists", key)
+			}
+			if i%2 == 1 && !bytes.Equal(got, key) {
+				return fmt.Errorf("unexpected value %q for key %s",
+					got, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("View: unexpected error: %v", err)
+	}
+
+	db.Close()
+	err = compacter.Compact()
+	if dbErr, ok := err.(database.Error); !ok ||
+		dbErr.ErrorCode != database.ErrDbNotOpen {
+
+		t.Fatalf("Compact: unexpected error on closed database: %v", err)
+	}
+}
+
 // TestPrune tests that the older .fdb files are deleted with a call to prune.
 func TestPrune(t *testing.T) {
 	t.Parallel()
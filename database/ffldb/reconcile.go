@@ -7,8 +7,11 @@ package ffldb
 import (
 	"fmt"
 	"hash/crc32"
+	"sort"
 
 	"github.com/ltcsuite/ltcd/database"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
 )
 
 // The serialized write cursor location format is:
@@ -47,6 +50,143 @@ func deserializeWriteRow(writeRow []byte) (uint32, uint32, error) {
 	return fileNum, fileOffset, nil
 }
 
+// The serialized prune journal format is:
+//
+//  [0:4*n]      Block file numbers to delete (4 bytes each)
+//  [4*n:4*n+4]  Castagnoli CRC-32 checksum (4 bytes)
+
+// serializePruneJournal serializes the block file numbers to be deleted by a
+// prune into a format suitable for storage into the metadata.
+func serializePruneJournal(fileNums []uint32) []byte {
+	serialized := make([]byte, len(fileNums)*4+4)
+	for i, fileNum := range fileNums {
+		byteOrder.PutUint32(serialized[i*4:], fileNum)
+	}
+	checksum := crc32.Checksum(serialized[:len(fileNums)*4], castagnoli)
+	byteOrder.PutUint32(serialized[len(fileNums)*4:], checksum)
+	return serialized
+}
+
+// deserializePruneJournal deserializes the block file numbers to be deleted by
+// a prune which are stored in the metadata.  Returns ErrCorruption if the entry
+// is malformed or its checksum doesn't match.
+func deserializePruneJournal(serialized []byte) ([]uint32, error) {
+	if len(serialized) < 4 || len(serialized)%4 != 0 {
+		str := fmt.Sprintf("prune journal has unexpected length %d",
+			len(serialized))
+		return nil, makeDbErr(database.ErrCorruption, str, nil)
+	}
+
+	// Ensure the checksum matches.  The checksum is at the end.
+	numFiles := len(serialized)/4 - 1
+	gotChecksum := crc32.Checksum(serialized[:numFiles*4], castagnoli)
+	wantChecksum := byteOrder.Uint32(serialized[numFiles*4:])
+	if gotChecksum != wantChecksum {
+		str := fmt.Sprintf("prune journal does not match the expected "+
+			"checksum - got %d, want %d", gotChecksum, wantChecksum)
+		return nil, makeDbErr(database.ErrCorruption, str, nil)
+	}
+
+	fileNums := make([]uint32, numFiles)
+	for i := range fileNums {
+		fileNums[i] = byteOrder.Uint32(serialized[i*4:])
+	}
+	return fileNums, nil
+}
+
+// The serialized deleted sizes format is:
+//
+//  [0:8*n]      Block file number and bytes of deleted blocks in it (4 bytes each)
+//  [8*n:8*n+4]  Castagnoli CRC-32 checksum (4 bytes)
+
+// serializeDeletedSizes serializes the number of bytes used by deleted blocks
+// in each block file into a format suitable for storage into the metadata.  The
+// files are serialized in ascending order so the result is deterministic.
+func serializeDeletedSizes(sizes map[uint32]uint32) []byte {
+	fileNums := make([]uint32, 0, len(sizes))
+	for fileNum := range sizes {
+		fileNums = append(fileNums, fileNum)
+	}
+	sort.Slice(fileNums, func(i, j int) bool {
+		return fileNums[i] < fileNums[j]
+	})
+
+	serialized := make([]byte, len(fileNums)*8+4)
+	for i, fileNum := range fileNums {
+		byteOrder.PutUint32(serialized[i*8:], fileNum)
+		byteOrder.PutUint32(serialized[i*8+4:], sizes[fileNum])
+	}
+	checksum := crc32.Checksum(serialized[:len(fileNums)*8], castagnoli)
+	byteOrder.PutUint32(serialized[len(fileNums)*8:], checksum)
+	return serialized
+}
+
+// deserializeDeletedSizes deserializes the number of bytes used by deleted
+// blocks in each block file which are stored in the metadata.  Returns
+// ErrCorruption if the entry is malformed or its checksum doesn't match.
+func deserializeDeletedSizes(serialized []byte) (map[uint32]uint32, error) {
+	if len(serialized) < 4 || len(serialized)%8 != 4 {
+		str := fmt.Sprintf("deleted block sizes have unexpected length "+
+			"%d", len(serialized))
+		return nil, makeDbErr(database.ErrCorruption, str, nil)
+	}
+
+	// Ensure the checksum matches.  The checksum is at the end.
+	numFiles := len(serialized) / 8
+	gotChecksum := crc32.Checksum(serialized[:numFiles*8], castagnoli)
+	wantChecksum := byteOrder.Uint32(serialized[numFiles*8:])
+	if gotChecksum != wantChecksum {
+		str := fmt.Sprintf("deleted block sizes do not match the "+
+			"expected checksum - got %d, want %d", gotChecksum,
+			wantChecksum)
+		return nil, makeDbErr(database.ErrCorruption, str, nil)
+	}
+
+	sizes := make(map[uint32]uint32, numFiles)
+	for i := 0; i < numFiles; i++ {
+		fileNum := byteOrder.Uint32(serialized[i*8:])
+		sizes[fileNum] = byteOrder.Uint32(serialized[i*8+4:])
+	}
+	return sizes, nil
+}
+
+// deletePrunedFiles deletes the passed block files which were pruned and then
+// removes the prune journal.  The database cache is flushed first so the
+// removal of the block locations in the files and the journal itself are
+// persisted before any of the files are deleted.
+//
+// Failures are only logged since the changes to the metadata have already
+// been committed.  Any files which could not be deleted remain in the journal
+// and their deletion is retried when the database is next opened.
+//
+// This function MUST be called with the database write lock held.
+func (db *db) deletePrunedFiles(fileNums []uint32) {
+	if err := db.cache.flush(); err != nil {
+		log.Warnf("Unable to flush the database before deleting pruned "+
+			"block files: %v", err)
+		return
+	}
+
+	for _, fileNum := range fileNums {
+		if !fileExists(blockFilePath(db.store.basePath, fileNum)) {
+			continue
+		}
+		if err := db.store.deleteFileFunc(fileNum); err != nil {
+			log.Warnf("Unable to delete pruned block file %d: %v",
+				fileNum, err)
+			return
+		}
+	}
+
+	// The journal was flushed above, so it is removed from the underlying
+	// database directly.
+	key := bucketizedKey(metadataBucketID, pruneJournalKeyName)
+	err := db.cache.ldb.Delete(key, &opt.WriteOptions{Sync: true})
+	if err != nil {
+		log.Warnf("Unable to remove the prune journal: %v", err)
+	}
+}
+
 // reconcileDB reconciles the metadata with the flat block files on disk.  It
 // will also initialize the underlying database if the create flag is set.
 func reconcileDB(pdb *db, create bool) (database.DB, error) {
@@ -113,5 +253,26 @@ func reconcileDB(pdb *db, create bool) (database.DB, error) {
 		return nil, makeDbErr(database.ErrCorruption, str, nil)
 	}
 
+	// Complete the deletion of the block files of a prune which was
+	// interrupted by an unclean shutdown.  The block locations in the files
+	// were already removed along with writing the journal, so the files are
+	// no longer referenced and only need to be deleted.
+	key := bucketizedKey(metadataBucketID, pruneJournalKeyName)
+	serialized, err := pdb.cache.ldb.Get(key, nil)
+	if err != nil && err != leveldb.ErrNotFound {
+		return nil, convertErr("failed to read prune journal", err)
+	}
+	if err == nil {
+		fileNums, err := deserializePruneJournal(serialized)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Info("Detected interrupted prune - Repairing...")
+		pdb.writeLock.Lock()
+		pdb.deletePrunedFiles(fileNums)
+		pdb.writeLock.Unlock()
+	}
+
 	return pdb, nil
 }
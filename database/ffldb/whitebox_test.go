@@ -8,6 +8,7 @@
 package ffldb
 
 import (
+	"bytes"
 	"compress/bzip2"
 	"encoding/binary"
 	"fmt"
@@ -18,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/database"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/wire"
@@ -705,3 +707,283 @@ func testCorruption(tc *testContext) bool {
 // 	// Test various corruption scenarios.
 // 	testCorruption(tc)
 // }
+
+// TestPruneJournal ensures the block files removed by a prune are only deleted
+// once the transaction is committed and that a prune which is interrupted
+// before the files are deleted is completed when the database is opened.
+func TestPruneJournal(t *testing.T) {
+	t.Parallel()
+
+	dbPath := t.TempDir()
+	idb, err := openDB(dbPath, blockDataNet, true)
+	if err != nil {
+		t.Fatalf("openDB: unexpected error: %v", err)
+	}
+	pdb := idb.(*db)
+
+	// Store each block in its own file.
+	pdb.store.maxBlockFileSize = 400
+	var blocks []*ltcutil.Block
+	for i := uint32(0); i < 4; i++ {
+		msgBlock := *chaincfg.MainNetParams.GenesisBlock
+		msgBlock.Header.Nonce = i
+		blocks = append(blocks, ltcutil.NewBlock(&msgBlock))
+	}
+	err = idb.Update(func(tx database.Tx) error {
+		for _, block := range blocks {
+			if err := tx.StoreBlock(block); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		idb.Close()
+		t.Fatalf("StoreBlock: unexpected error: %v", err)
+	}
+
+	// checkFiles ensures the block files which exist match the passed
+	// flags.
+	checkFiles := func(desc string, want ...bool) {
+		t.Helper()
+		for fileNum, wantExists := range want {
+			path := blockFilePath(dbPath, uint32(fileNum))
+			if exists := fileExists(path); exists != wantExists {
+				t.Fatalf("%s: block file %d exists %v, want %v",
+					desc, fileNum, exists, wantExists)
+			}
+		}
+	}
+	checkFiles("stored", true, true, true, true)
+
+	// Files are not deleted when the transaction is rolled back.
+	errRollback := fmt.Errorf("rollback")
+	err = idb.Update(func(tx database.Tx) error {
+		if _, err := tx.PruneBlocks(400); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if err != errRollback {
+		idb.Close()
+		t.Fatalf("PruneBlocks: unexpected error: %v", err)
+	}
+	checkFiles("rolled back", true, true, true, true)
+
+	// Simulate an interruption before the files are deleted by failing to
+	// delete them.
+	pdb.store.deleteFileFunc = func(fileNum uint32) error {
+		return makeDbErr(database.ErrDriverSpecific, "test", nil)
+	}
+	var pruned []chainhash.Hash
+	err = idb.Update(func(tx database.Tx) error {
+		var err error
+		pruned, err = tx.PruneBlocks(400)
+		return err
+	})
+	if err != nil || len(pruned) != 3 {
+		idb.Close()
+		t.Fatalf("PruneBlocks: pruned %d blocks with error %v",
+			len(pruned), err)
+	}
+	checkFiles("interrupted", true, true, true, true)
+	err = idb.View(func(tx database.Tx) error {
+		if tx.Metadata().Get(pruneJournalKeyName) == nil {
+			return fmt.Errorf("prune journal was not written")
+		}
+		_, err := tx.FetchBlock(blocks[0].Hash())
+		if !checkDbError(t, "FetchBlock", err, database.ErrBlockNotFound) {
+			return errSubTestFail
+		}
+		return nil
+	})
+	if err != nil {
+		idb.Close()
+		t.Fatal(err)
+	}
+	if err := idb.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+
+	// Opening the database completes the prune.
+	idb, err = openDB(dbPath, blockDataNet, false)
+	if err != nil {
+		t.Fatalf("openDB: unexpected error: %v", err)
+	}
+	defer idb.Close()
+	checkFiles("reopened", false, false, false, true)
+	err = idb.View(func(tx database.Tx) error {
+		if tx.Metadata().Get(pruneJournalKeyName) != nil {
+			return fmt.Errorf("prune journal was not removed")
+		}
+		_, err := tx.FetchBlock(blocks[3].Hash())
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReclaimBlockSpace ensures deleted blocks are no longer available and that
+// reclaiming their space deletes the block files with the most deleted data
+// after moving the remaining blocks in them, while the first and the current
+// write file are kept.
+func TestReclaimBlockSpace(t *testing.T) {
+	t.Parallel()
+
+	dbPath := t.TempDir()
+	idb, err := openDB(dbPath, blockDataNet, true)
+	if err != nil {
+		t.Fatalf("openDB: unexpected error: %v", err)
+	}
+	pdb := idb.(*db)
+
+	// Store two blocks in each of the first three files.
+	pdb.store.maxBlockFileSize = 700
+	var blocks []*ltcutil.Block
+	for i := uint32(0); i < 6; i++ {
+		msgBlock := *chaincfg.MainNetParams.GenesisBlock
+		msgBlock.Header.Nonce = i
+		blocks = append(blocks, ltcutil.NewBlock(&msgBlock))
+	}
+	err = idb.Update(func(tx database.Tx) error {
+		for _, block := range blocks {
+			if err := tx.StoreBlock(block); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		idb.Close()
+		t.Fatalf("StoreBlock: unexpected error: %v", err)
+	}
+
+	// checkFiles ensures the block files which exist match the passed
+	// flags.
+	checkFiles := func(desc string, want ...bool) {
+		t.Helper()
+		for fileNum, wantExists := range want {
+			path := blockFilePath(dbPath, uint32(fileNum))
+			if exists := fileExists(path); exists != wantExists {
+				t.Fatalf("%s: block file %d exists %v, want %v",
+					desc, fileNum, exists, wantExists)
+			}
+		}
+	}
+
+	// checkBlocks ensures only the passed blocks are stored and that they
+	// are intact.
+	checkBlocks := func(desc string, stored ...int) {
+		t.Helper()
+		err := idb.View(func(tx database.Tx) error {
+			for i, block := range blocks {
+				var isStored bool
+				for _, j := range stored {
+					isStored = isStored || i == j
+				}
+				got, err := tx.FetchBlock(block.Hash())
+				if !isStored {
+					if !checkDbError(t, desc, err,
+						database.ErrBlockNotFound) {
+
+						return errSubTestFail
+					}
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("%s: block %d: %v", desc,
+						i, err)
+				}
+				want, _ := block.Bytes()
+				if !bytes.Equal(got, want) {
+					return fmt.Errorf("%s: block %d differs",
+						desc, i)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkFiles("stored", true, true, true, false)
+
+	// Deleted blocks are gone, but their space is kept while it is within
+	// the target.
+	var blockSize uint64
+	err = idb.Update(func(tx database.Tx) error {
+		err := tx.DeleteBlocks([]chainhash.Hash{*blocks[2].Hash(),
+			chainhash.Hash{}})
+		if err != nil {
+			return err
+		}
+		sizes, err := tx.(*transaction).fetchDeletedSizes()
+		if err != nil {
+			return err
+		}
+		blockSize = uint64(sizes[1])
+		return tx.ReclaimBlockSpace(blockSize)
+	})
+	if err != nil {
+		idb.Close()
+		t.Fatalf("DeleteBlocks: unexpected error: %v", err)
+	}
+	checkFiles("deleted", true, true, true, false)
+	checkBlocks("deleted", 0, 1, 3, 4, 5)
+
+	// Reclaiming the space moves the other block of the file to a new
+	// write file and deletes the file.
+	err = idb.Update(func(tx database.Tx) error {
+		return tx.ReclaimBlockSpace(0)
+	})
+	if err != nil {
+		idb.Close()
+		t.Fatalf("ReclaimBlockSpace: unexpected error: %v", err)
+	}
+	checkFiles("reclaimed", true, false, true, true)
+	checkBlocks("reclaimed", 0, 1, 3, 4, 5)
+
+	// The first file is never reclaimed, so only the space of the third
+	// file is.
+	err = idb.Update(func(tx database.Tx) error {
+		err := tx.DeleteBlocks([]chainhash.Hash{*blocks[1].Hash(),
+			*blocks[4].Hash()})
+		if err != nil {
+			return err
+		}
+		return tx.ReclaimBlockSpace(0)
+	})
+	if err != nil {
+		idb.Close()
+		t.Fatalf("ReclaimBlockSpace: unexpected error: %v", err)
+	}
+	checkFiles("first file", true, false, false, true)
+	checkBlocks("first file", 0, 3, 5)
+	err = idb.View(func(tx database.Tx) error {
+		sizes, err := tx.(*transaction).fetchDeletedSizes()
+		if err != nil {
+			return err
+		}
+		if len(sizes) != 1 || uint64(sizes[0]) != blockSize {
+			return fmt.Errorf("unexpected deleted sizes %v", sizes)
+		}
+		return nil
+	})
+	if err != nil {
+		idb.Close()
+		t.Fatal(err)
+	}
+
+	// The moved blocks are still available once the database is opened
+	// again.
+	if err := idb.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+	idb, err = openDB(dbPath, blockDataNet, false)
+	if err != nil {
+		t.Fatalf("openDB: unexpected error: %v", err)
+	}
+	defer idb.Close()
+	checkBlocks("reopened", 0, 3, 5)
+}
@@ -404,6 +404,30 @@ type Tx interface {
 	// implementations.
 	PruneBlocks(targetSize uint64) ([]chainhash.Hash, error)
 
+	// DeleteBlocks removes the blocks for the given hashes from the block
+	// storage.  Hashes of blocks which are not stored, including blocks
+	// stored by the same transaction, are ignored.  The space used by the
+	// removed blocks is not released until it is reclaimed with
+	// ReclaimBlockSpace or the block files are pruned.
+	//
+	// The interface contract guarantees at least the following errors will
+	// be returned (other implementation-specific errors are possible):
+	//   - ErrTxNotWritable if attempted against a read-only transaction
+	//   - ErrTxClosed if the transaction has already been closed
+	DeleteBlocks(hashes []chainhash.Hash) error
+
+	// ReclaimBlockSpace releases the space used by blocks removed with
+	// DeleteBlocks until at most the target size (specified in bytes) of
+	// it remains unreleased.  The space is released by moving the
+	// remaining blocks of the block files with the most removed data to
+	// the end of the block storage and deleting those files.
+	//
+	// The interface contract guarantees at least the following errors will
+	// be returned (other implementation-specific errors are possible):
+	//   - ErrTxNotWritable if attempted against a read-only transaction
+	//   - ErrTxClosed if the transaction has already been closed
+	ReclaimBlockSpace(targetSize uint64) error
+
 	// BeenPruned returns if the block storage has ever been pruned.
 	//
 	// Implementation specific errors are possible.
@@ -484,3 +508,15 @@ type DB interface {
 	// back or committed).
 	Close() error
 }
+
+// Compacter is an optional interface implemented by databases which are able
+// to compact their underlying storage on demand.  Compaction reclaims the space
+// used by deleted and overwritten entries and reduces the amount of work the
+// storage engine has to do in the background later.  Callers should check for
+// it with a type assertion since not all drivers support it.
+type Compacter interface {
+	// Compact flushes any cached data to persistent storage and compacts
+	// the entire underlying storage.  It may take a long time on large
+	// databases, during which other transactions are allowed to proceed.
+	Compact() error
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+const (
+	// migrateBatchSize is the maximum number of keys written to the
+	// destination database in a single transaction while migrating.
+	migrateBatchSize = 10000
+
+	// migrateBlockBatchSize is the maximum number of blocks written to the
+	// destination database in a single transaction while migrating.
+	migrateBlockBatchSize = 100
+)
+
+// keyValue houses a key/value pair read from the source database during a
+// migration.
+type keyValue struct {
+	key   []byte
+	value []byte
+}
+
+// copyBytes returns a copy of the passed slice since the keys and values
+// returned by cursors are only valid during the transaction.
+func copyBytes(b []byte) []byte {
+	return append([]byte{}, b...)
+}
+
+// bucketAt returns the nested bucket at the passed path from the metadata
+// bucket, creating it when create is set.
+func bucketAt(tx Tx, path [][]byte, create bool) (Bucket, error) {
+	bucket := tx.Metadata()
+	for _, name := range path {
+		if create {
+			var err error
+			bucket, err = bucket.CreateBucketIfNotExists(name)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		bucket = bucket.Bucket(name)
+		if bucket == nil {
+			str := fmt.Sprintf("bucket %x does not exist", name)
+			return nil, makeError(ErrBucketNotFound, str, nil)
+		}
+	}
+	return bucket, nil
+}
+
+// copyBucket copies the keys of the bucket at the passed path, along with all
+// of its nested buckets, from src to dst in batches.  Keys and buckets of the
+// metadata bucket for which skip returns true are not copied.
+func copyBucket(src, dst DB, path [][]byte, skip func(key []byte) bool) error {
+	// Create the bucket in the destination and gather its nested buckets.
+	// The nested buckets are listed separately from the keys since seeking
+	// a cursor only considers the keys of the bucket.
+	var children [][]byte
+	err := src.View(func(tx Tx) error {
+		bucket, err := bucketAt(tx, path, false)
+		if err != nil {
+			return err
+		}
+		return bucket.ForEachBucket(func(name []byte) error {
+			if len(path) == 0 && skip != nil && skip(name) {
+				return nil
+			}
+			children = append(children, copyBytes(name))
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	err = dst.Update(func(tx Tx) error {
+		_, err := bucketAt(tx, path, true)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var seek []byte
+	for done := false; !done; {
+		var pairs []keyValue
+		done = true
+		err := src.View(func(tx Tx) error {
+			bucket, err := bucketAt(tx, path, false)
+			if err != nil {
+				return err
+			}
+
+			cursor := bucket.Cursor()
+			ok := cursor.First()
+			if seek != nil {
+				ok = cursor.Seek(seek)
+			}
+			for ; ok; ok = cursor.Next() {
+				value := cursor.Value()
+				if value == nil {
+					continue
+				}
+				key := cursor.Key()
+				if len(path) == 0 && skip != nil && skip(key) {
+					continue
+				}
+				if len(pairs) == migrateBatchSize {
+					seek = copyBytes(key)
+					done = false
+					return nil
+				}
+				pairs = append(pairs, keyValue{
+					key:   copyBytes(key),
+					value: copyBytes(value),
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = dst.Update(func(tx Tx) error {
+			bucket, err := bucketAt(tx, path, false)
+			if err != nil {
+				return err
+			}
+			for _, pair := range pairs {
+				if err := bucket.Put(pair.key, pair.value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, child := range children {
+		childPath := append(append([][]byte{}, path...), child)
+		if err := copyBucket(src, dst, childPath, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CopyMetadata copies every key and nested bucket of the metadata bucket of src
+// to the metadata bucket of dst.  The copy is done in batches so arbitrarily
+// large databases can be copied without holding all of their data in memory.
+//
+// The skip function, which may be nil, is invoked with each key and bucket name
+// of the metadata bucket and must return true for those which are not to be
+// copied.  It is typically used to skip the keys a driver uses internally, such
+// as the block index kept by the ffldb driver under its "ffldb-" prefix.
+//
+// The destination database should not be used by anything else until the copy
+// completes since it is not done in a single transaction.
+func CopyMetadata(src, dst DB, skip func(key []byte) bool) error {
+	return copyBucket(src, dst, nil, skip)
+}
+
+// CopyBlocks stores the blocks identified by the passed hashes in dst after
+// fetching them from src.  Blocks which already exist in dst are skipped.  The
+// number of blocks copied is returned along with any error.
+func CopyBlocks(src, dst DB, hashes []chainhash.Hash) (int, error) {
+	var copied int
+	for len(hashes) > 0 {
+		batch := hashes
+		if len(batch) > migrateBlockBatchSize {
+			batch = batch[:migrateBlockBatchSize]
+		}
+		hashes = hashes[len(batch):]
+
+		var blocks []*ltcutil.Block
+		err := src.View(func(tx Tx) error {
+			for i := range batch {
+				blockBytes, err := tx.FetchBlock(&batch[i])
+				if err != nil {
+					return err
+				}
+				block, err := ltcutil.NewBlockFromBytes(blockBytes)
+				if err != nil {
+					str := fmt.Sprintf("failed to deserialize "+
+						"block %v", batch[i])
+					return makeError(ErrCorruption, str, err)
+				}
+				blocks = append(blocks, block)
+			}
+			return nil
+		})
+		if err != nil {
+			return copied, err
+		}
+
+		var stored int
+		err = dst.Update(func(tx Tx) error {
+			stored = 0
+			for _, block := range blocks {
+				exists, err := tx.HasBlock(block.Hash())
+				if err != nil {
+					return err
+				}
+				if exists {
+					continue
+				}
+				if err := tx.StoreBlock(block); err != nil {
+					return err
+				}
+				stored++
+			}
+			return nil
+		})
+		if err != nil {
+			return copied, err
+		}
+		copied += stored
+	}
+	return copied, nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database_test
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestCopyDB ensures the metadata and blocks of a database are copied to
+// another one.
+func TestCopyDB(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	src, err := database.Create("ffldb", filepath.Join(tempDir, "src"),
+		wire.MainNet)
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	defer src.Close()
+	dst, err := database.Create("ffldb", filepath.Join(tempDir, "dst"),
+		wire.MainNet)
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	defer dst.Close()
+
+	// Populate the source with enough keys to require several batches,
+	// nested buckets, and a block.
+	genesis := ltcutil.NewBlock(chaincfg.MainNetParams.GenesisBlock)
+	err = src.Update(func(tx database.Tx) error {
+		meta := tx.Metadata()
+		if err := meta.Put([]byte("rootkey"), []byte("rootvalue")); err != nil {
+			return err
+		}
+		bucket, err := meta.CreateBucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 25000; i++ {
+			key := []byte(fmt.Sprintf("key%05d", i))
+			if err := bucket.Put(key, key); err != nil {
+				return err
+			}
+		}
+		nested, err := bucket.CreateBucket([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		if err := nested.Put([]byte("a"), []byte("b")); err != nil {
+			return err
+		}
+		return tx.StoreBlock(genesis)
+	})
+	if err != nil {
+		t.Fatalf("unable to populate source database: %v", err)
+	}
+
+	skip := func(key []byte) bool {
+		return strings.HasPrefix(string(key), "ffldb-")
+	}
+	if err := database.CopyMetadata(src, dst, skip); err != nil {
+		t.Fatalf("CopyMetadata: unexpected error: %v", err)
+	}
+	hashes := []chainhash.Hash{*genesis.Hash()}
+	copied, err := database.CopyBlocks(src, dst, hashes)
+	if err != nil || copied != 1 {
+		t.Fatalf("CopyBlocks: copied %d blocks with error %v", copied,
+			err)
+	}
+
+	// Blocks already in the destination are skipped.
+	copied, err = database.CopyBlocks(src, dst, hashes)
+	if err != nil || copied != 0 {
+		t.Fatalf("CopyBlocks: copied %d existing blocks with error %v",
+			copied, err)
+	}
+
+	err = dst.View(func(tx database.Tx) error {
+		meta := tx.Metadata()
+		if v := meta.Get([]byte("rootkey")); !bytes.Equal(v, []byte("rootvalue")) {
+			return fmt.Errorf("unexpected root value %q", v)
+		}
+		bucket := meta.Bucket([]byte("bucket"))
+		if bucket == nil {
+			return fmt.Errorf("bucket was not copied")
+		}
+		var numKeys int
+		err := bucket.ForEach(func(k, v []byte) error {
+			if !bytes.Equal(k, v) {
+				return fmt.Errorf("unexpected value %q for %q", v, k)
+			}
+			numKeys++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if numKeys != 25000 {
+			return fmt.Errorf("copied %d keys, want 25000", numKeys)
+		}
+		nested := bucket.Bucket([]byte("nested"))
+		if nested == nil || !bytes.Equal(nested.Get([]byte("a")), []byte("b")) {
+			return fmt.Errorf("nested bucket was not copied")
+		}
+
+		blockBytes, err := tx.FetchBlock(genesis.Hash())
+		if err != nil {
+			return err
+		}
+		wantBytes, _ := genesis.Bytes()
+		if !bytes.Equal(blockBytes, wantBytes) {
+			return fmt.Errorf("copied block does not match")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
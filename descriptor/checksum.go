@@ -0,0 +1,137 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package descriptor
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// inputCharset is the set of characters which may appear in a
+	// descriptor.  The position of each character is used as its value
+	// when computing the checksum, so the order must not be changed.
+	inputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+
+	// checksumCharset is the set of characters used to encode the
+	// checksum.
+	checksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// ChecksumLength is the number of characters in a descriptor checksum.
+	ChecksumLength = 8
+)
+
+// polyMod updates the checksum state c with the 5-bit value val.  The
+// generator constants are those of the BCH code defined by BIP 380.
+func polyMod(c uint64, val int) uint64 {
+	c0 := c >> 35
+	c = ((c & 0x7ffffffff) << 5) ^ uint64(val)
+	if c0&1 != 0 {
+		c ^= 0xf5dee51989
+	}
+	if c0&2 != 0 {
+		c ^= 0xa9fdca3312
+	}
+	if c0&4 != 0 {
+		c ^= 0x1bab10e32d
+	}
+	if c0&8 != 0 {
+		c ^= 0x3706b1677a
+	}
+	if c0&16 != 0 {
+		c ^= 0x644d626ffd
+	}
+	return c
+}
+
+// Checksum returns the checksum of the passed descriptor, which must not
+// already contain a checksum.  An error is returned if the descriptor contains
+// characters outside of the descriptor character set.
+func Checksum(desc string) (string, error) {
+	c := uint64(1)
+	cls := 0
+	clsCount := 0
+	for i := 0; i < len(desc); i++ {
+		pos := strings.IndexByte(inputCharset, desc[i])
+		if pos == -1 {
+			return "", fmt.Errorf("invalid character %q in descriptor",
+				desc[i])
+		}
+
+		// Emit a symbol for the position inside the group for every
+		// character, and one symbol for every group of three characters
+		// identifying which group they were in.
+		c = polyMod(c, pos&31)
+		cls = cls*3 + (pos >> 5)
+		clsCount++
+		if clsCount == 3 {
+			c = polyMod(c, cls)
+			cls = 0
+			clsCount = 0
+		}
+	}
+	if clsCount > 0 {
+		c = polyMod(c, cls)
+	}
+
+	// Shift further to determine the checksum.
+	for i := 0; i < ChecksumLength; i++ {
+		c = polyMod(c, 0)
+	}
+
+	// Prevent appending zeroes from not affecting the checksum.
+	c ^= 1
+
+	var checksum [ChecksumLength]byte
+	for i := 0; i < ChecksumLength; i++ {
+		checksum[i] = checksumCharset[(c>>(5*(7-i)))&31]
+	}
+	return string(checksum[:]), nil
+}
+
+// AddChecksum returns the passed descriptor with its checksum appended.
+func AddChecksum(desc string) (string, error) {
+	checksum, err := Checksum(desc)
+	if err != nil {
+		return "", err
+	}
+	return desc + "#" + checksum, nil
+}
+
+// splitChecksum separates a descriptor from its checksum, if any, and
+// verifies the checksum when present.  When requireChecksum is set, an error
+// is returned for a descriptor without a checksum.
+func splitChecksum(desc string, requireChecksum bool) (string, error) {
+	idx := strings.IndexByte(desc, '#')
+	if idx == -1 {
+		if requireChecksum {
+			return "", ErrMissingChecksum
+		}
+
+		// Still ensure the character set is valid.
+		if _, err := Checksum(desc); err != nil {
+			return "", err
+		}
+		return desc, nil
+	}
+
+	body, checksum := desc[:idx], desc[idx+1:]
+	if len(checksum) != ChecksumLength {
+		return "", fmt.Errorf("expected %d character checksum, got %d",
+			ChecksumLength, len(checksum))
+	}
+	want, err := Checksum(body)
+	if err != nil {
+		return "", err
+	}
+	if checksum != want {
+		return "", fmt.Errorf("%w: provided %s, expected %s",
+			ErrBadChecksum, checksum, want)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,701 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package descriptor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ltcsuite/ltcd/btcec/v2/schnorr"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+)
+
+var (
+	// ErrMissingChecksum is returned when a checksum is required but the
+	// descriptor does not have one.
+	ErrMissingChecksum = errors.New("missing descriptor checksum")
+
+	// ErrBadChecksum is returned when the checksum of a descriptor does
+	// not match its contents.
+	ErrBadChecksum = errors.New("descriptor checksum mismatch")
+
+	// ErrUncompressedKey is returned when an uncompressed public key is
+	// used in a witness or tapscript context.
+	ErrUncompressedKey = errors.New("uncompressed keys are not allowed " +
+		"in witness scripts")
+
+	// ErrHardenedFromPublic is returned when a hardened derivation step
+	// follows an extended public key.
+	ErrHardenedFromPublic = errors.New("hardened derivation requires an " +
+		"extended private key")
+
+	// ErrIndexOutOfRange is returned when a ranged descriptor is derived at
+	// an index which is not a valid non-hardened child index.
+	ErrIndexOutOfRange = errors.New("derivation index out of range")
+
+	// ErrNoAddress is returned when the script produced by a descriptor has
+	// no address representation, such as a bare multisig script.
+	ErrNoAddress = errors.New("descriptor does not have a corresponding " +
+		"address")
+)
+
+const (
+	// maxTapTreeDepth is the maximum depth of a tapscript tree as limited
+	// by the size of a control block.
+	maxTapTreeDepth = 128
+)
+
+// nodeType identifies the kind of a script expression.
+type nodeType int
+
+const (
+	nodePK nodeType = iota
+	nodePKH
+	nodeWPKH
+	nodeSH
+	nodeWSH
+	nodeMulti
+	nodeSortedMulti
+	nodeTR
+	nodeRaw
+	nodeAddr
+)
+
+// Map of node types back to the names used in descriptors.
+var nodeTypeStrings = map[nodeType]string{
+	nodePK:          "pk",
+	nodePKH:         "pkh",
+	nodeWPKH:        "wpkh",
+	nodeSH:          "sh",
+	nodeWSH:         "wsh",
+	nodeMulti:       "multi",
+	nodeSortedMulti: "sortedmulti",
+	nodeTR:          "tr",
+	nodeRaw:         "raw",
+	nodeAddr:        "addr",
+}
+
+// node is a parsed script expression.
+type node struct {
+	typ nodeType
+
+	// ctx is the context the expression appeared in.
+	ctx scriptContext
+
+	// keys holds the key arguments of pk, pkh, wpkh, multi, sortedmulti,
+	// and the internal key of tr.
+	keys []*keyExpr
+
+	// threshold is the number of required signatures of a multisig.
+	threshold int
+
+	// sub is the script wrapped by sh or wsh.
+	sub *node
+
+	// tree is the optional script tree of tr.
+	tree *tapTree
+
+	// script is the script of raw.
+	script []byte
+
+	// addr is the address of addr.
+	addr ltcutil.Address
+}
+
+// tapTree is a node of a tapscript tree.  Leaves have a script set while
+// branches have both children set.
+type tapTree struct {
+	leaf        *node
+	left, right *tapTree
+}
+
+// Descriptor is a parsed output script descriptor.
+type Descriptor struct {
+	root *node
+	net  *chaincfg.Params
+}
+
+// Parse parses the passed descriptor for the given network.  When the
+// descriptor carries a checksum it is verified.  When requireChecksum is set, a
+// descriptor without a checksum is rejected with ErrMissingChecksum.
+func Parse(desc string, requireChecksum bool,
+	net *chaincfg.Params) (*Descriptor, error) {
+
+	body, err := splitChecksum(desc, requireChecksum)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseNode(body, contextTop, net)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Descriptor{root: root, net: net}, nil
+}
+
+// splitFunc splits an expression of the form name(args) into the name and
+// the arguments.
+func splitFunc(s string) (string, string, error) {
+	open := strings.IndexByte(s, '(')
+	if open == -1 || !strings.HasSuffix(s, ")") {
+		return "", "", fmt.Errorf("%q is not a valid script expression",
+			s)
+	}
+	return s[:open], s[open+1 : len(s)-1], nil
+}
+
+// splitArgs splits a comma separated argument list at the top nesting level.
+func splitArgs(s string) ([]string, error) {
+	var (
+		args  []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced brackets in %q", s)
+			}
+		case ',':
+			if depth == 0 {
+				args = append(args, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced brackets in %q", s)
+	}
+	return append(args, s[start:]), nil
+}
+
+// parseNode parses a script expression appearing in the passed context.
+func parseNode(s string, ctx scriptContext,
+	net *chaincfg.Params) (*node, error) {
+
+	name, argStr, err := splitFunc(s)
+	if err != nil {
+		return nil, err
+	}
+	args, err := splitArgs(argStr)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &node{ctx: ctx}
+	switch name {
+	case "pk":
+		n.typ = nodePK
+
+	case "pkh":
+		if ctx == contextTapscript {
+			return nil, fmt.Errorf("pkh() is not allowed in tr()")
+		}
+		n.typ = nodePKH
+
+	case "wpkh":
+		if ctx != contextTop && ctx != contextP2SH {
+			return nil, fmt.Errorf("wpkh() is only allowed at the " +
+				"top level or inside sh()")
+		}
+		n.typ = nodeWPKH
+
+	case "sh", "tr", "raw", "addr":
+		if ctx != contextTop {
+			return nil, fmt.Errorf("%s() is only allowed at the top "+
+				"level", name)
+		}
+
+	case "wsh":
+		if ctx != contextTop && ctx != contextP2SH {
+			return nil, fmt.Errorf("wsh() is only allowed at the " +
+				"top level or inside sh()")
+		}
+
+	case "multi", "sortedmulti":
+		if ctx == contextTapscript {
+			return nil, fmt.Errorf("%s() is not allowed in tr()",
+				name)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown script expression %q", name)
+	}
+
+	switch name {
+	case "pk", "pkh", "wpkh":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s() takes exactly one key", name)
+		}
+		keyCtx := ctx
+		if n.typ == nodeWPKH {
+			keyCtx = contextP2WPKH
+		}
+		key, err := parseKey(args[0], keyCtx, net)
+		if err != nil {
+			return nil, err
+		}
+		n.keys = []*keyExpr{key}
+
+	case "sh":
+		n.typ = nodeSH
+		if len(args) != 1 {
+			return nil, fmt.Errorf("sh() takes exactly one script")
+		}
+		n.sub, err = parseNode(args[0], contextP2SH, net)
+		if err != nil {
+			return nil, err
+		}
+
+	case "wsh":
+		n.typ = nodeWSH
+		if len(args) != 1 {
+			return nil, fmt.Errorf("wsh() takes exactly one script")
+		}
+		n.sub, err = parseNode(args[0], contextP2WSH, net)
+		if err != nil {
+			return nil, err
+		}
+
+	case "multi", "sortedmulti":
+		n.typ = nodeMulti
+		if name == "sortedmulti" {
+			n.typ = nodeSortedMulti
+		}
+		if err := n.parseMulti(args, net); err != nil {
+			return nil, err
+		}
+
+	case "tr":
+		n.typ = nodeTR
+		if len(args) != 1 && len(args) != 2 {
+			return nil, fmt.Errorf("tr() takes a key and an optional " +
+				"script tree")
+		}
+		key, err := parseKey(args[0], contextTapscript, net)
+		if err != nil {
+			return nil, err
+		}
+		n.keys = []*keyExpr{key}
+		if len(args) == 2 {
+			n.tree, err = parseTapTree(args[1], 0, net)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case "raw":
+		n.typ = nodeRaw
+		n.script, err = hex.DecodeString(argStr)
+		if err != nil {
+			return nil, fmt.Errorf("raw script %q is not hex", argStr)
+		}
+
+	case "addr":
+		n.typ = nodeAddr
+		n.addr, err = ltcutil.DecodeAddress(argStr, net)
+		if err != nil {
+			return nil, err
+		}
+		if !n.addr.IsForNet(net) {
+			return nil, fmt.Errorf("address %q is not for this "+
+				"network", argStr)
+		}
+	}
+
+	return n, nil
+}
+
+// parseMulti parses the arguments of a multi or sortedmulti expression.
+func (n *node) parseMulti(args []string, net *chaincfg.Params) error {
+	name := nodeTypeStrings[n.typ]
+	if len(args) < 2 {
+		return fmt.Errorf("%s() requires a threshold and at least one "+
+			"key", name)
+	}
+
+	threshold, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("multisig threshold %q is not valid", args[0])
+	}
+	numKeys := len(args) - 1
+	if numKeys > txscript.MaxPubKeysPerMultiSig {
+		return fmt.Errorf("%s() has %d keys which exceeds the maximum "+
+			"of %d", name, numKeys, txscript.MaxPubKeysPerMultiSig)
+	}
+	if threshold < 1 || int(threshold) > numKeys {
+		return fmt.Errorf("multisig threshold %d is not between 1 and "+
+			"%d", threshold, numKeys)
+	}
+	n.threshold = int(threshold)
+
+	// The script size of a multisig does not depend on the derivation
+	// index, so the P2SH redeem script size limit can be enforced here.
+	scriptLen := 3
+	for _, arg := range args[1:] {
+		key, err := parseKey(arg, n.ctx, net)
+		if err != nil {
+			return err
+		}
+		n.keys = append(n.keys, key)
+		scriptLen += 1 + key.serializedLen(n.ctx)
+	}
+	if n.ctx == contextP2SH && scriptLen > txscript.MaxScriptElementSize {
+		return fmt.Errorf("P2SH script is too large, %d bytes is "+
+			"larger than %d bytes", scriptLen,
+			txscript.MaxScriptElementSize)
+	}
+
+	return nil
+}
+
+// parseTapTree parses a tapscript tree which is either a single leaf script
+// or a pair of trees of the form {TREE,TREE}.
+func parseTapTree(s string, depth int, net *chaincfg.Params) (*tapTree, error) {
+	if depth > maxTapTreeDepth {
+		return nil, fmt.Errorf("tapscript tree exceeds the maximum "+
+			"depth of %d", maxTapTreeDepth)
+	}
+
+	if !strings.HasPrefix(s, "{") {
+		leaf, err := parseNode(s, contextTapscript, net)
+		if err != nil {
+			return nil, err
+		}
+		if leaf.typ != nodePK {
+			return nil, fmt.Errorf("%s() is not supported in a "+
+				"tapscript tree", nodeTypeStrings[leaf.typ])
+		}
+		return &tapTree{leaf: leaf}, nil
+	}
+
+	if !strings.HasSuffix(s, "}") {
+		return nil, fmt.Errorf("script tree start '{' has no matching "+
+			"'}' in %q", s)
+	}
+	branches, err := splitArgs(s[1 : len(s)-1])
+	if err != nil {
+		return nil, err
+	}
+	if len(branches) != 2 {
+		return nil, fmt.Errorf("script tree branch %q does not have "+
+			"exactly two children", s)
+	}
+	left, err := parseTapTree(branches[0], depth+1, net)
+	if err != nil {
+		return nil, err
+	}
+	right, err := parseTapTree(branches[1], depth+1, net)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tapTree{left: left, right: right}, nil
+}
+
+// walkKeys calls fn for every key in the expression and its children.
+func (n *node) walkKeys(fn func(*keyExpr)) {
+	for _, key := range n.keys {
+		fn(key)
+	}
+	if n.sub != nil {
+		n.sub.walkKeys(fn)
+	}
+	if n.tree != nil {
+		n.tree.walkKeys(fn)
+	}
+}
+
+// walkKeys calls fn for every key in the leaves of the tree.
+func (t *tapTree) walkKeys(fn func(*keyExpr)) {
+	if t.leaf != nil {
+		t.leaf.walkKeys(fn)
+		return
+	}
+	t.left.walkKeys(fn)
+	t.right.walkKeys(fn)
+}
+
+// string returns the canonical form of the expression.
+func (n *node) string(private bool) (string, error) {
+	var args []string
+	switch n.typ {
+	case nodeMulti, nodeSortedMulti:
+		args = append(args, strconv.Itoa(n.threshold))
+
+	case nodeSH, nodeWSH:
+		sub, err := n.sub.string(private)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, sub)
+
+	case nodeRaw:
+		args = append(args, hex.EncodeToString(n.script))
+
+	case nodeAddr:
+		args = append(args, n.addr.EncodeAddress())
+	}
+
+	for _, key := range n.keys {
+		s, err := key.string(private)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, s)
+	}
+
+	if n.tree != nil {
+		tree, err := n.tree.string(private)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, tree)
+	}
+
+	return nodeTypeStrings[n.typ] + "(" + strings.Join(args, ",") + ")",
+		nil
+}
+
+// string returns the canonical form of the tree.
+func (t *tapTree) string(private bool) (string, error) {
+	if t.leaf != nil {
+		return t.leaf.string(private)
+	}
+	left, err := t.left.string(private)
+	if err != nil {
+		return "", err
+	}
+	right, err := t.right.string(private)
+	if err != nil {
+		return "", err
+	}
+	return "{" + left + "," + right + "}", nil
+}
+
+// address returns the address of the expression at the passed index.
+func (n *node) address(index uint32, net *chaincfg.Params) (ltcutil.Address, error) {
+	switch n.typ {
+	case nodePKH:
+		pubKey, err := n.keys[0].serialize(index, n.ctx)
+		if err != nil {
+			return nil, err
+		}
+		return ltcutil.NewAddressPubKeyHash(ltcutil.Hash160(pubKey), net)
+
+	case nodeWPKH:
+		pubKey, err := n.keys[0].serialize(index, contextP2WPKH)
+		if err != nil {
+			return nil, err
+		}
+		return ltcutil.NewAddressWitnessPubKeyHash(
+			ltcutil.Hash160(pubKey), net,
+		)
+
+	case nodeSH:
+		script, err := n.sub.pkScript(index, net)
+		if err != nil {
+			return nil, err
+		}
+		return ltcutil.NewAddressScriptHash(script, net)
+
+	case nodeWSH:
+		script, err := n.sub.pkScript(index, net)
+		if err != nil {
+			return nil, err
+		}
+		hash := sha256.Sum256(script)
+		return ltcutil.NewAddressWitnessScriptHash(hash[:], net)
+
+	case nodeTR:
+		internalKey, err := n.keys[0].pubKeyAt(index)
+		if err != nil {
+			return nil, err
+		}
+		outputKey := txscript.ComputeTaprootKeyNoScript(internalKey)
+		if n.tree != nil {
+			tapNode, err := n.tree.tapNode(index, net)
+			if err != nil {
+				return nil, err
+			}
+			rootHash := tapNode.TapHash()
+			outputKey = txscript.ComputeTaprootOutputKey(
+				internalKey, rootHash[:],
+			)
+		}
+		return ltcutil.NewAddressTaproot(
+			schnorr.SerializePubKey(outputKey), net,
+		)
+
+	case nodeAddr:
+		return n.addr, nil
+
+	case nodeRaw:
+		class, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			n.script, net,
+		)
+		if err != nil {
+			return nil, err
+		}
+		switch class {
+		case txscript.PubKeyTy, txscript.MultiSigTy,
+			txscript.NullDataTy, txscript.NonStandardTy:
+
+			return nil, ErrNoAddress
+		}
+		if len(addrs) != 1 {
+			return nil, ErrNoAddress
+		}
+		return addrs[0], nil
+	}
+
+	return nil, ErrNoAddress
+}
+
+// pkScript returns the script of the expression at the passed index.  For
+// expressions nested in sh or wsh this is the redeem or witness script.
+func (n *node) pkScript(index uint32, net *chaincfg.Params) ([]byte, error) {
+	switch n.typ {
+	case nodePK:
+		pubKey, err := n.keys[0].serialize(index, n.ctx)
+		if err != nil {
+			return nil, err
+		}
+		return txscript.NewScriptBuilder().AddData(pubKey).
+			AddOp(txscript.OP_CHECKSIG).Script()
+
+	case nodeMulti, nodeSortedMulti:
+		pubKeys := make([][]byte, 0, len(n.keys))
+		for _, key := range n.keys {
+			pubKey, err := key.serialize(index, n.ctx)
+			if err != nil {
+				return nil, err
+			}
+			pubKeys = append(pubKeys, pubKey)
+		}
+		if n.typ == nodeSortedMulti {
+			sort.Slice(pubKeys, func(i, j int) bool {
+				return bytes.Compare(pubKeys[i], pubKeys[j]) < 0
+			})
+		}
+
+		builder := txscript.NewScriptBuilder().
+			AddInt64(int64(n.threshold))
+		for _, pubKey := range pubKeys {
+			builder.AddData(pubKey)
+		}
+		return builder.AddInt64(int64(len(pubKeys))).
+			AddOp(txscript.OP_CHECKMULTISIG).Script()
+
+	case nodeRaw:
+		return n.script, nil
+	}
+
+	addr, err := n.address(index, net)
+	if err != nil {
+		return nil, err
+	}
+	return txscript.PayToAddrScript(addr)
+}
+
+// tapNode returns the tapscript tree node for the tree at the passed index.
+func (t *tapTree) tapNode(index uint32,
+	net *chaincfg.Params) (txscript.TapNode, error) {
+
+	if t.leaf != nil {
+		script, err := t.leaf.pkScript(index, net)
+		if err != nil {
+			return nil, err
+		}
+		return txscript.NewBaseTapLeaf(script), nil
+	}
+
+	left, err := t.left.tapNode(index, net)
+	if err != nil {
+		return nil, err
+	}
+	right, err := t.right.tapNode(index, net)
+	if err != nil {
+		return nil, err
+	}
+	return txscript.NewTapBranch(left, right), nil
+}
+
+// String returns the canonical form of the descriptor with any private keys
+// replaced by their public keys and the checksum appended.
+func (d *Descriptor) String() string {
+	s, err := d.root.string(false)
+	if err != nil {
+		return ""
+	}
+	s, err = AddChecksum(s)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// PrivateString returns the canonical form of the descriptor including any
+// private keys with the checksum appended.
+func (d *Descriptor) PrivateString() (string, error) {
+	s, err := d.root.string(true)
+	if err != nil {
+		return "", err
+	}
+	return AddChecksum(s)
+}
+
+// IsRange returns whether the descriptor contains a ranged key and therefore
+// produces a different script for every index.
+func (d *Descriptor) IsRange() bool {
+	var isRange bool
+	d.root.walkKeys(func(k *keyExpr) {
+		isRange = isRange || k.isRange()
+	})
+	return isRange
+}
+
+// IsSolvable returns whether the descriptor contains all of the information
+// needed to sign for its scripts given the private keys.  The raw and addr
+// expressions are not solvable.
+func (d *Descriptor) IsSolvable() bool {
+	return d.root.typ != nodeRaw && d.root.typ != nodeAddr
+}
+
+// HasPrivateKeys returns whether the descriptor contains at least one private
+// key.
+func (d *Descriptor) HasPrivateKeys() bool {
+	var hasPrivate bool
+	d.root.walkKeys(func(k *keyExpr) {
+		hasPrivate = hasPrivate || k.hasPrivateKey()
+	})
+	return hasPrivate
+}
+
+// Script returns the output script of the descriptor at the passed index.  The
+// index is ignored for descriptors which are not ranged.
+func (d *Descriptor) Script(index uint32) ([]byte, error) {
+	return d.root.pkScript(index, d.net)
+}
+
+// Address returns the address of the output script of the descriptor at the
+// passed index.  ErrNoAddress is returned for scripts without an address, such
+// as pk() and bare multisig.
+func (d *Descriptor) Address(index uint32) (ltcutil.Address, error) {
+	return d.root.address(index, d.net)
+}
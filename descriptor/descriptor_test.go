@@ -0,0 +1,317 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package descriptor
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
+)
+
+const (
+	// BIP 32 test vector 1 master keys, which use the same version bytes
+	// as the main network.
+	testXprv = "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqj" +
+		"iChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"
+	testXpub = "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY" +
+		"2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8"
+
+	// Public keys of m, m/0', and m/0'/1 derived from the keys above.
+	testPubM = "0339a36013301597daef41fbe593a02cc513d0b55527ec2df105" +
+		"0e2e8ff49c85c2"
+	testPubM0H = "035a784662a4a20a65bf6aab9ae98a6c068a81c52e4b032c0fb5" +
+		"400c706cfccc56"
+	testPubM0H1 = "03501e454bf00751f24b1b489aa925215d66af2234e3891c3b21" +
+		"a52bedb3cd711c"
+)
+
+// TestChecksum ensures descriptor checksums match the BIP 380 test vectors.
+func TestChecksum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc     string
+		checksum string
+	}{
+		{"raw(deadbeef)", "89f8spxm"},
+		{"pkh(02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac0" +
+			"9b95c709ee5)", "8fhd9pwu"},
+	}
+
+	for _, test := range tests {
+		checksum, err := Checksum(test.desc)
+		if err != nil {
+			t.Errorf("Checksum(%q): unexpected error: %v", test.desc,
+				err)
+			continue
+		}
+		if checksum != test.checksum {
+			t.Errorf("Checksum(%q): got %s, want %s", test.desc,
+				checksum, test.checksum)
+			continue
+		}
+
+		desc := test.desc + "#" + test.checksum
+		if _, err := Parse(desc, true, &chaincfg.MainNetParams); err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", desc, err)
+		}
+	}
+
+	// Characters outside of the descriptor character set are rejected.
+	if _, err := Checksum("raw(deadbeef)\n"); err == nil {
+		t.Error("Checksum: expected error for invalid character")
+	}
+
+	// Checksums must match and may be required.
+	_, err := Parse("raw(deadbeef)#89f8spxx", false, &chaincfg.MainNetParams)
+	if !errors.Is(err, ErrBadChecksum) {
+		t.Errorf("Parse: got error %v, want %v", err, ErrBadChecksum)
+	}
+	_, err = Parse("raw(deadbeef)", true, &chaincfg.MainNetParams)
+	if !errors.Is(err, ErrMissingChecksum) {
+		t.Errorf("Parse: got error %v, want %v", err, ErrMissingChecksum)
+	}
+}
+
+// TestDescriptorScripts ensures descriptors produce the expected scripts and
+// addresses.
+func TestDescriptorScripts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		desc    string
+		index   uint32
+		script  string
+		addr    string
+		isRange bool
+		hasPriv bool
+	}{
+		{
+			name:   "pk",
+			desc:   "pk(" + testPubM + ")",
+			script: "21" + testPubM + "ac",
+		},
+		{
+			name:   "pkh xpub",
+			desc:   "pkh(" + testXpub + ")",
+			script: "76a9143442193e1bb70916e914552172cd4e2dbc9df81188ac",
+			addr:   "D9uQrqyJ7Guz3aHVTTcxVhNnthobME3o4w",
+		},
+		{
+			name:    "wpkh xprv hardened path",
+			desc:    "wpkh(" + testXprv + "/0h/1)",
+			script:  "0014bef5a2f9a56a94aab12459f72ad9cf8cf19c7bbe",
+			addr:    "dsv1qhm6697d9d2224vfyt8mj4kw03ncec7a7s2u8dp",
+			hasPriv: true,
+		},
+		{
+			name:    "sh wpkh ranged",
+			desc:    "sh(wpkh([d34db33f/49'/0'/0']" + testXprv + "/0'/*))",
+			index:   1,
+			script:  "a91486cc442a97817c245ce90ed0d31d6dbcde3841f987",
+			addr:    "3DymAvEWH38HuzHZ3VwLus673bNZnYwNXu",
+			isRange: true,
+			hasPriv: true,
+		},
+		{
+			name: "wsh multi",
+			desc: "wsh(multi(1," + testPubM + "," + testPubM0H + "))",
+			script: "0020c1235d25e0569950ede858531af2943289644d2f2319d5" +
+				"9068af8185a9c00c34",
+			addr: "dsv1qcy346f0q26v4pm0gtpf34u55x2ykgnf0yvvatyrg47qct2wq" +
+				"ps6qsr37hx",
+		},
+		{
+			name: "wsh sortedmulti",
+			desc: "wsh(sortedmulti(1," + testPubM0H + "," + testPubM +
+				"))",
+			script: "0020c1235d25e0569950ede858531af2943289644d2f2319d5" +
+				"9068af8185a9c00c34",
+			addr: "dsv1qcy346f0q26v4pm0gtpf34u55x2ykgnf0yvvatyrg47qct2wq" +
+				"ps6qsr37hx",
+		},
+		{
+			name:   "multi",
+			desc:   "multi(1," + testPubM + "," + testPubM0H1 + ")",
+			script: "5121" + testPubM + "21" + testPubM0H1 + "52ae",
+		},
+		{
+			// BIP 86 test vector for m/86'/0'/0'/0/0.
+			name: "tr",
+			desc: "tr(cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223" +
+				"c6fc5d7cd6fc115)",
+			script: "5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc48705" +
+				"3f1dc6880949dc684c",
+			addr: "dsv1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwu" +
+				"dpxqte5dxy",
+		},
+		{
+			name:   "raw",
+			desc:   "raw(deadbeef)",
+			script: "deadbeef",
+		},
+		{
+			name:   "addr",
+			desc:   "addr(D9uQrqyJ7Guz3aHVTTcxVhNnthobME3o4w)",
+			script: "76a9143442193e1bb70916e914552172cd4e2dbc9df81188ac",
+			addr:   "D9uQrqyJ7Guz3aHVTTcxVhNnthobME3o4w",
+		},
+	}
+
+	for _, test := range tests {
+		desc, err := Parse(test.desc, false, &chaincfg.MainNetParams)
+		if err != nil {
+			t.Errorf("%s: unexpected parse error: %v", test.name, err)
+			continue
+		}
+
+		if desc.IsRange() != test.isRange {
+			t.Errorf("%s: IsRange got %v, want %v", test.name,
+				desc.IsRange(), test.isRange)
+		}
+		if desc.HasPrivateKeys() != test.hasPriv {
+			t.Errorf("%s: HasPrivateKeys got %v, want %v", test.name,
+				desc.HasPrivateKeys(), test.hasPriv)
+		}
+
+		script, err := desc.Script(test.index)
+		if err != nil {
+			t.Errorf("%s: unexpected script error: %v", test.name, err)
+			continue
+		}
+		if hex.EncodeToString(script) != test.script {
+			t.Errorf("%s: script got %x, want %s", test.name, script,
+				test.script)
+		}
+
+		addr, err := desc.Address(test.index)
+		switch {
+		case test.addr == "" && err == nil:
+			t.Errorf("%s: expected no address, got %v", test.name,
+				addr)
+		case test.addr != "" && err != nil:
+			t.Errorf("%s: unexpected address error: %v", test.name,
+				err)
+		case test.addr != "" && addr.EncodeAddress() != test.addr:
+			t.Errorf("%s: address got %v, want %v", test.name,
+				addr.EncodeAddress(), test.addr)
+		}
+
+		// The canonical form must parse back to the same script when
+		// it has no hardened derivation from an extended public key.
+		if test.hasPriv {
+			continue
+		}
+		canonical, err := Parse(desc.String(), true,
+			&chaincfg.MainNetParams)
+		if err != nil {
+			t.Errorf("%s: unexpected error parsing %q: %v", test.name,
+				desc.String(), err)
+			continue
+		}
+		script2, err := canonical.Script(test.index)
+		if err != nil || hex.EncodeToString(script2) != test.script {
+			t.Errorf("%s: canonical script got %x, want %s",
+				test.name, script2, test.script)
+		}
+	}
+}
+
+// TestDescriptorString ensures descriptors are returned in canonical form.
+func TestDescriptorString(t *testing.T) {
+	t.Parallel()
+
+	desc, err := Parse("sh(wpkh([D34DB33F/49h/0h/0h]"+testXprv+"/0h/*h))",
+		false, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+
+	want, _ := AddChecksum("sh(wpkh([d34db33f/49'/0'/0']" + testXpub +
+		"/0'/*'))")
+	if got := desc.String(); got != want {
+		t.Errorf("String: got %s, want %s", got, want)
+	}
+
+	want, _ = AddChecksum("sh(wpkh([d34db33f/49'/0'/0']" + testXprv +
+		"/0'/*'))")
+	got, err := desc.PrivateString()
+	if err != nil || got != want {
+		t.Errorf("PrivateString: got %s (err %v), want %s", got, err,
+			want)
+	}
+
+	// Script trees are preserved.
+	tr := "tr(" + testPubM + ",{pk(" + testPubM0H + "),pk(" +
+		testPubM0H1 + ")})"
+	desc, err = Parse(tr, false, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	want, _ = AddChecksum(tr)
+	if got := desc.String(); got != want {
+		t.Errorf("String: got %s, want %s", got, want)
+	}
+	script, err := desc.Script(0)
+	if err != nil || len(script) != 34 {
+		t.Errorf("Script: got %x (err %v), want taproot script",
+			script, err)
+	}
+}
+
+// TestParseErrors ensures invalid descriptors are rejected.
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	uncompressed := "04" + strings.Repeat("0", 128)
+
+	// Create an extended key using the test network version bytes.
+	xpub, err := hdkeychain.NewKeyFromString(testXpub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testTpub, err := xpub.CloneWithVersion(
+		chaincfg.TestNet4Params.HDPublicKeyID[:],
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		desc string
+	}{
+		{"unknown function", "foo(" + testPubM + ")"},
+		{"missing paren", "pkh(" + testPubM},
+		{"nested sh", "sh(sh(pk(" + testPubM + ")))"},
+		{"wpkh in wsh", "wsh(wpkh(" + testPubM + "))"},
+		{"tr not top level", "sh(tr(" + testPubM + "))"},
+		{"uncompressed in wpkh", "wpkh(" + uncompressed + ")"},
+		{"hardened from xpub", "pkh(" + testXpub + "/0'/*)"},
+		{"hardened range from xpub", "pkh(" + testXpub + "/*')"},
+		{"threshold too high", "multi(3," + testPubM + "," +
+			testPubM0H + ")"},
+		{"threshold zero", "multi(0," + testPubM + ")"},
+		{"bad origin", "pkh([d34db33/0]" + testPubM + ")"},
+		{"wrong network", "pkh(" + testTpub.String() + ")"},
+		{"tree branch arity", "tr(" + testPubM + ",{pk(" + testPubM0H +
+			")})"},
+		{"multi in tree", "tr(" + testPubM + ",multi(1," + testPubM0H +
+			"))"},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.desc, false, &chaincfg.MainNetParams)
+		if err == nil {
+			t.Errorf("%s: expected error parsing %q", test.name,
+				test.desc)
+		}
+	}
+}
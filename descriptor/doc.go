@@ -0,0 +1,39 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package descriptor implements parsing and derivation of output script
+descriptors as described by BIP 380 through BIP 386.
+
+An output descriptor is a human readable language describing a set of output
+scripts, such as pkh(KEY) or wsh(multi(2,KEY,KEY)).  Keys may be given as hex
+encoded public keys, WIF encoded private keys, or BIP 32 extended keys with an
+optional derivation path.  An extended key path ending in /* makes the
+descriptor ranged, in which case a separate script is produced for each child
+index.
+
+# Supported Expressions
+
+The following script expressions are supported:
+
+  - pk(KEY), pkh(KEY), wpkh(KEY)
+  - sh(SCRIPT), wsh(SCRIPT)
+  - multi(k,KEY,...,KEY), sortedmulti(k,KEY,...,KEY)
+  - tr(KEY) and tr(KEY,TREE), where the leaves of TREE are pk(KEY)
+  - raw(HEX), addr(ADDRESS)
+
+# Checksums
+
+A descriptor may be followed by a # and an eight character checksum which
+protects against typing errors.  The Checksum function computes the checksum
+of a descriptor and Parse verifies it when present.
+
+# Networks
+
+Extended keys, WIF private keys, and addresses are only accepted when their
+version bytes match the network parameters passed to Parse.  Extended private
+keys are converted to their public form using the HD key IDs registered in the
+chaincfg package.
+*/
+package descriptor
@@ -0,0 +1,412 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package descriptor
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/btcec/v2/schnorr"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
+)
+
+// pubKeyBytesLenUncompressed is the length of a serialized uncompressed public
+// key.
+const pubKeyBytesLenUncompressed = 65
+
+// scriptContext identifies where a key or script expression appears, which
+// determines the forms of keys and scripts that are permitted.
+type scriptContext int
+
+const (
+	// contextTop is the top level of a descriptor.
+	contextTop scriptContext = iota
+
+	// contextP2SH is inside sh().
+	contextP2SH
+
+	// contextP2WSH is inside wsh().
+	contextP2WSH
+
+	// contextP2WPKH is inside wpkh().  It is only used for keys.
+	contextP2WPKH
+
+	// contextTapscript is inside tr(), either as the internal key or as
+	// part of a script tree leaf.
+	contextTapscript
+)
+
+// isWitness returns whether keys in the context must be compressed.
+func (c scriptContext) isWitness() bool {
+	return c == contextP2WSH || c == contextP2WPKH || c == contextTapscript
+}
+
+// rangeType describes whether, and how, an extended key is ranged.
+type rangeType int
+
+const (
+	// rangeNone indicates the key is not ranged.
+	rangeNone rangeType = iota
+
+	// rangeNormal indicates the key ends in /* and children are derived
+	// using normal derivation.
+	rangeNormal
+
+	// rangeHardened indicates the key ends in /*' and children are
+	// derived using hardened derivation.
+	rangeHardened
+)
+
+// keyExpr is a parsed key expression.  Exactly one of pubKey, wif, or extKey
+// is set.
+type keyExpr struct {
+	// origin is the canonical key origin information, including the
+	// enclosing brackets, or the empty string when there is none.
+	origin string
+
+	// pubKey is set for hex encoded public keys.
+	pubKey *btcec.PublicKey
+
+	// compressed indicates whether a hex or WIF encoded key is serialized
+	// in compressed form.
+	compressed bool
+
+	// xOnly indicates the key was given as a 32 byte x-only public key.
+	xOnly bool
+
+	// wif is set for WIF encoded private keys.
+	wif *ltcutil.WIF
+
+	// extKey, path, and rng are set for extended keys.
+	extKey *hdkeychain.ExtendedKey
+	path   []uint32
+	rng    rangeType
+}
+
+// parsePathElement parses a single BIP 32 path element, where a trailing ' or
+// h marks a hardened step.
+func parsePathElement(s string) (uint32, error) {
+	var offset uint32
+	if strings.HasSuffix(s, "'") || strings.HasSuffix(s, "h") {
+		s = s[:len(s)-1]
+		offset = hdkeychain.HardenedKeyStart
+	}
+	if s == "" || s[0] == '+' || s[0] == '-' {
+		return 0, fmt.Errorf("invalid key path element %q", s)
+	}
+	n, err := strconv.ParseUint(s, 10, 31)
+	if err != nil {
+		return 0, fmt.Errorf("key path element %q is out of range", s)
+	}
+	return uint32(n) + offset, nil
+}
+
+// formatPath returns the canonical string form of a BIP 32 path, including a
+// leading / for every element.
+func formatPath(path []uint32) string {
+	var b strings.Builder
+	for _, step := range path {
+		b.WriteByte('/')
+		if step >= hdkeychain.HardenedKeyStart {
+			b.WriteString(strconv.FormatUint(uint64(
+				step-hdkeychain.HardenedKeyStart), 10))
+			b.WriteByte('\'')
+			continue
+		}
+		b.WriteString(strconv.FormatUint(uint64(step), 10))
+	}
+	return b.String()
+}
+
+// parseOrigin parses key origin information of the form
+// [fingerprint/path...] and returns it in canonical form.
+func parseOrigin(s string) (string, error) {
+	parts := strings.Split(s, "/")
+	if len(parts[0]) != 8 {
+		return "", fmt.Errorf("fingerprint %q is not 4 bytes", parts[0])
+	}
+	fingerprint, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("fingerprint %q is not hex", parts[0])
+	}
+
+	path := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		step, err := parsePathElement(part)
+		if err != nil {
+			return "", err
+		}
+		path = append(path, step)
+	}
+
+	return "[" + hex.EncodeToString(fingerprint) + formatPath(path) + "]",
+		nil
+}
+
+// parseKey parses a key expression appearing in the passed context.
+func parseKey(s string, ctx scriptContext,
+	net *chaincfg.Params) (*keyExpr, error) {
+
+	k := &keyExpr{}
+
+	// Split off the key origin, if any.
+	if strings.HasPrefix(s, "[") {
+		end := strings.IndexByte(s, ']')
+		if end == -1 {
+			return nil, fmt.Errorf("key origin start '[' has no " +
+				"matching ']'")
+		}
+		origin, err := parseOrigin(s[1:end])
+		if err != nil {
+			return nil, err
+		}
+		k.origin = origin
+		s = s[end+1:]
+	}
+	if strings.ContainsAny(s, "[]") {
+		return nil, fmt.Errorf("multiple key origins in %q", s)
+	}
+
+	parts := strings.Split(s, "/")
+	if len(parts) == 1 {
+		// Hex encoded public keys.
+		if b, err := hex.DecodeString(s); err == nil {
+			if err := k.setPubKey(b, ctx); err != nil {
+				return nil, err
+			}
+			return k, nil
+		}
+
+		// WIF encoded private keys.
+		if wif, err := ltcutil.DecodeWIF(s); err == nil {
+			if !wif.IsForNet(net) {
+				return nil, fmt.Errorf("private key is not " +
+					"for this network")
+			}
+			if !wif.CompressPubKey && ctx.isWitness() {
+				return nil, ErrUncompressedKey
+			}
+			k.wif = wif
+			k.compressed = wif.CompressPubKey
+			return k, nil
+		}
+	}
+
+	extKey, err := hdkeychain.NewKeyFromString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("key %q is not valid: %v", parts[0], err)
+	}
+	if !extKey.IsForNet(net) {
+		return nil, fmt.Errorf("extended key %q is not for this "+
+			"network", parts[0])
+	}
+	k.extKey = extKey
+
+	path := parts[1:]
+	if len(path) > 0 {
+		switch path[len(path)-1] {
+		case "*":
+			k.rng = rangeNormal
+			path = path[:len(path)-1]
+		case "*'", "*h":
+			k.rng = rangeHardened
+			path = path[:len(path)-1]
+		}
+	}
+	k.path = make([]uint32, 0, len(path))
+	for _, part := range path {
+		step, err := parsePathElement(part)
+		if err != nil {
+			return nil, err
+		}
+		if step >= hdkeychain.HardenedKeyStart && !extKey.IsPrivate() {
+			return nil, ErrHardenedFromPublic
+		}
+		k.path = append(k.path, step)
+	}
+	if k.rng == rangeHardened && !extKey.IsPrivate() {
+		return nil, ErrHardenedFromPublic
+	}
+
+	return k, nil
+}
+
+// setPubKey sets the key to the passed serialized public key, which may be
+// compressed, uncompressed, or x-only when in a tapscript context.
+func (k *keyExpr) setPubKey(b []byte, ctx scriptContext) error {
+	switch {
+	case len(b) == 32 && ctx == contextTapscript:
+		pubKey, err := schnorr.ParsePubKey(b)
+		if err != nil {
+			return err
+		}
+		k.pubKey = pubKey
+		k.compressed = true
+		k.xOnly = true
+
+	case len(b) == btcec.PubKeyBytesLenCompressed:
+		pubKey, err := btcec.ParsePubKey(b)
+		if err != nil {
+			return err
+		}
+		k.pubKey = pubKey
+		k.compressed = true
+
+	case len(b) == pubKeyBytesLenUncompressed:
+		if ctx.isWitness() {
+			return ErrUncompressedKey
+		}
+		pubKey, err := btcec.ParsePubKey(b)
+		if err != nil {
+			return err
+		}
+		k.pubKey = pubKey
+
+	default:
+		return fmt.Errorf("public key %x has invalid length %d", b,
+			len(b))
+	}
+
+	return nil
+}
+
+// isRange returns whether the key derives a different public key for every
+// index.
+func (k *keyExpr) isRange() bool {
+	return k.rng != rangeNone
+}
+
+// hasPrivateKey returns whether the key expression includes private key
+// material.
+func (k *keyExpr) hasPrivateKey() bool {
+	return k.wif != nil || (k.extKey != nil && k.extKey.IsPrivate())
+}
+
+// pubKeyAt returns the public key for the passed index.  The index is ignored
+// for keys which are not ranged.
+func (k *keyExpr) pubKeyAt(index uint32) (*btcec.PublicKey, error) {
+	switch {
+	case k.pubKey != nil:
+		return k.pubKey, nil
+
+	case k.wif != nil:
+		return k.wif.PrivKey.PubKey(), nil
+	}
+
+	key := k.extKey
+	for _, step := range k.path {
+		var err error
+		key, err = key.Derive(step)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch k.rng {
+	case rangeNormal:
+		if index >= hdkeychain.HardenedKeyStart {
+			return nil, ErrIndexOutOfRange
+		}
+		child, err := key.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+		key = child
+
+	case rangeHardened:
+		if index >= hdkeychain.HardenedKeyStart {
+			return nil, ErrIndexOutOfRange
+		}
+		child, err := key.Derive(index + hdkeychain.HardenedKeyStart)
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+
+	return key.ECPubKey()
+}
+
+// serialize returns the serialized public key for the passed index in the form
+// used by scripts in the passed context.
+func (k *keyExpr) serialize(index uint32, ctx scriptContext) ([]byte, error) {
+	pubKey, err := k.pubKeyAt(index)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case ctx == contextTapscript:
+		return schnorr.SerializePubKey(pubKey), nil
+
+	// Extended keys are always compressed.
+	case k.extKey == nil && !k.compressed:
+		return pubKey.SerializeUncompressed(), nil
+	}
+
+	return pubKey.SerializeCompressed(), nil
+}
+
+// serializedLen returns the length of the key when serialized for the passed
+// context.
+func (k *keyExpr) serializedLen(ctx scriptContext) int {
+	switch {
+	case ctx == contextTapscript:
+		return schnorr.PubKeyBytesLen
+
+	case k.extKey == nil && !k.compressed:
+		return pubKeyBytesLenUncompressed
+	}
+
+	return btcec.PubKeyBytesLenCompressed
+}
+
+// string returns the canonical form of the key expression.  Private keys are
+// replaced by their public keys unless private is set.
+func (k *keyExpr) string(private bool) (string, error) {
+	var s string
+	switch {
+	case k.pubKey != nil && k.xOnly:
+		s = hex.EncodeToString(schnorr.SerializePubKey(k.pubKey))
+
+	case k.pubKey != nil && k.compressed:
+		s = hex.EncodeToString(k.pubKey.SerializeCompressed())
+
+	case k.pubKey != nil:
+		s = hex.EncodeToString(k.pubKey.SerializeUncompressed())
+
+	case k.wif != nil && private:
+		s = k.wif.String()
+
+	case k.wif != nil:
+		s = hex.EncodeToString(k.wif.SerializePubKey())
+
+	default:
+		key := k.extKey
+		if !private && key.IsPrivate() {
+			// Neutering uses the HD key IDs registered with
+			// chaincfg to determine the public version bytes.
+			var err error
+			key, err = key.Neuter()
+			if err != nil {
+				return "", err
+			}
+		}
+		s = key.String() + formatPath(k.path)
+		switch k.rng {
+		case rangeNormal:
+			s += "/*"
+		case rangeHardened:
+			s += "/*'"
+		}
+	}
+
+	return k.origin + s, nil
+}
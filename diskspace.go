@@ -0,0 +1,76 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+)
+
+const (
+	// lowDiskSpaceThreshold is the free space on the disk the data
+	// directory is on below which a warning is raised.
+	lowDiskSpaceThreshold = 1 << 30 // 1 GiB
+
+	// diskSpaceCheckInterval is the interval at which the free space on
+	// the disk the data directory is on is checked.
+	diskSpaceCheckInterval = 5 * time.Minute
+)
+
+// errDiskSpaceUnsupported is returned by freeDiskSpace on platforms where the
+// free space of a disk can't be determined.
+var errDiskSpaceUnsupported = errors.New("determining the free disk space " +
+	"is not supported on this platform")
+
+// checkDiskSpace raises a warning when the free space on the disk the data
+// directory is on is below the low disk space threshold and clears it
+// otherwise.
+func (s *server) checkDiskSpace() error {
+	free, err := freeDiskSpace(cfg.DataDir)
+	if err != nil {
+		return err
+	}
+
+	var message string
+	if free < lowDiskSpaceThreshold {
+		message = fmt.Sprintf("Disk space is low, only %d MiB are left "+
+			"for the data directory %s", free>>20, cfg.DataDir)
+	}
+	s.chain.SetWarning(blockchain.WarningLowDiskSpace, message)
+	return nil
+}
+
+// diskSpaceHandler periodically checks the free space on the disk the data
+// directory is on so the operator is warned before the node runs out of it.
+//
+// It must be run as a goroutine.
+func (s *server) diskSpaceHandler() {
+	defer s.wg.Done()
+
+	if err := s.checkDiskSpace(); err != nil {
+		srvrLog.Debugf("Unable to check the free disk space: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(diskSpaceCheckInterval)
+	defer ticker.Stop()
+
+out:
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.checkDiskSpace(); err != nil {
+				srvrLog.Warnf("Unable to check the free disk "+
+					"space: %v", err)
+			}
+
+		case <-s.quit:
+			break out
+		}
+	}
+}
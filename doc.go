@@ -17,6 +17,11 @@ parsed when ltcd starts up.  By default, the configuration file is located at
 on Windows.  The -C (--configfile) flag, as shown below, can be used to override
 this location.
 
+All options may also be set through environment variables named after the long
+form of the option in upper case with the LTCD_ prefix, such as LTCD_RPCUSER for
+--rpcuser.  Environment variables take precedence over the configuration file
+while the command line options take precedence over both.
+
 Usage:
 
 	ltcd [OPTIONS]
@@ -46,9 +51,25 @@ Application Options:
 	                            transactions when creating a block (default:
 	                            50000)
 	    --blocksonly            Do not accept transactions from remote peers.
+	    --coinbasereservedsize= Number of bytes to reserve in the signature
+	                            script of the coinbase of block templates for
+	                            the extra nonces of mining pools
 	-C, --configfile=           Path to configuration file
 	    --connect=              Connect only to the specified peers at startup
 	    --cpuprofile=           Write CPU profile to the specified file
+	    --dandelion             Relay locally submitted transactions to a single
+	                            outbound peer along a Dandelion++ stem before
+	                            they are broadcast to all peers to hide their
+	                            origin
+	    --dandelionembargo=     Minimum time a transaction stays in the
+	                            Dandelion++ stem before it is broadcast to all
+	                            peers unless another peer announced it -- A
+	                            random delay of up to the same duration is added
+	                            (default: 30s)
+	    --dandelionepoch=       Minimum time the outbound peer chosen as the
+	                            Dandelion++ stem is kept before a new one is
+	                            chosen at random -- A random delay of up to the
+	                            same duration is added (default: 10m0s)
 	-b, --datadir=              Directory to store data
 	    --dbtype=               Database backend to use for the Block Chain
 	                            (default: ffldb)
@@ -66,16 +87,51 @@ Application Options:
 	                            then exits.
 	    --droptxindex           Deletes the hash-based transaction index from the
 	                            database on start up and then exits.
+	    --dropwatchonly         Deletes the watched scripts and their unspent
+	                            outputs from the database on start up and then
+	                            exits.
+	    --dumpconfig            Write the configuration merged from the config
+	                            file, environment variables and command line
+	                            options to stdout in the format of the config
+	                            file and exit -- Passwords are masked
+	    --export-blocks=        Write the blocks of the main chain to the
+	                            specified file in the bootstrap.dat format on
+	                            start up and then exit
+	    --export-height=        Height of the last block written by
+	                            --export-blocks (default: the current best
+	                            height)
 	    --externalip=           Add an ip to the list of local addresses we claim
 	                            to listen on to peers
+	    --fasttemplate          Serve an empty block template to
+	                            getblocktemplate callers as soon as the best
+	                            chain changes and notify long polling callers
+	                            once the full template with transactions is
+	                            ready
 	    --generate              Generate (mine) litecoins using the CPU
+	    --getwork               Enable the legacy getwork RPC for mining proxies
+	                            which cannot speak getblocktemplate or stratum
+	                            -- Requires --miningaddr
 	    --limitfreerelay=       Limit relay of transactions with no transaction
 	                            fee to the given amount in thousands of bytes per
 	                            minute (default: 15)
-	    --listen=               Add an interface/port to listen for connections
-	                            (default all interfaces port: 9333, testnet:
-	                            19335, signet: 39333)
+	    --listen=               Add an interface/port to listen for connections,
+	                            optionally preceded by the comma separated
+	                            services {network, witness, bloom, cf,
+	                            networklimited, mweblightclient, mweb} or none
+	                            advertised to its peers (default all interfaces
+	                            port: 9333, testnet: 19335, signet: 39333,
+	                            services: all of the node, eg.
+	                            witness,networklimited@127.0.0.1:9335)
+	    --loadblock=            Import the blocks from the specified
+	                            bootstrap.dat file on start up -- May be
+	                            specified multiple times
 	    --logdir=               Directory to log output
+	    --logformat=            Format of the log output {text, json} (default:
+	                            text)
+	    --logmaxrolls=          Maximum number of rotated log files to keep
+	                            (default: 3)
+	    --logmaxsize=           Maximum size in MiB of the log file before it is
+	                            rotated (default: 10)
 	    --maxorphantx=          Max number of orphan transactions to keep in
 	                            memory (default: 100)
 	    --maxpeers=             Max number of inbound and outbound peers
@@ -84,8 +140,14 @@ Application Options:
 	                            addresses to use for generated blocks -- At least
 	                            one address is required if the generate option is
 	                            set
+	    --minprotocolversion=   Minimum protocol version accepted from peers
+	                            (default: the minimum of the active network)
 	    --minrelaytxfee=        The minimum transaction fee in LTC/kB to be
 	                            considered a non-zero fee. (default: 1e-05)
+	    --netproxy=             Connect to the ipv4 or ipv6 network via its own
+	                            SOCKS5 proxy, or directly with 'direct', instead
+	                            of --proxy (eg. ipv6=127.0.0.1:9050 or
+	                            ipv4=direct) -- May be specified multiple times
 	    --nobanning             Disable banning of misbehaving peers
 	    --nocfilters            Disable committed filtering (CF) support
 	    --nocheckpoints         Disable built-in checkpoints.  Don't do this
@@ -96,13 +158,15 @@ Application Options:
 	                            --connect or --proxy options are used without
 	                            also specifying listen interfaces via --listen
 	    --noonion               Disable connecting to tor hidden services
-	    --nopeerbloomfilters    Disable bloom filtering support
+	    --nopeerbloomfilters    DEPRECATED: Bloom filtering support is disabled
+	                            by default -- use --peerbloomfilters to enable
+	                            it
 	    --norelaypriority       Do not require free or low-fee transactions to
 	                            have high priority for relaying
 	    --norpc                 Disable built-in RPC server -- NOTE: The RPC
 	                            server is disabled by default if no
-	                            rpcuser/rpcpass or rpclimituser/rpclimitpass is
-	                            specified
+	                            rpcuser/rpcpass, rpclimituser/rpclimitpass or
+	                            rpcclientca is specified
 	    --notls                 Disable TLS for the RPC server -- NOTE: This is
 	                            only allowed if the RPC server is bound to
 	                            localhost
@@ -110,17 +174,45 @@ Application Options:
 	                            (eg. 127.0.0.1:9050)
 	    --onionpass=            Password for onion proxy server
 	    --onionuser=            Username for onion proxy server
+	    --onlynet=              Only make outbound connections, including to
+	                            --addpeer and --connect peers, to the given
+	                            network {ipv4, ipv6, onion} -- May be specified
+	                            multiple times
+	    --peerbloomfilters      Enable BIP0037 bloom filtering support for SPV
+	                            peers
+	    --preferredservices=    Comma separated services {network, witness,
+	                            bloom, cf, networklimited, mweblightclient,
+	                            mweb} outbound connections favor peers
+	                            advertising (default: the preferences of the
+	                            active network)
 	    --profile=              Enable HTTP profiling on given port -- NOTE port
 	                            must be between 1024 and 65536
 	    --proxy=                Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)
 	    --proxypass=            Password for proxy server
 	    --proxyuser=            Username for proxy server
+	    --prunestaleforks=      Remove side chains whose tips are more than the
+	                            given number of blocks behind the main chain
+	                            from the block index along with their blocks
+	                            (minimum value of 288, default value of 0 will
+	                            keep all side chains)
+	    --prunestaleforkquota=  Disk space in MiB the deleted blocks of removed
+	                            side chains may keep using before the block
+	                            files holding them are rewritten to release it
+	                            -- Only used with --prunestaleforks (default:
+	                            128)
 	    --regtest               Use the regression test network
 	    --rejectnonstd          Reject non-standard transactions regardless of
 	                            the default settings for the active network.
 	    --relaynonstd           Relay non-standard transactions regardless of the
 	                            default settings for the active network.
+	    --requiredservices=     Comma separated services {network, witness,
+	                            bloom, cf, networklimited, mweblightclient,
+	                            mweb} outbound peers must advertise (default:
+	                            the requirements of the active network)
 	    --rpccert=              File containing the certificate file
+	    --rpcclientca=          File containing the CA certificates RPC client
+	                            certificates must be signed by -- Requires RPC
+	                            clients to authenticate with a certificate
 	    --rpckey=               File containing the certificate key
 	    --rpclimitpass=         Password for limited RPC connections
 	    --rpclimituser=         Username for limited RPC connections
@@ -130,15 +222,36 @@ Application Options:
 	                            connections (default: 10)
 	    --rpcmaxconcurrentreqs= Max number of concurrent RPC requests that may be
 	                            processed concurrently (default: 20)
+	    --rpcmaxglobalreqs=     Max number of RPC requests processed concurrently
+	                            across all clients, mining requests excluded --
+	                            0 for no limit
 	    --rpcmaxwebsockets=     Max number of RPC websocket connections (default:
 	                            25)
+	    --rpcratelimit=         Max number of RPC requests per second from a
+	                            single client host -- 0 for no limit
+	    --rpcrateburst=         Max number of RPC requests a single client host
+	                            may send at once when rpcratelimit is set
+	                            (default: 20)
 	    --rpcquirks             Mirror some JSON-RPC quirks of Litecoin Core --
 	                            NOTE: Discouraged unless interoperability issues
 	                            need to be worked around
 	-P, --rpcpass=              Password for RPC connections
 	-u, --rpcuser=              Username for RPC connections
+	    --shutdowntimeout=      Maximum time to wait for the server to stop on
+	                            shutdown before the database is flushed and
+	                            closed anyway -- Valid time units are {s, m, h}.
+	                            Set to 0 to wait indefinitely (default: 20s)
 	    --sigcachemaxsize=      The maximum number of entries in the signature
 	                            verification cache (default: 100000)
+	    --scriptcachemaxsize=   The maximum number of entries in the validated
+	                            script cache (default: 100000)
+	    --signetgenesisnonce=   Nonce of the genesis block of the custom signet
+	                            network as output by gensignet
+	    --signetgenesistime=    Unix timestamp of the genesis block of the custom
+	                            signet network as output by gensignet
+	    --signetminingkey=      Add the specified WIF private key to the keys used
+	                            to sign the blocks generated on the signet
+	                            network -- May be specified multiple times
 	    --simnet                Use the simulation test network
 	    --testnet               Use the test network
 	    --torisolation          Enable Tor stream isolation by randomizing user
@@ -152,8 +265,14 @@ Application Options:
 	                            for more information.
 	    --upnp                  Use UPnP to map our listening port outside of NAT
 	-V, --version               Display version information and exit
-	    --whitelist=            Add an IP network or IP that will not be banned.
-	                            (eg. 192.168.1.0/24 or ::1)
+	    --watchonly             Track the unspent outputs paying to the scripts
+	                            registered with the importwatchonly RPC
+	    --whitelist=            Add an IP network or IP with optional comma
+	                            separated permissions (noban, forcerelay,
+	                            mempool, bloomfilter, all) granted to its peers
+	                            -- Defaults to noban when no permissions are
+	                            given (eg. 192.168.1.0/24, ::1, or
+	                            forcerelay,noban@10.0.0.0/8)
 
 Help Options:
 
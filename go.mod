@@ -12,7 +12,8 @@ require (
 	github.com/jrick/logrotate v1.0.0
 	github.com/ltcsuite/ltcd/btcec/v2 v2.3.2
 	github.com/ltcsuite/ltcd/chaincfg/chainhash v1.0.2
-	github.com/ltcsuite/ltcd/ltcutil v1.1.4-0.20250505084124-c37ac1524e04
+	github.com/ltcsuite/ltcd/ltcutil v1.2.0
+	github.com/ltcsuite/ltcd/ltcutil/psbt v1.2.0
 	github.com/ltcsuite/secp256k1 v0.1.1
 	github.com/stretchr/testify v1.8.3
 	github.com/syndtr/goleveldb v1.0.1-0.20210819022825-2ae1ddf74ef7
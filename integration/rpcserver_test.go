@@ -21,6 +21,8 @@ import (
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/integration/rpctest"
 	"github.com/ltcsuite/ltcd/rpcclient"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
 )
 
 func testGetBestBlock(r *rpctest.Harness, t *testing.T) {
@@ -289,6 +291,66 @@ func testGetNetworkHashPS3(r *rpctest.Harness, t *testing.T) {
 	}
 }
 
+func testGenerateBlock(r *rpctest.Harness, t *testing.T) {
+	bestHash, _, err := r.Client.GetBestBlock()
+	if err != nil {
+		t.Fatalf("Call to `getbestblock` failed: %v", err)
+	}
+	bestHeader, err := r.Client.GetBlockHeader(bestHash)
+	if err != nil {
+		t.Fatalf("Call to `getblockheader` failed: %v", err)
+	}
+
+	// Generate a block with a transaction spending a mature coinbase and a
+	// timestamp ahead of the current time.
+	addr, err := r.NewAddress()
+	if err != nil {
+		t.Fatalf("Unable to generate new address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("Unable to create pkScript: %v", err)
+	}
+	tx, err := r.CreateTransaction(
+		[]*wire.TxOut{wire.NewTxOut(1e8, pkScript)}, 10, true,
+	)
+	if err != nil {
+		t.Fatalf("Unable to create transaction: %v", err)
+	}
+	timestamp := bestHeader.Timestamp.Add(time.Hour).Unix()
+	blockHash, err := r.Client.GenerateBlock(addr, []*wire.MsgTx{tx},
+		&timestamp)
+	if err != nil {
+		t.Fatalf("Call to `generateblock` failed: %v", err)
+	}
+
+	block, err := r.Client.GetBlock(blockHash)
+	if err != nil {
+		t.Fatalf("Call to `getblock` failed: %v", err)
+	}
+	if block.Header.PrevBlock != *bestHash {
+		t.Fatalf("Generated block does not extend the best chain")
+	}
+	if block.Header.Timestamp.Unix() != timestamp {
+		t.Fatalf("Generated block has timestamp %v, want %v",
+			block.Header.Timestamp.Unix(), timestamp)
+	}
+	if len(block.Transactions) != 2 ||
+		block.Transactions[1].TxHash() != tx.TxHash() {
+
+		t.Fatalf("Generated block does not contain exactly the given " +
+			"transaction")
+	}
+
+	// A timestamp which is not after the median time past is rejected.
+	timestamp = bestHeader.Timestamp.Add(-time.Hour).Unix()
+	_, err = r.Client.GenerateBlock(addr, nil, &timestamp)
+	if err == nil {
+		t.Fatalf("Generated a block timestamped before the median time " +
+			"past")
+	}
+}
+
 var rpcTestCases = []rpctest.HarnessTestCase{
 	testGetBestBlock,
 	testGetBlockCount,
@@ -297,6 +359,7 @@ var rpcTestCases = []rpctest.HarnessTestCase{
 	testGetNetworkHashPS,
 	testGetNetworkHashPS2,
 	testGetNetworkHashPS3,
+	testGenerateBlock,
 }
 
 var primaryHarness *rpctest.Harness
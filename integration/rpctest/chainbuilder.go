@@ -0,0 +1,348 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpctest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/mining"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// builderNode is a block of a chain assembled by a ChainBuilder.  It
+// implements the blockchain.HeaderCtx interface so the required difficulty of
+// its children can be calculated with the same code used by the node.
+type builderNode struct {
+	parent *builderNode
+	block  *ltcutil.Block
+	height int32
+}
+
+// Ensure builderNode implements the blockchain.HeaderCtx interface.
+var _ blockchain.HeaderCtx = (*builderNode)(nil)
+
+// Height returns the height of the block.
+//
+// This is part of the blockchain.HeaderCtx interface.
+func (n *builderNode) Height() int32 {
+	return n.height
+}
+
+// Bits returns the difficulty bits of the block.
+//
+// This is part of the blockchain.HeaderCtx interface.
+func (n *builderNode) Bits() uint32 {
+	return n.block.MsgBlock().Header.Bits
+}
+
+// Timestamp returns the timestamp of the block as a unix timestamp.
+//
+// This is part of the blockchain.HeaderCtx interface.
+func (n *builderNode) Timestamp() int64 {
+	return n.block.MsgBlock().Header.Timestamp.Unix()
+}
+
+// Parent returns the parent of the block, or nil for the genesis block.
+//
+// This is part of the blockchain.HeaderCtx interface.
+func (n *builderNode) Parent() blockchain.HeaderCtx {
+	// Avoid returning a typed nil for the genesis block.
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+// RelativeAncestorCtx returns the ancestor of the block the passed distance
+// before it, or nil when there is no such ancestor.
+//
+// This is part of the blockchain.HeaderCtx interface.
+func (n *builderNode) RelativeAncestorCtx(distance int32) blockchain.HeaderCtx {
+	ancestor := n.ancestor(n.height - distance)
+	if ancestor == nil {
+		return nil
+	}
+	return ancestor
+}
+
+// ancestor returns the ancestor of the block at the passed height, or nil when
+// the height is out of range.
+func (n *builderNode) ancestor(height int32) *builderNode {
+	if height < 0 || height > n.height {
+		return nil
+	}
+
+	node := n
+	for node != nil && node.height != height {
+		node = node.parent
+	}
+	return node
+}
+
+// ChainBuilder deterministically assembles chains of valid blocks for the
+// network described by its chain parameters without the need for a running
+// node.  Every block pays its subsidy to the same mining address and is
+// timestamped one target block time after its parent unless stated otherwise,
+// so building the same sequence of blocks always yields the same hashes.
+//
+// The difficulty of every block is calculated with the same algorithms the
+// node uses to validate it, which means the chains honor the heights at which
+// the LWMA and ASERT difficulty algorithms activate.  Note that solving the
+// blocks is only practical on networks with a trivial proof of work limit such
+// as regtest and simnet.
+//
+// The blocks may be submitted to the nodes of test harnesses via
+// Harness.SubmitBlocks.  Forks are built by calling Fork, which allows
+// reorganizations to be tested deterministically as well.
+//
+// A ChainBuilder is NOT safe for concurrent access.
+type ChainBuilder struct {
+	params     *chaincfg.Params
+	miningAddr ltcutil.Address
+	tip        *builderNode
+
+	// extraNonce is included in the coinbase of every block so the blocks
+	// of forks differ from the blocks of the chain they fork from.  forks
+	// is shared by all builders forked from the same builder so that the
+	// extra nonce of every fork is unique.
+	extraNonce uint64
+	forks      *uint64
+
+	blocksPerRetarget   int32
+	minRetargetTimespan int64
+	maxRetargetTimespan int64
+}
+
+// Ensure ChainBuilder implements the blockchain.ChainCtx interface.
+var _ blockchain.ChainCtx = (*ChainBuilder)(nil)
+
+// NewChainBuilder returns a chain builder which builds on top of the genesis
+// block of the passed network, paying the subsidy of all blocks to the passed
+// mining address.
+func NewChainBuilder(params *chaincfg.Params,
+	miningAddr ltcutil.Address) *ChainBuilder {
+
+	genesis := ltcutil.NewBlock(params.GenesisBlock)
+	genesis.SetHeight(0)
+
+	targetTimespan := int64(params.TargetTimespan / time.Second)
+	targetTimePerBlock := int64(params.TargetTimePerBlock / time.Second)
+	adjustmentFactor := params.RetargetAdjustmentFactor
+	return &ChainBuilder{
+		params:              params,
+		miningAddr:          miningAddr,
+		tip:                 &builderNode{block: genesis},
+		forks:               new(uint64),
+		blocksPerRetarget:   int32(targetTimespan / targetTimePerBlock),
+		minRetargetTimespan: targetTimespan / adjustmentFactor,
+		maxRetargetTimespan: targetTimespan * adjustmentFactor,
+	}
+}
+
+// ChainParams returns the parameters of the network the chain is built for.
+//
+// This is part of the blockchain.ChainCtx interface.
+func (b *ChainBuilder) ChainParams() *chaincfg.Params {
+	return b.params
+}
+
+// BlocksPerRetarget returns the number of blocks between difficulty retargets
+// of the original difficulty algorithm.
+//
+// This is part of the blockchain.ChainCtx interface.
+func (b *ChainBuilder) BlocksPerRetarget() int32 {
+	return b.blocksPerRetarget
+}
+
+// MinRetargetTimespan returns the minimum timespan used by the original
+// difficulty algorithm.
+//
+// This is part of the blockchain.ChainCtx interface.
+func (b *ChainBuilder) MinRetargetTimespan() int64 {
+	return b.minRetargetTimespan
+}
+
+// MaxRetargetTimespan returns the maximum timespan used by the original
+// difficulty algorithm.
+//
+// This is part of the blockchain.ChainCtx interface.
+func (b *ChainBuilder) MaxRetargetTimespan() int64 {
+	return b.maxRetargetTimespan
+}
+
+// VerifyCheckpoint always returns true since the built chains are not checked
+// against checkpoints.
+//
+// This is part of the blockchain.ChainCtx interface.
+func (b *ChainBuilder) VerifyCheckpoint(height int32,
+	hash *chainhash.Hash) bool {
+
+	return true
+}
+
+// FindPreviousCheckpoint always returns nil since the built chains are not
+// checked against checkpoints.
+//
+// This is part of the blockchain.ChainCtx interface.
+func (b *ChainBuilder) FindPreviousCheckpoint() (blockchain.HeaderCtx, error) {
+	return nil, nil
+}
+
+// Tip returns the last block of the chain, which is the genesis block until a
+// block is built.
+func (b *ChainBuilder) Tip() *ltcutil.Block {
+	return b.tip.block
+}
+
+// Height returns the height of the last block of the chain.
+func (b *ChainBuilder) Height() int32 {
+	return b.tip.height
+}
+
+// BlockAt returns the block of the chain at the passed height, or nil when the
+// height is out of range.
+func (b *ChainBuilder) BlockAt(height int32) *ltcutil.Block {
+	node := b.tip.ancestor(height)
+	if node == nil {
+		return nil
+	}
+	return node.block
+}
+
+// Blocks returns the blocks of the chain after the passed height in order,
+// which are the blocks a node at that height needs to catch up with the chain.
+func (b *ChainBuilder) Blocks(afterHeight int32) []*ltcutil.Block {
+	if afterHeight < 0 {
+		afterHeight = 0
+	}
+	if afterHeight >= b.tip.height {
+		return nil
+	}
+
+	blocks := make([]*ltcutil.Block, b.tip.height-afterHeight)
+	for node := b.tip; node.height > afterHeight; node = node.parent {
+		blocks[node.height-afterHeight-1] = node.block
+	}
+	return blocks
+}
+
+// Fork returns a new chain builder whose chain shares the blocks up to and
+// including the passed height with the chain of this builder.  The blocks built
+// by the returned builder differ from the blocks built by this builder even
+// when they contain the same transactions.
+func (b *ChainBuilder) Fork(height int32) (*ChainBuilder, error) {
+	node := b.tip.ancestor(height)
+	if node == nil {
+		return nil, fmt.Errorf("unable to fork at height %d of a chain "+
+			"with height %d", height, b.tip.height)
+	}
+
+	*b.forks++
+	fork := *b
+	fork.tip = node
+	fork.extraNonce = *b.forks
+	return &fork, nil
+}
+
+// NextBlock builds a block including the passed transactions on top of the
+// chain, timestamped one target block time after the current tip, and makes it
+// the new tip.
+func (b *ChainBuilder) NextBlock(txns []*ltcutil.Tx) (*ltcutil.Block, error) {
+	blockTime := b.tip.block.MsgBlock().Header.Timestamp.Add(
+		b.params.TargetTimePerBlock)
+	return b.NextBlockAt(txns, blockTime)
+}
+
+// NextBlockAt builds a block including the passed transactions and timestamped
+// with the passed time on top of the chain and makes it the new tip.  The
+// timestamp is not checked, which allows building chains whose blocks are
+// found faster or slower than the target block time.
+func (b *ChainBuilder) NextBlockAt(txns []*ltcutil.Tx,
+	blockTime time.Time) (*ltcutil.Block, error) {
+
+	height := b.tip.height + 1
+	bits, err := blockchain.CalcNextRequiredDifficultyCtx(b.tip, blockTime, b)
+	if err != nil {
+		return nil, err
+	}
+
+	coinbaseScript, err := standardCoinbaseScript(height, b.extraNonce)
+	if err != nil {
+		return nil, err
+	}
+	coinbaseTx, err := createCoinbaseTx(coinbaseScript, height,
+		b.miningAddr, nil, b.params)
+	if err != nil {
+		return nil, err
+	}
+
+	blockTxns := append([]*ltcutil.Tx{coinbaseTx}, txns...)
+	for _, tx := range txns {
+		if tx.MsgTx().HasWitness() {
+			_ = mining.AddWitnessCommitment(coinbaseTx, blockTxns)
+			break
+		}
+	}
+
+	var block wire.MsgBlock
+	block.Header = wire.BlockHeader{
+		Version:    BlockVersion,
+		PrevBlock:  *b.tip.block.Hash(),
+		MerkleRoot: blockchain.CalcMerkleRoot(blockTxns, false),
+		Timestamp:  blockTime,
+		Bits:       bits,
+	}
+	for _, tx := range blockTxns {
+		if err := block.AddTransaction(tx.MsgTx()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Search the nonces in order rather than in parallel so the same
+	// solution is found every time.
+	target := blockchain.CompactToBig(bits)
+	solved := false
+	for nonce := uint64(0); nonce <= math.MaxUint32; nonce++ {
+		block.Header.Nonce = uint32(nonce)
+		hash := block.Header.PowHash()
+		if blockchain.HashToBig(&hash).Cmp(target) <= 0 {
+			solved = true
+			break
+		}
+	}
+	if !solved {
+		return nil, errors.New("unable to solve block")
+	}
+
+	utilBlock := ltcutil.NewBlock(&block)
+	utilBlock.SetHeight(height)
+	b.tip = &builderNode{
+		parent: b.tip,
+		block:  utilBlock,
+		height: height,
+	}
+	return utilBlock, nil
+}
+
+// NextBlocks builds the passed number of empty blocks on top of the chain and
+// returns them in order.
+func (b *ChainBuilder) NextBlocks(count uint32) ([]*ltcutil.Block, error) {
+	blocks := make([]*ltcutil.Block, 0, count)
+	for i := uint32(0); i < count; i++ {
+		block, err := b.NextBlock(nil)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
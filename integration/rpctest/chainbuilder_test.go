@@ -0,0 +1,133 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpctest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/database"
+	_ "github.com/ltcsuite/ltcd/database/ffldb"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+)
+
+// TestChainBuilder ensures the chain builder deterministically builds chains
+// which are accepted by the node across the activation of the LWMA and ASERT
+// difficulty algorithms, and that forks built by it cause reorganizations.
+func TestChainBuilder(t *testing.T) {
+	// Retarget on a copy of the regtest parameters with the difficulty
+	// algorithms activating early enough to keep the test quick.
+	params := chaincfg.RegressionNetParams
+	params.PoWNoRetargeting = false
+	params.LWMAHeight = 10
+	params.LWMAFixHeight = 20
+	params.ASERTHeight = 30
+	params.ASERTAnchorBits = params.PowLimitBits
+
+	miningAddr, err := ltcutil.NewAddressPubKeyHash(make([]byte, 20),
+		&params)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	// The regtest genesis block does not have the minimum difficulty, so
+	// the blocks before LWMA activates are found slowly enough for the
+	// minimum difficulty to apply.  Blocks found faster than the target
+	// block time raise the difficulty afterwards.
+	fastBlocks := func(b *ChainBuilder, count int) []*ltcutil.Block {
+		t.Helper()
+		var blocks []*ltcutil.Block
+		for i := 0; i < count; i++ {
+			solveTime := 2 * time.Minute
+			if b.Height()+1 < params.LWMAHeight {
+				solveTime = params.MinDiffReductionTime + time.Minute
+			}
+			prevTime := b.Tip().MsgBlock().Header.Timestamp
+			block, err := b.NextBlockAt(nil, prevTime.Add(solveTime))
+			if err != nil {
+				t.Fatalf("NextBlockAt: unexpected error: %v", err)
+			}
+			blocks = append(blocks, block)
+		}
+		return blocks
+	}
+
+	builder := NewChainBuilder(&params, miningAddr)
+	blocks := fastBlocks(builder, 40)
+	if builder.Height() != 40 {
+		t.Fatalf("Height: got %d, want 40", builder.Height())
+	}
+	if got := builder.Blocks(0); len(got) != 40 || got[39] != builder.Tip() {
+		t.Fatalf("Blocks: got %d blocks, want 40", len(got))
+	}
+	if bits := builder.Tip().MsgBlock().Header.Bits; bits == params.PowLimitBits {
+		t.Fatalf("difficulty was not raised by fast blocks")
+	}
+
+	// Building the same chain again yields the same blocks.
+	again := fastBlocks(NewChainBuilder(&params, miningAddr), 40)
+	for i := range blocks {
+		if *again[i].Hash() != *blocks[i].Hash() {
+			t.Fatalf("block %d: got hash %v, want %v", i+1,
+				again[i].Hash(), blocks[i].Hash())
+		}
+	}
+
+	// The blocks are accepted by the node.
+	db, err := database.Create("ffldb", t.TempDir(), params.Net)
+	if err != nil {
+		t.Fatalf("database.Create: unexpected error: %v", err)
+	}
+	defer db.Close()
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		ChainParams: &params,
+		TimeSource:  blockchain.NewMedianTime(),
+		SigCache:    txscript.NewSigCache(1000),
+	})
+	if err != nil {
+		t.Fatalf("blockchain.New: unexpected error: %v", err)
+	}
+	processBlocks := func(blocks []*ltcutil.Block) {
+		t.Helper()
+		for _, block := range blocks {
+			_, isOrphan, err := chain.ProcessBlock(block,
+				blockchain.BFNone)
+			if err != nil {
+				t.Fatalf("ProcessBlock: block %d rejected: %v",
+					block.Height(), err)
+			}
+			if isOrphan {
+				t.Fatalf("ProcessBlock: block %d is an orphan",
+					block.Height())
+			}
+		}
+	}
+	processBlocks(blocks)
+
+	// A longer fork of the chain causes a reorganization.
+	fork, err := builder.Fork(35)
+	if err != nil {
+		t.Fatalf("Fork: unexpected error: %v", err)
+	}
+	forkBlocks, err := fork.NextBlocks(10)
+	if err != nil {
+		t.Fatalf("NextBlocks: unexpected error: %v", err)
+	}
+	if *forkBlocks[0].Hash() == *blocks[35].Hash() {
+		t.Fatalf("fork built the same block as the chain it forks from")
+	}
+	processBlocks(forkBlocks)
+	if best := chain.BestSnapshot(); best.Hash != *fork.Tip().Hash() {
+		t.Fatalf("best chain: got tip %v, want fork tip %v", best.Hash,
+			fork.Tip().Hash())
+	}
+	if _, err := builder.Fork(41); err == nil {
+		t.Fatalf("Fork: forked beyond the tip of the chain")
+	}
+}
@@ -5,6 +5,11 @@
 // creating new addresses, and crafting fully signed transactions paying to an
 // arbitrary set of outputs.
 //
+// Harnesses may be joined into networks and partitioned again via
+// JoinNetworks and PartitionNetworks, while a ChainBuilder deterministically
+// assembles chains, including forks, honoring the difficulty algorithm
+// activation heights of the network which can be submitted to any harness.
+//
 // This package was designed specifically to act as an RPC testing harness for
 // `ltcd`. However, the constructs presented are general enough to be adapted to
 // any project wishing to programmatically drive a `ltcd` instance of its
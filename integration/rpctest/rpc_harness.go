@@ -20,6 +20,7 @@ import (
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/ltcutil"
 	"github.com/ltcsuite/ltcd/rpcclient"
+	"github.com/ltcsuite/ltcd/txscript"
 	"github.com/ltcsuite/ltcd/wire"
 )
 
@@ -522,6 +523,45 @@ func (h *Harness) GenerateAndSubmitBlockWithCustomCoinbaseOutputs(
 	return newBlock, nil
 }
 
+// SubmitBlocks submits the passed blocks, such as those assembled by a
+// ChainBuilder, to the running node in order.
+//
+// This function is safe for concurrent access.
+func (h *Harness) SubmitBlocks(blocks []*ltcutil.Block) error {
+	for _, block := range blocks {
+		if err := h.Client.SubmitBlock(block, nil); err != nil {
+			return fmt.Errorf("unable to submit block %v at height "+
+				"%d: %v", block.Hash(), block.Height(), err)
+		}
+	}
+	return nil
+}
+
+// FundAddress sends the passed amount to the passed address from the
+// harness' available mature coinbase outputs and mines a block confirming the
+// transaction, whose hash is returned.  The passed fee rate should be expressed
+// in sat/b.
+//
+// This function is safe for concurrent access.
+func (h *Harness) FundAddress(addr ltcutil.Address, amt,
+	feeRate ltcutil.Amount) (*chainhash.Hash, error) {
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+	txid, err := h.SendOutputs(
+		[]*wire.TxOut{wire.NewTxOut(int64(amt), pkScript)}, feeRate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.Client.Generate(1); err != nil {
+		return nil, err
+	}
+	return txid, nil
+}
+
 // generateListeningAddresses is a function that returns two listener
 // addresses with unique ports and should be used to overwrite rpctest's
 // default generator which is prone to use colliding ports.
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/ltcsuite/ltcd/btcjson"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/rpcclient"
 )
@@ -134,6 +135,84 @@ func ConnectNode(from *Harness, to *Harness) error {
 	return nil
 }
 
+// DisconnectNode tears down any peer-to-peer connection the "from" harness
+// established to the "to" harness, including persistent connections made by
+// ConnectNode, and blocks until the connection is gone.
+func DisconnectNode(from *Harness, to *Harness) error {
+	targetAddr := to.node.config.listen
+	connected, err := isConnectedTo(from, targetAddr)
+	if err != nil || !connected {
+		return err
+	}
+
+	// Removing a persistent peer disconnects it as well, while other
+	// outbound peers have to be disconnected explicitly.
+	err = from.Client.AddNode(targetAddr, rpcclient.ANRemove)
+	if err != nil {
+		err = from.Client.Node(btcjson.NDisconnect, targetAddr, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Block until the connection has been torn down.
+	for connected {
+		time.Sleep(time.Millisecond * 100)
+		connected, err = isConnectedTo(from, targetAddr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isConnectedTo returns whether the harness has an outbound connection to the
+// peer with the passed address.
+func isConnectedTo(h *Harness, addr string) (bool, error) {
+	peerInfo, err := h.Client.GetPeerInfo()
+	if err != nil {
+		return false, err
+	}
+	for _, peer := range peerInfo {
+		if !peer.Inbound && peer.Addr == addr {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// JoinNetworks connects every harness of the first network to every harness of
+// the second network so that the two networks converge on the same chain.
+func JoinNetworks(a, b []*Harness) error {
+	for _, from := range a {
+		for _, to := range b {
+			if err := ConnectNode(from, to); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PartitionNetworks tears down all connections between the harnesses of the
+// first network and the harnesses of the second network, in either direction,
+// so that the two networks may build competing chains.  The harnesses within
+// each network stay connected.
+func PartitionNetworks(a, b []*Harness) error {
+	for _, from := range a {
+		for _, to := range b {
+			if err := DisconnectNode(from, to); err != nil {
+				return err
+			}
+			if err := DisconnectNode(to, from); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // TearDownAll tears down all active test harnesses.
 func TearDownAll() error {
 	harnessStateMtx.Lock()
@@ -0,0 +1,17 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package keyutil provides helpers for working with serialized private keys
+whose network is not known in advance.
+
+Tools which accept keys for several networks can use DecodeWIFAny to decode a
+WIF encoded private key along with the parameters of the network it belongs to,
+instead of comparing the network byte of the key against the private key ID of
+each network they support.  The network is looked up among the default
+networks and any network registered with chaincfg.Register.  The network of an
+extended key of one of the default networks is identified the same way by the
+NewKeyFromStringAny function of the ltcutil/hdkeychain package.
+*/
+package keyutil
@@ -0,0 +1,34 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package keyutil
+
+import (
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/base58"
+)
+
+// DecodeWIFAny decodes a WIF encoded private key and returns it along with the
+// parameters of the network it belongs to.  Since several networks may share
+// the same private key ID, the network registered first is reported, so keys
+// of the regression test network are reported as test network keys.
+//
+// The errors of ltcutil.DecodeWIF are returned for malformed keys, and the
+// ErrUnknownPrivateKeyID error from chaincfg is returned when the key does not
+// belong to any default or registered network.
+func DecodeWIFAny(wif string) (*ltcutil.WIF, *chaincfg.Params, error) {
+	decoded, err := ltcutil.DecodeWIF(wif)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The first byte of a successfully decoded WIF string identifies its
+	// network.
+	net, err := chaincfg.ParamsForPrivateKeyID(base58.Decode(wif)[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	return decoded, net, nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package keyutil
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// TestDecodeWIFAny ensures WIF encoded private keys are decoded along with the
+// network they belong to.
+func TestDecodeWIFAny(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := btcec.PrivKeyFromBytes(chainhash.HashB([]byte{1}))
+
+	tests := []struct {
+		name string
+		net  *chaincfg.Params
+		want *chaincfg.Params
+	}{
+		{"mainnet", &chaincfg.MainNetParams, &chaincfg.MainNetParams},
+		{"testnet4", &chaincfg.TestNet4Params, &chaincfg.TestNet4Params},
+		{"simnet", &chaincfg.SimNetParams, &chaincfg.SimNetParams},
+
+		// The regression test network shares its private key ID with
+		// the test network, which was registered first.
+		{"regtest", &chaincfg.RegressionNetParams,
+			&chaincfg.TestNet4Params},
+	}
+
+	for _, test := range tests {
+		for _, compress := range []bool{false, true} {
+			wif, err := ltcutil.NewWIF(privKey, test.net, compress)
+			if err != nil {
+				t.Fatalf("%s: NewWIF: unexpected error: %v",
+					test.name, err)
+			}
+
+			decoded, net, err := DecodeWIFAny(wif.String())
+			if err != nil {
+				t.Errorf("%s: DecodeWIFAny: unexpected error: %v",
+					test.name, err)
+				continue
+			}
+			if net != test.want {
+				t.Errorf("%s: got network %s, want %s",
+					test.name, net.Name, test.want.Name)
+			}
+			if !decoded.IsForNet(test.net) ||
+				decoded.CompressPubKey != compress ||
+				!decoded.PrivKey.Key.Equals(&privKey.Key) {
+
+				t.Errorf("%s: decoded key does not match", test.name)
+			}
+		}
+	}
+
+	// Keys of unregistered networks are rejected.
+	unknownNet := chaincfg.MainNetParams
+	unknownNet.PrivateKeyID = 0x01
+	wif, err := ltcutil.NewWIF(privKey, &unknownNet, true)
+	if err != nil {
+		t.Fatalf("NewWIF: unexpected error: %v", err)
+	}
+	_, _, err = DecodeWIFAny(wif.String())
+	if err != chaincfg.ErrUnknownPrivateKeyID {
+		t.Fatalf("unexpected error for unknown network: %v", err)
+	}
+
+	// Malformed keys are rejected.
+	_, _, err = DecodeWIFAny("abc")
+	if err != ltcutil.ErrMalformedPrivateKey {
+		t.Fatalf("unexpected error for malformed key: %v", err)
+	}
+}
@@ -54,21 +54,22 @@ var (
 	// application shutdown.
 	logRotator *rotator.Rotator
 
-	adxrLog = backendLog.Logger("ADXR")
-	amgrLog = backendLog.Logger("AMGR")
-	cmgrLog = backendLog.Logger("CMGR")
-	bcdbLog = backendLog.Logger("BCDB")
-	ltcdLog = backendLog.Logger("LTCD")
-	chanLog = backendLog.Logger("CHAN")
-	discLog = backendLog.Logger("DISC")
-	indxLog = backendLog.Logger("INDX")
-	minrLog = backendLog.Logger("MINR")
-	peerLog = backendLog.Logger("PEER")
-	rpcsLog = backendLog.Logger("RPCS")
-	scrpLog = backendLog.Logger("SCRP")
-	srvrLog = backendLog.Logger("SRVR")
-	syncLog = backendLog.Logger("SYNC")
-	txmpLog = backendLog.Logger("TXMP")
+	adxrLog = newSubsystemLogger("ADXR")
+	amgrLog = newSubsystemLogger("AMGR")
+	cmgrLog = newSubsystemLogger("CMGR")
+	bcdbLog = newSubsystemLogger("BCDB")
+	ltcdLog = newSubsystemLogger("LTCD")
+	chanLog = newSubsystemLogger("CHAN")
+	discLog = newSubsystemLogger("DISC")
+	indxLog = newSubsystemLogger("INDX")
+	minrLog = newSubsystemLogger("MINR")
+	misbLog = newSubsystemLogger("MISB")
+	peerLog = newSubsystemLogger("PEER")
+	rpcsLog = newSubsystemLogger("RPCS")
+	scrpLog = newSubsystemLogger("SCRP")
+	srvrLog = newSubsystemLogger("SRVR")
+	syncLog = newSubsystemLogger("SYNC")
+	txmpLog = newSubsystemLogger("TXMP")
 )
 
 // Initialize package-global logger variables.
@@ -97,6 +98,7 @@ var subsystemLoggers = map[string]btclog.Logger{
 	"DISC": discLog,
 	"INDX": indxLog,
 	"MINR": minrLog,
+	"MISB": misbLog,
 	"PEER": peerLog,
 	"RPCS": rpcsLog,
 	"SCRP": scrpLog,
@@ -106,16 +108,17 @@ var subsystemLoggers = map[string]btclog.Logger{
 }
 
 // initLogRotator initializes the logging rotater to write logs to logFile and
-// create roll files in the same directory.  It must be called before the
-// package-global log rotater variables are used.
-func initLogRotator(logFile string) {
+// create roll files in the same directory.  The log file is rotated once it
+// grows beyond maxSize MiB and at most maxRolls rotated files are kept.  It
+// must be called before the package-global log rotater variables are used.
+func initLogRotator(logFile string, maxSize int64, maxRolls int) {
 	logDir, _ := filepath.Split(logFile)
 	err := os.MkdirAll(logDir, 0700)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to create log directory: %v\n", err)
 		os.Exit(1)
 	}
-	r, err := rotator.New(logFile, 10*1024, false, 3)
+	r, err := rotator.New(logFile, maxSize*1024, false, maxRolls)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to create file rotator: %v\n", err)
 		os.Exit(1)
@@ -139,6 +142,15 @@ func setLogLevel(subsystemID string, logLevel string) {
 	logger.SetLevel(level)
 }
 
+// logLevels returns the current logging level of each subsystem.
+func logLevels() map[string]string {
+	levels := make(map[string]string, len(subsystemLoggers))
+	for subsystemID, logger := range subsystemLoggers {
+		levels[subsystemID] = logLevelName(logger.Level())
+	}
+	return levels
+}
+
 // setLogLevels sets the log level for all subsystem loggers to the passed
 // level.  It also dynamically creates the subsystem loggers as needed, so it
 // can be used to initialize the logging system.
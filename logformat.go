@@ -0,0 +1,286 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btclog"
+)
+
+const (
+	// logFormatText is the log format which writes human-readable lines.
+	logFormatText = "text"
+
+	// logFormatJSON is the log format which writes one JSON object per
+	// line.
+	logFormatJSON = "json"
+)
+
+var (
+	// jsonLogging is set to one when log entries are written as JSON.  It
+	// must be accessed atomically.
+	jsonLogging int32
+
+	// jsonLogMtx serializes writing JSON log entries so lines from
+	// different subsystems are not interleaved.
+	jsonLogMtx sync.Mutex
+)
+
+// validLogFormat returns whether or not logFormat is a supported log format.
+func validLogFormat(logFormat string) bool {
+	switch logFormat {
+	case logFormatText, logFormatJSON:
+		return true
+	}
+	return false
+}
+
+// setLogFormat sets the format used to write the log entries of all
+// subsystems.  Invalid formats are ignored.
+func setLogFormat(logFormat string) {
+	switch logFormat {
+	case logFormatText:
+		atomic.StoreInt32(&jsonLogging, 0)
+	case logFormatJSON:
+		atomic.StoreInt32(&jsonLogging, 1)
+	}
+}
+
+// logLevelName returns the name of the passed logging level as accepted by the
+// debuglevel option, as opposed to the abbreviation used in text log entries.
+func logLevelName(level btclog.Level) string {
+	switch level {
+	case btclog.LevelTrace:
+		return "trace"
+	case btclog.LevelDebug:
+		return "debug"
+	case btclog.LevelInfo:
+		return "info"
+	case btclog.LevelWarn:
+		return "warn"
+	case btclog.LevelError:
+		return "error"
+	case btclog.LevelCritical:
+		return "critical"
+	}
+	return "off"
+}
+
+// jsonLogEntry describes a log entry written when logging as JSON.
+type jsonLogEntry struct {
+	Time      string            `json:"time"`
+	Level     string            `json:"level"`
+	Subsystem string            `json:"subsystem"`
+	Message   string            `json:"msg"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+// logFields returns the key=value pairs in the passed log message.  Messages
+// which are meant to be consumed by machines, such as difficulty retargets,
+// use such pairs so they can be indexed without parsing free-form text.
+func logFields(msg string) map[string]string {
+	var fields map[string]string
+	for _, token := range strings.Fields(msg) {
+		idx := strings.IndexByte(token, '=')
+		if idx < 1 || idx == len(token)-1 {
+			continue
+		}
+		key := token[:idx]
+		if strings.IndexFunc(key, func(r rune) bool {
+			return !(r == '_' || (r >= 'a' && r <= 'z') ||
+				(r >= '0' && r <= '9'))
+		}) != -1 {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		fields[key] = token[idx+1:]
+	}
+	return fields
+}
+
+// formatJSONLogEntry returns the passed log message as a JSON log entry
+// terminated by a newline.
+func formatJSONLogEntry(t time.Time, level btclog.Level, subsystem,
+	msg string) []byte {
+
+	entry := jsonLogEntry{
+		Time:      t.UTC().Format(time.RFC3339Nano),
+		Level:     logLevelName(level),
+		Subsystem: subsystem,
+		Message:   msg,
+		Fields:    logFields(msg),
+	}
+	b, err := json.Marshal(&entry)
+	if err != nil {
+		// The entry only consists of strings, so this can't happen.
+		b = []byte(fmt.Sprintf(`{"msg":%q}`, err.Error()))
+	}
+	return append(b, '\n')
+}
+
+// subsystemLogger is the logger used by each subsystem.  It writes the log
+// entries using the human-readable format of the backend logger it wraps, or
+// as JSON when enabled.
+type subsystemLogger struct {
+	btclog.Logger
+	subsystem string
+}
+
+// Ensure subsystemLogger implements the btclog.Logger interface.
+var _ btclog.Logger = (*subsystemLogger)(nil)
+
+// newSubsystemLogger returns a new logger for the passed subsystem which writes
+// to the logging backend.
+func newSubsystemLogger(subsystem string) btclog.Logger {
+	return &subsystemLogger{
+		Logger:    backendLog.Logger(subsystem),
+		subsystem: subsystem,
+	}
+}
+
+// writeJSON writes the passed message as a JSON log entry if JSON logging is
+// enabled and returns whether or not it was handled.
+func (l *subsystemLogger) writeJSON(level btclog.Level, msg func() string) bool {
+	if atomic.LoadInt32(&jsonLogging) == 0 {
+		return false
+	}
+	if level < l.Level() {
+		return true
+	}
+
+	entry := formatJSONLogEntry(time.Now(), level, l.subsystem, msg())
+	jsonLogMtx.Lock()
+	logWriter{}.Write(entry)
+	jsonLogMtx.Unlock()
+	return true
+}
+
+// Tracef formats message according to format specifier and writes to log with
+// LevelTrace.
+func (l *subsystemLogger) Tracef(format string, params ...interface{}) {
+	if !l.writeJSON(btclog.LevelTrace, func() string {
+		return fmt.Sprintf(format, params...)
+	}) {
+		l.Logger.Tracef(format, params...)
+	}
+}
+
+// Debugf formats message according to format specifier and writes to log with
+// LevelDebug.
+func (l *subsystemLogger) Debugf(format string, params ...interface{}) {
+	if !l.writeJSON(btclog.LevelDebug, func() string {
+		return fmt.Sprintf(format, params...)
+	}) {
+		l.Logger.Debugf(format, params...)
+	}
+}
+
+// Infof formats message according to format specifier and writes to log with
+// LevelInfo.
+func (l *subsystemLogger) Infof(format string, params ...interface{}) {
+	if !l.writeJSON(btclog.LevelInfo, func() string {
+		return fmt.Sprintf(format, params...)
+	}) {
+		l.Logger.Infof(format, params...)
+	}
+}
+
+// Warnf formats message according to format specifier and writes to log with
+// LevelWarn.
+func (l *subsystemLogger) Warnf(format string, params ...interface{}) {
+	if !l.writeJSON(btclog.LevelWarn, func() string {
+		return fmt.Sprintf(format, params...)
+	}) {
+		l.Logger.Warnf(format, params...)
+	}
+}
+
+// Errorf formats message according to format specifier and writes to log with
+// LevelError.
+func (l *subsystemLogger) Errorf(format string, params ...interface{}) {
+	if !l.writeJSON(btclog.LevelError, func() string {
+		return fmt.Sprintf(format, params...)
+	}) {
+		l.Logger.Errorf(format, params...)
+	}
+}
+
+// Criticalf formats message according to format specifier and writes to log
+// with LevelCritical.
+func (l *subsystemLogger) Criticalf(format string, params ...interface{}) {
+	if !l.writeJSON(btclog.LevelCritical, func() string {
+		return fmt.Sprintf(format, params...)
+	}) {
+		l.Logger.Criticalf(format, params...)
+	}
+}
+
+// Trace formats message using the default formats for its operands and writes
+// to log with LevelTrace.
+func (l *subsystemLogger) Trace(v ...interface{}) {
+	if !l.writeJSON(btclog.LevelTrace, func() string {
+		return fmt.Sprint(v...)
+	}) {
+		l.Logger.Trace(v...)
+	}
+}
+
+// Debug formats message using the default formats for its operands and writes
+// to log with LevelDebug.
+func (l *subsystemLogger) Debug(v ...interface{}) {
+	if !l.writeJSON(btclog.LevelDebug, func() string {
+		return fmt.Sprint(v...)
+	}) {
+		l.Logger.Debug(v...)
+	}
+}
+
+// Info formats message using the default formats for its operands and writes
+// to log with LevelInfo.
+func (l *subsystemLogger) Info(v ...interface{}) {
+	if !l.writeJSON(btclog.LevelInfo, func() string {
+		return fmt.Sprint(v...)
+	}) {
+		l.Logger.Info(v...)
+	}
+}
+
+// Warn formats message using the default formats for its operands and writes
+// to log with LevelWarn.
+func (l *subsystemLogger) Warn(v ...interface{}) {
+	if !l.writeJSON(btclog.LevelWarn, func() string {
+		return fmt.Sprint(v...)
+	}) {
+		l.Logger.Warn(v...)
+	}
+}
+
+// Error formats message using the default formats for its operands and writes
+// to log with LevelError.
+func (l *subsystemLogger) Error(v ...interface{}) {
+	if !l.writeJSON(btclog.LevelError, func() string {
+		return fmt.Sprint(v...)
+	}) {
+		l.Logger.Error(v...)
+	}
+}
+
+// Critical formats message using the default formats for its operands and
+// writes to log with LevelCritical.
+func (l *subsystemLogger) Critical(v ...interface{}) {
+	if !l.writeJSON(btclog.LevelCritical, func() string {
+		return fmt.Sprint(v...)
+	}) {
+		l.Logger.Critical(v...)
+	}
+}
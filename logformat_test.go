@@ -0,0 +1,67 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btclog"
+)
+
+// TestFormatJSONLogEntry ensures log entries are formatted as JSON with the
+// key=value pairs in the message as fields.
+func TestFormatJSONLogEntry(t *testing.T) {
+	tests := []struct {
+		msg    string
+		fields map[string]string
+	}{{
+		msg:    "Chain verify completed successfully",
+		fields: nil,
+	}, {
+		msg: "Difficulty retarget height=2016 old_bits=1e0ffff0 " +
+			"new_bits=1e0fffe0 actual_timespan=84h0m0s",
+		fields: map[string]string{
+			"height":          "2016",
+			"old_bits":        "1e0ffff0",
+			"new_bits":        "1e0fffe0",
+			"actual_timespan": "84h0m0s",
+		},
+	}, {
+		// Tokens which are not lowercase keys with a value are not
+		// fields.
+		msg:    "Supported subsystems [A=B] =x y= Key=value",
+		fields: nil,
+	}}
+
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, test := range tests {
+		b := formatJSONLogEntry(ts, btclog.LevelDebug, "CHAN", test.msg)
+		if b[len(b)-1] != '\n' {
+			t.Errorf("%q: entry is not terminated by a newline",
+				test.msg)
+			continue
+		}
+
+		var entry jsonLogEntry
+		if err := json.Unmarshal(b, &entry); err != nil {
+			t.Errorf("%q: unable to decode entry: %v", test.msg, err)
+			continue
+		}
+		want := jsonLogEntry{
+			Time:      "2026-01-02T03:04:05Z",
+			Level:     "debug",
+			Subsystem: "CHAN",
+			Message:   test.msg,
+			Fields:    test.fields,
+		}
+		if !reflect.DeepEqual(entry, want) {
+			t.Errorf("%q: got entry %+v, want %+v", test.msg, entry,
+				want)
+		}
+	}
+}
@@ -81,17 +81,19 @@ func encodeSegWitAddress(hrp string, witnessVersion byte, witnessProgram []byte)
 	combined[0] = witnessVersion
 	copy(combined[1:], converted)
 
+	// Witness version 0 and the MWEB versions use bech32, while every
+	// other version uses bech32m as described in BIP 350.
 	var bech string
-	switch witnessVersion {
-	case 0, 8, 9:
-		bech, err = bech32.Encode(hrp, combined)
+	switch {
+	case witnessVersion > 16:
+		return "", fmt.Errorf("unsupported witness version %d",
+			witnessVersion)
 
-	case 1:
-		bech, err = bech32.EncodeM(hrp, combined)
+	case usesBech32(witnessVersion):
+		bech, err = bech32.Encode(hrp, combined)
 
 	default:
-		return "", fmt.Errorf("unsupported witness version %d",
-			witnessVersion)
+		bech, err = bech32.EncodeM(hrp, combined)
 	}
 	if err != nil {
 		return "", err
@@ -278,15 +280,27 @@ func decodeSegWitAddress(address string) (byte, []byte, error) {
 			"encoding for address with witness version 0")
 	}
 
-	// For witness version 1, the bech32m encoding must be used.
-	if version == 1 && bech32version != bech32.VersionM {
-		return 0, nil, fmt.Errorf("invalid checksum expected bech32m " +
-			"encoding for address with witness version 1")
+	// For witness version 1 and above, the bech32m encoding must be used.
+	if !usesBech32(version) && bech32version != bech32.VersionM {
+		return 0, nil, fmt.Errorf("invalid checksum expected bech32m "+
+			"encoding for address with witness version %d", version)
 	}
 
 	return version, regrouped, nil
 }
 
+// usesBech32 returns whether addresses for the passed witness version are
+// encoded using the original bech32 checksum rather than bech32m.  This is
+// the case for witness version 0 and the MWEB witness versions, which predate
+// BIP 350.
+func usesBech32(witnessVersion byte) bool {
+	switch witnessVersion {
+	case 0, 8, 9:
+		return true
+	}
+	return false
+}
+
 // decodeMwebAddress parses a bech32 encoded MWEB address string and
 // returns the stealth address representation.
 func decodeMwebAddress(address string) (*mw.StealthAddress, error) {
@@ -321,10 +335,17 @@ func decodeMwebAddress(address string) (*mw.StealthAddress, error) {
 		return nil, fmt.Errorf("invalid data length")
 	}
 
-	return &mw.StealthAddress{
-		Scan:  (*mw.PublicKey)(regrouped[:33]),
-		Spend: (*mw.PublicKey)(regrouped[33:]),
-	}, nil
+	// Both the scan and spend keys must be valid curve points.
+	scan, err := mw.ReadPublicKey(regrouped[:33])
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan pubkey: %v", err)
+	}
+	spend, err := mw.ReadPublicKey(regrouped[33:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid spend pubkey: %v", err)
+	}
+
+	return &mw.StealthAddress{Scan: scan, Spend: spend}, nil
 }
 
 // AddressPubKeyHash is an Address for a pay-to-pubkey-hash (P2PKH)
@@ -582,7 +603,7 @@ type AddressSegWit struct {
 	witnessProgram []byte
 }
 
-// EncodeAddress returns the bech32 (or bech32m for SegWit v1) string encoding
+// EncodeAddress returns the bech32 (or bech32m for SegWit v1+) string encoding
 // of an AddressSegWit.
 //
 // NOTE: This method is part of the Address interface.
@@ -620,7 +641,7 @@ func (a *AddressSegWit) String() string {
 	return a.EncodeAddress()
 }
 
-// Hrp returns the human-readable part of the bech32 (or bech32m for SegWit v1)
+// Hrp returns the human-readable part of the bech32 (or bech32m for SegWit v1+)
 // encoded AddressSegWit.
 func (a *AddressSegWit) Hrp() string {
 	return a.hrp
@@ -14,6 +14,7 @@ import (
 
 	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/bech32"
 	"golang.org/x/crypto/ripemd160"
 )
 
@@ -676,3 +677,112 @@ func TestAddresses(t *testing.T) {
 		}
 	}
 }
+
+// TestSegWitAddressChecksumVersion ensures witness version 0 and the MWEB
+// versions are encoded using bech32 while all other versions use bech32m, and
+// that decoding enforces the matching checksum.
+func TestSegWitAddressChecksumVersion(t *testing.T) {
+	program := make([]byte, 32)
+	for i := range program {
+		program[i] = byte(i)
+	}
+
+	for version := byte(0); version <= 16; version++ {
+		addr, err := ltcutil.TstEncodeSegWitAddress("dsv", version, program)
+		if err != nil {
+			t.Errorf("version %d: unexpected encode error: %v", version,
+				err)
+			continue
+		}
+
+		_, data, bech32Version, err := bech32.DecodeGeneric(addr)
+		if err != nil {
+			t.Errorf("version %d: unexpected decode error: %v", version,
+				err)
+			continue
+		}
+		wantBech32Version := bech32.VersionM
+		if version == 0 || version == 8 || version == 9 {
+			wantBech32Version = bech32.Version0
+		}
+		if bech32Version != wantBech32Version {
+			t.Errorf("version %d: got checksum version %v, want %v",
+				version, bech32Version, wantBech32Version)
+			continue
+		}
+
+		gotVersion, gotProgram, err := ltcutil.TstDecodeSegWitAddress(addr)
+		if err != nil {
+			t.Errorf("version %d: unexpected error decoding %s: %v",
+				version, addr, err)
+			continue
+		}
+		if gotVersion != version || !bytes.Equal(gotProgram, program) {
+			t.Errorf("version %d: %s did not round trip", version, addr)
+		}
+
+		// Re-encoding a v1+ address with the bech32 checksum must be
+		// rejected.
+		if wantBech32Version != bech32.VersionM {
+			continue
+		}
+		wrong, err := bech32.Encode("dsv", data)
+		if err != nil {
+			t.Errorf("version %d: unexpected encode error: %v", version,
+				err)
+			continue
+		}
+		if _, _, err := ltcutil.TstDecodeSegWitAddress(wrong); err == nil {
+			t.Errorf("version %d: expected error decoding bech32 "+
+				"address %s", version, wrong)
+		}
+	}
+
+	if _, err := ltcutil.TstEncodeSegWitAddress("dsv", 17, program); err == nil {
+		t.Error("expected error encoding witness version 17")
+	}
+}
+
+// TestMwebAddress ensures MWEB stealth addresses round trip through
+// DecodeAddress and that addresses with invalid pubkeys are rejected.
+func TestMwebAddress(t *testing.T) {
+	const encoded = "tmweb1qqv0mlyyk7sl09jkcrgy059m5yplw567ypuj6lxpwkcw4tl8m" +
+		"59p7wq6jc6prtph5kf45kdlql8fjppr32nmwng34fs6ess9fq72ck7lfyvmr6s0c"
+
+	addr, err := ltcutil.DecodeAddress(encoded, &chaincfg.TestNet4Params)
+	if err != nil {
+		t.Fatalf("DecodeAddress: unexpected error: %v", err)
+	}
+	mwebAddr, ok := addr.(*ltcutil.AddressMweb)
+	if !ok {
+		t.Fatalf("DecodeAddress: got %T, want *ltcutil.AddressMweb", addr)
+	}
+	if !mwebAddr.IsForNet(&chaincfg.TestNet4Params) {
+		t.Error("IsForNet: address is not for testnet")
+	}
+	if mwebAddr.IsForNet(&chaincfg.MainNetParams) {
+		t.Error("IsForNet: address is for mainnet")
+	}
+
+	sa := mwebAddr.StealthAddress()
+	reencoded := ltcutil.NewAddressMweb(sa, &chaincfg.TestNet4Params)
+	if reencoded.EncodeAddress() != encoded {
+		t.Errorf("EncodeAddress: got %s, want %s",
+			reencoded.EncodeAddress(), encoded)
+	}
+
+	// Replace the scan pubkey prefix so it is no longer a valid point.
+	data := mwebAddr.ScriptAddress()
+	data[0] = 0x05
+	converted, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		t.Fatalf("ConvertBits: unexpected error: %v", err)
+	}
+	bad, err := bech32.Encode("tmweb", append([]byte{0}, converted...))
+	if err != nil {
+		t.Fatalf("Encode: unexpected error: %v", err)
+	}
+	if _, err := ltcutil.DecodeAddress(bad, &chaincfg.TestNet4Params); err == nil {
+		t.Error("DecodeAddress: expected error for invalid scan pubkey")
+	}
+}
@@ -81,5 +81,20 @@ Extended keys are much like normal Litecoin addresses in that they have version
 bytes which tie them to a specific network.  The SetNet and IsForNet functions
 are provided to set and determinine which network an extended key is associated
 with.
+
+# SLIP-0132 Version Bytes
+
+SLIP-0132 defines additional version bytes which signal the type of output
+script the keys derived from an extended key are used with, such as zprv/zpub
+for pay-to-witness-pubkey-hash outputs on the main network and vprv/vpub on the
+test networks.  The SLIP-0132 version bytes are registered with chaincfg when
+this package is loaded, so keys using them can be neutered like standard keys.
+Custom version bytes may be supported the same way by registering them with
+chaincfg.RegisterHDKeyID.
+
+The ScriptType function returns the script type signalled by an extended key,
+and VersionForScriptType returns the version bytes a network uses for a script
+type, which may be passed to CloneWithVersion to convert between the standard
+and SLIP-0132 serializations of a key.
 */
 package hdkeychain
@@ -600,15 +600,30 @@ func (k *ExtendedKey) String() string {
 }
 
 // IsForNet returns whether or not the extended key is associated with the
-// passed litecoin network.
+// passed litecoin network.  Keys using the SLIP-0132 version bytes registered
+// for the network, such as zprv/zpub on the main network, are also considered
+// to be associated with it.
 func (k *ExtendedKey) IsForNet(net *chaincfg.Params) bool {
-	return bytes.Equal(k.version, net.HDPrivateKeyID[:]) ||
-		bytes.Equal(k.version, net.HDPublicKeyID[:])
+	if bytes.Equal(k.version, net.HDPrivateKeyID[:]) ||
+		bytes.Equal(k.version, net.HDPublicKeyID[:]) {
+
+		return true
+	}
+
+	version, ok := lookupVersion(k.version)
+	return ok && version.netPrivID == net.HDPrivateKeyID
 }
 
 // SetNet associates the extended key, and any child keys yet to be derived from
-// it, with the passed network.
+// it, with the passed network.  The script type of a key using SLIP-0132 version
+// bytes is kept when the network defines version bytes for it.
 func (k *ExtendedKey) SetNet(net *chaincfg.Params) {
+	version, err := VersionForScriptType(net, k.ScriptType(), k.isPrivate)
+	if err == nil {
+		k.version = version
+		return
+	}
+
 	if k.isPrivate {
 		k.version = net.HDPrivateKeyID[:]
 	} else {
@@ -732,6 +747,39 @@ func NewKeyFromString(key string) (*ExtendedKey, error) {
 		childNum, isPrivate), nil
 }
 
+// defaultNets houses the default networks in the order their extended keys are
+// identified by NewKeyFromStringAny.
+var defaultNets = []*chaincfg.Params{
+	&chaincfg.MainNetParams,
+	&chaincfg.TestNet4Params,
+	&chaincfg.RegressionNetParams,
+	&chaincfg.SimNetParams,
+}
+
+// NewKeyFromStringAny returns a new extended key instance from a base58-encoded
+// extended key along with the parameters of the network it belongs to.  The
+// network is identified from the version bytes of the key, which may be either
+// the standard version bytes of a default network or SLIP-0132 version bytes.
+// Since several networks share the same version bytes, the first matching
+// network is reported, so keys of the regression test network are reported as
+// test network keys.
+//
+// The ErrUnknownHDKeyID error from chaincfg is returned when the version bytes
+// do not belong to any default network.
+func NewKeyFromStringAny(key string) (*ExtendedKey, *chaincfg.Params, error) {
+	extKey, err := NewKeyFromString(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, net := range defaultNets {
+		if extKey.IsForNet(net) {
+			return extKey, net, nil
+		}
+	}
+	return nil, nil, chaincfg.ErrUnknownHDKeyID
+}
+
 // GenerateSeed returns a cryptographically secure random seed that can be used
 // as the input for the NewMaster function to generate a new master node.
 //
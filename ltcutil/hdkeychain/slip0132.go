@@ -0,0 +1,195 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hdkeychain
+
+// References:
+//   [SLIP132]: SLIP-0132 - Registered HD version bytes for BIP-0032
+//   https://github.com/satoshilabs/slips/blob/master/slip-0132.md
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+)
+
+// ScriptType identifies the kind of output script the keys derived from an
+// extended key are intended to be used with, as signalled by the SLIP-0132
+// version bytes of the extended key.
+type ScriptType uint8
+
+const (
+	// ScriptTypeP2PKH is used by extended keys serialized with the standard
+	// BIP0032 version bytes of a network, such as xprv/xpub.
+	ScriptTypeP2PKH ScriptType = iota
+
+	// ScriptTypeP2SHP2WPKH is used by yprv/ypub and uprv/upub keys.
+	ScriptTypeP2SHP2WPKH
+
+	// ScriptTypeP2WPKH is used by zprv/zpub and vprv/vpub keys.
+	ScriptTypeP2WPKH
+
+	// ScriptTypeP2SHP2WSH is used by Yprv/Ypub and Uprv/Upub keys.
+	ScriptTypeP2SHP2WSH
+
+	// ScriptTypeP2WSH is used by Zprv/Zpub and Vprv/Vpub keys.
+	ScriptTypeP2WSH
+)
+
+// scriptTypeStrings is a map of script types back to their constant names
+// for pretty printing.
+var scriptTypeStrings = map[ScriptType]string{
+	ScriptTypeP2PKH:      "ScriptTypeP2PKH",
+	ScriptTypeP2SHP2WPKH: "ScriptTypeP2SHP2WPKH",
+	ScriptTypeP2WPKH:     "ScriptTypeP2WPKH",
+	ScriptTypeP2SHP2WSH:  "ScriptTypeP2SHP2WSH",
+	ScriptTypeP2WSH:      "ScriptTypeP2WSH",
+}
+
+// String returns the ScriptType as a human-readable name.
+func (t ScriptType) String() string {
+	if s := scriptTypeStrings[t]; s != "" {
+		return s
+	}
+	return fmt.Sprintf("Unknown ScriptType (%d)", uint8(t))
+}
+
+// slip0132Version describes a pair of SLIP-0132 version bytes.
+type slip0132Version struct {
+	privID     [4]byte
+	pubID      [4]byte
+	scriptType ScriptType
+
+	// netPrivID is the standard private extended key ID of the networks
+	// the version bytes are used on.
+	netPrivID [4]byte
+}
+
+// slip0132Versions houses the SLIP-0132 version bytes known by this package.
+// The test network versions are shared by every network using the tprv/tpub
+// version bytes.
+var slip0132Versions = []slip0132Version{
+	// Main network.
+	{
+		privID:     [4]byte{0x04, 0x9d, 0x78, 0x78}, // yprv
+		pubID:      [4]byte{0x04, 0x9d, 0x7c, 0xb2}, // ypub
+		scriptType: ScriptTypeP2SHP2WPKH,
+		netPrivID:  chaincfg.MainNetParams.HDPrivateKeyID,
+	},
+	{
+		privID:     [4]byte{0x04, 0xb2, 0x43, 0x0c}, // zprv
+		pubID:      [4]byte{0x04, 0xb2, 0x47, 0x46}, // zpub
+		scriptType: ScriptTypeP2WPKH,
+		netPrivID:  chaincfg.MainNetParams.HDPrivateKeyID,
+	},
+	{
+		privID:     [4]byte{0x02, 0x95, 0xb0, 0x05}, // Yprv
+		pubID:      [4]byte{0x02, 0x95, 0xb4, 0x3f}, // Ypub
+		scriptType: ScriptTypeP2SHP2WSH,
+		netPrivID:  chaincfg.MainNetParams.HDPrivateKeyID,
+	},
+	{
+		privID:     [4]byte{0x02, 0xaa, 0x7a, 0x99}, // Zprv
+		pubID:      [4]byte{0x02, 0xaa, 0x7e, 0xd3}, // Zpub
+		scriptType: ScriptTypeP2WSH,
+		netPrivID:  chaincfg.MainNetParams.HDPrivateKeyID,
+	},
+
+	// Test networks.
+	{
+		privID:     [4]byte{0x04, 0x4a, 0x4e, 0x28}, // uprv
+		pubID:      [4]byte{0x04, 0x4a, 0x52, 0x62}, // upub
+		scriptType: ScriptTypeP2SHP2WPKH,
+		netPrivID:  chaincfg.TestNet4Params.HDPrivateKeyID,
+	},
+	{
+		privID:     [4]byte{0x04, 0x5f, 0x18, 0xbc}, // vprv
+		pubID:      [4]byte{0x04, 0x5f, 0x1c, 0xf6}, // vpub
+		scriptType: ScriptTypeP2WPKH,
+		netPrivID:  chaincfg.TestNet4Params.HDPrivateKeyID,
+	},
+	{
+		privID:     [4]byte{0x02, 0x42, 0x85, 0xb5}, // Uprv
+		pubID:      [4]byte{0x02, 0x42, 0x89, 0xef}, // Upub
+		scriptType: ScriptTypeP2SHP2WSH,
+		netPrivID:  chaincfg.TestNet4Params.HDPrivateKeyID,
+	},
+	{
+		privID:     [4]byte{0x02, 0x57, 0x50, 0x48}, // Vprv
+		pubID:      [4]byte{0x02, 0x57, 0x54, 0x83}, // Vpub
+		scriptType: ScriptTypeP2WSH,
+		netPrivID:  chaincfg.TestNet4Params.HDPrivateKeyID,
+	},
+}
+
+// lookupVersion returns the SLIP-0132 version bytes the passed private or
+// public extended key ID belongs to.
+func lookupVersion(id []byte) (slip0132Version, bool) {
+	for _, version := range slip0132Versions {
+		if bytes.Equal(id, version.privID[:]) ||
+			bytes.Equal(id, version.pubID[:]) {
+
+			return version, true
+		}
+	}
+	return slip0132Version{}, false
+}
+
+// VersionForScriptType returns the extended key ID used on the passed network
+// for extended keys of the passed script type.  The standard BIP0032 version
+// bytes of the network are returned for ScriptTypeP2PKH.  The result may be
+// used with CloneWithVersion to convert between the standard and SLIP-0132
+// serializations of an extended key.
+//
+// The ErrUnknownHDKeyID error from chaincfg is returned when the network does
+// not define version bytes for the script type.
+func VersionForScriptType(net *chaincfg.Params, scriptType ScriptType,
+	private bool) ([]byte, error) {
+
+	if scriptType == ScriptTypeP2PKH {
+		if private {
+			return append([]byte(nil), net.HDPrivateKeyID[:]...), nil
+		}
+		return append([]byte(nil), net.HDPublicKeyID[:]...), nil
+	}
+
+	for _, version := range slip0132Versions {
+		if version.netPrivID != net.HDPrivateKeyID ||
+			version.scriptType != scriptType {
+
+			continue
+		}
+		if private {
+			return append([]byte(nil), version.privID[:]...), nil
+		}
+		return append([]byte(nil), version.pubID[:]...), nil
+	}
+	return nil, chaincfg.ErrUnknownHDKeyID
+}
+
+// ScriptType returns the script type signalled by the version bytes of the
+// extended key.  Keys with version bytes which are not known SLIP-0132 version
+// bytes, including the standard version bytes of every network and any custom
+// version bytes, are reported as ScriptTypeP2PKH.
+func (k *ExtendedKey) ScriptType() ScriptType {
+	if version, ok := lookupVersion(k.version); ok {
+		return version.scriptType
+	}
+	return ScriptTypeP2PKH
+}
+
+func init() {
+	// Register the SLIP-0132 version bytes so the private keys using them
+	// can be neutered.
+	for i := range slip0132Versions {
+		version := &slip0132Versions[i]
+		err := chaincfg.RegisterHDKeyID(version.pubID[:],
+			version.privID[:])
+		if err != nil {
+			panic(fmt.Sprintf("failed to register SLIP-0132 version "+
+				"bytes: %v", err))
+		}
+	}
+}
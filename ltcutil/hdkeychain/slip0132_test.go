@@ -0,0 +1,251 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hdkeychain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+)
+
+// TestSLIP0132 ensures extended keys using SLIP-0132 version bytes serialize
+// with the expected prefixes, can be neutered, and are associated with the
+// expected networks and script types.
+func TestSLIP0132(t *testing.T) {
+	t.Parallel()
+
+	const xprv = "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"
+	const xpub = "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw"
+
+	tests := []struct {
+		net        *chaincfg.Params
+		scriptType ScriptType
+		privPrefix string
+		pubPrefix  string
+	}{
+		{&chaincfg.MainNetParams, ScriptTypeP2PKH, "xprv", "xpub"},
+		{&chaincfg.MainNetParams, ScriptTypeP2SHP2WPKH, "yprv", "ypub"},
+		{&chaincfg.MainNetParams, ScriptTypeP2WPKH, "zprv", "zpub"},
+		{&chaincfg.MainNetParams, ScriptTypeP2SHP2WSH, "Yprv", "Ypub"},
+		{&chaincfg.MainNetParams, ScriptTypeP2WSH, "Zprv", "Zpub"},
+		{&chaincfg.TestNet4Params, ScriptTypeP2PKH, "tprv", "tpub"},
+		{&chaincfg.TestNet4Params, ScriptTypeP2SHP2WPKH, "uprv", "upub"},
+		{&chaincfg.TestNet4Params, ScriptTypeP2WPKH, "vprv", "vpub"},
+		{&chaincfg.TestNet4Params, ScriptTypeP2SHP2WSH, "Uprv", "Upub"},
+		{&chaincfg.RegressionNetParams, ScriptTypeP2WSH, "Vprv", "Vpub"},
+	}
+
+	standard, err := NewKeyFromString(xprv)
+	if err != nil {
+		t.Fatalf("NewKeyFromString: unexpected error: %v", err)
+	}
+	standardPub, err := NewKeyFromString(xpub)
+	if err != nil {
+		t.Fatalf("NewKeyFromString: unexpected error: %v", err)
+	}
+
+	for _, test := range tests {
+		privVersion, err := VersionForScriptType(test.net,
+			test.scriptType, true)
+		if err != nil {
+			t.Errorf("%s: VersionForScriptType: unexpected error: %v",
+				test.privPrefix, err)
+			continue
+		}
+		privKey, err := standard.CloneWithVersion(privVersion)
+		if err != nil {
+			t.Errorf("%s: CloneWithVersion: unexpected error: %v",
+				test.privPrefix, err)
+			continue
+		}
+
+		privStr := privKey.String()
+		if !strings.HasPrefix(privStr, test.privPrefix) {
+			t.Errorf("%s: unexpected serialized key %s",
+				test.privPrefix, privStr)
+			continue
+		}
+
+		// Round trip the key through its serialization.
+		privKey, err = NewKeyFromString(privStr)
+		if err != nil {
+			t.Errorf("%s: NewKeyFromString: unexpected error: %v",
+				test.privPrefix, err)
+			continue
+		}
+		if got := privKey.ScriptType(); got != test.scriptType {
+			t.Errorf("%s: unexpected script type %v, want %v",
+				test.privPrefix, got, test.scriptType)
+		}
+		if !privKey.IsForNet(test.net) {
+			t.Errorf("%s: key is not for network %s",
+				test.privPrefix, test.net.Name)
+		}
+		if privKey.IsForNet(&chaincfg.SimNetParams) {
+			t.Errorf("%s: key is for network %s", test.privPrefix,
+				chaincfg.SimNetParams.Name)
+		}
+
+		// Neutering uses the matching public version bytes and keeps
+		// the key material.
+		pubKey, err := privKey.Neuter()
+		if err != nil {
+			t.Errorf("%s: Neuter: unexpected error: %v",
+				test.privPrefix, err)
+			continue
+		}
+		pubStr := pubKey.String()
+		if !strings.HasPrefix(pubStr, test.pubPrefix) {
+			t.Errorf("%s: unexpected neutered key %s",
+				test.privPrefix, pubStr)
+		}
+		pubVersion, err := VersionForScriptType(test.net,
+			test.scriptType, false)
+		if err != nil {
+			t.Errorf("%s: VersionForScriptType: unexpected error: %v",
+				test.pubPrefix, err)
+			continue
+		}
+		if !bytes.Equal(pubKey.Version(), pubVersion) {
+			t.Errorf("%s: unexpected neutered version %x, want %x",
+				test.privPrefix, pubKey.Version(), pubVersion)
+		}
+		wantPub, _ := standardPub.CloneWithVersion(pubVersion)
+		if pubStr != wantPub.String() {
+			t.Errorf("%s: neutered key %s does not match %s",
+				test.privPrefix, pubStr, wantPub.String())
+		}
+	}
+
+	// Moving a key to another network keeps its script type.
+	zprvVersion, _ := VersionForScriptType(&chaincfg.MainNetParams,
+		ScriptTypeP2WPKH, true)
+	key, _ := standard.CloneWithVersion(zprvVersion)
+	key.SetNet(&chaincfg.TestNet4Params)
+	if str := key.String(); !strings.HasPrefix(str, "vprv") {
+		t.Fatalf("unexpected key after changing network: %s", str)
+	}
+
+	// Networks without SLIP-0132 version bytes only support the standard
+	// version bytes.
+	_, err = VersionForScriptType(&chaincfg.SimNetParams, ScriptTypeP2WPKH,
+		true)
+	if err != chaincfg.ErrUnknownHDKeyID {
+		t.Fatalf("unexpected error for unknown version bytes: %v", err)
+	}
+}
+
+// TestCustomVersion ensures extended keys using custom version bytes can only
+// be neutered once the version bytes are registered.
+func TestCustomVersion(t *testing.T) {
+	const xprv = "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"
+	privID := []byte{0x04, 0x00, 0x00, 0x01}
+	pubID := []byte{0x04, 0x00, 0x00, 0x02}
+
+	standard, err := NewKeyFromString(xprv)
+	if err != nil {
+		t.Fatalf("NewKeyFromString: unexpected error: %v", err)
+	}
+	key, err := standard.CloneWithVersion(privID)
+	if err != nil {
+		t.Fatalf("CloneWithVersion: unexpected error: %v", err)
+	}
+	if _, err := key.Neuter(); err != chaincfg.ErrUnknownHDKeyID {
+		t.Fatalf("unexpected error neutering unregistered key: %v", err)
+	}
+	if key.ScriptType() != ScriptTypeP2PKH {
+		t.Fatalf("unexpected script type %v", key.ScriptType())
+	}
+
+	if err := chaincfg.RegisterHDKeyID(pubID, privID); err != nil {
+		t.Fatalf("RegisterHDKeyID: unexpected error: %v", err)
+	}
+	pubKey, err := key.Neuter()
+	if err != nil {
+		t.Fatalf("Neuter: unexpected error: %v", err)
+	}
+	if !bytes.Equal(pubKey.Version(), pubID) {
+		t.Fatalf("unexpected neutered version %x", pubKey.Version())
+	}
+
+	if _, err := key.CloneWithVersion([]byte{0x04}); err !=
+		chaincfg.ErrUnknownHDKeyID {
+
+		t.Fatalf("unexpected error cloning with invalid version: %v",
+			err)
+	}
+}
+
+// TestNewKeyFromStringAny ensures the network of standard and SLIP-0132
+// extended keys is identified.
+func TestNewKeyFromStringAny(t *testing.T) {
+	t.Parallel()
+
+	const xprv = "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7"
+	standard, err := NewKeyFromString(xprv)
+	if err != nil {
+		t.Fatalf("NewKeyFromString: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		net        *chaincfg.Params
+		scriptType ScriptType
+		private    bool
+		want       *chaincfg.Params
+	}{
+		{"xprv", &chaincfg.MainNetParams, ScriptTypeP2PKH, true,
+			&chaincfg.MainNetParams},
+		{"zpub", &chaincfg.MainNetParams, ScriptTypeP2WPKH, false,
+			&chaincfg.MainNetParams},
+		{"spub", &chaincfg.SimNetParams, ScriptTypeP2PKH, false,
+			&chaincfg.SimNetParams},
+		{"vprv", &chaincfg.TestNet4Params, ScriptTypeP2WPKH, true,
+			&chaincfg.TestNet4Params},
+
+		// The regression test network shares its version bytes with
+		// the test network, which was registered first.
+		{"tpub", &chaincfg.RegressionNetParams, ScriptTypeP2PKH, false,
+			&chaincfg.TestNet4Params},
+	}
+
+	for _, test := range tests {
+		version, err := VersionForScriptType(test.net, test.scriptType,
+			test.private)
+		if err != nil {
+			t.Fatalf("%s: VersionForScriptType: unexpected error: %v",
+				test.name, err)
+		}
+		key := standard
+		if !test.private {
+			key, _ = standard.Neuter()
+		}
+		key, _ = key.CloneWithVersion(version)
+
+		decoded, net, err := NewKeyFromStringAny(key.String())
+		if err != nil {
+			t.Errorf("%s: NewKeyFromStringAny: unexpected error: %v",
+				test.name, err)
+			continue
+		}
+		if net != test.want {
+			t.Errorf("%s: got network %s, want %s", test.name,
+				net.Name, test.want.Name)
+		}
+		if decoded.String() != key.String() {
+			t.Errorf("%s: decoded key %s does not match %s",
+				test.name, decoded.String(), key.String())
+		}
+	}
+
+	// Keys with unregistered version bytes are rejected.
+	key, _ := standard.CloneWithVersion([]byte{0x04, 0xff, 0xff, 0xff})
+	_, _, err = NewKeyFromStringAny(key.String())
+	if err != chaincfg.ErrUnknownHDKeyID {
+		t.Fatalf("unexpected error for unknown version bytes: %v", err)
+	}
+}
@@ -145,3 +145,15 @@ func TstAddressTaprootSAddr(addr string) []byte {
 	}
 	return data
 }
+
+// TstEncodeSegWitAddress exposes encodeSegWitAddress for testing.
+func TstEncodeSegWitAddress(hrp string, witnessVersion byte,
+	witnessProgram []byte) (string, error) {
+
+	return encodeSegWitAddress(hrp, witnessVersion, witnessProgram)
+}
+
+// TstDecodeSegWitAddress exposes decodeSegWitAddress for testing.
+func TstDecodeSegWitAddress(address string) (byte, []byte, error) {
+	return decodeSegWitAddress(address)
+}
@@ -2,11 +2,25 @@ package mweb
 
 import (
 	"encoding/binary"
+	"errors"
 
+	"github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
 	"github.com/ltcsuite/ltcd/ltcutil/mweb/mw"
 	"lukechampine.com/blake3"
 )
 
+const (
+	// KeychainAccount and KeychainChain are the hardened child indexes
+	// of the BIP 32 path m/0'/100' from which MWEB keys are derived.
+	KeychainAccount = hdkeychain.HardenedKeyStart + 0
+	KeychainChain   = hdkeychain.HardenedKeyStart + 100
+
+	// ScanKeyIndex and SpendKeyIndex are the hardened child indexes of
+	// m/0'/100' holding the scan and spend secrets respectively.
+	ScanKeyIndex  = hdkeychain.HardenedKeyStart + 0
+	SpendKeyIndex = hdkeychain.HardenedKeyStart + 1
+)
+
 type Keychain struct {
 	Scan, Spend *mw.SecretKey
 	SpendPubKey *mw.PublicKey
@@ -32,3 +46,55 @@ func (k *Keychain) Address(index uint32) *mw.StealthAddress {
 func (k *Keychain) SpendKey(index uint32) *mw.SecretKey {
 	return k.Spend.Add(k.mi(index))
 }
+
+// DeriveKeychain derives the MWEB scan and spend secrets from the passed BIP 32
+// master private key, using the same m/0'/100'/0' and m/0'/100'/1' paths as
+// Litecoin Core wallets.
+func DeriveKeychain(master *hdkeychain.ExtendedKey) (*Keychain, error) {
+	if !master.IsPrivate() {
+		return nil, errors.New("mweb keys require a private master key")
+	}
+
+	chain, err := master.Derive(KeychainAccount)
+	if err != nil {
+		return nil, err
+	}
+	chain, err = chain.Derive(KeychainChain)
+	if err != nil {
+		return nil, err
+	}
+
+	derive := func(index uint32) (*mw.SecretKey, error) {
+		child, err := chain.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+		privKey, err := child.ECPrivKey()
+		if err != nil {
+			return nil, err
+		}
+		return (*mw.SecretKey)(privKey.Serialize()), nil
+	}
+
+	scan, err := derive(ScanKeyIndex)
+	if err != nil {
+		return nil, err
+	}
+	spend, err := derive(SpendKeyIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keychain{Scan: scan, Spend: spend}, nil
+}
+
+// WatchOnly returns a copy of the keychain without the spend secret.  The
+// returned keychain can still generate addresses and detect incoming outputs,
+// but not compute the keys needed to spend them.
+func (k *Keychain) WatchOnly() *Keychain {
+	spendPubKey := k.SpendPubKey
+	if spendPubKey == nil {
+		spendPubKey = k.Spend.PubKey()
+	}
+	return &Keychain{Scan: k.Scan, SpendPubKey: spendPubKey}
+}
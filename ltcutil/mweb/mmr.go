@@ -0,0 +1,91 @@
+package mweb
+
+import (
+	"errors"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// Mmr is the append-only Merkle mountain range of the output IDs of the MWEB
+// outputs, whose root is committed to by the output root of the MWEB header.
+// The hashes of all of its nodes are kept, including those above spent
+// outputs, so that any range of unspent outputs can be proven.
+type Mmr struct {
+	nodes  []chainhash.Hash
+	leaves uint64
+}
+
+// Leaves returns the number of outputs added to the MMR.
+func (m *Mmr) Leaves() uint64 {
+	return m.leaves
+}
+
+// Add appends the output with the passed output ID to the MMR.
+func (m *Mmr) Add(outputId *chainhash.Hash) {
+	i := nodeIdx(len(m.nodes))
+	m.nodes = append(m.nodes, *i.hash(outputId[:]))
+	m.leaves++
+
+	// Add the parents of the peaks merged by the new leaf.
+	for i = nodeIdx(len(m.nodes)); i.height() > 0; i = nodeIdx(len(m.nodes)) {
+		left, right := m.nodes[i.left(i.height())], m.nodes[i.right()]
+		m.nodes = append(m.nodes, *i.parentHash(left[:], right[:]))
+	}
+}
+
+// Copy returns a copy of the MMR which outputs can be added to without
+// affecting the original.
+func (m *Mmr) Copy() *Mmr {
+	// The nodes are never modified once added, so the copy shares them
+	// with the original until it grows.
+	return &Mmr{
+		nodes:  m.nodes[:len(m.nodes):len(m.nodes)],
+		leaves: m.leaves,
+	}
+}
+
+func (m *Mmr) bagPeaks(nextNodeIdx nodeIdx, peaks []nodeIdx) *chainhash.Hash {
+	baggedPeak := m.nodes[peaks[len(peaks)-1]]
+	for i := len(peaks) - 2; i >= 0; i-- {
+		baggedPeak = *nextNodeIdx.parentHash(m.nodes[peaks[i]][:],
+			baggedPeak[:])
+	}
+	return &baggedPeak
+}
+
+// Root returns the root of the MMR, which is the zero hash when it is empty.
+func (m *Mmr) Root() *chainhash.Hash {
+	if m.leaves == 0 {
+		return &chainhash.Hash{}
+	}
+	nextNodeIdx := leafIdx(m.leaves).nodeIdx()
+	return m.bagPeaks(nextNodeIdx, calcPeaks(uint64(nextNodeIdx)))
+}
+
+// ProveUtxos sets the proof hashes of the passed unspent outputs, which must
+// be consecutive unspent leaves of the passed leafset of the MMR, so that they
+// can be verified against the root of the MMR with VerifyUtxos.
+func (m *Mmr) ProveUtxos(leafset *Leafset, mwebUtxos *wire.MsgMwebUtxos) error {
+	mwebUtxos.ProofHashes = nil
+	if leafset.Size != m.leaves {
+		return errors.New("leafset does not match the MMR")
+	}
+	if len(mwebUtxos.Utxos) == 0 {
+		return nil
+	}
+
+	v := newVerifyUtxosVars(leafset, mwebUtxos)
+	if v == nil {
+		return errors.New("outputs are not consecutive unspent leaves")
+	}
+	v.mmr = m
+
+	nextNodeIdx := leafIdx(leafset.Size).nodeIdx()
+	if v.calcPeakHashes(nextNodeIdx, calcPeaks(uint64(nextNodeIdx))) == nil {
+		mwebUtxos.ProofHashes = nil
+		return errors.New("unable to prove outputs")
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package mweb_test
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil/mweb"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestMmrProveUtxos ensures batches of unspent outputs proven by the MMR are
+// accepted by VerifyUtxos against its root, and rejected once tampered with.
+func TestMmrProveUtxos(t *testing.T) {
+	var empty mweb.Mmr
+	if *empty.Root() != (chainhash.Hash{}) {
+		t.Fatalf("empty MMR has root %v", empty.Root())
+	}
+
+	for _, size := range []uint64{1, 2, 3, 7, 8, 9, 100} {
+		mmr := &mweb.Mmr{}
+		ids := make([]chainhash.Hash, size)
+		leafset := &mweb.Leafset{Bits: make([]byte, (size+7)/8), Size: size}
+		for i := range ids {
+			ids[i] = chainhash.DoubleHashH([]byte{byte(i)})
+			mmr.Add(&ids[i])
+
+			// Every third output is spent, except for the last one.
+			if i%3 != 1 || uint64(i) == size-1 {
+				leafset.Bits[i/8] |= 0x80 >> (i % 8)
+			}
+		}
+		if mmr.Leaves() != size {
+			t.Fatalf("size %d: MMR has %d leaves", size, mmr.Leaves())
+		}
+		header := &wire.MwebHeader{OutputRoot: *mmr.Root()}
+
+		// Copies of the MMR are unaffected by the original growing.
+		copied := mmr.Copy()
+		mmr.Add(&chainhash.Hash{})
+		if *copied.Root() != header.OutputRoot {
+			t.Fatalf("size %d: copy changed with the original", size)
+		}
+		mmr = copied
+
+		for _, batch := range []int{1, 2, 5, 4096} {
+			for start := uint64(0); start < size; start++ {
+				if !leafset.Contains(start) {
+					continue
+				}
+				msg := wire.NewMsgMwebUtxos(chainhash.Hash{}, start,
+					wire.MwebNetUtxoHashOnly)
+				for i := start; i < size && len(msg.Utxos) < batch; i++ {
+					if leafset.Contains(i) {
+						msg.Utxos = append(msg.Utxos,
+							&wire.MwebNetUtxo{
+								LeafIndex: i,
+								OutputId:  &ids[i],
+							})
+					}
+				}
+
+				if err := mmr.ProveUtxos(leafset, msg); err != nil {
+					t.Fatalf("size %d, start %d, batch %d: "+
+						"ProveUtxos: %v", size, start, batch,
+						err)
+				}
+				if !mweb.VerifyUtxos(header, leafset, msg) {
+					t.Fatalf("size %d, start %d, batch %d: "+
+						"proof rejected", size, start, batch)
+				}
+
+				other := chainhash.Hash{0x01}
+				msg.Utxos[0].OutputId = &other
+				if mweb.VerifyUtxos(header, leafset, msg) {
+					t.Fatalf("size %d, start %d, batch %d: "+
+						"tampered proof accepted", size, start,
+						batch)
+				}
+			}
+		}
+
+		// Spent outputs can not be proven.
+		if size > 2 {
+			msg := wire.NewMsgMwebUtxos(chainhash.Hash{}, 1,
+				wire.MwebNetUtxoHashOnly)
+			msg.Utxos = []*wire.MwebNetUtxo{
+				{LeafIndex: 1, OutputId: &ids[1]},
+			}
+			if err := mmr.ProveUtxos(leafset, msg); err == nil {
+				t.Fatalf("size %d: spent output proven", size)
+			}
+		}
+	}
+}
@@ -29,7 +29,9 @@ func generatorH() *secp256k1.JacobianPoint {
 func NewCommitment(blind *BlindingFactor, value uint64) *Commitment {
 	var vs secp256k1.ModNScalar
 	var bj, rj secp256k1.JacobianPoint
-	vs.SetByteSlice(binary.BigEndian.AppendUint64(nil, value))
+	var valueBytes [8]byte
+	binary.BigEndian.PutUint64(valueBytes[:], value)
+	vs.SetByteSlice(valueBytes[:])
 	secp256k1.ScalarBaseMultNonConst(blind.scalar(), &bj)
 	secp256k1.ScalarMultNonConst(&vs, generatorH(), &rj)
 	secp256k1.AddNonConst(&bj, &rj, &rj)
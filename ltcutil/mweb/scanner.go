@@ -0,0 +1,63 @@
+package mweb
+
+import (
+	"github.com/ltcsuite/ltcd/ltcutil/mweb/mw"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// Scanner detects MWEB outputs sent to the stealth addresses of a keychain.
+// It keeps a table mapping the spend pubkey of each address to its index,
+// so ownership of an output is determined with a single rewind rather than
+// by regenerating every address.
+type Scanner struct {
+	keychain *Keychain
+	indexes  map[mw.PublicKey]uint32
+	next     uint32
+}
+
+// NewScanner returns a scanner recognizing the first numAddresses addresses
+// of the passed keychain.
+func NewScanner(keychain *Keychain, numAddresses uint32) *Scanner {
+	s := &Scanner{
+		keychain: keychain,
+		indexes:  make(map[mw.PublicKey]uint32, numAddresses),
+	}
+	s.Extend(numAddresses)
+	return s
+}
+
+// Extend grows the set of recognized addresses to the first numAddresses
+// addresses of the keychain.  It does nothing if the scanner already
+// recognizes at least that many.
+func (s *Scanner) Extend(numAddresses uint32) {
+	for ; s.next < numAddresses; s.next++ {
+		addr := s.keychain.Address(s.next)
+		s.indexes[*addr.Spend] = s.next
+	}
+}
+
+// NumAddresses returns the number of addresses recognized by the scanner.
+func (s *Scanner) NumAddresses() uint32 {
+	return s.next
+}
+
+// Scan checks whether the output was sent to one of the recognized addresses.
+// When it was, the rewound coin and the index of the receiving address are
+// returned along with true.  The coin's spend key is populated when the
+// keychain holds the spend secret.
+func (s *Scanner) Scan(output *wire.MwebOutput) (*Coin, uint32, bool) {
+	coin, err := RewindOutput(output, s.keychain.Scan)
+	if err != nil {
+		return nil, 0, false
+	}
+
+	index, ok := s.indexes[*coin.Address.Spend]
+	if !ok {
+		return nil, 0, false
+	}
+
+	if s.keychain.Spend != nil {
+		coin.CalculateOutputKey(s.keychain.SpendKey(index))
+	}
+	return coin, index, true
+}
@@ -0,0 +1,103 @@
+package mweb_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
+	"github.com/ltcsuite/ltcd/ltcutil/mweb"
+	"github.com/ltcsuite/ltcd/ltcutil/mweb/mw"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+func TestScanner(t *testing.T) {
+	outputRawBytes, _ := hex.DecodeString(outputRawBytes)
+	output := &wire.MwebOutput{}
+	output.Deserialize(bytes.NewReader(outputRawBytes))
+	scan, _ := hex.DecodeString(scanKeyBytes)
+	spend, _ := hex.DecodeString(spendKeyBytes)
+	keys := &mweb.Keychain{Scan: (*mw.SecretKey)(scan), Spend: (*mw.SecretKey)(spend)}
+
+	// The output pays to address 0, so it isn't recognized until the
+	// scanner covers that index.
+	scanner := mweb.NewScanner(keys, 0)
+	if _, _, ok := scanner.Scan(output); ok {
+		t.Fatal("output recognized without any addresses")
+	}
+	scanner.Extend(5)
+	if scanner.NumAddresses() != 5 {
+		t.Fatalf("unexpected address count %d", scanner.NumAddresses())
+	}
+	coin, index, ok := scanner.Scan(output)
+	if !ok {
+		t.Fatal("output not recognized")
+	}
+	if index != 0 {
+		t.Errorf("unexpected address index %d", index)
+	}
+	if coin.SpendKey == nil || *coin.SpendKey.PubKey() != output.ReceiverPubKey {
+		t.Error("spend key does not match output receiver pubkey")
+	}
+
+	// Watch-only keychains detect the output without the spend key.
+	coin, index, ok = mweb.NewScanner(keys.WatchOnly(), 5).Scan(output)
+	if !ok || index != 0 {
+		t.Fatal("output not recognized by watch-only keychain")
+	}
+	if coin.SpendKey != nil {
+		t.Error("unexpected spend key for watch-only keychain")
+	}
+
+	// A keychain with a different scan secret doesn't own the output.
+	other := &mweb.Keychain{Scan: keys.Spend, Spend: keys.Scan}
+	if _, _, ok := mweb.NewScanner(other, 5).Scan(output); ok {
+		t.Error("output recognized by unrelated keychain")
+	}
+}
+
+func TestDeriveKeychain(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewMaster failed: %v", err)
+	}
+	keys, err := mweb.DeriveKeychain(master)
+	if err != nil {
+		t.Fatalf("DeriveKeychain failed: %v", err)
+	}
+
+	// Derive m/0'/100'/0' and m/0'/100'/1' independently.
+	for _, test := range []struct {
+		index uint32
+		key   *mw.SecretKey
+	}{
+		{mweb.ScanKeyIndex, keys.Scan},
+		{mweb.SpendKeyIndex, keys.Spend},
+	} {
+		child := master
+		for _, i := range []uint32{mweb.KeychainAccount,
+			mweb.KeychainChain, test.index} {
+
+			if child, err = child.Derive(i); err != nil {
+				t.Fatalf("Derive failed: %v", err)
+			}
+		}
+		privKey, _ := child.ECPrivKey()
+		if !bytes.Equal(privKey.Serialize(), test.key[:]) {
+			t.Errorf("unexpected key for index %x", test.index)
+		}
+	}
+
+	// Addresses generated by the watch-only keychain must match.
+	if !keys.Address(3).Equal(keys.WatchOnly().Address(3)) {
+		t.Error("watch-only address mismatch")
+	}
+
+	// Public master keys can't derive the hardened path.
+	pub, _ := master.Neuter()
+	if _, err := mweb.DeriveKeychain(pub); err == nil {
+		t.Error("expected error deriving from public key")
+	}
+}
@@ -97,6 +97,37 @@ type verifyUtxosVars struct {
 	firstLeafIdx, lastLeafIdx leafIdx
 	leavesUsed, hashesUsed    int
 	isProofHash               map[nodeIdx]bool
+
+	// mmr is set when proving rather than verifying the utxos, in which
+	// case the proof hashes are taken from it as they are needed.
+	mmr *Mmr
+}
+
+func newVerifyUtxosVars(leafset *Leafset,
+	mwebUtxos *wire.MsgMwebUtxos) *verifyUtxosVars {
+
+	v := &verifyUtxosVars{
+		mwebUtxos:    mwebUtxos,
+		leafset:      leafset,
+		firstLeafIdx: leafIdx(mwebUtxos.StartIndex),
+		lastLeafIdx:  leafIdx(mwebUtxos.StartIndex),
+		isProofHash:  make(map[nodeIdx]bool),
+	}
+
+	for i := 0; ; i++ {
+		if !v.leafset.contains(v.lastLeafIdx) {
+			return nil
+		}
+		if leafIdx(mwebUtxos.Utxos[i].LeafIndex) != v.lastLeafIdx {
+			return nil
+		}
+		if i == len(mwebUtxos.Utxos)-1 {
+			break
+		}
+		v.lastLeafIdx = v.leafset.nextUnspent(v.lastLeafIdx)
+	}
+
+	return v
 }
 
 func (v *verifyUtxosVars) nextLeaf() (
@@ -115,6 +146,10 @@ func (v *verifyUtxosVars) nextLeaf() (
 func (v *verifyUtxosVars) nextHash(
 	nodeIdx nodeIdx) (hash *chainhash.Hash) {
 
+	if v.mmr != nil && v.hashesUsed == len(v.mwebUtxos.ProofHashes) {
+		hash := v.mmr.nodes[nodeIdx]
+		v.mwebUtxos.ProofHashes = append(v.mwebUtxos.ProofHashes, &hash)
+	}
 	if v.hashesUsed == len(v.mwebUtxos.ProofHashes) {
 		return
 	}
@@ -163,6 +198,53 @@ func (v *verifyUtxosVars) calcNodeHash(
 	return nodeIdx.parentHash(left[:], right[:])
 }
 
+// nextBaggedPeak returns the next proof hash, which is the bagged hash of the
+// passed peaks to the right of the peak holding the last leaf.
+func (v *verifyUtxosVars) nextBaggedPeak(nextNodeIdx nodeIdx,
+	peaks []nodeIdx) *chainhash.Hash {
+
+	if v.mmr != nil && v.hashesUsed == len(v.mwebUtxos.ProofHashes) {
+		v.mwebUtxos.ProofHashes = append(v.mwebUtxos.ProofHashes,
+			v.mmr.bagPeaks(nextNodeIdx, peaks))
+	}
+	return v.nextHash(nextNodeIdx)
+}
+
+func (v *verifyUtxosVars) calcPeakHashes(nextNodeIdx nodeIdx,
+	peaks []nodeIdx) (peakHashes []*chainhash.Hash) {
+
+	v.leavesUsed = 0
+	v.hashesUsed = 0
+
+	for i, peakNodeIdx := range peaks {
+		peakHash := v.calcNodeHash(peakNodeIdx, peakNodeIdx.height())
+		if peakHash == nil {
+			peakHash = v.nextHash(peakNodeIdx)
+			if peakHash == nil {
+				return nil
+			}
+		}
+		peakHashes = append(peakHashes, peakHash)
+		if v.lastLeafIdx.nodeIdx() <= peakNodeIdx {
+			if i != len(peaks)-1 {
+				baggedPeak := v.nextBaggedPeak(nextNodeIdx,
+					peaks[i+1:])
+				if baggedPeak == nil {
+					return nil
+				}
+				peakHashes = append(peakHashes, baggedPeak)
+			}
+			break
+		}
+	}
+	if v.leavesUsed != len(v.mwebUtxos.Utxos) ||
+		v.hashesUsed != len(v.mwebUtxos.ProofHashes) {
+		return nil
+	}
+
+	return peakHashes
+}
+
 func VerifyUtxos(mwebHeader *wire.MwebHeader,
 	leafset *Leafset, mwebUtxos *wire.MsgMwebUtxos) bool {
 
@@ -174,25 +256,9 @@ func VerifyUtxos(mwebHeader *wire.MwebHeader,
 		return false
 	}
 
-	v := &verifyUtxosVars{
-		mwebUtxos:    mwebUtxos,
-		leafset:      leafset,
-		firstLeafIdx: leafIdx(mwebUtxos.StartIndex),
-		lastLeafIdx:  leafIdx(mwebUtxos.StartIndex),
-		isProofHash:  make(map[nodeIdx]bool),
-	}
-
-	for i := 0; ; i++ {
-		if !v.leafset.contains(v.lastLeafIdx) {
-			return false
-		}
-		if leafIdx(mwebUtxos.Utxos[i].LeafIndex) != v.lastLeafIdx {
-			return false
-		}
-		if i == len(mwebUtxos.Utxos)-1 {
-			break
-		}
-		v.lastLeafIdx = v.leafset.nextUnspent(v.lastLeafIdx)
+	v := newVerifyUtxosVars(leafset, mwebUtxos)
+	if v == nil {
+		return false
 	}
 
 	var (
@@ -201,32 +267,8 @@ func VerifyUtxos(mwebHeader *wire.MwebHeader,
 		peakHashes  []*chainhash.Hash
 	)
 	for i := 0; i < 2; i++ {
-		peakHashes = nil
-		v.leavesUsed = 0
-		v.hashesUsed = 0
-
-		for _, peakNodeIdx := range peaks {
-			peakHash := v.calcNodeHash(peakNodeIdx, peakNodeIdx.height())
-			if peakHash == nil {
-				peakHash = v.nextHash(peakNodeIdx)
-				if peakHash == nil {
-					return false
-				}
-			}
-			peakHashes = append(peakHashes, peakHash)
-			if v.lastLeafIdx.nodeIdx() <= peakNodeIdx {
-				if peakNodeIdx != peaks[len(peaks)-1] {
-					baggedPeak := v.nextHash(nextNodeIdx)
-					if baggedPeak == nil {
-						return false
-					}
-					peakHashes = append(peakHashes, baggedPeak)
-				}
-				break
-			}
-		}
-		if v.leavesUsed != len(v.mwebUtxos.Utxos) ||
-			v.hashesUsed != len(v.mwebUtxos.ProofHashes) {
+		peakHashes = v.calcPeakHashes(nextNodeIdx, peaks)
+		if peakHashes == nil {
 			return false
 		}
 	}
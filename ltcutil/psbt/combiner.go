@@ -0,0 +1,162 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package psbt
+
+// The Combiner merges several PSBTs for the same transaction, which were
+// typically updated and signed independently by different participants, into
+// a single PSBT holding the union of all of their key-value pairs.
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrDifferentTransactions is returned when the PSBTs passed to Combine do not
+// describe the same transaction.
+var ErrDifferentTransactions = errors.New("Cannot combine PSBTs for " +
+	"different transactions")
+
+// Combine merges the passed packets into a new packet containing the union of
+// their global, input, output, and kernel key-value pairs, as specified by the
+// Combiner role of BIP 174.  When several packets contain the same key, the
+// value from the first packet is kept.  All packets must describe the same
+// underlying transaction, otherwise ErrDifferentTransactions is returned.  The
+// passed packets are not modified.
+func Combine(packets ...*Packet) (*Packet, error) {
+	if len(packets) == 0 {
+		return nil, ErrInvalidPsbtFormat
+	}
+
+	first := packets[0]
+	for _, p := range packets[1:] {
+		if !sameTransaction(first, p) {
+			return nil, ErrDifferentTransactions
+		}
+	}
+
+	// There is one global map followed by one map per input, output, and
+	// kernel.
+	numMaps := 1 + len(first.Inputs) + len(first.Outputs) +
+		len(first.Kernels)
+	merged := make([]*kvMap, numMaps)
+	for i := range merged {
+		merged[i] = newKVMap()
+	}
+
+	for _, p := range packets {
+		var buf bytes.Buffer
+		if err := p.Serialize(&buf); err != nil {
+			return nil, err
+		}
+
+		// Skip the magic bytes which were just written by Serialize.
+		r := bytes.NewReader(buf.Bytes()[psbtMagicLength:])
+		for _, m := range merged {
+			if err := m.read(r); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var combined bytes.Buffer
+	combined.Write(psbtMagic[:])
+	for _, m := range merged {
+		if err := m.write(&combined); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewFromRawBytes(&combined, false)
+}
+
+// sameTransaction returns whether the two packets describe the same underlying
+// transaction.
+func sameTransaction(a, b *Packet) bool {
+	if a.PsbtVersion != b.PsbtVersion ||
+		len(a.Inputs) != len(b.Inputs) ||
+		len(a.Outputs) != len(b.Outputs) ||
+		len(a.Kernels) != len(b.Kernels) {
+
+		return false
+	}
+
+	if a.PsbtVersion == 0 {
+		return a.UnsignedTx.TxHash() == b.UnsignedTx.TxHash()
+	}
+
+	if a.TxVersion != b.TxVersion {
+		return false
+	}
+	for i := range a.Inputs {
+		ai, bi := &a.Inputs[i], &b.Inputs[i]
+		if !ai.PrevoutHash.IsEqual(bi.PrevoutHash) ||
+			!ai.MwebOutputId.IsEqual(bi.MwebOutputId) {
+
+			return false
+		}
+		if (ai.PrevoutIndex == nil) != (bi.PrevoutIndex == nil) ||
+			(ai.PrevoutIndex != nil &&
+				*ai.PrevoutIndex != *bi.PrevoutIndex) {
+
+			return false
+		}
+	}
+	for i := range a.Outputs {
+		ao, bo := &a.Outputs[i], &b.Outputs[i]
+		if ao.Amount != bo.Amount ||
+			!bytes.Equal(ao.PKScript, bo.PKScript) ||
+			ao.isMWEB() != bo.isMWEB() {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// kvMap is an ordered set of raw PSBT key-value pairs keyed by their full
+// serialized key.
+type kvMap struct {
+	keys   [][]byte
+	values map[string][]byte
+}
+
+// newKVMap returns an empty kvMap.
+func newKVMap() *kvMap {
+	return &kvMap{values: make(map[string][]byte)}
+}
+
+// read adds the key-value pairs of the next map in r, up to and including its
+// separator, keeping existing values for keys that are already present.
+func (m *kvMap) read(r *bytes.Reader) error {
+	for {
+		kv, err := getKVPair(r)
+		if err != nil {
+			return err
+		}
+		if kv == nil {
+			return nil
+		}
+
+		key := append([]byte{kv.keyType}, kv.keyData...)
+		if _, ok := m.values[string(key)]; ok {
+			continue
+		}
+		m.keys = append(m.keys, key)
+		m.values[string(key)] = kv.valueData
+	}
+}
+
+// write serializes the key-value pairs in insertion order followed by the map
+// separator.
+func (m *kvMap) write(w *bytes.Buffer) error {
+	for _, key := range m.keys {
+		if err := serializeKVpair(w, key, m.values[string(key)]); err != nil {
+			return err
+		}
+	}
+
+	return w.WriteByte(0x00)
+}
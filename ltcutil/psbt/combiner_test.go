@@ -0,0 +1,84 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package psbt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// copyPacket returns a deep copy of the passed packet by round tripping it
+// through its serialization.
+func copyPacket(t *testing.T, p *Packet) *Packet {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, p.Serialize(&buf))
+	c, err := NewFromRawBytes(&buf, false)
+	require.NoError(t, err)
+	return c
+}
+
+// TestCombine ensures independently updated packets for the same transaction
+// are merged into the union of their key-value pairs.
+func TestCombine(t *testing.T) {
+	prevOut := wire.NewOutPoint(&chainhash.Hash{0x01}, 1)
+	txOut := wire.NewTxOut(1e8, []byte{
+		0x00, 0x14, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12,
+		0x13, 0x14,
+	})
+	base, err := New(
+		[]*wire.OutPoint{prevOut}, []*wire.TxOut{txOut}, 2, 0,
+		[]uint32{wire.MaxTxInSequenceNum},
+	)
+	require.NoError(t, err)
+
+	// The first participant adds the witness utxo and a global unknown.
+	a := copyPacket(t, base)
+	a.Inputs[0].WitnessUtxo = wire.NewTxOut(2e8, txOut.PkScript)
+	a.Unknowns = []*Unknown{{Key: []byte{0xf0, 0x01}, Value: []byte{1}}}
+
+	// The second participant adds an input unknown and a conflicting
+	// value for the same global unknown.
+	b := copyPacket(t, base)
+	b.Inputs[0].Unknowns = []*Unknown{
+		{Key: []byte{0xf0, 0x02}, Value: []byte{2}},
+	}
+	b.Unknowns = []*Unknown{{Key: []byte{0xf0, 0x01}, Value: []byte{9}}}
+
+	combined, err := Combine(a, b)
+	require.NoError(t, err)
+	require.Equal(t, base.UnsignedTx.TxHash(), combined.UnsignedTx.TxHash())
+	require.NotNil(t, combined.Inputs[0].WitnessUtxo)
+	require.Equal(t, int64(2e8), combined.Inputs[0].WitnessUtxo.Value)
+	require.Len(t, combined.Inputs[0].Unknowns, 1)
+	require.Equal(t, []byte{2}, combined.Inputs[0].Unknowns[0].Value)
+
+	// The value of the first packet wins for duplicate keys.
+	require.Len(t, combined.Unknowns, 1)
+	require.Equal(t, []byte{1}, combined.Unknowns[0].Value)
+
+	// The inputs must not have been modified.
+	require.Nil(t, b.Inputs[0].WitnessUtxo)
+	require.Empty(t, a.Inputs[0].Unknowns)
+
+	// Packets for different transactions can't be combined.
+	other, err := New(
+		[]*wire.OutPoint{wire.NewOutPoint(&chainhash.Hash{0x02}, 0)},
+		[]*wire.TxOut{txOut}, 2, 0, []uint32{wire.MaxTxInSequenceNum},
+	)
+	require.NoError(t, err)
+	_, err = Combine(a, other)
+	require.ErrorIs(t, err, ErrDifferentTransactions)
+
+	// At least one packet is required.
+	_, err = Combine()
+	require.ErrorIs(t, err, ErrInvalidPsbtFormat)
+}
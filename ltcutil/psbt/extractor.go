@@ -94,7 +94,7 @@ func ExtractUnsignedTx(p *Packet) (*wire.MsgTx, error) {
 		}
 
 		for _, po := range p.Outputs {
-			if !po.isMWEB() {
+			if !po.isMWEB() && !po.isPegout() {
 				txout := wire.TxOut{Value: int64(po.Amount), PkScript: po.PKScript}
 				tx.AddTxOut(&txout)
 			}
@@ -137,8 +137,9 @@ func extractV2(p *Packet) (*wire.MsgTx, error) {
 		}
 	}
 
+	// Peg-out outputs are committed to by their kernels instead.
 	for _, output := range p.Outputs {
-		if !output.isMWEB() {
+		if !output.isMWEB() && !output.isPegout() {
 			txout := wire.TxOut{Value: int64(output.Amount), PkScript: output.PKScript}
 			tx.AddTxOut(&txout)
 		}
@@ -215,10 +215,17 @@ func (pi *PInput) deserialize(r io.Reader, psbtVersion uint32) error {
 			return ErrDuplicateKey
 		}
 
-		// Check if kvPair.keyType is allowed for psbtVersion
+		// Check if kvPair.keyType is allowed for psbtVersion.  The
+		// fields of later versions have no key data, so keys of their
+		// types with key data are unknown fields of a v0 PSBT, as in
+		// the BIP 174 test vectors.
 		inputType := InputType(kvPair.keyType)
 		if !pi.isAllowed(psbtVersion, inputType) {
-			return ErrUnsupportedFieldInPsbtVersion
+			if psbtVersion != 0 || kvPair.keyData == nil {
+				return ErrUnsupportedFieldInPsbtVersion
+			}
+			pi.Unknowns = append(pi.Unknowns, kvPair.unknown())
+			continue
 		}
 
 		switch InputType(kvPair.keyType) {
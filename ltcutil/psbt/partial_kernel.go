@@ -200,8 +200,10 @@ func (pk *PKernel) serialize(w io.Writer) error {
 
 	// Kernel Fee
 	if pk.Fee != nil {
+		var fee [8]byte
+		binary.LittleEndian.PutUint64(fee[:], uint64(*pk.Fee))
 		err := serializeKVPairWithType(
-			w, uint8(MwebKernelFeeType), nil, binary.LittleEndian.AppendUint64(nil, uint64(*pk.Fee)),
+			w, uint8(MwebKernelFeeType), nil, fee[:],
 		)
 		if err != nil {
 			return err
@@ -210,9 +212,10 @@ func (pk *PKernel) serialize(w io.Writer) error {
 
 	// Peg-ins
 	if pk.PeginAmount != nil {
+		var peginAmount [8]byte
+		binary.LittleEndian.PutUint64(peginAmount[:], uint64(*pk.PeginAmount))
 		err := serializeKVPairWithType(
-			w, uint8(MwebKernelPeginAmountType), nil,
-			binary.LittleEndian.AppendUint64(nil, uint64(*pk.PeginAmount)),
+			w, uint8(MwebKernelPeginAmountType), nil, peginAmount[:],
 		)
 		if err != nil {
 			return err
@@ -227,8 +230,10 @@ func (pk *PKernel) serialize(w io.Writer) error {
 			if err != nil {
 				return err
 			}
+			var value [8]byte
+			binary.LittleEndian.PutUint64(value[:], uint64(pegout.Value))
 			var valueData bytes.Buffer
-			_, err = valueData.Write(binary.LittleEndian.AppendUint64(nil, uint64(pegout.Value)))
+			_, err = valueData.Write(value[:])
 			if err != nil {
 				return err
 			}
@@ -246,8 +251,10 @@ func (pk *PKernel) serialize(w io.Writer) error {
 
 	// Lock Height
 	if pk.LockHeight != nil {
+		var lockHeight [4]byte
+		binary.LittleEndian.PutUint32(lockHeight[:], uint32(*pk.LockHeight))
 		err := serializeKVPairWithType(
-			w, uint8(MwebKernelLockHeightType), nil, binary.LittleEndian.AppendUint32(nil, uint32(*pk.LockHeight)),
+			w, uint8(MwebKernelLockHeightType), nil, lockHeight[:],
 		)
 		if err != nil {
 			return err
@@ -1,6 +1,7 @@
 package psbt
 
 import (
+	"bytes"
 	"encoding/binary"
 	"io"
 	"sort"
@@ -31,6 +32,7 @@ type POutput struct {
 	RangeProof             *secp256k1.RangeProof
 	MwebSignature          *mw.Signature
 	MwebExtraData          []byte
+	MwebPegoutKernel       *uint32
 	Unknowns               []*Unknown
 }
 
@@ -57,6 +59,11 @@ func (po *POutput) isMWEB() bool {
 	return po.StealthAddress != nil || po.OutputCommit != nil
 }
 
+// isPegout returns true if the output is paid by a peg-out of an MWEB kernel.
+func (po *POutput) isPegout() bool {
+	return po.MwebPegoutKernel != nil
+}
+
 func (po *POutput) isFinalized() bool {
 	return !po.isMWEB() || po.MwebSignature != nil
 }
@@ -78,6 +85,12 @@ func (po *POutput) isSane(psbtVersion uint32) bool {
 		}
 	}
 
+	// Peg-outs pay transparent outputs, so they can't be MWEB outputs, and
+	// only PSBTv2 has kernels to peg them out.
+	if po.isPegout() && (psbtVersion < 2 || po.isMWEB()) {
+		return false
+	}
+
 	if po.isMWEB() {
 		if po.StealthAddress == nil && po.OutputCommit == nil {
 			return false
@@ -153,10 +166,17 @@ func (po *POutput) deserialize(r io.Reader, psbtVersion uint32) error {
 			return ErrDuplicateKey
 		}
 
-		// Check if kvPair.keyType is allowed for psbtVersion
+		// Check if kvPair.keyType is allowed for psbtVersion.  The
+		// fields of later versions have no key data, so keys of their
+		// types with key data are unknown fields of a v0 PSBT, as in
+		// the BIP 174 test vectors.
 		outputType := OutputType(kvPair.keyType)
 		if !po.isAllowed(psbtVersion, outputType) {
-			return ErrUnsupportedFieldInPsbtVersion
+			if psbtVersion != 0 || kvPair.keyData == nil {
+				return ErrUnsupportedFieldInPsbtVersion
+			}
+			po.Unknowns = append(po.Unknowns, kvPair.unknown())
+			continue
 		}
 
 		switch OutputType(kvPair.keyType) {
@@ -332,6 +352,21 @@ func (po *POutput) deserialize(r io.Reader, psbtVersion uint32) error {
 				return ErrInvalidKeyData
 			}
 			po.MwebExtraData = kvPair.valueData
+		case ProprietaryOutputType:
+			// Only the peg-out kernel key is known, and only in
+			// PSBTs which have kernels.
+			if psbtVersion == 0 || !bytes.Equal(kvPair.keyData,
+				proprietaryKeyData(MwebPegoutKernelSubtype)) {
+
+				po.Unknowns = append(po.Unknowns, kvPair.unknown())
+				continue
+			}
+			if len(kvPair.valueData) != 4 {
+				return ErrInvalidPsbtFormat
+			}
+
+			kernelIndex := binary.LittleEndian.Uint32(kvPair.valueData)
+			po.MwebPegoutKernel = &kernelIndex
 		default:
 			// A fall through case for any proprietary types.
 			keyCodeAndData := append(
@@ -489,7 +524,9 @@ func (po *POutput) serialize(w io.Writer, psbtVersion uint32) error {
 		if po.MwebStandardFields != nil {
 			valueData := po.MwebStandardFields.KeyExchangePubkey[:]
 			valueData = append(valueData, po.MwebStandardFields.ViewTag)
-			valueData = binary.LittleEndian.AppendUint64(valueData, po.MwebStandardFields.EncryptedValue)
+			var encryptedValue [8]byte
+			binary.LittleEndian.PutUint64(encryptedValue[:], po.MwebStandardFields.EncryptedValue)
+			valueData = append(valueData, encryptedValue[:]...)
 			valueData = append(valueData, po.MwebStandardFields.EncryptedNonce[:]...)
 			err := serializeKVPairWithType(w, uint8(MwebStandardFieldsOutputType), nil, valueData)
 			if err != nil {
@@ -510,6 +547,16 @@ func (po *POutput) serialize(w io.Writer, psbtVersion uint32) error {
 				return err
 			}
 		}
+
+		if po.MwebPegoutKernel != nil {
+			var kernelIndex [4]byte
+			binary.LittleEndian.PutUint32(kernelIndex[:], *po.MwebPegoutKernel)
+			err := serializeKVPairWithType(w, uint8(ProprietaryOutputType),
+				proprietaryKeyData(MwebPegoutKernelSubtype), kernelIndex[:])
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Unknown is a special case; we don't have a key type, only a key and a value field
@@ -333,7 +333,11 @@ func NewFromRawBytes(r io.Reader, b64 bool) (*Packet, error) {
 		switch globalType {
 		case UnsignedTxType:
 			// UnsignedTxType should've already been parsed above
-			return nil, ErrInvalidPsbtFormat
+			// since it must be the first key.  Repeats of it are
+			// rejected by the duplicate key check.
+			if msgTx == nil {
+				return nil, ErrInvalidPsbtFormat
+			}
 		case XpubType:
 			if len(kvPair.keyData) != BIP32_EXTKEY_WITH_VERSION_SIZE {
 				return nil, ErrInvalidKeyData
@@ -745,6 +749,13 @@ func (p *Packet) SanityCheck() error {
 		if !output.isSane(p.PsbtVersion) {
 			return ErrInvalidPsbtFormat
 		}
+
+		// Peg-outs must refer to one of the kernels of the PSBT.
+		if output.isPegout() &&
+			*output.MwebPegoutKernel >= uint32(len(p.Kernels)) {
+
+			return ErrInvalidPsbtFormat
+		}
 	}
 
 	return nil
@@ -759,7 +770,7 @@ func (p *Packet) GetTxFee() (ltcutil.Amount, error) {
 	}
 
 	var sumOutputs int64
-	for _, txOut := range p.UnsignedTx.TxOut {
+	for _, txOut := range p.BuildTxOuts() {
 		sumOutputs += txOut.Value
 	}
 
@@ -793,6 +804,21 @@ func (p *Packet) HasMwebComponents() bool {
 	return false
 }
 
+// pegouts returns the transparent outputs pegged out by the kernel at the
+// passed index.
+func (p *Packet) pegouts(kernelIndex uint32) []*wire.TxOut {
+	var pegouts []*wire.TxOut
+	for _, output := range p.Outputs {
+		if output.isPegout() && *output.MwebPegoutKernel == kernelIndex {
+			pegouts = append(pegouts, &wire.TxOut{
+				Value:    int64(output.Amount),
+				PkScript: output.PKScript,
+			})
+		}
+	}
+	return pegouts
+}
+
 func (p *Packet) getPrevOut(i int) (*wire.OutPoint, *chainhash.Hash) {
 	if p.PsbtVersion == 0 {
 		if p.UnsignedTx == nil || len(p.UnsignedTx.TxIn) < i {
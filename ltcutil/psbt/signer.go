@@ -58,7 +58,9 @@ const (
 func (u *Updater) Sign(inIndex int, sig []byte, pubKey []byte,
 	redeemScript []byte, witnessScript []byte) (SignOutcome, error) {
 
-	pInput := u.Upsbt.Inputs[inIndex]
+	// The input is referenced rather than copied so the scripts and the
+	// witness utxo added below are seen when deciding how to sign it.
+	pInput := &u.Upsbt.Inputs[inIndex]
 	if pInput.isFinalized() {
 		return SignFinalized, nil
 	}
@@ -260,6 +262,12 @@ func (s *Signer) SignMwebComponents() (SignOutcome, error) {
 			continue
 		}
 
+		// The peg-outs of the kernel default to the outputs it pegs
+		// out.
+		if len(kernel.PegOuts) == 0 {
+			kernel.PegOuts = p.pegouts(uint32(i))
+		}
+
 		kernelBlind, kernelStealthKey, err := signMwebKernel(kernel)
 		if err != nil {
 			return SignInvalid, err
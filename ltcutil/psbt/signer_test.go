@@ -1,6 +1,8 @@
 package psbt
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/binary"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/ltcutil"
@@ -9,6 +11,7 @@ import (
 	"github.com/ltcsuite/ltcd/wire"
 	"lukechampine.com/blake3"
 	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -168,7 +171,7 @@ func TestSignMwebComponents(t *testing.T) {
 	}
 
 	mwebInputSigner := BasicMwebInputSigner{
-		Keychain:           mwebKeychain,
+		Keychain:           &mwebKeychain,
 		LookupAddressIndex: NaiveAddressLookup,
 	}
 	signer, err := NewSigner(packet, mwebInputSigner)
@@ -188,3 +191,90 @@ func TestSignMwebComponents(t *testing.T) {
 
 	// TODO(dburkett) Verify all signatures.
 }
+
+func TestSignMwebPegouts(t *testing.T) {
+	masterScanKey, _ := mw.NewSecretKey()
+	masterSpendKey, _ := mw.NewSecretKey()
+	mwebKeychain := mweb.Keychain{Scan: masterScanKey, Spend: masterSpendKey}
+
+	pi := generateUnsignedPInput(wire.MwebInputStealthKeyFeatureBit, *mwebKeychain.Address(10))
+
+	kernelIndex := uint32(0)
+	pkScript := []byte{0x76, 0xa9, 0x14, 0x20, 0x88, 0xac} // basic P2PKH
+	po := POutput{
+		Amount:           100000,
+		PKScript:         pkScript,
+		MwebPegoutKernel: &kernelIndex,
+	}
+
+	fee := ltcutil.Amount(10000)
+	packet := &Packet{
+		PsbtVersion: 2,
+		Inputs:      []PInput{*pi},
+		Outputs:     []POutput{po},
+		Kernels:     []PKernel{{Fee: &fee}},
+	}
+
+	// The peg-out field survives a round trip through the serialization.
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		t.Fatalf("B64Encode failed: %v", err)
+	}
+	decoded, err := NewFromRawBytes(strings.NewReader(encoded), true)
+	if err != nil {
+		t.Fatalf("NewFromRawBytes failed: %v", err)
+	}
+	if idx := decoded.Outputs[0].MwebPegoutKernel; idx == nil || *idx != kernelIndex {
+		t.Fatalf("peg-out kernel index was not decoded")
+	}
+
+	// The peg-out field is serialized with a proprietary key so other PSBT
+	// implementations keep it as an unknown field.
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("DecodeString failed: %v", err)
+	}
+	key := append([]byte{0x07, 0xfc, 0x04}, []byte("dsvd\x00")...)
+	if !bytes.Contains(raw, key) {
+		t.Fatalf("peg-out kernel index not serialized with a " +
+			"proprietary key")
+	}
+
+	// Peg-outs must refer to an existing kernel.
+	badIndex := uint32(1)
+	decoded.Outputs[0].MwebPegoutKernel = &badIndex
+	if err := decoded.SanityCheck(); err != ErrInvalidPsbtFormat {
+		t.Fatalf("peg-out of a missing kernel passed sanity check: %v", err)
+	}
+
+	signer, err := NewSigner(packet, BasicMwebInputSigner{
+		Keychain:           &mwebKeychain,
+		LookupAddressIndex: NaiveAddressLookup,
+	})
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	outcome, err := signer.SignMwebComponents()
+	if outcome != SignSuccesful || err != nil {
+		t.Fatalf("SignMwebComponents failed: %v", err)
+	}
+
+	kernel := packet.Kernels[0]
+	if *kernel.Features&wire.MwebKernelPegoutFeatureBit == 0 ||
+		len(kernel.PegOuts) != 1 || kernel.PegOuts[0].Value != 100000 ||
+		!bytes.Equal(kernel.PegOuts[0].PkScript, pkScript) {
+		t.Fatalf("kernel does not peg out the output: %+v", kernel)
+	}
+
+	// The output is paid by the kernel rather than the transaction.
+	tx, err := Extract(packet)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(tx.TxOut) != 0 {
+		t.Fatalf("peg-out was added as a transparent output")
+	}
+	if len(tx.Mweb.TxBody.Kernels) != 1 || len(tx.Mweb.TxBody.Kernels[0].Pegouts) != 1 {
+		t.Fatalf("peg-out is missing from the extracted kernel")
+	}
+}
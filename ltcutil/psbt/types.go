@@ -249,6 +249,34 @@ const (
 	MwebRangeProofOutputType     OutputType = 0x96
 	MwebSignatureOutputType      OutputType = 0x97
 	MwebExtraDataOutputType      OutputType = 0x98
+
+	// ProprietaryOutputType is a custom type for use by devs.
+	//
+	// The key ({0xFC}|<prefix>|{subtype}|{key data}), is a Variable length
+	// identifier prefix, followed by a subtype, followed by the key data
+	// itself.
+	//
+	// The value is any value data as defined by the proprietary type user.
+	ProprietaryOutputType OutputType = 0xFC
+)
+
+// ProprietaryPrefix is the identifier prefix of the proprietary keys defined
+// by this package.
+const ProprietaryPrefix = "dsvd"
+
+// ProprietarySubtype is the set of subtypes of the proprietary keys with the
+// ProprietaryPrefix identifier prefix.
+type ProprietarySubtype uint64
+
+const (
+	// MwebPegoutKernelSubtype is the subtype of the proprietary output key
+	// ({0xFC}|{0x04}|"dsvd"|{0x00}) without key data.
+	//
+	// The value is the 32-bit little endian index of the kernel which pegs
+	// out the coins paid to this transparent output from the MWEB.  Such
+	// outputs are committed to by the peg-outs of the kernel rather than
+	// added to the canonical transaction.
+	MwebPegoutKernelSubtype ProprietarySubtype = 0
 )
 
 // KernelType is the set of types defined per MWEB kernel within the PSBT.
@@ -285,6 +285,25 @@ type keyValuePair struct {
 	valueData []byte
 }
 
+// proprietaryKeyData returns the key data of the proprietary key with the
+// ProprietaryPrefix identifier prefix and the passed subtype, which is
+// ({len(prefix)}|<prefix>|{subtype}).
+func proprietaryKeyData(subtype ProprietarySubtype) []byte {
+	var keyData bytes.Buffer
+	_ = wire.WriteVarString(&keyData, 0, ProprietaryPrefix)
+	_ = wire.WriteVarInt(&keyData, 0, uint64(subtype))
+	return keyData.Bytes()
+}
+
+// unknown returns the key-value pair as an unknown field, which is serialized
+// back as it was read.
+func (kv *keyValuePair) unknown() *Unknown {
+	return &Unknown{
+		Key:   append([]byte{kv.keyType}, kv.keyData...),
+		Value: kv.valueData,
+	}
+}
+
 // Returns a keyValuePair, defined by BIP-0174 as <keypair>.
 // A separator will be returned as a nil keyValuePair and error.
 // <keypair> := <key> <value>
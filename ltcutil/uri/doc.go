@@ -0,0 +1,39 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package uri implements parsing and constructing doriancoin: payment URIs as
+defined by BIP 21.
+
+# Overview
+
+A payment URI identifies an address to pay along with an optional amount and
+a label and message describing the payment, for example:
+
+	doriancoin:<address>?amount=1.5&label=Shop&message=Order%20123
+
+Parse decodes such a URI and validates the address against the parameters of
+the network the caller is on, so a URI for another network is rejected rather
+than paid.  The String method of a URI encodes it back into its textual form,
+which is how a payment processor builds the URIs it presents to its payers.
+
+# Parameters
+
+Amounts are specified in whole coins with at most eight decimal places and are
+converted to and from an ltcutil.Amount without going through floating point,
+so an amount always round trips exactly.  Labels and messages are percent
+encoded.
+
+Parameters which aren't known to this package are kept in the Params map of the
+URI, except for the parameters prefixed with "req-", which BIP 21 reserves for
+parameters the payer is required to understand, so they cause Parse to fail.
+
+# MWEB
+
+URIs for MWEB stealth addresses carry the mweb=1 flag, which lets wallets that
+don't support MWEB report that clearly instead of failing to decode the
+address.  Parse sets the MWEB field of the URI for such addresses and rejects
+the flag when it is set for an address which isn't an MWEB address.
+*/
+package uri
@@ -0,0 +1,273 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package uri
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// Scheme is the scheme of payment URIs.
+const Scheme = "doriancoin"
+
+// Names of the parameters of payment URIs which are known to this package.
+const (
+	paramAmount  = "amount"
+	paramLabel   = "label"
+	paramMessage = "message"
+	paramMWEB    = "mweb"
+)
+
+// requiredParamPrefix is the prefix of the parameters the payer is required
+// to understand.
+const requiredParamPrefix = "req-"
+
+// maxAmountDecimals is the maximum number of decimal places of an amount.
+const maxAmountDecimals = 8
+
+var (
+	// ErrInvalidScheme describes an error where a URI does not use the
+	// payment URI scheme.
+	ErrInvalidScheme = errors.New("not a " + Scheme + " URI")
+
+	// ErrWrongNetwork describes an error where the address of a URI is
+	// for another network than the one it is parsed for.
+	ErrWrongNetwork = errors.New("address is for another network")
+
+	// ErrInvalidAmount describes an error where an amount is not a
+	// positive decimal number of coins within the range of valid amounts.
+	ErrInvalidAmount = errors.New("invalid amount")
+
+	// ErrMWEBMismatch describes an error where the MWEB flag of a URI is
+	// set for an address which is not an MWEB address.
+	ErrMWEBMismatch = errors.New("MWEB flag set for a non-MWEB address")
+)
+
+// URI is a payment URI.
+type URI struct {
+	// Address is the address to pay.
+	Address ltcutil.Address
+
+	// Amount is the amount to pay, where zero means the amount is left up
+	// to the payer.
+	Amount ltcutil.Amount
+
+	// Label is the label of the address, such as the name of the payee.
+	Label string
+
+	// Message is a message describing the payment.
+	Message string
+
+	// MWEB specifies whether the address is an MWEB stealth address.
+	MWEB bool
+
+	// Params holds the parameters which are not known to this package.
+	Params map[string]string
+}
+
+// Parse parses the passed payment URI and ensures its address is valid for the
+// passed network.
+func Parse(s string, params *chaincfg.Params) (*URI, error) {
+	i := strings.Index(s, ":")
+	if i < 0 || !strings.EqualFold(s[:i], Scheme) {
+		return nil, ErrInvalidScheme
+	}
+	s = strings.TrimPrefix(s[i+1:], "//")
+
+	var query string
+	if i := strings.Index(s, "?"); i >= 0 {
+		s, query = s[:i], s[i+1:]
+	}
+
+	addr, err := ltcutil.DecodeAddress(s, params)
+	if err != nil {
+		return nil, err
+	}
+	if !addr.IsForNet(params) {
+		return nil, ErrWrongNetwork
+	}
+	_, isMWEB := addr.(*ltcutil.AddressMweb)
+	u := &URI{
+		Address: addr,
+		MWEB:    isMWEB,
+	}
+
+	seen := make(map[string]struct{})
+	for _, param := range strings.Split(query, "&") {
+		if param == "" {
+			continue
+		}
+		var rawValue string
+		name := param
+		if i := strings.Index(param, "="); i >= 0 {
+			name, rawValue = param[:i], param[i+1:]
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("parameter %q specified more "+
+				"than once", name)
+		}
+		seen[name] = struct{}{}
+		value, err := url.PathUnescape(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("malformed parameter %q: %v",
+				name, err)
+		}
+
+		switch name {
+		case paramAmount:
+			u.Amount, err = parseAmount(value)
+			if err != nil {
+				return nil, err
+			}
+
+		case paramLabel:
+			u.Label = value
+
+		case paramMessage:
+			u.Message = value
+
+		case paramMWEB:
+			switch value {
+			case "1":
+				if !isMWEB {
+					return nil, ErrMWEBMismatch
+				}
+			case "0":
+			default:
+				return nil, fmt.Errorf("malformed parameter "+
+					"%q: %q is not 0 or 1", name, value)
+			}
+
+		default:
+			if strings.HasPrefix(name, requiredParamPrefix) {
+				return nil, fmt.Errorf("unsupported required "+
+					"parameter %q", name)
+			}
+			if u.Params == nil {
+				u.Params = make(map[string]string)
+			}
+			u.Params[name] = value
+		}
+	}
+
+	return u, nil
+}
+
+// String returns the textual form of the payment URI.  The parameters are
+// encoded in a deterministic order with the known parameters first, followed by
+// the remaining parameters sorted by name.
+func (u *URI) String() string {
+	var b strings.Builder
+	b.WriteString(Scheme)
+	b.WriteString(":")
+	if u.Address != nil {
+		b.WriteString(u.Address.EncodeAddress())
+	}
+
+	sep := "?"
+	writeParam := func(name, value string) {
+		b.WriteString(sep)
+		b.WriteString(escape(name))
+		b.WriteString("=")
+		b.WriteString(escape(value))
+		sep = "&"
+	}
+	if u.Amount != 0 {
+		writeParam(paramAmount, formatAmount(u.Amount))
+	}
+	if u.Label != "" {
+		writeParam(paramLabel, u.Label)
+	}
+	if u.Message != "" {
+		writeParam(paramMessage, u.Message)
+	}
+	if u.MWEB {
+		writeParam(paramMWEB, "1")
+	}
+
+	names := make([]string, 0, len(u.Params))
+	for name := range u.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeParam(name, u.Params[name])
+	}
+
+	return b.String()
+}
+
+// escape percent encodes the passed string for use in the query of a URI.
+// Spaces are encoded as %20 rather than +, which BIP 21 doesn't define.
+func escape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+// parseAmount parses the passed decimal number of coins into an amount.
+func parseAmount(s string) (ltcutil.Amount, error) {
+	whole, frac := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" && frac == "" || len(frac) > maxAmountDecimals ||
+		!isDigits(whole) || !isDigits(frac) {
+
+		return 0, ErrInvalidAmount
+	}
+	frac += strings.Repeat("0", maxAmountDecimals-len(frac))
+
+	// The whole number of coins is limited to the maximum amount before it
+	// is scaled so the scaling can't overflow.
+	var coins, satoshi int64
+	var err error
+	if whole != "" {
+		coins, err = strconv.ParseInt(whole, 10, 64)
+		if err != nil || coins > ltcutil.MaxSatoshi/ltcutil.SatoshiPerBitcoin {
+			return 0, ErrInvalidAmount
+		}
+	}
+	satoshi, err = strconv.ParseInt(frac, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+
+	amount := ltcutil.Amount(coins*ltcutil.SatoshiPerBitcoin + satoshi)
+	if amount <= 0 || amount > ltcutil.MaxSatoshi {
+		return 0, ErrInvalidAmount
+	}
+	return amount, nil
+}
+
+// formatAmount formats the passed amount as a decimal number of coins without
+// trailing zeros.
+func formatAmount(amount ltcutil.Amount) string {
+	s := strconv.FormatInt(int64(amount), 10)
+	if len(s) <= maxAmountDecimals {
+		s = strings.Repeat("0", maxAmountDecimals-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-maxAmountDecimals], s[len(s)-maxAmountDecimals:]
+	frac = strings.TrimRight(frac, "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
+
+// isDigits returns whether the passed string only consists of decimal digits.
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
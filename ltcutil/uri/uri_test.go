@@ -0,0 +1,190 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package uri
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+)
+
+// mwebAddr is an MWEB stealth address on the test network.
+const mwebAddr = "tmweb1qqv0mlyyk7sl09jkcrgy059m5yplw567ypuj6lxpwkcw4tl8m" +
+	"59p7wq6jc6prtph5kf45kdlql8fjppr32nmwng34fs6ess9fq72ck7lfyvmr6s0c"
+
+// TestParse ensures valid payment URIs are parsed and encoded back into their
+// canonical form.
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.TestNet4Params
+	p2pkh, _ := ltcutil.NewAddressPubKeyHash(make([]byte, 20), params)
+	p2wpkh, _ := ltcutil.NewAddressWitnessPubKeyHash(make([]byte, 20), params)
+	mweb, _ := ltcutil.DecodeAddress(mwebAddr, params)
+
+	tests := []struct {
+		name      string
+		uri       string
+		want      URI
+		canonical string
+	}{
+		{
+			name:      "address only",
+			uri:       "doriancoin:" + p2pkh.EncodeAddress(),
+			want:      URI{Address: p2pkh},
+			canonical: "doriancoin:" + p2pkh.EncodeAddress(),
+		},
+		{
+			name: "all params",
+			uri: "doriancoin:" + p2wpkh.EncodeAddress() +
+				"?amount=20.3&label=Luke-Jr&message=Donation%20for" +
+				"%20project%20xyz",
+			want: URI{
+				Address: p2wpkh,
+				Amount:  2030000000,
+				Label:   "Luke-Jr",
+				Message: "Donation for project xyz",
+			},
+			canonical: "doriancoin:" + p2wpkh.EncodeAddress() +
+				"?amount=20.3&label=Luke-Jr&message=Donation%20for" +
+				"%20project%20xyz",
+		},
+		{
+			name: "uppercase scheme and slashes",
+			uri:  "DORIANCOIN://" + p2pkh.EncodeAddress() + "?amount=.00000001",
+			want: URI{Address: p2pkh, Amount: 1},
+			canonical: "doriancoin:" + p2pkh.EncodeAddress() +
+				"?amount=0.00000001",
+		},
+		{
+			name: "unknown params",
+			uri: "doriancoin:" + p2pkh.EncodeAddress() +
+				"?somethingyoudontunderstand=50&&plus=a+b&amp=%26",
+			want: URI{
+				Address: p2pkh,
+				Params: map[string]string{
+					"somethingyoudontunderstand": "50",
+					"plus":                       "a+b",
+					"amp":                        "&",
+				},
+			},
+			canonical: "doriancoin:" + p2pkh.EncodeAddress() +
+				"?amp=%26&plus=a%2Bb&somethingyoudontunderstand=50",
+		},
+		{
+			name:      "mweb address",
+			uri:       "doriancoin:" + mwebAddr + "?amount=1",
+			want:      URI{Address: mweb, Amount: 1e8, MWEB: true},
+			canonical: "doriancoin:" + mwebAddr + "?amount=1&mweb=1",
+		},
+		{
+			name:      "mweb flag",
+			uri:       "doriancoin:" + mwebAddr + "?mweb=1",
+			want:      URI{Address: mweb, MWEB: true},
+			canonical: "doriancoin:" + mwebAddr + "?mweb=1",
+		},
+		{
+			name:      "cleared mweb flag",
+			uri:       "doriancoin:" + p2pkh.EncodeAddress() + "?mweb=0",
+			want:      URI{Address: p2pkh},
+			canonical: "doriancoin:" + p2pkh.EncodeAddress(),
+		},
+	}
+
+	for _, test := range tests {
+		u, err := Parse(test.uri, params)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(*u, test.want) {
+			t.Errorf("%s: mismatched URI - got %+v, want %+v",
+				test.name, *u, test.want)
+			continue
+		}
+		if got := u.String(); got != test.canonical {
+			t.Errorf("%s: mismatched string - got %s, want %s",
+				test.name, got, test.canonical)
+		}
+	}
+}
+
+// TestParseErrors ensures malformed payment URIs and URIs for other networks
+// are rejected.
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.TestNet4Params
+	p2pkh, _ := ltcutil.NewAddressPubKeyHash(make([]byte, 20), params)
+	mainnet, _ := ltcutil.NewAddressWitnessPubKeyHash(make([]byte, 20),
+		&chaincfg.MainNetParams)
+	addr := p2pkh.EncodeAddress()
+
+	tests := []struct {
+		name string
+		uri  string
+		err  error
+	}{
+		{"other scheme", "bitcoin:" + addr, ErrInvalidScheme},
+		{"no scheme", addr, ErrInvalidScheme},
+		{"invalid address", "doriancoin:notanaddress", nil},
+		{"other network", "doriancoin:" + mainnet.EncodeAddress(), nil},
+		{"negative amount", "doriancoin:" + addr + "?amount=-1", ErrInvalidAmount},
+		{"zero amount", "doriancoin:" + addr + "?amount=0", ErrInvalidAmount},
+		{"empty amount", "doriancoin:" + addr + "?amount=.", ErrInvalidAmount},
+		{"exponent amount", "doriancoin:" + addr + "?amount=1e3", ErrInvalidAmount},
+		{"precise amount", "doriancoin:" + addr + "?amount=0.000000001", ErrInvalidAmount},
+		{"huge amount", "doriancoin:" + addr + "?amount=84000000.00000001", ErrInvalidAmount},
+		{"overflow amount", "doriancoin:" + addr + "?amount=99999999999999999999", ErrInvalidAmount},
+		{"mweb flag", "doriancoin:" + addr + "?mweb=1", ErrMWEBMismatch},
+		{"malformed mweb flag", "doriancoin:" + mwebAddr + "?mweb=yes", nil},
+		{"required param", "doriancoin:" + addr + "?req-somethingyoudontunderstand=50", nil},
+		{"duplicate param", "doriancoin:" + addr + "?label=a&label=b", nil},
+		{"malformed escape", "doriancoin:" + addr + "?label=%zz", nil},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.uri, params)
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+			continue
+		}
+		if test.err != nil && err != test.err {
+			t.Errorf("%s: got error %v, want %v", test.name, err,
+				test.err)
+		}
+	}
+}
+
+// TestAmountRoundTrip ensures amounts are formatted as decimal numbers of coins
+// which parse back into the same amount.
+func TestAmountRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		amount ltcutil.Amount
+		str    string
+	}{
+		{1, "0.00000001"},
+		{10, "0.0000001"},
+		{1e8, "1"},
+		{123456789, "1.23456789"},
+		{ltcutil.MaxSatoshi, "84000000"},
+	}
+
+	for _, test := range tests {
+		if got := formatAmount(test.amount); got != test.str {
+			t.Errorf("formatAmount(%d): got %s, want %s",
+				int64(test.amount), got, test.str)
+		}
+		got, err := parseAmount(test.str)
+		if err != nil || got != test.amount {
+			t.Errorf("parseAmount(%s): got %d (err %v), want %d",
+				test.str, int64(got), err, int64(test.amount))
+		}
+	}
+}
@@ -55,6 +55,62 @@ const (
 // so that orphans can be identified by which peer first relayed them.
 type Tag uint64
 
+// RemovalReason identifies why a transaction was removed from the memory pool.
+type RemovalReason int
+
+// These constants define the reasons a transaction can be removed from the
+// memory pool.
+const (
+	// RemovalReasonBlock indicates the transaction was included in a block
+	// connected to the main chain.
+	RemovalReasonBlock RemovalReason = iota
+
+	// RemovalReasonConflict indicates the transaction, or one of its
+	// ancestors, spends an output which was spent by a transaction in a
+	// block connected to the main chain.
+	RemovalReasonConflict
+
+	// RemovalReasonReplaced indicates the transaction, or one of its
+	// ancestors, was replaced by a transaction paying a higher fee.
+	RemovalReasonReplaced
+
+	// RemovalReasonReorg indicates the transaction depends on a transaction
+	// from a disconnected block which is no longer valid.
+	RemovalReasonReorg
+
+	// RemovalReasonExpiry indicates the transaction stayed in the memory
+	// pool for longer than it is allowed to.
+	RemovalReasonExpiry
+
+	// RemovalReasonEviction indicates the transaction was evicted to keep
+	// the memory pool within its size limit.
+	RemovalReasonEviction
+
+	// RemovalReasonManual indicates the caller removed the transaction for
+	// a reason of its own.
+	RemovalReasonManual
+)
+
+// Map of RemovalReason values back to their constant names for pretty
+// printing.
+var removalReasonStrings = map[RemovalReason]string{
+	RemovalReasonBlock:    "block",
+	RemovalReasonConflict: "conflict",
+	RemovalReasonReplaced: "replaced",
+	RemovalReasonReorg:    "reorg",
+	RemovalReasonExpiry:   "expiry",
+	RemovalReasonEviction: "eviction",
+	RemovalReasonManual:   "manual",
+}
+
+// String returns the RemovalReason as a human-readable name.
+func (r RemovalReason) String() string {
+	if s := removalReasonStrings[r]; s != "" {
+		return s
+	}
+	return fmt.Sprintf("Unknown RemovalReason (%d)", int(r))
+}
+
 // Config is a descriptor containing the memory pool configuration.
 type Config struct {
 	// Policy defines the various mempool configuration options related
@@ -92,6 +148,10 @@ type Config struct {
 	// SigCache defines a signature cache to use.
 	SigCache *txscript.SigCache
 
+	// ScriptCache defines a cache of transaction inputs whose scripts have
+	// already been validated.
+	ScriptCache *txscript.ScriptCache
+
 	// HashCache defines the transaction hash mid-state cache to use.
 	HashCache *txscript.HashCache
 
@@ -103,6 +163,12 @@ type Config struct {
 	// FeeEstimatator provides a feeEstimator. If it is not nil, the mempool
 	// records all new transactions it observes into the feeEstimator.
 	FeeEstimator *FeeEstimator
+
+	// TxRemoved defines the optional function to call whenever a
+	// transaction is removed from the pool along with the reason for its
+	// removal.  It is called with the mempool lock held, so it must not
+	// call back into the pool.
+	TxRemoved func(txDesc *TxDesc, reason RemovalReason)
 }
 
 // Policy houses the policy (configuration parameters) which is used to
@@ -466,14 +532,14 @@ func (mp *TxPool) HaveTransaction(hash *chainhash.Hash) bool {
 // RemoveTransaction.  See the comment for RemoveTransaction for more details.
 //
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *TxPool) removeTransaction(tx *ltcutil.Tx, removeRedeemers bool) {
+func (mp *TxPool) removeTransaction(tx *ltcutil.Tx, removeRedeemers bool, reason RemovalReason) {
 	txHash := tx.Hash()
 	if removeRedeemers {
 		// Remove any transactions which rely on this one.
 		for i := uint32(0); i < uint32(len(tx.MsgTx().TxOut)); i++ {
 			prevOut := wire.OutPoint{Hash: *txHash, Index: i}
 			if txRedeemer, exists := mp.outpoints[prevOut]; exists {
-				mp.removeTransaction(txRedeemer, true)
+				mp.removeTransaction(txRedeemer, true, reason)
 			}
 		}
 	}
@@ -486,25 +552,40 @@ func (mp *TxPool) removeTransaction(tx *ltcutil.Tx, removeRedeemers bool) {
 			mp.cfg.AddrIndex.RemoveUnconfirmedTx(txHash)
 		}
 
+		// The transactions which spend the removed one, such as when
+		// it was mined in a block, no longer have it as an ancestor.
+		vsize := GetTxVirtualSize(tx)
+		for hash := range mp.txDescendants(tx, nil) {
+			desc := mp.pool[hash]
+			desc.AncestorFee -= txDesc.Fee
+			desc.AncestorSize -= vsize
+			desc.AncestorCount--
+		}
+
 		// Mark the referenced outpoints as unspent by the pool.
 		for _, txIn := range txDesc.Tx.MsgTx().TxIn {
 			delete(mp.outpoints, txIn.PreviousOutPoint)
 		}
 		delete(mp.pool, *txHash)
 		atomic.StoreInt64(&mp.lastUpdated, time.Now().Unix())
+
+		if mp.cfg.TxRemoved != nil {
+			mp.cfg.TxRemoved(txDesc, reason)
+		}
 	}
 }
 
 // RemoveTransaction removes the passed transaction from the mempool. When the
 // removeRedeemers flag is set, any transactions that redeem outputs from the
 // removed transaction will also be removed recursively from the mempool, as
-// they would otherwise become orphans.
+// they would otherwise become orphans.  The reason is passed on to the
+// TxRemoved callback for every removed transaction.
 //
 // This function is safe for concurrent access.
-func (mp *TxPool) RemoveTransaction(tx *ltcutil.Tx, removeRedeemers bool) {
+func (mp *TxPool) RemoveTransaction(tx *ltcutil.Tx, removeRedeemers bool, reason RemovalReason) {
 	// Protect concurrent access.
 	mp.mtx.Lock()
-	mp.removeTransaction(tx, removeRedeemers)
+	mp.removeTransaction(tx, removeRedeemers, reason)
 	mp.mtx.Unlock()
 }
 
@@ -521,7 +602,8 @@ func (mp *TxPool) RemoveDoubleSpends(tx *ltcutil.Tx) {
 	for _, txIn := range tx.MsgTx().TxIn {
 		if txRedeemer, ok := mp.outpoints[txIn.PreviousOutPoint]; ok {
 			if !txRedeemer.Hash().IsEqual(tx.Hash()) {
-				mp.removeTransaction(txRedeemer, true)
+				mp.removeTransaction(txRedeemer, true,
+					RemovalReasonConflict)
 			}
 		}
 	}
@@ -551,6 +633,28 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint, tx *ltcutil
 	for _, txIn := range tx.MsgTx().TxIn {
 		mp.outpoints[txIn.PreviousOutPoint] = tx
 	}
+
+	// Compute the statistics of the package made of the transaction and
+	// its unconfirmed ancestors which are used to select transactions for
+	// block templates.  The transaction also becomes an ancestor of any
+	// transactions in the pool which spend it, which happens when it is
+	// added back to the pool after a block is disconnected.
+	vsize := GetTxVirtualSize(tx)
+	txD.AncestorFee = fee
+	txD.AncestorSize = vsize
+	txD.AncestorCount = 1
+	for hash := range mp.txAncestors(tx, nil) {
+		ancestor := mp.pool[hash]
+		txD.AncestorFee += ancestor.Fee
+		txD.AncestorSize += GetTxVirtualSize(ancestor.Tx)
+		txD.AncestorCount++
+	}
+	for hash := range mp.txDescendants(tx, nil) {
+		desc := mp.pool[hash]
+		desc.AncestorFee += fee
+		desc.AncestorSize += vsize
+		desc.AncestorCount++
+	}
 	atomic.StoreInt64(&mp.lastUpdated, time.Now().Unix())
 
 	// Add unconfirmed address index entries associated with the transaction
@@ -923,8 +1027,13 @@ func (mp *TxPool) validateReplacement(tx *ltcutil.Tx,
 // MaybeAcceptTransaction.  See the comment for MaybeAcceptTransaction for
 // more details.
 //
+// The trusted flag bypasses the standardness, minimum fee, priority, and free
+// transaction rate limiting policies for transactions from trusted sources.
+//
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *TxPool) maybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit, rejectDupOrphans bool) ([]*chainhash.Hash, *TxDesc, error) {
+func (mp *TxPool) maybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit, rejectDupOrphans, trusted bool) ([]*chainhash.Hash, *TxDesc, error) {
+	defer startTraceRegion(traceRegionMaybeAcceptTransaction).End()
+
 	txHash := tx.Hash()
 
 	// If a transaction has witness data, and segwit isn't active yet, If
@@ -988,7 +1097,8 @@ func (mp *TxPool) maybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit, rejec
 
 	// Don't allow non-standard transactions if the network parameters
 	// forbid their acceptance.
-	if !mp.cfg.Policy.AcceptNonStd {
+	acceptNonStd := mp.cfg.Policy.AcceptNonStd || trusted
+	if !acceptNonStd {
 		err = CheckTransactionStandard(tx, nextBlockHeight,
 			medianTimePast, mp.cfg.Policy.MinRelayTxFee,
 			mp.cfg.Policy.MaxTxVersion)
@@ -1093,10 +1203,19 @@ func (mp *TxPool) maybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit, rejec
 		return nil, nil, err
 	}
 
+	// Taproot spends are only validated and considered standard once the
+	// deployment is active.
+	taprootActive, err := mp.cfg.IsDeploymentActive(
+		chaincfg.DeploymentTaproot,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Don't allow transactions with non-standard inputs if the network
 	// parameters forbid their acceptance.
-	if !mp.cfg.Policy.AcceptNonStd {
-		err := checkInputsStandard(tx, utxoView)
+	if !acceptNonStd {
+		err := checkInputsStandard(tx, utxoView, taprootActive)
 		if err != nil {
 			// Attempt to extract a reject code from the error so
 			// it can be retained.  When not possible, fall back to
@@ -1148,7 +1267,9 @@ func (mp *TxPool) maybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit, rejec
 	serializedSize := GetTxVirtualSize(tx)
 	minFee := calcMinRequiredTxRelayFee(serializedSize,
 		mp.cfg.Policy.MinRelayTxFee)
-	if serializedSize >= (DefaultBlockPrioritySize-1000) && txFee < minFee {
+	if !trusted && serializedSize >= (DefaultBlockPrioritySize-1000) &&
+		txFee < minFee {
+
 		str := fmt.Sprintf("transaction %v has %d fees which is under "+
 			"the required amount of %d", txHash, txFee,
 			minFee)
@@ -1159,7 +1280,9 @@ func (mp *TxPool) maybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit, rejec
 	// in the next block.  Transactions which are being added back to the
 	// memory pool from blocks that have been disconnected during a reorg
 	// are exempted.
-	if isNew && !mp.cfg.Policy.DisableRelayPriority && txFee < minFee {
+	if isNew && !trusted && !mp.cfg.Policy.DisableRelayPriority &&
+		txFee < minFee {
+
 		currentPriority := mining.CalcPriority(tx.MsgTx(), utxoView,
 			nextBlockHeight)
 		if currentPriority <= mining.MinHighPriority {
@@ -1172,7 +1295,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit, rejec
 
 	// Free-to-relay transactions are rate limited here to prevent
 	// penny-flooding with tiny transactions as a form of attack.
-	if rateLimit && txFee < minFee {
+	if rateLimit && !trusted && txFee < minFee {
 		nowUnix := time.Now().Unix()
 		// Decay passed data with an exponentially decaying ~10 minute
 		// window - matches litecoind handling.
@@ -1206,8 +1329,12 @@ func (mp *TxPool) maybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit, rejec
 
 	// Verify crypto signatures for each input and reject the transaction if
 	// any don't verify.
+	scriptFlags := txscript.StandardVerifyFlags
+	if !taprootActive {
+		scriptFlags &^= txscript.ScriptVerifyTaproot
+	}
 	err = blockchain.ValidateTransactionScripts(tx, utxoView,
-		txscript.StandardVerifyFlags, mp.cfg.SigCache,
+		scriptFlags, mp.cfg.SigCache, mp.cfg.ScriptCache,
 		mp.cfg.HashCache)
 	if err != nil {
 		if cerr, ok := err.(blockchain.RuleError); ok {
@@ -1228,7 +1355,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit, rejec
 		// The conflict set should already include the descendants for
 		// each one, so we don't need to remove the redeemers within
 		// this call as they'll be removed eventually.
-		mp.removeTransaction(conflict, false)
+		mp.removeTransaction(conflict, false, RemovalReasonReplaced)
 	}
 	txD := mp.addTransaction(utxoView, tx, bestHeight, txFee)
 
@@ -1252,7 +1379,8 @@ func (mp *TxPool) maybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit, rejec
 func (mp *TxPool) MaybeAcceptTransaction(tx *ltcutil.Tx, isNew, rateLimit bool) ([]*chainhash.Hash, *TxDesc, error) {
 	// Protect concurrent access.
 	mp.mtx.Lock()
-	hashes, txD, err := mp.maybeAcceptTransaction(tx, isNew, rateLimit, true)
+	hashes, txD, err := mp.maybeAcceptTransaction(tx, isNew, rateLimit, true,
+		false)
 	mp.mtx.Unlock()
 
 	return hashes, txD, err
@@ -1295,7 +1423,7 @@ func (mp *TxPool) processOrphans(acceptedTx *ltcutil.Tx) []*TxDesc {
 			// Potentially accept an orphan into the tx pool.
 			for _, tx := range orphans {
 				missing, txD, err := mp.maybeAcceptTransaction(
-					tx, true, true, false)
+					tx, true, true, false, false)
 				if err != nil {
 					// The orphan is now invalid, so there
 					// is no way any other orphans which
@@ -1377,9 +1505,37 @@ func (mp *TxPool) ProcessTransaction(tx *ltcutil.Tx, allowOrphan, rateLimit bool
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
 
+	return mp.processTransaction(tx, allowOrphan, rateLimit, false, tag)
+}
+
+// ProcessTrustedTransaction is identical to ProcessTransaction except the
+// standardness, minimum fee, priority, and free transaction rate limiting
+// policies are not enforced.  It is intended for transactions received from
+// sources which have explicitly been granted permission to relay such
+// transactions, such as whitelisted wallet backends.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) ProcessTrustedTransaction(tx *ltcutil.Tx, allowOrphan bool, tag Tag) ([]*TxDesc, error) {
+	log.Tracef("Processing trusted transaction %v", tx.Hash())
+
+	// Protect concurrent access.
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	return mp.processTransaction(tx, allowOrphan, false, true, tag)
+}
+
+// processTransaction is the internal function which implements the public
+// ProcessTransaction and ProcessTrustedTransaction.  See the comments for
+// those functions for more details.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) processTransaction(tx *ltcutil.Tx, allowOrphan, rateLimit, trusted bool, tag Tag) ([]*TxDesc, error) {
+	defer startTraceRegion(traceRegionProcessTransaction).End()
+
 	// Potentially accept the transaction to the memory pool.
 	missingParents, txD, err := mp.maybeAcceptTransaction(tx, true, rateLimit,
-		true)
+		true, trusted)
 	if err != nil {
 		return nil, err
 	}
@@ -1480,7 +1636,11 @@ func (mp *TxPool) MiningDescs() []*mining.TxDesc {
 	descs := make([]*mining.TxDesc, len(mp.pool))
 	i := 0
 	for _, desc := range mp.pool {
-		descs[i] = &desc.TxDesc
+		// Return a copy since the package statistics of the
+		// descriptors in the pool change as transactions are added and
+		// removed.
+		miningDesc := desc.TxDesc
+		descs[i] = &miningDesc
 		i++
 	}
 	mp.mtx.RUnlock()
@@ -1545,6 +1705,49 @@ func (mp *TxPool) LastUpdated() time.Time {
 	return time.Unix(atomic.LoadInt64(&mp.lastUpdated), 0)
 }
 
+// FeeFilter returns the minimum fee rate, in satoshi per kilobyte, a
+// transaction must pay in order to be accepted into the pool without relying
+// on priority.  It is suitable for announcing to peers via the BIP0133
+// feefilter message so they don't waste bandwidth announcing transactions that
+// would be rejected anyway.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) FeeFilter() int64 {
+	mp.mtx.RLock()
+	feeFilter := int64(mp.cfg.Policy.MinRelayTxFee)
+	mp.mtx.RUnlock()
+	return feeFilter
+}
+
+// Policy returns a copy of the policy currently used by the pool.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) Policy() Policy {
+	mp.mtx.RLock()
+	policy := mp.cfg.Policy
+	mp.mtx.RUnlock()
+	return policy
+}
+
+// SetPolicy replaces the policy used by the pool, which allows it to be
+// adjusted at runtime without losing the transactions in the pool.  The new
+// policy only applies to transactions processed afterwards, with the exception
+// of the orphans, which are evicted at random until their number no longer
+// exceeds the new maximum.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) SetPolicy(policy *Policy) {
+	mp.mtx.Lock()
+	mp.cfg.Policy = *policy
+	for _, otx := range mp.orphans {
+		if len(mp.orphans) <= mp.cfg.Policy.MaxOrphanTxs {
+			break
+		}
+		mp.removeOrphan(otx.tx, false)
+	}
+	mp.mtx.Unlock()
+}
+
 // New returns a new memory pool for validating and storing standalone
 // transactions until they are mined into a block.
 func New(cfg *Config) *TxPool {
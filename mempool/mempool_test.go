@@ -26,9 +26,10 @@ import (
 // transactions to appear as though they are spending completely valid utxos.
 type fakeChain struct {
 	sync.RWMutex
-	utxos          *blockchain.UtxoViewpoint
-	currentHeight  int32
-	medianTimePast time.Time
+	utxos               *blockchain.UtxoViewpoint
+	currentHeight       int32
+	medianTimePast      time.Time
+	inactiveDeployments map[uint32]struct{}
 }
 
 // FetchUtxoView loads utxo details about the inputs referenced by the passed
@@ -95,6 +96,31 @@ func (s *fakeChain) SetMedianTimePast(mtp time.Time) {
 	s.Unlock()
 }
 
+// IsDeploymentActive returns whether the passed deployment is active for the
+// fake chain instance.  All deployments are active unless explicitly
+// deactivated with SetDeploymentActive.
+func (s *fakeChain) IsDeploymentActive(deploymentID uint32) (bool, error) {
+	s.RLock()
+	_, inactive := s.inactiveDeployments[deploymentID]
+	s.RUnlock()
+	return !inactive, nil
+}
+
+// SetDeploymentActive sets whether the passed deployment is active for the
+// fake chain instance.
+func (s *fakeChain) SetDeploymentActive(deploymentID uint32, active bool) {
+	s.Lock()
+	if active {
+		delete(s.inactiveDeployments, deploymentID)
+	} else {
+		if s.inactiveDeployments == nil {
+			s.inactiveDeployments = make(map[uint32]struct{})
+		}
+		s.inactiveDeployments[deploymentID] = struct{}{}
+	}
+	s.Unlock()
+}
+
 // CalcSequenceLock returns the current sequence lock for the passed
 // transaction associated with the fake chain instance.
 func (s *fakeChain) CalcSequenceLock(tx *ltcutil.Tx,
@@ -324,13 +350,14 @@ func newPoolHarness(chainParams *chaincfg.Params) (*poolHarness, []spendableOutp
 				MinRelayTxFee:        1000, // 1 Satoshi per byte
 				MaxTxVersion:         1,
 			},
-			ChainParams:      chainParams,
-			FetchUtxoView:    chain.FetchUtxoView,
-			BestHeight:       chain.BestHeight,
-			MedianTimePast:   chain.MedianTimePast,
-			CalcSequenceLock: chain.CalcSequenceLock,
-			SigCache:         nil,
-			AddrIndex:        nil,
+			ChainParams:        chainParams,
+			FetchUtxoView:      chain.FetchUtxoView,
+			BestHeight:         chain.BestHeight,
+			MedianTimePast:     chain.MedianTimePast,
+			CalcSequenceLock:   chain.CalcSequenceLock,
+			IsDeploymentActive: chain.IsDeploymentActive,
+			SigCache:           nil,
+			AddrIndex:          nil,
 		}),
 	}
 
@@ -632,6 +659,52 @@ func TestOrphanEviction(t *testing.T) {
 	}
 }
 
+// TestSetPolicy ensures the policy of the pool can be replaced at runtime and
+// that lowering the maximum number of orphans evicts the orphans exceeding it.
+func TestSetPolicy(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+
+	maxOrphans := uint32(harness.txPool.cfg.Policy.MaxOrphanTxs)
+	chainedTxns, err := harness.CreateTxChain(outputs[0], maxOrphans+1)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns[1:] {
+		_, err := harness.txPool.ProcessTransaction(tx, true, false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid "+
+				"orphan %v", err)
+		}
+	}
+
+	policy := harness.txPool.Policy()
+	policy.MaxOrphanTxs = 2
+	policy.MinRelayTxFee = 2 * DefaultMinRelayTxFee
+	harness.txPool.SetPolicy(&policy)
+
+	if got := harness.txPool.Policy(); got != policy {
+		t.Fatalf("Policy: got %+v, want %+v", got, policy)
+	}
+	if got := harness.txPool.FeeFilter(); got != int64(policy.MinRelayTxFee) {
+		t.Fatalf("FeeFilter: got %d, want %d", got, policy.MinRelayTxFee)
+	}
+	numOrphans := 0
+	for _, tx := range chainedTxns[1:] {
+		if harness.txPool.IsOrphanInPool(tx.Hash()) {
+			numOrphans++
+		}
+	}
+	if numOrphans != policy.MaxOrphanTxs {
+		t.Fatalf("got %d orphans after lowering the maximum, want %d",
+			numOrphans, policy.MaxOrphanTxs)
+	}
+}
+
 // TestBasicOrphanRemoval ensure that orphan removal works as expected when an
 // orphan that doesn't exist is removed  both when there is another orphan that
 // redeems it and when there is not.
@@ -1849,3 +1922,369 @@ func TestRBF(t *testing.T) {
 		}
 	}
 }
+
+// TestProcessTrustedTransaction ensures non-standard transactions are rejected
+// by ProcessTransaction but accepted when processed as trusted.
+func TestProcessTrustedTransaction(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Create a transaction with a version above the maximum allowed by
+	// the policy, which makes it non-standard.
+	tx := wire.NewMsgTx(harness.txPool.cfg.Policy.MaxTxVersion + 1)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: outputs[0].outPoint,
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	tx.AddTxOut(&wire.TxOut{
+		PkScript: harness.payScript,
+		Value:    int64(outputs[0].amount) - 1000,
+	})
+	sigScript, err := txscript.SignatureScript(tx, 0, harness.payScript,
+		txscript.SigHashAll, harness.signKey, true)
+	if err != nil {
+		t.Fatalf("unable to sign transaction: %v", err)
+	}
+	tx.TxIn[0].SignatureScript = sigScript
+	nonStdTx := ltcutil.NewTx(tx)
+
+	_, err = harness.txPool.ProcessTransaction(nonStdTx, false, true, 0)
+	if err == nil {
+		t.Fatal("ProcessTransaction: accepted non-standard transaction")
+	}
+	rerr, ok := err.(RuleError)
+	if !ok {
+		t.Fatalf("ProcessTransaction: unexpected error type %T", err)
+	}
+	if code, _ := extractRejectCode(rerr); code != wire.RejectNonstandard {
+		t.Fatalf("ProcessTransaction: unexpected reject code %v", code)
+	}
+	testPoolMembership(tc, nonStdTx, false, false)
+
+	acceptedTxns, err := harness.txPool.ProcessTrustedTransaction(
+		nonStdTx, false, 0,
+	)
+	if err != nil {
+		t.Fatalf("ProcessTrustedTransaction: unexpected error: %v", err)
+	}
+	if len(acceptedTxns) != 1 {
+		t.Fatalf("ProcessTrustedTransaction: expected one accepted "+
+			"transaction, got %d", len(acceptedTxns))
+	}
+	testPoolMembership(tc, nonStdTx, false, true)
+}
+
+// TestTaprootSpendActivation ensures spends of taproot outputs are rejected as
+// non-standard until the taproot deployment is active, and are validated
+// according to the taproot rules once it is.
+func TestTaprootSpendActivation(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Create a transaction which pays to a BIP 86 taproot output of the
+	// harness signing key and add it to the pool.
+	outputKey := txscript.ComputeTaprootKeyNoScript(
+		harness.signKey.PubKey(),
+	)
+	taprootScript, err := txscript.PayToTaprootScript(outputKey)
+	if err != nil {
+		t.Fatalf("unable to create taproot script: %v", err)
+	}
+	fundingTx := wire.NewMsgTx(wire.TxVersion)
+	fundingTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: outputs[0].outPoint,
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	fundingTx.AddTxOut(&wire.TxOut{
+		PkScript: taprootScript,
+		Value:    int64(outputs[0].amount) - 1000,
+	})
+	sigScript, err := txscript.SignatureScript(fundingTx, 0,
+		harness.payScript, txscript.SigHashAll, harness.signKey, true)
+	if err != nil {
+		t.Fatalf("unable to sign transaction: %v", err)
+	}
+	fundingTx.TxIn[0].SignatureScript = sigScript
+	_, err = harness.txPool.ProcessTransaction(
+		ltcutil.NewTx(fundingTx), false, false, 0,
+	)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: unable to add funding "+
+			"transaction: %v", err)
+	}
+
+	// createSpend returns a key path spend of the taproot output.  When
+	// corrupt is set, the signature is invalidated.
+	createSpend := func(corrupt bool) *ltcutil.Tx {
+		prevOut := fundingTx.TxOut[0]
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{
+				Hash: fundingTx.TxHash(),
+			},
+			Sequence: wire.MaxTxInSequenceNum,
+		})
+		tx.AddTxOut(&wire.TxOut{
+			PkScript: harness.payScript,
+			Value:    prevOut.Value - 1000,
+		})
+		sigHashes := txscript.NewTxSigHashes(
+			tx, txscript.NewCannedPrevOutputFetcher(
+				prevOut.PkScript, prevOut.Value,
+			),
+		)
+		witness, err := txscript.TaprootWitnessSignature(
+			tx, sigHashes, 0, prevOut.Value, prevOut.PkScript,
+			txscript.SigHashDefault, harness.signKey,
+		)
+		if err != nil {
+			t.Fatalf("unable to sign taproot input: %v", err)
+		}
+		if corrupt {
+			witness[0][0] ^= 0x01
+		}
+		tx.TxIn[0].Witness = witness
+		return ltcutil.NewTx(tx)
+	}
+
+	// The spend must be rejected as non-standard before activation.
+	harness.chain.SetDeploymentActive(chaincfg.DeploymentTaproot, false)
+	spendTx := createSpend(false)
+	_, err = harness.txPool.ProcessTransaction(spendTx, false, false, 0)
+	if err == nil {
+		t.Fatal("ProcessTransaction: accepted taproot spend before " +
+			"activation")
+	}
+	if code, _ := extractRejectCode(err); code != wire.RejectNonstandard {
+		t.Fatalf("ProcessTransaction: unexpected reject code %v", code)
+	}
+	testPoolMembership(tc, spendTx, false, false)
+
+	// Once active, an invalid signature must be rejected while a valid
+	// one is accepted.
+	harness.chain.SetDeploymentActive(chaincfg.DeploymentTaproot, true)
+	badTx := createSpend(true)
+	_, err = harness.txPool.ProcessTransaction(badTx, false, false, 0)
+	if err == nil {
+		t.Fatal("ProcessTransaction: accepted taproot spend with an " +
+			"invalid signature")
+	}
+	testPoolMembership(tc, badTx, false, false)
+
+	_, err = harness.txPool.ProcessTransaction(spendTx, false, false, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: unexpected error: %v", err)
+	}
+	testPoolMembership(tc, spendTx, false, true)
+}
+
+// TestScriptCachePopulated ensures inputs validated by the mempool are added to
+// the script cache, so they aren't validated again later.
+func TestScriptCachePopulated(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	scriptCache := txscript.NewScriptCache(10)
+	harness.txPool.cfg.ScriptCache = scriptCache
+
+	chainedTxns, err := harness.CreateTxChain(outputs[0], 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns {
+		_, err := harness.txPool.ProcessTransaction(tx, false, false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: unexpected error: %v", err)
+		}
+		testPoolMembership(tc, tx, false, true)
+	}
+
+	stats := scriptCache.Stats()
+	if stats.Entries != 2 || stats.Misses != 2 || stats.Hits != 0 {
+		t.Fatalf("unexpected script cache stats %+v", stats)
+	}
+
+	// Accepting the last transaction again after it is removed must be
+	// served from the cache.
+	lastTx := chainedTxns[1]
+	harness.txPool.RemoveTransaction(lastTx, false, RemovalReasonManual)
+	_, err = harness.txPool.ProcessTransaction(lastTx, false, false, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: unexpected error: %v", err)
+	}
+	stats = scriptCache.Stats()
+	if stats.Entries != 2 || stats.Misses != 2 || stats.Hits != 1 {
+		t.Fatalf("unexpected script cache stats %+v", stats)
+	}
+}
+
+// TestTxRemovedReasons ensures the TxRemoved callback is invoked with the
+// expected reason for every transaction removed from the pool.
+func TestTxRemovedReasons(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	removed := make(map[chainhash.Hash]RemovalReason)
+	harness.txPool.cfg.TxRemoved = func(txDesc *TxDesc, reason RemovalReason) {
+		removed[*txDesc.Tx.Hash()] = reason
+	}
+
+	chainedTxns, err := harness.CreateTxChain(outputs[0], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns {
+		_, err := harness.txPool.ProcessTransaction(tx, false, false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: unexpected error: %v", err)
+		}
+	}
+
+	// Removing the first transaction as included in a block must leave its
+	// descendants in the pool.
+	harness.txPool.RemoveTransaction(chainedTxns[0], false,
+		RemovalReasonBlock)
+	testPoolMembership(tc, chainedTxns[0], false, false)
+	testPoolMembership(tc, chainedTxns[1], false, true)
+
+	// A confirmed transaction which double spends the second transaction
+	// removes it along with its descendant as conflicts.
+	doubleSpend, err := harness.CreateSignedTx(
+		[]spendableOutput{txOutToSpendableOut(chainedTxns[0], 0)}, 2,
+		1000, false,
+	)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	harness.txPool.RemoveDoubleSpends(doubleSpend)
+	testPoolMembership(tc, chainedTxns[2], false, false)
+
+	// Replacing a transaction which signals replacement removes it as
+	// replaced.
+	coinbase := tc.addCoinbaseTx(1)
+	outs := []spendableOutput{txOutToSpendableOut(coinbase, 0)}
+	replaced := tc.addSignedTx(outs, 1, 1000, true, false)
+	replacement, err := harness.CreateSignedTx(outs, 1, 100000, false)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(replacement, false, false, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: unexpected error: %v", err)
+	}
+
+	want := map[chainhash.Hash]RemovalReason{
+		*chainedTxns[0].Hash(): RemovalReasonBlock,
+		*chainedTxns[1].Hash(): RemovalReasonConflict,
+		*chainedTxns[2].Hash(): RemovalReasonConflict,
+		*replaced.Hash():       RemovalReasonReplaced,
+	}
+	if len(removed) != len(want) {
+		t.Fatalf("got %d removed transactions, want %d", len(removed),
+			len(want))
+	}
+	for hash, reason := range want {
+		if removed[hash] != reason {
+			t.Errorf("transaction %v: got reason %v, want %v", hash,
+				removed[hash], reason)
+		}
+	}
+
+	if s := RemovalReason(100).String(); s != "Unknown RemovalReason (100)" {
+		t.Errorf("String: got %q for an unknown reason", s)
+	}
+}
+
+// TestAncestorStats ensures the package statistics of the transactions in the
+// pool are kept up to date as transactions are added and removed.
+func TestAncestorStats(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	chainedTxns, err := harness.CreateTxChain(outputs[0], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+
+	// checkStats ensures the package statistics of the passed transaction
+	// match the sum of the fees and sizes of the passed package.
+	checkStats := func(tx *ltcutil.Tx, pkg []*ltcutil.Tx) {
+		t.Helper()
+
+		txPool.mtx.RLock()
+		defer txPool.mtx.RUnlock()
+
+		var fee, size int64
+		for _, pkgTx := range pkg {
+			fee += txPool.pool[*pkgTx.Hash()].Fee
+			size += GetTxVirtualSize(pkgTx)
+		}
+		desc := txPool.pool[*tx.Hash()]
+		if desc.AncestorFee != fee || desc.AncestorSize != size ||
+			desc.AncestorCount != int64(len(pkg)) {
+
+			t.Fatalf("tx %v: got ancestor fee %d, size %d, count "+
+				"%d, want fee %d, size %d, count %d", tx.Hash(),
+				desc.AncestorFee, desc.AncestorSize,
+				desc.AncestorCount, fee, size, len(pkg))
+		}
+	}
+
+	for i, tx := range chainedTxns {
+		_, err := txPool.ProcessTransaction(tx, false, false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: unexpected error: %v", err)
+		}
+		checkStats(tx, chainedTxns[:i+1])
+	}
+
+	// The mining descriptors carry the same statistics.
+	for _, desc := range txPool.MiningDescs() {
+		if *desc.Tx.Hash() == *chainedTxns[2].Hash() &&
+			desc.AncestorCount != 3 {
+
+			t.Fatalf("MiningDescs: got ancestor count %d, want 3",
+				desc.AncestorCount)
+		}
+	}
+
+	// Removing the first transaction as included in a block removes it
+	// from the packages of its descendants.
+	txPool.RemoveTransaction(chainedTxns[0], false, RemovalReasonBlock)
+	checkStats(chainedTxns[1], chainedTxns[1:2])
+	checkStats(chainedTxns[2], chainedTxns[1:3])
+
+	// Adding it back, such as when the block is disconnected, adds it to
+	// the packages of its descendants again.
+	_, err = txPool.ProcessTransaction(chainedTxns[0], false, false, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: unexpected error: %v", err)
+	}
+	checkStats(chainedTxns[0], chainedTxns[:1])
+	checkStats(chainedTxns[1], chainedTxns[:2])
+	checkStats(chainedTxns[2], chainedTxns[:3])
+}
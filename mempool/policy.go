@@ -55,6 +55,11 @@ const (
 	// in a multi-signature transaction output script for it to be
 	// considered standard.
 	maxStandardMultiSigKeys = 3
+
+	// maxStandardTapscriptStackItemSize is the maximum size of each
+	// initial stack item of a tapscript spend for it to be considered
+	// standard.
+	maxStandardTapscriptStackItemSize = 80
 )
 
 // calcMinRequiredTxRelayFee returns the minimum transaction fee required for a
@@ -91,7 +96,13 @@ func calcMinRequiredTxRelayFee(serializedSize int64, minRelayTxFee ltcutil.Amoun
 // not perform those checks because the script engine already does this more
 // accurately and concisely via the txscript.ScriptVerifyCleanStack and
 // txscript.ScriptVerifySigPushOnly flags.
-func checkInputsStandard(tx *ltcutil.Tx, utxoView *blockchain.UtxoViewpoint) error {
+//
+// Spends of taproot outputs are only standard once the taproot deployment is
+// active, as indicated by taprootActive, and must also satisfy the taproot
+// witness policy enforced by checkTaprootWitnessStandard.
+func checkInputsStandard(tx *ltcutil.Tx, utxoView *blockchain.UtxoViewpoint,
+	taprootActive bool) error {
+
 	// NOTE: The reference implementation also does a coinbase check here,
 	// but coinbases have already been rejected prior to calling this
 	// function so no need to recheck.
@@ -114,6 +125,18 @@ func checkInputsStandard(tx *ltcutil.Tx, utxoView *blockchain.UtxoViewpoint) err
 				return txRuleError(wire.RejectNonstandard, str)
 			}
 
+		case txscript.WitnessV1TaprootTy:
+			if !taprootActive {
+				str := fmt.Sprintf("transaction input #%d "+
+					"spends a taproot output before taproot "+
+					"is active", i)
+				return txRuleError(wire.RejectNonstandard, str)
+			}
+			err := checkTaprootWitnessStandard(i, txIn.Witness)
+			if err != nil {
+				return err
+			}
+
 		case txscript.NonStandardTy:
 			str := fmt.Sprintf("transaction input #%d has a "+
 				"non-standard script form", i)
@@ -124,6 +147,63 @@ func checkInputsStandard(tx *ltcutil.Tx, utxoView *blockchain.UtxoViewpoint) err
 	return nil
 }
 
+// checkTaprootWitnessStandard ensures the witness of the taproot spend at the
+// passed input index is "standard".  A standard taproot witness does not carry
+// an annex, since no semantics are defined for it yet, and for script path
+// spends of the base tapscript leaf version, does not push any initial stack
+// item larger than maxStandardTapscriptStackItemSize.  The consensus rules are
+// enforced separately by the script engine.
+func checkTaprootWitnessStandard(idx int, witness wire.TxWitness) error {
+	if len(witness) >= 2 {
+		annex := witness[len(witness)-1]
+		if len(annex) > 0 && annex[0] == txscript.TaprootAnnexTag {
+			str := fmt.Sprintf("transaction input #%d has a "+
+				"taproot annex", idx)
+			return txRuleError(wire.RejectNonstandard, str)
+		}
+	}
+
+	switch {
+	// Key path spends consist of a single signature and have no further
+	// policy rules.
+	case len(witness) == 1:
+		return nil
+
+	// An empty witness is already invalid by the consensus rules.
+	case len(witness) == 0:
+		str := fmt.Sprintf("transaction input #%d has an empty "+
+			"taproot witness", idx)
+		return txRuleError(wire.RejectNonstandard, str)
+	}
+
+	// This is a script path spend, so the final two items are the leaf
+	// script and the control block.
+	controlBlock := witness[len(witness)-1]
+	if len(controlBlock) == 0 {
+		str := fmt.Sprintf("transaction input #%d has an empty "+
+			"taproot control block", idx)
+		return txRuleError(wire.RejectNonstandard, str)
+	}
+	leafVersion := txscript.TapscriptLeafVersion(
+		controlBlock[0] & txscript.TaprootLeafMask,
+	)
+	if leafVersion != txscript.BaseLeafVersion {
+		return nil
+	}
+	for _, item := range witness[:len(witness)-2] {
+		if len(item) > maxStandardTapscriptStackItemSize {
+			str := fmt.Sprintf("transaction input #%d has a "+
+				"tapscript stack item of %d bytes which is "+
+				"larger than the max allowed size of %d",
+				idx, len(item),
+				maxStandardTapscriptStackItemSize)
+			return txRuleError(wire.RejectNonstandard, str)
+		}
+	}
+
+	return nil
+}
+
 // checkPkScriptStandard performs a series of checks on a transaction output
 // script (public key script) to ensure it is a "standard" public key script.
 // A standard public key script is one that is a recognized form, and for
@@ -384,10 +464,5 @@ func CheckTransactionStandard(tx *ltcutil.Tx, height int32,
 // any witness data it contains, proportional to the current
 // blockchain.WitnessScaleFactor value.
 func GetTxVirtualSize(tx *ltcutil.Tx) int64 {
-	// vSize := (weight(tx) + 3) / 4
-	//       := (((baseSize * 3) + totalSize) + 3) / 4
-	// We add 3 here as a way to compute the ceiling of the prior arithmetic
-	// to 4. The division by 4 creates a discount for wit witness data.
-	return (blockchain.GetTransactionWeight(tx) + (blockchain.WitnessScaleFactor - 1)) /
-		blockchain.WitnessScaleFactor
+	return blockchain.GetTransactionVirtualSize(tx)
 }
@@ -511,3 +511,96 @@ func TestCheckTransactionStandard(t *testing.T) {
 		}
 	}
 }
+
+// TestCheckTaprootWitnessStandard tests the checkTaprootWitnessStandard API.
+func TestCheckTaprootWitnessStandard(t *testing.T) {
+	sig := bytes.Repeat([]byte{0x01}, 64)
+	script := []byte{txscript.OP_TRUE}
+	baseControlBlock := append(
+		[]byte{byte(txscript.BaseLeafVersion)},
+		bytes.Repeat([]byte{0x02}, 32)...,
+	)
+	futureControlBlock := append(
+		[]byte{0xc2}, bytes.Repeat([]byte{0x02}, 32)...,
+	)
+	annex := []byte{txscript.TaprootAnnexTag, 0x00}
+	bigItem := bytes.Repeat([]byte{0x03},
+		maxStandardTapscriptStackItemSize+1)
+
+	tests := []struct {
+		name       string
+		witness    wire.TxWitness
+		isStandard bool
+	}{
+		{
+			name:       "key path spend",
+			witness:    wire.TxWitness{sig},
+			isStandard: true,
+		},
+		{
+			name:       "empty witness",
+			witness:    wire.TxWitness{},
+			isStandard: false,
+		},
+		{
+			name:       "key path spend with annex",
+			witness:    wire.TxWitness{sig, annex},
+			isStandard: false,
+		},
+		{
+			name: "script path spend",
+			witness: wire.TxWitness{
+				sig, script, baseControlBlock,
+			},
+			isStandard: true,
+		},
+		{
+			name: "script path spend with oversized item",
+			witness: wire.TxWitness{
+				bigItem, script, baseControlBlock,
+			},
+			isStandard: false,
+		},
+		{
+			name: "future leaf version with oversized item",
+			witness: wire.TxWitness{
+				bigItem, script, futureControlBlock,
+			},
+			isStandard: true,
+		},
+		{
+			name:       "empty control block",
+			witness:    wire.TxWitness{script, {}},
+			isStandard: false,
+		},
+	}
+
+	for _, test := range tests {
+		err := checkTaprootWitnessStandard(0, test.witness)
+		if err == nil && !test.isStandard {
+			t.Errorf("checkTaprootWitnessStandard (%s): standard "+
+				"when it should not be", test.name)
+			continue
+		}
+		if err != nil && test.isStandard {
+			t.Errorf("checkTaprootWitnessStandard (%s): nonstandard "+
+				"when it should not be: %v", test.name, err)
+			continue
+		}
+		if err == nil {
+			continue
+		}
+
+		rerr, ok := err.(RuleError)
+		if !ok {
+			t.Errorf("checkTaprootWitnessStandard (%s): unexpected "+
+				"error type %T", test.name, err)
+			continue
+		}
+		txrerr, ok := rerr.Err.(TxRuleError)
+		if !ok || txrerr.RejectCode != wire.RejectNonstandard {
+			t.Errorf("checkTaprootWitnessStandard (%s): unexpected "+
+				"error %v", test.name, err)
+		}
+	}
+}
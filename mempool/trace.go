@@ -0,0 +1,25 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// The following are the types of the runtime trace regions around the stages
+// of transaction acceptance.  They are meant to be relied upon by the tools
+// used to analyze execution traces, so they must not be changed.
+const (
+	traceRegionProcessTransaction     = "mempool.processTransaction"
+	traceRegionMaybeAcceptTransaction = "mempool.maybeAcceptTransaction"
+)
+
+// startTraceRegion starts a runtime trace region of the passed type which must
+// be ended by the caller.  Regions are only recorded while an execution trace
+// is being captured and are otherwise nearly free.
+func startTraceRegion(regionType string) *trace.Region {
+	return trace.StartRegion(context.Background(), regionType)
+}
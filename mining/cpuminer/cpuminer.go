@@ -81,6 +81,11 @@ type Config struct {
 	// not current since any solved blocks would be on a side chain and and
 	// up orphaned anyways.
 	IsCurrent func() bool
+
+	// SignetSigner defines the function used to solve the signet challenge
+	// of the generated blocks.  It is only used when the chain parameters
+	// are for a signet network.
+	SignetSigner mining.SignetSigner
 }
 
 // CPUMiner provides facilities for solving blocks (mining) using the CPU in
@@ -195,6 +200,28 @@ func (m *CPUMiner) submitBlock(block *ltcutil.Block) bool {
 	return true
 }
 
+// signBlock solves the signet challenge of the passed block when mining on a
+// signet network, which must be done each time the extra nonce or timestamp of
+// the block changes.  It returns whether or not the block is ready to be
+// solved.
+func (m *CPUMiner) signBlock(msgBlock *wire.MsgBlock) bool {
+	challenge := m.cfg.ChainParams.SignetChallenge
+	if challenge == nil {
+		return true
+	}
+	if m.cfg.SignetSigner == nil {
+		log.Errorf("Unable to sign signet block: no signet signer")
+		return false
+	}
+	err := mining.SolveSignetChallenge(msgBlock, challenge,
+		m.cfg.SignetSigner)
+	if err != nil {
+		log.Errorf("Unable to sign signet block: %v", err)
+		return false
+	}
+	return true
+}
+
 // solveBlock attempts to find some combination of a nonce, extra nonce, and
 // current timestamp which makes the passed block hash to a value less than the
 // target difficulty.  The timestamp is updated periodically and the passed
@@ -233,6 +260,9 @@ func (m *CPUMiner) solveBlock(msgBlock *wire.MsgBlock, blockHeight int32,
 		// new value by regenerating the coinbase script and
 		// setting the merkle root to the new value.
 		m.g.UpdateExtraNonce(msgBlock, blockHeight, extraNonce+enOffset)
+		if !m.signBlock(msgBlock) {
+			return false
+		}
 
 		// Search through the entire nonce range for a solution while
 		// periodically checking for early quit and stale block
@@ -264,6 +294,9 @@ func (m *CPUMiner) solveBlock(msgBlock *wire.MsgBlock, blockHeight int32,
 				}
 
 				m.g.UpdateBlockTime(msgBlock)
+				if !m.signBlock(msgBlock) {
+					return false
+				}
 
 			default:
 				// Non-blocking select to fall through
@@ -627,6 +660,82 @@ func (m *CPUMiner) GenerateNBlocks(n uint32) ([]*chainhash.Hash, error) {
 	}
 }
 
+// GenerateBlock generates a single block which pays to the passed address and
+// contains exactly the passed transactions in order.  The block is timestamped
+// with the passed time, or the current adjusted time when it is the zero time,
+// which is kept while the block is solved.  The solved block is processed like
+// any other block and its hash is returned, or the reason it was rejected.
+func (m *CPUMiner) GenerateBlock(payToAddr ltcutil.Address, txns []*ltcutil.Tx,
+	ts time.Time) (*chainhash.Hash, error) {
+
+	m.Lock()
+
+	// Respond with an error if server is already mining.
+	if m.started || m.discreteMining {
+		m.Unlock()
+		return nil, errors.New("Server is already CPU mining. Please call " +
+			"`setgenerate 0` before calling discrete `generate` commands.")
+	}
+	m.discreteMining = true
+	m.Unlock()
+
+	defer func() {
+		m.Lock()
+		m.discreteMining = false
+		m.Unlock()
+	}()
+
+	// Hold the lock used for block submission until the block is processed
+	// so it can't become stale while it is being solved.
+	m.submitBlockLock.Lock()
+	defer m.submitBlockLock.Unlock()
+
+	template, err := m.g.NewBlockTemplateWithTxns(payToAddr, txns, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Search the nonce space for a solution, moving on to the next extra
+	// nonce whenever it is exhausted.  Unlike solveBlock, the timestamp is
+	// never updated.
+	msgBlock := template.Block
+	header := &msgBlock.Header
+	targetDifficulty := blockchain.CompactToBig(header.Bits)
+	for extraNonce := uint64(0); extraNonce < maxExtraNonce; extraNonce++ {
+		err := m.g.UpdateExtraNonce(msgBlock, template.Height, extraNonce)
+		if err != nil {
+			return nil, err
+		}
+		if !m.signBlock(msgBlock) {
+			return nil, errors.New("unable to sign signet block")
+		}
+
+		for nonce := uint64(0); nonce <= uint64(maxNonce); nonce++ {
+			header.Nonce = uint32(nonce)
+			hash := header.PowHash()
+			if blockchain.HashToBig(&hash).Cmp(targetDifficulty) > 0 {
+				continue
+			}
+
+			block := ltcutil.NewBlock(msgBlock)
+			isOrphan, err := m.cfg.ProcessBlock(block, blockchain.BFNone)
+			if err != nil {
+				return nil, err
+			}
+			if isOrphan {
+				return nil, errors.New("generated block is an orphan")
+			}
+
+			log.Infof("Block generated via CPU miner accepted (hash %s, "+
+				"%d transactions, timestamp %v)", block.Hash(),
+				len(msgBlock.Transactions), header.Timestamp)
+			return block.Hash(), nil
+		}
+	}
+
+	return nil, errors.New("unable to solve block")
+}
+
 // New returns a new instance of a CPU miner for the provided configuration.
 // Use Start to begin the mining process.  See the documentation for CPUMiner
 // type for more details.
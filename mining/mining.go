@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"container/heap"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ltcsuite/ltcd/blockchain"
@@ -31,6 +32,14 @@ const (
 	// and is used to monitor BIP16 support as well as blocks that are
 	// generated via ltcd.
 	CoinbaseFlags = "/P2SH/ltcd/"
+
+	// MaxCoinbaseReservedSize is the maximum number of bytes which can be
+	// reserved in the signature script of the coinbase for the extra nonces
+	// of mining pools.  It is what is left of the maximum coinbase script
+	// length after the largest possible pushes of the block height, the
+	// extra nonce, the coinbase flags and the reserved bytes themselves.
+	MaxCoinbaseReservedSize = uint32(blockchain.MaxCoinbaseScriptLen - 5 -
+		9 - (1 + len(CoinbaseFlags)) - 1)
 )
 
 // TxDesc is a descriptor about a transaction in a transaction source along with
@@ -51,6 +60,16 @@ type TxDesc struct {
 
 	// FeePerKB is the fee the transaction pays in Satoshi per 1000 bytes.
 	FeePerKB int64
+
+	// AncestorFee, AncestorSize and AncestorCount are the total fee, the
+	// total virtual size and the number of transactions of the package
+	// made of the transaction and all of its unconfirmed ancestors in the
+	// source pool.  The source pool keeps them up to date as transactions
+	// are added and removed.  When AncestorCount is zero, the statistics
+	// are computed from the transactions returned by MiningDescs instead.
+	AncestorFee   int64
+	AncestorSize  int64
+	AncestorCount int64
 }
 
 // TxSource represents a source of transactions to consider for inclusion in
@@ -80,12 +99,37 @@ type txPrioItem struct {
 	fee      int64
 	priority float64
 	feePerKB int64
+	vsize    int64
 
 	// dependsOn holds a map of transaction hashes which this one depends
 	// on.  It will only be set when the transaction references other
 	// transactions in the source pool and hence must come after them in
 	// a block.
 	dependsOn map[chainhash.Hash]struct{}
+
+	// ancestorFee and ancestorSize are the total fee and virtual size of
+	// the package made of the transaction and all of its ancestors which
+	// have not been included in the block yet.  ancestorFeePerKB is the
+	// resulting package fee in Satoshi per 1000 bytes, which is what the
+	// transaction is sorted by in a txPackageQueue.
+	ancestorFee      int64
+	ancestorSize     int64
+	ancestorFeePerKB int64
+
+	// pkgIndex is the index of the item in the txPackageQueue, or -1 when
+	// the item is not in the queue.
+	pkgIndex int
+}
+
+// setAncestorStats sets the package statistics of the item to the passed
+// total fee and virtual size of the transaction and its ancestors.
+func (item *txPrioItem) setAncestorStats(fee, vsize int64) {
+	item.ancestorFee = fee
+	item.ancestorSize = vsize
+	item.ancestorFeePerKB = 0
+	if vsize > 0 {
+		item.ancestorFeePerKB = fee * 1000 / vsize
+	}
 }
 
 // txPriorityQueueLessFunc describes a function that can be used as a compare
@@ -183,6 +227,63 @@ func newTxPriorityQueue(reserve int, sortByFee bool) *txPriorityQueue {
 	return pq
 }
 
+// txPackageQueue implements a priority queue of txPrioItem elements sorted by
+// the fee per kilobyte of their ancestor package, so a transaction paying a
+// high fee lifts the unconfirmed transactions it depends on (child pays for
+// parent).  The queue tracks the index of every item so an item can be moved
+// with heap.Fix or removed with heap.Remove when the package statistics
+// change.
+type txPackageQueue struct {
+	items []*txPrioItem
+}
+
+// Len returns the number of items in the package queue.  It is part of the
+// heap.Interface implementation.
+func (pq *txPackageQueue) Len() int {
+	return len(pq.items)
+}
+
+// Less returns whether the item in the package queue with index i should sort
+// before the item with index j.  Packages are sorted by fee per kilobyte and
+// then by size, so the smaller of two packages paying the same rate comes
+// first.  It is part of the heap.Interface implementation.
+func (pq *txPackageQueue) Less(i, j int) bool {
+	// Using > here so that pop gives the highest fee item as opposed to
+	// the lowest.
+	if pq.items[i].ancestorFeePerKB == pq.items[j].ancestorFeePerKB {
+		return pq.items[i].ancestorSize < pq.items[j].ancestorSize
+	}
+	return pq.items[i].ancestorFeePerKB > pq.items[j].ancestorFeePerKB
+}
+
+// Swap swaps the items at the passed indices in the package queue.  It is
+// part of the heap.Interface implementation.
+func (pq *txPackageQueue) Swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].pkgIndex = i
+	pq.items[j].pkgIndex = j
+}
+
+// Push pushes the passed item onto the package queue.  It is part of the
+// heap.Interface implementation.
+func (pq *txPackageQueue) Push(x interface{}) {
+	item := x.(*txPrioItem)
+	item.pkgIndex = len(pq.items)
+	pq.items = append(pq.items, item)
+}
+
+// Pop removes the item with the highest package fee per kilobyte from the
+// package queue and returns it.  It is part of the heap.Interface
+// implementation.
+func (pq *txPackageQueue) Pop() interface{} {
+	n := len(pq.items)
+	item := pq.items[n-1]
+	item.pkgIndex = -1
+	pq.items[n-1] = nil
+	pq.items = pq.items[0 : n-1]
+	return item
+}
+
 // BlockTemplate houses a block that has yet to be solved along with additional
 // details about the fees and the number of signature operations for each
 // transaction in the block.
@@ -217,6 +318,14 @@ type BlockTemplate struct {
 	// witness has been activated, and the block contains a transaction
 	// which has witness data.
 	WitnessCommitment []byte
+
+	// CoinbaseReservedSize is the number of bytes reserved for the extra
+	// nonces of mining pools in the signature script of the coinbase, and
+	// CoinbaseReservedOffset is the offset of the first reserved byte
+	// within the signature script.  The reserved bytes are all zero and
+	// may be changed freely.
+	CoinbaseReservedSize   int
+	CoinbaseReservedOffset int
 }
 
 // mergeUtxoView adds all of the entries in viewB to viewA.  The result is that
@@ -236,12 +345,48 @@ func mergeUtxoView(viewA *blockchain.UtxoViewpoint, viewB *blockchain.UtxoViewpo
 
 // standardCoinbaseScript returns a standard script suitable for use as the
 // signature script of the coinbase transaction of a new block.  In particular,
-// it starts with the block height that is required by version 2 blocks and adds
-// the extra nonce as well as additional coinbase flags.
-func standardCoinbaseScript(nextBlockHeight int32, extraNonce uint64) ([]byte, error) {
-	return txscript.NewScriptBuilder().AddInt64(int64(nextBlockHeight)).
-		AddInt64(int64(extraNonce)).AddData([]byte(CoinbaseFlags)).
-		Script()
+// it starts with the block height that is required by version 2 blocks,
+// followed by the passed number of zero bytes reserved for the extra nonces of
+// mining pools, and adds the extra nonce as well as additional coinbase flags.
+func standardCoinbaseScript(nextBlockHeight int32, reservedSize uint32,
+	extraNonce uint64) ([]byte, error) {
+
+	script, err := txscript.NewScriptBuilder().
+		AddInt64(int64(nextBlockHeight)).Script()
+	if err != nil {
+		return nil, err
+	}
+
+	// The reserved bytes are always pushed with a single data push opcode,
+	// even when a shorter encoding exists for zero bytes, so they can be
+	// set to any value without changing the layout of the script.
+	if reservedSize > 0 {
+		if reservedSize > MaxCoinbaseReservedSize {
+			return nil, fmt.Errorf("coinbase reserved size of %d "+
+				"exceeds the maximum of %d", reservedSize,
+				MaxCoinbaseReservedSize)
+		}
+		script = append(script, txscript.OP_DATA_1-1+byte(reservedSize))
+		script = append(script, make([]byte, reservedSize)...)
+	}
+
+	suffix, err := txscript.NewScriptBuilder().AddInt64(int64(extraNonce)).
+		AddData([]byte(CoinbaseFlags)).Script()
+	if err != nil {
+		return nil, err
+	}
+	return append(script, suffix...), nil
+}
+
+// coinbaseReservedOffset returns the offset of the first byte reserved for the
+// extra nonces of mining pools within the signature script of the coinbase of
+// a block at the passed height as created by standardCoinbaseScript.
+func coinbaseReservedOffset(nextBlockHeight int32) int {
+	// The reserved bytes come after the push of the height and the data
+	// push opcode of the reserved bytes.
+	heightScript, _ := txscript.NewScriptBuilder().
+		AddInt64(int64(nextBlockHeight)).Script()
+	return len(heightScript) + 1
 }
 
 // createCoinbaseTx returns a coinbase transaction paying an appropriate subsidy
@@ -314,6 +459,43 @@ func logSkippedDeps(tx *ltcutil.Tx, deps map[chainhash.Hash]*txPrioItem) {
 	}
 }
 
+// packageTxns returns the passed candidate transaction along with all of its
+// ancestors which have not been included in the block yet, ordered so every
+// transaction comes after the transactions it depends on.  It returns nil when
+// the package can't be included because one of the ancestors is not a
+// candidate for the block.
+func packageTxns(item *txPrioItem, candidates map[chainhash.Hash]*txPrioItem,
+	included map[chainhash.Hash]struct{}) []*txPrioItem {
+
+	var pkg []*txPrioItem
+	visited := make(map[chainhash.Hash]struct{})
+	var visit func(item *txPrioItem) bool
+	visit = func(item *txPrioItem) bool {
+		visited[*item.tx.Hash()] = struct{}{}
+		for hash := range item.dependsOn {
+			if _, ok := included[hash]; ok {
+				continue
+			}
+			if _, ok := visited[hash]; ok {
+				continue
+			}
+			parent, ok := candidates[hash]
+			if !ok {
+				return false
+			}
+			if !visit(parent) {
+				return false
+			}
+		}
+		pkg = append(pkg, item)
+		return true
+	}
+	if !visit(item) {
+		return nil
+	}
+	return pkg
+}
+
 // MinimumMedianTime returns the minimum allowed timestamp for a block building
 // on the end of the provided best chain.  In particular, it is one second after
 // the median timestamp of the last several blocks per the chain consensus
@@ -346,12 +528,14 @@ func medianAdjustedTime(chainState *blockchain.BestState, timeSource blockchain.
 // It also houses additional state required in order to ensure the templates
 // are built on top of the current best chain and adhere to the consensus rules.
 type BlkTmplGenerator struct {
+	policyMtx   sync.RWMutex
 	policy      *Policy
 	chainParams *chaincfg.Params
 	txSource    TxSource
 	chain       *blockchain.BlockChain
 	timeSource  blockchain.MedianTimeSource
 	sigCache    *txscript.SigCache
+	scriptCache *txscript.ScriptCache
 	hashCache   *txscript.HashCache
 }
 
@@ -365,6 +549,7 @@ func NewBlkTmplGenerator(policy *Policy, params *chaincfg.Params,
 	txSource TxSource, chain *blockchain.BlockChain,
 	timeSource blockchain.MedianTimeSource,
 	sigCache *txscript.SigCache,
+	scriptCache *txscript.ScriptCache,
 	hashCache *txscript.HashCache) *BlkTmplGenerator {
 
 	return &BlkTmplGenerator{
@@ -374,6 +559,7 @@ func NewBlkTmplGenerator(policy *Policy, params *chaincfg.Params,
 		chain:       chain,
 		timeSource:  timeSource,
 		sigCache:    sigCache,
+		scriptCache: scriptCache,
 		hashCache:   hashCache,
 	}
 }
@@ -391,32 +577,38 @@ func NewBlkTmplGenerator(policy *Policy, params *chaincfg.Params,
 // factors.  First, each transaction has a priority calculated based on its
 // value, age of inputs, and size.  Transactions which consist of larger
 // amounts, older inputs, and small sizes have the highest priority.  Second, a
-// fee per kilobyte is calculated for each transaction.  Transactions with a
-// higher fee per kilobyte are preferred.  Finally, the block generation related
-// policy settings are all taken into account.
+// fee per kilobyte is calculated for the package made of each transaction and
+// all of its unconfirmed ancestors in the source pool.  Transactions whose
+// package pays a higher fee per kilobyte are preferred, which allows a child
+// transaction paying a high fee to pull in the low-fee parents it depends on.
+// Finally, the block generation related policy settings are all taken into
+// account.
 //
-// Transactions which only spend outputs from other transactions already in the
-// block chain are immediately added to a priority queue which either
-// prioritizes based on the priority (then fee per kilobyte) or the fee per
-// kilobyte (then priority) depending on whether or not the BlockPrioritySize
-// policy setting allots space for high-priority transactions.  Transactions
-// which spend outputs from other transactions in the source pool are added to a
-// dependency map so they can be added to the priority queue once the
-// transactions they depend on have been included.
+// When the BlockPrioritySize policy setting allots space for high-priority
+// transactions, transactions which only spend outputs from other transactions
+// already in the block chain or in the block are first added to a priority
+// queue which prioritizes based on the priority (then fee per kilobyte).  Once
+// the high-priority area has been filled with transactions, or the priority
+// falls below what is considered high-priority, the remaining transactions are
+// selected by package fee per kilobyte.
 //
-// Once the high-priority area (if configured) has been filled with
-// transactions, or the priority falls below what is considered high-priority,
-// the priority queue is updated to prioritize by fees per kilobyte (then
-// priority).
+// Every remaining transaction is kept in a queue sorted by the fee per
+// kilobyte of its package.  The package of the transaction at the front of the
+// queue is included at once, ancestors first, and the package statistics of
+// the descendants of the included transactions are updated in the queue since
+// they no longer have to pay for them.  The package statistics are maintained
+// incrementally by the source pool as transactions are added and removed, see
+// TxDesc.
 //
-// When the fees per kilobyte drop below the TxMinFreeFee policy setting, the
-// transaction will be skipped unless the BlockMinSize policy setting is
-// nonzero, in which case the block will be filled with the low-fee/free
+// When the package fee per kilobyte drops below the TxMinFreeFee policy
+// setting, the package will be skipped unless the BlockMinSize policy setting
+// is nonzero, in which case the block will be filled with the low-fee/free
 // transactions until the block size reaches that minimum size.
 //
 // Any transactions which would cause the block to exceed the BlockMaxSize
 // policy setting, exceed the maximum allowed signature operations per block, or
-// otherwise cause the block to be invalid are skipped.
+// otherwise cause the block to be invalid are skipped along with the
+// transactions depending on them.
 //
 // Given the above, a block generated by this function is of the following form:
 //
@@ -430,9 +622,9 @@ func NewBlkTmplGenerator(policy *Policy, params *chaincfg.Params,
 //	|                                   |   |
 //	|                                   |   |
 //	|                                   |   |--- policy.BlockMaxSize
-//	|  Transactions prioritized by fee  |   |
-//	|  until <= policy.TxMinFreeFee     |   |
-//	|                                   |   |
+//	|  Transactions prioritized by      |   |
+//	|  package fee until                |   |
+//	|  <= policy.TxMinFreeFee           |   |
 //	|                                   |   |
 //	|                                   |   |
 //	|-----------------------------------|   |
@@ -441,39 +633,94 @@ func NewBlkTmplGenerator(policy *Policy, params *chaincfg.Params,
 //	|  <= policy.BlockMinSize)          |   |
 //	 -----------------------------------  --
 func (g *BlkTmplGenerator) NewBlockTemplate(payToAddress ltcutil.Address) (*BlockTemplate, error) {
+	return g.newBlockTemplate(payToAddress, nil)
+}
+
+// RefreshBlockTemplate returns a new block template which updates the passed
+// block template, which must build on the current best chain, with the changes
+// of the transaction source pool since it was generated.
+//
+// The transactions of the passed template which are no longer in the source
+// pool, such as conflicts and replaced transactions, are dropped along with the
+// transactions depending on them.  The remaining ones are kept as is without
+// validating them again, and the transactions which were added to the source
+// pool are then selected to fill the rest of the block the same way as
+// NewBlockTemplate does.  This makes it much faster than generating a new
+// template when most transactions are unchanged.  The coinbase of the passed
+// template is reused with its value updated for the new fees.
+func (g *BlkTmplGenerator) RefreshBlockTemplate(template *BlockTemplate) (*BlockTemplate, error) {
+	return g.newBlockTemplate(nil, template)
+}
+
+// templateCoinbase returns a copy of the coinbase transaction of the passed
+// block template without the fees of its transactions and without its witness
+// commitment, so it can be reused for an updated template.
+func templateCoinbase(template *BlockTemplate, params *chaincfg.Params) *ltcutil.Tx {
+	msgTx := template.Block.Transactions[0].Copy()
+	if template.WitnessCommitment != nil {
+		msgTx.TxIn[0].Witness = nil
+		msgTx.TxOut = msgTx.TxOut[:len(msgTx.TxOut)-1]
+	}
+	msgTx.TxOut[0].Value = blockchain.CalcBlockSubsidy(template.Height,
+		params)
+	return ltcutil.NewTx(msgTx)
+}
+
+// newBlockTemplate is the internal function which implements NewBlockTemplate
+// and RefreshBlockTemplate.  The passed base template is nil when a new block
+// template is generated.
+func (g *BlkTmplGenerator) newBlockTemplate(payToAddress ltcutil.Address,
+	base *BlockTemplate) (*BlockTemplate, error) {
+
 	// Extend the most recently known best block.
 	best := g.chain.BestSnapshot()
 	nextBlockHeight := best.Height + 1
+	policy := g.Policy()
 
 	// Create a standard coinbase transaction paying to the provided
-	// address.  NOTE: The coinbase value will be updated to include the
-	// fees from the selected transactions later after they have actually
-	// been selected.  It is created here to detect any errors early
-	// before potentially doing a lot of work below.  The extra nonce helps
-	// ensure the transaction is not a duplicate transaction (paying the
-	// same value to the same public key address would otherwise be an
-	// identical transaction for block version 1).
-	extraNonce := uint64(0)
-	coinbaseScript, err := standardCoinbaseScript(nextBlockHeight, extraNonce)
-	if err != nil {
-		return nil, err
-	}
-	coinbaseTx, err := createCoinbaseTx(g.chainParams, coinbaseScript,
-		nextBlockHeight, payToAddress)
-	if err != nil {
-		return nil, err
+	// address, or reuse the one of the base template.  NOTE: The coinbase
+	// value will be updated to include the fees from the selected
+	// transactions later after they have actually been selected.  It is
+	// created here to detect any errors early before potentially doing a
+	// lot of work below.  The extra nonce helps ensure the transaction is
+	// not a duplicate transaction (paying the same value to the same public
+	// key address would otherwise be an identical transaction for block
+	// version 1).
+	var coinbaseTx *ltcutil.Tx
+	validPayAddress := payToAddress != nil
+	coinbaseReservedSize := int(policy.CoinbaseReservedSize)
+	if base != nil {
+		if base.Block.Header.PrevBlock != best.Hash {
+			return nil, fmt.Errorf("block template builds on %v "+
+				"instead of the best chain tip %v",
+				base.Block.Header.PrevBlock, best.Hash)
+		}
+		coinbaseTx = templateCoinbase(base, g.chainParams)
+		validPayAddress = base.ValidPayAddress
+		coinbaseReservedSize = base.CoinbaseReservedSize
+	} else {
+		extraNonce := uint64(0)
+		coinbaseScript, err := standardCoinbaseScript(nextBlockHeight,
+			policy.CoinbaseReservedSize, extraNonce)
+		if err != nil {
+			return nil, err
+		}
+		coinbaseTx, err = createCoinbaseTx(g.chainParams,
+			coinbaseScript, nextBlockHeight, payToAddress)
+		if err != nil {
+			return nil, err
+		}
 	}
 	coinbaseSigOpCost := int64(blockchain.CountSigOps(coinbaseTx)) * blockchain.WitnessScaleFactor
 
 	// Get the current source transactions and create a priority queue to
 	// hold the transactions which are ready for inclusion into a block
 	// along with some priority related and fee metadata.  Reserve the same
-	// number of items that are available for the priority queue.  Also,
-	// choose the initial sort order for the priority queue based on whether
-	// or not there is an area allocated for high-priority transactions.
+	// number of items that are available for the priority queue.  The
+	// priority queue is only used to fill the area allocated for
+	// high-priority transactions, if any.
 	sourceTxns := g.txSource.MiningDescs()
-	sortedByFee := g.policy.BlockPrioritySize == 0
-	priorityQueue := newTxPriorityQueue(len(sourceTxns), sortedByFee)
+	priorityQueue := newTxPriorityQueue(len(sourceTxns), false)
 
 	// Create a slice to hold the transactions to be included in the
 	// generated block with reserved space.  Also create a utxo view to
@@ -483,6 +730,13 @@ func (g *BlkTmplGenerator) NewBlockTemplate(payToAddress ltcutil.Address) (*Bloc
 	blockTxns = append(blockTxns, coinbaseTx)
 	blockUtxos := blockchain.NewUtxoViewpoint()
 
+	// candidates holds the transactions which may still be included in
+	// the generated block.  Transactions which turn out to be invalid are
+	// removed from it, which also rules out all of the transactions which
+	// depend on them.
+	candidates := make(map[chainhash.Hash]*txPrioItem, len(sourceTxns))
+	var needAncestorStats []*txPrioItem
+
 	// dependers is used to track transactions which depend on another
 	// transaction in the source pool.  This, in conjunction with the
 	// dependsOn map kept with each dependent transaction helps quickly
@@ -501,18 +755,56 @@ func (g *BlkTmplGenerator) NewBlockTemplate(payToAddress ltcutil.Address) (*Bloc
 	txFees = append(txFees, -1) // Updated once known
 	txSigOpCosts = append(txSigOpCosts, coinbaseSigOpCost)
 
+	// Keep the transactions of the base template which are still in the
+	// source pool unless they depend on a transaction which is not.  They
+	// are added to the block before selecting any other transaction.
+	var keptTxns []*ltcutil.Tx
+	var keptFees, keptSigOpCosts []int64
+	kept := make(map[chainhash.Hash]struct{})
+	if base != nil {
+		inPool := make(map[chainhash.Hash]struct{}, len(sourceTxns))
+		for _, txDesc := range sourceTxns {
+			inPool[*txDesc.Tx.Hash()] = struct{}{}
+		}
+		dropped := make(map[chainhash.Hash]struct{})
+		for i, msgTx := range base.Block.Transactions[1:] {
+			tx := ltcutil.NewTx(msgTx)
+			_, keep := inPool[*tx.Hash()]
+			for _, txIn := range msgTx.TxIn {
+				_, ok := dropped[txIn.PreviousOutPoint.Hash]
+				if ok {
+					keep = false
+				}
+			}
+			if !keep {
+				log.Tracef("Dropping tx %s from the block "+
+					"template", tx.Hash())
+				dropped[*tx.Hash()] = struct{}{}
+				continue
+			}
+			kept[*tx.Hash()] = struct{}{}
+			keptTxns = append(keptTxns, tx)
+			keptFees = append(keptFees, base.Fees[i+1])
+			keptSigOpCosts = append(keptSigOpCosts, base.SigOpCosts[i+1])
+		}
+	}
+
 	log.Debugf("Considering %d transactions for inclusion to new block",
-		len(sourceTxns))
+		len(sourceTxns)-len(kept))
 
 mempoolLoop:
 	for _, txDesc := range sourceTxns {
 		// A block can't have more than one coinbase or contain
-		// non-finalized transactions.
+		// non-finalized transactions.  Transactions kept from the base
+		// template are already in the block.
 		tx := txDesc.Tx
 		if blockchain.IsCoinBase(tx) {
 			log.Tracef("Skipping coinbase tx %s", tx.Hash())
 			continue
 		}
+		if _, ok := kept[*tx.Hash()]; ok {
+			continue
+		}
 		if !blockchain.IsFinalizedTransaction(tx, nextBlockHeight,
 			g.timeSource.AdjustedTime()) {
 
@@ -535,7 +827,7 @@ mempoolLoop:
 		// Setup dependencies for any transactions which reference
 		// other transactions in the mempool so they can be properly
 		// ordered below.
-		prioItem := &txPrioItem{tx: tx}
+		prioItem := &txPrioItem{tx: tx, pkgIndex: -1}
 		for _, txIn := range tx.MsgTx().TxIn {
 			originHash := &txIn.PreviousOutPoint.Hash
 			entry := utxos.LookupEntry(txIn.PreviousOutPoint)
@@ -578,6 +870,17 @@ mempoolLoop:
 		// Calculate the fee in Satoshi/kB.
 		prioItem.feePerKB = txDesc.FeePerKB
 		prioItem.fee = txDesc.Fee
+		prioItem.vsize = blockchain.GetTransactionVirtualSize(tx)
+
+		// Use the package statistics kept by the source pool when
+		// available.
+		if txDesc.AncestorCount > 0 {
+			prioItem.setAncestorStats(txDesc.AncestorFee,
+				txDesc.AncestorSize)
+		} else {
+			needAncestorStats = append(needAncestorStats, prioItem)
+		}
+		candidates[*tx.Hash()] = prioItem
 
 		// Add the transaction to the priority queue to mark it ready
 		// for inclusion in the block unless it has dependencies.
@@ -591,8 +894,20 @@ mempoolLoop:
 		mergeUtxoView(blockUtxos, utxos)
 	}
 
-	log.Tracef("Priority queue len %d, dependers len %d",
-		priorityQueue.Len(), len(dependers))
+	// Compute the package statistics the source pool didn't provide from
+	// the candidates.  A package with an ancestor which is not a candidate
+	// can't be included, so its statistics don't matter.
+	for _, item := range needAncestorStats {
+		var fee, vsize int64
+		for _, pkgItem := range packageTxns(item, candidates, nil) {
+			fee += pkgItem.fee
+			vsize += pkgItem.vsize
+		}
+		item.setAncestorStats(fee, vsize)
+	}
+
+	log.Tracef("Priority queue len %d, candidates len %d, dependers len %d",
+		priorityQueue.Len(), len(candidates), len(dependers))
 
 	// The starting block size is the size of the block header plus the max
 	// possible transaction count size, plus the size of the coinbase
@@ -612,68 +927,118 @@ mempoolLoop:
 	}
 	segwitActive := segwitState == blockchain.ThresholdActive
 
-	witnessIncluded := false
-
-	// Choose which transactions make it into the block.
-	for priorityQueue.Len() > 0 {
-		// Grab the highest priority (or highest fee per kilobyte
-		// depending on the sort order) transaction.
-		prioItem := heap.Pop(priorityQueue).(*txPrioItem)
-		tx := prioItem.tx
+	// If we include a transaction bearing witness data, then we'll also
+	// need to include a witness commitment in the coinbase transaction.
+	// Therefore, we account for the additional weight within the block
+	// with a model coinbase tx with a witness commitment.  In order to
+	// accurately account for the weight addition due to this coinbase
+	// transaction, we'll add the difference of the transaction before and
+	// after the addition of the commitment to the block weight once the
+	// first transaction with witness data is included.
+	coinbaseCopy := ltcutil.NewTx(coinbaseTx.MsgTx().Copy())
+	coinbaseCopy.MsgTx().TxIn[0].Witness = [][]byte{
+		bytes.Repeat([]byte("a"), blockchain.CoinbaseWitnessDataLen),
+	}
+	coinbaseCopy.MsgTx().AddTxOut(&wire.TxOut{
+		PkScript: bytes.Repeat([]byte("a"),
+			blockchain.CoinbaseWitnessPkScriptLength),
+	})
+	witnessWeightDiff := uint32(blockchain.GetTransactionWeight(coinbaseCopy) -
+		blockchain.GetTransactionWeight(coinbaseTx))
 
-		switch {
-		// If segregated witness has not been activated yet, then we
-		// shouldn't include any witness transactions in the block.
-		case !segwitActive && tx.HasWitness():
-			continue
+	witnessIncluded := false
 
-		// Otherwise, Keep track of if we've included a transaction
-		// with witness data or not. If so, then we'll need to include
-		// the witness commitment as the last output in the coinbase
-		// transaction.
-		case segwitActive && !witnessIncluded && tx.HasWitness():
-			// If we're about to include a transaction bearing
-			// witness data, then we'll also need to include a
-			// witness commitment in the coinbase transaction.
-			// Therefore, we account for the additional weight
-			// within the block with a model coinbase tx with a
-			// witness commitment.
-			coinbaseCopy := ltcutil.NewTx(coinbaseTx.MsgTx().Copy())
-			coinbaseCopy.MsgTx().TxIn[0].Witness = [][]byte{
-				bytes.Repeat([]byte("a"),
-					blockchain.CoinbaseWitnessDataLen),
+	// included holds the hashes of the transactions included in the block
+	// so far, and pkgQueue the remaining candidates sorted by the fee per
+	// kilobyte of their ancestor package once the high-priority area has
+	// been filled.
+	included := make(map[chainhash.Hash]struct{})
+	var pkgQueue *txPackageQueue
+
+	// pkgWeight returns the weight the passed transactions would add to
+	// the block, including the witness commitment when they bear the first
+	// witness data of the block.
+	pkgWeight := func(pkg []*txPrioItem) int64 {
+		var weight int64
+		needCommitment := false
+		for _, item := range pkg {
+			weight += blockchain.GetTransactionWeight(item.tx)
+			if !witnessIncluded && item.tx.HasWitness() {
+				needCommitment = true
 			}
-			coinbaseCopy.MsgTx().AddTxOut(&wire.TxOut{
-				PkScript: bytes.Repeat([]byte("a"),
-					blockchain.CoinbaseWitnessPkScriptLength),
-			})
+		}
+		if needCommitment {
+			weight += int64(witnessWeightDiff)
+		}
+		return weight
+	}
 
-			// In order to accurately account for the weight
-			// addition due to this coinbase transaction, we'll add
-			// the difference of the transaction before and after
-			// the addition of the commitment to the block weight.
-			weightDiff := blockchain.GetTransactionWeight(coinbaseCopy) -
-				blockchain.GetTransactionWeight(coinbaseTx)
+	// dropTx rules out the passed transaction, and hence all of the
+	// transactions which depend on it, from the block.
+	dropTx := func(item *txPrioItem) {
+		delete(candidates, *item.tx.Hash())
+		if item.pkgIndex >= 0 {
+			heap.Remove(pkgQueue, item.pkgIndex)
+		}
+		logSkippedDeps(item.tx, dependers[*item.tx.Hash()])
+	}
 
-			blockWeight += uint32(weightDiff)
+	// addToBlock adds the passed transaction, which must be valid, to the
+	// block along with its fee and signature operation cost.
+	addToBlock := func(tx *ltcutil.Tx, fee, sigOpCost, vsize int64) {
+		// Spend the transaction inputs in the block utxo view and add
+		// an entry for it to ensure any transactions which reference
+		// this one have it available as an input and can ensure they
+		// aren't double spending.
+		spendTransaction(blockUtxos, tx, nextBlockHeight)
 
+		// Keep track of if we've included a transaction with witness
+		// data or not. If so, then we'll need to include the witness
+		// commitment as the last output in the coinbase transaction.
+		if !witnessIncluded && tx.HasWitness() {
+			blockWeight += witnessWeightDiff
 			witnessIncluded = true
 		}
 
-		// Grab any transactions which depend on this one.
-		deps := dependers[*tx.Hash()]
-
-		// Enforce maximum block size.  Also check for overflow.
-		txWeight := uint32(blockchain.GetTransactionWeight(tx))
-		blockPlusTxWeight := blockWeight + txWeight
-		if blockPlusTxWeight < blockWeight ||
-			blockPlusTxWeight >= g.policy.BlockMaxWeight {
-
-			log.Tracef("Skipping tx %s because it would exceed "+
-				"the max block weight", tx.Hash())
-			logSkippedDeps(tx, deps)
-			continue
+		// Add the transaction to the block, increment counters, and
+		// save the fees and signature operation counts to the block
+		// template.
+		blockTxns = append(blockTxns, tx)
+		blockWeight += uint32(blockchain.GetTransactionWeight(tx))
+		blockSigOpCost += sigOpCost
+		totalFees += fee
+		txFees = append(txFees, fee)
+		txSigOpCosts = append(txSigOpCosts, sigOpCost)
+		included[*tx.Hash()] = struct{}{}
+
+		// The descendants of the transaction no longer have to pay for
+		// it, so remove it from their package statistics and reorder
+		// them accordingly.
+		visited := make(map[chainhash.Hash]struct{})
+		pending := []chainhash.Hash{*tx.Hash()}
+		for len(pending) > 0 {
+			hash := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			for depHash, dep := range dependers[hash] {
+				if _, ok := visited[depHash]; ok {
+					continue
+				}
+				visited[depHash] = struct{}{}
+				dep.setAncestorStats(dep.ancestorFee-fee,
+					dep.ancestorSize-vsize)
+				if dep.pkgIndex >= 0 {
+					heap.Fix(pkgQueue, dep.pkgIndex)
+				}
+				pending = append(pending, depHash)
+			}
 		}
+	}
+
+	// includeTx adds the passed transaction, whose dependencies must
+	// already be in the block, to the block unless it would be invalid.
+	// The block weight must already have been checked by the caller.
+	includeTx := func(item *txPrioItem) bool {
+		tx := item.tx
 
 		// Enforce maximum signature operation cost per block.  Also
 		// check for overflow.
@@ -682,59 +1047,13 @@ mempoolLoop:
 		if err != nil {
 			log.Tracef("Skipping tx %s due to error in "+
 				"GetSigOpCost: %v", tx.Hash(), err)
-			logSkippedDeps(tx, deps)
-			continue
+			return false
 		}
 		if blockSigOpCost+int64(sigOpCost) < blockSigOpCost ||
-			blockSigOpCost+int64(sigOpCost) > blockchain.MaxBlockSigOpsCost {
+			blockSigOpCost+int64(sigOpCost) > blockchain.BlockSigOpsCostLimit(g.chainParams) {
 			log.Tracef("Skipping tx %s because it would "+
 				"exceed the maximum sigops per block", tx.Hash())
-			logSkippedDeps(tx, deps)
-			continue
-		}
-
-		// Skip free transactions once the block is larger than the
-		// minimum block size.
-		if sortedByFee &&
-			prioItem.feePerKB < int64(g.policy.TxMinFreeFee) &&
-			blockPlusTxWeight >= g.policy.BlockMinWeight {
-
-			log.Tracef("Skipping tx %s with feePerKB %d "+
-				"< TxMinFreeFee %d and block weight %d >= "+
-				"minBlockWeight %d", tx.Hash(), prioItem.feePerKB,
-				g.policy.TxMinFreeFee, blockPlusTxWeight,
-				g.policy.BlockMinWeight)
-			logSkippedDeps(tx, deps)
-			continue
-		}
-
-		// Prioritize by fee per kilobyte once the block is larger than
-		// the priority size or there are no more high-priority
-		// transactions.
-		if !sortedByFee && (blockPlusTxWeight >= g.policy.BlockPrioritySize ||
-			prioItem.priority <= MinHighPriority) {
-
-			log.Tracef("Switching to sort by fees per "+
-				"kilobyte blockSize %d >= BlockPrioritySize "+
-				"%d || priority %.2f <= minHighPriority %.2f",
-				blockPlusTxWeight, g.policy.BlockPrioritySize,
-				prioItem.priority, MinHighPriority)
-
-			sortedByFee = true
-			priorityQueue.SetLessFunc(txPQByFee)
-
-			// Put the transaction back into the priority queue and
-			// skip it so it is re-priortized by fees if it won't
-			// fit into the high-priority section or the priority
-			// is too low.  Otherwise this transaction will be the
-			// final one in the high-priority section, so just fall
-			// though to the code below so it is added now.
-			if blockPlusTxWeight > g.policy.BlockPrioritySize ||
-				prioItem.priority < MinHighPriority {
-
-				heap.Push(priorityQueue, prioItem)
-				continue
-			}
+			return false
 		}
 
 		// Ensure the transaction inputs pass all of the necessary
@@ -744,51 +1063,191 @@ mempoolLoop:
 		if err != nil {
 			log.Tracef("Skipping tx %s due to error in "+
 				"CheckTransactionInputs: %v", tx.Hash(), err)
-			logSkippedDeps(tx, deps)
-			continue
+			return false
 		}
 		err = blockchain.ValidateTransactionScripts(tx, blockUtxos,
 			txscript.StandardVerifyFlags, g.sigCache,
-			g.hashCache)
+			g.scriptCache, g.hashCache)
 		if err != nil {
 			log.Tracef("Skipping tx %s due to error in "+
 				"ValidateTransactionScripts: %v", tx.Hash(), err)
-			logSkippedDeps(tx, deps)
+			return false
+		}
+
+		addToBlock(tx, item.fee, int64(sigOpCost), item.vsize)
+
+		log.Tracef("Adding tx %s (priority %.2f, feePerKB %d, package "+
+			"feePerKB %d)", tx.Hash(), item.priority, item.feePerKB,
+			item.ancestorFeePerKB)
+
+		return true
+	}
+
+	// Add the transactions kept from the base template to the block.  They
+	// were already validated when the base template was generated.
+	for i, tx := range keptTxns {
+		utxos, err := g.chain.FetchUtxoView(tx)
+		if err != nil {
+			return nil, err
+		}
+		mergeUtxoView(blockUtxos, utxos)
+		addToBlock(tx, keptFees[i], keptSigOpCosts[i],
+			blockchain.GetTransactionVirtualSize(tx))
+	}
+
+	// Fill the high-priority area, if one is allocated, with the
+	// transactions ready for inclusion sorted by priority (then fee per
+	// kilobyte).  The high-priority area of a base template has already
+	// been filled.
+	for base == nil && policy.BlockPrioritySize > 0 &&
+		priorityQueue.Len() > 0 {
+
+		// Grab the highest priority transaction.
+		prioItem := heap.Pop(priorityQueue).(*txPrioItem)
+		tx := prioItem.tx
+
+		// If segregated witness has not been activated yet, then we
+		// shouldn't include any witness transactions in the block.
+		if !segwitActive && tx.HasWitness() {
+			dropTx(prioItem)
 			continue
 		}
 
-		// Spend the transaction inputs in the block utxo view and add
-		// an entry for it to ensure any transactions which reference
-		// this one have it available as an input and can ensure they
-		// aren't double spending.
-		spendTransaction(blockUtxos, tx, nextBlockHeight)
+		// Enforce maximum block size.
+		blockPlusTxWeight := int64(blockWeight) +
+			pkgWeight([]*txPrioItem{prioItem})
+		if blockPlusTxWeight >= int64(policy.BlockMaxWeight) {
+			log.Tracef("Skipping tx %s because it would exceed "+
+				"the max block weight", tx.Hash())
+			continue
+		}
 
-		// Add the transaction to the block, increment counters, and
-		// save the fees and signature operation counts to the block
-		// template.
-		blockTxns = append(blockTxns, tx)
-		blockWeight += txWeight
-		blockSigOpCost += int64(sigOpCost)
-		totalFees += prioItem.fee
-		txFees = append(txFees, prioItem.fee)
-		txSigOpCosts = append(txSigOpCosts, int64(sigOpCost))
+		// Stop filling the high-priority area once the block is larger
+		// than the priority size or there are no more high-priority
+		// transactions.  The transaction is left for the selection by
+		// package fee below if it won't fit into the high-priority area
+		// or the priority is too low.  Otherwise this transaction will
+		// be the final one in the high-priority area, so it is added
+		// now.
+		if blockPlusTxWeight >= int64(policy.BlockPrioritySize) ||
+			prioItem.priority <= MinHighPriority {
+
+			log.Tracef("Switching to sort by package fees per "+
+				"kilobyte blockSize %d >= BlockPrioritySize "+
+				"%d || priority %.2f <= minHighPriority %.2f",
+				blockPlusTxWeight, policy.BlockPrioritySize,
+				prioItem.priority, MinHighPriority)
+
+			if blockPlusTxWeight <= int64(policy.BlockPrioritySize) &&
+				prioItem.priority >= MinHighPriority &&
+				!includeTx(prioItem) {
 
-		log.Tracef("Adding tx %s (priority %.2f, feePerKB %.2f)",
-			prioItem.tx.Hash(), prioItem.priority, prioItem.feePerKB)
+				dropTx(prioItem)
+			}
+			break
+		}
+
+		if !includeTx(prioItem) {
+			dropTx(prioItem)
+			continue
+		}
 
 		// Add transactions which depend on this one (and also do not
 		// have any other unsatisified dependencies) to the priority
 		// queue.
-		for _, item := range deps {
-			// Add the transaction to the priority queue if there
-			// are no more dependencies after this one.
-			delete(item.dependsOn, *tx.Hash())
-			if len(item.dependsOn) == 0 {
+		for _, item := range dependers[*tx.Hash()] {
+			ready := true
+			for hash := range item.dependsOn {
+				if _, ok := included[hash]; !ok {
+					ready = false
+					break
+				}
+			}
+			if ready {
 				heap.Push(priorityQueue, item)
 			}
 		}
 	}
 
+	// Fill the rest of the block with the remaining candidates sorted by
+	// the fee per kilobyte of their ancestor package, so a transaction
+	// paying a high fee brings the transactions it depends on along with
+	// it.  Each package is included at once, ancestors first.
+	pkgQueue = &txPackageQueue{
+		items: make([]*txPrioItem, 0, len(candidates)),
+	}
+	for hash, item := range candidates {
+		if _, ok := included[hash]; !ok {
+			item.pkgIndex = len(pkgQueue.items)
+			pkgQueue.items = append(pkgQueue.items, item)
+		}
+	}
+	heap.Init(pkgQueue)
+
+	for pkgQueue.Len() > 0 {
+		// Grab the transaction with the highest package fee per
+		// kilobyte along with its ancestors which are not in the block
+		// yet.
+		prioItem := heap.Pop(pkgQueue).(*txPrioItem)
+		tx := prioItem.tx
+		pkg := packageTxns(prioItem, candidates, included)
+		if pkg == nil {
+			log.Tracef("Skipping tx %s because it depends on a "+
+				"transaction which can't be included", tx.Hash())
+			continue
+		}
+
+		// If segregated witness has not been activated yet, then we
+		// shouldn't include any witness transactions in the block.
+		if !segwitActive {
+			hasWitness := false
+			for _, item := range pkg {
+				if item.tx.HasWitness() {
+					dropTx(item)
+					hasWitness = true
+				}
+			}
+			if hasWitness {
+				continue
+			}
+		}
+
+		// Enforce maximum block size.
+		blockPlusPkgWeight := int64(blockWeight) + pkgWeight(pkg)
+		if blockPlusPkgWeight >= int64(policy.BlockMaxWeight) {
+			log.Tracef("Skipping tx %s because it would exceed "+
+				"the max block weight with its %d unconfirmed "+
+				"ancestors", tx.Hash(), len(pkg)-1)
+			continue
+		}
+
+		// Skip free packages once the block is larger than the minimum
+		// block size.
+		if prioItem.ancestorFeePerKB < int64(policy.TxMinFreeFee) &&
+			blockPlusPkgWeight >= int64(policy.BlockMinWeight) {
+
+			log.Tracef("Skipping tx %s with package feePerKB %d "+
+				"< TxMinFreeFee %d and block weight %d >= "+
+				"minBlockWeight %d", tx.Hash(),
+				prioItem.ancestorFeePerKB, policy.TxMinFreeFee,
+				blockPlusPkgWeight, policy.BlockMinWeight)
+			continue
+		}
+
+		// Include the package, stopping at the first transaction which
+		// would make the block invalid since the transactions after it
+		// depend on it.
+		for _, item := range pkg {
+			if item.pkgIndex >= 0 {
+				heap.Remove(pkgQueue, item.pkgIndex)
+			}
+			if !includeTx(item) {
+				dropTx(item)
+				break
+			}
+		}
+	}
+
 	// Now that the actual transactions have been selected, update the
 	// block weight for the real transaction count and coinbase value with
 	// the total fees accordingly.
@@ -800,9 +1259,11 @@ mempoolLoop:
 
 	// If segwit is active and we included transactions with witness data,
 	// then we'll need to include a commitment to the witness data in an
-	// OP_RETURN output within the coinbase transaction.
+	// OP_RETURN output within the coinbase transaction.  Blocks of signet
+	// networks always need the commitment since it carries the solution to
+	// the signet challenge.
 	var witnessCommitment []byte
-	if witnessIncluded {
+	if witnessIncluded || g.chainParams.SignetChallenge != nil {
 		witnessCommitment = AddWitnessCommitment(coinbaseTx, blockTxns)
 	}
 
@@ -851,6 +1312,161 @@ mempoolLoop:
 		"%064x)", len(msgBlock.Transactions), totalFees, blockSigOpCost,
 		blockWeight, blockchain.CompactToBig(msgBlock.Header.Bits))
 
+	return &BlockTemplate{
+		Block:             &msgBlock,
+		Fees:              txFees,
+		SigOpCosts:        txSigOpCosts,
+		Height:            nextBlockHeight,
+		ValidPayAddress:   validPayAddress,
+		WitnessCommitment: witnessCommitment,
+
+		CoinbaseReservedSize:   coinbaseReservedSize,
+		CoinbaseReservedOffset: coinbaseReservedOffset(nextBlockHeight),
+	}, nil
+}
+
+// NewEmptyBlockTemplate returns a new block template that is ready to be solved
+// and only contains a coinbase that either pays to the passed address if it is
+// not nil, or is redeemable by anyone if the passed address is nil.
+//
+// Since no transactions are selected from the source pool, this is much faster
+// than NewBlockTemplate, which allows work on a new best chain tip to be handed
+// out right away while the full template is being generated.
+func (g *BlkTmplGenerator) NewEmptyBlockTemplate(payToAddress ltcutil.Address) (*BlockTemplate, error) {
+	return g.NewBlockTemplateWithTxns(payToAddress, nil, time.Time{})
+}
+
+// NewBlockTemplateWithTxns returns a new block template that is ready to be
+// solved which contains a coinbase paying to the passed address followed by
+// exactly the passed transactions in the passed order.  The block is
+// timestamped with the passed time, or with the same time NewBlockTemplate uses
+// when it is the zero time.
+//
+// Unlike NewBlockTemplate, no transactions are selected from the source pool
+// and the mining policy is not applied, which allows blocks with specific
+// contents and timestamps to be crafted on test networks.  The transactions
+// must still be valid in the order given and the timestamp must be after the
+// median time of the last several blocks, otherwise an error is returned.
+func (g *BlkTmplGenerator) NewBlockTemplateWithTxns(payToAddress ltcutil.Address,
+	txns []*ltcutil.Tx, ts time.Time) (*BlockTemplate, error) {
+
+	// Extend the most recently known best block.
+	best := g.chain.BestSnapshot()
+	nextBlockHeight := best.Height + 1
+	policy := g.Policy()
+
+	coinbaseScript, err := standardCoinbaseScript(nextBlockHeight,
+		policy.CoinbaseReservedSize, 0)
+	if err != nil {
+		return nil, err
+	}
+	coinbaseTx, err := createCoinbaseTx(g.chainParams, coinbaseScript,
+		nextBlockHeight, payToAddress)
+	if err != nil {
+		return nil, err
+	}
+	coinbaseSigOpCost := int64(blockchain.CountSigOps(coinbaseTx)) * blockchain.WitnessScaleFactor
+
+	blockTxns := make([]*ltcutil.Tx, 0, len(txns)+1)
+	blockTxns = append(blockTxns, coinbaseTx)
+	blockUtxos := blockchain.NewUtxoViewpoint()
+	txFees := make([]int64, 0, len(txns)+1)
+	txSigOpCosts := make([]int64, 0, len(txns)+1)
+	txFees = append(txFees, -1) // Updated once known
+	txSigOpCosts = append(txSigOpCosts, coinbaseSigOpCost)
+	totalFees := int64(0)
+
+	segwitState, err := g.chain.ThresholdState(chaincfg.DeploymentSegwit)
+	if err != nil {
+		return nil, err
+	}
+	segwitActive := segwitState == blockchain.ThresholdActive
+
+	witnessIncluded := false
+	for _, tx := range txns {
+		if blockchain.IsCoinBase(tx) {
+			return nil, fmt.Errorf("transaction %s is a coinbase",
+				tx.Hash())
+		}
+		if tx.HasWitness() {
+			if !segwitActive {
+				return nil, fmt.Errorf("transaction %s has "+
+					"witness data before segwit is active",
+					tx.Hash())
+			}
+			witnessIncluded = true
+		}
+
+		// Fetch the utxos referenced by the transaction which are not
+		// created by the transactions before it in the block.
+		utxos, err := g.chain.FetchUtxoView(tx)
+		if err != nil {
+			return nil, err
+		}
+		mergeUtxoView(blockUtxos, utxos)
+
+		fee, err := blockchain.CheckTransactionInputs(tx,
+			nextBlockHeight, blockUtxos, g.chainParams)
+		if err != nil {
+			return nil, err
+		}
+		sigOpCost, err := blockchain.GetSigOpCost(tx, false,
+			blockUtxos, true, segwitActive)
+		if err != nil {
+			return nil, err
+		}
+		spendTransaction(blockUtxos, tx, nextBlockHeight)
+
+		blockTxns = append(blockTxns, tx)
+		totalFees += fee
+		txFees = append(txFees, fee)
+		txSigOpCosts = append(txSigOpCosts, int64(sigOpCost))
+	}
+	coinbaseTx.MsgTx().TxOut[0].Value += totalFees
+	txFees[0] = -totalFees
+
+	var witnessCommitment []byte
+	if witnessIncluded || g.chainParams.SignetChallenge != nil {
+		witnessCommitment = AddWitnessCommitment(coinbaseTx, blockTxns)
+	}
+
+	if ts.IsZero() {
+		ts = medianAdjustedTime(best, g.timeSource)
+	}
+	reqDifficulty, err := g.chain.CalcNextRequiredDifficulty(ts)
+	if err != nil {
+		return nil, err
+	}
+	nextBlockVersion, err := g.chain.CalcNextBlockVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	var msgBlock wire.MsgBlock
+	msgBlock.Header = wire.BlockHeader{
+		Version:    nextBlockVersion,
+		PrevBlock:  best.Hash,
+		MerkleRoot: blockchain.CalcMerkleRoot(blockTxns, false),
+		Timestamp:  ts,
+		Bits:       reqDifficulty,
+	}
+	for _, tx := range blockTxns {
+		if err := msgBlock.AddTransaction(tx.MsgTx()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Perform a full check on the created block against the chain
+	// consensus rules, which also ensures the timestamp is acceptable.
+	block := ltcutil.NewBlock(&msgBlock)
+	block.SetHeight(nextBlockHeight)
+	if err := g.chain.CheckConnectBlockTemplate(block); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Created new block template with %d given transactions "+
+		"timestamped %v", len(txns), ts)
+
 	return &BlockTemplate{
 		Block:             &msgBlock,
 		Fees:              txFees,
@@ -858,6 +1474,9 @@ mempoolLoop:
 		Height:            nextBlockHeight,
 		ValidPayAddress:   payToAddress != nil,
 		WitnessCommitment: witnessCommitment,
+
+		CoinbaseReservedSize:   int(policy.CoinbaseReservedSize),
+		CoinbaseReservedOffset: coinbaseReservedOffset(nextBlockHeight),
 	}, nil
 }
 
@@ -932,7 +1551,8 @@ func (g *BlkTmplGenerator) UpdateBlockTime(msgBlock *wire.MsgBlock) error {
 // height.  It also recalculates and updates the new merkle root that results
 // from changing the coinbase script.
 func (g *BlkTmplGenerator) UpdateExtraNonce(msgBlock *wire.MsgBlock, blockHeight int32, extraNonce uint64) error {
-	coinbaseScript, err := standardCoinbaseScript(blockHeight, extraNonce)
+	coinbaseScript, err := standardCoinbaseScript(blockHeight,
+		g.Policy().CoinbaseReservedSize, extraNonce)
 	if err != nil {
 		return err
 	}
@@ -965,6 +1585,28 @@ func (g *BlkTmplGenerator) BestSnapshot() *blockchain.BestState {
 	return g.chain.BestSnapshot()
 }
 
+// Policy returns the mining policy used by the generator.
+//
+// This function is safe for concurrent access.
+func (g *BlkTmplGenerator) Policy() Policy {
+	g.policyMtx.RLock()
+	policy := *g.policy
+	g.policyMtx.RUnlock()
+	return policy
+}
+
+// SetPolicy replaces the mining policy used by the generator, which allows it
+// to be adjusted at runtime.  The new policy applies to the templates generated
+// afterwards.
+//
+// This function is safe for concurrent access.
+func (g *BlkTmplGenerator) SetPolicy(policy *Policy) {
+	g.policyMtx.Lock()
+	policyCopy := *policy
+	g.policy = &policyCopy
+	g.policyMtx.Unlock()
+}
+
 // TxSource returns the associated transaction source.
 //
 // This function is safe for concurrent access.
@@ -5,11 +5,16 @@
 package mining
 
 import (
+	"bytes"
 	"container/heap"
 	"math/rand"
 	"testing"
 
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
 )
 
 // TestTxFeePrioHeap ensures the priority queue for transaction fees and
@@ -108,3 +113,171 @@ func TestTxFeePrioHeap(t *testing.T) {
 		highest = prioItem
 	}
 }
+
+// TestTxPackageQueue ensures the package queue sorts transactions by the fee
+// per kilobyte of their ancestor package and that packages are returned with
+// the ancestors first.
+func TestTxPackageQueue(t *testing.T) {
+	// Create a parent paying a low fee, a child paying a high fee which
+	// depends on it and an unrelated transaction paying a fee in between.
+	newItem := func(lockTime uint32, fee, vsize int64) *txPrioItem {
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.LockTime = lockTime
+		return &txPrioItem{
+			tx:       ltcutil.NewTx(msgTx),
+			fee:      fee,
+			vsize:    vsize,
+			pkgIndex: -1,
+		}
+	}
+	parent := newItem(1, 100, 1000)
+	child := newItem(2, 10000, 1000)
+	other := newItem(3, 3000, 1000)
+	child.dependsOn = map[chainhash.Hash]struct{}{
+		*parent.tx.Hash(): {},
+	}
+	candidates := map[chainhash.Hash]*txPrioItem{
+		*parent.tx.Hash(): parent,
+		*child.tx.Hash():  child,
+		*other.tx.Hash():  other,
+	}
+	for _, item := range candidates {
+		var fee, vsize int64
+		for _, pkgItem := range packageTxns(item, candidates, nil) {
+			fee += pkgItem.fee
+			vsize += pkgItem.vsize
+		}
+		item.setAncestorStats(fee, vsize)
+	}
+	if child.ancestorFeePerKB != 5050 {
+		t.Fatalf("child package feePerKB: got %d, want 5050",
+			child.ancestorFeePerKB)
+	}
+
+	pq := &txPackageQueue{}
+	for _, item := range candidates {
+		heap.Push(pq, item)
+	}
+
+	// The child comes first thanks to its package and brings the parent
+	// along with it.
+	item := heap.Pop(pq).(*txPrioItem)
+	if item != child {
+		t.Fatalf("got tx %v first, want the child", item.tx.Hash())
+	}
+	pkg := packageTxns(item, candidates, nil)
+	if len(pkg) != 2 || pkg[0] != parent || pkg[1] != child {
+		t.Fatalf("got package of %d transactions, want the parent "+
+			"then the child", len(pkg))
+	}
+	heap.Remove(pq, parent.pkgIndex)
+	if parent.pkgIndex != -1 || pq.Len() != 1 {
+		t.Fatalf("parent still in the package queue")
+	}
+	if item := heap.Pop(pq).(*txPrioItem); item != other {
+		t.Fatalf("got tx %v last, want the unrelated tx",
+			item.tx.Hash())
+	}
+
+	// A package is empty when one of its ancestors is not a candidate and
+	// only holds the transaction once its ancestors are included.
+	delete(candidates, *parent.tx.Hash())
+	if pkg := packageTxns(child, candidates, nil); pkg != nil {
+		t.Fatalf("got package of %d transactions with a missing "+
+			"ancestor", len(pkg))
+	}
+	included := map[chainhash.Hash]struct{}{*parent.tx.Hash(): {}}
+	pkg = packageTxns(child, candidates, included)
+	if len(pkg) != 1 || pkg[0] != child {
+		t.Fatalf("got package of %d transactions with an included "+
+			"ancestor, want 1", len(pkg))
+	}
+}
+
+// TestTemplateCoinbase ensures the coinbase of a block template is reused
+// without its fees and witness commitment.
+func TestTemplateCoinbase(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	coinbaseScript, err := standardCoinbaseScript(100, 0, 0)
+	if err != nil {
+		t.Fatalf("standardCoinbaseScript: unexpected error: %v", err)
+	}
+	coinbaseTx, err := createCoinbaseTx(params, coinbaseScript, 100, nil)
+	if err != nil {
+		t.Fatalf("createCoinbaseTx: unexpected error: %v", err)
+	}
+	pkScript := coinbaseTx.MsgTx().TxOut[0].PkScript
+	coinbaseTx.MsgTx().TxOut[0].Value += 5000
+	witnessCommitment := AddWitnessCommitment(coinbaseTx,
+		[]*ltcutil.Tx{coinbaseTx})
+
+	var msgBlock wire.MsgBlock
+	msgBlock.AddTransaction(coinbaseTx.MsgTx())
+	template := &BlockTemplate{
+		Block:             &msgBlock,
+		Height:            100,
+		WitnessCommitment: witnessCommitment,
+	}
+
+	msgTx := templateCoinbase(template, params).MsgTx()
+	if len(msgTx.TxOut) != 1 || len(msgTx.TxIn[0].Witness) != 0 {
+		t.Fatalf("got %d outputs and %d witness items, want the "+
+			"witness commitment removed", len(msgTx.TxOut),
+			len(msgTx.TxIn[0].Witness))
+	}
+	subsidy := blockchain.CalcBlockSubsidy(100, params)
+	if msgTx.TxOut[0].Value != subsidy {
+		t.Fatalf("got coinbase value %d, want %d", msgTx.TxOut[0].Value,
+			subsidy)
+	}
+	if !bytes.Equal(msgTx.TxOut[0].PkScript, pkScript) {
+		t.Fatalf("coinbase pays to a different script")
+	}
+
+	// The coinbase of the template is left untouched.
+	if len(coinbaseTx.MsgTx().TxOut) != 2 {
+		t.Fatalf("template coinbase modified")
+	}
+}
+
+// TestCoinbaseReservedSize ensures the bytes reserved in the coinbase for the
+// extra nonces of mining pools are at the expected offset and that the
+// coinbase script stays within the consensus limits.
+func TestCoinbaseReservedSize(t *testing.T) {
+	heights := []int32{1, 16, 17, 1000, 0x7fffffff}
+	for _, height := range heights {
+		offset := coinbaseReservedOffset(height)
+		for size := uint32(0); size <= MaxCoinbaseReservedSize; size++ {
+			script, err := standardCoinbaseScript(height, size,
+				^uint64(0))
+			if err != nil {
+				t.Fatalf("standardCoinbaseScript(%d, %d): "+
+					"unexpected error: %v", height, size, err)
+			}
+			if len(script) > blockchain.MaxCoinbaseScriptLen {
+				t.Fatalf("standardCoinbaseScript(%d, %d): got "+
+					"script length %d", height, size,
+					len(script))
+			}
+			if size == 0 {
+				continue
+			}
+			if script[offset-1] != byte(size) {
+				t.Fatalf("standardCoinbaseScript(%d, %d): got "+
+					"push opcode %x", height, size,
+					script[offset-1])
+			}
+			reserved := script[offset : offset+int(size)]
+			if !bytes.Equal(reserved, make([]byte, size)) {
+				t.Fatalf("standardCoinbaseScript(%d, %d): got "+
+					"reserved bytes %x", height, size, reserved)
+			}
+		}
+	}
+
+	_, err := standardCoinbaseScript(1, MaxCoinbaseReservedSize+1, 0)
+	if err == nil {
+		t.Fatal("standardCoinbaseScript: no error with too many " +
+			"reserved bytes")
+	}
+}
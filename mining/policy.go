@@ -45,6 +45,12 @@ type Policy struct {
 	// required for a transaction to be treated as free for mining purposes
 	// (block template generation).
 	TxMinFreeFee ltcutil.Amount
+
+	// CoinbaseReservedSize is the number of bytes reserved in the
+	// signature script of the coinbase of block templates for the extra
+	// nonces rolled by mining pools.  It must not exceed
+	// MaxCoinbaseReservedSize.
+	CoinbaseReservedSize uint32
 }
 
 // minInt is a helper function to return the minimum of two ints.  This avoids
@@ -0,0 +1,145 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// CheckProofOfWorkShare ensures the scrypt proof of work hash of the passed
+// header is not higher than the passed share target.  Mining pools use it to
+// validate the shares submitted by miners, which are solved against a target
+// easier than the one of the block, with the same hashing code the node uses
+// to validate blocks.  A share which also satisfies the target claimed by the
+// bits of the header is a block solution.
+func CheckProofOfWorkShare(header *wire.BlockHeader, shareTarget *big.Int) error {
+	if shareTarget.Sign() <= 0 {
+		return fmt.Errorf("share target of %064x is too low", shareTarget)
+	}
+
+	hash := header.PowHash()
+	hashNum := blockchain.HashToBig(&hash)
+	if hashNum.Cmp(shareTarget) > 0 {
+		return fmt.Errorf("share hash of %064x is higher than the share "+
+			"target of %064x", hashNum, shareTarget)
+	}
+
+	return nil
+}
+
+// CheckShareTemplate ensures the passed share, which is a block solved against
+// a share target, was built from the passed block template, so the work it
+// represents would have produced a valid block had it met the target of the
+// block.  The share must claim the version, previous block and bits of the
+// template, and its timestamp may only be rolled forward from the one of the
+// template by up to blockchain.MaxTimeOffsetSeconds.
+//
+// The coinbase may be replaced, such as to roll the extra nonces of the pool,
+// but it must not pay out more than the coinbase of the template and must keep
+// its witness commitment.  All other transactions must be the ones of the
+// template, in the same order, and the merkle root of the share must commit to
+// them.
+func CheckShareTemplate(share *wire.MsgBlock, template *BlockTemplate) error {
+	header := &share.Header
+	tmplHeader := &template.Block.Header
+	if header.Version != tmplHeader.Version {
+		return fmt.Errorf("share version of %d does not match the "+
+			"template version of %d", header.Version, tmplHeader.Version)
+	}
+	if header.PrevBlock != tmplHeader.PrevBlock {
+		return fmt.Errorf("share builds on %v instead of the template "+
+			"previous block %v", header.PrevBlock, tmplHeader.PrevBlock)
+	}
+	if header.Bits != tmplHeader.Bits {
+		return fmt.Errorf("share bits of %08x do not match the template "+
+			"bits of %08x", header.Bits, tmplHeader.Bits)
+	}
+	maxTimestamp := tmplHeader.Timestamp.Add(time.Second *
+		blockchain.MaxTimeOffsetSeconds)
+	if header.Timestamp.Before(tmplHeader.Timestamp) ||
+		header.Timestamp.After(maxTimestamp) {
+
+		return fmt.Errorf("share timestamp of %v is outside of the range "+
+			"%v to %v allowed by the template", header.Timestamp,
+			tmplHeader.Timestamp, maxTimestamp)
+	}
+
+	if len(share.Transactions) != len(template.Block.Transactions) {
+		return fmt.Errorf("share has %d transactions instead of the %d "+
+			"transactions of the template", len(share.Transactions),
+			len(template.Block.Transactions))
+	}
+	if len(share.Transactions) == 0 {
+		return fmt.Errorf("share does not have a coinbase")
+	}
+	for i, tx := range share.Transactions[1:] {
+		hash := tx.TxHash()
+		tmplHash := template.Block.Transactions[i+1].TxHash()
+		if hash != tmplHash {
+			return fmt.Errorf("share transaction %d is %v instead of "+
+				"the template transaction %v", i+1, hash, tmplHash)
+		}
+	}
+
+	// The coinbase may be replaced by the pool, but it must not pay out
+	// more than the template allows and has to keep the witness
+	// commitment of the template.
+	coinbase := share.Transactions[0]
+	if !blockchain.IsCoinBaseTx(coinbase) {
+		return fmt.Errorf("first transaction of share is not a coinbase")
+	}
+	var value, tmplValue int64
+	for _, txOut := range coinbase.TxOut {
+		value += txOut.Value
+	}
+	for _, txOut := range template.Block.Transactions[0].TxOut {
+		tmplValue += txOut.Value
+	}
+	if value > tmplValue {
+		return fmt.Errorf("share coinbase pays %v which is more than the "+
+			"%v paid by the template", ltcutil.Amount(value),
+			ltcutil.Amount(tmplValue))
+	}
+	if len(template.WitnessCommitment) > 0 {
+		commitmentScript := make([]byte, 0, len(blockchain.WitnessMagicBytes)+
+			len(template.WitnessCommitment))
+		commitmentScript = append(commitmentScript,
+			blockchain.WitnessMagicBytes...)
+		commitmentScript = append(commitmentScript,
+			template.WitnessCommitment...)
+
+		var hasCommitment bool
+		for _, txOut := range coinbase.TxOut {
+			if bytes.Equal(txOut.PkScript, commitmentScript) {
+				hasCommitment = true
+				break
+			}
+		}
+		if !hasCommitment {
+			return fmt.Errorf("share coinbase does not contain the " +
+				"witness commitment of the template")
+		}
+	}
+
+	txns := make([]*ltcutil.Tx, 0, len(share.Transactions))
+	for _, tx := range share.Transactions {
+		txns = append(txns, ltcutil.NewTx(tx))
+	}
+	merkleRoot := blockchain.CalcMerkleRoot(txns, false)
+	if header.MerkleRoot != merkleRoot {
+		return fmt.Errorf("share merkle root of %v does not commit to "+
+			"its transactions, which have a merkle root of %v",
+			header.MerkleRoot, merkleRoot)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestCheckProofOfWorkShare ensures shares are accepted up to and including
+// their target.
+func TestCheckProofOfWorkShare(t *testing.T) {
+	header := wire.BlockHeader{
+		Version:   4,
+		Timestamp: time.Unix(1700000000, 0),
+		Bits:      0x207fffff,
+	}
+	hash := header.PowHash()
+	hashNum := blockchain.HashToBig(&hash)
+
+	tests := []struct {
+		name   string
+		target *big.Int
+		valid  bool
+	}{
+		{"exact target", hashNum, true},
+		{"easier target", new(big.Int).Add(hashNum, big.NewInt(1)), true},
+		{"harder target", new(big.Int).Sub(hashNum, big.NewInt(1)), false},
+		{"zero target", big.NewInt(0), false},
+	}
+	for _, test := range tests {
+		err := CheckProofOfWorkShare(&header, test.target)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: got error %v, want valid %v", test.name, err,
+				test.valid)
+		}
+	}
+}
+
+// TestCheckShareTemplate ensures shares are only accepted when they were built
+// from the block template.
+func TestCheckShareTemplate(t *testing.T) {
+	newCoinbase := func(extraNonce byte) *wire.MsgTx {
+		coinbase := wire.NewMsgTx(wire.TxVersion)
+		coinbase.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+			SignatureScript:  []byte{txscript.OP_1, extraNonce},
+			Sequence:         wire.MaxTxInSequenceNum,
+		})
+		coinbase.AddTxOut(wire.NewTxOut(5000000000,
+			[]byte{txscript.OP_TRUE}))
+		return coinbase
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{1}},
+	})
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{txscript.OP_TRUE}))
+
+	tmplCoinbase := newCoinbase(0)
+	witnessCommitment := AddWitnessCommitment(ltcutil.NewTx(tmplCoinbase),
+		[]*ltcutil.Tx{ltcutil.NewTx(tmplCoinbase), ltcutil.NewTx(tx)})
+	template := &BlockTemplate{
+		Block: &wire.MsgBlock{
+			Header: wire.BlockHeader{
+				Version:   4,
+				PrevBlock: chainhash.Hash{2},
+				Timestamp: time.Unix(1700000000, 0),
+				Bits:      0x207fffff,
+			},
+			Transactions: []*wire.MsgTx{tmplCoinbase, tx},
+		},
+		WitnessCommitment: witnessCommitment,
+	}
+
+	// newShare returns a share built from the template with a coinbase
+	// using a different extra nonce, modified by the passed function.
+	newShare := func(modify func(*wire.MsgBlock)) *wire.MsgBlock {
+		share := *template.Block
+		coinbase := tmplCoinbase.Copy()
+		coinbase.TxIn[0].SignatureScript[1] = 1
+		share.Transactions = []*wire.MsgTx{coinbase, tx}
+		modify(&share)
+		txns := []*ltcutil.Tx{ltcutil.NewTx(share.Transactions[0])}
+		for _, tx := range share.Transactions[1:] {
+			txns = append(txns, ltcutil.NewTx(tx))
+		}
+		share.Header.MerkleRoot = blockchain.CalcMerkleRoot(txns, false)
+		return &share
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*wire.MsgBlock)
+		valid  bool
+	}{
+		{"valid", func(*wire.MsgBlock) {}, true},
+		{"rolled time", func(b *wire.MsgBlock) {
+			b.Header.Timestamp = b.Header.Timestamp.Add(time.Minute)
+		}, true},
+		{"lower payout", func(b *wire.MsgBlock) {
+			b.Transactions[0].TxOut[0].Value--
+		}, true},
+		{"version", func(b *wire.MsgBlock) {
+			b.Header.Version++
+		}, false},
+		{"previous block", func(b *wire.MsgBlock) {
+			b.Header.PrevBlock = chainhash.Hash{3}
+		}, false},
+		{"bits", func(b *wire.MsgBlock) {
+			b.Header.Bits--
+		}, false},
+		{"old time", func(b *wire.MsgBlock) {
+			b.Header.Timestamp = b.Header.Timestamp.Add(-time.Second)
+		}, false},
+		{"future time", func(b *wire.MsgBlock) {
+			b.Header.Timestamp = b.Header.Timestamp.Add(3 * time.Hour)
+		}, false},
+		{"higher payout", func(b *wire.MsgBlock) {
+			b.Transactions[0].TxOut[0].Value++
+		}, false},
+		{"missing commitment", func(b *wire.MsgBlock) {
+			txOuts := b.Transactions[0].TxOut
+			b.Transactions[0].TxOut = txOuts[:len(txOuts)-1]
+		}, false},
+		{"missing transaction", func(b *wire.MsgBlock) {
+			b.Transactions = b.Transactions[:1]
+		}, false},
+		{"replaced transaction", func(b *wire.MsgBlock) {
+			b.Transactions = []*wire.MsgTx{b.Transactions[0],
+				newCoinbase(2)}
+		}, false},
+	}
+	for _, test := range tests {
+		err := CheckShareTemplate(newShare(test.modify), template)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: got error %v, want valid %v", test.name, err,
+				test.valid)
+		}
+	}
+
+	// A share whose merkle root does not commit to its transactions must
+	// be rejected.
+	share := newShare(func(*wire.MsgBlock) {})
+	share.Header.MerkleRoot = chainhash.Hash{4}
+	if err := CheckShareTemplate(share, template); err == nil {
+		t.Error("bad merkle root: share was accepted")
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// SignetSigner defines the function used to sign signet blocks.  It is
+// provided with the virtual transaction which spends the output paying to the
+// signet challenge of a block, as returned by blockchain.SignetTxs, along with
+// the challenge and must return the signature script and witness which satisfy
+// the challenge.
+type SignetSigner func(toSign *wire.MsgTx, challenge []byte) ([]byte,
+	wire.TxWitness, error)
+
+// NewSignetKeySigner returns a signet signer which signs with the passed
+// private keys.  It supports the challenges which can be signed without a
+// witness, such as pay-to-pubkey-hash and bare multisig scripts.  A signer
+// without keys provides an empty solution, which satisfies challenges such as
+// OP_TRUE.
+func NewSignetKeySigner(chainParams *chaincfg.Params,
+	keys []*ltcutil.WIF) SignetSigner {
+
+	keysByAddr := make(map[string]*ltcutil.WIF, len(keys))
+	for _, key := range keys {
+		addr, err := ltcutil.NewAddressPubKeyHash(
+			ltcutil.Hash160(key.SerializePubKey()), chainParams)
+		if err != nil {
+			continue
+		}
+		keysByAddr[addr.EncodeAddress()] = key
+	}
+	lookupKey := func(addr ltcutil.Address) (*btcec.PrivateKey, bool, error) {
+		key, ok := keysByAddr[addr.EncodeAddress()]
+		if !ok {
+			return nil, false, errors.New("no signet signing key " +
+				"for address " + addr.EncodeAddress())
+		}
+		return key.PrivKey, key.CompressPubKey, nil
+	}
+
+	return func(toSign *wire.MsgTx, challenge []byte) ([]byte, wire.TxWitness, error) {
+		if len(keys) == 0 {
+			return nil, nil, nil
+		}
+		sigScript, err := txscript.SignTxOutput(chainParams, toSign, 0,
+			challenge, txscript.SigHashAll,
+			txscript.KeyClosure(lookupKey), nil, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sigScript, nil, nil
+	}
+}
+
+// SolveSignetChallenge signs the passed block with the signer and stores the
+// solution to the signet challenge in the witness commitment of its coinbase,
+// replacing any previous solution.  The merkle root of the block is updated
+// accordingly.
+//
+// The solution commits to the version, previous block, transactions and
+// timestamp of the block, so the block must be signed again whenever any of
+// them change.
+func SolveSignetChallenge(msgBlock *wire.MsgBlock, challenge []byte,
+	signer SignetSigner) error {
+
+	if len(msgBlock.Transactions) == 0 {
+		return errors.New("block does not have a coinbase")
+	}
+	coinbase := msgBlock.Transactions[0]
+	commitmentIdx := -1
+	for i := len(coinbase.TxOut) - 1; i >= 0; i-- {
+		pkScript := coinbase.TxOut[i].PkScript
+		if len(pkScript) >= blockchain.CoinbaseWitnessPkScriptLength &&
+			bytes.HasPrefix(pkScript, blockchain.WitnessMagicBytes) {
+
+			commitmentIdx = i
+			break
+		}
+	}
+	if commitmentIdx < 0 {
+		return errors.New("block does not contain a witness commitment")
+	}
+
+	// The solution is signed with only the signet header in place of it,
+	// which is what the block is verified against once the solution is
+	// removed again.
+	txOut := coinbase.TxOut[commitmentIdx]
+	commitment := make([]byte, blockchain.CoinbaseWitnessPkScriptLength)
+	copy(commitment, txOut.PkScript)
+	pkScript, err := txscript.NewScriptBuilder().
+		AddData(blockchain.SignetHeader).Script()
+	if err != nil {
+		return err
+	}
+	txOut.PkScript = append(commitment, pkScript...)
+
+	_, toSign, err := blockchain.SignetTxs(msgBlock, challenge)
+	if err != nil {
+		return err
+	}
+	sigScript, witness, err := signer(toSign, challenge)
+	if err != nil {
+		return err
+	}
+
+	// Serialize the solution as the signature script followed by the
+	// witness stack.
+	var solution bytes.Buffer
+	solution.Write(blockchain.SignetHeader)
+	if err := wire.WriteVarBytes(&solution, 0, sigScript); err != nil {
+		return err
+	}
+	err = wire.WriteVarInt(&solution, 0, uint64(len(witness)))
+	if err != nil {
+		return err
+	}
+	for _, item := range witness {
+		if err := wire.WriteVarBytes(&solution, 0, item); err != nil {
+			return err
+		}
+	}
+	pkScript, err = txscript.NewScriptBuilder().
+		AddData(solution.Bytes()).Script()
+	if err != nil {
+		return err
+	}
+	txOut.PkScript = append(commitment, pkScript...)
+
+	block := ltcutil.NewBlock(msgBlock)
+	msgBlock.Header.MerkleRoot = blockchain.CalcMerkleRoot(
+		block.Transactions(), false)
+	return nil
+}
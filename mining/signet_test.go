@@ -0,0 +1,169 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mining
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// newSignetTestBlock returns a block with a coinbase which contains a witness
+// commitment and a second transaction.
+func newSignetTestBlock() *wire.MsgBlock {
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		SignatureScript:  []byte{txscript.OP_1, txscript.OP_1},
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(5000000000, []byte{txscript.OP_TRUE}))
+
+	spend := wire.NewMsgTx(wire.TxVersion)
+	spend.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{0x01}},
+		nil, nil))
+	spend.AddTxOut(wire.NewTxOut(1000, []byte{txscript.OP_TRUE}))
+
+	txns := []*ltcutil.Tx{ltcutil.NewTx(coinbase), ltcutil.NewTx(spend)}
+	AddWitnessCommitment(txns[0], txns)
+
+	msgBlock := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   0x20000000,
+			PrevBlock: chainhash.Hash{0x02},
+			Timestamp: time.Unix(1700000000, 0),
+			Bits:      0x207fffff,
+		},
+		Transactions: []*wire.MsgTx{coinbase, spend},
+	}
+	msgBlock.Header.MerkleRoot = blockchain.CalcMerkleRoot(txns, false)
+	return msgBlock
+}
+
+// TestSolveSignetChallenge ensures blocks signed with the signet key signer
+// solve the signet challenge and that changes to the signed block data
+// invalidate the solution.
+func TestSolveSignetChallenge(t *testing.T) {
+	privKey1, _ := btcec.PrivKeyFromBytes([]byte{0x01, 0x02, 0x03})
+	privKey2, _ := btcec.PrivKeyFromBytes([]byte{0x04, 0x05, 0x06})
+	params := chaincfg.SigNetParams
+
+	// A 1-of-2 multisig challenge which can be solved with either key.
+	pubKey1, _ := ltcutil.NewAddressPubKey(
+		privKey1.PubKey().SerializeCompressed(), &params)
+	pubKey2, _ := ltcutil.NewAddressPubKey(
+		privKey2.PubKey().SerializeCompressed(), &params)
+	challenge, err := txscript.MultiSigScript(
+		[]*ltcutil.AddressPubKey{pubKey1, pubKey2}, 1)
+	if err != nil {
+		t.Fatalf("MultiSigScript: unexpected error: %v", err)
+	}
+	params = chaincfg.CustomSignetParams(challenge, nil)
+
+	wif, err := ltcutil.NewWIF(privKey2, &params, true)
+	if err != nil {
+		t.Fatalf("NewWIF: unexpected error: %v", err)
+	}
+	signer := NewSignetKeySigner(&params, []*ltcutil.WIF{wif})
+
+	msgBlock := newSignetTestBlock()
+	err = SolveSignetChallenge(msgBlock, challenge, signer)
+	if err != nil {
+		t.Fatalf("SolveSignetChallenge: unexpected error: %v", err)
+	}
+	block := ltcutil.NewBlock(msgBlock)
+	wantMerkleRoot := blockchain.CalcMerkleRoot(block.Transactions(), false)
+	if msgBlock.Header.MerkleRoot != wantMerkleRoot {
+		t.Fatalf("merkle root was not updated")
+	}
+	err = blockchain.CheckSignetBlockSolution(block, &params)
+	if err != nil {
+		t.Fatalf("CheckSignetBlockSolution: unexpected error: %v", err)
+	}
+
+	// Signing the block again replaces the previous solution.
+	numOutputs := len(msgBlock.Transactions[0].TxOut)
+	err = SolveSignetChallenge(msgBlock, challenge, signer)
+	if err != nil {
+		t.Fatalf("SolveSignetChallenge: unexpected error: %v", err)
+	}
+	if len(msgBlock.Transactions[0].TxOut) != numOutputs {
+		t.Fatalf("signing again added an output")
+	}
+	err = blockchain.CheckSignetBlockSolution(ltcutil.NewBlock(msgBlock),
+		&params)
+	if err != nil {
+		t.Fatalf("CheckSignetBlockSolution: unexpected error after "+
+			"signing again: %v", err)
+	}
+
+	// Changing the timestamp invalidates the solution while changing the
+	// nonce does not since it is not signed.
+	msgBlock.Header.Nonce++
+	err = blockchain.CheckSignetBlockSolution(ltcutil.NewBlock(msgBlock),
+		&params)
+	if err != nil {
+		t.Fatalf("CheckSignetBlockSolution: unexpected error after "+
+			"changing the nonce: %v", err)
+	}
+	msgBlock.Header.Timestamp = msgBlock.Header.Timestamp.Add(time.Second)
+	err = blockchain.CheckSignetBlockSolution(ltcutil.NewBlock(msgBlock),
+		&params)
+	if !isRuleError(err, blockchain.ErrBadSignetSolution) {
+		t.Fatalf("CheckSignetBlockSolution: got %v, want "+
+			"ErrBadSignetSolution", err)
+	}
+
+	// A block solving the challenge of another signet is invalid.
+	otherSigner := NewSignetKeySigner(&params, nil)
+	trueChallenge := []byte{txscript.OP_TRUE}
+	msgBlock = newSignetTestBlock()
+	err = SolveSignetChallenge(msgBlock, trueChallenge, otherSigner)
+	if err != nil {
+		t.Fatalf("SolveSignetChallenge: unexpected error: %v", err)
+	}
+	err = blockchain.CheckSignetBlockSolution(ltcutil.NewBlock(msgBlock),
+		&params)
+	if !isRuleError(err, blockchain.ErrBadSignetSolution) {
+		t.Fatalf("CheckSignetBlockSolution: got %v, want "+
+			"ErrBadSignetSolution", err)
+	}
+	trueParams := chaincfg.CustomSignetParams(trueChallenge, nil)
+	err = blockchain.CheckSignetBlockSolution(ltcutil.NewBlock(msgBlock),
+		&trueParams)
+	if err != nil {
+		t.Fatalf("CheckSignetBlockSolution: unexpected error: %v", err)
+	}
+
+	// A block without a witness commitment can't be signed or verified.
+	msgBlock = newSignetTestBlock()
+	coinbase := msgBlock.Transactions[0]
+	coinbase.TxOut = coinbase.TxOut[:len(coinbase.TxOut)-1]
+	err = SolveSignetChallenge(msgBlock, challenge, signer)
+	if err == nil {
+		t.Fatalf("SolveSignetChallenge: block without a witness " +
+			"commitment was signed")
+	}
+	err = blockchain.CheckSignetBlockSolution(ltcutil.NewBlock(msgBlock),
+		&trueParams)
+	if !isRuleError(err, blockchain.ErrBadSignetSolution) {
+		t.Fatalf("CheckSignetBlockSolution: got %v, want "+
+			"ErrBadSignetSolution", err)
+	}
+}
+
+// isRuleError returns whether or not the passed error is a rule error with the
+// passed error code.
+func isRuleError(err error, code blockchain.ErrorCode) bool {
+	rerr, ok := err.(blockchain.RuleError)
+	return ok && rerr.ErrorCode == code
+}
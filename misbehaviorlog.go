@@ -0,0 +1,160 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ltcsuite/ltcd/peer"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// maxMisbehaviorEvents is the maximum number of events kept by the
+	// misbehavior log.  The oldest events are forgotten first.
+	maxMisbehaviorEvents = 1000
+
+	// maxMisbehaviorMsgBytes is the maximum number of bytes of the
+	// serialized offending message kept with an event.
+	maxMisbehaviorMsgBytes = 128
+)
+
+// These constants define the kinds of events in the misbehavior log.
+const (
+	// misbehaviorViolation is a protocol violation by a peer, which may
+	// have increased its ban score.
+	misbehaviorViolation = "violation"
+
+	// misbehaviorBan is the decision to ban a peer.
+	misbehaviorBan = "ban"
+
+	// misbehaviorDisconnect is the disconnection of a peer because of its
+	// behavior.
+	misbehaviorDisconnect = "disconnect"
+)
+
+// misbehaviorEvent describes an event of the misbehavior log.
+type misbehaviorEvent struct {
+	time     time.Time
+	kind     string
+	id       int32
+	addr     string
+	inbound  bool
+	banScore uint32
+	reason   string
+	command  string
+	msgHex   string
+}
+
+// misbehaviorLog keeps the most recent protocol violations, ban decisions and
+// disconnections of peers in memory so they can be queried with the
+// getmisbehavinglog RPC after an incident.  Every event is also written to the
+// log with the MISB subsystem tag.
+type misbehaviorLog struct {
+	mtx    sync.Mutex
+	events []misbehaviorEvent
+	next   int
+}
+
+// newMisbehaviorLog returns a new misbehavior log which keeps at most the
+// passed number of events.
+func newMisbehaviorLog(size int) *misbehaviorLog {
+	return &misbehaviorLog{
+		events: make([]misbehaviorEvent, 0, size),
+	}
+}
+
+// add appends the passed event to the log, replacing the oldest event once the
+// log is full.
+//
+// This function is safe for concurrent access.
+func (l *misbehaviorLog) add(event *misbehaviorEvent) {
+	misbLog.Infof("%s peer=%s id=%d inbound=%v banscore=%d cmd=%s "+
+		"reason=%q msg=%s", event.kind, event.addr, event.id,
+		event.inbound, event.banScore, event.command, event.reason,
+		event.msgHex)
+
+	l.mtx.Lock()
+	if len(l.events) < cap(l.events) {
+		l.events = append(l.events, *event)
+	} else {
+		l.events[l.next] = *event
+		l.next = (l.next + 1) % len(l.events)
+	}
+	l.mtx.Unlock()
+}
+
+// recent returns up to the passed number of the most recent events, from the
+// oldest to the newest.
+//
+// This function is safe for concurrent access.
+func (l *misbehaviorLog) recent(count int) []misbehaviorEvent {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if count > len(l.events) {
+		count = len(l.events)
+	}
+	events := make([]misbehaviorEvent, 0, count)
+	for i := len(l.events) - count; i < len(l.events); i++ {
+		events = append(events, l.events[(l.next+i)%len(l.events)])
+	}
+	return events
+}
+
+// record adds an event of the passed kind about the passed peer to the log.
+// The offending message, when not nil, is kept as a hex snippet of its
+// serialization.
+//
+// This function is safe for concurrent access.
+func (l *misbehaviorLog) record(p *peer.Peer, banScore uint32, kind,
+	reason string, msg wire.Message) {
+
+	event := &misbehaviorEvent{
+		time:     time.Now(),
+		kind:     kind,
+		id:       p.ID(),
+		addr:     p.Addr(),
+		inbound:  p.Inbound(),
+		banScore: banScore,
+		reason:   reason,
+	}
+	if msg != nil {
+		event.command = msg.Command()
+		event.msgHex = msgSnippet(msg, p.ProtocolVersion())
+	}
+	l.add(event)
+}
+
+// snippetWriter is an io.Writer which only keeps the first bytes written to it
+// and fails once it is full, which stops the serialization of large messages
+// early.
+type snippetWriter struct {
+	buf []byte
+}
+
+// Write appends the passed bytes to the snippet until it is full.
+//
+// This is part of the io.Writer interface.
+func (w *snippetWriter) Write(p []byte) (int, error) {
+	n := maxMisbehaviorMsgBytes - len(w.buf)
+	if len(p) > n {
+		w.buf = append(w.buf, p[:n]...)
+		return n, io.ErrShortWrite
+	}
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+// msgSnippet returns the hex encoding of at most the first
+// maxMisbehaviorMsgBytes bytes of the payload of the passed message.
+func msgSnippet(msg wire.Message, pver uint32) string {
+	var w snippetWriter
+	_ = msg.BtcEncode(&w, pver, wire.LatestEncoding)
+	return hex.EncodeToString(w.buf)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+
+	"github.com/btcsuite/btclog"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestMisbehaviorLog ensures the misbehavior log only keeps the most recent
+// events and returns them from the oldest to the newest.
+func TestMisbehaviorLog(t *testing.T) {
+	origLog := misbLog
+	misbLog = btclog.Disabled
+	defer func() {
+		misbLog = origLog
+	}()
+
+	const size = 4
+	l := newMisbehaviorLog(size)
+	reasons := func(events []misbehaviorEvent) []string {
+		var reasons []string
+		for _, event := range events {
+			reasons = append(reasons, event.reason)
+		}
+		return reasons
+	}
+
+	if events := l.recent(10); len(events) != 0 {
+		t.Fatalf("recent: got %v from empty log", reasons(events))
+	}
+	for i := 0; i < size+2; i++ {
+		l.add(&misbehaviorEvent{
+			kind:   misbehaviorViolation,
+			reason: strconv.Itoa(i),
+		})
+	}
+
+	tests := []struct {
+		count int
+		want  []string
+	}{
+		{count: 0, want: nil},
+		{count: 2, want: []string{"4", "5"}},
+		{count: size, want: []string{"2", "3", "4", "5"}},
+		{count: size + 10, want: []string{"2", "3", "4", "5"}},
+	}
+	for _, test := range tests {
+		got := reasons(l.recent(test.count))
+		if len(got) != len(test.want) {
+			t.Errorf("recent(%d): got %v, want %v", test.count, got,
+				test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("recent(%d): got %v, want %v",
+					test.count, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+// TestMsgSnippet ensures the serialization of offending messages is truncated
+// to maxMisbehaviorMsgBytes.
+func TestMsgSnippet(t *testing.T) {
+	small := wire.NewMsgFeeFilter(1000)
+	got := msgSnippet(small, wire.ProtocolVersion)
+	if want := "e803000000000000"; got != want {
+		t.Errorf("msgSnippet: got %s, want %s", got, want)
+	}
+
+	large := wire.NewMsgInv()
+	for i := 0; i < 100; i++ {
+		hash := chainhash.Hash{byte(i)}
+		large.AddInvVect(wire.NewInvVect(wire.InvTypeBlock, &hash))
+	}
+	got = msgSnippet(large, wire.ProtocolVersion)
+	if len(got) != hex.EncodedLen(maxMisbehaviorMsgBytes) {
+		t.Errorf("msgSnippet: got %d hex characters, want %d", len(got),
+			hex.EncodedLen(maxMisbehaviorMsgBytes))
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil/mweb"
+	"github.com/ltcsuite/ltcd/wire"
+	"lukechampine.com/blake3"
+)
+
+// mwebOutputSet is the MWEB output set as of a block of the main chain, which
+// is served to MWEB light clients.  It consists of the output MMR, the leafset
+// marking which of its leaves are unspent and the unspent outputs themselves.
+type mwebOutputSet struct {
+	hash    chainhash.Hash
+	mmr     *mweb.Mmr
+	leafset *mweb.Leafset
+
+	// outputs holds the outputs by their leaf index, with the spent ones
+	// set to nil.
+	outputs []*wire.MwebOutput
+
+	// unspent maps the output IDs of the unspent outputs to their leaf
+	// index.
+	unspent map[chainhash.Hash]uint64
+}
+
+// newMwebOutputSet returns the empty MWEB output set preceding MWEB
+// activation.
+func newMwebOutputSet() *mwebOutputSet {
+	return &mwebOutputSet{
+		mmr:     &mweb.Mmr{},
+		leafset: &mweb.Leafset{},
+		unspent: make(map[chainhash.Hash]uint64),
+	}
+}
+
+// copy returns a copy of the output set which blocks can be connected to
+// without affecting the original.
+func (s *mwebOutputSet) copy() *mwebOutputSet {
+	unspent := make(map[chainhash.Hash]uint64, len(s.unspent))
+	for id, index := range s.unspent {
+		unspent[id] = index
+	}
+	leafset := *s.leafset
+	leafset.Bits = append([]byte(nil), s.leafset.Bits...)
+	return &mwebOutputSet{
+		hash:    s.hash,
+		mmr:     s.mmr.Copy(),
+		leafset: &leafset,
+		outputs: append([]*wire.MwebOutput(nil), s.outputs...),
+		unspent: unspent,
+	}
+}
+
+// connectBlock spends the outputs spent by the MWEB data of the passed block
+// and adds the outputs it creates.  An error is returned when the resulting
+// output set does not match the MWEB header of the block.
+func (s *mwebOutputSet) connectBlock(block *wire.MsgBlock) error {
+	hash := block.BlockHash()
+	for _, input := range block.MwebTransactions.Inputs {
+		index, ok := s.unspent[input.OutputId]
+		if !ok {
+			return fmt.Errorf("MWEB input of block %v spends unknown "+
+				"output %v", hash, input.OutputId)
+		}
+		delete(s.unspent, input.OutputId)
+		s.outputs[index] = nil
+		s.leafset.Bits[index/8] &^= 0x80 >> (index % 8)
+	}
+	for _, output := range block.MwebTransactions.Outputs {
+		index := s.mmr.Leaves()
+		id := output.Hash()
+		s.mmr.Add(id)
+		s.outputs = append(s.outputs, output)
+		s.unspent[*id] = index
+		if index/8 == uint64(len(s.leafset.Bits)) {
+			s.leafset.Bits = append(s.leafset.Bits, 0)
+		}
+		s.leafset.Bits[index/8] |= 0x80 >> (index % 8)
+	}
+
+	s.hash = hash
+	s.leafset.Size = s.mmr.Leaves()
+	s.leafset.Height = uint32(block.MwebHeader.Height)
+	s.leafset.Block = &block.Header
+
+	header := block.MwebHeader
+	leafsetRoot := chainhash.Hash(blake3.Sum256(s.leafset.Bits))
+	if s.mmr.Leaves() != header.OutputMMRSize ||
+		*s.mmr.Root() != header.OutputRoot ||
+		leafsetRoot != header.LeafsetRoot {
+
+		return fmt.Errorf("MWEB outputs of block %v do not match its "+
+			"MWEB header", hash)
+	}
+	return nil
+}
+
+// fetchMwebOutputSet returns the MWEB output set as of the main chain block
+// with the passed hash.  It is built by connecting the MWEB data of the blocks
+// since MWEB activated, starting from the most recently fetched output set
+// instead when it belongs to an ancestor of the block.
+func (s *server) fetchMwebOutputSet(hash *chainhash.Hash) (*mwebOutputSet, error) {
+	s.mwebOutputsMtx.Lock()
+	defer s.mwebOutputsMtx.Unlock()
+
+	cached := s.mwebOutputs
+	if cached != nil && cached.hash == *hash {
+		return cached, nil
+	}
+
+	// Walk back from the block until reaching the cached output set or the
+	// last block before MWEB activated.
+	var blocks []*wire.MsgBlock
+	var base *mwebOutputSet
+	for blockHash := *hash; ; {
+		if cached != nil && cached.hash == blockHash {
+			base = cached.copy()
+			break
+		}
+		block, err := s.chain.BlockByHash(&blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if block.MsgBlock().MwebHeader == nil {
+			break
+		}
+		blocks = append(blocks, block.MsgBlock())
+		blockHash = block.MsgBlock().Header.PrevBlock
+	}
+	if len(blocks) == 0 && base == nil {
+		return nil, fmt.Errorf("block %v has no MWEB header", hash)
+	}
+	if base == nil {
+		base = newMwebOutputSet()
+	}
+
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if err := base.connectBlock(blocks[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mwebOutputs = base
+	return base, nil
+}
+
+// mwebUtxos returns an mwebutxos message with at most the passed number of
+// unspent outputs of the output set, starting with the one at the passed leaf
+// index, along with the hashes proving them against the output root.
+func (s *mwebOutputSet) mwebUtxos(startIndex uint64, numRequested uint16,
+	format wire.MwebNetUtxoType) (*wire.MsgMwebUtxos, error) {
+
+	if startIndex >= uint64(len(s.outputs)) || s.outputs[startIndex] == nil {
+		return nil, fmt.Errorf("leaf %d is not an unspent output",
+			startIndex)
+	}
+
+	msg := wire.NewMsgMwebUtxos(s.hash, startIndex, format)
+	for i := startIndex; i < uint64(len(s.outputs)) &&
+		len(msg.Utxos) < int(numRequested); i++ {
+
+		output := s.outputs[i]
+		if output == nil {
+			continue
+		}
+		utxo := &wire.MwebNetUtxo{LeafIndex: i, OutputId: output.Hash()}
+		if format != wire.MwebNetUtxoHashOnly {
+			utxo.Output = output
+		}
+		msg.Utxos = append(msg.Utxos, utxo)
+	}
+
+	if err := s.mmr.ProveUtxos(s.leafset, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
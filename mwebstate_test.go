@@ -0,0 +1,131 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/ltcutil/mweb"
+	"github.com/ltcsuite/ltcd/wire"
+	"lukechampine.com/blake3"
+)
+
+// TestMwebOutputSet ensures the MWEB output set follows the MWEB data of the
+// connected blocks, and that the unspent outputs it serves verify against the
+// MWEB header of the block.
+func TestMwebOutputSet(t *testing.T) {
+	// newOutput returns a distinct MWEB output for each passed number.
+	newOutput := func(n byte) *wire.MwebOutput {
+		output := &wire.MwebOutput{}
+		output.Commitment[0] = n
+		return output
+	}
+
+	// connect connects a block with the passed outputs and spending the
+	// outputs at the passed leaf indices to the set, committing to the
+	// resulting output set in its MWEB header.
+	set := newMwebOutputSet()
+	connect := func(outputs []*wire.MwebOutput, spends []uint64) *wire.MsgBlock {
+		block := &wire.MsgBlock{
+			Header: wire.BlockHeader{PrevBlock: set.hash},
+			MwebTransactions: &wire.MwebTxBody{Outputs: outputs},
+		}
+		for _, index := range spends {
+			block.MwebTransactions.Inputs = append(
+				block.MwebTransactions.Inputs, &wire.MwebInput{
+					OutputId: *set.outputs[index].Hash(),
+				})
+		}
+
+		// The output set of the block is taken from connecting it to a
+		// copy, which fails without the MWEB header committing to it.
+		block.MwebHeader = &wire.MwebHeader{}
+		next := set.copy()
+		if err := next.connectBlock(block); err == nil {
+			t.Fatalf("block connected with an empty MWEB header")
+		}
+		block.MwebHeader = &wire.MwebHeader{
+			OutputRoot:    *next.mmr.Root(),
+			LeafsetRoot:   blake3.Sum256(next.leafset.Bits),
+			OutputMMRSize: next.mmr.Leaves(),
+		}
+		if err := set.connectBlock(block); err != nil {
+			t.Fatalf("connectBlock: %v", err)
+		}
+		return block
+	}
+
+	var outputs []*wire.MwebOutput
+	for i := byte(0); i < 20; i++ {
+		outputs = append(outputs, newOutput(i))
+	}
+	connect(outputs[:5], nil)
+	before := set.copy()
+	connect(outputs[5:12], []uint64{1, 3})
+	block := connect(outputs[12:], []uint64{0, 6, 7, 8})
+
+	// Copies are unaffected by blocks connected to the original.
+	if before.mmr.Leaves() != 5 || len(before.unspent) != 5 ||
+		!before.leafset.Contains(1) {
+
+		t.Fatalf("copy changed along with the original")
+	}
+
+	// Inputs must spend unspent outputs.
+	invalid := &wire.MsgBlock{
+		MwebHeader: block.MwebHeader,
+		MwebTransactions: &wire.MwebTxBody{
+			Inputs: []*wire.MwebInput{{OutputId: *outputs[0].Hash()}},
+		},
+	}
+	if err := set.copy().connectBlock(invalid); err == nil {
+		t.Fatalf("block spending a spent output connected")
+	}
+
+	if set.hash != block.BlockHash() || set.leafset.Size != 20 ||
+		len(set.unspent) != 14 {
+
+		t.Fatalf("output set of block %v with %d leaves and %d unspent "+
+			"outputs", set.hash, set.leafset.Size, len(set.unspent))
+	}
+
+	for start := uint64(0); start < set.leafset.Size; start++ {
+		msg, err := set.mwebUtxos(start, 4, wire.MwebNetUtxoFull)
+		if !set.leafset.Contains(start) {
+			if err == nil {
+				t.Fatalf("outputs starting at spent leaf %d "+
+					"served", start)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("mwebUtxos(%d): %v", start, err)
+		}
+		if len(msg.Utxos) == 0 || len(msg.Utxos) > 4 ||
+			msg.Utxos[0].Output != outputs[start] {
+
+			t.Fatalf("mwebUtxos(%d): served %d outputs", start,
+				len(msg.Utxos))
+		}
+		if !mweb.VerifyUtxos(block.MwebHeader, set.leafset, msg) {
+			t.Fatalf("mwebUtxos(%d): outputs do not verify", start)
+		}
+	}
+
+	// Hash only outputs carry no output.
+	msg, err := set.mwebUtxos(2, wire.MaxMwebUtxosPerQuery,
+		wire.MwebNetUtxoHashOnly)
+	if err != nil {
+		t.Fatalf("mwebUtxos: %v", err)
+	}
+	if len(msg.Utxos) != 14 || msg.Utxos[0].Output != nil ||
+		*msg.Utxos[0].OutputId != *outputs[2].Hash() {
+
+		t.Fatalf("served %d hash only outputs", len(msg.Utxos))
+	}
+	if !mweb.VerifyUtxos(block.MwebHeader, set.leafset, msg) {
+		t.Fatalf("hash only outputs do not verify")
+	}
+}
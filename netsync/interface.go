@@ -25,6 +25,12 @@ type PeerNotifier interface {
 	RelayInventory(invVect *wire.InvVect, data interface{})
 
 	TransactionConfirmed(tx *ltcutil.Tx)
+
+	// PeerMisbehaving is invoked when a peer violates the protocol, such as
+	// by sending an invalid block or unrequested data.  The offending
+	// message may be nil, and disconnected is true when the peer is being
+	// disconnected because of the violation.
+	PeerMisbehaving(peer *peer.Peer, reason string, msg wire.Message, disconnected bool)
 }
 
 // Config is a configuration struct used to initialize a new SyncManager.
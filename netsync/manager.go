@@ -6,6 +6,10 @@ package netsync
 
 import (
 	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
 	"math/rand"
 	"net"
 	"sync"
@@ -36,17 +40,29 @@ const (
 	// hashes to store in memory.
 	maxRequestedBlocks = wire.MaxInvPerMsg
 
-	// maxRequestedTxns is the maximum number of requested transactions
-	// hashes to store in memory.
-	maxRequestedTxns = wire.MaxInvPerMsg
-
 	// maxStallDuration is the time after which we will disconnect our
 	// current sync peer if we haven't made progress.
 	maxStallDuration = 3 * time.Minute
 
 	// stallSampleInterval the interval at which we will check to see if our
 	// sync has stalled.
-	stallSampleInterval = 30 * time.Second
+	stallSampleInterval = 5 * time.Second
+
+	// minBlockStallWindow is the initial and minimum duration without
+	// block download progress after which the sync peer is considered
+	// stalled during the initial block download.
+	minBlockStallWindow = 30 * time.Second
+
+	// maxBlockStallWindow is the maximum duration without block download
+	// progress after which the sync peer is considered stalled during the
+	// initial block download.  The stall window moves between the minimum
+	// and this duration depending on how the sync peers keep up.
+	maxBlockStallWindow = maxStallDuration
+
+	// txRequestInterval is the interval at which transactions announced by
+	// peers which could not be requested right away, such as the ones
+	// announced by inbound peers or whose requests expired, are requested.
+	txRequestInterval = time.Second
 )
 
 // zeroHash is the zero value hash (all zeros).  It is defined as a convenience.
@@ -138,20 +154,31 @@ type pauseMsg struct {
 	unpause <-chan struct{}
 }
 
+// fetchBlockMsg is a message type to be sent across the message channel for
+// requesting a specific block from a specific peer.
+type fetchBlockMsg struct {
+	hash   *chainhash.Hash
+	peerID int32
+	reply  chan error
+}
+
 // headerNode is used as a node in a list of headers that are linked together
-// between checkpoints.
+// between checkpoints.  The work sum is the total work of the chain ending with
+// the header, which is nil when the work of the chain is not known.
 type headerNode struct {
-	height int32
-	hash   *chainhash.Hash
+	height  int32
+	hash    *chainhash.Hash
+	workSum *big.Int
 }
 
 // peerSyncState stores additional information that the SyncManager tracks
 // about a peer.
 type peerSyncState struct {
-	syncCandidate   bool
-	requestQueue    []*wire.InvVect
-	requestedTxns   map[chainhash.Hash]struct{}
-	requestedBlocks map[chainhash.Hash]struct{}
+	syncCandidate    bool
+	requestedHeaders bool
+	requestQueue     []*wire.InvVect
+	requestedBlocks  map[chainhash.Hash]struct{}
+	mwebRequest      *mwebStateRequest
 }
 
 // limitAdd is a helper function for maps that require a maximum limit by
@@ -192,11 +219,12 @@ type SyncManager struct {
 
 	// These fields should only be accessed from the blockHandler thread
 	rejectedTxns     map[chainhash.Hash]struct{}
-	requestedTxns    map[chainhash.Hash]struct{}
+	txRequests       *txRequestTracker
 	requestedBlocks  map[chainhash.Hash]struct{}
 	syncPeer         *peerpkg.Peer
 	peerStates       map[*peerpkg.Peer]*peerSyncState
 	lastProgressTime time.Time
+	blockStallWindow time.Duration
 
 	// The following fields are used for headers-first mode.
 	headersFirstMode bool
@@ -220,6 +248,7 @@ func (sm *SyncManager) resetHeaderState(newestHash *chainhash.Hash, newestHeight
 	// to prove it links to the chain properly.
 	if sm.nextCheckpoint != nil {
 		node := headerNode{height: newestHeight, hash: newestHash}
+		node.workSum, _ = sm.chain.ChainWorkByHash(newestHash)
 		sm.headerList.PushBack(&node)
 	}
 }
@@ -252,6 +281,23 @@ func (sm *SyncManager) findNextHeaderCheckpoint(height int32) *chaincfg.Checkpoi
 	return nextCheckpoint
 }
 
+// requestHeaders sends a getheaders message for the headers after the passed
+// locator up to the stop hash to the peer and notes that a headers response is
+// expected from it.  Only a single headers request is kept in flight per peer
+// so a peer can never feed more than one headers message worth of data at a
+// time.
+func (sm *SyncManager) requestHeaders(peer *peerpkg.Peer,
+	locator blockchain.BlockLocator, stopHash *chainhash.Hash) error {
+
+	if err := peer.PushGetHeadersMsg(locator, stopHash); err != nil {
+		return err
+	}
+	if state, exists := sm.peerStates[peer]; exists {
+		state.requestedHeaders = true
+	}
+	return nil
+}
+
 // startSync will choose the best peer among the available candidate peers to
 // download/sync the blockchain from.  When syncing is already running, it
 // simply returns.  It also examines the candidates for any which are no longer
@@ -278,6 +324,12 @@ func (sm *SyncManager) startSync() {
 			continue
 		}
 
+		// Skip peers which are already being disconnected, such as a
+		// sync peer which was just found to be stalling.
+		if !peer.Connected() {
+			continue
+		}
+
 		if segwitActive && !peer.IsWitnessEnabled() {
 			log.Debugf("peer %v not witness enabled, skipping", peer)
 			continue
@@ -304,20 +356,21 @@ func (sm *SyncManager) startSync() {
 		}
 
 		// This peer has a height greater than our own, we'll consider
-		// it in the set of better peers from which we'll randomly
-		// select.
+		// it in the set of better peers from which we'll select the
+		// one with the most work.
 		higherPeers = append(higherPeers, peer)
 	}
 
-	// Pick randomly from the set of peers greater than our block height,
-	// falling back to a random peer of the same height if none are greater.
+	// Pick the peer with the most announced work from the set of peers
+	// greater than our block height, falling back to a random peer of the
+	// same height if none are greater.
 	//
 	// TODO(conner): Use a better algorithm to ranking peers based on
 	// observed metrics and/or sync in parallel.
 	var bestPeer *peerpkg.Peer
 	switch {
 	case len(higherPeers) > 0:
-		bestPeer = higherPeers[rand.Intn(len(higherPeers))]
+		bestPeer = sm.mostWorkPeer(higherPeers)
 
 	case len(equalPeers) > 0:
 		// If the chain is already current and all peers are at the
@@ -369,7 +422,7 @@ func (sm *SyncManager) startSync() {
 			best.Height < sm.nextCheckpoint.Height &&
 			sm.chainParams != &chaincfg.RegressionNetParams {
 
-			bestPeer.PushGetHeadersMsg(locator, sm.nextCheckpoint.Hash)
+			sm.requestHeaders(bestPeer, locator, sm.nextCheckpoint.Hash)
 			sm.headersFirstMode = true
 			log.Infof("Downloading headers for blocks %d to "+
 				"%d from peer %s", best.Height+1,
@@ -388,6 +441,62 @@ func (sm *SyncManager) startSync() {
 	}
 }
 
+// announcedWork returns the total work of the chain ending with the block last
+// announced by the passed peer, or nil when the block is not known.
+func (sm *SyncManager) announcedWork(peer *peerpkg.Peer) *big.Int {
+	hash := peer.LastAnnouncedBlock()
+	if hash == nil {
+		return nil
+	}
+	work, err := sm.chain.ChainWorkByHash(hash)
+	if err != nil {
+		return nil
+	}
+	return work
+}
+
+// mostWorkPeer returns the peer among the passed ones which announced the
+// chain with the most work.  Peers whose announced block is not known yet are
+// ranked after the ones whose work is known, by their latest block height.
+// Ties are broken randomly so that peers are not always rotated in the same
+// order.
+func (sm *SyncManager) mostWorkPeer(peers []*peerpkg.Peer) *peerpkg.Peer {
+	rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+
+	var best *peerpkg.Peer
+	var bestWork *big.Int
+	for _, peer := range peers {
+		work := sm.announcedWork(peer)
+		if best == nil || betterSyncCandidate(work, peer.LastBlock(),
+			bestWork, best.LastBlock()) {
+
+			best, bestWork = peer, work
+		}
+	}
+	return best
+}
+
+// betterSyncCandidate returns whether a peer which announced a chain with the
+// passed work and height is a better sync candidate than another one.  A nil
+// work denotes a chain whose work is not known.
+func betterSyncCandidate(work *big.Int, height int32, otherWork *big.Int,
+	otherHeight int32) bool {
+
+	switch {
+	case work != nil && otherWork == nil:
+		return true
+	case work == nil && otherWork != nil:
+		return false
+	case work != nil:
+		if cmp := work.Cmp(otherWork); cmp != 0 {
+			return cmp > 0
+		}
+	}
+	return height > otherHeight
+}
+
 // isSyncCandidate returns whether or not the peer is a candidate to consider
 // syncing from.
 func (sm *SyncManager) isSyncCandidate(peer *peerpkg.Peer) bool {
@@ -440,7 +549,6 @@ func (sm *SyncManager) handleNewPeerMsg(peer *peerpkg.Peer) {
 	isSyncCandidate := sm.isSyncCandidate(peer)
 	sm.peerStates[peer] = &peerSyncState{
 		syncCandidate:   isSyncCandidate,
-		requestedTxns:   make(map[chainhash.Hash]struct{}),
 		requestedBlocks: make(map[chainhash.Hash]struct{}),
 	}
 
@@ -453,7 +561,11 @@ func (sm *SyncManager) handleNewPeerMsg(peer *peerpkg.Peer) {
 // handleStallSample will switch to a new sync peer if the current one has
 // stalled. This is detected when by comparing the last progress timestamp with
 // the current time, and disconnecting the peer if we stalled before reaching
-// their highest advertised block.
+// their highest advertised block.  During the initial block download the
+// progress is compared against the block stall window, which widens each time
+// a sync peer stalls and narrows again as blocks are downloaded, so a slow
+// sync peer is replaced quickly without rotating through every peer when the
+// whole network is slow.
 func (sm *SyncManager) handleStallSample() {
 	if atomic.LoadInt32(&sm.shutdown) != 0 {
 		return
@@ -465,7 +577,12 @@ func (sm *SyncManager) handleStallSample() {
 	}
 
 	// If the stall timeout has not elapsed, exit early.
-	if time.Since(sm.lastProgressTime) <= maxStallDuration {
+	stallWindow := maxStallDuration
+	ibd := !sm.chain.IsCurrent()
+	if ibd {
+		stallWindow = sm.blockStallWindow
+	}
+	if time.Since(sm.lastProgressTime) <= stallWindow {
 		return
 	}
 
@@ -478,9 +595,34 @@ func (sm *SyncManager) handleStallSample() {
 	sm.clearRequestedState(state)
 
 	disconnectSyncPeer := sm.shouldDCStalledSyncPeer()
+	if ibd && disconnectSyncPeer {
+		log.Infof("Sync peer %s made no progress for %v -- rotating "+
+			"to the next best peer", sm.syncPeer, stallWindow)
+		sm.blockStallWindow = widenStallWindow(sm.blockStallWindow)
+	}
 	sm.updateSyncPeer(disconnectSyncPeer)
 }
 
+// widenStallWindow returns the block stall window to use after a sync peer
+// stalled within the passed one.
+func widenStallWindow(window time.Duration) time.Duration {
+	window *= 2
+	if window > maxBlockStallWindow {
+		window = maxBlockStallWindow
+	}
+	return window
+}
+
+// narrowStallWindow returns the block stall window to use after the sync peer
+// made progress within the passed one.
+func narrowStallWindow(window time.Duration) time.Duration {
+	window = window * 17 / 20
+	if window < minBlockStallWindow {
+		window = minBlockStallWindow
+	}
+	return window
+}
+
 // shouldDCStalledSyncPeer determines whether or not we should disconnect a
 // stalled sync peer. If the peer has stalled and its reported height is greater
 // than our own best height, we will disconnect it. Otherwise, we will keep the
@@ -520,6 +662,13 @@ func (sm *SyncManager) handleDonePeerMsg(peer *peerpkg.Peer) {
 	log.Infof("Lost peer %s", peer)
 
 	sm.clearRequestedState(state)
+	if state.mwebRequest != nil {
+		sm.failMwebRequest(peer, state, errors.New("peer disconnected"))
+	}
+
+	// Make the transactions requested from the peer available to the other
+	// peers which announced them.
+	sm.txRequests.RemovePeer(peer.ID())
 
 	if peer == sm.syncPeer {
 		// Update the sync peer. The server has already disconnected the
@@ -528,16 +677,11 @@ func (sm *SyncManager) handleDonePeerMsg(peer *peerpkg.Peer) {
 	}
 }
 
-// clearRequestedState wipes all expected transactions and blocks from the sync
-// manager's requested maps that were requested under a peer's sync state, This
-// allows them to be rerequested by a subsequent sync peer.
+// clearRequestedState wipes all expected blocks from the sync manager's
+// requested maps that were requested under a peer's sync state, This allows
+// them to be rerequested by a subsequent sync peer.  Requested transactions
+// are tracked by the transaction request tracker instead.
 func (sm *SyncManager) clearRequestedState(state *peerSyncState) {
-	// Remove requested transactions from the global map so that they will
-	// be fetched from elsewhere next time we get an inv.
-	for txHash := range state.requestedTxns {
-		delete(sm.requestedTxns, txHash)
-	}
-
 	// Remove requested blocks from the global map so that they will be
 	// fetched from elsewhere next time we get an inv.
 	// TODO: we could possibly here check which peers have these blocks
@@ -573,7 +717,7 @@ func (sm *SyncManager) updateSyncPeer(dcSyncPeer bool) {
 // handleTxMsg handles transaction messages from all peers.
 func (sm *SyncManager) handleTxMsg(tmsg *txMsg) {
 	peer := tmsg.peer
-	state, exists := sm.peerStates[peer]
+	_, exists := sm.peerStates[peer]
 	if !exists {
 		log.Warnf("Received tx message from unknown peer %s", peer)
 		return
@@ -589,10 +733,26 @@ func (sm *SyncManager) handleTxMsg(tmsg *txMsg) {
 	// interoperability.
 	txHash := tmsg.tx.Hash()
 
+	// Ignore transactions until the best chain has the minimum required
+	// work since they can't be properly validated against a chain that
+	// is still far behind the network.
+	if !sm.chain.HasMinimumChainWork() {
+		log.Debugf("Ignoring transaction %v from %s -- chain does not "+
+			"have the minimum required work", txHash, peer)
+		sm.txRequests.Forget(*txHash)
+		return
+	}
+
+	// Peers granted the forcerelay permission bypass the standardness and
+	// fee policies of the memory pool.
+	forceRelay := peer.HasPermission(peerpkg.PermissionForceRelay)
+
 	// Ignore transactions that we have already rejected.  Do not
 	// send a reject message here because if the transaction was already
-	// rejected, the transaction was unsolicited.
-	if _, exists = sm.rejectedTxns[*txHash]; exists {
+	// rejected, the transaction was unsolicited.  Transactions from peers
+	// with the forcerelay permission are always reconsidered since they
+	// may have been rejected by policy alone.
+	if _, exists = sm.rejectedTxns[*txHash]; exists && !forceRelay {
 		log.Debugf("Ignoring unsolicited previously rejected "+
 			"transaction %v from %s", txHash, peer)
 		return
@@ -600,15 +760,21 @@ func (sm *SyncManager) handleTxMsg(tmsg *txMsg) {
 
 	// Process the transaction to include validation, insertion in the
 	// memory pool, orphan handling, etc.
-	acceptedTxs, err := sm.txMemPool.ProcessTransaction(tmsg.tx,
-		true, true, mempool.Tag(peer.ID()))
+	var acceptedTxs []*mempool.TxDesc
+	var err error
+	if forceRelay {
+		acceptedTxs, err = sm.txMemPool.ProcessTrustedTransaction(
+			tmsg.tx, true, mempool.Tag(peer.ID()))
+	} else {
+		acceptedTxs, err = sm.txMemPool.ProcessTransaction(tmsg.tx,
+			true, true, mempool.Tag(peer.ID()))
+	}
 
-	// Remove transaction from request maps. Either the mempool/chain
-	// already knows about it and as such we shouldn't have any more
-	// instances of trying to fetch it, or we failed to insert and thus
-	// we'll retry next time we get an inv.
-	delete(state.requestedTxns, *txHash)
-	delete(sm.requestedTxns, *txHash)
+	// Stop tracking the announcements of the transaction. Either the
+	// mempool/chain already knows about it and as such we shouldn't have
+	// any more instances of trying to fetch it, or we failed to insert and
+	// thus we'll retry next time we get an inv.
+	sm.txRequests.Forget(*txHash)
 
 	if err != nil {
 		// Do not request this transaction again until a new block
@@ -658,8 +824,17 @@ func (sm *SyncManager) current() bool {
 	return true
 }
 
-// handleBlockMsg handles block messages from all peers.
-func (sm *SyncManager) handleBlockMsg(bmsg *blockMsg) {
+// disconnectMisbehaving reports the passed protocol violation of the peer to
+// the peer notifier and disconnects the peer.  The offending message may be
+// nil.
+func (sm *SyncManager) disconnectMisbehaving(peer *peerpkg.Peer, reason string, msg wire.Message) {
+	sm.peerNotifier.PeerMisbehaving(peer, reason, msg, true)
+	peer.Disconnect()
+}
+
+// handleBlockMsg handles block messages from all peers.  The processing of the
+// block is cancelled when the passed context is done.
+func (sm *SyncManager) handleBlockMsg(ctx context.Context, bmsg *blockMsg) {
 	peer := bmsg.peer
 	state, exists := sm.peerStates[peer]
 	if !exists {
@@ -674,11 +849,16 @@ func (sm *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 		// to test duplicate block insertion fails.  Don't disconnect
 		// the peer or ignore the block when we're in regression test
 		// mode in this case so the chain code is actually fed the
-		// duplicate blocks.
-		if sm.chainParams != &chaincfg.RegressionNetParams {
+		// duplicate blocks.  Even then, unrequested blocks are only
+		// processed once the chain has the minimum required work so
+		// they can't be used to build up a low-work chain.
+		if sm.chainParams != &chaincfg.RegressionNetParams ||
+			!sm.chain.HasMinimumChainWork() {
+
 			log.Warnf("Got unrequested block %v from %s -- "+
 				"disconnecting", blockHash, peer.Addr())
-			peer.Disconnect()
+			sm.disconnectMisbehaving(peer, "unrequested block "+
+				blockHash.String(), bmsg.block.MsgBlock())
 			return
 		}
 	}
@@ -697,7 +877,7 @@ func (sm *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 		if firstNodeEl != nil {
 			firstNode := firstNodeEl.Value.(*headerNode)
 			if blockHash.IsEqual(firstNode.hash) {
-				behaviorFlags |= blockchain.BFFastAdd
+				behaviorFlags |= sm.headersFirstFlags()
 				if firstNode.hash.IsEqual(sm.nextCheckpoint.Hash) {
 					isCheckpointBlock = true
 				} else {
@@ -715,8 +895,17 @@ func (sm *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 
 	// Process the block to include validation, best chain selection, orphan
 	// handling, etc.
-	_, isOrphan, err := sm.chain.ProcessBlock(bmsg.block, behaviorFlags)
+	_, isOrphan, err := sm.chain.ProcessBlockContext(ctx, bmsg.block,
+		behaviorFlags)
 	if err != nil {
+		// Processing was cancelled because the sync manager is
+		// shutting down, which says nothing about the block.
+		if ctx.Err() != nil {
+			log.Infof("Stopped processing block %v from %s: %v",
+				blockHash, peer, err)
+			return
+		}
+
 		// When the error is a rule error, it means the block was simply
 		// rejected as opposed to something actually going wrong, so log
 		// it as such.  Otherwise, something really did go wrong, so log
@@ -724,6 +913,9 @@ func (sm *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 		if _, ok := err.(blockchain.RuleError); ok {
 			log.Infof("Rejected block %v from %s: %v", blockHash,
 				peer, err)
+			sm.peerNotifier.PeerMisbehaving(peer, fmt.Sprintf(
+				"invalid block %v: %v", blockHash, err),
+				bmsg.block.MsgBlock(), false)
 		} else {
 			log.Errorf("Failed to process block %v: %v",
 				blockHash, err)
@@ -785,6 +977,8 @@ func (sm *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 	} else {
 		if peer == sm.syncPeer {
 			sm.lastProgressTime = time.Now()
+			sm.blockStallWindow = narrowStallWindow(
+				sm.blockStallWindow)
 		}
 
 		// When the block is not an orphan, log information about it and
@@ -838,7 +1032,7 @@ func (sm *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 	sm.nextCheckpoint = sm.findNextHeaderCheckpoint(prevHeight)
 	if sm.nextCheckpoint != nil {
 		locator := blockchain.BlockLocator([]*chainhash.Hash{prevHash})
-		err := peer.PushGetHeadersMsg(locator, sm.nextCheckpoint.Hash)
+		err := sm.requestHeaders(peer, locator, sm.nextCheckpoint.Hash)
 		if err != nil {
 			log.Warnf("Failed to send getheaders message to "+
 				"peer %s: %v", peer.Addr(), err)
@@ -865,6 +1059,38 @@ func (sm *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 	}
 }
 
+// headersFirstFlags returns the behavior flags used to process a block whose
+// header was downloaded in headers-first mode.  Such blocks are only valid up
+// to the next checkpoint, so they are fast added without script validation
+// unless the checkpoints of the network are only advisory.
+func (sm *SyncManager) headersFirstFlags() blockchain.BehaviorFlags {
+	if sm.chainParams.AdvisoryCheckpoints {
+		return blockchain.BFNone
+	}
+	return blockchain.BFFastAdd
+}
+
+// leaveHeadersFirstMode switches from headers-first mode to normal mode, in
+// which blocks are fully validated as they are downloaded, by requesting the
+// blocks after the current best block from the passed peer.
+func (sm *SyncManager) leaveHeadersFirstMode(peer *peerpkg.Peer) {
+	sm.headersFirstMode = false
+	sm.headerList.Init()
+	sm.startHeader = nil
+
+	locator, err := sm.chain.LatestBlockLocator()
+	if err != nil {
+		log.Errorf("Failed to get block locator for the latest block: %v",
+			err)
+		return
+	}
+	err = peer.PushGetBlocksMsg(locator, &zeroHash)
+	if err != nil {
+		log.Warnf("Failed to send getblocks message to peer %s: %v",
+			peer.Addr(), err)
+	}
+}
+
 // fetchHeaderBlocks creates and sends a request to the syncPeer for the next
 // list of blocks to be downloaded based on the current list of headers.
 func (sm *SyncManager) fetchHeaderBlocks() {
@@ -925,27 +1151,70 @@ func (sm *SyncManager) fetchHeaderBlocks() {
 // requested when performing a headers-first sync.
 func (sm *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 	peer := hmsg.peer
-	_, exists := sm.peerStates[peer]
+	state, exists := sm.peerStates[peer]
 	if !exists {
 		log.Warnf("Received headers message from unknown peer %s", peer)
 		return
 	}
 
-	// The remote peer is misbehaving if we didn't request headers.
+	// The remote peer is misbehaving if we didn't request headers from it.
+	// Only the sync peer is ever asked for headers, and only one request
+	// is outstanding at a time, which bounds the amount of header data any
+	// single peer can make us hold in memory.
 	msg := hmsg.headers
 	numHeaders := len(msg.Headers)
-	if !sm.headersFirstMode {
+	if !sm.headersFirstMode || peer != sm.syncPeer ||
+		!state.requestedHeaders {
+
 		log.Warnf("Got %d unrequested headers from %s -- "+
 			"disconnecting", numHeaders, peer.Addr())
-		peer.Disconnect()
+		sm.disconnectMisbehaving(peer, "unrequested headers", msg)
 		return
 	}
+	state.requestedHeaders = false
 
-	// Nothing to do for an empty headers message.
+	// An empty headers message means the peer has no more headers, so the
+	// chain it is syncing us to ends with the final header in the list.
 	if numHeaders == 0 {
+		sm.checkHeadersChainWork(peer, msg)
+		return
+	}
+
+	// Ensure all of the headers actually commit to the proof of work they
+	// claim.  This makes feeding long chains of garbage headers
+	// prohibitively expensive since each one must be mined.  The scrypt
+	// hashes are checked for the whole message at once across all of the
+	// processors since they are the most expensive part of the sync.
+	badIdx, err := blockchain.CheckHeadersProofOfWork(msg.Headers,
+		sm.chainParams.PowLimit)
+	if err != nil {
+		blockHash := msg.Headers[badIdx].BlockHash()
+		log.Warnf("Received invalid block header %s from peer %s: %v "+
+			"-- disconnecting", blockHash, peer.Addr(), err)
+		sm.disconnectMisbehaving(peer, fmt.Sprintf("invalid block "+
+			"header %s: %v", blockHash, err), msg)
 		return
 	}
 
+	// Ensure the claimed difficulty of the headers is within what the
+	// difficulty algorithms allow since the last checkpoint so cheap low
+	// difficulty headers are rejected before their blocks are requested.
+	// Headers which don't follow the header list are rejected below.
+	if prevNodeEl := sm.headerList.Back(); prevNodeEl != nil {
+		height := prevNodeEl.Value.(*headerNode).height + 1
+		badIdx, err = sm.chain.CheckHeadersDifficulty(msg.Headers, height)
+		if err != nil {
+			blockHash := msg.Headers[badIdx].BlockHash()
+			log.Warnf("Received block header %s with invalid "+
+				"difficulty from peer %s: %v -- disconnecting",
+				blockHash, peer.Addr(), err)
+			sm.disconnectMisbehaving(peer, fmt.Sprintf("block "+
+				"header %s with invalid difficulty: %v",
+				blockHash, err), msg)
+			return
+		}
+	}
+
 	// Process all of the received headers ensuring each one connects to the
 	// previous and that checkpoints match.
 	receivedCheckpoint := false
@@ -969,6 +1238,10 @@ func (sm *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 		prevNode := prevNodeEl.Value.(*headerNode)
 		if prevNode.hash.IsEqual(&blockHeader.PrevBlock) {
 			node.height = prevNode.height + 1
+			if prevNode.workSum != nil {
+				node.workSum = new(big.Int).Add(prevNode.workSum,
+					blockchain.CalcWork(blockHeader.Bits))
+			}
 			e := sm.headerList.PushBack(&node)
 			if sm.startHeader == nil {
 				sm.startHeader = e
@@ -977,7 +1250,8 @@ func (sm *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 			log.Warnf("Received block header that does not "+
 				"properly connect to the chain from peer %s "+
 				"-- disconnecting", peer.Addr())
-			peer.Disconnect()
+			sm.disconnectMisbehaving(peer, "block header "+
+				blockHash.String()+" does not connect", msg)
 			return
 		}
 
@@ -988,6 +1262,20 @@ func (sm *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 				log.Infof("Verified downloaded block "+
 					"header against checkpoint at height "+
 					"%d/hash %s", node.height, node.hash)
+			} else if sm.chainParams.AdvisoryCheckpoints {
+				// The headers of a chain which does not
+				// match an advisory checkpoint are not
+				// invalid, but the chain won't reach the
+				// checkpoint, so its blocks are downloaded
+				// in normal mode instead.
+				log.Warnf("Block header at height %d/hash "+
+					"%s from peer %s does not match "+
+					"advisory checkpoint hash of %s -- "+
+					"switching to normal mode", node.height,
+					node.hash, peer.Addr(),
+					sm.nextCheckpoint.Hash)
+				sm.leaveHeadersFirstMode(peer)
+				return
 			} else {
 				log.Warnf("Block header at height %d/hash "+
 					"%s from peer %s does NOT match "+
@@ -995,13 +1283,22 @@ func (sm *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 					"disconnecting", node.height,
 					node.hash, peer.Addr(),
 					sm.nextCheckpoint.Hash)
-				peer.Disconnect()
+				sm.disconnectMisbehaving(peer, fmt.Sprintf(
+					"block header %s does not match the "+
+						"checkpoint at height %d",
+					node.hash, node.height), msg)
 				return
 			}
 			break
 		}
 	}
 
+	// Downloading the headers is progress in headers-first mode since no
+	// blocks are requested until the next checkpoint is reached.
+	if peer == sm.syncPeer {
+		sm.lastProgressTime = time.Now()
+	}
+
 	// When this header is a checkpoint, switch to fetching the blocks for
 	// all of the headers since the last checkpoint.
 	if receivedCheckpoint {
@@ -1017,11 +1314,19 @@ func (sm *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 		return
 	}
 
+	// A headers message which is not full means the peer has no more
+	// headers, so the chain it is syncing us to ends with the final one.
+	if numHeaders < wire.MaxBlockHeadersPerMsg &&
+		!sm.checkHeadersChainWork(peer, msg) {
+
+		return
+	}
+
 	// This header is not a checkpoint, so request the next batch of
 	// headers starting from the latest known header and ending with the
 	// next checkpoint.
 	locator := blockchain.BlockLocator([]*chainhash.Hash{finalHash})
-	err := peer.PushGetHeadersMsg(locator, sm.nextCheckpoint.Hash)
+	err = sm.requestHeaders(peer, locator, sm.nextCheckpoint.Hash)
 	if err != nil {
 		log.Warnf("Failed to send getheaders message to "+
 			"peer %s: %v", peer.Addr(), err)
@@ -1029,6 +1334,34 @@ func (sm *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 	}
 }
 
+// checkHeadersChainWork ensures the chain ending with the final header in the
+// list of headers being downloaded has at least the minimum amount of work
+// required by the chain parameters, disconnecting the peer which served the
+// headers when it does not.  It is called once the peer ran out of headers
+// before reaching the next checkpoint, since the blocks of a chain with less
+// work than that are never worth downloading during the initial sync.
+func (sm *SyncManager) checkHeadersChainWork(peer *peerpkg.Peer,
+	msg *wire.MsgHeaders) bool {
+
+	minWork := sm.chainParams.MinimumChainWork
+	finalNodeEl := sm.headerList.Back()
+	if minWork == nil || finalNodeEl == nil {
+		return true
+	}
+	finalNode := finalNodeEl.Value.(*headerNode)
+	if finalNode.workSum == nil || finalNode.workSum.Cmp(minWork) >= 0 {
+		return true
+	}
+
+	log.Warnf("Received headers for a chain ending with block %s from "+
+		"peer %s with less than the minimum required work -- "+
+		"disconnecting", finalNode.hash, peer.Addr())
+	sm.disconnectMisbehaving(peer, fmt.Sprintf("header chain ending with "+
+		"block %s has less than the minimum required work",
+		finalNode.hash), msg)
+	return false
+}
+
 // handleNotFoundMsg handles notfound messages from all peers.
 func (sm *SyncManager) handleNotFoundMsg(nfmsg *notFoundMsg) {
 	peer := nfmsg.peer
@@ -1054,9 +1387,13 @@ func (sm *SyncManager) handleNotFoundMsg(nfmsg *notFoundMsg) {
 		case wire.InvTypeWitnessTx:
 			fallthrough
 		case wire.InvTypeTx:
-			if _, exists := state.requestedTxns[inv.Hash]; exists {
-				delete(state.requestedTxns, inv.Hash)
-				delete(sm.requestedTxns, inv.Hash)
+			sm.txRequests.NotFound(peer.ID(), inv.Hash)
+
+		case wire.InvTypeMwebHeader, wire.InvTypeMwebLeafset:
+			req := state.mwebRequest
+			if req != nil && req.hash == inv.Hash {
+				sm.failMwebRequest(peer, state, fmt.Errorf(
+					"peer does not have %v", inv))
 			}
 		}
 	}
@@ -1160,6 +1497,9 @@ func (sm *SyncManager) handleInvMsg(imsg *invMsg) {
 		}
 	}
 
+	hasMinWork := sm.chain.HasMinimumChainWork()
+	now := time.Now()
+
 	// Request the advertised inventory if we don't already have it.  Also,
 	// request parent blocks of orphans if we receive one we already have.
 	// Finally, attempt to detect potential stalls due to long side chains
@@ -1203,6 +1543,21 @@ func (sm *SyncManager) handleInvMsg(imsg *invMsg) {
 				}
 			}
 
+			// Don't request transactions until the best chain has
+			// the minimum required work.  Otherwise, track the
+			// announcement so the transaction is requested from the
+			// best of the peers which announced it.
+			switch iv.Type {
+			case wire.InvTypeTx, wire.InvTypeWitnessTx,
+				wire.InvTypeMwebTx:
+
+				if hasMinWork {
+					sm.txRequests.Announce(peer.ID(),
+						!peer.Inbound(), iv.Hash, now)
+				}
+				continue
+			}
+
 			// Ignore invs block invs from non-witness enabled
 			// peers, as after segwit activation we only want to
 			// download from peers that can provide us full witness
@@ -1285,27 +1640,6 @@ func (sm *SyncManager) handleInvMsg(imsg *invMsg) {
 					iv.Type = wire.InvTypeWitnessBlock
 				}
 
-				gdmsg.AddInvVect(iv)
-				numRequested++
-			}
-
-		case wire.InvTypeMwebTx:
-			fallthrough
-		case wire.InvTypeWitnessTx:
-			fallthrough
-		case wire.InvTypeTx:
-			// Request the transaction if there is not already a
-			// pending request.
-			if _, exists := sm.requestedTxns[iv.Hash]; !exists {
-				limitAdd(sm.requestedTxns, iv.Hash, maxRequestedTxns)
-				limitAdd(state.requestedTxns, iv.Hash, maxRequestedTxns)
-
-				// If the peer is capable, request the txn
-				// including all witness data.
-				if peer.IsWitnessEnabled() {
-					iv.Type = wire.InvTypeWitnessTx
-				}
-
 				gdmsg.AddInvVect(iv)
 				numRequested++
 			}
@@ -1316,11 +1650,76 @@ func (sm *SyncManager) handleInvMsg(imsg *invMsg) {
 		}
 	}
 	state.requestQueue = requestQueue
+	sm.requestTxns(peer, gdmsg, now)
 	if len(gdmsg.InvList) > 0 {
 		peer.QueueMessage(gdmsg, nil)
 	}
 }
 
+// requestTxns adds the transactions the transaction request tracker schedules
+// for download from the passed peer to the passed getdata message, up to the
+// maximum number of inventory vectors it may hold.
+func (sm *SyncManager) requestTxns(peer *peerpkg.Peer, gdmsg *wire.MsgGetData,
+	now time.Time) {
+
+	limit := wire.MaxInvPerMsg - len(gdmsg.InvList)
+	for _, hash := range sm.txRequests.Requestable(peer.ID(), now, limit) {
+		// If the peer is capable, request the txn including all
+		// witness data.
+		invType := wire.InvTypeTx
+		if peer.IsWitnessEnabled() {
+			invType = wire.InvTypeWitnessTx
+		}
+		gdmsg.AddInvVect(wire.NewInvVect(invType, &hash))
+	}
+}
+
+// handleTxRequestTick expires the transaction requests which were not answered
+// in time and requests the transactions which are due from each peer.
+func (sm *SyncManager) handleTxRequestTick() {
+	now := time.Now()
+	if expired := sm.txRequests.Expire(now); expired > 0 {
+		log.Debugf("Expired %d transaction requests", expired)
+	}
+	for peer := range sm.peerStates {
+		gdmsg := wire.NewMsgGetData()
+		sm.requestTxns(peer, gdmsg, now)
+		if len(gdmsg.InvList) > 0 {
+			peer.QueueMessage(gdmsg, nil)
+		}
+	}
+}
+
+// handleFetchBlockMsg requests the block of a fetchBlockMsg from the peer with
+// the requested ID.  The block is marked as requested so it is processed when
+// the peer delivers it, which stores it even when it is on a side chain as long
+// as its parent is known.
+func (sm *SyncManager) handleFetchBlockMsg(msg *fetchBlockMsg) error {
+	for peer, state := range sm.peerStates {
+		if peer.ID() != msg.peerID {
+			continue
+		}
+
+		iv := wire.NewInvVect(wire.InvTypeBlock, msg.hash)
+		if peer.IsMwebEnabled() {
+			iv.Type = wire.InvTypeMwebBlock
+		} else if peer.IsWitnessEnabled() {
+			iv.Type = wire.InvTypeWitnessBlock
+		}
+		sm.requestedBlocks[*msg.hash] = struct{}{}
+		state.requestedBlocks[*msg.hash] = struct{}{}
+
+		gdmsg := wire.NewMsgGetData()
+		gdmsg.AddInvVect(iv)
+		peer.QueueMessage(gdmsg, nil)
+
+		log.Infof("Requested block %v from peer %s", msg.hash, peer)
+		return nil
+	}
+
+	return fmt.Errorf("peer %d does not exist", msg.peerID)
+}
+
 // blockHandler is the main handler for the sync manager.  It must be run as a
 // goroutine.  It processes block and inv messages in a separate goroutine
 // from the peer handlers so the block (MsgBlock) messages are handled by a
@@ -1328,8 +1727,22 @@ func (sm *SyncManager) handleInvMsg(imsg *invMsg) {
 // important because the sync manager controls which blocks are needed and how
 // the fetching should proceed.
 func (sm *SyncManager) blockHandler() {
+	// Cancel the processing of blocks, which can take minutes for a deep
+	// reorganization, as soon as the sync manager is shutting down.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-sm.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	stallTicker := time.NewTicker(stallSampleInterval)
 	defer stallTicker.Stop()
+	txRequestTicker := time.NewTicker(txRequestInterval)
+	defer txRequestTicker.Stop()
 
 out:
 	for {
@@ -1344,7 +1757,7 @@ out:
 				msg.reply <- struct{}{}
 
 			case *blockMsg:
-				sm.handleBlockMsg(msg)
+				sm.handleBlockMsg(ctx, msg)
 				msg.reply <- struct{}{}
 
 			case *invMsg:
@@ -1359,6 +1772,15 @@ out:
 			case *donePeerMsg:
 				sm.handleDonePeerMsg(msg.peer)
 
+			case *mwebHeaderMsg:
+				sm.handleMwebHeaderMsg(msg)
+
+			case *mwebLeafsetMsg:
+				sm.handleMwebLeafsetMsg(msg)
+
+			case *mwebUtxosMsg:
+				sm.handleMwebUtxosMsg(msg)
+
 			case getSyncPeerMsg:
 				var peerID int32
 				if sm.syncPeer != nil {
@@ -1367,13 +1789,14 @@ out:
 				msg.reply <- peerID
 
 			case processBlockMsg:
-				_, isOrphan, err := sm.chain.ProcessBlock(
-					msg.block, msg.flags)
+				_, isOrphan, err := sm.chain.ProcessBlockContext(
+					ctx, msg.block, msg.flags)
 				if err != nil {
 					msg.reply <- processBlockResponse{
 						isOrphan: false,
 						err:      err,
 					}
+					continue
 				}
 
 				msg.reply <- processBlockResponse{
@@ -1388,6 +1811,15 @@ out:
 				// Wait until the sender unpauses the manager.
 				<-msg.unpause
 
+			case fetchBlockMsg:
+				msg.reply <- sm.handleFetchBlockMsg(&msg)
+
+			case fetchMwebStateMsg:
+				err := sm.handleFetchMwebStateMsg(&msg)
+				if err != nil {
+					msg.reply <- fetchMwebStateResponse{err: err}
+				}
+
 			default:
 				log.Warnf("Invalid message type in block "+
 					"handler: %T", msg)
@@ -1396,6 +1828,9 @@ out:
 		case <-stallTicker.C:
 			sm.handleStallSample()
 
+		case <-txRequestTicker.C:
+			sm.handleTxRequestTick()
+
 		case <-sm.quit:
 			break out
 		}
@@ -1445,7 +1880,8 @@ func (sm *SyncManager) handleBlockchainNotification(notification *blockchain.Not
 		// transaction are NOT removed recursively because they are still
 		// valid.
 		for _, tx := range block.Transactions()[1:] {
-			sm.txMemPool.RemoveTransaction(tx, false)
+			sm.txMemPool.RemoveTransaction(tx, false,
+				mempool.RemovalReasonBlock)
 			sm.txMemPool.RemoveDoubleSpends(tx)
 			sm.txMemPool.RemoveOrphan(tx)
 			sm.peerNotifier.TransactionConfirmed(tx)
@@ -1484,7 +1920,8 @@ func (sm *SyncManager) handleBlockchainNotification(notification *blockchain.Not
 				// Remove the transaction and all transactions
 				// that depend on it if it wasn't accepted into
 				// the transaction pool.
-				sm.txMemPool.RemoveTransaction(tx, true)
+				sm.txMemPool.RemoveTransaction(tx, true,
+					mempool.RemovalReasonReorg)
 			}
 		}
 
@@ -1618,6 +2055,15 @@ func (sm *SyncManager) ProcessBlock(block *ltcutil.Block, flags blockchain.Behav
 	return response.isOrphan, response.err
 }
 
+// FetchBlock requests the block with the passed hash from the peer with the
+// passed ID.  The block is processed once the peer delivers it, which stores it
+// even when it is on a side chain as long as its parent is known.
+func (sm *SyncManager) FetchBlock(hash *chainhash.Hash, peerID int32) error {
+	reply := make(chan error)
+	sm.msgChan <- fetchBlockMsg{hash: hash, peerID: peerID, reply: reply}
+	return <-reply
+}
+
 // IsCurrent returns whether or not the sync manager believes it is synced with
 // the connected peers.
 func (sm *SyncManager) IsCurrent() bool {
@@ -1640,19 +2086,20 @@ func (sm *SyncManager) Pause() chan<- struct{} {
 // block, tx, and inv updates.
 func New(config *Config) (*SyncManager, error) {
 	sm := SyncManager{
-		peerNotifier:    config.PeerNotifier,
-		chain:           config.Chain,
-		txMemPool:       config.TxMemPool,
-		chainParams:     config.ChainParams,
-		rejectedTxns:    make(map[chainhash.Hash]struct{}),
-		requestedTxns:   make(map[chainhash.Hash]struct{}),
-		requestedBlocks: make(map[chainhash.Hash]struct{}),
-		peerStates:      make(map[*peerpkg.Peer]*peerSyncState),
-		progressLogger:  newBlockProgressLogger("Processed", log),
-		msgChan:         make(chan interface{}, config.MaxPeers*3),
-		headerList:      list.New(),
-		quit:            make(chan struct{}),
-		feeEstimator:    config.FeeEstimator,
+		peerNotifier:     config.PeerNotifier,
+		chain:            config.Chain,
+		txMemPool:        config.TxMemPool,
+		chainParams:      config.ChainParams,
+		rejectedTxns:     make(map[chainhash.Hash]struct{}),
+		txRequests:       newTxRequestTracker(),
+		requestedBlocks:  make(map[chainhash.Hash]struct{}),
+		peerStates:       make(map[*peerpkg.Peer]*peerSyncState),
+		blockStallWindow: minBlockStallWindow,
+		progressLogger:   newBlockProgressLogger("Processed", log),
+		msgChan:          make(chan interface{}, config.MaxPeers*3),
+		headerList:       list.New(),
+		quit:             make(chan struct{}),
+		feeEstimator:     config.FeeEstimator,
 	}
 
 	best := sm.chain.BestSnapshot()
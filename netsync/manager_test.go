@@ -0,0 +1,331 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"container/list"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+	_ "github.com/ltcsuite/ltcd/database/ffldb"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/mempool"
+	peerpkg "github.com/ltcsuite/ltcd/peer"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// misbehaviorNotifier is a PeerNotifier which records the peers reported as
+// misbehaving.
+type misbehaviorNotifier struct {
+	misbehaving []*peerpkg.Peer
+}
+
+func (n *misbehaviorNotifier) AnnounceNewTransactions([]*mempool.TxDesc) {}
+
+func (n *misbehaviorNotifier) UpdatePeerHeights(*chainhash.Hash, int32,
+	*peerpkg.Peer) {
+}
+
+func (n *misbehaviorNotifier) RelayInventory(*wire.InvVect, interface{}) {}
+
+func (n *misbehaviorNotifier) TransactionConfirmed(*ltcutil.Tx) {}
+
+func (n *misbehaviorNotifier) PeerMisbehaving(peer *peerpkg.Peer, _ string,
+	_ wire.Message, _ bool) {
+
+	n.misbehaving = append(n.misbehaving, peer)
+}
+
+// TestBetterSyncCandidate ensures sync candidates are ranked by their announced
+// work first and by their height otherwise.
+func TestBetterSyncCandidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		work        *big.Int
+		height      int32
+		otherWork   *big.Int
+		otherHeight int32
+		want        bool
+	}{{
+		name:        "more work",
+		work:        big.NewInt(200),
+		height:      10,
+		otherWork:   big.NewInt(100),
+		otherHeight: 20,
+		want:        true,
+	}, {
+		name:        "less work",
+		work:        big.NewInt(100),
+		height:      20,
+		otherWork:   big.NewInt(200),
+		otherHeight: 10,
+		want:        false,
+	}, {
+		name:        "equal work, higher",
+		work:        big.NewInt(100),
+		height:      20,
+		otherWork:   big.NewInt(100),
+		otherHeight: 10,
+		want:        true,
+	}, {
+		name:        "known work over unknown",
+		work:        big.NewInt(100),
+		height:      10,
+		otherHeight: 20,
+		want:        true,
+	}, {
+		name:        "unknown work under known",
+		height:      20,
+		otherWork:   big.NewInt(100),
+		otherHeight: 10,
+		want:        false,
+	}, {
+		name:        "unknown work, higher",
+		height:      20,
+		otherHeight: 10,
+		want:        true,
+	}, {
+		name:        "unknown work, same height",
+		height:      10,
+		otherHeight: 10,
+		want:        false,
+	}}
+
+	for _, test := range tests {
+		got := betterSyncCandidate(test.work, test.height,
+			test.otherWork, test.otherHeight)
+		if got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+// TestBlockStallWindow ensures the block stall window widens on stalls and
+// narrows on progress within its bounds.
+func TestBlockStallWindow(t *testing.T) {
+	window := minBlockStallWindow
+	for i := 0; i < 10; i++ {
+		window = widenStallWindow(window)
+	}
+	if window != maxBlockStallWindow {
+		t.Fatalf("widened window is %v, want %v", window,
+			maxBlockStallWindow)
+	}
+
+	window = narrowStallWindow(window)
+	if window >= maxBlockStallWindow || window <= minBlockStallWindow {
+		t.Fatalf("narrowed window %v is out of range", window)
+	}
+	for i := 0; i < 100; i++ {
+		window = narrowStallWindow(window)
+	}
+	if window != minBlockStallWindow {
+		t.Fatalf("narrowed window is %v, want %v", window,
+			minBlockStallWindow)
+	}
+}
+
+// newHeadersTestChain returns a chain on the regression test network along
+// with a chain of headers mined on top of its genesis block.
+func newHeadersTestChain(t *testing.T) (*blockchain.BlockChain,
+	chaincfg.Params, []*wire.BlockHeader) {
+
+	params := chaincfg.RegressionNetParams
+	db, err := database.Create("ffldb", t.TempDir(), params.Net)
+	if err != nil {
+		t.Fatalf("unable to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		ChainParams: &params,
+		TimeSource:  blockchain.NewMedianTime(),
+		SigCache:    txscript.NewSigCache(1000),
+	})
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+
+	// Mine a header chain on top of the genesis block.
+	genesis := params.GenesisBlock.Header
+	headers := make([]*wire.BlockHeader, 10)
+	prevHash := params.GenesisHash
+	for i := range headers {
+		header := wire.NewBlockHeader(1, prevHash, &chainhash.Hash{},
+			params.PowLimitBits, 0)
+		header.Timestamp = genesis.Timestamp.Add(time.Duration(i+1) *
+			time.Minute)
+		for {
+			powHash := header.PowHash()
+			if blockchain.HashToBig(&powHash).Cmp(params.PowLimit) <= 0 {
+				break
+			}
+			header.Nonce++
+		}
+		headers[i] = header
+		hash := header.BlockHash()
+		prevHash = &hash
+	}
+
+	return chain, params, headers
+}
+
+// newHeadersTestSyncManager returns a sync manager in headers-first mode which
+// syncs the passed chain from the passed peer up to the passed checkpoint.
+func newHeadersTestSyncManager(t *testing.T, chain *blockchain.BlockChain,
+	params *chaincfg.Params, peer *peerpkg.Peer,
+	nextCheckpoint *chaincfg.Checkpoint) (*SyncManager,
+	*misbehaviorNotifier, *peerSyncState) {
+
+	notifier := &misbehaviorNotifier{}
+	state := &peerSyncState{}
+	sm := &SyncManager{
+		peerNotifier: notifier,
+		chain:        chain,
+		chainParams:  params,
+		peerStates: map[*peerpkg.Peer]*peerSyncState{
+			peer: state,
+		},
+		headerList:     list.New(),
+		syncPeer:       peer,
+		nextCheckpoint: nextCheckpoint,
+	}
+	sm.resetHeaderState(params.GenesisHash, 0)
+	sm.headersFirstMode = true
+	return sm, notifier, state
+}
+
+// newHeadersTestPeer returns an unconnected outbound peer to sync headers from.
+func newHeadersTestPeer(t *testing.T, params *chaincfg.Params) *peerpkg.Peer {
+	peer, err := peerpkg.NewOutboundPeer(&peerpkg.Config{
+		ChainParams: params,
+	}, "10.0.0.1:19444")
+	if err != nil {
+		t.Fatalf("NewOutboundPeer: unexpected error: %v", err)
+	}
+	return peer
+}
+
+// sendHeaders delivers the passed batches of headers from the peer to the sync
+// manager as requested headers messages.
+func sendHeaders(sm *SyncManager, peer *peerpkg.Peer, state *peerSyncState,
+	batches [][]*wire.BlockHeader) {
+
+	for _, batch := range batches {
+		state.requestedHeaders = true
+		msg := wire.NewMsgHeaders()
+		for _, header := range batch {
+			msg.AddBlockHeader(header)
+		}
+		sm.handleHeadersMsg(&headersMsg{headers: msg, peer: peer})
+	}
+}
+
+// TestHeadersMinimumChainWork ensures a header chain served during the initial
+// sync which ends with less than the minimum chain work is rejected, while one
+// with enough work has its download continued.
+func TestHeadersMinimumChainWork(t *testing.T) {
+	DisableLog()
+
+	chain, params, headers := newHeadersTestChain(t)
+
+	// The minimum chain work is reached by the final header.
+	genesis := params.GenesisBlock.Header
+	minWork := new(big.Int).Mul(blockchain.CalcWork(params.PowLimitBits),
+		big.NewInt(int64(len(headers))))
+	minWork.Add(minWork, blockchain.CalcWork(genesis.Bits))
+	syncParams := params
+	syncParams.MinimumChainWork = minWork
+
+	tests := []struct {
+		name      string
+		batches   [][]*wire.BlockHeader
+		reject    bool
+		requested bool
+	}{
+		{"short chain", [][]*wire.BlockHeader{headers[:5]}, true, false},
+		{"short chain ended by empty headers",
+			[][]*wire.BlockHeader{headers[:5], {}}, true, false},
+		{"chain with minimum work", [][]*wire.BlockHeader{headers},
+			false, true},
+		{"chain with minimum work ended by empty headers",
+			[][]*wire.BlockHeader{headers, {}}, false, false},
+	}
+	for _, test := range tests {
+		peer := newHeadersTestPeer(t, &params)
+		sm, notifier, state := newHeadersTestSyncManager(t, chain,
+			&syncParams, peer, &chaincfg.Checkpoint{
+				Height: 100,
+				Hash:   &chainhash.Hash{},
+			})
+		sendHeaders(sm, peer, state, test.batches)
+
+		rejected := len(notifier.misbehaving) != 0
+		if rejected != test.reject {
+			t.Errorf("%s: rejected is %v, want %v", test.name,
+				rejected, test.reject)
+		}
+		if state.requestedHeaders != test.requested {
+			t.Errorf("%s: requested next headers is %v, want %v",
+				test.name, state.requestedHeaders, test.requested)
+		}
+	}
+}
+
+// TestAdvisoryCheckpointHeaders ensures headers which conflict with a
+// checkpoint get the peer disconnected unless the checkpoints are advisory, in
+// which case the blocks are downloaded in normal mode instead, and that blocks
+// downloaded in headers-first mode are only fast added when the checkpoints
+// are enforced.
+func TestAdvisoryCheckpointHeaders(t *testing.T) {
+	DisableLog()
+
+	chain, params, headers := newHeadersTestChain(t)
+
+	for _, advisory := range []bool{false, true} {
+		syncParams := params
+		syncParams.AdvisoryCheckpoints = advisory
+
+		// The checkpoint at the height of the fifth header does not
+		// match it.
+		peer := newHeadersTestPeer(t, &params)
+		sm, notifier, state := newHeadersTestSyncManager(t, chain,
+			&syncParams, peer, &chaincfg.Checkpoint{
+				Height: 5,
+				Hash:   &chainhash.Hash{},
+			})
+
+		wantFlags := blockchain.BFFastAdd
+		if advisory {
+			wantFlags = blockchain.BFNone
+		}
+		if flags := sm.headersFirstFlags(); flags != wantFlags {
+			t.Errorf("advisory %v: got flags %v, want %v", advisory,
+				flags, wantFlags)
+		}
+
+		sendHeaders(sm, peer, state, [][]*wire.BlockHeader{headers})
+
+		rejected := len(notifier.misbehaving) != 0
+		if rejected == advisory {
+			t.Errorf("advisory %v: rejected is %v", advisory,
+				rejected)
+		}
+		if sm.headersFirstMode == advisory {
+			t.Errorf("advisory %v: headers-first mode is %v",
+				advisory, sm.headersFirstMode)
+		}
+		if advisory && sm.headerList.Len() != 0 {
+			t.Errorf("advisory %v: %d headers left to fetch",
+				advisory, sm.headerList.Len())
+		}
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil/mweb"
+	peerpkg "github.com/ltcsuite/ltcd/peer"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// mwebStateTimeout is how long FetchMwebState waits for a peer to deliver the
+// MWEB state of a block before giving up.
+const mwebStateTimeout = 2 * time.Minute
+
+// MwebState is the MWEB state of a block as served by a peer to MWEB light
+// clients.  The header, leafset and unspent outputs have all been verified to
+// be committed to by the block.
+type MwebState struct {
+	// Header proves the MWEB header is committed to by the HogEx
+	// transaction of the block.
+	Header *wire.MsgMwebHeader
+
+	// Leafset marks the leaves of the output MMR which are unspent as of
+	// the block.
+	Leafset *mweb.Leafset
+
+	// Utxos are the unspent MWEB outputs as of the block ordered by their
+	// leaf index.
+	Utxos []*wire.MwebNetUtxo
+}
+
+// mwebHeaderMsg packages a litecoin mwebheader message and the peer it came
+// from together so the block handler has access to that information.
+type mwebHeaderMsg struct {
+	header *wire.MsgMwebHeader
+	peer   *peerpkg.Peer
+}
+
+// mwebLeafsetMsg packages a litecoin mwebleafset message and the peer it came
+// from together so the block handler has access to that information.
+type mwebLeafsetMsg struct {
+	leafset *wire.MsgMwebLeafset
+	peer    *peerpkg.Peer
+}
+
+// mwebUtxosMsg packages a litecoin mwebutxos message and the peer it came from
+// together so the block handler has access to that information.
+type mwebUtxosMsg struct {
+	utxos *wire.MsgMwebUtxos
+	peer  *peerpkg.Peer
+}
+
+// fetchMwebStateResponse is a response sent to the reply channel of a
+// fetchMwebStateMsg.
+type fetchMwebStateResponse struct {
+	state *MwebState
+	err   error
+}
+
+// fetchMwebStateMsg is a message type to be sent across the message channel
+// for requesting the MWEB state of a block from a specific peer.
+type fetchMwebStateMsg struct {
+	hash   *chainhash.Hash
+	peerID int32
+	format wire.MwebNetUtxoType
+	reply  chan fetchMwebStateResponse
+}
+
+// mwebStateRequest tracks the progress of fetching the MWEB state of a block
+// from a peer.  The header and leafset are requested first, after which the
+// unspent outputs are requested in batches.
+type mwebStateRequest struct {
+	hash    chainhash.Hash
+	format  wire.MwebNetUtxoType
+	leafset []byte
+	state   MwebState
+
+	// nextIndex is the leaf index of the first unspent output of the next
+	// batch of unspent outputs requested from the peer.
+	nextIndex uint64
+
+	reply chan fetchMwebStateResponse
+}
+
+// finish replies to the request with the passed error, or with the fetched
+// state when it is nil.
+func (r *mwebStateRequest) finish(err error) {
+	if err != nil {
+		r.reply <- fetchMwebStateResponse{err: err}
+		return
+	}
+	r.reply <- fetchMwebStateResponse{state: &r.state}
+}
+
+// nextUnspentLeaf returns the index of the first leaf at or after the passed
+// index which is unspent according to the leafset.  The size of the leafset is
+// returned when there are no unspent leaves left.
+func nextUnspentLeaf(leafset *mweb.Leafset, index uint64) uint64 {
+	for ; index < leafset.Size; index++ {
+		if leafset.Contains(index) {
+			return index
+		}
+	}
+	return leafset.Size
+}
+
+// handleFetchMwebStateMsg requests the header and leafset of the MWEB state of
+// the block with the passed hash from the peer with the passed ID.  Any MWEB
+// state request still in progress with the peer is abandoned.
+func (sm *SyncManager) handleFetchMwebStateMsg(msg *fetchMwebStateMsg) error {
+	for peer, state := range sm.peerStates {
+		if peer.ID() != msg.peerID {
+			continue
+		}
+
+		if !peer.IsMwebLightClientEnabled() {
+			return fmt.Errorf("peer %d does not serve MWEB light "+
+				"clients", msg.peerID)
+		}
+		if state.mwebRequest != nil {
+			state.mwebRequest.finish(errors.New("request superseded"))
+		}
+		state.mwebRequest = &mwebStateRequest{
+			hash:   *msg.hash,
+			format: msg.format,
+			reply:  msg.reply,
+		}
+
+		gdmsg := wire.NewMsgGetData()
+		gdmsg.AddInvVect(wire.NewInvVect(wire.InvTypeMwebHeader, msg.hash))
+		gdmsg.AddInvVect(wire.NewInvVect(wire.InvTypeMwebLeafset, msg.hash))
+		peer.QueueMessage(gdmsg, nil)
+
+		log.Debugf("Requested MWEB state of block %v from peer %s",
+			msg.hash, peer)
+		return nil
+	}
+
+	return fmt.Errorf("peer %d does not exist", msg.peerID)
+}
+
+// mwebRequestFor returns the MWEB state request in progress with the passed
+// peer for the block with the passed hash, or nil when there is none.
+func (sm *SyncManager) mwebRequestFor(peer *peerpkg.Peer,
+	hash *chainhash.Hash) (*peerSyncState, *mwebStateRequest) {
+
+	state, exists := sm.peerStates[peer]
+	if !exists {
+		log.Warnf("Received MWEB message from unknown peer %s", peer)
+		return nil, nil
+	}
+	req := state.mwebRequest
+	if req == nil || req.hash != *hash {
+		log.Debugf("Received unrequested MWEB state of block %v from "+
+			"peer %s", hash, peer)
+		return nil, nil
+	}
+	return state, req
+}
+
+// failMwebRequest abandons the MWEB state request in progress with the peer,
+// replying to it with the passed error.
+func (sm *SyncManager) failMwebRequest(peer *peerpkg.Peer,
+	state *peerSyncState, err error) {
+
+	log.Debugf("Unable to fetch MWEB state of block %v from peer %s: %v",
+		state.mwebRequest.hash, peer, err)
+	state.mwebRequest.finish(err)
+	state.mwebRequest = nil
+}
+
+// handleMwebHeaderMsg handles mwebheader messages from all peers.
+func (sm *SyncManager) handleMwebHeaderMsg(hmsg *mwebHeaderMsg) {
+	peer := hmsg.peer
+	blockHash := hmsg.header.Merkle.Header.BlockHash()
+	state, req := sm.mwebRequestFor(peer, &blockHash)
+	if req == nil || req.state.Header != nil {
+		return
+	}
+
+	if err := mweb.VerifyHeader(hmsg.header); err != nil {
+		sm.failMwebRequest(peer, state, err)
+		return
+	}
+	req.state.Header = hmsg.header
+	sm.advanceMwebRequest(peer, state)
+}
+
+// handleMwebLeafsetMsg handles mwebleafset messages from all peers.
+func (sm *SyncManager) handleMwebLeafsetMsg(lmsg *mwebLeafsetMsg) {
+	peer := lmsg.peer
+	state, req := sm.mwebRequestFor(peer, &lmsg.leafset.BlockHash)
+	if req == nil || req.leafset != nil {
+		return
+	}
+
+	req.leafset = lmsg.leafset.Leafset
+	sm.advanceMwebRequest(peer, state)
+}
+
+// handleMwebUtxosMsg handles mwebutxos messages from all peers.
+func (sm *SyncManager) handleMwebUtxosMsg(umsg *mwebUtxosMsg) {
+	peer := umsg.peer
+	msg := umsg.utxos
+	state, req := sm.mwebRequestFor(peer, &msg.BlockHash)
+	if req == nil || req.state.Leafset == nil {
+		return
+	}
+
+	switch {
+	case msg.StartIndex != req.nextIndex:
+		log.Debugf("Received unrequested MWEB outputs of block %v "+
+			"starting at leaf %d from peer %s", msg.BlockHash,
+			msg.StartIndex, peer)
+		return
+
+	case msg.OutputFormat != req.format || len(msg.Utxos) == 0 ||
+		len(msg.Utxos) > wire.MaxMwebUtxosPerQuery:
+
+		sm.failMwebRequest(peer, state, errors.New("malformed MWEB "+
+			"outputs"))
+		return
+
+	case !mweb.VerifyUtxos(&req.state.Header.MwebHeader,
+		req.state.Leafset, msg):
+
+		sm.failMwebRequest(peer, state, errors.New("MWEB outputs are "+
+			"not committed to by the block"))
+		return
+	}
+
+	req.state.Utxos = append(req.state.Utxos, msg.Utxos...)
+	lastIndex := msg.Utxos[len(msg.Utxos)-1].LeafIndex
+	req.nextIndex = nextUnspentLeaf(req.state.Leafset, lastIndex+1)
+	sm.advanceMwebRequest(peer, state)
+}
+
+// advanceMwebRequest moves the MWEB state request in progress with the peer
+// forward once the header and leafset are both known, requesting the next
+// batch of unspent outputs or replying to the request once all of them were
+// received.
+func (sm *SyncManager) advanceMwebRequest(peer *peerpkg.Peer,
+	state *peerSyncState) {
+
+	req := state.mwebRequest
+	if req.state.Header == nil || req.leafset == nil {
+		return
+	}
+
+	if req.state.Leafset == nil {
+		err := mweb.VerifyLeafset(req.state.Header,
+			wire.NewMsgMwebLeafset(&req.hash, req.leafset))
+		if err != nil {
+			sm.failMwebRequest(peer, state, err)
+			return
+		}
+
+		header := &req.state.Header.MwebHeader
+		if uint64(len(req.leafset)) < (header.OutputMMRSize+7)/8 {
+			sm.failMwebRequest(peer, state, errors.New("MWEB "+
+				"leafset is smaller than the output MMR"))
+			return
+		}
+		req.state.Leafset = &mweb.Leafset{
+			Bits:   req.leafset,
+			Size:   header.OutputMMRSize,
+			Height: uint32(header.Height),
+			Block:  &req.state.Header.Merkle.Header,
+		}
+		req.nextIndex = nextUnspentLeaf(req.state.Leafset, 0)
+	}
+
+	if req.nextIndex >= req.state.Leafset.Size {
+		log.Debugf("Fetched MWEB state of block %v with %d unspent "+
+			"outputs from peer %s", req.hash, len(req.state.Utxos),
+			peer)
+		req.finish(nil)
+		state.mwebRequest = nil
+		return
+	}
+
+	peer.QueueMessage(wire.NewMsgGetMwebUtxos(req.hash, req.nextIndex,
+		wire.MaxMwebUtxosPerQuery, req.format), nil)
+}
+
+// QueueMwebHeader adds the passed mwebheader message and peer to the block
+// handling queue.
+func (sm *SyncManager) QueueMwebHeader(header *wire.MsgMwebHeader,
+	peer *peerpkg.Peer) {
+
+	if atomic.LoadInt32(&sm.shutdown) != 0 {
+		return
+	}
+
+	sm.msgChan <- &mwebHeaderMsg{header: header, peer: peer}
+}
+
+// QueueMwebLeafset adds the passed mwebleafset message and peer to the block
+// handling queue.
+func (sm *SyncManager) QueueMwebLeafset(leafset *wire.MsgMwebLeafset,
+	peer *peerpkg.Peer) {
+
+	if atomic.LoadInt32(&sm.shutdown) != 0 {
+		return
+	}
+
+	sm.msgChan <- &mwebLeafsetMsg{leafset: leafset, peer: peer}
+}
+
+// QueueMwebUtxos adds the passed mwebutxos message and peer to the block
+// handling queue.
+func (sm *SyncManager) QueueMwebUtxos(utxos *wire.MsgMwebUtxos,
+	peer *peerpkg.Peer) {
+
+	if atomic.LoadInt32(&sm.shutdown) != 0 {
+		return
+	}
+
+	sm.msgChan <- &mwebUtxosMsg{utxos: utxos, peer: peer}
+}
+
+// FetchMwebState fetches the MWEB state of the block with the passed hash from
+// the peer with the passed ID, which must serve MWEB light clients.  The
+// unspent outputs are requested in the passed format.  Everything the peer
+// delivers is verified to be committed to by the block before it is returned.
+func (sm *SyncManager) FetchMwebState(hash *chainhash.Hash, peerID int32,
+	format wire.MwebNetUtxoType) (*MwebState, error) {
+
+	reply := make(chan fetchMwebStateResponse, 1)
+	sm.msgChan <- fetchMwebStateMsg{
+		hash:   hash,
+		peerID: peerID,
+		format: format,
+		reply:  reply,
+	}
+
+	select {
+	case response := <-reply:
+		return response.state, response.err
+	case <-time.After(mwebStateTimeout):
+		return nil, fmt.Errorf("timeout fetching MWEB state of block %v",
+			hash)
+	case <-sm.quit:
+		return nil, errors.New("sync manager is shutting down")
+	}
+}
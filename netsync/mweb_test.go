@@ -0,0 +1,177 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/bloom"
+	peerpkg "github.com/ltcsuite/ltcd/peer"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+	"lukechampine.com/blake3"
+)
+
+// mmrHash returns the hash of the output MMR node with the passed index which
+// is either a leaf committing to the passed output ID or the parent of the
+// passed child hashes.
+func mmrHash(index uint64, data ...[]byte) chainhash.Hash {
+	h := blake3.New(32, nil)
+	binary.Write(h, binary.LittleEndian, index)
+	if len(data) == 1 {
+		wire.WriteVarBytes(h, 0, data[0])
+	} else {
+		h.Write(data[0])
+		h.Write(data[1])
+	}
+	var hash chainhash.Hash
+	copy(hash[:], h.Sum(nil))
+	return hash
+}
+
+// TestFetchMwebState ensures the MWEB state of a block is only delivered once
+// its header, leafset and unspent outputs have been verified.
+func TestFetchMwebState(t *testing.T) {
+	DisableLog()
+
+	// The output MMR has two leaves of which only the second is unspent.
+	spentID := chainhash.Hash{0x01}
+	unspentID := chainhash.Hash{0x02}
+	spentLeaf := mmrHash(0, spentID[:])
+	unspentLeaf := mmrHash(1, unspentID[:])
+	leafset := []byte{0x40}
+	mwebHeader := wire.MwebHeader{
+		Height:        10,
+		OutputRoot:    mmrHash(2, spentLeaf[:], unspentLeaf[:]),
+		LeafsetRoot:   blake3.Sum256(leafset),
+		OutputMMRSize: 2,
+	}
+
+	// The HogEx transaction at the end of the block commits to the MWEB
+	// header.
+	hogAddr, _ := txscript.NewScriptBuilder().
+		AddOp(txscript.MwebHogAddrWitnessVersion + txscript.OP_1 - 1).
+		AddData(mwebHeader.Hash()[:]).Script()
+	hogex := wire.NewMsgTx(2)
+	hogex.IsHogEx = true
+	hogex.AddTxOut(wire.NewTxOut(0, hogAddr))
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(&wire.TxIn{PreviousOutPoint: wire.OutPoint{
+		Index: wire.MaxPrevOutIndex}})
+	coinbase.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_TRUE}))
+	block := ltcutil.NewBlock(&wire.MsgBlock{
+		Transactions: []*wire.MsgTx{coinbase, hogex},
+	})
+	block.MsgBlock().Header.MerkleRoot = blockchain.CalcMerkleRoot(
+		block.Transactions(), false)
+	filter := bloom.NewFilter(1, 0, 0.000001, wire.BloomUpdateNone)
+	filter.AddHash(block.Transactions()[1].Hash())
+	merkle, _ := bloom.NewMerkleBlock(block, filter)
+	blockHash := block.Hash()
+	headerMsg := wire.NewMsgMwebHeader(merkle, hogex, &mwebHeader)
+
+	peer, err := peerpkg.NewOutboundPeer(&peerpkg.Config{
+		ChainParams: &chaincfg.RegressionNetParams,
+	}, "10.0.0.1:19444")
+	if err != nil {
+		t.Fatalf("NewOutboundPeer: unexpected error: %v", err)
+	}
+	state := &peerSyncState{}
+	sm := &SyncManager{
+		peerStates: map[*peerpkg.Peer]*peerSyncState{peer: state},
+	}
+	fetch := func() chan fetchMwebStateResponse {
+		reply := make(chan fetchMwebStateResponse, 1)
+		state.mwebRequest = &mwebStateRequest{
+			hash:   *blockHash,
+			format: wire.MwebNetUtxoHashOnly,
+			reply:  reply,
+		}
+		return reply
+	}
+	utxosMsg := func(proof chainhash.Hash) *wire.MsgMwebUtxos {
+		return &wire.MsgMwebUtxos{
+			BlockHash:    *blockHash,
+			StartIndex:   1,
+			OutputFormat: wire.MwebNetUtxoHashOnly,
+			Utxos: []*wire.MwebNetUtxo{{
+				LeafIndex: 1,
+				OutputId:  &unspentID,
+			}},
+			ProofHashes: []*chainhash.Hash{&proof},
+		}
+	}
+
+	// The leafset may arrive before the header, after which the unspent
+	// outputs are requested starting at the first unspent leaf.
+	reply := fetch()
+	sm.handleMwebLeafsetMsg(&mwebLeafsetMsg{
+		leafset: wire.NewMsgMwebLeafset(blockHash, leafset),
+		peer:    peer,
+	})
+	sm.handleMwebHeaderMsg(&mwebHeaderMsg{header: headerMsg, peer: peer})
+	if state.mwebRequest == nil || state.mwebRequest.nextIndex != 1 {
+		t.Fatalf("unspent outputs were not requested from leaf 1")
+	}
+	sm.handleMwebUtxosMsg(&mwebUtxosMsg{
+		utxos: utxosMsg(spentLeaf),
+		peer:  peer,
+	})
+	response := <-reply
+	if response.err != nil {
+		t.Fatalf("FetchMwebState: unexpected error: %v", response.err)
+	}
+	if len(response.state.Utxos) != 1 ||
+		*response.state.Utxos[0].OutputId != unspentID ||
+		!response.state.Leafset.Contains(1) {
+
+		t.Fatalf("FetchMwebState: unexpected state %+v", response.state)
+	}
+	if state.mwebRequest != nil {
+		t.Fatalf("MWEB state request was not completed")
+	}
+
+	// Unspent outputs which are not committed to by the block fail the
+	// request.
+	reply = fetch()
+	sm.handleMwebHeaderMsg(&mwebHeaderMsg{header: headerMsg, peer: peer})
+	sm.handleMwebLeafsetMsg(&mwebLeafsetMsg{
+		leafset: wire.NewMsgMwebLeafset(blockHash, leafset),
+		peer:    peer,
+	})
+	sm.handleMwebUtxosMsg(&mwebUtxosMsg{
+		utxos: utxosMsg(chainhash.Hash{0xff}),
+		peer:  peer,
+	})
+	if response := <-reply; response.err == nil {
+		t.Fatalf("FetchMwebState: fetched unspent outputs with a bad proof")
+	}
+
+	// A leafset which does not match the header fails the request.
+	reply = fetch()
+	sm.handleMwebHeaderMsg(&mwebHeaderMsg{header: headerMsg, peer: peer})
+	sm.handleMwebLeafsetMsg(&mwebLeafsetMsg{
+		leafset: wire.NewMsgMwebLeafset(blockHash, []byte{0xc0}),
+		peer:    peer,
+	})
+	if response := <-reply; response.err == nil {
+		t.Fatalf("FetchMwebState: fetched a leafset which does not " +
+			"match the header")
+	}
+
+	// A peer without the requested data fails the request.
+	reply = fetch()
+	notFound := wire.NewMsgNotFound()
+	notFound.AddInvVect(wire.NewInvVect(wire.InvTypeMwebLeafset, blockHash))
+	sm.handleNotFoundMsg(&notFoundMsg{notFound: notFound, peer: peer})
+	if response := <-reply; response.err == nil {
+		t.Fatalf("FetchMwebState: fetched state the peer does not have")
+	}
+}
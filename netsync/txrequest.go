@@ -0,0 +1,322 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+const (
+	// maxPeerTxAnnouncements is the maximum number of transaction
+	// announcements tracked for a single peer.  Further announcements from
+	// the peer are ignored until some of the tracked ones are resolved.
+	maxPeerTxAnnouncements = 5000
+
+	// maxPeerTxInFlight is the maximum number of transactions requested from
+	// a single peer which have not been received yet.
+	maxPeerTxInFlight = 100
+
+	// inboundPeerTxDelay is how long requests for transactions announced by
+	// inbound peers are delayed.  This gives outbound peers, which are much
+	// harder for an attacker to control, the chance to announce the same
+	// transactions so they are requested from them instead.
+	inboundPeerTxDelay = 2 * time.Second
+
+	// txRequestTimeout is how long to wait for a requested transaction
+	// before requesting it from another peer which announced it.
+	txRequestTimeout = time.Minute
+
+	// txRequestRate is the number of transactions per second the request
+	// token bucket of a peer is refilled with.
+	txRequestRate = 50
+
+	// txRequestBurst is the capacity of the request token bucket of a peer,
+	// which is the maximum number of transactions that can be requested
+	// from it at once.
+	txRequestBurst = maxPeerTxInFlight
+)
+
+// txAnnouncement is the announcement of a transaction by a peer.
+type txAnnouncement struct {
+	peerID    int32
+	preferred bool
+	seq       uint64
+
+	// reqTime is the earliest time the transaction may be requested from
+	// the peer.
+	reqTime time.Time
+
+	// expiry is the time the request for the transaction expires.  It is
+	// zero when the transaction has not been requested from the peer.
+	expiry time.Time
+}
+
+// requested returns whether the transaction has been requested from the peer
+// which announced it.
+func (a *txAnnouncement) requested() bool {
+	return !a.expiry.IsZero()
+}
+
+// txRequestPeer tracks the transaction announcements of a single peer along
+// with its request token bucket.
+type txRequestPeer struct {
+	announced  map[chainhash.Hash]*txAnnouncement
+	inFlight   map[chainhash.Hash]*txAnnouncement
+	tokens     float64
+	lastRefill time.Time
+}
+
+// refill adds the tokens accumulated since the last refill of the request
+// token bucket of the peer.
+func (p *txRequestPeer) refill(now time.Time) {
+	elapsed := now.Sub(p.lastRefill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	p.tokens += elapsed * txRequestRate
+	if p.tokens > txRequestBurst {
+		p.tokens = txRequestBurst
+	}
+	p.lastRefill = now
+}
+
+// txRequestTracker schedules the download of transactions announced by
+// peers.  Every peer which announces a transaction is tracked as a candidate
+// to download it from, so a peer which fails to deliver a requested
+// transaction only delays its download until the request expires rather than
+// stalling it.  Among the candidates, outbound peers are preferred over
+// inbound ones, whose requests are additionally delayed, and the peer which
+// announced the transaction first is preferred otherwise.  The number and rate
+// of requests to each peer are limited by an in-flight limit and a token
+// bucket respectively.
+//
+// The tracker is not safe for concurrent access.  It is only accessed from the
+// block handler of the sync manager.
+type txRequestTracker struct {
+	txns  map[chainhash.Hash][]*txAnnouncement
+	peers map[int32]*txRequestPeer
+	seq   uint64
+}
+
+// newTxRequestTracker returns a new empty transaction request tracker.
+func newTxRequestTracker() *txRequestTracker {
+	return &txRequestTracker{
+		txns:  make(map[chainhash.Hash][]*txAnnouncement),
+		peers: make(map[int32]*txRequestPeer),
+	}
+}
+
+// Announce records the announcement of the passed transaction by the peer
+// with the passed ID.  Preferred peers, which are the outbound ones, may be
+// requested the transaction immediately while others have to wait for
+// inboundPeerTxDelay.  It returns false when the announcement is ignored
+// because the peer already announced the transaction or has too many
+// announcements tracked.
+func (t *txRequestTracker) Announce(peerID int32, preferred bool,
+	hash chainhash.Hash, now time.Time) bool {
+
+	peer, ok := t.peers[peerID]
+	if !ok {
+		peer = &txRequestPeer{
+			announced:  make(map[chainhash.Hash]*txAnnouncement),
+			inFlight:   make(map[chainhash.Hash]*txAnnouncement),
+			tokens:     txRequestBurst,
+			lastRefill: now,
+		}
+		t.peers[peerID] = peer
+	}
+	if _, ok := peer.announced[hash]; ok {
+		return false
+	}
+	if len(peer.announced) >= maxPeerTxAnnouncements {
+		return false
+	}
+
+	reqTime := now
+	if !preferred {
+		reqTime = now.Add(inboundPeerTxDelay)
+	}
+	t.seq++
+	announcement := &txAnnouncement{
+		peerID:    peerID,
+		preferred: preferred,
+		seq:       t.seq,
+		reqTime:   reqTime,
+	}
+	peer.announced[hash] = announcement
+	t.txns[hash] = append(t.txns[hash], announcement)
+	return true
+}
+
+// bestCandidate returns the announcement of the passed transaction which it
+// should be requested with at the passed time, or nil when it should not be
+// requested from any peer.  Transactions which are already in flight are not
+// requested again until the request expires.
+func (t *txRequestTracker) bestCandidate(hash chainhash.Hash,
+	now time.Time) *txAnnouncement {
+
+	var best *txAnnouncement
+	for _, announcement := range t.txns[hash] {
+		if announcement.requested() {
+			return nil
+		}
+		if announcement.reqTime.After(now) {
+			continue
+		}
+		if best == nil ||
+			(announcement.preferred && !best.preferred) ||
+			(announcement.preferred == best.preferred &&
+				announcement.seq < best.seq) {
+
+			best = announcement
+		}
+	}
+	return best
+}
+
+// Requestable returns the transactions which should be requested from the
+// peer with the passed ID at the passed time, in the order they were
+// announced, and marks them as requested.  At most limit transactions are
+// returned, and fewer when the in-flight limit or the request token bucket of
+// the peer do not allow more.
+func (t *txRequestTracker) Requestable(peerID int32, now time.Time,
+	limit int) []chainhash.Hash {
+
+	peer, ok := t.peers[peerID]
+	if !ok {
+		return nil
+	}
+	peer.refill(now)
+	if room := maxPeerTxInFlight - len(peer.inFlight); room < limit {
+		limit = room
+	}
+	if tokens := int(peer.tokens); tokens < limit {
+		limit = tokens
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	var candidates []*txAnnouncement
+	hashes := make(map[*txAnnouncement]chainhash.Hash)
+	for hash, announcement := range peer.announced {
+		if t.bestCandidate(hash, now) != announcement {
+			continue
+		}
+		candidates = append(candidates, announcement)
+		hashes[announcement] = hash
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].seq < candidates[j].seq
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	requested := make([]chainhash.Hash, 0, len(candidates))
+	for _, announcement := range candidates {
+		hash := hashes[announcement]
+		announcement.expiry = now.Add(txRequestTimeout)
+		peer.inFlight[hash] = announcement
+		peer.tokens--
+		requested = append(requested, hash)
+	}
+	return requested
+}
+
+// removeAnnouncement removes the announcement of the passed transaction by the
+// peer with the passed ID.
+func (t *txRequestTracker) removeAnnouncement(peerID int32,
+	hash chainhash.Hash) {
+
+	if peer, ok := t.peers[peerID]; ok {
+		delete(peer.announced, hash)
+		delete(peer.inFlight, hash)
+	}
+
+	announcements := t.txns[hash]
+	for i, announcement := range announcements {
+		if announcement.peerID != peerID {
+			continue
+		}
+		announcements = append(announcements[:i], announcements[i+1:]...)
+		break
+	}
+	if len(announcements) == 0 {
+		delete(t.txns, hash)
+		return
+	}
+	t.txns[hash] = announcements
+}
+
+// Expire removes the announcements whose requests expired by the passed time
+// so the transactions are requested from the other peers which announced
+// them.  It returns the number of expired requests.
+func (t *txRequestTracker) Expire(now time.Time) int {
+	var expired int
+	for peerID, peer := range t.peers {
+		for hash, announcement := range peer.inFlight {
+			if announcement.expiry.After(now) {
+				continue
+			}
+			t.removeAnnouncement(peerID, hash)
+			expired++
+		}
+	}
+	return expired
+}
+
+// NotFound removes the announcement of the passed transaction by the peer with
+// the passed ID after the peer replied that it does not have it.  Only
+// requested transactions are removed since the peer may otherwise still
+// provide it.
+func (t *txRequestTracker) NotFound(peerID int32, hash chainhash.Hash) {
+	peer, ok := t.peers[peerID]
+	if !ok {
+		return
+	}
+	if _, ok := peer.inFlight[hash]; ok {
+		t.removeAnnouncement(peerID, hash)
+	}
+}
+
+// Forget removes all announcements of the passed transaction.  It must be
+// called once a transaction has been received, from any peer, or is otherwise
+// no longer needed.
+func (t *txRequestTracker) Forget(hash chainhash.Hash) {
+	for _, announcement := range t.txns[hash] {
+		if peer, ok := t.peers[announcement.peerID]; ok {
+			delete(peer.announced, hash)
+			delete(peer.inFlight, hash)
+		}
+	}
+	delete(t.txns, hash)
+}
+
+// RemovePeer removes all announcements by the peer with the passed ID, which
+// makes the transactions it was requested available to the other peers which
+// announced them.
+func (t *txRequestTracker) RemovePeer(peerID int32) {
+	peer, ok := t.peers[peerID]
+	if !ok {
+		return
+	}
+	for hash := range peer.announced {
+		t.removeAnnouncement(peerID, hash)
+	}
+	delete(t.peers, peerID)
+}
+
+// InFlight returns the number of transactions requested from the peer with the
+// passed ID which have not been received yet.
+func (t *txRequestTracker) InFlight(peerID int32) int {
+	if peer, ok := t.peers[peerID]; ok {
+		return len(peer.inFlight)
+	}
+	return 0
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+// testTxHash returns a distinct transaction hash for the passed index.
+func testTxHash(i int) chainhash.Hash {
+	var hash chainhash.Hash
+	hash[0] = byte(i)
+	hash[1] = byte(i >> 8)
+	return hash
+}
+
+// TestTxRequestDeduplication ensures a transaction announced by several peers
+// is only requested from one of them at a time, and from the next one when the
+// request fails.
+func TestTxRequestDeduplication(t *testing.T) {
+	tracker := newTxRequestTracker()
+	now := time.Unix(1700000000, 0)
+	hash := testTxHash(1)
+
+	tracker.Announce(1, true, hash, now)
+	tracker.Announce(2, true, hash, now)
+	if tracker.Announce(2, true, hash, now) {
+		t.Fatalf("duplicate announcement was tracked")
+	}
+
+	// The first announcer is requested the transaction and the other one
+	// has to wait while the request is in flight.
+	if got := tracker.Requestable(2, now, 10); len(got) != 0 {
+		t.Fatalf("second announcer was requested %v", got)
+	}
+	got := tracker.Requestable(1, now, 10)
+	if !reflect.DeepEqual(got, []chainhash.Hash{hash}) {
+		t.Fatalf("first announcer was requested %v, want %v", got, hash)
+	}
+	if got := tracker.Requestable(2, now, 10); len(got) != 0 {
+		t.Fatalf("in flight transaction was requested again: %v", got)
+	}
+
+	// A stalling peer only delays the download until its request expires.
+	if n := tracker.Expire(now.Add(txRequestTimeout - time.Second)); n != 0 {
+		t.Fatalf("expired %d requests before the timeout", n)
+	}
+	later := now.Add(txRequestTimeout)
+	if n := tracker.Expire(later); n != 1 {
+		t.Fatalf("expired %d requests, want 1", n)
+	}
+	got = tracker.Requestable(2, later, 10)
+	if !reflect.DeepEqual(got, []chainhash.Hash{hash}) {
+		t.Fatalf("second announcer was requested %v, want %v", got, hash)
+	}
+
+	// Receiving the transaction forgets all announcements of it.
+	tracker.Forget(hash)
+	if len(tracker.txns) != 0 || tracker.InFlight(2) != 0 {
+		t.Fatalf("transaction is still tracked after it was received")
+	}
+}
+
+// TestTxRequestPreferOutbound ensures transactions are requested from outbound
+// peers before inbound ones, whose requests are delayed.
+func TestTxRequestPreferOutbound(t *testing.T) {
+	tracker := newTxRequestTracker()
+	now := time.Unix(1700000000, 0)
+	hash := testTxHash(1)
+
+	// Inbound announcements have to wait for the delay.
+	tracker.Announce(1, false, hash, now)
+	if got := tracker.Requestable(1, now, 10); len(got) != 0 {
+		t.Fatalf("inbound peer was requested %v before the delay", got)
+	}
+
+	// An outbound peer which announces the transaction during the delay
+	// is preferred over the inbound one which announced it first.
+	later := now.Add(inboundPeerTxDelay)
+	tracker.Announce(2, true, hash, later)
+	if got := tracker.Requestable(1, later, 10); len(got) != 0 {
+		t.Fatalf("inbound peer was requested %v over an outbound one",
+			got)
+	}
+	got := tracker.Requestable(2, later, 10)
+	if !reflect.DeepEqual(got, []chainhash.Hash{hash}) {
+		t.Fatalf("outbound peer was requested %v, want %v", got, hash)
+	}
+
+	// The inbound peer is used once the outbound one replies that it does
+	// not have the transaction.
+	tracker.NotFound(2, hash)
+	got = tracker.Requestable(1, later, 10)
+	if !reflect.DeepEqual(got, []chainhash.Hash{hash}) {
+		t.Fatalf("inbound peer was requested %v, want %v", got, hash)
+	}
+
+	// Disconnecting the peer makes the transaction untracked since no
+	// other peer announced it.
+	tracker.RemovePeer(1)
+	if _, ok := tracker.peers[1]; ok || len(tracker.txns) != 0 {
+		t.Fatalf("announcements are still tracked after the peer " +
+			"was removed")
+	}
+}
+
+// TestTxRequestLimits ensures the in-flight limit and the token bucket bound
+// the requests to a single peer and that the number of tracked announcements
+// is bounded.
+func TestTxRequestLimits(t *testing.T) {
+	tracker := newTxRequestTracker()
+	now := time.Unix(1700000000, 0)
+	for i := 0; i < maxPeerTxInFlight*2; i++ {
+		tracker.Announce(1, true, testTxHash(i), now)
+	}
+
+	// Only the in-flight limit can be requested, in announcement order.
+	got := tracker.Requestable(1, now, maxPeerTxInFlight*2)
+	if len(got) != maxPeerTxInFlight {
+		t.Fatalf("requested %d transactions, want %d", len(got),
+			maxPeerTxInFlight)
+	}
+	for i, hash := range got {
+		if hash != testTxHash(i) {
+			t.Fatalf("request %d is %v, want %v", i, hash,
+				testTxHash(i))
+		}
+	}
+	if got := tracker.Requestable(1, now, 10); len(got) != 0 {
+		t.Fatalf("requested %d transactions over the in-flight limit",
+			len(got))
+	}
+
+	// Receiving transactions frees in-flight slots, but the token bucket
+	// has to refill before more can be requested.
+	for _, hash := range got {
+		tracker.Forget(hash)
+	}
+	if got := tracker.Requestable(1, now, 10); len(got) != 0 {
+		t.Fatalf("requested %d transactions with an empty token bucket",
+			len(got))
+	}
+	later := now.Add(100 * time.Millisecond)
+	got = tracker.Requestable(1, later, 10)
+	if want := txRequestRate / 10; len(got) != want {
+		t.Fatalf("requested %d transactions after the refill, want %d",
+			len(got), want)
+	}
+
+	// Announcements beyond the limit of a peer are ignored.
+	for i := 0; i < maxPeerTxAnnouncements; i++ {
+		tracker.Announce(2, true, testTxHash(i), now)
+	}
+	if tracker.Announce(2, true, testTxHash(maxPeerTxAnnouncements), now) {
+		t.Fatalf("announcement over the limit was tracked")
+	}
+}
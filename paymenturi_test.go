@@ -0,0 +1,78 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
+	"github.com/ltcsuite/ltcd/ltcutil/mweb"
+	"github.com/ltcsuite/ltcd/ltcutil/uri"
+)
+
+// TestPaymentURINetworks ensures payment URIs for the addresses of each network
+// the node runs on round trip through the uri package, and that URIs for the
+// addresses of another network are rejected.
+func TestPaymentURINetworks(t *testing.T) {
+	t.Parallel()
+
+	networks := []*chaincfg.Params{
+		&chaincfg.MainNetParams,
+		&chaincfg.TestNet4Params,
+		&chaincfg.RegressionNetParams,
+	}
+	for _, params := range networks {
+		p2wpkh, _ := ltcutil.NewAddressWitnessPubKeyHash(make([]byte, 20),
+			params)
+		master, _ := hdkeychain.NewMaster(
+			make([]byte, hdkeychain.RecommendedSeedLen), params)
+		keychain, err := mweb.DeriveKeychain(master)
+		if err != nil {
+			t.Fatalf("%s: DeriveKeychain: %v", params.Name, err)
+		}
+		stealth := ltcutil.NewAddressMweb(keychain.Address(0), params)
+
+		tests := []struct {
+			uri  string
+			mweb bool
+		}{
+			{uri.Scheme + ":" + p2wpkh.EncodeAddress() + "?amount=1.5", false},
+			{uri.Scheme + ":" + stealth.EncodeAddress() + "?mweb=1", true},
+		}
+		for _, test := range tests {
+			u, err := uri.Parse(test.uri, params)
+			if err != nil {
+				t.Errorf("%s: Parse(%q): %v", params.Name, test.uri,
+					err)
+				continue
+			}
+			if u.MWEB != test.mweb || u.String() != test.uri {
+				t.Errorf("%s: Parse(%q) = %+v, encoded as %q",
+					params.Name, test.uri, u, u.String())
+			}
+
+			// The test networks share the MWEB address prefix, so
+			// only the networks with another prefix reject them.
+			for _, other := range networks {
+				hrp, otherHRP := params.Bech32HRPSegwit,
+					other.Bech32HRPSegwit
+				if test.mweb {
+					hrp, otherHRP = params.Bech32HRPMweb,
+						other.Bech32HRPMweb
+				}
+				if hrp == otherHRP {
+					continue
+				}
+				_, err := uri.Parse(test.uri, other)
+				if err == nil {
+					t.Errorf("%s: Parse(%q) succeeded for %s",
+						params.Name, test.uri, other.Name)
+				}
+			}
+		}
+	}
+}
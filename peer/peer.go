@@ -29,7 +29,7 @@ import (
 
 const (
 	// MaxProtocolVersion is the max protocol version the peer supports.
-	MaxProtocolVersion = wire.AddrV2Version
+	MaxProtocolVersion = wire.MwebLightClientVersion
 
 	// DefaultTrickleInterval is the min time between attempts to send an
 	// inv message to a peer.
@@ -54,6 +54,12 @@ const (
 	// messages.
 	pingInterval = 2 * time.Minute
 
+	// feeFilterInterval is the average interval of time to wait in between
+	// checking whether the local fee filter changed and needs to be
+	// announced to the remote peer again.  The actual interval is
+	// randomized around this value to avoid leaking timing information.
+	feeFilterInterval = 10 * time.Minute
+
 	// negotiateTimeout is the duration of inactivity before we timeout a
 	// peer that hasn't completed the initial version negotiation.
 	negotiateTimeout = 30 * time.Second
@@ -212,6 +218,10 @@ type MessageListeners struct {
 	// OnMwebUtxos is invoked when a peer receives a mwebutxos message.
 	OnMwebUtxos func(p *Peer, msg *wire.MsgMwebUtxos)
 
+	// OnGetMwebUtxos is invoked when a peer receives a getmwebutxos
+	// message.
+	OnGetMwebUtxos func(p *Peer, msg *wire.MsgGetMwebUtxos)
+
 	// OnRead is invoked when a peer receives a litecoin message.  It
 	// consists of the number of bytes read, the message, and whether or not
 	// an error in the read occurred.  Typically, callers will opt to use
@@ -237,6 +247,14 @@ type Config struct {
 	// reported.
 	NewestBlock HashFunc
 
+	// FeeFilter specifies a callback which provides the minimum fee rate,
+	// in satoshi per kilobyte, of the transactions the local peer wants
+	// announced to it.  When set, the value is sent to remote peers that
+	// support BIP0133 via a feefilter message once the handshake completes
+	// and again whenever it changes.  This can be nil in which case no
+	// feefilter messages are sent.
+	FeeFilter FeeFilterFunc
+
 	// HostToNetAddress returns the netaddress for the given host. This can be
 	// nil in  which case the host will be parsed as an IP address.
 	HostToNetAddress HostToNetAddrFunc
@@ -275,10 +293,20 @@ type Config struct {
 	// peer.MaxProtocolVersion will be used.
 	ProtocolVersion uint32
 
+	// MinProtocolVersion specifies the lowest protocol version accepted from
+	// the remote peer.  This field can be omitted in which case, as with any
+	// value below it, peer.MinAcceptableProtocolVersion will be used.
+	MinProtocolVersion uint32
+
 	// DisableRelayTx specifies if the remote peer should be informed to
 	// not send inv messages for transactions.
 	DisableRelayTx bool
 
+	// Permissions specifies the special permissions granted to the remote
+	// peer, such as exemption from banning.  The peer itself does not act
+	// on them; they are exposed via HasPermission for use by the caller.
+	Permissions PermissionFlags
+
 	// Listeners houses callback functions to be invoked on receiving peer
 	// messages.
 	Listeners MessageListeners
@@ -402,8 +430,19 @@ type StatsSnap struct {
 	LastPingNonce  uint64
 	LastPingTime   time.Time
 	LastPingMicros int64
+
+	// BytesSentPerMsg and BytesRecvPerMsg are the total number of bytes
+	// sent and received for each message command.  Messages which could
+	// not be decoded are accounted under OtherMsgCommand.
+	BytesSentPerMsg map[string]uint64
+	BytesRecvPerMsg map[string]uint64
 }
 
+// OtherMsgCommand is the key under which the bytes of messages that could not
+// be decoded, such as messages with an unknown command, are accounted in the
+// per message statistics of a peer.
+const OtherMsgCommand = "*other*"
+
 // HashFunc is a function which returns a block hash, height and error
 // It is used as a callback to get newest block details.
 type HashFunc func() (hash *chainhash.Hash, height int32, err error)
@@ -411,6 +450,10 @@ type HashFunc func() (hash *chainhash.Hash, height int32, err error)
 // AddrFunc is a func which takes an address and returns a related address.
 type AddrFunc func(remoteAddr *wire.NetAddress) *wire.NetAddress
 
+// FeeFilterFunc is a function which returns the minimum fee rate, in satoshi
+// per kilobyte, that should be announced to remote peers via feefilter.
+type FeeFilterFunc func() int64
+
 // HostToNetAddrFunc is a func which takes a host, port, services and returns
 // the netaddress.
 type HostToNetAddrFunc func(host string, port uint16,
@@ -459,6 +502,12 @@ type Peer struct {
 	cfg     Config
 	inbound bool
 
+	// bytesPerMsgMtx protects the per message byte counters, which are
+	// keyed by message command.
+	bytesPerMsgMtx  sync.Mutex
+	bytesSentPerMsg map[string]uint64
+	bytesRecvPerMsg map[string]uint64
+
 	flagsMtx             sync.Mutex // protects the peer flags below
 	na                   *wire.NetAddressV2
 	id                   int32
@@ -494,6 +543,7 @@ type Peer struct {
 	lastPingNonce      uint64    // Set to nonce if we have a pending ping.
 	lastPingTime       time.Time // Time we sent last ping.
 	lastPingMicros     int64     // Time for last ping to return.
+	lastFeeFilter      int64     // Last fee filter sent to the peer.
 
 	stallControl  chan stallControlMsg
 	outputQueue   chan outMsg
@@ -583,6 +633,7 @@ func (p *Peer) StatsSnapshot() *StatsSnap {
 		LastPingMicros: p.lastPingMicros,
 		LastPingTime:   p.lastPingTime,
 	}
+	statsSnap.BytesSentPerMsg, statsSnap.BytesRecvPerMsg = p.BytesPerMsg()
 
 	p.statsMtx.RUnlock()
 	return statsSnap
@@ -637,6 +688,21 @@ func (p *Peer) Services() wire.ServiceFlag {
 	return services
 }
 
+// Permissions returns the special permissions granted to the remote peer.
+//
+// This function is safe for concurrent access.
+func (p *Peer) Permissions() PermissionFlags {
+	return p.cfg.Permissions
+}
+
+// HasPermission returns whether the remote peer has been granted all of the
+// passed permissions.
+//
+// This function is safe for concurrent access.
+func (p *Peer) HasPermission(flags PermissionFlags) bool {
+	return p.cfg.Permissions&flags == flags
+}
+
 // UserAgent returns the user agent of the remote peer.
 //
 // This function is safe for concurrent access.
@@ -777,6 +843,45 @@ func (p *Peer) BytesReceived() uint64 {
 	return atomic.LoadUint64(&p.bytesReceived)
 }
 
+// BytesPerMsg returns copies of the total number of bytes sent to and received
+// from the peer for each message command.  Messages which could not be decoded
+// are accounted under OtherMsgCommand.
+//
+// This function is safe for concurrent access.
+func (p *Peer) BytesPerMsg() (sent, received map[string]uint64) {
+	p.bytesPerMsgMtx.Lock()
+	defer p.bytesPerMsgMtx.Unlock()
+
+	sent = make(map[string]uint64, len(p.bytesSentPerMsg))
+	for command, n := range p.bytesSentPerMsg {
+		sent[command] = n
+	}
+	received = make(map[string]uint64, len(p.bytesRecvPerMsg))
+	for command, n := range p.bytesRecvPerMsg {
+		received[command] = n
+	}
+	return sent, received
+}
+
+// addBytesPerMsg adds the passed number of bytes to the counter of the command
+// of the passed message in the passed map, or to OtherMsgCommand when there is
+// no message.
+//
+// This function is safe for concurrent access.
+func (p *Peer) addBytesPerMsg(counters map[string]uint64, msg wire.Message, n int) {
+	if n == 0 {
+		return
+	}
+	command := OtherMsgCommand
+	if msg != nil {
+		command = msg.Command()
+	}
+
+	p.bytesPerMsgMtx.Lock()
+	counters[command] += uint64(n)
+	p.bytesPerMsgMtx.Unlock()
+}
+
 // TimeConnected returns the time at which the peer connected.
 //
 // This function is safe for concurrent access.
@@ -849,6 +954,37 @@ func (p *Peer) IsMwebEnabled() bool {
 	return mwebEnabled
 }
 
+// IsMwebLightClientEnabled returns true if the peer has signalled that it
+// serves MWEB light client data and the negotiated protocol version includes
+// the messages to exchange MWEB headers, leafsets and UTXOs with it.
+//
+// This function is safe for concurrent access.
+func (p *Peer) IsMwebLightClientEnabled() bool {
+	return p.Services()&wire.SFNodeMWEBLightClient ==
+		wire.SFNodeMWEBLightClient &&
+		p.ProtocolVersion() >= wire.MwebLightClientVersion
+}
+
+// isMwebLightClientRequest returns whether the passed message requests MWEB
+// light client data, which only peers advertising wire.SFNodeMWEBLightClient
+// serve.
+func isMwebLightClientRequest(msg wire.Message) bool {
+	switch msg := msg.(type) {
+	case *wire.MsgGetMwebUtxos:
+		return true
+
+	case *wire.MsgGetData:
+		for _, iv := range msg.InvList {
+			if iv.Type == wire.InvTypeMwebHeader ||
+				iv.Type == wire.InvTypeMwebLeafset {
+
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // WantsAddrV2 returns if the peer supports addrv2 messages instead of the
 // legacy addr messages.
 func (p *Peer) WantsAddrV2() bool {
@@ -1052,6 +1188,28 @@ func (p *Peer) PushRejectMsg(command string, code wire.RejectCode, reason string
 	<-doneChan
 }
 
+// PushFeeFilterMsg sends a feefilter message with the provided minimum fee
+// rate, in satoshi per kilobyte, to the peer.  The message is not sent when the
+// negotiated protocol version does not support BIP0133 or when the same fee
+// rate was already announced to the peer.
+//
+// This function is safe for concurrent access.
+func (p *Peer) PushFeeFilterMsg(minFee int64) {
+	if p.ProtocolVersion() < wire.FeeFilterVersion {
+		return
+	}
+
+	p.statsMtx.Lock()
+	if p.lastFeeFilter == minFee {
+		p.statsMtx.Unlock()
+		return
+	}
+	p.lastFeeFilter = minFee
+	p.statsMtx.Unlock()
+
+	p.QueueMessage(wire.NewMsgFeeFilter(minFee), nil)
+}
+
 // handlePingMsg is invoked when a peer receives a ping litecoin message.  For
 // recent clients (protocol version > BIP0031Version), it replies with a pong
 // message.  For older clients, it does nothing and anything other than failure
@@ -1092,6 +1250,7 @@ func (p *Peer) readMessage(encoding wire.MessageEncoding) (wire.Message, []byte,
 	n, msg, buf, err := wire.ReadMessageWithEncodingN(p.conn,
 		p.ProtocolVersion(), p.cfg.ChainParams.Net, encoding)
 	atomic.AddUint64(&p.bytesReceived, uint64(n))
+	p.addBytesPerMsg(p.bytesRecvPerMsg, msg, n)
 	if p.cfg.Listeners.OnRead != nil {
 		p.cfg.Listeners.OnRead(p, n, msg, err)
 	}
@@ -1155,6 +1314,7 @@ func (p *Peer) writeMessage(msg wire.Message, enc wire.MessageEncoding) error {
 	n, err := wire.WriteMessageWithEncodingN(p.conn, msg,
 		p.ProtocolVersion(), p.cfg.ChainParams.Net, enc)
 	atomic.AddUint64(&p.bytesSent, uint64(n))
+	p.addBytesPerMsg(p.bytesSentPerMsg, msg, n)
 	if p.cfg.Listeners.OnWrite != nil {
 		p.cfg.Listeners.OnWrite(p, n, msg, err)
 	}
@@ -1473,6 +1633,7 @@ out:
 
 		// Handle each supported message type.
 		p.stallControl <- stallControlMsg{sccHandlerStart, rmsg}
+		traceRegion := startMessageTraceRegion(rmsg)
 		switch msg := rmsg.(type) {
 		case *wire.MsgVersion:
 			// Limit to one version message per peer.
@@ -1635,24 +1796,48 @@ out:
 			}
 
 		case *wire.MsgMwebHeader:
+			if !p.IsMwebLightClientEnabled() {
+				log.Debugf("Ignoring %v from %v which does not "+
+					"serve MWEB light client data",
+					msg.Command(), p)
+				break
+			}
 			if p.cfg.Listeners.OnMwebHeader != nil {
 				p.cfg.Listeners.OnMwebHeader(p, msg)
 			}
 
 		case *wire.MsgMwebLeafset:
+			if !p.IsMwebLightClientEnabled() {
+				log.Debugf("Ignoring %v from %v which does not "+
+					"serve MWEB light client data",
+					msg.Command(), p)
+				break
+			}
 			if p.cfg.Listeners.OnMwebLeafset != nil {
 				p.cfg.Listeners.OnMwebLeafset(p, msg)
 			}
 
 		case *wire.MsgMwebUtxos:
+			if !p.IsMwebLightClientEnabled() {
+				log.Debugf("Ignoring %v from %v which does not "+
+					"serve MWEB light client data",
+					msg.Command(), p)
+				break
+			}
 			if p.cfg.Listeners.OnMwebUtxos != nil {
 				p.cfg.Listeners.OnMwebUtxos(p, msg)
 			}
 
+		case *wire.MsgGetMwebUtxos:
+			if p.cfg.Listeners.OnGetMwebUtxos != nil {
+				p.cfg.Listeners.OnGetMwebUtxos(p, msg)
+			}
+
 		default:
 			log.Debugf("Received unhandled message of type %v "+
 				"from %v", rmsg.Command(), p)
 		}
+		traceRegion.End()
 		p.stallControl <- stallControlMsg{sccHandlerDone, rmsg}
 
 		// A message was received so reset the idle timer.
@@ -1924,6 +2109,34 @@ out:
 	}
 }
 
+// feeFilterHandler announces the local fee filter to the peer once the
+// protocol has been negotiated and periodically re-announces it whenever it
+// changes.  It must be run as a goroutine.
+func (p *Peer) feeFilterHandler() {
+	p.PushFeeFilterMsg(p.cfg.FeeFilter())
+
+	// Randomize the interval between checks so the announcements can't be
+	// used to correlate the peer with other connections.
+	nextInterval := func() time.Duration {
+		return feeFilterInterval/2 +
+			time.Duration(rand.Int63n(int64(feeFilterInterval)))
+	}
+	timer := time.NewTimer(nextInterval())
+	defer timer.Stop()
+
+out:
+	for {
+		select {
+		case <-timer.C:
+			p.PushFeeFilterMsg(p.cfg.FeeFilter())
+			timer.Reset(nextInterval())
+
+		case <-p.quit:
+			break out
+		}
+	}
+}
+
 // QueueMessage adds the passed litecoin message to the peer send queue.
 //
 // This function is safe for concurrent access.
@@ -1943,7 +2156,18 @@ func (p *Peer) QueueMessageWithEncoding(msg wire.Message, doneChan chan<- struct
 	// Avoid risk of deadlock if goroutine already exited.  The goroutine
 	// we will be sending to hangs around until it knows for a fact that
 	// it is marked as disconnected and *then* it drains the channels.
-	if !p.Connected() {
+	//
+	// MWEB light client requests are dropped as well when the peer does not
+	// serve MWEB light client data, since it would not answer them.
+	dropMsg := !p.Connected()
+	if !dropMsg && isMwebLightClientRequest(msg) &&
+		!p.IsMwebLightClientEnabled() {
+
+		log.Debugf("Not sending %v to %v which does not serve MWEB "+
+			"light client data", msg.Command(), p)
+		dropMsg = true
+	}
+	if dropMsg {
 		if doneChan != nil {
 			go func() {
 				doneChan <- struct{}{}
@@ -2087,12 +2311,12 @@ func (p *Peer) readRemoteVersionMsg() error {
 	// NOTE: If minAcceptableProtocolVersion is raised to be higher than
 	// wire.RejectVersion, this should send a reject packet before
 	// disconnecting.
-	if uint32(msg.ProtocolVersion) < MinAcceptableProtocolVersion {
+	if uint32(msg.ProtocolVersion) < p.cfg.MinProtocolVersion {
 		// Send a reject message indicating the protocol version is
 		// obsolete and wait for the message to be sent before
 		// disconnecting.
 		reason := fmt.Sprintf("protocol version must be %d or greater",
-			MinAcceptableProtocolVersion)
+			p.cfg.MinProtocolVersion)
 		rejectMsg := wire.NewMsgReject(msg.Command(), wire.RejectObsolete,
 			reason)
 		_ = p.writeMessage(rejectMsg, wire.LatestEncoding)
@@ -2357,6 +2581,14 @@ func (p *Peer) start() error {
 	go p.outHandler()
 	go p.pingHandler()
 
+	// Announce our fee filter to peers that understand it unless we've
+	// asked them not to relay transactions at all.
+	if p.cfg.FeeFilter != nil && !p.cfg.DisableRelayTx &&
+		p.ProtocolVersion() >= wire.FeeFilterVersion {
+
+		go p.feeFilterHandler()
+	}
+
 	return nil
 }
 
@@ -2417,6 +2649,9 @@ func newPeerBase(origCfg *Config, inbound bool) *Peer {
 	if cfg.ProtocolVersion == 0 {
 		cfg.ProtocolVersion = MaxProtocolVersion
 	}
+	if cfg.MinProtocolVersion < MinAcceptableProtocolVersion {
+		cfg.MinProtocolVersion = MinAcceptableProtocolVersion
+	}
 
 	// Set the chain parameters to testnet if the caller did not specify any.
 	if cfg.ChainParams == nil {
@@ -2431,6 +2666,8 @@ func newPeerBase(origCfg *Config, inbound bool) *Peer {
 	p := Peer{
 		inbound:         inbound,
 		wireEncoding:    wire.BaseEncoding,
+		bytesSentPerMsg: make(map[string]uint64),
+		bytesRecvPerMsg: make(map[string]uint64),
 		knownInventory:  lru.NewCache(maxKnownInventory),
 		stallControl:    make(chan stallControlMsg, 1), // nonblocking sync
 		outputQueue:     make(chan outMsg, outputBufferSize),
@@ -16,6 +16,7 @@ import (
 	"github.com/btcsuite/go-socks/socks"
 	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil/mweb"
 	"github.com/ltcsuite/ltcd/peer"
 	"github.com/ltcsuite/ltcd/wire"
 )
@@ -177,6 +178,29 @@ func testPeer(t *testing.T, p *peer.Peer, s peerStats) {
 		return
 	}
 
+	// The bytes accounted per message must add up to the totals and include
+	// the handshake.
+	sentPerMsg, recvPerMsg := p.BytesPerMsg()
+	var sentTotal, recvTotal uint64
+	for _, n := range sentPerMsg {
+		sentTotal += n
+	}
+	for _, n := range recvPerMsg {
+		recvTotal += n
+	}
+	if sentTotal != s.wantBytesSent || recvTotal != s.wantBytesReceived {
+		t.Errorf("testPeer: wrong bytes per message - got sent %v and "+
+			"received %v, want totals %v and %v", sentPerMsg,
+			recvPerMsg, s.wantBytesSent, s.wantBytesReceived)
+		return
+	}
+	if sentPerMsg[wire.CmdVerAck] != 24 || recvPerMsg[wire.CmdVerAck] != 24 {
+		t.Errorf("testPeer: wrong verack bytes - got sent %v and "+
+			"received %v, want 24", sentPerMsg[wire.CmdVerAck],
+			recvPerMsg[wire.CmdVerAck])
+		return
+	}
+
 	if p.StartingHeight() != s.wantStartingHeight {
 		t.Errorf("testPeer: wrong StartingHeight - got %v, want %v", p.StartingHeight(), s.wantStartingHeight)
 		return
@@ -623,6 +647,262 @@ func TestPeerListeners(t *testing.T) {
 	outPeer.Disconnect()
 }
 
+// TestMwebLightClientGating ensures MWEB light client data is only accepted
+// from and requested of peers advertising that they serve it.
+func TestMwebLightClientGating(t *testing.T) {
+	for _, serves := range []bool{false, true} {
+		leafsets := make(chan *wire.MsgMwebLeafset, 1)
+		written := make(chan wire.Message, 10)
+		verack := make(chan struct{}, 2)
+		inCfg := &peer.Config{
+			Listeners: peer.MessageListeners{
+				OnVerAck: func(p *peer.Peer, msg *wire.MsgVerAck) {
+					verack <- struct{}{}
+				},
+				OnMwebLeafset: func(p *peer.Peer, msg *wire.MsgMwebLeafset) {
+					leafsets <- msg
+				},
+			},
+			ChainParams:     &chaincfg.MainNetParams,
+			ProtocolVersion: wire.MwebLightClientVersion,
+			TrickleInterval: time.Second * 10,
+			AllowSelfConns:  true,
+		}
+		outCfg := *inCfg
+		outCfg.Listeners = peer.MessageListeners{
+			OnVerAck: func(p *peer.Peer, msg *wire.MsgVerAck) {
+				verack <- struct{}{}
+			},
+			OnWrite: func(p *peer.Peer, n int, msg wire.Message, err error) {
+				written <- msg
+			},
+		}
+		if serves {
+			outCfg.Services = wire.SFNodeMWEBLightClient
+		}
+		inPeer := peer.NewInboundPeer(inCfg)
+		outPeer, err := peer.NewOutboundPeer(&outCfg, "10.0.0.1:9333")
+		if err != nil {
+			t.Fatalf("NewOutboundPeer: unexpected err %v", err)
+		}
+		if err := setupPeerConnection(inPeer, outPeer); err != nil {
+			t.Fatalf("setupPeerConnection: failed: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			select {
+			case <-verack:
+			case <-time.After(time.Second):
+				t.Fatalf("verack timeout")
+			}
+		}
+		if inPeer.IsMwebLightClientEnabled() != serves {
+			t.Fatalf("serves %v: IsMwebLightClientEnabled is %v",
+				serves, !serves)
+		}
+
+		// The leafset is only accepted from a peer serving MWEB light
+		// client data.
+		outPeer.QueueMessage(wire.NewMsgMwebLeafset(&chainhash.Hash{},
+			[]byte{0x01}), nil)
+		select {
+		case <-leafsets:
+			if !serves {
+				t.Fatalf("leafset accepted from a peer which does " +
+					"not serve MWEB light client data")
+			}
+		case <-time.After(200 * time.Millisecond):
+			if serves {
+				t.Fatalf("leafset from a serving peer was ignored")
+			}
+		}
+
+		// MWEB light client requests are never sent to the inbound peer
+		// since it does not serve the data.
+		done := make(chan struct{}, 1)
+		outPeer.QueueMessage(wire.NewMsgGetMwebUtxos(chainhash.Hash{}, 0,
+			1, wire.MwebNetUtxoFull), done)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("request was not completed")
+		}
+	drain:
+		for {
+			select {
+			case msg := <-written:
+				if _, ok := msg.(*wire.MsgGetMwebUtxos); ok {
+					t.Fatalf("request sent to a peer which does " +
+						"not serve MWEB light client data")
+				}
+			default:
+				break drain
+			}
+		}
+
+		inPeer.Disconnect()
+		outPeer.Disconnect()
+	}
+}
+
+// TestMwebLightClientExchange ensures the MWEB leafset and unspent outputs of
+// a block are served over a connection which negotiated the MWEB light client
+// protocol version, and that they verify against the MWEB header of the block.
+func TestMwebLightClientExchange(t *testing.T) {
+	// Build the output MMR of the block with every other output spent.
+	const numOutputs = 10
+	mmr := &mweb.Mmr{}
+	ids := make([]chainhash.Hash, numOutputs)
+	leafset := &mweb.Leafset{Bits: make([]byte, (numOutputs+7)/8),
+		Size: numOutputs}
+	for i := range ids {
+		ids[i] = chainhash.DoubleHashH([]byte{byte(i)})
+		mmr.Add(&ids[i])
+		if i%2 == 0 {
+			leafset.Bits[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	header := &wire.MwebHeader{OutputRoot: *mmr.Root()}
+	blockHash := chainhash.Hash{0x01}
+
+	for _, version := range []uint32{wire.AddrV2Version, 0} {
+		verack := make(chan struct{}, 2)
+		leafsets := make(chan *wire.MsgMwebLeafset, 1)
+		utxos := make(chan *wire.MsgMwebUtxos, 1)
+
+		// The inbound peer serves the MWEB state of the block.
+		inCfg := &peer.Config{
+			Listeners: peer.MessageListeners{
+				OnVerAck: func(p *peer.Peer, msg *wire.MsgVerAck) {
+					verack <- struct{}{}
+				},
+				OnGetData: func(p *peer.Peer, msg *wire.MsgGetData) {
+					p.QueueMessage(wire.NewMsgMwebLeafset(
+						&blockHash, leafset.Bits), nil)
+				},
+				OnGetMwebUtxos: func(p *peer.Peer,
+					msg *wire.MsgGetMwebUtxos) {
+
+					reply := wire.NewMsgMwebUtxos(msg.BlockHash,
+						msg.StartIndex, msg.OutputFormat)
+					for i := msg.StartIndex; i < numOutputs; i++ {
+						if leafset.Contains(i) {
+							reply.Utxos = append(reply.Utxos,
+								&wire.MwebNetUtxo{
+									LeafIndex: i,
+									OutputId:  &ids[i],
+								})
+						}
+					}
+					if err := mmr.ProveUtxos(leafset, reply); err != nil {
+						t.Errorf("ProveUtxos: %v", err)
+						return
+					}
+					p.QueueMessage(reply, nil)
+				},
+			},
+			ChainParams:     &chaincfg.MainNetParams,
+			ProtocolVersion: version,
+			Services:        wire.SFNodeMWEBLightClient,
+			TrickleInterval: time.Second * 10,
+			AllowSelfConns:  true,
+		}
+		outCfg := &peer.Config{
+			Listeners: peer.MessageListeners{
+				OnVerAck: func(p *peer.Peer, msg *wire.MsgVerAck) {
+					verack <- struct{}{}
+				},
+				OnMwebLeafset: func(p *peer.Peer, msg *wire.MsgMwebLeafset) {
+					leafsets <- msg
+				},
+				OnMwebUtxos: func(p *peer.Peer, msg *wire.MsgMwebUtxos) {
+					utxos <- msg
+				},
+			},
+			ChainParams:     &chaincfg.MainNetParams,
+			TrickleInterval: time.Second * 10,
+			AllowSelfConns:  true,
+		}
+		inPeer := peer.NewInboundPeer(inCfg)
+		outPeer, err := peer.NewOutboundPeer(outCfg, "10.0.0.1:9333")
+		if err != nil {
+			t.Fatalf("NewOutboundPeer: unexpected err %v", err)
+		}
+		if err := setupPeerConnection(inPeer, outPeer); err != nil {
+			t.Fatalf("setupPeerConnection: failed: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			select {
+			case <-verack:
+			case <-time.After(time.Second):
+				t.Fatalf("verack timeout")
+			}
+		}
+
+		// The MWEB light client messages are only exchanged once the
+		// negotiated protocol version includes them.
+		negotiated := outPeer.ProtocolVersion()
+		if version == 0 && negotiated != wire.MwebLightClientVersion {
+			t.Fatalf("negotiated protocol version %d, want %d",
+				negotiated, wire.MwebLightClientVersion)
+		}
+		serves := negotiated >= wire.MwebLightClientVersion
+		if outPeer.IsMwebLightClientEnabled() != serves {
+			t.Fatalf("version %d: IsMwebLightClientEnabled is %v",
+				negotiated, !serves)
+		}
+		if !serves {
+			done := make(chan struct{}, 1)
+			outPeer.QueueMessage(wire.NewMsgGetMwebUtxos(blockHash, 0,
+				wire.MaxMwebUtxosPerQuery, wire.MwebNetUtxoHashOnly),
+				done)
+			<-done
+			select {
+			case <-utxos:
+				t.Fatalf("version %d: outputs were served",
+					negotiated)
+			case <-time.After(200 * time.Millisecond):
+			}
+			inPeer.Disconnect()
+			outPeer.Disconnect()
+			continue
+		}
+
+		gdmsg := wire.NewMsgGetData()
+		gdmsg.AddInvVect(wire.NewInvVect(wire.InvTypeMwebLeafset,
+			&blockHash))
+		outPeer.QueueMessage(gdmsg, nil)
+		select {
+		case msg := <-leafsets:
+			if msg.BlockHash != blockHash ||
+				string(msg.Leafset) != string(leafset.Bits) {
+
+				t.Fatalf("received leafset %x of block %v",
+					msg.Leafset, msg.BlockHash)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("leafset timeout")
+		}
+
+		outPeer.QueueMessage(wire.NewMsgGetMwebUtxos(blockHash, 2,
+			wire.MaxMwebUtxosPerQuery, wire.MwebNetUtxoHashOnly), nil)
+		select {
+		case msg := <-utxos:
+			if msg.StartIndex != 2 || len(msg.Utxos) != 4 {
+				t.Fatalf("received %d outputs starting at %d",
+					len(msg.Utxos), msg.StartIndex)
+			}
+			if !mweb.VerifyUtxos(header, leafset, msg) {
+				t.Fatalf("received outputs do not verify")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("outputs timeout")
+		}
+
+		inPeer.Disconnect()
+		outPeer.Disconnect()
+	}
+}
+
 // TestOutboundPeer tests that the outbound peer works as expected.
 func TestOutboundPeer(t *testing.T) {
 
@@ -873,6 +1153,106 @@ func TestUnsupportedVersionPeer(t *testing.T) {
 	}
 }
 
+// TestMinProtocolVersionPeer ensures the peer rejects and disconnects from
+// peers whose protocol version is below the configured minimum even though it
+// is supported by the package.
+func TestMinProtocolVersionPeer(t *testing.T) {
+	peerCfg := &peer.Config{
+		UserAgentName:      "peer",
+		UserAgentVersion:   "1.0",
+		ChainParams:        &chaincfg.MainNetParams,
+		MinProtocolVersion: wire.AddrV2Version,
+		TrickleInterval:    time.Second * 10,
+		AllowSelfConns:     true,
+	}
+
+	localNA := wire.NewNetAddressIPPort(
+		net.ParseIP("10.0.0.1"),
+		uint16(9333),
+		wire.SFNodeNetwork,
+	)
+	remoteNA := wire.NewNetAddressIPPort(
+		net.ParseIP("10.0.0.2"),
+		uint16(9333),
+		wire.SFNodeNetwork,
+	)
+	localConn, remoteConn := pipe(
+		&conn{laddr: "10.0.0.1:9333", raddr: "10.0.0.2:9333"},
+		&conn{laddr: "10.0.0.2:9333", raddr: "10.0.0.1:9333"},
+	)
+
+	p, err := peer.NewOutboundPeer(peerCfg, "10.0.0.1:9333")
+	if err != nil {
+		t.Fatalf("NewOutboundPeer: unexpected err - %v\n", err)
+	}
+	p.AssociateConnection(localConn)
+
+	// Read outbound messages to peer into a channel
+	outboundMessages := make(chan wire.Message)
+	go func() {
+		for {
+			_, msg, _, err := wire.ReadMessageN(
+				remoteConn,
+				p.ProtocolVersion(),
+				peerCfg.ChainParams.Net,
+			)
+			if err != nil {
+				close(outboundMessages)
+				return
+			}
+
+			outboundMessages <- msg
+		}
+	}()
+
+	// Read version message sent to remote peer
+	select {
+	case msg := <-outboundMessages:
+		if _, ok := msg.(*wire.MsgVersion); !ok {
+			t.Fatalf("Expected version message, got [%s]", msg.Command())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Peer did not send version message")
+	}
+
+	// Remote peer writes version message advertising a supported protocol
+	// version below the configured minimum.
+	oldVersionMsg := wire.NewMsgVersion(remoteNA, localNA, 0, 0)
+	oldVersionMsg.ProtocolVersion = int32(wire.FeeFilterVersion)
+	_, err = wire.WriteMessageN(
+		remoteConn.Writer,
+		oldVersionMsg,
+		uint32(oldVersionMsg.ProtocolVersion),
+		peerCfg.ChainParams.Net,
+	)
+	if err != nil {
+		t.Fatalf("wire.WriteMessageN: unexpected err - %v\n", err)
+	}
+
+	// Expect the version to be rejected as obsolete.
+	select {
+	case msg := <-outboundMessages:
+		reject, ok := msg.(*wire.MsgReject)
+		if !ok || reject.Code != wire.RejectObsolete {
+			t.Fatalf("Expected obsolete reject message, got %v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Peer did not send reject message")
+	}
+
+	// Expect peer to disconnect automatically
+	disconnected := make(chan struct{})
+	go func() {
+		p.WaitForDisconnect()
+		close(disconnected)
+	}()
+	select {
+	case <-disconnected:
+	case <-time.After(time.Second):
+		t.Fatal("Peer did not automatically disconnect")
+	}
+}
+
 // TestDuplicateVersionMsg ensures that receiving a version message after one
 // has already been received results in the peer being disconnected.
 func TestDuplicateVersionMsg(t *testing.T) {
@@ -1209,3 +1589,128 @@ func TestSendAddrV2Handshake(t *testing.T) {
 		outPeer.WaitForDisconnect()
 	}
 }
+
+// TestFeeFilterAnnouncement ensures a peer configured with a fee filter
+// callback announces it to the remote peer once the handshake completes.
+func TestFeeFilterAnnouncement(t *testing.T) {
+	const wantFee = 10000
+
+	feeFilters := make(chan int64, 2)
+	inCfg := &peer.Config{
+		FeeFilter:      func() int64 { return wantFee },
+		AllowSelfConns: true,
+		ChainParams:    &chaincfg.MainNetParams,
+	}
+	outCfg := &peer.Config{
+		Listeners: peer.MessageListeners{
+			OnFeeFilter: func(p *peer.Peer, msg *wire.MsgFeeFilter) {
+				feeFilters <- msg.MinFee
+			},
+		},
+		AllowSelfConns: true,
+		ChainParams:    &chaincfg.MainNetParams,
+	}
+
+	inPeer := peer.NewInboundPeer(inCfg)
+	outPeer, err := peer.NewOutboundPeer(outCfg, "10.0.0.2:9333")
+	if err != nil {
+		t.Fatalf("NewOutboundPeer: unexpected err: %v", err)
+	}
+	if err := setupPeerConnection(inPeer, outPeer); err != nil {
+		t.Fatalf("setupPeerConnection: unexpected err: %v", err)
+	}
+
+	select {
+	case fee := <-feeFilters:
+		if fee != wantFee {
+			t.Fatalf("unexpected fee filter - got %d, want %d", fee,
+				wantFee)
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatal("timeout waiting for feefilter message")
+	}
+
+	// Announcing the same fee rate again must not result in a duplicate
+	// message.
+	inPeer.PushFeeFilterMsg(wantFee)
+	select {
+	case fee := <-feeFilters:
+		t.Fatalf("unexpected duplicate fee filter %d", fee)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	inPeer.Disconnect()
+	outPeer.Disconnect()
+	inPeer.WaitForDisconnect()
+	outPeer.WaitForDisconnect()
+}
+
+// TestPermissionFlags ensures permission flags are parsed from and formatted
+// to their string representations properly.
+func TestPermissionFlags(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  peer.PermissionFlags
+		str   string
+		isErr bool
+	}{
+		{in: "noban", want: peer.PermissionNoBan, str: "noban"},
+		{
+			in:   "mempool, NoBan",
+			want: peer.PermissionNoBan | peer.PermissionMempool,
+			str:  "noban,mempool",
+		},
+		{
+			in: "forcerelay,bloomfilter",
+			want: peer.PermissionForceRelay |
+				peer.PermissionBloomFilter,
+			str: "forcerelay,bloomfilter",
+		},
+		{
+			in:   "all",
+			want: peer.PermissionAll,
+			str:  "noban,forcerelay,mempool,bloomfilter",
+		},
+		{in: "relay", isErr: true},
+		{in: "noban,", isErr: true},
+	}
+
+	for i, test := range tests {
+		flags, err := peer.ParsePermissionFlags(test.in)
+		if test.isErr {
+			if err == nil {
+				t.Errorf("ParsePermissionFlags #%d (%q): expected "+
+					"error", i, test.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePermissionFlags #%d (%q): unexpected "+
+				"error: %v", i, test.in, err)
+			continue
+		}
+		if flags != test.want {
+			t.Errorf("ParsePermissionFlags #%d (%q): got %v, want %v",
+				i, test.in, flags, test.want)
+			continue
+		}
+		if flags.String() != test.str {
+			t.Errorf("String #%d: got %q, want %q", i,
+				flags.String(), test.str)
+		}
+	}
+
+	// Ensure the accessors reflect the configured permissions.
+	p := peer.NewInboundPeer(&peer.Config{
+		Permissions: peer.PermissionNoBan | peer.PermissionMempool,
+	})
+	if !p.HasPermission(peer.PermissionNoBan) {
+		t.Error("HasPermission: missing noban permission")
+	}
+	if p.HasPermission(peer.PermissionNoBan | peer.PermissionForceRelay) {
+		t.Error("HasPermission: unexpected forcerelay permission")
+	}
+	if p.Permissions() != peer.PermissionNoBan|peer.PermissionMempool {
+		t.Errorf("Permissions: got %v", p.Permissions())
+	}
+}
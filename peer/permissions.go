@@ -0,0 +1,113 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PermissionFlags identifies the special permissions granted to a peer,
+// typically because it connected from a whitelisted network.
+type PermissionFlags uint32
+
+const (
+	// PermissionNoBan indicates the peer can not be banned or disconnected
+	// for misbehavior.
+	PermissionNoBan PermissionFlags = 1 << iota
+
+	// PermissionForceRelay indicates transactions from the peer are
+	// accepted and relayed even when they are non-standard or pay too
+	// low a fee, and even when the local node is in blocks only mode.
+	PermissionForceRelay
+
+	// PermissionMempool indicates the peer may request the contents of
+	// the memory pool via the mempool message.
+	PermissionMempool
+
+	// PermissionBloomFilter indicates the peer may use BIP0037 bloom
+	// filters even when the local node does not advertise bloom filter
+	// support.
+	PermissionBloomFilter
+
+	// PermissionNone indicates the peer has no special permissions.
+	PermissionNone PermissionFlags = 0
+
+	// PermissionAll indicates the peer has every permission.
+	PermissionAll = PermissionNoBan | PermissionForceRelay |
+		PermissionMempool | PermissionBloomFilter
+)
+
+// Map of permission flags back to their names for pretty printing and
+// parsing.
+var permissionFlagStrings = map[PermissionFlags]string{
+	PermissionNoBan:       "noban",
+	PermissionForceRelay:  "forcerelay",
+	PermissionMempool:     "mempool",
+	PermissionBloomFilter: "bloomfilter",
+}
+
+// orderedPermissionFlags is an ordered list of permission flags from lowest
+// value to highest.
+var orderedPermissionFlags = []PermissionFlags{
+	PermissionNoBan,
+	PermissionForceRelay,
+	PermissionMempool,
+	PermissionBloomFilter,
+}
+
+// String returns the PermissionFlags in human-readable form.
+func (f PermissionFlags) String() string {
+	// No flags are set.
+	if f == PermissionNone {
+		return "none"
+	}
+
+	// Add individual bit flags.
+	s := ""
+	for _, flag := range orderedPermissionFlags {
+		if f&flag == flag {
+			s += permissionFlagStrings[flag] + ","
+			f -= flag
+		}
+	}
+
+	// Add any remaining flags which aren't accounted for as hex.
+	s = strings.TrimRight(s, ",")
+	if f != 0 {
+		s += "|0x" + fmt.Sprintf("%x", uint32(f))
+	}
+	s = strings.TrimLeft(s, "|")
+	return s
+}
+
+// ParsePermissionFlags parses a comma separated list of permission names such
+// as "noban,mempool" into the flags they represent.  The special name "all"
+// grants every permission.  An error is returned for any unknown name.
+func ParsePermissionFlags(s string) (PermissionFlags, error) {
+	var flags PermissionFlags
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "all" {
+			flags |= PermissionAll
+			continue
+		}
+
+		var found bool
+		for flag, flagName := range permissionFlagStrings {
+			if name == flagName {
+				flags |= flag
+				found = true
+				break
+			}
+		}
+		if !found {
+			return PermissionNone, fmt.Errorf("unknown permission "+
+				"%q", name)
+		}
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"context"
+	"runtime/trace"
+
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// traceRegionMessagePrefix is the prefix of the type of the runtime trace
+// regions around the handling of each received message.  The command of the
+// message is appended to it, for example "peer.msg.block".  It is meant to be
+// relied upon by the tools used to analyze execution traces, so it must not be
+// changed.
+const traceRegionMessagePrefix = "peer.msg."
+
+// messageTraceRegion houses a runtime trace region around the handling of a
+// received message.  The zero value is a region which is not being recorded.
+type messageTraceRegion struct {
+	region *trace.Region
+}
+
+// startMessageTraceRegion starts a runtime trace region around the handling of
+// the passed message which must be ended by the caller.  The region is only
+// created while an execution trace is being captured so the message handling
+// does not pay for building the region type otherwise.
+func startMessageTraceRegion(msg wire.Message) messageTraceRegion {
+	if !trace.IsEnabled() {
+		return messageTraceRegion{}
+	}
+	return messageTraceRegion{
+		region: trace.StartRegion(context.Background(),
+			traceRegionMessagePrefix+msg.Command()),
+	}
+}
+
+// End marks the end of the region when it is being recorded.
+func (r messageTraceRegion) End() {
+	if r.region != nil {
+		r.region.End()
+	}
+}
@@ -7,6 +7,7 @@ package main
 import (
 	"sync/atomic"
 
+	"github.com/ltcsuite/ltcd/addrmgr"
 	"github.com/ltcsuite/ltcd/blockchain"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/ltcutil"
@@ -155,6 +156,23 @@ func (cm *rpcConnManager) ConnectedCount() int32 {
 	return cm.server.ConnectedCount()
 }
 
+// MaxPeers returns the maximum number of peers the server accepts.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserverConnManager interface implementation.
+func (cm *rpcConnManager) MaxPeers() int {
+	return cm.server.MaxPeers()
+}
+
+// SetMaxPeers changes the maximum number of peers the server accepts without
+// disconnecting any of the connected peers.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserverConnManager interface implementation.
+func (cm *rpcConnManager) SetMaxPeers(maxPeers int) {
+	cm.server.SetMaxPeers(maxPeers)
+}
+
 // NetTotals returns the sum of all bytes received and sent across the network
 // for all peers.
 //
@@ -218,9 +236,11 @@ func (cm *rpcConnManager) AddRebroadcastInventory(iv *wire.InvVect, data interfa
 }
 
 // RelayTransactions generates and relays inventory vectors for all of the
-// passed transactions to all connected peers.
+// passed transactions, which were submitted to this node.  They are relayed
+// along the Dandelion++ stem when it is enabled and to all connected peers
+// otherwise.
 func (cm *rpcConnManager) RelayTransactions(txns []*mempool.TxDesc) {
-	cm.server.relayTransactions(txns)
+	cm.server.relayLocalTransactions(txns)
 }
 
 // NodeAddresses returns an array consisting node addresses which can
@@ -232,6 +252,24 @@ func (cm *rpcConnManager) NodeAddresses() []*wire.NetAddressV2 {
 	return cm.server.addrManager.AddressCache()
 }
 
+// AddrManagerStats returns statistics about the addresses known to the address
+// manager.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserverConnManager interface implementation.
+func (cm *rpcConnManager) AddrManagerStats() *addrmgr.Stats {
+	return cm.server.addrManager.Stats()
+}
+
+// SampleAddresses returns up to the passed number of addresses known to the
+// address manager picked at random.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserverConnManager interface implementation.
+func (cm *rpcConnManager) SampleAddresses(count int) []*addrmgr.AddressInfo {
+	return cm.server.addrManager.SampleAddresses(count)
+}
+
 // rpcSyncMgr provides a block manager for use with the RPC server and
 // implements the rpcserverSyncManager interface.
 type rpcSyncMgr struct {
@@ -286,3 +324,12 @@ func (b *rpcSyncMgr) SyncPeerID() int32 {
 func (b *rpcSyncMgr) LocateHeaders(locators []*chainhash.Hash, hashStop *chainhash.Hash) []wire.BlockHeader {
 	return b.server.chain.LocateHeaders(locators, hashStop)
 }
+
+// FetchBlock requests the block with the provided hash from the peer with the
+// provided ID, storing it once received even when it is on a side chain.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserverSyncManager interface implementation.
+func (b *rpcSyncMgr) FetchBlock(hash *chainhash.Hash, peerID int32) error {
+	return b.syncMgr.FetchBlock(hash, peerID)
+}
@@ -307,6 +307,120 @@ func (c *Client) GetBlockCount() (int64, error) {
 	return c.GetBlockCountAsync().Receive()
 }
 
+// FutureGetChainTipsResult is a future promise to deliver the result of a
+// GetChainTipsAsync RPC invocation (or an applicable error).
+type FutureGetChainTipsResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the tips
+// of all known branches of the block tree.
+func (r FutureGetChainTipsResult) Receive() ([]btcjson.GetChainTipsResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as an array of chain tips.
+	var tips []btcjson.GetChainTipsResult
+	err = json.Unmarshal(res, &tips)
+	if err != nil {
+		return nil, err
+	}
+	return tips, nil
+}
+
+// GetChainTipsAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetChainTips for the blocking version and more details.
+func (c *Client) GetChainTipsAsync() FutureGetChainTipsResult {
+	cmd := btcjson.NewGetChainTipsCmd(nil)
+	return c.SendCmd(cmd)
+}
+
+// GetChainTips returns the tips of all known branches of the block tree,
+// including the main chain and any side chains.
+func (c *Client) GetChainTips() ([]btcjson.GetChainTipsResult, error) {
+	return c.GetChainTipsAsync().Receive()
+}
+
+// FutureGetChainTipsVerboseResult is a future promise to deliver the result of
+// a GetChainTipsVerboseAsync RPC invocation (or an applicable error).
+type FutureGetChainTipsVerboseResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the tips
+// of all known branches of the block tree along with statistics about the
+// removal of stale side chains.
+func (r FutureGetChainTipsVerboseResult) Receive() (*btcjson.GetChainTipsVerboseResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a verbose getchaintips result object.
+	var tips btcjson.GetChainTipsVerboseResult
+	err = json.Unmarshal(res, &tips)
+	if err != nil {
+		return nil, err
+	}
+	return &tips, nil
+}
+
+// GetChainTipsVerboseAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetChainTipsVerbose for the blocking version and more details.
+func (c *Client) GetChainTipsVerboseAsync() FutureGetChainTipsVerboseResult {
+	cmd := btcjson.NewGetChainTipsCmd(btcjson.Bool(true))
+	return c.SendCmd(cmd)
+}
+
+// GetChainTipsVerbose returns the tips of all known branches of the block tree
+// along with how many stale side chain blocks have been removed from the block
+// index.
+func (c *Client) GetChainTipsVerbose() (*btcjson.GetChainTipsVerboseResult, error) {
+	return c.GetChainTipsVerboseAsync().Receive()
+}
+
+// FutureGetIndexInfoResult is a future promise to deliver the result of a
+// GetIndexInfoAsync RPC invocation (or an applicable error).
+type FutureGetIndexInfoResult chan *Response
+
+// Receive waits for the Response promised by the future and returns how far
+// each of the enabled optional indexes is synced, keyed by index name.
+func (r FutureGetIndexInfoResult) Receive() (map[string]btcjson.GetIndexInfoResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a map of index states.
+	var info map[string]btcjson.GetIndexInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetIndexInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetIndexInfo for the blocking version and more details.
+func (c *Client) GetIndexInfoAsync(indexName *string) FutureGetIndexInfoResult {
+	cmd := btcjson.NewGetIndexInfoCmd(indexName)
+	return c.SendCmd(cmd)
+}
+
+// GetIndexInfo returns how far each of the enabled optional indexes of the
+// server is synced with the best chain, keyed by index name.  Only the index
+// with the passed name is returned when it is not nil.
+func (c *Client) GetIndexInfo(indexName *string) (map[string]btcjson.GetIndexInfoResult, error) {
+	return c.GetIndexInfoAsync(indexName).Receive()
+}
+
 // FutureGetChainTxStatsResult is a future promise to deliver the result of a
 // GetChainTxStatsAsync RPC invocation (or an applicable error).
 type FutureGetChainTxStatsResult chan *Response
@@ -417,6 +531,45 @@ func (c *Client) GetDifficulty() (float64, error) {
 	return c.GetDifficultyAsync().Receive()
 }
 
+// FutureGetDifficultyVerboseResult is a future promise to deliver the result
+// of a GetDifficultyVerboseAsync RPC invocation (or an applicable error).
+type FutureGetDifficultyVerboseResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the
+// proof-of-work difficulty along with the difficulty algorithm of the best
+// block.
+func (r FutureGetDifficultyVerboseResult) Receive() (*btcjson.GetDifficultyResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a getdifficulty result object.
+	var difficulty btcjson.GetDifficultyResult
+	err = json.Unmarshal(res, &difficulty)
+	if err != nil {
+		return nil, err
+	}
+	return &difficulty, nil
+}
+
+// GetDifficultyVerboseAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetDifficultyVerbose for the blocking version and more details.
+func (c *Client) GetDifficultyVerboseAsync() FutureGetDifficultyVerboseResult {
+	cmd := btcjson.NewGetDifficultyVerboseCmd()
+	return c.SendCmd(cmd)
+}
+
+// GetDifficultyVerbose returns the proof-of-work difficulty as a multiple of
+// the minimum difficulty along with the difficulty algorithm which determined
+// the difficulty of the best block.
+func (c *Client) GetDifficultyVerbose() (*btcjson.GetDifficultyResult, error) {
+	return c.GetDifficultyVerboseAsync().Receive()
+}
+
 // FutureGetBlockChainInfoResult is a promise to deliver the result of a
 // GetBlockChainInfoAsync RPC invocation (or an applicable error).
 type FutureGetBlockChainInfoResult struct {
@@ -546,6 +699,39 @@ func (c *Client) GetBlockFilter(blockHash chainhash.Hash, filterType *btcjson.Fi
 	return c.GetBlockFilterAsync(blockHash, filterType).Receive()
 }
 
+// FutureGetBlockFromPeerResult is a future promise to deliver the result of a
+// GetBlockFromPeerAsync RPC invocation (or an applicable error).
+type FutureGetBlockFromPeerResult chan *Response
+
+// Receive waits for the Response promised by the future and returns an error if
+// the block could not be requested from the peer.
+func (r FutureGetBlockFromPeerResult) Receive() error {
+	_, err := ReceiveFuture(r)
+	return err
+}
+
+// GetBlockFromPeerAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetBlockFromPeer for the blocking version and more details.
+func (c *Client) GetBlockFromPeerAsync(blockHash *chainhash.Hash, peerID int32) FutureGetBlockFromPeerResult {
+	hash := ""
+	if blockHash != nil {
+		hash = blockHash.String()
+	}
+
+	cmd := btcjson.NewGetBlockFromPeerCmd(hash, peerID)
+	return c.SendCmd(cmd)
+}
+
+// GetBlockFromPeer requests the block with the given hash from the peer with
+// the given ID.  The block is stored once received, even when it is on a side
+// chain.
+func (c *Client) GetBlockFromPeer(blockHash *chainhash.Hash, peerID int32) error {
+	return c.GetBlockFromPeerAsync(blockHash, peerID).Receive()
+}
+
 // FutureGetBlockHashResult is a future promise to deliver the result of a
 // GetBlockHashAsync RPC invocation (or an applicable error).
 type FutureGetBlockHashResult chan *Response
@@ -0,0 +1,124 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"encoding/json"
+
+	"github.com/ltcsuite/ltcd/btcjson"
+)
+
+// FutureUptimeResult is a future promise to deliver the result of an
+// UptimeAsync RPC invocation (or an applicable error).
+type FutureUptimeResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the number
+// of seconds the server has been running.
+func (r FutureUptimeResult) Receive() (int64, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return 0, err
+	}
+
+	// Unmarshal result as an int64.
+	var uptime int64
+	err = json.Unmarshal(res, &uptime)
+	if err != nil {
+		return 0, err
+	}
+
+	return uptime, nil
+}
+
+// UptimeAsync returns an instance of a type that can be used to get the result
+// of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See Uptime for the blocking version and more details.
+func (c *Client) UptimeAsync() FutureUptimeResult {
+	cmd := btcjson.NewUptimeCmd()
+	return c.SendCmd(cmd)
+}
+
+// Uptime returns the number of seconds the server has been running.
+func (c *Client) Uptime() (int64, error) {
+	return c.UptimeAsync().Receive()
+}
+
+// FutureGetMemoryInfoResult is a future promise to deliver the result of a
+// GetMemoryInfoAsync RPC invocation (or an applicable error).
+type FutureGetMemoryInfoResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the memory
+// usage statistics of the server.
+func (r FutureGetMemoryInfoResult) Receive() (*btcjson.GetMemoryInfoResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getmemoryinfo result object.
+	var info btcjson.GetMemoryInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// GetMemoryInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetMemoryInfo for the blocking version and more details.
+func (c *Client) GetMemoryInfoAsync() FutureGetMemoryInfoResult {
+	cmd := btcjson.NewGetMemoryInfoCmd(nil)
+	return c.SendCmd(cmd)
+}
+
+// GetMemoryInfo returns the memory usage statistics of the server, including
+// the statistics of the Go runtime and of its caches.
+func (c *Client) GetMemoryInfo() (*btcjson.GetMemoryInfoResult, error) {
+	return c.GetMemoryInfoAsync().Receive()
+}
+
+// FutureGetRPCInfoResult is a future promise to deliver the result of a
+// GetRPCInfoAsync RPC invocation (or an applicable error).
+type FutureGetRPCInfoResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the
+// commands the server is processing along with the path of its log file.
+func (r FutureGetRPCInfoResult) Receive() (*btcjson.GetRPCInfoResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getrpcinfo result object.
+	var info btcjson.GetRPCInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// GetRPCInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetRPCInfo for the blocking version and more details.
+func (c *Client) GetRPCInfoAsync() FutureGetRPCInfoResult {
+	cmd := btcjson.NewGetRPCInfoCmd()
+	return c.SendCmd(cmd)
+}
+
+// GetRPCInfo returns the commands the server is processing, including this
+// one, along with the path of its log file.
+func (c *Client) GetRPCInfo() (*btcjson.GetRPCInfoResult, error) {
+	return c.GetRPCInfoAsync().Receive()
+}
@@ -87,11 +87,16 @@ additional RPCs must be issued an a completely decoupled manner.
 
 By default, when running in websockets mode, this client will automatically
 keep trying to reconnect to the RPC server should the connection be lost.  There
-is a back-off in between each connection attempt until it reaches one try per
-minute.  Once a connection is re-established, all previously registered
-notifications are automatically re-registered and any in-flight commands are
-re-issued.  This means from the caller's perspective, the request simply takes
-longer to complete.
+is an exponential back-off in between each connection attempt until it reaches
+one try per minute.  Once a connection is re-established, all previously
+registered notifications, including the transaction filter, are automatically
+re-registered and any in-flight commands are re-issued.  An interrupted rescan
+is resumed from the block of its last progress notification.  This means from
+the caller's perspective, the request simply takes longer to complete.
+
+Notifications sent by the server while the client was disconnected are lost.
+The OnReconnect notification handler is invoked once the connection has been
+re-established so the caller can catch up with the state of the server.
 
 The caller may invoke the Shutdown method on the client to force the client
 to cease reconnect attempts and return ErrClientShutdown for all outstanding
@@ -100,6 +105,18 @@ commands.
 The automatic reconnection can be disabled by setting the DisableAutoReconnect
 flag to true in the connection config when creating the client.
 
+# Cancellation and Timeouts
+
+By default, the requests wait for the reply of the server for as long as it
+takes.  Any request can instead be bound to a context by issuing it with the
+client returned by the WithContext method, which shares the connection of the
+original client.  The request then returns the error of the context as soon as
+it's done, for example:
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	blockCount, err := client.WithContext(ctx).GetBlockCount()
+
 Minor RPC Server Differences and Chain/Wallet Separation
 
 Some of the commands are extensions specific to a particular RPC server.  For
@@ -67,6 +67,90 @@ func (c *Client) DebugLevel(levelSpec string) (string, error) {
 	return c.DebugLevelAsync(levelSpec).Receive()
 }
 
+// FutureSetLogLevelResult is a future promise to deliver the result of a
+// SetLogLevelAsync RPC invocation (or an applicable error).
+type FutureSetLogLevelResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the
+// logging level of each subsystem after the change.
+func (r FutureSetLogLevelResult) Receive() (map[string]string, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a map of subsystems to levels.
+	var result map[string]string
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetLogLevelAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See SetLogLevel for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) SetLogLevelAsync(subsystem, level string) FutureSetLogLevelResult {
+	cmd := btcjson.NewSetLogLevelCmd(subsystem, level)
+	return c.SendCmd(cmd)
+}
+
+// SetLogLevel dynamically sets the logging level of the passed subsystem, or
+// of all subsystems for the special subsystem 'all', and returns the logging
+// level of each subsystem after the change.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) SetLogLevel(subsystem, level string) (map[string]string, error) {
+	return c.SetLogLevelAsync(subsystem, level).Receive()
+}
+
+// FutureSetPolicyResult is a future promise to deliver the result of a
+// SetPolicyAsync RPC invocation (or an applicable error).
+type FutureSetPolicyResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the
+// runtime settings in effect after the change.
+func (r FutureSetPolicyResult) Receive() (*btcjson.SetPolicyResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a setpolicy result object.
+	var result btcjson.SetPolicyResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SetPolicyAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See SetPolicy for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) SetPolicyAsync(options *btcjson.SetPolicyOpts) FutureSetPolicyResult {
+	cmd := btcjson.NewSetPolicyCmd(options)
+	return c.SendCmd(cmd)
+}
+
+// SetPolicy changes the runtime settings of the server which are set in the
+// passed options without restarting it and returns the settings in effect
+// afterwards.  Passing nil options returns the current settings.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) SetPolicy(options *btcjson.SetPolicyOpts) (*btcjson.SetPolicyResult, error) {
+	return c.SetPolicyAsync(options).Receive()
+}
+
 // FutureCreateEncryptedWalletResult is a future promise to deliver the error
 // result of a CreateEncryptedWalletAsync RPC invocation.
 type FutureCreateEncryptedWalletResult chan *Response
@@ -195,6 +279,180 @@ func (c *Client) GetBestBlock() (*chainhash.Hash, int32, error) {
 	return c.GetBestBlockAsync().Receive()
 }
 
+// FutureGetBlockSubsidyResult is a future promise to deliver the result of a
+// GetBlockSubsidyAsync RPC invocation (or an applicable error).
+type FutureGetBlockSubsidyResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the block
+// subsidy at the requested height.
+func (r FutureGetBlockSubsidyResult) Receive() (*btcjson.GetBlockSubsidyResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getblocksubsidy result object.
+	var result btcjson.GetBlockSubsidyResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetBlockSubsidyAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See GetBlockSubsidy for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) GetBlockSubsidyAsync(height *int32) FutureGetBlockSubsidyResult {
+	cmd := btcjson.NewGetBlockSubsidyCmd(height)
+	return c.SendCmd(cmd)
+}
+
+// GetBlockSubsidy returns the subsidy paid by the coinbase of the block at the
+// passed height, or of the next block when the height is nil.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) GetBlockSubsidy(height *int32) (*btcjson.GetBlockSubsidyResult, error) {
+	return c.GetBlockSubsidyAsync(height).Receive()
+}
+
+// FutureGetChainParamsResult is a future promise to deliver the result of a
+// GetChainParamsAsync RPC invocation (or an applicable error).
+type FutureGetChainParamsResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the
+// parameters of the network the server is running on.
+func (r FutureGetChainParamsResult) Receive() (*btcjson.GetChainParamsResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getchainparams result object.
+	var result btcjson.GetChainParamsResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetChainParamsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See GetChainParams for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) GetChainParamsAsync() FutureGetChainParamsResult {
+	cmd := btcjson.NewGetChainParamsCmd()
+	return c.SendCmd(cmd)
+}
+
+// GetChainParams returns the parameters of the network the server is running
+// on, such as its address prefixes and deployment schedule.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) GetChainParams() (*btcjson.GetChainParamsResult, error) {
+	return c.GetChainParamsAsync().Receive()
+}
+
+// FutureGetDifficultyHistoryResult is a future promise to deliver the result
+// of a GetDifficultyHistoryAsync RPC invocation (or an applicable error).
+type FutureGetDifficultyHistoryResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the proof
+// of work of the requested blocks.
+func (r FutureGetDifficultyHistoryResult) Receive() ([]btcjson.GetDifficultyHistoryResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of getdifficultyhistory result objects.
+	var history []btcjson.GetDifficultyHistoryResult
+	err = json.Unmarshal(res, &history)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetDifficultyHistoryAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetDifficultyHistory for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) GetDifficultyHistoryAsync(startHeight int32,
+	endHeight *int32) FutureGetDifficultyHistoryResult {
+
+	cmd := btcjson.NewGetDifficultyHistoryCmd(startHeight, endHeight)
+	return c.SendCmd(cmd)
+}
+
+// GetDifficultyHistory returns the bits, target and solve time of the blocks
+// of the main chain from the start height through the end height, or through
+// the best block when the end height is nil.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) GetDifficultyHistory(startHeight int32,
+	endHeight *int32) ([]btcjson.GetDifficultyHistoryResult, error) {
+
+	return c.GetDifficultyHistoryAsync(startHeight, endHeight).Receive()
+}
+
+// FutureGetMisbehavingLogResult is a future promise to deliver the result of a
+// GetMisbehavingLogAsync RPC invocation (or an applicable error).
+type FutureGetMisbehavingLogResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the most
+// recent events of the misbehavior log.
+func (r FutureGetMisbehavingLogResult) Receive() ([]btcjson.MisbehaviorEventResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of misbehavior events.
+	var events []btcjson.MisbehaviorEventResult
+	err = json.Unmarshal(res, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetMisbehavingLogAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetMisbehavingLog for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) GetMisbehavingLogAsync(count *int) FutureGetMisbehavingLogResult {
+	cmd := btcjson.NewGetMisbehavingLogCmd(count)
+	return c.SendCmd(cmd)
+}
+
+// GetMisbehavingLog returns the most recent protocol violations, ban decisions
+// and disconnections of misbehaving peers, from the oldest to the newest.  At
+// most count events are returned, or 100 when count is nil.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) GetMisbehavingLog(count *int) ([]btcjson.MisbehaviorEventResult, error) {
+	return c.GetMisbehavingLogAsync(count).Receive()
+}
+
 // FutureGetCurrentNetResult is a future promise to deliver the result of a
 // GetCurrentNetAsync RPC invocation (or an applicable error).
 type FutureGetCurrentNetResult chan *Response
@@ -472,3 +730,270 @@ func (c *Client) VersionAsync() FutureVersionResult {
 func (c *Client) Version() (map[string]btcjson.VersionResult, error) {
 	return c.VersionAsync().Receive()
 }
+
+// FutureImportWatchOnlyResult is a future promise to deliver the result of an
+// ImportWatchOnlyAsync RPC invocation (or an applicable error).
+type FutureImportWatchOnlyResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the result
+// of starting to watch the scripts.
+func (r FutureImportWatchOnlyResult) Receive() error {
+	_, err := ReceiveFuture(r)
+	return err
+}
+
+// ImportWatchOnlyAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See ImportWatchOnly for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) ImportWatchOnlyAsync(scripts []string, rescan *bool,
+	startHeight *int32,
+	descriptorRange *btcjson.DescriptorRange) FutureImportWatchOnlyResult {
+
+	cmd := btcjson.NewImportWatchOnlyCmd(scripts, rescan, startHeight,
+		descriptorRange)
+	return c.SendCmd(cmd)
+}
+
+// ImportWatchOnly makes the server track the unspent outputs paying to the
+// passed addresses, hex-encoded public key scripts or output descriptors.
+// Unless rescan is false, the blocks of the main chain from startHeight are
+// scanned for the outputs already paying to them before returning.
+//
+// NOTE: This is a ltcd extension and requires the server to run with
+// --watchonly.
+func (c *Client) ImportWatchOnly(scripts []string, rescan *bool,
+	startHeight *int32, descriptorRange *btcjson.DescriptorRange) error {
+
+	return c.ImportWatchOnlyAsync(scripts, rescan, startHeight,
+		descriptorRange).Receive()
+}
+
+// FutureListUnspentWatchOnlyResult is a future promise to deliver the result
+// of a ListUnspentWatchOnlyAsync RPC invocation (or an applicable error).
+type FutureListUnspentWatchOnlyResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the
+// unspent outputs paying to the watched scripts.
+func (r FutureListUnspentWatchOnlyResult) Receive() ([]btcjson.ListUnspentWatchOnlyResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of listunspentwatchonly result objects.
+	var unspent []btcjson.ListUnspentWatchOnlyResult
+	err = json.Unmarshal(res, &unspent)
+	if err != nil {
+		return nil, err
+	}
+
+	return unspent, nil
+}
+
+// ListUnspentWatchOnlyAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See ListUnspentWatchOnly for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) ListUnspentWatchOnlyAsync(minConf, maxConf *int,
+	addresses *[]string) FutureListUnspentWatchOnlyResult {
+
+	cmd := btcjson.NewListUnspentWatchOnlyCmd(minConf, maxConf, addresses)
+	return c.SendCmd(cmd)
+}
+
+// ListUnspentWatchOnly returns the unspent outputs paying to the scripts
+// watched by the server, including the outputs of mempool transactions which
+// have no confirmations, optionally limited to the passed addresses.
+//
+// NOTE: This is a ltcd extension and requires the server to run with
+// --watchonly.
+func (c *Client) ListUnspentWatchOnly(minConf, maxConf *int,
+	addresses *[]string) ([]btcjson.ListUnspentWatchOnlyResult, error) {
+
+	return c.ListUnspentWatchOnlyAsync(minConf, maxConf, addresses).Receive()
+}
+
+// FutureRescanBlockchainResult is a future promise to deliver the result of a
+// RescanBlockchainAsync RPC invocation (or an applicable error).
+type FutureRescanBlockchainResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the
+// transactions found by the rescan.
+func (r FutureRescanBlockchainResult) Receive() (*btcjson.RescanBlockchainResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a rescanblockchain result object.
+	var result btcjson.RescanBlockchainResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// RescanBlockchainAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See RescanBlockchain for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) RescanBlockchainAsync(startHeight, stopHeight *int32,
+	scripts *[]string, outPoints []*wire.OutPoint) FutureRescanBlockchainResult {
+
+	var ops *[]btcjson.OutPoint
+	if outPoints != nil {
+		converted := make([]btcjson.OutPoint, 0, len(outPoints))
+		for _, op := range outPoints {
+			converted = append(converted, newOutPointFromWire(op))
+		}
+		ops = &converted
+	}
+
+	cmd := btcjson.NewRescanBlockchainCmd(startHeight, stopHeight, scripts,
+		ops)
+	return c.SendCmd(cmd)
+}
+
+// RescanBlockchain scans the blocks of the main chain between the passed
+// heights for the transactions paying to the passed addresses, hex-encoded
+// public key scripts or output descriptors, or spending the passed outpoints.
+// Over websockets, each transaction is also delivered to the
+// OnRescanBlockchainMatch notification handler as soon as it is found.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) RescanBlockchain(startHeight, stopHeight *int32,
+	scripts *[]string,
+	outPoints []*wire.OutPoint) (*btcjson.RescanBlockchainResult, error) {
+
+	return c.RescanBlockchainAsync(startHeight, stopHeight, scripts,
+		outPoints).Receive()
+}
+
+// FutureStartOperationResult is a future promise to deliver the result of a
+// StartOperationAsync RPC invocation (or an applicable error).
+type FutureStartOperationResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the id of
+// the started operation.
+func (r FutureStartOperationResult) Receive() (string, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return "", err
+	}
+
+	// Unmarshal the result as a string.
+	var id string
+	err = json.Unmarshal(res, &id)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// StartOperationAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See StartOperation for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) StartOperationAsync(method string,
+	params ...interface{}) FutureStartOperationResult {
+
+	marshalled := make([]json.RawMessage, 0, len(params))
+	for _, param := range params {
+		raw, err := json.Marshal(param)
+		if err != nil {
+			return newFutureError(err)
+		}
+		marshalled = append(marshalled, raw)
+	}
+
+	cmd := btcjson.NewStartOperationCmd(method, &marshalled)
+	return c.SendCmd(cmd)
+}
+
+// StartOperation runs the passed long-running command, such as verifychain,
+// gettxoutsetinfo or rescanblockchain, with the passed parameters in the
+// background and returns the id of the operation.  Its progress and result
+// are returned by GetOperationStatus, and over websockets its progress is
+// also delivered to the OnOperationProgress notification handler.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) StartOperation(method string, params ...interface{}) (string, error) {
+	return c.StartOperationAsync(method, params...).Receive()
+}
+
+// FutureOperationStatusResult is a future promise to deliver the result of a
+// GetOperationStatusAsync or CancelOperationAsync RPC invocation (or an
+// applicable error).
+type FutureOperationStatusResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the status
+// of the operation.
+func (r FutureOperationStatusResult) Receive() (*btcjson.OperationStatusResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as an operation status object.
+	var status btcjson.OperationStatusResult
+	err = json.Unmarshal(res, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetOperationStatusAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetOperationStatus for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) GetOperationStatusAsync(id string) FutureOperationStatusResult {
+	cmd := btcjson.NewGetOperationStatusCmd(id)
+	return c.SendCmd(cmd)
+}
+
+// GetOperationStatus returns the progress of the operation with the passed id
+// along with the result of its command once it has completed.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) GetOperationStatus(id string) (*btcjson.OperationStatusResult, error) {
+	return c.GetOperationStatusAsync(id).Receive()
+}
+
+// CancelOperationAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See CancelOperation for the blocking version and more details.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) CancelOperationAsync(id string) FutureOperationStatusResult {
+	cmd := btcjson.NewCancelOperationCmd(id)
+	return c.SendCmd(cmd)
+}
+
+// CancelOperation requests the operation with the passed id to stop and
+// returns its status, which may still be running until it has stopped.
+//
+// NOTE: This is a ltcd extension.
+func (c *Client) CancelOperation(id string) (*btcjson.OperationStatusResult, error) {
+	return c.CancelOperationAsync(id).Receive()
+}
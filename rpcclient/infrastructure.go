@@ -7,6 +7,7 @@ package rpcclient
 import (
 	"bytes"
 	"container/list"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
@@ -84,9 +85,14 @@ const (
 	sendPostBufferSize = 100
 
 	// connectionRetryInterval is the amount of time to wait in between
-	// retries when automatically reconnecting to an RPC server.
+	// retries when automatically reconnecting to an RPC server.  It is
+	// doubled after each failed retry up to maxConnectionRetryInterval.
 	connectionRetryInterval = time.Second * 5
 
+	// maxConnectionRetryInterval is the maximum amount of time to wait in
+	// between retries when automatically reconnecting to an RPC server.
+	maxConnectionRetryInterval = time.Minute
+
 	// requestRetryInterval is the initial amount of time to wait in between
 	// retries when sending HTTP POST requests.
 	requestRetryInterval = time.Millisecond * 500
@@ -100,6 +106,9 @@ type jsonRequest struct {
 	cmd            interface{}
 	marshalledJSON []byte
 	responseChan   chan *Response
+
+	// ctx is the context the request is bound to, if any.
+	ctx context.Context
 }
 
 // BackendVersion represents the version of the backend the client is currently
@@ -130,7 +139,20 @@ const (
 // result of the invocation at some future time.  Invoking the Receive method on
 // the returned future will block until the result is available if it's not
 // already.
+//
+// The requests may be bound to a context, which allows them to be cancelled or
+// to time out, by issuing them with the client returned by WithContext.
 type Client struct {
+	*clientState
+
+	// ctx is the context the requests issued with this client are bound
+	// to.  It is nil unless the client was returned by WithContext.
+	ctx context.Context
+}
+
+// clientState houses the connection state of a client, which is shared with the
+// clients returned by its WithContext method.
+type clientState struct {
 	id uint64 // atomic, so must stay 64-bit aligned
 
 	// config holds the connection configuration assoiated with this client.
@@ -291,6 +313,23 @@ func (c *Client) trackRegisteredNtfns(cmd interface{}) {
 		for _, addr := range bcmd.Addresses {
 			c.ntfnState.notifyReceived[addr] = struct{}{}
 		}
+
+	case *btcjson.LoadTxFilterCmd:
+		if bcmd.Reload {
+			c.ntfnState.txFilterAddrs = make(map[string]struct{})
+			c.ntfnState.txFilterOutPoints = make(
+				map[btcjson.OutPoint]struct{})
+		}
+		for _, addr := range bcmd.Addresses {
+			c.ntfnState.txFilterAddrs[addr] = struct{}{}
+		}
+		for _, op := range bcmd.OutPoints {
+			c.ntfnState.txFilterOutPoints[op] = struct{}{}
+		}
+
+	case *btcjson.RescanCmd:
+		// The rescan finished, so there is nothing to resume.
+		c.ntfnState.rescanProgress = nil
 	}
 }
 
@@ -617,13 +656,61 @@ func (c *Client) reregisterNtfns() error {
 		}
 	}
 
+	// Reload the transaction filter with all of the previously loaded
+	// addresses and outpoints in one command if needed.
+	if len(stateCopy.txFilterAddrs) > 0 || len(stateCopy.txFilterOutPoints) > 0 {
+		addresses := make([]string, 0, len(stateCopy.txFilterAddrs))
+		for addr := range stateCopy.txFilterAddrs {
+			addresses = append(addresses, addr)
+		}
+		outpoints := make([]btcjson.OutPoint, 0,
+			len(stateCopy.txFilterOutPoints))
+		for op := range stateCopy.txFilterOutPoints {
+			outpoints = append(outpoints, op)
+		}
+		log.Debugf("Reregistering [loadtxfilter] with %d addresses and "+
+			"%d outpoints", len(addresses), len(outpoints))
+		cmd := btcjson.NewLoadTxFilterCmd(true, addresses, outpoints)
+		if err := FutureLoadTxFilterResult(c.SendCmd(cmd)).Receive(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// ignoreResends is a set of all methods for requests that are "long running"
-// are not be reissued by the client on reconnect.
-var ignoreResends = map[string]struct{}{
-	"rescan": {},
+// resumeRescans updates the passed pending rescan requests so they resume from
+// the block of the last rescan progress notification when they are resent on
+// reconnect.  Since the progress notifications can't be told apart when
+// several rescans are underway at once, they are only resumed when there is a
+// single one.  Otherwise, they are restarted from their starting block.
+//
+// This function MUST be called with the request lock held.
+func (c *Client) resumeRescans(rescans []*jsonRequest) {
+	c.ntfnStateLock.Lock()
+	progress := c.ntfnState.rescanProgress
+	c.ntfnStateLock.Unlock()
+	if len(rescans) != 1 || progress == nil {
+		return
+	}
+
+	jReq := rescans[0]
+	cmd, ok := jReq.cmd.(*btcjson.RescanCmd)
+	if !ok {
+		return
+	}
+	resumeCmd := *cmd
+	resumeCmd.BeginBlock = progress.String()
+	marshalledJSON, err := btcjson.MarshalCmd(btcjson.RpcVersion1, jReq.id,
+		&resumeCmd)
+	if err != nil {
+		log.Warnf("Unable to resume rescan from block %v: %v",
+			progress, err)
+		return
+	}
+	log.Debugf("Resuming rescan from block %v", progress)
+	jReq.cmd = &resumeCmd
+	jReq.marshalledJSON = marshalledJSON
 }
 
 // resendRequests resends any requests that had not completed when the client
@@ -644,21 +731,15 @@ func (c *Client) resendRequests() {
 	// also allows the lock to be released quickly.
 	c.requestLock.Lock()
 	resendReqs := make([]*jsonRequest, 0, c.requestList.Len())
-	var nextElem *list.Element
-	for e := c.requestList.Front(); e != nil; e = nextElem {
-		nextElem = e.Next()
-
+	var rescans []*jsonRequest
+	for e := c.requestList.Front(); e != nil; e = e.Next() {
 		jReq := e.Value.(*jsonRequest)
-		if _, ok := ignoreResends[jReq.method]; ok {
-			// If a request is not sent on reconnect, remove it
-			// from the request structures, since no reply is
-			// expected.
-			delete(c.requestMap, jReq.id)
-			c.requestList.Remove(e)
-		} else {
-			resendReqs = append(resendReqs, jReq)
+		if jReq.method == "rescan" {
+			rescans = append(rescans, jReq)
 		}
+		resendReqs = append(resendReqs, jReq)
 	}
+	c.resumeRescans(rescans)
 	c.requestLock.Unlock()
 
 	for _, jReq := range resendReqs {
@@ -672,14 +753,32 @@ func (c *Client) resendRequests() {
 			jReq.id)
 		c.sendMessage(jReq.marshalledJSON)
 	}
+
+	if c.ntfnHandlers != nil && c.ntfnHandlers.OnReconnect != nil {
+		c.ntfnHandlers.OnReconnect()
+	}
+}
+
+// reconnectInterval returns the amount of time to wait before the passed retry
+// to reconnect to the RPC server, which doubles with each retry so there is an
+// exponential backoff up to a max of maxConnectionRetryInterval.
+func reconnectInterval(retryCount int64) time.Duration {
+	interval := connectionRetryInterval
+	for i := int64(1); i < retryCount; i++ {
+		interval *= 2
+		if interval >= maxConnectionRetryInterval {
+			return maxConnectionRetryInterval
+		}
+	}
+	return interval
 }
 
 // wsReconnectHandler listens for client disconnects and automatically tries
 // to reconnect with retry interval that scales based on the number of retries.
-// It also resends any commands that had not completed when the client
-// disconnected so the disconnect/reconnect process is largely transparent to
-// the caller.  This function is not run when the DisableAutoReconnect config
-// options is set.
+// It also re-registers the notifications the client was registered for and
+// resends any commands that had not completed when the client disconnected so
+// the disconnect/reconnect process is largely transparent to the caller.  This
+// function is not run when the DisableAutoReconnect config options is set.
 //
 // This function must be run as a goroutine.
 func (c *Client) wsReconnectHandler() {
@@ -708,17 +807,16 @@ out:
 				log.Infof("Failed to connect to %s: %v",
 					c.config.Host, err)
 
-				// Scale the retry interval by the number of
-				// retries so there is a backoff up to a max
-				// of 1 minute.
-				scaledInterval := connectionRetryInterval.Nanoseconds() * c.retryCount
-				scaledDuration := time.Duration(scaledInterval)
-				if scaledDuration > time.Minute {
-					scaledDuration = time.Minute
-				}
+				// Back off exponentially, but stop waiting
+				// as soon as the client is shutdown.
+				interval := reconnectInterval(c.retryCount)
 				log.Infof("Retrying connection to %s in "+
-					"%s", c.config.Host, scaledDuration)
-				time.Sleep(scaledDuration)
+					"%s", c.config.Host, interval)
+				select {
+				case <-time.After(interval):
+				case <-c.shutdown:
+					break out
+				}
 				continue reconnect
 			}
 
@@ -768,6 +866,17 @@ func (c *Client) handleSendPostMessage(jReq *jsonRequest) {
 	}
 	url := protocol + "://" + c.config.Host
 
+	// Don't send the request if its context is done already, which is
+	// the case when it was cancelled while queued.
+	ctx := jReq.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		jReq.responseChan <- &Response{err: err}
+		return
+	}
+
 	var (
 		err, lastErr error
 		backoff      time.Duration
@@ -779,7 +888,8 @@ func (c *Client) handleSendPostMessage(jReq *jsonRequest) {
 		var httpReq *http.Request
 
 		bodyReader := bytes.NewReader(jReq.marshalledJSON)
-		httpReq, err = http.NewRequest("POST", url, bodyReader)
+		httpReq, err = http.NewRequestWithContext(ctx, "POST", url,
+			bodyReader)
 		if err != nil {
 			jReq.responseChan <- &Response{result: nil, err: err}
 			return
@@ -791,12 +901,14 @@ func (c *Client) handleSendPostMessage(jReq *jsonRequest) {
 		}
 
 		// Configure basic access authorization.
-		user, pass, err := c.config.getAuth()
-		if err != nil {
-			jReq.responseChan <- &Response{result: nil, err: err}
-			return
+		if !c.config.useClientCertAuth() {
+			user, pass, err := c.config.getAuth()
+			if err != nil {
+				jReq.responseChan <- &Response{result: nil, err: err}
+				return
+			}
+			httpReq.SetBasicAuth(user, pass)
 		}
-		httpReq.SetBasicAuth(user, pass)
 
 		httpResponse, err = c.httpClient.Do(httpReq)
 
@@ -823,6 +935,10 @@ func (c *Client) handleSendPostMessage(jReq *jsonRequest) {
 		select {
 		case <-time.After(backoff):
 
+		case <-ctx.Done():
+			jReq.responseChan <- &Response{err: ctx.Err()}
+			return
+
 		case <-c.shutdown:
 			return
 		}
@@ -1026,10 +1142,65 @@ func (c *Client) SendCmd(cmd interface{}) chan *Response {
 		cmd:            cmd,
 		marshalledJSON: marshalledJSON,
 		responseChan:   responseChan,
+		ctx:            c.ctx,
 	}
 
 	c.sendRequest(jReq)
 
+	return c.bindContext(jReq)
+}
+
+// WithContext returns a client which shares the connection of the receiver and
+// binds the requests issued with it to the passed context.  The future of a
+// request is delivered the error of the context, such as context.Canceled or
+// context.DeadlineExceeded, as soon as the context is done before the reply
+// is received.  The request is then abandoned, which means it is not reissued
+// on reconnect and its reply is ignored, although the server might still
+// process it.
+//
+// For example, the following waits at most 10 seconds for the block count:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	blockCount, err := client.WithContext(ctx).GetBlockCount()
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("nil context")
+	}
+	return &Client{clientState: c.clientState, ctx: ctx}
+}
+
+// Context returns the context the requests issued with the client are bound to,
+// which is context.Background unless the client was returned by WithContext.
+func (c *Client) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// bindContext returns the channel the response of the passed request, which was
+// sent already, is delivered on.  When the request is bound to a context which
+// can be done, the channel is instead delivered the error of the context once
+// it's done before the response is received, and the request is abandoned.
+func (c *Client) bindContext(jReq *jsonRequest) chan *Response {
+	if jReq.ctx == nil || jReq.ctx.Done() == nil {
+		return jReq.responseChan
+	}
+
+	responseChan := make(chan *Response, 1)
+	go func() {
+		select {
+		case resp := <-jReq.responseChan:
+			responseChan <- resp
+
+		case <-jReq.ctx.Done():
+			// Stop tracking the request so it is not reissued on
+			// reconnect.
+			c.removeRequest(jReq.id)
+			responseChan <- &Response{err: jReq.ctx.Err()}
+		}
+	}()
 	return responseChan
 }
 
@@ -1233,6 +1404,14 @@ type ConnConfig struct {
 	// is true.
 	Certificates []byte
 
+	// ClientCertificate and ClientKey are the bytes for a PEM-encoded
+	// certificate chain and private key the client authenticates with when
+	// the RPC server requires client certificates.  The username and
+	// passphrase may be left empty in that case.  They have no effect if
+	// the DisableTLS parameter is true.
+	ClientCertificate []byte
+	ClientKey         []byte
+
 	// Proxy specifies to connect through a SOCKS 5 proxy server.  It may
 	// be an empty string if a proxy is not required.
 	Proxy string
@@ -1288,6 +1467,28 @@ func (config *ConnConfig) getAuth() (username, passphrase string, err error) {
 	return config.retrieveCookie()
 }
 
+// useClientCertAuth returns whether the client authenticates with a client
+// certificate only, in which case no basic authorization is sent.
+func (config *ConnConfig) useClientCertAuth() bool {
+	return !config.DisableTLS && len(config.ClientCertificate) > 0 &&
+		config.User == "" && config.Pass == "" && config.CookiePath == ""
+}
+
+// addClientCertificate adds the client certificate of the connection
+// configuration, if any, to the passed TLS configuration.
+func (config *ConnConfig) addClientCertificate(tlsConfig *tls.Config) error {
+	if len(config.ClientCertificate) == 0 {
+		return nil
+	}
+	cert, err := tls.X509KeyPair(config.ClientCertificate,
+		config.ClientKey)
+	if err != nil {
+		return err
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return nil
+}
+
 // retrieveCookie returns the cookie username and passphrase.
 func (config *ConnConfig) retrieveCookie() (username, passphrase string, err error) {
 	if !config.cookieLastCheckTime.IsZero() && time.Now().Before(config.cookieLastCheckTime.Add(30*time.Second)) {
@@ -1334,6 +1535,15 @@ func newHTTPClient(config *ConnConfig) (*http.Client, error) {
 				RootCAs: pool,
 			}
 		}
+		if len(config.ClientCertificate) > 0 {
+			if tlsConfig == nil {
+				tlsConfig = &tls.Config{}
+			}
+			err := config.addClientCertificate(tlsConfig)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	client := http.Client{
@@ -1361,6 +1571,9 @@ func dial(config *ConnConfig) (*websocket.Conn, error) {
 			pool.AppendCertsFromPEM(config.Certificates)
 			tlsConfig.RootCAs = pool
 		}
+		if err := config.addClientCertificate(tlsConfig); err != nil {
+			return nil, err
+		}
 		scheme = "wss"
 	}
 
@@ -1380,14 +1593,16 @@ func dial(config *ConnConfig) (*websocket.Conn, error) {
 
 	// The RPC server requires basic authorization, so create a custom
 	// request header with the Authorization header set.
-	user, pass, err := config.getAuth()
-	if err != nil {
-		return nil, err
-	}
-	login := user + ":" + pass
-	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
 	requestHeader := make(http.Header)
-	requestHeader.Add("Authorization", auth)
+	if !config.useClientCertAuth() {
+		user, pass, err := config.getAuth()
+		if err != nil {
+			return nil, err
+		}
+		login := user + ":" + pass
+		auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+		requestHeader.Add("Authorization", auth)
+	}
 	for key, value := range config.ExtraHeaders {
 		requestHeader.Add(key, value)
 	}
@@ -1452,7 +1667,7 @@ func New(config *ConnConfig, ntfnHandlers *NotificationHandlers) (*Client, error
 		}
 	}
 
-	client := &Client{
+	state := &clientState{
 		config:          config,
 		wsConn:          wsConn,
 		httpClient:      httpClient,
@@ -1468,6 +1683,7 @@ func New(config *ConnConfig, ntfnHandlers *NotificationHandlers) (*Client, error
 		disconnect:      make(chan struct{}),
 		shutdown:        make(chan struct{}),
 	}
+	client := &Client{clientState: state}
 
 	// Default network is mainnet, no parameters are necessary but if mainnet
 	// is specified it will be the param
@@ -0,0 +1,198 @@
+package rpcclient
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ltcsuite/ltcd/btcjson"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+// TestReconnectInterval ensures the interval in between reconnect attempts is
+// doubled after each retry up to the maximum.
+func TestReconnectInterval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		retryCount int64
+		want       time.Duration
+	}{
+		{retryCount: 1, want: 5 * time.Second},
+		{retryCount: 2, want: 10 * time.Second},
+		{retryCount: 3, want: 20 * time.Second},
+		{retryCount: 4, want: 40 * time.Second},
+		{retryCount: 5, want: time.Minute},
+		{retryCount: 1000, want: time.Minute},
+	}
+	for _, test := range tests {
+		got := reconnectInterval(test.retryCount)
+		if got != test.want {
+			t.Errorf("reconnectInterval(%d): got %v, want %v",
+				test.retryCount, got, test.want)
+		}
+	}
+}
+
+// TestTrackLoadTxFilter ensures the transaction filter is tracked so it can be
+// reloaded on reconnect and that reloading it replaces the tracked filter.
+func TestTrackLoadTxFilter(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{clientState: &clientState{
+		ntfnHandlers: &NotificationHandlers{},
+		ntfnState:    newNotificationState(),
+	}}
+	op := btcjson.OutPoint{Hash: chainhash.Hash{1}.String(), Index: 1}
+	c.trackRegisteredNtfns(btcjson.NewLoadTxFilterCmd(false,
+		[]string{"addr1"}, nil))
+	c.trackRegisteredNtfns(btcjson.NewLoadTxFilterCmd(false,
+		[]string{"addr2"}, []btcjson.OutPoint{op}))
+	if len(c.ntfnState.txFilterAddrs) != 2 ||
+		len(c.ntfnState.txFilterOutPoints) != 1 {
+
+		t.Fatalf("got %d addresses and %d outpoints, want 2 and 1",
+			len(c.ntfnState.txFilterAddrs),
+			len(c.ntfnState.txFilterOutPoints))
+	}
+
+	c.trackRegisteredNtfns(btcjson.NewLoadTxFilterCmd(true,
+		[]string{"addr3"}, nil))
+	if _, ok := c.ntfnState.txFilterAddrs["addr3"]; !ok ||
+		len(c.ntfnState.txFilterAddrs) != 1 ||
+		len(c.ntfnState.txFilterOutPoints) != 0 {
+
+		t.Fatalf("reloading did not replace the filter: %v %v",
+			c.ntfnState.txFilterAddrs, c.ntfnState.txFilterOutPoints)
+	}
+}
+
+// TestResumeRescans ensures a single pending rescan is resumed from the block
+// of the last progress notification while several are left untouched.
+func TestResumeRescans(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{clientState: &clientState{
+		ntfnHandlers: &NotificationHandlers{},
+		ntfnState:    newNotificationState(),
+	}}
+	progress := chainhash.Hash{2}
+	c.ntfnState.rescanProgress = &progress
+
+	newRescan := func(id uint64) *jsonRequest {
+		cmd := btcjson.NewRescanCmd(chainhash.Hash{1}.String(),
+			[]string{"addr"}, nil, nil)
+		marshalled, err := btcjson.MarshalCmd(btcjson.RpcVersion1, id, cmd)
+		if err != nil {
+			t.Fatalf("MarshalCmd: %v", err)
+		}
+		return &jsonRequest{id: id, method: "rescan", cmd: cmd,
+			marshalledJSON: marshalled}
+	}
+
+	first, second := newRescan(1), newRescan(2)
+	c.resumeRescans([]*jsonRequest{first, second})
+	if first.cmd.(*btcjson.RescanCmd).BeginBlock != (chainhash.Hash{1}).String() {
+		t.Fatalf("resumed one of several rescans")
+	}
+
+	c.resumeRescans([]*jsonRequest{first})
+	cmd := first.cmd.(*btcjson.RescanCmd)
+	if cmd.BeginBlock != progress.String() {
+		t.Fatalf("rescan resumed from %v, want %v", cmd.BeginBlock,
+			progress)
+	}
+	want, err := btcjson.MarshalCmd(btcjson.RpcVersion1, 1, cmd)
+	if err != nil {
+		t.Fatalf("MarshalCmd: %v", err)
+	}
+	if string(first.marshalledJSON) != string(want) {
+		t.Fatalf("resumed rescan marshalled as %s, want %s",
+			first.marshalledJSON, want)
+	}
+
+	// A finished rescan clears the progress.
+	c.trackRegisteredNtfns(cmd)
+	if c.ntfnState.rescanProgress != nil {
+		t.Fatalf("rescan progress was not cleared")
+	}
+}
+
+// TestWithContext ensures requests issued with a client bound to a context
+// return the error of the context once it's done instead of blocking until
+// the server replies.
+func TestWithContext(t *testing.T) {
+	t.Parallel()
+
+	// The server replies to the requests once the test finishes.
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := New(&ConnConfig{
+		Host:         strings.TrimPrefix(server.URL, "http://"),
+		User:         "user",
+		Pass:         "pass",
+		DisableTLS:   true,
+		HTTPPostMode: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer client.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		50*time.Millisecond)
+	defer cancel()
+	_, err = client.WithContext(ctx).GetBlockCount()
+	if err != context.DeadlineExceeded {
+		t.Fatalf("GetBlockCount: got error %v, want %v", err,
+			context.DeadlineExceeded)
+	}
+
+	// A request bound to a context which is done already is not sent.
+	_, err = client.WithContext(ctx).GetBlockCount()
+	if err != context.DeadlineExceeded {
+		t.Fatalf("GetBlockCount: got error %v, want %v", err,
+			context.DeadlineExceeded)
+	}
+}
+
+// TestBindContext ensures a websocket request whose context is done is no
+// longer tracked, so it is not reissued on reconnect.
+func TestBindContext(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{clientState: &clientState{
+		requestMap:  make(map[uint64]*list.Element),
+		requestList: list.New(),
+		shutdown:    make(chan struct{}),
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	jReq := &jsonRequest{
+		id:           1,
+		method:       "getblockcount",
+		responseChan: make(chan *Response, 1),
+		ctx:          ctx,
+	}
+	if err := client.addRequest(jReq); err != nil {
+		t.Fatalf("addRequest: %v", err)
+	}
+
+	future := client.bindContext(jReq)
+	cancel()
+	if _, err := ReceiveFuture(future); err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if client.removeRequest(jReq.id) != nil {
+		t.Fatalf("cancelled request is still tracked")
+	}
+}
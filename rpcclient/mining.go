@@ -5,6 +5,7 @@
 package rpcclient
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -12,6 +13,7 @@ import (
 	"github.com/ltcsuite/ltcd/btcjson"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
 )
 
 // FutureGenerateResult is a future promise to deliver the result of a
@@ -171,6 +173,34 @@ func (c *Client) SetGenerate(enable bool, numCPUs int) error {
 	return c.SetGenerateAsync(enable, numCPUs).Receive()
 }
 
+// FutureSetMockTimeResult is a future promise to deliver the result of a
+// SetMockTimeAsync RPC invocation (or an applicable error).
+type FutureSetMockTimeResult chan *Response
+
+// Receive waits for the Response promised by the future and returns an error if
+// any occurred when setting the mock time of the server.
+func (r FutureSetMockTimeResult) Receive() error {
+	_, err := ReceiveFuture(r)
+	return err
+}
+
+// SetMockTimeAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See SetMockTime for the blocking version and more details.
+func (c *Client) SetMockTimeAsync(timestamp int64) FutureSetMockTimeResult {
+	cmd := btcjson.NewSetMockTimeCmd(timestamp)
+	return c.SendCmd(cmd)
+}
+
+// SetMockTime fixes the clock of the server to the passed unix time, or
+// restores its real clock when the passed time is 0.  It is only supported on
+// simnet and regtest.
+func (c *Client) SetMockTime(timestamp int64) error {
+	return c.SetMockTimeAsync(timestamp).Receive()
+}
+
 // FutureGetHashesPerSecResult is a future promise to deliver the result of a
 // GetHashesPerSecAsync RPC invocation (or an applicable error).
 type FutureGetHashesPerSecResult chan *Response
@@ -211,6 +241,61 @@ func (c *Client) GetHashesPerSec() (int64, error) {
 	return c.GetHashesPerSecAsync().Receive()
 }
 
+// FutureGenerateBlockResult is a future promise to deliver the result of a
+// GenerateBlockAsync RPC invocation (or an applicable error).
+type FutureGenerateBlockResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the hash
+// of the generated block.
+func (r FutureGenerateBlockResult) Receive() (*chainhash.Hash, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var result btcjson.GenerateBlockResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return chainhash.NewHashFromStr(result.Hash)
+}
+
+// GenerateBlockAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GenerateBlock for the blocking version and more details.
+func (c *Client) GenerateBlockAsync(address ltcutil.Address,
+	txns []*wire.MsgTx, timestamp *int64) FutureGenerateBlockResult {
+
+	rawTxns := make([]string, 0, len(txns))
+	for _, tx := range txns {
+		var buf bytes.Buffer
+		if err := tx.Serialize(&buf); err != nil {
+			return newFutureError(err)
+		}
+		rawTxns = append(rawTxns, hex.EncodeToString(buf.Bytes()))
+	}
+
+	cmd := btcjson.NewGenerateBlockCmd(address.EncodeAddress(), rawTxns,
+		timestamp)
+	return c.SendCmd(cmd)
+}
+
+// GenerateBlock generates a block paying to the given address which contains
+// exactly the given transactions and returns its hash.  The block is
+// timestamped with the given unix time unless it is nil.
+//
+// NOTE: This is a ltcd extension which is only available on simnet and
+// regtest.
+func (c *Client) GenerateBlock(address ltcutil.Address, txns []*wire.MsgTx,
+	timestamp *int64) (*chainhash.Hash, error) {
+
+	return c.GenerateBlockAsync(address, txns, timestamp).Receive()
+}
+
 // FutureGetMiningInfoResult is a future promise to deliver the result of a
 // GetMiningInfoAsync RPC invocation (or an applicable error).
 type FutureGetMiningInfoResult chan *Response
@@ -461,6 +546,43 @@ func (c *Client) SubmitBlock(block *ltcutil.Block, options *btcjson.SubmitBlockO
 	return c.SubmitBlockAsync(block, options).Receive()
 }
 
+// FutureSubmitHeaderResult is a future promise to deliver the result of a
+// SubmitHeaderAsync RPC invocation (or an applicable error).
+type FutureSubmitHeaderResult chan *Response
+
+// Receive waits for the Response promised by the future and returns an error if
+// the header was rejected.
+func (r FutureSubmitHeaderResult) Receive() error {
+	_, err := ReceiveFuture(r)
+	return err
+}
+
+// SubmitHeaderAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See SubmitHeader for the blocking version and more details.
+func (c *Client) SubmitHeaderAsync(header *wire.BlockHeader) FutureSubmitHeaderResult {
+	headerHex := ""
+	if header != nil {
+		var buf bytes.Buffer
+		if err := header.Serialize(&buf); err != nil {
+			return newFutureError(err)
+		}
+
+		headerHex = hex.EncodeToString(buf.Bytes())
+	}
+
+	cmd := btcjson.NewSubmitHeaderCmd(headerHex)
+	return c.SendCmd(cmd)
+}
+
+// SubmitHeader attempts to add a block header to the block index of the server
+// ahead of its block.
+func (c *Client) SubmitHeader(header *wire.BlockHeader) error {
+	return c.SubmitHeaderAsync(header).Receive()
+}
+
 // FutureGetBlockTemplateResponse is a future promise to deliver the result of a
 // GetBlockTemplateAsync RPC invocation (or an applicable error).
 type FutureGetBlockTemplateResponse chan *Response
@@ -318,6 +318,44 @@ func (c *Client) GetNodeAddresses(count *int32) ([]btcjson.GetNodeAddressesResul
 	return c.GetNodeAddressesAsync(count).Receive()
 }
 
+// FutureGetAddrManInfoResult is a future promise to deliver the result of a
+// GetAddrManInfoAsync RPC invocation (or an applicable error).
+type FutureGetAddrManInfoResult chan *Response
+
+// Receive waits for the Response promised by the future and returns statistics
+// about the addresses known to the address manager.
+func (r FutureGetAddrManInfoResult) Receive() (*btcjson.GetAddrManInfoResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getaddrmaninfo result object.
+	var info btcjson.GetAddrManInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// GetAddrManInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetAddrManInfo for the blocking version and more details.
+func (c *Client) GetAddrManInfoAsync(sample *int) FutureGetAddrManInfoResult {
+	cmd := btcjson.NewGetAddrManInfoCmd(sample)
+	return c.SendCmd(cmd)
+}
+
+// GetAddrManInfo returns statistics about the addresses known to the address
+// manager along with a random sample of the requested number of addresses.
+func (c *Client) GetAddrManInfo(sample *int) (*btcjson.GetAddrManInfoResult, error) {
+	return c.GetAddrManInfoAsync(sample).Receive()
+}
+
 // FutureGetPeerInfoResult is a future promise to deliver the result of a
 // GetPeerInfoAsync RPC invocation (or an applicable error).
 type FutureGetPeerInfoResult chan *Response
@@ -37,6 +37,13 @@ type notificationState struct {
 	notifyNewTxVerbose bool
 	notifyReceived     map[string]struct{}
 	notifySpent        map[btcjson.OutPoint]struct{}
+	txFilterAddrs      map[string]struct{}
+	txFilterOutPoints  map[btcjson.OutPoint]struct{}
+
+	// rescanProgress is the hash of the last block reported by a rescan
+	// progress notification while a rescan is underway.  It is used to
+	// resume the rescan from that block on reconnect.
+	rescanProgress *chainhash.Hash
 }
 
 // Copy returns a deep copy of the receiver.
@@ -53,6 +60,15 @@ func (s *notificationState) Copy() *notificationState {
 	for op := range s.notifySpent {
 		stateCopy.notifySpent[op] = struct{}{}
 	}
+	stateCopy.txFilterAddrs = make(map[string]struct{})
+	for addr := range s.txFilterAddrs {
+		stateCopy.txFilterAddrs[addr] = struct{}{}
+	}
+	stateCopy.txFilterOutPoints = make(map[btcjson.OutPoint]struct{})
+	for op := range s.txFilterOutPoints {
+		stateCopy.txFilterOutPoints[op] = struct{}{}
+	}
+	stateCopy.rescanProgress = s.rescanProgress
 
 	return &stateCopy
 }
@@ -60,8 +76,10 @@ func (s *notificationState) Copy() *notificationState {
 // newNotificationState returns a new notification state ready to be populated.
 func newNotificationState() *notificationState {
 	return &notificationState{
-		notifyReceived: make(map[string]struct{}),
-		notifySpent:    make(map[btcjson.OutPoint]struct{}),
+		notifyReceived:    make(map[string]struct{}),
+		notifySpent:       make(map[btcjson.OutPoint]struct{}),
+		txFilterAddrs:     make(map[string]struct{}),
+		txFilterOutPoints: make(map[btcjson.OutPoint]struct{}),
 	}
 }
 
@@ -90,6 +108,15 @@ type NotificationHandlers struct {
 	// notification handlers, and is safe for blocking client requests.
 	OnClientConnected func()
 
+	// OnReconnect is invoked once the client has automatically reconnected
+	// to the RPC server, re-registered the notifications it was registered
+	// for and resent the requests which had not completed.  Notifications
+	// sent by the server while the client was disconnected are lost, so
+	// this is a good callsite to catch up with the state of the server.
+	// This callback is run async with the rest of the notification
+	// handlers, and is safe for blocking client requests.
+	OnReconnect func()
+
 	// OnBlockConnected is invoked when a block is connected to the longest
 	// (best) chain.  It will only be invoked if a preceding call to
 	// NotifyBlocks has been made to register for the notification and the
@@ -144,6 +171,13 @@ type NotificationHandlers struct {
 	// Deprecated: Use OnRelevantTxAccepted instead.
 	OnRedeemingTx func(transaction *ltcutil.Tx, details *btcjson.BlockDetails)
 
+	// OnOperationProgress is invoked when an operation started by a
+	// StartOperation call has made progress and once it has finished, in
+	// which case the state is no longer "running".
+	//
+	// NOTE: This is a ltcd extension.
+	OnOperationProgress func(ntfn *btcjson.OperationProgressNtfn)
+
 	// OnRelevantTxAccepted is invoked when an unmined transaction passes
 	// the client's transaction filter.
 	//
@@ -151,6 +185,12 @@ type NotificationHandlers struct {
 	// github.com/decred/dcrrpcclient.
 	OnRelevantTxAccepted func(transaction []byte)
 
+	// OnRescanBlockchainMatch is invoked for each transaction found by a
+	// RescanBlockchain call in progress, before the call returns.
+	//
+	// NOTE: This is a ltcd extension.
+	OnRescanBlockchainMatch func(match *btcjson.RescanBlockchainMatch)
+
 	// OnRescanFinished is invoked after a rescan finishes due to a previous
 	// call to Rescan or RescanEndHeight.  Finished rescans should be
 	// signaled on this notification, rather than relying on the return
@@ -179,6 +219,13 @@ type NotificationHandlers struct {
 	// made to register for the notification and the function is non-nil.
 	OnTxAcceptedVerbose func(txDetails *btcjson.TxRawResult)
 
+	// OnTxRemoved is invoked when a transaction is removed from the memory
+	// pool along with the reason for its removal, such as "block",
+	// "conflict" or "replaced".  It will only be invoked if a preceding
+	// call to NotifyNewTransactions has been made to register for the
+	// notification and the function is non-nil.
+	OnTxRemoved func(hash *chainhash.Hash, reason string)
+
 	// OnBtcdConnected is invoked when a wallet connects or disconnects from
 	// ltcd.
 	//
@@ -341,6 +388,40 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		c.ntfnHandlers.OnRelevantTxAccepted(transaction)
 
+	// OnOperationProgress
+	case btcjson.OperationProgressNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnOperationProgress == nil {
+			return
+		}
+
+		ntfn, err := parseOperationProgressParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid operationprogress "+
+				"notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnOperationProgress(ntfn)
+
+	// OnRescanBlockchainMatch
+	case btcjson.RescanBlockchainMatchNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnRescanBlockchainMatch == nil {
+			return
+		}
+
+		match, err := parseRescanBlockchainMatchParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid rescanblockchainmatch "+
+				"notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnRescanBlockchainMatch(match)
+
 	// OnRescanFinished
 	case btcjson.RescanFinishedNtfnMethod:
 		// Ignore the notification if the client is not interested in
@@ -360,12 +441,6 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 	// OnRescanProgress
 	case btcjson.RescanProgressNtfnMethod:
-		// Ignore the notification if the client is not interested in
-		// it.
-		if c.ntfnHandlers.OnRescanProgress == nil {
-			return
-		}
-
 		hash, height, blkTime, err := parseRescanProgressParams(ntfn.Params)
 		if err != nil {
 			log.Warnf("Received invalid rescanprogress "+
@@ -373,6 +448,18 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 			return
 		}
 
+		// Track the progress so the rescan can be resumed from it on
+		// reconnect.
+		c.ntfnStateLock.Lock()
+		c.ntfnState.rescanProgress = hash
+		c.ntfnStateLock.Unlock()
+
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnRescanProgress == nil {
+			return
+		}
+
 		c.ntfnHandlers.OnRescanProgress(hash, height, blkTime)
 
 	// OnTxAccepted
@@ -409,6 +496,23 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		c.ntfnHandlers.OnTxAcceptedVerbose(rawTx)
 
+	// OnTxRemoved
+	case btcjson.TxRemovedNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnTxRemoved == nil {
+			return
+		}
+
+		hash, reason, err := parseTxRemovedNtfnParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid tx removed "+
+				"notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnTxRemoved(hash, reason)
+
 	// OnBtcdConnected
 	case btcjson.BtcdConnectedNtfnMethod:
 		// Ignore the notification if the client is not interested in
@@ -678,6 +782,44 @@ func parseChainTxNtfnParams(params []json.RawMessage) (*ltcutil.Tx,
 	return ltcutil.NewTx(&msgTx), block, nil
 }
 
+// parseRescanBlockchainMatchParams parses out the transaction found by a
+// rescanblockchain command from the parameters of a rescanblockchainmatch
+// notification.
+func parseRescanBlockchainMatchParams(params []json.RawMessage) (*btcjson.RescanBlockchainMatch, error) {
+	if len(params) != 1 {
+		return nil, wrongNumParams(len(params))
+	}
+
+	var match btcjson.RescanBlockchainMatch
+	if err := json.Unmarshal(params[0], &match); err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// parseOperationProgressParams parses out the state of an operation from the
+// parameters of an operationprogress notification.
+func parseOperationProgressParams(params []json.RawMessage) (*btcjson.OperationProgressNtfn, error) {
+	if len(params) != 4 {
+		return nil, wrongNumParams(len(params))
+	}
+
+	var ntfn btcjson.OperationProgressNtfn
+	if err := json.Unmarshal(params[0], &ntfn.ID); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(params[1], &ntfn.State); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(params[2], &ntfn.Done); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(params[3], &ntfn.Total); err != nil {
+		return nil, err
+	}
+	return &ntfn, nil
+}
+
 // parseRescanProgressParams parses out the height of the last rescanned block
 // from the parameters of rescanfinished and rescanprogress notifications.
 func parseRescanProgressParams(params []json.RawMessage) (*chainhash.Hash, int32, time.Time, error) {
@@ -753,6 +895,38 @@ func parseTxAcceptedNtfnParams(params []json.RawMessage) (*chainhash.Hash,
 	return txHash, amt, nil
 }
 
+// parseTxRemovedNtfnParams parses out the transaction hash and the reason for
+// its removal from the parameters of a txremoved notification.
+func parseTxRemovedNtfnParams(params []json.RawMessage) (*chainhash.Hash,
+	string, error) {
+
+	if len(params) != 2 {
+		return nil, "", wrongNumParams(len(params))
+	}
+
+	// Unmarshal first parameter as a string.
+	var txHashStr string
+	err := json.Unmarshal(params[0], &txHashStr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Unmarshal second parameter as a string.
+	var reason string
+	err = json.Unmarshal(params[1], &reason)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Decode string encoding of transaction sha.
+	txHash, err := chainhash.NewHashFromStr(txHashStr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return txHash, reason, nil
+}
+
 // parseTxAcceptedVerboseNtfnParams parses out details about a raw transaction
 // from the parameters of a txacceptedverbose notification.
 func parseTxAcceptedVerboseNtfnParams(params []json.RawMessage) (*btcjson.TxRawResult,
@@ -1030,7 +1204,7 @@ func (c *Client) NotifyNewTransactionsAsync(verbose bool) FutureNotifyNewTransac
 //
 // The notifications delivered as a result of this call will be via one of
 // OnTxAccepted (when verbose is false) or OnTxAcceptedVerbose (when verbose is
-// true).
+// true), and OnTxRemoved when a transaction is removed from the memory pool.
 //
 // NOTE: This is a ltcd extension and requires a websocket connection.
 func (c *Client) NotifyNewTransactions(verbose bool) error {
@@ -1142,11 +1316,11 @@ func (r FutureRescanResult) Receive() error {
 //
 // See Rescan for the blocking version and more details.
 //
-// NOTE: Rescan requests are not issued on client reconnect and must be
-// performed manually (ideally with a new start height based on the last
-// rescan progress notification).  See the OnClientConnected notification
-// callback for a good callsite to reissue rescan requests on connect and
-// reconnect.
+// NOTE: A rescan which is interrupted by a disconnect is resumed on automatic
+// reconnect from the block of the last rescan progress notification, so the
+// notifications of the transactions of that block might be delivered twice.
+// When several rescans are underway at once, they are restarted from their
+// starting block instead.
 //
 // NOTE: This is a ltcd extension and requires a websocket connection.
 //
@@ -1207,11 +1381,11 @@ func (c *Client) RescanAsync(startBlock *chainhash.Hash,
 // See RescanEndBlock to also specify an ending block to finish the rescan
 // without continuing through the best block on the main chain.
 //
-// NOTE: Rescan requests are not issued on client reconnect and must be
-// performed manually (ideally with a new start height based on the last
-// rescan progress notification).  See the OnClientConnected notification
-// callback for a good callsite to reissue rescan requests on connect and
-// reconnect.
+// NOTE: A rescan which is interrupted by a disconnect is resumed on automatic
+// reconnect from the block of the last rescan progress notification, so the
+// notifications of the transactions of that block might be delivered twice.
+// When several rescans are underway at once, they are restarted from their
+// starting block instead.
 //
 // NOTE: This is a ltcd extension and requires a websocket connection.
 //
@@ -62,10 +62,11 @@ func (c *Client) RawRequestAsync(method string, params []json.RawMessage) Future
 		cmd:            nil,
 		marshalledJSON: marshalledJSON,
 		responseChan:   responseChan,
+		ctx:            c.ctx,
 	}
 	c.sendRequest(jReq)
 
-	return responseChan
+	return c.bindContext(jReq)
 }
 
 // RawRequest allows the caller to send a raw or custom request to the server.
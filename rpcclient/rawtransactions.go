@@ -120,6 +120,28 @@ func (c *Client) GetRawTransaction(txHash *chainhash.Hash) (*ltcutil.Tx, error)
 	return c.GetRawTransactionAsync(txHash).Receive()
 }
 
+// GetRawTransactionFromBlockAsync returns an instance of a type that can be
+// used to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetRawTransactionFromBlock for the blocking version and more details.
+func (c *Client) GetRawTransactionFromBlockAsync(txHash,
+	blockHash *chainhash.Hash) FutureGetRawTransactionResult {
+
+	cmd := btcjson.NewGetRawTransactionFromBlockCmd(txHash.String(), 0,
+		blockHash.String())
+	return c.SendCmd(cmd)
+}
+
+// GetRawTransactionFromBlock returns a transaction given its hash and the hash
+// of the block containing it, which doesn't require the transaction index of
+// the server.
+func (c *Client) GetRawTransactionFromBlock(txHash,
+	blockHash *chainhash.Hash) (*ltcutil.Tx, error) {
+
+	return c.GetRawTransactionFromBlockAsync(txHash, blockHash).Receive()
+}
+
 // FutureGetRawTransactionVerboseResult is a future promise to deliver the
 // result of a GetRawTransactionVerboseAsync RPC invocation (or an applicable
 // error).
@@ -166,6 +188,29 @@ func (c *Client) GetRawTransactionVerbose(txHash *chainhash.Hash) (*btcjson.TxRa
 	return c.GetRawTransactionVerboseAsync(txHash).Receive()
 }
 
+// GetRawTransactionVerboseFromBlockAsync returns an instance of a type that can
+// be used to get the result of the RPC at some future time by invoking the
+// Receive function on the returned instance.
+//
+// See GetRawTransactionVerboseFromBlock for the blocking version and more
+// details.
+func (c *Client) GetRawTransactionVerboseFromBlockAsync(txHash,
+	blockHash *chainhash.Hash) FutureGetRawTransactionVerboseResult {
+
+	cmd := btcjson.NewGetRawTransactionFromBlockCmd(txHash.String(), 1,
+		blockHash.String())
+	return c.SendCmd(cmd)
+}
+
+// GetRawTransactionVerboseFromBlock returns information about a transaction
+// given its hash and the hash of the block containing it, which doesn't
+// require the transaction index of the server.
+func (c *Client) GetRawTransactionVerboseFromBlock(txHash,
+	blockHash *chainhash.Hash) (*btcjson.TxRawResult, error) {
+
+	return c.GetRawTransactionVerboseFromBlockAsync(txHash, blockHash).Receive()
+}
+
 // FutureDecodeRawTransactionResult is a future promise to deliver the result
 // of a DecodeRawTransactionAsync RPC invocation (or an applicable error).
 type FutureDecodeRawTransactionResult chan *Response
@@ -582,6 +627,56 @@ func (c *Client) SignRawTransaction4(tx *wire.MsgTx,
 		hashType).Receive()
 }
 
+// SignRawTransactionWithKeyAsync returns an instance of a type that can be
+// used to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See SignRawTransactionWithKey for the blocking version and more details.
+func (c *Client) SignRawTransactionWithKeyAsync(tx *wire.MsgTx,
+	privKeysWIF []string, inputs []btcjson.RawTxWitnessInput,
+	hashType SigHashType) FutureSignRawTransactionResult {
+
+	txHex := ""
+	if tx != nil {
+		// Serialize the transaction and convert to hex string.
+		buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+		if err := tx.Serialize(buf); err != nil {
+			return newFutureError(err)
+		}
+		txHex = hex.EncodeToString(buf.Bytes())
+	}
+
+	var inputsParam *[]btcjson.RawTxWitnessInput
+	if inputs != nil {
+		inputsParam = &inputs
+	}
+	var hashTypeParam *string
+	if hashType != "" {
+		hashTypeParam = btcjson.String(string(hashType))
+	}
+
+	cmd := btcjson.NewSignRawTransactionWithKeyCmd(txHex, privKeysWIF,
+		inputsParam, hashTypeParam)
+	return c.SendCmd(cmd)
+}
+
+// SignRawTransactionWithKey signs inputs for the passed transaction using the
+// passed private keys, which must be in wallet import format (WIF), and
+// returns the signed transaction as well as whether or not all inputs are now
+// signed.
+//
+// The previous outputs only need to be specified for inputs spending outputs
+// which are not in the server's memory pool or unspent transaction output
+// set, or which require a redeem or witness script.  The default signature
+// hash type is used when hashType is empty.
+func (c *Client) SignRawTransactionWithKey(tx *wire.MsgTx,
+	privKeysWIF []string, inputs []btcjson.RawTxWitnessInput,
+	hashType SigHashType) (*wire.MsgTx, bool, error) {
+
+	return c.SignRawTransactionWithKeyAsync(tx, privKeysWIF, inputs,
+		hashType).Receive()
+}
+
 // FutureSignRawTransactionWithWalletResult is a future promise to deliver
 // the result of the SignRawTransactionWithWalletAsync RPC invocation (or
 // an applicable error).
@@ -882,3 +977,144 @@ func (c *Client) DecodeScriptAsync(serializedScript []byte) FutureDecodeScriptRe
 func (c *Client) DecodeScript(serializedScript []byte) (*btcjson.DecodeScriptResult, error) {
 	return c.DecodeScriptAsync(serializedScript).Receive()
 }
+
+// FutureDecodePsbtResult is a future promise to deliver the result of a
+// DecodePsbtAsync RPC invocation (or an applicable error).
+type FutureDecodePsbtResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the
+// decoded contents of the PSBT.
+func (r FutureDecodePsbtResult) Receive() (*btcjson.DecodePsbtResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a decodepsbt result object.
+	var decodePsbtResult btcjson.DecodePsbtResult
+	err = json.Unmarshal(res, &decodePsbtResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decodePsbtResult, nil
+}
+
+// DecodePsbtAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See DecodePsbt for the blocking version and more details.
+func (c *Client) DecodePsbtAsync(psbt string) FutureDecodePsbtResult {
+	cmd := btcjson.NewDecodePsbtCmd(psbt)
+	return c.SendCmd(cmd)
+}
+
+// DecodePsbt returns the decoded contents of the passed base64 encoded PSBT.
+func (c *Client) DecodePsbt(psbt string) (*btcjson.DecodePsbtResult, error) {
+	return c.DecodePsbtAsync(psbt).Receive()
+}
+
+// FuturePsbtResult is a future promise to deliver the result of an RPC
+// invocation which returns a base64 encoded PSBT (or an applicable error).
+type FuturePsbtResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the base64
+// encoded PSBT.
+func (r FuturePsbtResult) Receive() (string, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return "", err
+	}
+
+	// Unmarshal result as a string.
+	var psbt string
+	err = json.Unmarshal(res, &psbt)
+	if err != nil {
+		return "", err
+	}
+
+	return psbt, nil
+}
+
+// CombinePsbtAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See CombinePsbt for the blocking version and more details.
+func (c *Client) CombinePsbtAsync(psbts []string) FuturePsbtResult {
+	cmd := btcjson.NewCombinePsbtCmd(psbts)
+	return c.SendCmd(cmd)
+}
+
+// CombinePsbt combines the passed base64 encoded PSBTs for the same
+// transaction into a single PSBT.
+func (c *Client) CombinePsbt(psbts []string) (string, error) {
+	return c.CombinePsbtAsync(psbts).Receive()
+}
+
+// ConvertToPsbtAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See ConvertToPsbt for the blocking version and more details.
+func (c *Client) ConvertToPsbtAsync(tx *wire.MsgTx, permitSigData bool) FuturePsbtResult {
+	txHex := ""
+	if tx != nil {
+		// Serialize the transaction and convert to hex string.
+		buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+		if err := tx.Serialize(buf); err != nil {
+			return newFutureError(err)
+		}
+		txHex = hex.EncodeToString(buf.Bytes())
+	}
+
+	cmd := btcjson.NewConvertToPsbtCmd(txHex, &permitSigData, nil)
+	return c.SendCmd(cmd)
+}
+
+// ConvertToPsbt converts the passed network serialized transaction into a
+// base64 encoded PSBT.  Any signature data is discarded when permitSigData is
+// set, otherwise signed transactions are rejected.
+func (c *Client) ConvertToPsbt(tx *wire.MsgTx, permitSigData bool) (string, error) {
+	return c.ConvertToPsbtAsync(tx, permitSigData).Receive()
+}
+
+// FutureFinalizePsbtResult is a future promise to deliver the result of a
+// FinalizePsbtAsync RPC invocation (or an applicable error).
+type FutureFinalizePsbtResult chan *Response
+
+// Receive waits for the Response promised by the future and returns the
+// finalized PSBT or extracted transaction.
+func (r FutureFinalizePsbtResult) Receive() (*btcjson.FinalizePsbtResult, error) {
+	res, err := ReceiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a finalizepsbt result object.
+	var finalizePsbtResult btcjson.FinalizePsbtResult
+	err = json.Unmarshal(res, &finalizePsbtResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return &finalizePsbtResult, nil
+}
+
+// FinalizePsbtAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See FinalizePsbt for the blocking version and more details.
+func (c *Client) FinalizePsbtAsync(psbt string, extract bool) FutureFinalizePsbtResult {
+	cmd := btcjson.NewFinalizePsbtCmd(psbt, &extract)
+	return c.SendCmd(cmd)
+}
+
+// FinalizePsbt finalizes the inputs of the passed base64 encoded PSBT.  When
+// the PSBT is complete and extract is set, the network serialized transaction
+// is returned in the Hex field of the result.
+func (c *Client) FinalizePsbt(psbt string, extract bool) (*btcjson.FinalizePsbtResult, error) {
+	return c.FinalizePsbtAsync(psbt, extract).Receive()
+}
@@ -0,0 +1,402 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/btcjson"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/mining"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// getworkDataLen is the length of the data field of the getwork RPC.
+	// It consists of the serialized block header plus the internal sha256
+	// padding.  The internal sha256 padding consists of a single 1 bit
+	// followed by zeros and the length of the message in bits encoded as a
+	// big-endian uint64, which makes the data a multiple of the sha256
+	// block size of 64 bytes.
+	getworkDataLen = (1 + ((wire.MaxBlockHeaderPayload + 8) / 64)) * 64
+
+	// getworkHash1Len is the length of the hash1 field of the getwork RPC.
+	// It consists of a zero hash plus the internal sha256 padding.  See
+	// the getworkDataLen comment for details about the internal sha256
+	// padding format.
+	getworkHash1Len = (1 + ((chainhash.HashSize + 8) / 64)) * 64
+
+	// maxGetworkIssued is the maximum number of pieces of work handed out
+	// by the getwork RPC which are kept so they can be submitted.  The
+	// oldest work is forgotten first, which makes it stale.
+	maxGetworkIssued = 4096
+)
+
+// getworkIssued is the work handed out by the getwork RPC for a merkle root.
+// Only the header and the coinbase, which differs for every piece of work, are
+// kept since the other transactions are the ones of the template.
+type getworkIssued struct {
+	template *mining.BlockTemplate
+	header   wire.BlockHeader
+	coinbase *wire.MsgTx
+}
+
+// getworkState houses state that is used in between multiple RPC invocations
+// to getwork.
+type getworkState struct {
+	sync.Mutex
+	lastTxUpdate  time.Time
+	lastGenerated time.Time
+	prevHash      *chainhash.Hash
+	template      *mining.BlockTemplate
+	extraNonce    uint64
+
+	// issued houses the work handed out since the best block last changed
+	// keyed by its merkle root, which is unique to every piece of work
+	// due to the extra nonce in the coinbase.  issuedOrder holds the merkle
+	// roots from the oldest to the newest work so no more than
+	// maxGetworkIssued pieces of work are kept.
+	issued      map[chainhash.Hash]*getworkIssued
+	issuedOrder []chainhash.Hash
+}
+
+// newGetworkState returns a new instance of a getworkState with all internal
+// fields initialized and ready to use.
+func newGetworkState() *getworkState {
+	return &getworkState{
+		issued: make(map[chainhash.Hash]*getworkIssued),
+	}
+}
+
+// addIssued records the passed work handed out for the passed merkle root and
+// forgets the oldest work beyond maxGetworkIssued.
+//
+// This function MUST be called with the state locked.
+func (state *getworkState) addIssued(merkleRoot chainhash.Hash, issued *getworkIssued) {
+	state.issued[merkleRoot] = issued
+	state.issuedOrder = append(state.issuedOrder, merkleRoot)
+	for len(state.issuedOrder) > maxGetworkIssued {
+		delete(state.issued, state.issuedOrder[0])
+		state.issuedOrder = state.issuedOrder[1:]
+	}
+}
+
+// reverseUint32Array treats the passed bytes as a series of uint32s and
+// reverses the byte order of each uint32.  The passed byte slice must be a
+// multiple of 4 for a correct result.  The passed bytes slice is modified.
+func reverseUint32Array(b []byte) {
+	blen := len(b)
+	for i := 0; i < blen; i += 4 {
+		b[i], b[i+3] = b[i+3], b[i]
+		b[i+1], b[i+2] = b[i+2], b[i+1]
+	}
+}
+
+// handleGetWork implements the getwork command.  It serves legacy mining
+// proxies which cannot speak getblocktemplate or stratum and is only available
+// when enabled with --getwork.
+func handleGetWork(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	if !cfg.GetWork {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCMethodNotFound.Code,
+			Message: "The getwork command is disabled, enable it " +
+				"with --getwork",
+		}
+	}
+
+	// Respond with an error if there are no addresses to pay the created
+	// blocks to.
+	if len(cfg.miningAddrs) == 0 {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInternal.Code,
+			Message: "No payment addresses specified via " +
+				"--miningaddr",
+		}
+	}
+
+	// Return an error if there are no peers connected since there is no
+	// way to relay a found block or receive transactions to work on.
+	// However, allow this state when running in the regression test or
+	// simulation test mode.
+	if !(cfg.RegressionTest || cfg.SimNet) &&
+		s.cfg.ConnMgr.ConnectedCount() == 0 {
+
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCClientNotConnected,
+			Message: "Litecoin is not connected",
+		}
+	}
+
+	// No point in generating or accepting work before the chain is synced.
+	currentHeight := s.cfg.Chain.BestSnapshot().Height
+	if currentHeight != 0 && !s.cfg.SyncMgr.IsCurrent() {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCClientInInitialDownload,
+			Message: "Litecoin is downloading blocks...",
+		}
+	}
+
+	c := cmd.(*btcjson.GetWorkCmd)
+	if c.Data != nil {
+		return handleGetWorkSubmission(s, *c.Data)
+	}
+	return handleGetWorkRequest(s)
+}
+
+// handleGetWorkRequest is a helper for handleGetWork which deals with
+// generating and returning work to the caller.
+func handleGetWorkRequest(s *rpcServer) (interface{}, error) {
+	state := s.getworkState
+	state.Lock()
+	defer state.Unlock()
+
+	generator := s.cfg.Generator
+	lastTxUpdate := generator.TxSource().LastUpdated()
+	if lastTxUpdate.IsZero() {
+		lastTxUpdate = time.Now()
+	}
+
+	// Generate a new block template when the current best block has
+	// changed or the transactions in the memory pool have been updated and
+	// it has been at least gbtRegenerateSeconds since the last template was
+	// generated.  The work issued for previous templates remains valid
+	// until the best block changes.
+	latestHash := &s.cfg.Chain.BestSnapshot().Hash
+	tipChanged := state.template == nil || state.prevHash == nil ||
+		!state.prevHash.IsEqual(latestHash)
+	if tipChanged || (state.lastTxUpdate != lastTxUpdate &&
+		time.Now().After(state.lastGenerated.Add(time.Second*
+			gbtRegenerateSeconds))) {
+
+		// Reset the previous best hash the block template was
+		// generated against so any errors below cause the next
+		// invocation to try again.
+		state.prevHash = nil
+
+		payAddr := cfg.miningAddrs[rand.Intn(len(cfg.miningAddrs))]
+		template, err := generator.NewBlockTemplate(payAddr)
+		if err != nil {
+			return nil, internalRPCError("Failed to create new block "+
+				"template: "+err.Error(), "")
+		}
+
+		// Forget the work issued for the previous best block since it
+		// can no longer extend the main chain.
+		if tipChanged {
+			state.issued = make(map[chainhash.Hash]*getworkIssued)
+			state.issuedOrder = nil
+		}
+
+		state.template = template
+		state.lastGenerated = time.Now()
+		state.lastTxUpdate = lastTxUpdate
+		state.prevHash = latestHash
+
+		rpcsLog.Debugf("Generated block template for getwork "+
+			"(timestamp %v, merkle root %s)",
+			template.Block.Header.Timestamp,
+			template.Block.Header.MerkleRoot)
+	}
+
+	// Hand out a unique piece of work by updating the extra nonce in a copy
+	// of the coinbase of the template along with the block time.
+	template := state.template
+	msgBlock := *template.Block
+	msgBlock.Transactions = make([]*wire.MsgTx, len(template.Block.Transactions))
+	copy(msgBlock.Transactions, template.Block.Transactions)
+	msgBlock.Transactions[0] = template.Block.Transactions[0].Copy()
+	state.extraNonce++
+	err := generator.UpdateExtraNonce(&msgBlock, template.Height,
+		state.extraNonce)
+	if err != nil {
+		return nil, internalRPCError("Failed to update extra nonce: "+
+			err.Error(), "")
+	}
+	if err := generator.UpdateBlockTime(&msgBlock); err != nil {
+		return nil, internalRPCError("Failed to update block time: "+
+			err.Error(), "")
+	}
+	state.addIssued(msgBlock.Header.MerkleRoot, &getworkIssued{
+		template: template,
+		header:   msgBlock.Header,
+		coinbase: msgBlock.Transactions[0],
+	})
+
+	// Serialize the block header into a buffer large enough to hold the
+	// block header and the internal sha256 padding that is added and
+	// returned as part of the data below.
+	data := make([]byte, 0, getworkDataLen)
+	buf := bytes.NewBuffer(data)
+	if err := msgBlock.Header.Serialize(buf); err != nil {
+		return nil, internalRPCError("Failed to serialize data: "+
+			err.Error(), "")
+	}
+
+	// Calculate the midstate for the block header.  The midstate here is
+	// the internal state of the sha256 algorithm for the first chunk of
+	// the block header (sha256 operates on 64-byte chunks) which is before
+	// the nonce.  Scrypt does not make use of it, but it is required by
+	// the proxies which speak the original protocol.
+	data = data[:buf.Len()]
+	hasher := sha256.New()
+	hasher.Write(data[:sha256.BlockSize])
+	hashState, err := hasher.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, internalRPCError("Failed to calculate midstate: "+
+			err.Error(), "")
+	}
+	var midstate [sha256.Size]byte
+	copy(midstate[:], hashState[len("sha\x03"):])
+
+	// Expand the data slice to include the full data buffer and apply the
+	// internal sha256 padding.
+	data = data[:getworkDataLen]
+	data[wire.MaxBlockHeaderPayload] = 0x80
+	binary.BigEndian.PutUint64(data[len(data)-8:],
+		wire.MaxBlockHeaderPayload*8)
+
+	// Create the hash1 field which is a zero hash along with the internal
+	// sha256 padding as described above.  It is unused, but required for
+	// compatibility with the reference implementation.
+	var hash1 [getworkHash1Len]byte
+	hash1[chainhash.HashSize] = 0x80
+	binary.BigEndian.PutUint64(hash1[len(hash1)-8:], chainhash.HashSize*8)
+
+	// The final result reverses each of the fields to little endian.  In
+	// particular, the data, hash1, and midstate fields are treated as
+	// arrays of uint32s (per the internal sha256 hashing state) which are
+	// in big endian, and thus each 4 bytes is byte swapped.  The target is
+	// also in big endian, but it is treated as a uint256 and byte swapped
+	// to little endian accordingly.
+	reverseUint32Array(data)
+	reverseUint32Array(hash1[:])
+	reverseUint32Array(midstate[:])
+	var target [chainhash.HashSize]byte
+	blockchain.CompactToBig(msgBlock.Header.Bits).FillBytes(target[:])
+	for i, j := 0, len(target)-1; i < j; i, j = i+1, j-1 {
+		target[i], target[j] = target[j], target[i]
+	}
+
+	return &btcjson.GetWorkResult{
+		Data:     hex.EncodeToString(data),
+		Hash1:    hex.EncodeToString(hash1[:]),
+		Midstate: hex.EncodeToString(midstate[:]),
+		Target:   hex.EncodeToString(target[:]),
+	}, nil
+}
+
+// handleGetWorkSubmission is a helper for handleGetWork which deals with the
+// caller submitting work to be verified and processed.
+func handleGetWorkSubmission(s *rpcServer, hexData string) (interface{}, error) {
+	// Ensure the provided data is sane.
+	if len(hexData)%2 != 0 {
+		hexData = "0" + hexData
+	}
+	data, err := hex.DecodeString(hexData)
+	if err != nil {
+		return false, rpcDecodeHexError(hexData)
+	}
+	if len(data) != getworkDataLen {
+		return false, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidParameter,
+			Message: "Argument must be 128 bytes, not " +
+				hexData,
+		}
+	}
+
+	// Reverse the data as if it were an array of 32-bit unsigned integers.
+	// The fact the getwork request and submission data is reversed in this
+	// way is rather odd and likely an artifact of some legacy internal
+	// state in the reference implementation, but it is required for
+	// compatibility.
+	reverseUint32Array(data)
+
+	// Deserialize the block header from the data.
+	var submittedHeader wire.BlockHeader
+	err = submittedHeader.Deserialize(bytes.NewReader(data))
+	if err != nil {
+		return false, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Invalid block header: " + err.Error(),
+		}
+	}
+
+	// Look up the work which was handed out for the merkle root of the
+	// submitted header.  Stale work is simply rejected.
+	state := s.getworkState
+	state.Lock()
+	issued, ok := state.issued[submittedHeader.MerkleRoot]
+	state.Unlock()
+	if !ok {
+		rpcsLog.Debugf("Block submitted via getwork has no matching "+
+			"work for merkle root %s", submittedHeader.MerkleRoot)
+		return false, nil
+	}
+
+	// Work for a previous best block can no longer extend the main chain.
+	best := s.cfg.Chain.BestSnapshot()
+	if !issued.header.PrevBlock.IsEqual(&best.Hash) {
+		rpcsLog.Debugf("Block submitted via getwork is stale: it "+
+			"builds on %s instead of the best block %s",
+			issued.header.PrevBlock, best.Hash)
+		return false, nil
+	}
+
+	// Reconstruct the block from the issued work using the timestamp and
+	// nonce of the submitted header.
+	msgBlock := wire.NewMsgBlock(&issued.header)
+	msgBlock.Header.Timestamp = submittedHeader.Timestamp
+	msgBlock.Header.Nonce = submittedHeader.Nonce
+	msgBlock.Transactions = make([]*wire.MsgTx, 0,
+		len(issued.template.Block.Transactions))
+	msgBlock.Transactions = append(msgBlock.Transactions, issued.coinbase)
+	msgBlock.Transactions = append(msgBlock.Transactions,
+		issued.template.Block.Transactions[1:]...)
+	block := ltcutil.NewBlock(msgBlock)
+
+	// Ensure the submitted block hash is less than the target difficulty.
+	err = blockchain.CheckProofOfWork(block, s.cfg.ChainParams.PowLimit)
+	if err != nil {
+		// Anything other than a rule violation is an unexpected error,
+		// so return that error as an internal error.
+		if _, ok := err.(blockchain.RuleError); !ok {
+			return false, internalRPCError("Unexpected error while "+
+				"checking proof of work: "+err.Error(), "")
+		}
+
+		rpcsLog.Debugf("Block submitted via getwork does not meet "+
+			"the required proof of work: %v", err)
+		return false, nil
+	}
+
+	// Process this block using the same rules as blocks coming from other
+	// nodes.  This will in turn relay it to the network like normal.
+	isOrphan, err := s.cfg.SyncMgr.SubmitBlock(block, blockchain.BFNone)
+	if err != nil || isOrphan {
+		// Anything other than a rule violation is an unexpected error,
+		// so return that error as an internal error.
+		if _, ok := err.(blockchain.RuleError); !ok && err != nil {
+			return false, internalRPCError("Unexpected error while "+
+				"processing block submitted via getwork: "+
+				err.Error(), "")
+		}
+
+		rpcsLog.Infof("Block submitted via getwork rejected: %v "+
+			"(orphan %v)", err, isOrphan)
+		return false, nil
+	}
+
+	rpcsLog.Infof("Accepted block %s via getwork", block.Hash())
+	return true, nil
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/btcjson"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// getworkTestSyncManager is a sync manager which is always current and
+// processes the submitted blocks directly with the chain.
+type getworkTestSyncManager struct {
+	rpcserverSyncManager
+	chain *blockchain.BlockChain
+}
+
+// IsCurrent returns true since the test chain is always current.
+func (m getworkTestSyncManager) IsCurrent() bool {
+	return true
+}
+
+// SubmitBlock processes the passed block with the chain.
+func (m getworkTestSyncManager) SubmitBlock(block *ltcutil.Block,
+	flags blockchain.BehaviorFlags) (bool, error) {
+
+	_, isOrphan, err := m.chain.ProcessBlock(block, flags)
+	return isOrphan, err
+}
+
+// newGetworkTestServer returns an RPC server serving the getwork command on a
+// new regression test chain.
+func newGetworkTestServer(t *testing.T) *rpcServer {
+	t.Helper()
+
+	s, _ := newTemplateTestServer(t)
+	s.cfg.SyncMgr = getworkTestSyncManager{chain: s.cfg.Chain}
+	s.getworkState = newGetworkState()
+	payAddr, err := ltcutil.NewAddressPubKeyHash(make([]byte, 20),
+		s.cfg.ChainParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	cfg.GetWork = true
+	cfg.RegressionTest = true
+	cfg.miningAddrs = []ltcutil.Address{payAddr}
+	return s
+}
+
+// getTestWork requests work with the getwork command and returns the header of
+// the block to solve along with the result.
+func getTestWork(t *testing.T, s *rpcServer) (*wire.BlockHeader, *btcjson.GetWorkResult) {
+	t.Helper()
+
+	result, err := handleGetWork(s, &btcjson.GetWorkCmd{}, nil)
+	if err != nil {
+		t.Fatalf("getwork: unexpected error: %v", err)
+	}
+	work := result.(*btcjson.GetWorkResult)
+	data, err := hex.DecodeString(work.Data)
+	if err != nil || len(data) != getworkDataLen {
+		t.Fatalf("getwork: invalid data %q", work.Data)
+	}
+	reverseUint32Array(data)
+	var header wire.BlockHeader
+	if err := header.Deserialize(bytes.NewReader(data)); err != nil {
+		t.Fatalf("getwork: unable to decode header: %v", err)
+	}
+	return &header, work
+}
+
+// submitTestWork solves the passed header and submits it with the getwork
+// command, returning whether the block was accepted.
+func submitTestWork(t *testing.T, s *rpcServer, header wire.BlockHeader) bool {
+	t.Helper()
+
+	target := blockchain.CompactToBig(header.Bits)
+	for header.Nonce = 0; ; header.Nonce++ {
+		hash := header.PowHash()
+		if blockchain.HashToBig(&hash).Cmp(target) <= 0 {
+			break
+		}
+	}
+	var buf bytes.Buffer
+	if err := header.Serialize(&buf); err != nil {
+		t.Fatalf("unable to serialize header: %v", err)
+	}
+	data := make([]byte, getworkDataLen)
+	copy(data, buf.Bytes())
+	reverseUint32Array(data)
+	hexData := hex.EncodeToString(data)
+	result, err := handleGetWork(s, &btcjson.GetWorkCmd{Data: &hexData},
+		nil)
+	if err != nil {
+		t.Fatalf("getwork submission: unexpected error: %v", err)
+	}
+	return result.(bool)
+}
+
+// TestGetWork ensures the getwork command hands out unique work building on
+// the best block which is accepted once solved, while work which is unknown,
+// stale or no longer kept is rejected.
+func TestGetWork(t *testing.T) {
+	s := newGetworkTestServer(t)
+
+	// Every piece of work builds on the best block and has a unique
+	// merkle root.
+	header, work := getTestWork(t, s)
+	best := s.cfg.Chain.BestSnapshot()
+	if !header.PrevBlock.IsEqual(&best.Hash) {
+		t.Fatalf("work builds on %v, want %v", header.PrevBlock,
+			best.Hash)
+	}
+	if len(work.Midstate) != 64 || len(work.Hash1) != getworkHash1Len*2 ||
+		len(work.Target) != 64 {
+
+		t.Fatalf("unexpected work %+v", work)
+	}
+	other, _ := getTestWork(t, s)
+	if other.MerkleRoot == header.MerkleRoot {
+		t.Fatal("work handed out twice with the same merkle root")
+	}
+
+	// Work which was not handed out is rejected.
+	unknown := *header
+	unknown.MerkleRoot[0] ^= 0xff
+	if submitTestWork(t, s, unknown) {
+		t.Fatal("accepted work which was not handed out")
+	}
+
+	// Solved work is accepted and extends the best block.
+	if !submitTestWork(t, s, *header) {
+		t.Fatal("solved work was not accepted")
+	}
+	best = s.cfg.Chain.BestSnapshot()
+	if best.Height != 1 {
+		t.Fatalf("best block height is %d, want 1", best.Height)
+	}
+
+	// The other work built on the previous best block is stale, and so is
+	// the work handed out before the best block changed again.
+	if submitTestWork(t, s, *other) {
+		t.Fatal("accepted work building on a previous best block")
+	}
+	stale, _ := getTestWork(t, s)
+	extendTestChain(t, s)
+	if submitTestWork(t, s, *stale) {
+		t.Fatal("accepted work handed out before the best block " +
+			"changed")
+	}
+
+	// New work builds on the new best block and forgets the work handed
+	// out for the previous one.
+	header, _ = getTestWork(t, s)
+	best = s.cfg.Chain.BestSnapshot()
+	if !header.PrevBlock.IsEqual(&best.Hash) {
+		t.Fatalf("work builds on %v after the best block changed, "+
+			"want %v", header.PrevBlock, best.Hash)
+	}
+	if _, ok := s.getworkState.issued[stale.MerkleRoot]; ok {
+		t.Fatal("work for the previous best block is still kept")
+	}
+}
+
+// TestGetWorkIssuedLimit ensures no more than maxGetworkIssued pieces of work
+// are kept, forgetting the oldest work first.
+func TestGetWorkIssuedLimit(t *testing.T) {
+	s := newGetworkTestServer(t)
+
+	oldest, _ := getTestWork(t, s)
+	for i := 0; i < maxGetworkIssued; i++ {
+		getTestWork(t, s)
+	}
+	state := s.getworkState
+	if len(state.issued) != maxGetworkIssued ||
+		len(state.issuedOrder) != maxGetworkIssued {
+
+		t.Fatalf("kept %d pieces of work, want %d", len(state.issued),
+			maxGetworkIssued)
+	}
+	if submitTestWork(t, s, *oldest) {
+		t.Fatal("accepted work which was forgotten")
+	}
+}
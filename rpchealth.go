@@ -0,0 +1,133 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ltcsuite/ltcd/database"
+)
+
+// healthReport describes the state of the node as reported by the /healthz and
+// /readyz endpoints of the RPC server.
+type healthReport struct {
+	Status           string   `json:"status"`
+	Database         bool     `json:"database"`
+	Peers            int      `json:"peers"`
+	Height           int32    `json:"height"`
+	TargetHeight     int32    `json:"targetheight"`
+	CheckpointHeight int32    `json:"checkpointheight"`
+	Progress         float64  `json:"progress"`
+	LastBlockAge     int64    `json:"lastblockage"`
+	Synced           bool     `json:"synced"`
+	Failures         []string `json:"failures,omitempty"`
+}
+
+// checkHealth gathers the state of the node and returns it along with whether
+// the node is healthy.  A node is healthy as long as its database is available.
+// When ready is true, the node must additionally be connected to peers and be
+// synced with the network in order to be reported as healthy, which means it is
+// ready to serve requests about the current state of the chain.
+//
+// Unlike the RPC commands, this avoids the sync manager so that the probes are
+// answered while it is busy processing blocks.
+func (s *rpcServer) checkHealth(ready bool) (*healthReport, bool) {
+	report := &healthReport{}
+
+	err := s.cfg.DB.View(func(dbTx database.Tx) error {
+		return nil
+	})
+	report.Database = err == nil
+	if err != nil {
+		report.Failures = append(report.Failures,
+			fmt.Sprintf("database unavailable: %v", err))
+	}
+
+	// The height of the chain being synced to is the height of the latest
+	// checkpoint until a peer announces a longer chain.
+	best := s.cfg.Chain.BestSnapshot()
+	report.Height = best.Height
+	if checkpoint := s.cfg.Chain.LatestCheckpoint(); checkpoint != nil {
+		report.CheckpointHeight = checkpoint.Height
+	}
+	report.TargetHeight = report.CheckpointHeight
+	peers := s.cfg.ConnMgr.ConnectedPeers()
+	report.Peers = len(peers)
+	for _, p := range peers {
+		if lastBlock := p.ToPeer().LastBlock(); lastBlock > report.TargetHeight {
+			report.TargetHeight = lastBlock
+		}
+	}
+	report.Progress = 1
+	if report.TargetHeight > report.Height {
+		report.Progress = float64(report.Height) /
+			float64(report.TargetHeight)
+	}
+
+	header, err := s.cfg.Chain.HeaderByHash(&best.Hash)
+	if err == nil {
+		now := s.cfg.TimeSource.AdjustedTime()
+		report.LastBlockAge = int64(now.Sub(header.Timestamp) / time.Second)
+	}
+	report.Synced = s.cfg.Chain.IsCurrent()
+
+	if ready {
+		if report.Peers == 0 {
+			report.Failures = append(report.Failures,
+				"not connected to any peers")
+		}
+		if !report.Synced {
+			report.Failures = append(report.Failures,
+				fmt.Sprintf("not synced: height %d of %d, last "+
+					"block %ds old", report.Height,
+					report.TargetHeight, report.LastBlockAge))
+		}
+	}
+
+	healthy := len(report.Failures) == 0
+	report.Status = "ok"
+	if !healthy {
+		report.Status = "unavailable"
+	}
+	return report, healthy
+}
+
+// healthHandler returns the handler of the /healthz endpoint, or of the /readyz
+// endpoint when ready is true.  These endpoints don't require authentication so
+// they can be used by liveness and readiness probes.  They respond with the
+// health report and a status of 200 when the node is healthy, and a status of
+// 503 otherwise.
+func (s *rpcServer) healthHandler(ready bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Connection", "close")
+		r.Close = true
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "405 Method not allowed.",
+				http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Limit the number of connections to max allowed.
+		if s.limitConnections(w, r.RemoteAddr) {
+			return
+		}
+		s.incrementClients()
+		defer s.decrementClients()
+
+		report, healthy := s.checkHealth(ready)
+		w.Header().Set("Content-Type", "application/json")
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			rpcsLog.Errorf("Failed to write health report: %v", err)
+		}
+	}
+}
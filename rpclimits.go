@@ -0,0 +1,132 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ltcsuite/ltcd/btcjson"
+)
+
+// maxRateLimitBuckets is the number of client hosts the rate limiter tracks
+// before it starts forgetting the hosts which haven't sent requests recently.
+const maxRateLimitBuckets = 1000
+
+// rpcLimitExempt houses the commands which are not subject to the global cap
+// on concurrent requests so that mining can't be starved by other clients.
+var rpcLimitExempt = map[string]struct{}{
+	"getblocktemplate": {},
+	"submitblock":      {},
+}
+
+// tokenBucket tracks the requests of a single client host.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// rpcRateLimiter limits the rate of the requests of each client host with a
+// token bucket which holds up to burst tokens and is refilled with rate tokens
+// per second.  Each request consumes a token and is rejected when the bucket is
+// empty.
+type rpcRateLimiter struct {
+	rate    float64
+	burst   float64
+	mtx     sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// newRPCRateLimiter returns a rate limiter which allows rate requests per
+// second with bursts of up to burst requests for each client host.
+func newRPCRateLimiter(rate float64, burst int) *rpcRateLimiter {
+	return &rpcRateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// allow returns whether a request from the passed client host received at the
+// passed time is allowed and consumes a token from its bucket when it is.
+//
+// This function is safe for concurrent access.
+func (l *rpcRateLimiter) allow(client string, now time.Time) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	bucket, ok := l.buckets[client]
+	if !ok {
+		// Forget the hosts whose bucket is full again, which are
+		// indistinguishable from hosts which never sent a request.
+		if len(l.buckets) >= maxRateLimitBuckets {
+			for host, b := range l.buckets {
+				elapsed := now.Sub(b.updated).Seconds()
+				if b.tokens+elapsed*l.rate >= l.burst {
+					delete(l.buckets, host)
+				}
+			}
+		}
+		bucket = &tokenBucket{tokens: l.burst, updated: now}
+		l.buckets[client] = bucket
+	}
+
+	if elapsed := now.Sub(bucket.updated).Seconds(); elapsed > 0 {
+		bucket.tokens = math.Min(l.burst, bucket.tokens+elapsed*l.rate)
+		bucket.updated = now
+	}
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// clientHost returns the host of the passed remote address which identifies
+// the client for the purposes of rate limiting.
+func clientHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// limitRequest enforces the rate limit of the passed client host and the global
+// cap on concurrent requests for a request of the passed method.  It returns an
+// error when the request must be rejected.  Otherwise, it returns a function
+// which must be called once the request has been processed.
+//
+// This function is safe for concurrent access.
+func (s *rpcServer) limitRequest(client, method string) (func(), *btcjson.RPCError) {
+	if s.rateLimiter != nil && !s.rateLimiter.allow(client, time.Now()) {
+		atomic.AddUint64(&s.numRateLimited, 1)
+		rpcsLog.Debugf("Rejected <%s> command from %s: rate limit "+
+			"exceeded", method, client)
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCLimitExceeded,
+			Message: "Rate limit exceeded",
+		}
+	}
+
+	if _, ok := rpcLimitExempt[method]; ok || s.requestSem == nil {
+		return func() {}, nil
+	}
+	select {
+	case s.requestSem <- struct{}{}:
+		return s.requestSem.release, nil
+	default:
+		atomic.AddUint64(&s.numConcurrencyLimited, 1)
+		rpcsLog.Debugf("Rejected <%s> command from %s: too many "+
+			"concurrent requests", method, client)
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCLimitExceeded,
+			Message: "Too many concurrent requests",
+		}
+	}
+}
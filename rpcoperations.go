@@ -0,0 +1,363 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/btcjson"
+)
+
+const (
+	// maxRunningOperations is the maximum number of operations started by
+	// the startoperation command which may run at the same time.
+	maxRunningOperations = 4
+
+	// maxFinishedOperations is the maximum number of finished operations
+	// whose status is kept for the getoperationstatus command.  The oldest
+	// finished operations are forgotten first.
+	maxFinishedOperations = 32
+
+	// operationNtfnInterval is the minimum amount of time between the
+	// progress notifications sent to the websocket client which started an
+	// operation.
+	operationNtfnInterval = time.Second
+)
+
+// These constants define the states of an operation as reported by the
+// getoperationstatus command and the operationprogress notification.
+const (
+	operationRunning   = "running"
+	operationCompleted = "completed"
+	operationFailed    = "failed"
+	operationCancelled = "cancelled"
+)
+
+// operationHandler is the type of the functions which run the commands that
+// can be started as operations.  They must stop once the quit channel is
+// closed and report their progress to the passed function.
+type operationHandler func(*rpcServer, interface{}, <-chan struct{}, blockchain.ProgressFunc) (interface{}, error)
+
+// rpcOperationHandlers maps the commands which can be run in the background by
+// the startoperation command to their handler functions.
+var rpcOperationHandlers = map[string]operationHandler{
+	"gettxoutsetinfo":  getTxOutSetInfo,
+	"rescanblockchain": rescanBlockchainOperation,
+	"verifychain":      verifyChain,
+}
+
+// rescanBlockchainOperation runs the rescanblockchain command as an operation.
+func rescanBlockchainOperation(s *rpcServer, cmd interface{}, quit <-chan struct{},
+	progress blockchain.ProgressFunc) (interface{}, error) {
+
+	c := cmd.(*btcjson.RescanBlockchainCmd)
+	return rescanBlockchain(s, c, quit, nil, progress)
+}
+
+// rpcOperation houses the state of a command run in the background by the
+// startoperation command.
+type rpcOperation struct {
+	id      string
+	method  string
+	started time.Time
+
+	// quit is closed to cancel the operation and finished is closed once
+	// the handler of the operation has returned.
+	quit       chan struct{}
+	cancelOnce sync.Once
+	finished   chan struct{}
+
+	// notify is called with the progress notifications of the operation.
+	// It is nil unless the operation was started by a websocket client.
+	notify func(*btcjson.OperationProgressNtfn)
+
+	mtx      sync.Mutex
+	state    string
+	done     int64
+	total    int64
+	ended    time.Time
+	lastNtfn time.Time
+	result   interface{}
+	err      *btcjson.RPCError
+}
+
+// cancel requests the operation to stop.  It is safe to call it more than once.
+func (op *rpcOperation) cancel() {
+	op.cancelOnce.Do(func() {
+		close(op.quit)
+	})
+}
+
+// cancelled returns whether the operation was requested to stop.
+func (op *rpcOperation) cancelled() bool {
+	select {
+	case <-op.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// setProgress records the progress of the operation and notifies the websocket
+// client which started it, at most once per operationNtfnInterval.
+//
+// This function is safe for concurrent access.
+func (op *rpcOperation) setProgress(done, total int64) {
+	now := time.Now()
+	op.mtx.Lock()
+	op.done, op.total = done, total
+	notify := op.notify != nil && now.Sub(op.lastNtfn) >= operationNtfnInterval
+	if notify {
+		op.lastNtfn = now
+	}
+	op.mtx.Unlock()
+
+	if notify {
+		op.notify(btcjson.NewOperationProgressNtfn(op.id,
+			operationRunning, done, total))
+	}
+}
+
+// finish records the outcome of the operation once its handler returned and
+// notifies the websocket client which started it.
+//
+// This function is safe for concurrent access.
+func (op *rpcOperation) finish(result interface{}, err error) {
+	op.mtx.Lock()
+	op.ended = time.Now()
+	switch {
+	case op.cancelled():
+		op.state = operationCancelled
+		op.err = &btcjson.RPCError{
+			Code:    btcjson.ErrRPCMisc,
+			Message: "Operation cancelled",
+		}
+
+	case err != nil:
+		op.state = operationFailed
+		if jsonErr, ok := err.(*btcjson.RPCError); ok {
+			op.err = jsonErr
+		} else {
+			op.err = internalRPCError(err.Error(), "")
+		}
+
+	default:
+		op.state = operationCompleted
+		op.result = result
+	}
+	state, done, total := op.state, op.done, op.total
+	op.mtx.Unlock()
+
+	if op.notify != nil {
+		op.notify(btcjson.NewOperationProgressNtfn(op.id, state, done,
+			total))
+	}
+}
+
+// status returns the state of the operation as reported by the
+// getoperationstatus command.
+//
+// This function is safe for concurrent access.
+func (op *rpcOperation) status() *btcjson.OperationStatusResult {
+	op.mtx.Lock()
+	defer op.mtx.Unlock()
+
+	ended := op.ended
+	if ended.IsZero() {
+		ended = time.Now()
+	}
+	var progress float64
+	if op.total > 0 {
+		progress = float64(op.done) / float64(op.total)
+	}
+	return &btcjson.OperationStatusResult{
+		ID:        op.id,
+		Method:    op.method,
+		State:     op.state,
+		Done:      op.done,
+		Total:     op.total,
+		Progress:  progress,
+		StartTime: op.started.Unix(),
+		Elapsed:   int64(ended.Sub(op.started) / time.Second),
+		Result:    op.result,
+		Error:     op.err,
+	}
+}
+
+// operationManager keeps track of the operations started by the startoperation
+// command.
+type operationManager struct {
+	mtx        sync.Mutex
+	nextID     uint64
+	running    int
+	operations map[string]*rpcOperation
+	finished   []*rpcOperation
+}
+
+// newOperationManager returns a new operation manager with no operations.
+func newOperationManager() *operationManager {
+	return &operationManager{
+		nextID:     1,
+		operations: make(map[string]*rpcOperation),
+	}
+}
+
+// add registers a new running operation for the passed method.  An error is
+// returned when the maximum number of operations are already running.
+//
+// This function is safe for concurrent access.
+func (m *operationManager) add(method string,
+	notify func(*btcjson.OperationProgressNtfn)) (*rpcOperation, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.running >= maxRunningOperations {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCMisc,
+			Message: fmt.Sprintf("Too many operations are running "+
+				"(max %d)", maxRunningOperations),
+		}
+	}
+
+	op := &rpcOperation{
+		id:       strconv.FormatUint(m.nextID, 10),
+		method:   method,
+		started:  time.Now(),
+		quit:     make(chan struct{}),
+		finished: make(chan struct{}),
+		notify:   notify,
+		state:    operationRunning,
+	}
+	m.nextID++
+	m.running++
+	m.operations[op.id] = op
+	return op, nil
+}
+
+// remove marks the passed operation as finished and forgets the oldest
+// finished operations beyond maxFinishedOperations.
+//
+// This function is safe for concurrent access.
+func (m *operationManager) remove(op *rpcOperation) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.running--
+	m.finished = append(m.finished, op)
+	for len(m.finished) > maxFinishedOperations {
+		delete(m.operations, m.finished[0].id)
+		m.finished[0] = nil
+		m.finished = m.finished[1:]
+	}
+}
+
+// lookup returns the operation with the passed id, or nil when it is unknown.
+//
+// This function is safe for concurrent access.
+func (m *operationManager) lookup(id string) *rpcOperation {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.operations[id]
+}
+
+// startOperation implements the startoperation command for both HTTP and
+// websocket clients.  The command is parsed and then run in the background,
+// returning the id of the operation right away.  The progress notifications of
+// the operation are passed to notify when it is not nil.
+func startOperation(s *rpcServer, c *btcjson.StartOperationCmd,
+	notify func(*btcjson.OperationProgressNtfn)) (interface{}, error) {
+
+	handler, ok := rpcOperationHandlers[c.Method]
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidParameter,
+			Message: fmt.Sprintf("Command %q cannot be run as an "+
+				"operation", c.Method),
+		}
+	}
+
+	var params []json.RawMessage
+	if c.Params != nil {
+		params = *c.Params
+	}
+	parsedCmd := parseCmd(&btcjson.Request{
+		Jsonrpc: btcjson.RpcVersion1,
+		Method:  c.Method,
+		Params:  params,
+	})
+	if parsedCmd.err != nil {
+		return nil, parsedCmd.err
+	}
+
+	op, err := s.operations.add(c.Method, notify)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cancel the operation when the server shuts down.
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		select {
+		case <-s.quit:
+			op.cancel()
+		case <-op.finished:
+		}
+	}()
+	go func() {
+		defer s.wg.Done()
+		result, err := handler(s, parsedCmd.cmd, op.quit, op.setProgress)
+		op.finish(result, err)
+		s.operations.remove(op)
+		close(op.finished)
+	}()
+
+	rpcsLog.Debugf("Started operation %s running %s", op.id, c.Method)
+	return op.id, nil
+}
+
+// handleStartOperation implements the startoperation command.
+func handleStartOperation(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.StartOperationCmd)
+	return startOperation(s, c, nil)
+}
+
+// handleGetOperationStatus implements the getoperationstatus command.
+func handleGetOperationStatus(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetOperationStatusCmd)
+	op := s.operations.lookup(c.ID)
+	if op == nil {
+		return nil, rpcUnknownOperationError(c.ID)
+	}
+	return op.status(), nil
+}
+
+// handleCancelOperation implements the canceloperation command.  The operation
+// stops asynchronously, so the returned status may still report it as running.
+func handleCancelOperation(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.CancelOperationCmd)
+	op := s.operations.lookup(c.ID)
+	if op == nil {
+		return nil, rpcUnknownOperationError(c.ID)
+	}
+	op.cancel()
+	return op.status(), nil
+}
+
+// rpcUnknownOperationError is a convenience function for returning an RPC
+// error which indicates there is no operation with the passed id.
+func rpcUnknownOperationError(id string) *btcjson.RPCError {
+	return &btcjson.RPCError{
+		Code:    btcjson.ErrRPCInvalidParameter,
+		Message: fmt.Sprintf("Unknown operation %q", id),
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/btcjson"
+)
+
+// TestOperations ensures operations run in the background, report their
+// progress and outcome, and can be cancelled.
+func TestOperations(t *testing.T) {
+	// Replace the handler of verifychain with one which completes on
+	// request, or blocks until it is cancelled when completing is nil.
+	complete := make(chan interface{})
+	origHandler := rpcOperationHandlers["verifychain"]
+	rpcOperationHandlers["verifychain"] = func(s *rpcServer, cmd interface{},
+		quit <-chan struct{}, progress blockchain.ProgressFunc) (interface{}, error) {
+
+		progress(1, 2)
+		select {
+		case result := <-complete:
+			progress(2, 2)
+			return result, nil
+		case <-quit:
+			return false, nil
+		}
+	}
+	defer func() {
+		rpcOperationHandlers["verifychain"] = origHandler
+	}()
+
+	s := &rpcServer{
+		quit:       make(chan int),
+		operations: newOperationManager(),
+	}
+	var ntfnsMtx sync.Mutex
+	var ntfns []*btcjson.OperationProgressNtfn
+	notify := func(n *btcjson.OperationProgressNtfn) {
+		ntfnsMtx.Lock()
+		ntfns = append(ntfns, n)
+		ntfnsMtx.Unlock()
+	}
+	start := func() *rpcOperation {
+		t.Helper()
+		cmd := &btcjson.StartOperationCmd{Method: "verifychain"}
+		id, err := startOperation(s, cmd, notify)
+		if err != nil {
+			t.Fatalf("startOperation: unexpected error: %v", err)
+		}
+		return s.operations.lookup(id.(string))
+	}
+	status := func(op *rpcOperation) *btcjson.OperationStatusResult {
+		t.Helper()
+		cmd := &btcjson.GetOperationStatusCmd{ID: op.id}
+		result, err := handleGetOperationStatus(s, cmd, nil)
+		if err != nil {
+			t.Fatalf("getoperationstatus: unexpected error: %v", err)
+		}
+		return result.(*btcjson.OperationStatusResult)
+	}
+
+	// A completed operation reports its result along with its progress.
+	op := start()
+	complete <- true
+	<-op.finished
+	got := status(op)
+	if got.State != operationCompleted || got.Result != true ||
+		got.Error != nil || got.Progress != 1 || got.Method != "verifychain" {
+
+		t.Fatalf("unexpected status of completed operation: %+v", got)
+	}
+
+	// A cancelled operation reports an error instead of its result.
+	op = start()
+	cancelCmd := &btcjson.CancelOperationCmd{ID: op.id}
+	if _, err := handleCancelOperation(s, cancelCmd, nil); err != nil {
+		t.Fatalf("canceloperation: unexpected error: %v", err)
+	}
+	<-op.finished
+	got = status(op)
+	if got.State != operationCancelled || got.Result != nil ||
+		got.Error == nil {
+
+		t.Fatalf("unexpected status of cancelled operation: %+v", got)
+	}
+
+	// The notifications end with the final state of each operation.
+	ntfnsMtx.Lock()
+	if len(ntfns) != 4 || ntfns[1].State != operationCompleted ||
+		ntfns[3].State != operationCancelled || ntfns[3].ID != op.id {
+
+		t.Errorf("unexpected notifications: %v", ntfns)
+	}
+	ntfnsMtx.Unlock()
+
+	// Only a limited number of operations may run at once, and all of them
+	// are cancelled once the server shuts down.
+	running := make([]*rpcOperation, 0, maxRunningOperations)
+	for i := 0; i < maxRunningOperations; i++ {
+		running = append(running, start())
+	}
+	cmd := &btcjson.StartOperationCmd{Method: "verifychain"}
+	if _, err := startOperation(s, cmd, nil); err == nil {
+		t.Fatal("startOperation: expected error beyond the limit")
+	}
+	close(s.quit)
+	for _, op := range running {
+		<-op.finished
+		if got := status(op); got.State != operationCancelled {
+			t.Errorf("operation %s: got state %s after shutdown",
+				op.id, got.State)
+		}
+	}
+	s.wg.Wait()
+
+	// Commands which cannot run as operations and unknown operations are
+	// rejected.
+	cmd = &btcjson.StartOperationCmd{Method: "getblockcount"}
+	if _, err := startOperation(s, cmd, nil); err == nil {
+		t.Error("startOperation: expected error for getblockcount")
+	}
+	statusCmd := &btcjson.GetOperationStatusCmd{ID: "unknown"}
+	if _, err := handleGetOperationStatus(s, statusCmd, nil); err == nil {
+		t.Error("getoperationstatus: expected error for unknown operation")
+	}
+}
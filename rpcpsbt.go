@@ -0,0 +1,381 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ltcsuite/ltcd/btcjson"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/psbt"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// decodePsbtParam decodes the passed base64 encoded PSBT parameter of a PSBT
+// command.
+func decodePsbtParam(b64 string) (*psbt.Packet, error) {
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(b64), true)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDeserialization,
+			Message: "TX decode failed " + err.Error(),
+		}
+	}
+	return packet, nil
+}
+
+// encodePsbtResult returns the base64 encoding of the passed PSBT for the
+// result of a PSBT command.
+func encodePsbtResult(packet *psbt.Packet) (string, error) {
+	b64, err := packet.B64Encode()
+	if err != nil {
+		context := "Failed to encode PSBT"
+		return "", internalRPCError(err.Error(), context)
+	}
+	return b64, nil
+}
+
+// createPsbtScript returns a JSON object for the passed script of a PSBT, or
+// nil when the script is not set.
+func createPsbtScript(script []byte) *btcjson.ScriptSig {
+	if script == nil {
+		return nil
+	}
+
+	// The disassembled string will contain [error] inline if the script
+	// doesn't fully parse, so ignore the error here.
+	disbuf, _ := txscript.DisasmString(script)
+	return &btcjson.ScriptSig{
+		Asm: disbuf,
+		Hex: hex.EncodeToString(script),
+	}
+}
+
+// createPsbtVoutList returns a slice of JSON objects for the passed outputs of
+// a PSBT.
+func createPsbtVoutList(txOuts []*wire.TxOut, chainParams *chaincfg.Params) []btcjson.Vout {
+	return createVoutList(&wire.MsgTx{TxOut: txOuts}, chainParams, nil)
+}
+
+// createPsbtUnknowns returns the hex encoded keys and values of the passed
+// unknown fields of a PSBT, or nil when there are none.
+func createPsbtUnknowns(unknowns []*psbt.Unknown) map[string]string {
+	if len(unknowns) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(unknowns))
+	for _, unknown := range unknowns {
+		result[hex.EncodeToString(unknown.Key)] =
+			hex.EncodeToString(unknown.Value)
+	}
+	return result
+}
+
+// psbtSigHashString returns the name of the passed signature hash type as
+// accepted by the signrawtransactionwithkey command, or an empty string when
+// no signature hash type is set.
+func psbtSigHashString(hashType txscript.SigHashType) string {
+	if hashType == txscript.SigHashDefault {
+		return ""
+	}
+	for name, value := range rpcSigHashTypes {
+		if value == hashType && name != "DEFAULT" {
+			return name
+		}
+	}
+	return fmt.Sprintf("%#x", uint32(hashType))
+}
+
+// decodeFinalScriptWitness returns the hex encoded items of the passed
+// serialized final witness of a PSBT input.
+func decodeFinalScriptWitness(serialized []byte) ([]string, error) {
+	r := bytes.NewReader(serialized)
+	count, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var witness wire.TxWitness
+	for i := uint64(0); i < count; i++ {
+		item, err := wire.ReadVarBytes(r, 0, wire.MaxMessagePayload,
+			"witness item")
+		if err != nil {
+			return nil, err
+		}
+		witness = append(witness, item)
+	}
+	return witnessToHex(witness), nil
+}
+
+// handleDecodePsbt handles decodepsbt commands.
+func handleDecodePsbt(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.DecodePsbtCmd)
+	params := s.cfg.ChainParams
+
+	packet, err := decodePsbtParam(c.Psbt)
+	if err != nil {
+		return nil, err
+	}
+	mtx, err := psbt.ExtractUnsignedTx(packet)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDeserialization,
+			Message: "TX decode failed " + err.Error(),
+		}
+	}
+
+	reply := btcjson.DecodePsbtResult{
+		Tx: btcjson.TxRawDecodeResult{
+			Txid:     mtx.TxHash().String(),
+			Version:  mtx.Version,
+			Locktime: mtx.LockTime,
+			Vin:      createVinList(mtx),
+			Vout:     createVoutList(mtx, params, nil),
+		},
+		PsbtVersion: packet.PsbtVersion,
+		Unknown:     createPsbtUnknowns(packet.Unknowns),
+		Inputs:      make([]btcjson.DecodePsbtInput, len(packet.Inputs)),
+		Outputs:     make([]btcjson.DecodePsbtOutput, len(packet.Outputs)),
+	}
+	if reply.Unknown == nil {
+		reply.Unknown = make(map[string]string)
+	}
+
+	for i := range packet.Inputs {
+		pInput := &packet.Inputs[i]
+		input := &reply.Inputs[i]
+
+		if prevTx := pInput.NonWitnessUtxo; prevTx != nil {
+			input.NonWitnessUtxo = &btcjson.TxRawDecodeResult{
+				Txid:     prevTx.TxHash().String(),
+				Version:  prevTx.Version,
+				Locktime: prevTx.LockTime,
+				Vin:      createVinList(prevTx),
+				Vout:     createVoutList(prevTx, params, nil),
+			}
+		}
+		if pInput.WitnessUtxo != nil {
+			vout := createPsbtVoutList(
+				[]*wire.TxOut{pInput.WitnessUtxo}, params)
+			input.WitnessUtxo = &vout[0]
+		}
+		if len(pInput.PartialSigs) > 0 {
+			input.PartialSignatures = make(map[string]string)
+			for _, sig := range pInput.PartialSigs {
+				input.PartialSignatures[hex.EncodeToString(sig.PubKey)] =
+					hex.EncodeToString(sig.Signature)
+			}
+		}
+		input.SigHash = psbtSigHashString(pInput.SighashType)
+		input.RedeemScript = createPsbtScript(pInput.RedeemScript)
+		input.WitnessScript = createPsbtScript(pInput.WitnessScript)
+		input.FinalScriptSig = createPsbtScript(pInput.FinalScriptSig)
+		if pInput.FinalScriptWitness != nil {
+			input.FinalScriptWitness, err = decodeFinalScriptWitness(
+				pInput.FinalScriptWitness)
+			if err != nil {
+				return nil, &btcjson.RPCError{
+					Code:    btcjson.ErrRPCDeserialization,
+					Message: "TX decode failed " + err.Error(),
+				}
+			}
+		}
+		if pInput.MwebOutputId != nil {
+			input.MwebOutputID = pInput.MwebOutputId.String()
+		}
+		if pInput.MwebAmount != nil {
+			input.MwebAmount = btcjson.Float64(pInput.MwebAmount.ToBTC())
+		}
+		input.Unknown = createPsbtUnknowns(pInput.Unknowns)
+	}
+
+	// The amounts and scripts of the outputs of a version 0 PSBT are only
+	// held by its unsigned transaction.
+	for i := range packet.Outputs {
+		pOutput := &packet.Outputs[i]
+		output := &reply.Outputs[i]
+
+		txOut := &wire.TxOut{
+			Value:    int64(pOutput.Amount),
+			PkScript: pOutput.PKScript,
+		}
+		if packet.PsbtVersion == 0 {
+			txOut = packet.UnsignedTx.TxOut[i]
+		}
+		output.Amount = ltcutil.Amount(txOut.Value).ToBTC()
+		if pOutput.StealthAddress != nil {
+			addr := ltcutil.NewAddressMweb(pOutput.StealthAddress, params)
+			output.StealthAddress = addr.EncodeAddress()
+		} else {
+			vout := createPsbtVoutList([]*wire.TxOut{txOut}, params)
+			output.ScriptPubKey = vout[0].ScriptPubKey
+		}
+		output.RedeemScript = createPsbtScript(pOutput.RedeemScript)
+		output.WitnessScript = createPsbtScript(pOutput.WitnessScript)
+		output.MwebPegoutKernel = pOutput.MwebPegoutKernel
+		output.Unknown = createPsbtUnknowns(pOutput.Unknowns)
+	}
+
+	for i := range packet.Kernels {
+		pKernel := &packet.Kernels[i]
+		kernel := btcjson.DecodePsbtKernel{
+			LockHeight: pKernel.LockHeight,
+			Signed:     pKernel.Signature != nil,
+		}
+		if pKernel.Fee != nil {
+			kernel.Fee = btcjson.Float64(pKernel.Fee.ToBTC())
+		}
+		if pKernel.PeginAmount != nil {
+			kernel.PeginAmount = btcjson.Float64(
+				pKernel.PeginAmount.ToBTC())
+		}
+		if len(pKernel.PegOuts) > 0 {
+			kernel.PegOuts = createPsbtVoutList(pKernel.PegOuts, params)
+		}
+		reply.Kernels = append(reply.Kernels, kernel)
+	}
+
+	// The fee is only known when the amounts of all inputs are known.
+	if fee, err := packet.GetTxFee(); err == nil {
+		reply.Fee = btcjson.Float64(fee.ToBTC())
+	}
+
+	return reply, nil
+}
+
+// handleCombinePsbt handles combinepsbt commands.
+func handleCombinePsbt(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.CombinePsbtCmd)
+
+	if len(c.Psbts) == 0 {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Parameter 1 must be a non-empty array",
+		}
+	}
+
+	packets := make([]*psbt.Packet, 0, len(c.Psbts))
+	for _, b64 := range c.Psbts {
+		packet, err := decodePsbtParam(b64)
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, packet)
+	}
+
+	combined, err := psbt.Combine(packets...)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: err.Error(),
+		}
+	}
+	return encodePsbtResult(combined)
+}
+
+// handleFinalizePsbt handles finalizepsbt commands.
+func handleFinalizePsbt(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.FinalizePsbtCmd)
+
+	packet, err := decodePsbtParam(c.Psbt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Finalize as many inputs as possible.  The PSBT is returned as is
+	// when some of them lack the data to be finalized.
+	for i := range packet.Inputs {
+		_, _ = psbt.MaybeFinalize(packet, i)
+	}
+
+	extract := c.Extract == nil || *c.Extract
+	if extract && packet.IsComplete() {
+		mtx, err := psbt.Extract(packet)
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCDeserialization,
+				Message: "TX extraction failed " + err.Error(),
+			}
+		}
+		mtxHex, err := messageToHex(mtx)
+		if err != nil {
+			return nil, err
+		}
+		return &btcjson.FinalizePsbtResult{
+			Hex:      mtxHex,
+			Complete: true,
+		}, nil
+	}
+
+	b64, err := encodePsbtResult(packet)
+	if err != nil {
+		return nil, err
+	}
+	return &btcjson.FinalizePsbtResult{
+		Psbt:     b64,
+		Complete: packet.IsComplete(),
+	}, nil
+}
+
+// handleConvertToPsbt handles converttopsbt commands.
+func handleConvertToPsbt(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.ConvertToPsbtCmd)
+
+	hexStr := c.HexTx
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+	serializedTx, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+
+	// The transaction is decoded without witnesses only when the caller
+	// says it has none, since the serialization of a transaction without
+	// inputs is otherwise ambiguous.
+	var mtx wire.MsgTx
+	if c.IsWitness != nil && !*c.IsWitness {
+		err = mtx.DeserializeNoWitness(bytes.NewReader(serializedTx))
+	} else {
+		err = mtx.Deserialize(bytes.NewReader(serializedTx))
+	}
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDeserialization,
+			Message: "TX decode failed " + err.Error(),
+		}
+	}
+
+	// Signature data is only discarded when permitted.
+	permitSigData := c.PermitSigData != nil && *c.PermitSigData
+	for _, txIn := range mtx.TxIn {
+		if len(txIn.SignatureScript) == 0 && len(txIn.Witness) == 0 {
+			continue
+		}
+		if !permitSigData {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCDeserialization,
+				Message: "Inputs must not have scriptSigs and scriptWitnesses",
+			}
+		}
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+
+	packet, err := psbt.NewFromUnsignedTx(&mtx)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: err.Error(),
+		}
+	}
+	return encodePsbtResult(packet)
+}
@@ -16,11 +16,15 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"math/big"
 	"math/rand"
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,18 +32,27 @@ import (
 	"time"
 
 	"github.com/btcsuite/websocket"
+	"github.com/ltcsuite/ltcd/addrmgr"
+	"github.com/ltcsuite/ltcd/amount"
 	"github.com/ltcsuite/ltcd/blockchain"
 	"github.com/ltcsuite/ltcd/blockchain/indexers"
-	"github.com/ltcsuite/ltcd/btcec/v2/ecdsa"
 	"github.com/ltcsuite/ltcd/btcjson"
 	"github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/database"
+	"github.com/ltcsuite/ltcd/descriptor"
 	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/base58"
+	"github.com/ltcsuite/ltcd/ltcutil/bech32"
+	"github.com/ltcsuite/ltcd/ltcutil/gcs"
+	"github.com/ltcsuite/ltcd/ltcutil/gcs/builder"
+	"github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
 	"github.com/ltcsuite/ltcd/mempool"
 	"github.com/ltcsuite/ltcd/mining"
 	"github.com/ltcsuite/ltcd/mining/cpuminer"
 	"github.com/ltcsuite/ltcd/peer"
+	"github.com/ltcsuite/ltcd/signmessage"
+	"github.com/ltcsuite/ltcd/txauthor"
 	"github.com/ltcsuite/ltcd/txscript"
 	"github.com/ltcsuite/ltcd/wire"
 )
@@ -75,6 +88,10 @@ const (
 
 	// maxProtocolVersion is the max protocol version the server supports.
 	maxProtocolVersion = 70002
+
+	// maxDifficultyHistory is the maximum number of blocks whose difficulty
+	// is returned by a single getdifficultyhistory request.
+	maxDifficultyHistory = 20000
 )
 
 var (
@@ -130,54 +147,87 @@ type commandHandler func(*rpcServer, interface{}, <-chan struct{}) (interface{},
 // a dependency loop.
 var rpcHandlers map[string]commandHandler
 var rpcHandlersBeforeInit = map[string]commandHandler{
-	"addnode":                handleAddNode,
-	"createrawtransaction":   handleCreateRawTransaction,
-	"debuglevel":             handleDebugLevel,
-	"decoderawtransaction":   handleDecodeRawTransaction,
-	"decodescript":           handleDecodeScript,
-	"estimatefee":            handleEstimateFee,
-	"generate":               handleGenerate,
-	"getaddednodeinfo":       handleGetAddedNodeInfo,
-	"getbestblock":           handleGetBestBlock,
-	"getbestblockhash":       handleGetBestBlockHash,
-	"getblock":               handleGetBlock,
-	"getblockchaininfo":      handleGetBlockChainInfo,
-	"getblockcount":          handleGetBlockCount,
-	"getblockhash":           handleGetBlockHash,
-	"getblockheader":         handleGetBlockHeader,
-	"getblocktemplate":       handleGetBlockTemplate,
-	"getcfilter":             handleGetCFilter,
-	"getcfilterheader":       handleGetCFilterHeader,
-	"getconnectioncount":     handleGetConnectionCount,
-	"getcurrentnet":          handleGetCurrentNet,
-	"getdifficulty":          handleGetDifficulty,
-	"getgenerate":            handleGetGenerate,
-	"gethashespersec":        handleGetHashesPerSec,
-	"getheaders":             handleGetHeaders,
-	"getinfo":                handleGetInfo,
-	"getmempoolinfo":         handleGetMempoolInfo,
-	"getmininginfo":          handleGetMiningInfo,
-	"getnettotals":           handleGetNetTotals,
-	"getnetworkhashps":       handleGetNetworkHashPS,
-	"getnodeaddresses":       handleGetNodeAddresses,
-	"getpeerinfo":            handleGetPeerInfo,
-	"getrawmempool":          handleGetRawMempool,
-	"getrawtransaction":      handleGetRawTransaction,
-	"gettxout":               handleGetTxOut,
-	"help":                   handleHelp,
-	"node":                   handleNode,
-	"ping":                   handlePing,
-	"searchrawtransactions":  handleSearchRawTransactions,
-	"sendrawtransaction":     handleSendRawTransaction,
-	"setgenerate":            handleSetGenerate,
-	"signmessagewithprivkey": handleSignMessageWithPrivKey,
-	"stop":                   handleStop,
-	"submitblock":            handleSubmitBlock,
-	"uptime":                 handleUptime,
-	"validateaddress":        handleValidateAddress,
-	"verifychain":            handleVerifyChain,
-	"verifymessage":          handleVerifyMessage,
-	"version":                handleVersion,
+	"addnode":                   handleAddNode,
+	"canceloperation":           handleCancelOperation,
+	"combinepsbt":               handleCombinePsbt,
+	"converttopsbt":             handleConvertToPsbt,
+	"createrawtransaction":      handleCreateRawTransaction,
+	"debuglevel":                handleDebugLevel,
+	"decodepsbt":                handleDecodePsbt,
+	"decoderawtransaction":      handleDecodeRawTransaction,
+	"decodescript":              handleDecodeScript,
+	"deriveaddresses":           handleDeriveAddresses,
+	"estimatefee":               handleEstimateFee,
+	"finalizepsbt":              handleFinalizePsbt,
+	"fundrawtransaction":        handleFundRawTransaction,
+	"generate":                  handleGenerate,
+	"generateblock":             handleGenerateBlock,
+	"getaddednodeinfo":          handleGetAddedNodeInfo,
+	"getaddrmaninfo":            handleGetAddrManInfo,
+	"getbestblock":              handleGetBestBlock,
+	"getbestblockhash":          handleGetBestBlockHash,
+	"getblock":                  handleGetBlock,
+	"getblockchaininfo":         handleGetBlockChainInfo,
+	"getblockcount":             handleGetBlockCount,
+	"getblockfrompeer":          handleGetBlockFromPeer,
+	"getblockhash":              handleGetBlockHash,
+	"getblockheader":            handleGetBlockHeader,
+	"getblocksubsidy":           handleGetBlockSubsidy,
+	"getblocktemplate":          handleGetBlockTemplate,
+	"getcfilter":                handleGetCFilter,
+	"getcfilterheader":          handleGetCFilterHeader,
+	"getchainparams":            handleGetChainParams,
+	"getchaintips":              handleGetChainTips,
+	"getconnectioncount":        handleGetConnectionCount,
+	"getcurrentnet":             handleGetCurrentNet,
+	"getdescriptorinfo":         handleGetDescriptorInfo,
+	"getdifficulty":             handleGetDifficulty,
+	"getdifficultyhistory":      handleGetDifficultyHistory,
+	"getgenerate":               handleGetGenerate,
+	"gethashespersec":           handleGetHashesPerSec,
+	"getheaders":                handleGetHeaders,
+	"getindexinfo":              handleGetIndexInfo,
+	"getinfo":                   handleGetInfo,
+	"getmemoryinfo":             handleGetMemoryInfo,
+	"getmempoolinfo":            handleGetMempoolInfo,
+	"getmininginfo":             handleGetMiningInfo,
+	"getmisbehavinglog":         handleGetMisbehavingLog,
+	"getnettotals":              handleGetNetTotals,
+	"getnetworkhashps":          handleGetNetworkHashPS,
+	"getnetworkinfo":            handleGetNetworkInfo,
+	"getnodeaddresses":          handleGetNodeAddresses,
+	"getoperationstatus":        handleGetOperationStatus,
+	"getpeerinfo":               handleGetPeerInfo,
+	"getrawmempool":             handleGetRawMempool,
+	"getrawtransaction":         handleGetRawTransaction,
+	"getrpcinfo":                handleGetRPCInfo,
+	"gettxout":                  handleGetTxOut,
+	"gettxoutsetinfo":           handleGetTxOutSetInfo,
+	"getwork":                   handleGetWork,
+	"help":                      handleHelp,
+	"importwatchonly":           handleImportWatchOnly,
+	"listunspentwatchonly":      handleListUnspentWatchOnly,
+	"node":                      handleNode,
+	"ping":                      handlePing,
+	"rescanblockchain":          handleRescanBlockchain,
+	"searchrawtransactions":     handleSearchRawTransactions,
+	"sendrawtransaction":        handleSendRawTransaction,
+	"setgenerate":               handleSetGenerate,
+	"setloglevel":               handleSetLogLevel,
+	"setmocktime":               handleSetMockTime,
+	"setpolicy":                 handleSetPolicy,
+	"signmessagewithprivkey":    handleSignMessageWithPrivKey,
+	"signrawtransactionwithkey": handleSignRawTransactionWithKey,
+	"startoperation":            handleStartOperation,
+	"stop":                      handleStop,
+	"submitblock":               handleSubmitBlock,
+	"submitheader":              handleSubmitHeader,
+	"tracescript":               handleTraceScript,
+	"uptime":                    handleUptime,
+	"validateaddress":           handleValidateAddress,
+	"verifychain":               handleVerifyChain,
+	"verifymessage":             handleVerifyMessage,
+	"version":                   handleVersion,
 }
 
 // list of commands that we recognize, but for which ltcd has no support because
@@ -200,7 +250,6 @@ var rpcAskWallet = map[string]struct{}{
 	"getreceivedbyaccount":   {},
 	"getreceivedbyaddress":   {},
 	"gettransaction":         {},
-	"gettxoutsetinfo":        {},
 	"getunconfirmedbalance":  {},
 	"getwalletinfo":          {},
 	"importprivkey":          {},
@@ -231,10 +280,7 @@ var rpcAskWallet = map[string]struct{}{
 // Commands that are currently unimplemented, but should ultimately be.
 var rpcUnimplemented = map[string]struct{}{
 	"estimatepriority": {},
-	"getchaintips":     {},
 	"getmempoolentry":  {},
-	"getnetworkinfo":   {},
-	"getwork":          {},
 	"invalidateblock":  {},
 	"preciousblock":    {},
 	"reconsiderblock":  {},
@@ -253,33 +299,50 @@ var rpcLimited = map[string]struct{}{
 	"session":               {},
 
 	// Websockets AND HTTP/S commands
-	"help": {},
+	"help":             {},
+	"rescanblockchain": {},
 
 	// HTTP/S-only commands
+	"combinepsbt":           {},
+	"converttopsbt":         {},
 	"createrawtransaction":  {},
+	"decodepsbt":            {},
 	"decoderawtransaction":  {},
 	"decodescript":          {},
+	"deriveaddresses":       {},
 	"estimatefee":           {},
+	"finalizepsbt":          {},
 	"getbestblock":          {},
 	"getbestblockhash":      {},
 	"getblock":              {},
 	"getblockcount":         {},
 	"getblockhash":          {},
 	"getblockheader":        {},
+	"getblocksubsidy":       {},
 	"getcfilter":            {},
 	"getcfilterheader":      {},
+	"getchainparams":        {},
+	"getchaintips":          {},
 	"getcurrentnet":         {},
+	"getdescriptorinfo":     {},
 	"getdifficulty":         {},
+	"getdifficultyhistory":  {},
 	"getheaders":            {},
+	"getindexinfo":          {},
 	"getinfo":               {},
 	"getnettotals":          {},
 	"getnetworkhashps":      {},
+	"getnetworkinfo":        {},
 	"getrawmempool":         {},
 	"getrawtransaction":     {},
 	"gettxout":              {},
+	"gettxoutsetinfo":       {},
+	"listunspentwatchonly":  {},
 	"searchrawtransactions": {},
 	"sendrawtransaction":    {},
 	"submitblock":           {},
+	"submitheader":          {},
+	"tracescript":           {},
 	"uptime":                {},
 	"validateaddress":       {},
 	"verifymessage":         {},
@@ -329,25 +392,49 @@ func rpcNoTxInfoError(txHash *chainhash.Hash) *btcjson.RPCError {
 			txHash))
 }
 
+// indexSynced returns whether the enabled optional index with the passed name
+// is caught up with the best chain.  Indexes enabled on a synced node are
+// caught up in the background, so lookups may miss entries until then.
+func indexSynced(s *rpcServer, name string) bool {
+	if s.cfg.IndexManager == nil {
+		return false
+	}
+	for _, status := range s.cfg.IndexManager.Statuses() {
+		if status.Name == name {
+			return status.Synced
+		}
+	}
+	return false
+}
+
 // gbtWorkState houses state that is used in between multiple RPC invocations to
 // getblocktemplate.
 type gbtWorkState struct {
 	sync.Mutex
 	lastTxUpdate  time.Time
 	lastGenerated time.Time
+	lastRebuilt   time.Time
 	prevHash      *chainhash.Hash
 	minTimestamp  time.Time
 	template      *mining.BlockTemplate
 	notifyMap     map[chainhash.Hash]map[int64]chan struct{}
 	timeSource    blockchain.MedianTimeSource
+
+	// chainParams are the parameters of the network, which define the
+	// limits of the blocks and the challenge the blocks must solve when
+	// running on a signet network.
+	chainParams *chaincfg.Params
 }
 
 // newGbtWorkState returns a new instance of a gbtWorkState with all internal
 // fields initialized and ready to use.
-func newGbtWorkState(timeSource blockchain.MedianTimeSource) *gbtWorkState {
+func newGbtWorkState(timeSource blockchain.MedianTimeSource,
+	chainParams *chaincfg.Params) *gbtWorkState {
+
 	return &gbtWorkState{
-		notifyMap:  make(map[chainhash.Hash]map[int64]chan struct{}),
-		timeSource: timeSource,
+		notifyMap:   make(map[chainhash.Hash]map[int64]chan struct{}),
+		timeSource:  timeSource,
+		chainParams: chainParams,
 	}
 }
 
@@ -526,9 +613,16 @@ func handleCreateRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan
 		}
 	}
 
+	// Discourage fee sniping when no locktime is given.
+	mtx := wire.NewMsgTx(wire.TxVersion)
+	if c.LockTime != nil {
+		mtx.LockTime = uint32(*c.LockTime)
+	} else {
+		mtx.LockTime = feeSnipingLockTime(s)
+	}
+
 	// Add all transaction inputs to a new transaction after performing
 	// some validity checks.
-	mtx := wire.NewMsgTx(wire.TxVersion)
 	for _, input := range c.Inputs {
 		txHash, err := chainhash.NewHashFromStr(input.Txid)
 		if err != nil {
@@ -537,7 +631,7 @@ func handleCreateRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan
 
 		prevOut := wire.NewOutPoint(txHash, input.Vout)
 		txIn := wire.NewTxIn(prevOut, []byte{}, nil)
-		if c.LockTime != nil && *c.LockTime != 0 {
+		if mtx.LockTime != 0 {
 			txIn.Sequence = wire.MaxTxInSequenceNum - 1
 		}
 		mtx.AddTxIn(txIn)
@@ -546,9 +640,11 @@ func handleCreateRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan
 	// Add all transaction outputs to the transaction after performing
 	// some validity checks.
 	params := s.cfg.ChainParams
-	for encodedAddr, amount := range c.Amounts {
-		// Ensure amount is in the valid range for monetary amounts.
-		if amount <= 0 || amount*ltcutil.SatoshiPerBitcoin > ltcutil.MaxSatoshi {
+	for encodedAddr, coins := range c.Amounts {
+		// Ensure amount is in the valid range for monetary amounts,
+		// which is bounded by the maximum supply of the network.
+		satoshi, err := ltcutil.NewAmount(coins)
+		if err != nil || satoshi <= 0 || amount.Validate(satoshi, params) != nil {
 			return nil, &btcjson.RPCError{
 				Code:    btcjson.ErrRPCType,
 				Message: "Invalid amount",
@@ -591,22 +687,10 @@ func handleCreateRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan
 			return nil, internalRPCError(err.Error(), context)
 		}
 
-		// Convert the amount to satoshi.
-		satoshi, err := ltcutil.NewAmount(amount)
-		if err != nil {
-			context := "Failed to convert amount"
-			return nil, internalRPCError(err.Error(), context)
-		}
-
 		txOut := wire.NewTxOut(int64(satoshi), pkScript)
 		mtx.AddTxOut(txOut)
 	}
 
-	// Set the Locktime, if given.
-	if c.LockTime != nil {
-		mtx.LockTime = uint32(*c.LockTime)
-	}
-
 	// Return the serialized and hex-encoded transaction.  Note that this
 	// is intentionally not directly returning because the first return
 	// value is a string and it would result in returning an empty string to
@@ -618,6 +702,16 @@ func handleCreateRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan
 	return mtxHex, nil
 }
 
+// feeSnipingLockTime returns the lock time discouraging fee sniping for a
+// transaction built on the best chain.  Zero is returned while the chain is
+// not current since the best height may then be far behind the network.
+func feeSnipingLockTime(s *rpcServer) uint32 {
+	if !s.cfg.SyncMgr.IsCurrent() {
+		return 0
+	}
+	return txauthor.FeeSnipingLockTime(s.cfg.Chain.BestSnapshot().Height)
+}
+
 // handleDebugLevel handles debuglevel commands.
 func handleDebugLevel(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*btcjson.DebugLevelCmd)
@@ -639,6 +733,32 @@ func handleDebugLevel(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 	return "Done.", nil
 }
 
+// handleSetLogLevel handles setloglevel commands.
+func handleSetLogLevel(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.SetLogLevelCmd)
+
+	if !validLogLevel(c.Level) {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParams.Code,
+			Message: fmt.Sprintf("invalid log level %q", c.Level),
+		}
+	}
+
+	if c.Subsystem == "all" {
+		setLogLevels(c.Level)
+		return logLevels(), nil
+	}
+	if _, ok := subsystemLoggers[c.Subsystem]; !ok {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidParams.Code,
+			Message: fmt.Sprintf("invalid subsystem %q -- supported "+
+				"subsystems %v", c.Subsystem, supportedSubsystems()),
+		}
+	}
+	setLogLevel(c.Subsystem, c.Level)
+	return logLevels(), nil
+}
+
 // witnessToHex formats the passed witness stack as a slice of hex-encoded
 // strings to be used in a JSON response.
 func witnessToHex(witness wire.TxWitness) []string {
@@ -874,196 +994,800 @@ func handleDecodeScript(s *rpcServer, cmd interface{}, closeChan <-chan struct{}
 	return reply, nil
 }
 
-// handleEstimateFee handles estimatefee commands.
-func handleEstimateFee(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	c := cmd.(*btcjson.EstimateFeeCmd)
-
-	if s.cfg.FeeEstimator == nil {
-		return nil, errors.New("Fee estimation disabled")
+// maxDeriveAddressesRange is the maximum number of addresses which may be
+// derived by a single deriveaddresses command.
+const maxDeriveAddressesRange = 1000000
+
+// parseDescriptorRange converts the optional range parameter of the
+// deriveaddresses command into the inclusive range of indexes to derive.  A
+// single integer n is treated as the range [0,n].
+func parseDescriptorRange(r *btcjson.DescriptorRange) (uint32, uint32, error) {
+	var begin, end int
+	switch v := r.Value.(type) {
+	case int:
+		end = v
+	case []int:
+		begin, end = v[0], v[1]
+	default:
+		return 0, 0, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Range must be an integer or [begin,end] pair",
+		}
 	}
 
-	if c.NumBlocks <= 0 {
-		return -1.0, errors.New("Parameter NumBlocks must be positive")
+	var msg string
+	switch {
+	case begin < 0 || end < 0:
+		msg = "Range should be greater or equal than 0"
+	case begin > end:
+		msg = "Range specified as [begin,end] must not have begin " +
+			"after end"
+	case end >= hdkeychain.HardenedKeyStart:
+		msg = "End of range is too high"
+	case end-begin >= maxDeriveAddressesRange:
+		msg = "Range is too large"
+	}
+	if msg != "" {
+		return 0, 0, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: msg,
+		}
 	}
 
-	feeRate, err := s.cfg.FeeEstimator.EstimateFee(uint32(c.NumBlocks))
+	return uint32(begin), uint32(end), nil
+}
 
+// handleDeriveAddresses implements the deriveaddresses command.
+func handleDeriveAddresses(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.DeriveAddressesCmd)
+
+	desc, err := descriptor.Parse(c.Descriptor, true, s.cfg.ChainParams)
 	if err != nil {
-		return -1.0, err
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: err.Error(),
+		}
 	}
 
-	// Convert to satoshis per kb.
-	return float64(feeRate), nil
-}
+	var begin, end uint32
+	switch {
+	case desc.IsRange() && c.Range == nil:
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Range must be specified for a ranged descriptor",
+		}
 
-// handleGenerate handles generate commands.
-func handleGenerate(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	// Respond with an error if there are no addresses to pay the
-	// created blocks to.
-	if len(cfg.miningAddrs) == 0 {
+	case !desc.IsRange() && c.Range != nil:
 		return nil, &btcjson.RPCError{
-			Code: btcjson.ErrRPCInternal.Code,
-			Message: "No payment addresses specified " +
-				"via --miningaddr",
+			Code: btcjson.ErrRPCInvalidParameter,
+			Message: "Range should not be specified for an " +
+				"un-ranged descriptor",
+		}
+
+	case c.Range != nil:
+		begin, end, err = parseDescriptorRange(c.Range)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	// Respond with an error if there's virtually 0 chance of mining a block
-	// with the CPU.
-	if !s.cfg.ChainParams.GenerateSupported {
-		return nil, &btcjson.RPCError{
-			Code: btcjson.ErrRPCDifficulty,
-			Message: fmt.Sprintf("No support for `generate` on "+
-				"the current network, %s, as it's unlikely to "+
-				"be possible to mine a block with the CPU.",
-				s.cfg.ChainParams.Net),
+	addresses := make(btcjson.DeriveAddressesResult, 0, end-begin+1)
+	for i := begin; i <= end; i++ {
+		addr, err := desc.Address(i)
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidAddressOrKey,
+				Message: err.Error(),
+			}
 		}
+		addresses = append(addresses, addr.EncodeAddress())
 	}
 
-	c := cmd.(*btcjson.GenerateCmd)
+	return addresses, nil
+}
 
-	// Respond with an error if the client is requesting 0 blocks to be generated.
-	if c.NumBlocks == 0 {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCInternal.Code,
-			Message: "Please request a nonzero number of blocks to generate.",
-		}
+// handleEstimateFee handles estimatefee commands.
+func handleEstimateFee(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.EstimateFeeCmd)
+
+	if s.cfg.FeeEstimator == nil {
+		return nil, errors.New("Fee estimation disabled")
 	}
 
-	// Create a reply
-	reply := make([]string, c.NumBlocks)
+	if c.NumBlocks <= 0 {
+		return -1.0, errors.New("Parameter NumBlocks must be positive")
+	}
+
+	feeRate, err := s.cfg.FeeEstimator.EstimateFee(uint32(c.NumBlocks))
 
-	blockHashes, err := s.cfg.CPUMiner.GenerateNBlocks(c.NumBlocks)
 	if err != nil {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCInternal.Code,
-			Message: err.Error(),
-		}
+		return -1.0, err
 	}
 
-	// Mine the correct number of blocks, assigning the hex representation of the
-	// hash of each one to its place in the reply.
-	for i, hash := range blockHashes {
-		reply[i] = hash.String()
-	}
+	// Convert to satoshis per kb.
+	return float64(feeRate), nil
+}
 
-	return reply, nil
+// fundRawTransactionResult models the data returned by the
+// fundrawtransaction command.
+type fundRawTransactionResult struct {
+	Hex            string  `json:"hex"`
+	Fee            float64 `json:"fee"`
+	ChangePosition int     `json:"changepos"`
 }
 
-// handleGetAddedNodeInfo handles getaddednodeinfo commands.
-func handleGetAddedNodeInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	c := cmd.(*btcjson.GetAddedNodeInfoCmd)
+// addrIndexUTXOSource is a txauthor.UTXOSource providing the unspent outputs
+// paying to a set of watched addresses, as found by the address index.
+type addrIndexUTXOSource struct {
+	s     *rpcServer
+	addrs []ltcutil.Address
+}
 
-	// Retrieve a list of persistent (added) peers from the server and
-	// filter the list of peers per the specified address (if any).
-	peers := s.cfg.ConnMgr.PersistentPeers()
-	if c.Node != nil {
-		node := *c.Node
-		found := false
-		for i, peer := range peers {
-			if peer.ToPeer().Addr() == node {
-				peers = peers[i : i+1]
-				found = true
+// UnspentOutputs returns the confirmed and unconfirmed outputs paying to the
+// watched addresses which are not spent by the main chain or the memory pool.
+// Immature coinbase outputs are excluded.
+//
+// This is part of the txauthor.UTXOSource interface.
+func (src *addrIndexUTXOSource) UnspentOutputs() ([]txauthor.Coin, error) {
+	s := src.s
+	spendHeight := s.cfg.Chain.BestSnapshot().Height + 1
+	maturity := int32(s.cfg.ChainParams.CoinbaseMaturity)
+
+	var coins []txauthor.Coin
+	seen := make(map[wire.OutPoint]struct{})
+	addCoins := func(tx *wire.MsgTx, pkScript []byte, confirmed bool) error {
+		txHash := tx.TxHash()
+		for i, txOut := range tx.TxOut {
+			if !bytes.Equal(txOut.PkScript, pkScript) {
+				continue
 			}
-		}
-		if !found {
-			return nil, &btcjson.RPCError{
-				Code:    btcjson.ErrRPCClientNodeNotAdded,
-				Message: "Node has not been added",
+			op := wire.OutPoint{Hash: txHash, Index: uint32(i)}
+			if _, ok := seen[op]; ok {
+				continue
+			}
+			seen[op] = struct{}{}
+			if s.cfg.TxMemPool.CheckSpend(op) != nil {
+				continue
 			}
-		}
-	}
 
-	// Without the dns flag, the result is just a slice of the addresses as
-	// strings.
-	if !c.DNS {
-		results := make([]string, 0, len(peers))
-		for _, peer := range peers {
-			results = append(results, peer.ToPeer().Addr())
+			if confirmed {
+				entry, err := s.cfg.Chain.FetchUtxoEntry(op)
+				if err != nil {
+					return err
+				}
+				if entry == nil || entry.IsSpent() {
+					continue
+				}
+				if entry.IsCoinBase() &&
+					spendHeight-entry.BlockHeight() < maturity {
+
+					continue
+				}
+			}
+
+			coins = append(coins, txauthor.Coin{
+				OutPoint: op,
+				Value:    ltcutil.Amount(txOut.Value),
+				PkScript: txOut.PkScript,
+			})
 		}
-		return results, nil
+		return nil
 	}
 
-	// With the dns flag, the result is an array of JSON objects which
-	// include the result of DNS lookups for each peer.
-	results := make([]*btcjson.GetAddedNodeInfoResult, 0, len(peers))
-	for _, rpcPeer := range peers {
-		// Set the "address" of the peer which could be an ip address
-		// or a domain name.
-		peer := rpcPeer.ToPeer()
-		var result btcjson.GetAddedNodeInfoResult
-		result.AddedNode = peer.Addr()
-		result.Connected = btcjson.Bool(peer.Connected())
-
-		// Split the address into host and port portions so we can do
-		// a DNS lookup against the host.  When no port is specified in
-		// the address, just use the address as the host.
-		host, _, err := net.SplitHostPort(peer.Addr())
+	for _, addr := range src.addrs {
+		pkScript, err := txscript.PayToAddrScript(addr)
 		if err != nil {
-			host = peer.Addr()
+			return nil, err
 		}
 
-		var ipList []string
-		switch {
-		case net.ParseIP(host) != nil, strings.HasSuffix(host, ".onion"):
-			ipList = make([]string, 1)
-			ipList[0] = host
-		default:
-			// Do a DNS lookup for the address.  If the lookup fails, just
-			// use the host.
-			ips, err := ltcdLookup(host)
+		var serializedTxns [][]byte
+		err = s.cfg.DB.View(func(dbTx database.Tx) error {
+			regions, _, err := s.cfg.AddrIndex.TxRegionsForAddress(
+				dbTx, addr, 0, math.MaxUint32, false)
 			if err != nil {
-				ipList = make([]string, 1)
-				ipList[0] = host
-				break
+				return err
 			}
-			ipList = make([]string, 0, len(ips))
-			for _, ip := range ips {
-				ipList = append(ipList, ip.String())
+			serializedTxns, err = dbTx.FetchBlockRegions(regions)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, serializedTx := range serializedTxns {
+			var mtx wire.MsgTx
+			err := mtx.Deserialize(bytes.NewReader(serializedTx))
+			if err != nil {
+				return nil, err
+			}
+			if err := addCoins(&mtx, pkScript, true); err != nil {
+				return nil, err
 			}
 		}
 
-		// Add the addresses and connection info to the result.
-		addrs := make([]btcjson.GetAddedNodeInfoResultAddr, 0, len(ipList))
-		for _, ip := range ipList {
-			var addr btcjson.GetAddedNodeInfoResultAddr
-			addr.Address = ip
-			addr.Connected = "false"
-			if ip == host && peer.Connected() {
-				addr.Connected = directionString(peer.Inbound())
+		for _, tx := range s.cfg.AddrIndex.UnconfirmedTxnsForAddress(addr) {
+			if err := addCoins(tx.MsgTx(), pkScript, false); err != nil {
+				return nil, err
 			}
-			addrs = append(addrs, addr)
 		}
-		result.Addresses = &addrs
-		results = append(results, &result)
 	}
-	return results, nil
+
+	return coins, nil
 }
 
-// handleGetBestBlock implements the getbestblock command.
-func handleGetBestBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	// All other "get block" commands give either the height, the
-	// hash, or both but require the block SHA.  This gets both for
-	// the best block.
-	best := s.cfg.Chain.BestSnapshot()
-	result := &btcjson.GetBestBlockResult{
-		Hash:   best.Hash.String(),
-		Height: best.Height,
+// decodeUnfundedTx decodes the passed serialized transaction.  Unless the
+// transaction is known to use the witness encoding, the legacy encoding is
+// tried first since an unfunded transaction without inputs is otherwise
+// mistaken for a witness transaction.
+func decodeUnfundedTx(serializedTx []byte, isWitness *bool) (*wire.MsgTx, error) {
+	decode := func(witness bool) (*wire.MsgTx, error) {
+		var mtx wire.MsgTx
+		r := bytes.NewReader(serializedTx)
+		var err error
+		if witness {
+			err = mtx.Deserialize(r)
+		} else {
+			err = mtx.DeserializeNoWitness(r)
+		}
+		if err == nil && r.Len() != 0 {
+			err = errors.New("trailing data after transaction")
+		}
+		return &mtx, err
 	}
-	return result, nil
-}
 
-// handleGetBestBlockHash implements the getbestblockhash command.
-func handleGetBestBlockHash(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	best := s.cfg.Chain.BestSnapshot()
-	return best.Hash.String(), nil
+	if isWitness != nil {
+		return decode(*isWitness)
+	}
+	mtx, err := decode(false)
+	if err != nil {
+		mtx, err = decode(true)
+	}
+	return mtx, err
 }
 
-// getDifficultyRatio returns the proof-of-work difficulty as a multiple of the
-// minimum difficulty using the passed bits field from the header of a block.
-func getDifficultyRatio(bits uint32, params *chaincfg.Params) float64 {
-	// The minimum difficulty is the max possible proof-of-work limit bits
-	// converted back to a number.  Note this is not the same as the proof of
+// handleFundRawTransaction implements the fundrawtransaction command.
+func handleFundRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	// Respond with an error if the address index is not enabled since it
+	// is used to find the outputs available to fund the transaction.
+	if s.cfg.AddrIndex == nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCMisc,
+			Message: "Address index must be enabled (--addrindex)",
+		}
+	}
+
+	c := cmd.(*btcjson.FundRawTransactionCmd)
+	opts := &c.Options
+	var lockTime *uint32
+	if opts.LockTime != nil {
+		if *opts.LockTime < 0 ||
+			*opts.LockTime > int64(wire.MaxTxInSequenceNum) {
+
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidParameter,
+				Message: "Locktime out of range",
+			}
+		}
+		lt := uint32(*opts.LockTime)
+		lockTime = &lt
+	}
+	if opts.LockUnspents != nil && *opts.LockUnspents {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "lockUnspents is not supported",
+		}
+	}
+
+	hexStr := c.HexTx
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+	serializedTx, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+	mtx, err := decodeUnfundedTx(serializedTx, c.IsWitness)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDeserialization,
+			Message: "TX decode failed: " + err.Error(),
+		}
+	}
+
+	// Decode the watched addresses providing the outputs to fund the
+	// transaction with and the change address.
+	params := s.cfg.ChainParams
+	decodeAddr := func(encodedAddr string) (ltcutil.Address, error) {
+		addr, err := ltcutil.DecodeAddress(encodedAddr, params)
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidAddressOrKey,
+				Message: "Invalid address or key: " + err.Error(),
+			}
+		}
+		if !addr.IsForNet(params) {
+			return nil, &btcjson.RPCError{
+				Code: btcjson.ErrRPCInvalidAddressOrKey,
+				Message: "Invalid address: " + encodedAddr +
+					" is for the wrong network",
+			}
+		}
+		if _, ok := addr.(*ltcutil.AddressMweb); ok {
+			return nil, &btcjson.RPCError{
+				Code: btcjson.ErrRPCInvalidAddressOrKey,
+				Message: "Invalid address: MWEB address " +
+					encodedAddr + " is not supported",
+			}
+		}
+		return addr, nil
+	}
+	if len(opts.Addresses) == 0 {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "No addresses to fund the transaction from",
+		}
+	}
+	source := &addrIndexUTXOSource{s: s}
+	for _, encodedAddr := range opts.Addresses {
+		addr, err := decodeAddr(encodedAddr)
+		if err != nil {
+			return nil, err
+		}
+		source.addrs = append(source.addrs, addr)
+	}
+	if opts.ChangeAddress == nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "changeAddress must be provided",
+		}
+	}
+	changeAddr, err := decodeAddr(*opts.ChangeAddress)
+	if err != nil {
+		return nil, err
+	}
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		context := "Failed to generate pay-to-address script"
+		return nil, internalRPCError(err.Error(), context)
+	}
+
+	// Use the requested fee rate, falling back to the estimated fee rate
+	// and then the minimum relay fee.
+	minRelayTxFee := s.cfg.TxMemPool.Policy().MinRelayTxFee
+	feeRate := minRelayTxFee
+	switch {
+	case opts.FeeRate != nil:
+		feeRate, err = ltcutil.NewAmount(*opts.FeeRate)
+		if err != nil || feeRate < 0 {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidParameter,
+				Message: "Invalid feeRate",
+			}
+		}
+		if feeRate < minRelayTxFee {
+			return nil, &btcjson.RPCError{
+				Code: btcjson.ErrRPCInvalidParameter,
+				Message: fmt.Sprintf("feeRate %v is below the "+
+					"minimum relay fee %v", feeRate,
+					minRelayTxFee),
+			}
+		}
+
+	case s.cfg.FeeEstimator != nil:
+		confTarget := uint32(6)
+		if opts.ConfTarget != nil && *opts.ConfTarget > 0 {
+			confTarget = uint32(*opts.ConfTarget)
+		}
+		estimate, err := s.cfg.FeeEstimator.EstimateFee(confTarget)
+		if err != nil {
+			break
+		}
+		rate, err := ltcutil.NewAmount(float64(estimate))
+		if err == nil && rate > feeRate {
+			feeRate = rate
+		}
+	}
+
+	// Gather the previous outputs spent by any inputs already present in
+	// the transaction.
+	var preselected []txauthor.Coin
+	for _, txIn := range mtx.TxIn {
+		prevOut, err := fetchTracePrevOut(s, &txIn.PreviousOutPoint)
+		if err != nil {
+			return nil, err
+		}
+		preselected = append(preselected, txauthor.Coin{
+			OutPoint: txIn.PreviousOutPoint,
+			Value:    ltcutil.Amount(prevOut.Value),
+			PkScript: prevOut.PkScript,
+		})
+	}
+
+	authored, err := txauthor.FundTransaction(mtx, source, &txauthor.Options{
+		FeeRate:                feeRate,
+		ChangeScript:           changeScript,
+		Strategy:               txauthor.BranchAndBound,
+		DustRelayFee:           minRelayTxFee,
+		SubtractFeeFromOutputs: opts.SubtractFeeFromOutputs,
+		Replaceable:            opts.Replaceable != nil && *opts.Replaceable,
+		Preselected:            preselected,
+		LockTime:               lockTime,
+		AntiFeeSniping:         s.cfg.SyncMgr.IsCurrent(),
+		BestHeight:             s.cfg.Chain.BestSnapshot().Height,
+	})
+	switch {
+	case err == txauthor.ErrInsufficientFunds:
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWalletInsufficientFunds,
+			Message: "Insufficient funds",
+		}
+	case err != nil:
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Failed to fund transaction: " + err.Error(),
+		}
+	}
+
+	// Place the change output at the requested position, or a random one
+	// so it can't be identified by its position.
+	if opts.ChangePosition != nil && authored.ChangeIndex >= 0 {
+		err := authored.SetChangePosition(*opts.ChangePosition)
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidParameter,
+				Message: "changePosition out of bounds",
+			}
+		}
+	} else {
+		authored.RandomizeChangePosition()
+	}
+
+	mtxHex, err := messageToHex(authored.Tx)
+	if err != nil {
+		return nil, err
+	}
+	return &fundRawTransactionResult{
+		Hex:            mtxHex,
+		Fee:            authored.Fee.ToBTC(),
+		ChangePosition: authored.ChangeIndex,
+	}, nil
+}
+
+// handleGenerate handles generate commands.
+func handleGenerate(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	// Respond with an error if there are no addresses to pay the
+	// created blocks to.
+	if len(cfg.miningAddrs) == 0 {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInternal.Code,
+			Message: "No payment addresses specified " +
+				"via --miningaddr",
+		}
+	}
+
+	// Respond with an error if there's virtually 0 chance of mining a block
+	// with the CPU.
+	if !s.cfg.ChainParams.GenerateSupported {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCDifficulty,
+			Message: fmt.Sprintf("No support for `generate` on "+
+				"the current network, %s, as it's unlikely to "+
+				"be possible to mine a block with the CPU.",
+				s.cfg.ChainParams.Net),
+		}
+	}
+
+	c := cmd.(*btcjson.GenerateCmd)
+
+	// Respond with an error if the client is requesting 0 blocks to be generated.
+	if c.NumBlocks == 0 {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInternal.Code,
+			Message: "Please request a nonzero number of blocks to generate.",
+		}
+	}
+
+	// Create a reply
+	reply := make([]string, c.NumBlocks)
+
+	blockHashes, err := s.cfg.CPUMiner.GenerateNBlocks(c.NumBlocks)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInternal.Code,
+			Message: err.Error(),
+		}
+	}
+
+	// Mine the correct number of blocks, assigning the hex representation of the
+	// hash of each one to its place in the reply.
+	for i, hash := range blockHashes {
+		reply[i] = hash.String()
+	}
+
+	return reply, nil
+}
+
+// handleGenerateBlock implements the generateblock command.
+func handleGenerateBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	// Respond with an error if there's virtually 0 chance of mining a block
+	// with the CPU.
+	params := s.cfg.ChainParams
+	if !params.GenerateSupported {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCDifficulty,
+			Message: fmt.Sprintf("No support for `generateblock` on "+
+				"the current network, %s, as it's unlikely to "+
+				"be possible to mine a block with the CPU.",
+				params.Net),
+		}
+	}
+
+	c := cmd.(*btcjson.GenerateBlockCmd)
+
+	payToAddr, err := ltcutil.DecodeAddress(c.Output, params)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "Invalid address or key: " + err.Error(),
+		}
+	}
+	if !payToAddr.IsForNet(params) {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "Invalid address or key: " +
+				"address is for the wrong network",
+		}
+	}
+
+	// Each transaction is either the txid of a transaction in the memory
+	// pool or a raw transaction.
+	txns := make([]*ltcutil.Tx, 0, len(c.Transactions))
+	for _, txStr := range c.Transactions {
+		if len(txStr) == chainhash.MaxHashStringSize {
+			txHash, err := chainhash.NewHashFromStr(txStr)
+			if err != nil {
+				return nil, rpcDecodeHexError(txStr)
+			}
+			tx, err := s.cfg.TxMemPool.FetchTransaction(txHash)
+			if err != nil {
+				return nil, &btcjson.RPCError{
+					Code: btcjson.ErrRPCInvalidAddressOrKey,
+					Message: fmt.Sprintf("Transaction %v not "+
+						"in mempool", txHash),
+				}
+			}
+			txns = append(txns, tx)
+			continue
+		}
+
+		hexStr := txStr
+		if len(hexStr)%2 != 0 {
+			hexStr = "0" + hexStr
+		}
+		serializedTx, err := hex.DecodeString(hexStr)
+		if err != nil {
+			return nil, rpcDecodeHexError(hexStr)
+		}
+		var msgTx wire.MsgTx
+		err = msgTx.Deserialize(bytes.NewReader(serializedTx))
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCDeserialization,
+				Message: "TX decode failed: " + err.Error(),
+			}
+		}
+		txns = append(txns, ltcutil.NewTx(&msgTx))
+	}
+
+	var ts time.Time
+	if c.Timestamp != nil {
+		ts = time.Unix(*c.Timestamp, 0)
+	}
+
+	blockHash, err := s.cfg.CPUMiner.GenerateBlock(payToAddr, txns, ts)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCVerify,
+			Message: "Unable to generate block: " + err.Error(),
+		}
+	}
+
+	return &btcjson.GenerateBlockResult{Hash: blockHash.String()}, nil
+}
+
+// handleGetAddedNodeInfo handles getaddednodeinfo commands.
+func handleGetAddedNodeInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetAddedNodeInfoCmd)
+
+	// Retrieve a list of persistent (added) peers from the server and
+	// filter the list of peers per the specified address (if any).
+	peers := s.cfg.ConnMgr.PersistentPeers()
+	if c.Node != nil {
+		node := *c.Node
+		found := false
+		for i, peer := range peers {
+			if peer.ToPeer().Addr() == node {
+				peers = peers[i : i+1]
+				found = true
+			}
+		}
+		if !found {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCClientNodeNotAdded,
+				Message: "Node has not been added",
+			}
+		}
+	}
+
+	// Without the dns flag, the result is just a slice of the addresses as
+	// strings.
+	if !c.DNS {
+		results := make([]string, 0, len(peers))
+		for _, peer := range peers {
+			results = append(results, peer.ToPeer().Addr())
+		}
+		return results, nil
+	}
+
+	// With the dns flag, the result is an array of JSON objects which
+	// include the result of DNS lookups for each peer.
+	results := make([]*btcjson.GetAddedNodeInfoResult, 0, len(peers))
+	for _, rpcPeer := range peers {
+		// Set the "address" of the peer which could be an ip address
+		// or a domain name.
+		peer := rpcPeer.ToPeer()
+		var result btcjson.GetAddedNodeInfoResult
+		result.AddedNode = peer.Addr()
+		result.Connected = btcjson.Bool(peer.Connected())
+
+		// Split the address into host and port portions so we can do
+		// a DNS lookup against the host.  When no port is specified in
+		// the address, just use the address as the host.
+		host, _, err := net.SplitHostPort(peer.Addr())
+		if err != nil {
+			host = peer.Addr()
+		}
+
+		var ipList []string
+		switch {
+		case net.ParseIP(host) != nil, strings.HasSuffix(host, ".onion"):
+			ipList = make([]string, 1)
+			ipList[0] = host
+		default:
+			// Do a DNS lookup for the address.  If the lookup fails, just
+			// use the host.
+			ips, err := ltcdLookup(host)
+			if err != nil {
+				ipList = make([]string, 1)
+				ipList[0] = host
+				break
+			}
+			ipList = make([]string, 0, len(ips))
+			for _, ip := range ips {
+				ipList = append(ipList, ip.String())
+			}
+		}
+
+		// Add the addresses and connection info to the result.
+		addrs := make([]btcjson.GetAddedNodeInfoResultAddr, 0, len(ipList))
+		for _, ip := range ipList {
+			var addr btcjson.GetAddedNodeInfoResultAddr
+			addr.Address = ip
+			addr.Connected = "false"
+			if ip == host && peer.Connected() {
+				addr.Connected = directionString(peer.Inbound())
+			}
+			addrs = append(addrs, addr)
+		}
+		result.Addresses = &addrs
+		results = append(results, &result)
+	}
+	return results, nil
+}
+
+// addrManNetwork returns the name of the network of the passed address as
+// reported by the getaddrmaninfo command, which groups both versions of Tor
+// addresses as onion like Bitcoin Core.
+func addrManNetwork(network string) string {
+	switch network {
+	case "torv2", "torv3":
+		return "onion"
+	}
+	return network
+}
+
+// handleGetAddrManInfo implements the getaddrmaninfo command.
+func handleGetAddrManInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetAddrManInfoCmd)
+
+	sample := 0
+	if c.Sample != nil {
+		sample = *c.Sample
+		if sample < 0 {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidParameter,
+				Message: "Sample size out of range",
+			}
+		}
+	}
+
+	// Every network is reported, even without any known address, so the
+	// diversity of the addresses is apparent at a glance.
+	stats := s.cfg.ConnMgr.AddrManagerStats()
+	networks := map[string]btcjson.AddrManNetworkResult{
+		"ipv4":         {},
+		"ipv6":         {},
+		"onion":        {},
+		"i2p":          {},
+		"all_networks": {},
+	}
+	for name, netStats := range stats.Networks {
+		for _, name := range []string{addrManNetwork(name), "all_networks"} {
+			result := networks[name]
+			result.New += netStats.New
+			result.Tried += netStats.Tried
+			result.Total += netStats.New + netStats.Tried
+			networks[name] = result
+		}
+	}
+	result := &btcjson.GetAddrManInfoResult{
+		Networks:         networks,
+		Terrible:         stats.Terrible,
+		NewBucketsUsed:   stats.NewBucketsUsed,
+		TriedBucketsUsed: stats.TriedBucketsUsed,
+	}
+
+	if sample == 0 {
+		return result, nil
+	}
+	for _, info := range s.cfg.ConnMgr.SampleAddresses(sample) {
+		addr := btcjson.AddrManAddressResult{
+			Address:  info.Key,
+			Network:  addrManNetwork(info.Network),
+			Services: uint64(info.Addr.Services),
+			Time:     info.Addr.Timestamp.Unix(),
+			Source:   addrmgr.NetAddressKey(info.Src),
+			Tried:    info.TriedBucket >= 0,
+			Buckets:  info.NewBuckets,
+			Attempts: info.Attempts,
+		}
+		if addr.Tried {
+			addr.Buckets = []int{info.TriedBucket}
+		}
+		if !info.LastAttempt.IsZero() {
+			addr.LastAttempt = info.LastAttempt.Unix()
+		}
+		if !info.LastSuccess.IsZero() {
+			addr.LastSuccess = info.LastSuccess.Unix()
+		}
+		result.Sample = append(result.Sample, addr)
+	}
+	return result, nil
+}
+
+// handleGetBestBlock implements the getbestblock command.
+func handleGetBestBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	// All other "get block" commands give either the height, the
+	// hash, or both but require the block SHA.  This gets both for
+	// the best block.
+	best := s.cfg.Chain.BestSnapshot()
+	result := &btcjson.GetBestBlockResult{
+		Hash:   best.Hash.String(),
+		Height: best.Height,
+	}
+	return result, nil
+}
+
+// handleGetBestBlockHash implements the getbestblockhash command.
+func handleGetBestBlockHash(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	best := s.cfg.Chain.BestSnapshot()
+	return best.Hash.String(), nil
+}
+
+// getDifficultyRatio returns the proof-of-work difficulty as a multiple of the
+// minimum difficulty using the passed bits field from the header of a block.
+func getDifficultyRatio(bits uint32, params *chaincfg.Params) float64 {
+	// The minimum difficulty is the max possible proof-of-work limit bits
+	// converted back to a number.  Note this is not the same as the proof of
 	// work limit directly because the block difficulty is encoded in a block
 	// with the compact form which loses precision.
 	max := blockchain.CompactToBig(params.PowLimitBits)
@@ -1208,18 +1932,40 @@ func handleGetBlockChainInfo(s *rpcServer, cmd interface{}, closeChan <-chan str
 	chainSnapshot := chain.BestSnapshot()
 
 	chainInfo := &btcjson.GetBlockChainInfoResult{
-		Chain:         params.Name,
-		Blocks:        chainSnapshot.Height,
-		Headers:       chainSnapshot.Height,
-		BestBlockHash: chainSnapshot.Hash.String(),
-		Difficulty:    getDifficultyRatio(chainSnapshot.Bits, params),
-		MedianTime:    chainSnapshot.MedianTime.Unix(),
-		Pruned:        cfg.Prune != 0,
+		Chain:                params.Name,
+		Blocks:               chainSnapshot.Height,
+		Headers:              chainSnapshot.Height,
+		BestBlockHash:        chainSnapshot.Hash.String(),
+		Difficulty:           getDifficultyRatio(chainSnapshot.Bits, params),
+		MedianTime:           chainSnapshot.MedianTime.Unix(),
+		ChainWork:            fmt.Sprintf("%064x", chainSnapshot.WorkSum),
+		VerificationProgress: chain.VerificationProgress(),
+		InitialBlockDownload: !chain.IsCurrent(),
+		Pruned:               cfg.Prune != 0,
+		Warnings:             chain.WarningsString(),
 		SoftForks: &btcjson.SoftForks{
 			Bip9SoftForks: make(map[string]*btcjson.Bip9SoftForkDescription),
 		},
 	}
 
+	// The size on disk is only an estimate, so don't fail the request when
+	// some of the database files can't be examined.
+	sizeOnDisk, err := dirSize(blockDbPath(cfg.DbType))
+	if err != nil {
+		rpcsLog.Debugf("Unable to determine the block database size: %v",
+			err)
+	}
+	chainInfo.SizeOnDisk = sizeOnDisk
+
+	if chainInfo.Pruned {
+		pruneHeight, err := chain.PruneHeight()
+		if err != nil {
+			context := "Failed to obtain prune height"
+			return nil, internalRPCError(err.Error(), context)
+		}
+		chainInfo.PruneHeight = pruneHeight
+	}
+
 	// Next, populate the response with information describing the current
 	// status of soft-forks deployed via the super-majority block
 	// signalling mechanism.
@@ -1259,24 +2005,8 @@ func handleGetBlockChainInfo(s *rpcServer, cmd interface{}, closeChan <-chan str
 	for deployment, deploymentDetails := range params.Deployments {
 		// Map the integer deployment ID into a human readable
 		// fork-name.
-		var forkName string
-		switch deployment {
-		case chaincfg.DeploymentTestDummy:
-			forkName = "dummy"
-
-		case chaincfg.DeploymentTestDummyMinActivation:
-			forkName = "dummy-min-activation"
-
-		case chaincfg.DeploymentCSV:
-			forkName = "csv"
-
-		case chaincfg.DeploymentSegwit:
-			forkName = "segwit"
-
-		case chaincfg.DeploymentTaproot:
-			forkName = "taproot"
-
-		default:
+		forkName, ok := deploymentName(deployment)
+		if !ok {
 			return nil, &btcjson.RPCError{
 				Code: btcjson.ErrRPCInternal.Code,
 				Message: fmt.Sprintf("Unknown deployment %v "+
@@ -1285,8 +2015,10 @@ func handleGetBlockChainInfo(s *rpcServer, cmd interface{}, closeChan <-chan str
 		}
 
 		// Query the chain for the current status of the deployment as
-		// identified by its deployment ID.
-		deploymentStatus, err := chain.ThresholdState(uint32(deployment))
+		// identified by its deployment ID along with the height it has
+		// been in that status since.
+		deploymentStatus, since, err := chain.ThresholdStateSince(
+			uint32(deployment))
 		if err != nil {
 			context := "Failed to obtain deployment status"
 			return nil, internalRPCError(err.Error(), context)
@@ -1306,23 +2038,74 @@ func handleGetBlockChainInfo(s *rpcServer, cmd interface{}, closeChan <-chan str
 
 		// Finally, populate the soft-fork description with all the
 		// information gathered above.
-		var startTime, endTime int64
-		if starter, ok := deploymentDetails.DeploymentStarter.(*chaincfg.MedianTimeDeploymentStarter); ok {
-			startTime = starter.StartTime().Unix()
-		}
-		if ender, ok := deploymentDetails.DeploymentEnder.(*chaincfg.MedianTimeDeploymentEnder); ok {
-			endTime = ender.EndTime().Unix()
-		}
+		startTime, endTime := deploymentTimes(&deploymentDetails)
 		chainInfo.SoftForks.Bip9SoftForks[forkName] = &btcjson.Bip9SoftForkDescription{
 			Status:              strings.ToLower(statusString),
 			Bit:                 deploymentDetails.BitNumber,
 			StartTime2:          startTime,
 			Timeout:             endTime,
+			Since:               since,
 			MinActivationHeight: int32(deploymentDetails.MinActivationHeight),
 		}
 	}
 
-	return chainInfo, nil
+	return chainInfo, nil
+}
+
+// dirSize returns the total size in bytes of the files within the passed
+// directory and its subdirectories.  The size of the files which could be
+// examined is returned along with the first error encountered.
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// deploymentName returns the human readable name of the passed BIP0009
+// deployment ID and whether or not the deployment is known.
+func deploymentName(deployment int) (string, bool) {
+	switch deployment {
+	case chaincfg.DeploymentTestDummy:
+		return "dummy", true
+
+	case chaincfg.DeploymentTestDummyMinActivation:
+		return "dummy-min-activation", true
+
+	case chaincfg.DeploymentCSV:
+		return "csv", true
+
+	case chaincfg.DeploymentSegwit:
+		return "segwit", true
+
+	case chaincfg.DeploymentTaproot:
+		return "taproot", true
+
+	case chaincfg.DeploymentMweb:
+		return "mweb", true
+	}
+	return "", false
+}
+
+// deploymentTimes returns the median times at which the passed deployment
+// starts and times out as unix timestamps.  Zero is returned for either time
+// when the deployment does not start or end at a median time.
+func deploymentTimes(deployment *chaincfg.ConsensusDeployment) (int64, int64) {
+	var startTime, endTime int64
+	if starter, ok := deployment.DeploymentStarter.(*chaincfg.MedianTimeDeploymentStarter); ok {
+		startTime = starter.StartTime().Unix()
+	}
+	if ender, ok := deployment.DeploymentEnder.(*chaincfg.MedianTimeDeploymentEnder); ok {
+		endTime = ender.EndTime().Unix()
+	}
+	return startTime, endTime
 }
 
 // handleGetBlockCount implements the getblockcount command.
@@ -1331,6 +2114,39 @@ func handleGetBlockCount(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 	return int64(best.Height), nil
 }
 
+// handleGetBlockFromPeer implements the getblockfrompeer command.
+func handleGetBlockFromPeer(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetBlockFromPeerCmd)
+
+	hash, err := chainhash.NewHashFromStr(c.BlockHash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.BlockHash)
+	}
+
+	// There is nothing to fetch when the block is already known.
+	haveBlock, err := s.cfg.Chain.HaveBlock(hash)
+	if err != nil {
+		context := "Failed to check for the block"
+		return nil, internalRPCError(err.Error(), context)
+	}
+	if haveBlock {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCMisc,
+			Message: "Block already downloaded",
+		}
+	}
+
+	if err := s.cfg.SyncMgr.FetchBlock(hash, c.PeerID); err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCMisc,
+			Message: err.Error(),
+		}
+	}
+
+	// no data returned unless an error.
+	return nil, nil
+}
+
 // handleGetBlockHash implements the getblockhash command.
 func handleGetBlockHash(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*btcjson.GetBlockHashCmd)
@@ -1564,6 +2380,13 @@ func (state *gbtWorkState) templateUpdateChan(prevHash *chainhash.Hash, lastGene
 // with a randomly selected payment address from the list of configured
 // addresses.
 //
+// Between the generations of new block templates, the existing block template
+// is refreshed incrementally with the changes of the memory pool.
+//
+// When the fasttemplate option is set, only an empty block template is
+// generated when the current best block has changed so it can be returned right
+// away.  The full block template then replaces it in the background.
+//
 // This function MUST be called with the state locked.
 func (state *gbtWorkState) updateBlockTemplate(s *rpcServer, useCoinbaseValue bool) error {
 	generator := s.cfg.Generator
@@ -1580,11 +2403,16 @@ func (state *gbtWorkState) updateBlockTemplate(s *rpcServer, useCoinbaseValue bo
 	var targetDifficulty string
 	latestHash := &s.cfg.Chain.BestSnapshot().Hash
 	template := state.template
-	if template == nil || state.prevHash == nil ||
-		!state.prevHash.IsEqual(latestHash) ||
-		(state.lastTxUpdate != lastTxUpdate &&
-			time.Now().After(state.lastGenerated.Add(time.Second*
-				gbtRegenerateSeconds))) {
+	tipChanged := template == nil || state.prevHash == nil ||
+		!state.prevHash.IsEqual(latestHash)
+	if tipChanged || (state.lastTxUpdate != lastTxUpdate &&
+		time.Now().After(state.lastRebuilt.Add(time.Second*
+			gbtRegenerateSeconds))) {
+
+		// Only an empty block template is generated right away when
+		// the best block has changed and fast templates are enabled.
+		// The full template is generated in the background.
+		emptyTemplate := cfg.FastTemplate && tipChanged
 
 		// Reset the previous best hash the block template was generated
 		// against so any errors below cause the next invocation to try
@@ -1604,7 +2432,13 @@ func (state *gbtWorkState) updateBlockTemplate(s *rpcServer, useCoinbaseValue bo
 		// block template doesn't include the coinbase, so the caller
 		// will ultimately create their own coinbase which pays to the
 		// appropriate address(es).
-		blkTemplate, err := generator.NewBlockTemplate(payAddr)
+		var blkTemplate *mining.BlockTemplate
+		var err error
+		if emptyTemplate {
+			blkTemplate, err = generator.NewEmptyBlockTemplate(payAddr)
+		} else {
+			blkTemplate, err = generator.NewBlockTemplate(payAddr)
+		}
 		if err != nil {
 			return internalRPCError("Failed to create new block "+
 				"template: "+err.Error(), "")
@@ -1624,6 +2458,7 @@ func (state *gbtWorkState) updateBlockTemplate(s *rpcServer, useCoinbaseValue bo
 		// generated until needed.
 		state.template = template
 		state.lastGenerated = time.Now()
+		state.lastRebuilt = state.lastGenerated
 		state.lastTxUpdate = lastTxUpdate
 		state.prevHash = latestHash
 		state.minTimestamp = minTimestamp
@@ -1636,6 +2471,10 @@ func (state *gbtWorkState) updateBlockTemplate(s *rpcServer, useCoinbaseValue bo
 		// Notify any clients that are long polling about the new
 		// template.
 		state.notifyLongPollers(latestHash, lastTxUpdate)
+
+		if emptyTemplate {
+			go state.completeEmptyTemplate(s, template, payAddr)
+		}
 	} else {
 		// At this point, there is a saved block template and another
 		// request for a template was made, but either the available
@@ -1643,6 +2482,39 @@ func (state *gbtWorkState) updateBlockTemplate(s *rpcServer, useCoinbaseValue bo
 		// trigger a new block template to be generated.  So, update the
 		// existing block template.
 
+		// Update the transactions of the block template incrementally
+		// when the transactions in the memory pool have changed.  The
+		// transactions which are still in the memory pool are kept
+		// without validating them again.
+		if state.lastTxUpdate != lastTxUpdate {
+			blkTemplate, err := generator.RefreshBlockTemplate(template)
+			if err != nil {
+				return internalRPCError("Failed to refresh "+
+					"block template: "+err.Error(), "")
+			}
+			template = blkTemplate
+			state.template = template
+			state.lastTxUpdate = lastTxUpdate
+
+			rpcsLog.Debugf("Refreshed block template (%d "+
+				"transactions, merkle root %s)",
+				len(template.Block.Transactions),
+				template.Block.Header.MerkleRoot)
+
+			// Notify any clients that are long polling about the
+			// updated template at most once every
+			// gbtRegenerateSeconds, the same as for newly generated
+			// templates, so frequent refreshes don't wake them up
+			// for every transaction.
+			if time.Now().After(state.lastGenerated.Add(
+				time.Second * gbtRegenerateSeconds)) {
+
+				state.lastGenerated = time.Now()
+				state.notifyLongPollers(latestHash,
+					state.lastGenerated)
+			}
+		}
+
 		// When the caller requires a full coinbase as opposed to only
 		// the pertinent details needed to create their own coinbase,
 		// add a payment address to the output of the coinbase of the
@@ -1688,6 +2560,58 @@ func (state *gbtWorkState) updateBlockTemplate(s *rpcServer, useCoinbaseValue bo
 	return nil
 }
 
+// nextGenerated returns the time to use as the generation time of a block
+// template which replaces the current one on the same best block.  The long
+// poll ID of a template only has a resolution of a second, so the time is moved
+// to the next second when clients are long polling the current template in the
+// same second, which gives the new template a different ID they are notified
+// about.  It is never moved ahead of the current time otherwise.
+//
+// This function MUST be called with the state locked.
+func (state *gbtWorkState) nextGenerated() time.Time {
+	generated := time.Now()
+	if generated.Unix() <= state.lastGenerated.Unix() &&
+		state.prevHash != nil && len(state.notifyMap[*state.prevHash]) > 0 {
+
+		generated = time.Unix(state.lastGenerated.Unix()+1, 0)
+	}
+	return generated
+}
+
+// completeEmptyTemplate generates the full block template which replaces the
+// passed empty block template and notifies any clients that are long polling
+// about it.  The full template is dropped if the empty template has already
+// been replaced, such as when the best block changed again in the meantime.
+//
+// This function MUST be called without the state locked.
+func (state *gbtWorkState) completeEmptyTemplate(s *rpcServer,
+	emptyTemplate *mining.BlockTemplate, payAddr ltcutil.Address) {
+
+	template, err := s.cfg.Generator.NewBlockTemplate(payAddr)
+	if err != nil {
+		rpcsLog.Warnf("Failed to create full block template: %v", err)
+		return
+	}
+
+	state.Lock()
+	defer state.Unlock()
+
+	if state.template != emptyTemplate {
+		return
+	}
+
+	lastGenerated := state.nextGenerated()
+	state.template = template
+	state.lastGenerated = lastGenerated
+	state.lastRebuilt = lastGenerated
+
+	rpcsLog.Debugf("Generated full block template (%d transactions, "+
+		"merkle root %s)", len(template.Block.Transactions),
+		template.Block.Header.MerkleRoot)
+
+	state.notifyLongPollers(state.prevHash, lastGenerated)
+}
+
 // blockTemplateResult returns the current block template associated with the
 // state as a btcjson.GetBlockTemplateResult that is ready to be encoded to JSON
 // and returned to the caller.
@@ -1776,8 +2700,8 @@ func (state *gbtWorkState) blockTemplateResult(useCoinbaseValue bool, submitOld
 		CurTime:      header.Timestamp.Unix(),
 		Height:       int64(template.Height),
 		PreviousHash: header.PrevBlock.String(),
-		WeightLimit:  blockchain.MaxBlockWeight,
-		SigOpLimit:   blockchain.MaxBlockSigOpsCost,
+		WeightLimit:  blockchain.BlockWeightLimit(state.chainParams),
+		SigOpLimit:   blockchain.BlockSigOpsCostLimit(state.chainParams),
 		SizeLimit:    wire.MaxBlockPayload,
 		Transactions: transactions,
 		Version:      header.Version,
@@ -1796,6 +2720,12 @@ func (state *gbtWorkState) blockTemplateResult(useCoinbaseValue bool, submitOld
 		reply.DefaultWitnessCommitment = hex.EncodeToString(template.WitnessCommitment)
 	}
 
+	// Blocks of signet networks must solve the signet challenge, so include
+	// it for the miners to sign the block with.
+	if challenge := state.chainParams.SignetChallenge; challenge != nil {
+		reply.SignetChallenge = hex.EncodeToString(challenge)
+	}
+
 	if useCoinbaseValue {
 		reply.CoinbaseAux = gbtCoinbaseAux
 		reply.CoinbaseValue = &msgBlock.Transactions[0].TxOut[0].Value
@@ -1829,6 +2759,24 @@ func (state *gbtWorkState) blockTemplateResult(useCoinbaseValue bool, submitOld
 		}
 
 		reply.CoinbaseTxn = &resultTx
+
+		// Report where the bytes reserved for the extra nonces of
+		// mining pools are.  Within the serialized transaction, the
+		// signature script of the only input comes after the version,
+		// the segwit marker and flag when there is a witness, the input
+		// count, the previous outpoint and the script length.
+		if template.CoinbaseReservedSize > 0 {
+			txIn := tx.TxIn[0]
+			dataOffset := 4 + wire.VarIntSerializeSize(1) + 36 +
+				wire.VarIntSerializeSize(uint64(len(txIn.SignatureScript))) +
+				template.CoinbaseReservedOffset
+			if tx.HasWitness() {
+				dataOffset += 2
+			}
+			reply.CoinbaseReservedSize = template.CoinbaseReservedSize
+			reply.CoinbaseReservedOffset = template.CoinbaseReservedOffset
+			reply.CoinbaseReservedDataOffset = dataOffset
+		}
 	}
 
 	return &reply, nil
@@ -2206,6 +3154,31 @@ func handleGetBlockTemplate(s *rpcServer, cmd interface{}, closeChan <-chan stru
 	}
 }
 
+// handleGetBlockSubsidy implements the getblocksubsidy command.
+func handleGetBlockSubsidy(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetBlockSubsidyCmd)
+
+	// Default to the height of the next block.
+	var height int32
+	switch {
+	case c.Height == nil:
+		height = s.cfg.Chain.BestSnapshot().Height + 1
+	case *c.Height < 0:
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCOutOfRange,
+			Message: "Block height out of range",
+		}
+	default:
+		height = *c.Height
+	}
+
+	subsidy := blockchain.CalcBlockSubsidy(height, s.cfg.ChainParams)
+	return &btcjson.GetBlockSubsidyResult{
+		Height:  height,
+		Subsidy: ltcutil.Amount(subsidy).ToBTC(),
+	}, nil
+}
+
 // handleGetCFilter implements the getcfilter command.
 func handleGetCFilter(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	if s.cfg.CfIndex == nil {
@@ -2266,20 +3239,244 @@ func handleGetCFilterHeader(s *rpcServer, cmd interface{}, closeChan <-chan stru
 	return hash.String(), nil
 }
 
+// handleGetChainTips implements the getchaintips command.
+func handleGetChainTips(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetChainTipsCmd)
+	tips := s.cfg.Chain.ChainTips()
+	results := make([]btcjson.GetChainTipsResult, 0, len(tips))
+	for _, tip := range tips {
+		results = append(results, btcjson.GetChainTipsResult{
+			Height:    tip.Height,
+			Hash:      tip.Hash.String(),
+			BranchLen: tip.BranchLen,
+			Status:    tip.Status.String(),
+		})
+	}
+	if c.Verbose == nil || !*c.Verbose {
+		return results, nil
+	}
+
+	// Include how many stale side chain blocks have been removed from the
+	// block index so operators can see forks are being cleaned up.
+	stats := s.cfg.Chain.StaleForkStats()
+	return &btcjson.GetChainTipsVerboseResult{
+		Tips:              results,
+		StaleForkDepth:    stats.Depth,
+		PrunedStaleBlocks: stats.PrunedBlocks,
+		LastPruneHeight:   stats.LastPruneHeight,
+	}, nil
+}
+
 // handleGetConnectionCount implements the getconnectioncount command.
 func handleGetConnectionCount(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	return s.cfg.ConnMgr.ConnectedCount(), nil
 }
 
+// handleGetChainParams implements the getchainparams command.
+func handleGetChainParams(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	params := s.cfg.ChainParams
+
+	dnsSeeds := make([]string, 0, len(params.DNSSeeds))
+	for _, seed := range params.DNSSeeds {
+		dnsSeeds = append(dnsSeeds, seed.Host)
+	}
+
+	checkpoints := make([]btcjson.ChainParamsCheckpoint, 0,
+		len(params.Checkpoints))
+	for _, checkpoint := range params.Checkpoints {
+		checkpoints = append(checkpoints, btcjson.ChainParamsCheckpoint{
+			Height: checkpoint.Height,
+			Hash:   checkpoint.Hash.String(),
+		})
+	}
+
+	deployments := make([]btcjson.ChainParamsDeployment, 0,
+		len(params.Deployments))
+	for id := range params.Deployments {
+		deployment := &params.Deployments[id]
+		name, ok := deploymentName(id)
+		if !ok {
+			return nil, &btcjson.RPCError{
+				Code: btcjson.ErrRPCInternal.Code,
+				Message: fmt.Sprintf("Unknown deployment %v "+
+					"detected", id),
+			}
+		}
+		threshold := params.RuleChangeActivationThreshold
+		if deployment.CustomActivationThreshold != 0 {
+			threshold = deployment.CustomActivationThreshold
+		}
+		startTime, endTime := deploymentTimes(deployment)
+		deployments = append(deployments, btcjson.ChainParamsDeployment{
+			Name:                name,
+			Bit:                 deployment.BitNumber,
+			StartTime:           startTime,
+			Timeout:             endTime,
+			MinActivationHeight: deployment.MinActivationHeight,
+			Threshold:           threshold,
+		})
+	}
+
+	var minimumChainWork string
+	if params.MinimumChainWork != nil {
+		minimumChainWork = fmt.Sprintf("%064x", params.MinimumChainWork)
+	}
+
+	return &btcjson.GetChainParamsResult{
+		Name:                          params.Name,
+		Net:                           uint32(params.Net),
+		DefaultPort:                   params.DefaultPort,
+		DNSSeeds:                      dnsSeeds,
+		GenesisHash:                   params.GenesisHash.String(),
+		PowLimit:                      fmt.Sprintf("%064x", params.PowLimit),
+		PowLimitBits:                  strconv.FormatInt(int64(params.PowLimitBits), 16),
+		PoWNoRetargeting:              params.PoWNoRetargeting,
+		BIP0034Height:                 params.BIP0034Height,
+		BIP0065Height:                 params.BIP0065Height,
+		BIP0066Height:                 params.BIP0066Height,
+		AllowLegacyBlockVersions:      params.AllowLegacyBlockVersions,
+		EnforceStrictDER:              params.EnforceStrictDER,
+		AllowNonStrictDER:             params.AllowNonStrictDER,
+		CoinbaseMaturity:              params.CoinbaseMaturity,
+		MwebPegoutMaturity:            params.MwebPegoutMaturity,
+		SubsidyReductionInterval:      params.SubsidyReductionInterval,
+		TargetTimespan:                int64(params.TargetTimespan / time.Second),
+		TargetTimePerBlock:            int64(params.TargetTimePerBlock / time.Second),
+		RetargetAdjustmentFactor:      params.RetargetAdjustmentFactor,
+		ReduceMinDifficulty:           params.ReduceMinDifficulty,
+		MinDiffReductionTime:          int64(params.MinDiffReductionTime / time.Second),
+		LWMAHeight:                    params.LWMAHeight,
+		LWMAFixHeight:                 params.LWMAFixHeight,
+		LWMAWindow:                    params.LWMAWindow,
+		ASERTHeight:                   params.ASERTHeight,
+		ASERTHalfLife:                 params.ASERTHalfLife,
+		ASERTAnchorBits:               strconv.FormatInt(int64(params.ASERTAnchorBits), 16),
+		MinimumChainWork:              minimumChainWork,
+		SignetChallenge:               hex.EncodeToString(params.SignetChallenge),
+		Checkpoints:                   checkpoints,
+		AdvisoryCheckpoints:           params.AdvisoryCheckpoints,
+		RuleChangeActivationThreshold: params.RuleChangeActivationThreshold,
+		MinerConfirmationWindow:       params.MinerConfirmationWindow,
+		Deployments:                   deployments,
+		RelayNonStdTxs:                params.RelayNonStdTxs,
+		Bech32HRPSegwit:               params.Bech32HRPSegwit,
+		Bech32HRPMweb:                 params.Bech32HRPMweb,
+		PubKeyHashAddrID:              params.PubKeyHashAddrID,
+		ScriptHashAddrID:              params.ScriptHashAddrID,
+		PrivateKeyID:                  params.PrivateKeyID,
+		WitnessPubKeyHashAddrID:       params.WitnessPubKeyHashAddrID,
+		WitnessScriptHashAddrID:       params.WitnessScriptHashAddrID,
+		HDPrivateKeyID:                hex.EncodeToString(params.HDPrivateKeyID[:]),
+		HDPublicKeyID:                 hex.EncodeToString(params.HDPublicKeyID[:]),
+		HDCoinType:                    params.HDCoinType,
+	}, nil
+}
+
 // handleGetCurrentNet implements the getcurrentnet command.
 func handleGetCurrentNet(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	return s.cfg.ChainParams.Net, nil
 }
 
+// handleGetDescriptorInfo implements the getdescriptorinfo command.
+func handleGetDescriptorInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetDescriptorInfoCmd)
+
+	desc, err := descriptor.Parse(c.Descriptor, false, s.cfg.ChainParams)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: err.Error(),
+		}
+	}
+
+	// The checksum is that of the passed descriptor, which has already
+	// been verified above if it was included.
+	body := c.Descriptor
+	if idx := strings.IndexByte(body, '#'); idx != -1 {
+		body = body[:idx]
+	}
+	checksum, err := descriptor.Checksum(body)
+	if err != nil {
+		return nil, internalRPCError(err.Error(), "")
+	}
+
+	return &btcjson.GetDescriptorInfoResult{
+		Descriptor:     desc.String(),
+		Checksum:       checksum,
+		IsRange:        desc.IsRange(),
+		IsSolvable:     desc.IsSolvable(),
+		HasPrivateKeys: desc.HasPrivateKeys(),
+	}, nil
+}
+
 // handleGetDifficulty implements the getdifficulty command.
 func handleGetDifficulty(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetDifficultyCmd)
 	best := s.cfg.Chain.BestSnapshot()
-	return getDifficultyRatio(best.Bits, s.cfg.ChainParams), nil
+	params := s.cfg.ChainParams
+	difficulty := getDifficultyRatio(best.Bits, params)
+	if c.Verbose == nil || !*c.Verbose {
+		return difficulty, nil
+	}
+
+	// Report the difficulty algorithm which determined the difficulty of
+	// the best block since the meaning of the difficulty changes with it.
+	return &btcjson.GetDifficultyResult{
+		Difficulty: difficulty,
+		Algo:       blockchain.DifficultyAlgorithm(best.Height, params),
+		Height:     best.Height,
+		Bits:       strconv.FormatInt(int64(best.Bits), 16),
+		PowLimit:   fmt.Sprintf("%064x", params.PowLimit),
+	}, nil
+}
+
+// handleGetDifficultyHistory implements the getdifficultyhistory command.
+func handleGetDifficultyHistory(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetDifficultyHistoryCmd)
+
+	// Default to the height of the current best block.
+	var endHeight int32
+	if c.EndHeight != nil {
+		endHeight = *c.EndHeight
+	} else {
+		endHeight = s.cfg.Chain.BestSnapshot().Height
+	}
+	if c.StartHeight < 0 || endHeight < c.StartHeight {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCOutOfRange,
+			Message: "Block height out of range",
+		}
+	}
+	if int64(endHeight)-int64(c.StartHeight) >= maxDifficultyHistory {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidParameter,
+			Message: fmt.Sprintf("At most %d blocks may be requested",
+				maxDifficultyHistory),
+		}
+	}
+
+	entries, err := s.cfg.Chain.DifficultyHistory(c.StartHeight, endHeight)
+	if err != nil {
+		context := "Failed to fetch difficulty history"
+		return nil, internalRPCError(err.Error(), context)
+	}
+
+	params := s.cfg.ChainParams
+	history := make([]btcjson.GetDifficultyHistoryResult, 0, len(entries))
+	for _, entry := range entries {
+		history = append(history, btcjson.GetDifficultyHistoryResult{
+			Height: entry.Height,
+			Hash:   entry.Hash.String(),
+			Bits:   strconv.FormatInt(int64(entry.Bits), 16),
+			Target: fmt.Sprintf("%064x",
+				blockchain.CompactToBig(entry.Bits)),
+			Difficulty: getDifficultyRatio(entry.Bits, params),
+			Time:       entry.Timestamp,
+			SolveTime:  entry.SolveTime,
+			Algorithm:  blockchain.DifficultyAlgorithm(entry.Height, params),
+		})
+	}
+	return history, nil
 }
 
 // handleGetGenerate implements the getgenerate command.
@@ -2333,6 +3530,26 @@ func handleGetHeaders(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 	return hexBlockHeaders, nil
 }
 
+// handleGetIndexInfo implements the getindexinfo command.
+func handleGetIndexInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetIndexInfoCmd)
+
+	result := make(map[string]btcjson.GetIndexInfoResult)
+	if s.cfg.IndexManager == nil {
+		return result, nil
+	}
+	for _, status := range s.cfg.IndexManager.Statuses() {
+		if c.IndexName != nil && *c.IndexName != status.Name {
+			continue
+		}
+		result[status.Name] = btcjson.GetIndexInfoResult{
+			Synced:          status.Synced,
+			BestBlockHeight: status.Height,
+		}
+	}
+	return result, nil
+}
+
 // handleGetInfo implements the getinfo command. We only return the fields
 // that are not related to wallet functionality.
 func handleGetInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
@@ -2346,12 +3563,69 @@ func handleGetInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (in
 		Proxy:           cfg.Proxy,
 		Difficulty:      getDifficultyRatio(best.Bits, s.cfg.ChainParams),
 		TestNet:         cfg.TestNet4,
-		RelayFee:        cfg.minRelayTxFee.ToBTC(),
+		RelayFee:        s.cfg.TxMemPool.Policy().MinRelayTxFee.ToBTC(),
 	}
 
 	return ret, nil
 }
 
+// cacheStatsResult converts the passed cache statistics to the form returned by
+// the getmemoryinfo command.
+func cacheStatsResult(stats txscript.CacheStats) btcjson.GetMemoryInfoCacheResult {
+	return btcjson.GetMemoryInfoCacheResult{
+		Entries:    stats.Entries,
+		MaxEntries: stats.MaxEntries,
+		Hits:       stats.Hits,
+		Misses:     stats.Misses,
+	}
+}
+
+// handleGetMemoryInfo implements the getmemoryinfo command.
+func handleGetMemoryInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetMemoryInfoCmd)
+
+	switch *c.Mode {
+	case "stats":
+	case "mallocinfo":
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "mallocinfo is only available when compiled with glibc 2.10+",
+		}
+	default:
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: fmt.Sprintf("unknown mode %s", *c.Mode),
+		}
+	}
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	result := &btcjson.GetMemoryInfoResult{
+		Runtime: btcjson.GetMemoryInfoRuntimeResult{
+			Alloc:        memStats.Alloc,
+			TotalAlloc:   memStats.TotalAlloc,
+			Sys:          memStats.Sys,
+			HeapAlloc:    memStats.HeapAlloc,
+			HeapInuse:    memStats.HeapInuse,
+			HeapIdle:     memStats.HeapIdle,
+			HeapReleased: memStats.HeapReleased,
+			HeapObjects:  memStats.HeapObjects,
+			StackInuse:   memStats.StackInuse,
+			NumGC:        memStats.NumGC,
+			GCPauseTotal: int64(memStats.PauseTotalNs / uint64(time.Millisecond)),
+			Goroutines:   runtime.NumGoroutine(),
+		},
+	}
+	if s.cfg.SigCache != nil {
+		result.Caches.SigCache = cacheStatsResult(s.cfg.SigCache.Stats())
+	}
+	if s.cfg.ScriptCache != nil {
+		result.Caches.ScriptCache = cacheStatsResult(
+			s.cfg.ScriptCache.Stats())
+	}
+	return result, nil
+}
+
 // handleGetMempoolInfo implements the getmempoolinfo command.
 func handleGetMempoolInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	mempoolTxns := s.cfg.TxMemPool.TxDescs()
@@ -2405,6 +3679,39 @@ func handleGetMiningInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 	return &result, nil
 }
 
+// handleGetMisbehavingLog implements the getmisbehavinglog command.
+func handleGetMisbehavingLog(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.GetMisbehavingLogCmd)
+	count := 100
+	if c.Count != nil {
+		count = *c.Count
+	}
+	if count < 0 {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Count must not be negative",
+		}
+	}
+
+	events := s.cfg.MisbehaviorLog.recent(count)
+	results := make([]btcjson.MisbehaviorEventResult, 0, len(events))
+	for i := range events {
+		event := &events[i]
+		results = append(results, btcjson.MisbehaviorEventResult{
+			Time:     event.time.Unix(),
+			Kind:     event.kind,
+			ID:       event.id,
+			Addr:     event.addr,
+			Inbound:  event.inbound,
+			BanScore: event.banScore,
+			Reason:   event.reason,
+			Command:  event.command,
+			MsgHex:   event.msgHex,
+		})
+	}
+	return results, nil
+}
+
 // handleGetNetTotals implements the getnettotals command.
 func handleGetNetTotals(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	totalBytesRecv, totalBytesSent := s.cfg.ConnMgr.NetTotals()
@@ -2510,6 +3817,75 @@ func handleGetNetworkHashPS(s *rpcServer, cmd interface{}, closeChan <-chan stru
 	return hashesPerSec, nil
 }
 
+// handleGetNetworkInfo implements the getnetworkinfo command.
+func handleGetNetworkInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	var version wire.MsgVersion
+	version.UserAgent = wire.DefaultUserAgent
+	err := version.AddUserAgent(userAgentName, userAgentVersion,
+		cfg.UserAgentComments...)
+	if err != nil {
+		context := "Failed to build user agent"
+		return nil, internalRPCError(err.Error(), context)
+	}
+
+	var connectionsIn, connectionsOut int32
+	for _, p := range s.cfg.ConnMgr.ConnectedPeers() {
+		if p.ToPeer().Inbound() {
+			connectionsIn++
+		} else {
+			connectionsOut++
+		}
+	}
+
+	// Onion addresses are reachable through either the onion proxy or the
+	// general proxy unless they are disabled.
+	onionProxy := cfg.OnionProxy
+	if onionProxy == "" {
+		onionProxy = cfg.Proxy
+	}
+	networks := []btcjson.NetworksResult{
+		{
+			Name:                      "ipv4",
+			Reachable:                 true,
+			Proxy:                     cfg.Proxy,
+			ProxyRandomizeCredentials: cfg.TorIsolation,
+		},
+		{
+			Name:                      "ipv6",
+			Reachable:                 true,
+			Proxy:                     cfg.Proxy,
+			ProxyRandomizeCredentials: cfg.TorIsolation,
+		},
+		{
+			Name:                      "onion",
+			Limited:                   cfg.NoOnion,
+			Reachable:                 !cfg.NoOnion && onionProxy != "",
+			Proxy:                     onionProxy,
+			ProxyRandomizeCredentials: cfg.TorIsolation,
+		},
+	}
+
+	minRelayTxFee := s.cfg.TxMemPool.Policy().MinRelayTxFee
+	reply := &btcjson.GetNetworkInfoResult{
+		Version:         int32(1000000*appMajor + 10000*appMinor + 100*appPatch),
+		SubVersion:      version.UserAgent,
+		ProtocolVersion: int32(maxProtocolVersion),
+		LocalServices:   fmt.Sprintf("%016x", uint64(s.cfg.Services)),
+		LocalRelay:      !cfg.BlocksOnly,
+		TimeOffset:      int64(s.cfg.TimeSource.Offset().Seconds()),
+		Connections:     connectionsIn + connectionsOut,
+		ConnectionsIn:   connectionsIn,
+		ConnectionsOut:  connectionsOut,
+		NetworkActive:   true,
+		Networks:        networks,
+		RelayFee:        minRelayTxFee.ToBTC(),
+		IncrementalFee:  minRelayTxFee.ToBTC(),
+		LocalAddresses:  []btcjson.LocalAddressesResult{},
+		Warnings:        s.cfg.Chain.WarningsString(),
+	}
+	return reply, nil
+}
+
 // handleGetNodeAddresses implements the getnodeaddresses command.
 func handleGetNodeAddresses(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*btcjson.GetNodeAddressesCmd)
@@ -2572,6 +3948,9 @@ func handleGetPeerInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 			BanScore:       int32(p.BanScore()),
 			FeeFilter:      p.FeeFilter(),
 			SyncNode:       statsSnap.ID == syncPeerID,
+
+			BytesSentPerMsg: statsSnap.BytesSentPerMsg,
+			BytesRecvPerMsg: statsSnap.BytesRecvPerMsg,
 		}
 		if p.ToPeer().LastPingNonce() != 0 {
 			wait := float64(time.Since(statsSnap.LastPingTime).Nanoseconds())
@@ -2618,6 +3997,12 @@ func handleGetRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan str
 		verbose = *c.Verbose != 0
 	}
 
+	// Look the transaction up in the provided block, if any, which doesn't
+	// require the transaction index.
+	if c.BlockHash != nil {
+		return getRawTransactionFromBlock(s, txHash, *c.BlockHash, verbose)
+	}
+
 	// Try to fetch the transaction from the memory pool and if that fails,
 	// try the block database.
 	var mtx *wire.MsgTx
@@ -2630,7 +4015,8 @@ func handleGetRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan str
 				Code: btcjson.ErrRPCNoTxInfo,
 				Message: "The transaction index must be " +
 					"enabled to query the blockchain " +
-					"(specify --txindex)",
+					"(specify --txindex) unless a block " +
+					"hash is provided",
 			}
 		}
 
@@ -2641,6 +4027,14 @@ func handleGetRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan str
 			return nil, internalRPCError(err.Error(), context)
 		}
 		if blockRegion == nil {
+			if !indexSynced(s, s.cfg.TxIndex.Name()) {
+				return nil, btcjson.NewRPCError(
+					btcjson.ErrRPCNoTxInfo,
+					fmt.Sprintf("No information available "+
+						"about transaction %v; the "+
+						"transaction index is still "+
+						"being built", txHash))
+			}
 			return nil, rpcNoTxInfoError(txHash)
 		}
 
@@ -2719,7 +4113,124 @@ func handleGetRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan str
 	if err != nil {
 		return nil, err
 	}
-	return *rawTxn, nil
+	return *rawTxn, nil
+}
+
+// getRawTransactionFromBlock returns the getrawtransaction result for the
+// transaction with the passed hash in the block with the passed hash, which may
+// be a side chain block.
+func getRawTransactionFromBlock(s *rpcServer, txHash *chainhash.Hash,
+	blockHashStr string, verbose bool) (interface{}, error) {
+
+	blkHash, err := chainhash.NewHashFromStr(blockHashStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(blockHashStr)
+	}
+	header, err := s.cfg.Chain.HeaderByHash(blkHash)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "Block hash not found",
+		}
+	}
+
+	// Load the block from the database and find the transaction in it.
+	var blkBytes []byte
+	err = s.cfg.DB.View(func(dbTx database.Tx) error {
+		var err error
+		blkBytes, err = dbTx.FetchBlock(blkHash)
+		return err
+	})
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCMisc,
+			Message: "Block not available",
+		}
+	}
+	blk, err := ltcutil.NewBlockFromBytes(blkBytes)
+	if err != nil {
+		context := "Failed to deserialize block"
+		return nil, internalRPCError(err.Error(), context)
+	}
+	var mtx *wire.MsgTx
+	for _, tx := range blk.Transactions() {
+		if tx.Hash().IsEqual(txHash) {
+			mtx = tx.MsgTx()
+			break
+		}
+	}
+	if mtx == nil {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "No such transaction found in the provided " +
+				"block",
+		}
+	}
+
+	// When the verbose flag isn't set, simply return the serialized
+	// transaction as a hex-encoded string.
+	if !verbose {
+		mtxHex, err := messageToHex(mtx)
+		if err != nil {
+			return nil, err
+		}
+		return mtxHex, nil
+	}
+
+	// Transactions in blocks which are not part of the main chain have no
+	// confirmations.
+	inActiveChain := s.cfg.Chain.MainChainHasBlock(blkHash)
+	var blkHeight, chainHeight int32
+	if inActiveChain {
+		blkHeight, err = s.cfg.Chain.BlockHeightByHash(blkHash)
+		if err != nil {
+			context := "Failed to retrieve block height"
+			return nil, internalRPCError(err.Error(), context)
+		}
+		chainHeight = s.cfg.Chain.BestSnapshot().Height
+	}
+	rawTxn, err := createTxRawResult(s.cfg.ChainParams, mtx, txHash.String(),
+		&header, blkHash.String(), blkHeight, chainHeight)
+	if err != nil {
+		return nil, err
+	}
+	if !inActiveChain {
+		rawTxn.Confirmations = 0
+	}
+	rawTxn.InActiveChain = &inActiveChain
+	return *rawTxn, nil
+}
+
+// handleGetRPCInfo implements the getrpcinfo command.
+func handleGetRPCInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	now := time.Now()
+	s.activeCmdsLock.Lock()
+	activeCmds := make([]*activeRPCCmd, 0, len(s.activeCmds))
+	for active := range s.activeCmds {
+		activeCmds = append(activeCmds, active)
+	}
+	s.activeCmdsLock.Unlock()
+
+	// Report the commands in the order they were started with their
+	// durations in microseconds.
+	sort.Slice(activeCmds, func(i, j int) bool {
+		return activeCmds[i].started.Before(activeCmds[j].started)
+	})
+	result := &btcjson.GetRPCInfoResult{
+		ActiveCommands: make([]btcjson.RPCActiveCommand, 0,
+			len(activeCmds)),
+		LogPath:            filepath.Join(cfg.LogDir, defaultLogFilename),
+		RateLimited:        atomic.LoadUint64(&s.numRateLimited),
+		ConcurrencyLimited: atomic.LoadUint64(&s.numConcurrencyLimited),
+	}
+	for _, active := range activeCmds {
+		result.ActiveCommands = append(result.ActiveCommands,
+			btcjson.RPCActiveCommand{
+				Method:   active.method,
+				Duration: int64(now.Sub(active.started) / time.Microsecond),
+			})
+	}
+	return result, nil
 }
 
 // handleGetTxOut handles gettxout commands.
@@ -2778,103 +4289,669 @@ func handleGetTxOut(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 		out := wire.OutPoint{Hash: *txHash, Index: c.Vout}
 		entry, err := s.cfg.Chain.FetchUtxoEntry(out)
 		if err != nil {
-			return nil, rpcNoTxInfoError(txHash)
+			return nil, rpcNoTxInfoError(txHash)
+		}
+
+		// To match the behavior of the reference client, return nil
+		// (JSON null) if the transaction output is spent by another
+		// transaction already in the main chain.  Mined transactions
+		// that are spent by a mempool transaction are not affected by
+		// this.
+		if entry == nil || entry.IsSpent() {
+			return nil, nil
+		}
+
+		best := s.cfg.Chain.BestSnapshot()
+		bestBlockHash = best.Hash.String()
+		confirmations = 1 + best.Height - entry.BlockHeight()
+		value = entry.Amount()
+		pkScript = entry.PkScript()
+		isCoinbase = entry.IsCoinBase()
+	}
+
+	// Disassemble script into single line printable format.
+	// The disassembled string will contain [error] inline if the script
+	// doesn't fully parse, so ignore the error here.
+	disbuf, _ := txscript.DisasmString(pkScript)
+
+	// Get further info about the script.
+	// Ignore the error here since an error means the script couldn't parse
+	// and there is no additional information about it anyways.
+	scriptClass, addrs, reqSigs, _ := txscript.ExtractPkScriptAddrs(pkScript,
+		s.cfg.ChainParams)
+	addresses := make([]string, len(addrs))
+	for i, addr := range addrs {
+		addresses[i] = addr.EncodeAddress()
+	}
+
+	// Address is defined when there's a single well-defined
+	// receiver address. To spend the output a signature for this,
+	// and only this, address is required.
+	if len(addresses) == 1 && reqSigs <= 1 {
+		address = addresses[0]
+	}
+
+	txOutReply := &btcjson.GetTxOutResult{
+		BestBlock:     bestBlockHash,
+		Confirmations: int64(confirmations),
+		Value:         ltcutil.Amount(value).ToBTC(),
+		ScriptPubKey: btcjson.ScriptPubKeyResult{
+			Asm:       disbuf,
+			Hex:       hex.EncodeToString(pkScript),
+			ReqSigs:   int32(reqSigs),
+			Type:      scriptClass.String(),
+			Address:   address,
+			Addresses: addresses,
+		},
+		Coinbase: isCoinbase,
+	}
+
+	return txOutReply, nil
+}
+
+// txOutSetSubsidyEras groups the blocks of the main chain up to and including
+// the passed height into runs paying the same subsidy and returns them along
+// with the total subsidy paid by all of them.
+func txOutSetSubsidyEras(height int32, params *chaincfg.Params) ([]btcjson.TxOutSetSubsidyEra, int64) {
+	var eras []btcjson.TxOutSetSubsidyEra
+	var total, eraTotal, eraSubsidy int64
+	eraStart := int32(0)
+	addEra := func(end int32) {
+		eras = append(eras, btcjson.TxOutSetSubsidyEra{
+			StartHeight: eraStart,
+			EndHeight:   end,
+			Subsidy:     ltcutil.Amount(eraSubsidy).ToBTC(),
+			Blocks:      int64(end-eraStart) + 1,
+			TotalAmount: ltcutil.Amount(eraTotal).ToBTC(),
+		})
+	}
+	for h := int32(0); h <= height; h++ {
+		subsidy := blockchain.CalcBlockSubsidy(h, params)
+		if h > 0 && subsidy != eraSubsidy {
+			addEra(h - 1)
+			eraStart, eraTotal = h, 0
+		}
+		eraSubsidy = subsidy
+		eraTotal += subsidy
+		total += subsidy
+	}
+	if height >= 0 {
+		addEra(height)
+	}
+
+	return eras, total
+}
+
+// handleGetTxOutSetInfo implements the gettxoutsetinfo command.
+func handleGetTxOutSetInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	return getTxOutSetInfo(s, cmd, closeChan, nil)
+}
+
+// getTxOutSetInfo implements the gettxoutsetinfo command for both requests and
+// operations, passing the progress of the scan of the set to progress if it is
+// not nil.
+func getTxOutSetInfo(s *rpcServer, cmd interface{}, quit <-chan struct{},
+	progress blockchain.ProgressFunc) (interface{}, error) {
+
+	stats, err := s.cfg.Chain.FetchUtxoSetStats(quit, progress)
+	if err != nil {
+		context := "Failed to scan the unspent transaction output set"
+		return nil, internalRPCError(err.Error(), context)
+	}
+
+	// Every coin in existence was created by a block subsidy, so whatever
+	// was paid out and is not in the unspent set is unspendable: the
+	// genesis output, provably unspendable outputs, fees and subsidies
+	// which were not claimed by miners, and coins sent to unspendable
+	// scripts.
+	eras, totalSubsidy := txOutSetSubsidyEras(stats.Height,
+		s.cfg.ChainParams)
+
+	return &btcjson.GetTxOutSetInfoResult{
+		Height:                 int64(stats.Height),
+		BestBlock:              stats.Hash,
+		Transactions:           stats.Transactions,
+		TxOuts:                 stats.TxOuts,
+		BogoSize:               stats.BogoSize,
+		HashSerialized:         stats.SerializedHash,
+		DiskSize:               stats.DiskSize,
+		TotalAmount:            ltcutil.Amount(stats.TotalAmount),
+		TotalUnspendableAmount: ltcutil.Amount(totalSubsidy - stats.TotalAmount),
+		TotalSubsidyAmount:     ltcutil.Amount(totalSubsidy),
+		MwebPeggedAmount:       ltcutil.Amount(stats.MwebPeggedAmount),
+		SubsidyEras:            eras,
+	}, nil
+}
+
+// handleHelp implements the help command.
+func handleHelp(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.HelpCmd)
+
+	// Provide a usage overview of all commands when no specific command
+	// was specified.
+	var command string
+	if c.Command != nil {
+		command = *c.Command
+	}
+	if command == "" {
+		usage, err := s.helpCacher.rpcUsage(false)
+		if err != nil {
+			context := "Failed to generate RPC usage"
+			return nil, internalRPCError(err.Error(), context)
+		}
+		return usage, nil
+	}
+
+	// Check that the command asked for is supported and implemented.  Only
+	// search the main list of handlers since help should not be provided
+	// for commands that are unimplemented or related to wallet
+	// functionality.
+	if _, ok := rpcHandlers[command]; !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Unknown command: " + command,
+		}
+	}
+
+	// Get the help for the command.
+	help, err := s.helpCacher.rpcMethodHelp(command)
+	if err != nil {
+		context := "Failed to generate help"
+		return nil, internalRPCError(err.Error(), context)
+	}
+	return help, nil
+}
+
+// errWatchOnlyDisabled is returned by the watch-only commands when the
+// watch-only index is not enabled.
+var errWatchOnlyDisabled = &btcjson.RPCError{
+	Code:    btcjson.ErrRPCMisc,
+	Message: "Watch-only tracking must be enabled (--watchonly)",
+}
+
+// parseWatchScripts converts the scripts of an importwatchonly or
+// rescanblockchain command, which are addresses, hex-encoded public key scripts
+// or output descriptors, into the public key scripts to watch.  Ranged descriptors are expanded over the
+// passed range.
+func parseWatchScripts(s *rpcServer, encodedScripts []string,
+	descriptorRange *btcjson.DescriptorRange) ([][]byte, error) {
+
+	params := s.cfg.ChainParams
+	var scripts [][]byte
+	for _, encoded := range encodedScripts {
+		if addr, err := ltcutil.DecodeAddress(encoded, params); err == nil {
+			if _, ok := addr.(*ltcutil.AddressMweb); ok {
+				return nil, &btcjson.RPCError{
+					Code: btcjson.ErrRPCInvalidAddressOrKey,
+					Message: "Invalid address: MWEB address " +
+						encoded + " is not supported",
+				}
+			}
+			if !addr.IsForNet(params) {
+				return nil, &btcjson.RPCError{
+					Code: btcjson.ErrRPCInvalidAddressOrKey,
+					Message: "Invalid address: " + encoded +
+						" is for the wrong network",
+				}
+			}
+			script, err := txscript.PayToAddrScript(addr)
+			if err != nil {
+				return nil, &btcjson.RPCError{
+					Code:    btcjson.ErrRPCInvalidAddressOrKey,
+					Message: err.Error(),
+				}
+			}
+			scripts = append(scripts, script)
+			continue
+		}
+
+		if !strings.Contains(encoded, "(") {
+			script, err := hex.DecodeString(encoded)
+			if err != nil || len(script) == 0 {
+				return nil, &btcjson.RPCError{
+					Code: btcjson.ErrRPCInvalidAddressOrKey,
+					Message: "Invalid address, script or " +
+						"descriptor: " + encoded,
+				}
+			}
+			scripts = append(scripts, script)
+			continue
+		}
+
+		desc, err := descriptor.Parse(encoded, false, params)
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidAddressOrKey,
+				Message: err.Error(),
+			}
+		}
+		var begin, end uint32
+		if desc.IsRange() {
+			if descriptorRange == nil {
+				return nil, &btcjson.RPCError{
+					Code: btcjson.ErrRPCInvalidParameter,
+					Message: "Range must be specified for a " +
+						"ranged descriptor",
+				}
+			}
+			begin, end, err = parseDescriptorRange(descriptorRange)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for i := begin; i <= end; i++ {
+			script, err := desc.Script(i)
+			if err != nil {
+				return nil, &btcjson.RPCError{
+					Code:    btcjson.ErrRPCInvalidAddressOrKey,
+					Message: err.Error(),
+				}
+			}
+			scripts = append(scripts, script)
+		}
+	}
+	return scripts, nil
+}
+
+// rescanWatchScripts finds the unspent outputs paying to the passed scripts in
+// the blocks of the main chain from the passed height and starts watching the
+// scripts along with the outputs found.
+//
+// The blocks are scanned without holding up the sync manager, which is then
+// paused just long enough to ensure no block was connected since the last one
+// scanned while the scripts are added.  Otherwise, the scan continues with the
+// new blocks, or starts over when the blocks scanned were reorganized out of
+// the main chain.
+func rescanWatchScripts(s *rpcServer, scripts [][]byte, startHeight int32,
+	closeChan <-chan struct{}) error {
+
+	chain := s.cfg.Chain
+	rescan := indexers.NewWatchRescan(scripts)
+	height := startHeight
+	var lastHash *chainhash.Hash
+	restart := func() {
+		rpcsLog.Debugf("Restarting watch-only rescan from height %d "+
+			"after a reorganization", startHeight)
+		rescan = indexers.NewWatchRescan(scripts)
+		height = startHeight
+		lastHash = nil
+	}
+	for {
+		best := chain.BestSnapshot()
+		for ; height <= best.Height; height++ {
+			select {
+			case <-closeChan:
+				return ErrClientQuit
+			default:
+			}
+
+			block, err := chain.BlockByHeight(height)
+			if err != nil {
+				// The block is no longer in the main chain.
+				restart()
+				break
+			}
+			if lastHash != nil &&
+				block.MsgBlock().Header.PrevBlock != *lastHash {
+
+				restart()
+				break
+			}
+			rescan.ScanBlock(block)
+			lastHash = block.Hash()
+		}
+		if lastHash == nil {
+			continue
+		}
+
+		pauseGuard := s.cfg.SyncMgr.Pause()
+		best = chain.BestSnapshot()
+		if best.Hash == *lastHash {
+			err := s.cfg.WatchIndex.AddScripts(scripts, rescan)
+			close(pauseGuard)
+			if err != nil {
+				return &btcjson.RPCError{
+					Code:    btcjson.ErrRPCDatabase,
+					Message: "Database error: " + err.Error(),
+				}
+			}
+			return nil
+		}
+		reorganized := !chain.MainChainHasBlock(lastHash)
+		close(pauseGuard)
+		if reorganized {
+			restart()
+		}
+	}
+}
+
+// handleImportWatchOnly implements the importwatchonly command.
+func handleImportWatchOnly(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.ImportWatchOnlyCmd)
+
+	if s.cfg.WatchIndex == nil {
+		return nil, errWatchOnlyDisabled
+	}
+
+	scripts, err := parseWatchScripts(s, c.Scripts, c.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Rescan == nil || !*c.Rescan {
+		err := s.cfg.WatchIndex.AddScripts(scripts, nil)
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCDatabase,
+				Message: "Database error: " + err.Error(),
+			}
+		}
+		return nil, nil
+	}
+
+	var startHeight int32
+	if c.StartHeight != nil {
+		startHeight = *c.StartHeight
+	}
+	best := s.cfg.Chain.BestSnapshot()
+	if startHeight < 0 || startHeight > best.Height {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Start height out of range",
+		}
+	}
+	return nil, rescanWatchScripts(s, scripts, startHeight, closeChan)
+}
+
+// handleListUnspentWatchOnly implements the listunspentwatchonly command.
+func handleListUnspentWatchOnly(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.ListUnspentWatchOnlyCmd)
+
+	watchIndex := s.cfg.WatchIndex
+	if watchIndex == nil {
+		return nil, errWatchOnlyDisabled
+	}
+
+	minConf, maxConf := int64(1), int64(9999999)
+	if c.MinConf != nil {
+		minConf = int64(*c.MinConf)
+	}
+	if c.MaxConf != nil {
+		maxConf = int64(*c.MaxConf)
+	}
+
+	// Only report the outputs paying to the requested addresses, if any.
+	params := s.cfg.ChainParams
+	var filter map[string]struct{}
+	if c.Addresses != nil {
+		filter = make(map[string]struct{}, len(*c.Addresses))
+		for _, encodedAddr := range *c.Addresses {
+			addr, err := ltcutil.DecodeAddress(encodedAddr, params)
+			if err != nil || !addr.IsForNet(params) {
+				return nil, &btcjson.RPCError{
+					Code: btcjson.ErrRPCInvalidAddressOrKey,
+					Message: "Invalid address: " +
+						encodedAddr,
+				}
+			}
+			script, err := txscript.PayToAddrScript(addr)
+			if err != nil {
+				return nil, &btcjson.RPCError{
+					Code:    btcjson.ErrRPCInvalidAddressOrKey,
+					Message: err.Error(),
+				}
+			}
+			filter[string(script)] = struct{}{}
+		}
+	}
+
+	txPool := s.cfg.TxMemPool
+	result := make([]btcjson.ListUnspentWatchOnlyResult, 0)
+	addResult := func(op *wire.OutPoint, amount ltcutil.Amount,
+		pkScript []byte, confirmations int64, isCoinBase bool) {
+
+		if confirmations < minConf || confirmations > maxConf {
+			return
+		}
+		if _, ok := filter[string(pkScript)]; filter != nil && !ok {
+			return
+		}
+		if txPool.CheckSpend(*op) != nil {
+			return
+		}
+
+		var address string
+		_, addrs, _, _ := txscript.ExtractPkScriptAddrs(pkScript, params)
+		if len(addrs) == 1 {
+			address = addrs[0].EncodeAddress()
+		}
+		result = append(result, btcjson.ListUnspentWatchOnlyResult{
+			TxID:          op.Hash.String(),
+			Vout:          op.Index,
+			Address:       address,
+			ScriptPubKey:  hex.EncodeToString(pkScript),
+			Amount:        amount.ToBTC(),
+			Confirmations: confirmations,
+			Coinbase:      isCoinBase,
+		})
+	}
+
+	// Report the confirmed outputs followed by the outputs of the
+	// transactions in the mempool, which have no confirmations.
+	best := s.cfg.Chain.BestSnapshot()
+	outputs := watchIndex.UnspentOutputs()
+	sort.Slice(outputs, func(i, j int) bool {
+		if outputs[i].Height != outputs[j].Height {
+			return outputs[i].Height < outputs[j].Height
+		}
+		return outputs[i].OutPoint.String() < outputs[j].OutPoint.String()
+	})
+	for i := range outputs {
+		out := &outputs[i]
+		addResult(&out.OutPoint, out.Amount, out.PkScript,
+			int64(best.Height-out.Height+1), out.IsCoinBase)
+	}
+	for _, txD := range txPool.TxDescs() {
+		for i, txOut := range txD.Tx.MsgTx().TxOut {
+			if !watchIndex.IsWatched(txOut.PkScript) {
+				continue
+			}
+			op := wire.OutPoint{Hash: *txD.Tx.Hash(), Index: uint32(i)}
+			addResult(&op, ltcutil.Amount(txOut.Value),
+				txOut.PkScript, 0, false)
+		}
+	}
+	return result, nil
+}
+
+// rescanBlockchain implements the rescanblockchain command for both HTTP and
+// websocket clients.  The blocks of the main chain in the requested range are
+// scanned for transactions paying to the requested scripts or spending the
+// requested outpoints, along with the outputs paying to the scripts.  Each
+// match is passed to notify, if not nil, as soon as it is found, and the
+// number of blocks scanned is passed to progress, if not nil, as the scan
+// advances.
+//
+// The committed filters are used to skip the blocks which do not involve the
+// scripts when the CF index is enabled and no outpoints were requested, since
+// the filters only commit to the scripts of the spent outputs.
+func rescanBlockchain(s *rpcServer, c *btcjson.RescanBlockchainCmd,
+	quit <-chan struct{}, notify func(*btcjson.RescanBlockchainMatch),
+	progress blockchain.ProgressFunc) (*btcjson.RescanBlockchainResult, error) {
+
+	var scripts [][]byte
+	if c.Scripts != nil {
+		var err error
+		scripts, err = parseWatchScripts(s, *c.Scripts, nil)
+		if err != nil {
+			return nil, err
 		}
+	}
+	cfIndex := s.cfg.CfIndex
+	watchedScripts := make(map[string]struct{}, len(scripts))
+	for _, script := range scripts {
+		watchedScripts[string(script)] = struct{}{}
 
-		// To match the behavior of the reference client, return nil
-		// (JSON null) if the transaction output is spent by another
-		// transaction already in the main chain.  Mined transactions
-		// that are spent by a mempool transaction are not affected by
-		// this.
-		if entry == nil || entry.IsSpent() {
-			return nil, nil
+		// Filters do not commit to OP_RETURN outputs.
+		if script[0] == txscript.OP_RETURN {
+			cfIndex = nil
 		}
-
-		best := s.cfg.Chain.BestSnapshot()
-		bestBlockHash = best.Hash.String()
-		confirmations = 1 + best.Height - entry.BlockHeight()
-		value = entry.Amount()
-		pkScript = entry.PkScript()
-		isCoinbase = entry.IsCoinBase()
 	}
-
-	// Disassemble script into single line printable format.
-	// The disassembled string will contain [error] inline if the script
-	// doesn't fully parse, so ignore the error here.
-	disbuf, _ := txscript.DisasmString(pkScript)
-
-	// Get further info about the script.
-	// Ignore the error here since an error means the script couldn't parse
-	// and there is no additional information about it anyways.
-	scriptClass, addrs, reqSigs, _ := txscript.ExtractPkScriptAddrs(pkScript,
-		s.cfg.ChainParams)
-	addresses := make([]string, len(addrs))
-	for i, addr := range addrs {
-		addresses[i] = addr.EncodeAddress()
+	outPoints := make(map[wire.OutPoint]struct{})
+	if c.OutPoints != nil {
+		for _, op := range *c.OutPoints {
+			hash, err := chainhash.NewHashFromStr(op.Hash)
+			if err != nil {
+				return nil, rpcDecodeHexError(op.Hash)
+			}
+			outPoints[wire.OutPoint{Hash: *hash, Index: op.Index}] = struct{}{}
+		}
+	}
+	if len(outPoints) != 0 {
+		cfIndex = nil
 	}
 
-	// Address is defined when there's a single well-defined
-	// receiver address. To spend the output a signature for this,
-	// and only this, address is required.
-	if len(addresses) == 1 && reqSigs <= 1 {
-		address = addresses[0]
+	chain := s.cfg.Chain
+	best := chain.BestSnapshot()
+	var startHeight int32
+	if c.StartHeight != nil {
+		startHeight = *c.StartHeight
+	}
+	stopHeight := best.Height
+	if c.StopHeight != nil {
+		stopHeight = *c.StopHeight
+	}
+	if startHeight < 0 || startHeight > best.Height {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Invalid start_height",
+		}
+	}
+	if stopHeight < startHeight || stopHeight > best.Height {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Invalid stop_height",
+		}
 	}
 
-	txOutReply := &btcjson.GetTxOutResult{
-		BestBlock:     bestBlockHash,
-		Confirmations: int64(confirmations),
-		Value:         ltcutil.Amount(value).ToBTC(),
-		ScriptPubKey: btcjson.ScriptPubKeyResult{
-			Asm:       disbuf,
-			Hex:       hex.EncodeToString(pkScript),
-			ReqSigs:   int32(reqSigs),
-			Type:      scriptClass.String(),
-			Address:   address,
-			Addresses: addresses,
-		},
-		Coinbase: isCoinbase,
+	result := &btcjson.RescanBlockchainResult{
+		StartHeight: startHeight,
+		StopHeight:  stopHeight,
+		Matches:     make([]btcjson.RescanBlockchainMatch, 0),
 	}
+	var prevHash *chainhash.Hash
+	numBlocks := int64(stopHeight - startHeight + 1)
+	for height := startHeight; height <= stopHeight; height++ {
+		select {
+		case <-quit:
+			return nil, ErrClientQuit
+		default:
+		}
+		if progress != nil {
+			progress(int64(height-startHeight), numBlocks)
+		}
 
-	return txOutReply, nil
-}
+		hash, err := chain.BlockHashByHeight(height)
+		if err != nil {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCMisc,
+				Message: "Chain reorganized during rescan",
+			}
+		}
+		if prevHash != nil {
+			header, err := chain.HeaderByHash(hash)
+			if err != nil || header.PrevBlock != *prevHash {
+				return nil, &btcjson.RPCError{
+					Code:    btcjson.ErrRPCMisc,
+					Message: "Chain reorganized during rescan",
+				}
+			}
+		}
+		prevHash = hash
 
-// handleHelp implements the help command.
-func handleHelp(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	c := cmd.(*btcjson.HelpCmd)
+		if cfIndex != nil && !rescanFilterMatches(cfIndex, hash, scripts) {
+			continue
+		}
 
-	// Provide a usage overview of all commands when no specific command
-	// was specified.
-	var command string
-	if c.Command != nil {
-		command = *c.Command
-	}
-	if command == "" {
-		usage, err := s.helpCacher.rpcUsage(false)
+		block, err := chain.BlockByHash(hash)
 		if err != nil {
-			context := "Failed to generate RPC usage"
-			return nil, internalRPCError(err.Error(), context)
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCMisc,
+				Message: "Block not available",
+			}
 		}
-		return usage, nil
-	}
+		for txIdx, tx := range block.Transactions() {
+			matched := false
+			if txIdx != 0 {
+				for _, txIn := range tx.MsgTx().TxIn {
+					op := txIn.PreviousOutPoint
+					if _, ok := outPoints[op]; ok {
+						delete(outPoints, op)
+						matched = true
+					}
+				}
+			}
+			for i, txOut := range tx.MsgTx().TxOut {
+				if _, ok := watchedScripts[string(txOut.PkScript)]; ok {
+					op := wire.OutPoint{Hash: *tx.Hash(), Index: uint32(i)}
+					outPoints[op] = struct{}{}
+					matched = true
+				}
+			}
+			if !matched {
+				continue
+			}
 
-	// Check that the command asked for is supported and implemented.  Only
-	// search the main list of handlers since help should not be provided
-	// for commands that are unimplemented or related to wallet
-	// functionality.
-	if _, ok := rpcHandlers[command]; !ok {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCInvalidParameter,
-			Message: "Unknown command: " + command,
+			var buf bytes.Buffer
+			if err := tx.MsgTx().Serialize(&buf); err != nil {
+				context := "Failed to serialize transaction"
+				return nil, internalRPCError(err.Error(), context)
+			}
+			match := btcjson.RescanBlockchainMatch{
+				TxID:      tx.Hash().String(),
+				Hex:       hex.EncodeToString(buf.Bytes()),
+				BlockHash: hash.String(),
+				Height:    height,
+				Index:     txIdx,
+			}
+			result.Matches = append(result.Matches, match)
+			if notify != nil {
+				notify(&match)
+			}
 		}
 	}
+	if progress != nil {
+		progress(numBlocks, numBlocks)
+	}
+	return result, nil
+}
 
-	// Get the help for the command.
-	help, err := s.helpCacher.rpcMethodHelp(command)
+// rescanFilterMatches returns whether the committed filter of the passed block
+// matches any of the passed scripts.  Blocks whose filter is not available,
+// such as while the CF index is catching up, are considered to match.
+func rescanFilterMatches(cfIndex *indexers.CfIndex, hash *chainhash.Hash,
+	scripts [][]byte) bool {
+
+	filterBytes, err := cfIndex.FilterByBlockHash(hash, wire.GCSFilterRegular)
+	if err != nil || len(filterBytes) == 0 {
+		return true
+	}
+	filter, err := gcs.FromNBytes(builder.DefaultP, builder.DefaultM,
+		filterBytes)
 	if err != nil {
-		context := "Failed to generate help"
-		return nil, internalRPCError(err.Error(), context)
+		return true
 	}
-	return help, nil
+	if filter.N() == 0 || len(scripts) == 0 {
+		return false
+	}
+	matched, err := filter.MatchAny(builder.DeriveKey(hash), scripts)
+	return err != nil || matched
+}
+
+// handleRescanBlockchain implements the rescanblockchain command.
+func handleRescanBlockchain(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.RescanBlockchainCmd)
+	return rescanBlockchain(s, c, closeChan, nil, nil)
 }
 
 // handlePing implements the ping command.
@@ -3452,7 +5529,8 @@ func handleSendRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan st
 	// Also, since an error is being returned to the caller, ensure the
 	// transaction is removed from the memory pool.
 	if len(acceptedTxs) == 0 || !acceptedTxs[0].Tx.Hash().IsEqual(tx.Hash()) {
-		s.cfg.TxMemPool.RemoveTransaction(tx, true)
+		s.cfg.TxMemPool.RemoveTransaction(tx, true,
+			mempool.RemovalReasonManual)
 
 		errStr := fmt.Sprintf("transaction %v is not in accepted list",
 			tx.Hash())
@@ -3500,63 +5578,345 @@ func handleSetGenerate(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 		// created blocks to.
 		if len(cfg.miningAddrs) == 0 {
 			return nil, &btcjson.RPCError{
-				Code: btcjson.ErrRPCInternal.Code,
-				Message: "No payment addresses specified " +
-					"via --miningaddr",
+				Code: btcjson.ErrRPCInternal.Code,
+				Message: "No payment addresses specified " +
+					"via --miningaddr",
+			}
+		}
+
+		// It's safe to call start even if it's already started.
+		s.cfg.CPUMiner.SetNumWorkers(int32(genProcLimit))
+		s.cfg.CPUMiner.Start()
+	}
+	return nil, nil
+}
+
+// handleSetMockTime implements the setmocktime command.
+func handleSetMockTime(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.SetMockTimeCmd)
+
+	// The mock time is only meant for testing, so restrict it to the
+	// networks which support CPU mining for the same purpose.
+	params := s.cfg.ChainParams
+	mockTimeSource, ok := s.cfg.TimeSource.(blockchain.MockTimeSource)
+	if !params.GenerateSupported || !ok {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCMisc,
+			Message: fmt.Sprintf("No support for `setmocktime` on "+
+				"the current network, %s", params.Net),
+		}
+	}
+	if c.Timestamp < 0 {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Mock time cannot be negative",
+		}
+	}
+
+	var mockTime time.Time
+	if c.Timestamp != 0 {
+		mockTime = time.Unix(c.Timestamp, 0)
+		rpcsLog.Infof("Setting mock time to %v", mockTime)
+	} else {
+		rpcsLog.Infof("Clearing mock time")
+	}
+	mockTimeSource.SetMockTime(mockTime)
+
+	return nil, nil
+}
+
+// handleSetPolicy implements the setpolicy command.
+func handleSetPolicy(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.SetPolicyCmd)
+
+	policy := s.cfg.TxMemPool.Policy()
+	maxPeers := s.cfg.ConnMgr.MaxPeers()
+	if opts := c.Options; opts != nil {
+		// Validate all of the settings before changing any of them so
+		// an invalid setting doesn't leave the others half applied.
+		if opts.MinRelayTxFee != nil {
+			fee, err := ltcutil.NewAmount(*opts.MinRelayTxFee)
+			if err != nil || fee < 0 {
+				return nil, &btcjson.RPCError{
+					Code:    btcjson.ErrRPCInvalidParameter,
+					Message: "Invalid minrelaytxfee",
+				}
+			}
+			policy.MinRelayTxFee = fee
+		}
+		if opts.MaxOrphanTxs != nil {
+			if *opts.MaxOrphanTxs < 0 {
+				return nil, &btcjson.RPCError{
+					Code:    btcjson.ErrRPCInvalidParameter,
+					Message: "Invalid maxorphantx",
+				}
+			}
+			policy.MaxOrphanTxs = *opts.MaxOrphanTxs
+		}
+		if opts.RelayNonStd != nil {
+			// Relaying non-standard transactions is only meant
+			// for testing, so refuse to toggle it on the main
+			// network.
+			params := s.cfg.ChainParams
+			if params.Net == wire.MainNet {
+				return nil, &btcjson.RPCError{
+					Code: btcjson.ErrRPCMisc,
+					Message: fmt.Sprintf("No support for "+
+						"changing relaynonstd on the "+
+						"current network, %s", params.Net),
+				}
+			}
+			policy.AcceptNonStd = *opts.RelayNonStd
+		}
+		if opts.MaxPeers != nil {
+			if *opts.MaxPeers < 0 {
+				return nil, &btcjson.RPCError{
+					Code:    btcjson.ErrRPCInvalidParameter,
+					Message: "Invalid maxpeers",
+				}
+			}
+			maxPeers = *opts.MaxPeers
+		}
+
+		rpcsLog.Infof("Changing policy: minrelaytxfee=%v maxorphantx=%d "+
+			"relaynonstd=%v maxpeers=%d", policy.MinRelayTxFee,
+			policy.MaxOrphanTxs, policy.AcceptNonStd, maxPeers)
+		s.cfg.TxMemPool.SetPolicy(&policy)
+		s.cfg.ConnMgr.SetMaxPeers(maxPeers)
+
+		// Transactions paying less than the minimum relay fee are not
+		// mined either, so keep the mining policy in step with it.
+		if opts.MinRelayTxFee != nil && s.cfg.Generator != nil {
+			miningPolicy := s.cfg.Generator.Policy()
+			miningPolicy.TxMinFreeFee = policy.MinRelayTxFee
+			s.cfg.Generator.SetPolicy(&miningPolicy)
+		}
+	}
+
+	return &btcjson.SetPolicyResult{
+		MinRelayTxFee: policy.MinRelayTxFee.ToBTC(),
+		MaxOrphanTxs:  policy.MaxOrphanTxs,
+		RelayNonStd:   policy.AcceptNonStd,
+		MaxPeers:      maxPeers,
+	}, nil
+}
+
+// handleSignMessageWithPrivKey implements the signmessagewithprivkey command.
+func handleSignMessageWithPrivKey(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.SignMessageWithPrivKeyCmd)
+
+	wif, err := ltcutil.DecodeWIF(c.PrivKey)
+	if err != nil {
+		message := "Invalid private key"
+		switch err {
+		case ltcutil.ErrMalformedPrivateKey:
+			message = "Malformed private key"
+		case ltcutil.ErrChecksumMismatch:
+			message = "Private key checksum mismatch"
+		}
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: message,
+		}
+	}
+	if !wif.IsForNet(s.cfg.ChainParams) {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "Private key for wrong network",
+		}
+	}
+
+	addrType := signmessage.P2PKH
+	if c.AddressType != nil {
+		var ok bool
+		addrType, ok = rpcMessageAddressTypes[*c.AddressType]
+		if !ok {
+			return nil, &btcjson.RPCError{
+				Code: btcjson.ErrRPCInvalidParameter,
+				Message: fmt.Sprintf("Unknown address type %q",
+					*c.AddressType),
+			}
+		}
+	}
+
+	sig, err := signmessage.Sign(wif.PrivKey, wif.CompressPubKey, addrType,
+		c.Message)
+	if err == signmessage.ErrUncompressedSegwit {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "Segwit addresses require a compressed private key",
+		}
+	}
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "Sign failed",
+		}
+	}
+
+	return sig, nil
+}
+
+// rpcMessageAddressTypes maps the address types accepted by the
+// signmessagewithprivkey command to the address types of the signatures.
+var rpcMessageAddressTypes = map[string]signmessage.AddressType{
+	"legacy":      signmessage.P2PKH,
+	"p2sh-segwit": signmessage.P2SHP2WPKH,
+	"bech32":      signmessage.P2WPKH,
+}
+
+// rpcSigHashTypes maps the signature hash types accepted by the
+// signrawtransactionwithkey command to their values.
+var rpcSigHashTypes = map[string]txscript.SigHashType{
+	"DEFAULT":             txscript.SigHashDefault,
+	"ALL":                 txscript.SigHashAll,
+	"NONE":                txscript.SigHashNone,
+	"SINGLE":              txscript.SigHashSingle,
+	"ALL|ANYONECANPAY":    txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+	"NONE|ANYONECANPAY":   txscript.SigHashNone | txscript.SigHashAnyOneCanPay,
+	"SINGLE|ANYONECANPAY": txscript.SigHashSingle | txscript.SigHashAnyOneCanPay,
+}
+
+// handleSignRawTransactionWithKey implements the signrawtransactionwithkey
+// command.
+func handleSignRawTransactionWithKey(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.SignRawTransactionWithKeyCmd)
+
+	hexStr := c.RawTx
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+	serializedTx, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+	var mtx wire.MsgTx
+	if err := mtx.Deserialize(bytes.NewReader(serializedTx)); err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDeserialization,
+			Message: "TX decode failed: " + err.Error(),
+		}
+	}
+
+	hashType, ok := rpcSigHashTypes[*c.SigHashType]
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Invalid sighash param",
+		}
+	}
+
+	params := s.cfg.ChainParams
+	keyStore := txauthor.NewKeyStore(params)
+	for _, privKey := range c.PrivKeys {
+		wif, err := ltcutil.DecodeWIF(privKey)
+		if err != nil || !wif.IsForNet(params) {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidAddressOrKey,
+				Message: "Invalid private key",
 			}
 		}
-
-		// It's safe to call start even if it's already started.
-		s.cfg.CPUMiner.SetNumWorkers(int32(genProcLimit))
-		s.cfg.CPUMiner.Start()
+		keyStore.AddKey(wif)
 	}
-	return nil, nil
-}
-
-// Text used to signify that a signed message follows and to prevent
-// inadvertently signing a transaction.
-const messageSignatureHeader = "Bitcoin Signed Message:\n"
-
-// handleSignMessageWithPrivKey implements the signmessagewithprivkey command.
-func handleSignMessageWithPrivKey(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	c := cmd.(*btcjson.SignMessageWithPrivKeyCmd)
 
-	wif, err := ltcutil.DecodeWIF(c.PrivKey)
-	if err != nil {
-		message := "Invalid private key"
-		switch err {
-		case ltcutil.ErrMalformedPrivateKey:
-			message = "Malformed private key"
-		case ltcutil.ErrChecksumMismatch:
-			message = "Private key checksum mismatch"
+	// Gather the previous outputs and scripts provided by the caller.
+	providedOuts := make(map[wire.OutPoint]*wire.TxOut)
+	if c.Inputs != nil {
+		decodeScript := func(name, script string) ([]byte, error) {
+			decoded, err := hex.DecodeString(script)
+			if err != nil {
+				return nil, &btcjson.RPCError{
+					Code:    btcjson.ErrRPCDeserialization,
+					Message: name + " must be hexadecimal string",
+				}
+			}
+			return decoded, nil
 		}
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCInvalidAddressOrKey,
-			Message: message,
+
+		for _, input := range *c.Inputs {
+			txHash, err := chainhash.NewHashFromStr(input.Txid)
+			if err != nil {
+				return nil, rpcDecodeHexError(input.Txid)
+			}
+			pkScript, err := decodeScript("scriptPubKey",
+				input.ScriptPubKey)
+			if err != nil {
+				return nil, err
+			}
+			var amount ltcutil.Amount
+			if input.Amount != nil {
+				amount, err = ltcutil.NewAmount(*input.Amount)
+				if err != nil || amount < 0 {
+					return nil, &btcjson.RPCError{
+						Code:    btcjson.ErrRPCType,
+						Message: "Invalid amount",
+					}
+				}
+			}
+			op := wire.OutPoint{Hash: *txHash, Index: input.Vout}
+			providedOuts[op] = wire.NewTxOut(int64(amount), pkScript)
+
+			for name, script := range map[string]*string{
+				"redeemScript":  input.RedeemScript,
+				"witnessScript": input.WitnessScript,
+			} {
+				if script == nil {
+					continue
+				}
+				decoded, err := decodeScript(name, *script)
+				if err != nil {
+					return nil, err
+				}
+				keyStore.AddScript(decoded)
+			}
 		}
 	}
-	if !wif.IsForNet(s.cfg.ChainParams) {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCInvalidAddressOrKey,
-			Message: "Private key for wrong network",
+
+	// Look up the outputs which were not provided in the memory pool and
+	// the main chain's unspent transaction outputs.
+	prevOuts := make([]*wire.TxOut, len(mtx.TxIn))
+	for i, txIn := range mtx.TxIn {
+		op := txIn.PreviousOutPoint
+		if prevOut, ok := providedOuts[op]; ok {
+			prevOuts[i] = prevOut
+			continue
+		}
+		prevOut, err := fetchTracePrevOut(s, &op)
+		if err != nil {
+			rpcErr, ok := err.(*btcjson.RPCError)
+			if ok && rpcErr.Code == btcjson.ErrRPCNoTxInfo {
+				continue
+			}
+			return nil, err
 		}
+		prevOuts[i] = prevOut
 	}
 
-	var buf bytes.Buffer
-	wire.WriteVarString(&buf, 0, messageSignatureHeader)
-	wire.WriteVarString(&buf, 0, c.Message)
-	messageHash := chainhash.DoubleHashB(buf.Bytes())
+	signErrs := keyStore.SignTransaction(&mtx, prevOuts, hashType)
 
-	sig, err := ecdsa.SignCompact(wif.PrivKey,
-		messageHash, wif.CompressPubKey)
+	mtxHex, err := messageToHex(&mtx)
 	if err != nil {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCInvalidAddressOrKey,
-			Message: "Sign failed",
+		return nil, err
+	}
+	result := &btcjson.SignRawTransactionResult{
+		Hex:      mtxHex,
+		Complete: true,
+	}
+	for i, signErr := range signErrs {
+		if signErr == nil {
+			continue
 		}
+		txIn := mtx.TxIn[i]
+		result.Complete = false
+		result.Errors = append(result.Errors, btcjson.SignRawTransactionError{
+			TxID:      txIn.PreviousOutPoint.Hash.String(),
+			Vout:      txIn.PreviousOutPoint.Index,
+			ScriptSig: hex.EncodeToString(txIn.SignatureScript),
+			Sequence:  txIn.Sequence,
+			Error:     signErr.Error(),
+		})
 	}
-
-	return base64.StdEncoding.EncodeToString(sig), nil
+	return result, nil
 }
 
 // handleStop implements the stop command.
@@ -3590,107 +5950,341 @@ func handleSubmitBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 		}
 	}
 
+	// Reject obviously invalid work, such as a stale difficulty or a bad
+	// merkle root, before queueing the block behind the blocks of other
+	// nodes.  The rejection reasons match those described in BIP0022.
+	if err := s.cfg.Chain.PrecheckBlock(block); err != nil {
+		rpcsLog.Infof("Rejected block %s via submitblock: %v",
+			block.Hash(), err)
+		return chainErrToGBTErrString(err), nil
+	}
+
 	// Process this block using the same rules as blocks coming from other
 	// nodes.  This will in turn relay it to the network like normal.
 	_, err = s.cfg.SyncMgr.SubmitBlock(block, blockchain.BFNone)
 	if err != nil {
-		return fmt.Sprintf("rejected: %s", err.Error()), nil
+		rpcsLog.Infof("Rejected block %s via submitblock: %v",
+			block.Hash(), err)
+		return chainErrToGBTErrString(err), nil
 	}
 
 	rpcsLog.Infof("Accepted block %s via submitblock", block.Hash())
 	return nil, nil
 }
 
+// handleSubmitHeader implements the submitheader command.
+func handleSubmitHeader(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.SubmitHeaderCmd)
+
+	// Deserialize the submitted header.
+	hexStr := c.HexData
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + c.HexData
+	}
+	serializedHeader, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+	var header wire.BlockHeader
+	err = header.Deserialize(bytes.NewReader(serializedHeader))
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDeserialization,
+			Message: "Block header decode failed: " + err.Error(),
+		}
+	}
+
+	// The header must build on a known header.
+	chain := s.cfg.Chain
+	if _, err := chain.HeaderByHash(&header.PrevBlock); err != nil {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCVerify,
+			Message: fmt.Sprintf("Must submit previous header (%v) "+
+				"first", header.PrevBlock),
+		}
+	}
+
+	if err := chain.ProcessBlockHeader(&header, blockchain.BFNone); err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCVerify,
+			Message: err.Error(),
+		}
+	}
+
+	// The header is not announced to peers here since they would request
+	// a block which is not available.  It is announced like any other
+	// block once its block is submitted and stored.
+	rpcsLog.Infof("Accepted block header %s via submitheader",
+		header.BlockHash())
+	return nil, nil
+}
+
+// traceCondNames maps the conditional execution states reported by the script
+// debugger to the strings returned by the tracescript command.
+var traceCondNames = map[int]string{
+	txscript.OpCondFalse: "false",
+	txscript.OpCondTrue:  "true",
+	txscript.OpCondSkip:  "skip",
+}
+
+// fetchTracePrevOut returns the output referenced by the passed outpoint from
+// either the memory pool or the main chain's unspent transaction outputs.
+func fetchTracePrevOut(s *rpcServer, outpoint *wire.OutPoint) (*wire.TxOut, error) {
+	if tx, err := s.cfg.TxMemPool.FetchTransaction(&outpoint.Hash); err == nil {
+		txOuts := tx.MsgTx().TxOut
+		if outpoint.Index >= uint32(len(txOuts)) {
+			return nil, rpcNoTxInfoError(&outpoint.Hash)
+		}
+		return txOuts[outpoint.Index], nil
+	}
+
+	entry, err := s.cfg.Chain.FetchUtxoEntry(*outpoint)
+	if err != nil {
+		return nil, internalRPCError(err.Error(), "Failed to fetch utxo")
+	}
+	if entry == nil || entry.IsSpent() {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCNoTxInfo,
+			Message: fmt.Sprintf("Previous output %v is not in the "+
+				"memory pool or unspent", outpoint),
+		}
+	}
+	return wire.NewTxOut(entry.Amount(), entry.PkScript()), nil
+}
+
+// handleTraceScript implements the tracescript command.
+func handleTraceScript(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*btcjson.TraceScriptCmd)
+
+	// Deserialize the transaction.
+	hexStr := c.HexTx
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+	serializedTx, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+	var mtx wire.MsgTx
+	err = mtx.Deserialize(bytes.NewReader(serializedTx))
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDeserialization,
+			Message: "TX decode failed: " + err.Error(),
+		}
+	}
+	if int(c.Index) >= len(mtx.TxIn) {
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCInvalidParameter,
+			Message: fmt.Sprintf("Input index %d is out of range for "+
+				"a transaction with %d inputs", c.Index,
+				len(mtx.TxIn)),
+		}
+	}
+
+	// Taproot signatures commit to every output spent by the transaction,
+	// so all of them are needed rather than only the traced one.
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for _, txIn := range mtx.TxIn {
+		prevOut, err := fetchTracePrevOut(s, &txIn.PreviousOutPoint)
+		if err != nil {
+			return nil, err
+		}
+		prevOuts.AddPrevOut(txIn.PreviousOutPoint, prevOut)
+	}
+
+	// Trace using the same flags the memory pool would use to accept the
+	// transaction.
+	flags := txscript.StandardVerifyFlags
+	taprootActive, err := s.cfg.Chain.IsDeploymentActive(
+		chaincfg.DeploymentTaproot,
+	)
+	if err != nil {
+		return nil, internalRPCError(err.Error(),
+			"Failed to determine taproot deployment state")
+	}
+	if !taprootActive {
+		flags &^= txscript.ScriptVerifyTaproot
+	}
+
+	prevOut := prevOuts.FetchPrevOutput(mtx.TxIn[c.Index].PreviousOutPoint)
+	result := btcjson.TraceScriptResult{
+		Steps: []btcjson.TraceScriptStep{},
+	}
+	debugger, err := txscript.NewStepDebugger(prevOut.PkScript, &mtx,
+		int(c.Index), flags, nil, txscript.NewTxSigHashes(&mtx, prevOuts),
+		prevOut.Value, prevOuts)
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	trace, err := debugger.Run()
+	if err != nil {
+		result.Error = err.Error()
+	}
+	result.Valid = err == nil
+	for _, state := range trace {
+		step := btcjson.TraceScriptStep{
+			ScriptIndex: state.ScriptIndex,
+			OpcodeIndex: state.OpcodeIndex,
+			Opcode:      state.Opcode,
+			Stack:       make([]string, len(state.Stack)),
+			AltStack:    make([]string, len(state.AltStack)),
+			CondStack:   make([]string, len(state.CondStack)),
+			Remaining:   state.Remaining,
+		}
+		for i, data := range state.Stack {
+			step.Stack[i] = hex.EncodeToString(data)
+		}
+		for i, data := range state.AltStack {
+			step.AltStack[i] = hex.EncodeToString(data)
+		}
+		for i, cond := range state.CondStack {
+			step.CondStack[i] = traceCondNames[cond]
+		}
+		if step.Remaining == nil {
+			step.Remaining = []string{}
+		}
+		result.Steps = append(result.Steps, step)
+	}
+
+	return result, nil
+}
+
 // handleUptime implements the uptime command.
 func handleUptime(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	return time.Now().Unix() - s.cfg.StartupTime, nil
 }
 
+// addressParams returns the parameters of the default or registered network the
+// passed encoded address belongs to, or nil when it can't be determined, such
+// as for raw public keys which don't encode a network.
+func addressParams(addr string) *chaincfg.Params {
+	// Bech32 encoded segwit and MWEB addresses are prefixed with the
+	// human-readable part of their network.
+	if hrp, _, err := bech32.Decode(addr); err == nil {
+		params, err := chaincfg.ParamsForBech32HRP(hrp)
+		if err != nil {
+			return nil
+		}
+		return params
+	}
+
+	_, netID, err := base58.CheckDecode(addr)
+	if err != nil {
+		return nil
+	}
+	if params, err := chaincfg.ParamsForPubKeyHashAddrID(netID); err == nil {
+		return params
+	}
+	if params, err := chaincfg.ParamsForScriptHashAddrID(netID); err == nil {
+		return params
+	}
+	return nil
+}
+
 // handleValidateAddress implements the validateaddress command.
 func handleValidateAddress(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	c := cmd.(*btcjson.ValidateAddressCmd)
 
+	// Decode the address for the network it belongs to, so the details of
+	// an address of another network can be reported as well.
 	result := btcjson.ValidateAddressChainResult{}
-	addr, err := ltcutil.DecodeAddress(c.Address, s.cfg.ChainParams)
+	params := addressParams(c.Address)
+	if params == nil {
+		params = s.cfg.ChainParams
+	}
+	addr, err := ltcutil.DecodeAddress(c.Address, params)
 	if err != nil {
 		// Return the default value (false) for IsValid.
+		result.Error = err.Error()
 		return result, nil
 	}
 
 	switch addr := addr.(type) {
 	case *ltcutil.AddressPubKeyHash:
+		result.Type = "p2pkh"
 		result.IsScript = btcjson.Bool(false)
 		result.IsWitness = btcjson.Bool(false)
 
 	case *ltcutil.AddressScriptHash:
+		result.Type = "p2sh"
 		result.IsScript = btcjson.Bool(true)
 		result.IsWitness = btcjson.Bool(false)
 
 	case *ltcutil.AddressPubKey:
+		result.Type = "p2pk"
 		result.IsScript = btcjson.Bool(false)
 		result.IsWitness = btcjson.Bool(false)
 
 	case *ltcutil.AddressWitnessPubKeyHash:
+		result.Type = "p2wpkh"
 		result.IsScript = btcjson.Bool(false)
 		result.IsWitness = btcjson.Bool(true)
 		result.WitnessVersion = btcjson.Int32(int32(addr.WitnessVersion()))
 		result.WitnessProgram = btcjson.String(hex.EncodeToString(addr.WitnessProgram()))
 
 	case *ltcutil.AddressWitnessScriptHash:
+		result.Type = "p2wsh"
+		result.IsScript = btcjson.Bool(true)
+		result.IsWitness = btcjson.Bool(true)
+		result.WitnessVersion = btcjson.Int32(int32(addr.WitnessVersion()))
+		result.WitnessProgram = btcjson.String(hex.EncodeToString(addr.WitnessProgram()))
+
+	case *ltcutil.AddressTaproot:
+		result.Type = "p2tr"
 		result.IsScript = btcjson.Bool(true)
 		result.IsWitness = btcjson.Bool(true)
 		result.WitnessVersion = btcjson.Int32(int32(addr.WitnessVersion()))
 		result.WitnessProgram = btcjson.String(hex.EncodeToString(addr.WitnessProgram()))
 
+	case *ltcutil.AddressMweb:
+		// MWEB addresses are stealth addresses rather than scripts, so
+		// there is no script paid to by them.
+		result.Type = "mweb"
+		result.IsScript = btcjson.Bool(false)
+		result.IsWitness = btcjson.Bool(false)
+
 	default:
 		// Handle the case when a new Address is supported by ltcutil, but none
 		// of the cases were matched in the switch block. The current behaviour
 		// is to do nothing, and only populate the Address and IsValid fields.
 	}
 
-	result.Address = addr.EncodeAddress()
-	result.IsValid = true
-
-	return result, nil
-}
-
-func verifyChain(s *rpcServer, level, depth int32) error {
-	best := s.cfg.Chain.BestSnapshot()
-	finishHeight := best.Height - depth
-	if finishHeight < 0 {
-		finishHeight = 0
-	}
-	rpcsLog.Infof("Verifying chain for %d blocks at level %d",
-		best.Height-finishHeight, level)
-
-	for height := best.Height; height > finishHeight; height-- {
-		// Level 0 just looks up the block.
-		block, err := s.cfg.Chain.BlockByHeight(height)
-		if err != nil {
-			rpcsLog.Errorf("Verify is unable to fetch block at "+
-				"height %d: %v", height, err)
-			return err
+	if result.Type != "" && result.Type != "mweb" {
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err == nil {
+			result.ScriptPubKey = hex.EncodeToString(pkScript)
 		}
+	}
 
-		// Level 1 does basic chain sanity checks.
-		if level > 0 {
-			err := blockchain.CheckBlockSanity(block,
-				s.cfg.ChainParams.PowLimit, s.cfg.TimeSource)
-			if err != nil {
-				rpcsLog.Errorf("Verify is unable to validate "+
-					"block at hash %v height %d: %v",
-					block.Hash(), height, err)
-				return err
-			}
-		}
+	// Several networks may share the same address IDs, in which case the
+	// active network takes precedence over the network found above.
+	result.Network = params.Name
+	if !addr.IsForNet(s.cfg.ChainParams) {
+		result.Error = fmt.Sprintf("address is for the %s network, "+
+			"not %s", params.Name, s.cfg.ChainParams.Name)
+		return result, nil
 	}
-	rpcsLog.Infof("Chain verify completed successfully")
+	result.Network = s.cfg.ChainParams.Name
+	result.Address = addr.EncodeAddress()
+	result.IsValid = true
 
-	return nil
+	return result, nil
 }
 
 // handleVerifyChain implements the verifychain command.
 func handleVerifyChain(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	return verifyChain(s, cmd, closeChan, nil)
+}
+
+// verifyChain implements the verifychain command for both requests and
+// operations, passing the number of blocks verified to progress if it is not
+// nil.
+func verifyChain(s *rpcServer, cmd interface{}, quit <-chan struct{},
+	progress blockchain.ProgressFunc) (interface{}, error) {
+
 	c := cmd.(*btcjson.VerifyChainCmd)
 
 	var checkLevel, checkDepth int32
@@ -3701,7 +6295,12 @@ func handleVerifyChain(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 		checkDepth = *c.CheckDepth
 	}
 
-	err := verifyChain(s, checkLevel, checkDepth)
+	// Corruption is reported by logging it and returning false rather than
+	// an error, matching the reference implementation.
+	err := s.cfg.Chain.VerifyChain(checkLevel, checkDepth, quit, progress)
+	if err != nil {
+		rpcsLog.Errorf("Chain verification failed: %v", err)
+	}
 	return err == nil, nil
 }
 
@@ -3719,53 +6318,41 @@ func handleVerifyMessage(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 		}
 	}
 
-	// Only P2PKH addresses are valid for signing.
-	if _, ok := addr.(*ltcutil.AddressPubKeyHash); !ok {
+	// Segwit addresses of any registered network are decoded, so ensure
+	// the address is for the active network.
+	if !addr.IsForNet(params) {
 		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCType,
-			Message: "Address is not a pay-to-pubkey-hash address",
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "Invalid address or key: address is for the wrong network",
 		}
 	}
 
 	// Decode base64 signature.
-	sig, err := base64.StdEncoding.DecodeString(c.Signature)
-	if err != nil {
+	if _, err := base64.StdEncoding.DecodeString(c.Signature); err != nil {
 		return nil, &btcjson.RPCError{
 			Code:    btcjson.ErrRPCParse.Code,
 			Message: "Malformed base64 encoding: " + err.Error(),
 		}
 	}
 
-	// Validate the signature - this just shows that it was valid at all.
-	// we will compare it with the key next.
-	var buf bytes.Buffer
-	wire.WriteVarString(&buf, 0, messageSignatureHeader)
-	wire.WriteVarString(&buf, 0, c.Message)
-	expectedMessageHash := chainhash.DoubleHashB(buf.Bytes())
-	pk, wasCompressed, err := ecdsa.RecoverCompact(sig,
-		expectedMessageHash)
-	if err != nil {
-		// Mirror Litecoin Core behavior, which treats error in
-		// RecoverCompact as invalid signature.
-		return false, nil
-	}
-
-	// Reconstruct the pubkey hash.
-	var serializedPK []byte
-	if wasCompressed {
-		serializedPK = pk.SerializeCompressed()
-	} else {
-		serializedPK = pk.SerializeUncompressed()
-	}
-	address, err := ltcutil.NewAddressPubKey(serializedPK, params)
-	if err != nil {
-		// Again mirror Litecoin Core behavior, which treats error in public key
-		// reconstruction as invalid signature.
+	// Mirror Litecoin Core behavior, which treats signatures that are not
+	// compact signatures as invalid signatures.
+	valid, err := signmessage.Verify(addr, c.Signature, c.Message)
+	switch err {
+	case nil:
+	case signmessage.ErrUnsupportedAddress:
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCType,
+			Message: "Address does not refer to a key",
+		}
+	case signmessage.ErrMalformedSignature:
 		return false, nil
+	default:
+		return nil, internalRPCError(err.Error(), "Failed to verify "+
+			"message")
 	}
 
-	// Return boolean if addresses match.
-	return address.EncodeAddress() == c.Address, nil
+	return valid, nil
 }
 
 // handleVersion implements the version command.
@@ -3785,6 +6372,11 @@ func handleVersion(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (in
 
 // rpcServer provides a concurrent safe RPC server to a chain server.
 type rpcServer struct {
+	// The following variables must only be used atomically.  They are
+	// placed first for 64-bit alignment on 32-bit platforms.
+	numRateLimited        uint64
+	numConcurrencyLimited uint64
+
 	started                int32
 	shutdown               int32
 	cfg                    rpcserverConfig
@@ -3796,9 +6388,31 @@ type rpcServer struct {
 	statusLock             sync.RWMutex
 	wg                     sync.WaitGroup
 	gbtWorkState           *gbtWorkState
+	getworkState           *getworkState
 	helpCacher             *helpCacher
 	requestProcessShutdown chan struct{}
 	quit                   chan int
+
+	// activeCmds houses the commands which are currently being processed
+	// as reported by the getrpcinfo command.
+	activeCmds     map[*activeRPCCmd]struct{}
+	activeCmdsLock sync.Mutex
+
+	// operations tracks the commands run in the background by the
+	// startoperation command.
+	operations *operationManager
+
+	// rateLimiter limits the rate of the requests of each client host and
+	// requestSem caps the number of requests processed concurrently across
+	// all clients.  They are nil when the respective limit is disabled.
+	rateLimiter *rpcRateLimiter
+	requestSem  semaphore
+}
+
+// activeRPCCmd describes a command which is being processed by the RPC server.
+type activeRPCCmd struct {
+	method  string
+	started time.Time
 }
 
 // httpStatusLine returns a response Status-Line (RFC 2616 Section 6.1)
@@ -3899,6 +6513,15 @@ func (s *rpcServer) NotifyNewTransactions(txns []*mempool.TxDesc) {
 	}
 }
 
+// NotifyRemovedTransaction notifies websocket clients of the passed
+// transaction and the reason it was removed from the mempool.  This function
+// should be called whenever a transaction is removed from the mempool.
+func (s *rpcServer) NotifyRemovedTransaction(txD *mempool.TxDesc,
+	reason mempool.RemovalReason) {
+
+	s.ntfnMgr.NotifyMempoolTxRemoved(txD.Tx, reason)
+}
+
 // limitConnections responds with a 503 service unavailable and returns true if
 // adding another client would exceed the maximum allow RPC clients.
 //
@@ -3938,6 +6561,11 @@ func (s *rpcServer) decrementClients() {
 // does not match the username and password expected, a non-nil error is
 // returned.
 //
+// A client which presented a certificate verified against the CAs configured
+// with --rpcclientca is authenticated as the admin user when the request has no
+// HTTP Basic authentication.  Otherwise, the supplied authentication is checked
+// as usual so clients may still restrict themselves to the limited user.
+//
 // This check is time-constant.
 //
 // The first bool return value signifies auth success (true if successful) and
@@ -3947,6 +6575,11 @@ func (s *rpcServer) decrementClients() {
 func (s *rpcServer) checkAuth(r *http.Request, require bool) (bool, bool, error) {
 	authhdr := r.Header["Authorization"]
 	if len(authhdr) <= 0 {
+		if cfg.RPCClientCA != "" && r.TLS != nil &&
+			len(r.TLS.VerifiedChains) > 0 {
+
+			return true, true, nil
+		}
 		if require {
 			rpcsLog.Warnf("RPC authentication failure from %s",
 				r.RemoteAddr)
@@ -4009,6 +6642,17 @@ func (s *rpcServer) standardCmdResult(cmd *parsedRPCCmd, closeChan <-chan struct
 	return nil, btcjson.ErrRPCMethodNotFound
 handled:
 
+	// Keep track of the command while it is being processed.
+	active := &activeRPCCmd{method: cmd.method, started: time.Now()}
+	s.activeCmdsLock.Lock()
+	s.activeCmds[active] = struct{}{}
+	s.activeCmdsLock.Unlock()
+	defer func() {
+		s.activeCmdsLock.Lock()
+		delete(s.activeCmds, active)
+		s.activeCmdsLock.Unlock()
+	}()
+
 	return handler(s, cmd.cmd, closeChan)
 }
 
@@ -4062,11 +6706,13 @@ func createMarshalledReply(rpcVersion btcjson.RPCVersion, id interface{}, result
 }
 
 // processRequest determines the incoming request type (single or batched),
-// parses it and returns a marshalled response.
-func (s *rpcServer) processRequest(request *btcjson.Request, isAdmin bool, closeChan <-chan struct{}) []byte {
+// parses it and returns a marshalled response along with whether the request
+// was rejected because the passed client host exceeded the request limits.
+func (s *rpcServer) processRequest(request *btcjson.Request, isAdmin bool, client string, closeChan <-chan struct{}) ([]byte, bool) {
 	var result interface{}
 	var err error
 	var jsonErr *btcjson.RPCError
+	var limited bool
 
 	if !isAdmin {
 		if _, ok := rpcLimited[request.Method]; !ok {
@@ -4084,25 +6730,29 @@ func (s *rpcServer) processRequest(request *btcjson.Request, isAdmin bool, close
 			msg, err := createMarshalledReply(request.Jsonrpc, request.ID, result, jsonErr)
 			if err != nil {
 				rpcsLog.Errorf("Failed to marshal reply: %v", err)
-				return nil
+				return nil, false
 			}
-			return msg
+			return msg, false
 		}
 
 		// Valid requests with no ID (notifications) must not have a response
 		// per the JSON-RPC spec.
 		if request.ID == nil {
-			return nil
+			return nil, false
 		}
 
 		// Attempt to parse the JSON-RPC request into a known
 		// concrete command.
 		parsedCmd := parseCmd(request)
+		var release func()
 		if parsedCmd.err != nil {
 			jsonErr = parsedCmd.err
+		} else if release, jsonErr = s.limitRequest(client, parsedCmd.method); jsonErr != nil {
+			limited = true
 		} else {
 			result, err = s.standardCmdResult(parsedCmd,
 				closeChan)
+			release()
 			if err != nil {
 				if rpcErr, ok := err.(*btcjson.RPCError); ok {
 					jsonErr = rpcErr
@@ -4120,9 +6770,9 @@ func (s *rpcServer) processRequest(request *btcjson.Request, isAdmin bool, close
 	msg, err := createMarshalledReply(request.Jsonrpc, request.ID, result, jsonErr)
 	if err != nil {
 		rpcsLog.Errorf("Failed to marshal reply: %v", err)
-		return nil
+		return nil, limited
 	}
-	return msg
+	return msg, limited
 }
 
 // jsonRPCRead handles reading and responding to RPC messages.
@@ -4180,6 +6830,8 @@ func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin
 	var results []json.RawMessage
 	var batchSize int
 	var batchedRequest bool
+	var limited bool
+	client := clientHost(r.RemoteAddr)
 
 	// Determine request type
 	if bytes.HasPrefix(body, batchedRequestPrefix) {
@@ -4225,7 +6877,8 @@ func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin
 			if req.ID == nil && !(cfg.RPCQuirks && req.Jsonrpc == "") {
 				return
 			}
-			resp = s.processRequest(&req, isAdmin, closeChan)
+			resp, limited = s.processRequest(&req, isAdmin, client,
+				closeChan)
 		}
 
 		if resp != nil {
@@ -4314,7 +6967,8 @@ func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin
 						continue
 					}
 
-					resp = s.processRequest(&req, isAdmin, closeChan)
+					resp, _ = s.processRequest(&req, isAdmin,
+						client, closeChan)
 					if resp != nil {
 						results = append(results, resp)
 					}
@@ -4349,8 +7003,15 @@ func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin
 		}
 	}
 
-	// Write the response.
-	err = s.writeHTTPResponseHeaders(r, w.Header(), http.StatusOK, buf)
+	// Write the response.  A single request which was rejected because
+	// the client exceeded the request limits is responded to with status
+	// 429 so that HTTP clients can back off, while the entries of a batch
+	// are rejected individually.
+	code := http.StatusOK
+	if limited {
+		code = http.StatusTooManyRequests
+	}
+	err = s.writeHTTPResponseHeaders(r, w.Header(), code, buf)
 	if err != nil {
 		rpcsLog.Error(err)
 		return
@@ -4409,6 +7070,10 @@ func (s *rpcServer) Start() {
 		s.jsonRPCRead(w, r, isAdmin)
 	})
 
+	// Unauthenticated health and readiness endpoints for probes.
+	rpcServeMux.HandleFunc("/healthz", s.healthHandler(false))
+	rpcServeMux.HandleFunc("/readyz", s.healthHandler(true))
+
 	// Websocket endpoint.
 	rpcServeMux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		authenticated, isAdmin, err := s.checkAuth(r, false)
@@ -4525,6 +7190,13 @@ type rpcserverConnManager interface {
 	// ConnectedCount returns the number of currently connected peers.
 	ConnectedCount() int32
 
+	// MaxPeers returns the maximum number of peers the server accepts.
+	MaxPeers() int
+
+	// SetMaxPeers changes the maximum number of peers the server accepts
+	// without disconnecting any of the connected peers.
+	SetMaxPeers(maxPeers int)
+
 	// NetTotals returns the sum of all bytes received and sent across the
 	// network for all peers.
 	NetTotals() (uint64, uint64)
@@ -4546,12 +7218,22 @@ type rpcserverConnManager interface {
 	AddRebroadcastInventory(iv *wire.InvVect, data interface{})
 
 	// RelayTransactions generates and relays inventory vectors for all of
-	// the passed transactions to all connected peers.
+	// the passed transactions, which were submitted to this node.  They are
+	// relayed along the Dandelion++ stem when it is enabled and to all
+	// connected peers otherwise.
 	RelayTransactions(txns []*mempool.TxDesc)
 
 	// NodeAddresses returns an array consisting node addresses which can
 	// potentially be used to find new nodes in the network.
 	NodeAddresses() []*wire.NetAddressV2
+
+	// AddrManagerStats returns statistics about the addresses known to the
+	// address manager.
+	AddrManagerStats() *addrmgr.Stats
+
+	// SampleAddresses returns up to the passed number of addresses known
+	// to the address manager picked at random.
+	SampleAddresses(count int) []*addrmgr.AddressInfo
 }
 
 // rpcserverSyncManager represents a sync manager for use with the RPC server.
@@ -4579,6 +7261,11 @@ type rpcserverSyncManager interface {
 	// current tip is reached, up to a max of wire.MaxBlockHeadersPerMsg
 	// hashes.
 	LocateHeaders(locators []*chainhash.Hash, hashStop *chainhash.Hash) []wire.BlockHeader
+
+	// FetchBlock requests the block with the provided hash from the peer
+	// with the provided ID, storing it once received even when it is on a
+	// side chain.
+	FetchBlock(hash *chainhash.Hash, peerID int32) error
 }
 
 // rpcserverConfig is a descriptor containing the RPC server configuration.
@@ -4602,6 +7289,10 @@ type rpcserverConfig struct {
 	// SyncMgr defines the sync manager for the RPC server to use.
 	SyncMgr rpcserverSyncManager
 
+	// Services defines the services the server that is hosting the RPC
+	// server advertises to its peers.
+	Services wire.ServiceFlag
+
 	// These fields allow the RPC server to interface with the local block
 	// chain data and state.
 	TimeSource  blockchain.MedianTimeSource
@@ -4612,6 +7303,11 @@ type rpcserverConfig struct {
 	// TxMemPool defines the transaction memory pool to interact with.
 	TxMemPool *mempool.TxPool
 
+	// SigCache and ScriptCache are the caches of verified signatures and
+	// scripts, whose statistics are reported by getmemoryinfo.
+	SigCache    *txscript.SigCache
+	ScriptCache *txscript.ScriptCache
+
 	// These fields allow the RPC server to interface with mining.
 	//
 	// Generator produces block templates and the CPUMiner solves them using
@@ -4622,13 +7318,23 @@ type rpcserverConfig struct {
 
 	// These fields define any optional indexes the RPC server can make use
 	// of to provide additional data when queried.
-	TxIndex   *indexers.TxIndex
-	AddrIndex *indexers.AddrIndex
-	CfIndex   *indexers.CfIndex
+	TxIndex    *indexers.TxIndex
+	AddrIndex  *indexers.AddrIndex
+	WatchIndex *indexers.WatchIndex
+	CfIndex    *indexers.CfIndex
+
+	// IndexManager manages the enabled optional indexes and reports how
+	// far they are synced with the best chain.  It is nil when no optional
+	// indexes are enabled.
+	IndexManager *indexers.Manager
 
 	// The fee estimator keeps track of how long transactions are left in
 	// the mempool before they are mined into blocks.
 	FeeEstimator *mempool.FeeEstimator
+
+	// MisbehaviorLog keeps the most recent protocol violations, bans and
+	// disconnections of misbehaving peers.
+	MisbehaviorLog *misbehaviorLog
 }
 
 // newRPCServer returns a new instance of the rpcServer struct.
@@ -4636,10 +7342,13 @@ func newRPCServer(config *rpcserverConfig) (*rpcServer, error) {
 	rpc := rpcServer{
 		cfg:                    *config,
 		statusLines:            make(map[int]string),
-		gbtWorkState:           newGbtWorkState(config.TimeSource),
+		gbtWorkState:           newGbtWorkState(config.TimeSource, config.ChainParams),
+		getworkState:           newGetworkState(),
 		helpCacher:             newHelpCacher(),
 		requestProcessShutdown: make(chan struct{}),
 		quit:                   make(chan int),
+		activeCmds:             make(map[*activeRPCCmd]struct{}),
+		operations:             newOperationManager(),
 	}
 	if cfg.RPCUser != "" && cfg.RPCPass != "" {
 		login := cfg.RPCUser + ":" + cfg.RPCPass
@@ -4651,6 +7360,13 @@ func newRPCServer(config *rpcserverConfig) (*rpcServer, error) {
 		auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
 		rpc.limitauthsha = sha256.Sum256([]byte(auth))
 	}
+	if cfg.RPCRateLimit > 0 {
+		rpc.rateLimiter = newRPCRateLimiter(cfg.RPCRateLimit,
+			cfg.RPCRateBurst)
+	}
+	if cfg.RPCMaxGlobalReqs > 0 {
+		rpc.requestSem = makeSemaphore(cfg.RPCMaxGlobalReqs)
+	}
 	rpc.ntfnMgr = newWsNotificationManager(&rpc)
 	rpc.cfg.Chain.Subscribe(rpc.handleBlockchainNotification)
 
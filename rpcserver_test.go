@@ -0,0 +1,1297 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btclog"
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/blockchain/indexers"
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/btcjson"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/database"
+	_ "github.com/ltcsuite/ltcd/database/ffldb"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/ltcutil/bech32"
+	"github.com/ltcsuite/ltcd/ltcutil/hdkeychain"
+	"github.com/ltcsuite/ltcd/ltcutil/mweb"
+	"github.com/ltcsuite/ltcd/ltcutil/psbt"
+	"github.com/ltcsuite/ltcd/mempool"
+	"github.com/ltcsuite/ltcd/mining"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestHandleGetChainParams ensures the getchainparams command describes the
+// parameters of each network, including every defined deployment.
+func TestHandleGetChainParams(t *testing.T) {
+	networks := []*chaincfg.Params{
+		&chaincfg.MainNetParams,
+		&chaincfg.TestNet4Params,
+		&chaincfg.RegressionNetParams,
+		&chaincfg.SimNetParams,
+		&chaincfg.SigNetParams,
+	}
+	for _, params := range networks {
+		s := &rpcServer{cfg: rpcserverConfig{ChainParams: params}}
+		res, err := handleGetChainParams(s, &btcjson.GetChainParamsCmd{}, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", params.Name, err)
+		}
+		result := res.(*btcjson.GetChainParamsResult)
+
+		if result.Name != params.Name || result.Net != uint32(params.Net) {
+			t.Errorf("%s: got network %s (%d)", params.Name,
+				result.Name, result.Net)
+		}
+		if result.GenesisHash != params.GenesisHash.String() {
+			t.Errorf("%s: got genesis hash %s, want %s", params.Name,
+				result.GenesisHash, params.GenesisHash)
+		}
+		if result.Bech32HRPSegwit != params.Bech32HRPSegwit ||
+			result.PubKeyHashAddrID != params.PubKeyHashAddrID {
+
+			t.Errorf("%s: address parameters do not match",
+				params.Name)
+		}
+		if result.ASERTHeight != params.ASERTHeight ||
+			result.SubsidyReductionInterval != params.SubsidyReductionInterval {
+
+			t.Errorf("%s: consensus parameters do not match",
+				params.Name)
+		}
+		if int64(result.TargetTimePerBlock) != int64(params.TargetTimePerBlock.Seconds()) {
+			t.Errorf("%s: got target time per block %d", params.Name,
+				result.TargetTimePerBlock)
+		}
+		if len(result.Deployments) != chaincfg.DefinedDeployments {
+			t.Errorf("%s: got %d deployments, want %d", params.Name,
+				len(result.Deployments), chaincfg.DefinedDeployments)
+		}
+		if len(result.Checkpoints) != len(params.Checkpoints) {
+			t.Errorf("%s: got %d checkpoints, want %d", params.Name,
+				len(result.Checkpoints), len(params.Checkpoints))
+		}
+	}
+}
+
+// TestHandleGetBlockSubsidy ensures the getblocksubsidy command returns the
+// subsidy of the network at the requested height and rejects negative heights.
+func TestHandleGetBlockSubsidy(t *testing.T) {
+	params := chaincfg.MainNetParams
+	s := &rpcServer{cfg: rpcserverConfig{ChainParams: &params}}
+
+	tests := []struct {
+		height int32
+		want   float64
+	}{
+		{0, 50},
+		{params.SubsidyReductionInterval - 1, 50},
+		{params.SubsidyReductionInterval, 25},
+		{params.SubsidyReductionInterval * 2, 12.5},
+	}
+	for _, test := range tests {
+		cmd := btcjson.NewGetBlockSubsidyCmd(btcjson.Int32(test.height))
+		res, err := handleGetBlockSubsidy(s, cmd, nil)
+		if err != nil {
+			t.Fatalf("height %d: unexpected error: %v", test.height,
+				err)
+		}
+		result := res.(*btcjson.GetBlockSubsidyResult)
+		if result.Height != test.height || result.Subsidy != test.want {
+			t.Errorf("height %d: got subsidy %v at height %d, want %v",
+				test.height, result.Subsidy, result.Height, test.want)
+		}
+	}
+
+	cmd := btcjson.NewGetBlockSubsidyCmd(btcjson.Int32(-1))
+	if _, err := handleGetBlockSubsidy(s, cmd, nil); err == nil {
+		t.Fatalf("negative height: expected an error")
+	}
+
+	// A subsidy schedule overrides the halving of the network.
+	params.SubsidySchedule = []chaincfg.SubsidyStep{{Height: 0, Subsidy: 1e8}}
+	cmd = btcjson.NewGetBlockSubsidyCmd(btcjson.Int32(
+		params.SubsidyReductionInterval))
+	res, err := handleGetBlockSubsidy(s, cmd, nil)
+	if err != nil {
+		t.Fatalf("subsidy schedule: unexpected error: %v", err)
+	}
+	if subsidy := res.(*btcjson.GetBlockSubsidyResult).Subsidy; subsidy != 1 {
+		t.Errorf("subsidy schedule: got subsidy %v, want 1", subsidy)
+	}
+}
+
+// TestHandleGetDifficultyHistory ensures the getdifficultyhistory command
+// rejects invalid and oversized ranges before reading the block index.
+func TestHandleGetDifficultyHistory(t *testing.T) {
+	s := &rpcServer{cfg: rpcserverConfig{
+		ChainParams: &chaincfg.MainNetParams,
+	}}
+
+	tests := []struct {
+		name        string
+		start, end  int32
+		wantErrCode btcjson.RPCErrorCode
+	}{
+		{"negative start", -1, 10, btcjson.ErrRPCOutOfRange},
+		{"end before start", 10, 9, btcjson.ErrRPCOutOfRange},
+		{"too many blocks", 0, maxDifficultyHistory,
+			btcjson.ErrRPCInvalidParameter},
+	}
+	for _, test := range tests {
+		cmd := btcjson.NewGetDifficultyHistoryCmd(test.start,
+			btcjson.Int32(test.end))
+		_, err := handleGetDifficultyHistory(s, cmd, nil)
+		rpcErr, ok := err.(*btcjson.RPCError)
+		if !ok || rpcErr.Code != test.wantErrCode {
+			t.Errorf("%s: unexpected error %v, want code %d",
+				test.name, err, test.wantErrCode)
+		}
+	}
+}
+
+// TestGetDifficultyRatio ensures the difficulty reported by getdifficulty is
+// relative to the compact form of the proof-of-work limit of the network like
+// the difficulty reported by the other commands.
+func TestGetDifficultyRatio(t *testing.T) {
+	s, _ := newTemplateTestServer(t)
+	params := s.cfg.ChainParams
+	if ratio := getDifficultyRatio(params.PowLimitBits, params); ratio != 1 {
+		t.Errorf("getDifficultyRatio: got %v at the pow limit, want 1",
+			ratio)
+	}
+
+	best := s.cfg.Chain.BestSnapshot()
+	want := getDifficultyRatio(best.Bits, params)
+	result, err := handleGetDifficulty(s, &btcjson.GetDifficultyCmd{}, nil)
+	if err != nil {
+		t.Fatalf("getdifficulty: unexpected error: %v", err)
+	}
+	if result.(float64) != want {
+		t.Errorf("getdifficulty: got %v, want %v", result, want)
+	}
+	result, err = handleGetDifficulty(s, btcjson.NewGetDifficultyVerboseCmd(),
+		nil)
+	if err != nil {
+		t.Fatalf("getdifficulty verbose: unexpected error: %v", err)
+	}
+	if got := result.(*btcjson.GetDifficultyResult).Difficulty; got != want {
+		t.Errorf("getdifficulty verbose: got %v, want %v", got, want)
+	}
+}
+
+// TestTxOutSetSubsidyEras ensures the blocks of the main chain are grouped into
+// runs paying the same subsidy for the supply audit of gettxoutsetinfo.
+func TestTxOutSetSubsidyEras(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	params.SubsidyReductionInterval = 10
+
+	eras, total := txOutSetSubsidyEras(24, &params)
+	want := []btcjson.TxOutSetSubsidyEra{
+		{StartHeight: 0, EndHeight: 9, Subsidy: 50, Blocks: 10, TotalAmount: 500},
+		{StartHeight: 10, EndHeight: 19, Subsidy: 25, Blocks: 10, TotalAmount: 250},
+		{StartHeight: 20, EndHeight: 24, Subsidy: 12.5, Blocks: 5, TotalAmount: 62.5},
+	}
+	if !reflect.DeepEqual(eras, want) {
+		t.Fatalf("unexpected eras %+v, want %+v", eras, want)
+	}
+	if total != 81250000000 {
+		t.Fatalf("unexpected total subsidy %d", total)
+	}
+
+	// The genesis block alone is a single era.
+	eras, total = txOutSetSubsidyEras(0, &params)
+	if len(eras) != 1 || eras[0].Blocks != 1 || total != 5000000000 {
+		t.Fatalf("unexpected genesis eras %+v with total %d", eras, total)
+	}
+}
+
+// solveTestBlock returns a block with only a coinbase transaction paying to the
+// passed script which builds on the passed parent and satisfies the proof of
+// work of the regression test network.
+func solveTestBlock(t *testing.T, parent *chainhash.Hash, height int32,
+	timestamp time.Time, pkScript []byte) *ltcutil.Block {
+
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{},
+			wire.MaxPrevOutIndex),
+		SignatureScript: []byte{0x51, byte(height), byte(height >> 8)},
+		Sequence:        wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(0, pkScript))
+
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:    4,
+			PrevBlock:  *parent,
+			MerkleRoot: coinbase.TxHash(),
+			Timestamp:  timestamp,
+			Bits:       chaincfg.RegressionNetParams.PowLimitBits,
+		},
+		Transactions: []*wire.MsgTx{coinbase},
+	}
+
+	target := blockchain.CompactToBig(block.Header.Bits)
+	for nonce := uint32(0); ; nonce++ {
+		block.Header.Nonce = nonce
+		hash := block.Header.PowHash()
+		if blockchain.HashToBig(&hash).Cmp(target) <= 0 {
+			break
+		}
+		if nonce == ^uint32(0) {
+			t.Fatalf("unable to solve block at height %d", height)
+		}
+	}
+	return ltcutil.NewBlock(block)
+}
+
+// TestHandleRescanBlockchain ensures the rescanblockchain command finds the
+// transactions paying to the requested scripts, with and without the committed
+// filters.
+func TestHandleRescanBlockchain(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	db, err := database.Create("ffldb", t.TempDir(), params.Net)
+	if err != nil {
+		t.Fatalf("unable to create database: %v", err)
+	}
+	defer db.Close()
+
+	// The log rotator is not initialized by tests.
+	blockchain.UseLogger(btclog.Disabled)
+	indexers.UseLogger(btclog.Disabled)
+	defer blockchain.UseLogger(chanLog)
+	defer indexers.UseLogger(indxLog)
+
+	cfIndex := indexers.NewCfIndex(db, &params)
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:           db,
+		ChainParams:  &params,
+		TimeSource:   blockchain.NewMedianTime(),
+		IndexManager: indexers.NewManager(db, []indexers.Indexer{cfIndex}),
+	})
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+
+	// Only the second block pays to the watched script.
+	watched := []byte{0x00, 0x14, 0x01, 0x02, 0x03}
+	other := []byte{0x51}
+	timestamp := params.GenesisBlock.Header.Timestamp
+	var blocks []*ltcutil.Block
+	for _, pkScript := range [][]byte{other, watched, other} {
+		best := chain.BestSnapshot()
+		timestamp = timestamp.Add(time.Minute)
+		block := solveTestBlock(t, &best.Hash, best.Height+1, timestamp,
+			pkScript)
+		if _, _, err := chain.ProcessBlock(block, blockchain.BFNone); err != nil {
+			t.Fatalf("ProcessBlock: unexpected error: %v", err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	for i, block := range blocks {
+		matches := rescanFilterMatches(cfIndex, block.Hash(),
+			[][]byte{watched})
+		if matches != (i == 1) {
+			t.Errorf("block %d: filter match is %v", i+1, matches)
+		}
+	}
+
+	s := &rpcServer{cfg: rpcserverConfig{
+		Chain:       chain,
+		ChainParams: &params,
+		CfIndex:     cfIndex,
+	}}
+	scripts := []string{hex.EncodeToString(watched)}
+	for _, withFilters := range []bool{true, false} {
+		if !withFilters {
+			s.cfg.CfIndex = nil
+		}
+		cmd := btcjson.NewRescanBlockchainCmd(nil, nil, &scripts, nil)
+		res, err := handleRescanBlockchain(s, cmd, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result := res.(*btcjson.RescanBlockchainResult)
+		if result.StartHeight != 0 || result.StopHeight != 3 {
+			t.Fatalf("scanned heights %d to %d", result.StartHeight,
+				result.StopHeight)
+		}
+		want := blocks[1].Transactions()[0].Hash().String()
+		if len(result.Matches) != 1 || result.Matches[0].TxID != want ||
+			result.Matches[0].Height != 2 {
+
+			t.Fatalf("unexpected matches %+v", result.Matches)
+		}
+	}
+
+	cmd := btcjson.NewRescanBlockchainCmd(btcjson.Int32(3),
+		btcjson.Int32(2), &scripts, nil)
+	if _, err := handleRescanBlockchain(s, cmd, nil); err == nil {
+		t.Fatalf("stop height before start height: expected an error")
+	}
+}
+
+// healthTestConnManager is a connection manager without any connected peers
+// for testing the health endpoints.
+type healthTestConnManager struct {
+	rpcserverConnManager
+}
+
+// ConnectedPeers returns no peers.
+func (healthTestConnManager) ConnectedPeers() []rpcserverPeer {
+	return nil
+}
+
+// TestCheckHealth ensures the health report requires the database to be
+// available, while the readiness report additionally requires peers and the
+// chain to be synced.
+func TestCheckHealth(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	db, err := database.Create("ffldb", t.TempDir(), params.Net)
+	if err != nil {
+		t.Fatalf("unable to create database: %v", err)
+	}
+	defer db.Close()
+
+	// The log rotator is not initialized by tests.
+	blockchain.UseLogger(btclog.Disabled)
+	defer blockchain.UseLogger(chanLog)
+
+	timeSource := blockchain.NewMedianTime()
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		ChainParams: &params,
+		TimeSource:  timeSource,
+	})
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+	s := &rpcServer{cfg: rpcserverConfig{
+		ChainParams: &params,
+		Chain:       chain,
+		DB:          db,
+		ConnMgr:     healthTestConnManager{},
+		TimeSource:  timeSource,
+	}}
+
+	report, healthy := s.checkHealth(false)
+	if !healthy || report.Status != "ok" || !report.Database ||
+		len(report.Failures) != 0 {
+
+		t.Fatalf("healthz: unexpected report %+v", report)
+	}
+	if report.Progress != 1 || report.LastBlockAge <= 0 {
+		t.Fatalf("healthz: unexpected sync progress in %+v", report)
+	}
+
+	// The node isn't ready without peers and with only the genesis block.
+	report, healthy = s.checkHealth(true)
+	if healthy || report.Status != "unavailable" || len(report.Failures) != 2 {
+		t.Fatalf("readyz: unexpected report %+v", report)
+	}
+
+	// The node isn't healthy once the database is unavailable.
+	db.Close()
+	report, healthy = s.checkHealth(false)
+	if healthy || report.Database {
+		t.Fatalf("healthz: unexpected report with a closed database %+v",
+			report)
+	}
+}
+
+// TestHandleGetMemoryInfo ensures the getmemoryinfo command reports the
+// statistics of the caches and rejects the modes which are not supported.
+func TestHandleGetMemoryInfo(t *testing.T) {
+	sigCache := txscript.NewSigCache(10)
+	s := &rpcServer{cfg: rpcserverConfig{SigCache: sigCache}}
+
+	res, err := handleGetMemoryInfo(s, btcjson.NewGetMemoryInfoCmd(
+		btcjson.String("stats")), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := res.(*btcjson.GetMemoryInfoResult)
+	if result.Caches.SigCache.MaxEntries != 10 {
+		t.Errorf("sigcache: got max entries %d, want 10",
+			result.Caches.SigCache.MaxEntries)
+	}
+	if result.Runtime.Sys == 0 || result.Runtime.Goroutines == 0 {
+		t.Errorf("missing runtime statistics %+v", result.Runtime)
+	}
+
+	for _, mode := range []string{"mallocinfo", "unknown"} {
+		_, err := handleGetMemoryInfo(s, btcjson.NewGetMemoryInfoCmd(
+			btcjson.String(mode)), nil)
+		rpcErr, ok := err.(*btcjson.RPCError)
+		if !ok || rpcErr.Code != btcjson.ErrRPCInvalidParameter {
+			t.Errorf("mode %s: unexpected error %v", mode, err)
+		}
+	}
+}
+
+// TestHandleValidateAddress ensures the validateaddress command reports the
+// type and network of addresses and rejects addresses of other networks.
+func TestHandleValidateAddress(t *testing.T) {
+	s := &rpcServer{cfg: rpcserverConfig{
+		ChainParams: &chaincfg.RegressionNetParams,
+	}}
+
+	hash := make([]byte, 20)
+	p2pkh, _ := ltcutil.NewAddressPubKeyHash(hash,
+		&chaincfg.RegressionNetParams)
+	p2wpkh, _ := ltcutil.NewAddressWitnessPubKeyHash(hash,
+		&chaincfg.RegressionNetParams)
+	mainP2WPKH, _ := ltcutil.NewAddressWitnessPubKeyHash(hash,
+		&chaincfg.MainNetParams)
+	program := make([]byte, 32)
+	p2tr, _ := ltcutil.NewAddressTaproot(program,
+		&chaincfg.RegressionNetParams)
+
+	// Addresses of witness versions after 1 must use the bech32m checksum
+	// of BIP 350 as well, so only the bech32m encoding of a version 2
+	// address is recognized, even though the version isn't supported.
+	hrp := chaincfg.RegressionNetParams.Bech32HRPSegwit
+	converted, _ := bech32.ConvertBits(program, 8, 5, true)
+	v2Bech32, _ := bech32.Encode(hrp, append([]byte{2}, converted...))
+	v2Bech32m, _ := bech32.EncodeM(hrp, append([]byte{2}, converted...))
+
+	// MWEB stealth addresses are derived from the watch-only keychain of a
+	// wallet, and the scan and spend keys encoded in them must be valid
+	// curve points.
+	master, _ := hdkeychain.NewMaster(make([]byte, hdkeychain.RecommendedSeedLen),
+		&chaincfg.RegressionNetParams)
+	keychain, err := mweb.DeriveKeychain(master)
+	if err != nil {
+		t.Fatalf("DeriveKeychain: %v", err)
+	}
+	stealth := ltcutil.NewAddressMweb(keychain.WatchOnly().Address(0),
+		&chaincfg.RegressionNetParams)
+	badKeys := make([]byte, 66)
+	badKeys[0], badKeys[33] = 0x05, 0x02
+	converted, _ = bech32.ConvertBits(badKeys, 8, 5, true)
+	badStealth, _ := bech32.Encode(chaincfg.RegressionNetParams.Bech32HRPMweb,
+		append([]byte{0}, converted...))
+
+	tests := []struct {
+		name    string
+		addr    string
+		valid   bool
+		typ     string
+		network string
+		script  string
+		err     string
+	}{
+		{
+			name:    "p2pkh",
+			addr:    p2pkh.EncodeAddress(),
+			valid:   true,
+			typ:     "p2pkh",
+			network: "regtest",
+			script:  "76a914" + hex.EncodeToString(hash) + "88ac",
+		},
+		{
+			name:    "p2wpkh",
+			addr:    p2wpkh.EncodeAddress(),
+			valid:   true,
+			typ:     "p2wpkh",
+			network: "regtest",
+			script:  "0014" + hex.EncodeToString(hash),
+		},
+		{
+			name:    "p2tr",
+			addr:    p2tr.EncodeAddress(),
+			valid:   true,
+			typ:     "p2tr",
+			network: "regtest",
+			script:  "5120" + hex.EncodeToString(program),
+		},
+		{
+			name: "v2 with bech32 checksum",
+			addr: v2Bech32,
+			err:  "checksum mismatch",
+		},
+		{
+			name: "v2 with bech32m checksum",
+			addr: v2Bech32m,
+			err:  "unsupported witness version",
+		},
+		{
+			name:    "mweb",
+			addr:    stealth.EncodeAddress(),
+			valid:   true,
+			typ:     "mweb",
+			network: "regtest",
+		},
+		{
+			name: "mweb with invalid scan key",
+			addr: badStealth,
+		},
+		{
+			name:    "other network",
+			addr:    mainP2WPKH.EncodeAddress(),
+			typ:     "p2wpkh",
+			network: "mainnet",
+			script:  "0014" + hex.EncodeToString(hash),
+		},
+		{
+			name: "malformed",
+			addr: "bogus",
+		},
+	}
+
+	for _, test := range tests {
+		res, err := handleValidateAddress(s,
+			btcjson.NewValidateAddressCmd(test.addr), nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		result := res.(btcjson.ValidateAddressChainResult)
+		if result.IsValid != test.valid || result.Type != test.typ ||
+			result.Network != test.network ||
+			result.ScriptPubKey != test.script {
+
+			t.Errorf("%s: unexpected result %+v", test.name, result)
+
+		}
+		if result.IsValid == (result.Error != "") ||
+			!strings.Contains(result.Error, test.err) {
+
+			t.Errorf("%s: unexpected error %q", test.name,
+				result.Error)
+		}
+	}
+}
+
+// TestHandleSignVerifyMessage ensures messages signed for each address type
+// with the signmessagewithprivkey command are verified by the verifymessage
+// command.
+func TestHandleSignVerifyMessage(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	s := &rpcServer{cfg: rpcserverConfig{ChainParams: params}}
+
+	key, _ := btcec.PrivKeyFromBytes([]byte{0x01, 0x02, 0x03, 0x04})
+	wif, err := ltcutil.NewWIF(key, params, true)
+	if err != nil {
+		t.Fatalf("NewWIF: %v", err)
+	}
+	pkHash := ltcutil.Hash160(key.PubKey().SerializeCompressed())
+	p2pkh, _ := ltcutil.NewAddressPubKeyHash(pkHash, params)
+	p2wpkh, _ := ltcutil.NewAddressWitnessPubKeyHash(pkHash, params)
+
+	tests := []struct {
+		addrType string
+		addr     ltcutil.Address
+	}{
+		{"legacy", p2pkh},
+		{"bech32", p2wpkh},
+	}
+	for _, test := range tests {
+		sig, err := handleSignMessageWithPrivKey(s,
+			btcjson.NewSignMessageWithPrivKey(wif.String(), "msg",
+				btcjson.String(test.addrType)), nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.addrType, err)
+		}
+		valid, err := handleVerifyMessage(s, btcjson.NewVerifyMessageCmd(
+			test.addr.EncodeAddress(), sig.(string), "msg"), nil)
+		if err != nil || valid != true {
+			t.Errorf("%s: got %v (err %v), want true", test.addrType,
+				valid, err)
+		}
+	}
+
+	_, err = handleSignMessageWithPrivKey(s, btcjson.NewSignMessageWithPrivKey(
+		wif.String(), "msg", btcjson.String("bogus")), nil)
+	if rpcErr, ok := err.(*btcjson.RPCError); !ok ||
+		rpcErr.Code != btcjson.ErrRPCInvalidParameter {
+
+		t.Errorf("unexpected error for unknown address type: %v", err)
+	}
+}
+
+// TestHandlePsbt ensures PSBTs converted from transactions by the converttopsbt
+// command are decoded, combined and finalized by the other PSBT commands.
+func TestHandlePsbt(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	s := &rpcServer{cfg: rpcserverConfig{ChainParams: params}}
+
+	hash := make([]byte, 20)
+	p2wpkh, _ := ltcutil.NewAddressWitnessPubKeyHash(hash, params)
+	pkScript, _ := txscript.PayToAddrScript(p2wpkh)
+	mtx := wire.NewMsgTx(2)
+	mtx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	mtx.AddTxOut(wire.NewTxOut(150000000, pkScript))
+	var buf bytes.Buffer
+	mtx.Serialize(&buf)
+	txHex := hex.EncodeToString(buf.Bytes())
+
+	res, err := handleConvertToPsbt(s, btcjson.NewConvertToPsbtCmd(txHex,
+		nil, nil), nil)
+	if err != nil {
+		t.Fatalf("converttopsbt: unexpected error: %v", err)
+	}
+	b64 := res.(string)
+
+	res, err = handleDecodePsbt(s, btcjson.NewDecodePsbtCmd(b64), nil)
+	if err != nil {
+		t.Fatalf("decodepsbt: unexpected error: %v", err)
+	}
+	decoded := res.(btcjson.DecodePsbtResult)
+	if decoded.Tx.Txid != mtx.TxHash().String() ||
+		len(decoded.Inputs) != 1 || len(decoded.Outputs) != 1 ||
+		decoded.Outputs[0].Amount != 1.5 ||
+		decoded.Outputs[0].ScriptPubKey.Address != p2wpkh.EncodeAddress() ||
+		decoded.Fee != nil {
+
+		t.Fatalf("decodepsbt: unexpected result %+v", decoded)
+	}
+
+	res, err = handleCombinePsbt(s, btcjson.NewCombinePsbtCmd(
+		[]string{b64, b64}), nil)
+	if err != nil || res.(string) != b64 {
+		t.Fatalf("combinepsbt: got %v (err %v), want %s", res, err, b64)
+	}
+
+	// The input can't be finalized without its signature.
+	res, err = handleFinalizePsbt(s, btcjson.NewFinalizePsbtCmd(b64, nil),
+		nil)
+	if err != nil {
+		t.Fatalf("finalizepsbt: unexpected error: %v", err)
+	}
+	finalized := res.(*btcjson.FinalizePsbtResult)
+	if finalized.Complete || finalized.Psbt != b64 || finalized.Hex != "" {
+		t.Fatalf("finalizepsbt: unexpected result %+v", finalized)
+	}
+
+	// Transactions with signatures are only converted when the signatures
+	// may be discarded.
+	signed := mtx.Copy()
+	signed.TxIn[0].SignatureScript = []byte{txscript.OP_TRUE}
+	buf.Reset()
+	signed.Serialize(&buf)
+	signedHex := hex.EncodeToString(buf.Bytes())
+	_, err = handleConvertToPsbt(s, btcjson.NewConvertToPsbtCmd(signedHex,
+		nil, nil), nil)
+	if rpcErr, ok := err.(*btcjson.RPCError); !ok ||
+		rpcErr.Code != btcjson.ErrRPCDeserialization {
+
+		t.Fatalf("converttopsbt: unexpected error for signed "+
+			"transaction: %v", err)
+	}
+	res, err = handleConvertToPsbt(s, btcjson.NewConvertToPsbtCmd(signedHex,
+		btcjson.Bool(true), nil), nil)
+	if err != nil || res.(string) != b64 {
+		t.Fatalf("converttopsbt: got %v (err %v), want %s", res, err, b64)
+	}
+
+	// A PSBT whose inputs are finalized is extracted into the signed
+	// transaction.
+	packet, _ := psbt.NewFromUnsignedTx(mtx)
+	packet.Inputs[0].FinalScriptSig = signed.TxIn[0].SignatureScript
+	finalB64, _ := packet.B64Encode()
+	res, err = handleFinalizePsbt(s, btcjson.NewFinalizePsbtCmd(finalB64,
+		nil), nil)
+	if err != nil {
+		t.Fatalf("finalizepsbt: unexpected error: %v", err)
+	}
+	finalized = res.(*btcjson.FinalizePsbtResult)
+	if !finalized.Complete || finalized.Hex != signedHex {
+		t.Fatalf("finalizepsbt: unexpected result %+v", finalized)
+	}
+
+	// Outputs paid by a peg-out of an MWEB kernel are reported along with
+	// the kernel.
+	kernelIndex := uint32(0)
+	fee := ltcutil.Amount(10000)
+	packet = &psbt.Packet{
+		PsbtVersion: 2,
+		TxVersion:   2,
+		Outputs: []psbt.POutput{{
+			Amount:           150000000,
+			PKScript:         pkScript,
+			MwebPegoutKernel: &kernelIndex,
+		}},
+		Kernels: []psbt.PKernel{{Fee: &fee}},
+	}
+	pegoutB64, _ := packet.B64Encode()
+	res, err = handleDecodePsbt(s, btcjson.NewDecodePsbtCmd(pegoutB64), nil)
+	if err != nil {
+		t.Fatalf("decodepsbt: unexpected error: %v", err)
+	}
+	decoded = res.(btcjson.DecodePsbtResult)
+	if len(decoded.Tx.Vout) != 0 || len(decoded.Outputs) != 1 ||
+		decoded.Outputs[0].MwebPegoutKernel == nil ||
+		*decoded.Outputs[0].MwebPegoutKernel != 0 ||
+		len(decoded.Kernels) != 1 || decoded.Kernels[0].Signed {
+
+		t.Fatalf("decodepsbt: unexpected result %+v", decoded)
+	}
+}
+
+// TestRPCRateLimiter ensures the rate limiter allows bursts of requests, refills
+// the bucket of each client host over time and tracks the hosts separately.
+func TestRPCRateLimiter(t *testing.T) {
+	limiter := newRPCRateLimiter(2, 3)
+	now := time.Unix(1700000000, 0)
+
+	for i := 0; i < 3; i++ {
+		if !limiter.allow("10.0.0.1", now) {
+			t.Fatalf("request %d of the burst was rejected", i)
+		}
+	}
+	if limiter.allow("10.0.0.1", now) {
+		t.Fatalf("request exceeding the burst was allowed")
+	}
+	if !limiter.allow("10.0.0.2", now) {
+		t.Fatalf("request from another host was rejected")
+	}
+
+	// Half a second refills a single token at a rate of 2 per second.
+	now = now.Add(500 * time.Millisecond)
+	if !limiter.allow("10.0.0.1", now) {
+		t.Fatalf("request after refilling a token was rejected")
+	}
+	if limiter.allow("10.0.0.1", now) {
+		t.Fatalf("request exceeding the refilled tokens was allowed")
+	}
+
+	// The bucket never holds more than the burst.
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if !limiter.allow("10.0.0.1", now) {
+			t.Fatalf("request %d after refilling was rejected", i)
+		}
+	}
+	if limiter.allow("10.0.0.1", now) {
+		t.Fatalf("bucket was refilled past the burst")
+	}
+}
+
+// TestLimitRequest ensures requests are rejected once the global cap on
+// concurrent requests is reached, except for the mining requests, and that the
+// rejected requests are counted.
+func TestLimitRequest(t *testing.T) {
+	s := &rpcServer{requestSem: makeSemaphore(1)}
+
+	release, jsonErr := s.limitRequest("10.0.0.1", "getblockcount")
+	if jsonErr != nil {
+		t.Fatalf("first request was rejected: %v", jsonErr)
+	}
+	_, jsonErr = s.limitRequest("10.0.0.2", "getblockcount")
+	if jsonErr == nil || jsonErr.Code != btcjson.ErrRPCLimitExceeded {
+		t.Fatalf("request over the cap was not rejected: %v", jsonErr)
+	}
+	exemptRelease, jsonErr := s.limitRequest("10.0.0.2", "getblocktemplate")
+	if jsonErr != nil {
+		t.Fatalf("mining request was rejected: %v", jsonErr)
+	}
+	exemptRelease()
+
+	release()
+	release, jsonErr = s.limitRequest("10.0.0.2", "getblockcount")
+	if jsonErr != nil {
+		t.Fatalf("request after releasing the cap was rejected: %v",
+			jsonErr)
+	}
+	release()
+
+	s.rateLimiter = newRPCRateLimiter(1, 1)
+	release, jsonErr = s.limitRequest("10.0.0.1", "getblocktemplate")
+	if jsonErr != nil {
+		t.Fatalf("first rate limited request was rejected: %v", jsonErr)
+	}
+	release()
+	_, jsonErr = s.limitRequest("10.0.0.1", "getblocktemplate")
+	if jsonErr == nil || jsonErr.Code != btcjson.ErrRPCLimitExceeded {
+		t.Fatalf("request over the rate limit was not rejected: %v",
+			jsonErr)
+	}
+
+	if s.numRateLimited != 1 || s.numConcurrencyLimited != 1 {
+		t.Fatalf("got %d rate limited and %d concurrency limited "+
+			"requests, want 1 and 1", s.numRateLimited,
+			s.numConcurrencyLimited)
+	}
+}
+
+// TestCheckAuthClientCert ensures clients which presented a verified
+// certificate are authenticated as the admin user unless they supply HTTP Basic
+// authentication, which is then checked as usual.
+func TestCheckAuthClientCert(t *testing.T) {
+	savedCfg, savedLog := cfg, rpcsLog
+	defer func() { cfg, rpcsLog = savedCfg, savedLog }()
+	cfg = &config{RPCClientCA: "ca.pem"}
+	rpcsLog = btclog.Disabled
+
+	limitAuth := "Basic " + base64.StdEncoding.EncodeToString(
+		[]byte("limited:secret"))
+	s := &rpcServer{limitauthsha: sha256.Sum256([]byte(limitAuth))}
+	verified := &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{}}},
+	}
+
+	tests := []struct {
+		name    string
+		tls     *tls.ConnectionState
+		auth    string
+		authed  bool
+		isAdmin bool
+	}{
+		{name: "no certificate", tls: &tls.ConnectionState{}},
+		{name: "verified certificate", tls: verified, authed: true,
+			isAdmin: true},
+		{name: "limited credentials", tls: verified, auth: limitAuth,
+			authed: true},
+		{name: "wrong credentials", tls: verified, auth: "Basic Og=="},
+	}
+	for _, test := range tests {
+		r := &http.Request{Header: make(http.Header), TLS: test.tls}
+		if test.auth != "" {
+			r.Header.Set("Authorization", test.auth)
+		}
+		authed, isAdmin, _ := s.checkAuth(r, true)
+		if authed != test.authed || isAdmin != test.isAdmin {
+			t.Errorf("%s: got authenticated %v and admin %v, want "+
+				"%v and %v", test.name, authed, isAdmin,
+				test.authed, test.isAdmin)
+		}
+	}
+}
+
+// templateTestTxSource is a mining.TxSource without transactions whose last
+// update time is set by the tests.  Generating a full block template can be
+// held up until the tests release it.
+type templateTestTxSource struct {
+	sync.Mutex
+	lastUpdated time.Time
+
+	// building is signalled, and release waited on, by every call to
+	// MiningDescs while they are set.
+	building chan struct{}
+	release  chan struct{}
+}
+
+// LastUpdated returns the last update time set by the test.
+func (s *templateTestTxSource) LastUpdated() time.Time {
+	s.Lock()
+	defer s.Unlock()
+	return s.lastUpdated
+}
+
+// SetLastUpdated sets the last update time of the source pool.
+func (s *templateTestTxSource) SetLastUpdated(lastUpdated time.Time) {
+	s.Lock()
+	s.lastUpdated = lastUpdated
+	s.Unlock()
+}
+
+// MiningDescs returns no transactions, after waiting for the test to release
+// it when it holds up the generation of full block templates.
+func (s *templateTestTxSource) MiningDescs() []*mining.TxDesc {
+	s.Lock()
+	building, release := s.building, s.release
+	s.Unlock()
+	if building != nil {
+		building <- struct{}{}
+		<-release
+	}
+	return nil
+}
+
+// HoldBuilds makes the generation of full block templates wait for the test to
+// release them when hold is set.  It returns the channels signalled when a
+// generation starts and used to release it.
+func (s *templateTestTxSource) HoldBuilds(hold bool) (chan struct{}, chan struct{}) {
+	s.Lock()
+	defer s.Unlock()
+	s.building, s.release = nil, nil
+	if hold {
+		s.building, s.release = make(chan struct{}), make(chan struct{})
+	}
+	return s.building, s.release
+}
+
+// HaveTransaction returns false since the source pool is always empty.
+func (s *templateTestTxSource) HaveTransaction(hash *chainhash.Hash) bool {
+	return false
+}
+
+// newTemplateTestServer returns an RPC server with a block template generator
+// on a new regression test chain along with the source of its transactions.
+func newTemplateTestServer(t *testing.T) (*rpcServer, *templateTestTxSource) {
+	t.Helper()
+
+	params := chaincfg.RegressionNetParams
+	db, err := database.Create("ffldb", t.TempDir(), params.Net)
+	if err != nil {
+		t.Fatalf("unable to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// The log rotator is not initialized by tests.
+	savedCfg, savedLog := cfg, rpcsLog
+	t.Cleanup(func() { cfg, rpcsLog = savedCfg, savedLog })
+	cfg = &config{}
+	rpcsLog = btclog.Disabled
+	blockchain.UseLogger(btclog.Disabled)
+	t.Cleanup(func() { blockchain.UseLogger(chanLog) })
+
+	timeSource := blockchain.NewMedianTime()
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		ChainParams: &params,
+		TimeSource:  timeSource,
+	})
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+
+	txSource := &templateTestTxSource{lastUpdated: time.Now()}
+	policy := mining.Policy{
+		BlockMaxWeight:       blockchain.MaxBlockWeight,
+		BlockMaxSize:         blockchain.MaxBlockBaseSize,
+		CoinbaseReservedSize: 0,
+	}
+	generator := mining.NewBlkTmplGenerator(&policy, &params, txSource,
+		chain, timeSource, txscript.NewSigCache(100),
+		txscript.NewScriptCache(100), txscript.NewHashCache(100))
+	s := &rpcServer{
+		cfg: rpcserverConfig{
+			ChainParams: &params,
+			Chain:       chain,
+			DB:          db,
+			TimeSource:  timeSource,
+			Generator:   generator,
+		},
+		gbtWorkState: newGbtWorkState(timeSource, &params),
+	}
+	return s, txSource
+}
+
+// extendTestChain solves and processes a block extending the best chain of the
+// passed server.
+func extendTestChain(t *testing.T, s *rpcServer) {
+	t.Helper()
+
+	best := s.cfg.Chain.BestSnapshot()
+	timestamp := best.MedianTime.Add(time.Minute)
+	block := solveTestBlock(t, &best.Hash, best.Height+1, timestamp,
+		[]byte{0x51})
+	_, _, err := s.cfg.Chain.ProcessBlock(block, blockchain.BFNone)
+	if err != nil {
+		t.Fatalf("ProcessBlock: unexpected error: %v", err)
+	}
+}
+
+// TestUpdateBlockTemplateRefresh ensures refreshing a block template with the
+// changes of the memory pool only notifies long polling clients at most once
+// per gbtRegenerateSeconds, and never moves the generation time of the
+// template ahead of the current time when nobody is long polling.
+func TestUpdateBlockTemplateRefresh(t *testing.T) {
+	s, txSource := newTemplateTestServer(t)
+	state := s.gbtWorkState
+	state.Lock()
+	defer state.Unlock()
+
+	if err := state.updateBlockTemplate(s, true); err != nil {
+		t.Fatalf("updateBlockTemplate: unexpected error: %v", err)
+	}
+	generated := state.lastGenerated
+	longPoll := state.templateUpdateChan(state.prevHash,
+		generated.Unix())
+
+	// A refresh within gbtRegenerateSeconds of the generation of the
+	// template updates it without notifying the long polling client.
+	lastUpdated := time.Now().Add(time.Second)
+	txSource.SetLastUpdated(lastUpdated)
+	if err := state.updateBlockTemplate(s, true); err != nil {
+		t.Fatalf("updateBlockTemplate: unexpected error: %v", err)
+	}
+	if !state.lastTxUpdate.Equal(lastUpdated) {
+		t.Fatalf("template not refreshed: last update %v, want %v",
+			state.lastTxUpdate, lastUpdated)
+	}
+	if !state.lastGenerated.Equal(generated) {
+		t.Fatalf("refresh moved generation time from %v to %v",
+			generated, state.lastGenerated)
+	}
+	select {
+	case <-longPoll:
+		t.Fatal("long polling client notified before " +
+			"gbtRegenerateSeconds passed")
+	default:
+	}
+
+	// A refresh once gbtRegenerateSeconds passed notifies it.
+	state.lastGenerated = generated.Add(-(gbtRegenerateSeconds + 1) *
+		time.Second)
+	longPoll = state.templateUpdateChan(state.prevHash,
+		state.lastGenerated.Unix())
+	lastUpdated = lastUpdated.Add(time.Second)
+	txSource.SetLastUpdated(lastUpdated)
+	if err := state.updateBlockTemplate(s, true); err != nil {
+		t.Fatalf("updateBlockTemplate: unexpected error: %v", err)
+	}
+	select {
+	case <-longPoll:
+	default:
+		t.Fatal("long polling client not notified of the refreshed " +
+			"template")
+	}
+	if state.lastGenerated.After(time.Now()) {
+		t.Fatalf("generation time %v is ahead of the current time",
+			state.lastGenerated)
+	}
+
+	// The generation time of a replacement template is only moved ahead
+	// of the current time to give it a different long poll ID when a
+	// client is long polling the current template.  The clients from
+	// above are removed by notifying them about a different best block.
+	state.notifyLongPollers(&chainhash.Hash{}, time.Time{})
+	state.lastGenerated = time.Now()
+	if next := state.nextGenerated(); next.After(time.Now()) {
+		t.Fatalf("next generation time %v is ahead of the current "+
+			"time without long polling clients", next)
+	}
+	state.templateUpdateChan(state.prevHash, state.lastGenerated.Unix())
+	if next := state.nextGenerated(); next.Unix() <= state.lastGenerated.Unix() {
+		t.Fatalf("next generation time %v does not change the long "+
+			"poll ID of %v", next, state.lastGenerated)
+	}
+}
+
+// waitLongPoll waits for the passed long poll channel to be closed, failing the
+// test when that doesn't happen in time.
+func waitLongPoll(t *testing.T, longPoll chan struct{}, what string) {
+	t.Helper()
+
+	select {
+	case <-longPoll:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("long polling client not notified of %s", what)
+	}
+}
+
+// TestFastBlockTemplate ensures an empty block template is served right away
+// when the best block changed with the fasttemplate option, and that the full
+// block template replaces it and is announced to long polling clients unless
+// it became stale while it was generated.
+func TestFastBlockTemplate(t *testing.T) {
+	s, txSource := newTemplateTestServer(t)
+	cfg.FastTemplate = true
+	state := s.gbtWorkState
+	extendTestChain(t, s)
+
+	// The empty template is served right away and the full template
+	// replaces it in the background, which notifies the long polling
+	// client of the empty template.
+	state.Lock()
+	if err := state.updateBlockTemplate(s, true); err != nil {
+		state.Unlock()
+		t.Fatalf("updateBlockTemplate: unexpected error: %v", err)
+	}
+	empty := state.template
+	best := s.cfg.Chain.BestSnapshot()
+	if len(empty.Block.Transactions) != 1 ||
+		!empty.Block.Header.PrevBlock.IsEqual(&best.Hash) {
+
+		state.Unlock()
+		t.Fatalf("unexpected empty template with %d transactions "+
+			"building on %v", len(empty.Block.Transactions),
+			empty.Block.Header.PrevBlock)
+	}
+	emptyGenerated := state.lastGenerated
+	longPoll := state.templateUpdateChan(state.prevHash,
+		emptyGenerated.Unix())
+	state.Unlock()
+
+	waitLongPoll(t, longPoll, "the full template")
+	state.Lock()
+	if state.template == empty || state.lastGenerated.Unix() <=
+		emptyGenerated.Unix() {
+
+		state.Unlock()
+		t.Fatal("full template did not replace the empty template " +
+			"with a new long poll ID")
+	}
+	state.Unlock()
+
+	// A full template is dropped when the empty template it completes
+	// was already replaced.
+	empty, err := s.cfg.Generator.NewEmptyBlockTemplate(nil)
+	if err != nil {
+		t.Fatalf("NewEmptyBlockTemplate: unexpected error: %v", err)
+	}
+	state.Lock()
+	replaced := state.template
+	state.Unlock()
+	state.completeEmptyTemplate(s, empty, nil)
+	state.Lock()
+	if state.template != replaced {
+		state.Unlock()
+		t.Fatal("full template of a replaced empty template was not " +
+			"dropped")
+	}
+
+	// A full template is also dropped when the best block changes while
+	// it is generated, since the generator rejects a template building on
+	// the previous best block.
+	state.template = empty
+	state.Unlock()
+	building, release := txSource.HoldBuilds(true)
+	done := make(chan struct{})
+	go func() {
+		state.completeEmptyTemplate(s, empty, nil)
+		close(done)
+	}()
+	<-building
+	extendTestChain(t, s)
+	release <- struct{}{}
+	<-done
+	txSource.HoldBuilds(false)
+	state.Lock()
+	if state.template != empty {
+		state.Unlock()
+		t.Fatal("full template building on the previous best block " +
+			"was not dropped")
+	}
+	state.Unlock()
+}
+
+// TestFastBlockTemplateTxUpdate ensures memory pool changes made while the full
+// block template is generated are picked up by the next request, which
+// refreshes the full template.
+func TestFastBlockTemplateTxUpdate(t *testing.T) {
+	s, txSource := newTemplateTestServer(t)
+	cfg.FastTemplate = true
+	state := s.gbtWorkState
+	extendTestChain(t, s)
+
+	building, release := txSource.HoldBuilds(true)
+	state.Lock()
+	if err := state.updateBlockTemplate(s, true); err != nil {
+		state.Unlock()
+		t.Fatalf("updateBlockTemplate: unexpected error: %v", err)
+	}
+	emptyTxUpdate := state.lastTxUpdate
+	longPoll := state.templateUpdateChan(state.prevHash,
+		state.lastGenerated.Unix())
+	state.Unlock()
+
+	// Change the memory pool after the full template selected its
+	// transactions.
+	<-building
+	lastUpdated := emptyTxUpdate.Add(time.Second)
+	txSource.SetLastUpdated(lastUpdated)
+	release <- struct{}{}
+	waitLongPoll(t, longPoll, "the full template")
+	txSource.HoldBuilds(false)
+
+	// The full template doesn't claim the change, so the next request
+	// refreshes it instead of serving it as is.
+	state.Lock()
+	defer state.Unlock()
+	if !state.lastTxUpdate.Equal(emptyTxUpdate) {
+		t.Fatalf("full template claims memory pool update %v, want %v",
+			state.lastTxUpdate, emptyTxUpdate)
+	}
+	full := state.template
+	if err := state.updateBlockTemplate(s, true); err != nil {
+		t.Fatalf("updateBlockTemplate: unexpected error: %v", err)
+	}
+	if state.template == full || !state.lastTxUpdate.Equal(lastUpdated) {
+		t.Fatalf("full template not refreshed with memory pool "+
+			"update %v", lastUpdated)
+	}
+}
+
+// policyTestConnManager is a connection manager which only keeps track of the
+// maximum number of peers for testing the setpolicy command.
+type policyTestConnManager struct {
+	rpcserverConnManager
+	maxPeers int
+}
+
+// MaxPeers returns the maximum number of peers.
+func (cm *policyTestConnManager) MaxPeers() int {
+	return cm.maxPeers
+}
+
+// SetMaxPeers changes the maximum number of peers.
+func (cm *policyTestConnManager) SetMaxPeers(maxPeers int) {
+	cm.maxPeers = maxPeers
+}
+
+// TestHandleSetPolicy ensures the setpolicy command changes the policies of
+// the memory pool, the block template generator and the connection manager,
+// and leaves all of them unchanged when any setting is invalid.
+func TestHandleSetPolicy(t *testing.T) {
+	s, _ := newTemplateTestServer(t)
+	connMgr := &policyTestConnManager{maxPeers: 125}
+	s.cfg.ConnMgr = connMgr
+	s.cfg.TxMemPool = mempool.New(&mempool.Config{
+		Policy: mempool.Policy{
+			MaxOrphanTxs:  100,
+			MinRelayTxFee: 10000,
+		},
+	})
+
+	res, err := handleSetPolicy(s, &btcjson.SetPolicyCmd{
+		Options: &btcjson.SetPolicyOpts{
+			MinRelayTxFee: btcjson.Float64(0.0002),
+			MaxPeers:      btcjson.Int(4),
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("setpolicy: unexpected error: %v", err)
+	}
+	result := res.(*btcjson.SetPolicyResult)
+	if result.MinRelayTxFee != 0.0002 || result.MaxOrphanTxs != 100 ||
+		result.MaxPeers != 4 {
+
+		t.Fatalf("setpolicy: unexpected result %+v", result)
+	}
+	if fee := s.cfg.TxMemPool.Policy().MinRelayTxFee; fee != 20000 {
+		t.Fatalf("mempool minimum relay fee is %v, want 20000", fee)
+	}
+	if fee := s.cfg.Generator.Policy().TxMinFreeFee; fee != 20000 {
+		t.Fatalf("mining minimum fee is %v, want 20000", fee)
+	}
+	if connMgr.maxPeers != 4 {
+		t.Fatalf("max peers is %d, want 4", connMgr.maxPeers)
+	}
+
+	// None of the settings change when one of them is invalid.
+	_, err = handleSetPolicy(s, &btcjson.SetPolicyCmd{
+		Options: &btcjson.SetPolicyOpts{
+			MinRelayTxFee: btcjson.Float64(0.0003),
+			MaxPeers:      btcjson.Int(-1),
+		},
+	}, nil)
+	if err == nil {
+		t.Fatal("setpolicy: expected an error for negative maxpeers")
+	}
+	if fee := s.cfg.Generator.Policy().TxMinFreeFee; fee != 20000 {
+		t.Fatalf("mining minimum fee changed to %v by an invalid "+
+			"setting", fee)
+	}
+}
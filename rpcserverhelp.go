@@ -21,7 +21,7 @@ var helpDescsEnUS = map[string]string{
 		"The levelspec can either a debug level or of the form:\n" +
 		"<subsystem>=<level>,<subsystem2>=<level2>,...\n" +
 		"The valid debug levels are trace, debug, info, warn, error, and critical.\n" +
-		"The valid subsystems are AMGR, ADXR, BCDB, BMGR, LTCD, CHAN, DISC, PEER, RPCS, SCRP, SRVR, and TXMP.\n" +
+		"The valid subsystems are AMGR, ADXR, BCDB, BMGR, LTCD, CHAN, DISC, MISB, PEER, RPCS, SCRP, SRVR, and TXMP.\n" +
 		"Finally the keyword 'show' will return a list of the available subsystems.",
 	"debuglevel-levelspec":   "The debug level(s) to use or the keyword 'show'",
 	"debuglevel--condition0": "levelspec!=show",
@@ -53,7 +53,7 @@ var helpDescsEnUS = map[string]string{
 	"createrawtransaction-amounts--key":   "address",
 	"createrawtransaction-amounts--value": "n.nnn",
 	"createrawtransaction-amounts--desc":  "The destination address as the key and the amount in LTC as the value",
-	"createrawtransaction-locktime":       "Locktime value; a non-zero value will also locktime-activate the inputs",
+	"createrawtransaction-locktime":       "Locktime value; a non-zero value will also locktime-activate the inputs (defaults to the current height, occasionally less, to discourage fee sniping)",
 	"createrawtransaction--result0":       "Hex-encoded bytes of the serialized transaction",
 
 	// ScriptSig help.
@@ -105,6 +105,82 @@ var helpDescsEnUS = map[string]string{
 	"decoderawtransaction--synopsis": "Returns a JSON object representing the provided serialized, hex-encoded transaction.",
 	"decoderawtransaction-hextx":     "Serialized, hex-encoded transaction",
 
+	// DecodePsbtInput help.
+	"decodepsbtinput-non_witness_utxo":          "The full transaction spent by a non-witness input",
+	"decodepsbtinput-witness_utxo":              "The transaction output spent by a witness input",
+	"decodepsbtinput-partial_signatures":        "The partial signatures of the input",
+	"decodepsbtinput-partial_signatures--key":   "pubkey",
+	"decodepsbtinput-partial_signatures--value": "signature",
+	"decodepsbtinput-partial_signatures--desc":  "The hex-encoded signature of each hex-encoded public key",
+	"decodepsbtinput-sighash":                   "The signature hash type to sign the input with",
+	"decodepsbtinput-redeem_script":             "The redeem script of the input",
+	"decodepsbtinput-witness_script":            "The witness script of the input",
+	"decodepsbtinput-final_scriptSig":           "The final signature script of the input",
+	"decodepsbtinput-final_scriptwitness":       "The final witness of the input encoded as a string array of its items",
+	"decodepsbtinput-mweb_output_id":            "The ID of the MWEB output spent by the input (MWEB inputs only)",
+	"decodepsbtinput-mweb_amount":               "The amount of the MWEB output spent by the input in LTC (MWEB inputs only)",
+	"decodepsbtinput-unknown":                   "The unknown fields of the input",
+	"decodepsbtinput-unknown--key":              "key",
+	"decodepsbtinput-unknown--value":            "value",
+	"decodepsbtinput-unknown--desc":             "The hex-encoded value of each hex-encoded key",
+
+	// DecodePsbtOutput help.
+	"decodepsbtoutput-amount":             "The amount of the output in LTC",
+	"decodepsbtoutput-scriptPubKey":       "The public key script paid by the output (transparent outputs only)",
+	"decodepsbtoutput-redeem_script":      "The redeem script of the output",
+	"decodepsbtoutput-witness_script":     "The witness script of the output",
+	"decodepsbtoutput-stealth_address":    "The MWEB stealth address paid by the output (MWEB outputs only)",
+	"decodepsbtoutput-mweb_pegout_kernel": "The index of the MWEB kernel pegging out the output (peg-out outputs only)",
+	"decodepsbtoutput-unknown":            "The unknown fields of the output",
+	"decodepsbtoutput-unknown--key":       "key",
+	"decodepsbtoutput-unknown--value":     "value",
+	"decodepsbtoutput-unknown--desc":      "The hex-encoded value of each hex-encoded key",
+
+	// DecodePsbtKernel help.
+	"decodepsbtkernel-fee":          "The fee of the kernel in LTC",
+	"decodepsbtkernel-pegin_amount": "The amount pegged into the MWEB by the kernel in LTC",
+	"decodepsbtkernel-pegouts":      "The outputs pegged out of the MWEB by the kernel",
+	"decodepsbtkernel-lock_height":  "The height the kernel is locked until",
+	"decodepsbtkernel-signed":       "Whether the kernel is signed",
+
+	// DecodePsbtResult help.
+	"decodepsbtresult-tx":             "The unsigned transaction of the PSBT",
+	"decodepsbtresult-psbt_version":   "The version of the PSBT",
+	"decodepsbtresult-unknown":        "The unknown global fields of the PSBT",
+	"decodepsbtresult-unknown--key":   "key",
+	"decodepsbtresult-unknown--value": "value",
+	"decodepsbtresult-unknown--desc":  "The hex-encoded value of each hex-encoded key",
+	"decodepsbtresult-inputs":         "The inputs of the PSBT",
+	"decodepsbtresult-outputs":        "The outputs of the PSBT",
+	"decodepsbtresult-kernels":        "The MWEB kernels of the PSBT",
+	"decodepsbtresult-fee":            "The fee paid by the transaction in LTC (only if the amounts of all inputs are known)",
+
+	// DecodePsbtCmd help.
+	"decodepsbt--synopsis": "Returns a JSON object representing the provided base64-encoded partially signed transaction.",
+	"decodepsbt-psbt":      "The base64-encoded PSBT",
+
+	// CombinePsbtCmd help.
+	"combinepsbt--synopsis": "Combines multiple partially signed transactions of the same transaction into one.",
+	"combinepsbt-psbts":     "The base64-encoded PSBTs to combine",
+	"combinepsbt--result0":  "The base64-encoded combined PSBT",
+
+	// ConvertToPsbtCmd help.
+	"converttopsbt--synopsis":     "Converts a serialized, hex-encoded transaction into an unsigned PSBT.",
+	"converttopsbt-hextx":         "Serialized, hex-encoded transaction",
+	"converttopsbt-permitsigdata": "Discard the signature scripts and witnesses of the inputs instead of failing when they are present",
+	"converttopsbt-iswitness":     "Whether the transaction is serialized with witnesses (defaults to trying the witness serialization)",
+	"converttopsbt--result0":      "The base64-encoded PSBT",
+
+	// FinalizePsbtResult help.
+	"finalizepsbtresult-psbt":     "The base64-encoded PSBT (only if the transaction was not extracted)",
+	"finalizepsbtresult-hex":      "The serialized, hex-encoded transaction (only if the PSBT is complete and extraction was requested)",
+	"finalizepsbtresult-complete": "Whether all inputs of the PSBT are finalized",
+
+	// FinalizePsbtCmd help.
+	"finalizepsbt--synopsis": "Finalizes the inputs of a partially signed transaction and extracts the network serialized transaction once it is complete.",
+	"finalizepsbt-psbt":      "The base64-encoded PSBT",
+	"finalizepsbt-extract":   "Extract the transaction when the PSBT is complete",
+
 	// DecodeScriptResult help.
 	"decodescriptresult-asm":       "Disassembly of the script",
 	"decodescriptresult-reqSigs":   "(DEPRECATED) The number of required signatures",
@@ -117,6 +193,15 @@ var helpDescsEnUS = map[string]string{
 	"decodescript--synopsis": "Returns a JSON object with information about the provided hex-encoded script.",
 	"decodescript-hexscript": "Hex-encoded script",
 
+	// DeriveAddressesCmd help.
+	"deriveaddresses--synopsis":  "Derives one or more addresses corresponding to an output descriptor.",
+	"deriveaddresses-descriptor": "The descriptor, which must include a checksum",
+	"deriveaddresses-range":      "The end or [begin,end] of the range to derive (only for ranged descriptors)",
+	"deriveaddresses--result0":   "The derived addresses",
+
+	// DescriptorRange help.
+	"descriptorrange-value": "The end of the range as an integer, or the [begin,end] of the range as an array",
+
 	// EstimateFeeCmd help.
 	"estimatefee--synopsis": "Estimate the fee per kilobyte in satoshis " +
 		"required for a transaction to be mined before a certain number of " +
@@ -126,6 +211,42 @@ var helpDescsEnUS = map[string]string{
 	"estimatefee--result0": "Estimated fee per kilobyte in satoshis for a block to " +
 		"be mined in the next NumBlocks blocks.",
 
+	// FundRawTransactionOpts help.
+	"fundrawtransactionopts-changeAddress":          "The address to send the change to",
+	"fundrawtransactionopts-changePosition":         "The index of the change output (random if omitted)",
+	"fundrawtransactionopts-change_type":            "Unused since changeAddress must be provided",
+	"fundrawtransactionopts-includeWatching":        "Unused since only the outputs of the watched addresses are considered",
+	"fundrawtransactionopts-lockUnspents":           "Not supported",
+	"fundrawtransactionopts-feeRate":                "The fee rate to pay in LTC/KB (defaults to the estimated fee rate or the minimum relay fee)",
+	"fundrawtransactionopts-subtractFeeFromOutputs": "The indexes of the outputs the fee is deducted from, split equally",
+	"fundrawtransactionopts-replaceable":            "Signal BIP 125 replaceability on the added inputs",
+	"fundrawtransactionopts-conf_target":            "The confirmation target used to estimate the fee rate when feeRate is omitted",
+	"fundrawtransactionopts-estimate_mode":          "Unused",
+	"fundrawtransactionopts-locktime":               "The locktime of the transaction (defaults to the locktime of the transaction or, when zero, the current height to discourage fee sniping)",
+	"fundrawtransactionopts-addresses":              "The watched addresses whose unspent outputs may fund the transaction",
+
+	// FundRawTransactionResult help.
+	"fundrawtransactionresult-hex":       "The funded, unsigned, hex-encoded transaction",
+	"fundrawtransactionresult-fee":       "The fee paid by the transaction in LTC",
+	"fundrawtransactionresult-changepos": "The index of the change output, or -1 when no change output was added",
+
+	// FundRawTransactionCmd help.
+	"fundrawtransaction--synopsis": "Adds inputs spending the unspent outputs of a set of watched addresses to a transaction until it pays for its outputs and fee, adding a change output when the leftover value is not dust.\n" +
+		"Outputs are found using the address index.  The transaction is not signed and the inputs are not locked.",
+	"fundrawtransaction-hextx":     "Serialized, hex-encoded transaction",
+	"fundrawtransaction-options":   "Funding options",
+	"fundrawtransaction-iswitness": "Whether the transaction is serialized with witness data (tried both ways when omitted)",
+
+	// GenerateBlockCmd help
+	"generateblock--synopsis": "Generates a block containing exactly the given transactions (simnet or regtest only) and returns its hash.\n" +
+		"Unlike generate, no transactions are taken from the memory pool and the block timestamp may be chosen.",
+	"generateblock-output":       "The address the coinbase of the block pays to",
+	"generateblock-transactions": "The transactions to include in order, each either the txid of a memory pool transaction or a hex-encoded raw transaction",
+	"generateblock-timestamp":    "The block timestamp in seconds since 1 Jan 1970 GMT, which must be after the median time of the last several blocks (default: the current adjusted time)",
+
+	// GenerateBlockResult help
+	"generateblockresult-hash": "The hash of the generated block",
+
 	// GenerateCmd help
 	"generate--synopsis": "Generates a set number of blocks (simnet or regtest only) and returns a JSON\n" +
 		" array of their hashes.",
@@ -149,6 +270,37 @@ var helpDescsEnUS = map[string]string{
 	"getaddednodeinfo--condition1": "dns=true",
 	"getaddednodeinfo--result0":    "List of added peers",
 
+	// AddrManNetworkResult help.
+	"addrmannetworkresult-new":   "The number of addresses in the new table, which have not been connected to successfully",
+	"addrmannetworkresult-tried": "The number of addresses in the tried table, which have been connected to successfully",
+	"addrmannetworkresult-total": "The total number of addresses in both tables",
+
+	// AddrManAddressResult help.
+	"addrmanaddressresult-address":     "The address and port of the node",
+	"addrmanaddressresult-network":     "The network of the address (ipv4, ipv6 or onion)",
+	"addrmanaddressresult-services":    "The services offered by the node",
+	"addrmanaddressresult-time":        "Timestamp in seconds since epoch (Jan 1 1970 GMT) of when the node was last seen",
+	"addrmanaddressresult-source":      "The address of the peer the address was learned from",
+	"addrmanaddressresult-tried":       "Whether the address is in the tried table",
+	"addrmanaddressresult-buckets":     "The buckets of the table the address is in",
+	"addrmanaddressresult-attempts":    "The number of connection attempts since the last successful connection",
+	"addrmanaddressresult-lastattempt": "Timestamp in seconds since epoch (Jan 1 1970 GMT) of the last connection attempt, or 0 when never attempted",
+	"addrmanaddressresult-lastsuccess": "Timestamp in seconds since epoch (Jan 1 1970 GMT) of the last successful connection, or 0 when never connected",
+
+	// GetAddrManInfoResult help.
+	"getaddrmaninforesult-networks":         "The number of addresses of each network, along with all_networks for the sum over all networks",
+	"getaddrmaninforesult-networks--key":    "network",
+	"getaddrmaninforesult-networks--value":  "{\"new\": n, \"tried\": n, \"total\": n}",
+	"getaddrmaninforesult-networks--desc":   "The number of addresses of the network (ipv4, ipv6, onion, i2p or all_networks)",
+	"getaddrmaninforesult-terrible":         "The estimated number of addresses which are considered terrible and are the first to be evicted",
+	"getaddrmaninforesult-newbucketsused":   "The number of buckets of the new table holding at least one address",
+	"getaddrmaninforesult-triedbucketsused": "The number of buckets of the tried table holding at least one address",
+	"getaddrmaninforesult-sample":           "The randomly sampled addresses, only present when a sample was requested",
+
+	// GetAddrManInfoCmd help.
+	"getaddrmaninfo--synopsis": "Returns statistics about the addresses known to the address manager, per network and table, to help verify address diversity.",
+	"getaddrmaninfo-sample":    "The number of known addresses to return picked at random",
+
 	// GetBestBlockResult help.
 	"getbestblockresult-hash":   "Hex-encoded bytes of the best block hash",
 	"getbestblockresult-height": "Height of the best block",
@@ -185,6 +337,7 @@ var helpDescsEnUS = map[string]string{
 	"getblockchaininforesult-chainwork":            "The total cumulative work in the best chain",
 	"getblockchaininforesult-size_on_disk":         "The estimated size of the block and undo files on disk",
 	"getblockchaininforesult-initialblockdownload": "Estimate of whether this node is in Initial Block Download mode",
+	"getblockchaininforesult-warnings":             "Any warnings about conditions which might prevent the node from working properly",
 	"getblockchaininforesult-softforks":            "The status of the super-majority soft-forks",
 	"getblockchaininforesult-unifiedsoftforks":     "The status of the super-majority soft-forks used by litecoind on or after v0.19.0",
 
@@ -208,20 +361,21 @@ var helpDescsEnUS = map[string]string{
 	"unifiedsoftforks-softforks--desc":  "JSON object describing an active softfork deployment used by litecoind on or after v0.19.0",
 
 	// TxRawResult help.
-	"txrawresult-hex":           "Hex-encoded transaction",
-	"txrawresult-txid":          "The hash of the transaction",
-	"txrawresult-version":       "The transaction version",
-	"txrawresult-locktime":      "The transaction lock time",
-	"txrawresult-vin":           "The transaction inputs as JSON objects",
-	"txrawresult-vout":          "The transaction outputs as JSON objects",
-	"txrawresult-blockhash":     "Hash of the block the transaction is part of",
-	"txrawresult-confirmations": "Number of confirmations of the block",
-	"txrawresult-time":          "Transaction time in seconds since 1 Jan 1970 GMT",
-	"txrawresult-blocktime":     "Block time in seconds since the 1 Jan 1970 GMT",
-	"txrawresult-size":          "The size of the transaction in bytes",
-	"txrawresult-vsize":         "The virtual size of the transaction in bytes",
-	"txrawresult-weight":        "The transaction's weight (between vsize*4-3 and vsize*4)",
-	"txrawresult-hash":          "The wtxid of the transaction",
+	"txrawresult-hex":             "Hex-encoded transaction",
+	"txrawresult-txid":            "The hash of the transaction",
+	"txrawresult-version":         "The transaction version",
+	"txrawresult-locktime":        "The transaction lock time",
+	"txrawresult-vin":             "The transaction inputs as JSON objects",
+	"txrawresult-vout":            "The transaction outputs as JSON objects",
+	"txrawresult-blockhash":       "Hash of the block the transaction is part of",
+	"txrawresult-confirmations":   "Number of confirmations of the block",
+	"txrawresult-time":            "Transaction time in seconds since 1 Jan 1970 GMT",
+	"txrawresult-blocktime":       "Block time in seconds since the 1 Jan 1970 GMT",
+	"txrawresult-size":            "The size of the transaction in bytes",
+	"txrawresult-vsize":           "The virtual size of the transaction in bytes",
+	"txrawresult-weight":          "The transaction's weight (between vsize*4-3 and vsize*4)",
+	"txrawresult-hash":            "The wtxid of the transaction",
+	"txrawresult-in_active_chain": "Whether the block the transaction was looked up in is part of the main chain (only set when a block hash is provided)",
 
 	// SearchRawTransactionsResult help.
 	"searchrawtransactionsresult-hex":           "Hex-encoded transaction",
@@ -262,6 +416,12 @@ var helpDescsEnUS = map[string]string{
 	"getblockcount--synopsis": "Returns the number of blocks in the longest block chain.",
 	"getblockcount--result0":  "The current block count",
 
+	// GetBlockFromPeerCmd help.
+	"getblockfrompeer--synopsis": "Requests a block from the peer with the given ID and stores it once received, even when it is on a side chain, as long as its parent is known.\n" +
+		"The peer IDs are reported by getpeerinfo. The command returns once the request is sent, use getblock to check whether the block was received.",
+	"getblockfrompeer-blockhash": "The hash of the block to request",
+	"getblockfrompeer-peerid":    "The ID of the peer to request the block from",
+
 	// GetBlockHashCmd help.
 	"getblockhash--synopsis": "Returns hash of the block in best block chain at the given height.",
 	"getblockhash-index":     "The block height",
@@ -289,6 +449,25 @@ var helpDescsEnUS = map[string]string{
 	"getblockheaderverboseresult-previousblockhash": "The hash of the previous block",
 	"getblockheaderverboseresult-nextblockhash":     "The hash of the next block (only if there is one)",
 
+	// GetChainTipsCmd help.
+	"getchaintips--synopsis": "Returns the tips of all known branches of the block tree, including the main chain and any side chains.\n" +
+		"The tip of the main chain is listed first followed by the side chain tips in descending height order.",
+	"getchaintips-verbose":     "Specifies the tips are returned along with statistics about the removal of stale side chains",
+	"getchaintips--condition0": "verbose=false",
+	"getchaintips--condition1": "verbose=true",
+
+	// GetChainTipsResult help.
+	"getchaintipsresult-height":    "The height of the tip",
+	"getchaintipsresult-hash":      "The hash of the tip",
+	"getchaintipsresult-branchlen": "The number of blocks between the tip and the main chain (0 for the main chain)",
+	"getchaintipsresult-status":    "The status of the branch ending with the tip: 'active' for the main chain, 'valid-fork' for a fully validated side chain, 'valid-headers' for a side chain whose blocks are stored but not fully validated, 'headers-only' for a side chain whose blocks are not stored, or 'invalid' for a side chain containing an invalid block",
+
+	// GetChainTipsVerboseResult help.
+	"getchaintipsverboseresult-tips":              "The tips of all known branches of the block tree",
+	"getchaintipsverboseresult-staleforkdepth":    "The number of blocks a side chain tip must be behind the main chain for the side chain to be removed (0 when side chains are kept)",
+	"getchaintipsverboseresult-prunedstaleblocks": "The number of stale side chain blocks removed from the block index since the server started",
+	"getchaintipsverboseresult-lastpruneheight":   "The height of the main chain when stale side chains were last searched for (0 if they never were)",
+
 	// TemplateRequest help.
 	"templaterequest-mode":         "This is 'template', 'proposal', or omitted",
 	"templaterequest-capabilities": "List of capabilities",
@@ -338,8 +517,21 @@ var helpDescsEnUS = map[string]string{
 	"getblocktemplateresult-capabilities":               "List of server capabilities including 'proposal' to indicate support for block proposals",
 	"getblocktemplateresult-reject-reason":              "Reason the proposal was invalid as-is (only applies to proposal responses)",
 	"getblocktemplateresult-default_witness_commitment": "The witness commitment itself. Will be populated if the block has witness data",
+	"getblocktemplateresult-signet_challenge":           "The hex-encoded challenge the block must solve on signet networks",
 	"getblocktemplateresult-weightlimit":                "The current limit on the max allowed weight of a block",
 
+	"getblocktemplateresult-coinbase_reserved_size":       "The number of bytes reserved in the coinbase signature script for the extra nonces of mining pools (only with coinbasetxn)",
+	"getblocktemplateresult-coinbase_reserved_offset":     "The offset of the first reserved byte within the coinbase signature script",
+	"getblocktemplateresult-coinbase_reserved_dataoffset": "The offset in bytes of the first reserved byte within the serialized coinbase transaction of coinbasetxn",
+
+	// GetBlockSubsidyCmd help.
+	"getblocksubsidy--synopsis": "Returns the subsidy paid by the coinbase of the block at the given height, excluding transaction fees.",
+	"getblocksubsidy-height":    "The block height (default: the height of the next block)",
+
+	// GetBlockSubsidyResult help.
+	"getblocksubsidyresult-height":  "The height of the block",
+	"getblocksubsidyresult-subsidy": "The block subsidy in LTC",
+
 	// GetBlockTemplateCmd help.
 	"getblocktemplate--synopsis": "Returns a JSON object with information necessary to construct a block to mine or accepts a proposal to validate.\n" +
 		"See BIP0022 and BIP0023 for the full specification.",
@@ -365,13 +557,112 @@ var helpDescsEnUS = map[string]string{
 	"getconnectioncount--synopsis": "Returns the number of active connections to other peers.",
 	"getconnectioncount--result0":  "The number of connections",
 
+	// GetChainParamsCmd help.
+	"getchainparams--synopsis": "Returns the parameters of the network the server is running on so clients can configure themselves for it.",
+
+	// GetChainParamsResult help.
+	"getchainparamsresult-name":                          "The name of the network",
+	"getchainparamsresult-net":                           "The magic bytes which identify the network as an integer",
+	"getchainparamsresult-defaultport":                   "The default peer-to-peer port of the network",
+	"getchainparamsresult-dnsseeds":                      "The DNS seeds used to find peers",
+	"getchainparamsresult-genesishash":                   "The hash of the genesis block",
+	"getchainparamsresult-powlimit":                      "The highest allowed proof of work target as a hex-encoded 256-bit value",
+	"getchainparamsresult-powlimitbits":                  "The highest allowed proof of work target in compact form",
+	"getchainparamsresult-pownoretargeting":              "Whether or not the difficulty is never retargeted",
+	"getchainparamsresult-bip34height":                   "The height at which BIP0034 became active",
+	"getchainparamsresult-bip65height":                   "The height at which BIP0065 became active",
+	"getchainparamsresult-bip66height":                   "The height at which BIP0066 became active",
+	"getchainparamsresult-allowlegacyblockversions":      "Whether blocks with versions below the ones required by BIP0034, BIP0065 and BIP0066 are accepted after their activation",
+	"getchainparamsresult-enforcestrictder":              "Whether strict DER signatures are required in every block regardless of its version and height",
+	"getchainparamsresult-allownonstrictder":             "Whether signatures which are not strict DER are accepted in every block regardless of its version and height",
+	"getchainparamsresult-coinbasematurity":              "The number of blocks before a coinbase output can be spent",
+	"getchainparamsresult-mwebpegoutmaturity":            "The number of blocks before an MWEB peg-out output can be spent",
+	"getchainparamsresult-subsidyreductioninterval":      "The number of blocks between block subsidy reductions",
+	"getchainparamsresult-targettimespan":                "The desired time in seconds between difficulty retargets",
+	"getchainparamsresult-targettimeperblock":            "The desired time in seconds between blocks",
+	"getchainparamsresult-retargetadjustmentfactor":      "The maximum factor by which the difficulty can change in a single retarget",
+	"getchainparamsresult-reducemindifficulty":           "Whether or not the minimum difficulty is allowed after a delay",
+	"getchainparamsresult-mindiffreductiontime":          "The time in seconds after which a minimum difficulty block is allowed",
+	"getchainparamsresult-lwmaheight":                    "The height at which the LWMA difficulty algorithm activates",
+	"getchainparamsresult-lwmafixheight":                 "The height at which the LWMA difficulty algorithm fix activates",
+	"getchainparamsresult-lwmawindow":                    "The number of blocks averaged by the LWMA difficulty algorithm",
+	"getchainparamsresult-asertheight":                   "The height at which the ASERT difficulty algorithm activates",
+	"getchainparamsresult-aserthalflife":                 "The half-life in seconds of the ASERT difficulty algorithm",
+	"getchainparamsresult-asertanchorbits":               "The compact target of the ASERT anchor block",
+	"getchainparamsresult-minimumchainwork":              "The minimum cumulative work of the best chain as a hex-encoded 256-bit value",
+	"getchainparamsresult-signetchallenge":               "The hex-encoded challenge blocks must solve on signet networks",
+	"getchainparamsresult-checkpoints":                   "The checkpoints of the network",
+	"getchainparamsresult-advisorycheckpoints":           "Whether blocks which do not match the checkpoints are accepted rather than rejected",
+	"getchainparamsresult-rulechangeactivationthreshold": "The number of blocks in a window which must signal for a deployment to lock in",
+	"getchainparamsresult-minerconfirmationwindow":       "The number of blocks in each deployment signalling window",
+	"getchainparamsresult-deployments":                   "The BIP0009 soft-fork deployments of the network",
+	"getchainparamsresult-relaynonstdtxs":                "Whether or not non-standard transactions are relayed by default",
+	"getchainparamsresult-bech32hrpsegwit":               "The human-readable part of segwit addresses",
+	"getchainparamsresult-bech32hrpmweb":                 "The human-readable part of MWEB addresses",
+	"getchainparamsresult-pubkeyhashaddrid":              "The first byte of pay-to-pubkey-hash addresses",
+	"getchainparamsresult-scripthashaddrid":              "The first byte of pay-to-script-hash addresses",
+	"getchainparamsresult-privatekeyid":                  "The first byte of WIF private keys",
+	"getchainparamsresult-witnesspubkeyhashaddrid":       "The first byte of base58 pay-to-witness-pubkey-hash addresses",
+	"getchainparamsresult-witnessscripthashaddrid":       "The first byte of base58 pay-to-witness-script-hash addresses",
+	"getchainparamsresult-hdprivatekeyid":                "The hex-encoded version bytes of extended private keys",
+	"getchainparamsresult-hdpublickeyid":                 "The hex-encoded version bytes of extended public keys",
+	"getchainparamsresult-hdcointype":                    "The BIP0044 coin type of the network",
+
+	// ChainParamsCheckpoint help.
+	"chainparamscheckpoint-height": "The height of the checkpointed block",
+	"chainparamscheckpoint-hash":   "The hash of the checkpointed block",
+
+	// ChainParamsDeployment help.
+	"chainparamsdeployment-name":                "The name of the deployment",
+	"chainparamsdeployment-bit":                 "The block version bit used to signal the deployment",
+	"chainparamsdeployment-starttime":           "The median time at which signalling starts or 0 when it does not start at a median time",
+	"chainparamsdeployment-timeout":             "The median time at which the deployment times out or 0 when it does not time out at a median time",
+	"chainparamsdeployment-minactivationheight": "The minimum height at which the deployment can activate",
+	"chainparamsdeployment-threshold":           "The number of blocks in a window which must signal for the deployment to lock in",
+
 	// GetCurrentNetCmd help.
 	"getcurrentnet--synopsis": "Get litecoin network the server is running on.",
 	"getcurrentnet--result0":  "The network identifer",
 
+	// GetDescriptorInfoResult help.
+	"getdescriptorinforesult-descriptor":     "The descriptor in canonical form, without private keys",
+	"getdescriptorinforesult-checksum":       "The checksum for the input descriptor",
+	"getdescriptorinforesult-isrange":        "Whether the descriptor is ranged",
+	"getdescriptorinforesult-issolvable":     "Whether the descriptor is solvable",
+	"getdescriptorinforesult-hasprivatekeys": "Whether the input descriptor contained at least one private key",
+
+	// GetDescriptorInfoCmd help.
+	"getdescriptorinfo--synopsis":  "Analyses an output descriptor.",
+	"getdescriptorinfo-descriptor": "The descriptor",
+
 	// GetDifficultyCmd help.
-	"getdifficulty--synopsis": "Returns the proof-of-work difficulty as a multiple of the minimum difficulty.",
-	"getdifficulty--result0":  "The difficulty",
+	"getdifficulty--synopsis":   "Returns the proof-of-work difficulty as a multiple of the minimum difficulty, which is the compact form of the proof-of-work limit of the network as with the difficulty reported by the other commands.",
+	"getdifficulty-verbose":     "Specifies the difficulty is returned along with the difficulty algorithm of the best block",
+	"getdifficulty--condition0": "verbose=false",
+	"getdifficulty--condition1": "verbose=true",
+	"getdifficulty--result0":    "The difficulty",
+
+	// GetDifficultyResult help.
+	"getdifficultyresult-difficulty": "The proof-of-work difficulty as a multiple of the minimum difficulty",
+	"getdifficultyresult-algo":       "The difficulty algorithm which determined the difficulty of the best block (none, retarget, lwma, lwmav2 or asert)",
+	"getdifficultyresult-height":     "The height of the best block",
+	"getdifficultyresult-bits":       "The difficulty bits of the best block",
+	"getdifficultyresult-powlimit":   "The proof-of-work limit of the network, whose compact form the difficulty is relative to",
+
+	// GetDifficultyHistoryCmd help.
+	"getdifficultyhistory--synopsis":   "Returns the proof of work of a range of blocks in the main chain, read from the block index without loading the headers.",
+	"getdifficultyhistory-startheight": "The height of the first block",
+	"getdifficultyhistory-endheight":   "The height of the last block, which defaults to and is limited to the best height",
+
+	// GetDifficultyHistoryResult help.
+	"getdifficultyhistoryresult-height":     "The height of the block",
+	"getdifficultyhistoryresult-hash":       "The hash of the block",
+	"getdifficultyhistoryresult-bits":       "The difficulty bits of the block",
+	"getdifficultyhistoryresult-target":     "The target the hash of the block must not exceed",
+	"getdifficultyhistoryresult-difficulty": "The proof-of-work difficulty as a multiple of the minimum difficulty",
+	"getdifficultyhistoryresult-time":       "The timestamp of the block",
+	"getdifficultyhistoryresult-solvetime":  "The number of seconds between the timestamps of the block and its parent, which may be negative",
+	"getdifficultyhistoryresult-algorithm":  "The difficulty algorithm which determined the difficulty of the block (none, retarget, lwma, lwmav2 or asert)",
 
 	// GetGenerateCmd help.
 	"getgenerate--synopsis": "Returns if the server is set to generate coins (mine) or not.",
@@ -417,9 +708,61 @@ var helpDescsEnUS = map[string]string{
 	"getheaders-hashstop":      "Block hash to stop including block headers for; if not found, all headers to the latest known block are returned.",
 	"getheaders--result0":      "Serialized block headers of all located blocks, limited to some arbitrary maximum number of hashes (currently 2000, which matches the wire protocol headers message, but this is not guaranteed)",
 
+	// GetIndexInfoCmd help.
+	"getindexinfo--synopsis":       "Returns how far each of the enabled optional indexes is synced with the best chain.",
+	"getindexinfo-indexname":       "Only return the index with this name, such as 'transaction index'",
+	"getindexinfo--result0--desc":  "Index states keyed by the name of the index",
+	"getindexinfo--result0--key":   "The name of the index",
+	"getindexinfo--result0--value": "Object containing the state of the index",
+
+	// GetIndexInfoResult help.
+	"getindexinforesult-synced":            "Whether the index is caught up with the best chain",
+	"getindexinforesult-best_block_height": "The height of the last block indexed",
+
 	// GetInfoCmd help.
 	"getinfo--synopsis": "Returns a JSON object containing various state info.",
 
+	// GetMemoryInfoCmd help.
+	"getmemoryinfo--synopsis": "Returns information about the memory usage of the node.",
+	"getmemoryinfo-mode":      "The kind of information to return, only stats is supported since mallocinfo requires glibc",
+
+	// GetMemoryInfoResult help.
+	"getmemoryinforesult-locked":  "Information about the locked memory, which is not used so all values are zero",
+	"getmemoryinforesult-runtime": "Memory statistics of the Go runtime",
+	"getmemoryinforesult-caches":  "Statistics of the caches",
+
+	// GetMemoryInfoLockedResult help.
+	"getmemoryinfolockedresult-used":        "Number of bytes used",
+	"getmemoryinfolockedresult-free":        "Number of bytes available in the current arenas",
+	"getmemoryinfolockedresult-total":       "Total number of bytes managed",
+	"getmemoryinfolockedresult-locked":      "Number of bytes which succeeded locking",
+	"getmemoryinfolockedresult-chunks_used": "Number of allocated chunks",
+	"getmemoryinfolockedresult-chunks_free": "Number of unused chunks",
+
+	// GetMemoryInfoRuntimeResult help.
+	"getmemoryinforuntimeresult-alloc":        "Number of bytes of allocated heap objects",
+	"getmemoryinforuntimeresult-totalalloc":   "Cumulative number of bytes allocated for heap objects",
+	"getmemoryinforuntimeresult-sys":          "Total number of bytes of memory obtained from the operating system",
+	"getmemoryinforuntimeresult-heapalloc":    "Number of bytes of allocated heap objects",
+	"getmemoryinforuntimeresult-heapinuse":    "Number of bytes in in-use heap spans",
+	"getmemoryinforuntimeresult-heapidle":     "Number of bytes in idle heap spans",
+	"getmemoryinforuntimeresult-heapreleased": "Number of bytes of physical memory returned to the operating system",
+	"getmemoryinforuntimeresult-heapobjects":  "Number of allocated heap objects",
+	"getmemoryinforuntimeresult-stackinuse":   "Number of bytes in stack spans",
+	"getmemoryinforuntimeresult-numgc":        "Number of completed garbage collection cycles",
+	"getmemoryinforuntimeresult-gcpausetotal": "Cumulative time spent in garbage collection pauses in milliseconds",
+	"getmemoryinforuntimeresult-goroutines":   "Number of goroutines",
+
+	// GetMemoryInfoCachesResult help.
+	"getmemoryinfocachesresult-sigcache":    "Statistics of the cache of verified signatures",
+	"getmemoryinfocachesresult-scriptcache": "Statistics of the cache of verified scripts",
+
+	// GetMemoryInfoCacheResult help.
+	"getmemoryinfocacheresult-entries":    "Number of entries in the cache",
+	"getmemoryinfocacheresult-maxentries": "Maximum number of entries in the cache",
+	"getmemoryinfocacheresult-hits":       "Number of lookups which found an entry",
+	"getmemoryinfocacheresult-misses":     "Number of lookups which did not find an entry",
+
 	// GetMempoolInfoCmd help.
 	"getmempoolinfo--synopsis": "Returns memory pool information",
 
@@ -444,6 +787,23 @@ var helpDescsEnUS = map[string]string{
 	// GetMiningInfoCmd help.
 	"getmininginfo--synopsis": "Returns a JSON object containing mining-related information.",
 
+	// GetMisbehavingLogCmd help.
+	"getmisbehavinglog--synopsis": "Returns the most recent protocol violations, ban decisions and disconnections of misbehaving peers, from the oldest to the newest.\n" +
+		"At most 1000 events are kept in memory, and every event is also written to the log with the MISB subsystem tag.",
+	"getmisbehavinglog-count":    "The maximum number of events to return",
+	"getmisbehavinglog--result0": "The most recent events",
+
+	// MisbehaviorEventResult help.
+	"misbehavioreventresult-time":     "The time of the event in seconds since 1 Jan 1970 GMT",
+	"misbehavioreventresult-kind":     "The kind of the event (violation, ban or disconnect)",
+	"misbehavioreventresult-id":       "The id of the peer",
+	"misbehavioreventresult-addr":     "The ip address and port of the peer",
+	"misbehavioreventresult-inbound":  "Whether or not the peer is an inbound connection",
+	"misbehavioreventresult-banscore": "The ban score of the peer at the time of the event, or 0 for violations detected while syncing the chain",
+	"misbehavioreventresult-reason":   "The reason of the event",
+	"misbehavioreventresult-command":  "The command of the offending message, if any",
+	"misbehavioreventresult-msghex":   "The hex-encoded start of the payload of the offending message, if any",
+
 	// GetNetworkHashPSCmd help.
 	"getnetworkhashps--synopsis": "Returns the estimated network hashes per second for the block heights provided by the parameters.",
 	"getnetworkhashps-blocks":    "The number of blocks, or -1 for blocks since last difficulty change",
@@ -458,6 +818,38 @@ var helpDescsEnUS = map[string]string{
 	"getnettotalsresult-totalbytessent": "Total bytes sent",
 	"getnettotalsresult-timemillis":     "Number of milliseconds since 1 Jan 1970 GMT",
 
+	// GetNetworkInfoCmd help.
+	"getnetworkinfo--synopsis": "Returns a JSON object containing various state info regarding P2P networking.",
+
+	// NetworksResult help.
+	"networksresult-name":                        "The network name (ipv4, ipv6 or onion)",
+	"networksresult-limited":                     "Whether connections to the network are disabled",
+	"networksresult-reachable":                   "Whether the network is reachable",
+	"networksresult-proxy":                       "The proxy used for the network, or an empty string when none is used",
+	"networksresult-proxy_randomize_credentials": "Whether the proxy credentials are randomized for each connection",
+
+	// LocalAddressesResult help.
+	"localaddressesresult-address": "The local network address",
+	"localaddressesresult-port":    "The local network port",
+	"localaddressesresult-score":   "The relative score of the address",
+
+	// GetNetworkInfoResult help.
+	"getnetworkinforesult-version":         "The version of the server",
+	"getnetworkinforesult-subversion":      "The user agent the server advertises to its peers",
+	"getnetworkinforesult-protocolversion": "The latest supported protocol version",
+	"getnetworkinforesult-localservices":   "The services the server advertises to its peers as a hex string",
+	"getnetworkinforesult-localrelay":      "Whether transactions are relayed to and accepted from peers",
+	"getnetworkinforesult-timeoffset":      "The time offset in seconds applied to the local clock, which is the median offset of the time of the peers",
+	"getnetworkinforesult-connections":     "The number of connected peers",
+	"getnetworkinforesult-connections_in":  "The number of inbound peers",
+	"getnetworkinforesult-connections_out": "The number of outbound peers",
+	"getnetworkinforesult-networkactive":   "Whether peer-to-peer networking is enabled",
+	"getnetworkinforesult-networks":        "Information about each network",
+	"getnetworkinforesult-relayfee":        "The minimum relay fee for non-free transactions in LTC/KB",
+	"getnetworkinforesult-incrementalfee":  "The minimum fee rate increase for replacement transactions in LTC/KB",
+	"getnetworkinforesult-localaddresses":  "The local addresses the server advertises",
+	"getnetworkinforesult-warnings":        "Any warnings about conditions which might prevent the node from working properly",
+
 	// GetNodeAddressesResult help.
 	"getnodeaddressesresult-time":     "Timestamp in seconds since epoch (Jan 1 1970 GMT) keeping track of when the node was last seen",
 	"getnodeaddressesresult-services": "The services offered",
@@ -492,6 +884,15 @@ var helpDescsEnUS = map[string]string{
 	"getpeerinforesult-feefilter":      "The requested minimum fee a transaction must have to be announced to the peer",
 	"getpeerinforesult-syncnode":       "Whether or not the peer is the sync peer",
 
+	"getpeerinforesult-bytessent_per_msg":        "Total bytes sent for each message command",
+	"getpeerinforesult-bytessent_per_msg--key":   "command",
+	"getpeerinforesult-bytessent_per_msg--value": "n",
+	"getpeerinforesult-bytessent_per_msg--desc":  "The bytes sent in messages with the command, where *other* accounts for messages that could not be decoded",
+	"getpeerinforesult-bytesrecv_per_msg":        "Total bytes received for each message command",
+	"getpeerinforesult-bytesrecv_per_msg--key":   "command",
+	"getpeerinforesult-bytesrecv_per_msg--value": "n",
+	"getpeerinforesult-bytesrecv_per_msg--desc":  "The bytes received in messages with the command, where *other* accounts for messages that could not be decoded",
+
 	// GetPeerInfoCmd help.
 	"getpeerinfo--synopsis": "Returns data about each connected network peer as an array of json objects.",
 
@@ -517,10 +918,24 @@ var helpDescsEnUS = map[string]string{
 	"getrawtransaction--synopsis":   "Returns information about a transaction given its hash.",
 	"getrawtransaction-txid":        "The hash of the transaction",
 	"getrawtransaction-verbose":     "Specifies the transaction is returned as a JSON object instead of a hex-encoded string",
+	"getrawtransaction-blockhash":   "The hash of the block to look the transaction up in, which does not require the transaction index",
 	"getrawtransaction--condition0": "verbose=false",
 	"getrawtransaction--condition1": "verbose=true",
 	"getrawtransaction--result0":    "Hex-encoded bytes of the serialized transaction",
 
+	// GetRPCInfoCmd help.
+	"getrpcinfo--synopsis": "Returns information about the RPC server.",
+
+	// GetRPCInfoResult help.
+	"getrpcinforesult-active_commands":    "The commands which are currently being processed",
+	"getrpcinforesult-logpath":            "The path of the log file",
+	"getrpcinforesult-ratelimited":        "The number of requests rejected because the client exceeded the rate limit",
+	"getrpcinforesult-concurrencylimited": "The number of requests rejected because too many requests were being processed concurrently",
+
+	// RPCActiveCommand help.
+	"rpcactivecommand-method":   "The name of the command",
+	"rpcactivecommand-duration": "The time the command has been running in microseconds",
+
 	// GetTxOutResult help.
 	"gettxoutresult-bestblock":     "The block hash that contains the transaction output",
 	"gettxoutresult-confirmations": "The number of confirmations",
@@ -535,6 +950,45 @@ var helpDescsEnUS = map[string]string{
 	"gettxout-vout":           "The index of the output",
 	"gettxout-includemempool": "Include the mempool when true",
 
+	// GetTxOutSetInfoCmd help.
+	"gettxoutsetinfo--synopsis": "Returns statistics about the unspent transaction output set along with an audit of the coin supply.\n" +
+		"The set is scanned from a single database snapshot, so the statistics are consistent with the reported block.",
+
+	// GetTxOutSetInfoResult help.
+	"gettxoutsetinforesult-height":                   "The height of the block the set is as of",
+	"gettxoutsetinforesult-bestblock":                "The hash of the block the set is as of",
+	"gettxoutsetinforesult-transactions":             "The number of transactions with unspent outputs",
+	"gettxoutsetinforesult-txouts":                   "The number of unspent transaction outputs",
+	"gettxoutsetinforesult-bogosize":                 "A database-independent metric of the size of the set",
+	"gettxoutsetinforesult-hash_serialized_2":        "The hash of the serialized set",
+	"gettxoutsetinforesult-disk_size":                "The size of the serialized set in bytes",
+	"gettxoutsetinforesult-total_amount":             "The total amount of the unspent outputs in LTC, including the amount pegged into the MWEB",
+	"gettxoutsetinforesult-total_unspendable_amount": "The amount in LTC created by block subsidies which is not part of the set, such as the genesis output, unclaimed rewards and burned coins",
+	"gettxoutsetinforesult-total_subsidy_amount":     "The total amount in LTC created by the block subsidies of the main chain",
+	"gettxoutsetinforesult-mweb_pegged_amount":       "The amount in LTC pegged into the MWEB",
+	"gettxoutsetinforesult-subsidy_eras":             "The runs of blocks paying the same subsidy",
+
+	// TxOutSetSubsidyEra help.
+	"txoutsetsubsidyera-start_height": "The height of the first block of the era",
+	"txoutsetsubsidyera-end_height":   "The height of the last block of the era",
+	"txoutsetsubsidyera-subsidy":      "The subsidy paid by each block of the era in LTC",
+	"txoutsetsubsidyera-blocks":       "The number of blocks in the era",
+	"txoutsetsubsidyera-total_amount": "The total subsidy paid by the era in LTC",
+
+	// GetWorkCmd help.
+	"getwork--synopsis": "Returns formatted hash data to work on or submits solved data when enabled with --getwork.\n" +
+		"The work pays to the addresses specified with --miningaddr and is meant for legacy mining proxies which cannot speak getblocktemplate or stratum.",
+	"getwork-data":        "Hex-encoded data to submit",
+	"getwork--condition0": "no data provided",
+	"getwork--condition1": "data provided",
+	"getwork--result1":    "Whether or not the solved data is valid and was added to the chain",
+
+	// GetWorkResult help.
+	"getworkresult-data":     "Hex-encoded block header with the internal sha256 padding, each 4 bytes in little endian",
+	"getworkresult-hash1":    "Hex-encoded zero hash with the internal sha256 padding (only for compatibility)",
+	"getworkresult-midstate": "Hex-encoded sha256 state of the first 64 bytes of the header (only for compatibility, scrypt does not use it)",
+	"getworkresult-target":   "Hex-encoded little-endian target the scrypt hash of the header must not exceed",
+
 	// HelpCmd help.
 	"help--synopsis":   "Returns a list of all commands or help for a specified command.",
 	"help-command":     "The command to retrieve help for",
@@ -543,6 +997,52 @@ var helpDescsEnUS = map[string]string{
 	"help--result0":    "List of commands",
 	"help--result1":    "Help for specified command",
 
+	// ImportWatchOnlyCmd help.
+	"importwatchonly--synopsis": "Starts tracking the unspent outputs paying to the specified scripts without keeping any keys.\n" +
+		"Requires the watch-only index to be enabled (--watchonly).",
+	"importwatchonly-scripts":     "The addresses, hex-encoded public key scripts or output descriptors to watch",
+	"importwatchonly-rescan":      "Scan the blocks of the main chain for the unspent outputs already paying to the scripts",
+	"importwatchonly-startheight": "The height of the first block to scan",
+	"importwatchonly-range":       "The end or [begin,end] of the range to watch (only for ranged descriptors)",
+
+	// ListUnspentWatchOnlyCmd help.
+	"listunspentwatchonly--synopsis": "Returns the unspent outputs, including those of mempool transactions, paying to the watched scripts.\n" +
+		"Outputs spent by mempool transactions are not returned.",
+	"listunspentwatchonly-minconf":   "Minimum number of confirmations, where mempool outputs have none",
+	"listunspentwatchonly-maxconf":   "Maximum number of confirmations",
+	"listunspentwatchonly-addresses": "Only return the outputs paying to these addresses",
+
+	// ListUnspentWatchOnlyResult help.
+	"listunspentwatchonlyresult-txid":          "The hash of the transaction",
+	"listunspentwatchonlyresult-vout":          "The index of the output",
+	"listunspentwatchonlyresult-address":       "The address paid by the output, if any",
+	"listunspentwatchonlyresult-scriptPubKey":  "The public key script of the output encoded as a string",
+	"listunspentwatchonlyresult-amount":        "The amount of the output in LTC",
+	"listunspentwatchonlyresult-confirmations": "The number of confirmations of the output",
+	"listunspentwatchonlyresult-coinbase":      "Whether the output belongs to a coinbase transaction",
+
+	// RescanBlockchainCmd help.
+	"rescanblockchain--synopsis": "Scans the blocks of the main chain for transactions paying to the specified scripts or spending the specified outpoints.\n" +
+		"Transactions spending the outputs paying to the scripts are returned as well.\n" +
+		"Websocket clients are also sent each transaction as a rescanblockchainmatch notification as soon as it is found.\n" +
+		"Blocks which do not involve the scripts are skipped using the committed filters when the CF index is enabled and no outpoints are specified.",
+	"rescanblockchain-startheight": "The height of the first block to scan",
+	"rescanblockchain-stopheight":  "The height of the last block to scan (default: the best height)",
+	"rescanblockchain-scripts":     "The addresses, hex-encoded public key scripts or output descriptors to look for",
+	"rescanblockchain-outpoints":   "The outpoints whose spending transactions to look for",
+
+	// RescanBlockchainResult help.
+	"rescanblockchainresult-start_height": "The height of the first block scanned",
+	"rescanblockchainresult-stop_height":  "The height of the last block scanned",
+	"rescanblockchainresult-matches":      "The transactions found, in the order of the chain",
+
+	// RescanBlockchainMatch help.
+	"rescanblockchainmatch-txid":      "The hash of the transaction",
+	"rescanblockchainmatch-hex":       "The serialized, hex-encoded transaction",
+	"rescanblockchainmatch-blockhash": "The hash of the block containing the transaction",
+	"rescanblockchainmatch-height":    "The height of the block containing the transaction",
+	"rescanblockchainmatch-index":     "The index of the transaction in the block",
+
 	// PingCmd help.
 	"ping--synopsis": "Queues a ping to be sent to each connected peer.\n" +
 		"Ping times are provided by getpeerinfo via the pingtime and pingwait fields.",
@@ -576,11 +1076,105 @@ var helpDescsEnUS = map[string]string{
 	"setgenerate-generate":     "Use true to enable generation, false to disable it",
 	"setgenerate-genproclimit": "The number of processors (cores) to limit generation to or -1 for default",
 
+	// SetLogLevelCmd help.
+	"setloglevel--synopsis": "Dynamically changes the logging level of a subsystem and returns the logging level of each subsystem.\n" +
+		"The valid levels are trace, debug, info, warn, error, and critical.",
+	"setloglevel-subsystem":       "The subsystem to change, such as CHAN, or 'all' to change every subsystem",
+	"setloglevel-level":           "The logging level to use",
+	"setloglevel--result0--desc":  "The logging level of each subsystem",
+	"setloglevel--result0--key":   "Subsystem",
+	"setloglevel--result0--value": "Logging level",
+
+	// SetMockTimeCmd help.
+	"setmocktime--synopsis": "Fixes the clock of the node to the given time (simnet or regtest only).\n" +
+		"The adjusted time, including the time offsets of peers, is based on the mock time until it is cleared.",
+	"setmocktime-timestamp": "The unix time in seconds the clock is fixed to, or 0 to restore the system clock",
+
+	// SetPolicyOpts help.
+	"setpolicyopts-minrelaytxfee": "The minimum fee rate in LTC/KB for a transaction to be considered to pay a non-zero fee, which also applies to mined transactions",
+	"setpolicyopts-maxorphantx":   "The maximum number of orphan transactions kept in memory",
+	"setpolicyopts-relaynonstd":   "Whether to relay non-standard transactions (test networks only)",
+	"setpolicyopts-maxpeers":      "The maximum number of inbound and outbound peers, which also caps the number of outbound peers targeted, connected peers are not disconnected when lowering it",
+
+	// SetPolicyResult help.
+	"setpolicyresult-minrelaytxfee": "The minimum fee rate in LTC/KB for a transaction to be considered to pay a non-zero fee",
+	"setpolicyresult-maxorphantx":   "The maximum number of orphan transactions kept in memory",
+	"setpolicyresult-relaynonstd":   "Whether non-standard transactions are relayed",
+	"setpolicyresult-maxpeers":      "The maximum number of inbound and outbound peers",
+
+	// SetPolicyCmd help.
+	"setpolicy--synopsis": "Changes a subset of the runtime settings without restarting the node and returns the settings in effect afterwards.\n" +
+		"The settings which are omitted are left unchanged and the transactions in the memory pool are kept.",
+	"setpolicy-options": "The settings to change, or omitted to only return the current settings",
+
 	// SignMessageWithPrivKeyCmd help.
-	"signmessagewithprivkey--synopsis": "Sign a message with the private key of an address",
-	"signmessagewithprivkey-privkey":   "The private key to sign the message with",
-	"signmessagewithprivkey-message":   "The message to create a signature of",
-	"signmessagewithprivkey--result0":  "The signature of the message encoded in base 64",
+	"signmessagewithprivkey--synopsis":   "Sign a message with the private key of an address",
+	"signmessagewithprivkey-privkey":     "The private key to sign the message with",
+	"signmessagewithprivkey-message":     "The message to create a signature of",
+	"signmessagewithprivkey-addresstype": "The type of the address of the private key the message is signed for (legacy, p2sh-segwit, or bech32)",
+	"signmessagewithprivkey--result0":    "The signature of the message encoded in base 64",
+
+	// RawTxWitnessInput help.
+	"rawtxwitnessinput-txid":          "The hash of the transaction containing the previous output",
+	"rawtxwitnessinput-vout":          "The index of the previous output",
+	"rawtxwitnessinput-scriptPubKey":  "The hex-encoded public key script of the previous output",
+	"rawtxwitnessinput-redeemScript":  "The hex-encoded redeem script (pay-to-script-hash outputs only)",
+	"rawtxwitnessinput-witnessScript": "The hex-encoded witness script (pay-to-witness-script-hash outputs only)",
+	"rawtxwitnessinput-amount":        "The value of the previous output in LTC (required for segwit outputs)",
+
+	// SignRawTransactionError help.
+	"signrawtransactionerror-txid":      "The hash of the transaction containing the previous output",
+	"signrawtransactionerror-vout":      "The index of the previous output",
+	"signrawtransactionerror-scriptSig": "The hex-encoded signature script of the input",
+	"signrawtransactionerror-sequence":  "The sequence number of the input",
+	"signrawtransactionerror-error":     "The reason the input is not fully signed",
+
+	// SignRawTransactionResult help.
+	"signrawtransactionresult-hex":      "The hex-encoded transaction with the signatures added",
+	"signrawtransactionresult-complete": "Whether every input is fully signed",
+	"signrawtransactionresult-errors":   "The inputs which are not fully signed (omitted when complete)",
+
+	// SignRawTransactionWithKeyCmd help.
+	"signrawtransactionwithkey--synopsis": "Signs the inputs of a raw transaction using the provided private keys and returns the transaction along with the inputs which are not fully signed.\n" +
+		"Pay-to-pubkey, pay-to-pubkey-hash, multisig, pay-to-script-hash (including nested segwit), pay-to-witness-pubkey-hash, pay-to-witness-script-hash, and BIP 86 taproot key path inputs are supported.\n" +
+		"Previous outputs which are not provided are looked up in the memory pool and the unspent transaction outputs.",
+	"signrawtransactionwithkey-rawtx":       "Serialized, hex-encoded transaction",
+	"signrawtransactionwithkey-privkeys":    "WIF-encoded private keys to sign with",
+	"signrawtransactionwithkey-inputs":      "The previous outputs spent by the transaction along with any redeem and witness scripts",
+	"signrawtransactionwithkey-sighashtype": "The signature hash type: 'DEFAULT', 'ALL', 'NONE', or 'SINGLE', optionally combined with '|ANYONECANPAY' ('DEFAULT' and 'ALL' are equivalent, producing 'ALL' signatures for other inputs and 'DEFAULT' signatures for taproot inputs)",
+
+	// StartOperationCmd help.
+	"startoperation--synopsis": "Runs a long-running command in the background instead of blocking the request until it completes.\n" +
+		"The commands gettxoutsetinfo, rescanblockchain and verifychain are supported.\n" +
+		"The progress and the result of the operation are returned by getoperationstatus, and websocket clients are also sent operationprogress notifications.",
+	"startoperation-method":   "The command to run",
+	"startoperation-params":   "The parameters of the command as a JSON array",
+	"startoperation--result0": "The id of the operation",
+
+	// GetOperationStatusCmd help.
+	"getoperationstatus--synopsis": "Returns the progress of an operation started by startoperation along with its result once it has finished.\n" +
+		"The status of finished operations is only kept for the most recent operations.",
+	"getoperationstatus-id": "The id of the operation",
+
+	// CancelOperationCmd help.
+	"canceloperation--synopsis": "Requests an operation started by startoperation to stop and returns its status, which may still be running until it has stopped.",
+	"canceloperation-id":        "The id of the operation",
+
+	// OperationStatusResult help.
+	"operationstatusresult-id":        "The id of the operation",
+	"operationstatusresult-method":    "The command run by the operation",
+	"operationstatusresult-state":     "The state of the operation: 'running', 'completed', 'failed' or 'cancelled'",
+	"operationstatusresult-done":      "The units of work done so far, such as blocks verified",
+	"operationstatusresult-total":     "The total units of work of the operation",
+	"operationstatusresult-progress":  "The fraction of the work done, from 0 to 1",
+	"operationstatusresult-starttime": "The time the operation was started in seconds since 1 Jan 1970 GMT",
+	"operationstatusresult-elapsed":   "The number of seconds the operation has run for",
+	"operationstatusresult-result":    "The result of the command (only when completed)",
+	"operationstatusresult-error":     "The error of the command (only when failed or cancelled)",
+
+	// RPCError help.
+	"rpcerror-code":    "The error code",
+	"rpcerror-message": "The error message",
 
 	// StopCmd help.
 	"stop--synopsis": "Shutdown ltcd.",
@@ -595,15 +1189,44 @@ var helpDescsEnUS = map[string]string{
 	"submitblock-options":     "This parameter is currently ignored",
 	"submitblock--condition0": "Block successfully submitted",
 	"submitblock--condition1": "Block rejected",
-	"submitblock--result1":    "The reason the block was rejected",
+	"submitblock--result1":    "The reason the block was rejected as described in BIP0022 (for example bad-diffbits, bad-txnmrklroot or time-too-new)",
+
+	// SubmitHeaderCmd help.
+	"submitheader--synopsis": "Decodes the given hex-encoded block header and adds it to the block index ahead of its block if it is valid.\n" +
+		"The header must build on a known header. It is announced to peers once its block is submitted.",
+	"submitheader-hexdata": "Serialized, hex-encoded block header",
+
+	// TraceScriptStep help.
+	"tracescriptstep-scriptindex": "Index of the script the next opcode executes from (0 is the signature script, 1 the public key script, and higher indexes redeem or witness scripts)",
+	"tracescriptstep-opcodeindex": "Index of the next opcode to execute within the script",
+	"tracescriptstep-opcode":      "Disassembly of the opcode executed by this step (omitted for the initial state)",
+	"tracescriptstep-stack":       "Hex-encoded data stack items, top of the stack last",
+	"tracescriptstep-altstack":    "Hex-encoded alternate stack items, top of the stack last",
+	"tracescriptstep-condstack":   "Conditional execution state of each nested branch, innermost last ('true', 'false', or 'skip')",
+	"tracescriptstep-remaining":   "Disassembly of the opcodes left to execute in the current script",
+
+	// TraceScriptResult help.
+	"tracescriptresult-valid": "Whether the input's scripts executed successfully",
+	"tracescriptresult-error": "The reason execution failed (only when valid is false)",
+	"tracescriptresult-steps": "The engine state before the first step and after every subsequent step",
+
+	// TraceScriptCmd help.
+	"tracescript--synopsis": "Executes the scripts of a transaction input one opcode at a time using the standard verification flags and returns the resulting execution trace.\n" +
+		"The outputs spent by the transaction must be in the memory pool or unspent in the main chain.",
+	"tracescript-hextx": "Serialized, hex-encoded transaction",
+	"tracescript-index": "Index of the transaction input to trace",
 
 	// ValidateAddressResult help.
 	"validateaddresschainresult-isvalid":         "Whether or not the address is valid",
 	"validateaddresschainresult-address":         "The litecoin address (only when isvalid is true)",
+	"validateaddresschainresult-type":            "The type of the address (p2pk, p2pkh, p2sh, p2wpkh, p2wsh, p2tr, or mweb)",
+	"validateaddresschainresult-network":         "The network the address belongs to (also set when the address is only invalid because it belongs to another network)",
+	"validateaddresschainresult-scriptPubKey":    "The hex-encoded script paid to by the address (not set for mweb addresses)",
 	"validateaddresschainresult-isscript":        "If the key is a script",
 	"validateaddresschainresult-iswitness":       "If the address is a witness address",
 	"validateaddresschainresult-witness_version": "The version number of the witness program",
 	"validateaddresschainresult-witness_program": "The hex value of the witness program",
+	"validateaddresschainresult-error":           "The reason the address is invalid (only when isvalid is false)",
 
 	// ValidateAddressCmd help.
 	"validateaddress--synopsis": "Verify an address is valid.",
@@ -614,14 +1237,16 @@ var helpDescsEnUS = map[string]string{
 		"The actual checks performed by the checklevel parameter are implementation specific.\n" +
 		"For ltcd this is:\n" +
 		"checklevel=0 - Look up each block and ensure it can be loaded from the database.\n" +
-		"checklevel=1 - Perform basic context-free sanity checks on each block.",
+		"checklevel=1 - Perform basic context-free sanity checks on each block.\n" +
+		"checklevel=2 - Ensure the undo data of each block is present and consistent with the block.\n" +
+		"checklevel=3 - Ensure the outputs created by each block are consistent with the utxo set after rewinding the later blocks in memory.",
 	"verifychain-checklevel": "How thorough the block verification is",
-	"verifychain-checkdepth": "The number of blocks to check",
+	"verifychain-checkdepth": "The number of blocks to check (0 = all)",
 	"verifychain--result0":   "Whether or not the chain verified",
 
 	// VerifyMessageCmd help.
 	"verifymessage--synopsis": "Verify a signed message.",
-	"verifymessage-address":   "The litecoin address to use for the signature",
+	"verifymessage-address":   "The pay-to-pubkey-hash, pay-to-witness-pubkey-hash, or nested pay-to-witness-pubkey-hash address to use for the signature",
 	"verifymessage-signature": "The base-64 encoded signature provided by the signer",
 	"verifymessage-message":   "The signed message",
 	"verifymessage--result0":  "Whether or not the signature verified",
@@ -639,7 +1264,7 @@ var helpDescsEnUS = map[string]string{
 	"stopnotifyblocks--synopsis": "Cancel registered notifications for whenever a block is connected or disconnected from the main (best) chain.",
 
 	// NotifyNewTransactionsCmd help.
-	"notifynewtransactions--synopsis": "Send either a txaccepted or a txacceptedverbose notification when a new transaction is accepted into the mempool.",
+	"notifynewtransactions--synopsis": "Send either a txaccepted or a txacceptedverbose notification when a new transaction is accepted into the mempool, and a txremoved notification when a transaction is removed from it.",
 	"notifynewtransactions-verbose":   "Specifies which type of notification to receive. If verbose is true, then the caller receives txacceptedverbose, otherwise the caller receives txaccepted",
 
 	// StopNotifyNewTransactionsCmd help.
@@ -714,54 +1339,87 @@ var helpDescsEnUS = map[string]string{
 // This information is used to generate the help.  Each result type must be a
 // pointer to the type (or nil to indicate no return value).
 var rpcResultTypes = map[string][]interface{}{
-	"addnode":                nil,
-	"createrawtransaction":   {(*string)(nil)},
-	"debuglevel":             {(*string)(nil), (*string)(nil)},
-	"decoderawtransaction":   {(*btcjson.TxRawDecodeResult)(nil)},
-	"decodescript":           {(*btcjson.DecodeScriptResult)(nil)},
-	"estimatefee":            {(*float64)(nil)},
-	"generate":               {(*[]string)(nil)},
-	"getaddednodeinfo":       {(*[]string)(nil), (*[]btcjson.GetAddedNodeInfoResult)(nil)},
-	"getbestblock":           {(*btcjson.GetBestBlockResult)(nil)},
-	"getbestblockhash":       {(*string)(nil)},
-	"getblock":               {(*string)(nil), (*btcjson.GetBlockVerboseResult)(nil)},
-	"getblockcount":          {(*int64)(nil)},
-	"getblockhash":           {(*string)(nil)},
-	"getblockheader":         {(*string)(nil), (*btcjson.GetBlockHeaderVerboseResult)(nil)},
-	"getblocktemplate":       {(*btcjson.GetBlockTemplateResult)(nil), (*string)(nil), nil},
-	"getblockchaininfo":      {(*btcjson.GetBlockChainInfoResult)(nil)},
-	"getcfilter":             {(*string)(nil)},
-	"getcfilterheader":       {(*string)(nil)},
-	"getconnectioncount":     {(*int32)(nil)},
-	"getcurrentnet":          {(*uint32)(nil)},
-	"getdifficulty":          {(*float64)(nil)},
-	"getgenerate":            {(*bool)(nil)},
-	"gethashespersec":        {(*float64)(nil)},
-	"getheaders":             {(*[]string)(nil)},
-	"getinfo":                {(*btcjson.InfoChainResult)(nil)},
-	"getmempoolinfo":         {(*btcjson.GetMempoolInfoResult)(nil)},
-	"getmininginfo":          {(*btcjson.GetMiningInfoResult)(nil)},
-	"getnettotals":           {(*btcjson.GetNetTotalsResult)(nil)},
-	"getnetworkhashps":       {(*float64)(nil)},
-	"getnodeaddresses":       {(*[]btcjson.GetNodeAddressesResult)(nil)},
-	"getpeerinfo":            {(*[]btcjson.GetPeerInfoResult)(nil)},
-	"getrawmempool":          {(*[]string)(nil), (*btcjson.GetRawMempoolVerboseResult)(nil)},
-	"getrawtransaction":      {(*string)(nil), (*btcjson.TxRawResult)(nil)},
-	"gettxout":               {(*btcjson.GetTxOutResult)(nil)},
-	"node":                   nil,
-	"help":                   {(*string)(nil), (*string)(nil)},
-	"ping":                   nil,
-	"searchrawtransactions":  {(*string)(nil), (*[]btcjson.SearchRawTransactionsResult)(nil)},
-	"sendrawtransaction":     {(*string)(nil)},
-	"setgenerate":            nil,
-	"signmessagewithprivkey": {(*string)(nil)},
-	"stop":                   {(*string)(nil)},
-	"submitblock":            {nil, (*string)(nil)},
-	"uptime":                 {(*int64)(nil)},
-	"validateaddress":        {(*btcjson.ValidateAddressChainResult)(nil)},
-	"verifychain":            {(*bool)(nil)},
-	"verifymessage":          {(*bool)(nil)},
-	"version":                {(*map[string]btcjson.VersionResult)(nil)},
+	"addnode":                   nil,
+	"canceloperation":           {(*btcjson.OperationStatusResult)(nil)},
+	"combinepsbt":               {(*string)(nil)},
+	"converttopsbt":             {(*string)(nil)},
+	"createrawtransaction":      {(*string)(nil)},
+	"debuglevel":                {(*string)(nil), (*string)(nil)},
+	"decodepsbt":                {(*btcjson.DecodePsbtResult)(nil)},
+	"decoderawtransaction":      {(*btcjson.TxRawDecodeResult)(nil)},
+	"decodescript":              {(*btcjson.DecodeScriptResult)(nil)},
+	"deriveaddresses":           {(*btcjson.DeriveAddressesResult)(nil)},
+	"estimatefee":               {(*float64)(nil)},
+	"finalizepsbt":              {(*btcjson.FinalizePsbtResult)(nil)},
+	"fundrawtransaction":        {(*fundRawTransactionResult)(nil)},
+	"generate":                  {(*[]string)(nil)},
+	"generateblock":             {(*btcjson.GenerateBlockResult)(nil)},
+	"getaddednodeinfo":          {(*[]string)(nil), (*[]btcjson.GetAddedNodeInfoResult)(nil)},
+	"getaddrmaninfo":            {(*btcjson.GetAddrManInfoResult)(nil)},
+	"getbestblock":              {(*btcjson.GetBestBlockResult)(nil)},
+	"getbestblockhash":          {(*string)(nil)},
+	"getblock":                  {(*string)(nil), (*btcjson.GetBlockVerboseResult)(nil)},
+	"getblockcount":             {(*int64)(nil)},
+	"getblockfrompeer":          nil,
+	"getblockhash":              {(*string)(nil)},
+	"getblockheader":            {(*string)(nil), (*btcjson.GetBlockHeaderVerboseResult)(nil)},
+	"getblocksubsidy":           {(*btcjson.GetBlockSubsidyResult)(nil)},
+	"getblocktemplate":          {(*btcjson.GetBlockTemplateResult)(nil), (*string)(nil), nil},
+	"getblockchaininfo":         {(*btcjson.GetBlockChainInfoResult)(nil)},
+	"getcfilter":                {(*string)(nil)},
+	"getcfilterheader":          {(*string)(nil)},
+	"getconnectioncount":        {(*int32)(nil)},
+	"getchainparams":            {(*btcjson.GetChainParamsResult)(nil)},
+	"getchaintips":              {(*[]btcjson.GetChainTipsResult)(nil), (*btcjson.GetChainTipsVerboseResult)(nil)},
+	"getcurrentnet":             {(*uint32)(nil)},
+	"getdescriptorinfo":         {(*btcjson.GetDescriptorInfoResult)(nil)},
+	"getdifficulty":             {(*float64)(nil), (*btcjson.GetDifficultyResult)(nil)},
+	"getdifficultyhistory":      {(*[]btcjson.GetDifficultyHistoryResult)(nil)},
+	"getgenerate":               {(*bool)(nil)},
+	"gethashespersec":           {(*float64)(nil)},
+	"getheaders":                {(*[]string)(nil)},
+	"getindexinfo":              {(*map[string]btcjson.GetIndexInfoResult)(nil)},
+	"getinfo":                   {(*btcjson.InfoChainResult)(nil)},
+	"getmemoryinfo":             {(*btcjson.GetMemoryInfoResult)(nil)},
+	"getmempoolinfo":            {(*btcjson.GetMempoolInfoResult)(nil)},
+	"getmininginfo":             {(*btcjson.GetMiningInfoResult)(nil)},
+	"getmisbehavinglog":         {(*[]btcjson.MisbehaviorEventResult)(nil)},
+	"getnettotals":              {(*btcjson.GetNetTotalsResult)(nil)},
+	"getnetworkhashps":          {(*float64)(nil)},
+	"getnetworkinfo":            {(*btcjson.GetNetworkInfoResult)(nil)},
+	"getnodeaddresses":          {(*[]btcjson.GetNodeAddressesResult)(nil)},
+	"getoperationstatus":        {(*btcjson.OperationStatusResult)(nil)},
+	"getpeerinfo":               {(*[]btcjson.GetPeerInfoResult)(nil)},
+	"getrawmempool":             {(*[]string)(nil), (*btcjson.GetRawMempoolVerboseResult)(nil)},
+	"getrawtransaction":         {(*string)(nil), (*btcjson.TxRawResult)(nil)},
+	"getrpcinfo":                {(*btcjson.GetRPCInfoResult)(nil)},
+	"gettxout":                  {(*btcjson.GetTxOutResult)(nil)},
+	"gettxoutsetinfo":           {(*btcjson.GetTxOutSetInfoResult)(nil)},
+	"getwork":                   {(*btcjson.GetWorkResult)(nil), (*bool)(nil)},
+	"node":                      nil,
+	"help":                      {(*string)(nil), (*string)(nil)},
+	"importwatchonly":           nil,
+	"listunspentwatchonly":      {(*[]btcjson.ListUnspentWatchOnlyResult)(nil)},
+	"ping":                      nil,
+	"rescanblockchain":          {(*btcjson.RescanBlockchainResult)(nil)},
+	"searchrawtransactions":     {(*string)(nil), (*[]btcjson.SearchRawTransactionsResult)(nil)},
+	"sendrawtransaction":        {(*string)(nil)},
+	"setgenerate":               nil,
+	"setloglevel":               {(*map[string]string)(nil)},
+	"setmocktime":               nil,
+	"setpolicy":                 {(*btcjson.SetPolicyResult)(nil)},
+	"signmessagewithprivkey":    {(*string)(nil)},
+	"signrawtransactionwithkey": {(*btcjson.SignRawTransactionResult)(nil)},
+	"startoperation":            {(*string)(nil)},
+	"stop":                      {(*string)(nil)},
+	"submitblock":               {nil, (*string)(nil)},
+	"submitheader":              nil,
+	"tracescript":               {(*btcjson.TraceScriptResult)(nil)},
+	"uptime":                    {(*int64)(nil)},
+	"validateaddress":           {(*btcjson.ValidateAddressChainResult)(nil)},
+	"verifychain":               {(*bool)(nil)},
+	"verifymessage":             {(*bool)(nil)},
+	"version":                   {(*map[string]btcjson.VersionResult)(nil)},
 
 	// Websocket commands.
 	"loadtxfilter":              nil,
@@ -798,8 +1456,13 @@ func (c *helpCacher) rpcMethodHelp(method string) (string, error) {
 		return help, nil
 	}
 
-	// Look up the result types for the method.
+	// Look up the result types for the method, falling back to the result
+	// types registered along with the command for commands which are not
+	// built in.
 	resultTypes, ok := rpcResultTypes[method]
+	if !ok {
+		resultTypes, ok = btcjson.RegisteredCmdHelp(method)
+	}
 	if !ok {
 		return "", errors.New("no result types specified for method " +
 			method)
@@ -4,7 +4,22 @@
 
 package main
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/btcjson"
+)
+
+// hasResultTypes returns whether result types are specified for the provided
+// method, either by the RPC server or along with the command.
+func hasResultTypes(method string) bool {
+	if _, ok := rpcResultTypes[method]; ok {
+		return true
+	}
+	_, ok := btcjson.RegisteredCmdHelp(method)
+	return ok
+}
 
 // TestHelp ensures the help is reasonably accurate by checking that every
 // command specified also has result types defined and the one-line usage and
@@ -12,7 +27,7 @@ import "testing"
 func TestHelp(t *testing.T) {
 	// Ensure there are result types specified for every handler.
 	for k := range rpcHandlers {
-		if _, ok := rpcResultTypes[k]; !ok {
+		if !hasResultTypes(k) {
 			t.Errorf("RPC handler defined for method '%v' without "+
 				"also specifying result types", k)
 			continue
@@ -20,7 +35,7 @@ func TestHelp(t *testing.T) {
 
 	}
 	for k := range wsHandlers {
-		if _, ok := rpcResultTypes[k]; !ok {
+		if !hasResultTypes(k) {
 			t.Errorf("RPC handler defined for method '%v' without "+
 				"also specifying result types", k)
 			continue
@@ -63,3 +78,38 @@ func TestHelp(t *testing.T) {
 		}
 	}
 }
+
+// TestHelpRegisteredCmd ensures the help for commands which are not built in is
+// generated from the help registered along with them.
+func TestHelpRegisteredCmd(t *testing.T) {
+	type testExtCmd struct {
+		Verbose *bool
+	}
+	type testExtResult struct {
+		Height int32 `json:"height"`
+	}
+
+	const method = "testextcmd"
+	btcjson.MustRegisterCmd(method, (*testExtCmd)(nil), 0)
+	btcjson.MustRegisterCmdHelp(method, map[string]string{
+		"testextcmd--synopsis":   "Returns the test extension state.",
+		"testextcmd-verbose":     "Whether to be verbose",
+		"testextresult-height":   "The height",
+		"testextcmd--condition0": "verbose=false",
+		"testextcmd--condition1": "verbose=true",
+		"testextcmd--result0":    "The height",
+	}, (*int32)(nil), (*testExtResult)(nil))
+
+	help, err := newHelpCacher().rpcMethodHelp(method)
+	if err != nil {
+		t.Fatalf("Failed to generate help for method '%v': %v", method,
+			err)
+	}
+	wantHelp := "testextcmd (verbose)\n\n" +
+		"Returns the test extension state.\n\n"
+	if !strings.HasPrefix(help, wantHelp) ||
+		!strings.Contains(help, "The height") {
+
+		t.Fatalf("unexpected help - got\n%v", help)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 	"github.com/ltcsuite/ltcd/database"
 	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/mempool"
 	"github.com/ltcsuite/ltcd/txscript"
 	"github.com/ltcsuite/ltcd/wire"
 	"golang.org/x/crypto/ripemd160"
@@ -76,6 +77,8 @@ var wsHandlersBeforeInit = map[string]wsCommandHandler{
 	"stopnotifyreceived":        handleStopNotifyReceived,
 	"rescan":                    handleRescan,
 	"rescanblocks":              handleRescanBlocks,
+	"rescanblockchain":          handleWebsocketRescanBlockchain,
+	"startoperation":            handleWebsocketStartOperation,
 }
 
 // WebsocketHandler handles a new websocket client by creating a new wsClient,
@@ -246,6 +249,27 @@ func (m *wsNotificationManager) NotifyMempoolTx(tx *ltcutil.Tx, isNew bool) {
 	}
 }
 
+// NotifyMempoolTxRemoved passes a transaction removed from the mempool along
+// with the reason for its removal to the notification manager for transaction
+// notification processing.
+func (m *wsNotificationManager) NotifyMempoolTxRemoved(tx *ltcutil.Tx,
+	reason mempool.RemovalReason) {
+
+	n := &notificationTxRemovedFromMempool{
+		reason: reason,
+		tx:     tx,
+	}
+
+	// As NotifyMempoolTxRemoved will be called by mempool and the RPC
+	// server may no longer be running, use a select statement to unblock
+	// enqueuing the notification once the RPC server has begun shutting
+	// down.
+	select {
+	case m.queueNotification <- n:
+	case <-m.quit:
+	}
+}
+
 // wsClientFilter tracks relevant addresses for each websocket client for
 // the `rescanblocks` extension. It is modified by the `loadtxfilter` command.
 //
@@ -451,6 +475,10 @@ type notificationTxAcceptedByMempool struct {
 	isNew bool
 	tx    *ltcutil.Tx
 }
+type notificationTxRemovedFromMempool struct {
+	reason mempool.RemovalReason
+	tx     *ltcutil.Tx
+}
 
 // Notification control requests
 type notificationRegisterClient wsClient
@@ -539,6 +567,12 @@ out:
 				m.notifyForTx(watchedOutPoints, watchedAddrs, n.tx, nil)
 				m.notifyRelevantTxAccepted(n.tx, clients)
 
+			case *notificationTxRemovedFromMempool:
+				if len(txNotifications) != 0 {
+					m.notifyForRemovedTx(txNotifications,
+						n.tx, n.reason)
+				}
+
 			case *notificationRegisterBlocks:
 				wsc := (*wsClient)(n)
 				blockNotifications[wsc.quit] = wsc
@@ -868,6 +902,23 @@ func (m *wsNotificationManager) notifyForNewTx(clients map[chan struct{}]*wsClie
 	}
 }
 
+// notifyForRemovedTx notifies websocket clients that have registered for
+// updates when a transaction is removed from the memory pool.
+func (m *wsNotificationManager) notifyForRemovedTx(clients map[chan struct{}]*wsClient,
+	tx *ltcutil.Tx, reason mempool.RemovalReason) {
+
+	ntfn := btcjson.NewTxRemovedNtfn(tx.Hash().String(), reason.String())
+	marshalledJSON, err := btcjson.MarshalCmd(btcjson.RpcVersion1, nil, ntfn)
+	if err != nil {
+		rpcsLog.Errorf("Failed to marshal tx removed notification: %v",
+			err)
+		return
+	}
+	for _, wsc := range clients {
+		wsc.QueueNotification(marshalledJSON)
+	}
+}
+
 // RegisterSpentRequests requests a notification when each of the passed
 // outpoints is confirmed spent (contained in a block connected to the main
 // chain) for the passed websocket client.  The request is automatically
@@ -1700,11 +1751,16 @@ out:
 						// Lookup the websocket extension for the command, if it doesn't
 						// exist fallback to handling the command as a standard command.
 						var resp interface{}
-						wsHandler, ok := wsHandlers[cmd.method]
-						if ok {
+						release, jsonErr := c.server.limitRequest(
+							clientHost(c.addr), cmd.method)
+						if jsonErr != nil {
+							err = jsonErr
+						} else if wsHandler, ok := wsHandlers[cmd.method]; ok {
 							resp, err = wsHandler(c, cmd.cmd)
+							release()
 						} else {
 							resp, err = c.server.standardCmdResult(cmd, nil)
+							release()
 						}
 
 						// Marshal request output.
@@ -1771,11 +1827,15 @@ func (c *wsClient) serviceRequest(r *parsedRPCCmd) {
 
 	// Lookup the websocket extension for the command and if it doesn't
 	// exist fallback to handling the command as a standard command.
-	wsHandler, ok := wsHandlers[r.method]
-	if ok {
+	release, jsonErr := c.server.limitRequest(clientHost(c.addr), r.method)
+	if jsonErr != nil {
+		err = jsonErr
+	} else if wsHandler, ok := wsHandlers[r.method]; ok {
 		result, err = wsHandler(c, r.cmd)
+		release()
 	} else {
 		result, err = c.server.standardCmdResult(r, nil)
+		release()
 	}
 	reply, err := createMarshalledReply(r.jsonrpc, r.id, result, err)
 	if err != nil {
@@ -2555,6 +2615,56 @@ func handleRescanBlocks(wsc *wsClient, icmd interface{}) (interface{}, error) {
 	return &discoveredData, nil
 }
 
+// handleWebsocketRescanBlockchain implements the rescanblockchain command
+// extension for websocket connections.  Unlike over HTTP, each transaction
+// found is also sent as a rescanblockchainmatch notification as soon as it is
+// found, before the command returns.
+func handleWebsocketRescanBlockchain(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.RescanBlockchainCmd)
+	if !ok {
+		return nil, btcjson.ErrRPCInternal
+	}
+
+	return rescanBlockchain(wsc.server, cmd, wsc.quit,
+		func(match *btcjson.RescanBlockchainMatch) {
+			n := btcjson.NewRescanBlockchainMatchNtfn(*match)
+			mn, err := btcjson.MarshalCmd(btcjson.RpcVersion1, nil, n)
+			if err != nil {
+				rpcsLog.Errorf("Failed to marshal rescan match "+
+					"notification: %v", err)
+				return
+			}
+
+			// The rescan stops on its own once the client
+			// disconnects.
+			_ = wsc.QueueNotification(mn)
+		}, nil)
+}
+
+// handleWebsocketStartOperation implements the startoperation command for
+// websocket clients, which are sent operationprogress notifications as the
+// operation makes progress and once it has finished.
+func handleWebsocketStartOperation(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.StartOperationCmd)
+	if !ok {
+		return nil, btcjson.ErrRPCInternal
+	}
+
+	return startOperation(wsc.server, cmd,
+		func(n *btcjson.OperationProgressNtfn) {
+			mn, err := btcjson.MarshalCmd(btcjson.RpcVersion1, nil, n)
+			if err != nil {
+				rpcsLog.Errorf("Failed to marshal operation "+
+					"progress notification: %v", err)
+				return
+			}
+
+			// The operation keeps running once the client
+			// disconnects, so the error is ignored.
+			_ = wsc.QueueNotification(mn)
+		})
+}
+
 // recoverFromReorg attempts to recover from a detected reorganize during a
 // rescan.  It fetches a new range of block shas from the database and
 // verifies that the new range of blocks is on the same fork as a previous
@@ -7,13 +7,17 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"net"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"strconv"
@@ -48,10 +52,6 @@ const (
 		wire.SFNodeBloom | wire.SFNodeWitness | wire.SFNodeCF |
 		wire.SFNodeMWEBLightClient | wire.SFNodeMWEB
 
-	// defaultRequiredServices describes the default services that are
-	// required to be supported by outbound peers.
-	defaultRequiredServices = wire.SFNodeNetwork
-
 	// defaultTargetOutbound is the default number of outbound peers to target.
 	defaultTargetOutbound = 8
 
@@ -59,6 +59,21 @@ const (
 	// retries when connecting to persistent peers.  It is adjusted by the
 	// number of retries such that there is a retry backoff.
 	connectionRetryInterval = time.Second * 5
+
+	// maxFilterAddsPerFilter is the maximum number of filteradd messages a
+	// peer may send for a single loaded bloom filter.  Every added element
+	// raises the false positive rate of the filter, so unbounded additions
+	// would eventually make it match everything and turn each merkle block
+	// into a full block.
+	maxFilterAddsPerFilter = 1000
+
+	// maxFilterMsgScore is the maximum decaying score of bloom filter
+	// messages (filterload, filteradd, and filterclear) a peer may
+	// accumulate before it is disconnected.  Each message increases the
+	// score by one and the score halves every minute, so short bursts are
+	// allowed while the sustained rate is bounded to a few messages per
+	// second.
+	maxFilterMsgScore = 200
 )
 
 var (
@@ -118,6 +133,34 @@ func (a simpleAddr) Network() string {
 // Ensure simpleAddr implements the net.Addr interface.
 var _ net.Addr = simpleAddr{}
 
+// serviceListener wraps a listener configured to advertise its own services
+// to the peers connecting to it instead of all the services of the server.
+type serviceListener struct {
+	net.Listener
+	services wire.ServiceFlag
+}
+
+// Accept waits for and returns the next connection to the listener, which
+// carries the services of the listener.
+//
+// This is part of the net.Listener interface.
+func (l *serviceListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &serviceConn{Conn: conn, services: l.services}, nil
+}
+
+// Ensure serviceListener implements the net.Listener interface.
+var _ net.Listener = (*serviceListener)(nil)
+
+// serviceConn is a connection accepted by a serviceListener.
+type serviceConn struct {
+	net.Conn
+	services wire.ServiceFlag
+}
+
 // broadcastMsg provides the ability to house a litecoin message to be broadcast
 // to all connected peers except specified excluded peers.
 type broadcastMsg struct {
@@ -138,6 +181,10 @@ type broadcastInventoryDel *wire.InvVect
 type relayMsg struct {
 	invVect *wire.InvVect
 	data    interface{}
+
+	// stem is set for locally submitted transactions which are relayed
+	// along the Dandelion++ stem instead of to all peers.
+	stem bool
 }
 
 // updatePeerHeightsMsg is a message sent from the blockmanager to the server
@@ -160,6 +207,10 @@ type peerState struct {
 	persistentPeers map[int32]*serverPeer
 	banned          map[string]time.Time
 	outboundGroups  map[string]int
+
+	// maxPeers is the maximum number of peers, which starts out as the
+	// configured value and may be adjusted at runtime.
+	maxPeers int
 }
 
 // Count returns the count of all known peers.
@@ -211,6 +262,7 @@ type server struct {
 	addrManager          *addrmgr.AddrManager
 	connManager          *connmgr.ConnManager
 	sigCache             *txscript.SigCache
+	scriptCache          *txscript.ScriptCache
 	hashCache            *txscript.HashCache
 	rpcServer            *rpcServer
 	syncManager          *netsync.SyncManager
@@ -236,19 +288,36 @@ type server struct {
 	// if the associated index is not enabled.  These fields are set during
 	// initial creation of the server and never changed afterwards, so they
 	// do not need to be protected for concurrent access.
-	txIndex   *indexers.TxIndex
-	addrIndex *indexers.AddrIndex
-	cfIndex   *indexers.CfIndex
+	txIndex      *indexers.TxIndex
+	addrIndex    *indexers.AddrIndex
+	watchIndex   *indexers.WatchIndex
+	cfIndex      *indexers.CfIndex
+	indexManager *indexers.Manager
 
 	// The fee estimator keeps track of how long transactions are left in
 	// the mempool before they are mined into blocks.
 	feeEstimator *mempool.FeeEstimator
 
+	// dandelion routes locally submitted transactions along a Dandelion++
+	// stem.  It is nil when Dandelion++ is disabled.
+	dandelion *dandelionRouter
+
 	// cfCheckptCaches stores a cached slice of filter headers for cfcheckpt
 	// messages for each filter type.
 	cfCheckptCaches    map[wire.FilterType][]cfHeaderKV
 	cfCheckptCachesMtx sync.RWMutex
 
+	// mwebOutputs caches the most recently fetched MWEB output set, which
+	// is served to MWEB light clients.
+	mwebOutputs    *mwebOutputSet
+	mwebOutputsMtx sync.Mutex
+
+	// rebroadcastTxns holds the hashes of the transactions pending
+	// rebroadcast so requests for any other transaction don't need to be
+	// passed to the rebroadcast handler.
+	rebroadcastTxns    map[chainhash.Hash]struct{}
+	rebroadcastTxnsMtx sync.RWMutex
+
 	// agentBlacklist is a list of blacklisted substrings by which to filter
 	// user agents.
 	agentBlacklist []string
@@ -256,6 +325,10 @@ type server struct {
 	// agentWhitelist is a list of whitelisted user agent substrings, no
 	// whitelisting will be applied if the list is empty or nil.
 	agentWhitelist []string
+
+	// misbehaviorLog keeps the most recent protocol violations, bans and
+	// disconnections of misbehaving peers for the getmisbehavinglog RPC.
+	misbehaviorLog *misbehaviorLog
 }
 
 // serverPeer extends the peer to maintain state shared by the server and
@@ -273,8 +346,11 @@ type serverPeer struct {
 	relayMtx       sync.Mutex
 	disableRelayTx bool
 	sentAddrs      bool
-	isWhitelisted  bool
+	permissions    peer.PermissionFlags
+	services       wire.ServiceFlag
 	filter         *bloom.Filter
+	filterAdds     uint32
+	filterMsgScore connmgr.DynamicBanScore
 	addressesMtx   sync.RWMutex
 	knownAddresses lru.Cache
 	banScore       connmgr.DynamicBanScore
@@ -290,6 +366,7 @@ func newServerPeer(s *server, isPersistent bool) *serverPeer {
 	return &serverPeer{
 		server:         s,
 		persistent:     isPersistent,
+		services:       s.services,
 		filter:         bloom.LoadFilter(nil),
 		knownAddresses: lru.NewCache(5000),
 		quit:           make(chan struct{}),
@@ -305,6 +382,18 @@ func (sp *serverPeer) newestBlock() (*chainhash.Hash, int32, error) {
 	return &best.Hash, best.Height, nil
 }
 
+// localFeeFilter returns the minimum fee rate, in satoshi per kilobyte, to announce
+// to the peer via a feefilter message using the format required by the
+// configuration for the peer package.  While the chain is still syncing, the
+// maximum possible fee rate is announced since any transactions relayed would
+// only be wasted bandwidth until the mempool is usable.
+func (sp *serverPeer) localFeeFilter() int64 {
+	if !sp.server.syncManager.IsCurrent() {
+		return ltcutil.MaxSatoshi
+	}
+	return sp.server.txMemPool.FeeFilter()
+}
+
 // addKnownAddresses adds the given addresses to the set of known addresses to
 // the peer to prevent sending duplicate addresses.
 func (sp *serverPeer) addKnownAddresses(addresses []*wire.NetAddressV2) {
@@ -415,14 +504,18 @@ func (sp *serverPeer) pushAddrMsg(addresses []*wire.NetAddressV2) {
 // values passed as parameters. If the resulting score exceeds half of the ban
 // threshold, a warning is logged including the reason provided. Further, if
 // the score is above the ban threshold, the peer will be banned and
-// disconnected.
-func (sp *serverPeer) addBanScore(persistent, transient uint32, reason string) bool {
+// disconnected.  The violation is recorded in the misbehavior log along with
+// the offending message, which may be nil, even when banning is disabled.
+func (sp *serverPeer) addBanScore(persistent, transient uint32, reason string, msg wire.Message) bool {
 	// No warning is logged and no score is calculated if banning is disabled.
 	if cfg.DisableBanning {
+		sp.recordMisbehavior(misbehaviorViolation, reason, msg)
 		return false
 	}
-	if sp.isWhitelisted {
-		peerLog.Debugf("Misbehaving whitelisted peer %s: %s", sp, reason)
+	if sp.HasPermission(peer.PermissionNoBan) {
+		peerLog.Debugf("Misbehaving peer %s with noban permission: %s",
+			sp, reason)
+		sp.recordMisbehavior(misbehaviorViolation, reason, msg)
 		return false
 	}
 
@@ -435,29 +528,77 @@ func (sp *serverPeer) addBanScore(persistent, transient uint32, reason string) b
 			peerLog.Warnf("Misbehaving peer %s: %s -- ban score is %d, "+
 				"it was not increased this time", sp, reason, score)
 		}
+		sp.recordMisbehavior(misbehaviorViolation, reason, msg)
 		return false
 	}
 	score := sp.banScore.Increase(persistent, transient)
+	sp.recordMisbehavior(misbehaviorViolation, reason, msg)
 	if score > warnThreshold {
 		peerLog.Warnf("Misbehaving peer %s: %s -- ban score increased to %d",
 			sp, reason, score)
 		if score > cfg.BanThreshold {
 			peerLog.Warnf("Misbehaving peer %s -- banning and disconnecting",
 				sp)
+			sp.recordMisbehavior(misbehaviorBan, fmt.Sprintf("ban "+
+				"score %d exceeds the ban threshold %d", score,
+				cfg.BanThreshold), nil)
 			sp.server.BanPeer(sp)
-			sp.Disconnect()
+			sp.disconnectMisbehaving("banned", nil)
 			return true
 		}
 	}
 	return false
 }
 
+// recordMisbehavior adds an event of the passed kind about the peer to the
+// misbehavior log of the server.  The offending message may be nil.
+func (sp *serverPeer) recordMisbehavior(kind, reason string, msg wire.Message) {
+	sp.server.misbehaviorLog.record(sp.Peer, sp.banScore.Int(), kind,
+		reason, msg)
+}
+
+// disconnectMisbehaving records the disconnection of the peer for the passed
+// reason in the misbehavior log of the server and disconnects it.  The
+// offending message may be nil.
+func (sp *serverPeer) disconnectMisbehaving(reason string, msg wire.Message) {
+	sp.recordMisbehavior(misbehaviorDisconnect, reason, msg)
+	sp.Disconnect()
+}
+
 // hasServices returns whether or not the provided advertised service flags have
 // all of the provided desired service flags set.
 func hasServices(advertised, desired wire.ServiceFlag) bool {
 	return advertised&desired == desired
 }
 
+// outboundAddrScore scores the services advertised by an address for outbound
+// connection selection.  Addresses advertising both the required and preferred
+// services score 2, those only advertising the required services score 1, and
+// all others score 0.
+func outboundAddrScore(services, required, preferred wire.ServiceFlag) int {
+	switch {
+	case !hasServices(services, required):
+		return 0
+	case !hasServices(services, preferred):
+		return 1
+	}
+	return 2
+}
+
+// minOutboundAddrScore returns the lowest outboundAddrScore accepted for an
+// outbound connection after the passed number of failed attempts to select an
+// address, so the requirements are relaxed rather than leaving outbound slots
+// empty when few known addresses meet them.
+func minOutboundAddrScore(tries int) int {
+	switch {
+	case tries < 20:
+		return 2
+	case tries < 40:
+		return 1
+	}
+	return 0
+}
+
 // OnVersion is invoked when a peer receives a version litecoin message
 // and is used to negotiate the protocol version details as well as kick start
 // the communications.
@@ -481,12 +622,14 @@ func (sp *serverPeer) OnVersion(_ *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 
 	// Ignore peers that have a protcol version that is too old.  The peer
 	// negotiation logic will disconnect it after this callback returns.
-	if msg.ProtocolVersion < int32(peer.MinAcceptableProtocolVersion) {
+	if msg.ProtocolVersion < int32(peer.MinAcceptableProtocolVersion) ||
+		msg.ProtocolVersion < int32(cfg.minProtocolVersion) {
+
 		return nil
 	}
 
-	// Reject outbound peers that are not full nodes.
-	wantServices := wire.SFNodeNetwork
+	// Reject outbound peers that do not provide the required services.
+	wantServices := cfg.requiredServices
 	if !isInbound && !hasServices(msg.Services, wantServices) {
 		missingServices := wantServices & ^msg.Services
 		srvrLog.Debugf("Rejecting peer %s with services %v due to not "+
@@ -539,12 +682,15 @@ func (sp *serverPeer) OnVerAck(_ *peer.Peer, _ *wire.MsgVerAck) {
 // pool up to the maximum inventory allowed per message.  When the peer has a
 // bloom filter loaded, the contents are filtered accordingly.
 func (sp *serverPeer) OnMemPool(_ *peer.Peer, msg *wire.MsgMemPool) {
-	// Only allow mempool requests if the server has bloom filtering
-	// enabled.
-	if sp.server.services&wire.SFNodeBloom != wire.SFNodeBloom {
+	// Only allow mempool requests if bloom filtering is advertised to the
+	// peer or the peer has explicitly been granted permission.
+	if sp.services&wire.SFNodeBloom != wire.SFNodeBloom &&
+		!sp.HasPermission(peer.PermissionMempool) {
+
 		peerLog.Debugf("peer %v sent mempool request with bloom "+
 			"filtering disabled -- disconnecting", sp)
-		sp.Disconnect()
+		sp.disconnectMisbehaving("mempool request with bloom "+
+			"filtering disabled", msg)
 		return
 	}
 
@@ -552,7 +698,7 @@ func (sp *serverPeer) OnMemPool(_ *peer.Peer, msg *wire.MsgMemPool) {
 	// The ban score accumulates and passes the ban threshold if a burst of
 	// mempool messages comes from a peer. The score decays each minute to
 	// half of its value.
-	if sp.addBanScore(0, 33, "mempool") {
+	if sp.addBanScore(0, 33, "mempool", msg) {
 		return
 	}
 
@@ -565,7 +711,22 @@ func (sp *serverPeer) OnMemPool(_ *peer.Peer, msg *wire.MsgMemPool) {
 	txDescs := txMemPool.TxDescs()
 	invMsg := wire.NewMsgInvSizeHint(uint(len(txDescs)))
 
+	feeFilter := atomic.LoadInt64(&sp.feeFilter)
 	for _, txDesc := range txDescs {
+		// Don't include transactions the peer asked not to be told
+		// about via its feefilter.
+		if feeFilter > 0 && txDesc.FeePerKB < feeFilter {
+			continue
+		}
+
+		// Don't reveal transactions which are still in the Dandelion++
+		// stem.
+		if sp.server.dandelion != nil &&
+			sp.server.dandelion.IsEmbargoed(txDesc.Tx.Hash(), sp.ID()) {
+
+			continue
+		}
+
 		// Either add all transactions when there is no bloom filter,
 		// or only the transactions that match the filter when there is
 		// one.
@@ -589,7 +750,7 @@ func (sp *serverPeer) OnMemPool(_ *peer.Peer, msg *wire.MsgMemPool) {
 // handler this does not serialize all transactions through a single thread
 // transactions don't rely on the previous one in a linear fashion like blocks.
 func (sp *serverPeer) OnTx(_ *peer.Peer, msg *wire.MsgTx) {
-	if cfg.BlocksOnly {
+	if cfg.BlocksOnly && !sp.HasPermission(peer.PermissionForceRelay) {
 		peerLog.Tracef("Ignoring tx %v from %v - blocksonly enabled",
 			msg.TxHash(), sp)
 		return
@@ -642,6 +803,16 @@ func (sp *serverPeer) OnBlock(_ *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 // accordingly.  We pass the message down to blockmanager which will call
 // QueueMessage with any appropriate responses.
 func (sp *serverPeer) OnInv(_ *peer.Peer, msg *wire.MsgInv) {
+	// Transactions in the Dandelion++ stem which are announced by a peer
+	// have been fluffed, so they no longer need to be kept secret.
+	if sp.server.dandelion != nil {
+		for _, iv := range msg.InvList {
+			if iv.Type == wire.InvTypeTx || iv.Type == wire.InvTypeWitnessTx {
+				sp.server.dandelion.Fluffed(&iv.Hash)
+			}
+		}
+	}
+
 	if !cfg.BlocksOnly {
 		if len(msg.InvList) > 0 {
 			sp.server.syncManager.QueueInv(msg, sp.Peer)
@@ -657,7 +828,8 @@ func (sp *serverPeer) OnInv(_ *peer.Peer, msg *wire.MsgInv) {
 			if sp.ProtocolVersion() >= wire.BIP0037Version {
 				peerLog.Infof("Peer %v is announcing "+
 					"transactions -- disconnecting", sp)
-				sp.Disconnect()
+				sp.disconnectMisbehaving("announced "+
+					"transactions in blocksonly mode", msg)
 				return
 			}
 			continue
@@ -680,6 +852,52 @@ func (sp *serverPeer) OnHeaders(_ *peer.Peer, msg *wire.MsgHeaders) {
 	sp.server.syncManager.QueueHeaders(msg, sp.Peer)
 }
 
+// OnMwebHeader is invoked when a peer receives a mwebheader litecoin message.
+// The message is passed down to the sync manager.
+func (sp *serverPeer) OnMwebHeader(_ *peer.Peer, msg *wire.MsgMwebHeader) {
+	sp.server.syncManager.QueueMwebHeader(msg, sp.Peer)
+}
+
+// OnMwebLeafset is invoked when a peer receives a mwebleafset litecoin message.
+// The message is passed down to the sync manager.
+func (sp *serverPeer) OnMwebLeafset(_ *peer.Peer, msg *wire.MsgMwebLeafset) {
+	sp.server.syncManager.QueueMwebLeafset(msg, sp.Peer)
+}
+
+// OnGetMwebUtxos is invoked when a peer receives a getmwebutxos litecoin
+// message.  The requested unspent MWEB outputs of the block are sent along with
+// the hashes proving them against the output root of its MWEB header.
+func (sp *serverPeer) OnGetMwebUtxos(_ *peer.Peer, msg *wire.MsgGetMwebUtxos) {
+	if msg.NumRequested == 0 || msg.NumRequested > wire.MaxMwebUtxosPerQuery {
+		peerLog.Debugf("Invalid getmwebutxos request for %d outputs "+
+			"from %v", msg.NumRequested, sp)
+		return
+	}
+
+	set, err := sp.server.fetchMwebOutputSet(&msg.BlockHash)
+	if err != nil {
+		peerLog.Debugf("Unable to fetch MWEB outputs of block %v for "+
+			"%v: %v", msg.BlockHash, sp, err)
+		return
+	}
+	utxos, err := set.mwebUtxos(msg.StartIndex, msg.NumRequested,
+		msg.OutputFormat)
+	if err != nil {
+		peerLog.Debugf("Unable to serve MWEB outputs of block %v "+
+			"starting at leaf %d to %v: %v", msg.BlockHash,
+			msg.StartIndex, sp, err)
+		return
+	}
+
+	sp.QueueMessageWithEncoding(utxos, nil, wire.LatestEncoding)
+}
+
+// OnMwebUtxos is invoked when a peer receives a mwebutxos litecoin message.
+// The message is passed down to the sync manager.
+func (sp *serverPeer) OnMwebUtxos(_ *peer.Peer, msg *wire.MsgMwebUtxos) {
+	sp.server.syncManager.QueueMwebUtxos(msg, sp.Peer)
+}
+
 // handleGetData is invoked when a peer receives a getdata litecoin message and
 // is used to deliver block and transaction information.
 func (sp *serverPeer) OnGetData(_ *peer.Peer, msg *wire.MsgGetData) {
@@ -694,7 +912,8 @@ func (sp *serverPeer) OnGetData(_ *peer.Peer, msg *wire.MsgGetData) {
 	// bursts of small requests are not penalized as that would potentially ban
 	// peers performing IBD.
 	// This incremental score decays each minute to half of its value.
-	if sp.addBanScore(0, uint32(length)*99/wire.MaxInvPerMsg, "getdata") {
+	if sp.addBanScore(0, uint32(length)*99/wire.MaxInvPerMsg, "getdata",
+		msg) {
 		return
 	}
 
@@ -720,7 +939,25 @@ func (sp *serverPeer) OnGetData(_ *peer.Peer, msg *wire.MsgGetData) {
 			err = sp.server.pushTxMsg(sp, &iv.Hash, c, waitChan, wire.LatestEncoding)
 		case wire.InvTypeTx:
 			err = sp.server.pushTxMsg(sp, &iv.Hash, c, waitChan, wire.BaseEncoding)
+		case wire.InvTypeMwebTx:
+			// Only peers advertising MWEB support are able to
+			// decode transactions along with their MWEB data.
+			if !sp.IsMwebEnabled() {
+				peerLog.Debugf("Not serving MWEB transaction %v to "+
+					"%v which does not support MWEB", iv.Hash, sp)
+				err = fmt.Errorf("peer does not support MWEB")
+				break
+			}
+			err = sp.server.pushTxMsg(sp, &iv.Hash, c, waitChan, wire.LatestEncoding)
 		case wire.InvTypeMwebBlock:
+			// Only peers advertising MWEB support are able to
+			// decode blocks along with their MWEB data.
+			if !sp.IsMwebEnabled() {
+				peerLog.Debugf("Not serving MWEB block %v to %v "+
+					"which does not support MWEB", iv.Hash, sp)
+				err = fmt.Errorf("peer does not support MWEB")
+				break
+			}
 			err = sp.server.pushBlockMsg(sp, &iv.Hash, c, waitChan, wire.LatestEncoding)
 		case wire.InvTypeWitnessBlock:
 			err = sp.server.pushBlockMsg(sp, &iv.Hash, c, waitChan, wire.LatestEncoding)
@@ -730,11 +967,21 @@ func (sp *serverPeer) OnGetData(_ *peer.Peer, msg *wire.MsgGetData) {
 			err = sp.server.pushMerkleBlockMsg(sp, &iv.Hash, c, waitChan, wire.LatestEncoding)
 		case wire.InvTypeFilteredBlock:
 			err = sp.server.pushMerkleBlockMsg(sp, &iv.Hash, c, waitChan, wire.BaseEncoding)
+		case wire.InvTypeMwebHeader:
+			err = sp.server.pushMwebHeaderMsg(sp, &iv.Hash, c, waitChan)
+		case wire.InvTypeMwebLeafset:
+			err = sp.server.pushMwebLeafsetMsg(sp, &iv.Hash, c, waitChan)
 		default:
 			peerLog.Warnf("Unknown type in inventory request %d",
 				iv.Type)
 			continue
 		}
+		if err == nil && !sp.Inbound() && (iv.Type == wire.InvTypeTx ||
+			iv.Type == wire.InvTypeWitnessTx ||
+			iv.Type == wire.InvTypeMwebTx) {
+
+			sp.server.TransactionRequested(&iv.Hash)
+		}
 		if err != nil {
 			notFound.AddInvVect(iv)
 
@@ -1156,12 +1403,17 @@ func (sp *serverPeer) OnGetCFCheckpt(_ *peer.Peer, msg *wire.MsgGetCFCheckpt) {
 	sp.QueueMessage(checkptMsg, nil)
 }
 
-// enforceNodeBloomFlag disconnects the peer if the server is not configured to
-// allow bloom filters.  Additionally, if the peer has negotiated to a protocol
+// enforceNodeBloomFlag disconnects the peer if bloom filters are not advertised
+// to it, either since the server is not configured to allow them or since its
+// listener does not advertise them, and the peer has not been granted the
+// bloomfilter permission.  Additionally, if the peer has negotiated to a protocol
 // version  that is high enough to observe the bloom filter service support bit,
 // it will be banned since it is intentionally violating the protocol.
-func (sp *serverPeer) enforceNodeBloomFlag(cmd string) bool {
-	if sp.server.services&wire.SFNodeBloom != wire.SFNodeBloom {
+func (sp *serverPeer) enforceNodeBloomFlag(msg wire.Message) bool {
+	cmd := msg.Command()
+	if sp.services&wire.SFNodeBloom != wire.SFNodeBloom &&
+		!sp.HasPermission(peer.PermissionBloomFilter) {
+
 		// Ban the peer if the protocol version is high enough that the
 		// peer is knowingly violating the protocol and banning is
 		// enabled.
@@ -1175,8 +1427,10 @@ func (sp *serverPeer) enforceNodeBloomFlag(cmd string) bool {
 
 			// Disconnect the peer regardless of whether it was
 			// banned.
-			sp.addBanScore(100, 0, cmd)
-			sp.Disconnect()
+			if !sp.addBanScore(100, 0, cmd, msg) {
+				sp.disconnectMisbehaving("unsupported "+cmd+
+					" request", msg)
+			}
 			return false
 		}
 
@@ -1184,7 +1438,22 @@ func (sp *serverPeer) enforceNodeBloomFlag(cmd string) bool {
 		// state.
 		peerLog.Debugf("%s sent an unsupported %s request -- "+
 			"disconnecting", sp, cmd)
-		sp.Disconnect()
+		sp.disconnectMisbehaving("unsupported "+cmd+" request", msg)
+		return false
+	}
+
+	return true
+}
+
+// enforceFilterMsgRate increases the bloom filter message score of the peer and
+// disconnects it when the score exceeds the per-peer limit.  It returns
+// whether the message should be processed.
+func (sp *serverPeer) enforceFilterMsgRate(msg wire.Message) bool {
+	if sp.filterMsgScore.Increase(0, 1) > maxFilterMsgScore {
+		peerLog.Debugf("%s exceeded the %s rate limit -- disconnecting",
+			sp, msg.Command())
+		sp.disconnectMisbehaving(msg.Command()+" rate limit exceeded",
+			msg)
 		return false
 	}
 
@@ -1200,7 +1469,7 @@ func (sp *serverPeer) OnFeeFilter(_ *peer.Peer, msg *wire.MsgFeeFilter) {
 	if msg.MinFee < 0 || msg.MinFee > ltcutil.MaxSatoshi {
 		peerLog.Debugf("Peer %v sent an invalid feefilter '%v' -- "+
 			"disconnecting", sp, ltcutil.Amount(msg.MinFee))
-		sp.Disconnect()
+		sp.disconnectMisbehaving("invalid feefilter", msg)
 		return
 	}
 
@@ -1210,20 +1479,34 @@ func (sp *serverPeer) OnFeeFilter(_ *peer.Peer, msg *wire.MsgFeeFilter) {
 // OnFilterAdd is invoked when a peer receives a filteradd litecoin
 // message and is used by remote peers to add data to an already loaded bloom
 // filter.  The peer will be disconnected if a filter is not loaded when this
-// message is received or the server is not configured to allow bloom filters.
+// message is received, the server is not configured to allow bloom filters, or
+// the peer exceeds the per-peer filter limits.
 func (sp *serverPeer) OnFilterAdd(_ *peer.Peer, msg *wire.MsgFilterAdd) {
 	// Disconnect and/or ban depending on the node bloom services flag and
 	// negotiated protocol version.
-	if !sp.enforceNodeBloomFlag(msg.Command()) {
+	if !sp.enforceNodeBloomFlag(msg) {
+		return
+	}
+
+	if !sp.enforceFilterMsgRate(msg) {
 		return
 	}
 
 	if !sp.filter.IsLoaded() {
 		peerLog.Debugf("%s sent a filteradd request with no filter "+
 			"loaded -- disconnecting", sp)
-		sp.Disconnect()
+		sp.disconnectMisbehaving("filteradd with no filter loaded", msg)
+		return
+	}
+
+	if sp.filterAdds >= maxFilterAddsPerFilter {
+		peerLog.Debugf("%s exceeded the maximum of %d filteradd "+
+			"requests per filter -- disconnecting", sp,
+			maxFilterAddsPerFilter)
+		sp.disconnectMisbehaving("too many filteradd requests", msg)
 		return
 	}
+	sp.filterAdds++
 
 	sp.filter.Add(msg.Data)
 }
@@ -1235,35 +1518,46 @@ func (sp *serverPeer) OnFilterAdd(_ *peer.Peer, msg *wire.MsgFilterAdd) {
 func (sp *serverPeer) OnFilterClear(_ *peer.Peer, msg *wire.MsgFilterClear) {
 	// Disconnect and/or ban depending on the node bloom services flag and
 	// negotiated protocol version.
-	if !sp.enforceNodeBloomFlag(msg.Command()) {
+	if !sp.enforceNodeBloomFlag(msg) {
+		return
+	}
+
+	if !sp.enforceFilterMsgRate(msg) {
 		return
 	}
 
 	if !sp.filter.IsLoaded() {
 		peerLog.Debugf("%s sent a filterclear request with no "+
 			"filter loaded -- disconnecting", sp)
-		sp.Disconnect()
+		sp.disconnectMisbehaving("filterclear with no filter loaded",
+			msg)
 		return
 	}
 
 	sp.filter.Unload()
+	sp.filterAdds = 0
 }
 
 // OnFilterLoad is invoked when a peer receives a filterload litecoin
 // message and it used to load a bloom filter that should be used for
 // delivering merkle blocks and associated transactions that match the filter.
 // The peer will be disconnected if the server is not configured to allow bloom
-// filters.
+// filters or the peer exceeds the per-peer filter limits.
 func (sp *serverPeer) OnFilterLoad(_ *peer.Peer, msg *wire.MsgFilterLoad) {
 	// Disconnect and/or ban depending on the node bloom services flag and
 	// negotiated protocol version.
-	if !sp.enforceNodeBloomFlag(msg.Command()) {
+	if !sp.enforceNodeBloomFlag(msg) {
+		return
+	}
+
+	if !sp.enforceFilterMsgRate(msg) {
 		return
 	}
 
 	sp.setDisableRelayTx(false)
 
 	sp.filter.Reload(msg)
+	sp.filterAdds = 0
 }
 
 // OnGetAddr is invoked when a peer receives a getaddr litecoin message
@@ -1322,7 +1616,7 @@ func (sp *serverPeer) OnAddr(_ *peer.Peer, msg *wire.MsgAddr) {
 	if len(msg.AddrList) == 0 {
 		peerLog.Errorf("Command [%s] from %s does not contain any addresses",
 			msg.Command(), sp.Peer)
-		sp.Disconnect()
+		sp.disconnectMisbehaving("empty addr message", msg)
 		return
 	}
 
@@ -1371,7 +1665,7 @@ func (sp *serverPeer) OnAddrV2(_ *peer.Peer, msg *wire.MsgAddrV2) {
 	if len(msg.AddrList) == 0 {
 		peerLog.Errorf("Command [%s] from %s does not contain any "+
 			"addresses", msg.Command(), sp.Peer)
-		sp.Disconnect()
+		sp.disconnectMisbehaving("empty addrv2 message", msg)
 		return
 	}
 
@@ -1399,9 +1693,15 @@ func (sp *serverPeer) OnAddrV2(_ *peer.Peer, msg *wire.MsgAddrV2) {
 }
 
 // OnRead is invoked when a peer receives a message and it is used to update
-// the bytes received by the server.
+// the bytes received by the server and to record the messages which could not
+// be decoded in the misbehavior log.
 func (sp *serverPeer) OnRead(_ *peer.Peer, bytesRead int, msg wire.Message, err error) {
 	sp.server.AddBytesReceived(uint64(bytesRead))
+
+	// Messages which could not be decoded are protocol violations.
+	if msgErr, ok := err.(*wire.MessageError); ok {
+		sp.recordMisbehavior(misbehaviorViolation, msgErr.Error(), nil)
+	}
 }
 
 // OnWrite is invoked when a peer sends a message and it is used to update
@@ -1434,21 +1734,22 @@ func (sp *serverPeer) OnNotFound(p *peer.Peer, msg *wire.MsgNotFound) {
 		default:
 			peerLog.Debugf("Invalid inv type '%d' in notfound message from %s",
 				inv.Type, sp)
-			sp.Disconnect()
+			sp.disconnectMisbehaving(fmt.Sprintf("invalid inv "+
+				"type %d in notfound", inv.Type), msg)
 			return
 		}
 	}
 	if numBlocks > 0 {
 		blockStr := pickNoun(uint64(numBlocks), "block", "blocks")
 		reason := fmt.Sprintf("%d %v not found", numBlocks, blockStr)
-		if sp.addBanScore(20*numBlocks, 0, reason) {
+		if sp.addBanScore(20*numBlocks, 0, reason, msg) {
 			return
 		}
 	}
 	if numTxns > 0 {
 		txStr := pickNoun(uint64(numTxns), "transaction", "transactions")
 		reason := fmt.Sprintf("%d %v not found", numTxns, txStr)
-		if sp.addBanScore(0, 10*numTxns, reason) {
+		if sp.addBanScore(0, 10*numTxns, reason, msg) {
 			return
 		}
 	}
@@ -1482,6 +1783,12 @@ func (s *server) AddRebroadcastInventory(iv *wire.InvVect, data interface{}) {
 		return
 	}
 
+	if iv.Type == wire.InvTypeTx {
+		s.rebroadcastTxnsMtx.Lock()
+		s.rebroadcastTxns[iv.Hash] = struct{}{}
+		s.rebroadcastTxnsMtx.Unlock()
+	}
+
 	s.modifyRebroadcastInv <- broadcastInventoryAdd{invVect: iv, data: data}
 }
 
@@ -1505,6 +1812,24 @@ func (s *server) relayTransactions(txns []*mempool.TxDesc) {
 	}
 }
 
+// relayLocalTransactions relays the passed transactions which were submitted to
+// this node.  They are relayed along the Dandelion++ stem when it is enabled
+// and to all connected peers otherwise.
+func (s *server) relayLocalTransactions(txns []*mempool.TxDesc) {
+	if s.dandelion == nil {
+		s.relayTransactions(txns)
+		return
+	}
+
+	for _, txD := range txns {
+		s.relayInv <- relayMsg{
+			invVect: wire.NewInvVect(wire.InvTypeTx, txD.Tx.Hash()),
+			data:    txD,
+			stem:    true,
+		}
+	}
+}
+
 // AnnounceNewTransactions generates and relays inventory vectors and notifies
 // both websocket and getblocktemplate long poll clients of the passed
 // transactions.  This function should be called whenever new transactions
@@ -1521,6 +1846,19 @@ func (s *server) AnnounceNewTransactions(txns []*mempool.TxDesc) {
 	}
 }
 
+// PeerMisbehaving records a protocol violation of the passed peer reported by
+// the sync manager in the misbehavior log.  The ban score of the peer is not
+// known to the sync manager, so it is recorded as zero.
+//
+// This is part of the netsync.PeerNotifier interface.
+func (s *server) PeerMisbehaving(p *peer.Peer, reason string, msg wire.Message, disconnected bool) {
+	kind := misbehaviorViolation
+	if disconnected {
+		kind = misbehaviorDisconnect
+	}
+	s.misbehaviorLog.record(p, 0, kind, reason, msg)
+}
+
 // Transaction has one confirmation on the main chain. Now we can mark it as no
 // longer needing rebroadcasting.
 func (s *server) TransactionConfirmed(tx *ltcutil.Tx) {
@@ -1533,6 +1871,29 @@ func (s *server) TransactionConfirmed(tx *ltcutil.Tx) {
 	s.RemoveRebroadcastInventory(iv)
 }
 
+// TransactionRequested is invoked when an outbound peer requests a transaction
+// we announced.  Outbound peers are chosen by us, so a request from one means
+// the transaction has propagated beyond this node and it no longer needs to be
+// rebroadcast.
+func (s *server) TransactionRequested(hash *chainhash.Hash) {
+	// Rebroadcasting is only necessary when the RPC server is active.
+	if s.rpcServer == nil {
+		return
+	}
+
+	// Most requested transactions were relayed for other peers, so only
+	// the ones pending rebroadcast are passed to the rebroadcast handler.
+	s.rebroadcastTxnsMtx.RLock()
+	_, ok := s.rebroadcastTxns[*hash]
+	s.rebroadcastTxnsMtx.RUnlock()
+	if !ok {
+		return
+	}
+
+	iv := wire.NewInvVect(wire.InvTypeTx, hash)
+	s.RemoveRebroadcastInventory(iv)
+}
+
 // pushTxMsg sends a tx message for the provided transaction hash to the
 // connected peer.  An error is returned if the transaction hash is not known.
 func (s *server) pushTxMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<- struct{},
@@ -1541,7 +1902,15 @@ func (s *server) pushTxMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<-
 	// Attempt to fetch the requested transaction from the pool.  A
 	// call could be made to check for existence first, but simply trying
 	// to fetch a missing transaction results in the same behavior.
+	//
+	// Transactions in the Dandelion++ stem are treated as missing for all
+	// peers but the stem peer so probing for them reveals nothing.
 	tx, err := s.txMemPool.FetchTransaction(hash)
+	if err == nil && s.dandelion != nil &&
+		s.dandelion.IsEmbargoed(hash, sp.ID()) {
+
+		err = fmt.Errorf("transaction %v is in the Dandelion++ stem", hash)
+	}
 	if err != nil {
 		peerLog.Tracef("Unable to fetch tx %v from transaction "+
 			"pool: %v", hash, err)
@@ -1689,6 +2058,74 @@ func (s *server) pushMerkleBlockMsg(sp *serverPeer, hash *chainhash.Hash,
 	return nil
 }
 
+// pushMwebHeaderMsg sends an mwebheader message for the provided block hash to
+// the connected peer.  The message proves the MWEB header of the block is
+// committed to by the HogEx transaction, which is the final transaction of the
+// block.  An error is returned if the block hash is not known or the block
+// does not carry MWEB data.
+func (s *server) pushMwebHeaderMsg(sp *serverPeer, hash *chainhash.Hash,
+	doneChan chan<- struct{}, waitChan <-chan struct{}) error {
+
+	blk, err := sp.server.chain.BlockByHash(hash)
+	if err == nil && blk.MsgBlock().MwebHeader == nil {
+		err = fmt.Errorf("block %v has no MWEB header", hash)
+	}
+	if err != nil {
+		peerLog.Tracef("Unable to fetch requested MWEB header %v: %v",
+			hash, err)
+
+		if doneChan != nil {
+			doneChan <- struct{}{}
+		}
+		return err
+	}
+
+	// Prove the inclusion of the HogEx transaction with a merkle block
+	// which matches it.
+	txns := blk.Transactions()
+	hogex := txns[len(txns)-1]
+	filter := bloom.NewFilter(1, 0, 0.000001, wire.BloomUpdateNone)
+	filter.AddHash(hogex.Hash())
+	merkle, _ := bloom.NewMerkleBlock(blk, filter)
+
+	// Once we have fetched data wait for any previous operation to finish.
+	if waitChan != nil {
+		<-waitChan
+	}
+
+	msg := wire.NewMsgMwebHeader(merkle, hogex.MsgTx(),
+		blk.MsgBlock().MwebHeader)
+	sp.QueueMessageWithEncoding(msg, doneChan, wire.LatestEncoding)
+	return nil
+}
+
+// pushMwebLeafsetMsg sends an mwebleafset message for the provided block hash
+// to the connected peer.  An error is returned if the block hash is not known
+// or the MWEB output set of the block can not be built.
+func (s *server) pushMwebLeafsetMsg(sp *serverPeer, hash *chainhash.Hash,
+	doneChan chan<- struct{}, waitChan <-chan struct{}) error {
+
+	set, err := s.fetchMwebOutputSet(hash)
+	if err != nil {
+		peerLog.Tracef("Unable to fetch requested MWEB leafset %v: %v",
+			hash, err)
+
+		if doneChan != nil {
+			doneChan <- struct{}{}
+		}
+		return err
+	}
+
+	// Once we have fetched data wait for any previous operation to finish.
+	if waitChan != nil {
+		<-waitChan
+	}
+
+	msg := wire.NewMsgMwebLeafset(hash, set.leafset.Bits)
+	sp.QueueMessageWithEncoding(msg, doneChan, wire.LatestEncoding)
+	return nil
+}
+
 // handleUpdatePeerHeight updates the heights of all peers who were known to
 // announce a block we recently accepted.
 func (s *server) handleUpdatePeerHeights(state *peerState, umsg updatePeerHeightsMsg) {
@@ -1748,7 +2185,8 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 		if time.Now().Before(banEnd) {
 			srvrLog.Debugf("Peer %s is banned for another %v - disconnecting",
 				host, time.Until(banEnd))
-			sp.Disconnect()
+			sp.disconnectMisbehaving(fmt.Sprintf("banned for "+
+				"another %v", time.Until(banEnd)), nil)
 			return false
 		}
 
@@ -1759,9 +2197,9 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 	// TODO: Check for max peers from a single IP.
 
 	// Limit max number of total peers.
-	if state.Count() >= cfg.MaxPeers {
+	if state.Count() >= state.maxPeers {
 		srvrLog.Infof("Max peers reached [%d] - disconnecting peer %s",
-			cfg.MaxPeers, sp)
+			state.maxPeers, sp)
 		sp.Disconnect()
 		// TODO: how to handle permanent peers here?
 		// they should be rescheduled.
@@ -1875,6 +2313,11 @@ func (s *server) handleBanPeerMsg(state *peerState, sp *serverPeer) {
 // handleRelayInvMsg deals with relaying inventory to peers that are not already
 // known to have it.  It is invoked from the peerHandler goroutine.
 func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
+	if msg.stem {
+		s.handleStemInvMsg(state, msg)
+		return
+	}
+
 	state.forAllPeers(func(sp *serverPeer) {
 		if !sp.Connected() {
 			return
@@ -1938,6 +2381,74 @@ func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
 	})
 }
 
+// handleStemInvMsg relays a locally submitted transaction to the Dandelion++
+// stem peer, which is chosen among the outbound peers which accept it.  The
+// transaction is relayed to all peers instead when there are none.  It is
+// invoked from the peerHandler goroutine.
+func (s *server) handleStemInvMsg(state *peerState, msg relayMsg) {
+	txD := msg.data.(*mempool.TxDesc)
+	candidates := make([]int32, 0, len(state.outboundPeers))
+	for id, sp := range state.outboundPeers {
+		if !sp.Connected() || sp.relayTxDisabled() ||
+			sp.filter.IsLoaded() {
+
+			continue
+		}
+		feeFilter := atomic.LoadInt64(&sp.feeFilter)
+		if feeFilter > 0 && txD.FeePerKB < feeFilter {
+			continue
+		}
+		candidates = append(candidates, id)
+	}
+
+	now := time.Now()
+	id, ok := s.dandelion.StemPeer(now, candidates)
+	if !ok {
+		peerLog.Debugf("No Dandelion++ stem peer for transaction %v -- "+
+			"relaying it to all peers", msg.invVect.Hash)
+		msg.stem = false
+		s.handleRelayInvMsg(state, msg)
+		return
+	}
+
+	sp := state.outboundPeers[id]
+	peerLog.Debugf("Relaying transaction %v to Dandelion++ stem peer %v",
+		msg.invVect.Hash, sp)
+	s.dandelion.Stem(now, txD, id)
+	sp.QueueInventory(msg.invVect)
+}
+
+// dandelionHandler fluffs the transactions in the Dandelion++ stem whose
+// embargo expired by relaying them to all peers.  It must be run as a
+// goroutine.
+func (s *server) dandelionHandler() {
+	ticker := time.NewTicker(dandelionTick)
+	defer ticker.Stop()
+
+out:
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, txD := range s.dandelion.Expired(now) {
+				// Transactions which were mined or evicted in the
+				// meantime are no longer relayed.
+				if !s.txMemPool.HaveTransaction(txD.Tx.Hash()) {
+					continue
+				}
+				peerLog.Debugf("Dandelion++ embargo of transaction "+
+					"%v expired -- relaying it to all peers",
+					txD.Tx.Hash())
+				s.relayTransactions([]*mempool.TxDesc{txD})
+			}
+
+		case <-s.quit:
+			break out
+		}
+	}
+
+	s.wg.Done()
+}
+
 // handleBroadcastMsg deals with broadcasting messages to peers.  It is invoked
 // from the peerHandler goroutine.
 func (s *server) handleBroadcastMsg(state *peerState, bmsg *broadcastMsg) {
@@ -1989,6 +2500,15 @@ type removeNodeMsg struct {
 	reply chan error
 }
 
+type getMaxPeersMsg struct {
+	reply chan int
+}
+
+type setMaxPeersMsg struct {
+	maxPeers int
+	reply    chan struct{}
+}
+
 // handleQuery is the central handler for all queries and commands from other
 // goroutines related to peer state.
 func (s *server) handleQuery(state *peerState, querymsg interface{}) {
@@ -2015,7 +2535,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 	case connectNodeMsg:
 		// TODO: duplicate oneshots?
 		// Limit max number of total peers.
-		if state.Count() >= cfg.MaxPeers {
+		if state.Count() >= state.maxPeers {
 			msg.reply <- errors.New("max peers reached")
 			return
 		}
@@ -2098,6 +2618,15 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		}
 
 		msg.reply <- errors.New("peer not found")
+
+	case getMaxPeersMsg:
+		msg.reply <- state.maxPeers
+
+	case setMaxPeersMsg:
+		srvrLog.Infof("Changing max peers from %d to %d",
+			state.maxPeers, msg.maxPeers)
+		state.maxPeers = msg.maxPeers
+		msg.reply <- struct{}{}
 	}
 }
 
@@ -2127,6 +2656,11 @@ func disconnectPeer(peerList map[int32]*serverPeer, compareFunc func(*serverPeer
 
 // newPeerConfig returns the configuration for the given serverPeer.
 func newPeerConfig(sp *serverPeer) *peer.Config {
+	// Peers granted the forcerelay permission may relay transactions even
+	// when running in blocks only mode.
+	disableRelayTx := cfg.BlocksOnly &&
+		sp.permissions&peer.PermissionForceRelay == 0
+
 	return &peer.Config{
 		Listeners: peer.MessageListeners{
 			OnVersion:      sp.OnVersion,
@@ -2152,6 +2686,10 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 			OnRead:         sp.OnRead,
 			OnWrite:        sp.OnWrite,
 			OnNotFound:     sp.OnNotFound,
+			OnMwebHeader:   sp.OnMwebHeader,
+			OnMwebLeafset:  sp.OnMwebLeafset,
+			OnMwebUtxos:    sp.OnMwebUtxos,
+			OnGetMwebUtxos: sp.OnGetMwebUtxos,
 
 			// Note: The reference client currently bans peers that send alerts
 			// not signed with its key.  We could verify against their key, but
@@ -2160,15 +2698,18 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 			OnAlert: nil,
 		},
 		NewestBlock:         sp.newestBlock,
+		FeeFilter:           sp.localFeeFilter,
 		HostToNetAddress:    sp.server.addrManager.HostToNetAddress,
 		Proxy:               cfg.Proxy,
 		UserAgentName:       userAgentName,
 		UserAgentVersion:    userAgentVersion,
 		UserAgentComments:   cfg.UserAgentComments,
 		ChainParams:         sp.server.chainParams,
-		Services:            sp.server.services,
-		DisableRelayTx:      cfg.BlocksOnly,
+		Services:            sp.services,
+		DisableRelayTx:      disableRelayTx,
+		Permissions:         sp.permissions,
 		ProtocolVersion:     peer.MaxProtocolVersion,
+		MinProtocolVersion:  cfg.minProtocolVersion,
 		TrickleInterval:     cfg.TrickleInterval,
 		DisableStallHandler: cfg.DisableStallHandler,
 	}
@@ -2180,7 +2721,10 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 // for disconnection.
 func (s *server) inboundPeerConnected(conn net.Conn) {
 	sp := newServerPeer(s, false)
-	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
+	sp.permissions = whitelistPermissions(conn.RemoteAddr())
+	if sc, ok := conn.(*serviceConn); ok {
+		sp.services = sc.services
+	}
 	sp.Peer = peer.NewInboundPeer(newPeerConfig(sp))
 	sp.AssociateConnection(conn)
 	go s.peerDoneHandler(sp)
@@ -2193,6 +2737,7 @@ func (s *server) inboundPeerConnected(conn net.Conn) {
 // manager of the attempt.
 func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
 	sp := newServerPeer(s, c.Permanent)
+	sp.permissions = whitelistPermissions(conn.RemoteAddr())
 	p, err := peer.NewOutboundPeer(newPeerConfig(sp), c.Addr.String())
 	if err != nil {
 		srvrLog.Debugf("Cannot create outbound peer %s: %v", c.Addr, err)
@@ -2206,7 +2751,6 @@ func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
 	}
 	sp.Peer = p
 	sp.connReq = c
-	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
 	sp.AssociateConnection(conn)
 	go s.peerDoneHandler(sp)
 }
@@ -2252,11 +2796,19 @@ func (s *server) peerHandler() {
 		outboundPeers:   make(map[int32]*serverPeer),
 		banned:          make(map[string]time.Time),
 		outboundGroups:  make(map[string]int),
+		maxPeers:        cfg.MaxPeers,
 	}
 
 	if !cfg.DisableDNSSeed {
 		// Add peers discovered through DNS to the address manager.
-		connmgr.SeedFromDNS(activeNetParams.Params, defaultRequiredServices,
+		// Ask the seeds which support filtering for peers serving MWEB
+		// blocks when they are preferred, so the outbound slots are
+		// filled with them rather than legacy nodes.
+		seedServices := cfg.requiredServices
+		if hasServices(cfg.preferredServices, wire.SFNodeMWEB) {
+			seedServices |= wire.SFNodeMWEB
+		}
+		connmgr.SeedFromDNS(activeNetParams.Params, seedServices,
 			ltcdLookup, func(addrs []*wire.NetAddressV2) {
 				// Litecoind uses a lookup of the dns seeder here. This
 				// is rather strange since the values looked up by the
@@ -2309,8 +2861,13 @@ out:
 		}
 	}
 
+	// Stop the connection manager first so no new peers are accepted, then
+	// the sync manager so no more blocks are processed before the state
+	// which depends on them is persisted.  The address manager saves the
+	// known peers when it is stopped.
 	s.connManager.Stop()
 	s.syncManager.Stop()
+	s.saveFeeEstimator()
 	s.addrManager.Stop()
 
 	// Drain channels before exiting so nothing is left waiting around
@@ -2366,6 +2923,39 @@ func (s *server) ConnectedCount() int32 {
 	return <-replyChan
 }
 
+// MaxPeers returns the maximum number of peers the server accepts.
+func (s *server) MaxPeers() int {
+	replyChan := make(chan int)
+
+	s.query <- getMaxPeersMsg{reply: replyChan}
+
+	return <-replyChan
+}
+
+// SetMaxPeers changes the maximum number of peers the server accepts along
+// with the number of outbound peers it targets.  Peers which are already
+// connected are not disconnected when the number of peers exceeds the new
+// maximum, instead no new peers are accepted until enough of them disconnected
+// on their own.
+func (s *server) SetMaxPeers(maxPeers int) {
+	replyChan := make(chan struct{})
+
+	s.query <- setMaxPeersMsg{maxPeers: maxPeers, reply: replyChan}
+
+	<-replyChan
+
+	s.connManager.SetTargetOutbound(targetOutboundPeers(maxPeers))
+}
+
+// targetOutboundPeers returns the number of outbound peers to target for the
+// passed maximum number of peers.
+func targetOutboundPeers(maxPeers int) uint32 {
+	if maxPeers < defaultTargetOutbound {
+		return uint32(maxPeers)
+	}
+	return defaultTargetOutbound
+}
+
 // OutboundGroupCount returns the number of peers connected to the given
 // outbound group key.
 func (s *server) OutboundGroupCount(key string) int {
@@ -2405,13 +2995,92 @@ func (s *server) UpdatePeerHeights(latestBlkHash *chainhash.Hash, latestHeight i
 	}
 }
 
+// loadUnbroadcastTxns reads the user submitted transactions that were still
+// pending rebroadcast when the server last shut down and attempts to add them
+// back to the memory pool.  The transactions that are accepted are returned
+// keyed by their inventory vectors.
+func (s *server) loadUnbroadcastTxns() map[wire.InvVect]interface{} {
+	pendingInvs := make(map[wire.InvVect]interface{})
+
+	path := filepath.Join(cfg.DataDir, unbroadcastFilename)
+	txns, err := loadUnbroadcastTxns(path)
+	if err != nil {
+		srvrLog.Warnf("Unable to load unbroadcast transactions from "+
+			"%s: %v", path, err)
+		return pendingInvs
+	}
+
+	for _, msgTx := range txns {
+		// Transactions that are no longer valid, most commonly because
+		// they were confirmed while the node was offline, are simply
+		// dropped.
+		tx := ltcutil.NewTx(msgTx)
+		acceptedTxs, err := s.txMemPool.ProcessTransaction(tx, false,
+			false, 0)
+		if err != nil {
+			srvrLog.Debugf("Dropping unbroadcast transaction %v: %v",
+				tx.Hash(), err)
+			continue
+		}
+
+		iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
+		pendingInvs[*iv] = acceptedTxs[0]
+	}
+	if len(pendingInvs) > 0 {
+		srvrLog.Infof("Loaded %d unbroadcast transactions",
+			len(pendingInvs))
+	}
+
+	return pendingInvs
+}
+
+// saveUnbroadcastTxns persists the user submitted transactions that are still
+// pending rebroadcast so they survive a restart.
+func (s *server) saveUnbroadcastTxns(pendingInvs map[wire.InvVect]interface{}) {
+	txns := make([]*wire.MsgTx, 0, len(pendingInvs))
+	for _, data := range pendingInvs {
+		if txD, ok := data.(*mempool.TxDesc); ok {
+			txns = append(txns, txD.Tx.MsgTx())
+		}
+	}
+
+	path := filepath.Join(cfg.DataDir, unbroadcastFilename)
+	if err := saveUnbroadcastTxns(path, txns); err != nil {
+		srvrLog.Warnf("Unable to save unbroadcast transactions to "+
+			"%s: %v", path, err)
+	}
+}
+
+// rebroadcastInventory relays the passed pending inventory to all connected
+// peers.  Transactions which are still in the Dandelion++ stem are skipped since
+// relaying them to all peers would reveal their origin.  They are rebroadcast
+// once they were fluffed.
+func (s *server) rebroadcastInventory(pendingInvs map[wire.InvVect]interface{}) {
+	for iv, data := range pendingInvs {
+		if s.dandelion != nil && iv.Type == wire.InvTypeTx &&
+			s.dandelion.InStem(&iv.Hash) {
+
+			continue
+		}
+		ivCopy := iv
+		s.RelayInventory(&ivCopy, data)
+	}
+}
+
 // rebroadcastHandler keeps track of user submitted inventories that we have
-// sent out but have not yet made it into a block. We periodically rebroadcast
-// them in case our peers restarted or otherwise lost track of them.
+// sent out but have not yet made it into a block or been requested by an
+// outbound peer. We periodically rebroadcast them in case our peers restarted
+// or otherwise lost track of them.  The pending inventory is persisted across
+// restarts.
 func (s *server) rebroadcastHandler() {
 	// Wait 5 min before first tx rebroadcast.
 	timer := time.NewTimer(5 * time.Minute)
-	pendingInvs := make(map[wire.InvVect]interface{})
+	pendingInvs := s.loadUnbroadcastTxns()
+	s.rebroadcastTxnsMtx.Lock()
+	for iv := range pendingInvs {
+		s.rebroadcastTxns[iv.Hash] = struct{}{}
+	}
+	s.rebroadcastTxnsMtx.Unlock()
 
 out:
 	for {
@@ -2422,19 +3091,22 @@ out:
 			case broadcastInventoryAdd:
 				pendingInvs[*msg.invVect] = msg.data
 
-			// When an InvVect has been added to a block, we can
-			// now remove it, if it was present.
+			// When an InvVect has been added to a block or was
+			// requested by an outbound peer, we can now remove it,
+			// if it was present.
 			case broadcastInventoryDel:
 				delete(pendingInvs, *msg)
+				if msg.Type == wire.InvTypeTx {
+					s.rebroadcastTxnsMtx.Lock()
+					delete(s.rebroadcastTxns, msg.Hash)
+					s.rebroadcastTxnsMtx.Unlock()
+				}
 			}
 
 		case <-timer.C:
 			// Any inventory we have has not made it into a block
 			// yet. We periodically resubmit them until they have.
-			for iv, data := range pendingInvs {
-				ivCopy := iv
-				s.RelayInventory(&ivCopy, data)
-			}
+			s.rebroadcastInventory(pendingInvs)
 
 			// Process at a random time up to 30mins (in seconds)
 			// in the future.
@@ -2447,6 +3119,7 @@ out:
 	}
 
 	timer.Stop()
+	s.saveUnbroadcastTxns(pendingInvs)
 
 	// Drain channels before exiting so nothing is left waiting around
 	// to send.
@@ -2470,14 +3143,16 @@ func (s *server) Start() {
 
 	srvrLog.Trace("Starting server")
 
-	// Server startup time. Used for the uptime command for uptime calculation.
-	s.startupTime = time.Now().Unix()
-
 	// Start the peer handler which in turn starts the address and block
 	// managers.
 	s.wg.Add(1)
 	go s.peerHandler()
 
+	// Catch up any optional indexes which are behind the best chain.
+	if s.indexManager != nil {
+		s.indexManager.Start()
+	}
+
 	if s.nat != nil {
 		s.wg.Add(1)
 		go s.upnpUpdateThread()
@@ -2493,6 +3168,14 @@ func (s *server) Start() {
 		s.rpcServer.Start()
 	}
 
+	if s.dandelion != nil {
+		s.wg.Add(1)
+		go s.dandelionHandler()
+	}
+
+	s.wg.Add(1)
+	go s.diskSpaceHandler()
+
 	// Start the CPU miner if generation is enabled.
 	if cfg.Generate {
 		s.cpuMiner.Start()
@@ -2518,15 +3201,15 @@ func (s *server) Stop() error {
 		s.rpcServer.Stop()
 	}
 
-	// Save fee estimator state in the database.
-	s.db.Update(func(tx database.Tx) error {
-		metadata := tx.Metadata()
-		metadata.Put(mempool.EstimateFeeDatabaseKey, s.feeEstimator.Save())
-
-		return nil
-	})
+	// Stop catching up the optional indexes.
+	if s.indexManager != nil {
+		s.indexManager.Stop()
+	}
 
-	// Signal the remaining goroutines to quit.
+	// Signal the remaining goroutines to quit.  The peer handler stops the
+	// sync manager and persists the fee estimator and address manager
+	// state, while the rebroadcast handler persists the transactions which
+	// are pending rebroadcast.
 	close(s.quit)
 	return nil
 }
@@ -2536,6 +3219,47 @@ func (s *server) WaitForShutdown() {
 	s.wg.Wait()
 }
 
+// Shutdown stops the server and blocks until all of its subsystems have
+// stopped and persisted their state, or the passed context is done.  The
+// teardown happens in order: the CPU miner, RPC server and index catch up are
+// stopped first, then the peers are disconnected and the sync manager is stopped so no more
+// blocks are accepted, and finally the fee estimator, peer addresses and
+// pending rebroadcast transactions are saved.
+//
+// The error of the context is returned when it is done before the teardown
+// completes.  The caller is expected to close the database regardless, since
+// any block still being processed at that point holds a database transaction
+// which closing the database waits for.
+func (s *server) Shutdown(ctx context.Context) error {
+	s.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		s.WaitForShutdown()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// saveFeeEstimator saves the state of the fee estimator in the database so it
+// can be restored on the next start.
+func (s *server) saveFeeEstimator() {
+	err := s.db.Update(func(tx database.Tx) error {
+		metadata := tx.Metadata()
+		return metadata.Put(mempool.EstimateFeeDatabaseKey,
+			s.feeEstimator.Save())
+	})
+	if err != nil {
+		srvrLog.Errorf("Unable to save fee estimator state: %v", err)
+	}
+}
+
 // ScheduleShutdown schedules a server shutdown after the specified duration.
 // It also dynamically adjusts how often to warn the server is going down based
 // on remaining duration.
@@ -2699,6 +3423,23 @@ func setupRPCListeners() ([]net.Listener, error) {
 			MinVersion:   tls.VersionTLS12,
 		}
 
+		// Require the clients to present a certificate signed by one of
+		// the configured CAs when client certificate authentication is
+		// enabled.
+		if cfg.RPCClientCA != "" {
+			pem, err := ioutil.ReadFile(cfg.RPCClientCA)
+			if err != nil {
+				return nil, err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no CA certificates found "+
+					"in %s", cfg.RPCClientCA)
+			}
+			tlsConfig.ClientCAs = pool
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+
 		// Change the standard net.Listen function to the tls one.
 		listenFunc = func(net string, laddr string) (net.Listener, error) {
 			return tls.Listen(net, laddr, &tlsConfig)
@@ -2731,7 +3472,7 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 	interrupt <-chan struct{}) (*server, error) {
 
 	services := defaultServices
-	if cfg.NoPeerBloomFilters {
+	if !cfg.PeerBloomFilters {
 		services &^= wire.SFNodeBloom
 	}
 	if cfg.NoCFilters {
@@ -2774,16 +3515,25 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 		broadcast:            make(chan broadcastMsg, cfg.MaxPeers),
 		quit:                 make(chan struct{}),
 		modifyRebroadcastInv: make(chan interface{}),
+		rebroadcastTxns:      make(map[chainhash.Hash]struct{}),
 		peerHeightsUpdate:    make(chan updatePeerHeightsMsg),
 		nat:                  nat,
 		db:                   db,
 		timeSource:           blockchain.NewMedianTime(),
 		services:             services,
+		startupTime:          time.Now().Unix(),
 		sigCache:             txscript.NewSigCache(cfg.SigCacheMaxSize),
+		scriptCache:          txscript.NewScriptCache(cfg.ScriptCacheMaxSize),
 		hashCache:            txscript.NewHashCache(cfg.SigCacheMaxSize),
 		cfCheckptCaches:      make(map[wire.FilterType][]cfHeaderKV),
 		agentBlacklist:       agentBlacklist,
 		agentWhitelist:       agentWhitelist,
+		misbehaviorLog:       newMisbehaviorLog(maxMisbehaviorEvents),
+	}
+	if cfg.Dandelion {
+		srvrLog.Info("Dandelion++ relay of local transactions is enabled")
+		s.dandelion = newDandelionRouter(cfg.DandelionEpoch,
+			cfg.DandelionEmbargo)
 	}
 
 	// Create the transaction and address indexes if needed.
@@ -2812,6 +3562,11 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 		s.addrIndex = indexers.NewAddrIndex(db, chainParams)
 		indexes = append(indexes, s.addrIndex)
 	}
+	if cfg.WatchOnly {
+		indxLog.Info("Watch-only index is enabled")
+		s.watchIndex = indexers.NewWatchIndex(db)
+		indexes = append(indexes, s.watchIndex)
+	}
 	if !cfg.NoCFilters {
 		indxLog.Info("Committed filter index is enabled")
 		s.cfIndex = indexers.NewCfIndex(db, chainParams)
@@ -2819,9 +3574,12 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 	}
 
 	// Create an index manager if any of the optional indexes are enabled.
+	// Indexes which are behind the best chain, such as those enabled on a
+	// synced node, are caught up in the background once the server starts.
 	var indexManager blockchain.IndexManager
 	if len(indexes) > 0 {
-		indexManager = indexers.NewManager(db, indexes)
+		s.indexManager = indexers.NewBackgroundManager(db, indexes)
+		indexManager = s.indexManager
 	}
 
 	// Merge given checkpoints with the default ones unless they are disabled.
@@ -2833,15 +3591,18 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 	// Create a new block chain instance with the appropriate configuration.
 	var err error
 	s.chain, err = blockchain.New(&blockchain.Config{
-		DB:           s.db,
-		Interrupt:    interrupt,
-		ChainParams:  s.chainParams,
-		Checkpoints:  checkpoints,
-		TimeSource:   s.timeSource,
-		SigCache:     s.sigCache,
-		IndexManager: indexManager,
-		HashCache:    s.hashCache,
-		Prune:        cfg.Prune * 1024 * 1024,
+		DB:             s.db,
+		Interrupt:      interrupt,
+		ChainParams:    s.chainParams,
+		Checkpoints:    checkpoints,
+		TimeSource:     s.timeSource,
+		SigCache:       s.sigCache,
+		ScriptCache:    s.scriptCache,
+		IndexManager:   indexManager,
+		HashCache:      s.hashCache,
+		Prune:          cfg.Prune * 1024 * 1024,
+		StaleForkDepth: cfg.PruneStaleForks,
+		StaleForkQuota: cfg.PruneStaleForkQuota * 1024 * 1024,
 	})
 	if err != nil {
 		return nil, err
@@ -2884,7 +3645,7 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 			FreeTxRelayLimit:     cfg.FreeTxRelayLimit,
 			MaxOrphanTxs:         cfg.MaxOrphanTxs,
 			MaxOrphanTxSize:      defaultMaxOrphanTxSize,
-			MaxSigOpCostPerTx:    blockchain.MaxBlockSigOpsCost / 4,
+			MaxSigOpCostPerTx:    int(blockchain.BlockSigOpsCostLimit(chainParams) / 4),
 			MinRelayTxFee:        cfg.minRelayTxFee,
 			MaxTxVersion:         2,
 			RejectReplacement:    cfg.RejectReplacement,
@@ -2898,9 +3659,15 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 		},
 		IsDeploymentActive: s.chain.IsDeploymentActive,
 		SigCache:           s.sigCache,
+		ScriptCache:        s.scriptCache,
 		HashCache:          s.hashCache,
 		AddrIndex:          s.addrIndex,
 		FeeEstimator:       s.feeEstimator,
+		TxRemoved: func(txD *mempool.TxDesc, reason mempool.RemovalReason) {
+			if s.rpcServer != nil {
+				s.rpcServer.NotifyRemovedTransaction(txD, reason)
+			}
+		},
 	}
 	s.txMemPool = mempool.New(&txC)
 
@@ -2923,16 +3690,17 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 	// NOTE: The CPU miner relies on the mempool, so the mempool has to be
 	// created before calling the function to create the CPU miner.
 	policy := mining.Policy{
-		BlockMinWeight:    cfg.BlockMinWeight,
-		BlockMaxWeight:    cfg.BlockMaxWeight,
-		BlockMinSize:      cfg.BlockMinSize,
-		BlockMaxSize:      cfg.BlockMaxSize,
-		BlockPrioritySize: cfg.BlockPrioritySize,
-		TxMinFreeFee:      cfg.minRelayTxFee,
+		BlockMinWeight:       cfg.BlockMinWeight,
+		BlockMaxWeight:       cfg.BlockMaxWeight,
+		BlockMinSize:         cfg.BlockMinSize,
+		BlockMaxSize:         cfg.BlockMaxSize,
+		BlockPrioritySize:    cfg.BlockPrioritySize,
+		TxMinFreeFee:         cfg.minRelayTxFee,
+		CoinbaseReservedSize: cfg.CoinbaseReservedSize,
 	}
 	blockTemplateGenerator := mining.NewBlkTmplGenerator(&policy,
 		s.chainParams, s.txMemPool, s.chain, s.timeSource,
-		s.sigCache, s.hashCache)
+		s.sigCache, s.scriptCache, s.hashCache)
 	s.cpuMiner = cpuminer.New(&cpuminer.Config{
 		ChainParams:            chainParams,
 		BlockTemplateGenerator: blockTemplateGenerator,
@@ -2940,6 +3708,8 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 		ProcessBlock:           s.syncManager.ProcessBlock,
 		ConnectedCount:         s.ConnectedCount,
 		IsCurrent:              s.syncManager.IsCurrent,
+		SignetSigner: mining.NewSignetKeySigner(chainParams,
+			cfg.signetMiningKeys),
 	})
 
 	// Only setup a function to return new addresses to connect to when
@@ -2968,6 +3738,16 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 					continue
 				}
 
+				// Favor addresses advertising the preferred services
+				// and skip those without the required services until
+				// many attempts have failed, since the services of
+				// addresses learned from other peers may be stale.
+				score := outboundAddrScore(addr.Services(),
+					cfg.requiredServices, cfg.preferredServices)
+				if score < minOutboundAddrScore(tries) {
+					continue
+				}
+
 				// only allow recent nodes (10mins) after we failed 30
 				// times
 				if tries < 30 && time.Since(addr.LastAttempt()) < 10*time.Minute {
@@ -2980,11 +3760,18 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 					continue
 				}
 
+				// Skip addresses of the networks outbound
+				// connections are not allowed to by --onlynet.
+				addrString := addrmgr.NetAddressKey(addr.NetAddress())
+				netAddr, err := addrStringToNetAddr(addrString)
+				if err == nil && !s.connManager.NetworkAllowed(netAddr) {
+					continue
+				}
+
 				// Mark an attempt for the valid address.
 				s.addrManager.Attempt(addr.NetAddress())
 
-				addrString := addrmgr.NetAddressKey(addr.NetAddress())
-				return addrStringToNetAddr(addrString)
+				return netAddr, err
 			}
 
 			return nil, errors.New("no valid connect address")
@@ -2992,18 +3779,15 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 	}
 
 	// Create a connection manager.
-	targetOutbound := defaultTargetOutbound
-	if cfg.MaxPeers < targetOutbound {
-		targetOutbound = cfg.MaxPeers
-	}
 	cmgr, err := connmgr.New(&connmgr.Config{
 		Listeners:      listeners,
 		OnAccept:       s.inboundPeerConnected,
 		RetryDuration:  connectionRetryInterval,
-		TargetOutbound: uint32(targetOutbound),
+		TargetOutbound: targetOutboundPeers(cfg.MaxPeers),
 		Dial:           ltcdDial,
 		OnConnection:   s.outboundPeerConnected,
 		GetNewAddress:  newAddressFunc,
+		OnlyNets:       cfg.OnlyNets,
 	})
 	if err != nil {
 		return nil, err
@@ -3043,17 +3827,24 @@ func newServer(listenAddrs, agentBlacklist, agentWhitelist []string,
 			StartupTime:  s.startupTime,
 			ConnMgr:      &rpcConnManager{&s},
 			SyncMgr:      &rpcSyncMgr{&s, s.syncManager},
+			Services:     s.services,
 			TimeSource:   s.timeSource,
 			Chain:        s.chain,
 			ChainParams:  chainParams,
 			DB:           db,
 			TxMemPool:    s.txMemPool,
+			SigCache:     s.sigCache,
+			ScriptCache:  s.scriptCache,
 			Generator:    blockTemplateGenerator,
 			CPUMiner:     s.cpuMiner,
 			TxIndex:      s.txIndex,
 			AddrIndex:    s.addrIndex,
+			WatchIndex:   s.watchIndex,
 			CfIndex:      s.cfIndex,
+			IndexManager: s.indexManager,
 			FeeEstimator: s.feeEstimator,
+
+			MisbehaviorLog: s.misbehaviorLog,
 		})
 		if err != nil {
 			return nil, err
@@ -3079,6 +3870,16 @@ func initListeners(amgr *addrmgr.AddrManager, listenAddrs []string, services wir
 		return nil, nil, err
 	}
 
+	// Listeners may only advertise a subset of the services of the server
+	// to their peers.
+	for addr, listenerServices := range cfg.listenerServices {
+		if listenerServices&^services != 0 {
+			return nil, nil, fmt.Errorf("listener %s advertises "+
+				"services %v which are not offered by the server "+
+				"(%v)", addr, listenerServices&^services, services)
+		}
+	}
+
 	listeners := make([]net.Listener, 0, len(netAddrs))
 	for _, addr := range netAddrs {
 		listener, err := net.Listen(addr.Network(), addr.String())
@@ -3086,6 +3887,14 @@ func initListeners(amgr *addrmgr.AddrManager, listenAddrs []string, services wir
 			srvrLog.Warnf("Can't listen on %s: %v", addr, err)
 			continue
 		}
+		if listenerServices, ok := cfg.listenerServices[addr.String()]; ok {
+			srvrLog.Infof("Advertising services %v to peers of %s",
+				listenerServices, addr)
+			listener = &serviceListener{
+				Listener: listener,
+				services: listenerServices,
+			}
+		}
 		listeners = append(listeners, listener)
 	}
 
@@ -3137,7 +3946,11 @@ func initListeners(amgr *addrmgr.AddrManager, listenAddrs []string, services wir
 		// Add bound addresses to address manager to be advertised to peers.
 		for _, listener := range listeners {
 			addr := listener.Addr().String()
-			err := addLocalAddress(amgr, addr, services)
+			addrServices := services
+			if sl, ok := listener.(*serviceListener); ok {
+				addrServices = sl.services
+			}
+			err := addLocalAddress(amgr, addr, addrServices)
 			if err != nil {
 				amgrLog.Warnf("Skipping bound address %s: %v", addr, err)
 			}
@@ -3265,30 +4078,31 @@ func dynamicTickDuration(remaining time.Duration) time.Duration {
 	return time.Hour
 }
 
-// isWhitelisted returns whether the IP address is included in the whitelisted
-// networks and IPs.
-func isWhitelisted(addr net.Addr) bool {
+// whitelistPermissions returns the combined permissions granted by all of the
+// whitelisted networks and IPs that include the IP address.
+func whitelistPermissions(addr net.Addr) peer.PermissionFlags {
 	if len(cfg.whitelists) == 0 {
-		return false
+		return peer.PermissionNone
 	}
 
 	host, _, err := net.SplitHostPort(addr.String())
 	if err != nil {
 		srvrLog.Warnf("Unable to SplitHostPort on '%s': %v", addr, err)
-		return false
+		return peer.PermissionNone
 	}
 	ip := net.ParseIP(host)
 	if ip == nil {
 		srvrLog.Warnf("Unable to parse IP '%s'", addr)
-		return false
+		return peer.PermissionNone
 	}
 
-	for _, ipnet := range cfg.whitelists {
-		if ipnet.Contains(ip) {
-			return true
+	permissions := peer.PermissionNone
+	for _, wl := range cfg.whitelists {
+		if wl.ipnet.Contains(ip) {
+			permissions |= wl.permissions
 		}
 	}
-	return false
+	return permissions
 }
 
 // checkpointSorter implements sort.Interface to allow a slice of checkpoints to
@@ -0,0 +1,54 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestOutboundAddrScore ensures addresses are scored by the required and
+// preferred services they advertise and that the minimum accepted score is
+// relaxed as attempts to select an address fail.
+func TestOutboundAddrScore(t *testing.T) {
+	required := wire.SFNodeNetwork | wire.SFNodeWitness
+	preferred := wire.ServiceFlag(wire.SFNodeMWEB)
+
+	tests := []struct {
+		services wire.ServiceFlag
+		want     int
+	}{
+		{0, 0},
+		{wire.SFNodeNetwork, 0},
+		{wire.SFNodeNetwork | wire.SFNodeMWEB, 0},
+		{required, 1},
+		{required | wire.SFNodeBloom, 1},
+		{required | preferred, 2},
+	}
+	for _, test := range tests {
+		got := outboundAddrScore(test.services, required, preferred)
+		if got != test.want {
+			t.Errorf("services %v: got score %d, want %d",
+				test.services, got, test.want)
+		}
+	}
+
+	// Without preferred services, every address with the required
+	// services has the top score.
+	if got := outboundAddrScore(required, required, 0); got != 2 {
+		t.Errorf("no preferred services: got score %d, want 2", got)
+	}
+
+	for _, test := range []struct {
+		tries int
+		want  int
+	}{{0, 2}, {19, 2}, {20, 1}, {39, 1}, {40, 0}, {99, 0}} {
+		if got := minOutboundAddrScore(test.tries); got != test.want {
+			t.Errorf("tries %d: got minimum score %d, want %d",
+				test.tries, got, test.want)
+		}
+	}
+}
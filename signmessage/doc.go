@@ -0,0 +1,33 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package signmessage implements signing messages with the private key of an
+address and verifying the signatures, which proves the ownership of the address.
+
+Messages are prefixed with MessageMagic and double SHA-256 hashed before they
+are signed, which prevents a signed message from being a valid transaction.
+Signatures are 65 byte compact ECDSA signatures encoded in base64, from which
+the public key of the signer is recovered during verification.
+
+# Address Types
+
+Besides pay-to-pubkey-hash addresses, messages can be signed for the single key
+segwit addresses, which are pay-to-witness-pubkey-hash addresses and
+pay-to-witness-pubkey-hash addresses nested in pay-to-script-hash addresses.
+This is a subset of BIP 322 which only covers the addresses whose ownership is
+proven by a single key.  The first byte of the signature identifies the type of
+the address as described by BIP 137:
+
+  - 27-30: pay-to-pubkey-hash with an uncompressed key
+  - 31-34: pay-to-pubkey-hash with a compressed key
+  - 35-38: pay-to-witness-pubkey-hash nested in pay-to-script-hash
+  - 39-42: pay-to-witness-pubkey-hash
+
+Since some signers always use the pay-to-pubkey-hash range, verification only
+relies on the type of the address being verified, so a signature is valid for
+any address of the recovered key regardless of the type its first byte
+identifies.
+*/
+package signmessage
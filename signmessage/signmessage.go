@@ -0,0 +1,189 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package signmessage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/btcec/v2/ecdsa"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// MessageMagic is the text signed along with every message to signify that a
+// signed message follows and to prevent inadvertently signing a transaction.
+const MessageMagic = "Doriancoin Signed Message:\n"
+
+// signatureLen is the length of a compact signature.
+const signatureLen = 65
+
+// compactSigMagicOffset is the value of the first byte of a compact signature
+// of an uncompressed key with a recovery code of zero.
+const compactSigMagicOffset = 27
+
+var (
+	// ErrUnsupportedAddress describes an error where a message is verified
+	// for an address whose ownership is not proven by a single key, such
+	// as a pay-to-script-hash address of a multisig script.
+	ErrUnsupportedAddress = errors.New("address does not refer to a key")
+
+	// ErrMalformedSignature describes an error where a signature is not a
+	// base64 encoded compact signature.
+	ErrMalformedSignature = errors.New("malformed signature")
+
+	// ErrUncompressedSegwit describes an error where a message is signed
+	// for a segwit address with an uncompressed key, which can't be used
+	// with segwit.
+	ErrUncompressedSegwit = errors.New("segwit addresses require " +
+		"compressed keys")
+)
+
+// AddressType identifies the type of address a message is signed for.
+type AddressType byte
+
+const (
+	// P2PKH is a pay-to-pubkey-hash address.
+	P2PKH AddressType = iota
+
+	// P2SHP2WPKH is a pay-to-witness-pubkey-hash address nested in a
+	// pay-to-script-hash address.
+	P2SHP2WPKH
+
+	// P2WPKH is a pay-to-witness-pubkey-hash address.
+	P2WPKH
+)
+
+// addressTypeStrings is a map of address types back to their constant names
+// for pretty printing.
+var addressTypeStrings = map[AddressType]string{
+	P2PKH:      "P2PKH",
+	P2SHP2WPKH: "P2SHP2WPKH",
+	P2WPKH:     "P2WPKH",
+}
+
+// String returns the AddressType in human-readable form.
+func (t AddressType) String() string {
+	if s, ok := addressTypeStrings[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown AddressType (%d)", byte(t))
+}
+
+// Hash returns the hash of the passed message which is signed, which is the
+// double SHA-256 hash of the message prefixed with MessageMagic.
+func Hash(message string) []byte {
+	var buf bytes.Buffer
+	wire.WriteVarString(&buf, 0, MessageMagic)
+	wire.WriteVarString(&buf, 0, message)
+	return chainhash.DoubleHashB(buf.Bytes())
+}
+
+// Sign signs the passed message with the passed private key for an address of
+// the passed type and returns the base64 encoded signature.  The compressed
+// flag specifies whether the address is derived from the compressed public key,
+// which is required by the segwit address types.
+func Sign(key *btcec.PrivateKey, compressed bool, addrType AddressType,
+	message string) (string, error) {
+
+	if addrType != P2PKH && !compressed {
+		return "", ErrUncompressedSegwit
+	}
+
+	sig, err := ecdsa.SignCompact(key, Hash(message), compressed)
+	if err != nil {
+		return "", err
+	}
+
+	// The signatures of compressed keys use the range of the pay-to-pubkey-
+	// hash addresses of compressed keys, which is followed by the ranges
+	// of the segwit address types.
+	switch addrType {
+	case P2PKH:
+	case P2SHP2WPKH:
+		sig[0] += 4
+	case P2WPKH:
+		sig[0] += 8
+	default:
+		return "", fmt.Errorf("unsupported address type %v", addrType)
+	}
+
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// Verify returns whether the passed base64 encoded signature is a valid
+// signature of the passed message by the key of the passed address.  The
+// ErrUnsupportedAddress error is returned for addresses whose ownership is not
+// proven by a single key and the ErrMalformedSignature error is returned when
+// the signature can't be decoded.
+func Verify(addr ltcutil.Address, signature, message string) (bool, error) {
+	var keyHash []byte
+	switch addr := addr.(type) {
+	case *ltcutil.AddressPubKeyHash:
+		keyHash = addr.Hash160()[:]
+	case *ltcutil.AddressWitnessPubKeyHash:
+		keyHash = addr.Hash160()[:]
+	case *ltcutil.AddressScriptHash:
+		keyHash = addr.Hash160()[:]
+	default:
+		return false, ErrUnsupportedAddress
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(sig) != signatureLen {
+		return false, ErrMalformedSignature
+	}
+	header := sig[0]
+	if header < compactSigMagicOffset || header >= compactSigMagicOffset+16 {
+		return false, ErrMalformedSignature
+	}
+
+	// Map the segwit ranges back to the range of the compressed keys for
+	// the recovery of the public key.
+	if header >= compactSigMagicOffset+8 {
+		sig[0] = compactSigMagicOffset + 4 + (header-compactSigMagicOffset)%4
+	}
+
+	// A signature which does not recover a public key is invalid rather
+	// than malformed, which mirrors the reference implementation.
+	pubKey, compressed, err := ecdsa.RecoverCompact(sig, Hash(message))
+	if err != nil {
+		return false, nil
+	}
+
+	var serializedPubKey []byte
+	if compressed {
+		serializedPubKey = pubKey.SerializeCompressed()
+	} else {
+		serializedPubKey = pubKey.SerializeUncompressed()
+	}
+	pubKeyHash := ltcutil.Hash160(serializedPubKey)
+
+	switch addr.(type) {
+	case *ltcutil.AddressPubKeyHash:
+		return bytes.Equal(pubKeyHash, keyHash), nil
+
+	case *ltcutil.AddressWitnessPubKeyHash:
+		return compressed && bytes.Equal(pubKeyHash, keyHash), nil
+
+	default:
+		// The script of a pay-to-script-hash address is assumed to be
+		// a nested pay-to-witness-pubkey-hash script.
+		if !compressed {
+			return false, nil
+		}
+		witnessScript, err := txscript.NewScriptBuilder().
+			AddOp(txscript.OP_0).AddData(pubKeyHash).Script()
+		if err != nil {
+			return false, err
+		}
+		return bytes.Equal(ltcutil.Hash160(witnessScript), keyHash), nil
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package signmessage
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+)
+
+// testAddresses returns the addresses of each type for the passed key.
+func testAddresses(t *testing.T, key *btcec.PrivateKey) map[string]ltcutil.Address {
+	t.Helper()
+
+	params := &chaincfg.MainNetParams
+	compressed := ltcutil.Hash160(key.PubKey().SerializeCompressed())
+	uncompressed := ltcutil.Hash160(key.PubKey().SerializeUncompressed())
+	witnessScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_0).AddData(compressed).Script()
+	if err != nil {
+		t.Fatalf("unable to build witness script: %v", err)
+	}
+
+	p2pkh, _ := ltcutil.NewAddressPubKeyHash(compressed, params)
+	p2pkhUncompressed, _ := ltcutil.NewAddressPubKeyHash(uncompressed, params)
+	p2shP2WPKH, _ := ltcutil.NewAddressScriptHash(witnessScript, params)
+	p2wpkh, _ := ltcutil.NewAddressWitnessPubKeyHash(compressed, params)
+	return map[string]ltcutil.Address{
+		"p2pkh":              p2pkh,
+		"p2pkh uncompressed": p2pkhUncompressed,
+		"p2sh-p2wpkh":        p2shP2WPKH,
+		"p2wpkh":             p2wpkh,
+	}
+}
+
+// TestSignVerify ensures signatures of each address type are valid for the
+// addresses of the signing key of the same compression and invalid for other
+// keys and messages.
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+
+	key, _ := btcec.PrivKeyFromBytes([]byte{0x01, 0x02, 0x03, 0x04})
+	otherKey, _ := btcec.PrivKeyFromBytes([]byte{0x05, 0x06, 0x07, 0x08})
+	addrs := testAddresses(t, key)
+	otherAddrs := testAddresses(t, otherKey)
+
+	tests := []struct {
+		name       string
+		compressed bool
+		addrType   AddressType
+		header     byte
+		valid      []string
+	}{
+		{
+			name:       "p2pkh uncompressed",
+			compressed: false,
+			addrType:   P2PKH,
+			header:     27,
+			valid:      []string{"p2pkh uncompressed"},
+		},
+		{
+			name:       "p2pkh",
+			compressed: true,
+			addrType:   P2PKH,
+			header:     31,
+			valid:      []string{"p2pkh", "p2sh-p2wpkh", "p2wpkh"},
+		},
+		{
+			name:       "p2sh-p2wpkh",
+			compressed: true,
+			addrType:   P2SHP2WPKH,
+			header:     35,
+			valid:      []string{"p2pkh", "p2sh-p2wpkh", "p2wpkh"},
+		},
+		{
+			name:       "p2wpkh",
+			compressed: true,
+			addrType:   P2WPKH,
+			header:     39,
+			valid:      []string{"p2pkh", "p2sh-p2wpkh", "p2wpkh"},
+		},
+	}
+
+	const message = "proof of ownership"
+	for _, test := range tests {
+		sig, err := Sign(key, test.compressed, test.addrType, message)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		rawSig, _ := base64.StdEncoding.DecodeString(sig)
+		if rawSig[0] < test.header || rawSig[0] >= test.header+4 {
+			t.Errorf("%s: got header %d, want range starting at %d",
+				test.name, rawSig[0], test.header)
+		}
+
+		isValid := make(map[string]bool)
+		for _, name := range test.valid {
+			isValid[name] = true
+		}
+		for name, addr := range addrs {
+			valid, err := Verify(addr, sig, message)
+			if err != nil || valid != isValid[name] {
+				t.Errorf("%s: %s: got %v (err %v), want %v",
+					test.name, name, valid, err, isValid[name])
+			}
+			valid, err = Verify(addr, sig, message+".")
+			if err != nil || valid {
+				t.Errorf("%s: %s: signature valid for another "+
+					"message (err %v)", test.name, name, err)
+			}
+			valid, err = Verify(otherAddrs[name], sig, message)
+			if err != nil || valid {
+				t.Errorf("%s: %s: signature valid for another "+
+					"key (err %v)", test.name, name, err)
+			}
+		}
+	}
+}
+
+// TestSignVerifyErrors ensures the expected errors are returned for segwit
+// addresses of uncompressed keys, unsupported addresses, and malformed
+// signatures.
+func TestSignVerifyErrors(t *testing.T) {
+	t.Parallel()
+
+	key, _ := btcec.PrivKeyFromBytes([]byte{0x01, 0x02, 0x03, 0x04})
+	if _, err := Sign(key, false, P2WPKH, "msg"); err != ErrUncompressedSegwit {
+		t.Errorf("Sign: unexpected error for uncompressed segwit key: %v",
+			err)
+	}
+
+	addr, _ := ltcutil.NewAddressWitnessScriptHash(make([]byte, 32),
+		&chaincfg.MainNetParams)
+	if _, err := Verify(addr, "", "msg"); err != ErrUnsupportedAddress {
+		t.Errorf("Verify: unexpected error for unsupported address: %v",
+			err)
+	}
+
+	p2pkh := testAddresses(t, key)["p2pkh"]
+	badHeader := make([]byte, signatureLen)
+	badHeader[0] = 43
+	for _, sig := range []string{
+		"not base64!",
+		base64.StdEncoding.EncodeToString([]byte{31, 1, 2}),
+		base64.StdEncoding.EncodeToString(badHeader),
+	} {
+		if _, err := Verify(p2pkh, sig, "msg"); err != ErrMalformedSignature {
+			t.Errorf("Verify(%q): unexpected error: %v", sig, err)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/mempool"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// maxSelectionInputs is the number of inputs assumed when sizing the input
+// count of a transaction during coin selection, before the final number of
+// inputs is known.  Assuming a large count errs on the side of overpaying the
+// fee by at most a couple of bytes.
+const maxSelectionInputs = 0xffff
+
+const (
+	// feeSnipingBackoffChance is the inverse of the probability that the
+	// lock time discouraging fee sniping is moved further back, so that
+	// transactions whose broadcast was delayed, such as those sent over
+	// anonymity networks, don't stand out.
+	feeSnipingBackoffChance = 10
+
+	// maxFeeSnipingBackoff bounds the number of blocks the lock time
+	// discouraging fee sniping is moved back by.
+	maxFeeSnipingBackoff = 100
+)
+
+// UTXOSource provides the coins available to fund a transaction.
+type UTXOSource interface {
+	// UnspentOutputs returns the unspent outputs which may be spent by the
+	// funded transaction.
+	UnspentOutputs() ([]Coin, error)
+}
+
+// Options houses the parameters used to fund a transaction.
+type Options struct {
+	// FeeRate is the fee rate the funded transaction pays, in satoshi per
+	// 1000 virtual bytes.
+	FeeRate ltcutil.Amount
+
+	// ChangeScript is the public key script of the change output.
+	ChangeScript []byte
+
+	// Strategy is the coin selection algorithm to use.
+	Strategy Strategy
+
+	// DustRelayFee is the minimum relay fee used to determine whether the
+	// change output would be dust, in which case it is added to the fee
+	// instead.  mempool.DefaultMinRelayTxFee is used when it is zero.
+	DustRelayFee ltcutil.Amount
+
+	// SubtractFeeFromOutputs lists the indexes of the outputs the fee is
+	// deducted from, in which case the added inputs only need to cover the
+	// value of the outputs.  The fee is split equally between the outputs,
+	// with the first listed output paying any remainder.
+	SubtractFeeFromOutputs []int
+
+	// Replaceable signals BIP 125 replaceability on the added inputs.
+	Replaceable bool
+
+	// Preselected provides the previous outputs spent by inputs already
+	// present in the transaction which are not returned by the UTXO
+	// source.
+	Preselected []Coin
+
+	// LockTime, when set, replaces the lock time of the transaction.
+	LockTime *uint32
+
+	// AntiFeeSniping sets the lock time of a transaction without one to
+	// the value returned by FeeSnipingLockTime for BestHeight, unless
+	// LockTime is set.
+	AntiFeeSniping bool
+
+	// BestHeight is the height of the best chain the transaction is built
+	// on.  It is only used when AntiFeeSniping is set.
+	BestHeight int32
+}
+
+// AuthoredTx is a transaction funded by FundTransaction.
+type AuthoredTx struct {
+	// Tx is the unsigned funded transaction.
+	Tx *wire.MsgTx
+
+	// PrevOutputs are the previous outputs spent by each input of Tx, in
+	// input order.
+	PrevOutputs []*wire.TxOut
+
+	// Fee is the fee paid by Tx.
+	Fee ltcutil.Amount
+
+	// ChangeIndex is the index of the change output in Tx, or -1 when no
+	// change output was added.
+	ChangeIndex int
+}
+
+// SetChangePosition moves the change output to the passed output index.
+func (tx *AuthoredTx) SetChangePosition(pos int) error {
+	if tx.ChangeIndex < 0 {
+		return errors.New("transaction has no change output")
+	}
+	if pos < 0 || pos >= len(tx.Tx.TxOut) {
+		return fmt.Errorf("change position %d is out of bounds", pos)
+	}
+
+	outputs := tx.Tx.TxOut
+	change := outputs[tx.ChangeIndex]
+	outputs = append(outputs[:tx.ChangeIndex], outputs[tx.ChangeIndex+1:]...)
+	outputs = append(outputs[:pos], append([]*wire.TxOut{change},
+		outputs[pos:]...)...)
+	tx.Tx.TxOut = outputs
+	tx.ChangeIndex = pos
+	return nil
+}
+
+// RandomizeChangePosition moves the change output, if any, to a random output
+// index so the change can't be identified by its position.
+func (tx *AuthoredTx) RandomizeChangePosition() {
+	if tx.ChangeIndex < 0 {
+		return
+	}
+	// The position is always in bounds, so the error can be ignored.
+	_ = tx.SetChangePosition(rand.Intn(len(tx.Tx.TxOut)))
+}
+
+// FundTransaction returns a copy of the passed transaction with inputs from
+// source added to pay for its outputs and a fee at the requested fee rate.
+// The previous outputs spent by inputs already present in the transaction
+// must be provided either by source or by opts.Preselected.  A change output
+// paying to opts.ChangeScript is appended when the leftover value is not dust,
+// otherwise the leftover value is added to the fee.
+//
+// Coins spending output scripts of a type whose signed size can't be
+// estimated are never selected.  ErrInsufficientFunds is returned when the
+// available coins are not worth enough to fund the transaction.
+func FundTransaction(tx *wire.MsgTx, source UTXOSource,
+	opts *Options) (*AuthoredTx, error) {
+
+	if len(tx.TxOut) == 0 {
+		return nil, errors.New("transaction has no outputs")
+	}
+	if tx.IsHogEx || tx.Mweb != nil {
+		return nil, errors.New("funding MWEB transactions is not " +
+			"supported")
+	}
+	if len(opts.ChangeScript) == 0 {
+		return nil, errors.New("no change script provided")
+	}
+	if opts.FeeRate < 0 {
+		return nil, fmt.Errorf("negative fee rate %v", opts.FeeRate)
+	}
+	dustRelayFee := opts.DustRelayFee
+	if dustRelayFee == 0 {
+		dustRelayFee = mempool.DefaultMinRelayTxFee
+	}
+
+	subtractFrom := make(map[int]struct{}, len(opts.SubtractFeeFromOutputs))
+	for _, idx := range opts.SubtractFeeFromOutputs {
+		if idx < 0 || idx >= len(tx.TxOut) {
+			return nil, fmt.Errorf("output index %d to subtract the "+
+				"fee from is out of bounds", idx)
+		}
+		if _, ok := subtractFrom[idx]; ok {
+			return nil, fmt.Errorf("duplicate output index %d to "+
+				"subtract the fee from", idx)
+		}
+		subtractFrom[idx] = struct{}{}
+	}
+	subtractFee := len(subtractFrom) > 0
+
+	coins, err := source.UnspentOutputs()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[wire.OutPoint]Coin, len(coins)+len(opts.Preselected))
+	for _, coin := range coins {
+		known[coin.OutPoint] = coin
+	}
+	for _, coin := range opts.Preselected {
+		known[coin.OutPoint] = coin
+	}
+
+	// Account for the inputs already present in the transaction.
+	tx = copyTx(tx)
+	switch {
+	case opts.LockTime != nil:
+		tx.LockTime = *opts.LockTime
+	case opts.AntiFeeSniping && tx.LockTime == 0:
+		tx.LockTime = FeeSnipingLockTime(opts.BestHeight)
+	}
+	var (
+		inputValue  ltcutil.Amount
+		inputWeight int64
+		prevOuts    = make([]*wire.TxOut, 0, len(tx.TxIn))
+		spent       = make(map[wire.OutPoint]struct{}, len(tx.TxIn))
+	)
+	for i, txIn := range tx.TxIn {
+		coin, ok := known[txIn.PreviousOutPoint]
+		if !ok {
+			return nil, fmt.Errorf("unable to find previous output "+
+				"%v spent by input %d", txIn.PreviousOutPoint, i)
+		}
+		weight, ok := InputWeight(coin.PkScript)
+		if !ok {
+			return nil, fmt.Errorf("unable to estimate the size of "+
+				"input %d spending unsupported script", i)
+		}
+		inputValue += coin.Value
+		inputWeight += weight
+		prevOuts = append(prevOuts, wire.NewTxOut(int64(coin.Value),
+			coin.PkScript))
+		spent[txIn.PreviousOutPoint] = struct{}{}
+	}
+
+	var (
+		outputValue  ltcutil.Amount
+		outputWeight int64
+	)
+	for _, txOut := range tx.TxOut {
+		outputValue += ltcutil.Amount(txOut.Value)
+		outputWeight += OutputWeight(txOut)
+	}
+
+	changeOut := wire.NewTxOut(0, opts.ChangeScript)
+	changeWeight := OutputWeight(changeOut)
+	changeSpendWeight, ok := InputWeight(opts.ChangeScript)
+	if !ok {
+		changeSpendWeight = inputSize(0)*blockchain.WitnessScaleFactor +
+			redeemP2WPKHWitnessSize
+	}
+	costOfChange := FeeForWeight(changeWeight, opts.FeeRate) +
+		FeeForWeight(changeSpendWeight, opts.FeeRate)
+
+	// When the fee is subtracted from the outputs, the added inputs only
+	// need to cover the value of the outputs.
+	selectionFeeRate := opts.FeeRate
+	if subtractFee {
+		selectionFeeRate = 0
+	}
+	fixedWeight := baseWeight(maxSelectionInputs, len(tx.TxOut)) +
+		inputWeight + outputWeight
+	target := outputValue + FeeForWeight(fixedWeight, selectionFeeRate) -
+		inputValue
+
+	available := make([]Coin, 0, len(coins))
+	for _, coin := range coins {
+		if _, ok := spent[coin.OutPoint]; !ok {
+			available = append(available, coin)
+		}
+	}
+	selected, err := selectCandidates(
+		candidates(available, selectionFeeRate), target, costOfChange,
+		opts.Strategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The added inputs must not be final for a lock time to be enforced.
+	sequence := uint32(wire.MaxTxInSequenceNum)
+	switch {
+	case opts.Replaceable:
+		sequence = wire.MaxTxInSequenceNum - 2
+	case tx.LockTime != 0:
+		sequence = wire.MaxTxInSequenceNum - 1
+	}
+	for i := range selected {
+		coin := &selected[i].coin
+		txIn := wire.NewTxIn(&coin.OutPoint, nil, nil)
+		txIn.Sequence = sequence
+		tx.AddTxIn(txIn)
+
+		inputValue += coin.Value
+		inputWeight += selected[i].weight
+		prevOuts = append(prevOuts, wire.NewTxOut(int64(coin.Value),
+			coin.PkScript))
+	}
+
+	excess := inputValue - outputValue
+	if excess < 0 {
+		return nil, ErrInsufficientFunds
+	}
+	weight := baseWeight(len(tx.TxIn), len(tx.TxOut)) + inputWeight +
+		outputWeight
+	feeWithoutChange := FeeForWeight(weight, opts.FeeRate)
+	weightWithChange := baseWeight(len(tx.TxIn), len(tx.TxOut)+1) +
+		inputWeight + outputWeight + changeWeight
+	feeWithChange := FeeForWeight(weightWithChange, opts.FeeRate)
+
+	changeIndex := -1
+	if subtractFee {
+		// Return any value in excess of the outputs as change, leaving
+		// the outputs to pay the entire fee.
+		deduction := feeWithoutChange - excess
+		changeOut.Value = int64(excess)
+		if excess > 0 && !mempool.IsDust(changeOut, dustRelayFee) {
+			changeIndex = len(tx.TxOut)
+			tx.AddTxOut(changeOut)
+			deduction = feeWithChange
+		}
+		if deduction > 0 {
+			err := subtractFeeFromOutputs(
+				tx, opts.SubtractFeeFromOutputs, deduction,
+				dustRelayFee,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if excess < feeWithoutChange {
+			return nil, ErrInsufficientFunds
+		}
+		changeOut.Value = int64(excess - feeWithChange)
+		if changeOut.Value > 0 && !mempool.IsDust(changeOut, dustRelayFee) {
+			changeIndex = len(tx.TxOut)
+			tx.AddTxOut(changeOut)
+		}
+	}
+
+	fee := inputValue
+	for _, txOut := range tx.TxOut {
+		fee -= ltcutil.Amount(txOut.Value)
+	}
+
+	return &AuthoredTx{
+		Tx:          tx,
+		PrevOutputs: prevOuts,
+		Fee:         fee,
+		ChangeIndex: changeIndex,
+	}, nil
+}
+
+// FeeSnipingLockTime returns the lock time of a transaction built on a best
+// chain with the passed height which discourages fee sniping.  Since such a
+// transaction can only be mined in a block extending the best chain, miners
+// gain nothing by reorganizing the tip of the chain to collect its fee.  The
+// lock time is occasionally moved back by a random number of blocks so that
+// transactions whose broadcast was delayed blend in.
+func FeeSnipingLockTime(bestHeight int32) uint32 {
+	if bestHeight <= 0 {
+		return 0
+	}
+	height := int64(bestHeight)
+	if rand.Intn(feeSnipingBackoffChance) == 0 {
+		height -= int64(rand.Intn(maxFeeSnipingBackoff))
+		if height < 0 {
+			height = 0
+		}
+	}
+	return uint32(height)
+}
+
+// copyTx returns a copy of the inputs and outputs of the passed transaction.
+// MsgTx.Copy can't be used since it round trips the transaction through its
+// serialization, which is ambiguous for transactions without inputs.
+func copyTx(tx *wire.MsgTx) *wire.MsgTx {
+	txCopy := &wire.MsgTx{
+		Version:  tx.Version,
+		TxIn:     make([]*wire.TxIn, 0, len(tx.TxIn)),
+		TxOut:    make([]*wire.TxOut, 0, len(tx.TxOut)+1),
+		LockTime: tx.LockTime,
+	}
+	for _, txIn := range tx.TxIn {
+		newTxIn := *txIn
+		newTxIn.SignatureScript = append([]byte(nil),
+			txIn.SignatureScript...)
+		newTxIn.Witness = make(wire.TxWitness, 0, len(txIn.Witness))
+		for _, item := range txIn.Witness {
+			newTxIn.Witness = append(newTxIn.Witness,
+				append([]byte(nil), item...))
+		}
+		txCopy.TxIn = append(txCopy.TxIn, &newTxIn)
+	}
+	for _, txOut := range tx.TxOut {
+		txCopy.TxOut = append(txCopy.TxOut, wire.NewTxOut(txOut.Value,
+			append([]byte(nil), txOut.PkScript...)))
+	}
+	return txCopy
+}
+
+// subtractFeeFromOutputs deducts the passed fee from the outputs of tx with
+// the passed indexes.  The fee is split equally, with the first output paying
+// any remainder.
+func subtractFeeFromOutputs(tx *wire.MsgTx, indexes []int,
+	fee, dustRelayFee ltcutil.Amount) error {
+
+	share := int64(fee) / int64(len(indexes))
+	remainder := int64(fee) % int64(len(indexes))
+	for i, idx := range indexes {
+		txOut := tx.TxOut[idx]
+		txOut.Value -= share
+		if i == 0 {
+			txOut.Value -= remainder
+		}
+		if txOut.Value <= 0 || mempool.IsDust(txOut, dustRelayFee) {
+			return fmt.Errorf("output %d is too small to pay its "+
+				"share of the fee", idx)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// coinSource is a UTXOSource backed by a fixed set of coins.
+type coinSource []Coin
+
+// UnspentOutputs returns the coins of the source.
+//
+// This is part of the UTXOSource interface.
+func (s coinSource) UnspentOutputs() ([]Coin, error) {
+	return s, nil
+}
+
+// unfundedTx returns a transaction without inputs paying the passed values to
+// P2WPKH outputs.
+func unfundedTx(values ...int64) *wire.MsgTx {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	for _, value := range values {
+		tx.AddTxOut(wire.NewTxOut(value, p2wpkhScript))
+	}
+	return tx
+}
+
+// checkFunded ensures the fee of the passed funded transaction matches its
+// inputs and outputs and pays at least the passed fee rate.
+func checkFunded(t *testing.T, authored *AuthoredTx, feeRate ltcutil.Amount) {
+	t.Helper()
+
+	tx := authored.Tx
+	if len(authored.PrevOutputs) != len(tx.TxIn) {
+		t.Fatalf("got %d previous outputs for %d inputs",
+			len(authored.PrevOutputs), len(tx.TxIn))
+	}
+	fee := ltcutil.Amount(0)
+	for _, prevOut := range authored.PrevOutputs {
+		fee += ltcutil.Amount(prevOut.Value)
+	}
+	for _, txOut := range tx.TxOut {
+		fee -= ltcutil.Amount(txOut.Value)
+	}
+	if fee != authored.Fee {
+		t.Fatalf("reported fee %v does not match actual fee %v",
+			authored.Fee, fee)
+	}
+
+	var weight int64
+	for _, prevOut := range authored.PrevOutputs {
+		inputWeight, _ := InputWeight(prevOut.PkScript)
+		weight += inputWeight
+	}
+	for _, txOut := range tx.TxOut {
+		weight += OutputWeight(txOut)
+	}
+	weight += baseWeight(len(tx.TxIn), len(tx.TxOut))
+	if minFee := FeeForWeight(weight, feeRate); fee < minFee {
+		t.Fatalf("fee %v is less than the minimum fee %v", fee, minFee)
+	}
+}
+
+// TestFundTransaction ensures transactions are funded with the expected
+// inputs, change, and fee.
+func TestFundTransaction(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = 1000
+	changeScript := p2pkhScript
+
+	// A transaction requiring change.
+	source := coinSource(testCoins(300000, 5000000))
+	authored, err := FundTransaction(unfundedTx(1000000), source, &Options{
+		FeeRate:      feeRate,
+		ChangeScript: changeScript,
+		Strategy:     BranchAndBound,
+	})
+	if err != nil {
+		t.Fatalf("FundTransaction: unexpected error: %v", err)
+	}
+	checkFunded(t, authored, feeRate)
+	if len(authored.Tx.TxIn) != 1 ||
+		authored.Tx.TxIn[0].PreviousOutPoint.Index != 5000000 {
+
+		t.Fatalf("unexpected inputs selected: %v", authored.Tx.TxIn)
+	}
+	if authored.ChangeIndex != 1 {
+		t.Fatalf("unexpected change index %d", authored.ChangeIndex)
+	}
+	if seq := authored.Tx.TxIn[0].Sequence; seq != wire.MaxTxInSequenceNum {
+		t.Fatalf("unexpected sequence %d", seq)
+	}
+
+	// A coin exactly covering the output and the fee of spending it is
+	// selected without change.  The selection assumes a three byte input
+	// count, so the selection target is 1000044 satoshi, while spending
+	// the coin costs 69 satoshi.  The final transaction is 110 vbytes, so
+	// the remaining 3 satoshi are added to the fee.
+	source = coinSource(testCoins(3000000, 1000113, 5000000))
+	authored, err = FundTransaction(unfundedTx(1000000), source, &Options{
+		FeeRate:      feeRate,
+		ChangeScript: changeScript,
+		Strategy:     BranchAndBound,
+		Replaceable:  true,
+	})
+	if err != nil {
+		t.Fatalf("FundTransaction: unexpected error: %v", err)
+	}
+	checkFunded(t, authored, feeRate)
+	if len(authored.Tx.TxIn) != 1 ||
+		authored.Tx.TxIn[0].PreviousOutPoint.Index != 1000113 {
+
+		t.Fatalf("unexpected inputs selected: %v", authored.Tx.TxIn)
+	}
+	if authored.ChangeIndex != -1 || authored.Fee != 113 {
+		t.Fatalf("unexpected change index %d and fee %v",
+			authored.ChangeIndex, authored.Fee)
+	}
+	if seq := authored.Tx.TxIn[0].Sequence; seq != wire.MaxTxInSequenceNum-2 {
+		t.Fatalf("unexpected sequence %d", seq)
+	}
+
+	// Insufficient funds.
+	source = coinSource(testCoins(300000, 500000))
+	_, err = FundTransaction(unfundedTx(1000000), source, &Options{
+		FeeRate:      feeRate,
+		ChangeScript: changeScript,
+	})
+	if err != ErrInsufficientFunds {
+		t.Fatalf("unexpected error funding with insufficient funds: %v",
+			err)
+	}
+}
+
+// TestFundTransactionSubtractFee ensures the fee is deducted from the
+// requested outputs.
+func TestFundTransactionSubtractFee(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = 1000
+	source := coinSource(testCoins(1500000))
+	authored, err := FundTransaction(unfundedTx(1000000, 500000), source,
+		&Options{
+			FeeRate:                feeRate,
+			ChangeScript:           p2pkhScript,
+			SubtractFeeFromOutputs: []int{1, 0},
+		})
+	if err != nil {
+		t.Fatalf("FundTransaction: unexpected error: %v", err)
+	}
+	checkFunded(t, authored, feeRate)
+	if authored.ChangeIndex != -1 {
+		t.Fatalf("unexpected change index %d", authored.ChangeIndex)
+	}
+
+	// The 141 vbyte transaction pays 141 satoshi, split between the outputs
+	// with the first listed output paying the remainder.
+	if authored.Fee != 141 {
+		t.Fatalf("unexpected fee %v", authored.Fee)
+	}
+	if got := authored.Tx.TxOut[0].Value; got != 1000000-70 {
+		t.Fatalf("unexpected first output value %d", got)
+	}
+	if got := authored.Tx.TxOut[1].Value; got != 500000-71 {
+		t.Fatalf("unexpected second output value %d", got)
+	}
+}
+
+// TestFundTransactionPreselected ensures inputs already present in the
+// transaction are accounted for.
+func TestFundTransactionPreselected(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = 1000
+	preselected := Coin{
+		OutPoint: wire.OutPoint{Index: 1},
+		Value:    2000000,
+		PkScript: p2pkhScript,
+	}
+	tx := unfundedTx(1000000)
+	tx.AddTxIn(wire.NewTxIn(&preselected.OutPoint, nil, nil))
+
+	source := coinSource(testCoins(5000000))
+	opts := &Options{FeeRate: feeRate, ChangeScript: p2pkhScript}
+	if _, err := FundTransaction(tx, source, opts); err == nil {
+		t.Fatal("FundTransaction: funded transaction spending an " +
+			"unknown output")
+	}
+
+	opts.Preselected = []Coin{preselected}
+	authored, err := FundTransaction(tx, source, opts)
+	if err != nil {
+		t.Fatalf("FundTransaction: unexpected error: %v", err)
+	}
+	checkFunded(t, authored, feeRate)
+	if len(authored.Tx.TxIn) != 1 || authored.ChangeIndex != 1 {
+		t.Fatalf("unexpected inputs %v and change index %d",
+			authored.Tx.TxIn, authored.ChangeIndex)
+	}
+	if len(tx.TxOut) != 1 {
+		t.Fatal("FundTransaction modified the passed transaction")
+	}
+}
+
+// TestFundTransactionLockTime ensures funded transactions get the requested
+// lock time, or one discouraging fee sniping, along with inputs which enforce
+// it.
+func TestFundTransactionLockTime(t *testing.T) {
+	t.Parallel()
+
+	const bestHeight = 1000
+	source := coinSource(testCoins(5000000))
+	opts := &Options{
+		FeeRate:        1000,
+		ChangeScript:   p2pkhScript,
+		AntiFeeSniping: true,
+		BestHeight:     bestHeight,
+	}
+	authored, err := FundTransaction(unfundedTx(1000000), source, opts)
+	if err != nil {
+		t.Fatalf("FundTransaction: unexpected error: %v", err)
+	}
+	lockTime := authored.Tx.LockTime
+	if lockTime > bestHeight || lockTime <= bestHeight-maxFeeSnipingBackoff {
+		t.Fatalf("lock time %d does not discourage fee sniping at "+
+			"height %d", lockTime, bestHeight)
+	}
+	for i, txIn := range authored.Tx.TxIn {
+		if txIn.Sequence != wire.MaxTxInSequenceNum-1 {
+			t.Fatalf("input %d has sequence %x which does not "+
+				"enforce the lock time", i, txIn.Sequence)
+		}
+	}
+
+	// An explicit lock time, including zero, is used as is.
+	for _, lockTime := range []uint32{0, 500} {
+		lockTime := lockTime
+		opts.LockTime = &lockTime
+		authored, err := FundTransaction(unfundedTx(1000000), source,
+			opts)
+		if err != nil {
+			t.Fatalf("FundTransaction: unexpected error: %v", err)
+		}
+		if authored.Tx.LockTime != lockTime {
+			t.Fatalf("lock time is %d, want %d",
+				authored.Tx.LockTime, lockTime)
+		}
+		wantSequence := uint32(wire.MaxTxInSequenceNum)
+		if lockTime != 0 {
+			wantSequence--
+		}
+		if authored.Tx.TxIn[0].Sequence != wantSequence {
+			t.Fatalf("input has sequence %x, want %x",
+				authored.Tx.TxIn[0].Sequence, wantSequence)
+		}
+	}
+
+	// The lock time of a transaction which has one is kept.
+	opts.LockTime = nil
+	tx := unfundedTx(1000000)
+	tx.LockTime = 700
+	authored, err = FundTransaction(tx, source, opts)
+	if err != nil {
+		t.Fatalf("FundTransaction: unexpected error: %v", err)
+	}
+	if authored.Tx.LockTime != 700 {
+		t.Fatalf("lock time is %d, want 700", authored.Tx.LockTime)
+	}
+}
+
+// TestFeeSnipingLockTime ensures the lock time discouraging fee sniping is
+// within the back-off of the best height and never negative.
+func TestFeeSnipingLockTime(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 1000; i++ {
+		lockTime := FeeSnipingLockTime(50)
+		if lockTime > 50 {
+			t.Fatalf("lock time %d is above the best height", lockTime)
+		}
+		lockTime = FeeSnipingLockTime(1000)
+		if lockTime > 1000 || lockTime <= 1000-maxFeeSnipingBackoff {
+			t.Fatalf("lock time %d is out of range", lockTime)
+		}
+	}
+	if lockTime := FeeSnipingLockTime(0); lockTime != 0 {
+		t.Fatalf("lock time at the genesis block is %d", lockTime)
+	}
+}
+
+// TestSetChangePosition ensures the change output is moved to the requested
+// position.
+func TestSetChangePosition(t *testing.T) {
+	t.Parallel()
+
+	source := coinSource(testCoins(5000000))
+	authored, err := FundTransaction(unfundedTx(1000000, 2000000), source,
+		&Options{FeeRate: 1000, ChangeScript: p2pkhScript})
+	if err != nil {
+		t.Fatalf("FundTransaction: unexpected error: %v", err)
+	}
+	change := authored.Tx.TxOut[authored.ChangeIndex]
+
+	if err := authored.SetChangePosition(0); err != nil {
+		t.Fatalf("SetChangePosition: unexpected error: %v", err)
+	}
+	if authored.ChangeIndex != 0 || authored.Tx.TxOut[0] != change ||
+		authored.Tx.TxOut[1].Value != 1000000 ||
+		authored.Tx.TxOut[2].Value != 2000000 {
+
+		t.Fatalf("unexpected outputs after moving change: %v",
+			authored.Tx.TxOut)
+	}
+	if err := authored.SetChangePosition(3); err == nil {
+		t.Fatal("SetChangePosition: moved change out of bounds")
+	}
+
+	authored.RandomizeChangePosition()
+	if authored.Tx.TxOut[authored.ChangeIndex] != change {
+		t.Fatal("RandomizeChangePosition: change index does not " +
+			"match change output")
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package txauthor provides coin selection and funding of raw transactions.
+
+Given a transaction paying to a set of outputs and a source of spendable
+outputs, FundTransaction selects the inputs needed to pay for the outputs and
+the fee at a requested fee rate, and adds a change output when the leftover
+value is worth more than the dust limit.  The resulting transaction is
+unsigned.
+
+# Coin Selection
+
+Two selection strategies are provided:
+
+  - LargestFirst picks the coins with the highest value until the target is
+    met.  It is simple and minimizes the number of inputs.
+  - BranchAndBound searches for a set of coins whose value matches the target
+    closely enough that no change output is needed, falling back to
+    LargestFirst when no such set exists.
+
+Coins are compared by their effective value, which is their value less the fee
+required to spend them at the requested fee rate, so coins which cost more to
+spend than they are worth are never selected.
+
+# Fee Estimation
+
+Since the transaction is unsigned, its final size is estimated from the type of
+the output script being spent by each input.  Pay-to-pubkey, pay-to-pubkey-hash,
+pay-to-witness-pubkey-hash, pay-to-script-hash wrapping a
+pay-to-witness-pubkey-hash, and key path pay-to-taproot outputs are supported.
+Signatures are assumed to have their maximum size, so the actual fee rate of the
+signed transaction is never lower than the requested one.
+
+# Signing
+
+A KeyStore holds private keys along with any redeem and witness scripts, and
+signs every input of a transaction it has the keys for.  The same input types
+supported for fee estimation are supported, along with bare multisig and
+pay-to-witness-script-hash inputs whose witness script is a multisig,
+pay-to-pubkey, or pay-to-pubkey-hash script.  Signatures already present in a
+multisig input are kept, so several parties can sign the same transaction in
+turn.  An error is reported for each input which is not fully signed.
+*/
+package txauthor
@@ -0,0 +1,246 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// bnbMaxTries is the maximum number of combinations the branch-and-bound
+// search visits before giving up.
+const bnbMaxTries = 100000
+
+var (
+	// ErrInsufficientFunds is returned when the available coins are not
+	// worth enough to pay for the outputs and the fee.
+	ErrInsufficientFunds = errors.New("insufficient funds available to " +
+		"construct transaction")
+
+	// ErrNoChangeless is returned by the branch-and-bound search when no
+	// combination of coins matches the target without requiring a change
+	// output.
+	ErrNoChangeless = errors.New("no changeless coin selection found")
+)
+
+// Coin is a transaction output available to fund a transaction.
+type Coin struct {
+	OutPoint wire.OutPoint
+	Value    ltcutil.Amount
+	PkScript []byte
+}
+
+// Strategy identifies a coin selection algorithm.
+type Strategy int
+
+const (
+	// LargestFirst selects the coins with the highest effective value
+	// until the target is met.
+	LargestFirst Strategy = iota
+
+	// BranchAndBound searches for a combination of coins which meets the
+	// target without requiring a change output, falling back to
+	// LargestFirst when there is none.
+	BranchAndBound
+)
+
+// strategyStrings is a map of selection strategies back to their constant
+// names for pretty printing.
+var strategyStrings = map[Strategy]string{
+	LargestFirst:   "LargestFirst",
+	BranchAndBound: "BranchAndBound",
+}
+
+// String returns the Strategy as a human-readable name.
+func (s Strategy) String() string {
+	if str, ok := strategyStrings[s]; ok {
+		return str
+	}
+	return fmt.Sprintf("Unknown Strategy (%d)", int(s))
+}
+
+// candidate is a coin along with the estimated cost of spending it.
+type candidate struct {
+	coin     Coin
+	weight   int64
+	effValue ltcutil.Amount
+}
+
+// candidates returns the coins which are worth spending at the passed fee
+// rate, sorted by descending effective value.  Coins with unsupported scripts
+// are skipped.
+func candidates(coins []Coin, feeRate ltcutil.Amount) []candidate {
+	cands := make([]candidate, 0, len(coins))
+	for _, coin := range coins {
+		weight, ok := InputWeight(coin.PkScript)
+		if !ok {
+			continue
+		}
+		effValue := coin.Value - FeeForWeight(weight, feeRate)
+		if effValue <= 0 {
+			continue
+		}
+		cands = append(cands, candidate{
+			coin:     coin,
+			weight:   weight,
+			effValue: effValue,
+		})
+	}
+
+	// Sort by descending effective value, breaking ties by outpoint so the
+	// selection is deterministic.
+	sort.Slice(cands, func(i, j int) bool {
+		if cands[i].effValue != cands[j].effValue {
+			return cands[i].effValue > cands[j].effValue
+		}
+		a, b := &cands[i].coin.OutPoint, &cands[j].coin.OutPoint
+		if c := bytes.Compare(a.Hash[:], b.Hash[:]); c != 0 {
+			return c < 0
+		}
+		return a.Index < b.Index
+	})
+	return cands
+}
+
+// selectLargestFirst selects candidates, which must be sorted by descending
+// effective value, until their total effective value reaches target.
+func selectLargestFirst(cands []candidate, target ltcutil.Amount) ([]candidate, error) {
+	var total ltcutil.Amount
+	for i := range cands {
+		if total >= target {
+			return cands[:i], nil
+		}
+		total += cands[i].effValue
+	}
+	if total < target {
+		return nil, ErrInsufficientFunds
+	}
+	return cands, nil
+}
+
+// selectBranchAndBound performs a depth first search over candidates, which
+// must be sorted by descending effective value, for the combination whose
+// total effective value is at least target and exceeds it by the least
+// amount, without exceeding it by more than costOfChange.  Any such excess is
+// paid as fee in place of creating a change output.
+func selectBranchAndBound(cands []candidate, target,
+	costOfChange ltcutil.Amount) ([]candidate, error) {
+
+	var available ltcutil.Amount
+	for i := range cands {
+		available += cands[i].effValue
+	}
+	if available < target {
+		return nil, ErrInsufficientFunds
+	}
+
+	var (
+		tries     int
+		selected  = make([]bool, len(cands))
+		best      []bool
+		bestWaste ltcutil.Amount
+	)
+	var search func(idx int, value, remaining ltcutil.Amount)
+	search = func(idx int, value, remaining ltcutil.Amount) {
+		tries++
+		switch {
+		case tries > bnbMaxTries:
+			return
+
+		// Adding further coins only increases the value, so stop
+		// descending once the target range is reached or overshot.
+		case value > target+costOfChange:
+			return
+
+		case value >= target:
+			waste := value - target
+			if best == nil || waste < bestWaste {
+				best = append(best[:0], selected...)
+				bestWaste = waste
+			}
+			return
+
+		// Prune branches which can't reach the target even when
+		// including every remaining coin.
+		case idx == len(cands) || value+remaining < target:
+			return
+		}
+
+		remaining -= cands[idx].effValue
+
+		selected[idx] = true
+		search(idx+1, value+cands[idx].effValue, remaining)
+		selected[idx] = false
+
+		// There is no improving on an exact match.
+		if best != nil && bestWaste == 0 {
+			return
+		}
+		search(idx+1, value, remaining)
+	}
+	search(0, 0, available)
+
+	if best == nil {
+		return nil, ErrNoChangeless
+	}
+	var result []candidate
+	for i, sel := range best {
+		if sel {
+			result = append(result, cands[i])
+		}
+	}
+	return result, nil
+}
+
+// SelectCoins selects coins whose total effective value at the passed fee
+// rate is at least target using the passed strategy.  The effective value of
+// a coin is its value less the fee required to spend it.  For the
+// BranchAndBound strategy, costOfChange is the fee required to create and
+// later spend a change output, which is the most a changeless selection may
+// overshoot the target by.
+func SelectCoins(coins []Coin, target, feeRate, costOfChange ltcutil.Amount,
+	strategy Strategy) ([]Coin, error) {
+
+	selected, err := selectCandidates(
+		candidates(coins, feeRate), target, costOfChange, strategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Coin, 0, len(selected))
+	for i := range selected {
+		result = append(result, selected[i].coin)
+	}
+	return result, nil
+}
+
+// selectCandidates selects from cands, which must be sorted by descending
+// effective value, using the passed strategy.
+func selectCandidates(cands []candidate, target, costOfChange ltcutil.Amount,
+	strategy Strategy) ([]candidate, error) {
+
+	if target <= 0 {
+		return nil, nil
+	}
+
+	switch strategy {
+	case LargestFirst:
+		return selectLargestFirst(cands, target)
+
+	case BranchAndBound:
+		selected, err := selectBranchAndBound(cands, target, costOfChange)
+		if err == ErrNoChangeless {
+			return selectLargestFirst(cands, target)
+		}
+		return selected, err
+	}
+
+	return nil, fmt.Errorf("unknown coin selection strategy %v", strategy)
+}
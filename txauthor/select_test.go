@@ -0,0 +1,181 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// p2wpkhScript is a pay-to-witness-pubkey-hash script used by the tests.
+var p2wpkhScript = append([]byte{0x00, 0x14}, make([]byte, 20)...)
+
+// p2pkhScript is a pay-to-pubkey-hash script used by the tests.
+var p2pkhScript = append(append([]byte{0x76, 0xa9, 0x14}, make([]byte, 20)...),
+	0x88, 0xac)
+
+// testCoins returns P2WPKH coins with the passed values, using the value as
+// the output index so the coins are easy to identify.
+func testCoins(values ...ltcutil.Amount) []Coin {
+	coins := make([]Coin, 0, len(values))
+	for _, value := range values {
+		coins = append(coins, Coin{
+			OutPoint: wire.OutPoint{Index: uint32(value)},
+			Value:    value,
+			PkScript: p2wpkhScript,
+		})
+	}
+	return coins
+}
+
+// coinValues returns the sorted values of the passed coins.
+func coinValues(coins []Coin) []ltcutil.Amount {
+	values := make([]ltcutil.Amount, 0, len(coins))
+	for _, coin := range coins {
+		values = append(values, coin.Value)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values
+}
+
+// TestInputWeight ensures the input weight estimates match the expected
+// values for the supported script types and unsupported scripts are rejected.
+func TestInputWeight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		pkScript []byte
+		weight   int64
+		ok       bool
+	}{
+		{"p2pkh", p2pkhScript, 149 * 4, true},
+		{"p2wpkh", p2wpkhScript, 41*4 + 109, true},
+		{"p2tr", append([]byte{0x51, 0x20}, make([]byte, 32)...),
+			41*4 + 66, true},
+		{"nonstandard", []byte{0x51}, 0, false},
+	}
+
+	for _, test := range tests {
+		weight, ok := InputWeight(test.pkScript)
+		if ok != test.ok || weight != test.weight {
+			t.Errorf("%s: got weight %d (ok %v), want %d (ok %v)",
+				test.name, weight, ok, test.weight, test.ok)
+		}
+	}
+}
+
+// TestSelectCoins ensures the coin selection strategies select the expected
+// coins.
+func TestSelectCoins(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		coins        []Coin
+		target       ltcutil.Amount
+		costOfChange ltcutil.Amount
+		strategy     Strategy
+		want         []ltcutil.Amount
+		err          error
+	}{
+		{
+			name:     "largest first",
+			coins:    testCoins(1000, 2000, 5000),
+			target:   6000,
+			strategy: LargestFirst,
+			want:     []ltcutil.Amount{2000, 5000},
+		},
+		{
+			name:     "largest first insufficient",
+			coins:    testCoins(1000, 2000),
+			target:   6000,
+			strategy: LargestFirst,
+			err:      ErrInsufficientFunds,
+		},
+		{
+			name:     "branch and bound exact match",
+			coins:    testCoins(1000, 2000, 3000, 4000, 10000),
+			target:   7000,
+			strategy: BranchAndBound,
+			want:     []ltcutil.Amount{3000, 4000},
+		},
+		{
+			name:         "branch and bound within cost of change",
+			coins:        testCoins(1000, 2500, 4000, 10000),
+			target:       6000,
+			costOfChange: 600,
+			strategy:     BranchAndBound,
+			want:         []ltcutil.Amount{2500, 4000},
+		},
+		{
+			name:     "branch and bound falls back",
+			coins:    testCoins(4000, 10000),
+			target:   5000,
+			strategy: BranchAndBound,
+			want:     []ltcutil.Amount{10000},
+		},
+		{
+			name:     "branch and bound insufficient",
+			coins:    testCoins(1000, 2000),
+			target:   6000,
+			strategy: BranchAndBound,
+			err:      ErrInsufficientFunds,
+		},
+		{
+			name:     "nothing needed",
+			coins:    testCoins(1000),
+			target:   0,
+			strategy: BranchAndBound,
+			want:     []ltcutil.Amount{},
+		},
+	}
+
+	for _, test := range tests {
+		selected, err := SelectCoins(test.coins, test.target, 0,
+			test.costOfChange, test.strategy)
+		if err != test.err {
+			t.Errorf("%s: unexpected error: got %v, want %v",
+				test.name, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := coinValues(selected); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+// TestSelectCoinsEffectiveValue ensures coins which cost more to spend than
+// they are worth are never selected and that the fee of spending the selected
+// coins is accounted for.
+func TestSelectCoinsEffectiveValue(t *testing.T) {
+	t.Parallel()
+
+	// At 100 sat/vB a P2WPKH input costs 6900 satoshi to spend.
+	const feeRate = 100000
+	coins := testCoins(5000, 20000, 20001)
+
+	if _, err := SelectCoins(coins[:1], 1, feeRate, 0, LargestFirst); err !=
+		ErrInsufficientFunds {
+
+		t.Fatalf("unexpected error selecting uneconomical coin: %v", err)
+	}
+
+	selected, err := SelectCoins(coins, 13102, feeRate, 0, LargestFirst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []ltcutil.Amount{20000, 20001}
+	if got := coinValues(selected); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,432 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/btcec/v2/ecdsa"
+	"github.com/ltcsuite/ltcd/btcec/v2/schnorr"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+var (
+	// ErrMissingPrevOut is returned for an input whose previous output is
+	// not known.
+	ErrMissingPrevOut = errors.New("previous output not found or " +
+		"already spent")
+
+	// ErrMissingKey is returned for an input for which no private key is
+	// available.
+	ErrMissingKey = errors.New("no private key available to sign input")
+
+	// ErrMissingScript is returned for a pay-to-script-hash or
+	// pay-to-witness-script-hash input whose script is not known.
+	ErrMissingScript = errors.New("no redeem or witness script available " +
+		"to sign input")
+)
+
+// keyEntry is a private key along with whether its public key is serialized
+// in compressed form.
+type keyEntry struct {
+	key        *btcec.PrivateKey
+	compressed bool
+}
+
+// KeyStore holds the private keys and scripts used to sign transactions.
+// Keys are matched against the outputs being spent by public key hash, public
+// key, or BIP 86 taproot output key, while scripts are matched by their
+// pay-to-script-hash and pay-to-witness-script-hash hashes.
+//
+// KeyStore implements the txscript.KeyDB and txscript.ScriptDB interfaces.
+type KeyStore struct {
+	params  *chaincfg.Params
+	keys    map[[20]byte]keyEntry
+	taproot map[[32]byte]*btcec.PrivateKey
+	scripts map[string][]byte
+}
+
+// Ensure KeyStore implements the txscript.KeyDB and txscript.ScriptDB
+// interfaces.
+var _ txscript.KeyDB = (*KeyStore)(nil)
+var _ txscript.ScriptDB = (*KeyStore)(nil)
+
+// NewKeyStore returns an empty KeyStore for the passed network.
+func NewKeyStore(params *chaincfg.Params) *KeyStore {
+	return &KeyStore{
+		params:  params,
+		keys:    make(map[[20]byte]keyEntry),
+		taproot: make(map[[32]byte]*btcec.PrivateKey),
+		scripts: make(map[string][]byte),
+	}
+}
+
+// AddKey adds the private key of the passed WIF to the store.  The key may be
+// used to spend outputs paying to either serialization of its public key, as
+// well as the BIP 86 taproot output derived from it.
+func (ks *KeyStore) AddKey(wif *ltcutil.WIF) {
+	pubKey := wif.PrivKey.PubKey()
+
+	var compressedHash, uncompressedHash [20]byte
+	copy(compressedHash[:], ltcutil.Hash160(pubKey.SerializeCompressed()))
+	copy(uncompressedHash[:], ltcutil.Hash160(pubKey.SerializeUncompressed()))
+	ks.keys[compressedHash] = keyEntry{wif.PrivKey, true}
+	ks.keys[uncompressedHash] = keyEntry{wif.PrivKey, false}
+
+	var outputKey [32]byte
+	copy(outputKey[:], schnorr.SerializePubKey(
+		txscript.ComputeTaprootKeyNoScript(pubKey),
+	))
+	ks.taproot[outputKey] = wif.PrivKey
+}
+
+// AddScript adds a redeem or witness script to the store.
+func (ks *KeyStore) AddScript(script []byte) {
+	scriptHash := sha256.Sum256(script)
+	ks.scripts[string(ltcutil.Hash160(script))] = script
+	ks.scripts[string(scriptHash[:])] = script
+}
+
+// lookupKey returns the private key for the public key with the passed hash.
+func (ks *KeyStore) lookupKey(pubKeyHash []byte) (keyEntry, bool) {
+	var hash [20]byte
+	copy(hash[:], pubKeyHash)
+	entry, ok := ks.keys[hash]
+	return entry, ok
+}
+
+// lookupScript returns the script with the passed hash160 or sha256 hash.
+func (ks *KeyStore) lookupScript(scriptHash []byte) ([]byte, error) {
+	script, ok := ks.scripts[string(scriptHash)]
+	if !ok {
+		return nil, ErrMissingScript
+	}
+	return script, nil
+}
+
+// GetKey returns the private key for the passed address and whether its
+// public key is compressed.
+//
+// This is part of the txscript.KeyDB interface.
+func (ks *KeyStore) GetKey(addr ltcutil.Address) (*btcec.PrivateKey, bool, error) {
+	var pubKeyHash []byte
+	switch addr := addr.(type) {
+	case *ltcutil.AddressPubKeyHash:
+		pubKeyHash = addr.ScriptAddress()
+	case *ltcutil.AddressWitnessPubKeyHash:
+		pubKeyHash = addr.ScriptAddress()
+	case *ltcutil.AddressPubKey:
+		pubKeyHash = ltcutil.Hash160(addr.ScriptAddress())
+	default:
+		return nil, false, fmt.Errorf("unsupported address type %T", addr)
+	}
+
+	entry, ok := ks.lookupKey(pubKeyHash)
+	if !ok {
+		return nil, false, ErrMissingKey
+	}
+	return entry.key, entry.compressed, nil
+}
+
+// GetScript returns the redeem or witness script for the passed address.
+//
+// This is part of the txscript.ScriptDB interface.
+func (ks *KeyStore) GetScript(addr ltcutil.Address) ([]byte, error) {
+	switch addr.(type) {
+	case *ltcutil.AddressScriptHash, *ltcutil.AddressWitnessScriptHash:
+		return ks.lookupScript(addr.ScriptAddress())
+	}
+	return nil, fmt.Errorf("unsupported address type %T", addr)
+}
+
+// SignTransaction signs every input of tx it holds the keys for, merging the
+// new signatures with any already present, and then verifies each input.
+// prevOuts holds the output spent by each input, with nil entries for unknown
+// outputs.  Pay-to-pubkey, pay-to-pubkey-hash, multisig, pay-to-script-hash
+// (including nested segwit), pay-to-witness-pubkey-hash,
+// pay-to-witness-script-hash, and BIP 86 taproot key path inputs are
+// supported.  Taproot inputs signed with SigHashAll use SigHashDefault, which
+// commits to the same data with a shorter signature.
+//
+// The returned slice holds an entry for each input, which is nil when the
+// input is fully signed, including inputs which were already fully signed,
+// and the reason it isn't otherwise.
+func (ks *KeyStore) SignTransaction(tx *wire.MsgTx, prevOuts []*wire.TxOut,
+	hashType txscript.SigHashType) []error {
+
+	// Taproot signatures commit to every output being spent, so they can
+	// only be created when all of them are known.
+	allKnown := true
+	fetcherOuts := make(map[wire.OutPoint]*wire.TxOut, len(tx.TxIn))
+	for i, txIn := range tx.TxIn {
+		prevOut := prevOuts[i]
+		if prevOut == nil {
+			allKnown = false
+			prevOut = wire.NewTxOut(0, nil)
+		}
+		fetcherOuts[txIn.PreviousOutPoint] = prevOut
+	}
+	fetcher := txscript.NewMultiPrevOutFetcher(fetcherOuts)
+	sigHashes := txscript.NewTxSigHashes(tx, fetcher)
+
+	errs := make([]error, len(tx.TxIn))
+	for i := range tx.TxIn {
+		prevOut := prevOuts[i]
+		if prevOut == nil {
+			errs[i] = ErrMissingPrevOut
+			continue
+		}
+		if txscript.IsPayToTaproot(prevOut.PkScript) && !allKnown {
+			errs[i] = errors.New("all previous outputs must be " +
+				"known to sign taproot inputs")
+			continue
+		}
+
+		// Failing to sign an input is only an error when it isn't
+		// already fully signed.
+		signErr := ks.signInput(tx, i, prevOut, sigHashes, hashType)
+		vm, err := txscript.NewEngine(
+			prevOut.PkScript, tx, i, txscript.StandardVerifyFlags,
+			nil, sigHashes, prevOut.Value, fetcher,
+		)
+		if err == nil {
+			err = vm.Execute()
+		}
+		if err != nil && signErr != nil {
+			err = signErr
+		}
+		errs[i] = err
+	}
+	return errs
+}
+
+// signInput signs input idx of tx spending prevOut.
+func (ks *KeyStore) signInput(tx *wire.MsgTx, idx int, prevOut *wire.TxOut,
+	sigHashes *txscript.TxSigHashes, hashType txscript.SigHashType) error {
+
+	txIn := tx.TxIn[idx]
+	pkScript := prevOut.PkScript
+
+	switch txscript.GetScriptClass(pkScript) {
+	case txscript.WitnessV0PubKeyHashTy, txscript.WitnessV0ScriptHashTy:
+		witness, err := ks.signWitness(tx, idx, pkScript, prevOut.Value,
+			sigHashes, hashType, txIn.Witness)
+		if err != nil {
+			return err
+		}
+		txIn.Witness = witness
+		return nil
+
+	case txscript.WitnessV1TaprootTy:
+		var outputKey [32]byte
+		copy(outputKey[:], pkScript[2:])
+		key, ok := ks.taproot[outputKey]
+		if !ok {
+			return ErrMissingKey
+		}
+		if hashType == txscript.SigHashAll {
+			hashType = txscript.SigHashDefault
+		}
+		witness, err := txscript.TaprootWitnessSignature(tx, sigHashes,
+			idx, prevOut.Value, pkScript, hashType, key)
+		if err != nil {
+			return err
+		}
+		txIn.Witness = witness
+		return nil
+
+	case txscript.ScriptHashTy:
+		redeemScript, err := ks.lookupScript(pkScript[2:22])
+		if err != nil {
+			return err
+		}
+
+		// Nested segwit outputs are spent by pushing the witness
+		// program and providing the witness.
+		if txscript.IsWitnessProgram(redeemScript) {
+			sigScript, err := txscript.NewScriptBuilder().
+				AddData(redeemScript).Script()
+			if err != nil {
+				return err
+			}
+			witness, err := ks.signWitness(tx, idx, redeemScript,
+				prevOut.Value, sigHashes, hashType, txIn.Witness)
+			if err != nil {
+				return err
+			}
+			txIn.SignatureScript = sigScript
+			txIn.Witness = witness
+			return nil
+		}
+	}
+
+	if hashType == txscript.SigHashDefault {
+		hashType = txscript.SigHashAll
+	}
+	sigScript, err := txscript.SignTxOutput(ks.params, tx, idx, pkScript,
+		hashType, ks, ks, txIn.SignatureScript)
+	if err != nil {
+		return err
+	}
+	txIn.SignatureScript = sigScript
+	return nil
+}
+
+// signWitness returns the witness spending the passed version 0 witness
+// program.
+func (ks *KeyStore) signWitness(tx *wire.MsgTx, idx int, program []byte,
+	amount int64, sigHashes *txscript.TxSigHashes,
+	hashType txscript.SigHashType, prevWitness wire.TxWitness) (wire.TxWitness, error) {
+
+	if hashType == txscript.SigHashDefault {
+		hashType = txscript.SigHashAll
+	}
+
+	if txscript.IsPayToWitnessPubKeyHash(program) {
+		entry, ok := ks.lookupKey(program[2:])
+		if !ok {
+			return nil, ErrMissingKey
+		}
+		return txscript.WitnessSignature(tx, sigHashes, idx, amount,
+			program, hashType, entry.key, entry.compressed)
+	}
+
+	witnessScript, err := ks.lookupScript(program[2:])
+	if err != nil {
+		return nil, err
+	}
+	sign := func(entry keyEntry) ([]byte, error) {
+		return txscript.RawTxInWitnessSignature(tx, sigHashes, idx,
+			amount, witnessScript, hashType, entry.key)
+	}
+
+	class, addrs, nRequired, err := txscript.ExtractPkScriptAddrs(
+		witnessScript, ks.params,
+	)
+	if err != nil {
+		return nil, err
+	}
+	switch class {
+	case txscript.PubKeyTy:
+		entry, ok := ks.lookupKey(ltcutil.Hash160(addrs[0].ScriptAddress()))
+		if !ok {
+			return nil, ErrMissingKey
+		}
+		sig, err := sign(entry)
+		if err != nil {
+			return nil, err
+		}
+		return wire.TxWitness{sig, witnessScript}, nil
+
+	case txscript.PubKeyHashTy:
+		entry, ok := ks.lookupKey(addrs[0].ScriptAddress())
+		if !ok {
+			return nil, ErrMissingKey
+		}
+		sig, err := sign(entry)
+		if err != nil {
+			return nil, err
+		}
+		pubKey := entry.key.PubKey().SerializeUncompressed()
+		if entry.compressed {
+			pubKey = entry.key.PubKey().SerializeCompressed()
+		}
+		return wire.TxWitness{sig, pubKey, witnessScript}, nil
+
+	case txscript.MultiSigTy:
+		return ks.signWitnessMultiSig(tx, idx, witnessScript, amount,
+			sigHashes, addrs, nRequired, prevWitness, sign)
+	}
+
+	return nil, fmt.Errorf("unsupported witness script type %v", class)
+}
+
+// signWitnessMultiSig returns the witness spending the passed multisig
+// witness script, keeping the valid signatures of prevWitness and adding
+// signatures for the public keys whose private keys are known, up to the
+// number required.
+func (ks *KeyStore) signWitnessMultiSig(tx *wire.MsgTx, idx int,
+	witnessScript []byte, amount int64, sigHashes *txscript.TxSigHashes,
+	addrs []ltcutil.Address, nRequired int, prevWitness wire.TxWitness,
+	sign func(keyEntry) ([]byte, error)) (wire.TxWitness, error) {
+
+	pubKeys := make([]*btcec.PublicKey, len(addrs))
+	for i, addr := range addrs {
+		pubKeys[i] = addr.(*ltcutil.AddressPubKey).PubKey()
+	}
+
+	// Assign the signatures of any previous witness for the same script
+	// to the public keys they are valid for.
+	sigs := make([][]byte, len(addrs))
+	if len(prevWitness) > 2 &&
+		bytes.Equal(prevWitness[len(prevWitness)-1], witnessScript) {
+
+		for _, sig := range prevWitness[1 : len(prevWitness)-1] {
+			if len(sig) == 0 {
+				continue
+			}
+			sigHashType := txscript.SigHashType(sig[len(sig)-1])
+			hash, err := txscript.CalcWitnessSigHash(witnessScript,
+				sigHashes, sigHashType, tx, idx, amount)
+			if err != nil {
+				continue
+			}
+			parsedSig, err := ecdsa.ParseDERSignature(sig[:len(sig)-1])
+			if err != nil {
+				continue
+			}
+			for i, pubKey := range pubKeys {
+				if sigs[i] == nil && parsedSig.Verify(hash, pubKey) {
+					sigs[i] = sig
+					break
+				}
+			}
+		}
+	}
+
+	numSigs := 0
+	for _, sig := range sigs {
+		if sig != nil {
+			numSigs++
+		}
+	}
+	for i, addr := range addrs {
+		if numSigs >= nRequired {
+			break
+		}
+		if sigs[i] != nil {
+			continue
+		}
+		entry, ok := ks.lookupKey(ltcutil.Hash160(addr.ScriptAddress()))
+		if !ok {
+			continue
+		}
+		sig, err := sign(entry)
+		if err != nil {
+			return nil, err
+		}
+		sigs[i] = sig
+		numSigs++
+	}
+
+	// The extra element consumed by OP_CHECKMULTISIG is followed by the
+	// signatures in public key order and the witness script.
+	witness := wire.TxWitness{nil}
+	numSigs = 0
+	for _, sig := range sigs {
+		if sig != nil && numSigs < nRequired {
+			witness = append(witness, sig)
+			numSigs++
+		}
+	}
+	return append(witness, witnessScript), nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/btcec/v2"
+	"github.com/ltcsuite/ltcd/btcec/v2/schnorr"
+	"github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// newTestKey returns a deterministic compressed WIF key derived from seed.
+func newTestKey(t *testing.T, seed byte) *ltcutil.WIF {
+	t.Helper()
+
+	privKey, _ := btcec.PrivKeyFromBytes(chainhash.HashB([]byte{seed}))
+	wif, err := ltcutil.NewWIF(privKey, &chaincfg.MainNetParams, true)
+	if err != nil {
+		t.Fatalf("NewWIF: unexpected error: %v", err)
+	}
+	return wif
+}
+
+// TestSignTransaction ensures inputs of every supported type are signed and
+// the per-input errors are reported.
+func TestSignTransaction(t *testing.T) {
+	t.Parallel()
+
+	params := &chaincfg.MainNetParams
+	payToAddr := func(addr ltcutil.Address, err error) []byte {
+		if err != nil {
+			t.Fatalf("unable to create address: %v", err)
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatalf("PayToAddrScript: unexpected error: %v", err)
+		}
+		return pkScript
+	}
+	key1, key2, key3 := newTestKey(t, 1), newTestKey(t, 2), newTestKey(t, 3)
+	pubKey1 := key1.SerializePubKey()
+	pkHash1 := ltcutil.Hash160(pubKey1)
+
+	// A 2-of-3 multisig witness script.
+	var multiSigKeys []*ltcutil.AddressPubKey
+	for _, key := range []*ltcutil.WIF{key1, key2, key3} {
+		addr, err := ltcutil.NewAddressPubKey(key.SerializePubKey(), params)
+		if err != nil {
+			t.Fatalf("NewAddressPubKey: unexpected error: %v", err)
+		}
+		multiSigKeys = append(multiSigKeys, addr)
+	}
+	multiSigScript, err := txscript.MultiSigScript(multiSigKeys, 2)
+	if err != nil {
+		t.Fatalf("MultiSigScript: unexpected error: %v", err)
+	}
+	multiSigHash := sha256.Sum256(multiSigScript)
+
+	// The witness program nested in a pay-to-script-hash output.
+	nestedProgram := payToAddr(ltcutil.NewAddressWitnessPubKeyHash(
+		pkHash1, params))
+
+	taprootKey := txscript.ComputeTaprootKeyNoScript(key1.PrivKey.PubKey())
+
+	prevScripts := []struct {
+		name     string
+		pkScript []byte
+	}{
+		{"p2pkh", payToAddr(ltcutil.NewAddressPubKeyHash(pkHash1,
+			params))},
+		{"p2wpkh", nestedProgram},
+		{"p2sh-p2wpkh", payToAddr(ltcutil.NewAddressScriptHash(
+			nestedProgram, params))},
+		{"p2wsh multisig", payToAddr(
+			ltcutil.NewAddressWitnessScriptHash(multiSigHash[:], params))},
+		{"p2tr", payToAddr(ltcutil.NewAddressTaproot(
+			schnorr.SerializePubKey(taprootKey), params))},
+	}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	prevOuts := make([]*wire.TxOut, 0, len(prevScripts)+1)
+	for i, prevScript := range prevScripts {
+		op := wire.OutPoint{Index: uint32(i)}
+		tx.AddTxIn(wire.NewTxIn(&op, nil, nil))
+		prevOuts = append(prevOuts, wire.NewTxOut(100000,
+			prevScript.pkScript))
+	}
+	tx.AddTxOut(wire.NewTxOut(400000, prevScripts[0].pkScript))
+
+	// Sign with the first key only, which leaves the multisig input
+	// incomplete.
+	ks := NewKeyStore(params)
+	ks.AddKey(key1)
+	ks.AddScript(nestedProgram)
+	ks.AddScript(multiSigScript)
+	errs := ks.SignTransaction(tx, prevOuts, txscript.SigHashAll)
+	for i, prevScript := range prevScripts {
+		if i == 3 {
+			if errs[i] == nil {
+				t.Fatalf("%s: incomplete input reported as "+
+					"signed", prevScript.name)
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Fatalf("%s: unexpected error: %v", prevScript.name,
+				errs[i])
+		}
+	}
+	if n := len(tx.TxIn[3].Witness); n != 3 {
+		t.Fatalf("unexpected partial multisig witness length %d", n)
+	}
+	if n := len(tx.TxIn[4].Witness[0]); n != schnorr.SignatureSize {
+		t.Fatalf("unexpected taproot signature length %d", n)
+	}
+
+	// Signing with another key completes the multisig input while keeping
+	// the existing signature.
+	ks = NewKeyStore(params)
+	ks.AddKey(key3)
+	ks.AddScript(multiSigScript)
+	errs = ks.SignTransaction(tx, prevOuts, txscript.SigHashAll)
+	if errs[3] != nil {
+		t.Fatalf("unexpected error completing multisig input: %v",
+			errs[3])
+	}
+	if n := len(tx.TxIn[3].Witness); n != 4 {
+		t.Fatalf("unexpected multisig witness length %d", n)
+	}
+
+	// The inputs already signed with the first key remain valid despite
+	// the key being unavailable.
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("%s: unexpected error for signed input: %v",
+				prevScripts[i].name, err)
+		}
+	}
+
+	// Inputs which can't be signed are reported.
+	tx.TxIn[0].SignatureScript = nil
+	errs = ks.SignTransaction(tx, prevOuts, txscript.SigHashAll)
+	if errs[0] != ErrMissingKey {
+		t.Fatalf("unexpected error without key: %v", errs[0])
+	}
+
+	prevOuts[0] = nil
+	errs = ks.SignTransaction(tx, prevOuts, txscript.SigHashAll)
+	if errs[0] != ErrMissingPrevOut {
+		t.Fatalf("unexpected error for unknown previous output: %v",
+			errs[0])
+	}
+	if errs[4] == nil {
+		t.Fatal("signed taproot input without knowing all previous " +
+			"outputs")
+	}
+}
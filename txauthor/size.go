@@ -0,0 +1,125 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor
+
+import (
+	"github.com/ltcsuite/ltcd/blockchain"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	"github.com/ltcsuite/ltcd/txscript"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// redeemP2PKSigScriptSize is the worst case size of a signature script
+	// redeeming a pay-to-pubkey output.  It is calculated as:
+	//
+	//  - OP_DATA_73
+	//  - 72 bytes DER signature + 1 byte sighash
+	redeemP2PKSigScriptSize = 1 + 73
+
+	// redeemP2PKHSigScriptSize is the worst case size of a signature script
+	// redeeming a pay-to-pubkey-hash output.  It is calculated as:
+	//
+	//  - OP_DATA_73
+	//  - 72 bytes DER signature + 1 byte sighash
+	//  - OP_DATA_33
+	//  - 33 bytes compressed public key
+	redeemP2PKHSigScriptSize = 1 + 73 + 1 + 33
+
+	// nestedP2WPKHSigScriptSize is the size of a signature script redeeming
+	// a pay-to-script-hash output wrapping a pay-to-witness-pubkey-hash
+	// script.  It is calculated as:
+	//
+	//  - OP_DATA_22
+	//  - 22 bytes witness program
+	nestedP2WPKHSigScriptSize = 1 + 22
+
+	// redeemP2WPKHWitnessSize is the worst case size of a witness redeeming
+	// a pay-to-witness-pubkey-hash output.  It is calculated as:
+	//
+	//  - 1 byte witness item count
+	//  - 1 byte signature length
+	//  - 72 bytes DER signature + 1 byte sighash
+	//  - 1 byte public key length
+	//  - 33 bytes compressed public key
+	redeemP2WPKHWitnessSize = 1 + 1 + 73 + 1 + 33
+
+	// redeemP2TRKeyPathWitnessSize is the size of a witness redeeming a
+	// pay-to-taproot output using the key path with the default sighash
+	// type.  It is calculated as:
+	//
+	//  - 1 byte witness item count
+	//  - 1 byte signature length
+	//  - 64 bytes schnorr signature
+	redeemP2TRKeyPathWitnessSize = 1 + 1 + 64
+
+	// segwitMarkerWeight is the weight of the marker and flag bytes which
+	// are serialized when any input has a witness.
+	segwitMarkerWeight = 2
+)
+
+// inputSize returns the serialized size of a transaction input with a
+// signature script of the passed length, excluding any witness.
+func inputSize(sigScriptSize int) int64 {
+	// Outpoint hash + outpoint index + script length + script + sequence.
+	return int64(32 + 4 + wire.VarIntSerializeSize(uint64(sigScriptSize)) +
+		sigScriptSize + 4)
+}
+
+// InputWeight returns the estimated weight of an input spending an output
+// with the passed public key script once it has been signed.  The second
+// return value is false when the script type is not supported.
+func InputWeight(pkScript []byte) (int64, bool) {
+	const scale = blockchain.WitnessScaleFactor
+
+	switch txscript.GetScriptClass(pkScript) {
+	case txscript.PubKeyTy:
+		return inputSize(redeemP2PKSigScriptSize) * scale, true
+
+	case txscript.PubKeyHashTy:
+		return inputSize(redeemP2PKHSigScriptSize) * scale, true
+
+	case txscript.WitnessV0PubKeyHashTy:
+		return inputSize(0)*scale + redeemP2WPKHWitnessSize, true
+
+	case txscript.ScriptHashTy:
+		// The redeem script of a pay-to-script-hash output is unknown,
+		// so assume the common case of a nested
+		// pay-to-witness-pubkey-hash script.
+		return inputSize(nestedP2WPKHSigScriptSize)*scale +
+			redeemP2WPKHWitnessSize, true
+
+	case txscript.WitnessV1TaprootTy:
+		return inputSize(0)*scale + redeemP2TRKeyPathWitnessSize, true
+	}
+
+	return 0, false
+}
+
+// OutputWeight returns the weight of the passed transaction output.
+func OutputWeight(txOut *wire.TxOut) int64 {
+	return int64(txOut.SerializeSize()) * blockchain.WitnessScaleFactor
+}
+
+// baseWeight returns the weight of a transaction with the passed number of
+// inputs and outputs, excluding the inputs and outputs themselves.  The
+// weight of the segwit marker and flag is always included so the estimate
+// never falls short.
+func baseWeight(numInputs, numOutputs int) int64 {
+	// Version + input count + output count + lock time.
+	size := 4 + wire.VarIntSerializeSize(uint64(numInputs)) +
+		wire.VarIntSerializeSize(uint64(numOutputs)) + 4
+	return int64(size)*blockchain.WitnessScaleFactor + segwitMarkerWeight
+}
+
+// FeeForWeight returns the fee required for a transaction of the passed weight
+// to pay the passed fee rate, which is expressed in satoshi per 1000 virtual
+// bytes.  The result is rounded up, so the fees of the parts of a transaction
+// always add up to at least the fee of the whole.
+func FeeForWeight(weight int64, feeRate ltcutil.Amount) ltcutil.Amount {
+	const scale = blockchain.WitnessScaleFactor
+	vsize := (weight + scale - 1) / scale
+	return (ltcutil.Amount(vsize)*feeRate + 999) / 1000
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"strings"
+
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// StepState is a snapshot of the script engine state taken by a StepDebugger.
+type StepState struct {
+	// ScriptIndex is the index of the script the next opcode will be
+	// executed from.  Index 0 is the signature script, 1 is the public
+	// key script, and any subsequent indexes are redeem or witness scripts.
+	ScriptIndex int
+
+	// OpcodeIndex is the index of the next opcode to execute within the
+	// script identified by ScriptIndex.
+	OpcodeIndex int
+
+	// Opcode is the disassembly of the opcode executed by the step that
+	// produced this state.  It is empty for the initial state.
+	Opcode string
+
+	// Stack and AltStack are copies of the data and alternate stacks.
+	Stack    [][]byte
+	AltStack [][]byte
+
+	// CondStack is a copy of the conditional execution state, innermost
+	// conditional last.  Each entry is one of OpCondFalse, OpCondTrue, or
+	// OpCondSkip.
+	CondStack []int
+
+	// Remaining is the disassembly of the opcodes left to execute in the
+	// current script.
+	Remaining []string
+
+	// Done is set once every script has been executed, or execution
+	// stopped due to an error.
+	Done bool
+}
+
+// StepDebugger executes scripts one opcode at a time and exposes the engine
+// state after every step.  It is intended to diagnose why a script fails
+// validation and SHOULD NOT be used during regular operation.
+type StepDebugger struct {
+	vm    *Engine
+	state *StepState
+	err   error
+}
+
+// NewStepDebugger returns a new debugger for the script engine that would be
+// created by NewEngine with the same parameters.
+func NewStepDebugger(scriptPubKey []byte, tx *wire.MsgTx, txIdx int,
+	flags ScriptFlags, sigCache *SigCache, hashCache *TxSigHashes,
+	inputAmount int64, prevOutFetcher PrevOutputFetcher) (*StepDebugger, error) {
+
+	vm, err := NewEngine(
+		scriptPubKey, tx, txIdx, flags, sigCache, hashCache,
+		inputAmount, prevOutFetcher,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &StepDebugger{vm: vm}
+	d.state = d.snapshot("")
+
+	// All script versions other than 0 currently execute without issue,
+	// so there is nothing to step through.
+	if vm.version != 0 {
+		d.state.Done = true
+		d.state.Remaining = nil
+	}
+	return d, nil
+}
+
+// snapshot returns the current engine state.
+func (d *StepDebugger) snapshot(opcode string) *StepState {
+	vm := d.vm
+	state := &StepState{
+		ScriptIndex: vm.scriptIdx,
+		OpcodeIndex: vm.opcodeIdx,
+		Opcode:      opcode,
+		Stack:       copyStack(vm.dstack.stk),
+		AltStack:    copyStack(vm.astack.stk),
+		CondStack:   append([]int(nil), vm.condStack...),
+	}
+
+	if vm.scriptIdx < len(vm.scripts) {
+		// Disassemble from a copy of the tokenizer to avoid mutating
+		// the program counter.
+		tokenizer := vm.tokenizer
+		for tokenizer.Next() {
+			var buf strings.Builder
+			disasmOpcode(&buf, tokenizer.op, tokenizer.Data(), false)
+			state.Remaining = append(state.Remaining, buf.String())
+		}
+	}
+
+	return state
+}
+
+// State returns the engine state after the most recent step, or the initial
+// state if Step has not been called.
+func (d *StepDebugger) State() *StepState {
+	return d.state
+}
+
+// Done returns whether execution has finished.
+func (d *StepDebugger) Done() bool {
+	return d.state.Done
+}
+
+// Err returns the error that stopped execution, if any.  Once Done returns
+// true, a nil error means the scripts executed successfully.
+func (d *StepDebugger) Err() error {
+	return d.err
+}
+
+// Step executes the next opcode and returns the resulting engine state along
+// with any error that stopped execution.  When the final opcode has been
+// executed, the returned error is the result of the final error condition
+// checks performed by Execute, while the returned state still reflects the
+// stacks prior to those checks.  Calling Step once execution is done returns
+// the final state and error again.
+func (d *StepDebugger) Step() (*StepState, error) {
+	if d.state.Done {
+		return d.state, d.err
+	}
+
+	// Disassemble the opcode about to be executed before stepping.
+	var opcode string
+	peekTokenizer := d.vm.tokenizer
+	if peekTokenizer.Next() {
+		var buf strings.Builder
+		disasmOpcode(&buf, peekTokenizer.op, peekTokenizer.Data(), false)
+		opcode = buf.String()
+	}
+
+	done, err := d.vm.Step()
+	d.state = d.snapshot(opcode)
+	switch {
+	case err != nil:
+		d.err = err
+		d.state.Done = true
+
+	case done:
+		d.err = d.vm.CheckErrorCondition(true)
+		d.state.Done = true
+	}
+
+	return d.state, d.err
+}
+
+// Run executes all remaining opcodes and returns the state after every step,
+// starting with the current state.  The returned error is the same as Err.
+func (d *StepDebugger) Run() ([]*StepState, error) {
+	trace := []*StepState{d.state}
+	for !d.state.Done {
+		state, _ := d.Step()
+		trace = append(trace, state)
+	}
+	return trace, d.err
+}
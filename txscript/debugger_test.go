@@ -0,0 +1,91 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStepDebugger ensures the StepDebugger exposes the expected engine state
+// after each step.
+func TestStepDebugger(t *testing.T) {
+	t.Parallel()
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{})
+	tx.AddTxOut(&wire.TxOut{})
+
+	pkScript := mustParseShortForm("1 IF 2 ELSE 3 ENDIF 2 EQUAL")
+	d, err := NewStepDebugger(pkScript, tx, 0, 0, nil, nil, 0, nil)
+	require.NoError(t, err)
+
+	// The empty signature script is skipped.
+	initial := d.State()
+	require.Equal(t, 1, initial.ScriptIndex)
+	require.Equal(t, 0, initial.OpcodeIndex)
+	require.Empty(t, initial.Opcode)
+	require.Len(t, initial.Remaining, 8)
+	require.Equal(t, "OP_1", initial.Remaining[0])
+	require.False(t, initial.Done)
+
+	state, err := d.Step()
+	require.NoError(t, err)
+	require.Equal(t, "OP_1", state.Opcode)
+	require.Equal(t, [][]byte{{1}}, state.Stack)
+	require.Len(t, state.Remaining, 7)
+
+	state, err = d.Step()
+	require.NoError(t, err)
+	require.Equal(t, "OP_IF", state.Opcode)
+	require.Equal(t, []int{OpCondTrue}, state.CondStack)
+	require.Empty(t, state.Stack)
+
+	d.Step()
+	state, err = d.Step()
+	require.NoError(t, err)
+	require.Equal(t, "OP_ELSE", state.Opcode)
+	require.Equal(t, []int{OpCondFalse}, state.CondStack)
+	require.Equal(t, [][]byte{{2}}, state.Stack)
+
+	trace, err := d.Run()
+	require.NoError(t, err)
+	require.Len(t, trace, 5)
+	require.Equal(t, state, trace[0])
+
+	final := trace[len(trace)-1]
+	require.True(t, final.Done)
+	require.True(t, d.Done())
+	require.Equal(t, "OP_EQUAL", final.Opcode)
+	require.Equal(t, [][]byte{{1}}, final.Stack)
+	require.Empty(t, final.CondStack)
+	require.Empty(t, final.Remaining)
+
+	// Further steps return the final state.
+	state, err = d.Step()
+	require.NoError(t, err)
+	require.Equal(t, final, state)
+
+	// Failing scripts report the error along with the state at the time
+	// of failure.
+	pkScript = mustParseShortForm("2 3 EQUAL")
+	d, err = NewStepDebugger(pkScript, tx, 0, 0, nil, nil, 0, nil)
+	require.NoError(t, err)
+	trace, err = d.Run()
+	require.True(t, IsErrorCode(err, ErrEvalFalse))
+	require.Equal(t, err, d.Err())
+	require.Len(t, trace, 4)
+	require.Equal(t, [][]byte{{}}, trace[3].Stack)
+
+	pkScript = mustParseShortForm("1 VERIFY 0 VERIFY 1")
+	d, err = NewStepDebugger(pkScript, tx, 0, 0, nil, nil, 0, nil)
+	require.NoError(t, err)
+	trace, err = d.Run()
+	require.True(t, IsErrorCode(err, ErrVerify))
+	require.Equal(t, "OP_VERIFY", trace[len(trace)-1].Opcode)
+	require.Equal(t, []string{"OP_1"}, trace[len(trace)-1].Remaining)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+// ScriptCache caches the inputs whose scripts have been successfully validated
+// so that validating them again, for example when generating successive block
+// templates or re-validating a block, doesn't execute the scripts at all.
+// Like SigCache, only successful validations are cached, entries are evicted
+// at random once the cache is full, and the cache is split into lock-striped
+// shards to reduce contention.
+type ScriptCache struct {
+	cache shardedCache
+}
+
+// NewScriptCache creates and initializes a new instance of ScriptCache which
+// holds up to maxEntries validated inputs.
+func NewScriptCache(maxEntries uint) *ScriptCache {
+	s := &ScriptCache{}
+	s.cache.init(maxEntries)
+	return s
+}
+
+// ScriptCacheKey returns the key identifying the validation of a transaction
+// input under the passed script flags.
+//
+// The signature script alone does not determine whether an input is valid
+// since signatures commit to the rest of the transaction, so the key commits to
+// the witness hash of the spending transaction, which covers both the
+// signature script and witness, along with the input index and the public key
+// script and amount of the output being spent.
+func ScriptCacheKey(wtxid *chainhash.Hash, txIdx int, pkScript []byte,
+	amount int64, flags ScriptFlags) chainhash.Hash {
+
+	var buf [chainhash.HashSize + 4 + 8 + 4]byte
+	copy(buf[:], wtxid[:])
+	offset := chainhash.HashSize
+	binary.LittleEndian.PutUint32(buf[offset:], uint32(txIdx))
+	offset += 4
+	binary.LittleEndian.PutUint64(buf[offset:], uint64(amount))
+	offset += 8
+	binary.LittleEndian.PutUint32(buf[offset:], uint32(flags))
+
+	h := sha256.New()
+	h.Write(buf[:])
+	h.Write(pkScript)
+
+	var key chainhash.Hash
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// Exists returns whether the input identified by the passed key, as returned
+// by ScriptCacheKey, has previously been validated.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *ScriptCache) Exists(key chainhash.Hash) bool {
+	_, ok := s.cache.lookup(&key)
+	s.cache.recordLookup(ok)
+	return ok
+}
+
+// Add records that the input identified by the passed key, as returned by
+// ScriptCacheKey, was successfully validated.  In the event that the cache is
+// full, an existing entry is randomly chosen to be evicted in order to make
+// space for the new entry.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *ScriptCache) Add(key chainhash.Hash) {
+	s.cache.add(&key, sigCacheEntry{})
+}
+
+// Stats returns a snapshot of the script cache usage statistics.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *ScriptCache) Stats() CacheStats {
+	return s.cache.stats()
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+)
+
+// TestScriptCacheKey ensures the script cache key commits to every field.
+func TestScriptCacheKey(t *testing.T) {
+	t.Parallel()
+
+	wtxid := chainhash.HashH([]byte("tx"))
+	otherWtxid := chainhash.HashH([]byte("other tx"))
+	pkScript := []byte{OP_TRUE}
+
+	key := ScriptCacheKey(&wtxid, 0, pkScript, 1000, StandardVerifyFlags)
+	if key != ScriptCacheKey(&wtxid, 0, pkScript, 1000, StandardVerifyFlags) {
+		t.Fatal("script cache key is not deterministic")
+	}
+
+	others := []chainhash.Hash{
+		ScriptCacheKey(&otherWtxid, 0, pkScript, 1000, StandardVerifyFlags),
+		ScriptCacheKey(&wtxid, 1, pkScript, 1000, StandardVerifyFlags),
+		ScriptCacheKey(&wtxid, 0, []byte{OP_FALSE}, 1000,
+			StandardVerifyFlags),
+		ScriptCacheKey(&wtxid, 0, pkScript, 1001, StandardVerifyFlags),
+		ScriptCacheKey(&wtxid, 0, pkScript, 1000, ScriptBip16),
+	}
+	for i, other := range others {
+		if other == key {
+			t.Errorf("key #%d matches the original key", i)
+		}
+	}
+}
+
+// TestScriptCache ensures validated inputs are found in the script cache and
+// that the cache respects its maximum size.
+func TestScriptCache(t *testing.T) {
+	t.Parallel()
+
+	scriptCache := NewScriptCache(2)
+
+	var keys []chainhash.Hash
+	for i := 0; i < 3; i++ {
+		wtxid := chainhash.HashH([]byte{byte(i)})
+		keys = append(keys, ScriptCacheKey(&wtxid, 0, nil, 0, 0))
+	}
+
+	if scriptCache.Exists(keys[0]) {
+		t.Fatal("key found in empty cache")
+	}
+	scriptCache.Add(keys[0])
+	scriptCache.Add(keys[1])
+	if !scriptCache.Exists(keys[0]) || !scriptCache.Exists(keys[1]) {
+		t.Fatal("added key not found in cache")
+	}
+
+	// Adding a third key evicts one of the others.
+	scriptCache.Add(keys[2])
+	if !scriptCache.Exists(keys[2]) {
+		t.Fatal("added key not found in cache")
+	}
+	want := CacheStats{Hits: 3, Misses: 1, Entries: 2, MaxEntries: 2}
+	if stats := scriptCache.Stats(); stats != want {
+		t.Fatalf("unexpected stats: got %+v, want %+v", stats, want)
+	}
+
+	// Caches with no capacity never hold entries.
+	scriptCache = NewScriptCache(0)
+	scriptCache.Add(keys[0])
+	if scriptCache.Exists(keys[0]) {
+		t.Fatal("key found in cache without capacity")
+	}
+}
@@ -7,10 +7,30 @@ package txscript
 import (
 	"bytes"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 )
 
+// numCacheShards is the number of independently locked shards the signature
+// and script caches are split into.  Entries are assigned to a shard based on
+// their key, so concurrent validation goroutines rarely contend on the same
+// lock.  It must be a power of two.
+const numCacheShards = 32
+
+// CacheStats houses the usage statistics of a SigCache or ScriptCache.
+type CacheStats struct {
+	// Hits and Misses are the number of lookups which did and did not
+	// find a matching entry, respectively.
+	Hits   uint64
+	Misses uint64
+
+	// Entries is the number of entries currently in the cache and
+	// MaxEntries is the maximum number allowed.
+	Entries    uint
+	MaxEntries uint
+}
+
 // sigCacheEntry represents an entry in the SigCache. Entries within the
 // SigCache are keyed according to the sigHash of the signature. In the
 // scenario of a cache-hit (according to the sigHash), an additional comparison
@@ -22,6 +42,131 @@ type sigCacheEntry struct {
 	pubKey []byte
 }
 
+// cacheShard is a single lock-striped partition of a shardedCache.
+type cacheShard struct {
+	sync.RWMutex
+	entries map[chainhash.Hash]sigCacheEntry
+}
+
+// shardedCache is a bounded map from hashes to cache entries with a randomized
+// eviction policy.  It is split into numCacheShards shards, each protected by
+// its own lock, while the entry limit is enforced across the entire cache.
+type shardedCache struct {
+	// The following fields are accessed atomically and are placed first to
+	// guarantee 64-bit alignment on 32-bit platforms.
+	numEntries int64
+	hits       uint64
+	misses     uint64
+
+	maxEntries uint
+	shards     [numCacheShards]cacheShard
+}
+
+// init initializes the cache to hold up to maxEntries entries.
+func (c *shardedCache) init(maxEntries uint) {
+	c.maxEntries = maxEntries
+	for i := range c.shards {
+		c.shards[i].entries = make(map[chainhash.Hash]sigCacheEntry,
+			maxEntries/numCacheShards)
+	}
+}
+
+// shardIndex returns the index of the shard responsible for the passed key.
+// Keys are the output of cryptographic hash functions, so their leading byte
+// is uniformly distributed.
+func shardIndex(key *chainhash.Hash) int {
+	return int(key[0]) & (numCacheShards - 1)
+}
+
+// lookup returns the entry for the passed key, if any.
+func (c *shardedCache) lookup(key *chainhash.Hash) (sigCacheEntry, bool) {
+	shard := &c.shards[shardIndex(key)]
+	shard.RLock()
+	entry, ok := shard.entries[*key]
+	shard.RUnlock()
+	return entry, ok
+}
+
+// recordLookup updates the hit or miss counter for a lookup.
+func (c *shardedCache) recordLookup(hit bool) {
+	if hit {
+		atomic.AddUint64(&c.hits, 1)
+		return
+	}
+	atomic.AddUint64(&c.misses, 1)
+}
+
+// add adds or replaces the entry for the passed key.  In the event that the
+// cache is full, an existing entry other than the new one is randomly chosen
+// to be evicted in order to make space for it.
+func (c *shardedCache) add(key *chainhash.Hash, entry sigCacheEntry) {
+	if c.maxEntries <= 0 {
+		return
+	}
+
+	idx := shardIndex(key)
+	shard := &c.shards[idx]
+	shard.Lock()
+	_, exists := shard.entries[*key]
+	shard.entries[*key] = entry
+	shard.Unlock()
+	if exists {
+		return
+	}
+
+	if uint(atomic.AddInt64(&c.numEntries, 1)) > c.maxEntries {
+		c.evict(idx, key)
+	}
+}
+
+// evict removes a single entry other than keep from the cache, starting the
+// search at the shard with the passed index.  Only one shard lock is held at a
+// time to avoid lock ordering issues between concurrent callers.
+func (c *shardedCache) evict(start int, keep *chainhash.Hash) {
+	for i := 0; i < numCacheShards; i++ {
+		shard := &c.shards[(start+i)&(numCacheShards-1)]
+		shard.Lock()
+		// Remove a random entry from the map. Relying on the random
+		// starting point of Go's map iteration. It's worth noting that
+		// the random iteration starting point is not 100% guaranteed
+		// by the spec, however most Go compilers support it.
+		// Ultimately, the iteration order isn't important here because
+		// in order to manipulate which items are evicted, an adversary
+		// would need to be able to execute preimage attacks on the
+		// hashing function in order to start eviction at a specific
+		// entry.
+		for key := range shard.entries {
+			if key == *keep {
+				continue
+			}
+			delete(shard.entries, key)
+			shard.Unlock()
+			atomic.AddInt64(&c.numEntries, -1)
+			return
+		}
+		shard.Unlock()
+	}
+}
+
+// len returns the number of entries in the cache.
+func (c *shardedCache) len() uint {
+	n := atomic.LoadInt64(&c.numEntries)
+	if n < 0 {
+		return 0
+	}
+	return uint(n)
+}
+
+// stats returns a snapshot of the cache usage statistics.
+func (c *shardedCache) stats() CacheStats {
+	return CacheStats{
+		Hits:       atomic.LoadUint64(&c.hits),
+		Misses:     atomic.LoadUint64(&c.misses),
+		Entries:    c.len(),
+		MaxEntries: c.maxEntries,
+	}
+}
+
 // SigCache implements an Schnorr+ECDSA signature verification cache with a
 // randomized entry eviction policy. Only valid signatures will be added to the
 // cache. The benefits of SigCache are two fold. Firstly, usage of SigCache
@@ -34,11 +179,11 @@ type sigCacheEntry struct {
 // optimization which speeds up the validation of transactions within a block,
 // if they've already been seen and verified within the mempool.
 //
-// TODO(roasbeef): use type params here after Go 1.18
+// The cache is split into lock-striped shards keyed by the signature hash so
+// script validation goroutines running on many cores don't serialize on a
+// single lock.
 type SigCache struct {
-	sync.RWMutex
-	validSigs  map[chainhash.Hash]sigCacheEntry
-	maxEntries uint
+	cache shardedCache
 }
 
 // NewSigCache creates and initializes a new instance of SigCache. Its sole
@@ -47,23 +192,23 @@ type SigCache struct {
 // to make room for new entries that would cause the number of entries in the
 // cache to exceed the max.
 func NewSigCache(maxEntries uint) *SigCache {
-	return &SigCache{
-		validSigs:  make(map[chainhash.Hash]sigCacheEntry, maxEntries),
-		maxEntries: maxEntries,
-	}
+	s := &SigCache{}
+	s.cache.init(maxEntries)
+	return s
 }
 
 // Exists returns true if an existing entry of 'sig' over 'sigHash' for public
 // key 'pubKey' is found within the SigCache. Otherwise, false is returned.
 //
 // NOTE: This function is safe for concurrent access. Readers won't be blocked
-// unless there exists a writer, adding an entry to the SigCache.
+// unless there exists a writer, adding an entry to the same shard of the
+// SigCache.
 func (s *SigCache) Exists(sigHash chainhash.Hash, sig []byte, pubKey []byte) bool {
-	s.RLock()
-	entry, ok := s.validSigs[sigHash]
-	s.RUnlock()
-
-	return ok && bytes.Equal(entry.pubKey, pubKey) && bytes.Equal(entry.sig, sig)
+	entry, ok := s.cache.lookup(&sigHash)
+	hit := ok && bytes.Equal(entry.pubKey, pubKey) &&
+		bytes.Equal(entry.sig, sig)
+	s.cache.recordLookup(hit)
+	return hit
 }
 
 // Add adds an entry for a signature over 'sigHash' under public key 'pubKey'
@@ -72,31 +217,15 @@ func (s *SigCache) Exists(sigHash chainhash.Hash, sig []byte, pubKey []byte) boo
 // the new entry.
 //
 // NOTE: This function is safe for concurrent access. Writers will block
-// simultaneous readers until function execution has concluded.
+// simultaneous readers of the same shard until function execution has
+// concluded.
 func (s *SigCache) Add(sigHash chainhash.Hash, sig []byte, pubKey []byte) {
-	s.Lock()
-	defer s.Unlock()
-
-	if s.maxEntries <= 0 {
-		return
-	}
+	s.cache.add(&sigHash, sigCacheEntry{sig, pubKey})
+}
 
-	// If adding this new entry will put us over the max number of allowed
-	// entries, then evict an entry.
-	if uint(len(s.validSigs)+1) > s.maxEntries {
-		// Remove a random entry from the map. Relying on the random
-		// starting point of Go's map iteration. It's worth noting that
-		// the random iteration starting point is not 100% guaranteed
-		// by the spec, however most Go compilers support it.
-		// Ultimately, the iteration order isn't important here because
-		// in order to manipulate which items are evicted, an adversary
-		// would need to be able to execute preimage attacks on the
-		// hashing function in order to start eviction at a specific
-		// entry.
-		for sigEntry := range s.validSigs {
-			delete(s.validSigs, sigEntry)
-			break
-		}
-	}
-	s.validSigs[sigHash] = sigCacheEntry{sig, pubKey}
+// Stats returns a snapshot of the signature cache usage statistics.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *SigCache) Stats() CacheStats {
+	return s.cache.stats()
 }
@@ -6,6 +6,7 @@ package txscript
 
 import (
 	"crypto/rand"
+	"sync"
 	"testing"
 
 	"github.com/ltcsuite/ltcd/btcec/v2"
@@ -86,9 +87,9 @@ func TestSigCacheAddEvictEntry(t *testing.T) {
 	}
 
 	// The sigcache should now have sigCacheSize entries within it.
-	if uint(len(sigCache.validSigs)) != sigCacheSize {
+	if sigCache.Stats().Entries != sigCacheSize {
 		t.Fatalf("sigcache should now have %v entries, instead it has %v",
-			sigCacheSize, len(sigCache.validSigs))
+			sigCacheSize, sigCache.Stats().Entries)
 	}
 
 	// Add a new entry, this should cause eviction of a randomly chosen
@@ -100,9 +101,9 @@ func TestSigCacheAddEvictEntry(t *testing.T) {
 	sigCache.Add(*msgNew, sigNew.Serialize(), keyNew.SerializeCompressed())
 
 	// The sigcache should still have sigCache entries.
-	if uint(len(sigCache.validSigs)) != sigCacheSize {
+	if sigCache.Stats().Entries != sigCacheSize {
 		t.Fatalf("sigcache should now have %v entries, instead it has %v",
-			sigCacheSize, len(sigCache.validSigs))
+			sigCacheSize, sigCache.Stats().Entries)
 	}
 
 	// The entry added above should be found within the sigcache.
@@ -137,8 +138,70 @@ func TestSigCacheAddMaxEntriesZeroOrNegative(t *testing.T) {
 	}
 
 	// There shouldn't be any entries in the sigCache.
-	if len(sigCache.validSigs) != 0 {
+	if sigCache.Stats().Entries != 0 {
 		t.Errorf("%v items found in sigcache, no items should have"+
-			"been added", len(sigCache.validSigs))
+			"been added", sigCache.Stats().Entries)
+	}
+}
+
+// TestSigCacheStats ensures the signature cache tracks hits and misses.
+func TestSigCacheStats(t *testing.T) {
+	sigCache := NewSigCache(10)
+
+	msg, sig, key, err := genRandomSig()
+	if err != nil {
+		t.Fatalf("unable to generate random signature test data")
+	}
+	sigBytes, keyBytes := sig.Serialize(), key.SerializeCompressed()
+
+	sigCache.Exists(*msg, sigBytes, keyBytes)
+	sigCache.Add(*msg, sigBytes, keyBytes)
+	sigCache.Exists(*msg, sigBytes, keyBytes)
+
+	// A matching sighash with a different public key is a miss.
+	sigCache.Exists(*msg, sigBytes, keyBytes[1:])
+
+	// Adding the same entry again doesn't increase the number of entries.
+	sigCache.Add(*msg, sigBytes, keyBytes)
+
+	want := CacheStats{Hits: 1, Misses: 2, Entries: 1, MaxEntries: 10}
+	if stats := sigCache.Stats(); stats != want {
+		t.Fatalf("unexpected stats: got %+v, want %+v", stats, want)
+	}
+}
+
+// TestSigCacheConcurrent ensures the number of entries in the signature cache
+// never exceeds the maximum when entries are added concurrently.
+func TestSigCacheConcurrent(t *testing.T) {
+	const (
+		maxEntries = 50
+		goroutines = 8
+		perRoutine = 100
+	)
+	sigCache := NewSigCache(maxEntries)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perRoutine; j++ {
+				var msg chainhash.Hash
+				rand.Read(msg[:])
+				sigCache.Add(msg, msg[:], msg[:])
+				sigCache.Exists(msg, msg[:], msg[:])
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := sigCache.Stats()
+	if stats.Entries != maxEntries {
+		t.Fatalf("unexpected number of entries: got %d, want %d",
+			stats.Entries, maxEntries)
+	}
+	if stats.Hits+stats.Misses != goroutines*perRoutine {
+		t.Fatalf("unexpected number of lookups: got %d, want %d",
+			stats.Hits+stats.Misses, goroutines*perRoutine)
 	}
 }
@@ -777,6 +777,43 @@ func TestPayToAddrScript(t *testing.T) {
 	}
 }
 
+// TestTaprootAddressRoundTrip ensures a bech32m encoded taproot address
+// produces a standard taproot script which extracts back to the same address.
+func TestTaprootAddressRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	const encoded = "dsv1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwu" +
+		"dpxqte5dxy"
+
+	addr, err := ltcutil.DecodeAddress(encoded, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("DecodeAddress: unexpected error: %v", err)
+	}
+	if _, ok := addr.(*ltcutil.AddressTaproot); !ok {
+		t.Fatalf("DecodeAddress: got %T, want *ltcutil.AddressTaproot",
+			addr)
+	}
+
+	pkScript, err := PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("PayToAddrScript: unexpected error: %v", err)
+	}
+	if class := GetScriptClass(pkScript); class != WitnessV1TaprootTy {
+		t.Fatalf("GetScriptClass: got %v, want %v", class,
+			WitnessV1TaprootTy)
+	}
+
+	_, addrs, _, err := ExtractPkScriptAddrs(pkScript,
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("ExtractPkScriptAddrs: unexpected error: %v", err)
+	}
+	if len(addrs) != 1 || addrs[0].EncodeAddress() != encoded {
+		t.Fatalf("ExtractPkScriptAddrs: got %v, want %s", addrs,
+			encoded)
+	}
+}
+
 // TestMultiSigScript ensures the MultiSigScript function returns the expected
 // scripts and errors.
 func TestMultiSigScript(t *testing.T) {
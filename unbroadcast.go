@@ -0,0 +1,102 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+const (
+	// unbroadcastFilename is the name of the file in the data directory
+	// used to persist user submitted transactions which have not yet been
+	// confirmed or requested by an outbound peer across restarts.
+	unbroadcastFilename = "unbroadcast.dat"
+
+	// maxUnbroadcastTxns is the maximum number of transactions that will be
+	// loaded from the unbroadcast file.  It bounds the memory used when
+	// the file is corrupt.
+	maxUnbroadcastTxns = 10000
+)
+
+// saveUnbroadcastTxns writes the passed transactions to the file at the given
+// path.  The file is written to a temporary file first and then renamed so a
+// crash while saving does not leave a truncated file behind.
+func saveUnbroadcastTxns(path string, txns []*wire.MsgTx) error {
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	err = writeUnbroadcastTxns(w, txns)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// writeUnbroadcastTxns serializes the passed transactions to w as a count
+// followed by each transaction.
+func writeUnbroadcastTxns(w io.Writer, txns []*wire.MsgTx) error {
+	if err := wire.WriteVarInt(w, 0, uint64(len(txns))); err != nil {
+		return err
+	}
+	for _, tx := range txns {
+		if err := tx.Serialize(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadUnbroadcastTxns reads the transactions previously written by
+// saveUnbroadcastTxns from the file at the given path.  A missing file is not
+// an error and results in no transactions.
+func loadUnbroadcastTxns(path string) ([]*wire.MsgTx, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	count, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if count > maxUnbroadcastTxns {
+		return nil, fmt.Errorf("too many unbroadcast transactions "+
+			"[count %d, max %d]", count, maxUnbroadcastTxns)
+	}
+
+	txns := make([]*wire.MsgTx, 0, count)
+	for i := uint64(0); i < count; i++ {
+		var tx wire.MsgTx
+		if err := tx.Deserialize(r); err != nil {
+			return nil, err
+		}
+		txns = append(txns, &tx)
+	}
+
+	return txns, nil
+}
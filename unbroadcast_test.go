@@ -0,0 +1,103 @@
+// Copyright (c) 2024 The ltcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	"github.com/ltcsuite/ltcd/wire"
+)
+
+// TestUnbroadcastTxnsRoundTrip ensures transactions saved to the unbroadcast
+// file are loaded back unchanged and that a missing file yields nothing.
+func TestUnbroadcastTxnsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), unbroadcastFilename)
+
+	txns, err := loadUnbroadcastTxns(path)
+	if err != nil {
+		t.Fatalf("loadUnbroadcastTxns: unexpected error on missing "+
+			"file: %v", err)
+	}
+	if len(txns) != 0 {
+		t.Fatalf("loadUnbroadcastTxns: got %d txns from missing file",
+			len(txns))
+	}
+
+	want := make([]*wire.MsgTx, 0, 3)
+	for i := 0; i < 3; i++ {
+		tx := wire.NewMsgTx(wire.TxVersion)
+		prevHash := chainhash.Hash{byte(i + 1)}
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevHash, uint32(i)),
+			[]byte{0x51}, nil))
+		tx.AddTxOut(wire.NewTxOut(int64(i+1)*1e8, []byte{0x51}))
+		want = append(want, tx)
+	}
+
+	if err := saveUnbroadcastTxns(path, want); err != nil {
+		t.Fatalf("saveUnbroadcastTxns: unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("saveUnbroadcastTxns: temporary file left behind")
+	}
+
+	got, err := loadUnbroadcastTxns(path)
+	if err != nil {
+		t.Fatalf("loadUnbroadcastTxns: unexpected error: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("loadUnbroadcastTxns: got %d txns, want %d", len(got),
+			len(want))
+	}
+	for i := range want {
+		if got[i].TxHash() != want[i].TxHash() {
+			t.Errorf("loadUnbroadcastTxns #%d: got %v, want %v", i,
+				got[i].TxHash(), want[i].TxHash())
+		}
+	}
+
+	// Ensure a truncated file is reported as an error.
+	if err := os.WriteFile(path, []byte{0x02, 0x01}, 0644); err != nil {
+		t.Fatalf("WriteFile: unexpected error: %v", err)
+	}
+	if _, err := loadUnbroadcastTxns(path); err == nil {
+		t.Fatal("loadUnbroadcastTxns: expected error for truncated file")
+	}
+}
+
+// TestTransactionRequested ensures only requests for transactions pending
+// rebroadcast are passed to the rebroadcast handler.
+func TestTransactionRequested(t *testing.T) {
+	s := &server{
+		rpcServer:            &rpcServer{},
+		modifyRebroadcastInv: make(chan interface{}, 1),
+		rebroadcastTxns:      make(map[chainhash.Hash]struct{}),
+	}
+	tracked := chainhash.Hash{0x01}
+	s.AddRebroadcastInventory(wire.NewInvVect(wire.InvTypeTx, &tracked), nil)
+	<-s.modifyRebroadcastInv
+
+	untracked := chainhash.Hash{0x02}
+	s.TransactionRequested(&untracked)
+	select {
+	case msg := <-s.modifyRebroadcastInv:
+		t.Fatalf("untracked transaction passed to the rebroadcast "+
+			"handler: %v", msg)
+	default:
+	}
+
+	s.TransactionRequested(&tracked)
+	select {
+	case msg := <-s.modifyRebroadcastInv:
+		del, ok := msg.(broadcastInventoryDel)
+		if !ok || del.Hash != tracked {
+			t.Fatalf("unexpected rebroadcast handler message %v", msg)
+		}
+	default:
+		t.Fatal("tracked transaction not removed from rebroadcasting")
+	}
+}
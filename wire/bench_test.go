@@ -438,6 +438,42 @@ func BenchmarkDecodeHeaders(b *testing.B) {
 	}
 }
 
+// BenchmarkReadMessageBlockPipe performs a benchmark on how long it takes to
+// read a block message with many transactions from an unbuffered connection,
+// which is how peers read the streamed payload of blocks.
+func BenchmarkReadMessageBlockPipe(b *testing.B) {
+	pver := ProtocolVersion
+	block := NewMsgBlock(&blockOne.Header)
+	for i := 0; i < 2000; i++ {
+		block.AddTransaction(&genesisCoinbaseTx)
+	}
+
+	var bb bytes.Buffer
+	if err := WriteMessage(&bb, block, pver, MainNet); err != nil {
+		b.Fatalf("WriteMessage: unexpected error: %v", err)
+	}
+	buf := bb.Bytes()
+
+	r, w := net.Pipe()
+	defer r.Close()
+	go func() {
+		defer w.Close()
+		for i := 0; i < b.N; i++ {
+			if _, err := w.Write(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	b.SetBytes(int64(len(buf)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, err := ReadMessageN(r, pver, MainNet); err != nil {
+			b.Fatalf("ReadMessageN: unexpected error: %v", err)
+		}
+	}
+}
+
 // BenchmarkDecodeGetBlocks performs a benchmark on how long it takes to
 // decode a getblocks message with the maximum number of block locator hashes.
 func BenchmarkDecodeGetBlocks(b *testing.B) {
@@ -5,7 +5,9 @@
 package wire
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"unicode/utf8"
@@ -213,6 +215,9 @@ func makeEmptyMessage(command string) (Message, error) {
 	case CmdMwebUtxos:
 		msg = &MsgMwebUtxos{}
 
+	case CmdGetMwebUtxos:
+		msg = &MsgGetMwebUtxos{}
+
 	default:
 		return nil, ErrUnknownMessage
 	}
@@ -251,6 +256,75 @@ func readMessageHeader(r io.Reader) (int, *messageHeader, error) {
 	return n, &hdr, nil
 }
 
+// maxStreamBufferSize is the maximum size of the buffer streamed payloads are
+// read through.  Decoding reads every field of a payload separately, so the
+// payload is buffered to avoid a read from the underlying connection per field.
+const maxStreamBufferSize = 64 * 1024
+
+// isStreamedMessage returns whether the payload of messages with the passed
+// command is decoded while it is read rather than after reading it whole.  The
+// payload of these messages may be up to MaxBlockPayload bytes, so streaming
+// them ensures the memory used to read them grows with the bytes actually
+// received instead of the length claimed by the message header, and that a
+// malformed payload is rejected as soon as its invalid part is read.
+func isStreamedMessage(command string) bool {
+	switch command {
+	case CmdBlock, CmdHeaders, CmdMwebUtxos:
+		return true
+	}
+	return false
+}
+
+// readStreamedPayload decodes the payload of the message described by the
+// passed header from r into msg while it is read, and returns the number of
+// bytes read along with the raw payload.  The payload checksum is verified once
+// the whole payload has been read, and any bytes remaining after a decoding
+// error are discarded so the next message can be read.
+func readStreamedPayload(r io.Reader, hdr *messageHeader, msg Message,
+	pver uint32, enc MessageEncoding) (int, []byte, error) {
+
+	// The buffer reads from the limited reader so it never reads past the
+	// payload into the next message.
+	lr := &io.LimitedReader{R: r, N: int64(hdr.length)}
+	bufSize := int(hdr.length)
+	if bufSize > maxStreamBufferSize {
+		bufSize = maxStreamBufferSize
+	}
+	br := bufio.NewReaderSize(lr, bufSize)
+	var payload bytes.Buffer
+	hasher := sha256.New()
+	tr := io.TeeReader(br, io.MultiWriter(&payload, hasher))
+
+	err := msg.BtcDecode(tr, pver, enc)
+	if err != nil {
+		n := int(int64(hdr.length) - lr.N)
+		discardInput(r, uint32(lr.N))
+		return n, nil, err
+	}
+
+	// Read any trailing bytes the message didn't decode so they are part
+	// of the checksum, as they are when the payload is read whole.
+	_, err = io.Copy(io.Discard, tr)
+	n := int(int64(hdr.length) - lr.N)
+	if err != nil {
+		return n, nil, err
+	}
+	if lr.N > 0 {
+		return n, nil, io.ErrUnexpectedEOF
+	}
+
+	// Test checksum.
+	checksum := sha256.Sum256(hasher.Sum(nil))
+	if !bytes.Equal(checksum[0:4], hdr.checksum[:]) {
+		str := fmt.Sprintf("payload checksum failed - header "+
+			"indicates %v, but actual checksum is %v.",
+			hdr.checksum, checksum[0:4])
+		return n, nil, messageError("ReadMessage", str)
+	}
+
+	return n, payload.Bytes(), nil
+}
+
 // discardInput reads n bytes from reader r in chunks and discards the read
 // bytes.  This is used to skip payloads when various errors occur and helps
 // prevent rogue nodes from causing massive memory allocation through forging
@@ -425,6 +499,18 @@ func ReadMessageWithEncodingN(r io.Reader, pver uint32, btcnet BitcoinNet,
 		return totalBytes, nil, nil, messageError("ReadMessage", str)
 	}
 
+	// Decode large messages while reading them so a malicious client
+	// can't make the payload length claimed by the header be allocated
+	// without sending it.
+	if isStreamedMessage(command) {
+		n, payload, err := readStreamedPayload(r, hdr, msg, pver, enc)
+		totalBytes += n
+		if err != nil {
+			return totalBytes, nil, nil, err
+		}
+		return totalBytes, msg, payload, nil
+	}
+
 	// Read payload.
 	payload := make([]byte, hdr.length)
 	n, err = io.ReadFull(r, payload)
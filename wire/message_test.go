@@ -76,6 +76,11 @@ func TestMessage(t *testing.T) {
 		[]byte("payload"))
 	msgCFHeaders := NewMsgCFHeaders()
 	msgCFCheckpt := NewMsgCFCheckpt(GCSFilterRegular, &chainhash.Hash{}, 0)
+	msgGetMwebUtxos := NewMsgGetMwebUtxos(chainhash.Hash{}, 0,
+		MaxMwebUtxosPerQuery, MwebNetUtxoHashOnly)
+	msgMwebUtxos := NewMsgMwebUtxos(chainhash.Hash{}, 0, MwebNetUtxoHashOnly)
+	msgMwebUtxos.Utxos = []*MwebNetUtxo{{OutputId: &chainhash.Hash{}}}
+	msgMwebUtxos.ProofHashes = []*chainhash.Hash{{}}
 
 	tests := []struct {
 		in     Message    // Value to encode
@@ -111,6 +116,8 @@ func TestMessage(t *testing.T) {
 		{msgCFilter, msgCFilter, pver, MainNet, 65},
 		{msgCFHeaders, msgCFHeaders, pver, MainNet, 90},
 		{msgCFCheckpt, msgCFCheckpt, pver, MainNet, 58},
+		{msgGetMwebUtxos, msgGetMwebUtxos, pver, MainNet, 60},
+		{msgMwebUtxos, msgMwebUtxos, pver, MainNet, 125},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -239,6 +246,29 @@ func TestReadMessageWireErrors(t *testing.T) {
 	// bytes of data to discard.
 	discardBytes := makeHeader(btcnet, "bogus", 15*1024, 0)
 
+	// Wire encoded bytes for a block message which the header claims has
+	// the max block payload, but whose transaction count is too large.
+	// It must be rejected as soon as the count is read.
+	badBlockBytes := makeHeader(btcnet, "block", MaxBlockPayload, 0)
+	badBlockBytes = append(badBlockBytes, blockOneBytes[:80]...)
+	badBlockBytes = append(badBlockBytes, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff)
+
+	// Wire encoded bytes for a block message with a bad checksum.
+	badBlockChecksumBytes := makeHeader(btcnet, "block",
+		uint32(len(blockOneBytes)), 0xbeef)
+	badBlockChecksumBytes = append(badBlockChecksumBytes, blockOneBytes...)
+
+	// Wire encoded bytes for a block message which does not deliver the
+	// full payload according to the header length.
+	shortBlockBytes := makeHeader(btcnet, "block",
+		uint32(len(blockOneBytes)+1), 0)
+	shortBlockBytes = append(shortBlockBytes, blockOneBytes...)
+
+	// Wire encoded bytes for a mwebutxos message, which is not allowed
+	// before protocol version MwebLightClientVersion.
+	mwebUtxosBytes := makeHeader(btcnet, "mwebutxos", 1, 0)
+
 	tests := []struct {
 		buf     []byte     // Wire encoding
 		pver    uint32     // Protocol version for wire encoding
@@ -348,6 +378,46 @@ func TestReadMessageWireErrors(t *testing.T) {
 			ErrUnknownMessage,
 			24,
 		},
+
+		// Block with too many transactions.
+		{
+			badBlockBytes,
+			pver,
+			btcnet,
+			len(badBlockBytes),
+			&MessageError{},
+			113,
+		},
+
+		// Block with a bad checksum.
+		{
+			badBlockChecksumBytes,
+			pver,
+			btcnet,
+			len(badBlockChecksumBytes),
+			&MessageError{},
+			len(badBlockChecksumBytes),
+		},
+
+		// Block with a payload shorter than the header indicates.
+		{
+			shortBlockBytes,
+			pver,
+			btcnet,
+			len(shortBlockBytes),
+			io.ErrUnexpectedEOF,
+			len(shortBlockBytes),
+		},
+
+		// Message not allowed by the protocol version.
+		{
+			mwebUtxosBytes,
+			MwebLightClientVersion - 1,
+			btcnet,
+			len(mwebUtxosBytes),
+			&MessageError{},
+			24,
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -380,6 +450,50 @@ func TestReadMessageWireErrors(t *testing.T) {
 	}
 }
 
+// TestReadMessageStreamed ensures messages which are decoded while their payload
+// is read return the same payload as other messages, including any bytes after
+// the decoded message.
+func TestReadMessageStreamed(t *testing.T) {
+	btcnet := MainNet
+	for _, trailing := range [][]byte{nil, {0x01, 0x02}} {
+		payload := append(append([]byte(nil), blockOneBytes...),
+			trailing...)
+		checksum := chainhash.DoubleHashB(payload)
+		buf := makeHeader(btcnet, "block", uint32(len(payload)),
+			binary.LittleEndian.Uint32(checksum))
+		buf = append(buf, payload...)
+		buf = append(buf, makeHeader(btcnet, "verack", 0, 0xe2e0f65d)...)
+
+		r := bytes.NewReader(buf)
+		nr, msg, gotPayload, err := ReadMessageN(r, ProtocolVersion,
+			btcnet)
+		if err != nil {
+			t.Fatalf("ReadMessageN: %v", err)
+		}
+		if nr != MessageHeaderSize+len(payload) {
+			t.Errorf("ReadMessageN: read %d bytes, want %d", nr,
+				MessageHeaderSize+len(payload))
+		}
+		if !reflect.DeepEqual(msg, &blockOne) {
+			t.Errorf("ReadMessageN: got %v, want %v",
+				spew.Sdump(msg), spew.Sdump(&blockOne))
+		}
+		if !bytes.Equal(gotPayload, payload) {
+			t.Errorf("ReadMessageN: got payload %x, want %x",
+				gotPayload, payload)
+		}
+
+		// The next message must be read from where the block ended.
+		msg, _, err = ReadMessage(r, ProtocolVersion, btcnet)
+		if err != nil {
+			t.Fatalf("ReadMessage: %v", err)
+		}
+		if _, ok := msg.(*MsgVerAck); !ok {
+			t.Errorf("ReadMessage: got %T, want *MsgVerAck", msg)
+		}
+	}
+}
+
 // TestWriteMessageWireErrors performs negative tests against wire encoding from
 // concrete messages to confirm error paths work correctly.
 func TestWriteMessageWireErrors(t *testing.T) {
@@ -24,6 +24,8 @@ const MaxBlocksPerMsg = 500
 
 // MaxBlockPayload is the maximum bytes a block message can be in bytes.
 // After Segregated Witness, the max block payload has been raised to 4MB.
+// It applies to every network, so the maximum block weight of a network can't
+// exceed it.
 const MaxBlockPayload = 4000000
 
 // maxTxPerBlock is the maximum number of transactions that could
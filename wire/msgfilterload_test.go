@@ -6,9 +6,12 @@ package wire
 
 import (
 	"bytes"
+	"encoding/binary"
 	"io"
 	"reflect"
 	"testing"
+
+	"github.com/ltcsuite/ltcd/chaincfg/chainhash"
 )
 
 // TestFilterCLearLatest tests the MsgFilterLoad API against the latest protocol
@@ -125,6 +128,42 @@ func TestFilterLoadMaxHashFuncsSize(t *testing.T) {
 	}
 }
 
+// TestFilterLoadOversizedMessage ensures filterload messages exceeding the
+// maximum filter size or number of hash functions are rejected while reading
+// them from the wire, so they never reach the handlers of a peer.
+func TestFilterLoadOversizedMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		hashFuncs uint32
+	}{
+		{"filter size", MaxFilterLoadFilterSize + 1, MaxFilterLoadHashFuncs},
+		{"hash funcs", 10, MaxFilterLoadHashFuncs + 1},
+	}
+	for _, test := range tests {
+		var payload bytes.Buffer
+		WriteVarBytes(&payload, ProtocolVersion,
+			bytes.Repeat([]byte{0xff}, test.size))
+		binary.Write(&payload, littleEndian, test.hashFuncs)
+		payload.Write(make([]byte, 5)) // tweak and flags
+
+		var msg bytes.Buffer
+		binary.Write(&msg, littleEndian, MainNet)
+		var command [CommandSize]byte
+		copy(command[:], CmdFilterLoad)
+		msg.Write(command[:])
+		binary.Write(&msg, littleEndian, uint32(payload.Len()))
+		msg.Write(chainhash.DoubleHashB(payload.Bytes())[:4])
+		msg.Write(payload.Bytes())
+
+		_, _, err := ReadMessage(&msg, ProtocolVersion, MainNet)
+		if _, ok := err.(*MessageError); !ok {
+			t.Errorf("%s: got error %v, want *MessageError",
+				test.name, err)
+		}
+	}
+}
+
 // TestFilterLoadWireErrors performs negative tests against wire encode and decode
 // of MsgFilterLoad to confirm error paths work correctly.
 func TestFilterLoadWireErrors(t *testing.T) {
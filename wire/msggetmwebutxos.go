@@ -83,7 +83,15 @@ func (msg *MsgGetMwebUtxos) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver.  This is part of the Message interface implementation.
 func (msg *MsgGetMwebUtxos) MaxPayloadLength(pver uint32) uint32 {
-	return MaxBlockPayload
+	// The getmwebutxos message did not exist before protocol version
+	// MwebLightClientVersion.
+	if pver < MwebLightClientVersion {
+		return 0
+	}
+
+	// Block hash + start index (varInt) + number requested + output
+	// format.
+	return chainhash.HashSize + MaxVarIntPayload + 2 + 1
 }
 
 // NewMsgGetMwebUtxos returns a new litecoin getmwebutxos message that conforms to
@@ -72,6 +72,12 @@ func (msg *MsgMwebHeader) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver.  This is part of the Message interface implementation.
 func (msg *MsgMwebHeader) MaxPayloadLength(pver uint32) uint32 {
+	// The mwebheader message did not exist before protocol version
+	// MwebLightClientVersion.
+	if pver < MwebLightClientVersion {
+		return 0
+	}
+
 	return MaxBlockPayload
 }
 
@@ -65,6 +65,12 @@ func (msg *MsgMwebLeafset) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver.  This is part of the Message interface implementation.
 func (msg *MsgMwebLeafset) MaxPayloadLength(pver uint32) uint32 {
+	// The mwebleafset message did not exist before protocol version
+	// MwebLightClientVersion.
+	if pver < MwebLightClientVersion {
+		return 0
+	}
+
 	return MaxBlockPayload
 }
 
@@ -37,7 +37,8 @@ func readMwebNetUtxo(r io.Reader, pver uint32, utxo *MwebNetUtxo,
 		utxo.Output = new(MwebOutput)
 		err = utxo.Output.read(r, pver, false)
 	case MwebNetUtxoHashOnly:
-		err = readElement(r, &utxo.OutputId)
+		utxo.OutputId = new(chainhash.Hash)
+		err = readElement(r, utxo.OutputId)
 	case MwebNetUtxoCompact:
 		utxo.Output = new(MwebOutput)
 		err = utxo.Output.read(r, pver, true)
@@ -72,7 +73,7 @@ func writeMwebNetUtxo(w io.Writer, pver uint32, utxo *MwebNetUtxo,
 	case MwebNetUtxoFull:
 		err = utxo.Output.write(w, pver, false, false)
 	case MwebNetUtxoHashOnly:
-		err = writeElement(w, &utxo.OutputId)
+		err = writeElement(w, utxo.OutputId)
 	case MwebNetUtxoCompact:
 		err = utxo.Output.write(w, pver, true, false)
 	}
@@ -211,6 +212,12 @@ func (msg *MsgMwebUtxos) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver.  This is part of the Message interface implementation.
 func (msg *MsgMwebUtxos) MaxPayloadLength(pver uint32) uint32 {
+	// The mwebutxos message did not exist before protocol version
+	// MwebLightClientVersion.
+	if pver < MwebLightClientVersion {
+		return 0
+	}
+
 	return MaxBlockPayload
 }
 
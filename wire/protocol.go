@@ -13,7 +13,7 @@ import (
 // XXX pedro: we will probably need to bump this.
 const (
 	// ProtocolVersion is the latest protocol version this package supports.
-	ProtocolVersion uint32 = 70016
+	ProtocolVersion uint32 = 70017
 
 	// MultipleAddressVersion is the protocol version which added multiple
 	// addresses per message (pver >= MultipleAddressVersion).
@@ -149,6 +149,33 @@ func (f ServiceFlag) String() string {
 	return s
 }
 
+// ParseServiceFlags parses a comma separated list of service flag names such as
+// "network,witness" into the flags they represent.  The names are matched
+// case-insensitively against the names of the flags with or without their
+// "SFNode" prefix.  An error is returned for any unknown name.
+func ParseServiceFlags(s string) (ServiceFlag, error) {
+	var flags ServiceFlag
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.TrimPrefix(name, "sfnode")
+
+		var found bool
+		for flag, flagName := range sfStrings {
+			flagName = strings.TrimPrefix(flagName, "SFNode")
+			if name == strings.ToLower(flagName) {
+				flags |= flag
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown service flag %q", name)
+		}
+	}
+
+	return flags, nil
+}
+
 // BitcoinNet represents which litecoin network a message belongs to.
 type BitcoinNet uint32
 
@@ -33,6 +33,38 @@ func TestServiceFlagStringer(t *testing.T) {
 	}
 }
 
+// TestParseServiceFlags tests parsing lists of service flag names.
+func TestParseServiceFlags(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ServiceFlag
+		wantErr bool
+	}{
+		{"network", SFNodeNetwork, false},
+		{"network,witness", SFNodeNetwork | SFNodeWitness, false},
+		{" Witness , MWEB ", SFNodeWitness | SFNodeMWEB, false},
+		{"SFNodeNetworkLimited,mweblightclient",
+			SFNodeNetworkLimited | SFNodeMWEBLightClient, false},
+		{"getutxo,bloom,cf", SFNodeGetUTXO | SFNodeBloom | SFNodeCF, false},
+		{"network,unknown", 0, true},
+		{"", 0, true},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		result, err := ParseServiceFlags(test.in)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseServiceFlags #%d (%q): unexpected error "+
+				"%v", i, test.in, err)
+			continue
+		}
+		if result != test.want {
+			t.Errorf("ParseServiceFlags #%d (%q)\n got: %v want: %v",
+				i, test.in, result, test.want)
+		}
+	}
+}
+
 // TestBitcoinNetStringer tests the stringized output for litecoin net types.
 func TestBitcoinNetStringer(t *testing.T) {
 	tests := []struct {